@@ -0,0 +1,279 @@
+// Command pr-reviewer-dev runs the service in the dev composition mode: data is kept in memory,
+// notifications are written to stdout and synthetic PR traffic can be generated on demand,
+// so the whole pipeline can be demoed without PostgreSQL or Docker.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/buildinfo"
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/creator"
+	"github.com/YusovID/pr-reviewer-service/internal/deactivator"
+	"github.com/YusovID/pr-reviewer-service/internal/dispatcher"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/filler"
+	"github.com/YusovID/pr-reviewer-service/internal/httpclient"
+	"github.com/YusovID/pr-reviewer-service/internal/localcache"
+	"github.com/YusovID/pr-reviewer-service/internal/notifier"
+	"github.com/YusovID/pr-reviewer-service/internal/publisher"
+	"github.com/YusovID/pr-reviewer-service/internal/refresher"
+	"github.com/YusovID/pr-reviewer-service/internal/relay"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/memory"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/internal/runner"
+	"github.com/YusovID/pr-reviewer-service/internal/sampler"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/YusovID/pr-reviewer-service/internal/signature"
+	"github.com/YusovID/pr-reviewer-service/internal/simulator"
+	myhttp "github.com/YusovID/pr-reviewer-service/internal/transport/http"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/slogpretty"
+	"github.com/go-chi/chi/v5"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	simulateEvery := flag.Duration("simulate-every", 0, "interval between background simulated events, 0 disables them")
+	sampleEvery := flag.Duration("sample-every", 15*time.Second, "interval between samples of the open pull request age metrics, 0 disables them")
+	refreshStatsEvery := flag.Duration("refresh-stats-every", 0, "interval between refreshes of the precomputed all-time stats, 0 computes them on every request")
+	localCacheSize := flag.Int("local-cache-size", 0, "number of GET /team/get and GET /users/getReview results cached in process for 5s, 0 disables the cache")
+	fillEvery := flag.Duration("fill-every", 5*time.Second, "interval between runs of the pending assignment filler, 0 disables it")
+	backfillEvery := flag.Duration("backfill-every", time.Minute, "interval between runs of the pending assignment backfill, 0 disables it")
+	deactivationWorkers := flag.Int("deactivation-workers", 4, "number of workers reassigning batched team deactivations, 0 disables them")
+	createWorkers := flag.Int("create-workers", 4, "number of workers processing asynchronous pull request creations, 0 disables them")
+	jobWorkers := flag.Int("job-workers", 2, "number of workers running jobs created through POST /jobs, 0 disables them")
+	webhookWorkers := flag.Int("webhook-workers", 4, "number of workers delivering events to the webhooks registered on /admin/webhooks, 0 disables them")
+	logEvents := flag.Bool("log-events", false, "publish the events written to the outbox to the log sink")
+	requireApprovals := flag.Bool("require-approvals", false, "merge pull requests only once every reviewer has approved them")
+	defaultStrategy := flag.String("default-strategy", string(domain.StrategyRandom), "strategy picking the reviewers of teams without a policy: random, least_loaded or round_robin")
+	caseInsensitiveUsernames := flag.Bool("case-insensitive-usernames", false, "reject teams whose members' usernames differ only in case")
+	gitHubWebhookSecret := flag.String("github-webhook-secret", "", "secret of the GitHub webhook deliveries accepted on /webhooks/github, empty disables the webhook")
+	serviceKey := flag.String("service-key", "", "service key required as a bearer token by the API, empty leaves the API open")
+	gitLabWebhookToken := flag.String("gitlab-webhook-token", "", "secret token of the GitLab webhook deliveries accepted on /webhooks/gitlab, empty disables the webhook")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log := slogpretty.SetupLogger("local")
+	build := buildinfo.Get()
+	log.Info("starting pr-reviewer-service in dev mode",
+		slog.String("version", build.Version),
+		slog.String("commit", build.Commit),
+		slog.String("build_date", build.BuildDate),
+	)
+
+	strategy := domain.AssignmentStrategy(*defaultStrategy)
+	if !strategy.IsValid() {
+		log.Error("unknown default strategy", slog.String("strategy", *defaultStrategy))
+		os.Exit(1)
+	}
+
+	store := memory.NewStore(log)
+	txManager := txctx.NewManager(store.DB(), log)
+
+	var readCache *localcache.Cache
+	if *localCacheSize > 0 {
+		readCache = localcache.New(*localCacheSize, 5*time.Second)
+	}
+
+	var teamOpts []service.TeamServiceOption
+	if *caseInsensitiveUsernames {
+		teamOpts = append(teamOpts, service.WithCaseInsensitiveUsernames())
+	}
+
+	teamOpts = append(teamOpts, service.WithPolicyDefaults(service.PolicyDefaults{
+		DefaultStrategy:  strategy,
+		RequireApprovals: *requireApprovals,
+	}), service.WithTeamReadCache(readCache))
+
+	teamService := service.NewTeamService(store, store, store, store, teamOpts...)
+	userOpts := []service.UserServiceOption{
+		service.WithUserDefaultStrategy(strategy),
+		service.WithUserReadCache(readCache),
+		service.WithTeamDeactivations(store),
+	}
+	if *deactivationWorkers > 0 {
+		userOpts = append(userOpts, service.WithDeactivationJobs(store))
+	}
+
+	if *jobWorkers > 0 {
+		userOpts = append(userOpts, service.WithRebalanceJobs(store))
+	}
+
+	userService := service.NewUserService(store, store, store, store, store, store, store, txManager, log, userOpts...)
+	notificationService := service.NewNotificationService(store, log, service.WithNotificationChannel(notifier.NewLogNotifier(log)))
+	prOpts := []service.PullRequestServiceOption{
+		service.WithNotifier(notificationService),
+		service.WithPendingAssignments(store),
+		service.WithCustomFields(store),
+		service.WithSubscriptions(store),
+		service.WithMergeFreezes(store),
+		// The dev server is never production, so every mutation is checked.
+		service.WithInvariantChecks(1),
+		service.WithDefaultStrategy(strategy),
+		service.WithReadCache(readCache),
+	}
+	if *requireApprovals {
+		prOpts = append(prOpts, service.WithRequiredApprovals())
+	}
+
+	if *createWorkers > 0 {
+		prOpts = append(prOpts, service.WithAsyncCreate(store, time.Minute))
+	}
+
+	if *webhookWorkers > 0 {
+		prOpts = append(prOpts, service.WithWebhookEvents(store))
+	}
+
+	if *logEvents {
+		prOpts = append(prOpts, service.WithOutbox(store))
+	}
+
+	if *refreshStatsEvery > 0 {
+		prOpts = append(prOpts, service.WithStatsView(store))
+	}
+
+	prService := service.NewPullRequestService(txManager, log, store, store, store, store, store, prOpts...)
+	freezeService := service.NewFreezeService(store, store, log)
+	gitLabUserService := service.NewGitLabUserService(store, log)
+	slackUserService := service.NewSlackUserService(store, log)
+	// A failing webhook is retried every few seconds rather than hours, so that the retries can be watched.
+	webhookClient := httpclient.New("webhooks", config.HTTPClient{
+		Timeout: 5 * time.Second, RetryBaseDelay: 100 * time.Millisecond, RetryMaxDelay: 2 * time.Second,
+		BreakerFailures: 5, BreakerOpenTimeout: 30 * time.Second,
+	}, log)
+	webhookService := service.NewWebhookService(store, webhookClient, log,
+		service.WithWebhookDeliveryPolicy(time.Minute, 5, time.Second, 30*time.Second))
+	outboxService := service.NewOutboxService(store, []service.EventPublisher{publisher.NewLogPublisher(log)}, log,
+		service.WithOutboxPublishPolicy(time.Minute, time.Second, 30*time.Second))
+
+	var jobOpts []service.JobServiceOption
+	if *jobWorkers > 0 {
+		jobOpts = append(jobOpts,
+			service.WithJobHandler(domain.JobTeamImport, service.NewTeamImportJob(teamService)),
+			service.WithJobHandler(domain.JobPendingBackfill, service.NewPendingBackfillJob(prService)),
+			service.WithJobHandler(domain.JobStatsExport, service.NewStatsExportJob(prService)),
+			service.WithJobHandler(domain.JobReviewerRebalance, service.NewReviewerRebalanceJob(userService)),
+			service.WithJobHandler(domain.JobStatsBackfill, service.NewStatsBackfillJob(prService)),
+		)
+	}
+
+	if *jobWorkers > 0 && *deactivationWorkers > 0 {
+		jobOpts = append(jobOpts, service.WithJobHandler(domain.JobTeamDeactivation, service.NewTeamDeactivationJob(userService, time.Second)))
+	}
+
+	jobService := service.NewJobService(store, time.Minute, log, jobOpts...)
+
+	sim := simulator.New(log, teamService, prService)
+	if err := sim.Seed(ctx); err != nil {
+		log.Error("failed to seed demo teams", sl.Err(err))
+		os.Exit(1)
+	}
+
+	if *simulateEvery > 0 {
+		go sim.RunEvery(ctx, *simulateEvery)
+	}
+
+	if *fillEvery > 0 {
+		go filler.New(log, prService, *fillEvery, 100).Run(ctx)
+	}
+
+	if *backfillEvery > 0 {
+		go filler.NewBackfiller(log, prService, *backfillEvery, 100).Run(ctx)
+	}
+
+	if *createWorkers > 0 {
+		go creator.New(log, prService, time.Second, *createWorkers).Run(ctx)
+	}
+
+	if *webhookWorkers > 0 {
+		go dispatcher.New(log, webhookService, time.Second, *webhookWorkers).Run(ctx)
+	}
+
+	if *logEvents {
+		go relay.New(log, outboxService, time.Second, 100).Run(ctx)
+	}
+
+	if *deactivationWorkers > 0 {
+		go deactivator.New(log, userService, time.Second, *deactivationWorkers).Run(ctx)
+	}
+
+	if *jobWorkers > 0 {
+		go runner.New(log, jobService, time.Second, *jobWorkers).Run(ctx)
+	}
+
+	if *sampleEvery > 0 {
+		go sampler.New(log, prService, *sampleEvery).Run(ctx)
+	}
+
+	if *refreshStatsEvery > 0 {
+		go refresher.New(log, prService, *refreshStatsEvery).Run(ctx)
+	}
+
+	mux := chi.NewRouter()
+	mux.Handle("/dev/webhook/simulate", sim)
+	serverOpts := []myhttp.ServerOption{
+		myhttp.WithJobs(jobService),
+		myhttp.WithNotifications(notificationService),
+		myhttp.WithFreezes(freezeService),
+		myhttp.WithGitLabUsers(gitLabUserService),
+		myhttp.WithSlackUsers(slackUserService),
+		myhttp.WithWebhooks(webhookService),
+		myhttp.WithReadTokens(service.NewReadTokenService(store, log)),
+		myhttp.WithAPIKeys(service.NewAPIKeyService(store, log)),
+		myhttp.WithOIDCSubjects(service.NewOIDCSubjectService(store, log)),
+	}
+	if *serviceKey != "" {
+		serverOpts = append(serverOpts, myhttp.WithAuth(config.Auth{
+			ServiceKeys: []string{*serviceKey}, ReadTokenRateLimit: 60, ReadTokenRateWindow: time.Minute,
+		}))
+	}
+	if *gitHubWebhookSecret != "" {
+		serverOpts = append(serverOpts, myhttp.WithGitHubWebhook(signature.NewVerifier([]byte(*gitHubWebhookSecret))))
+	}
+	if *gitLabWebhookToken != "" {
+		serverOpts = append(serverOpts, myhttp.WithGitLabWebhook(signature.NewVerifier([]byte(*gitLabWebhookToken))))
+	}
+
+	server := myhttp.NewServer(log, teamService, userService, prService, serverOpts...)
+	mux.Mount("/", server.Routes())
+
+	httpServer := &http.Server{
+		Addr:         *addr,
+		Handler:      mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  10 * time.Second,
+	}
+
+	go func() {
+		log.Info("server started", slog.String("addr", httpServer.Addr))
+
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("server failed to start", sl.Err(err))
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	log.Info("stopping server...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Error("server shutdown failed", sl.Err(err))
+	}
+
+	log.Info("server stopped")
+}
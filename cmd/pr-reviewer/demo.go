@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/buildinfo"
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/dispatcher"
+	"github.com/YusovID/pr-reviewer-service/internal/filler"
+	"github.com/YusovID/pr-reviewer-service/internal/httpclient"
+	"github.com/YusovID/pr-reviewer-service/internal/notifier"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/sqlite"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/YusovID/pr-reviewer-service/internal/simulator"
+	myhttp "github.com/YusovID/pr-reviewer-service/internal/transport/http"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/slogpretty"
+	"github.com/go-chi/chi/v5"
+)
+
+// runDemo runs "pr-reviewer demo": the service without any configuration or PostgreSQL, with data kept
+// in an embedded SQLite database and pre-seeded with the demo teams and sample pull requests, so that the API
+// can be tried through swagger right away. Unless -db names a file to keep, the database is a temporary file
+// removed once the process exits.
+func runDemo(args []string) {
+	flags := flag.NewFlagSet("demo", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	events := flags.Int("events", 0, "number of simulated events generated on top of the sample data")
+	dbFile := flags.String("db", "", "SQLite database file to keep the data in; a temporary one is used and removed on exit if empty")
+	_ = flags.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log := slogpretty.SetupLogger("local")
+	build := buildinfo.Get()
+	log.Info("starting pr-reviewer-service in demo mode",
+		slog.String("version", build.Version),
+		slog.String("commit", build.Commit),
+	)
+
+	dbPath := *dbFile
+	if dbPath == "" {
+		dir, err := os.MkdirTemp("", "pr-reviewer-demo-")
+		if err != nil {
+			log.Error("failed to create demo database directory", sl.Err(err))
+			os.Exit(1)
+		}
+		defer os.RemoveAll(dir)
+
+		dbPath = filepath.Join(dir, "demo.db")
+	}
+
+	store, err := sqlite.NewStore(dbPath, log)
+	if err != nil {
+		log.Error("failed to open demo database", slog.String("path", dbPath), sl.Err(err))
+		os.Exit(1)
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			log.Error("db close failed", sl.Err(err))
+		}
+	}()
+
+	log.Info("demo database opened", slog.String("path", dbPath))
+
+	txManager := txctx.NewManager(store.DB(), log)
+
+	teamService := service.NewTeamService(store, store, store, store)
+	userService := service.NewUserService(store, store, store, store, store, store, store, txManager, log, service.WithTeamDeactivations(store))
+	notificationService := service.NewNotificationService(store, log, service.WithNotificationChannel(notifier.NewLogNotifier(log)))
+	prService := service.NewPullRequestService(txManager, log, store, store, store, store, store,
+		service.WithNotifier(notificationService),
+		service.WithPendingAssignments(store),
+		service.WithCustomFields(store),
+		service.WithSubscriptions(store),
+		service.WithMergeFreezes(store),
+		service.WithWebhookEvents(store),
+	)
+	freezeService := service.NewFreezeService(store, store, log)
+	gitLabUserService := service.NewGitLabUserService(store, log)
+	slackUserService := service.NewSlackUserService(store, log)
+	webhookClient := httpclient.New("webhooks", config.HTTPClient{
+		Timeout: 5 * time.Second, RetryBaseDelay: 100 * time.Millisecond, RetryMaxDelay: 2 * time.Second,
+		BreakerFailures: 5, BreakerOpenTimeout: 30 * time.Second,
+	}, log)
+	webhookService := service.NewWebhookService(store, webhookClient, log,
+		service.WithWebhookDeliveryPolicy(time.Minute, 5, time.Second, 30*time.Second))
+
+	sim := simulator.New(log, teamService, prService)
+	if err := sim.Seed(ctx); err != nil {
+		log.Error("failed to seed demo teams", sl.Err(err))
+		os.Exit(1)
+	}
+
+	if err := sim.SeedSamples(ctx); err != nil {
+		log.Error("failed to seed sample pull requests", sl.Err(err))
+		os.Exit(1)
+	}
+
+	if *events > 0 {
+		sim.Run(ctx, *events)
+	}
+
+	go filler.New(log, prService, 5*time.Second, 100).Run(ctx)
+	go filler.NewBackfiller(log, prService, time.Minute, 100).Run(ctx)
+	go dispatcher.New(log, webhookService, time.Second, 4).Run(ctx)
+
+	mux := chi.NewRouter()
+	mux.Handle("/dev/webhook/simulate", sim)
+	server := myhttp.NewServer(log, teamService, userService, prService,
+		myhttp.WithNotifications(notificationService),
+		myhttp.WithFreezes(freezeService),
+		myhttp.WithGitLabUsers(gitLabUserService),
+		myhttp.WithSlackUsers(slackUserService),
+		myhttp.WithWebhooks(webhookService),
+	)
+	mux.Mount("/", server.Routes())
+
+	httpServer := &http.Server{
+		Addr:         *addr,
+		Handler:      mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  10 * time.Second,
+	}
+
+	go func() {
+		log.Info("demo server started", slog.String("swagger", "http://"+httpServer.Addr+"/swagger/"))
+
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("server failed to start", sl.Err(err))
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	log.Info("stopping server...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Error("server shutdown failed", sl.Err(err))
+	}
+
+	log.Info("server stopped")
+}
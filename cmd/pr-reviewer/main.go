@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"errors"
+	"io"
 	"log"
 	"log/slog"
 	"net"
@@ -12,15 +13,41 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/YusovID/pr-reviewer-service/internal/buildinfo"
 	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/creator"
+	"github.com/YusovID/pr-reviewer-service/internal/deactivator"
+	"github.com/YusovID/pr-reviewer-service/internal/dispatcher"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/filler"
+	"github.com/YusovID/pr-reviewer-service/internal/httpclient"
+	"github.com/YusovID/pr-reviewer-service/internal/localcache"
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/YusovID/pr-reviewer-service/internal/notifier"
+	"github.com/YusovID/pr-reviewer-service/internal/oidc"
+	"github.com/YusovID/pr-reviewer-service/internal/publisher"
+	"github.com/YusovID/pr-reviewer-service/internal/refresher"
+	"github.com/YusovID/pr-reviewer-service/internal/relay"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/cached"
 	"github.com/YusovID/pr-reviewer-service/internal/repository/postgres"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/internal/runner"
+	"github.com/YusovID/pr-reviewer-service/internal/sampler"
 	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/YusovID/pr-reviewer-service/internal/signature"
+	"github.com/YusovID/pr-reviewer-service/internal/tracing"
 	myhttp "github.com/YusovID/pr-reviewer-service/internal/transport/http"
 	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
 	"github.com/YusovID/pr-reviewer-service/pkg/logger/slogpretty"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "demo" {
+		runDemo(os.Args[2:])
+		return
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
@@ -30,7 +57,34 @@ func main() {
 	}
 
 	log := slogpretty.SetupLogger(cfg.Env)
-	log.Info("starting pr-reviewer-service", slog.String("env", cfg.Env))
+	build := buildinfo.Get()
+	log.Info("starting pr-reviewer-service",
+		slog.String("env", cfg.Env),
+		slog.String("version", build.Version),
+		slog.String("commit", build.Commit),
+		slog.String("build_date", build.BuildDate),
+		slog.String("go_version", build.GoVersion),
+	)
+
+	if cfg.Tracing.Enabled() {
+		shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, build.Version)
+		if err != nil {
+			log.Error("failed to set up tracing", sl.Err(err))
+			os.Exit(1)
+		}
+
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := shutdownTracing(shutdownCtx); err != nil {
+				log.Error("failed to flush traces", sl.Err(err))
+			}
+		}()
+
+		log = slog.New(tracing.NewLogHandler(log.Handler()))
+		log.Info("tracing enabled", slog.String("endpoint", cfg.Tracing.Endpoint))
+	}
 
 	db, err := postgres.NewDB(cfg.Postgres, log)
 	if err != nil {
@@ -44,15 +98,367 @@ func main() {
 		}
 	}()
 
-	teamRepo := postgres.NewTeamRepository(db, log)
-	userRepo := postgres.NewUserRepository(db, log)
+	if err := metrics.RegisterDBStats(db.DB, metrics.DBName); err != nil {
+		log.Error("failed to register db pool metrics", sl.Err(err))
+	}
+
+	// With a replica the query endpoints read from it, and from the primary while it is down.
+	var replica *postgres.Replica
+	if cfg.Postgres.HasReplica() {
+		replicaDB, err := postgres.NewReplicaDB(cfg.Postgres)
+		if err != nil {
+			log.Error("failed to init replica db", sl.Err(err))
+			os.Exit(1)
+		}
+
+		defer func() {
+			if err := replicaDB.Close(); err != nil {
+				log.Error("replica db close failed", sl.Err(err))
+			}
+		}()
+
+		if err := metrics.RegisterDBStats(replicaDB.DB, metrics.ReplicaDBName); err != nil {
+			log.Error("failed to register replica db pool metrics", sl.Err(err))
+		}
+
+		replica = postgres.NewReplica(db, replicaDB, log)
+		replica.Check(ctx)
+
+		go replica.Run(ctx, cfg.Postgres.ReplicaCheckInterval)
+
+		log.Info("serving the query endpoints from a replica")
+	}
+
+	// Without Redis the cache is a no-op and the team and user reads go to PostgreSQL.
+	var redisClient redis.UniversalClient
+	if cfg.Redis.Enabled() {
+		redisClient = redis.NewClient(&redis.Options{
+			Addr:         cfg.Redis.Addr,
+			Password:     cfg.Redis.Password,
+			DB:           cfg.Redis.DB,
+			DialTimeout:  cfg.Redis.Timeout,
+			ReadTimeout:  cfg.Redis.Timeout,
+			WriteTimeout: cfg.Redis.Timeout,
+			// A failed cache read goes to the database at once rather than waiting for retries.
+			MaxRetries: -1,
+		})
+
+		defer func() {
+			if err := redisClient.Close(); err != nil {
+				log.Error("redis close failed", sl.Err(err))
+			}
+		}()
+
+		// An unavailable Redis does not stop the service: the reads fall back to PostgreSQL until it is back.
+		if err := redisClient.Ping(ctx).Err(); err != nil {
+			log.Warn("redis is unavailable, team and user reads go to the database", sl.Err(err))
+		}
+
+		log.Info("caching team and user reads in redis", slog.String("addr", cfg.Redis.Addr))
+	}
+
+	cache := cached.New(redisClient, log, cfg.Redis.TeamTTL, cfg.Redis.UserTTL)
+
+	teamRepo := cached.NewTeamRepository(postgres.NewTeamRepository(db, log), cache)
+	userRepo := cached.NewUserRepository(postgres.NewUserRepository(db, log), cache)
 	prRepo := postgres.NewPullRequestRepository(db, log)
+	userPRRepo := cached.NewUserPRRepository(prRepo, cache)
+	policyRepo := postgres.NewPolicyRepository(db, log)
+	historyRepo := postgres.NewAssignmentHistoryRepository(db, log)
+	pendingRepo := postgres.NewPendingAssignmentRepository(db, log)
+	borrowRepo := postgres.NewBorrowRepository(db, log)
+	createRequestRepo := postgres.NewCreatePRRequestRepository(db, log)
+	deactivationJobRepo := postgres.NewDeactivationJobRepository(db, log)
+	jobRepo := postgres.NewJobRepository(db, log)
+	customFieldRepo := postgres.NewCustomFieldRepository(db, log)
+	subscriptionRepo := postgres.NewSubscriptionRepository(db, log)
+	deliveryRepo := postgres.NewNotificationDeliveryRepository(db, log)
+	freezeRepo := postgres.NewFreezeWindowRepository(db, log)
+	gitLabUserRepo := postgres.NewGitLabUserRepository(db, log)
+	webhookRepo := postgres.NewWebhookRepository(db, log)
+	slackUserRepo := postgres.NewSlackUserRepository(db, log)
+	txManager := txctx.NewManager(db, log)
+
+	defaultStrategy := domain.AssignmentStrategy(cfg.PullRequests.DefaultStrategy)
+
+	// The services share one cache, so that the writes of each invalidate the reads of the others.
+	var readCache *localcache.Cache
+	if cfg.LocalCache.Enabled() {
+		readCache = localcache.New(cfg.LocalCache.Size, cfg.LocalCache.TTL)
+		log.Info("caching hot reads in process", slog.Int("size", cfg.LocalCache.Size), slog.Duration("ttl", cfg.LocalCache.TTL))
+	}
+
+	var teamOpts []service.TeamServiceOption
+	if cfg.Teams.CaseInsensitiveUsernames {
+		teamOpts = append(teamOpts, service.WithCaseInsensitiveUsernames())
+	}
+
+	teamOpts = append(teamOpts, service.WithPolicyDefaults(service.PolicyDefaults{
+		DefaultStrategy:  defaultStrategy,
+		RequireApprovals: cfg.PullRequests.RequireApprovals,
+		StrictChecks:     cfg.PullRequests.StrictChecks,
+	}), service.WithTeamReadCache(readCache))
+
+	if replica != nil {
+		teamQueryRepo := cached.NewTeamRepository(postgres.NewTeamRepository(db, log).WithReplica(replica), cache)
+		teamOpts = append(teamOpts, service.WithTeamQueries(teamQueryRepo))
+	}
+
+	teamService := service.NewTeamService(teamRepo, policyRepo, borrowRepo, customFieldRepo, teamOpts...)
+	userOpts := []service.UserServiceOption{
+		service.WithUserDefaultStrategy(defaultStrategy),
+		service.WithUserReadCache(readCache),
+		service.WithUserTxMaxAttempts(cfg.Postgres.TxMaxAttempts),
+		service.WithTeamDeactivations(postgres.NewTeamDeactivationRepository(db, log)),
+	}
+	if cfg.Teams.DeactivationWorkers > 0 {
+		userOpts = append(userOpts, service.WithDeactivationJobs(deactivationJobRepo))
+	}
+
+	if cfg.Jobs.Workers > 0 {
+		userOpts = append(userOpts, service.WithRebalanceJobs(jobRepo))
+	}
 
-	teamService := service.NewTeamService(teamRepo, db)
-	userService := service.NewUserService(userRepo, teamRepo, prRepo, prRepo, prRepo, db, log)
-	prService := service.NewPullRequestService(db, log, prRepo, prRepo, prRepo)
+	userService := service.NewUserService(userRepo, teamRepo, prRepo, prRepo, userPRRepo, policyRepo, historyRepo, txManager, log, userOpts...)
+	var notificationOpts []service.NotificationServiceOption
+	if cfg.Notifications.LogChannel {
+		notificationOpts = append(notificationOpts, service.WithNotificationChannel(notifier.NewLogNotifier(log)))
+	}
+
+	if cfg.Slack.Enabled() {
+		slack := notifier.NewSlackNotifier(cfg.Slack, httpclient.New("slack", cfg.HTTPClient, log), slackUserRepo)
+		notificationOpts = append(notificationOpts, service.WithNotificationChannel(slack))
+	}
+
+	notificationService := service.NewNotificationService(deliveryRepo, log, notificationOpts...)
+
+	var prNotifier service.Notifier = notificationService
+	if cfg.Notifications.Workers > 0 {
+		async := notifier.NewAsync(log, notificationService, cfg.Notifications.QueueSize, cfg.Notifications.Workers)
+		go async.Run(ctx)
+
+		prNotifier = async
+	}
+
+	prOpts := []service.PullRequestServiceOption{
+		service.WithNotifier(prNotifier),
+		service.WithPendingAssignments(pendingRepo),
+		service.WithCustomFields(customFieldRepo),
+		service.WithSubscriptions(subscriptionRepo),
+		service.WithMergeFreezes(freezeRepo),
+		service.WithInvariantChecks(cfg.ReviewerCheckRate()),
+		service.WithDefaultStrategy(defaultStrategy),
+		service.WithReadCache(readCache),
+		service.WithTxMaxAttempts(cfg.Postgres.TxMaxAttempts),
+	}
+	if cfg.PullRequests.OnDuplicateCreate == config.DuplicateCreateReturnExisting {
+		prOpts = append(prOpts, service.WithReturnExistingOnDuplicate())
+	}
+
+	if cfg.PullRequests.RequireApprovals {
+		prOpts = append(prOpts, service.WithRequiredApprovals())
+	}
+
+	if cfg.PullRequests.StrictChecks {
+		prOpts = append(prOpts, service.WithStrictChecks())
+	}
+
+	if cfg.PullRequests.StatsRefreshInterval > 0 {
+		prOpts = append(prOpts, service.WithStatsView(prRepo))
+	}
+
+	workingHours, err := cfg.PullRequests.WorkingHours.Schedule()
+	if err != nil {
+		log.Error("invalid working hours", sl.Err(err))
+		os.Exit(1)
+	}
+
+	if workingHours != nil {
+		prOpts = append(prOpts, service.WithWorkingHours(*workingHours))
+	}
+
+	if cfg.PullRequests.AsyncCreateWorkers > 0 {
+		prOpts = append(prOpts, service.WithAsyncCreate(createRequestRepo, cfg.PullRequests.AsyncCreateLease))
+	}
+
+	if cfg.Outbound.Workers > 0 {
+		prOpts = append(prOpts, service.WithWebhookEvents(webhookRepo))
+	}
+
+	outboxRepo := postgres.NewOutboxRepository(db, log)
+	if cfg.Events.Enabled() {
+		prOpts = append(prOpts, service.WithOutbox(outboxRepo))
+	}
+
+	prService := service.NewPullRequestService(txManager, log, prRepo, prRepo, userPRRepo, policyRepo, historyRepo, prOpts...)
+	freezeService := service.NewFreezeService(freezeRepo, teamRepo, log)
+	gitLabUserService := service.NewGitLabUserService(gitLabUserRepo, log)
+	slackUserService := service.NewSlackUserService(slackUserRepo, log)
+	webhookService := service.NewWebhookService(webhookRepo, httpclient.New("webhooks", cfg.HTTPClient, log), log,
+		service.WithWebhookDeliveryPolicy(cfg.Outbound.Lease, cfg.Outbound.MaxAttempts, cfg.Outbound.RetryBaseDelay, cfg.Outbound.RetryMaxDelay))
+	readTokenService := service.NewReadTokenService(postgres.NewReadTokenRepository(db, log), log)
+	apiKeyService := service.NewAPIKeyService(postgres.NewAPIKeyRepository(db, log), log)
+	oidcSubjectService := service.NewOIDCSubjectService(postgres.NewOIDCSubjectRepository(db, log), log)
+
+	publishers, err := eventPublishers(cfg.Events, log)
+	if err != nil {
+		log.Error("failed to init event publishers", sl.Err(err))
+		os.Exit(1)
+	}
+
+	outboxService := service.NewOutboxService(outboxRepo, publishers, log,
+		service.WithOutboxPublishPolicy(cfg.Events.Lease, cfg.Events.RetryBaseDelay, cfg.Events.RetryMaxDelay))
+
+	var jobOpts []service.JobServiceOption
+	if cfg.Jobs.Workers > 0 {
+		jobOpts = append(jobOpts,
+			service.WithJobHandler(domain.JobTeamImport, service.NewTeamImportJob(teamService)),
+			service.WithJobHandler(domain.JobPendingBackfill, service.NewPendingBackfillJob(prService)),
+			service.WithJobHandler(domain.JobStatsExport, service.NewStatsExportJob(prService)),
+			service.WithJobHandler(domain.JobReviewerRebalance, service.NewReviewerRebalanceJob(userService)),
+			service.WithJobHandler(domain.JobStatsBackfill, service.NewStatsBackfillJob(prService)),
+		)
+	}
+
+	if cfg.Jobs.Workers > 0 && cfg.Teams.DeactivationWorkers > 0 {
+		jobOpts = append(jobOpts, service.WithJobHandler(domain.JobTeamDeactivation,
+			service.NewTeamDeactivationJob(userService, cfg.Teams.DeactivationPollInterval)))
+	}
 
-	handler := myhttp.NewServer(log, teamService, userService, prService)
+	jobService := service.NewJobService(jobRepo, cfg.Jobs.Lease, log, jobOpts...)
+
+	serverOpts := []myhttp.ServerOption{
+		myhttp.WithSLO(cfg.SLO),
+		myhttp.WithJobs(jobService),
+		myhttp.WithNotifications(notificationService),
+		myhttp.WithFreezes(freezeService),
+		myhttp.WithGitLabUsers(gitLabUserService),
+		myhttp.WithSlackUsers(slackUserService),
+		myhttp.WithWebhooks(webhookService),
+		myhttp.WithReadTokens(readTokenService),
+		myhttp.WithAPIKeys(apiKeyService),
+		myhttp.WithOIDCSubjects(oidcSubjectService),
+	}
+
+	if cfg.Auth.Enabled() {
+		serverOpts = append(serverOpts, myhttp.WithAuth(cfg.Auth))
+	} else {
+		log.Warn("no service or api keys configured, the API is open to everyone")
+	}
+
+	if cfg.OIDC.Enabled() {
+		provider, err := oidc.Discover(ctx, cfg.OIDC, httpclient.New("oidc", cfg.HTTPClient, log))
+		if err != nil {
+			log.Error("failed to discover oidc provider", sl.Err(err))
+			os.Exit(1)
+		}
+
+		serverOpts = append(serverOpts, myhttp.WithOIDC(provider))
+	}
+
+	if cfg.Webhooks.GitHubSecret != "" {
+		var verifierOpts []signature.VerifierOption
+		if cfg.Webhooks.GitHubPreviousSecret != "" {
+			verifierOpts = append(verifierOpts, signature.WithPreviousSecret([]byte(cfg.Webhooks.GitHubPreviousSecret)))
+		}
+
+		serverOpts = append(serverOpts, myhttp.WithGitHubWebhook(signature.NewVerifier([]byte(cfg.Webhooks.GitHubSecret), verifierOpts...)))
+	}
+
+	if cfg.Webhooks.GitLabToken != "" {
+		var verifierOpts []signature.VerifierOption
+		if cfg.Webhooks.GitLabPreviousToken != "" {
+			verifierOpts = append(verifierOpts, signature.WithPreviousSecret([]byte(cfg.Webhooks.GitLabPreviousToken)))
+		}
+
+		serverOpts = append(serverOpts, myhttp.WithGitLabWebhook(signature.NewVerifier([]byte(cfg.Webhooks.GitLabToken), verifierOpts...)))
+	}
+
+	if shadow := cfg.Webhooks.Shadow; shadow.GitHub || shadow.GitLab {
+		log.Info("webhooks in shadow mode, their assignments are computed but not persisted",
+			slog.Bool("github", shadow.GitHub), slog.Bool("gitlab", shadow.GitLab))
+
+		serverOpts = append(serverOpts, myhttp.WithWebhookShadow(shadow))
+	}
+
+	serverOpts = append(serverOpts, myhttp.WithMaxBodyBytes(cfg.Server.MaxBodyBytes))
+
+	if cfg.Tracing.Enabled() {
+		serverOpts = append(serverOpts, myhttp.WithTracing())
+	}
+
+	if cfg.SecurityHeaders.Enabled {
+		serverOpts = append(serverOpts, myhttp.WithSecurityHeaders(cfg.SecurityHeaders))
+	}
+
+	if cfg.Compression.Enabled {
+		serverOpts = append(serverOpts, myhttp.WithCompression(cfg.Compression))
+	}
+
+	if cfg.CORS.Enabled() {
+		serverOpts = append(serverOpts, myhttp.WithCORS(cfg.CORS))
+	}
+
+	if cfg.Server.RequestTimeout > 0 || len(cfg.Server.RouteTimeouts) > 0 {
+		serverOpts = append(serverOpts, myhttp.WithRequestTimeouts(cfg.Server.RequestTimeout, cfg.Server.RouteTimeouts))
+	}
+
+	if !cfg.Server.Swagger {
+		serverOpts = append(serverOpts, myhttp.WithoutSwagger())
+	}
+
+	// The background workers write, so a read-only instance leaves them to the instances using the primary.
+	writable := !cfg.Server.ReadOnly
+	if !writable {
+		log.Info("starting in read-only mode, mutations are rejected and background workers are disabled")
+
+		serverOpts = append(serverOpts, myhttp.WithReadOnly())
+	}
+
+	if writable && cfg.PullRequests.PendingFillInterval > 0 {
+		go filler.New(log, prService, cfg.PullRequests.PendingFillInterval, cfg.PullRequests.PendingFillBatch).Run(ctx)
+	}
+
+	if writable && cfg.PullRequests.BackfillInterval > 0 {
+		go filler.NewBackfiller(log, prService, cfg.PullRequests.BackfillInterval, cfg.PullRequests.PendingFillBatch).Run(ctx)
+	}
+
+	if writable && cfg.PullRequests.AsyncCreateWorkers > 0 {
+		go creator.New(log, prService, cfg.PullRequests.AsyncCreatePollInterval, cfg.PullRequests.AsyncCreateWorkers).Run(ctx)
+	}
+
+	if writable && cfg.Teams.DeactivationWorkers > 0 {
+		go deactivator.New(log, userService, cfg.Teams.DeactivationPollInterval, cfg.Teams.DeactivationWorkers).Run(ctx)
+	}
+
+	if writable && cfg.Jobs.Workers > 0 {
+		go runner.New(log, jobService, cfg.Jobs.PollInterval, cfg.Jobs.Workers).Run(ctx)
+	}
+
+	if writable && cfg.Outbound.Workers > 0 {
+		go dispatcher.New(log, webhookService, cfg.Outbound.PollInterval, cfg.Outbound.Workers).Run(ctx)
+	}
+
+	if writable && cfg.Events.Enabled() {
+		go relay.New(log, outboxService, cfg.Events.PollInterval, cfg.Events.BatchSize).Run(ctx)
+	}
+
+	// Read-only instances read the precomputed statistics refreshed by the writable ones.
+	if writable && cfg.PullRequests.StatsRefreshInterval > 0 {
+		go refresher.New(log, prService, cfg.PullRequests.StatsRefreshInterval).Run(ctx)
+	}
+
+	if cfg.PullRequests.AgeSampleInterval > 0 {
+		go sampler.New(log, prService, cfg.PullRequests.AgeSampleInterval).Run(ctx)
+	}
+
+	if replica != nil {
+		// The writes stay with prService, which reads the primary, so that they see the writes before them.
+		prQueryService := service.NewPullRequestService(txctx.NewManager(replica, log), log, prRepo, prRepo.WithReplica(replica), userPRRepo, policyRepo, historyRepo, prOpts...)
+		serverOpts = append(serverOpts, myhttp.WithPRQueries(prQueryService))
+	}
+
+	handler := myhttp.NewServer(log, teamService, userService, prService, serverOpts...)
 
 	httpServer := &http.Server{
 		Addr:         net.JoinHostPort(cfg.Server.Host, cfg.Server.Port),
@@ -71,6 +477,25 @@ func main() {
 		}
 	}()
 
+	var pprofServer *http.Server
+
+	if cfg.Pprof.Enabled {
+		pprofServer = &http.Server{
+			Addr:              net.JoinHostPort(cfg.Pprof.Host, cfg.Pprof.Port),
+			Handler:           myhttp.PprofHandler(),
+			ReadHeaderTimeout: cfg.Server.Timeout,
+			// No write timeout: a CPU profile or an execution trace takes as many seconds as asked for.
+		}
+
+		go func() {
+			log.Warn("pprof server started, do not expose it publicly", slog.String("addr", pprofServer.Addr))
+
+			if err := pprofServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error("pprof server failed to start", sl.Err(err))
+			}
+		}()
+	}
+
 	<-ctx.Done()
 	log.Info("stopping server...")
 
@@ -81,5 +506,48 @@ func main() {
 		log.Error("server shutdown failed", sl.Err(err))
 	}
 
+	if pprofServer != nil {
+		// A profile being taken is cut short rather than waited for.
+		if err := pprofServer.Close(); err != nil {
+			log.Error("pprof server close failed", sl.Err(err))
+		}
+	}
+
+	for _, p := range publishers {
+		if closer, ok := p.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				log.Error("failed to close event publisher", slog.String("sink", p.Name()), sl.Err(err))
+			}
+		}
+	}
+
 	log.Info("server stopped")
 }
+
+// eventPublishers builds the publishers of the configured event sinks.
+func eventPublishers(cfg config.Events, log *slog.Logger) ([]service.EventPublisher, error) {
+	publishers := make([]service.EventPublisher, 0, len(cfg.Sinks))
+
+	for _, sink := range cfg.Sinks {
+		switch sink {
+		case config.EventSinkLog:
+			publishers = append(publishers, publisher.NewLogPublisher(log))
+		case config.EventSinkKafka:
+			kafka, err := publisher.NewKafkaPublisher(cfg.Kafka)
+			if err != nil {
+				return nil, err
+			}
+
+			publishers = append(publishers, kafka)
+		case config.EventSinkNATS:
+			nats, err := publisher.NewNATSPublisher(cfg.NATS, log)
+			if err != nil {
+				return nil, err
+			}
+
+			publishers = append(publishers, nats)
+		}
+	}
+
+	return publishers, nil
+}
@@ -4,6 +4,10 @@ package apperrors
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
 )
 
 var (
@@ -18,15 +22,51 @@ var (
 
 	// ErrInvalidRequest indicates a malformed request body (e.g., bad JSON).
 	ErrInvalidRequest = errors.New("invalid request body")
+	// ErrBodyTooLarge indicates a request body over the limit of the server.
+	ErrBodyTooLarge = errors.New("request body too large")
 	// ErrValidation indicates that request data failed business rule validation.
 	ErrValidation = errors.New("validation failed")
 
 	// ErrPRMerged indicates an attempt to modify a pull request that has already been merged.
 	ErrPRMerged = errors.New("cannot modify merged pull request")
-	// ErrReviewerNotAssigned indicates an attempt to reassign a reviewer who is not assigned to the PR.
+	// ErrPRClosed indicates an attempt to modify a pull request that has been closed without merging.
+	ErrPRClosed = errors.New("cannot modify closed pull request")
+	// ErrApprovalRequired indicates an attempt to merge a pull request that some of its reviewers have not approved,
+	// while merging requires the approval of every reviewer.
+	ErrApprovalRequired = errors.New("pull request is not approved by all reviewers")
+	// ErrMergeFrozen indicates an attempt to merge a pull request during a freeze window of its author's team
+	// or of the whole organization.
+	ErrMergeFrozen = errors.New("merges are frozen")
+	// ErrReviewerNotAssigned indicates an attempt to reassign, or to review as, a user who is not assigned to the PR.
 	ErrReviewerNotAssigned = errors.New("reviewer is not assigned to this PR")
 	// ErrNoCandidate indicates that no suitable active user could be found to become a new reviewer.
 	ErrNoCandidate = errors.New("no active replacement candidate found in team")
+	// ErrDeactivationInProgress indicates that another deactivation of the same team has not finished yet.
+	ErrDeactivationInProgress = errors.New("team deactivation is already in progress")
+	// ErrInsufficientCapacity indicates that the remaining active users cannot take over all reviews being reassigned.
+	ErrInsufficientCapacity = errors.New("not enough active reviewers to take over the reviews")
+	// ErrBorrowNotPending indicates an attempt to accept a reviewer borrow that has already been accepted.
+	ErrBorrowNotPending = errors.New("reviewer borrow is not awaiting acceptance")
+	// ErrAuthorQuotaExceeded indicates that the author already has as many open pull requests as the team policy allows.
+	ErrAuthorQuotaExceeded = errors.New("author has reached the open pull request limit")
+	// ErrAuthorInactive indicates a pull request created by a deactivated author, rejected in strict mode.
+	ErrAuthorInactive = errors.New("author is inactive")
+	// ErrTeamTooSmall indicates a pull request whose author's team has fewer active reviewers than required,
+	// rejected in strict mode.
+	ErrTeamTooSmall = errors.New("team has too few active reviewers")
+	// ErrInvalidAssignment indicates that the storage rejected a reviewer assignment that breaks its invariants,
+	// which points to a bug in reviewer selection rather than to a bad request.
+	ErrInvalidAssignment = errors.New("invalid reviewer assignment")
+	// ErrJobFinished indicates an attempt to cancel a job that has already succeeded or failed.
+	ErrJobFinished = errors.New("job has already finished")
+	// ErrDeliveryNotFailed indicates an attempt to retry a notification delivery that has succeeded.
+	ErrDeliveryNotFailed = errors.New("notification has already been delivered")
+	// ErrDeliveryNotDead indicates an attempt to redeliver a webhook delivery that has not failed for good.
+	ErrDeliveryNotDead = errors.New("webhook delivery is not dead")
+	// ErrInvalidToken indicates an API token that is unknown or has expired.
+	ErrInvalidToken = errors.New("invalid or expired token")
+	// ErrJobLeaseLost indicates that a worker no longer owns the job it runs, e.g. because its lease expired.
+	ErrJobLeaseLost = errors.New("job lease lost")
 )
 
 // TeamAlreadyExistsError is a structured error for when a team with a given name already exists.
@@ -44,3 +84,97 @@ func (e *PRAlreadyExistsError) Error() string {
 	return fmt.Sprintf("pull request '%s' already exists", e.PRID)
 }
 func (e *PRAlreadyExistsError) Is(target error) bool { return target == ErrAlreadyExists }
+
+// InsufficientCapacityError is a structured error for a team deactivation that would leave
+// open pull requests without a replacement reviewer.
+type InsufficientCapacityError struct {
+	TeamName string
+	PRIDs    []string
+}
+
+func (e *InsufficientCapacityError) Error() string {
+	return fmt.Sprintf("no active replacement reviewers for %d pull requests of team '%s': %s",
+		len(e.PRIDs), e.TeamName, strings.Join(e.PRIDs, ", "))
+}
+func (e *InsufficientCapacityError) Is(target error) bool { return target == ErrInsufficientCapacity }
+
+// AuthorQuotaExceededError is a structured error for a pull request that would exceed
+// the open pull request limit of its author.
+type AuthorQuotaExceededError struct {
+	AuthorID string
+	Limit    int
+}
+
+func (e *AuthorQuotaExceededError) Error() string {
+	return fmt.Sprintf("author '%s' already has %d open pull requests, the limit of the team", e.AuthorID, e.Limit)
+}
+func (e *AuthorQuotaExceededError) Is(target error) bool { return target == ErrAuthorQuotaExceeded }
+
+// AuthorInactiveError is a structured error for a pull request of a deactivated author.
+type AuthorInactiveError struct{ AuthorID string }
+
+func (e *AuthorInactiveError) Error() string {
+	return fmt.Sprintf("author '%s' is inactive", e.AuthorID)
+}
+func (e *AuthorInactiveError) Is(target error) bool { return target == ErrAuthorInactive }
+
+// TeamTooSmallError is a structured error for a pull request that cannot get as many reviewers as required,
+// because the team of its author has too few active members.
+type TeamTooSmallError struct {
+	AuthorID  string
+	Required  int
+	Available int
+}
+
+func (e *TeamTooSmallError) Error() string {
+	return fmt.Sprintf("team of author '%s' has %d active reviewers available, %d required",
+		e.AuthorID, e.Available, e.Required)
+}
+func (e *TeamTooSmallError) Is(target error) bool { return target == ErrTeamTooSmall }
+
+// NoCandidateError is a structured error for a reassignment that found no replacement reviewer.
+// It carries the nearest alternatives, so that an administrator can pick a replacement manually.
+type NoCandidateError struct {
+	PRID         string
+	Alternatives []domain.ReplacementAlternative
+}
+
+func (e *NoCandidateError) Error() string {
+	return fmt.Sprintf("no active replacement candidate found in team for pull request '%s'", e.PRID)
+}
+func (e *NoCandidateError) Is(target error) bool { return target == ErrNoCandidate }
+
+// ApprovalRequiredError is a structured error for a merge blocked by the reviewers who have not approved the pull request.
+type ApprovalRequiredError struct {
+	PRID        string
+	ReviewerIDs []string
+}
+
+func (e *ApprovalRequiredError) Error() string {
+	return fmt.Sprintf("pull request '%s' is waiting for the approval of: %s", e.PRID, strings.Join(e.ReviewerIDs, ", "))
+}
+func (e *ApprovalRequiredError) Is(target error) bool { return target == ErrApprovalRequired }
+
+// MergeFrozenError is a structured error for a merge rejected by a freeze window.
+type MergeFrozenError struct {
+	PRID   string
+	Reason string
+	Until  time.Time
+}
+
+func (e *MergeFrozenError) Error() string {
+	return fmt.Sprintf("merges are frozen until %s: %s", e.Until.UTC().Format(time.RFC3339), e.Reason)
+}
+func (e *MergeFrozenError) Is(target error) bool { return target == ErrMergeFrozen }
+
+// InvalidAssignmentError is a structured error for a reviewer assignment rejected by the constraints of the storage:
+// a reviewer assigned twice to the same pull request, or the author assigned to their own pull request.
+type InvalidAssignmentError struct {
+	PRID   string
+	Reason string
+}
+
+func (e *InvalidAssignmentError) Error() string {
+	return fmt.Sprintf("invalid reviewer assignment for pull request '%s': %s", e.PRID, e.Reason)
+}
+func (e *InvalidAssignmentError) Is(target error) bool { return target == ErrInvalidAssignment }
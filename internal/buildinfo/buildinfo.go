@@ -0,0 +1,56 @@
+// Package buildinfo describes the build of the running binary.
+//
+// Version, Commit and Date are set at link time:
+//
+//	go build -ldflags "-X github.com/YusovID/pr-reviewer-service/internal/buildinfo.Version=v1.2.3 \
+//		-X github.com/YusovID/pr-reviewer-service/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//		-X github.com/YusovID/pr-reviewer-service/internal/buildinfo.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+const unknown = "unknown"
+
+var (
+	// Version is the release the binary was built from.
+	Version = "dev"
+	// Commit is the SHA of the commit the binary was built from.
+	Commit = unknown
+	// Date is the build time in RFC 3339.
+	Date = unknown
+)
+
+// Info is the build of the running binary.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build of the running binary. A commit missing from the ldflags is taken
+// from the VCS stamp of the Go toolchain, if the binary has one.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: Date,
+		GoVersion: runtime.Version(),
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	for _, setting := range bi.Settings {
+		if setting.Key == "vcs.revision" && info.Commit == unknown {
+			info.Commit = setting.Value
+		}
+	}
+
+	return info
+}
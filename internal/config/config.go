@@ -3,34 +3,814 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"slices"
+	"strings"
 	"time"
+	// The runtime image has no zoneinfo, so the working hours time zones are embedded.
+	_ "time/tzdata"
 
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
+// EnvProd is the value of Config.Env in production.
+const EnvProd = "prod"
+
 type Config struct {
-	Env      string   `yml:"env" default:"local"`
-	Postgres Postgres `yml:"postgres"`
-	Server   Server   `yml:"server" env-required:"true"`
+	Env           string        `yaml:"env" env-default:"local"`
+	Postgres      Postgres      `yaml:"postgres"`
+	Server        Server        `yaml:"server" env-required:"true"`
+	PullRequests  PullRequests  `yaml:"pull_requests"`
+	Teams         Teams         `yaml:"teams"`
+	Jobs          Jobs          `yaml:"jobs"`
+	Notifications Notifications `yaml:"notifications"`
+	Outbound      Outbound      `yaml:"outbound_webhooks"`
+	Events        Events        `yaml:"events"`
+	SLO           SLO           `yaml:"slo"`
+	HTTPClient    HTTPClient    `yaml:"http_client"`
+	Webhooks      Webhooks      `yaml:"webhooks"`
+	Slack         Slack         `yaml:"slack"`
+	Auth          Auth          `yaml:"auth"`
+	OIDC          OIDC          `yaml:"oidc"`
+	CORS          CORS          `yaml:"cors"`
+	// SecurityHeaders are the security headers of the responses.
+	SecurityHeaders SecurityHeaders `yaml:"security_headers"`
+	// Compression compresses the large responses.
+	Compression Compression `yaml:"compression"`
+	// Tracing exports the traces of the requests over OTLP.
+	Tracing Tracing `yaml:"tracing"`
+	// Pprof serves the profiles of the process on an admin port.
+	Pprof Pprof `yaml:"pprof"`
+	// Redis caches the team and user reads.
+	Redis Redis `yaml:"redis"`
+	// LocalCache caches the hot reads in process.
+	LocalCache LocalCache `yaml:"local_cache"`
 }
 
 type Postgres struct {
 	Username        string        `env:"POSTGRES_USER" env-required:"true"`
 	Password        string        `env:"POSTGRES_PASSWORD" env-required:"true"`
-	Host            string        `yml:"host" env-required:"true"`
+	Host            string        `yaml:"host" env-required:"true"`
 	Port            string        `env:"POSTGRES_PORT" env-required:"true"`
 	Database        string        `env:"POSTGRES_DB" env-required:"true"`
-	MaxOpenConns    int           `yml:"max_open_conns" default:"50"`
-	MaxIdleConns    int           `yml:"max_idle_conns" default:"10"`
-	ConnMaxLifetime time.Duration `yml:"conn_max_lifetime" default:"5m"`
-	ConnMaxIdleTime time.Duration `yml:"conn_max_idle_time" default:"1m"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env-default:"50"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env-default:"10"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env-default:"5m"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env-default:"1m"`
+	// ReadDSN is the connection string of a read replica serving the query endpoints, with the pool settings
+	// of the primary. It comes from the environment only, as it holds a password; empty reads from the primary.
+	ReadDSN string `env:"POSTGRES_READ_DSN"`
+	// ReplicaCheckInterval is how often the replica is pinged; the reads go to the primary while it fails.
+	ReplicaCheckInterval time.Duration `yaml:"replica_check_interval" env:"POSTGRES_REPLICA_CHECK_INTERVAL" env-default:"5s"`
+	// TxMaxAttempts is how many times a transaction aborted by a deadlock or a serialization failure is run
+	// before the error is returned; 1 disables the retries.
+	TxMaxAttempts int `yaml:"tx_max_attempts" env:"POSTGRES_TX_MAX_ATTEMPTS" env-default:"3"`
+}
+
+// HasReplica reports whether the query endpoints read from a replica.
+func (c Postgres) HasReplica() bool {
+	return c.ReadDSN != ""
 }
 
 type Server struct {
-	Host    string        `yml:"host" default:"localhost"`
-	Port    string        `yml:"port" default:"8080"`
-	Timeout time.Duration `yml:"timeout" default:"5s"`
+	Host    string        `yaml:"host" env-default:"localhost"`
+	Port    string        `yaml:"port" env-default:"8080"`
+	Timeout time.Duration `yaml:"timeout" env-default:"5s"`
+	// ReadOnly serves GET requests only and disables the background workers, so that the instance
+	// can run against a replica or a standby database, e.g. to scale out dashboards.
+	ReadOnly bool `yaml:"read_only" env:"SERVER_READ_ONLY" env-default:"false"`
+	// Swagger serves the swagger UI on /swagger; production deployments may turn it off.
+	Swagger bool `yaml:"swagger" env:"SERVER_SWAGGER" env-default:"true"`
+	// RequestTimeout bounds the API requests outside the groups of RouteTimeouts: their context is cancelled
+	// with the queries they run, and they are answered with 504. 0 leaves them unbounded.
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"SERVER_REQUEST_TIMEOUT" env-default:"0"`
+	// RouteTimeouts bound the requests of route groups instead, e.g. a longer one for the reports.
+	RouteTimeouts []RouteTimeout `yaml:"route_timeouts"`
+	// MaxBodyBytes bounds the JSON bodies of the API requests, e.g. of the bulk team imports; larger ones
+	// are answered with 413.
+	MaxBodyBytes int64 `yaml:"max_body_bytes" env:"SERVER_MAX_BODY_BYTES" env-default:"1048576"`
+}
+
+// RouteTimeout bounds the requests under a path prefix; the longest matching prefix wins.
+type RouteTimeout struct {
+	// Prefix is the path of the group without the /v1 prefix, e.g. /stats.
+	Prefix  string        `yaml:"prefix"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Validate checks that the request timeouts run out before Timeout, which cuts the connection: a request
+// bounded by a longer timeout would never get its 504.
+func (c Server) Validate() error {
+	if c.RequestTimeout < 0 || (c.Timeout > 0 && c.RequestTimeout >= c.Timeout) {
+		return errors.New("server.request_timeout must be between 0 and server.timeout")
+	}
+
+	if c.MaxBodyBytes <= 0 {
+		return errors.New("server.max_body_bytes must be positive")
+	}
+
+	prefixes := make(map[string]bool, len(c.RouteTimeouts))
+
+	for _, rt := range c.RouteTimeouts {
+		if !strings.HasPrefix(rt.Prefix, "/") {
+			return fmt.Errorf("route timeout prefix %q must start with /", rt.Prefix)
+		}
+
+		if prefixes[rt.Prefix] {
+			return fmt.Errorf("duplicate route timeout prefix %q", rt.Prefix)
+		}
+
+		prefixes[rt.Prefix] = true
+
+		if rt.Timeout <= 0 || (c.Timeout > 0 && rt.Timeout >= c.Timeout) {
+			return fmt.Errorf("timeout of route %s must be between 0 and server.timeout", rt.Prefix)
+		}
+	}
+
+	return nil
+}
+
+// Values of PullRequests.OnDuplicateCreate.
+const (
+	DuplicateCreateConflict       = "conflict"
+	DuplicateCreateReturnExisting = "return_existing"
+)
+
+type PullRequests struct {
+	// OnDuplicateCreate selects the answer to a repeated create request for an existing PR:
+	// "conflict" responds with 409 PR_EXISTS, "return_existing" responds with 200 and the current PR state.
+	OnDuplicateCreate string `yaml:"on_duplicate_create" env:"PR_ON_DUPLICATE_CREATE" env-default:"conflict"`
+	// DefaultStrategy picks the reviewers of the teams whose policy sets no strategy weights:
+	// "random", "least_loaded" or "round_robin".
+	DefaultStrategy string `yaml:"default_strategy" env:"PR_DEFAULT_STRATEGY" env-default:"random"`
+	// PendingFillInterval is how often reviewers are assigned to the pull requests waiting in the queue;
+	// 0 disables the background filler.
+	PendingFillInterval time.Duration `yaml:"pending_fill_interval" env:"PR_PENDING_FILL_INTERVAL" env-default:"30s"`
+	// PendingFillBatch is the maximum number of queued pull requests handled per run.
+	PendingFillBatch int `yaml:"pending_fill_batch" env-default:"100"`
+	// BackfillInterval is how often the open pull requests flagged with need_more_reviewers that are not queued,
+	// e.g. those held back by the author quota, are put into the queue; 0 disables the background backfill.
+	BackfillInterval time.Duration `yaml:"backfill_interval" env:"PR_BACKFILL_INTERVAL" env-default:"5m"`
+	// RequireApprovals blocks the merge of a pull request until every assigned reviewer has approved it.
+	RequireApprovals bool `yaml:"require_approvals" env:"PR_REQUIRE_APPROVALS" env-default:"false"`
+	// StrictChecks makes the creation of a pull request check its author and their team first, answering
+	// 409 AUTHOR_INACTIVE or TEAM_TOO_SMALL instead of creating a pull request that waits for reviewers.
+	StrictChecks bool `yaml:"strict_checks" env:"PR_STRICT_CHECKS" env-default:"false"`
+	// AgeSampleInterval is how often the open pull request age metrics are recomputed; 0 disables them.
+	AgeSampleInterval time.Duration `yaml:"age_sample_interval" env:"PR_AGE_SAMPLE_INTERVAL" env-default:"1m"`
+	// StatsRefreshInterval is how often the precomputed all-time review statistics are refreshed;
+	// 0 makes the statistics be computed on every request instead.
+	StatsRefreshInterval time.Duration `yaml:"stats_refresh_interval" env:"PR_STATS_REFRESH_INTERVAL" env-default:"5m"`
+	// AsyncCreateWorkers bounds how many queued creations are processed at once; 0 disables asynchronous creation.
+	AsyncCreateWorkers int `yaml:"async_create_workers" env:"PR_ASYNC_CREATE_WORKERS" env-default:"4"`
+	// AsyncCreatePollInterval is how often the workers look for queued creations.
+	AsyncCreatePollInterval time.Duration `yaml:"async_create_poll_interval" env-default:"1s"`
+	// AsyncCreateLease is how long a creation may stay processing before it is considered abandoned and claimed again.
+	AsyncCreateLease time.Duration `yaml:"async_create_lease" env-default:"5m"`
+	// InvariantCheckRate is the share of assignments, reassignments and merges whose reviewers are checked
+	// against the reviewer invariants in production; outside production every one of them is checked.
+	InvariantCheckRate float64 `yaml:"invariant_check_rate" env:"PR_INVARIANT_CHECK_RATE" env-default:"0.01"`
+	// WorkingHours is the working time the deferred reviewer assignments (assign_at) are moved into.
+	WorkingHours WorkingHours `yaml:"working_hours"`
+}
+
+// WorkingHours is the weekly working time of the reviewers. Without Start and End a deferred assignment
+// happens at the requested time.
+type WorkingHours struct {
+	// Timezone is the IANA name of the time zone of Start and End, e.g. "Europe/Moscow".
+	Timezone string `yaml:"timezone" env:"PR_WORKING_HOURS_TIMEZONE" env-default:"UTC"`
+	// Start and End bound the working time of each working day, "HH:MM".
+	Start string `yaml:"start" env:"PR_WORKING_HOURS_START"`
+	End   string `yaml:"end" env:"PR_WORKING_HOURS_END"`
+	// Days lists the working days: mon, tue, wed, thu, fri, sat, sun.
+	Days []string `yaml:"days" env:"PR_WORKING_DAYS" env-separator:"," env-default:"mon,tue,wed,thu,fri"`
+}
+
+// weekdays maps the day names of WorkingHours.Days onto the days of the week.
+var weekdays = map[string]time.Weekday{
+	"mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday, "thu": time.Thursday,
+	"fri": time.Friday, "sat": time.Saturday, "sun": time.Sunday,
+}
+
+// Enabled reports whether the working hours are set.
+func (c WorkingHours) Enabled() bool {
+	return c.Start != "" || c.End != ""
+}
+
+// Schedule parses the working hours; it returns nil if they are not set.
+func (c WorkingHours) Schedule() (*domain.WorkingHours, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
+
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q: %w", c.Timezone, err)
+	}
+
+	start, err := parseTimeOfDay(c.Start)
+	if err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+
+	end, err := parseTimeOfDay(c.End)
+	if err != nil {
+		return nil, fmt.Errorf("end: %w", err)
+	}
+
+	if start >= end {
+		return nil, errors.New("start must be before end")
+	}
+
+	if len(c.Days) == 0 {
+		return nil, errors.New("days must not be empty")
+	}
+
+	days := make([]time.Weekday, 0, len(c.Days))
+	for _, name := range c.Days {
+		day, ok := weekdays[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q", name)
+		}
+
+		days = append(days, day)
+	}
+
+	return &domain.WorkingHours{Location: location, Start: start, End: end, Days: days}, nil
+}
+
+// parseTimeOfDay parses "HH:MM" into the offset from midnight.
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("time of day %q is not HH:MM", value)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// ReviewerCheckRate returns the share of pull request mutations whose reviewers are checked in the environment.
+func (c *Config) ReviewerCheckRate() float64 {
+	if c.Env != EnvProd {
+		return 1
+	}
+
+	return c.PullRequests.InvariantCheckRate
+}
+
+type Teams struct {
+	// DeactivationWorkers bounds how many batches of batched team deactivations are reassigned at once;
+	// 0 disables batched deactivation.
+	DeactivationWorkers int `yaml:"deactivation_workers" env:"TEAM_DEACTIVATION_WORKERS" env-default:"4"`
+	// DeactivationPollInterval is how often the workers look for pending batches.
+	DeactivationPollInterval time.Duration `yaml:"deactivation_poll_interval" env-default:"1s"`
+	// CaseInsensitiveUsernames rejects teams whose members' usernames differ only in case.
+	CaseInsensitiveUsernames bool `yaml:"case_insensitive_usernames" env:"TEAM_CASE_INSENSITIVE_USERNAMES" env-default:"false"`
+}
+
+type Jobs struct {
+	// Workers bounds how many jobs created through POST /jobs run at once; 0 disables the jobs.
+	Workers int `yaml:"workers" env:"JOB_WORKERS" env-default:"2"`
+	// PollInterval is how often an idle worker looks for queued jobs.
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"1s"`
+	// Lease is how long a job stays with its worker without a renewal; the worker renews it three times as often.
+	Lease time.Duration `yaml:"lease" env-default:"1m"`
+}
+
+type Notifications struct {
+	// LogChannel delivers every notification by writing it to the log; the deliveries are recorded
+	// and listed on /admin/notifications like those of any other channel.
+	LogChannel bool `yaml:"log_channel" env:"NOTIFICATIONS_LOG_CHANNEL" env-default:"false"`
+	// Workers is the number of goroutines delivering the notifications in the background, so that a slow
+	// channel does not hold up the request that caused the event; 0 delivers them within the request.
+	Workers int `yaml:"workers" env:"NOTIFICATIONS_WORKERS" env-default:"2"`
+	// QueueSize bounds the events waiting for a worker; the events arriving at a full queue are dropped.
+	QueueSize int `yaml:"queue_size" env-default:"1000"`
+}
+
+// Outbound configures the delivery of the PR events to the webhooks registered on /admin/webhooks.
+type Outbound struct {
+	// Workers bounds how many deliveries are in flight at once; 0 disables the delivery and no events are queued,
+	// though the webhooks can still be managed.
+	Workers int `yaml:"workers" env:"OUTBOUND_WEBHOOK_WORKERS" env-default:"4"`
+	// PollInterval is how often the workers look for due deliveries.
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"1s"`
+	// Lease is how long an attempt may take before the delivery is considered abandoned and claimed again.
+	Lease time.Duration `yaml:"lease" env-default:"1m"`
+	// MaxAttempts is the number of failed attempts after which a delivery is dead.
+	MaxAttempts int `yaml:"max_attempts" env-default:"8"`
+	// RetryBaseDelay and RetryMaxDelay bound the exponential backoff between the attempts of a delivery.
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay" env-default:"30s"`
+	RetryMaxDelay  time.Duration `yaml:"retry_max_delay" env-default:"1h"`
+}
+
+// Values of Events.Sinks.
+const (
+	EventSinkLog   = "log"
+	EventSinkKafka = "kafka"
+	EventSinkNATS  = "nats"
+)
+
+// Values of Kafka.SASLMechanism; empty disables SASL.
+const (
+	SASLPlain       = "plain"
+	SASLSCRAMSHA256 = "scram-sha-256"
+	SASLSCRAMSHA512 = "scram-sha-512"
+)
+
+// Events configures the publication of the PR events to the event sinks through the outbox.
+type Events struct {
+	// Sinks lists the sinks every event is published to; empty disables the outbox and no events are written to it.
+	Sinks []string `yaml:"sinks" env:"EVENT_SINKS" env-separator:","`
+	// PollInterval is how often the relay looks for due events.
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"1s"`
+	// BatchSize bounds the events claimed at once; the events of a batch are published in order.
+	BatchSize int `yaml:"batch_size" env-default:"100"`
+	// Lease is how long a batch may take before its unpublished events are claimed again.
+	Lease time.Duration `yaml:"lease" env-default:"1m"`
+	// RetryBaseDelay and RetryMaxDelay bound the exponential backoff between the attempts to publish an event.
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay" env-default:"1s"`
+	RetryMaxDelay  time.Duration `yaml:"retry_max_delay" env-default:"5m"`
+	// Kafka configures the kafka sink.
+	Kafka Kafka `yaml:"kafka"`
+	// NATS configures the nats sink.
+	NATS NATS `yaml:"nats"`
+}
+
+// Kafka configures the publication of the events to Kafka. Every event is keyed by its PR, so that the events
+// of a PR land on one partition in order. The SASL credentials come from the environment only.
+type Kafka struct {
+	Brokers []string `yaml:"brokers" env:"KAFKA_BROKERS" env-separator:","`
+	// Topic receives every event, unless TopicPerEvent publishes the events of each type
+	// to their own topic "<topic>.<event>", e.g. "pr-reviewer.events.pr.merged".
+	Topic         string `yaml:"topic" env:"KAFKA_TOPIC" env-default:"pr-reviewer.events"`
+	TopicPerEvent bool   `yaml:"topic_per_event" env:"KAFKA_TOPIC_PER_EVENT" env-default:"false"`
+	// WriteTimeout bounds the wait for the brokers to acknowledge an event.
+	WriteTimeout time.Duration `yaml:"write_timeout" env-default:"10s"`
+	TLS          BrokerTLS     `yaml:"tls" env-prefix:"KAFKA_"`
+	// SASLMechanism authenticates the connections with SASL/PLAIN or SASL/SCRAM; empty disables SASL.
+	SASLMechanism string `yaml:"sasl_mechanism" env:"KAFKA_SASL_MECHANISM"`
+	SASLUsername  string `env:"KAFKA_SASL_USERNAME"`
+	SASLPassword  string `env:"KAFKA_SASL_PASSWORD"`
+}
+
+// NATS configures the publication of the events to NATS JetStream. Every event is published to the subject
+// "<subject>.<event>", e.g. "pr-reviewer.events.pr.merged", which a stream must capture; an event counts
+// as published once the stream acknowledges it. The credentials come from the environment only.
+type NATS struct {
+	// URL lists the servers to connect to, separated by commas.
+	URL     string `yaml:"url" env:"NATS_URL"`
+	Subject string `yaml:"subject" env:"NATS_SUBJECT" env-default:"pr-reviewer.events"`
+	// PublishTimeout bounds the wait for the stream to acknowledge an event.
+	PublishTimeout time.Duration `yaml:"publish_timeout" env-default:"5s"`
+	// ReconnectWait is the pause between the attempts to reconnect after losing the connection.
+	// The service reconnects for as long as it runs; the events published meanwhile are retried by the outbox.
+	ReconnectWait time.Duration `yaml:"reconnect_wait" env-default:"2s"`
+	TLS           BrokerTLS     `yaml:"tls" env-prefix:"NATS_"`
+	// User and Password, or else CredsFile, authenticate the connection; none of them connects anonymously.
+	User      string `env:"NATS_USER"`
+	Password  string `env:"NATS_PASSWORD"`
+	CredsFile string `env:"NATS_CREDS_FILE"`
+}
+
+// BrokerTLS configures TLS of the connections to a message broker. The environment variables
+// are prefixed with the name of the broker, e.g. KAFKA_TLS.
+type BrokerTLS struct {
+	Enabled bool `yaml:"enabled" env:"TLS" env-default:"false"`
+	// CAFile verifies the broker certificates instead of the system roots.
+	CAFile string `yaml:"ca_file" env:"TLS_CA_FILE"`
+	// CertFile and KeyFile authenticate the service with a client certificate.
+	CertFile string `yaml:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile  string `yaml:"key_file" env:"TLS_KEY_FILE"`
+}
+
+// Enabled reports whether the events are published to any sink.
+func (c Events) Enabled() bool {
+	return len(c.Sinks) > 0
+}
+
+// Webhooks configures the inbound webhooks. The secrets come from the environment only.
+type Webhooks struct {
+	// GitHubSecret verifies the deliveries of POST /webhooks/github; empty disables the endpoint.
+	GitHubSecret string `env:"GITHUB_WEBHOOK_SECRET"`
+	// GitHubPreviousSecret is accepted as well while the secret of the GitHub webhook is rotated.
+	GitHubPreviousSecret string `env:"GITHUB_WEBHOOK_PREVIOUS_SECRET"`
+	// GitLabToken is the secret token of the deliveries of POST /webhooks/gitlab; empty disables the endpoint.
+	GitLabToken string `env:"GITLAB_WEBHOOK_TOKEN"`
+	// GitLabPreviousToken is accepted as well while the token of the GitLab webhook is rotated.
+	GitLabPreviousToken string `env:"GITLAB_WEBHOOK_PREVIOUS_TOKEN"`
+	// Shadow switches the webhooks of the providers to shadow mode.
+	Shadow WebhookShadow `yaml:"shadow"`
+}
+
+// WebhookShadow holds the feature flags of the shadow mode of the inbound webhooks, one per provider.
+// In shadow mode the deliveries are verified and processed and the reviewers of an opened pull request
+// are selected, logged and counted, but nothing is persisted; it validates a provider integration
+// on production traffic before it goes live.
+type WebhookShadow struct {
+	GitHub bool `yaml:"github" env:"GITHUB_WEBHOOK_SHADOW" env-default:"false"`
+	GitLab bool `yaml:"gitlab" env:"GITLAB_WEBHOOK_SHADOW" env-default:"false"`
+}
+
+// Slack configures the Slack notification channel, which sends a direct message to a reviewer
+// when they are assigned. The secrets come from the environment only; without either the channel is disabled.
+type Slack struct {
+	// BotToken is the token of a Slack app with the chat:write scope; the messages are posted with chat.postMessage.
+	BotToken string `env:"SLACK_BOT_TOKEN"`
+	// WebhookURL is an incoming webhook the messages are posted to, mentioning the reviewer, when there is no BotToken.
+	WebhookURL string `env:"SLACK_WEBHOOK_URL"`
+}
+
+// Enabled reports whether the Slack channel is configured.
+func (c Slack) Enabled() bool {
+	return c.BotToken != "" || c.WebhookURL != ""
+}
+
+// minServiceKeyLength keeps the service keys long enough to resist guessing.
+const minServiceKeyLength = 16
+
+// Auth configures the authentication of the API. The service keys and the static API keys come from the environment
+// only; without either the API is open and neither the stored API keys nor the read tokens are checked.
+type Auth struct {
+	// ServiceKeys are the full-access keys of the services and administrators calling the API.
+	ServiceKeys []string `env:"AUTH_SERVICE_KEYS" env-separator:","`
+	// APIKeys are the scoped keys of the services calling the API, each written as name:scopes:key with the scopes
+	// joined by "+", e.g. ci:read+write:<key>. See StaticAPIKeys.
+	APIKeys []string `env:"AUTH_API_KEYS" env-separator:","`
+	// ReadTokenRateLimit is how many requests a read-only token may make within ReadTokenRateWindow.
+	ReadTokenRateLimit  int           `yaml:"read_token_rate_limit" env:"AUTH_READ_TOKEN_RATE_LIMIT" env-default:"60"`
+	ReadTokenRateWindow time.Duration `yaml:"read_token_rate_window" env-default:"1m"`
+}
+
+// StaticAPIKey is an API key of AUTH_API_KEYS.
+type StaticAPIKey struct {
+	Name   string
+	Scopes []domain.APIKeyScope
+	Key    string
+}
+
+// Enabled reports whether the API requires a token.
+func (c Auth) Enabled() bool {
+	return len(c.ServiceKeys) > 0 || len(c.APIKeys) > 0
+}
+
+// StaticAPIKeys parses APIKeys. The key is the part after the second colon, so it may contain colons itself.
+func (c Auth) StaticAPIKeys() ([]StaticAPIKey, error) {
+	keys := make([]StaticAPIKey, 0, len(c.APIKeys))
+
+	for i, entry := range c.APIKeys {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("AUTH_API_KEYS entry %d must be written as name:scopes:key", i+1)
+		}
+
+		if len(parts[2]) < minServiceKeyLength {
+			return nil, fmt.Errorf("AUTH_API_KEYS key '%s' must be at least %d characters long", parts[0], minServiceKeyLength)
+		}
+
+		key := StaticAPIKey{Name: parts[0], Key: parts[2]}
+		for _, name := range strings.Split(parts[1], "+") {
+			scope := domain.APIKeyScope(name)
+			if !scope.IsValid() {
+				return nil, fmt.Errorf("AUTH_API_KEYS key '%s' has unknown scope '%s'", parts[0], name)
+			}
+
+			if !slices.Contains(key.Scopes, scope) {
+				key.Scopes = append(key.Scopes, scope)
+			}
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// Validate checks that the service and API keys are well formed and long enough and the rate limit
+// of the read tokens is positive.
+func (c Auth) Validate() error {
+	for _, key := range c.ServiceKeys {
+		if len(key) < minServiceKeyLength {
+			return fmt.Errorf("AUTH_SERVICE_KEYS must be at least %d characters long each", minServiceKeyLength)
+		}
+	}
+
+	if _, err := c.StaticAPIKeys(); err != nil {
+		return err
+	}
+
+	if c.ReadTokenRateLimit < 1 || c.ReadTokenRateWindow <= 0 {
+		return errors.New("auth.read_token_rate_limit and auth.read_token_rate_window must be positive")
+	}
+
+	return nil
+}
+
+// OIDC configures the sign-in through the corporate identity provider, see internal/oidc. It comes from the environment
+// only; without an issuer the sign-in is off.
+type OIDC struct {
+	// Issuer is the issuer URL of the provider, its metadata is read from /.well-known/openid-configuration under it.
+	Issuer string `env:"OIDC_ISSUER"`
+	// ClientID and ClientSecret are the credentials of the service at the provider; a public client has no secret.
+	ClientID     string `env:"OIDC_CLIENT_ID"`
+	ClientSecret string `env:"OIDC_CLIENT_SECRET"`
+	// RedirectURL is the /auth/callback address of the service the provider sends the browser back to.
+	RedirectURL string `env:"OIDC_REDIRECT_URL"`
+}
+
+// Enabled reports whether the sign-in is configured.
+func (c OIDC) Enabled() bool {
+	return c.Issuer != ""
+}
+
+// Validate checks that the issuer and the redirect URL are absolute and the client is named.
+func (c OIDC) Validate() error {
+	if u, err := url.Parse(c.Issuer); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("OIDC_ISSUER must be an absolute http(s) URL")
+	}
+
+	if c.ClientID == "" {
+		return errors.New("OIDC_CLIENT_ID is required")
+	}
+
+	if u, err := url.Parse(c.RedirectURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("OIDC_REDIRECT_URL must be an absolute http(s) URL")
+	}
+
+	return nil
+}
+
+// CORS lets the browser-based dashboards served from other origins call the API. Without allowed origins
+// the browsers keep the API to pages of its own origin.
+type CORS struct {
+	// AllowedOrigins are the origins, e.g. https://dash.example.com, whose pages may call the API; "*" allows any.
+	AllowedOrigins []string `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" env-separator:","`
+	// AllowedMethods and AllowedHeaders are the methods and request headers the pages may use.
+	AllowedMethods []string `yaml:"allowed_methods" env:"CORS_ALLOWED_METHODS" env-separator:"," env-default:"GET,HEAD,POST,PUT,DELETE"`
+	AllowedHeaders []string `yaml:"allowed_headers" env:"CORS_ALLOWED_HEADERS" env-separator:"," env-default:"Authorization,Content-Type,X-Request-ID,X-Field-Naming"`
+	// MaxAge is how long a browser may cache the answer to a preflight request.
+	MaxAge time.Duration `yaml:"max_age" env-default:"10m"`
+}
+
+// Enabled reports whether any origin is allowed.
+func (c CORS) Enabled() bool {
+	return len(c.AllowedOrigins) > 0
+}
+
+// Validate checks that the origins are "*" or a scheme and host without a path, and that the methods are named.
+func (c CORS) Validate() error {
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") ||
+			u.RawQuery != "" || u.Fragment != "" {
+			return fmt.Errorf("CORS origin %q must be * or written as scheme://host[:port]", origin)
+		}
+	}
+
+	if len(c.AllowedMethods) == 0 {
+		return errors.New("CORS_ALLOWED_METHODS must not be empty")
+	}
+
+	for _, method := range c.AllowedMethods {
+		if method == "" || strings.ToUpper(method) != method {
+			return fmt.Errorf("CORS method %q must be an upper-case method name", method)
+		}
+	}
+
+	if c.MaxAge < 0 {
+		return errors.New("cors.max_age must not be negative")
+	}
+
+	return nil
+}
+
+// SecurityHeaders configures the security headers the service adds to its responses. Each header can be turned
+// off, e.g. when a proxy in front of the service sets its own.
+type SecurityHeaders struct {
+	// Enabled adds the headers at all.
+	Enabled bool `yaml:"enabled" env:"SECURITY_HEADERS_ENABLED" env-default:"true"`
+	// NoSniff sends X-Content-Type-Options: nosniff, so that browsers keep to the declared content types.
+	NoSniff bool `yaml:"no_sniff" env:"SECURITY_HEADERS_NO_SNIFF" env-default:"true"`
+	// FrameOptions is X-Frame-Options, DENY or SAMEORIGIN; empty omits the header.
+	FrameOptions string `yaml:"frame_options" env:"SECURITY_HEADERS_FRAME_OPTIONS" env-default:"DENY"`
+	// HSTSMaxAge is the max-age of Strict-Transport-Security, sent in answer to the requests that came over HTTPS,
+	// directly or through a proxy; 0 omits the header.
+	HSTSMaxAge time.Duration `yaml:"hsts_max_age" env:"SECURITY_HEADERS_HSTS_MAX_AGE" env-default:"8760h"`
+	// HSTSIncludeSubdomains extends HSTS to the subdomains of the host.
+	HSTSIncludeSubdomains bool `yaml:"hsts_include_subdomains" env:"SECURITY_HEADERS_HSTS_INCLUDE_SUBDOMAINS" env-default:"false"`
+	// SwaggerCSP is the Content-Security-Policy of the swagger UI; empty omits the header. The UI needs its own
+	// scripts, inline styles and data: images.
+	SwaggerCSP string `yaml:"swagger_csp" env:"SECURITY_HEADERS_SWAGGER_CSP" env-default:"default-src 'self'; script-src 'self'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; connect-src 'self'; frame-ancestors 'none'; base-uri 'self'; form-action 'self'"`
+}
+
+// Validate checks that the frame options are known and that the max-age of HSTS is not negative.
+func (c SecurityHeaders) Validate() error {
+	switch c.FrameOptions {
+	case "", "DENY", "SAMEORIGIN":
+	default:
+		return fmt.Errorf("security_headers.frame_options must be DENY, SAMEORIGIN or empty, got %q", c.FrameOptions)
+	}
+
+	if c.HSTSMaxAge < 0 {
+		return errors.New("security_headers.hsts_max_age must not be negative")
+	}
+
+	return nil
+}
+
+// Compression configures the gzip compression of the responses to the clients that accept it.
+type Compression struct {
+	// Enabled compresses the responses at all.
+	Enabled bool `yaml:"enabled" env:"COMPRESSION_ENABLED" env-default:"true"`
+	// MinSize is the size in bytes from which a response is compressed: the smaller ones would barely shrink,
+	// and the compression would cost more than it saves.
+	MinSize int `yaml:"min_size" env:"COMPRESSION_MIN_SIZE" env-default:"1024"`
+	// Level is the gzip level from 1, the fastest, to 9, the smallest.
+	Level int `yaml:"level" env:"COMPRESSION_LEVEL" env-default:"5"`
+	// ContentTypes are the media types that are compressed; "text/*" stands for every text type.
+	// The others, e.g. the images of the swagger UI, are compressed already.
+	ContentTypes []string `yaml:"content_types" env:"COMPRESSION_CONTENT_TYPES" env-separator:"," env-default:"application/json,application/yaml,text/*"`
+}
+
+// Validate checks the size threshold, the level and the media types.
+func (c Compression) Validate() error {
+	if c.MinSize < 0 {
+		return errors.New("compression.min_size must not be negative")
+	}
+
+	if c.Level < 1 || c.Level > 9 {
+		return errors.New("compression.level must be between 1 and 9")
+	}
+
+	if len(c.ContentTypes) == 0 {
+		return errors.New("COMPRESSION_CONTENT_TYPES must not be empty")
+	}
+
+	for _, contentType := range c.ContentTypes {
+		if kind, subtype, ok := strings.Cut(contentType, "/"); !ok || kind == "" || subtype == "" {
+			return fmt.Errorf("compression content type %q must be written as type/subtype", contentType)
+		}
+	}
+
+	return nil
+}
+
+// Tracing configures the OpenTelemetry traces of the HTTP requests, the service operations and the SQL queries,
+// exported over OTLP/HTTP to a collector.
+type Tracing struct {
+	// Endpoint is the URL of the collector, e.g. http://otel-collector:4318; empty disables tracing.
+	Endpoint string `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+	// ServiceName names the service in the traces.
+	ServiceName string `yaml:"service_name" env:"TRACING_SERVICE_NAME" env-default:"pr-reviewer-service"`
+	// SampleRatio is the share of the traces started by the service that are recorded; the traces continued
+	// from a caller follow its decision.
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+}
+
+// Enabled reports whether the traces are exported.
+func (c Tracing) Enabled() bool {
+	return c.Endpoint != ""
+}
+
+// Validate checks the URL of the collector and the sample ratio.
+func (c Tracing) Validate() error {
+	u, err := url.Parse(c.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("TRACING_ENDPOINT %q must be an http or https URL", c.Endpoint)
+	}
+
+	if c.ServiceName == "" {
+		return errors.New("TRACING_SERVICE_NAME must not be empty")
+	}
+
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return errors.New("TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+
+	return nil
+}
+
+// Pprof configures the net/http/pprof endpoints under /debug/pprof. They are served on their own admin
+// address, apart from the API, so that the profiles are not exposed with it; the host defaults to the loopback.
+type Pprof struct {
+	Enabled bool   `yaml:"enabled" env:"PPROF_ENABLED" env-default:"false"`
+	Host    string `yaml:"host" env:"PPROF_HOST" env-default:"localhost"`
+	Port    string `yaml:"port" env:"PPROF_PORT" env-default:"6060"`
+}
+
+// Validate checks that the admin port is set and differs from the port of the API.
+func (c Pprof) Validate(server Server) error {
+	if c.Port == "" {
+		return errors.New("pprof.port must not be empty")
+	}
+
+	if c.Port == server.Port {
+		return fmt.Errorf("pprof.port must differ from server.port %s, so that the profiles are not served with the API", server.Port)
+	}
+
+	return nil
+}
+
+// Redis configures the cache of the team and user reads in Redis, see internal/repository/cached.
+// Without an address the cache is disabled and every read goes to PostgreSQL. The password comes
+// from the environment only.
+type Redis struct {
+	Addr     string `yaml:"addr" env:"REDIS_ADDR"`
+	Password string `env:"REDIS_PASSWORD"`
+	DB       int    `yaml:"db" env:"REDIS_DB" env-default:"0"`
+	// TeamTTL and UserTTL bound how long a team with its members and the data of a user stay cached,
+	// and so how long a read racing a write may serve the data as of before the write.
+	TeamTTL time.Duration `yaml:"team_ttl" env:"REDIS_TEAM_TTL" env-default:"1m"`
+	UserTTL time.Duration `yaml:"user_ttl" env:"REDIS_USER_TTL" env-default:"1m"`
+	// Timeout bounds every Redis command; a read whose command fails or times out goes to PostgreSQL.
+	Timeout time.Duration `yaml:"timeout" env:"REDIS_TIMEOUT" env-default:"100ms"`
+}
+
+// Enabled reports whether the cache is configured.
+func (c Redis) Enabled() bool {
+	return c.Addr != ""
+}
+
+// Validate checks that the cached values expire and the commands are bounded.
+func (c Redis) Validate() error {
+	if c.TeamTTL <= 0 || c.UserTTL <= 0 {
+		return errors.New("redis.team_ttl and redis.user_ttl must be positive")
+	}
+
+	if c.Timeout <= 0 {
+		return errors.New("redis.timeout must be positive")
+	}
+
+	return nil
+}
+
+// LocalCache configures the in-process cache of GET /team/get and GET /users/getReview, see internal/localcache.
+// Every instance invalidates it on its own writes; the writes of other instances are seen once the entries
+// expire after TTL. A zero size disables the cache.
+type LocalCache struct {
+	Size int           `yaml:"size" env:"LOCAL_CACHE_SIZE" env-default:"0"`
+	TTL  time.Duration `yaml:"ttl" env:"LOCAL_CACHE_TTL" env-default:"5s"`
+}
+
+// Enabled reports whether the cache is configured.
+func (c LocalCache) Enabled() bool {
+	return c.Size > 0
+}
+
+// Validate checks that the size is not negative and the entries of an enabled cache expire.
+func (c LocalCache) Validate() error {
+	if c.Size < 0 {
+		return errors.New("local_cache.size must not be negative")
+	}
+
+	if c.Enabled() && c.TTL <= 0 {
+		return errors.New("local_cache.ttl must be positive")
+	}
+
+	return nil
+}
+
+// HTTPClient configures the shared client of the outbound integrations, see internal/httpclient.
+type HTTPClient struct {
+	// Timeout bounds a single attempt, including reading the response body.
+	Timeout time.Duration `yaml:"timeout" env-default:"5s"`
+	// MaxRetries is how many times a failed idempotent request is repeated; 0 disables retries.
+	MaxRetries int `yaml:"max_retries" env-default:"2"`
+	// RetryBaseDelay and RetryMaxDelay bound the exponential backoff with full jitter between attempts.
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay" env-default:"100ms"`
+	RetryMaxDelay  time.Duration `yaml:"retry_max_delay" env-default:"2s"`
+	// BreakerFailures is the number of consecutive failures that opens the circuit breaker of a host.
+	BreakerFailures int `yaml:"breaker_failures" env-default:"5"`
+	// BreakerOpenTimeout is how long an open circuit rejects requests before a trial request is let through.
+	BreakerOpenTimeout time.Duration `yaml:"breaker_open_timeout" env-default:"30s"`
+}
+
+// SLO holds the service level objectives that alerting rules and the /slo report are built from.
+type SLO struct {
+	// Window is the period the error budget is defined over.
+	Window     time.Duration  `yaml:"window" env-default:"720h"`
+	Objectives []SLOObjective `yaml:"objectives"`
+}
+
+// SLOObjective sets the availability and latency targets of a single endpoint.
+// Either target may be left out.
+type SLOObjective struct {
+	Name   string `yaml:"name"`
+	Method string `yaml:"method"`
+	Path   string `yaml:"path"`
+	// Availability is the target share of requests answered without a 5xx status, e.g. 0.999.
+	Availability float64 `yaml:"availability"`
+	// Latency is the response time a request must fit into; it must be one of metrics.HTTPDurationBuckets.
+	Latency time.Duration `yaml:"latency"`
+	// LatencyTarget is the target share of requests that fit into Latency, e.g. 0.99.
+	LatencyTarget float64 `yaml:"latency_target"`
 }
 
 func Load() (*Config, error) {
@@ -48,5 +828,375 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("cannot read config: %w", err)
 	}
 
+	if err := cfg.Server.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid server config: %w", err)
+	}
+
+	switch cfg.PullRequests.OnDuplicateCreate {
+	case DuplicateCreateConflict, DuplicateCreateReturnExisting:
+	default:
+		return nil, fmt.Errorf("unknown pull_requests.on_duplicate_create value %q", cfg.PullRequests.OnDuplicateCreate)
+	}
+
+	if !domain.AssignmentStrategy(cfg.PullRequests.DefaultStrategy).IsValid() {
+		return nil, fmt.Errorf("unknown pull_requests.default_strategy value %q", cfg.PullRequests.DefaultStrategy)
+	}
+
+	if cfg.PullRequests.PendingFillInterval < 0 {
+		return nil, errors.New("pull_requests.pending_fill_interval must not be negative")
+	}
+
+	if cfg.PullRequests.BackfillInterval < 0 {
+		return nil, errors.New("pull_requests.backfill_interval must not be negative")
+	}
+
+	if cfg.PullRequests.AgeSampleInterval < 0 {
+		return nil, errors.New("pull_requests.age_sample_interval must not be negative")
+	}
+
+	if cfg.PullRequests.StatsRefreshInterval < 0 {
+		return nil, errors.New("pull_requests.stats_refresh_interval must not be negative")
+	}
+
+	if cfg.PullRequests.PendingFillBatch < 1 || cfg.PullRequests.PendingFillBatch > 100 {
+		return nil, errors.New("pull_requests.pending_fill_batch must be between 1 and 100")
+	}
+
+	if cfg.PullRequests.AsyncCreateWorkers < 0 || cfg.PullRequests.AsyncCreateWorkers > 100 {
+		return nil, errors.New("pull_requests.async_create_workers must be between 0 and 100")
+	}
+
+	if cfg.PullRequests.AsyncCreateWorkers > 0 && (cfg.PullRequests.AsyncCreatePollInterval <= 0 || cfg.PullRequests.AsyncCreateLease <= 0) {
+		return nil, errors.New("pull_requests.async_create_poll_interval and async_create_lease must be positive")
+	}
+
+	if cfg.PullRequests.InvariantCheckRate < 0 || cfg.PullRequests.InvariantCheckRate > 1 {
+		return nil, errors.New("pull_requests.invariant_check_rate must be between 0 and 1")
+	}
+
+	if _, err := cfg.PullRequests.WorkingHours.Schedule(); err != nil {
+		return nil, fmt.Errorf("invalid pull_requests.working_hours: %w", err)
+	}
+
+	if cfg.Teams.DeactivationWorkers < 0 || cfg.Teams.DeactivationWorkers > 100 {
+		return nil, errors.New("teams.deactivation_workers must be between 0 and 100")
+	}
+
+	if cfg.Teams.DeactivationWorkers > 0 && cfg.Teams.DeactivationPollInterval <= 0 {
+		return nil, errors.New("teams.deactivation_poll_interval must be positive")
+	}
+
+	if cfg.Jobs.Workers < 0 || cfg.Jobs.Workers > 100 {
+		return nil, errors.New("jobs.workers must be between 0 and 100")
+	}
+
+	if cfg.Jobs.Workers > 0 && (cfg.Jobs.PollInterval <= 0 || cfg.Jobs.Lease <= 0) {
+		return nil, errors.New("jobs.poll_interval and jobs.lease must be positive")
+	}
+
+	if cfg.Outbound.Workers < 0 || cfg.Outbound.Workers > 100 {
+		return nil, errors.New("outbound_webhooks.workers must be between 0 and 100")
+	}
+
+	if cfg.Outbound.Workers > 0 {
+		if err := cfg.Outbound.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid outbound_webhooks config: %w", err)
+		}
+	}
+
+	if cfg.Events.Enabled() {
+		if err := cfg.Events.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid events config: %w", err)
+		}
+	}
+
+	if cfg.Notifications.Workers < 0 || cfg.Notifications.Workers > 100 {
+		return nil, errors.New("notifications.workers must be between 0 and 100")
+	}
+
+	if cfg.Notifications.Workers > 0 && cfg.Notifications.QueueSize < 1 {
+		return nil, errors.New("notifications.queue_size must be positive")
+	}
+
+	if err := cfg.Slack.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid slack config: %w", err)
+	}
+
+	if cfg.Auth.Enabled() {
+		if err := cfg.Auth.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid auth config: %w", err)
+		}
+	}
+
+	if cfg.OIDC.Enabled() {
+		// Without the keys the API is open, and the ID tokens would open nothing.
+		if !cfg.Auth.Enabled() {
+			return nil, errors.New("OIDC_ISSUER requires AUTH_SERVICE_KEYS or AUTH_API_KEYS")
+		}
+
+		if err := cfg.OIDC.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid oidc config: %w", err)
+		}
+	}
+
+	if cfg.CORS.Enabled() {
+		if err := cfg.CORS.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid cors config: %w", err)
+		}
+	}
+
+	if cfg.SecurityHeaders.Enabled {
+		if err := cfg.SecurityHeaders.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid security_headers config: %w", err)
+		}
+	}
+
+	if cfg.Compression.Enabled {
+		if err := cfg.Compression.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid compression config: %w", err)
+		}
+	}
+
+	if cfg.Tracing.Enabled() {
+		if err := cfg.Tracing.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid tracing config: %w", err)
+		}
+	}
+
+	if cfg.Pprof.Enabled {
+		if err := cfg.Pprof.Validate(cfg.Server); err != nil {
+			return nil, fmt.Errorf("invalid pprof config: %w", err)
+		}
+	}
+
+	if cfg.Postgres.HasReplica() && cfg.Postgres.ReplicaCheckInterval <= 0 {
+		return nil, errors.New("postgres.replica_check_interval must be positive")
+	}
+
+	if cfg.Postgres.TxMaxAttempts < 1 {
+		return nil, errors.New("postgres.tx_max_attempts must be at least 1")
+	}
+
+	if cfg.Redis.Enabled() {
+		if err := cfg.Redis.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid redis config: %w", err)
+		}
+	}
+
+	if err := cfg.LocalCache.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid local cache config: %w", err)
+	}
+
+	if err := cfg.SLO.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid slo config: %w", err)
+	}
+
+	if err := cfg.HTTPClient.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid http_client config: %w", err)
+	}
+
 	return &cfg, nil
 }
+
+// Validate checks that the intervals are positive and the backoff bounds are ordered.
+func (c Outbound) Validate() error {
+	if c.PollInterval <= 0 || c.Lease <= 0 {
+		return errors.New("poll_interval and lease must be positive")
+	}
+
+	if c.MaxAttempts < 1 {
+		return errors.New("max_attempts must be at least 1")
+	}
+
+	if c.RetryBaseDelay <= 0 || c.RetryMaxDelay < c.RetryBaseDelay {
+		return errors.New("retry_base_delay must be positive and not greater than retry_max_delay")
+	}
+
+	return nil
+}
+
+// Validate checks that the sinks are known and unique, the intervals are positive and the backoff bounds are ordered.
+func (c Events) Validate() error {
+	seen := make(map[string]bool, len(c.Sinks))
+
+	for _, sink := range c.Sinks {
+		switch sink {
+		case EventSinkLog:
+		case EventSinkKafka:
+			if err := c.Kafka.Validate(); err != nil {
+				return fmt.Errorf("invalid kafka config: %w", err)
+			}
+		case EventSinkNATS:
+			if err := c.NATS.Validate(); err != nil {
+				return fmt.Errorf("invalid nats config: %w", err)
+			}
+		default:
+			return fmt.Errorf("unknown sink %q", sink)
+		}
+
+		if seen[sink] {
+			return fmt.Errorf("duplicate sink %q", sink)
+		}
+
+		seen[sink] = true
+	}
+
+	if c.PollInterval <= 0 || c.Lease <= 0 {
+		return errors.New("poll_interval and lease must be positive")
+	}
+
+	if c.BatchSize < 1 || c.BatchSize > 1000 {
+		return errors.New("batch_size must be between 1 and 1000")
+	}
+
+	if c.RetryBaseDelay <= 0 || c.RetryMaxDelay < c.RetryBaseDelay {
+		return errors.New("retry_base_delay must be positive and not greater than retry_max_delay")
+	}
+
+	return nil
+}
+
+// Validate checks that the brokers and the topic are set and SASL, if enabled, has a known mechanism and credentials.
+func (c Kafka) Validate() error {
+	if len(c.Brokers) == 0 {
+		return errors.New("KAFKA_BROKERS must list at least one broker")
+	}
+
+	if c.Topic == "" {
+		return errors.New("topic must be set")
+	}
+
+	if c.WriteTimeout <= 0 {
+		return errors.New("write_timeout must be positive")
+	}
+
+	switch c.SASLMechanism {
+	case "":
+		return c.TLS.Validate()
+	case SASLPlain, SASLSCRAMSHA256, SASLSCRAMSHA512:
+	default:
+		return fmt.Errorf("unknown sasl_mechanism %q", c.SASLMechanism)
+	}
+
+	if c.SASLUsername == "" || c.SASLPassword == "" {
+		return errors.New("KAFKA_SASL_USERNAME and KAFKA_SASL_PASSWORD must be set for SASL")
+	}
+
+	return c.TLS.Validate()
+}
+
+// Validate checks that the servers and the subject are set, the timeouts are positive
+// and a user comes with a password.
+func (c NATS) Validate() error {
+	if c.URL == "" {
+		return errors.New("NATS_URL must be set")
+	}
+
+	if c.Subject == "" || strings.ContainsAny(c.Subject, " *>") {
+		return errors.New("subject must be set and contain no spaces or wildcards")
+	}
+
+	if c.PublishTimeout <= 0 || c.ReconnectWait <= 0 {
+		return errors.New("publish_timeout and reconnect_wait must be positive")
+	}
+
+	if (c.User == "") != (c.Password == "") {
+		return errors.New("NATS_USER and NATS_PASSWORD must be set together")
+	}
+
+	return c.TLS.Validate()
+}
+
+// Validate checks that the client certificate, if any, comes with its key.
+func (c BrokerTLS) Validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("tls cert_file and key_file must be set together")
+	}
+
+	return nil
+}
+
+// Validate checks that the webhook URL, if any, is an absolute HTTP(S) URL.
+func (c Slack) Validate() error {
+	if c.WebhookURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(c.WebhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("SLACK_WEBHOOK_URL must be an absolute http(s) URL")
+	}
+
+	return nil
+}
+
+// Validate checks that the timeouts are positive and the backoff bounds are ordered.
+func (c HTTPClient) Validate() error {
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+
+	if c.MaxRetries < 0 {
+		return errors.New("max_retries must not be negative")
+	}
+
+	if c.RetryBaseDelay <= 0 || c.RetryMaxDelay < c.RetryBaseDelay {
+		return errors.New("retry_base_delay must be positive and not greater than retry_max_delay")
+	}
+
+	if c.BreakerFailures < 1 {
+		return errors.New("breaker_failures must be at least 1")
+	}
+
+	if c.BreakerOpenTimeout <= 0 {
+		return errors.New("breaker_open_timeout must be positive")
+	}
+
+	return nil
+}
+
+// Validate checks that every objective names an endpoint and has sensible targets.
+func (c SLO) Validate() error {
+	if c.Window <= 0 {
+		return errors.New("window must be positive")
+	}
+
+	names := make(map[string]bool, len(c.Objectives))
+
+	for _, o := range c.Objectives {
+		if o.Name == "" || o.Method == "" || o.Path == "" {
+			return errors.New("objective name, method and path are required")
+		}
+
+		if names[o.Name] {
+			return fmt.Errorf("duplicate objective %q", o.Name)
+		}
+
+		names[o.Name] = true
+
+		if o.Availability == 0 && o.Latency == 0 {
+			return fmt.Errorf("objective %q sets neither availability nor latency", o.Name)
+		}
+
+		if o.Availability != 0 && !isRatio(o.Availability) {
+			return fmt.Errorf("objective %q: availability must be between 0 and 1", o.Name)
+		}
+
+		if o.Latency != 0 {
+			if !isRatio(o.LatencyTarget) {
+				return fmt.Errorf("objective %q: latency_target must be between 0 and 1", o.Name)
+			}
+
+			// Latency compliance is read from the histogram, so the threshold has to be a bucket boundary.
+			if !slices.Contains(metrics.HTTPDurationBuckets, o.Latency.Seconds()) {
+				return fmt.Errorf("objective %q: latency %s is not a bucket of %s", o.Name, o.Latency, metrics.HTTPRequestDuration.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isRatio(v float64) bool {
+	return v > 0 && v < 1
+}
@@ -0,0 +1,65 @@
+// Package creator processes the pull request creations queued through POST /pullRequest/createAsync.
+// A fixed number of workers bounds how many creations run at once, so that a burst of requests
+// queues up instead of exhausting the database connections.
+package creator
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/poller"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// CreateRequestProcessor is the part of service.PRCommandService the creator drives.
+type CreateRequestProcessor interface {
+	ClaimCreatePRRequests(ctx context.Context, limit int) ([]domain.CreatePRRequest, error)
+	ProcessCreatePRRequest(ctx context.Context, req domain.CreatePRRequest) error
+}
+
+// Creator polls the creation queue and processes the claimed creations concurrently.
+type Creator struct {
+	log     *slog.Logger
+	prs     CreateRequestProcessor
+	workers int
+	poller  *poller.Poller
+}
+
+func New(log *slog.Logger, prs CreateRequestProcessor, interval time.Duration, workers int) *Creator {
+	c := &Creator{
+		log:     log.With(slog.String("component", "creator")),
+		prs:     prs,
+		workers: workers,
+	}
+	c.poller = poller.New(interval, workers, c.process)
+
+	return c
+}
+
+// Run drains the queue once per interval until ctx is cancelled.
+func (c *Creator) Run(ctx context.Context) {
+	c.poller.Run(ctx)
+}
+
+// process claims a batch of at most one creation per worker, processes it and returns its size.
+func (c *Creator) process(ctx context.Context) int {
+	claimed, err := c.prs.ClaimCreatePRRequests(ctx, c.workers)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.log.Error("failed to claim pr creation requests", sl.Err(err))
+		}
+
+		return 0
+	}
+
+	poller.Each(claimed, func(req domain.CreatePRRequest) {
+		// A creation whose outcome was not recorded is claimed again once its lease expires.
+		if err := c.prs.ProcessCreatePRRequest(ctx, req); err != nil && ctx.Err() == nil {
+			c.log.Error("failed to process pr creation request", slog.Int64("request_id", req.ID), sl.Err(err))
+		}
+	})
+
+	return len(claimed)
+}
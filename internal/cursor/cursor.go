@@ -0,0 +1,85 @@
+// Package cursor encodes the position of a list page into the opaque next_cursor of the API and back.
+// A position is the sort key of the last element of the page, so that the next page starts right after it
+// without skipping rows (keyset pagination) and is not shifted by the rows added in between.
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrMalformed is returned for a cursor that was not made by the matching Encode function.
+var ErrMalformed = errors.New("malformed cursor")
+
+// separator joins the parts of a key. Only the last part may contain it, see Decode.
+const separator = "|"
+
+// Encode returns the cursor of the key made of parts, most significant first.
+func Encode(parts ...string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(parts, separator)))
+}
+
+// Decode returns the n non-empty parts of the key of a cursor made by Encode. The last part is read
+// whole, so it may contain the separator, e.g. when it is an ID chosen by a client.
+func Decode(cursor string, n int) ([]string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	parts := strings.SplitN(string(raw), separator, n)
+	if len(parts) != n {
+		return nil, ErrMalformed
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			return nil, ErrMalformed
+		}
+	}
+
+	return parts, nil
+}
+
+// EncodeTime returns the cursor of a position ordered by time and then by ID.
+func EncodeTime(at time.Time, id string) string {
+	return Encode(at.UTC().Format(time.RFC3339Nano), id)
+}
+
+// DecodeTime returns the time and the ID of a cursor made by EncodeTime.
+func DecodeTime(cursor string) (time.Time, string, error) {
+	parts, err := Decode(cursor, 2)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+
+	at, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, "", ErrMalformed
+	}
+
+	return at, parts[1], nil
+}
+
+// EncodeInt returns the cursor of a position given by a single number, e.g. a serial ID.
+func EncodeInt(n int64) string {
+	return Encode(strconv.FormatInt(n, 10))
+}
+
+// DecodeInt returns the number of a cursor made by EncodeInt. Negative numbers are malformed.
+func DecodeInt(cursor string) (int64, error) {
+	parts, err := Decode(cursor, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || n < 0 {
+		return 0, ErrMalformed
+	}
+
+	return n, nil
+}
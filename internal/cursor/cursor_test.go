@@ -0,0 +1,50 @@
+package cursor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeTime_RoundTrip(t *testing.T) {
+	at := time.Date(2025, 3, 14, 12, 0, 0, 123456000, time.FixedZone("MSK", 3*3600))
+
+	decodedAt, id, err := DecodeTime(EncodeTime(at, "pr|with-separator"))
+	require.NoError(t, err)
+	assert.Equal(t, "pr|with-separator", id)
+	assert.True(t, at.Equal(decodedAt))
+}
+
+func TestEncodeTime_KeepsFormat(t *testing.T) {
+	// Cursors handed out before the package existed must stay valid.
+	assert.Equal(t, "MjAyNS0wMy0xNFQxMjowMDowMFp8cHItMTAwMQ", EncodeTime(time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC), "pr-1001"))
+}
+
+func TestEncodeInt_RoundTrip(t *testing.T) {
+	n, err := DecodeInt(EncodeInt(42))
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), n)
+}
+
+func TestDecode_Malformed(t *testing.T) {
+	testCases := []struct {
+		name   string
+		decode func() error
+	}{
+		{name: "Not base64", decode: func() error { _, _, err := DecodeTime("not a cursor"); return err }},
+		{name: "Time without ID", decode: func() error { _, _, err := DecodeTime(Encode("2025-03-01T00:00:00Z", "")); return err }},
+		{name: "Missing part", decode: func() error { _, _, err := DecodeTime(Encode("2025-03-01T00:00:00Z")); return err }},
+		{name: "Bad time", decode: func() error { _, _, err := DecodeTime(Encode("yesterday", "pr-1")); return err }},
+		{name: "Bad number", decode: func() error { _, err := DecodeInt(Encode("forty-two")); return err }},
+		{name: "Negative number", decode: func() error { _, err := DecodeInt(EncodeInt(-1)); return err }},
+		{name: "Empty", decode: func() error { _, err := DecodeInt(""); return err }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorIs(t, tc.decode(), ErrMalformed)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Package deactivator reassigns the reviews of batched team deactivations in the background.
+// A fixed number of workers bounds how many batches are reassigned at once, so that deactivating
+// a large team does not hold a single long transaction nor take over the database connections.
+package deactivator
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/poller"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// BatchProcessor is the part of service.UserService the deactivator drives.
+type BatchProcessor interface {
+	ProcessDeactivationBatch(ctx context.Context) (bool, error)
+}
+
+// Deactivator polls for pending batches and processes them with a pool of workers.
+type Deactivator struct {
+	log     *slog.Logger
+	users   BatchProcessor
+	workers int
+	poller  *poller.Poller
+}
+
+func New(log *slog.Logger, users BatchProcessor, interval time.Duration, workers int) *Deactivator {
+	d := &Deactivator{
+		log:     log.With(slog.String("component", "deactivator")),
+		users:   users,
+		workers: workers,
+	}
+	d.poller = poller.New(interval, workers, d.process)
+
+	return d
+}
+
+// Run drains the pending batches once per interval until ctx is cancelled.
+func (d *Deactivator) Run(ctx context.Context) {
+	d.poller.Run(ctx)
+}
+
+// process has every worker process one pending batch and returns how many batches were processed.
+func (d *Deactivator) process(ctx context.Context) int {
+	var processed atomic.Int32
+
+	poller.Each(make([]struct{}, d.workers), func(struct{}) {
+		ok, err := d.users.ProcessDeactivationBatch(ctx)
+		if err != nil {
+			// The batch stays pending and is retried on the next run.
+			if ctx.Err() == nil {
+				d.log.Error("failed to process deactivation batch", sl.Err(err))
+			}
+
+			return
+		}
+
+		if ok {
+			processed.Add(1)
+		}
+	})
+
+	return int(processed.Load())
+}
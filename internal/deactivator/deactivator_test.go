@@ -0,0 +1,75 @@
+package deactivator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBatches hands out a fixed number of batches and fails every third call.
+type fakeBatches struct {
+	pending atomic.Int32
+	calls   atomic.Int32
+
+	running    atomic.Int32
+	maxRunning atomic.Int32
+}
+
+func (f *fakeBatches) ProcessDeactivationBatch(_ context.Context) (bool, error) {
+	running := f.running.Add(1)
+	defer f.running.Add(-1)
+
+	for {
+		peak := f.maxRunning.Load()
+		if running <= peak || f.maxRunning.CompareAndSwap(peak, running) {
+			break
+		}
+	}
+
+	time.Sleep(time.Millisecond)
+
+	if f.calls.Add(1)%3 == 0 {
+		return false, errors.New("db is down")
+	}
+
+	if f.pending.Add(-1) < 0 {
+		f.pending.Store(0)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func TestDeactivator_DrainsBatchesWithBoundedWorkers(t *testing.T) {
+	batches := &fakeBatches{}
+	batches.pending.Store(20)
+
+	d := New(slog.New(slog.NewTextHandler(io.Discard, nil)), batches, time.Millisecond, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+
+	// Failed batches are logged and retried on a later run.
+	assert.Eventually(t, func() bool { return batches.pending.Load() == 0 }, time.Second, time.Millisecond)
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deactivator did not stop after cancellation")
+	}
+
+	assert.LessOrEqual(t, batches.maxRunning.Load(), int32(3))
+}
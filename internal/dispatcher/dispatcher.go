@@ -0,0 +1,65 @@
+// Package dispatcher delivers the PR events queued for the outbound webhooks, see service.WebhookService.
+// A fixed number of workers bounds how many deliveries are in flight at once, so that a burst of events
+// or a slow webhook queues the deliveries up instead of exhausting the connections.
+package dispatcher
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/poller"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// DeliveryProcessor is the part of service.WebhookService the dispatcher drives.
+type DeliveryProcessor interface {
+	ClaimWebhookDeliveries(ctx context.Context, limit int) ([]domain.WebhookDelivery, error)
+	ProcessWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) error
+}
+
+// Dispatcher polls the delivery queue and makes the attempts of the claimed deliveries concurrently.
+type Dispatcher struct {
+	log      *slog.Logger
+	webhooks DeliveryProcessor
+	workers  int
+	poller   *poller.Poller
+}
+
+func New(log *slog.Logger, webhooks DeliveryProcessor, interval time.Duration, workers int) *Dispatcher {
+	d := &Dispatcher{
+		log:      log.With(slog.String("component", "dispatcher")),
+		webhooks: webhooks,
+		workers:  workers,
+	}
+	d.poller = poller.New(interval, workers, d.process)
+
+	return d
+}
+
+// Run drains the queue once per interval until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	d.poller.Run(ctx)
+}
+
+// process claims a batch of at most one delivery per worker, attempts it and returns its size.
+func (d *Dispatcher) process(ctx context.Context) int {
+	claimed, err := d.webhooks.ClaimWebhookDeliveries(ctx, d.workers)
+	if err != nil {
+		if ctx.Err() == nil {
+			d.log.Error("failed to claim webhook deliveries", sl.Err(err))
+		}
+
+		return 0
+	}
+
+	poller.Each(claimed, func(delivery domain.WebhookDelivery) {
+		// A delivery whose attempt was not recorded is claimed again once its lease expires.
+		if err := d.webhooks.ProcessWebhookDelivery(ctx, delivery); err != nil && ctx.Err() == nil {
+			d.log.Error("failed to process webhook delivery", slog.Int64("delivery_id", delivery.ID), sl.Err(err))
+		}
+	})
+
+	return len(claimed)
+}
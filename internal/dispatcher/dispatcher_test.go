@@ -0,0 +1,109 @@
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeQueue hands out the due deliveries in batches and records the ones processed.
+type fakeQueue struct {
+	mu        sync.Mutex
+	queued    []domain.WebhookDelivery
+	processed []int64
+	limits    []int
+
+	running    atomic.Int32
+	maxRunning atomic.Int32
+}
+
+func (q *fakeQueue) ClaimWebhookDeliveries(_ context.Context, limit int) ([]domain.WebhookDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.limits = append(q.limits, limit)
+
+	n := min(limit, len(q.queued))
+	claimed := q.queued[:n]
+	q.queued = q.queued[n:]
+
+	return claimed, nil
+}
+
+func (q *fakeQueue) ProcessWebhookDelivery(_ context.Context, delivery domain.WebhookDelivery) error {
+	running := q.running.Add(1)
+	defer q.running.Add(-1)
+
+	for {
+		peak := q.maxRunning.Load()
+		if running <= peak || q.maxRunning.CompareAndSwap(peak, running) {
+			break
+		}
+	}
+
+	time.Sleep(time.Millisecond)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.processed = append(q.processed, delivery.ID)
+
+	if delivery.ID%2 == 0 {
+		return errors.New("webhook is down")
+	}
+
+	return nil
+}
+
+func (q *fakeQueue) processedCount() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.processed)
+}
+
+func TestDispatcher_DrainsQueueWithBoundedWorkers(t *testing.T) {
+	queue := &fakeQueue{}
+	for id := int64(1); id <= 10; id++ {
+		queue.queued = append(queue.queued, domain.WebhookDelivery{ID: id})
+	}
+
+	d := New(slog.New(slog.NewTextHandler(io.Discard, nil)), queue, time.Millisecond, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+
+	// Processing errors are logged and do not stop the dispatcher.
+	assert.Eventually(t, func() bool { return queue.processedCount() == 10 }, time.Second, time.Millisecond)
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("dispatcher did not stop after cancellation")
+	}
+
+	assert.LessOrEqual(t, queue.maxRunning.Load(), int32(3))
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	assert.ElementsMatch(t, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, queue.processed)
+	for _, limit := range queue.limits {
+		assert.Equal(t, 3, limit)
+	}
+}
@@ -2,6 +2,7 @@
 package domain
 
 import (
+	"slices"
 	"time"
 
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
@@ -34,6 +35,17 @@ type PullRequest struct {
 	Name     string                `db:"name"`
 	AuthorID string                `db:"author_id"`
 	Status   api.PullRequestStatus `db:"status"`
+	// Description and ExternalURL are optional; ExternalURL links to the PR on GitHub or GitLab.
+	Description *string `db:"description"`
+	ExternalURL *string `db:"external_url"`
+	// CustomFields holds the JSON object of custom field values the pull request was created with.
+	// The fields are defined by the team of the author; nil means no values.
+	CustomFields []byte `db:"custom_fields"`
+	// AssignmentPolicy holds the JSON AssignmentPolicySnapshot taken when the reviewers were assigned;
+	// nil for pull requests created before the snapshots were stored.
+	AssignmentPolicy []byte `db:"assignment_policy"`
+	// AssignAt is the time the assignment of the reviewers was deferred to; nil if they were assigned on creation.
+	AssignAt *time.Time `db:"assign_at"`
 	// NeedMoreReviewers is a flag indicating that the system could not find
 	// the desired number of reviewers (less than 2) when the PR was created.
 	NeedMoreReviewers bool       `db:"need_more_reviewers"`
@@ -43,6 +55,61 @@ type PullRequest struct {
 	// This field is not persisted in the 'pull_requests' table directly
 	// but is populated from the 'reviewers' association table.
 	ReviewerIDs []string
+	// Reviews holds the reviews of the assigned reviewers ordered by reviewer ID.
+	// Like ReviewerIDs it is populated from the 'reviewers' table, and only where the caller needs it.
+	Reviews []Review
+}
+
+// ReviewState is the decision of a reviewer on a pull request.
+type ReviewState string
+
+const (
+	// ReviewPending marks a reviewer who has not decided yet; every assignment starts in it.
+	ReviewPending          ReviewState = "PENDING"
+	ReviewApproved         ReviewState = "APPROVED"
+	ReviewChangesRequested ReviewState = "CHANGES_REQUESTED"
+)
+
+// Review is the current decision of a reviewer assigned to a pull request.
+// A replacement reviewer starts over with a pending review.
+type Review struct {
+	UserID string      `db:"user_id"`
+	State  ReviewState `db:"review_state"`
+	// ReviewedAt is when the reviewer made the decision; nil while the review is pending.
+	ReviewedAt *time.Time `db:"reviewed_at"`
+}
+
+// PRSearchFilter describes a page of a full-text search over pull request names.
+type PRSearchFilter struct {
+	Query string
+	// Status limits the results to pull requests in the given status. An empty Status matches any status.
+	Status api.PullRequestStatus
+	// CustomFields limits the results to pull requests whose custom fields, formatted as text, hold the given values.
+	CustomFields map[string]string
+	Limit        int
+	Offset       int
+}
+
+// PRListFilter describes a page of pull requests listed newest first, by created_at and then by ID.
+// Zero values of the filter fields match every pull request.
+type PRListFilter struct {
+	Status   api.PullRequestStatus
+	AuthorID string
+	// TeamID matches the pull requests whose author is currently a member of the team.
+	TeamID int
+	// CreatedFrom is inclusive and CreatedTo is exclusive.
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// After starts the page right after the given position instead of skipping Offset pull requests.
+	After  *PRCursor
+	Limit  int
+	Offset int
+}
+
+// PRCursor is the position of a pull request in the newest first order.
+type PRCursor struct {
+	CreatedAt time.Time
+	ID        string
 }
 
 // Reviewer represents the association between a PullRequest and a User (reviewer).
@@ -57,4 +124,878 @@ type Stats struct {
 	Username      string `db:"username"`
 	OpenReviews   int    `db:"open_reviews"`
 	MergedReviews int    `db:"merged_reviews"`
+	// FirstReviews counts the reviews the user first decided within the period; the first review durations
+	// run from the assignment of the user to that decision and are nil without any.
+	FirstReviews          int      `db:"first_reviews"`
+	FirstReviewAvgSeconds *float64 `db:"first_review_avg_seconds"`
+	FirstReviewP50Seconds *float64 `db:"first_review_p50_seconds"`
+	FirstReviewP90Seconds *float64 `db:"first_review_p90_seconds"`
+	// The merge durations run from the assignment of the user to the merge of the pull requests counted
+	// in MergedReviews and are nil without any.
+	MergeAvgSeconds *float64 `db:"merge_avg_seconds"`
+	MergeP50Seconds *float64 `db:"merge_p50_seconds"`
+	MergeP90Seconds *float64 `db:"merge_p90_seconds"`
+}
+
+// TeamStats summarizes how fast the pull requests authored by members of a team get reviewed and merged.
+// The durations run from the creation of a pull request to its first review decision by any reviewer
+// within the period, or to its merge within the period, and are nil without any.
+type TeamStats struct {
+	TeamName              string   `db:"team_name"`
+	FirstReviewedPRs      int      `db:"first_reviewed_prs"`
+	FirstReviewAvgSeconds *float64 `db:"first_review_avg_seconds"`
+	FirstReviewP50Seconds *float64 `db:"first_review_p50_seconds"`
+	FirstReviewP90Seconds *float64 `db:"first_review_p90_seconds"`
+	MergedPRs             int      `db:"merged_prs"`
+	MergeAvgSeconds       *float64 `db:"merge_avg_seconds"`
+	MergeP50Seconds       *float64 `db:"merge_p50_seconds"`
+	MergeP90Seconds       *float64 `db:"merge_p90_seconds"`
+}
+
+// StatsView is the all-time review statistics as precomputed by the last refresh. Users and teams are
+// ordered as the live statistics are; changes made after RefreshedAt are not reflected.
+type StatsView struct {
+	Users       []Stats
+	Teams       []TeamStats
+	RefreshedAt time.Time
+}
+
+// StatsPeriod limits review statistics to a time range: merged reviews count the pull requests merged
+// within it and open reviews the open pull requests created within it. From is inclusive and To is exclusive;
+// a nil bound leaves that side of the range open.
+type StatsPeriod struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// AssignmentStrategy names an algorithm used to pick reviewers for a pull request.
+type AssignmentStrategy string
+
+const (
+	// StrategyRandom picks reviewers uniformly at random among active teammates.
+	StrategyRandom AssignmentStrategy = "random"
+	// StrategyLeastLoaded prefers active teammates with the fewest open reviews.
+	StrategyLeastLoaded AssignmentStrategy = "least_loaded"
+	// StrategyRoundRobin takes turns among active teammates, picking those assigned least recently.
+	StrategyRoundRobin AssignmentStrategy = "round_robin"
+)
+
+// IsValid reports whether the strategy is one the service knows how to run.
+func (s AssignmentStrategy) IsValid() bool {
+	switch s {
+	case StrategyRandom, StrategyLeastLoaded, StrategyRoundRobin:
+		return true
+	default:
+		return false
+	}
+}
+
+// Reason returns the assignment reason recorded for reviewers picked by the strategy.
+func (s AssignmentStrategy) Reason() AssignmentReason {
+	switch s {
+	case StrategyLeastLoaded:
+		return ReasonLeastLoaded
+	case StrategyRoundRobin:
+		return ReasonRoundRobin
+	default:
+		return ReasonRandom
+	}
+}
+
+// AssignmentReason explains why a particular user was chosen as a reviewer.
+type AssignmentReason string
+
+const (
+	ReasonRandom      AssignmentReason = "random"
+	ReasonLeastLoaded AssignmentReason = "least_loaded"
+	ReasonRoundRobin  AssignmentReason = "round_robin"
+	ReasonTagMatch    AssignmentReason = "tag_match"
+	ReasonEscalation  AssignmentReason = "escalation"
+	ReasonManual      AssignmentReason = "manual"
+	// ReasonRebalance marks a review moved from a loaded teammate to a user who has become active again.
+	ReasonRebalance AssignmentReason = "rebalance"
+	// ReasonRestored marks a review handed back to the reviewer it was taken from by a team deactivation.
+	ReasonRestored AssignmentReason = "restored"
+)
+
+// AssignmentCause is the action that changed the reviewers of a pull request, as opposed to
+// the AssignmentReason a particular user was picked for.
+type AssignmentCause string
+
+const (
+	// CauseCreated marks the reviewers assigned when the pull request was created.
+	CauseCreated AssignmentCause = "created"
+	// CausePending marks the reviewers assigned later from the queue of pending assignments.
+	CausePending AssignmentCause = "pending"
+	// CauseReassign marks a reviewer replaced through /pullRequest/reassign.
+	CauseReassign AssignmentCause = "reassign"
+	// CauseUserDeactivated marks a reviewer replaced because the user was deactivated.
+	CauseUserDeactivated AssignmentCause = "user_deactivated"
+	// CauseTeamDeactivated marks a reviewer replaced because the whole team was deactivated.
+	CauseTeamDeactivated AssignmentCause = "team_deactivated"
+	// CauseRebalance marks a review moved to a user who has become active again.
+	CauseRebalance AssignmentCause = "rebalance"
+	// CauseTeamReactivated marks a review restored when a team deactivation was undone.
+	CauseTeamReactivated AssignmentCause = "team_reactivated"
+	// CauseUserTransferred marks a reviewer replaced because the user moved to another team.
+	CauseUserTransferred AssignmentCause = "user_transferred"
+)
+
+// TeamPolicy holds team-level settings that tune reviewer assignment.
+type TeamPolicy struct {
+	TeamID int
+	// StrategyWeights maps a strategy to its relative share of assignments,
+	// e.g. {least_loaded: 80, random: 20}. An empty map means the default strategy is always used.
+	StrategyWeights map[AssignmentStrategy]int
+	// AuthorOpenPRLimit caps the number of open pull requests a single author may have in the team.
+	// Nil means no limit.
+	AuthorOpenPRLimit *int
+	// OverQuotaAction decides what happens to a pull request created beyond AuthorOpenPRLimit.
+	OverQuotaAction QuotaAction
+	// ReactivationRebalance decides whether a member who becomes active again takes over reviews of loaded teammates.
+	ReactivationRebalance RebalanceMode
+	UpdatedAt             time.Time
+}
+
+// AssignmentPolicySnapshot records the rules a pull request was assigned reviewers under, so that
+// audits see the policy in force at creation time rather than the current one.
+type AssignmentPolicySnapshot struct {
+	TeamID int `json:"team_id"`
+	// StrategyWeights are the effective weights: the known strategies with positive weights,
+	// or the fallback strategy with weight 1.
+	StrategyWeights map[AssignmentStrategy]int `json:"strategy_weights"`
+	// Strategy is the strategy drawn for the pull request.
+	Strategy          AssignmentStrategy `json:"strategy"`
+	ReviewersPerPR    int                `json:"reviewers_per_pr"`
+	AuthorOpenPRLimit *int               `json:"author_open_pr_limit,omitempty"`
+	OverQuotaAction   QuotaAction        `json:"over_quota_action"`
+	// PolicyUpdatedAt is the version of the team policy; nil when the team had no stored policy.
+	PolicyUpdatedAt *time.Time `json:"policy_updated_at,omitempty"`
+}
+
+// QuotaAction is the way a pull request created beyond the author's open PR limit is handled.
+type QuotaAction string
+
+const (
+	// QuotaReject refuses to create the pull request.
+	QuotaReject QuotaAction = "reject"
+	// QuotaQueue creates the pull request without reviewers, leaving it waiting for them.
+	QuotaQueue QuotaAction = "queue"
+)
+
+// IsValid reports whether the action is one the service knows how to apply.
+func (a QuotaAction) IsValid() bool {
+	switch a {
+	case QuotaReject, QuotaQueue:
+		return true
+	default:
+		return false
+	}
+}
+
+// RebalanceMode is the way reviews are rebalanced when a team member becomes active again.
+type RebalanceMode string
+
+const (
+	// RebalanceOff leaves the reviews as they are.
+	RebalanceOff RebalanceMode = "off"
+	// RebalanceImmediate moves the reviews in the transaction that activates the member.
+	RebalanceImmediate RebalanceMode = "immediate"
+	// RebalanceJob moves the reviews in a reviewer_rebalance job queued once the member is active.
+	RebalanceJob RebalanceMode = "job"
+)
+
+// IsValid reports whether the mode is one the service knows how to apply.
+func (m RebalanceMode) IsValid() bool {
+	switch m {
+	case RebalanceOff, RebalanceImmediate, RebalanceJob:
+		return true
+	default:
+		return false
+	}
+}
+
+// CustomField is a metadata field defined by a team for the pull requests of its members.
+type CustomField struct {
+	TeamID int             `db:"team_id"`
+	Key    string          `db:"key"`
+	Type   CustomFieldType `db:"type"`
+	// Required fields must be given a value when a pull request is created.
+	Required bool `db:"required"`
+}
+
+// CustomFieldType is the JSON type of the values of a custom field.
+type CustomFieldType string
+
+const (
+	CustomFieldString  CustomFieldType = "string"
+	CustomFieldNumber  CustomFieldType = "number"
+	CustomFieldBoolean CustomFieldType = "boolean"
+)
+
+// IsValid reports whether the type is one the service can validate.
+func (t CustomFieldType) IsValid() bool {
+	switch t {
+	case CustomFieldString, CustomFieldNumber, CustomFieldBoolean:
+		return true
+	default:
+		return false
+	}
+}
+
+// Accepts reports whether a value decoded from JSON is of the type.
+func (t CustomFieldType) Accepts(value any) bool {
+	switch value.(type) {
+	case string:
+		return t == CustomFieldString
+	case float64:
+		return t == CustomFieldNumber
+	case bool:
+		return t == CustomFieldBoolean
+	default:
+		return false
+	}
+}
+
+// AssignmentRecord is a single entry of the reviewer assignment history.
+type AssignmentRecord struct {
+	ID            int64  `db:"id"`
+	PullRequestID string `db:"pull_request_id"`
+	UserID        string `db:"user_id"`
+	// ReplacedUserID is set when the user took over the review from another reviewer.
+	ReplacedUserID *string            `db:"replaced_user_id"`
+	Strategy       AssignmentStrategy `db:"strategy"`
+	Reason         AssignmentReason   `db:"reason"`
+	// Cause is empty for the replacements recorded before the causes were.
+	Cause AssignmentCause `db:"cause"`
+	// HandoffNote is the context the replaced reviewer left for the user taking over the review.
+	HandoffNote *string   `db:"handoff_note"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// ReviewerReplacement is a reviewer of a pull request handing the review over to another user.
+type ReviewerReplacement struct {
+	PullRequestID string
+	OldReviewerID string
+	NewReviewerID string
+}
+
+// PendingAssignment is a pull request queued until the team has the capacity to review it,
+// because not enough reviewers could be found when it was created.
+type PendingAssignment struct {
+	PullRequestID   string `db:"pull_request_id"`
+	PullRequestName string `db:"pull_request_name"`
+	AuthorID        string `db:"author_id"`
+	TeamID          int    `db:"team_id"`
+	TeamName        string `db:"team_name"`
+	// Priority is the number of reviewers the pull request still lacks,
+	// so that pull requests without any reviewer are served first.
+	Priority   int       `db:"priority"`
+	EnqueuedAt time.Time `db:"enqueued_at"`
+	// AssignAt is the time of the deferred assignment of the pull request; the entry is not filled before it.
+	AssignAt *time.Time `db:"assign_at"`
+}
+
+// PRSubscription makes a user who is not necessarily a reviewer, e.g. a stakeholder,
+// receive the notifications about every state change of a pull request.
+type PRSubscription struct {
+	PullRequestID string    `db:"pull_request_id"`
+	UserID        string    `db:"user_id"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// ReviewerBorrow is a request of a team for reviewers from another team, the lender.
+// Once the lender accepts it, the lent users are picked as reviewers for the borrowing team until ExpiresAt.
+type ReviewerBorrow struct {
+	ID             int64        `db:"id"`
+	TeamID         int          `db:"team_id"`
+	TeamName       string       `db:"team_name"`
+	LenderTeamID   int          `db:"lender_team_id"`
+	LenderTeamName string       `db:"lender_team_name"`
+	Count          int          `db:"reviewer_count"`
+	DurationHours  int          `db:"duration_hours"`
+	Status         BorrowStatus `db:"status"`
+	RequestedAt    time.Time    `db:"requested_at"`
+	AcceptedAt     *time.Time   `db:"accepted_at"`
+	ExpiresAt      *time.Time   `db:"expires_at"`
+	// ReviewerIDs lists the lent users; it is empty until the borrow is accepted.
+	ReviewerIDs []string `db:"-"`
+}
+
+// BorrowStatus is the state of a reviewer borrow.
+type BorrowStatus string
+
+const (
+	// BorrowRequested marks a borrow waiting for the lender team to accept it.
+	BorrowRequested BorrowStatus = "requested"
+	// BorrowAccepted marks a borrow whose users have been lent.
+	BorrowAccepted BorrowStatus = "accepted"
+)
+
+// CreatePRRequest is a pull request creation accepted for asynchronous processing.
+// Workers claim queued requests, create the pull requests and record the outcome for the client to poll.
+type CreatePRRequest struct {
+	ID              int64         `db:"id"`
+	PullRequestID   string        `db:"pull_request_id"`
+	PullRequestName string        `db:"pull_request_name"`
+	AuthorID        string        `db:"author_id"`
+	Description     *string       `db:"description"`
+	ExternalURL     *string       `db:"external_url"`
+	CustomFields    []byte        `db:"custom_fields"`
+	AssignAt        *time.Time    `db:"assign_at"`
+	Status          CreatePRState `db:"status"`
+	// Attempts counts the times a worker has claimed the request.
+	Attempts int `db:"attempts"`
+	// ErrorCode and ErrorMessage describe why a failed request was not processed.
+	ErrorCode    *string    `db:"error_code"`
+	ErrorMessage *string    `db:"error_message"`
+	EnqueuedAt   time.Time  `db:"enqueued_at"`
+	StartedAt    *time.Time `db:"started_at"`
+	FinishedAt   *time.Time `db:"finished_at"`
+}
+
+// CreatePRState is the state of an asynchronous pull request creation.
+type CreatePRState string
+
+const (
+	// CreatePRQueued marks a request waiting for a worker.
+	CreatePRQueued CreatePRState = "queued"
+	// CreatePRProcessing marks a request claimed by a worker.
+	CreatePRProcessing CreatePRState = "processing"
+	// CreatePRSucceeded marks a request whose pull request has been created.
+	CreatePRSucceeded CreatePRState = "succeeded"
+	// CreatePRFailed marks a request that will not be retried.
+	CreatePRFailed CreatePRState = "failed"
+)
+
+// DeactivationJob is a team deactivation whose reviews are reassigned in batches in the background.
+// The users are deactivated when the job is created; every batch of their open pull requests
+// is then reassigned in a transaction of its own.
+type DeactivationJob struct {
+	ID           int64                `db:"id"`
+	TeamID       int                  `db:"team_id"`
+	TeamName     string               `db:"team_name"`
+	Status       DeactivationJobState `db:"status"`
+	BatchSize    int                  `db:"batch_size"`
+	TotalBatches int                  `db:"total_batches"`
+	DoneBatches  int                  `db:"done_batches"`
+	TotalPRs     int                  `db:"total_prs"`
+	// ReassignedReviews counts the reviews taken over by active users so far.
+	ReassignedReviews int        `db:"reassigned_reviews"`
+	CreatedAt         time.Time  `db:"created_at"`
+	FinishedAt        *time.Time `db:"finished_at"`
+	// DeactivatedUserIDs lists the users whose reviews the job reassigns.
+	DeactivatedUserIDs []string `db:"-"`
+	// Warnings lists the reviews that no active user could take over.
+	Warnings []string `db:"-"`
+}
+
+// TeamDeactivation records the members a team deactivation deactivated, so that it can be undone.
+type TeamDeactivation struct {
+	ID     int64 `db:"id"`
+	TeamID int   `db:"team_id"`
+	// JobID is set for a batched deactivation.
+	JobID         *int64     `db:"job_id"`
+	CreatedAt     time.Time  `db:"created_at"`
+	ReactivatedAt *time.Time `db:"reactivated_at"`
+	UserIDs       []string   `db:"-"`
+}
+
+// DeactivationJobState is the state of a batched team deactivation.
+type DeactivationJobState string
+
+const (
+	// DeactivationJobRunning marks a job with batches left to reassign.
+	DeactivationJobRunning DeactivationJobState = "running"
+	// DeactivationJobSucceeded marks a job whose batches have all been reassigned.
+	DeactivationJobSucceeded DeactivationJobState = "succeeded"
+)
+
+// DeactivationBatch is a part of the open pull requests of a deactivation job that is reassigned at once.
+type DeactivationBatch struct {
+	JobID   int64 `db:"job_id"`
+	BatchNo int   `db:"batch_no"`
+	TeamID  int   `db:"team_id"`
+	// PullRequestIDs are in ascending order, the order the pull requests are locked in.
+	PullRequestIDs     []string `db:"-"`
+	DeactivatedUserIDs []string `db:"-"`
+}
+
+// LeaderboardPeriod is the calendar period the reviewer leaderboard is counted over.
+type LeaderboardPeriod string
+
+const (
+	LeaderboardWeek  LeaderboardPeriod = "week"
+	LeaderboardMonth LeaderboardPeriod = "month"
+)
+
+func (p LeaderboardPeriod) IsValid() bool {
+	switch p {
+	case LeaderboardWeek, LeaderboardMonth:
+		return true
+	}
+
+	return false
+}
+
+// Bucket returns the period containing at, with the bounds date_trunc gives in UTC: weeks start on Monday.
+func (p LeaderboardPeriod) Bucket(at time.Time) StatsPeriod {
+	at = at.UTC()
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+
+	var from, to time.Time
+
+	switch p {
+	case LeaderboardMonth:
+		from = day.AddDate(0, 0, 1-day.Day())
+		to = from.AddDate(0, 1, 0)
+	default:
+		from = day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		to = from.AddDate(0, 0, 7)
+	}
+
+	return StatsPeriod{From: &from, To: &to}
+}
+
+// LeaderboardEntry is a reviewer with the number of their reviews of the pull requests merged in a period.
+type LeaderboardEntry struct {
+	UserID        string `db:"user_id"`
+	Username      string `db:"username"`
+	MergedReviews int    `db:"merged_reviews"`
+}
+
+// OpenPRAgeStats summarizes the ages of the open pull requests authored by members of a team.
+type OpenPRAgeStats struct {
+	TeamName   string  `db:"team_name"`
+	OpenPRs    int     `db:"open_prs"`
+	P50Seconds float64 `db:"p50_seconds"`
+	P90Seconds float64 `db:"p90_seconds"`
+	MaxSeconds float64 `db:"max_seconds"`
+}
+
+// ReplacementAlternative is a user who could not be picked as a replacement reviewer automatically
+// but could still be assigned by an administrator.
+type ReplacementAlternative struct {
+	UserID   string `db:"user_id"`
+	Username string `db:"username"`
+	TeamID   int    `db:"team_id"`
+	TeamName string `db:"team_name"`
+	// Reason tells why the automatic selection skipped the user.
+	Reason      AlternativeReason `db:"reason"`
+	OpenReviews int               `db:"open_reviews"`
+}
+
+// AlternativeReason is the reason a user was left out of the automatic reviewer selection.
+type AlternativeReason string
+
+const (
+	// AlternativeInactive marks an inactive member of the reviewer's team.
+	AlternativeInactive AlternativeReason = "inactive"
+	// AlternativeOtherTeam marks an active member of another team; reviewers are only picked within the team
+	// and among the users it has borrowed.
+	AlternativeOtherTeam AlternativeReason = "other_team"
+)
+
+// EventType names a change in the lifecycle of a pull request that users are notified about.
+type EventType string
+
+const (
+	EventReviewersAssigned  EventType = "reviewers_assigned"
+	EventReviewerReassigned EventType = "reviewer_reassigned"
+	EventPRMerged           EventType = "pr_merged"
+	EventPRClosed           EventType = "pr_closed"
+	// EventReviewApproved and EventChangesRequested tell the author about the decision of a reviewer.
+	EventReviewApproved   EventType = "review_approved"
+	EventChangesRequested EventType = "changes_requested"
+)
+
+// Event is a notification about a pull request.
+type Event struct {
+	Type            EventType
+	PullRequestID   string
+	PullRequestName string
+	Description     *string
+	ExternalURL     *string
+	// UserIDs lists the users the event is addressed to, e.g. the newly assigned reviewers.
+	UserIDs []string
+	// SubscriberIDs lists the users subscribed to the pull request who are not among UserIDs.
+	// They are told about the change rather than asked to act on it.
+	SubscriberIDs []string
+	// HandoffNote is the note left by the previous reviewer of a reassigned review, if any.
+	HandoffNote *string
+	OccurredAt  time.Time
+}
+
+// Job is a long-running operation started through POST /jobs and run by a pool of workers.
+// A running job belongs to the worker holding its lease; a job whose lease has expired is claimed by another worker.
+type Job struct {
+	ID     int64    `db:"id"`
+	Type   JobType  `db:"type"`
+	Status JobState `db:"status"`
+	// Params holds the JSON parameters of the job; Result holds the JSON outcome of a finished job, if any.
+	Params []byte  `db:"params"`
+	Result []byte  `db:"result"`
+	Error  *string `db:"error"`
+	// ProgressDone and ProgressTotal measure the work done; a zero total means it is not known in advance.
+	ProgressDone  int `db:"progress_done"`
+	ProgressTotal int `db:"progress_total"`
+	// CancelRequested asks the worker of a running job to stop at its next checkpoint.
+	CancelRequested bool       `db:"cancel_requested"`
+	Owner           *string    `db:"owner"`
+	LeaseExpiresAt  *time.Time `db:"lease_expires_at"`
+	// Attempts counts the times a worker has claimed the job.
+	Attempts   int        `db:"attempts"`
+	CreatedAt  time.Time  `db:"created_at"`
+	StartedAt  *time.Time `db:"started_at"`
+	FinishedAt *time.Time `db:"finished_at"`
+}
+
+// JobType selects the handler that runs a job.
+type JobType string
+
+const (
+	JobTeamImport        JobType = "team_import"
+	JobTeamDeactivation  JobType = "team_deactivation"
+	JobPendingBackfill   JobType = "pending_backfill"
+	JobStatsExport       JobType = "stats_export"
+	JobReviewerRebalance JobType = "reviewer_rebalance"
+	JobStatsBackfill     JobType = "stats_backfill"
+)
+
+// JobState is the state of a job.
+type JobState string
+
+const (
+	// JobQueued marks a job waiting for a worker.
+	JobQueued JobState = "queued"
+	// JobRunning marks a job claimed by a worker.
+	JobRunning JobState = "running"
+	// JobSucceeded marks a job that has done all its work.
+	JobSucceeded JobState = "succeeded"
+	// JobFailed marks a job stopped by an error.
+	JobFailed JobState = "failed"
+	// JobCancelled marks a job stopped on request.
+	JobCancelled JobState = "cancelled"
+)
+
+// jobTransitions lists the states each state may move to. A running job moves to running again
+// when another worker claims it after its lease has expired.
+var jobTransitions = map[JobState][]JobState{
+	JobQueued:  {JobRunning, JobCancelled},
+	JobRunning: {JobRunning, JobSucceeded, JobFailed, JobCancelled},
+}
+
+// CanTransitionTo reports whether a job in state s may move to next.
+func (s JobState) CanTransitionTo(next JobState) bool {
+	return slices.Contains(jobTransitions[s], next)
+}
+
+// Final reports whether s is a state no job leaves.
+func (s JobState) Final() bool {
+	return len(jobTransitions[s]) == 0
+}
+
+// DeliveryStatus is the outcome of the last attempt to deliver a notification.
+type DeliveryStatus string
+
+const (
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// NotificationDelivery records the delivery of an event to one recipient through one notification channel.
+type NotificationDelivery struct {
+	ID            int64          `db:"id"`
+	Channel       string         `db:"channel"`
+	RecipientID   string         `db:"recipient_id"`
+	Event         EventType      `db:"event"`
+	PullRequestID string         `db:"pull_request_id"`
+	Status        DeliveryStatus `db:"status"`
+	// Error holds the error of the last attempt if it failed.
+	Error    *string `db:"error"`
+	Attempts int     `db:"attempts"`
+	// Payload holds the JSON of the delivered Event, so that a failed delivery can be retried.
+	Payload   []byte    `db:"payload"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// DeliveryFilter selects notification deliveries, newest first. Zero values do not filter.
+type DeliveryFilter struct {
+	Channel       string
+	RecipientID   string
+	PullRequestID string
+	Event         EventType
+	Status        DeliveryStatus
+	// AfterID starts the page right after the delivery with the given ID instead of skipping Offset deliveries.
+	// Zero starts from the newest delivery.
+	AfterID int64
+	Limit   int
+	Offset  int
+}
+
+// FreezeWindow is a period during which pull requests may not be merged, e.g. around a release.
+// A window without a team applies to the whole organization.
+type FreezeWindow struct {
+	ID       int64     `db:"id"`
+	TeamID   *int      `db:"team_id"`
+	TeamName *string   `db:"team_name"`
+	Reason   string    `db:"reason"`
+	StartsAt time.Time `db:"starts_at"`
+	// EndsAt is the first moment after the window.
+	EndsAt    time.Time `db:"ends_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// FreezeWindowFilter selects freeze windows, in the order they start. Nil fields do not filter.
+type FreezeWindowFilter struct {
+	// TeamID selects the windows of the team and those of the whole organization.
+	TeamID *int
+	// ActiveAt selects the windows in effect at the time.
+	ActiveAt *time.Time
+	// EndsAfter selects the windows that have not ended by the time.
+	EndsAfter *time.Time
+}
+
+// GitLabUser maps the username of a GitLab account to the user acting as it in the service,
+// so that the GitLab webhook can tell who opened a merge request.
+type GitLabUser struct {
+	GitLabUsername string    `db:"gitlab_username"`
+	UserID         string    `db:"user_id"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+// OIDCSubject maps the subject of an account at the identity provider to the user signing in as it,
+// so that the ID tokens of the provider act for the user.
+type OIDCSubject struct {
+	Subject   string    `db:"subject"`
+	UserID    string    `db:"user_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// SlackUser maps a user to the Slack member the Slack notifications of the user are sent to.
+type SlackUser struct {
+	UserID      string    `db:"user_id"`
+	SlackUserID string    `db:"slack_user_id"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// WebhookEventType names an event sent to the outbound webhooks subscribed to it.
+type WebhookEventType string
+
+const (
+	WebhookPRCreated          WebhookEventType = "pr.created"
+	WebhookPRMerged           WebhookEventType = "pr.merged"
+	WebhookReviewerReassigned WebhookEventType = "reviewer.reassigned"
+)
+
+// IsValid reports whether t is one of the known webhook events.
+func (t WebhookEventType) IsValid() bool {
+	switch t {
+	case WebhookPRCreated, WebhookPRMerged, WebhookReviewerReassigned:
+		return true
+	default:
+		return false
+	}
+}
+
+// Webhook is an outbound webhook: a URL the events it is subscribed to are POSTed to,
+// signed with its secret.
+type Webhook struct {
+	ID     int64
+	URL    string
+	Events []WebhookEventType
+	// Secret signs the deliveries; it is never returned through the API.
+	Secret    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WebhookEvent is an event queued for delivery to the webhooks subscribed to it.
+type WebhookEvent struct {
+	Type          WebhookEventType
+	PullRequestID string
+	// Payload holds the JSON body POSTed to the webhooks.
+	Payload    []byte
+	OccurredAt time.Time
+}
+
+// WebhookDeliveryStatus is the state of the delivery of an event to a webhook.
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending marks a delivery waiting for its next attempt.
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryDelivered marks a delivery the webhook has accepted.
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryDead marks a delivery that failed every attempt; it is only retried on request.
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is the delivery of an event to one webhook. A pending delivery is attempted once
+// NextAttemptAt has passed; claiming it moves NextAttemptAt past the lease of the worker attempting it,
+// so that the delivery of a worker that died is attempted again.
+type WebhookDelivery struct {
+	ID            int64                 `db:"id"`
+	WebhookID     int64                 `db:"webhook_id"`
+	Event         WebhookEventType      `db:"event"`
+	PullRequestID string                `db:"pull_request_id"`
+	Payload       []byte                `db:"payload"`
+	Status        WebhookDeliveryStatus `db:"status"`
+	Attempts      int                   `db:"attempts"`
+	NextAttemptAt time.Time             `db:"next_attempt_at"`
+	// LastError and ResponseStatus describe the last failed attempt, if any.
+	LastError      *string   `db:"last_error"`
+	ResponseStatus *int      `db:"response_status"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+// OutboxEvent is a PR event written to the outbox in the transaction of the change it reports, so that
+// it is published to the event sinks even if the process dies right after the commit. An unpublished event
+// is published once NextAttemptAt has passed; claiming it moves NextAttemptAt past the lease of the worker
+// publishing it, so that the event of a worker that died is published again.
+type OutboxEvent struct {
+	ID            int64            `db:"id"`
+	Type          WebhookEventType `db:"event"`
+	PullRequestID string           `db:"pull_request_id"`
+	// Payload holds the JSON description of the event, the same as the body POSTed to the webhooks.
+	Payload       []byte     `db:"payload"`
+	Attempts      int        `db:"attempts"`
+	NextAttemptAt time.Time  `db:"next_attempt_at"`
+	PublishedAt   *time.Time `db:"published_at"`
+	// LastError describes the last failed attempt, if any.
+	LastError  *string   `db:"last_error"`
+	OccurredAt time.Time `db:"occurred_at"`
+}
+
+// WebhookDeliveryFilter selects the deliveries of a webhook, newest first. Zero values do not filter.
+type WebhookDeliveryFilter struct {
+	WebhookID int64
+	Status    WebhookDeliveryStatus
+	// AfterID starts the page right after the delivery with the given ID. Zero starts from the newest delivery.
+	AfterID int64
+	Limit   int
+}
+
+// ReadToken is a read-only API token handed out to dashboards and scripts. Only the SHA-256 hash of the token
+// is stored; the token itself is shown once, when it is created.
+type ReadToken struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	TokenHash string    `db:"token_hash"`
+	CreatedAt time.Time `db:"created_at"`
+	// ExpiresAt is the moment the token stops being accepted; nil means it never expires.
+	ExpiresAt *time.Time `db:"expires_at"`
+	// LastUsedAt is updated at most once a minute per token, so it is approximate.
+	LastUsedAt *time.Time `db:"last_used_at"`
+}
+
+// APIKeyScope names a kind of API operation an API key may call.
+type APIKeyScope string
+
+const (
+	// ScopeRead allows the GET and HEAD operations outside /admin.
+	ScopeRead APIKeyScope = "read"
+	// ScopeWrite allows the other operations outside /admin.
+	ScopeWrite APIKeyScope = "write"
+	// ScopeAdmin allows the operations under /admin.
+	ScopeAdmin APIKeyScope = "admin"
+)
+
+// IsValid reports whether s is one of the known scopes.
+func (s APIKeyScope) IsValid() bool {
+	switch s {
+	case ScopeRead, ScopeWrite, ScopeAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// APIKey is an API key of a service or a script. A key opens only the operations of its scopes; the scopes do not
+// imply each other. Like the read tokens, only the SHA-256 hash of a stored key is kept.
+type APIKey struct {
+	ID      int64
+	Name    string
+	KeyHash string
+	Scopes  []APIKeyScope
+	// ExpiresAt is the moment the key stops being accepted; nil means it never expires.
+	ExpiresAt *time.Time
+	// LastUsedAt is updated at most once a minute per key, so it is approximate.
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+	// UserID is the user the key acts for; its role further limits what the key opens. Nil for a key of a service.
+	UserID *string
+}
+
+// HasScope reports whether the key opens the operations of scope.
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// UserRole is what a user may do through the API. Each role includes the roles below it.
+type UserRole string
+
+const (
+	// RoleMember may only read.
+	RoleMember UserRole = "member"
+	// RoleLead may also change pull requests and reassign their reviewers.
+	RoleLead UserRole = "lead"
+	// RoleAdmin may also create and deactivate teams, assign roles and call /admin.
+	RoleAdmin UserRole = "admin"
+)
+
+// userRoleRanks orders the roles by what they include.
+var userRoleRanks = map[UserRole]int{RoleMember: 1, RoleLead: 2, RoleAdmin: 3}
+
+// IsValid reports whether r is one of the known roles.
+func (r UserRole) IsValid() bool {
+	_, ok := userRoleRanks[r]
+	return ok
+}
+
+// Includes reports whether r may do everything other may. An unknown role includes nothing.
+func (r UserRole) Includes(other UserRole) bool {
+	return r.IsValid() && userRoleRanks[r] >= userRoleRanks[other]
+}
+
+// WorkingHours is the weekly working time of the reviewers. The deferred reviewer assignments are moved into it,
+// so that a pull request created at night by CI does not notify its reviewers before the morning.
+type WorkingHours struct {
+	Location *time.Location
+	// Start and End bound the working time of each working day as offsets from midnight; Start is before End.
+	Start time.Duration
+	End   time.Duration
+	Days  []time.Weekday
+}
+
+// Next returns t if it falls within the working hours and the start of the next working period otherwise.
+// Without working days t is returned as is.
+func (h WorkingHours) Next(t time.Time) time.Time {
+	local := t.In(h.Location)
+
+	for i := range 8 {
+		day := time.Date(local.Year(), local.Month(), local.Day()+i, 0, 0, 0, 0, h.Location)
+		if !slices.Contains(h.Days, day.Weekday()) {
+			continue
+		}
+
+		start, end := h.at(day, h.Start), h.at(day, h.End)
+		if local.Before(start) {
+			return start.In(t.Location())
+		}
+
+		if local.Before(end) {
+			return t
+		}
+	}
+
+	return t
+}
+
+// at returns the time of day offset on the day of midnight, keeping the wall clock across DST changes.
+func (h WorkingHours) at(midnight time.Time, offset time.Duration) time.Time {
+	return time.Date(midnight.Year(), midnight.Month(), midnight.Day(),
+		int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, h.Location)
 }
@@ -0,0 +1,71 @@
+package filler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var backfillRunsTotal = promauto.NewCounterVec(metrics.PendingBackfillRuns.CounterOpts(), metrics.PendingBackfillRuns.Labels)
+
+// Requeuer is the part of service.PRCommandService the backfiller drives.
+type Requeuer interface {
+	RequeueNeedingReviewers(ctx context.Context, limit int) (int, error)
+}
+
+// Backfiller puts the pull requests flagged with need_more_reviewers that are not in the pending assignment
+// queue back into it periodically, e.g. those released by the author quota, so that the Filler assigns
+// their reviewers as teammates become active.
+type Backfiller struct {
+	log      *slog.Logger
+	prs      Requeuer
+	interval time.Duration
+	batch    int
+}
+
+func NewBackfiller(log *slog.Logger, prs Requeuer, interval time.Duration, batch int) *Backfiller {
+	return &Backfiller{
+		log:      log.With(slog.String("component", "backfiller")),
+		prs:      prs,
+		interval: interval,
+		batch:    batch,
+	}
+}
+
+// Run requeues the pull requests once per interval until ctx is cancelled.
+func (b *Backfiller) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.backfill(ctx)
+		}
+	}
+}
+
+func (b *Backfiller) backfill(ctx context.Context) {
+	requeued, err := b.prs.RequeueNeedingReviewers(ctx, b.batch)
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err != nil {
+		// The failed pull requests stay flagged and are retried on the next run.
+		b.log.Error("failed to requeue pull requests needing reviewers", sl.Err(err))
+		backfillRunsTotal.WithLabelValues("error").Inc()
+	} else {
+		backfillRunsTotal.WithLabelValues("success").Inc()
+	}
+
+	if requeued > 0 {
+		b.log.Info("queued pull requests needing reviewers", slog.Int("pull_requests", requeued))
+	}
+}
@@ -0,0 +1,62 @@
+// package filler assigns reviewers to the pull requests waiting in the pending assignment queue.
+// It runs in the background, so queued pull requests get their reviewers as teammates become active
+// without anyone having to retry the assignment. The Backfiller returns the pull requests needing reviewers
+// that are missing from the queue to it.
+package filler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// PendingFiller is the part of service.PRCommandService the filler drives.
+type PendingFiller interface {
+	FillPendingAssignments(ctx context.Context, limit int) (int, error)
+}
+
+// Filler drains the pending assignment queue periodically.
+type Filler struct {
+	log      *slog.Logger
+	prs      PendingFiller
+	interval time.Duration
+	batch    int
+}
+
+func New(log *slog.Logger, prs PendingFiller, interval time.Duration, batch int) *Filler {
+	return &Filler{
+		log:      log.With(slog.String("component", "filler")),
+		prs:      prs,
+		interval: interval,
+		batch:    batch,
+	}
+}
+
+// Run fills the queue once per interval until ctx is cancelled.
+func (f *Filler) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f.fill(ctx)
+		}
+	}
+}
+
+func (f *Filler) fill(ctx context.Context) {
+	assigned, err := f.prs.FillPendingAssignments(ctx, f.batch)
+	if err != nil && ctx.Err() == nil {
+		// The failed entries stay queued and are retried on the next run.
+		f.log.Error("failed to fill pending assignments", sl.Err(err))
+	}
+
+	if assigned > 0 {
+		f.log.Info("assigned reviewers to queued pull requests", slog.Int("reviewers", assigned))
+	}
+}
@@ -0,0 +1,101 @@
+package filler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeFiller struct {
+	calls atomic.Int32
+	limit atomic.Int32
+}
+
+func (f *fakeFiller) FillPendingAssignments(_ context.Context, limit int) (int, error) {
+	f.calls.Add(1)
+	f.limit.Store(int32(limit))
+
+	return 0, errors.New("db is down")
+}
+
+func TestFiller_RunUntilCancelled(t *testing.T) {
+	prs := &fakeFiller{}
+	f := New(slog.New(slog.NewTextHandler(io.Discard, nil)), prs, time.Millisecond, 7)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		f.Run(ctx)
+		close(done)
+	}()
+
+	// Errors are logged and do not stop the filler.
+	assert.Eventually(t, func() bool { return prs.calls.Load() >= 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(7), prs.limit.Load())
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("filler did not stop after cancellation")
+	}
+}
+
+type fakeRequeuer struct {
+	calls atomic.Int32
+	fail  bool
+}
+
+func (f *fakeRequeuer) RequeueNeedingReviewers(_ context.Context, _ int) (int, error) {
+	f.calls.Add(1)
+
+	if f.fail {
+		return 0, errors.New("db is down")
+	}
+
+	return 1, nil
+}
+
+func TestBackfiller_CountsRuns(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	successes := testutil.ToFloat64(backfillRunsTotal.WithLabelValues("success"))
+	errs := testutil.ToFloat64(backfillRunsTotal.WithLabelValues("error"))
+
+	NewBackfiller(log, &fakeRequeuer{}, time.Minute, 10).backfill(context.Background())
+	NewBackfiller(log, &fakeRequeuer{fail: true}, time.Minute, 10).backfill(context.Background())
+
+	assert.Equal(t, successes+1, testutil.ToFloat64(backfillRunsTotal.WithLabelValues("success")))
+	assert.Equal(t, errs+1, testutil.ToFloat64(backfillRunsTotal.WithLabelValues("error")))
+}
+
+func TestBackfiller_RunUntilCancelled(t *testing.T) {
+	prs := &fakeRequeuer{}
+	b := NewBackfiller(slog.New(slog.NewTextHandler(io.Discard, nil)), prs, time.Millisecond, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		b.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return prs.calls.Load() >= 2 }, time.Second, time.Millisecond)
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("backfiller did not stop after cancellation")
+	}
+}
@@ -0,0 +1,79 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitState is the state of a circuit breaker; the values are exported as the outbound_circuit_state gauge.
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitHalfOpen
+	circuitOpen
+)
+
+// breaker stops calls to a host after a run of consecutive failures. Once openTimeout has passed,
+// a single trial call is let through: its success closes the circuit, its failure opens it again.
+type breaker struct {
+	threshold   int
+	openTimeout time.Duration
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+	// trial is set while the trial call of a half-open circuit is in flight.
+	trial bool
+}
+
+func newBreaker(threshold int, openTimeout time.Duration) *breaker {
+	return &breaker{threshold: threshold, openTimeout: openTimeout}
+}
+
+// allow reports whether a call may be made at now, and the state of the circuit after the check.
+func (b *breaker) allow(now time.Time) (bool, circuitState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == circuitOpen && now.Sub(b.openedAt) >= b.openTimeout {
+		b.state = circuitHalfOpen
+	}
+
+	switch b.state {
+	case circuitOpen:
+		return false, b.state
+	case circuitHalfOpen:
+		if b.trial {
+			return false, b.state
+		}
+
+		b.trial = true
+	}
+
+	return true, b.state
+}
+
+// record registers the result of an allowed call made at now and returns the resulting state.
+func (b *breaker) record(success bool, now time.Time) circuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+
+	if success {
+		b.state = circuitClosed
+		b.failures = 0
+
+		return b.state
+	}
+
+	b.failures++
+	if b.state == circuitHalfOpen || b.failures >= b.threshold {
+		b.state = circuitOpen
+		b.openedAt = now
+	}
+
+	return b.state
+}
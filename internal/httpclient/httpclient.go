@@ -0,0 +1,232 @@
+// Package httpclient is the shared HTTP client of the outbound integrations (GitHub, Slack, outgoing webhooks).
+// It bounds every attempt with a timeout, retries failed idempotent requests with exponential backoff and
+// full jitter, stops calling a failing host with a per-host circuit breaker and exports the outbound_* metrics,
+// so that every integration gets the same resilience instead of reimplementing it.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+)
+
+// ErrCircuitOpen is returned without calling the host while its circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// idempotencyKeyHeader marks a non-idempotent request as safe to retry.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// Client sends requests of a single integration. It is safe for concurrent use.
+type Client struct {
+	name string
+	cfg  config.HTTPClient
+	http *http.Client
+	log  *slog.Logger
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+
+	now func() time.Time
+	// jitter returns a random delay in [0, max].
+	jitter func(max time.Duration) time.Duration
+}
+
+type Option func(*Client)
+
+// WithTransport sets the transport the requests are sent with, e.g. one with a custom TLS config.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.http.Transport = rt
+	}
+}
+
+// New creates a client for the integration name; the name is the client label of the outbound metrics.
+func New(name string, cfg config.HTTPClient, log *slog.Logger, opts ...Option) *Client {
+	c := &Client{
+		name:     name,
+		cfg:      cfg,
+		http:     &http.Client{Timeout: cfg.Timeout},
+		log:      log.With(slog.String("component", "httpclient"), slog.String("client", name)),
+		breakers: make(map[string]*breaker),
+		now:      time.Now,
+		jitter: func(max time.Duration) time.Duration {
+			return time.Duration(rand.Int64N(int64(max) + 1))
+		},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Do sends req and returns the response of the last attempt. A request is retried on a network error,
+// a 429 or a 5xx response if it is idempotent (GET, HEAD, OPTIONS, PUT, DELETE or an Idempotency-Key header)
+// and its body can be replayed. The caller must close the body of the returned response.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	host := req.URL.Host
+	retries := 0
+
+	if retryable(req) {
+		retries = c.cfg.MaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq, err := replay(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.attempt(attemptReq, host)
+		if attempt >= retries || !shouldRetry(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		if resp != nil {
+			// The body is drained so that the connection can be reused.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		delay := c.backoff(attempt)
+		outboundRetries.WithLabelValues(c.name, host).Inc()
+		c.log.Warn("retrying outbound request",
+			slog.String("method", req.Method),
+			slog.String("host", host),
+			slog.Int("attempt", attempt+1),
+			slog.Duration("delay", delay),
+			slog.String("outcome", outcome(resp, err)),
+		)
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt sends a single request through the circuit breaker of host.
+func (c *Client) attempt(req *http.Request, host string) (*http.Response, error) {
+	b := c.breaker(host)
+	state := outboundCircuitState.WithLabelValues(c.name, host)
+
+	allowed, current := b.allow(c.now())
+	state.Set(float64(current))
+
+	if !allowed {
+		outboundRequests.WithLabelValues(c.name, host, outcomeCircuitOpen).Inc()
+		return nil, fmt.Errorf("%w: host '%s'", ErrCircuitOpen, host)
+	}
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	outboundRequestDuration.WithLabelValues(c.name, host).Observe(time.Since(start).Seconds())
+	outboundRequests.WithLabelValues(c.name, host, outcome(resp, err)).Inc()
+
+	failed := err != nil || resp.StatusCode >= http.StatusInternalServerError
+	if err != nil && req.Context().Err() != nil {
+		// A call cancelled by the caller says nothing about the health of the host.
+		failed = false
+	}
+
+	state.Set(float64(b.record(!failed, c.now())))
+
+	return resp, err
+}
+
+func (c *Client) breaker(host string) *breaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.breakers[host]
+	if !ok {
+		b = newBreaker(c.cfg.BreakerFailures, c.cfg.BreakerOpenTimeout)
+		c.breakers[host] = b
+	}
+
+	return b
+}
+
+// backoff returns the delay before the retry following attempt: a random share of the exponential
+// delay capped at RetryMaxDelay ("full jitter"), so that clients failing together do not retry together.
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.cfg.RetryMaxDelay
+	if attempt < 32 {
+		delay = min(c.cfg.RetryBaseDelay<<attempt, c.cfg.RetryMaxDelay)
+	}
+
+	return c.jitter(delay)
+}
+
+// retryable reports whether req may be sent more than once.
+func retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return req.Header.Get(idempotencyKeyHeader) != ""
+}
+
+// replay returns the request to send on attempt; every attempt after the first gets a fresh body.
+func replay(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay request body: %w", err)
+	}
+
+	replayed := req.Clone(req.Context())
+	replayed.Body = body
+
+	return replayed, nil
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, ErrCircuitOpen)
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+func outcome(resp *http.Response, err error) string {
+	if err != nil {
+		if errors.Is(err, ErrCircuitOpen) {
+			return outcomeCircuitOpen
+		}
+
+		return outcomeError
+	}
+
+	return strconv.Itoa(resp.StatusCode)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,190 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, cfg config.HTTPClient) *Client {
+	t.Helper()
+
+	c := New("test", cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	c.jitter = func(time.Duration) time.Duration { return 0 }
+
+	return c
+}
+
+func testConfig() config.HTTPClient {
+	return config.HTTPClient{
+		Timeout:            time.Second,
+		MaxRetries:         2,
+		RetryBaseDelay:     time.Millisecond,
+		RetryMaxDelay:      10 * time.Millisecond,
+		BreakerFailures:    3,
+		BreakerOpenTimeout: time.Minute,
+	}
+}
+
+// statusSequence responds with the statuses in turn, repeating the last one, and counts the calls.
+func statusSequence(calls *atomic.Int32, statuses ...int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(statuses[min(n, len(statuses))-1])
+		_, _ = w.Write(body)
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	testCases := []struct {
+		name           string
+		method         string
+		header         string
+		statuses       []int
+		expectedStatus int
+		expectedCalls  int32
+	}{
+		{name: "Success on first attempt", method: http.MethodGet, statuses: []int{200}, expectedStatus: 200, expectedCalls: 1},
+		{name: "Idempotent request retried after 5xx", method: http.MethodGet, statuses: []int{503, 502, 200}, expectedStatus: 200, expectedCalls: 3},
+		{name: "Retries are bounded", method: http.MethodPut, statuses: []int{500}, expectedStatus: 500, expectedCalls: 3},
+		{name: "Throttled request retried", method: http.MethodDelete, statuses: []int{429, 200}, expectedStatus: 200, expectedCalls: 2},
+		{name: "Client error not retried", method: http.MethodGet, statuses: []int{404}, expectedStatus: 404, expectedCalls: 1},
+		{name: "POST not retried", method: http.MethodPost, statuses: []int{503, 200}, expectedStatus: 503, expectedCalls: 1},
+		{name: "POST with idempotency key retried", method: http.MethodPost, header: "key-1", statuses: []int{503, 200}, expectedStatus: 200, expectedCalls: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(statusSequence(&calls, tc.statuses...))
+			defer server.Close()
+
+			req, err := http.NewRequest(tc.method, server.URL, strings.NewReader("payload"))
+			require.NoError(t, err)
+			if tc.header != "" {
+				req.Header.Set(idempotencyKeyHeader, tc.header)
+			}
+
+			resp, err := newTestClient(t, testConfig()).Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tc.expectedCalls, calls.Load())
+			assert.Equal(t, "payload", string(body), "every attempt sends the whole body")
+		})
+	}
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(statusSequence(&calls, 500, 500, 500, 200))
+	defer server.Close()
+
+	cfg := testConfig()
+	cfg.MaxRetries = 0
+
+	now := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+	client := newTestClient(t, cfg)
+	client.now = func() time.Time { return now }
+
+	get := func() (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		resp, err := client.Do(req)
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		return resp, err
+	}
+
+	for range cfg.BreakerFailures {
+		resp, err := get()
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	}
+
+	_, err := get()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(3), calls.Load(), "open circuit does not call the host")
+
+	now = now.Add(cfg.BreakerOpenTimeout)
+
+	resp, err := get()
+	require.NoError(t, err, "trial request is let through after the open timeout")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = get()
+	require.NoError(t, err, "successful trial closes the circuit")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestClient_RetryStopsWhenContextIsDone(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(statusSequence(&calls, 503))
+	defer server.Close()
+
+	cfg := testConfig()
+	cfg.RetryBaseDelay, cfg.RetryMaxDelay = time.Hour, time.Hour
+
+	client := newTestClient(t, cfg)
+	client.jitter = func(max time.Duration) time.Duration { return max }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	_, err = client.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestBreaker_HalfOpenAllowsSingleTrial(t *testing.T) {
+	now := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+	b := newBreaker(1, time.Minute)
+
+	assert.Equal(t, circuitOpen, b.record(false, now))
+
+	allowed, _ := b.allow(now.Add(time.Second))
+	assert.False(t, allowed)
+
+	allowed, state := b.allow(now.Add(time.Minute))
+	assert.True(t, allowed)
+	assert.Equal(t, circuitHalfOpen, state)
+
+	allowed, _ = b.allow(now.Add(time.Minute))
+	assert.False(t, allowed, "only one trial call at a time")
+
+	assert.Equal(t, circuitOpen, b.record(false, now.Add(time.Minute)), "failed trial opens the circuit again")
+
+	allowed, _ = b.allow(now.Add(90 * time.Second))
+	assert.False(t, allowed)
+}
+
+func TestClient_Backoff(t *testing.T) {
+	client := newTestClient(t, testConfig())
+	client.jitter = func(max time.Duration) time.Duration { return max }
+
+	assert.Equal(t, time.Millisecond, client.backoff(0))
+	assert.Equal(t, 4*time.Millisecond, client.backoff(2))
+	assert.Equal(t, 10*time.Millisecond, client.backoff(5), "capped at the max delay")
+	assert.Equal(t, 10*time.Millisecond, client.backoff(100))
+}
@@ -0,0 +1,25 @@
+package httpclient
+
+import (
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Values of the outcome label of the outbound_requests_total metric besides the status code.
+const (
+	outcomeError       = "error"
+	outcomeCircuitOpen = "circuit_open"
+)
+
+var (
+	outboundRequests = promauto.NewCounterVec(metrics.OutboundRequests.CounterOpts(), metrics.OutboundRequests.Labels)
+
+	outboundRequestDuration = promauto.NewHistogramVec(
+		metrics.OutboundRequestDuration.HistogramOpts(metrics.HTTPDurationBuckets),
+		metrics.OutboundRequestDuration.Labels,
+	)
+
+	outboundRetries = promauto.NewCounterVec(metrics.OutboundRetries.CounterOpts(), metrics.OutboundRetries.Labels)
+
+	outboundCircuitState = promauto.NewGaugeVec(metrics.OutboundCircuitState.GaugeOpts(), metrics.OutboundCircuitState.Labels)
+)
@@ -0,0 +1,34 @@
+// Package idgen generates the IDs of the entities an external system has not identified,
+// e.g. a pull request created with generate_id=true. The IDs are UUIDv7 behind a prefix naming the kind
+// of entity, so that they neither collide nor look alike across kinds, and sort by creation time.
+package idgen
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// PullRequestPrefix starts the generated pull request IDs.
+const PullRequestPrefix = "pr"
+
+// Generator makes the IDs of one kind of entity.
+type Generator struct {
+	prefix string
+}
+
+// New creates a Generator of IDs starting with prefix and a hyphen. The prefix must match the ID format
+// of the entity, since the generated IDs are stored like the supplied ones.
+func New(prefix string) *Generator {
+	return &Generator{prefix: prefix}
+}
+
+// NewID returns a new ID such as pr-01936b2e-5a1c-7cc4-9f0e-3c2b8a6d4e10.
+func (g *Generator) NewID() (string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate id: %w", err)
+	}
+
+	return g.prefix + "-" + id.String(), nil
+}
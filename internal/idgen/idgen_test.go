@@ -0,0 +1,25 @@
+package idgen
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_NewID(t *testing.T) {
+	g := New(PullRequestPrefix)
+
+	first, err := g.NewID()
+	require.NoError(t, err)
+
+	second, err := g.NewID()
+	require.NoError(t, err)
+
+	pattern := regexp.MustCompile(`^pr-[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	assert.Regexp(t, pattern, first)
+	assert.Regexp(t, regexp.MustCompile(`^[a-zA-Z0-9_-]+$`), first, "the ID passes the validation of supplied IDs")
+	assert.NotEqual(t, first, second)
+	assert.Less(t, first, second, "the IDs sort by creation time")
+}
@@ -0,0 +1,103 @@
+// Package localcache keeps the results of the hottest reads, the team of GET /team/get and the review
+// assignments of GET /users/getReview, in process for the deployments that run without the Redis cache.
+// Concurrent identical reads that miss the cache share a single query.
+//
+// The services invalidate the whole cache after every write they commit, so an instance never serves data
+// older than its own writes. The writes of other instances are seen once the entries expire.
+package localcache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache is an LRU cache of read results with a TTL. A nil *Cache is a no-op: every read runs its query.
+type Cache struct {
+	entries *expirable.LRU[string, any]
+	flights singleflight.Group
+
+	// mu orders the writes of the entries against Invalidate, so that a query started before an
+	// invalidation never stores its result after it.
+	mu         sync.Mutex
+	generation uint64
+}
+
+// New creates a cache of up to size entries, each dropped ttl after it was stored.
+func New(size int, ttl time.Duration) *Cache {
+	return &Cache{entries: expirable.NewLRU[string, any](size, nil, ttl)}
+}
+
+// Invalidate drops every entry and makes the queries in flight not store their results.
+func (c *Cache) Invalidate() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.entries.Purge()
+}
+
+// Load returns the result cached under the name and key, or runs load and caches its result. Callers that
+// miss the same entry at once wait for a single load. Errors of load are not cached.
+// The cached result is shared by all the callers, which must not modify it.
+func Load[T any](ctx context.Context, c *Cache, name, key string, load func() (T, error)) (T, error) {
+	if c == nil {
+		return load()
+	}
+
+	entryKey := name + ":" + key
+
+	if value, ok := c.entries.Get(entryKey); ok {
+		cacheRequests.WithLabelValues(name, resultHit).Inc()
+		return value.(T), nil
+	}
+
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	// A read after an invalidation does not join a query started before it, which may miss the write.
+	flightKey := strconv.FormatUint(generation, 10) + ":" + entryKey
+
+	value, err, shared := c.flights.Do(flightKey, func() (any, error) {
+		value, err := load()
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		if c.generation == generation {
+			c.entries.Add(entryKey, value)
+		}
+		c.mu.Unlock()
+
+		return value, nil
+	})
+
+	if shared {
+		cacheRequests.WithLabelValues(name, resultShared).Inc()
+	} else {
+		cacheRequests.WithLabelValues(name, resultMiss).Inc()
+	}
+
+	if err != nil {
+		// The query ran with the context of the caller that started it, whose cancellation must not fail the others.
+		if shared && ctx.Err() == nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+			return load()
+		}
+
+		var zero T
+		return zero, err
+	}
+
+	return value.(T), nil
+}
@@ -0,0 +1,185 @@
+package localcache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_CachesUntilInvalidated(t *testing.T) {
+	ctx := context.Background()
+	cache := New(10, time.Minute)
+
+	var calls int
+	load := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	value, err := Load(ctx, cache, "test", "a", load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	value, err = Load(ctx, cache, "test", "a", load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value, "the second read is served from the cache")
+
+	value, err = Load(ctx, cache, "other", "a", load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, value, "names do not share entries")
+
+	cache.Invalidate()
+
+	value, err = Load(ctx, cache, "test", "a", load)
+	require.NoError(t, err)
+	assert.Equal(t, 3, value)
+}
+
+func TestLoad_Expires(t *testing.T) {
+	ctx := context.Background()
+	cache := New(10, 10*time.Millisecond)
+
+	var calls int
+	load := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	_, err := Load(ctx, cache, "test", "a", load)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		value, err := Load(ctx, cache, "test", "a", load)
+		return err == nil && value > 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestLoad_ErrorsAreNotCached(t *testing.T) {
+	ctx := context.Background()
+	cache := New(10, time.Minute)
+	errDB := errors.New("db is down")
+
+	_, err := Load(ctx, cache, "test", "a", func() (int, error) { return 0, errDB })
+	assert.ErrorIs(t, err, errDB)
+
+	value, err := Load(ctx, cache, "test", "a", func() (int, error) { return 7, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 7, value)
+}
+
+func TestLoad_CollapsesConcurrentMisses(t *testing.T) {
+	ctx := context.Background()
+	cache := New(10, time.Minute)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func() (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	const readers = 8
+
+	var wg sync.WaitGroup
+	results := make([]int, readers)
+
+	for i := range readers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			value, err := Load(ctx, cache, "test", "a", load)
+			assert.NoError(t, err)
+			results[i] = value
+		}()
+	}
+
+	// Give the readers time to join the query in flight before it completes.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, value := range results {
+		assert.Equal(t, 42, value)
+	}
+}
+
+func TestLoad_InvalidationDuringLoad(t *testing.T) {
+	ctx := context.Background()
+	cache := New(10, time.Minute)
+
+	// The cache is invalidated while the first query runs, as if a write committed meanwhile.
+	_, err := Load(ctx, cache, "test", "a", func() (string, error) {
+		cache.Invalidate()
+		return "before write", nil
+	})
+	require.NoError(t, err)
+
+	value, err := Load(ctx, cache, "test", "a", func() (string, error) { return "after write", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "after write", value, "a result loaded before the invalidation is not stored")
+}
+
+func TestLoad_CancelledLeaderDoesNotFailOthers(t *testing.T) {
+	cache := New(10, time.Minute)
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+
+	var leaderErr error
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_, leaderErr = Load(leaderCtx, cache, "test", "a", func() (int, error) {
+			close(started)
+			<-leaderCtx.Done()
+			// Give the follower time to join the query in flight before it fails.
+			time.Sleep(20 * time.Millisecond)
+			return 0, leaderCtx.Err()
+		})
+	}()
+
+	<-started
+	cancel()
+
+	value, err := Load(context.Background(), cache, "test", "a", func() (int, error) { return 5, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 5, value)
+
+	<-done
+	assert.ErrorIs(t, leaderErr, context.Canceled)
+}
+
+func TestLoad_NilCache(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		cache *Cache
+		calls int
+	)
+
+	load := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	for range 2 {
+		_, err := Load(ctx, cache, "test", "a", load)
+		require.NoError(t, err)
+	}
+
+	cache.Invalidate()
+
+	assert.Equal(t, 2, calls, "a nil cache runs every query")
+}
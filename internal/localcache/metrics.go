@@ -0,0 +1,15 @@
+package localcache
+
+import (
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Values of the result label of the local_cache_requests_total metric.
+const (
+	resultHit    = "hit"
+	resultMiss   = "miss"
+	resultShared = "shared"
+)
+
+var cacheRequests = promauto.NewCounterVec(metrics.LocalCacheRequests.CounterOpts(), metrics.LocalCacheRequests.Labels)
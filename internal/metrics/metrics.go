@@ -0,0 +1,448 @@
+// Package metrics is the catalog of the Prometheus metrics exported by the service.
+// Collectors are built from these definitions and tools/dashboards renders the Grafana
+// dashboards from them, so a renamed metric cannot be left behind in a dashboard.
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Type is the Prometheus metric type.
+type Type string
+
+const (
+	Counter   Type = "counter"
+	Gauge     Type = "gauge"
+	Histogram Type = "histogram"
+)
+
+// Group is the part of the service a metric describes; every group gets its own dashboard row.
+type Group string
+
+const (
+	GroupHTTP     Group = "http"
+	GroupBusiness Group = "business"
+	GroupWorker   Group = "worker"
+	GroupOutbound Group = "outbound"
+	GroupDB       Group = "db"
+	GroupCache    Group = "cache"
+)
+
+// Groups lists the groups in dashboard order.
+var Groups = []Group{GroupHTTP, GroupBusiness, GroupWorker, GroupOutbound, GroupDB, GroupCache}
+
+// Metric describes a single metric.
+type Metric struct {
+	Name   string
+	Help   string
+	Type   Type
+	Group  Group
+	Labels []string
+}
+
+// CounterOpts returns the options for a counter collector of the metric.
+func (m Metric) CounterOpts() prometheus.CounterOpts {
+	return prometheus.CounterOpts{Name: m.Name, Help: m.Help}
+}
+
+// GaugeOpts returns the options for a gauge collector of the metric.
+func (m Metric) GaugeOpts() prometheus.GaugeOpts {
+	return prometheus.GaugeOpts{Name: m.Name, Help: m.Help}
+}
+
+// HistogramOpts returns the options for a histogram collector of the metric.
+func (m Metric) HistogramOpts(buckets []float64) prometheus.HistogramOpts {
+	return prometheus.HistogramOpts{Name: m.Name, Help: m.Help, Buckets: buckets}
+}
+
+var (
+	HTTPRequests = Metric{
+		Name:   "http_requests_total",
+		Help:   "Total number of HTTP requests",
+		Type:   Counter,
+		Group:  GroupHTTP,
+		Labels: []string{"path", "method", "status"},
+	}
+	HTTPRequestDuration = Metric{
+		Name:   "http_request_duration_seconds",
+		Help:   "Duration of HTTP requests in seconds",
+		Type:   Histogram,
+		Group:  GroupHTTP,
+		Labels: []string{"path", "method"},
+	}
+	AuthFailures = Metric{
+		Name:   "auth_failures_total",
+		Help:   "Total number of requests rejected by authentication or authorization",
+		Type:   Counter,
+		Group:  GroupHTTP,
+		Labels: []string{"path", "method", "reason"},
+	}
+	AuthFailureBursts = Metric{
+		Name:   "auth_failure_bursts_total",
+		Help:   "Number of time windows in which rejected requests reached the burst threshold",
+		Type:   Counter,
+		Group:  GroupHTTP,
+		Labels: []string{"reason"},
+	}
+	ReadTokenRequests = Metric{
+		Name:   "read_token_requests_total",
+		Help:   "Total number of API requests made with read tokens, by whether the rate limit let them through",
+		Type:   Counter,
+		Group:  GroupHTTP,
+		Labels: []string{"outcome"},
+	}
+	ValidationFailures = Metric{
+		Name:   "validation_failures_total",
+		Help:   "Total number of request body fields rejected by validation",
+		Type:   Counter,
+		Group:  GroupHTTP,
+		Labels: []string{"endpoint", "field", "tag"},
+	}
+	SwaggerRequests = Metric{
+		Name:   "swagger_requests_total",
+		Help:   "Total number of swagger UI requests, kept out of http_requests_total so that UI asset hits are not counted as API traffic",
+		Type:   Counter,
+		Group:  GroupHTTP,
+		Labels: []string{"status"},
+	}
+	GitHubWebhookDeliveries = Metric{
+		Name:   "github_webhook_deliveries_total",
+		Help:   "Total number of GitHub webhook deliveries by outcome",
+		Type:   Counter,
+		Group:  GroupHTTP,
+		Labels: []string{"outcome"},
+	}
+	GitLabWebhookDeliveries = Metric{
+		Name:   "gitlab_webhook_deliveries_total",
+		Help:   "Total number of GitLab webhook deliveries by outcome",
+		Type:   Counter,
+		Group:  GroupHTTP,
+		Labels: []string{"outcome"},
+	}
+
+	WebhookShadowEvents = Metric{
+		Name:   "webhook_shadow_events_total",
+		Help:   "Total number of webhook events handled in shadow mode by provider and outcome",
+		Type:   Counter,
+		Group:  GroupHTTP,
+		Labels: []string{"provider", "outcome"},
+	}
+
+	WebhookShadowReviewers = Metric{
+		Name:   "webhook_shadow_reviewers_total",
+		Help:   "Total number of reviewers selected in shadow mode by provider and strategy",
+		Type:   Counter,
+		Group:  GroupHTTP,
+		Labels: []string{"provider", "strategy"},
+	}
+
+	PullRequestsCreated = Metric{
+		Name:  "pull_requests_created_total",
+		Help:  "Total number of created pull requests",
+		Type:  Counter,
+		Group: GroupBusiness,
+	}
+	PullRequestsMerged = Metric{
+		Name:  "pull_requests_merged_total",
+		Help:  "Total number of merged pull requests",
+		Type:  Counter,
+		Group: GroupBusiness,
+	}
+	PullRequestsClosed = Metric{
+		Name:  "pull_requests_closed_total",
+		Help:  "Total number of pull requests closed without merging",
+		Type:  Counter,
+		Group: GroupBusiness,
+	}
+	MergesFrozen = Metric{
+		Name:   "merges_frozen_total",
+		Help:   "Total number of merges attempted during a freeze window, by outcome",
+		Type:   Counter,
+		Group:  GroupBusiness,
+		Labels: []string{"outcome"},
+	}
+	ReviewersAssigned = Metric{
+		Name:   "reviewers_assigned_total",
+		Help:   "Total number of reviewers assigned to new pull requests",
+		Type:   Counter,
+		Group:  GroupBusiness,
+		Labels: []string{"strategy"},
+	}
+	ReviewerReassignments = Metric{
+		Name:   "reviewer_reassignments_total",
+		Help:   "Total number of reviewers replaced on open pull requests",
+		Type:   Counter,
+		Group:  GroupBusiness,
+		Labels: []string{"strategy"},
+	}
+	ReviewsRebalanced = Metric{
+		Name:  "reviews_rebalanced_total",
+		Help:  "Total number of reviews moved from loaded teammates to users who became active again",
+		Type:  Counter,
+		Group: GroupBusiness,
+	}
+	ReviewerInvariantViolations = Metric{
+		Name:   "reviewer_invariant_violations_total",
+		Help:   "Total number of reviewer invariant violations found after assignments, reassignments and merges",
+		Type:   Counter,
+		Group:  GroupBusiness,
+		Labels: []string{"operation", "violation"},
+	}
+
+	// The open pull request metrics are sampled periodically rather than updated on every change, see internal/sampler.
+
+	OpenPullRequests = Metric{
+		Name:   "open_pull_requests",
+		Help:   "Number of open pull requests at the last sample",
+		Type:   Gauge,
+		Group:  GroupBusiness,
+		Labels: []string{"team"},
+	}
+	OpenPullRequestAge = Metric{
+		Name:   "open_pull_request_age_seconds",
+		Help:   "Age of open pull requests in seconds at the last sample",
+		Type:   Gauge,
+		Group:  GroupBusiness,
+		Labels: []string{"team", "quantile"},
+	}
+
+	SimulatorEvents = Metric{
+		Name:   "simulator_events_total",
+		Help:   "Total number of events generated by the traffic simulator",
+		Type:   Counter,
+		Group:  GroupWorker,
+		Labels: []string{"outcome"},
+	}
+	SimulatorStepDuration = Metric{
+		Name:  "simulator_step_duration_seconds",
+		Help:  "Duration of a single traffic simulator step in seconds",
+		Type:  Histogram,
+		Group: GroupWorker,
+	}
+	PendingBackfillRuns = Metric{
+		Name:   "pending_backfill_runs_total",
+		Help:   "Total number of runs of the pending assignment backfill worker",
+		Type:   Counter,
+		Group:  GroupWorker,
+		Labels: []string{"outcome"},
+	}
+	PendingBackfillPullRequests = Metric{
+		Name:   "pending_backfill_pull_requests_total",
+		Help:   "Total number of unqueued pull requests needing reviewers handled by the backfill, by outcome",
+		Type:   Counter,
+		Group:  GroupWorker,
+		Labels: []string{"outcome"},
+	}
+	PendingReviewersFilled = Metric{
+		Name:  "pending_reviewers_filled_total",
+		Help:  "Total number of reviewers assigned to queued pull requests",
+		Type:  Counter,
+		Group: GroupWorker,
+	}
+	WebhookDeliveryAttempts = Metric{
+		Name:   "webhook_delivery_attempts_total",
+		Help:   "Total number of attempts to deliver PR events to the outbound webhooks by event and outcome",
+		Type:   Counter,
+		Group:  GroupWorker,
+		Labels: []string{"event", "outcome"},
+	}
+	OutboxPublishAttempts = Metric{
+		Name:   "outbox_publish_attempts_total",
+		Help:   "Total number of attempts to publish the PR events written to the outbox by sink and outcome",
+		Type:   Counter,
+		Group:  GroupWorker,
+		Labels: []string{"sink", "outcome"},
+	}
+	NotificationsDropped = Metric{
+		Name:  "notifications_dropped_total",
+		Help:  "Total number of notification events dropped because the delivery queue was full",
+		Type:  Counter,
+		Group: GroupWorker,
+	}
+
+	// The outbound metrics are exported by the shared client of the integrations, see internal/httpclient.
+
+	OutboundRequests = Metric{
+		Name:   "outbound_requests_total",
+		Help:   "Total number of outbound HTTP request attempts",
+		Type:   Counter,
+		Group:  GroupOutbound,
+		Labels: []string{"client", "host", "outcome"},
+	}
+	OutboundRequestDuration = Metric{
+		Name:   "outbound_request_duration_seconds",
+		Help:   "Duration of outbound HTTP request attempts in seconds",
+		Type:   Histogram,
+		Group:  GroupOutbound,
+		Labels: []string{"client", "host"},
+	}
+	OutboundRetries = Metric{
+		Name:   "outbound_retries_total",
+		Help:   "Total number of retried outbound HTTP requests",
+		Type:   Counter,
+		Group:  GroupOutbound,
+		Labels: []string{"client", "host"},
+	}
+	OutboundCircuitState = Metric{
+		Name:   "outbound_circuit_state",
+		Help:   "State of the circuit breaker of an outbound host: 0 closed, 1 half-open, 2 open",
+		Type:   Gauge,
+		Group:  GroupOutbound,
+		Labels: []string{"client", "host"},
+	}
+
+	// The DB pool metrics are exported by the client_golang DBStats collector, see RegisterDBStats.
+
+	DBOpenConnections = Metric{
+		Name:   "go_sql_open_connections",
+		Help:   "The number of established connections both in use and idle",
+		Type:   Gauge,
+		Group:  GroupDB,
+		Labels: []string{"db_name"},
+	}
+	DBInUseConnections = Metric{
+		Name:   "go_sql_in_use_connections",
+		Help:   "The number of connections currently in use",
+		Type:   Gauge,
+		Group:  GroupDB,
+		Labels: []string{"db_name"},
+	}
+	DBIdleConnections = Metric{
+		Name:   "go_sql_idle_connections",
+		Help:   "The number of idle connections",
+		Type:   Gauge,
+		Group:  GroupDB,
+		Labels: []string{"db_name"},
+	}
+	DBWaitCount = Metric{
+		Name:   "go_sql_wait_count_total",
+		Help:   "The total number of connections waited for",
+		Type:   Counter,
+		Group:  GroupDB,
+		Labels: []string{"db_name"},
+	}
+	DBWaitDuration = Metric{
+		Name:   "go_sql_wait_duration_seconds_total",
+		Help:   "The total time blocked waiting for a new connection",
+		Type:   Counter,
+		Group:  GroupDB,
+		Labels: []string{"db_name"},
+	}
+	// DBMaxOpenConnections is postgres.max_open_conns; go_sql_in_use_connections close to it, along with
+	// a growing go_sql_wait_count_total, means the pool is too small.
+	DBMaxOpenConnections = Metric{
+		Name:   "go_sql_max_open_connections",
+		Help:   "Maximum number of open connections to the database",
+		Type:   Gauge,
+		Group:  GroupDB,
+		Labels: []string{"db_name"},
+	}
+	// The connections closed by the pool settings: many closed for being idle mean postgres.max_idle_conns
+	// or postgres.conn_max_idle_time is too low for the traffic, and the pool keeps reconnecting.
+	DBMaxIdleClosed = Metric{
+		Name:   "go_sql_max_idle_closed_total",
+		Help:   "The total number of connections closed due to SetMaxIdleConns",
+		Type:   Counter,
+		Group:  GroupDB,
+		Labels: []string{"db_name"},
+	}
+	DBMaxIdleTimeClosed = Metric{
+		Name:   "go_sql_max_idle_time_closed_total",
+		Help:   "The total number of connections closed due to SetConnMaxIdleTime",
+		Type:   Counter,
+		Group:  GroupDB,
+		Labels: []string{"db_name"},
+	}
+	DBMaxLifetimeClosed = Metric{
+		Name:   "go_sql_max_lifetime_closed_total",
+		Help:   "The total number of connections closed due to SetConnMaxLifetime",
+		Type:   Counter,
+		Group:  GroupDB,
+		Labels: []string{"db_name"},
+	}
+	// CacheRequests counts the reads of the Redis cache in front of the team and user reads by outcome:
+	// hit, miss or error, the last falling back to the database.
+	CacheRequests = Metric{
+		Name:   "cache_requests_total",
+		Help:   "Total number of reads of the team and user cache",
+		Type:   Counter,
+		Group:  GroupCache,
+		Labels: []string{"cache", "result"},
+	}
+	// LocalCacheRequests counts the reads of the in-process cache of GET /team/get and GET /users/getReview
+	// by outcome: hit, miss, or shared when the read waited for an identical query already in flight.
+	LocalCacheRequests = Metric{
+		Name:   "local_cache_requests_total",
+		Help:   "Total number of reads of the in-process response cache",
+		Type:   Counter,
+		Group:  GroupCache,
+		Labels: []string{"cache", "result"},
+	}
+)
+
+// HTTPDurationBuckets are the buckets of HTTPRequestDuration; latency objectives must use one of them.
+var HTTPDurationBuckets = prometheus.DefBuckets
+
+// All returns every metric of the catalog.
+func All() []Metric {
+	return []Metric{
+		HTTPRequests,
+		HTTPRequestDuration,
+		AuthFailures,
+		AuthFailureBursts,
+		ReadTokenRequests,
+		ValidationFailures,
+		SwaggerRequests,
+		GitHubWebhookDeliveries,
+		GitLabWebhookDeliveries,
+		WebhookShadowEvents,
+		WebhookShadowReviewers,
+		PullRequestsCreated,
+		PullRequestsMerged,
+		PullRequestsClosed,
+		MergesFrozen,
+		ReviewersAssigned,
+		ReviewerReassignments,
+		ReviewsRebalanced,
+		ReviewerInvariantViolations,
+		OpenPullRequests,
+		OpenPullRequestAge,
+		SimulatorEvents,
+		SimulatorStepDuration,
+		PendingBackfillRuns,
+		PendingBackfillPullRequests,
+		PendingReviewersFilled,
+		WebhookDeliveryAttempts,
+		OutboxPublishAttempts,
+		NotificationsDropped,
+		OutboundRequests,
+		OutboundRequestDuration,
+		OutboundRetries,
+		OutboundCircuitState,
+		DBOpenConnections,
+		DBInUseConnections,
+		DBIdleConnections,
+		DBWaitCount,
+		DBWaitDuration,
+		DBMaxOpenConnections,
+		DBMaxIdleClosed,
+		DBMaxIdleTimeClosed,
+		DBMaxLifetimeClosed,
+		CacheRequests,
+		LocalCacheRequests,
+	}
+}
+
+// Values of the db_name label of the DB pool metrics.
+const (
+	DBName        = "postgres"
+	ReplicaDBName = "postgres_replica"
+)
+
+// RegisterDBStats exports the connection pool statistics of db to the default registry, labelled with name.
+func RegisterDBStats(db *sql.DB, name string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, name))
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAll_UniqueNamesInKnownGroups(t *testing.T) {
+	seen := make(map[string]bool)
+
+	for _, m := range All() {
+		assert.False(t, seen[m.Name], "duplicate metric %s", m.Name)
+		seen[m.Name] = true
+
+		assert.Contains(t, Groups, m.Group, "metric %s", m.Name)
+		assert.NotEmpty(t, m.Help, "metric %s", m.Name)
+	}
+}
+
+func TestDBMetricsMatchDBStatsCollector(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collectors.NewDBStatsCollector(db, DBName)))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	exported := make(map[string]bool, len(families))
+	for _, family := range families {
+		exported[family.GetName()] = true
+	}
+
+	for _, m := range All() {
+		if m.Group == GroupDB {
+			assert.True(t, exported[m.Name], "metric %s is not exported by the DBStats collector", m.Name)
+		}
+	}
+}
+
+func TestRegisterDBStats_RefreshedOnScrape(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	db.SetMaxOpenConns(7)
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collectors.NewDBStatsCollector(db, DBName)))
+
+	assert.InDelta(t, 7, gauge(t, registry, DBMaxOpenConnections.Name), 0)
+	assert.InDelta(t, 0, gauge(t, registry, DBInUseConnections.Name), 0)
+
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	assert.InDelta(t, 1, gauge(t, registry, DBInUseConnections.Name), 0, "the pool is read at every scrape")
+
+	require.NoError(t, conn.Close())
+	assert.InDelta(t, 0, gauge(t, registry, DBInUseConnections.Name), 0)
+}
+
+// gauge scrapes the value of a gauge of the registry.
+func gauge(t *testing.T, registry *prometheus.Registry, name string) float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() == name {
+			return family.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+
+	t.Fatalf("metric %s is not exported", name)
+
+	return 0
+}
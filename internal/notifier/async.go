@@ -0,0 +1,69 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var notificationsDroppedTotal = promauto.NewCounter(metrics.NotificationsDropped.CounterOpts())
+
+// Async is a service.Notifier handing the events over to a fixed number of workers, so that the request
+// which caused an event does not wait for the deliveries. The events are queued in memory: those arriving
+// at a full queue are dropped and counted, and those still queued on shutdown are lost.
+type Async struct {
+	log     *slog.Logger
+	next    service.Notifier
+	queue   chan domain.Event
+	workers int
+}
+
+// NewAsync creates an Async delivering the events through next once Run is started.
+func NewAsync(log *slog.Logger, next service.Notifier, queueSize, workers int) *Async {
+	return &Async{
+		log:     log.With(slog.String("component", "notifier")),
+		next:    next,
+		queue:   make(chan domain.Event, queueSize),
+		workers: workers,
+	}
+}
+
+// Notify queues event without blocking. The deliveries do not use ctx, which ends with the request.
+func (a *Async) Notify(_ context.Context, event domain.Event) {
+	select {
+	case a.queue <- event:
+	default:
+		notificationsDroppedTotal.Inc()
+		a.log.Warn("notification queue is full, event dropped",
+			slog.String("event", string(event.Type)), slog.String("pr_id", event.PullRequestID))
+	}
+}
+
+// Run delivers the queued events until ctx is cancelled.
+func (a *Async) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for range a.workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event := <-a.queue:
+					a.next.Notify(ctx, event)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
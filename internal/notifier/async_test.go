@@ -0,0 +1,63 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notifierFunc is a service.Notifier calling the function with every event.
+type notifierFunc func(ctx context.Context, event domain.Event)
+
+func (f notifierFunc) Notify(ctx context.Context, event domain.Event) { f(ctx, event) }
+
+func TestAsync(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	delivered := make(chan string, 2)
+	async := NewAsync(logger, notifierFunc(func(_ context.Context, event domain.Event) {
+		delivered <- event.PullRequestID
+	}), 2, 1)
+
+	requestCtx, cancelRequest := context.WithCancel(context.Background())
+	droppedBefore := testutil.ToFloat64(notificationsDroppedTotal)
+
+	async.Notify(requestCtx, domain.Event{PullRequestID: "pr-1"})
+	async.Notify(requestCtx, domain.Event{PullRequestID: "pr-2"})
+	async.Notify(requestCtx, domain.Event{PullRequestID: "pr-3"})
+	cancelRequest()
+
+	assert.Equal(t, droppedBefore+1, testutil.ToFloat64(notificationsDroppedTotal), "the event arriving at a full queue is dropped")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		async.Run(ctx)
+		close(done)
+	}()
+
+	for _, want := range []string{"pr-1", "pr-2"} {
+		select {
+		case got := <-delivered:
+			assert.Equal(t, want, got, "the events are delivered after the request has ended")
+		case <-time.After(time.Second):
+			require.Fail(t, "event not delivered", want)
+		}
+	}
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.Fail(t, "workers did not stop")
+	}
+}
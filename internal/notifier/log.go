@@ -0,0 +1,61 @@
+// package notifier provides implementations of service.Notifier.
+package notifier
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+// LogChannel is the name of LogNotifier as a notification channel.
+const LogChannel = "log"
+
+// LogNotifier writes every event to the log instead of delivering it.
+// It stands in for a real notification channel in the dev composition mode. Besides being
+// a service.Notifier, it is a service.NotificationChannel writing a line per recipient.
+type LogNotifier struct {
+	log *slog.Logger
+}
+
+func NewLogNotifier(log *slog.Logger) *LogNotifier {
+	return &LogNotifier{
+		log: log.With(slog.String("component", "notifier")),
+	}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, event domain.Event) {
+	attrs := []any{
+		slog.String("event", string(event.Type)),
+		slog.String("pr_id", event.PullRequestID),
+		slog.String("pr_name", event.PullRequestName),
+		slog.Any("recipients", event.UserIDs),
+		slog.Any("subscribers", event.SubscriberIDs),
+		slog.Time("occurred_at", event.OccurredAt),
+	}
+
+	if event.ExternalURL != nil {
+		attrs = append(attrs, slog.String("external_url", *event.ExternalURL))
+	}
+
+	if event.HandoffNote != nil {
+		attrs = append(attrs, slog.String("handoff_note", *event.HandoffNote))
+	}
+
+	n.log.InfoContext(ctx, "notification", attrs...)
+}
+
+func (n *LogNotifier) Name() string {
+	return LogChannel
+}
+
+func (n *LogNotifier) Deliver(ctx context.Context, recipientID string, event domain.Event) error {
+	n.log.InfoContext(ctx, "notification delivered",
+		slog.String("event", string(event.Type)),
+		slog.String("pr_id", event.PullRequestID),
+		slog.String("recipient", recipientID),
+		slog.Time("occurred_at", event.OccurredAt),
+	)
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+// SlackChannel is the name of SlackNotifier as a notification channel.
+const SlackChannel = "slack"
+
+// slackPostMessageURL is the Web API method the messages are sent with when a bot token is configured.
+const slackPostMessageURL = "https://slack.com/api/chat.postMessage"
+
+// maxSlackResponseSize bounds the part of a Slack response that is read.
+const maxSlackResponseSize = 64 << 10
+
+// HTTPDoer sends the requests of a notifier; *httpclient.Client is the one used in production.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// SlackUserResolver finds the Slack member ID a user is mapped to, see repository.SlackUserRepository.
+type SlackUserResolver interface {
+	GetSlackUser(ctx context.Context, userID string) (*domain.SlackUser, error)
+}
+
+// SlackNotifier is a service.NotificationChannel sending a direct message to a reviewer who has been assigned
+// to a pull request. With a bot token the message is posted to the reviewer through chat.postMessage;
+// with an incoming webhook it is posted to the channel of the webhook, mentioning the reviewer.
+// The other events and the subscribers are left to the other channels.
+type SlackNotifier struct {
+	client     HTTPDoer
+	users      SlackUserResolver
+	botToken   string
+	webhookURL string
+}
+
+// NewSlackNotifier creates a SlackNotifier posting with the bot token of cfg, or through its webhook if there is none.
+func NewSlackNotifier(cfg config.Slack, client HTTPDoer, users SlackUserResolver) *SlackNotifier {
+	return &SlackNotifier{
+		client:     client,
+		users:      users,
+		botToken:   cfg.BotToken,
+		webhookURL: cfg.WebhookURL,
+	}
+}
+
+func (n *SlackNotifier) Name() string {
+	return SlackChannel
+}
+
+// Accepts reports whether the event asks the recipient to review a pull request.
+func (n *SlackNotifier) Accepts(recipientID string, event domain.Event) bool {
+	switch event.Type {
+	case domain.EventReviewersAssigned, domain.EventReviewerReassigned:
+		return slices.Contains(event.UserIDs, recipientID)
+	default:
+		return false
+	}
+}
+
+// Deliver messages the recipient about event. A recipient who is not mapped to a Slack user
+// fails the delivery, so that the missing mapping shows up in the delivery log.
+func (n *SlackNotifier) Deliver(ctx context.Context, recipientID string, event domain.Event) error {
+	mapping, err := n.users.GetSlackUser(ctx, recipientID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return fmt.Errorf("user %s is not mapped to a slack user", recipientID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to get slack user: %w", err)
+	}
+
+	text := slackMessage(event)
+
+	if n.botToken != "" {
+		return n.postMessage(ctx, mapping.SlackUserID, text)
+	}
+
+	return n.postWebhook(ctx, fmt.Sprintf("<@%s> %s", mapping.SlackUserID, text))
+}
+
+// postMessage sends text to the Slack user through chat.postMessage, which opens the direct message conversation
+// with the bot. The method answers 200 to a rejected message as well and reports the error in the body.
+func (n *SlackNotifier) postMessage(ctx context.Context, slackUserID, text string) error {
+	resp, err := n.post(ctx, slackPostMessageURL, map[string]string{"channel": slackUserID, "text": text})
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return fmt.Errorf("failed to decode slack response: %w", err)
+	}
+
+	if !result.OK {
+		return fmt.Errorf("slack rejected the message: %s", result.Error)
+	}
+
+	return nil
+}
+
+func (n *SlackNotifier) postWebhook(ctx context.Context, text string) error {
+	_, err := n.post(ctx, n.webhookURL, map[string]string{"text": text})
+	return err
+}
+
+// post sends payload as JSON to url and returns the body of a 2xx response.
+func (n *SlackNotifier) post(ctx context.Context, url string, payload any) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	if n.botToken != "" {
+		req.Header.Set("Authorization", "Bearer "+n.botToken)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		// The body is drained so that the connection can be reused.
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("slack responded with status %d", resp.StatusCode)
+	}
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxSlackResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read slack response: %w", err)
+	}
+
+	return respBody, nil
+}
+
+// slackMessage is the text of the message about event, linking the pull request to its external URL if it has one.
+func slackMessage(event domain.Event) string {
+	pr := fmt.Sprintf("*%s* (%s)", slackEscape(event.PullRequestName), slackEscape(event.PullRequestID))
+	if event.ExternalURL != nil && *event.ExternalURL != "" {
+		pr = fmt.Sprintf("<%s|%s> (%s)", *event.ExternalURL, slackEscape(event.PullRequestName), slackEscape(event.PullRequestID))
+	}
+
+	if event.Type == domain.EventReviewerReassigned {
+		msg := "The review of pull request " + pr + " has been reassigned to you"
+		if event.HandoffNote != nil {
+			// Every line of the note is quoted, so that it reads apart from the message.
+			msg += "\n>" + strings.ReplaceAll(slackEscape(*event.HandoffNote), "\n", "\n>")
+		}
+
+		return msg
+	}
+
+	return "You have been assigned to review pull request " + pr
+}
+
+// slackEscape escapes the characters Slack treats as control characters in message text.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,153 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// doerFunc is an HTTPDoer answering every request with the function.
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+// slackUsers is a SlackUserResolver over a map of user IDs to Slack member IDs.
+type slackUsers map[string]string
+
+func (u slackUsers) GetSlackUser(_ context.Context, userID string) (*domain.SlackUser, error) {
+	slackUserID, ok := u[userID]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+
+	return &domain.SlackUser{UserID: userID, SlackUserID: slackUserID}, nil
+}
+
+// slackServer records the requests it is sent and answers them with status and body.
+type slackServer struct {
+	status   int
+	body     string
+	requests []*http.Request
+	payloads []map[string]string
+}
+
+func (s *slackServer) Do(req *http.Request) (*http.Response, error) {
+	var payload map[string]string
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	s.requests = append(s.requests, req)
+	s.payloads = append(s.payloads, payload)
+
+	return &http.Response{StatusCode: s.status, Body: io.NopCloser(strings.NewReader(s.body))}, nil
+}
+
+func TestSlackNotifier_Accepts(t *testing.T) {
+	n := NewSlackNotifier(config.Slack{BotToken: "xoxb-token"}, nil, slackUsers{})
+
+	assigned := domain.Event{Type: domain.EventReviewersAssigned, UserIDs: []string{"u2"}, SubscriberIDs: []string{"u4"}}
+	assert.True(t, n.Accepts("u2", assigned))
+	assert.False(t, n.Accepts("u4", assigned), "subscribers are not messaged")
+
+	reassigned := domain.Event{Type: domain.EventReviewerReassigned, UserIDs: []string{"u3"}}
+	assert.True(t, n.Accepts("u3", reassigned))
+
+	merged := domain.Event{Type: domain.EventPRMerged, UserIDs: []string{"u2"}}
+	assert.False(t, n.Accepts("u2", merged))
+}
+
+func TestSlackNotifier_Deliver(t *testing.T) {
+	ctx := context.Background()
+	url := "https://git.example.com/pr/1"
+	event := domain.Event{
+		Type:            domain.EventReviewersAssigned,
+		PullRequestID:   "pr-1",
+		PullRequestName: "Fix <script> & styles",
+		ExternalURL:     &url,
+		UserIDs:         []string{"u2"},
+	}
+	users := slackUsers{"u2": "U024BE7LH"}
+
+	t.Run("Bot token", func(t *testing.T) {
+		server := &slackServer{status: http.StatusOK, body: `{"ok":true}`}
+
+		err := NewSlackNotifier(config.Slack{BotToken: "xoxb-token", WebhookURL: "https://hooks.slack.com/x"}, server, users).
+			Deliver(ctx, "u2", event)
+
+		require.NoError(t, err)
+		require.Len(t, server.requests, 1)
+		assert.Equal(t, slackPostMessageURL, server.requests[0].URL.String(), "the bot token takes precedence over the webhook")
+		assert.Equal(t, "Bearer xoxb-token", server.requests[0].Header.Get("Authorization"))
+		assert.Equal(t, map[string]string{
+			"channel": "U024BE7LH",
+			"text":    "You have been assigned to review pull request <https://git.example.com/pr/1|Fix &lt;script&gt; &amp; styles> (pr-1)",
+		}, server.payloads[0])
+	})
+
+	t.Run("Message rejected", func(t *testing.T) {
+		server := &slackServer{status: http.StatusOK, body: `{"ok":false,"error":"user_not_found"}`}
+
+		err := NewSlackNotifier(config.Slack{BotToken: "xoxb-token"}, server, users).Deliver(ctx, "u2", event)
+
+		assert.ErrorContains(t, err, "user_not_found")
+	})
+
+	t.Run("Webhook", func(t *testing.T) {
+		server := &slackServer{status: http.StatusOK, body: "ok"}
+		reassigned := domain.Event{Type: domain.EventReviewerReassigned, PullRequestID: "pr-1", PullRequestName: "Fix", UserIDs: []string{"u2"}}
+
+		err := NewSlackNotifier(config.Slack{WebhookURL: "https://hooks.slack.com/x"}, server, users).Deliver(ctx, "u2", reassigned)
+
+		require.NoError(t, err)
+		require.Len(t, server.requests, 1)
+		assert.Equal(t, "https://hooks.slack.com/x", server.requests[0].URL.String())
+		assert.Empty(t, server.requests[0].Header.Get("Authorization"))
+		assert.Equal(t, map[string]string{
+			"text": "<@U024BE7LH> The review of pull request *Fix* (pr-1) has been reassigned to you",
+		}, server.payloads[0])
+	})
+
+	t.Run("Handoff note", func(t *testing.T) {
+		server := &slackServer{status: http.StatusOK, body: "ok"}
+		note := "Migrations <done>\nhandlers left"
+		reassigned := domain.Event{
+			Type: domain.EventReviewerReassigned, PullRequestID: "pr-1", PullRequestName: "Fix", UserIDs: []string{"u2"}, HandoffNote: &note,
+		}
+
+		err := NewSlackNotifier(config.Slack{WebhookURL: "https://hooks.slack.com/x"}, server, users).Deliver(ctx, "u2", reassigned)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"text": "<@U024BE7LH> The review of pull request *Fix* (pr-1) has been reassigned to you\n>Migrations &lt;done&gt;\n>handlers left",
+		}, server.payloads[0])
+	})
+
+	t.Run("Webhook failure", func(t *testing.T) {
+		server := &slackServer{status: http.StatusForbidden, body: "invalid_token"}
+
+		err := NewSlackNotifier(config.Slack{WebhookURL: "https://hooks.slack.com/x"}, server, users).Deliver(ctx, "u2", event)
+
+		assert.ErrorContains(t, err, "status 403")
+	})
+
+	t.Run("Unmapped recipient", func(t *testing.T) {
+		client := doerFunc(func(*http.Request) (*http.Response, error) {
+			t.Fatal("nothing is sent to an unmapped recipient")
+			return nil, nil
+		})
+
+		err := NewSlackNotifier(config.Slack{BotToken: "xoxb-token"}, client, users).Deliver(ctx, "u9", event)
+
+		assert.ErrorContains(t, err, "not mapped")
+	})
+}
@@ -0,0 +1,262 @@
+// Package oidc signs the users of the API in through the corporate identity provider with the authorization code
+// flow of OpenID Connect, and verifies the ID tokens the provider issues, which the API accepts as bearer tokens.
+// Only the RS256 and ES256 signatures are supported, as they are the ones the identity providers use by default.
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+)
+
+// maxResponseSize bounds the part of a response of the identity provider that is read.
+const maxResponseSize = 1 << 20
+
+// HTTPDoer sends the requests to the identity provider; *httpclient.Client is the one used in production.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Provider is the identity provider of an OIDC client. It is safe for concurrent use.
+type Provider struct {
+	client       HTTPDoer
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+
+	authEndpoint  string
+	tokenEndpoint string
+	jwksURI       string
+
+	keys *keySet
+	now  func() time.Time
+}
+
+type Option func(*Provider)
+
+// WithClock sets the clock the expiry of the ID tokens is checked against.
+func WithClock(now func() time.Time) Option {
+	return func(p *Provider) {
+		p.now = now
+	}
+}
+
+// discoveryDocument is the part of the provider metadata the client uses.
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Discover reads the metadata of the provider of cfg.Issuer from its /.well-known/openid-configuration.
+func Discover(ctx context.Context, cfg config.OIDC, client HTTPDoer, opts ...Option) (*Provider, error) {
+	const op = "internal.oidc.Discover"
+
+	issuer := strings.TrimSuffix(cfg.Issuer, "/")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build request: %w", op, err)
+	}
+
+	var doc discoveryDocument
+	if err := getJSON(client, req, &doc); err != nil {
+		return nil, fmt.Errorf("%s: failed to read provider metadata: %w", op, err)
+	}
+
+	// The metadata must come from the issuer it names, or the tokens of another issuer would be accepted.
+	if strings.TrimSuffix(doc.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("%s: provider metadata names issuer %q instead of %q", op, doc.Issuer, cfg.Issuer)
+	}
+
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, fmt.Errorf("%s: provider metadata lacks the authorization, token or jwks endpoint", op)
+	}
+
+	p := &Provider{
+		client:        client,
+		issuer:        doc.Issuer,
+		clientID:      cfg.ClientID,
+		clientSecret:  cfg.ClientSecret,
+		redirectURL:   cfg.RedirectURL,
+		authEndpoint:  doc.AuthorizationEndpoint,
+		tokenEndpoint: doc.TokenEndpoint,
+		jwksURI:       doc.JWKSURI,
+		now:           time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.keys = newKeySet(client, doc.JWKSURI, p.now)
+
+	return p, nil
+}
+
+// AuthCodeURL returns the address of the sign-in page of the provider. The provider redirects the browser back
+// to the redirect URL with the code and state; the ID token it issues for the code carries nonce, and the code
+// can only be exchanged with verifier (PKCE).
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) string {
+	challenge := sha256.Sum256([]byte(verifier))
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.clientID},
+		"redirect_uri":          {p.redirectURL},
+		"scope":                 {"openid"},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(p.authEndpoint, "?") {
+		separator = "&"
+	}
+
+	return p.authEndpoint + separator + params.Encode()
+}
+
+// tokenResponse is the part of the token endpoint response the client uses.
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+}
+
+// Exchange trades the code of a sign-in for the ID token of the user.
+// Returns apperrors.ErrInvalidToken if the provider rejects the code.
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (string, error) {
+	const op = "internal.oidc.Exchange"
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.redirectURL},
+		"client_id":     {p.clientID},
+		"code_verifier": {verifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to build request: %w", op, err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// A public client authenticates with the code verifier alone.
+	if p.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+	}
+
+	var token tokenResponse
+	if err := getJSON(p.client, req, &token); err != nil {
+		var statusErr *statusError
+		if errors.As(err, &statusErr) && statusErr.code >= 400 && statusErr.code < 500 {
+			return "", fmt.Errorf("%s: %w: code rejected: %s", op, apperrors.ErrInvalidToken, err)
+		}
+
+		return "", fmt.Errorf("%s: failed to exchange code: %w", op, err)
+	}
+
+	if token.IDToken == "" {
+		return "", fmt.Errorf("%s: token response has no id_token", op)
+	}
+
+	return token.IDToken, nil
+}
+
+// statusError is a response of the provider with a status other than 200.
+type statusError struct {
+	code int
+	body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.code, e.body)
+}
+
+// getJSON sends req and decodes the JSON body of a 200 response into v.
+func getJSON(client HTTPDoer, req *http.Request, v any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &statusError{code: resp.StatusCode, body: strings.TrimSpace(string(body))}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// keySet caches the signing keys of the provider, refreshing them when a token is signed with an unknown key.
+type keySet struct {
+	client HTTPDoer
+	uri    string
+	now    func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]any
+	fetchedAt time.Time
+}
+
+// minKeyRefreshInterval bounds how often the keys are fetched, so that tokens signed with made-up key IDs
+// cannot make the service hammer the provider.
+const minKeyRefreshInterval = time.Minute
+
+func newKeySet(client HTTPDoer, uri string, now func() time.Time) *keySet {
+	return &keySet{client: client, uri: uri, now: now}
+}
+
+// key returns the public key with the ID kid, fetching the keys of the provider if it is not cached.
+func (ks *keySet) key(ctx context.Context, kid string) (any, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if key, ok := ks.keys[kid]; ok {
+		return key, nil
+	}
+
+	if ks.keys != nil && ks.now().Sub(ks.fetchedAt) < minKeyRefreshInterval {
+		return nil, fmt.Errorf("%w: unknown signing key %q", apperrors.ErrInvalidToken, kid)
+	}
+
+	keys, err := fetchKeys(ctx, ks.client, ks.uri)
+	if err != nil {
+		return nil, err
+	}
+
+	ks.keys, ks.fetchedAt = keys, ks.now()
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown signing key %q", apperrors.ErrInvalidToken, kid)
+	}
+
+	return key, nil
+}
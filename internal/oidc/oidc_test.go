@@ -0,0 +1,270 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+
+// fakeProvider is an identity provider serving its metadata, keys and token endpoint.
+type fakeProvider struct {
+	*httptest.Server
+	rsaKey     *rsa.PrivateKey
+	ecKey      *ecdsa.PrivateKey
+	keyFetches atomic.Int32
+	// idToken is the token the token endpoint issues for the code "good-code".
+	idToken string
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p := &fakeProvider{rsaKey: rsaKey, ecKey: ecKey}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.URL,
+			"authorization_endpoint": p.URL + "/authorize",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("GET /keys", func(w http.ResponseWriter, _ *http.Request) {
+		p.keyFetches.Add(1)
+
+		ecBytes, err := p.ecKey.PublicKey.Bytes()
+		require.NoError(t, err)
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": b64(p.rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(p.rsaKey.E)).Bytes())},
+			{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(ecBytes[1:33]), "y": b64(ecBytes[33:])},
+			{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": b64(p.rsaKey.N.Bytes()), "e": "AQAB"},
+		}})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if r.FormValue("code") != "good-code" || r.FormValue("code_verifier") != "verifier" || id != "pr-reviewer" || secret != "s3cret" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "opaque", "id_token": p.idToken})
+	})
+
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// sign makes a token of claims signed with the key of alg.
+func (p *fakeProvider) sign(t *testing.T, alg, kid string, claims map[string]any) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	input := b64(header) + "." + b64(payload)
+	digest := sha256.Sum256([]byte(input))
+
+	var signature []byte
+
+	switch alg {
+	case "RS256":
+		signature, err = rsa.SignPKCS1v15(rand.Reader, p.rsaKey, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, p.ecKey, digest[:])
+		require.NoError(t, err)
+
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	default:
+		signature = []byte("forged")
+	}
+
+	return input + "." + b64(signature)
+}
+
+// claims returns valid claims of the subject for the client, changed by the overrides.
+func (p *fakeProvider) claims(overrides map[string]any) map[string]any {
+	claims := map[string]any{
+		"iss": p.URL, "sub": "subject-1", "aud": "pr-reviewer", "nonce": "nonce-1",
+		"exp": testNow.Add(time.Hour).Unix(), "iat": testNow.Unix(),
+	}
+
+	for k, v := range overrides {
+		if v == nil {
+			delete(claims, k)
+		} else {
+			claims[k] = v
+		}
+	}
+
+	return claims
+}
+
+func discover(t *testing.T, p *fakeProvider) *Provider {
+	t.Helper()
+
+	provider, err := Discover(context.Background(), config.OIDC{
+		Issuer: p.URL + "/", ClientID: "pr-reviewer", ClientSecret: "s3cret", RedirectURL: "https://reviewer.example.com/auth/callback",
+	}, http.DefaultClient, WithClock(func() time.Time { return testNow }))
+	require.NoError(t, err)
+
+	return provider
+}
+
+func TestDiscover(t *testing.T) {
+	p := newFakeProvider(t)
+
+	_, err := Discover(context.Background(), config.OIDC{Issuer: p.URL + "/other", ClientID: "pr-reviewer"}, http.DefaultClient)
+	assert.Error(t, err, "the metadata must not be read from another issuer")
+
+	provider := discover(t, p)
+
+	authURL, err := url.Parse(provider.AuthCodeURL("state-1", "nonce-1", "verifier"))
+	require.NoError(t, err)
+
+	challenge := sha256.Sum256([]byte("verifier"))
+	query := authURL.Query()
+	assert.Equal(t, p.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "pr-reviewer", query.Get("client_id"))
+	assert.Equal(t, "https://reviewer.example.com/auth/callback", query.Get("redirect_uri"))
+	assert.Equal(t, "openid", query.Get("scope"))
+	assert.Equal(t, "state-1", query.Get("state"))
+	assert.Equal(t, "nonce-1", query.Get("nonce"))
+	assert.Equal(t, b64(challenge[:]), query.Get("code_challenge"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	ctx := context.Background()
+	p := newFakeProvider(t)
+	p.idToken = "id-token"
+	provider := discover(t, p)
+
+	token, err := provider.Exchange(ctx, "good-code", "verifier")
+	require.NoError(t, err)
+	assert.Equal(t, "id-token", token)
+
+	_, err = provider.Exchange(ctx, "stolen-code", "verifier")
+	assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+
+	_, err = provider.Exchange(ctx, "good-code", "guessed-verifier")
+	assert.ErrorIs(t, err, apperrors.ErrInvalidToken, "the code is bound to the verifier")
+}
+
+func TestProvider_Verify(t *testing.T) {
+	ctx := context.Background()
+	p := newFakeProvider(t)
+	provider := discover(t, p)
+
+	t.Run("RS256", func(t *testing.T) {
+		claims, err := provider.Verify(ctx, p.sign(t, "RS256", "rsa-1", p.claims(nil)), "nonce-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "subject-1", claims.Subject)
+		assert.Equal(t, testNow.Add(time.Hour), claims.ExpiresAt)
+	})
+
+	t.Run("ES256 with an audience list and no nonce to check", func(t *testing.T) {
+		token := p.sign(t, "ES256", "ec-1", p.claims(map[string]any{"aud": []string{"other", "pr-reviewer"}}))
+
+		claims, err := provider.Verify(ctx, token, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, "subject-1", claims.Subject)
+	})
+
+	testCases := []struct {
+		name  string
+		token func() string
+	}{
+		{name: "Malformed", token: func() string { return "not-a-token" }},
+		{name: "Unsigned", token: func() string { return p.sign(t, "none", "rsa-1", p.claims(nil)) }},
+		{name: "HMAC", token: func() string { return p.sign(t, "HS256", "rsa-1", p.claims(nil)) }},
+		{name: "Algorithm of another key", token: func() string { return p.sign(t, "RS256", "ec-1", p.claims(nil)) }},
+		{name: "Encryption key", token: func() string { return p.sign(t, "RS256", "enc-1", p.claims(nil)) }},
+		{name: "Unknown key", token: func() string { return p.sign(t, "RS256", "rsa-2", p.claims(nil)) }},
+		{name: "Tampered claims", token: func() string {
+			signed := p.sign(t, "RS256", "rsa-1", p.claims(nil))
+			forged := p.sign(t, "RS256", "rsa-1", p.claims(map[string]any{"sub": "admin"}))
+
+			return forged[:len(forged)-len(signature(forged))] + signature(signed)
+		}},
+		{name: "Another issuer", token: func() string {
+			return p.sign(t, "RS256", "rsa-1", p.claims(map[string]any{"iss": "https://evil.example.com"}))
+		}},
+		{name: "Another client", token: func() string { return p.sign(t, "RS256", "rsa-1", p.claims(map[string]any{"aud": "other"})) }},
+		{name: "No subject", token: func() string { return p.sign(t, "RS256", "rsa-1", p.claims(map[string]any{"sub": nil})) }},
+		{name: "Expired", token: func() string {
+			return p.sign(t, "RS256", "rsa-1", p.claims(map[string]any{"exp": testNow.Add(-2 * time.Minute).Unix()}))
+		}},
+		{name: "Not valid yet", token: func() string {
+			return p.sign(t, "RS256", "rsa-1", p.claims(map[string]any{"nbf": testNow.Add(2 * time.Minute).Unix()}))
+		}},
+		{name: "Another nonce", token: func() string { return p.sign(t, "RS256", "rsa-1", p.claims(map[string]any{"nonce": "replayed"})) }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := provider.Verify(ctx, tc.token(), "nonce-1")
+
+			assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+		})
+	}
+
+	t.Run("Unknown keys are looked up at most once a minute", func(t *testing.T) {
+		provider := discover(t, p)
+		before := p.keyFetches.Load()
+
+		for range 3 {
+			_, err := provider.Verify(ctx, p.sign(t, "RS256", "rotated", p.claims(nil)), "")
+			assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+		}
+
+		assert.Equal(t, before+1, p.keyFetches.Load())
+	})
+}
+
+func signature(token string) string {
+	return token[strings.LastIndex(token, ".")+1:]
+}
@@ -0,0 +1,260 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+)
+
+// clockSkew is how far the clocks of the service and the provider may drift apart.
+const clockSkew = time.Minute
+
+// Claims are the claims of a verified ID token the service uses.
+type Claims struct {
+	// Subject identifies the user at the provider; it is mapped to a user of the service.
+	Subject   string
+	ExpiresAt time.Time
+}
+
+// tokenHeader is the JOSE header of an ID token.
+type tokenHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// tokenClaims are the registered claims of an ID token. The audience is a string or an array of strings.
+type tokenClaims struct {
+	Issuer    string          `json:"iss"`
+	Subject   string          `json:"sub"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt int64           `json:"exp"`
+	NotBefore int64           `json:"nbf"`
+	Nonce     string          `json:"nonce"`
+}
+
+// Verify checks the signature of an ID token against the keys of the provider, and that the token was issued
+// by the provider to this client and has not expired. A non-empty nonce must match the nonce of the token,
+// as it does right after a sign-in; the tokens presented to the API later are checked without it.
+// Returns apperrors.ErrInvalidToken if the token is not valid.
+func (p *Provider) Verify(ctx context.Context, rawIDToken, nonce string) (*Claims, error) {
+	const op = "internal.oidc.Verify"
+
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%s: %w: malformed token", op, apperrors.ErrInvalidToken)
+	}
+
+	var header tokenHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%s: %w: malformed header", op, apperrors.ErrInvalidToken)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: malformed signature", op, apperrors.ErrInvalidToken)
+	}
+
+	key, err := p.keys.key(ctx, header.Kid)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", op, apperrors.ErrInvalidToken, err)
+	}
+
+	var claims tokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%s: %w: malformed claims", op, apperrors.ErrInvalidToken)
+	}
+
+	if err := p.checkClaims(&claims, nonce); err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", op, apperrors.ErrInvalidToken, err)
+	}
+
+	return &Claims{Subject: claims.Subject, ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC()}, nil
+}
+
+func (p *Provider) checkClaims(claims *tokenClaims, nonce string) error {
+	if claims.Issuer != p.issuer {
+		return fmt.Errorf("issued by %q", claims.Issuer)
+	}
+
+	var audience []string
+	if err := json.Unmarshal(claims.Audience, &audience); err != nil {
+		var single string
+		if err := json.Unmarshal(claims.Audience, &single); err != nil {
+			return errors.New("malformed audience")
+		}
+
+		audience = []string{single}
+	}
+
+	if !slices.Contains(audience, p.clientID) {
+		return errors.New("issued to another client")
+	}
+
+	if claims.Subject == "" {
+		return errors.New("no subject")
+	}
+
+	now := p.now()
+
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(clockSkew)) {
+		return errors.New("expired")
+	}
+
+	if claims.NotBefore != 0 && now.Before(time.Unix(claims.NotBefore, 0).Add(-clockSkew)) {
+		return errors.New("not valid yet")
+	}
+
+	if nonce != "" && claims.Nonce != nonce {
+		return errors.New("nonce mismatch")
+	}
+
+	return nil
+}
+
+func decodeSegment(segment string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, v)
+}
+
+// verifySignature checks the signature of the signing input of a token made with alg.
+func verifySignature(alg string, key any, signingInput string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signingInput))
+
+	switch alg {
+	case "RS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key does not match algorithm %s", alg)
+		}
+
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature); err != nil {
+			return errors.New("bad signature")
+		}
+	case "ES256":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return fmt.Errorf("key does not match algorithm %s", alg)
+		}
+
+		r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(pub, digest[:], r, s) {
+			return errors.New("bad signature")
+		}
+	default:
+		// "none" and the HMAC algorithms in particular: the client secret must not be usable to forge tokens.
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+
+	return nil
+}
+
+// jwk is a public key of the key set of the provider.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetchKeys reads the signing keys of the provider by key ID. The keys of other types and uses are skipped.
+func fetchKeys(ctx context.Context, client HTTPDoer, uri string) (map[string]any, error) {
+	const op = "internal.oidc.fetchKeys"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build request: %w", op, err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := getJSON(client, req, &set); err != nil {
+		return nil, fmt.Errorf("%s: failed to read keys: %w", op, err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+
+	return keys, nil
+}
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("bad exponent")
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("bad coordinates")
+		}
+
+		// Rejects the points that are not on the curve.
+		return ecdsa.ParseUncompressedPublicKey(elliptic.P256(), append(append([]byte{4}, x...), y...))
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
@@ -0,0 +1,69 @@
+// Package poller runs the polling loop of the background workers that drain a queue, such as
+// the creator, the deactivator, the dispatcher and the relay. Each of them provides the function
+// processing one batch; the poller decides when to call it.
+package poller
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ProcessFunc claims a batch of at most the poller's batch size, processes it and returns its size.
+type ProcessFunc func(ctx context.Context) int
+
+// Poller drains a queue once per interval.
+type Poller struct {
+	interval time.Duration
+	batch    int
+	process  ProcessFunc
+}
+
+func New(interval time.Duration, batch int, process ProcessFunc) *Poller {
+	return &Poller{
+		interval: interval,
+		batch:    batch,
+		process:  process,
+	}
+}
+
+// Run drains the queue once per interval until ctx is cancelled.
+func (p *Poller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.drain(ctx)
+		}
+	}
+}
+
+// drain processes batches until one comes back short.
+// A full batch means more work is likely waiting, so the next one is claimed right away.
+func (p *Poller) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		if p.process(ctx) < p.batch {
+			return
+		}
+	}
+}
+
+// Each calls fn for every item concurrently and waits for all the calls to return.
+func Each[T any](items []T, fn func(item T)) {
+	var wg sync.WaitGroup
+
+	for _, item := range items {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			fn(item)
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,69 @@
+package poller
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoller_DrainsUntilBatchComesBackShort(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		queued  = 7
+		batches []int
+	)
+
+	p := New(time.Millisecond, 3, func(context.Context) int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		n := min(3, queued)
+		queued -= n
+		batches = append(batches, n)
+
+		return n
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return queued == 0
+	}, time.Second, time.Millisecond)
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("poller did not stop after cancellation")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// The full batches are followed right away by the next claim, the short one ends the drain.
+	assert.Equal(t, []int{3, 3, 1}, batches[:3])
+}
+
+func TestEach(t *testing.T) {
+	var sum atomic.Int64
+
+	Each([]int64{1, 2, 3, 4}, func(item int64) {
+		sum.Add(item)
+	})
+
+	assert.Equal(t, int64(10), sum.Load())
+}
@@ -0,0 +1,150 @@
+package publisher
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+// KafkaSink is the name of KafkaPublisher as an event sink.
+const KafkaSink = "kafka"
+
+// messageWriter is the part of kafka.Writer KafkaPublisher uses.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// KafkaPublisher produces every event as a Kafka message keyed by the PR, with the JSON payload
+// of the webhooks as the value and the event type and ID in the "event" and "event_id" headers.
+type KafkaPublisher struct {
+	writer        messageWriter
+	topic         string
+	topicPerEvent bool
+}
+
+// NewKafkaPublisher creates a publisher producing to the brokers of cfg. The connections are made lazily,
+// so an unavailable broker fails the publications rather than the start.
+func NewKafkaPublisher(cfg config.Kafka) (*KafkaPublisher, error) {
+	transport := &kafka.Transport{}
+
+	if cfg.TLS.Enabled {
+		tlsConfig, err := newTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure kafka tls: %w", err)
+		}
+
+		transport.TLS = tlsConfig
+	}
+
+	if cfg.SASLMechanism != "" {
+		mechanism, err := newSASLMechanism(cfg.SASLMechanism, cfg.SASLUsername, cfg.SASLPassword)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure kafka sasl: %w", err)
+		}
+
+		transport.SASL = mechanism
+	}
+
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(cfg.Brokers...),
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		WriteTimeout: cfg.WriteTimeout,
+		// The relay publishes one event at a time, so waiting for a batch to fill up only delays it.
+		BatchTimeout: time.Millisecond,
+		Transport:    transport,
+	}
+
+	return &KafkaPublisher{writer: writer, topic: cfg.Topic, topicPerEvent: cfg.TopicPerEvent}, nil
+}
+
+func (p *KafkaPublisher) Name() string {
+	return KafkaSink
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	if err := p.writer.WriteMessages(ctx, p.message(event)); err != nil {
+		return fmt.Errorf("failed to produce kafka message: %w", err)
+	}
+
+	return nil
+}
+
+// Close flushes the pending messages and closes the connections to the brokers.
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
+
+func (p *KafkaPublisher) message(event domain.OutboxEvent) kafka.Message {
+	topic := p.topic
+	if p.topicPerEvent {
+		topic += "." + string(event.Type)
+	}
+
+	return kafka.Message{
+		Topic: topic,
+		Key:   []byte(event.PullRequestID),
+		Value: event.Payload,
+		Headers: []kafka.Header{
+			{Key: "event", Value: []byte(event.Type)},
+			{Key: "event_id", Value: []byte(strconv.FormatInt(event.ID, 10))},
+		},
+		Time: event.OccurredAt,
+	}
+}
+
+// newTLSConfig builds the TLS config of the connections to a broker from cfg.
+func newTLSConfig(cfg config.BrokerTLS) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca file: %w", err)
+		}
+
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, errors.New("ca file contains no certificates")
+		}
+
+		tlsConfig.RootCAs = roots
+	}
+
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func newSASLMechanism(name, username, password string) (sasl.Mechanism, error) {
+	switch name {
+	case config.SASLPlain:
+		return plain.Mechanism{Username: username, Password: password}, nil
+	case config.SASLSCRAMSHA256:
+		return scram.Mechanism(scram.SHA256, username, password)
+	case config.SASLSCRAMSHA512:
+		return scram.Mechanism(scram.SHA512, username, password)
+	default:
+		return nil, fmt.Errorf("unknown sasl mechanism %q", name)
+	}
+}
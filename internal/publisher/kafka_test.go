@@ -0,0 +1,96 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWriter records the produced messages instead of sending them to the brokers.
+type fakeWriter struct {
+	messages []kafka.Message
+	err      error
+}
+
+func (w *fakeWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	w.messages = append(w.messages, msgs...)
+	return w.err
+}
+
+func (w *fakeWriter) Close() error {
+	return nil
+}
+
+func TestKafkaPublisher_Publish(t *testing.T) {
+	occurredAt := time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC)
+	event := domain.OutboxEvent{
+		ID:            42,
+		Type:          domain.WebhookPRMerged,
+		PullRequestID: "pr-1",
+		Payload:       []byte(`{"event":"pr.merged"}`),
+		OccurredAt:    occurredAt,
+	}
+
+	testCases := []struct {
+		name          string
+		topicPerEvent bool
+		expectedTopic string
+	}{
+		{name: "Single topic", expectedTopic: "pr-reviewer.events"},
+		{name: "Topic per event", topicPerEvent: true, expectedTopic: "pr-reviewer.events.pr.merged"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			writer := &fakeWriter{}
+			p := &KafkaPublisher{writer: writer, topic: "pr-reviewer.events", topicPerEvent: tc.topicPerEvent}
+
+			require.NoError(t, p.Publish(context.Background(), event))
+			require.Len(t, writer.messages, 1)
+
+			message := writer.messages[0]
+			assert.Equal(t, tc.expectedTopic, message.Topic)
+			assert.Equal(t, "pr-1", string(message.Key), "the events of a PR share a partition")
+			assert.JSONEq(t, `{"event":"pr.merged"}`, string(message.Value))
+			assert.Equal(t, []kafka.Header{{Key: "event", Value: []byte("pr.merged")}, {Key: "event_id", Value: []byte("42")}}, message.Headers)
+			assert.Equal(t, occurredAt, message.Time)
+		})
+	}
+
+	t.Run("Broker error fails the publication", func(t *testing.T) {
+		errBroker := errors.New("leader not available")
+		p := &KafkaPublisher{writer: &fakeWriter{err: errBroker}, topic: "pr-reviewer.events"}
+
+		assert.ErrorIs(t, p.Publish(context.Background(), event), errBroker)
+	})
+}
+
+func TestNewKafkaPublisher(t *testing.T) {
+	cfg := config.Kafka{Brokers: []string{"localhost:9092"}, Topic: "pr-reviewer.events", WriteTimeout: time.Second}
+
+	for _, mechanism := range []string{config.SASLPlain, config.SASLSCRAMSHA256, config.SASLSCRAMSHA512} {
+		t.Run("SASL "+mechanism, func(t *testing.T) {
+			c := cfg
+			c.SASLMechanism, c.SASLUsername, c.SASLPassword = mechanism, "svc", "secret"
+
+			p, err := NewKafkaPublisher(c)
+			require.NoError(t, err)
+			assert.Equal(t, KafkaSink, p.Name())
+		})
+	}
+
+	t.Run("TLS with a missing CA file", func(t *testing.T) {
+		c := cfg
+		c.TLS = config.BrokerTLS{Enabled: true, CAFile: "testdata/missing.pem"}
+
+		_, err := NewKafkaPublisher(c)
+		assert.ErrorContains(t, err, "ca file")
+	})
+}
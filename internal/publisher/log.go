@@ -0,0 +1,40 @@
+// Package publisher provides implementations of service.EventPublisher.
+package publisher
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+// LogSink is the name of LogPublisher as an event sink.
+const LogSink = "log"
+
+// LogPublisher writes every event to the log instead of publishing it.
+// It stands in for a message broker in development and while a broker is being set up.
+type LogPublisher struct {
+	log *slog.Logger
+}
+
+func NewLogPublisher(log *slog.Logger) *LogPublisher {
+	return &LogPublisher{
+		log: log.With(slog.String("component", "publisher")),
+	}
+}
+
+func (p *LogPublisher) Name() string {
+	return LogSink
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	p.log.InfoContext(ctx, "event published",
+		slog.Int64("event_id", event.ID),
+		slog.String("event", string(event.Type)),
+		slog.String("pr_id", event.PullRequestID),
+		slog.Time("occurred_at", event.OccurredAt),
+		slog.String("payload", string(event.Payload)),
+	)
+
+	return nil
+}
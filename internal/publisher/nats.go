@@ -0,0 +1,117 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// NATSSink is the name of NATSPublisher as an event sink.
+const NATSSink = "nats"
+
+// streamPublisher is the part of jetstream.JetStream NATSPublisher uses.
+type streamPublisher interface {
+	PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error)
+}
+
+// NATSPublisher publishes every event to NATS JetStream with the JSON payload of the webhooks as the data
+// and the event type and the PR in the "event" and "pull_request_id" headers. The event ID is the message ID, so that the stream drops
+// an event published again within its duplicate window.
+type NATSPublisher struct {
+	conn    *nats.Conn
+	js      streamPublisher
+	subject string
+	timeout time.Duration
+}
+
+// NewNATSPublisher connects to the servers of cfg. An unavailable server does not fail the start: the connection
+// is retried in the background for as long as the service runs, and the publications fail until it is made.
+func NewNATSPublisher(cfg config.NATS, log *slog.Logger) (*NATSPublisher, error) {
+	log = log.With(slog.String("component", "publisher"), slog.String("sink", NATSSink))
+
+	opts := []nats.Option{
+		nats.Name("pr-reviewer-service"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(cfg.ReconnectWait),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				log.Warn("disconnected from nats", sl.Err(err))
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			log.Info("reconnected to nats", slog.String("url", conn.ConnectedUrlRedacted()))
+		}),
+	}
+
+	if cfg.TLS.Enabled {
+		tlsConfig, err := newTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure nats tls: %w", err)
+		}
+
+		opts = append(opts, nats.Secure(tlsConfig))
+	}
+
+	switch {
+	case cfg.User != "":
+		opts = append(opts, nats.UserInfo(cfg.User, cfg.Password))
+	case cfg.CredsFile != "":
+		opts = append(opts, nats.UserCredentials(cfg.CredsFile))
+	}
+
+	conn, err := nats.Connect(cfg.URL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create jetstream context: %w", err)
+	}
+
+	return &NATSPublisher{conn: conn, js: js, subject: cfg.Subject, timeout: cfg.PublishTimeout}, nil
+}
+
+func (p *NATSPublisher) Name() string {
+	return NATSSink
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	// The ack confirms that the stream has stored the event; a duplicate ack means it already had.
+	if _, err := p.js.PublishMsg(ctx, p.message(event), jetstream.WithMsgID(strconv.FormatInt(event.ID, 10))); err != nil {
+		return fmt.Errorf("failed to publish to jetstream: %w", err)
+	}
+
+	return nil
+}
+
+// Close publishes the buffered messages and closes the connection.
+func (p *NATSPublisher) Close() error {
+	if p.conn == nil {
+		return nil
+	}
+
+	return p.conn.Drain()
+}
+
+func (p *NATSPublisher) message(event domain.OutboxEvent) *nats.Msg {
+	msg := nats.NewMsg(p.subject + "." + string(event.Type))
+	msg.Data = event.Payload
+	msg.Header.Set("event", string(event.Type))
+	msg.Header.Set("pull_request_id", event.PullRequestID)
+
+	return msg
+}
@@ -0,0 +1,77 @@
+package publisher
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStream records the published messages instead of sending them to the server.
+type fakeStream struct {
+	messages []*nats.Msg
+	opts     int
+	deadline bool
+	err      error
+}
+
+func (s *fakeStream) PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	s.messages = append(s.messages, msg)
+	s.opts = len(opts)
+	_, s.deadline = ctx.Deadline()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &jetstream.PubAck{Stream: "PR_EVENTS", Sequence: uint64(len(s.messages))}, nil
+}
+
+func TestNATSPublisher_Publish(t *testing.T) {
+	event := domain.OutboxEvent{
+		ID:            42,
+		Type:          domain.WebhookReviewerReassigned,
+		PullRequestID: "pr-1",
+		Payload:       []byte(`{"event":"reviewer.reassigned"}`),
+	}
+
+	stream := &fakeStream{}
+	p := &NATSPublisher{js: stream, subject: "pr-reviewer.events", timeout: time.Second}
+
+	require.NoError(t, p.Publish(context.Background(), event))
+	require.Len(t, stream.messages, 1)
+
+	msg := stream.messages[0]
+	assert.Equal(t, "pr-reviewer.events.reviewer.reassigned", msg.Subject)
+	assert.JSONEq(t, `{"event":"reviewer.reassigned"}`, string(msg.Data))
+	assert.Equal(t, "reviewer.reassigned", msg.Header.Get("event"))
+	assert.Equal(t, "pr-1", msg.Header.Get("pull_request_id"))
+	assert.Equal(t, 1, stream.opts, "the event ID is passed as the message ID")
+	assert.True(t, stream.deadline, "the wait for the ack is bounded")
+
+	t.Run("Missing ack fails the publication", func(t *testing.T) {
+		p := &NATSPublisher{js: &fakeStream{err: jetstream.ErrNoStreamResponse}, subject: "pr-reviewer.events", timeout: time.Second}
+
+		assert.ErrorIs(t, p.Publish(context.Background(), event), jetstream.ErrNoStreamResponse)
+	})
+}
+
+func TestNewNATSPublisher_UnavailableServer(t *testing.T) {
+	cfg := config.NATS{URL: "nats://127.0.0.1:1", Subject: "pr-reviewer.events", PublishTimeout: 50 * time.Millisecond, ReconnectWait: time.Second}
+
+	p, err := NewNATSPublisher(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err, "the connection is retried in the background")
+
+	defer p.Close()
+
+	event := domain.OutboxEvent{ID: 1, Type: domain.WebhookPRCreated, Payload: []byte(`{}`)}
+	assert.Error(t, p.Publish(context.Background(), event))
+}
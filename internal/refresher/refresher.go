@@ -0,0 +1,71 @@
+// package refresher periodically recomputes the precomputed all-time review statistics that GET /stats reads,
+// so that the statistics do not join every pull request on each request. Instances that find a refresh
+// already running elsewhere skip their turn.
+package refresher
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// StatsRefresher is the part of service.PRCommandService the refresher drives.
+type StatsRefresher interface {
+	RefreshStats(ctx context.Context) (bool, error)
+}
+
+// Refresher refreshes the precomputed statistics periodically.
+type Refresher struct {
+	log      *slog.Logger
+	prs      StatsRefresher
+	interval time.Duration
+}
+
+func New(log *slog.Logger, prs StatsRefresher, interval time.Duration) *Refresher {
+	return &Refresher{
+		log:      log.With(slog.String("component", "refresher")),
+		prs:      prs,
+		interval: interval,
+	}
+}
+
+// Run refreshes the statistics right away, as they may be as old as the previous run of the service,
+// and then once per interval until ctx is cancelled.
+func (r *Refresher) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Refresher) refresh(ctx context.Context) {
+	start := time.Now()
+
+	refreshed, err := r.prs.RefreshStats(ctx)
+	if err != nil {
+		// The previous statistics are served until a refresh succeeds.
+		if ctx.Err() == nil {
+			r.log.Error("failed to refresh stats", sl.Err(err))
+		}
+
+		return
+	}
+
+	if !refreshed {
+		r.log.Debug("stats are being refreshed by another instance")
+
+		return
+	}
+
+	r.log.Debug("refreshed stats", slog.Duration("took", time.Since(start)))
+}
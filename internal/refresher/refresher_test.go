@@ -0,0 +1,65 @@
+package refresher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRefresher struct {
+	calls atomic.Int32
+}
+
+func (f *fakeRefresher) RefreshStats(_ context.Context) (bool, error) {
+	// The refreshes cycle through being done, being skipped and failing.
+	switch f.calls.Add(1) % 3 {
+	case 0:
+		return false, errors.New("db is down")
+	case 1:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func TestRefresher_RunUntilCancelled(t *testing.T) {
+	prs := &fakeRefresher{}
+	r := New(slog.New(slog.NewTextHandler(io.Discard, nil)), prs, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+
+	// Failed and skipped refreshes do not stop the refresher.
+	assert.Eventually(t, func() bool { return prs.calls.Load() >= 4 }, time.Second, time.Millisecond)
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("refresher did not stop after cancellation")
+	}
+}
+
+func TestRefresher_RefreshesOnStart(t *testing.T) {
+	prs := &fakeRefresher{}
+	r := New(slog.New(slog.NewTextHandler(io.Discard, nil)), prs, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go r.Run(ctx)
+
+	assert.Eventually(t, func() bool { return prs.calls.Load() == 1 }, time.Second, time.Millisecond)
+}
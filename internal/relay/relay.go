@@ -0,0 +1,69 @@
+// Package relay publishes the PR events written to the outbox to the event sinks, see service.OutboxService.
+// The events are published one at a time in the order they were written, so that a consumer sees the events
+// of a pull request in order unless a publication fails and is retried.
+package relay
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/poller"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// EventProcessor is the part of service.OutboxService the relay drives.
+type EventProcessor interface {
+	ClaimOutboxEvents(ctx context.Context, limit int) ([]domain.OutboxEvent, error)
+	PublishOutboxEvent(ctx context.Context, event domain.OutboxEvent) error
+}
+
+// Relay polls the outbox and publishes the claimed events.
+type Relay struct {
+	log    *slog.Logger
+	events EventProcessor
+	batch  int
+	poller *poller.Poller
+}
+
+func New(log *slog.Logger, events EventProcessor, interval time.Duration, batch int) *Relay {
+	r := &Relay{
+		log:    log.With(slog.String("component", "relay")),
+		events: events,
+		batch:  batch,
+	}
+	r.poller = poller.New(interval, batch, r.process)
+
+	return r
+}
+
+// Run drains the outbox once per interval until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	r.poller.Run(ctx)
+}
+
+// process claims a batch of events, publishes them in order and returns its size.
+func (r *Relay) process(ctx context.Context) int {
+	claimed, err := r.events.ClaimOutboxEvents(ctx, r.batch)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.log.Error("failed to claim outbox events", sl.Err(err))
+		}
+
+		return 0
+	}
+
+	for _, event := range claimed {
+		if ctx.Err() != nil {
+			// The rest of the batch is claimed again once its lease expires.
+			return len(claimed)
+		}
+
+		if err := r.events.PublishOutboxEvent(ctx, event); err != nil && ctx.Err() == nil {
+			r.log.Error("failed to publish outbox event", slog.Int64("event_id", event.ID), sl.Err(err))
+		}
+	}
+
+	return len(claimed)
+}
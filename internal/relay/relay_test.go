@@ -0,0 +1,91 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeOutbox hands out the due events in batches and records the ones published.
+type fakeOutbox struct {
+	mu        sync.Mutex
+	queued    []domain.OutboxEvent
+	published []int64
+	limits    []int
+}
+
+func (o *fakeOutbox) ClaimOutboxEvents(_ context.Context, limit int) ([]domain.OutboxEvent, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.limits = append(o.limits, limit)
+
+	n := min(limit, len(o.queued))
+	claimed := o.queued[:n]
+	o.queued = o.queued[n:]
+
+	return claimed, nil
+}
+
+func (o *fakeOutbox) PublishOutboxEvent(_ context.Context, event domain.OutboxEvent) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.published = append(o.published, event.ID)
+
+	if event.ID%2 == 0 {
+		return errors.New("sink is down")
+	}
+
+	return nil
+}
+
+func (o *fakeOutbox) publishedCount() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return len(o.published)
+}
+
+func TestRelay_PublishesEventsInOrder(t *testing.T) {
+	outbox := &fakeOutbox{}
+	for id := int64(1); id <= 10; id++ {
+		outbox.queued = append(outbox.queued, domain.OutboxEvent{ID: id})
+	}
+
+	r := New(slog.New(slog.NewTextHandler(io.Discard, nil)), outbox, time.Millisecond, 4)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+
+	// Publication errors are logged and do not stop the relay.
+	assert.Eventually(t, func() bool { return outbox.publishedCount() == 10 }, time.Second, time.Millisecond)
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("relay did not stop after cancellation")
+	}
+
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, outbox.published)
+	for _, limit := range outbox.limits {
+		assert.Equal(t, 4, limit)
+	}
+}
@@ -0,0 +1,135 @@
+// Package cached puts a Redis cache in front of the team and user reads that most requests repeat,
+// such as the team of an author, whether a user is active or a team with its members. Its repositories
+// wrap other implementations of the repository interfaces and pass everything else through.
+//
+// Invalidation is by generation: every cached key embeds the current generation, and every write through
+// the repositories that may change a cached value increments it, so that all cached teams and users are
+// dropped at once. Writes are rare next to the reads, and a single counter cannot miss a value affected by
+// a write, e.g. the other teams of a user updated by CreateTeamWithUsers. The writes made in a transaction
+// move the generation once it is committed: a read racing the transaction may cache the data as of before
+// it, but under the generation the commit leaves behind.
+//
+// The cache never fails a read: reads within a transaction bypass it, so that they see the writes of the
+// transaction, and a read whose Redis command fails goes to the wrapped repository.
+package cached
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	keyPrefix = "pr-reviewer:"
+	// generationKey holds the current generation; a missing key is generation 0.
+	generationKey = keyPrefix + "generation"
+)
+
+// Names of the cached values, which label the cache_requests_total metric and prefix their keys.
+const (
+	cacheTeam     = "team"
+	cacheTeamID   = "team_id"
+	cacheUserTeam = "user_team"
+	cacheActive   = "user_active"
+	cacheRole     = "user_role"
+)
+
+// Cache is the Redis cache shared by the repositories. Without a client it is a no-op and every read
+// goes to the wrapped repository.
+type Cache struct {
+	client  redis.UniversalClient
+	log     *slog.Logger
+	teamTTL time.Duration
+	userTTL time.Duration
+}
+
+// New creates a cache stored in client; a nil client disables it. Teams with their members are cached
+// for teamTTL and the data of single users for userTTL.
+func New(client redis.UniversalClient, log *slog.Logger, teamTTL, userTTL time.Duration) *Cache {
+	return &Cache{
+		client:  client,
+		log:     log.With(slog.String("component", "cache")),
+		teamTTL: teamTTL,
+		userTTL: userTTL,
+	}
+}
+
+// invalidate drops every cached value by moving to the next generation, after the commit of the
+// transaction carried by ctx if there is one.
+func (c *Cache) invalidate(ctx context.Context) {
+	if c.client == nil {
+		return
+	}
+
+	txctx.AfterCommit(ctx, func() {
+		if err := c.client.Incr(ctx, generationKey).Err(); err != nil {
+			// The cached values are dropped by their TTLs instead.
+			c.log.Error("failed to invalidate cache", sl.Err(err))
+		}
+	})
+}
+
+// read returns the value cached under the name and id, or loads it with load and caches it for ttl.
+// Errors of load are not cached. inTx tells that load reads in the transaction of ctx, which bypasses the cache.
+func read[T any](ctx context.Context, c *Cache, inTx bool, name, id string, ttl time.Duration, load func() (T, error)) (T, error) {
+	if c.client == nil || inTx {
+		return load()
+	}
+
+	generation, err := c.client.Get(ctx, generationKey).Result()
+	if errors.Is(err, redis.Nil) {
+		generation, err = "0", nil
+	}
+
+	if err != nil {
+		c.failed(name, err)
+		return load()
+	}
+
+	key := keyPrefix + generation + ":" + name + ":" + id
+
+	data, err := c.client.Get(ctx, key).Bytes()
+	switch {
+	case err == nil:
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			cacheRequests.WithLabelValues(name, resultHit).Inc()
+			return value, nil
+		}
+
+		// A value that does not decode, e.g. one cached by an older version, is replaced.
+	case !errors.Is(err, redis.Nil):
+		c.failed(name, err)
+		return load()
+	}
+
+	cacheRequests.WithLabelValues(name, resultMiss).Inc()
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	data, err = json.Marshal(value)
+	if err != nil {
+		c.log.Error("failed to encode cached value", slog.String("cache", name), sl.Err(err))
+		return value, nil
+	}
+
+	if err := c.client.Set(ctx, key, data, ttl).Err(); err != nil {
+		c.log.Warn("failed to cache value", slog.String("cache", name), sl.Err(err))
+	}
+
+	return value, nil
+}
+
+func (c *Cache) failed(name string, err error) {
+	cacheRequests.WithLabelValues(name, resultError).Inc()
+	c.log.Warn("cache read failed, reading from the database", slog.String("cache", name), sl.Err(err))
+}
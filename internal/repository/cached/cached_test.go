@@ -0,0 +1,293 @@
+package cached
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/memory"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRepos struct {
+	store  *memory.Store
+	teams  *TeamRepository
+	users  *UserRepository
+	userPR *UserPRRepository
+}
+
+// newTestRepos wraps a store holding one team in repositories cached in client.
+func newTestRepos(t *testing.T, client redis.UniversalClient) testRepos {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := memory.NewStore(log)
+	cache := New(client, log, time.Minute, time.Minute)
+
+	repos := testRepos{
+		store:  store,
+		teams:  NewTeamRepository(store, cache),
+		users:  NewUserRepository(store, cache),
+		userPR: NewUserPRRepository(store, cache),
+	}
+
+	_, err := repos.teams.CreateTeamWithUsers(context.Background(), api.Team{
+		TeamName: "backend",
+		Members: []api.TeamMember{
+			{UserId: "u1", Username: "Alice", IsActive: true},
+			{UserId: "u2", Username: "Bob", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	return repos
+}
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, redis.UniversalClient) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return server, client
+}
+
+func TestCache_ServesReadsUntilInvalidated(t *testing.T) {
+	_, client := newTestRedis(t)
+	repos := newTestRepos(t, client)
+	ctx := context.Background()
+
+	active, err := repos.userPR.IsUserActive(ctx, "u1")
+	require.NoError(t, err)
+	assert.True(t, active)
+
+	team, err := repos.teams.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+	require.Len(t, team.Members, 2)
+
+	// A change made past the cache is not seen until the cache is invalidated.
+	_, _, err = repos.store.SetIsActive(ctx, "u1", false)
+	require.NoError(t, err)
+
+	active, err = repos.userPR.IsUserActive(ctx, "u1")
+	require.NoError(t, err)
+	assert.True(t, active)
+
+	team, err = repos.teams.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+	assert.True(t, team.Members[0].IsActive)
+
+	_, _, err = repos.users.SetIsActive(ctx, "u2", false)
+	require.NoError(t, err)
+
+	active, err = repos.userPR.IsUserActive(ctx, "u1")
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	team, err = repos.teams.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+	assert.False(t, team.Members[0].IsActive)
+	assert.False(t, team.Members[1].IsActive)
+}
+
+func TestCache_CreateTeamWithUsersInvalidates(t *testing.T) {
+	_, client := newTestRedis(t)
+	repos := newTestRepos(t, client)
+	ctx := context.Background()
+
+	backendID, err := repos.userPR.GetAuthorTeamID(ctx, "u2")
+	require.NoError(t, err)
+
+	team, err := repos.teams.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+	require.Len(t, team.Members, 2)
+
+	// Adding u2 to a new team updates u2 among the members of the team u2 already is in.
+	_, err = repos.teams.CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "frontend",
+		Members:  []api.TeamMember{{UserId: "u2", Username: "Bobby", IsActive: false}},
+	})
+	require.NoError(t, err)
+
+	primaryTeamID, err := repos.userPR.GetAuthorTeamID(ctx, "u2")
+	require.NoError(t, err)
+	assert.Equal(t, backendID, primaryTeamID)
+
+	team, err = repos.teams.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+	require.Len(t, team.Members, 2)
+	assert.Equal(t, "Bobby", team.Members[1].Username)
+	assert.False(t, team.Members[1].IsActive)
+}
+
+func TestCache_RenameTeamInvalidates(t *testing.T) {
+	_, client := newTestRedis(t)
+	repos := newTestRepos(t, client)
+	ctx := context.Background()
+
+	team, err := repos.teams.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+
+	require.NoError(t, repos.teams.RenameTeam(ctx, team.ID, "platform"))
+
+	_, err = repos.teams.GetTeamByName(ctx, "backend")
+	assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+
+	renamed, err := repos.teams.GetTeamByID(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "platform", renamed.Name)
+}
+
+func TestCache_DeleteTeamInvalidates(t *testing.T) {
+	_, client := newTestRedis(t)
+	repos := newTestRepos(t, client)
+	ctx := context.Background()
+
+	team, err := repos.teams.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+
+	_, err = repos.userPR.IsUserActive(ctx, "u1")
+	require.NoError(t, err)
+
+	_, err = repos.teams.DeleteTeam(ctx, team.ID, time.Now())
+	require.NoError(t, err)
+
+	_, err = repos.teams.GetTeamByName(ctx, "backend")
+	assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+
+	_, err = repos.userPR.IsUserActive(ctx, "u1")
+	assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+
+	require.NoError(t, repos.teams.RestoreTeam(ctx, team.ID))
+
+	restored, err := repos.teams.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+	assert.Len(t, restored.Members, 2)
+}
+
+func TestCache_ErrorsAreNotCached(t *testing.T) {
+	_, client := newTestRedis(t)
+	repos := newTestRepos(t, client)
+	ctx := context.Background()
+
+	_, err := repos.teams.GetTeamByName(ctx, "frontend")
+	require.True(t, errors.Is(err, apperrors.ErrNotFound))
+
+	_, err = repos.store.CreateTeamWithUsers(ctx, api.Team{TeamName: "frontend", Members: []api.TeamMember{}})
+	require.NoError(t, err)
+
+	team, err := repos.teams.GetTeamByName(ctx, "frontend")
+	require.NoError(t, err)
+	assert.Equal(t, "frontend", team.Name)
+}
+
+func TestCache_TransactionBypassesCache(t *testing.T) {
+	_, client := newTestRedis(t)
+	repos := newTestRepos(t, client)
+	ctx := context.Background()
+
+	_, err := repos.teams.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+
+	_, _, err = repos.store.SetIsActive(ctx, "u1", false)
+	require.NoError(t, err)
+
+	tx, err := repos.store.DB().Beginx()
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+
+	team, err := repos.teams.GetTeamByName(txctx.With(ctx, tx), "backend")
+	require.NoError(t, err)
+	assert.False(t, team.Members[0].IsActive)
+}
+
+func TestCache_InvalidatesAfterCommit(t *testing.T) {
+	_, client := newTestRedis(t)
+	repos := newTestRepos(t, client)
+	ctx := context.Background()
+
+	mockDB, smock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	smock.ExpectBegin()
+	smock.ExpectCommit()
+
+	manager := txctx.NewManager(sqlx.NewDb(mockDB, "sqlmock"), slog.New(slog.DiscardHandler))
+
+	err = manager.Do(ctx, nil, func(txCtx context.Context) error {
+		_, _, err := repos.users.SetIsActive(txCtx, "u1", false)
+		require.NoError(t, err)
+
+		// The store applies writes at once, so the write is hidden from the other readers until the commit
+		// the way postgres hides it.
+		_, _, err = repos.store.SetIsActive(ctx, "u1", true)
+		require.NoError(t, err)
+
+		// A read racing the transaction caches the user as of before it.
+		active, err := repos.userPR.IsUserActive(ctx, "u1")
+		require.NoError(t, err)
+		assert.True(t, active)
+
+		_, _, err = repos.store.SetIsActive(ctx, "u1", false)
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, smock.ExpectationsWereMet())
+
+	active, err := repos.userPR.IsUserActive(ctx, "u1")
+	require.NoError(t, err)
+	assert.False(t, active, "the value cached before the commit is dropped by it")
+}
+
+func TestCache_Disabled(t *testing.T) {
+	repos := newTestRepos(t, nil)
+	ctx := context.Background()
+
+	active, err := repos.userPR.IsUserActive(ctx, "u1")
+	require.NoError(t, err)
+	assert.True(t, active)
+
+	_, _, err = repos.store.SetIsActive(ctx, "u1", false)
+	require.NoError(t, err)
+
+	active, err = repos.userPR.IsUserActive(ctx, "u1")
+	require.NoError(t, err)
+	assert.False(t, active)
+}
+
+func TestCache_FallsBackWhenRedisIsDown(t *testing.T) {
+	server, client := newTestRedis(t)
+	repos := newTestRepos(t, client)
+	ctx := context.Background()
+
+	_, err := repos.userPR.IsUserActive(ctx, "u1")
+	require.NoError(t, err)
+
+	server.Close()
+
+	_, _, err = repos.users.SetIsActive(ctx, "u1", false)
+	require.NoError(t, err)
+
+	active, err := repos.userPR.IsUserActive(ctx, "u1")
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	role, err := repos.users.GetUserRole(ctx, "u1")
+	require.NoError(t, err)
+	assert.NotEmpty(t, role)
+}
@@ -0,0 +1,15 @@
+package cached
+
+import (
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Values of the result label of the cache_requests_total metric.
+const (
+	resultHit   = "hit"
+	resultMiss  = "miss"
+	resultError = "error"
+)
+
+var cacheRequests = promauto.NewCounterVec(metrics.CacheRequests.CounterOpts(), metrics.CacheRequests.Labels)
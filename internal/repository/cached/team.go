@@ -0,0 +1,81 @@
+package cached
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// TeamRepository caches the teams with their members read through the wrapped repository.
+type TeamRepository struct {
+	repository.TeamRepository
+	cache *Cache
+}
+
+func NewTeamRepository(next repository.TeamRepository, cache *Cache) *TeamRepository {
+	return &TeamRepository{TeamRepository: next, cache: cache}
+}
+
+// CreateTeamWithUsers invalidates the cache, as it may update existing users, who are members of other teams.
+func (r *TeamRepository) CreateTeamWithUsers(ctx context.Context, team api.Team) (*domain.TeamWithMembers, error) {
+	created, err := r.TeamRepository.CreateTeamWithUsers(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.invalidate(ctx)
+
+	return created, nil
+}
+
+func (r *TeamRepository) GetTeamByName(ctx context.Context, name string) (*domain.TeamWithMembers, error) {
+	_, inTx := txctx.From(ctx)
+
+	return read(ctx, r.cache, inTx, cacheTeam, name, r.cache.teamTTL, func() (*domain.TeamWithMembers, error) {
+		return r.TeamRepository.GetTeamByName(ctx, name)
+	})
+}
+
+func (r *TeamRepository) GetTeamByID(ctx context.Context, id int) (*domain.TeamWithMembers, error) {
+	_, inTx := txctx.From(ctx)
+
+	return read(ctx, r.cache, inTx, cacheTeamID, strconv.Itoa(id), r.cache.teamTTL, func() (*domain.TeamWithMembers, error) {
+		return r.TeamRepository.GetTeamByID(ctx, id)
+	})
+}
+
+func (r *TeamRepository) RenameTeam(ctx context.Context, id int, newName string) error {
+	if err := r.TeamRepository.RenameTeam(ctx, id, newName); err != nil {
+		return err
+	}
+
+	r.cache.invalidate(ctx)
+
+	return nil
+}
+
+func (r *TeamRepository) DeleteTeam(ctx context.Context, teamID int, deletedAt time.Time) ([]string, error) {
+	deletedUserIDs, err := r.TeamRepository.DeleteTeam(ctx, teamID, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.invalidate(ctx)
+
+	return deletedUserIDs, nil
+}
+
+func (r *TeamRepository) RestoreTeam(ctx context.Context, teamID int) error {
+	if err := r.TeamRepository.RestoreTeam(ctx, teamID); err != nil {
+		return err
+	}
+
+	r.cache.invalidate(ctx)
+
+	return nil
+}
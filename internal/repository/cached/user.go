@@ -0,0 +1,130 @@
+package cached
+
+import (
+	"context"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// UserRepository caches the roles of the users read through the wrapped repository
+// and invalidates the cache on every change of a user.
+type UserRepository struct {
+	repository.UserRepository
+	cache *Cache
+}
+
+func NewUserRepository(next repository.UserRepository, cache *Cache) *UserRepository {
+	return &UserRepository{UserRepository: next, cache: cache}
+}
+
+func (r *UserRepository) SetIsActive(ctx context.Context, userID string, isActive bool) (*api.User, bool, error) {
+	user, wasActive, err := r.UserRepository.SetIsActive(ctx, userID, isActive)
+	if err != nil {
+		return nil, false, err
+	}
+
+	r.cache.invalidate(ctx)
+
+	return user, wasActive, nil
+}
+
+func (r *UserRepository) DeactivateUsersByTeamID(ctx context.Context, teamID int) ([]string, error) {
+	userIDs, err := r.UserRepository.DeactivateUsersByTeamID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userIDs) > 0 {
+		r.cache.invalidate(ctx)
+	}
+
+	return userIDs, nil
+}
+
+func (r *UserRepository) ActivateUsers(ctx context.Context, teamID int, userIDs []string) ([]string, error) {
+	activatedUserIDs, err := r.UserRepository.ActivateUsers(ctx, teamID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(activatedUserIDs) > 0 {
+		r.cache.invalidate(ctx)
+	}
+
+	return activatedUserIDs, nil
+}
+
+func (r *UserRepository) SetUserRole(ctx context.Context, userID string, role domain.UserRole) error {
+	if err := r.UserRepository.SetUserRole(ctx, userID, role); err != nil {
+		return err
+	}
+
+	r.cache.invalidate(ctx)
+
+	return nil
+}
+
+func (r *UserRepository) TransferUser(ctx context.Context, userID string, teamID int) (*api.User, int, error) {
+	user, previousTeamID, err := r.UserRepository.TransferUser(ctx, userID, teamID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r.cache.invalidate(ctx)
+
+	return user, previousTeamID, nil
+}
+
+func (r *UserRepository) DeleteUser(ctx context.Context, userID string, deletedAt time.Time) error {
+	if err := r.UserRepository.DeleteUser(ctx, userID, deletedAt); err != nil {
+		return err
+	}
+
+	r.cache.invalidate(ctx)
+
+	return nil
+}
+
+func (r *UserRepository) RestoreUser(ctx context.Context, userID string) (*api.User, error) {
+	user, err := r.UserRepository.RestoreUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.invalidate(ctx)
+
+	return user, nil
+}
+
+func (r *UserRepository) GetUserRole(ctx context.Context, userID string) (domain.UserRole, error) {
+	return read(ctx, r.cache, false, cacheRole, userID, r.cache.userTTL, func() (domain.UserRole, error) {
+		return r.UserRepository.GetUserRole(ctx, userID)
+	})
+}
+
+// UserPRRepository caches the teams and the activity of the users read through the wrapped repository.
+// The users change through UserRepository and TeamRepository, which invalidate the cache. The wrapped
+// repository reads them outside any transaction, so the reads within one are cached as well.
+type UserPRRepository struct {
+	repository.UserPRRepository
+	cache *Cache
+}
+
+func NewUserPRRepository(next repository.UserPRRepository, cache *Cache) *UserPRRepository {
+	return &UserPRRepository{UserPRRepository: next, cache: cache}
+}
+
+func (r *UserPRRepository) GetAuthorTeamID(ctx context.Context, authorID string) (int, error) {
+	return read(ctx, r.cache, false, cacheUserTeam, authorID, r.cache.userTTL, func() (int, error) {
+		return r.UserPRRepository.GetAuthorTeamID(ctx, authorID)
+	})
+}
+
+func (r *UserPRRepository) IsUserActive(ctx context.Context, userID string) (bool, error) {
+	return read(ctx, r.cache, false, cacheActive, userID, r.cache.userTTL, func() (bool, error) {
+		return r.UserPRRepository.IsUserActive(ctx, userID)
+	})
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) CreateAPIKey(_ context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	var created domain.APIKey
+
+	err := s.update(func(st *state) error {
+		if key.UserID != nil {
+			if _, ok := st.users[*key.UserID]; !ok {
+				return fmt.Errorf("%w: user with id '%s'", apperrors.ErrNotFound, *key.UserID)
+			}
+		}
+
+		st.nextAPIKeyID++
+
+		created = *key
+		created.ID = st.nextAPIKeyID
+		created.Scopes = slices.Clone(key.Scopes)
+		created.CreatedAt = timestampOrNow(key.CreatedAt)
+		created.LastUsedAt = nil
+		st.apiKeys = append(st.apiKeys, created)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (s *Store) GetAPIKeyByHash(_ context.Context, hash string) (*domain.APIKey, error) {
+	const op = "internal.repository.memory.GetAPIKeyByHash"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := slices.IndexFunc(s.data.apiKeys, func(k domain.APIKey) bool { return k.KeyHash == hash })
+	if i < 0 {
+		return nil, fmt.Errorf("%s: %w: api key", op, apperrors.ErrNotFound)
+	}
+
+	key := s.data.apiKeys[i]
+
+	return &key, nil
+}
+
+func (s *Store) ListAPIKeys(_ context.Context) ([]domain.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]domain.APIKey{}, s.data.apiKeys...), nil
+}
+
+func (s *Store) TouchAPIKey(_ context.Context, id int64, usedAt time.Time) error {
+	const op = "internal.repository.memory.TouchAPIKey"
+
+	return s.update(func(st *state) error {
+		i := slices.IndexFunc(st.apiKeys, func(k domain.APIKey) bool { return k.ID == id })
+		if i < 0 {
+			return fmt.Errorf("%s: %w: api key %d", op, apperrors.ErrNotFound, id)
+		}
+
+		used := timestampOrNow(usedAt)
+		st.apiKeys[i].LastUsedAt = &used
+
+		return nil
+	})
+}
+
+func (s *Store) DeleteAPIKey(_ context.Context, id int64) error {
+	const op = "internal.repository.memory.DeleteAPIKey"
+
+	return s.update(func(st *state) error {
+		i := slices.IndexFunc(st.apiKeys, func(k domain.APIKey) bool { return k.ID == id })
+		if i < 0 {
+			return fmt.Errorf("%s: %w: api key %d", op, apperrors.ErrNotFound, id)
+		}
+
+		st.apiKeys = slices.Delete(st.apiKeys, i, i+1)
+
+		return nil
+	})
+}
@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) CreateBorrow(_ context.Context, borrow *domain.ReviewerBorrow) (*domain.ReviewerBorrow, error) {
+	const op = "internal.repository.memory.CreateBorrow"
+
+	var created domain.ReviewerBorrow
+
+	err := s.update(func(st *state) error {
+		team, ok := st.teams[borrow.TeamID]
+		if !ok {
+			return fmt.Errorf("%s: %w: team with id '%d'", op, apperrors.ErrNotFound, borrow.TeamID)
+		}
+
+		lender, ok := st.teams[borrow.LenderTeamID]
+		if !ok {
+			return fmt.Errorf("%s: %w: team with id '%d'", op, apperrors.ErrNotFound, borrow.LenderTeamID)
+		}
+
+		created = domain.ReviewerBorrow{
+			ID:             int64(len(st.borrows) + 1),
+			TeamID:         team.ID,
+			TeamName:       team.Name,
+			LenderTeamID:   lender.ID,
+			LenderTeamName: lender.Name,
+			Count:          borrow.Count,
+			DurationHours:  borrow.DurationHours,
+			Status:         domain.BorrowRequested,
+			RequestedAt:    timestampOrNow(time.Time{}),
+			ReviewerIDs:    []string{},
+		}
+		st.borrows = append(st.borrows, created)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cloneBorrow(created), nil
+}
+
+func (s *Store) AcceptBorrow(_ context.Context, borrowID int64, acceptedAt time.Time) (*domain.ReviewerBorrow, error) {
+	const op = "internal.repository.memory.AcceptBorrow"
+
+	var accepted domain.ReviewerBorrow
+
+	err := s.update(func(st *state) error {
+		if borrowID < 1 || borrowID > int64(len(st.borrows)) {
+			return fmt.Errorf("%s: %w: reviewer borrow %d", op, apperrors.ErrNotFound, borrowID)
+		}
+
+		borrow := &st.borrows[borrowID-1]
+		if borrow.Status != domain.BorrowRequested {
+			return fmt.Errorf("%s: %w: borrow %d is %s", op, apperrors.ErrBorrowNotPending, borrowID, borrow.Status)
+		}
+
+		reviewerIDs := st.lendableUsers(borrow.LenderTeamID, borrow.Count)
+		if len(reviewerIDs) == 0 {
+			return fmt.Errorf("%s: %w: team '%s' has no active members to lend", op, apperrors.ErrInsufficientCapacity, borrow.LenderTeamName)
+		}
+
+		acceptedAt = timestampOrNow(acceptedAt)
+		expiresAt := acceptedAt.Add(time.Duration(borrow.DurationHours) * time.Hour)
+
+		borrow.Status = domain.BorrowAccepted
+		borrow.AcceptedAt = &acceptedAt
+		borrow.ExpiresAt = &expiresAt
+		borrow.ReviewerIDs = reviewerIDs
+		accepted = *borrow
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cloneBorrow(accepted), nil
+}
+
+func (s *Store) ListBorrows(_ context.Context, teamID int) ([]domain.ReviewerBorrow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	borrows := []domain.ReviewerBorrow{}
+
+	for i := len(s.data.borrows) - 1; i >= 0; i-- {
+		borrow := s.data.borrows[i]
+		if borrow.TeamID != teamID && borrow.LenderTeamID != teamID {
+			continue
+		}
+
+		if borrow.ExpiresAt != nil && !borrow.ExpiresAt.After(now) {
+			continue
+		}
+
+		_, teamDeleted := s.data.deletedTeams[borrow.TeamID]
+		_, lenderDeleted := s.data.deletedTeams[borrow.LenderTeamID]
+
+		if teamDeleted || lenderDeleted {
+			continue
+		}
+
+		borrows = append(borrows, *cloneBorrow(borrow))
+	}
+
+	return borrows, nil
+}
+
+// borrowedUsers returns the set of users lent to the team by the borrows unexpired at now.
+func (st *state) borrowedUsers(teamID int, now time.Time) map[string]bool {
+	borrowed := make(map[string]bool)
+
+	for _, borrow := range st.borrows {
+		if borrow.TeamID == teamID && borrow.ExpiresAt != nil && borrow.ExpiresAt.After(now) {
+			for _, id := range borrow.ReviewerIDs {
+				borrowed[id] = true
+			}
+		}
+	}
+
+	return borrowed
+}
+
+// lendableUsers picks up to count active members of the team, those with the fewest open reviews first.
+func (st *state) lendableUsers(teamID int, count int) []string {
+	load := st.openReviewLoad()
+	userIDs := []string{}
+
+	for id, user := range st.users {
+		if st.isMember(id, teamID) && user.IsActive {
+			userIDs = append(userIDs, id)
+		}
+	}
+
+	slices.SortFunc(userIDs, func(a, b string) int {
+		return cmp.Or(cmp.Compare(load[a], load[b]), cmp.Compare(a, b))
+	})
+
+	userIDs = userIDs[:min(count, len(userIDs))]
+	slices.Sort(userIDs)
+
+	return userIDs
+}
+
+func cloneBorrow(borrow domain.ReviewerBorrow) *domain.ReviewerBorrow {
+	borrow.ReviewerIDs = slices.Clone(borrow.ReviewerIDs)
+
+	return &borrow
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) EnqueueCreatePR(_ context.Context, req *domain.CreatePRRequest) (*domain.CreatePRRequest, error) {
+	var created domain.CreatePRRequest
+
+	err := s.update(func(st *state) error {
+		created = domain.CreatePRRequest{
+			ID:              int64(len(st.createRequests) + 1),
+			PullRequestID:   req.PullRequestID,
+			PullRequestName: req.PullRequestName,
+			AuthorID:        req.AuthorID,
+			Description:     req.Description,
+			ExternalURL:     req.ExternalURL,
+			CustomFields:    slices.Clone(req.CustomFields),
+			AssignAt:        req.AssignAt,
+			Status:          domain.CreatePRQueued,
+			EnqueuedAt:      timestampOrNow(req.EnqueuedAt),
+		}
+		st.createRequests = append(st.createRequests, created)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (s *Store) ClaimCreatePRRequests(_ context.Context, limit int, claimedAt time.Time, staleBefore time.Time) ([]domain.CreatePRRequest, error) {
+	claimed := []domain.CreatePRRequest{}
+
+	err := s.update(func(st *state) error {
+		startedAt := timestampOrNow(claimedAt)
+
+		for i := range st.createRequests {
+			if len(claimed) == limit {
+				break
+			}
+
+			req := &st.createRequests[i]
+
+			stale := req.Status == domain.CreatePRProcessing && req.StartedAt != nil && req.StartedAt.Before(staleBefore)
+			if req.Status != domain.CreatePRQueued && !stale {
+				continue
+			}
+
+			req.Status = domain.CreatePRProcessing
+			req.StartedAt = &startedAt
+			req.Attempts++
+			claimed = append(claimed, *req)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+func (s *Store) FinishCreatePRRequest(_ context.Context, req *domain.CreatePRRequest) error {
+	const op = "internal.repository.memory.FinishCreatePRRequest"
+
+	return s.update(func(st *state) error {
+		if req.ID < 1 || req.ID > int64(len(st.createRequests)) {
+			return fmt.Errorf("%s: %w: pull request creation request %d", op, apperrors.ErrNotFound, req.ID)
+		}
+
+		stored := &st.createRequests[req.ID-1]
+		stored.Status = req.Status
+		stored.ErrorCode = req.ErrorCode
+		stored.ErrorMessage = req.ErrorMessage
+		stored.FinishedAt = nil
+
+		if req.FinishedAt != nil {
+			finishedAt := timestampOrNow(*req.FinishedAt)
+			stored.FinishedAt = &finishedAt
+		}
+
+		return nil
+	})
+}
+
+func (s *Store) GetCreatePRRequest(_ context.Context, id int64) (*domain.CreatePRRequest, error) {
+	const op = "internal.repository.memory.GetCreatePRRequest"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if id < 1 || id > int64(len(s.data.createRequests)) {
+		return nil, fmt.Errorf("%s: %w: pull request creation request %d", op, apperrors.ErrNotFound, id)
+	}
+
+	req := s.data.createRequests[id-1]
+
+	return &req, nil
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) GetCustomFields(_ context.Context, teamID int) ([]domain.CustomField, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fields := slices.Clone(s.data.customFields[teamID])
+	if fields == nil {
+		fields = []domain.CustomField{}
+	}
+
+	return fields, nil
+}
+
+func (s *Store) ReplaceCustomFields(_ context.Context, teamID int, fields []domain.CustomField) ([]domain.CustomField, error) {
+	const op = "internal.repository.memory.ReplaceCustomFields"
+
+	saved := make([]domain.CustomField, len(fields))
+	for i, field := range fields {
+		field.TeamID = teamID
+		saved[i] = field
+	}
+
+	slices.SortFunc(saved, func(a, b domain.CustomField) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+
+	err := s.update(func(st *state) error {
+		if _, ok := st.teams[teamID]; !ok {
+			return fmt.Errorf("%s: %w: team with id '%d'", op, apperrors.ErrNotFound, teamID)
+		}
+
+		st.customFields[teamID] = saved
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(saved), nil
+}
+
+// customFieldsMatch reports whether the custom fields of a pull request hold the values of filter,
+// comparing them as text the way the postgres ->> operator reads them.
+func customFieldsMatch(raw []byte, filter map[string]string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var values map[string]any
+	if err := decoder.Decode(&values); err != nil {
+		return false
+	}
+
+	for key, want := range filter {
+		value, ok := values[key]
+		if !ok || value == nil || fmt.Sprint(value) != want {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+// deactivationBatch is a batch of a deactivation job; its pull request IDs are never modified once stored.
+type deactivationBatch struct {
+	jobID   int64
+	batchNo int
+	prIDs   []string
+	done    bool
+}
+
+func (s *Store) CreateDeactivationJob(_ context.Context, job *domain.DeactivationJob, batches [][]string) (*domain.DeactivationJob, error) {
+	const op = "internal.repository.memory.CreateDeactivationJob"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	team, ok := s.data.teams[job.TeamID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w: team with id '%d'", op, apperrors.ErrNotFound, job.TeamID)
+	}
+
+	created := domain.DeactivationJob{
+		ID:                 int64(len(s.data.deactivationJobs) + 1),
+		TeamID:             team.ID,
+		TeamName:           team.Name,
+		Status:             domain.DeactivationJobRunning,
+		BatchSize:          job.BatchSize,
+		TotalBatches:       len(batches),
+		CreatedAt:          timestampOrNow(time.Time{}),
+		DeactivatedUserIDs: slices.Sorted(slices.Values(job.DeactivatedUserIDs)),
+		Warnings:           []string{},
+	}
+
+	if len(batches) == 0 {
+		finishedAt := created.CreatedAt
+		created.Status, created.FinishedAt = domain.DeactivationJobSucceeded, &finishedAt
+	}
+
+	for batchNo, prIDs := range batches {
+		created.TotalPRs += len(prIDs)
+		s.data.deactivationBatches = append(s.data.deactivationBatches, deactivationBatch{
+			jobID:   created.ID,
+			batchNo: batchNo + 1,
+			prIDs:   slices.Sorted(slices.Values(prIDs)),
+		})
+	}
+
+	s.data.deactivationJobs = append(s.data.deactivationJobs, created)
+
+	return cloneDeactivationJob(created), nil
+}
+
+// ClaimDeactivationBatch returns the oldest pending batch. Transactions of the store are serialized,
+// so a batch claimed by a transaction is either done or pending again by the time another one looks.
+func (s *Store) ClaimDeactivationBatch(_ context.Context) (*domain.DeactivationBatch, error) {
+	const op = "internal.repository.memory.ClaimDeactivationBatch"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, batch := range s.data.deactivationBatches {
+		if batch.done {
+			continue
+		}
+
+		job := s.data.deactivationJobs[batch.jobID-1]
+
+		return &domain.DeactivationBatch{
+			JobID:              batch.jobID,
+			BatchNo:            batch.batchNo,
+			TeamID:             job.TeamID,
+			PullRequestIDs:     slices.Clone(batch.prIDs),
+			DeactivatedUserIDs: slices.Clone(job.DeactivatedUserIDs),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s: %w: no pending deactivation batch", op, apperrors.ErrNotFound)
+}
+
+func (s *Store) FinishDeactivationBatch(_ context.Context, batch *domain.DeactivationBatch, reassigned int, warnings []string, finishedAt time.Time) error {
+	const op = "internal.repository.memory.FinishDeactivationBatch"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.data.deactivationBatches, func(b deactivationBatch) bool {
+		return b.jobID == batch.JobID && b.batchNo == batch.BatchNo
+	})
+	if i < 0 {
+		return fmt.Errorf("%s: %w: batch %d of team deactivation job %d", op, apperrors.ErrNotFound, batch.BatchNo, batch.JobID)
+	}
+
+	s.data.deactivationBatches[i].done = true
+
+	job := &s.data.deactivationJobs[batch.JobID-1]
+	job.DoneBatches++
+	job.ReassignedReviews += reassigned
+	job.Warnings = append(slices.Clone(job.Warnings), warnings...)
+
+	if job.DoneBatches >= job.TotalBatches {
+		finishedAt = timestampOrNow(finishedAt)
+		job.Status, job.FinishedAt = domain.DeactivationJobSucceeded, &finishedAt
+	}
+
+	return nil
+}
+
+func (s *Store) GetDeactivationJob(_ context.Context, id int64) (*domain.DeactivationJob, error) {
+	const op = "internal.repository.memory.GetDeactivationJob"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if id < 1 || id > int64(len(s.data.deactivationJobs)) {
+		return nil, fmt.Errorf("%s: %w: team deactivation job %d", op, apperrors.ErrNotFound, id)
+	}
+
+	return cloneDeactivationJob(s.data.deactivationJobs[id-1]), nil
+}
+
+func cloneDeactivationJob(job domain.DeactivationJob) *domain.DeactivationJob {
+	job.DeactivatedUserIDs = slices.Clone(job.DeactivatedUserIDs)
+	job.Warnings = slices.Clone(job.Warnings)
+
+	if job.FinishedAt != nil {
+		finishedAt := *job.FinishedAt
+		job.FinishedAt = &finishedAt
+	}
+
+	return &job
+}
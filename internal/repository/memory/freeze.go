@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) CreateFreezeWindow(_ context.Context, window *domain.FreezeWindow) (*domain.FreezeWindow, error) {
+	var created domain.FreezeWindow
+
+	err := s.update(func(st *state) error {
+		st.nextFreezeID++
+
+		created = *window
+		created.ID = st.nextFreezeID
+		created.TeamName = nil
+		created.CreatedAt = timestampOrNow(window.CreatedAt)
+		st.freezes = append(st.freezes, created)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.data.withTeamName(created), nil
+}
+
+func (s *Store) ListFreezeWindows(_ context.Context, filter domain.FreezeWindowFilter) ([]domain.FreezeWindow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	windows := []domain.FreezeWindow{}
+
+	for _, w := range s.data.freezes {
+		if filter.TeamID != nil && w.TeamID != nil && *w.TeamID != *filter.TeamID {
+			continue
+		}
+
+		if w.TeamID != nil {
+			if _, deleted := s.data.deletedTeams[*w.TeamID]; deleted {
+				continue
+			}
+		}
+
+		if filter.ActiveAt != nil && (filter.ActiveAt.Before(w.StartsAt) || !filter.ActiveAt.Before(w.EndsAt)) {
+			continue
+		}
+
+		if filter.EndsAfter != nil && !w.EndsAt.After(*filter.EndsAfter) {
+			continue
+		}
+
+		windows = append(windows, *s.data.withTeamName(w))
+	}
+
+	slices.SortFunc(windows, func(a, b domain.FreezeWindow) int {
+		return cmp.Or(a.StartsAt.Compare(b.StartsAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return windows, nil
+}
+
+func (s *Store) DeleteFreezeWindow(_ context.Context, id int64) error {
+	const op = "internal.repository.memory.DeleteFreezeWindow"
+
+	return s.update(func(st *state) error {
+		i := slices.IndexFunc(st.freezes, func(w domain.FreezeWindow) bool { return w.ID == id })
+		if i < 0 {
+			return fmt.Errorf("%s: %w: freeze window %d", op, apperrors.ErrNotFound, id)
+		}
+
+		st.freezes = slices.Delete(st.freezes, i, i+1)
+
+		return nil
+	})
+}
+
+// withTeamName fills the team name of a freeze window, which the postgres repository joins from the teams table.
+func (st *state) withTeamName(w domain.FreezeWindow) *domain.FreezeWindow {
+	if w.TeamID != nil {
+		name := st.teams[*w.TeamID].Name
+		w.TeamName = &name
+	}
+
+	return &w
+}
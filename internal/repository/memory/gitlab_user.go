@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) SetGitLabUser(_ context.Context, mapping *domain.GitLabUser) (*domain.GitLabUser, error) {
+	const op = "internal.repository.memory.SetGitLabUser"
+
+	stored := *mapping
+	stored.CreatedAt = timestampOrNow(mapping.CreatedAt)
+
+	err := s.update(func(st *state) error {
+		if _, ok := st.users[mapping.UserID]; !ok {
+			return fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, mapping.UserID)
+		}
+
+		st.gitLabUsers[stored.GitLabUsername] = stored
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+func (s *Store) GetGitLabUser(_ context.Context, gitLabUsername string) (*domain.GitLabUser, error) {
+	const op = "internal.repository.memory.GetGitLabUser"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mapping, ok := s.data.gitLabUsers[gitLabUsername]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w: gitlab user '%s'", op, apperrors.ErrNotFound, gitLabUsername)
+	}
+
+	return &mapping, nil
+}
+
+func (s *Store) ListGitLabUsers(_ context.Context) ([]domain.GitLabUser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mappings := make([]domain.GitLabUser, 0, len(s.data.gitLabUsers))
+	for _, mapping := range s.data.gitLabUsers {
+		mappings = append(mappings, mapping)
+	}
+
+	slices.SortFunc(mappings, func(a, b domain.GitLabUser) int {
+		return strings.Compare(a.GitLabUsername, b.GitLabUsername)
+	})
+
+	return mappings, nil
+}
+
+func (s *Store) DeleteGitLabUser(_ context.Context, gitLabUsername string) error {
+	const op = "internal.repository.memory.DeleteGitLabUser"
+
+	return s.update(func(st *state) error {
+		if _, ok := st.gitLabUsers[gitLabUsername]; !ok {
+			return fmt.Errorf("%s: %w: gitlab user '%s'", op, apperrors.ErrNotFound, gitLabUsername)
+		}
+
+		delete(st.gitLabUsers, gitLabUsername)
+
+		return nil
+	})
+}
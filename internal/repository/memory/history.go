@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) RecordAssignments(_ context.Context, records []domain.AssignmentRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, record := range records {
+		record.ID = int64(len(s.data.history) + 1)
+		record.CreatedAt = timestampOrNow(record.CreatedAt)
+		s.data.history = append(s.data.history, record)
+	}
+
+	return nil
+}
+
+func (s *Store) GetCurrentAssignments(_ context.Context, prID string) ([]domain.AssignmentRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	latest := make(map[string]domain.AssignmentRecord)
+
+	for _, record := range s.data.history {
+		if record.PullRequestID == prID {
+			latest[record.UserID] = record
+		}
+	}
+
+	records := []domain.AssignmentRecord{}
+
+	for _, userID := range s.data.reviewers[prID] {
+		if record, ok := latest[userID]; ok {
+			records = append(records, record)
+		}
+	}
+
+	slices.SortFunc(records, func(a, b domain.AssignmentRecord) int {
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+
+	return records, nil
+}
+
+func (s *Store) ListAssignmentHistory(_ context.Context, prID string) ([]domain.AssignmentRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := []domain.AssignmentRecord{}
+
+	for _, record := range s.data.history {
+		if record.PullRequestID == prID {
+			records = append(records, record)
+		}
+	}
+
+	slices.SortStableFunc(records, func(a, b domain.AssignmentRecord) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return records, nil
+}
+
+func (s *Store) ListReplacementsOf(_ context.Context, replacedUserIDs []string, cause domain.AssignmentCause, since time.Time) ([]domain.AssignmentRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := []domain.AssignmentRecord{}
+
+	for _, record := range s.data.history {
+		if record.ReplacedUserID != nil && slices.Contains(replacedUserIDs, *record.ReplacedUserID) &&
+			record.Cause == cause && !record.CreatedAt.Before(since) {
+			records = append(records, record)
+		}
+	}
+
+	slices.SortStableFunc(records, func(a, b domain.AssignmentRecord) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return records, nil
+}
@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) CreateJob(_ context.Context, job *domain.Job) (*domain.Job, error) {
+	var created domain.Job
+
+	err := s.update(func(st *state) error {
+		created = domain.Job{
+			ID:        int64(len(st.jobs) + 1),
+			Type:      job.Type,
+			Status:    domain.JobQueued,
+			Params:    slices.Clone(job.Params),
+			CreatedAt: timestampOrNow(job.CreatedAt),
+		}
+		st.jobs = append(st.jobs, created)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cloneJob(created), nil
+}
+
+func (s *Store) GetJob(_ context.Context, id int64) (*domain.Job, error) {
+	const op = "internal.repository.memory.GetJob"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if id < 1 || id > int64(len(s.data.jobs)) {
+		return nil, fmt.Errorf("%s: %w: job %d", op, apperrors.ErrNotFound, id)
+	}
+
+	return cloneJob(s.data.jobs[id-1]), nil
+}
+
+func (s *Store) ClaimJob(_ context.Context, owner string, claimedAt time.Time, leaseUntil time.Time) (*domain.Job, error) {
+	const op = "internal.repository.memory.ClaimJob"
+
+	var claimed *domain.Job
+
+	err := s.update(func(st *state) error {
+		for i := range st.jobs {
+			job := &st.jobs[i]
+
+			expired := job.Status == domain.JobRunning && job.LeaseExpiresAt != nil && job.LeaseExpiresAt.Before(claimedAt)
+			if job.Status != domain.JobQueued && !expired {
+				continue
+			}
+
+			startedAt, lease := timestampOrNow(claimedAt), timestampOrNow(leaseUntil)
+			if job.StartedAt == nil {
+				job.StartedAt = &startedAt
+			}
+
+			job.Status, job.Owner, job.LeaseExpiresAt = domain.JobRunning, &owner, &lease
+			job.Attempts++
+			claimed = cloneJob(*job)
+
+			return nil
+		}
+
+		return fmt.Errorf("%s: %w: no claimable job", op, apperrors.ErrNotFound)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+func (s *Store) RenewJobLease(_ context.Context, id int64, owner string, done int, total int, leaseUntil time.Time) (bool, error) {
+	const op = "internal.repository.memory.RenewJobLease"
+
+	var cancelRequested bool
+
+	err := s.update(func(st *state) error {
+		job, ok := ownedJob(st, id, owner)
+		if !ok {
+			return fmt.Errorf("%s: %w: job %d", op, apperrors.ErrJobLeaseLost, id)
+		}
+
+		lease := timestampOrNow(leaseUntil)
+		job.ProgressDone, job.ProgressTotal, job.LeaseExpiresAt = done, total, &lease
+		cancelRequested = job.CancelRequested
+
+		return nil
+	})
+
+	return cancelRequested, err
+}
+
+func (s *Store) FinishJob(_ context.Context, job *domain.Job, owner string) error {
+	const op = "internal.repository.memory.FinishJob"
+
+	return s.update(func(st *state) error {
+		stored, ok := ownedJob(st, job.ID, owner)
+		if !ok {
+			return fmt.Errorf("%s: %w: job %d", op, apperrors.ErrJobLeaseLost, job.ID)
+		}
+
+		stored.Status = job.Status
+		stored.Result = slices.Clone(job.Result)
+		stored.Error = job.Error
+		stored.ProgressDone, stored.ProgressTotal = job.ProgressDone, job.ProgressTotal
+		stored.LeaseExpiresAt, stored.FinishedAt = nil, nil
+
+		if job.FinishedAt != nil {
+			finishedAt := timestampOrNow(*job.FinishedAt)
+			stored.FinishedAt = &finishedAt
+		}
+
+		return nil
+	})
+}
+
+func (s *Store) CancelJob(_ context.Context, id int64, cancelledAt time.Time) (*domain.Job, error) {
+	const op = "internal.repository.memory.CancelJob"
+
+	var cancelled *domain.Job
+
+	err := s.update(func(st *state) error {
+		if id < 1 || id > int64(len(st.jobs)) {
+			return fmt.Errorf("%s: %w: job %d", op, apperrors.ErrNotFound, id)
+		}
+
+		job := &st.jobs[id-1]
+
+		switch job.Status {
+		case domain.JobQueued:
+			// A queued job has no worker to notice the request, so it is cancelled right away.
+			finishedAt := timestampOrNow(cancelledAt)
+			job.Status, job.FinishedAt, job.CancelRequested = domain.JobCancelled, &finishedAt, true
+		case domain.JobRunning:
+			job.CancelRequested = true
+		}
+
+		cancelled = cloneJob(*job)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cancelled, nil
+}
+
+// ownedJob returns the stored job if owner is running it.
+func ownedJob(st *state, id int64, owner string) (*domain.Job, bool) {
+	if id < 1 || id > int64(len(st.jobs)) {
+		return nil, false
+	}
+
+	job := &st.jobs[id-1]
+	if job.Status != domain.JobRunning || job.Owner == nil || *job.Owner != owner {
+		return nil, false
+	}
+
+	return job, true
+}
+
+func cloneJob(job domain.Job) *domain.Job {
+	job.Params = slices.Clone(job.Params)
+	job.Result = slices.Clone(job.Result)
+
+	return &job
+}
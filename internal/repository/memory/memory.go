@@ -0,0 +1,365 @@
+// package memory implements the repository interfaces on top of in-process maps.
+// It backs the dev composition mode, where the service runs without PostgreSQL.
+//
+// Transactions are serialized: beginning a transaction takes a store-wide lock that is held
+// until commit or rollback, and a rollback restores the state captured when the transaction began.
+// Reads outside a transaction do not wait for that lock and may observe uncommitted changes.
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+var errNoSQL = errors.New("memory: SQL statements are not supported")
+
+// Store keeps all service data in memory and implements every repository interface.
+type Store struct {
+	log *slog.Logger
+	db  *sqlx.DB
+
+	// txMu is held for the whole lifetime of a transaction.
+	txMu sync.Mutex
+	// mu guards data and snapshot.
+	mu       sync.RWMutex
+	data     state
+	snapshot *state
+}
+
+type state struct {
+	nextTeamID int
+	teams      map[int]domain.Team
+	// users holds the users with their primary teams, each of which is also among their memberships.
+	users map[string]domain.User
+	// memberships holds the teams each user reviews for.
+	memberships map[membership]struct{}
+	// deletedTeams and deletedUsers hold the soft-deleted teams and users, which are moved out of teams
+	// and users, so that the reads leave them out.
+	deletedTeams map[int]deletedTeam
+	deletedUsers map[string]deletedUser
+	// roles maps a user ID to its role; a user without an entry is a member, as the column defaults to.
+	roles map[string]domain.UserRole
+	prs   map[string]domain.PullRequest
+	// reviewers maps a pull request ID to its reviewers in assignment order.
+	reviewers map[string][]string
+	// reviews maps a pull request ID to the decided reviews of its reviewers by reviewer ID;
+	// a reviewer without an entry has not decided yet.
+	reviews map[string]map[string]domain.Review
+	// assignedAt maps a pull request ID to the assignment times of its reviewers by reviewer ID.
+	assignedAt map[string]map[string]time.Time
+	// firstReviewedAt maps a pull request ID to the first decisions of its reviewers by reviewer ID;
+	// later decisions do not move them.
+	firstReviewedAt map[string]map[string]time.Time
+	policies        map[int]domain.TeamPolicy
+	// customFields maps a team ID to its custom fields ordered by key.
+	customFields map[int][]domain.CustomField
+	history      []domain.AssignmentRecord
+	// pending maps a pull request ID to its entry in the pending assignment queue.
+	pending map[string]domain.PendingAssignment
+	// borrows holds the reviewer borrows in creation order; the ID of a borrow is its position plus one.
+	borrows []domain.ReviewerBorrow
+	// createRequests holds the asynchronous creation requests in enqueue order; the ID of a request is its position plus one.
+	createRequests []domain.CreatePRRequest
+	// deactivationJobs holds the batched team deactivations in creation order; the ID of a job is its position plus one.
+	deactivationJobs []domain.DeactivationJob
+	// deactivationBatches holds the batches of all jobs in creation order.
+	deactivationBatches []deactivationBatch
+	// teamDeactivations holds the team deactivations in creation order; the ID of one is its position plus one,
+	// and its user IDs are never modified once stored.
+	teamDeactivations []domain.TeamDeactivation
+	// jobs holds the long-running jobs in creation order; the ID of a job is its position plus one.
+	jobs []domain.Job
+	// subscriptions maps a pull request ID to its subscriptions ordered by user ID.
+	subscriptions map[string][]domain.PRSubscription
+	// deliveries holds the notification deliveries in creation order; the ID of a delivery is its position plus one.
+	deliveries []domain.NotificationDelivery
+	// freezes holds the freeze windows in creation order; deleted windows are removed, so IDs come from nextFreezeID.
+	nextFreezeID int64
+	freezes      []domain.FreezeWindow
+	// gitLabUsers maps a GitLab username to its mapping.
+	gitLabUsers map[string]domain.GitLabUser
+	// oidcSubjects maps a subject of the identity provider to its mapping.
+	oidcSubjects map[string]domain.OIDCSubject
+	// slackUsers maps a user ID to its Slack mapping.
+	slackUsers map[string]domain.SlackUser
+	// webhooks holds the outbound webhooks in creation order; deleted webhooks are removed, so IDs come from nextWebhookID.
+	nextWebhookID int64
+	webhooks      []domain.Webhook
+	// webhookDeliveries holds the webhook deliveries in creation order; the deliveries of a deleted webhook
+	// are removed with it, so IDs come from nextWebhookDeliveryID.
+	nextWebhookDeliveryID int64
+	webhookDeliveries     []domain.WebhookDelivery
+	// outboxEvents holds the outbox in the order the events were written; the ID of an event is its position plus one.
+	outboxEvents []domain.OutboxEvent
+	// readTokens holds the read tokens in creation order; deleted tokens are removed, so IDs come from nextReadTokenID.
+	nextReadTokenID int64
+	readTokens      []domain.ReadToken
+	// apiKeys holds the stored API keys in creation order; deleted keys are removed, so IDs come from nextAPIKeyID.
+	nextAPIKeyID int64
+	apiKeys      []domain.APIKey
+	// statsView is the statistics as of the last refresh; its slices are replaced, never modified.
+	statsView domain.StatsView
+}
+
+// membership is a user's membership in a team.
+type membership struct {
+	userID string
+	teamID int
+}
+
+// deletedTeam is a soft-deleted team with the time of its deletion.
+type deletedTeam struct {
+	team      domain.Team
+	deletedAt time.Time
+}
+
+// deletedUser is a soft-deleted user with the time of its deletion.
+type deletedUser struct {
+	user      domain.User
+	deletedAt time.Time
+}
+
+// NewStore creates an empty in-memory store.
+func NewStore(log *slog.Logger) *Store {
+	s := &Store{
+		log: log,
+		data: state{
+			nextTeamID: 1,
+			teams:      make(map[int]domain.Team),
+			users:      make(map[string]domain.User),
+			roles:      make(map[string]domain.UserRole),
+			prs:        make(map[string]domain.PullRequest),
+			reviewers:  make(map[string][]string),
+			reviews:    make(map[string]map[string]domain.Review),
+			policies:   make(map[int]domain.TeamPolicy),
+			pending:    make(map[string]domain.PendingAssignment),
+
+			memberships:     make(map[membership]struct{}),
+			deletedTeams:    make(map[int]deletedTeam),
+			deletedUsers:    make(map[string]deletedUser),
+			assignedAt:      make(map[string]map[string]time.Time),
+			firstReviewedAt: make(map[string]map[string]time.Time),
+			customFields:    make(map[int][]domain.CustomField),
+			subscriptions:   make(map[string][]domain.PRSubscription),
+			gitLabUsers:     make(map[string]domain.GitLabUser),
+			oidcSubjects:    make(map[string]domain.OIDCSubject),
+			slackUsers:      make(map[string]domain.SlackUser),
+
+			// Like the materialized views, the statistics are computed on creation, when there is no data yet.
+			statsView: domain.StatsView{Users: []domain.Stats{}, Teams: []domain.TeamStats{}, RefreshedAt: timestampOrNow(time.Time{})},
+		},
+	}
+
+	s.db = sqlx.NewDb(sql.OpenDB(connector{store: s}), "memory")
+
+	return s
+}
+
+// DB returns a handle whose transactions are backed by the store.
+// It satisfies the service layer's Transactor; executing SQL through it fails.
+func (s *Store) DB() *sqlx.DB {
+	return s.db
+}
+
+// timestampOrNow returns t in UTC, or the current time for a zero t, the way the postgres columns default to NOW().
+// It is truncated to microseconds, the precision of a TIMESTAMPTZ column.
+func timestampOrNow(t time.Time) time.Time {
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+func (st *state) clone() *state {
+	c := &state{
+		nextTeamID: st.nextTeamID,
+		teams:      maps.Clone(st.teams),
+		users:      maps.Clone(st.users),
+		roles:      maps.Clone(st.roles),
+		prs:        maps.Clone(st.prs),
+		reviewers:  make(map[string][]string, len(st.reviewers)),
+		reviews:    make(map[string]map[string]domain.Review, len(st.reviews)),
+		policies:   make(map[int]domain.TeamPolicy, len(st.policies)),
+		history:    slices.Clone(st.history),
+		pending:    maps.Clone(st.pending),
+		borrows:    slices.Clone(st.borrows),
+
+		memberships:         maps.Clone(st.memberships),
+		deletedTeams:        maps.Clone(st.deletedTeams),
+		deletedUsers:        maps.Clone(st.deletedUsers),
+		assignedAt:          make(map[string]map[string]time.Time, len(st.assignedAt)),
+		firstReviewedAt:     make(map[string]map[string]time.Time, len(st.firstReviewedAt)),
+		customFields:        make(map[int][]domain.CustomField, len(st.customFields)),
+		createRequests:      slices.Clone(st.createRequests),
+		deactivationJobs:    slices.Clone(st.deactivationJobs),
+		deactivationBatches: slices.Clone(st.deactivationBatches),
+		teamDeactivations:   slices.Clone(st.teamDeactivations),
+		jobs:                slices.Clone(st.jobs),
+		subscriptions:       make(map[string][]domain.PRSubscription, len(st.subscriptions)),
+		deliveries:          slices.Clone(st.deliveries),
+		nextFreezeID:        st.nextFreezeID,
+		freezes:             slices.Clone(st.freezes),
+		gitLabUsers:         maps.Clone(st.gitLabUsers),
+		oidcSubjects:        maps.Clone(st.oidcSubjects),
+		slackUsers:          maps.Clone(st.slackUsers),
+
+		nextWebhookID:         st.nextWebhookID,
+		webhooks:              slices.Clone(st.webhooks),
+		nextWebhookDeliveryID: st.nextWebhookDeliveryID,
+		webhookDeliveries:     slices.Clone(st.webhookDeliveries),
+		outboxEvents:          slices.Clone(st.outboxEvents),
+		nextReadTokenID:       st.nextReadTokenID,
+		readTokens:            slices.Clone(st.readTokens),
+		nextAPIKeyID:          st.nextAPIKeyID,
+		apiKeys:               slices.Clone(st.apiKeys),
+		statsView:             st.statsView,
+	}
+
+	for prID, userIDs := range st.reviewers {
+		c.reviewers[prID] = slices.Clone(userIDs)
+	}
+
+	for prID, reviews := range st.reviews {
+		c.reviews[prID] = maps.Clone(reviews)
+	}
+
+	for prID, times := range st.assignedAt {
+		c.assignedAt[prID] = maps.Clone(times)
+	}
+
+	for prID, times := range st.firstReviewedAt {
+		c.firstReviewedAt[prID] = maps.Clone(times)
+	}
+
+	for teamID, policy := range st.policies {
+		policy.StrategyWeights = maps.Clone(policy.StrategyWeights)
+		c.policies[teamID] = policy
+	}
+
+	for teamID, fields := range st.customFields {
+		c.customFields[teamID] = slices.Clone(fields)
+	}
+
+	for prID, subs := range st.subscriptions {
+		c.subscriptions[prID] = slices.Clone(subs)
+	}
+
+	for i := range c.borrows {
+		c.borrows[i].ReviewerIDs = slices.Clone(c.borrows[i].ReviewerIDs)
+	}
+
+	for i := range c.deactivationJobs {
+		c.deactivationJobs[i] = *cloneDeactivationJob(c.deactivationJobs[i])
+	}
+
+	return c
+}
+
+func (s *Store) begin() {
+	s.txMu.Lock()
+
+	s.mu.Lock()
+	s.snapshot = s.data.clone()
+	s.mu.Unlock()
+}
+
+func (s *Store) commit() {
+	s.mu.Lock()
+	s.snapshot = nil
+	s.mu.Unlock()
+
+	s.txMu.Unlock()
+}
+
+func (s *Store) rollback() {
+	s.mu.Lock()
+	s.data = *s.snapshot
+	s.snapshot = nil
+	s.mu.Unlock()
+
+	s.txMu.Unlock()
+}
+
+// update runs fn in its own transaction, the way the postgres repositories
+// run writes that are not part of a caller's transaction.
+func (s *Store) update(fn func(st *state) error) error {
+	s.begin()
+
+	s.mu.Lock()
+	err := fn(&s.data)
+	s.mu.Unlock()
+
+	if err != nil {
+		s.rollback()
+		return err
+	}
+
+	s.commit()
+
+	return nil
+}
+
+type connector struct {
+	store *Store
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return conn(c), nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return memoryDriver{}
+}
+
+type memoryDriver struct{}
+
+func (memoryDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("memory: connections can only be made through the store")
+}
+
+type conn struct {
+	store *Store
+}
+
+func (conn) Prepare(string) (driver.Stmt, error) {
+	return nil, errNoSQL
+}
+
+func (conn) Close() error {
+	return nil
+}
+
+func (c conn) Begin() (driver.Tx, error) {
+	c.store.begin()
+	return tx(c), nil
+}
+
+// BeginTx accepts any isolation level: serialized transactions provide all of them.
+func (c conn) BeginTx(_ context.Context, _ driver.TxOptions) (driver.Tx, error) {
+	return c.Begin()
+}
+
+type tx struct {
+	store *Store
+}
+
+func (t tx) Commit() error {
+	t.store.commit()
+	return nil
+}
+
+func (t tx) Rollback() error {
+	t.store.rollback()
+	return nil
+}
@@ -0,0 +1,1954 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	store := NewStore(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, err := store.CreateTeamWithUsers(context.Background(), api.Team{
+		TeamName: "pr-team",
+		Members: []api.TeamMember{
+			{UserId: "author", Username: "Author", IsActive: true},
+			{UserId: "rev1", Username: "Reviewer1", IsActive: true},
+			{UserId: "rev2", Username: "Reviewer2", IsActive: true},
+			{UserId: "rev3-inactive", Username: "Reviewer3", IsActive: false},
+		},
+	})
+	require.NoError(t, err)
+
+	return store
+}
+
+func TestStore_RollbackRestoresState(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-1", []string{"rev1"}))
+	require.NoError(t, tx.Rollback())
+
+	_, err = store.GetPRByID(ctx, "pr-1")
+	assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+
+	reviewerIDs, err := store.GetReviewerIDs(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Empty(t, reviewerIDs)
+}
+
+func TestStore_GetTeamByID(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	byName, err := store.GetTeamByName(ctx, "pr-team")
+	require.NoError(t, err)
+
+	byID, err := store.GetTeamByID(ctx, byName.ID)
+	require.NoError(t, err)
+	assert.Equal(t, byName, byID)
+
+	_, err = store.GetTeamByID(ctx, byName.ID+1)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestStore_RenameTeam(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	team, err := store.GetTeamByName(ctx, "pr-team")
+	require.NoError(t, err)
+	_, err = store.CreateTeamWithUsers(ctx, api.Team{TeamName: "other-team"})
+	require.NoError(t, err)
+
+	require.NoError(t, store.RenameTeam(ctx, team.ID, "renamed-team"))
+
+	renamed, err := store.GetTeamByName(ctx, "renamed-team")
+	require.NoError(t, err)
+	assert.Equal(t, team.ID, renamed.ID)
+	assert.Equal(t, team.Members, renamed.Members)
+
+	_, err = store.GetTeamByName(ctx, "pr-team")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	assert.ErrorIs(t, store.RenameTeam(ctx, team.ID, "other-team"), apperrors.ErrAlreadyExists)
+	assert.ErrorIs(t, store.RenameTeam(ctx, team.ID+100, "orphan"), apperrors.ErrNotFound)
+}
+
+func TestStore_DeleteAndRestoreTeam(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	deletedAt := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+
+	team, err := store.GetTeamByName(ctx, "pr-team")
+	require.NoError(t, err)
+
+	// rev2 was deleted before the team and stays deleted when the team is restored.
+	require.NoError(t, store.DeleteUser(ctx, "rev2", deletedAt.Add(-time.Hour)))
+
+	deleted, err := store.DeleteTeam(ctx, team.ID, deletedAt)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"author", "rev1", "rev3-inactive"}, deleted)
+
+	_, err = store.GetTeamByID(ctx, team.ID)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	_, err = store.GetAuthorTeamID(ctx, "author")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	_, err = store.DeleteTeam(ctx, team.ID, deletedAt)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	// The name of a deleted team is free, so the team cannot come back while another team holds it.
+	reused, err := store.CreateTeamWithUsers(ctx, api.Team{TeamName: "pr-team"})
+	require.NoError(t, err)
+	assert.ErrorIs(t, store.RestoreTeam(ctx, team.ID), apperrors.ErrAlreadyExists)
+	require.NoError(t, store.RenameTeam(ctx, reused.ID, "new-team"))
+
+	require.NoError(t, store.RestoreTeam(ctx, team.ID))
+
+	restored, err := store.GetTeamByID(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "pr-team", restored.Name)
+	assert.Len(t, restored.Members, 3)
+	assert.ErrorIs(t, store.RestoreTeam(ctx, team.ID), apperrors.ErrNotFound)
+}
+
+func TestStore_TransferUser(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	team, err := store.GetTeamByName(ctx, "pr-team")
+	require.NoError(t, err)
+	other, err := store.CreateTeamWithUsers(ctx, api.Team{TeamName: "other-team"})
+	require.NoError(t, err)
+
+	user, previousTeamID, err := store.TransferUser(ctx, "rev1", other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, team.ID, previousTeamID)
+	assert.Equal(t, "other-team", user.TeamName)
+
+	// The reviewers of the user's pull requests now come from the new team.
+	teamID, err := store.GetAuthorTeamID(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, teamID)
+
+	team, err = store.GetTeamByID(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Len(t, team.Members, 3, "rev1 leaves its previous team")
+
+	_, _, err = store.TransferUser(ctx, "ghost", other.ID)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	_, _, err = store.TransferUser(ctx, "rev1", other.ID+100)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestStore_MultipleTeams(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	team, err := store.GetTeamByName(ctx, "pr-team")
+	require.NoError(t, err)
+
+	// rev1 joins other-team and stays in pr-team, its primary team.
+	other, err := store.CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "other-team",
+		Members: []api.TeamMember{
+			{UserId: "rev1", Username: "Reviewer1", IsActive: true},
+			{UserId: "other", Username: "Other", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	team, err = store.GetTeamByID(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Len(t, team.Members, 4)
+
+	teamID, err := store.GetAuthorTeamID(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, team.ID, teamID)
+
+	reviewers, err := store.GetRandomActiveReviewers(ctx, other.ID, []string{"other"}, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev1"}, reviewers, "rev1 reviews for both teams")
+
+	teamIDs, err := store.GetUserTeamIDs(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, []int{team.ID, other.ID}, teamIDs)
+
+	// The reviewers of the pull request of other come from other-team, not from the primary team of rev1.
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "other", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-1", []string{"rev1"}))
+
+	prTeamID, err := store.GetPRTeamID(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, prTeamID)
+
+	// The pull request of rev1 counts toward its team only, the primary team of rev1.
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-2", Name: "PR 2", AuthorID: "rev1", Status: api.PullRequestStatusOPEN}))
+
+	ageStats, err := store.GetOpenPRAgeStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, ageStats, 2)
+	assert.Equal(t, "other-team", ageStats[0].TeamName)
+	assert.Equal(t, 1, ageStats[0].OpenPRs)
+	assert.Equal(t, "pr-team", ageStats[1].TeamName)
+	assert.Equal(t, 1, ageStats[1].OpenPRs)
+
+	// rev1 keeps reviewing for pr-team when other-team is deactivated.
+	deactivated, err := store.DeactivateUsersByTeamID(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other"}, deactivated)
+
+	// rev1 is not deleted with pr-team, and other-team becomes its primary team.
+	deleted, err := store.DeleteTeam(ctx, team.ID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"author", "rev2", "rev3-inactive"}, deleted)
+
+	teamID, err = store.GetAuthorTeamID(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, teamID)
+
+	require.NoError(t, store.RestoreTeam(ctx, team.ID))
+
+	restored, err := store.GetTeamByID(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Len(t, restored.Members, 4)
+}
+
+func TestStore_DeleteAndRestoreUser(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.DeleteUser(ctx, "rev1", time.Now()))
+	assert.ErrorIs(t, store.DeleteUser(ctx, "rev1", time.Now()), apperrors.ErrNotFound)
+
+	team, err := store.GetTeamByName(ctx, "pr-team")
+	require.NoError(t, err)
+	assert.Len(t, team.Members, 3)
+
+	_, err = store.IsUserActive(ctx, "rev1")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	user, err := store.RestoreUser(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, "pr-team", user.TeamName)
+
+	_, err = store.RestoreUser(ctx, "rev1")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	// A user whose team is deleted comes back only with the team.
+	require.NoError(t, store.DeleteUser(ctx, "rev2", time.Now()))
+	_, err = store.DeleteTeam(ctx, team.ID, time.Now())
+	require.NoError(t, err)
+
+	_, err = store.RestoreUser(ctx, "rev2")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestStore_MembersOrderedByCollation(t *testing.T) {
+	store := NewStore(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	_, err := store.CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "mixed-team",
+		Members: []api.TeamMember{
+			{UserId: "u1", Username: "яна", IsActive: true},
+			{UserId: "u2", Username: "Ёжиков", IsActive: true},
+			{UserId: "u3", Username: "bob", IsActive: true},
+			{UserId: "u4", Username: "Жанна", IsActive: true},
+			{UserId: "u5", Username: "Alice", IsActive: true},
+			{UserId: "u6", Username: "елена", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	team, err := store.GetTeamByName(ctx, "mixed-team")
+	require.NoError(t, err)
+
+	var usernames []string
+	for _, member := range team.Members {
+		usernames = append(usernames, member.Username)
+	}
+
+	// By code point "Ё" and the capital letters would come before all lowercase letters; here "Ё" sorts as "Е".
+	assert.Equal(t, []string{"Alice", "bob", "Ёжиков", "елена", "Жанна", "яна"}, usernames)
+}
+
+func TestStore_IsUserActive(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	active, err := store.IsUserActive(ctx, "author")
+	require.NoError(t, err)
+	assert.True(t, active)
+
+	active, err = store.IsUserActive(ctx, "rev3-inactive")
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	_, err = store.IsUserActive(ctx, "ghost")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestStore_PullRequestFlow(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	teamID, err := store.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	reviewers, err := store.GetRandomActiveReviewers(ctx, teamID, []string{"author"}, 2)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"rev1", "rev2"}, reviewers)
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-1", []string{"rev1"}))
+	require.NoError(t, tx.Commit())
+
+	tx, err = store.DB().Beginx()
+	require.NoError(t, err)
+	err = store.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "author", Status: api.PullRequestStatusOPEN})
+	assert.True(t, errors.Is(err, apperrors.ErrAlreadyExists))
+	require.NoError(t, tx.Rollback())
+
+	openPRs, err := store.CountOpenPRsByAuthor(ctx, "author")
+	require.NoError(t, err)
+	assert.Equal(t, 1, openPRs)
+
+	leastLoaded, err := store.GetLeastLoadedActiveReviewers(ctx, teamID, []string{"author"}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev2"}, leastLoaded)
+
+	stats, err := store.GetStatsByUserIDs(ctx, []string{"rev1", "rev2"})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Stats{
+		{UserID: "rev1", Username: "Reviewer1", OpenReviews: 1},
+		{UserID: "rev2", Username: "Reviewer2"},
+	}, stats)
+}
+
+func TestStore_GetLeastRecentlyAssignedActiveReviewers(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	assignedAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	teamID, err := store.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, store.RecordAssignments(ctx, []domain.AssignmentRecord{
+		{PullRequestID: "pr-1", UserID: "rev1", Strategy: domain.StrategyRoundRobin, Reason: domain.ReasonRoundRobin, CreatedAt: assignedAt},
+	}))
+	require.NoError(t, tx.Commit())
+
+	next, err := store.GetLeastRecentlyAssignedActiveReviewers(ctx, teamID, []string{"author"}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev2", "rev1"}, next, "a user never assigned goes first")
+
+	tx, err = store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.RecordAssignments(ctx, []domain.AssignmentRecord{
+		{PullRequestID: "pr-1", UserID: "rev2", Strategy: domain.StrategyRoundRobin, Reason: domain.ReasonRoundRobin, CreatedAt: assignedAt.Add(time.Minute)},
+	}))
+	require.NoError(t, tx.Commit())
+
+	next, err = store.GetLeastRecentlyAssignedActiveReviewers(ctx, teamID, []string{"author"}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev1"}, next)
+}
+
+func TestStore_ClosedPRLeavesWorkload(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "author", Status: api.PullRequestStatusOPEN, NeedMoreReviewers: true}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-1", []string{"rev1"}))
+	mergedAt, err := store.UpdatePRStatus(ctx, "pr-1", api.PullRequestStatusCLOSED, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	assert.Nil(t, mergedAt)
+
+	pr, err := store.GetPRByID(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, api.PullRequestStatusCLOSED, pr.Status)
+	assert.False(t, pr.NeedMoreReviewers)
+
+	openPRs, err := store.CountOpenPRsByAuthor(ctx, "author")
+	require.NoError(t, err)
+	assert.Zero(t, openPRs)
+
+	stats, err := store.GetStatsByUserIDs(ctx, []string{"rev1"})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Stats{{UserID: "rev1", Username: "Reviewer1"}}, stats)
+}
+
+func TestStore_Subscribe(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, tx.Commit())
+
+	first, created, err := store.Subscribe(ctx, "pr-1", "rev2")
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	_, _, err = store.Subscribe(ctx, "pr-1", "rev1")
+	require.NoError(t, err)
+
+	again, created, err := store.Subscribe(ctx, "pr-1", "rev2")
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, first, again)
+
+	subscriberIDs, err := store.GetSubscriberIDs(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev1", "rev2"}, subscriberIDs)
+
+	_, _, err = store.Subscribe(ctx, "pr-unknown", "rev1")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	_, _, err = store.Subscribe(ctx, "pr-1", "ghost")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestStore_Reviews(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	reviewedAt := time.Date(2025, 11, 1, 15, 0, 0, 0, time.UTC)
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-1", []string{"rev2", "rev1"}))
+	require.NoError(t, tx.Commit())
+
+	reviews, err := store.GetReviews(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Review{
+		{UserID: "rev1", State: domain.ReviewPending},
+		{UserID: "rev2", State: domain.ReviewPending},
+	}, reviews)
+
+	tx, err = store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.SetReviewState(ctx, "pr-1", "rev1", domain.ReviewApproved, reviewedAt))
+	require.NoError(t, store.SetReviewState(ctx, "pr-1", "rev2", domain.ReviewChangesRequested, reviewedAt))
+	err = store.SetReviewState(ctx, "pr-1", "author", domain.ReviewApproved, reviewedAt)
+	assert.ErrorIs(t, err, apperrors.ErrReviewerNotAssigned)
+	require.NoError(t, tx.Commit())
+
+	pr, err := store.GetPRByIDWithReviewers(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Review{
+		{UserID: "rev1", State: domain.ReviewApproved, ReviewedAt: &reviewedAt},
+		{UserID: "rev2", State: domain.ReviewChangesRequested, ReviewedAt: &reviewedAt},
+	}, pr.Reviews)
+
+	tx, err = store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.ReplaceReviewer(ctx, "pr-1", "rev2", "rev3-inactive"))
+	require.NoError(t, tx.Commit())
+
+	reviews, err = store.GetReviews(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Review{
+		{UserID: "rev1", State: domain.ReviewApproved, ReviewedAt: &reviewedAt},
+		{UserID: "rev3-inactive", State: domain.ReviewPending},
+	}, reviews, "the new reviewer starts with a pending review")
+}
+
+func TestStore_ListPRs(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	_, err := store.CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "other-team",
+		Members:  []api.TeamMember{{UserId: "outsider", Username: "Outsider", IsActive: true}},
+	})
+	require.NoError(t, err)
+
+	team, err := store.GetTeamByName(ctx, "pr-team")
+	require.NoError(t, err)
+
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-a", Name: "A", AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedAt: base}))
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-b", Name: "B", AuthorID: "author", Status: api.PullRequestStatusMERGED, CreatedAt: base.Add(time.Hour)}))
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-c", Name: "C", AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedAt: base.Add(time.Hour)}))
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-d", Name: "D", AuthorID: "outsider", Status: api.PullRequestStatusOPEN, CreatedAt: base.Add(2 * time.Hour)}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-c", []string{"rev1"}))
+	require.NoError(t, tx.Commit())
+
+	ids := func(prs []domain.PullRequest) []string {
+		result := []string{}
+		for _, pr := range prs {
+			result = append(result, pr.ID)
+		}
+
+		return result
+	}
+
+	prs, err := store.ListPRs(ctx, domain.PRListFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr-d", "pr-c", "pr-b", "pr-a"}, ids(prs))
+	assert.Equal(t, []string{"rev1"}, prs[1].ReviewerIDs)
+	assert.Equal(t, []string{}, prs[0].ReviewerIDs)
+
+	prs, err = store.ListPRs(ctx, domain.PRListFilter{TeamID: team.ID, Status: api.PullRequestStatusOPEN, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr-c", "pr-a"}, ids(prs))
+
+	from, to := base.Add(time.Hour), base.Add(2*time.Hour)
+	prs, err = store.ListPRs(ctx, domain.PRListFilter{AuthorID: "author", CreatedFrom: &from, CreatedTo: &to, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr-c", "pr-b"}, ids(prs))
+
+	prs, err = store.ListPRs(ctx, domain.PRListFilter{After: &domain.PRCursor{CreatedAt: base.Add(time.Hour), ID: "pr-c"}, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr-b", "pr-a"}, ids(prs))
+
+	prs, err = store.ListPRs(ctx, domain.PRListFilter{Limit: 2, Offset: 3})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr-a"}, ids(prs))
+}
+
+func TestStore_SearchPRs(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "Add search endpoint", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-2", Name: "Search: search index", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-3", Name: "Search cleanup", AuthorID: "author", Status: api.PullRequestStatusMERGED}))
+	require.NoError(t, tx.Commit())
+
+	prs, total, err := store.SearchPRs(ctx, domain.PRSearchFilter{Query: "Search", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, prs, 3)
+	assert.Equal(t, "pr-2", prs[0].ID)
+
+	prs, total, err = store.SearchPRs(ctx, domain.PRSearchFilter{Query: "search -cleanup", Status: api.PullRequestStatusOPEN, Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Len(t, prs, 1)
+
+	description := "Speeds up the search index rebuild"
+	tx, err = store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-4", Name: "Rebuild tuning", AuthorID: "author", Status: api.PullRequestStatusOPEN, Description: &description}))
+	require.NoError(t, tx.Commit())
+
+	prs, total, err = store.SearchPRs(ctx, domain.PRSearchFilter{Query: "index", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, prs, 2)
+	assert.Equal(t, "pr-2", prs[0].ID, "name matches rank above description matches")
+	assert.Equal(t, "pr-4", prs[1].ID)
+	assert.Equal(t, &description, prs[1].Description)
+}
+
+func TestStore_PendingQueue(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	teamID, err := store.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+
+	for _, id := range []string{"pr-1", "pr-2", "pr-3"} {
+		require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: id, Name: id, AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	}
+
+	require.NoError(t, store.EnqueuePending(ctx, "pr-1", teamID, 1))
+	require.NoError(t, store.EnqueuePending(ctx, "pr-2", teamID, 2))
+	require.NoError(t, store.EnqueuePending(ctx, "pr-3", teamID, 1))
+	require.NoError(t, tx.Commit())
+
+	entries, err := store.ListPending(ctx, "pr-team", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	// Higher priority first, then the oldest entries.
+	assert.Equal(t, "pr-2", entries[0].PullRequestID)
+	assert.Equal(t, "pr-1", entries[1].PullRequestID)
+	assert.Equal(t, "pr-3", entries[2].PullRequestID)
+	assert.Equal(t, "pr-team", entries[0].TeamName)
+	assert.Equal(t, "author", entries[0].AuthorID)
+
+	entries, err = store.ListPending(ctx, "other-team", 10)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	tx, err = store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.DequeuePending(ctx, "pr-2"))
+
+	_, err = store.GetPendingWithLock(ctx, "pr-2")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	require.NoError(t, tx.Rollback())
+
+	// The rollback restores the dequeued entry.
+	entry, err := store.GetPendingWithLock(ctx, "pr-2")
+	require.NoError(t, err)
+	assert.Equal(t, 2, entry.Priority)
+}
+
+func TestStore_ListDuePending(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	teamID, err := store.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	now := time.Date(2025, time.March, 14, 12, 0, 0, 0, time.UTC)
+	earlier, later := now.Add(-time.Minute), now.Add(time.Hour)
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-now", Name: "now", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-due", Name: "due", AuthorID: "author", Status: api.PullRequestStatusOPEN, AssignAt: &earlier}))
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-later", Name: "later", AuthorID: "author", Status: api.PullRequestStatusOPEN, AssignAt: &later}))
+
+	for _, id := range []string{"pr-now", "pr-due", "pr-later"} {
+		require.NoError(t, store.EnqueuePending(ctx, id, teamID, 2))
+	}
+	require.NoError(t, tx.Commit())
+
+	entries, err := store.ListDuePending(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "pr-now", entries[0].PullRequestID)
+	assert.Nil(t, entries[0].AssignAt)
+	assert.Equal(t, "pr-due", entries[1].PullRequestID)
+	assert.Equal(t, &earlier, entries[1].AssignAt)
+
+	// The deferred entry still shows up in the queue listing.
+	entries, err = store.ListPending(ctx, "pr-team", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, &later, entries[2].AssignAt)
+
+	entries, err = store.ListDuePending(ctx, later, 10)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+}
+
+func TestStore_ListUnqueuedNeedingReviewers(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	teamID, err := store.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+
+	for _, pr := range []domain.PullRequest{
+		{ID: "pr-3", NeedMoreReviewers: true, Status: api.PullRequestStatusOPEN},
+		{ID: "pr-1", NeedMoreReviewers: true, Status: api.PullRequestStatusOPEN},
+		{ID: "pr-2", NeedMoreReviewers: true, Status: api.PullRequestStatusOPEN},
+		{ID: "pr-queued", NeedMoreReviewers: true, Status: api.PullRequestStatusOPEN},
+		{ID: "pr-full", Status: api.PullRequestStatusOPEN},
+		{ID: "pr-closed", NeedMoreReviewers: true, Status: api.PullRequestStatusCLOSED},
+	} {
+		pr.Name = pr.ID
+		pr.AuthorID = "author"
+		require.NoError(t, store.CreatePR(ctx, &pr))
+	}
+
+	require.NoError(t, store.EnqueuePending(ctx, "pr-queued", teamID, 1))
+	require.NoError(t, tx.Commit())
+
+	ids, err := store.ListUnqueuedNeedingReviewers(ctx, "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr-1", "pr-2"}, ids)
+
+	ids, err = store.ListUnqueuedNeedingReviewers(ctx, "pr-2", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr-3"}, ids)
+}
+
+func TestStore_GetReplacementAlternatives(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	otherTeam, err := store.CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "other-team",
+		Members: []api.TeamMember{
+			{UserId: "other1", Username: "Other1", IsActive: true},
+			{UserId: "other2", Username: "Other2", IsActive: false},
+		},
+	})
+	require.NoError(t, err)
+
+	otherTeamID := otherTeam.ID
+
+	teamID, err := store.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	alternatives, err := store.GetReplacementAlternatives(ctx, teamID, []string{"author", "rev1"}, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ReplacementAlternative{
+		{UserID: "rev3-inactive", Username: "Reviewer3", TeamID: teamID, TeamName: "pr-team", Reason: domain.AlternativeInactive},
+		{UserID: "other1", Username: "Other1", TeamID: otherTeamID, TeamName: "other-team", Reason: domain.AlternativeOtherTeam},
+	}, alternatives)
+
+	alternatives, err = store.GetReplacementAlternatives(ctx, teamID, []string{"rev3-inactive"}, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ReplacementAlternative{
+		{UserID: "other1", Username: "Other1", TeamID: otherTeamID, TeamName: "other-team", Reason: domain.AlternativeOtherTeam},
+	}, alternatives)
+}
+
+func TestStore_GetOpenPRAgeStats(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-2", Name: "PR 2", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-3", Name: "PR 3", AuthorID: "author", Status: api.PullRequestStatusMERGED}))
+	require.NoError(t, tx.Commit())
+
+	stats, err := store.GetOpenPRAgeStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, "pr-team", stats[0].TeamName)
+	assert.Equal(t, 2, stats[0].OpenPRs)
+	assert.LessOrEqual(t, stats[0].P50Seconds, stats[0].MaxSeconds)
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{10, 20, 30, 40}
+
+	assert.Equal(t, 25.0, percentile(values, 0.5))
+	assert.InDelta(t, 37.0, percentile(values, 0.9), 1e-9)
+	assert.Equal(t, 40.0, percentile(values, 1))
+	assert.Equal(t, 5.0, percentile([]float64{5}, 0.9))
+}
+
+func TestStore_GetUserStatsForPeriod(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	sprintStart := time.Date(2025, time.November, 3, 0, 0, 0, 0, time.UTC)
+	sprintEnd := sprintStart.Add(14 * 24 * time.Hour)
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+
+	for _, pr := range []*domain.PullRequest{
+		{ID: "pr-open-before", AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedAt: sprintStart.Add(-time.Hour)},
+		{ID: "pr-open-during", AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedAt: sprintStart},
+		{ID: "pr-merged-during", AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedAt: sprintStart.Add(-time.Hour)},
+		{ID: "pr-merged-at-end", AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedAt: sprintStart.Add(-time.Hour)},
+	} {
+		require.NoError(t, store.CreatePR(ctx, pr))
+		require.NoError(t, store.AssignReviewers(ctx, pr.ID, []string{"rev1"}))
+	}
+
+	_, err = store.UpdatePRStatus(ctx, "pr-merged-during", api.PullRequestStatusMERGED, sprintStart.Add(time.Hour))
+	require.NoError(t, err)
+	_, err = store.UpdatePRStatus(ctx, "pr-merged-at-end", api.PullRequestStatusMERGED, sprintEnd)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	stats, err := store.GetUserStats(ctx, domain.StatsPeriod{From: &sprintStart, To: &sprintEnd})
+	require.NoError(t, err)
+	require.Len(t, stats, 4, "users without reviews in the period are still listed")
+
+	rev1 := stats[slices.IndexFunc(stats, func(s domain.Stats) bool { return s.UserID == "rev1" })]
+	assert.Equal(t, 1, rev1.OpenReviews)
+	assert.Equal(t, 1, rev1.MergedReviews, "the end of the period is exclusive")
+
+	stats, err = store.GetUserStats(ctx, domain.StatsPeriod{})
+	require.NoError(t, err)
+
+	rev1 = stats[slices.IndexFunc(stats, func(s domain.Stats) bool { return s.UserID == "rev1" })]
+	assert.Equal(t, 2, rev1.OpenReviews)
+	assert.Equal(t, 2, rev1.MergedReviews)
+}
+
+func TestStore_ReviewDurations(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedAt: now.Add(-2 * time.Hour)}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-1", []string{"rev1", "rev2"}))
+	require.NoError(t, store.SetReviewState(ctx, "pr-1", "rev2", domain.ReviewApproved, now.Add(30*time.Minute)))
+	require.NoError(t, store.SetReviewState(ctx, "pr-1", "rev1", domain.ReviewChangesRequested, now.Add(time.Hour)))
+	require.NoError(t, store.SetReviewState(ctx, "pr-1", "rev1", domain.ReviewApproved, now.Add(3*time.Hour)))
+	require.NoError(t, store.ReplaceReviewer(ctx, "pr-1", "rev2", "rev3-inactive"))
+	_, err = store.UpdatePRStatus(ctx, "pr-1", api.PullRequestStatusMERGED, now.Add(4*time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	byUser := func(stats []domain.Stats, userID string) domain.Stats {
+		return stats[slices.IndexFunc(stats, func(s domain.Stats) bool { return s.UserID == userID })]
+	}
+
+	stats, err := store.GetUserStats(ctx, domain.StatsPeriod{})
+	require.NoError(t, err)
+
+	rev1 := byUser(stats, "rev1")
+	assert.Equal(t, 1, rev1.FirstReviews, "later decisions do not move the first one")
+	assert.InDelta(t, time.Hour.Seconds(), *rev1.FirstReviewP50Seconds, 5)
+	assert.InDelta(t, (4 * time.Hour).Seconds(), *rev1.MergeAvgSeconds, 5)
+
+	rev2 := byUser(stats, "rev2")
+	assert.Zero(t, rev2.FirstReviews, "the review of a replaced reviewer is dropped")
+	assert.Nil(t, rev2.FirstReviewAvgSeconds)
+	assert.Nil(t, rev2.MergeAvgSeconds)
+
+	rev3 := byUser(stats, "rev3-inactive")
+	assert.Zero(t, rev3.FirstReviews)
+	assert.InDelta(t, (4 * time.Hour).Seconds(), *rev3.MergeP90Seconds, 5, "the replacement is timed from its own assignment")
+
+	teamStats, err := store.GetTeamStats(ctx, domain.StatsPeriod{})
+	require.NoError(t, err)
+	require.Len(t, teamStats, 1)
+	assert.Equal(t, "pr-team", teamStats[0].TeamName)
+	assert.Equal(t, 1, teamStats[0].FirstReviewedPRs)
+	assert.InDelta(t, (3 * time.Hour).Seconds(), *teamStats[0].FirstReviewAvgSeconds, 1)
+	assert.Equal(t, 1, teamStats[0].MergedPRs)
+	assert.InDelta(t, (6 * time.Hour).Seconds(), *teamStats[0].MergeAvgSeconds, 1)
+
+	from := now.Add(2 * time.Hour)
+	period := domain.StatsPeriod{From: &from}
+
+	stats, err = store.GetUserStats(ctx, period)
+	require.NoError(t, err)
+
+	rev1 = byUser(stats, "rev1")
+	assert.Zero(t, rev1.FirstReviews, "the first review was decided before the period")
+	assert.Nil(t, rev1.FirstReviewAvgSeconds)
+	assert.Equal(t, 1, rev1.MergedReviews)
+
+	teamStats, err = store.GetTeamStats(ctx, period)
+	require.NoError(t, err)
+	require.Len(t, teamStats, 1)
+	assert.Zero(t, teamStats[0].FirstReviewedPRs)
+	assert.Nil(t, teamStats[0].FirstReviewP90Seconds)
+	assert.Equal(t, 1, teamStats[0].MergedPRs)
+
+	to := now.Add(-time.Hour)
+
+	teamStats, err = store.GetTeamStats(ctx, domain.StatsPeriod{To: &to})
+	require.NoError(t, err)
+	assert.Empty(t, teamStats, "teams without any first review or merge in the period are left out")
+}
+
+func TestStore_BackfillReviewerDurations(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedAt: now.Add(-3 * time.Hour)}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-1", []string{"rev1", "rev2"}))
+	require.NoError(t, store.SetReviewState(ctx, "pr-1", "rev1", domain.ReviewApproved, now))
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-2", AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedAt: now.Add(-time.Hour)}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-2", []string{"rev1"}))
+	require.NoError(t, store.RecordAssignments(ctx, []domain.AssignmentRecord{
+		{PullRequestID: "pr-1", UserID: "rev1", CreatedAt: now.Add(-2 * time.Hour)},
+		{PullRequestID: "pr-1", UserID: "rev1", CreatedAt: now.Add(-time.Hour)},
+		{PullRequestID: "pr-2", UserID: "rev1", CreatedAt: now.Add(-time.Hour)},
+	}))
+
+	// The reviewers of pr-1 predate the recorded times.
+	delete(store.data.firstReviewedAt, "pr-1")
+	store.data.assignedAt["pr-2"]["rev1"] = now.Add(-time.Hour)
+
+	lastID, updated, err := store.BackfillReviewerDurations(ctx, "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "pr-1", lastID)
+	assert.Equal(t, 2, updated)
+	assert.True(t, store.data.assignedAt["pr-1"]["rev1"].Equal(now.Add(-time.Hour)), "the latest assignment wins")
+	assert.True(t, store.data.assignedAt["pr-1"]["rev2"].Equal(now.Add(-3*time.Hour)), "reviewers without history date from the PR")
+	assert.True(t, store.data.firstReviewedAt["pr-1"]["rev1"].Equal(now))
+
+	lastID, updated, err = store.BackfillReviewerDurations(ctx, lastID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "pr-2", lastID)
+	assert.Zero(t, updated, "reviewers whose times are right are left alone")
+
+	lastID, updated, err = store.BackfillReviewerDurations(ctx, lastID, 1)
+	require.NoError(t, err)
+	assert.Empty(t, lastID)
+	assert.Zero(t, updated)
+
+	_, updated, err = store.BackfillReviewerDurations(ctx, "", 10)
+	require.NoError(t, err)
+	assert.Zero(t, updated, "the backfill is idempotent")
+}
+
+func TestStore_StatsView(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	view, err := store.GetStatsView(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, view.Users, "the view is computed on creation, before the data is loaded")
+	assert.False(t, view.RefreshedAt.IsZero())
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-1", []string{"rev1"}))
+	_, err = store.UpdatePRStatus(ctx, "pr-1", api.PullRequestStatusMERGED, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	refreshed, err := store.RefreshStatsView(ctx)
+	require.NoError(t, err)
+	require.True(t, refreshed)
+
+	stats, err := store.GetUserStats(ctx, domain.StatsPeriod{})
+	require.NoError(t, err)
+	teamStats, err := store.GetTeamStats(ctx, domain.StatsPeriod{})
+	require.NoError(t, err)
+
+	view, err = store.GetStatsView(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, stats, view.Users)
+	assert.Equal(t, teamStats, view.Teams)
+
+	tx, err = store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-2", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-2", []string{"rev1"}))
+	require.NoError(t, tx.Commit())
+
+	unchanged, err := store.GetStatsView(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, view, unchanged, "the view does not change until it is refreshed")
+}
+
+func TestStore_GetLeaderboard(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	week := domain.LeaderboardWeek.Bucket(time.Date(2025, time.November, 5, 12, 0, 0, 0, time.UTC))
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+
+	for id, merge := range map[string]struct {
+		reviewers []string
+		mergedAt  time.Time
+	}{
+		"pr-1":         {[]string{"rev1", "rev2"}, week.From.Add(time.Hour)},
+		"pr-2":         {[]string{"rev2"}, week.To.Add(-time.Hour)},
+		"pr-last-week": {[]string{"rev1"}, week.From.Add(-time.Hour)},
+	} {
+		require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: id, AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+		require.NoError(t, store.AssignReviewers(ctx, id, merge.reviewers))
+		_, err = store.UpdatePRStatus(ctx, id, api.PullRequestStatusMERGED, merge.mergedAt)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-open", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-open", []string{"rev1"}))
+	require.NoError(t, tx.Commit())
+
+	entries, err := store.GetLeaderboard(ctx, week, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.LeaderboardEntry{
+		{UserID: "rev2", Username: "Reviewer2", MergedReviews: 2},
+		{UserID: "rev1", Username: "Reviewer1", MergedReviews: 1},
+	}, entries, "users without merged reviews in the period are left out")
+
+	entries, err = store.GetLeaderboard(ctx, week, 1)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestStore_SnapshotTransaction(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	tx, err := store.DB().BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	require.NoError(t, err)
+
+	stats, err := store.GetUserStats(ctx, domain.StatsPeriod{})
+	require.NoError(t, err)
+	assert.Len(t, stats, 4)
+	require.NoError(t, tx.Commit())
+}
+
+func TestStore_TimestampsAreUTC(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	createdAt := time.Date(2025, time.March, 14, 15, 9, 26, 535897932, time.FixedZone("UTC+3", 3*60*60))
+	storedCreatedAt := time.Date(2025, time.March, 14, 12, 9, 26, 535897000, time.UTC)
+
+	pr := &domain.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedAt: createdAt}
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, pr))
+	assert.Equal(t, storedCreatedAt, pr.CreatedAt)
+
+	require.NoError(t, store.AssignReviewers(ctx, "pr-1", []string{"rev1"}))
+	require.NoError(t, store.RecordAssignments(ctx, []domain.AssignmentRecord{
+		{PullRequestID: "pr-1", UserID: "rev1", Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, CreatedAt: createdAt},
+	}))
+
+	mergedAt, err := store.UpdatePRStatus(ctx, "pr-1", api.PullRequestStatusMERGED, createdAt.Add(time.Hour))
+	require.NoError(t, err)
+	require.NotNil(t, mergedAt)
+	assert.Equal(t, storedCreatedAt.Add(time.Hour), *mergedAt)
+	require.NoError(t, tx.Commit())
+
+	stored, err := store.GetPRByID(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, storedCreatedAt, stored.CreatedAt)
+	require.NotNil(t, stored.MergedAt)
+	assert.Equal(t, *mergedAt, *stored.MergedAt)
+
+	records, err := store.GetCurrentAssignments(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, storedCreatedAt, records[0].CreatedAt)
+}
+
+func TestStore_ReviewerBorrows(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	lender, err := store.CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "payments",
+		Members: []api.TeamMember{
+			{UserId: "pay1", Username: "Payments1", IsActive: true},
+			{UserId: "pay2-inactive", Username: "Payments2", IsActive: false},
+		},
+	})
+	require.NoError(t, err)
+
+	teamID, err := store.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	borrow, err := store.CreateBorrow(ctx, &domain.ReviewerBorrow{TeamID: teamID, LenderTeamID: lender.ID, Count: 2, DurationHours: 1})
+	require.NoError(t, err)
+	assert.Equal(t, domain.BorrowRequested, borrow.Status)
+	assert.Equal(t, "payments", borrow.LenderTeamName)
+
+	reviewers, err := store.GetRandomActiveReviewers(ctx, teamID, []string{"author"}, 5)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"rev1", "rev2"}, reviewers, "requested borrow lends nobody yet")
+
+	accepted, err := store.AcceptBorrow(ctx, borrow.ID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, domain.BorrowAccepted, accepted.Status)
+	assert.Equal(t, []string{"pay1"}, accepted.ReviewerIDs)
+	require.NotNil(t, accepted.ExpiresAt)
+	assert.Equal(t, accepted.AcceptedAt.Add(time.Hour), *accepted.ExpiresAt)
+
+	reviewers, err = store.GetRandomActiveReviewers(ctx, teamID, []string{"author"}, 5)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"rev1", "rev2", "pay1"}, reviewers)
+
+	reviewers, err = store.GetLeastLoadedActiveReviewers(ctx, teamID, []string{"author"}, 5)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"rev1", "rev2", "pay1"}, reviewers)
+
+	_, err = store.AcceptBorrow(ctx, borrow.ID, time.Now())
+	assert.ErrorIs(t, err, apperrors.ErrBorrowNotPending)
+
+	borrows, err := store.ListBorrows(ctx, lender.ID)
+	require.NoError(t, err)
+	require.Len(t, borrows, 1)
+	assert.Equal(t, borrow.ID, borrows[0].ID)
+
+	expired, err := store.CreateBorrow(ctx, &domain.ReviewerBorrow{TeamID: lender.ID, LenderTeamID: teamID, Count: 1, DurationHours: 1})
+	require.NoError(t, err)
+	_, err = store.AcceptBorrow(ctx, expired.ID, time.Now().Add(-2*time.Hour))
+	require.NoError(t, err)
+
+	reviewers, err = store.GetRandomActiveReviewers(ctx, lender.ID, nil, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pay1"}, reviewers, "expired borrow lends nobody")
+
+	borrows, err = store.ListBorrows(ctx, lender.ID)
+	require.NoError(t, err)
+	require.Len(t, borrows, 1)
+	assert.Equal(t, borrow.ID, borrows[0].ID)
+
+	_, err = store.AcceptBorrow(ctx, 42, time.Now())
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestStore_AcceptBorrowWithoutActiveMembers(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	lender, err := store.CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "payments",
+		Members:  []api.TeamMember{{UserId: "pay1", Username: "Payments1", IsActive: false}},
+	})
+	require.NoError(t, err)
+
+	teamID, err := store.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	borrow, err := store.CreateBorrow(ctx, &domain.ReviewerBorrow{TeamID: teamID, LenderTeamID: lender.ID, Count: 1, DurationHours: 1})
+	require.NoError(t, err)
+
+	_, err = store.AcceptBorrow(ctx, borrow.ID, time.Now())
+	assert.ErrorIs(t, err, apperrors.ErrInsufficientCapacity)
+
+	borrows, err := store.ListBorrows(ctx, teamID)
+	require.NoError(t, err)
+	require.Len(t, borrows, 1)
+	assert.Equal(t, domain.BorrowRequested, borrows[0].Status)
+}
+
+func TestStore_CreatePRRequests(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	for _, prID := range []string{"pr-a", "pr-b", "pr-c"} {
+		_, err := store.EnqueueCreatePR(ctx, &domain.CreatePRRequest{
+			PullRequestID: prID, PullRequestName: "Async " + prID, AuthorID: "author", EnqueuedAt: now,
+		})
+		require.NoError(t, err)
+	}
+
+	claimed, err := store.ClaimCreatePRRequests(ctx, 2, now, now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, []int64{1, 2}, []int64{claimed[0].ID, claimed[1].ID})
+	assert.Equal(t, domain.CreatePRProcessing, claimed[0].Status)
+	assert.Equal(t, 1, claimed[0].Attempts)
+
+	finishedAt := now.Add(time.Second)
+	claimed[0].Status, claimed[0].FinishedAt = domain.CreatePRSucceeded, &finishedAt
+	require.NoError(t, store.FinishCreatePRRequest(ctx, &claimed[0]))
+
+	// Request 2 is still within its lease, so only request 3 is left to claim.
+	claimed, err = store.ClaimCreatePRRequests(ctx, 5, now, now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, int64(3), claimed[0].ID)
+
+	// Once the lease has expired, the abandoned request is claimed again.
+	later := now.Add(2 * time.Minute)
+	claimed, err = store.ClaimCreatePRRequests(ctx, 5, later, later.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, int64(2), claimed[0].ID)
+	assert.Equal(t, 2, claimed[0].Attempts)
+
+	req, err := store.GetCreatePRRequest(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CreatePRSucceeded, req.Status)
+	require.NotNil(t, req.FinishedAt)
+	assert.True(t, finishedAt.Equal(*req.FinishedAt))
+
+	_, err = store.GetCreatePRRequest(ctx, 42)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	err = store.FinishCreatePRRequest(ctx, &domain.CreatePRRequest{ID: 42, Status: domain.CreatePRFailed})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestStore_DeactivationJobs(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	teamID, err := store.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	job, err := store.CreateDeactivationJob(ctx, &domain.DeactivationJob{
+		TeamID: teamID, BatchSize: 2, DeactivatedUserIDs: []string{"rev2", "rev1"},
+	}, [][]string{{"pr-2", "pr-1"}, {"pr-3"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), job.ID)
+	assert.Equal(t, "pr-team", job.TeamName)
+	assert.Equal(t, domain.DeactivationJobRunning, job.Status)
+	assert.Equal(t, 3, job.TotalPRs)
+
+	batch, err := store.ClaimDeactivationBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, batch.BatchNo)
+	assert.Equal(t, []string{"pr-1", "pr-2"}, batch.PullRequestIDs)
+	assert.Equal(t, []string{"rev1", "rev2"}, batch.DeactivatedUserIDs)
+	require.NoError(t, store.FinishDeactivationBatch(ctx, batch, 2, nil, now))
+
+	batch, err = store.ClaimDeactivationBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, batch.BatchNo)
+	require.NoError(t, store.FinishDeactivationBatch(ctx, batch, 0, []string{"no replacement"}, now))
+
+	_, err = store.ClaimDeactivationBatch(ctx)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	got, err := store.GetDeactivationJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeactivationJobSucceeded, got.Status)
+	assert.Equal(t, 2, got.DoneBatches)
+	assert.Equal(t, 2, got.ReassignedReviews)
+	assert.Equal(t, []string{"no replacement"}, got.Warnings)
+	require.NotNil(t, got.FinishedAt)
+	assert.True(t, now.Equal(*got.FinishedAt))
+
+	_, err = store.GetDeactivationJob(ctx, 42)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestStore_TeamDeactivations(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	teamID, err := store.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	_, err = store.GetLastTeamDeactivation(ctx, teamID)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	first := &domain.TeamDeactivation{TeamID: teamID, CreatedAt: now.Add(-time.Hour), UserIDs: []string{"rev1"}}
+	require.NoError(t, store.RecordTeamDeactivation(ctx, first))
+	second := &domain.TeamDeactivation{TeamID: teamID, CreatedAt: now, UserIDs: []string{"rev2", "author"}}
+	require.NoError(t, store.RecordTeamDeactivation(ctx, second))
+
+	last, err := store.GetLastTeamDeactivation(ctx, teamID)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, last.ID)
+	assert.Equal(t, []string{"author", "rev2"}, last.UserIDs)
+
+	// Undoing the last deactivation makes the one before it the last.
+	require.NoError(t, store.MarkTeamReactivated(ctx, second.ID, now))
+
+	last, err = store.GetLastTeamDeactivation(ctx, teamID)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, last.ID)
+
+	assert.ErrorIs(t, store.MarkTeamReactivated(ctx, 42, now), apperrors.ErrNotFound)
+}
+
+func TestStore_ActivateUsers(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	teamID, err := store.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	deactivated, err := store.DeactivateUsersByTeamID(ctx, teamID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"author", "rev1", "rev2"}, deactivated)
+
+	// rev1 comes back by hand, and the users of other teams are left alone.
+	_, _, err = store.SetIsActive(ctx, "rev1", true)
+	require.NoError(t, err)
+
+	activated, err := store.ActivateUsers(ctx, teamID, []string{"author", "rev1", "rev2", "ghost"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"author", "rev2"}, activated)
+
+	activated, err = store.ActivateUsers(ctx, teamID+1, []string{"rev3-inactive"})
+	require.NoError(t, err)
+	assert.Empty(t, activated)
+}
+
+func TestStore_ListReplacementsOf(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	rev1 := "rev1"
+
+	require.NoError(t, store.RecordAssignments(ctx, []domain.AssignmentRecord{
+		{PullRequestID: "pr-1", UserID: "rev2", ReplacedUserID: &rev1, Cause: domain.CauseTeamDeactivated, CreatedAt: now},
+		{PullRequestID: "pr-2", UserID: "rev2", ReplacedUserID: &rev1, Cause: domain.CauseReassign, CreatedAt: now},
+		{PullRequestID: "pr-3", UserID: "rev2", ReplacedUserID: &rev1, Cause: domain.CauseTeamDeactivated, CreatedAt: now.Add(-time.Hour)},
+		{PullRequestID: "pr-4", UserID: "rev1", Cause: domain.CauseCreated, CreatedAt: now},
+	}))
+
+	records, err := store.ListReplacementsOf(ctx, []string{"rev1"}, domain.CauseTeamDeactivated, now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "pr-1", records[0].PullRequestID)
+
+	records, err = store.ListReplacementsOf(ctx, []string{"rev2"}, domain.CauseTeamDeactivated, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestStore_Jobs(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	first, err := store.CreateJob(ctx, &domain.Job{Type: domain.JobStatsExport, Params: []byte(`{}`), CreatedAt: now})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), first.ID)
+	assert.Equal(t, domain.JobQueued, first.Status)
+
+	second, err := store.CreateJob(ctx, &domain.Job{Type: domain.JobPendingBackfill, Params: []byte(`{}`), CreatedAt: now})
+	require.NoError(t, err)
+
+	// A queued job is cancelled right away.
+	cancelled, err := store.CancelJob(ctx, second.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobCancelled, cancelled.Status)
+	require.NotNil(t, cancelled.FinishedAt)
+
+	claimed, err := store.ClaimJob(ctx, "worker-1", now, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, claimed.ID)
+	assert.Equal(t, domain.JobRunning, claimed.Status)
+	assert.Equal(t, 1, claimed.Attempts)
+
+	_, err = store.ClaimJob(ctx, "worker-2", now, now.Add(time.Minute))
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	cancelRequested, err := store.RenewJobLease(ctx, first.ID, "worker-1", 1, 2, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, cancelRequested)
+
+	_, err = store.RenewJobLease(ctx, first.ID, "worker-2", 1, 2, now.Add(time.Minute))
+	assert.ErrorIs(t, err, apperrors.ErrJobLeaseLost)
+
+	// A running job is only flagged; its worker notices the request on the next renewal.
+	flagged, err := store.CancelJob(ctx, first.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobRunning, flagged.Status)
+	assert.True(t, flagged.CancelRequested)
+
+	cancelRequested, err = store.RenewJobLease(ctx, first.ID, "worker-1", 1, 2, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, cancelRequested)
+
+	// Once the lease expires, another worker takes the job over.
+	reclaimed, err := store.ClaimJob(ctx, "worker-2", now.Add(2*time.Minute), now.Add(3*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, reclaimed.ID)
+	assert.Equal(t, 2, reclaimed.Attempts)
+	assert.True(t, now.Equal(*reclaimed.StartedAt))
+
+	finished := *reclaimed
+	finishedAt := now.Add(2 * time.Minute)
+	finished.Status, finished.FinishedAt, finished.Result = domain.JobCancelled, &finishedAt, []byte(`{}`)
+	assert.ErrorIs(t, store.FinishJob(ctx, &finished, "worker-1"), apperrors.ErrJobLeaseLost)
+	require.NoError(t, store.FinishJob(ctx, &finished, "worker-2"))
+
+	got, err := store.GetJob(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobCancelled, got.Status)
+	assert.Nil(t, got.LeaseExpiresAt)
+	assert.True(t, finishedAt.Equal(*got.FinishedAt))
+
+	_, err = store.GetJob(ctx, 42)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestStore_CustomFields(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	teamID, err := store.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	fields, err := store.GetCustomFields(ctx, teamID)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+
+	saved, err := store.ReplaceCustomFields(ctx, teamID, []domain.CustomField{
+		{Key: "story_points", Type: domain.CustomFieldNumber},
+		{Key: "risk", Type: domain.CustomFieldString, Required: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.CustomField{
+		{TeamID: teamID, Key: "risk", Type: domain.CustomFieldString, Required: true},
+		{TeamID: teamID, Key: "story_points", Type: domain.CustomFieldNumber},
+	}, saved)
+
+	_, err = store.ReplaceCustomFields(ctx, 999, nil)
+	assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "Risky change", AuthorID: "author", Status: api.PullRequestStatusOPEN,
+		CustomFields: []byte(`{"risk":"high","story_points":3}`)}))
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-2", Name: "Safe change", AuthorID: "author", Status: api.PullRequestStatusOPEN,
+		CustomFields: []byte(`{"risk":"low"}`)}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-1", []string{"rev1"}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-2", []string{"rev1"}))
+	require.NoError(t, tx.Commit())
+
+	prs, total, err := store.SearchPRs(ctx, domain.PRSearchFilter{Query: "change", CustomFields: map[string]string{"risk": "high", "story_points": "3"}, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, prs, 1)
+	assert.Equal(t, "pr-1", prs[0].ID)
+	assert.JSONEq(t, `{"risk":"high","story_points":3}`, string(prs[0].CustomFields))
+
+	assignments, err := store.GetReviewAssignments(ctx, "rev1", map[string]string{"risk": "low"})
+	require.NoError(t, err)
+	require.Len(t, assignments, 1)
+	assert.Equal(t, "pr-2", assignments[0].ID)
+
+	assignments, err = store.GetReviewAssignments(ctx, "rev1", nil)
+	require.NoError(t, err)
+	assert.Len(t, assignments, 2)
+}
+
+func TestStore_ReplaceReviewers(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	for _, prID := range []string{"pr-1", "pr-2"} {
+		require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: prID, Name: prID, AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	}
+	require.NoError(t, store.AssignReviewers(ctx, "pr-1", []string{"rev1", "rev2"}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-2", []string{"rev1"}))
+
+	// rev2 takes over the review of rev1 on pr-1 while handing their own over to rev4.
+	require.NoError(t, store.ReplaceReviewers(ctx, []domain.ReviewerReplacement{
+		{PullRequestID: "pr-1", OldReviewerID: "rev1", NewReviewerID: "rev2"},
+		{PullRequestID: "pr-1", OldReviewerID: "rev2", NewReviewerID: "rev4"},
+		{PullRequestID: "pr-2", OldReviewerID: "rev1", NewReviewerID: "rev2"},
+	}))
+
+	reviewerIDs, err := store.GetReviewerIDs(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"rev2", "rev4"}, reviewerIDs)
+
+	reviewerIDs, err = store.GetReviewerIDs(ctx, "pr-2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev2"}, reviewerIDs)
+
+	// A rejected replacement leaves the others of its batch unapplied.
+	err = store.ReplaceReviewers(ctx, []domain.ReviewerReplacement{
+		{PullRequestID: "pr-2", OldReviewerID: "rev2", NewReviewerID: "rev1"},
+		{PullRequestID: "pr-1", OldReviewerID: "rev4", NewReviewerID: "author"},
+	})
+	require.ErrorIs(t, err, apperrors.ErrInvalidAssignment)
+
+	reviewerIDs, err = store.GetReviewerIDs(ctx, "pr-2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev2"}, reviewerIDs)
+}
+
+func TestStore_RejectsInvalidAssignments(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	require.NoError(t, store.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, store.AssignReviewers(ctx, "pr-1", []string{"rev1"}))
+
+	var assignmentErr *apperrors.InvalidAssignmentError
+
+	err = store.AssignReviewers(ctx, "pr-1", []string{"rev2", "rev1"})
+	require.ErrorAs(t, err, &assignmentErr)
+	assert.Equal(t, "pr-1", assignmentErr.PRID)
+
+	err = store.AssignReviewers(ctx, "pr-1", []string{"author"})
+	assert.ErrorIs(t, err, apperrors.ErrInvalidAssignment)
+
+	err = store.ReplaceReviewer(ctx, "pr-1", "rev1", "author")
+	assert.ErrorIs(t, err, apperrors.ErrInvalidAssignment)
+
+	err = store.ReplaceReviewers(ctx, []domain.ReviewerReplacement{{PullRequestID: "pr-1", OldReviewerID: "rev1", NewReviewerID: "author"}})
+	assert.ErrorIs(t, err, apperrors.ErrInvalidAssignment)
+	require.NoError(t, tx.Commit())
+
+	reviewerIDs, err := store.GetReviewerIDs(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev1"}, reviewerIDs, "rejected assignments leave the reviewers unchanged")
+}
+
+func TestStore_NotificationDeliveries(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	createdAt := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+	failure := "connection refused"
+
+	first, err := store.CreateDelivery(ctx, &domain.NotificationDelivery{
+		Channel: "chat", RecipientID: "rev1", Event: domain.EventReviewersAssigned, PullRequestID: "pr-1",
+		Status: domain.DeliveryFailed, Error: &failure, Payload: []byte(`{}`), CreatedAt: createdAt,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), first.ID)
+	assert.Equal(t, 1, first.Attempts)
+	assert.Equal(t, createdAt, first.UpdatedAt)
+
+	for _, recipientID := range []string{"rev2", "rev1"} {
+		_, err := store.CreateDelivery(ctx, &domain.NotificationDelivery{
+			Channel: "chat", RecipientID: recipientID, Event: domain.EventPRMerged, PullRequestID: "pr-2",
+			Status: domain.DeliveryDelivered, Payload: []byte(`{}`), CreatedAt: createdAt,
+		})
+		require.NoError(t, err)
+	}
+
+	deliveries, err := store.ListDeliveries(ctx, domain.DeliveryFilter{RecipientID: "rev1", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, int64(3), deliveries[0].ID, "newest first")
+
+	deliveries, err = store.ListDeliveries(ctx, domain.DeliveryFilter{RecipientID: "rev1", Limit: 10, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, int64(1), deliveries[0].ID)
+
+	deliveries, err = store.ListDeliveries(ctx, domain.DeliveryFilter{RecipientID: "rev1", AfterID: 3, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, int64(1), deliveries[0].ID)
+
+	deliveries, err = store.ListDeliveries(ctx, domain.DeliveryFilter{Status: domain.DeliveryDelivered, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, int64(3), deliveries[0].ID)
+
+	retriedAt := createdAt.Add(time.Hour)
+	retried, err := store.RecordDeliveryAttempt(ctx, first.ID, domain.DeliveryDelivered, nil, retriedAt)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeliveryDelivered, retried.Status)
+	assert.Nil(t, retried.Error)
+	assert.Equal(t, 2, retried.Attempts)
+	assert.Equal(t, retriedAt, retried.UpdatedAt)
+
+	stored, err := store.GetDelivery(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, retried, stored)
+
+	_, err = store.GetDelivery(ctx, 42)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	_, err = store.RecordDeliveryAttempt(ctx, 42, domain.DeliveryDelivered, nil, retriedAt)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestStore_FreezeWindows(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	team, err := store.GetTeamByName(ctx, "pr-team")
+	require.NoError(t, err)
+
+	otherTeamID := team.ID + 1
+	start := time.Date(2025, time.December, 22, 18, 0, 0, 0, time.UTC)
+
+	teamWindow, err := store.CreateFreezeWindow(ctx, &domain.FreezeWindow{
+		TeamID: &team.ID, Reason: "release", StartsAt: start.Add(time.Hour), EndsAt: start.Add(2 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, teamWindow.TeamName)
+	assert.Equal(t, "pr-team", *teamWindow.TeamName)
+	assert.False(t, teamWindow.CreatedAt.IsZero())
+
+	orgWindow, err := store.CreateFreezeWindow(ctx, &domain.FreezeWindow{Reason: "holidays", StartsAt: start, EndsAt: start.Add(3 * time.Hour)})
+	require.NoError(t, err)
+	assert.Nil(t, orgWindow.TeamName)
+
+	all, err := store.ListFreezeWindows(ctx, domain.FreezeWindowFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, []int64{orgWindow.ID, teamWindow.ID}, []int64{all[0].ID, all[1].ID}, "windows are ordered by start")
+
+	activeAt := start.Add(90 * time.Minute)
+	active, err := store.ListFreezeWindows(ctx, domain.FreezeWindowFilter{TeamID: &otherTeamID, ActiveAt: &activeAt})
+	require.NoError(t, err)
+	require.Len(t, active, 1, "another team is only subject to the organization window")
+	assert.Equal(t, orgWindow.ID, active[0].ID)
+
+	endOfTeamWindow := start.Add(2 * time.Hour)
+	active, err = store.ListFreezeWindows(ctx, domain.FreezeWindowFilter{TeamID: &team.ID, ActiveAt: &endOfTeamWindow})
+	require.NoError(t, err)
+	require.Len(t, active, 1, "a window does not include its end")
+
+	upcoming, err := store.ListFreezeWindows(ctx, domain.FreezeWindowFilter{EndsAfter: &endOfTeamWindow})
+	require.NoError(t, err)
+	require.Len(t, upcoming, 1)
+	assert.Equal(t, orgWindow.ID, upcoming[0].ID)
+
+	require.NoError(t, store.DeleteFreezeWindow(ctx, orgWindow.ID))
+	assert.ErrorIs(t, store.DeleteFreezeWindow(ctx, orgWindow.ID), apperrors.ErrNotFound)
+
+	all, err = store.ListFreezeWindows(ctx, domain.FreezeWindowFilter{})
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func TestStore_GitLabUsers(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	stored, err := store.SetGitLabUser(ctx, &domain.GitLabUser{GitLabUsername: "jdoe", UserID: "author"})
+	require.NoError(t, err)
+	assert.False(t, stored.CreatedAt.IsZero())
+
+	_, err = store.SetGitLabUser(ctx, &domain.GitLabUser{GitLabUsername: "alice", UserID: "rev1"})
+	require.NoError(t, err)
+
+	_, err = store.SetGitLabUser(ctx, &domain.GitLabUser{GitLabUsername: "jdoe", UserID: "rev2"})
+	require.NoError(t, err)
+
+	_, err = store.SetGitLabUser(ctx, &domain.GitLabUser{GitLabUsername: "ghost", UserID: "no-such-user"})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	mapping, err := store.GetGitLabUser(ctx, "jdoe")
+	require.NoError(t, err)
+	assert.Equal(t, "rev2", mapping.UserID, "a username is mapped to one user at a time")
+
+	_, err = store.GetGitLabUser(ctx, "ghost")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	all, err := store.ListGitLabUsers(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, []string{"alice", "jdoe"}, []string{all[0].GitLabUsername, all[1].GitLabUsername})
+
+	require.NoError(t, store.DeleteGitLabUser(ctx, "jdoe"))
+	assert.ErrorIs(t, store.DeleteGitLabUser(ctx, "jdoe"), apperrors.ErrNotFound)
+}
+
+func TestStore_OIDCSubjects(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	stored, err := store.SetOIDCSubject(ctx, &domain.OIDCSubject{Subject: "00uB", UserID: "author"})
+	require.NoError(t, err)
+	assert.False(t, stored.CreatedAt.IsZero())
+
+	_, err = store.SetOIDCSubject(ctx, &domain.OIDCSubject{Subject: "00uA", UserID: "rev1"})
+	require.NoError(t, err)
+
+	_, err = store.SetOIDCSubject(ctx, &domain.OIDCSubject{Subject: "00uB", UserID: "rev2"})
+	require.NoError(t, err)
+
+	_, err = store.SetOIDCSubject(ctx, &domain.OIDCSubject{Subject: "00uX", UserID: "no-such-user"})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	mapping, err := store.GetOIDCSubject(ctx, "00uB")
+	require.NoError(t, err)
+	assert.Equal(t, "rev2", mapping.UserID, "a subject is mapped to one user at a time")
+
+	_, err = store.GetOIDCSubject(ctx, "00ub")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	all, err := store.ListOIDCSubjects(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, []string{"00uA", "00uB"}, []string{all[0].Subject, all[1].Subject})
+
+	require.NoError(t, store.DeleteOIDCSubject(ctx, "00uB"))
+	assert.ErrorIs(t, store.DeleteOIDCSubject(ctx, "00uB"), apperrors.ErrNotFound)
+}
+
+func TestStore_SlackUsers(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	_, err := store.SetSlackUser(ctx, &domain.SlackUser{UserID: "rev1", SlackUserID: "U0REV1"})
+	require.NoError(t, err)
+
+	_, err = store.SetSlackUser(ctx, &domain.SlackUser{UserID: "author", SlackUserID: "U0AUTHOR"})
+	require.NoError(t, err)
+
+	_, err = store.SetSlackUser(ctx, &domain.SlackUser{UserID: "rev1", SlackUserID: "U0REV1NEW"})
+	require.NoError(t, err)
+
+	_, err = store.SetSlackUser(ctx, &domain.SlackUser{UserID: "no-such-user", SlackUserID: "U0GHOST"})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	mapping, err := store.GetSlackUser(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, "U0REV1NEW", mapping.SlackUserID, "a user is mapped to one Slack member at a time")
+
+	all, err := store.ListSlackUsers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"author", "rev1"}, []string{all[0].UserID, all[1].UserID})
+
+	require.NoError(t, store.DeleteSlackUser(ctx, "rev1"))
+	assert.ErrorIs(t, store.DeleteSlackUser(ctx, "rev1"), apperrors.ErrNotFound)
+
+	_, err = store.GetSlackUser(ctx, "rev1")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestStore_SetIsActiveReportsPreviousState(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	user, wasActive, err := store.SetIsActive(ctx, "rev3-inactive", true)
+	require.NoError(t, err)
+	assert.False(t, wasActive)
+	assert.True(t, user.IsActive)
+
+	_, wasActive, err = store.SetIsActive(ctx, "rev3-inactive", true)
+	require.NoError(t, err)
+	assert.True(t, wasActive)
+
+	_, _, err = store.SetIsActive(ctx, "ghost", true)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	team, err := store.GetTeamByName(ctx, "pr-team")
+	require.NoError(t, err)
+
+	_, err = store.UpsertTeamPolicy(ctx, &domain.TeamPolicy{TeamID: team.ID, StrategyWeights: map[domain.AssignmentStrategy]int{}})
+	require.NoError(t, err)
+
+	policy, err := store.GetTeamPolicy(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RebalanceOff, policy.ReactivationRebalance)
+}
+
+func TestStore_LockActiveUsers(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	activeIDs, err := store.LockActiveUsers(ctx, []string{"rev2", "rev1", "rev3-inactive", "ghost", "rev1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev1", "rev2"}, activeIDs)
+
+	_, _, err = store.SetIsActive(ctx, "rev1", false)
+	require.NoError(t, err)
+
+	activeIDs, err = store.LockActiveUsers(ctx, []string{"rev1", "rev2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev2"}, activeIDs)
+}
+
+func TestStore_WebhookDeliveries(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 14, 12, 0, 0, 0, time.UTC)
+
+	merges, err := store.CreateWebhook(ctx, &domain.Webhook{URL: "https://ci.example.com", Events: []domain.WebhookEventType{domain.WebhookPRMerged}})
+	require.NoError(t, err)
+
+	all, err := store.CreateWebhook(ctx, &domain.Webhook{URL: "https://chat.example.com", Events: []domain.WebhookEventType{
+		domain.WebhookPRCreated, domain.WebhookPRMerged,
+	}})
+	require.NoError(t, err)
+
+	queued, err := store.EnqueueWebhookDeliveries(ctx, &domain.WebhookEvent{Type: domain.WebhookPRCreated, PullRequestID: "pr-1", OccurredAt: now})
+	require.NoError(t, err)
+	assert.Equal(t, 1, queued, "only the subscribed webhooks get a delivery")
+
+	queued, err = store.EnqueueWebhookDeliveries(ctx, &domain.WebhookEvent{Type: domain.WebhookPRMerged, PullRequestID: "pr-1", OccurredAt: now.Add(time.Second)})
+	require.NoError(t, err)
+	assert.Equal(t, 2, queued)
+
+	claimed, err := store.ClaimWebhookDeliveries(ctx, 2, now.Add(time.Second), now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, []int64{1, 2}, []int64{claimed[0].ID, claimed[1].ID})
+	assert.Equal(t, []int64{all.ID, merges.ID}, []int64{claimed[0].WebhookID, claimed[1].WebhookID}, "oldest due first")
+	assert.Equal(t, 1, claimed[0].Attempts)
+
+	// The claimed deliveries are leased, so only the third is left.
+	claimed, err = store.ClaimWebhookDeliveries(ctx, 5, now.Add(time.Second), now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, all.ID, claimed[0].WebhookID)
+
+	message := "webhook responded with status 500"
+	claimed[0].Status, claimed[0].LastError = domain.WebhookDeliveryDead, &message
+	require.NoError(t, store.FinishWebhookAttempt(ctx, &claimed[0]))
+
+	dead, err := store.ListWebhookDeliveries(ctx, domain.WebhookDeliveryFilter{WebhookID: all.ID, Status: domain.WebhookDeliveryDead, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, message, *dead[0].LastError)
+
+	page, err := store.ListWebhookDeliveries(ctx, domain.WebhookDeliveryFilter{AfterID: 3, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 1}, []int64{page[0].ID, page[1].ID}, "deliveries are listed newest first")
+
+	requeued, err := store.RequeueWebhookDelivery(ctx, dead[0].ID, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, domain.WebhookDeliveryPending, requeued.Status)
+	assert.Zero(t, requeued.Attempts)
+
+	_, err = store.RequeueWebhookDelivery(ctx, dead[0].ID, now)
+	assert.ErrorIs(t, err, apperrors.ErrDeliveryNotDead)
+
+	_, err = store.RequeueWebhookDelivery(ctx, 42, now)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	require.NoError(t, store.DeleteWebhook(ctx, all.ID))
+
+	left, err := store.ListWebhookDeliveries(ctx, domain.WebhookDeliveryFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, left, 1, "the deliveries of a deleted webhook are deleted with it")
+
+	assert.ErrorIs(t, store.FinishWebhookAttempt(ctx, &domain.WebhookDelivery{ID: 1}), apperrors.ErrNotFound)
+}
+
+func TestStore_OutboxEvents(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 14, 12, 0, 0, 0, time.UTC)
+
+	created := &domain.OutboxEvent{Type: domain.WebhookPRCreated, PullRequestID: "pr-1", OccurredAt: now}
+	require.NoError(t, store.AddOutboxEvent(ctx, created))
+	require.NoError(t, store.AddOutboxEvent(ctx, &domain.OutboxEvent{Type: domain.WebhookPRMerged, PullRequestID: "pr-1", OccurredAt: now}))
+	assert.Equal(t, int64(1), created.ID)
+
+	claimed, err := store.ClaimOutboxEvents(ctx, 1, now, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, domain.WebhookPRCreated, claimed[0].Type, "events are claimed in the order they were written")
+	assert.Equal(t, 1, claimed[0].Attempts)
+
+	claimed[0].PublishedAt = &now
+	require.NoError(t, store.FinishOutboxAttempt(ctx, &claimed[0]))
+
+	claimed, err = store.ClaimOutboxEvents(ctx, 5, now, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, domain.WebhookPRMerged, claimed[0].Type)
+
+	claimed, err = store.ClaimOutboxEvents(ctx, 5, now, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "published and leased events are not claimed")
+
+	assert.ErrorIs(t, store.FinishOutboxAttempt(ctx, &domain.OutboxEvent{ID: 42}), apperrors.ErrNotFound)
+}
+
+func TestStore_ReadTokens(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	expiresAt := time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	dashboard, err := store.CreateReadToken(ctx, &domain.ReadToken{Name: "grafana", TokenHash: "hash-1", ExpiresAt: &expiresAt})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), dashboard.ID)
+	assert.False(t, dashboard.CreatedAt.IsZero())
+
+	script, err := store.CreateReadToken(ctx, &domain.ReadToken{Name: "weekly report", TokenHash: "hash-2"})
+	require.NoError(t, err)
+
+	found, err := store.GetReadTokenByHash(ctx, "hash-2")
+	require.NoError(t, err)
+	assert.Equal(t, script.ID, found.ID)
+
+	_, err = store.GetReadTokenByHash(ctx, "unknown")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	usedAt := time.Date(2026, time.March, 2, 9, 30, 0, 0, time.UTC)
+	require.NoError(t, store.TouchReadToken(ctx, dashboard.ID, usedAt))
+	assert.ErrorIs(t, store.TouchReadToken(ctx, 42, usedAt), apperrors.ErrNotFound)
+
+	require.NoError(t, store.DeleteReadToken(ctx, script.ID))
+	assert.ErrorIs(t, store.DeleteReadToken(ctx, script.ID), apperrors.ErrNotFound)
+
+	tokens, err := store.ListReadTokens(ctx)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "grafana", tokens[0].Name)
+	require.NotNil(t, tokens[0].LastUsedAt)
+	assert.Equal(t, usedAt, *tokens[0].LastUsedAt)
+
+	next, err := store.CreateReadToken(ctx, &domain.ReadToken{Name: "ci", TokenHash: "hash-3"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), next.ID, "the IDs of deleted tokens are not reused")
+}
+
+func TestStore_APIKeys(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	scopes := []domain.APIKeyScope{domain.ScopeRead, domain.ScopeWrite}
+
+	ci, err := store.CreateAPIKey(ctx, &domain.APIKey{Name: "ci", KeyHash: "hash-1", Scopes: scopes})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), ci.ID)
+	assert.False(t, ci.CreatedAt.IsZero())
+
+	// The stored key does not share the scopes with the caller.
+	scopes[0] = domain.ScopeAdmin
+
+	ops, err := store.CreateAPIKey(ctx, &domain.APIKey{Name: "ops", KeyHash: "hash-2", Scopes: []domain.APIKeyScope{domain.ScopeAdmin}})
+	require.NoError(t, err)
+
+	found, err := store.GetAPIKeyByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.APIKeyScope{domain.ScopeRead, domain.ScopeWrite}, found.Scopes)
+
+	_, err = store.GetAPIKeyByHash(ctx, "unknown")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	usedAt := time.Date(2026, time.March, 2, 9, 30, 0, 0, time.UTC)
+	require.NoError(t, store.TouchAPIKey(ctx, ci.ID, usedAt))
+	assert.ErrorIs(t, store.TouchAPIKey(ctx, 42, usedAt), apperrors.ErrNotFound)
+
+	require.NoError(t, store.DeleteAPIKey(ctx, ops.ID))
+	assert.ErrorIs(t, store.DeleteAPIKey(ctx, ops.ID), apperrors.ErrNotFound)
+
+	keys, err := store.ListAPIKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "ci", keys[0].Name)
+	require.NotNil(t, keys[0].LastUsedAt)
+	assert.Equal(t, usedAt, *keys[0].LastUsedAt)
+}
+
+func TestStore_UserRoles(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	role, err := store.GetUserRole(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleMember, role, "a new user is a member")
+
+	require.NoError(t, store.SetUserRole(ctx, "rev1", domain.RoleLead))
+
+	role, err = store.GetUserRole(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleLead, role)
+
+	assert.ErrorIs(t, store.SetUserRole(ctx, "ghost", domain.RoleAdmin), apperrors.ErrNotFound)
+
+	_, err = store.GetUserRole(ctx, "ghost")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	userID := "rev1"
+
+	key, err := store.CreateAPIKey(ctx, &domain.APIKey{Name: "rev1", KeyHash: "hash-1", Scopes: []domain.APIKeyScope{domain.ScopeRead}, UserID: &userID})
+	require.NoError(t, err)
+	require.NotNil(t, key.UserID)
+	assert.Equal(t, "rev1", *key.UserID)
+
+	ghost := "ghost"
+	_, err = store.CreateAPIKey(ctx, &domain.APIKey{Name: "ghost", KeyHash: "hash-2", Scopes: []domain.APIKeyScope{domain.ScopeRead}, UserID: &ghost})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) CreateDelivery(_ context.Context, d *domain.NotificationDelivery) (*domain.NotificationDelivery, error) {
+	var created domain.NotificationDelivery
+
+	err := s.update(func(st *state) error {
+		created = *cloneDelivery(*d)
+		created.ID = int64(len(st.deliveries) + 1)
+		created.Attempts = 1
+		created.CreatedAt = timestampOrNow(d.CreatedAt)
+		created.UpdatedAt = created.CreatedAt
+		st.deliveries = append(st.deliveries, created)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cloneDelivery(created), nil
+}
+
+func (s *Store) GetDelivery(_ context.Context, id int64) (*domain.NotificationDelivery, error) {
+	const op = "internal.repository.memory.GetDelivery"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if id < 1 || id > int64(len(s.data.deliveries)) {
+		return nil, fmt.Errorf("%s: %w: notification delivery %d", op, apperrors.ErrNotFound, id)
+	}
+
+	return cloneDelivery(s.data.deliveries[id-1]), nil
+}
+
+func (s *Store) ListDeliveries(_ context.Context, filter domain.DeliveryFilter) ([]domain.NotificationDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	deliveries := []domain.NotificationDelivery{}
+	skipped := 0
+
+	// Delivery IDs are positions in the slice counted from one, so a page after a delivery starts right below it.
+	start := len(s.data.deliveries) - 1
+	if filter.AfterID != 0 {
+		start = min(start, int(filter.AfterID)-2)
+	}
+
+	for i := start; i >= 0 && len(deliveries) < filter.Limit; i-- {
+		d := s.data.deliveries[i]
+
+		switch {
+		case filter.Channel != "" && d.Channel != filter.Channel,
+			filter.RecipientID != "" && d.RecipientID != filter.RecipientID,
+			filter.PullRequestID != "" && d.PullRequestID != filter.PullRequestID,
+			filter.Event != "" && d.Event != filter.Event,
+			filter.Status != "" && d.Status != filter.Status:
+			continue
+		}
+
+		if skipped < filter.Offset {
+			skipped++
+			continue
+		}
+
+		deliveries = append(deliveries, *cloneDelivery(d))
+	}
+
+	return deliveries, nil
+}
+
+func (s *Store) RecordDeliveryAttempt(
+	_ context.Context,
+	id int64,
+	status domain.DeliveryStatus,
+	deliveryErr *string,
+	attemptedAt time.Time,
+) (*domain.NotificationDelivery, error) {
+	const op = "internal.repository.memory.RecordDeliveryAttempt"
+
+	var updated *domain.NotificationDelivery
+
+	err := s.update(func(st *state) error {
+		if id < 1 || id > int64(len(st.deliveries)) {
+			return fmt.Errorf("%s: %w: notification delivery %d", op, apperrors.ErrNotFound, id)
+		}
+
+		d := &st.deliveries[id-1]
+		d.Status, d.Error = status, deliveryErr
+		d.Attempts++
+		d.UpdatedAt = timestampOrNow(attemptedAt)
+		updated = cloneDelivery(*d)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func cloneDelivery(d domain.NotificationDelivery) *domain.NotificationDelivery {
+	d.Payload = slices.Clone(d.Payload)
+
+	return &d
+}
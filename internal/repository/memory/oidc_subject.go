@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) SetOIDCSubject(_ context.Context, mapping *domain.OIDCSubject) (*domain.OIDCSubject, error) {
+	const op = "internal.repository.memory.SetOIDCSubject"
+
+	stored := *mapping
+	stored.CreatedAt = timestampOrNow(mapping.CreatedAt)
+
+	err := s.update(func(st *state) error {
+		if _, ok := st.users[mapping.UserID]; !ok {
+			return fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, mapping.UserID)
+		}
+
+		st.oidcSubjects[stored.Subject] = stored
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+func (s *Store) GetOIDCSubject(_ context.Context, subject string) (*domain.OIDCSubject, error) {
+	const op = "internal.repository.memory.GetOIDCSubject"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mapping, ok := s.data.oidcSubjects[subject]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w: oidc subject '%s'", op, apperrors.ErrNotFound, subject)
+	}
+
+	return &mapping, nil
+}
+
+func (s *Store) ListOIDCSubjects(_ context.Context) ([]domain.OIDCSubject, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mappings := make([]domain.OIDCSubject, 0, len(s.data.oidcSubjects))
+	for _, mapping := range s.data.oidcSubjects {
+		mappings = append(mappings, mapping)
+	}
+
+	slices.SortFunc(mappings, func(a, b domain.OIDCSubject) int {
+		return strings.Compare(a.Subject, b.Subject)
+	})
+
+	return mappings, nil
+}
+
+func (s *Store) DeleteOIDCSubject(_ context.Context, subject string) error {
+	const op = "internal.repository.memory.DeleteOIDCSubject"
+
+	return s.update(func(st *state) error {
+		if _, ok := st.oidcSubjects[subject]; !ok {
+			return fmt.Errorf("%s: %w: oidc subject '%s'", op, apperrors.ErrNotFound, subject)
+		}
+
+		delete(st.oidcSubjects, subject)
+
+		return nil
+	})
+}
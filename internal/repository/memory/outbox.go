@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) AddOutboxEvent(_ context.Context, event *domain.OutboxEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	occurredAt := timestampOrNow(event.OccurredAt)
+
+	event.ID = int64(len(s.data.outboxEvents) + 1)
+	s.data.outboxEvents = append(s.data.outboxEvents, domain.OutboxEvent{
+		ID:            event.ID,
+		Type:          event.Type,
+		PullRequestID: event.PullRequestID,
+		Payload:       slices.Clone(event.Payload),
+		NextAttemptAt: occurredAt,
+		OccurredAt:    occurredAt,
+	})
+
+	return nil
+}
+
+func (s *Store) ClaimOutboxEvents(_ context.Context, limit int, now, leaseUntil time.Time) ([]domain.OutboxEvent, error) {
+	claimed := []domain.OutboxEvent{}
+
+	err := s.update(func(st *state) error {
+		for i := range st.outboxEvents {
+			if len(claimed) == limit {
+				break
+			}
+
+			e := &st.outboxEvents[i]
+			if e.PublishedAt != nil || e.NextAttemptAt.After(now) {
+				continue
+			}
+
+			e.Attempts++
+			e.NextAttemptAt = timestampOrNow(leaseUntil)
+			claimed = append(claimed, *e)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+func (s *Store) FinishOutboxAttempt(_ context.Context, event *domain.OutboxEvent) error {
+	const op = "internal.repository.memory.FinishOutboxAttempt"
+
+	return s.update(func(st *state) error {
+		if event.ID < 1 || event.ID > int64(len(st.outboxEvents)) {
+			return fmt.Errorf("%s: %w: outbox event %d", op, apperrors.ErrNotFound, event.ID)
+		}
+
+		stored := &st.outboxEvents[event.ID-1]
+		stored.PublishedAt = event.PublishedAt
+		stored.NextAttemptAt = timestampOrNow(event.NextAttemptAt)
+		stored.LastError = event.LastError
+
+		return nil
+	})
+}
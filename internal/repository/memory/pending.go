@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+func (s *Store) EnqueuePending(_ context.Context, prID string, teamID int, priority int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.data.pending[prID]
+	if !ok {
+		entry = domain.PendingAssignment{PullRequestID: prID, TeamID: teamID, EnqueuedAt: time.Now().UTC()}
+	}
+
+	entry.Priority = priority
+	s.data.pending[prID] = entry
+
+	return nil
+}
+
+func (s *Store) DequeuePending(_ context.Context, prID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data.pending, prID)
+
+	return nil
+}
+
+// GetPendingWithLock needs no row lock: the caller's transaction already excludes all others.
+func (s *Store) GetPendingWithLock(_ context.Context, prID string) (*domain.PendingAssignment, error) {
+	const op = "internal.repository.memory.GetPendingWithLock"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.data.pending[prID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w: pending assignment of PR '%s'", op, apperrors.ErrNotFound, prID)
+	}
+
+	entry = s.data.withNames(entry)
+
+	return &entry, nil
+}
+
+func (s *Store) ListPending(_ context.Context, teamName string, limit int) ([]domain.PendingAssignment, error) {
+	return s.listPending(func(entry domain.PendingAssignment) bool {
+		return teamName == "" || entry.TeamName == teamName
+	}, limit), nil
+}
+
+func (s *Store) ListDuePending(_ context.Context, now time.Time, limit int) ([]domain.PendingAssignment, error) {
+	return s.listPending(func(entry domain.PendingAssignment) bool {
+		return entry.AssignAt == nil || !entry.AssignAt.After(now)
+	}, limit), nil
+}
+
+// listPending returns up to limit entries accepted by keep in serving order.
+func (s *Store) listPending(keep func(domain.PendingAssignment) bool, limit int) []domain.PendingAssignment {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := []domain.PendingAssignment{}
+
+	for _, entry := range s.data.pending {
+		if _, ok := s.data.teams[entry.TeamID]; !ok {
+			continue
+		}
+
+		entry = s.data.withNames(entry)
+		if keep(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b domain.PendingAssignment) int {
+		return cmp.Or(
+			cmp.Compare(b.Priority, a.Priority),
+			a.EnqueuedAt.Compare(b.EnqueuedAt),
+			cmp.Compare(a.PullRequestID, b.PullRequestID),
+		)
+	})
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	return entries
+}
+
+func (s *Store) ListUnqueuedNeedingReviewers(_ context.Context, afterID string, limit int) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := []string{}
+
+	for _, pr := range s.data.prs {
+		if _, queued := s.data.pending[pr.ID]; queued {
+			continue
+		}
+
+		if pr.Status == api.PullRequestStatusOPEN && pr.NeedMoreReviewers && pr.ID > afterID {
+			ids = append(ids, pr.ID)
+		}
+	}
+
+	slices.Sort(ids)
+
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	return ids, nil
+}
+
+// withNames fills the fields of a queue entry that the postgres repository joins from other tables.
+func (st *state) withNames(entry domain.PendingAssignment) domain.PendingAssignment {
+	pr := st.prs[entry.PullRequestID]
+	entry.PullRequestName = pr.Name
+	entry.AuthorID = pr.AuthorID
+	entry.AssignAt = pr.AssignAt
+	entry.TeamName = st.teams[entry.TeamID].Name
+
+	return entry
+}
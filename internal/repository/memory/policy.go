@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) GetTeamPolicy(_ context.Context, teamID int) (*domain.TeamPolicy, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	policy, ok := s.data.policies[teamID]
+	if !ok {
+		return &domain.TeamPolicy{
+			TeamID:                teamID,
+			StrategyWeights:       map[domain.AssignmentStrategy]int{},
+			OverQuotaAction:       domain.QuotaReject,
+			ReactivationRebalance: domain.RebalanceOff,
+		}, nil
+	}
+
+	policy.StrategyWeights = maps.Clone(policy.StrategyWeights)
+
+	return &policy, nil
+}
+
+func (s *Store) UpsertTeamPolicy(_ context.Context, policy *domain.TeamPolicy) (*domain.TeamPolicy, error) {
+	const op = "internal.repository.memory.UpsertTeamPolicy"
+
+	saved := domain.TeamPolicy{
+		TeamID:                policy.TeamID,
+		StrategyWeights:       maps.Clone(policy.StrategyWeights),
+		AuthorOpenPRLimit:     policy.AuthorOpenPRLimit,
+		OverQuotaAction:       policy.OverQuotaAction,
+		ReactivationRebalance: policy.ReactivationRebalance,
+		UpdatedAt:             time.Now().UTC(),
+	}
+
+	if saved.OverQuotaAction == "" {
+		saved.OverQuotaAction = domain.QuotaReject
+	}
+
+	if saved.ReactivationRebalance == "" {
+		saved.ReactivationRebalance = domain.RebalanceOff
+	}
+
+	err := s.update(func(st *state) error {
+		if _, ok := st.teams[policy.TeamID]; !ok {
+			return fmt.Errorf("%s: %w: team with id '%d'", op, apperrors.ErrNotFound, policy.TeamID)
+		}
+
+		st.policies[policy.TeamID] = saved
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	saved.StrategyWeights = maps.Clone(saved.StrategyWeights)
+
+	return &saved, nil
+}
@@ -0,0 +1,1068 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+func (s *Store) GetAuthorTeamID(_ context.Context, authorID string) (int, error) {
+	const op = "internal.repository.memory.GetAuthorTeamID"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.data.users[authorID]
+	if !ok {
+		return 0, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, authorID)
+	}
+
+	return user.TeamID, nil
+}
+
+func (s *Store) IsUserActive(_ context.Context, userID string) (bool, error) {
+	const op = "internal.repository.memory.IsUserActive"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.data.users[userID]
+	if !ok {
+		return false, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+	}
+
+	return user.IsActive, nil
+}
+
+func (s *Store) GetUserTeamIDs(_ context.Context, userID string) ([]int, error) {
+	const op = "internal.repository.memory.GetUserTeamIDs"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	teamIDs := s.data.userTeamIDs(userID)
+	if _, ok := s.data.users[userID]; !ok || len(teamIDs) == 0 {
+		return nil, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+	}
+
+	return teamIDs, nil
+}
+
+func (s *Store) GetPRTeamID(_ context.Context, prID string) (int, error) {
+	const op = "internal.repository.memory.GetPRTeamID"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pr, ok := s.data.prs[prID]
+	if !ok {
+		return 0, fmt.Errorf("%s: %w: PR with id '%s'", op, apperrors.ErrNotFound, prID)
+	}
+
+	author, ok := s.data.users[pr.AuthorID]
+	if !ok {
+		deleted, ok := s.data.deletedUsers[pr.AuthorID]
+		if !ok {
+			return 0, fmt.Errorf("%s: %w: team of PR with id '%s'", op, apperrors.ErrNotFound, prID)
+		}
+
+		author = deleted.user
+	}
+
+	return author.TeamID, nil
+}
+
+func (s *Store) GetRandomActiveReviewers(_ context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error) {
+	s.mu.RLock()
+	candidateIDs := s.data.activeCandidates(teamID, excludeUserIDs)
+	s.mu.RUnlock()
+
+	rand.Shuffle(len(candidateIDs), func(i, j int) {
+		candidateIDs[i], candidateIDs[j] = candidateIDs[j], candidateIDs[i]
+	})
+
+	return candidateIDs[:min(count, len(candidateIDs))], nil
+}
+
+func (s *Store) GetLeastLoadedActiveReviewers(_ context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	candidateIDs := s.data.activeCandidates(teamID, excludeUserIDs)
+	load := s.data.openReviewLoad()
+
+	rand.Shuffle(len(candidateIDs), func(i, j int) {
+		candidateIDs[i], candidateIDs[j] = candidateIDs[j], candidateIDs[i]
+	})
+
+	slices.SortStableFunc(candidateIDs, func(a, b string) int {
+		return cmp.Compare(load[a], load[b])
+	})
+
+	return candidateIDs[:min(count, len(candidateIDs))], nil
+}
+
+func (s *Store) GetLeastRecentlyAssignedActiveReviewers(_ context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	candidateIDs := s.data.activeCandidates(teamID, excludeUserIDs)
+
+	lastAssigned := make(map[string]time.Time)
+	for _, record := range s.data.history {
+		if record.CreatedAt.After(lastAssigned[record.UserID]) {
+			lastAssigned[record.UserID] = record.CreatedAt
+		}
+	}
+
+	// A user never assigned has the zero time and therefore comes first.
+	slices.SortFunc(candidateIDs, func(a, b string) int {
+		return cmp.Or(lastAssigned[a].Compare(lastAssigned[b]), cmp.Compare(a, b))
+	})
+
+	return candidateIDs[:min(count, len(candidateIDs))], nil
+}
+
+func (s *Store) GetReplacementAlternatives(_ context.Context, teamID int, excludeUserIDs []string, limit int) ([]domain.ReplacementAlternative, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	load := s.data.openReviewLoad()
+	byReason := make(map[domain.AlternativeReason][]domain.ReplacementAlternative)
+
+	for _, user := range s.data.users {
+		if slices.Contains(excludeUserIDs, user.ID) {
+			continue
+		}
+
+		var reason domain.AlternativeReason
+
+		switch {
+		case s.data.isMember(user.ID, teamID) && !user.IsActive:
+			reason = domain.AlternativeInactive
+		case !s.data.isMember(user.ID, teamID) && user.IsActive:
+			reason = domain.AlternativeOtherTeam
+		default:
+			continue
+		}
+
+		byReason[reason] = append(byReason[reason], domain.ReplacementAlternative{
+			UserID:      user.ID,
+			Username:    user.Username,
+			TeamID:      user.TeamID,
+			TeamName:    s.data.teams[user.TeamID].Name,
+			Reason:      reason,
+			OpenReviews: load[user.ID],
+		})
+	}
+
+	alternatives := []domain.ReplacementAlternative{}
+
+	for _, reason := range []domain.AlternativeReason{domain.AlternativeInactive, domain.AlternativeOtherTeam} {
+		users := byReason[reason]
+		slices.SortFunc(users, func(a, b domain.ReplacementAlternative) int {
+			return cmp.Or(cmp.Compare(a.OpenReviews, b.OpenReviews), cmp.Compare(a.UserID, b.UserID))
+		})
+
+		alternatives = append(alternatives, users[:min(limit, len(users))]...)
+	}
+
+	return alternatives, nil
+}
+
+// openReviewLoad counts the open pull requests each user reviews.
+func (st *state) openReviewLoad() map[string]int {
+	load := make(map[string]int)
+
+	for prID, userIDs := range st.reviewers {
+		if st.prs[prID].Status != api.PullRequestStatusOPEN {
+			continue
+		}
+
+		for _, userID := range userIDs {
+			load[userID]++
+		}
+	}
+
+	return load
+}
+
+func (st *state) activeCandidates(teamID int, excludeUserIDs []string) []string {
+	candidateIDs := []string{}
+	borrowed := st.borrowedUsers(teamID, time.Now())
+
+	for id, user := range st.users {
+		if (st.isMember(id, teamID) || borrowed[id]) && user.IsActive && !slices.Contains(excludeUserIDs, id) {
+			candidateIDs = append(candidateIDs, id)
+		}
+	}
+
+	slices.Sort(candidateIDs)
+
+	return candidateIDs
+}
+
+func (s *Store) CreatePR(_ context.Context, pr *domain.PullRequest) error {
+	const op = "internal.repository.memory.CreatePR"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.data.prs[pr.ID]; ok {
+		return &apperrors.PRAlreadyExistsError{PRID: pr.ID}
+	}
+
+	if _, ok := s.data.users[pr.AuthorID]; !ok {
+		return fmt.Errorf("%s: %w: author with id '%s' not found", op, apperrors.ErrNotFound, pr.AuthorID)
+	}
+
+	pr.CreatedAt = timestampOrNow(pr.CreatedAt)
+
+	s.data.prs[pr.ID] = domain.PullRequest{
+		ID:                pr.ID,
+		Name:              pr.Name,
+		AuthorID:          pr.AuthorID,
+		Status:            pr.Status,
+		Description:       pr.Description,
+		ExternalURL:       pr.ExternalURL,
+		CustomFields:      slices.Clone(pr.CustomFields),
+		AssignmentPolicy:  slices.Clone(pr.AssignmentPolicy),
+		AssignAt:          pr.AssignAt,
+		NeedMoreReviewers: pr.NeedMoreReviewers,
+		CreatedAt:         pr.CreatedAt,
+	}
+
+	return nil
+}
+
+func (s *Store) AssignReviewers(_ context.Context, prID string, reviewerIDs []string) error {
+	const op = "internal.repository.memory.AssignReviewers"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	assigned := slices.Clone(s.data.reviewers[prID])
+
+	for _, reviewerID := range reviewerIDs {
+		if err := s.checkAssignment(prID, assigned, reviewerID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		assigned = append(assigned, reviewerID)
+	}
+
+	s.data.reviewers[prID] = assigned
+
+	for _, reviewerID := range reviewerIDs {
+		s.data.setAssignedAt(prID, reviewerID)
+	}
+
+	return nil
+}
+
+// setAssignedAt records that the reviewer was assigned to the pull request just now.
+func (st *state) setAssignedAt(prID, reviewerID string) {
+	if st.assignedAt[prID] == nil {
+		st.assignedAt[prID] = make(map[string]time.Time)
+	}
+
+	st.assignedAt[prID][reviewerID] = timestampOrNow(time.Time{})
+}
+
+// checkAssignment mirrors the constraints of the postgres reviewers table: a reviewer is assigned
+// to a pull request at most once and never to their own pull request.
+func (s *Store) checkAssignment(prID string, assigned []string, reviewerID string) error {
+	if slices.Contains(assigned, reviewerID) {
+		return &apperrors.InvalidAssignmentError{PRID: prID, Reason: "reviewer is already assigned"}
+	}
+
+	if pr, ok := s.data.prs[prID]; ok && pr.AuthorID == reviewerID {
+		return &apperrors.InvalidAssignmentError{PRID: prID, Reason: "author cannot review their own pull request"}
+	}
+
+	return nil
+}
+
+func (s *Store) GetReviewerIDs(_ context.Context, prID string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.data.reviewers[prID]), nil
+}
+
+func (s *Store) GetReviews(_ context.Context, prID string) ([]domain.Review, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reviews := []domain.Review{}
+
+	for _, reviewerID := range s.data.reviewers[prID] {
+		review, ok := s.data.reviews[prID][reviewerID]
+		if !ok {
+			review = domain.Review{UserID: reviewerID, State: domain.ReviewPending}
+		}
+
+		reviews = append(reviews, review)
+	}
+
+	slices.SortFunc(reviews, func(a, b domain.Review) int {
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+
+	return reviews, nil
+}
+
+func (s *Store) SetReviewState(_ context.Context, prID string, reviewerID string, state domain.ReviewState, reviewedAt time.Time) error {
+	const op = "internal.repository.memory.SetReviewState"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !slices.Contains(s.data.reviewers[prID], reviewerID) {
+		return fmt.Errorf("%s: %w: user '%s' does not review PR '%s'", op, apperrors.ErrReviewerNotAssigned, reviewerID, prID)
+	}
+
+	if s.data.reviews[prID] == nil {
+		s.data.reviews[prID] = make(map[string]domain.Review)
+	}
+
+	reviewedAt = timestampOrNow(reviewedAt)
+	s.data.reviews[prID][reviewerID] = domain.Review{UserID: reviewerID, State: state, ReviewedAt: &reviewedAt}
+
+	if s.data.firstReviewedAt[prID] == nil {
+		s.data.firstReviewedAt[prID] = make(map[string]time.Time)
+	}
+
+	if _, ok := s.data.firstReviewedAt[prID][reviewerID]; !ok {
+		s.data.firstReviewedAt[prID][reviewerID] = reviewedAt
+	}
+
+	return nil
+}
+
+func (s *Store) BackfillReviewerDurations(_ context.Context, afterID string, limit int) (string, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := []string{}
+
+	for id := range s.data.prs {
+		if id > afterID {
+			ids = append(ids, id)
+		}
+	}
+
+	slices.Sort(ids)
+
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	if len(ids) == 0 {
+		return "", 0, nil
+	}
+
+	// latest holds the latest assignment of every reviewer of the batch in the history.
+	latest := make(map[string]map[string]time.Time, len(ids))
+	for _, id := range ids {
+		latest[id] = make(map[string]time.Time)
+	}
+
+	for _, record := range s.data.history {
+		assignments, ok := latest[record.PullRequestID]
+		if !ok {
+			continue
+		}
+
+		if at, ok := assignments[record.UserID]; !ok || record.CreatedAt.After(at) {
+			assignments[record.UserID] = record.CreatedAt
+		}
+	}
+
+	updated := 0
+
+	for _, prID := range ids {
+		for _, reviewerID := range s.data.reviewers[prID] {
+			assignedAt, ok := latest[prID][reviewerID]
+			if !ok {
+				assignedAt = s.data.prs[prID].CreatedAt
+			}
+
+			changed := !assignedAt.Equal(s.data.assignedAt[prID][reviewerID])
+			if changed {
+				if s.data.assignedAt[prID] == nil {
+					s.data.assignedAt[prID] = make(map[string]time.Time)
+				}
+
+				s.data.assignedAt[prID][reviewerID] = assignedAt
+			}
+
+			review := s.data.reviews[prID][reviewerID]
+			if _, ok := s.data.firstReviewedAt[prID][reviewerID]; !ok && review.ReviewedAt != nil {
+				if s.data.firstReviewedAt[prID] == nil {
+					s.data.firstReviewedAt[prID] = make(map[string]time.Time)
+				}
+
+				s.data.firstReviewedAt[prID][reviewerID] = *review.ReviewedAt
+				changed = true
+			}
+
+			if changed {
+				updated++
+			}
+		}
+	}
+
+	return ids[len(ids)-1], updated, nil
+}
+
+func (s *Store) GetPRByID(_ context.Context, prID string) (*domain.PullRequest, error) {
+	const op = "internal.repository.memory.GetPRByID"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pr, ok := s.data.prs[prID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w: PR with id '%s'", op, apperrors.ErrNotFound, prID)
+	}
+
+	return &pr, nil
+}
+
+func (s *Store) GetPRByIDWithReviewers(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	pr, err := s.GetPRByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	pr.ReviewerIDs, err = s.GetReviewerIDs(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	pr.Reviews, err = s.GetReviews(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+// GetPRByIDWithLock needs no row lock: the caller's transaction already excludes all others.
+func (s *Store) GetPRByIDWithLock(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	return s.GetPRByID(ctx, prID)
+}
+
+func (s *Store) UpdatePRStatus(_ context.Context, prID string, status api.PullRequestStatus, mergedAt time.Time) (*time.Time, error) {
+	const op = "internal.repository.memory.UpdatePRStatus"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pr, ok := s.data.prs[prID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w: PR with id '%s'", op, apperrors.ErrNotFound, prID)
+	}
+
+	pr.Status = status
+
+	switch status {
+	case api.PullRequestStatusMERGED:
+		mergedAt = timestampOrNow(mergedAt)
+		pr.MergedAt = &mergedAt
+		pr.NeedMoreReviewers = false
+	case api.PullRequestStatusCLOSED:
+		pr.NeedMoreReviewers = false
+	}
+
+	s.data.prs[prID] = pr
+
+	var storedMergedAt *time.Time
+	if pr.MergedAt != nil {
+		t := *pr.MergedAt
+		storedMergedAt = &t
+	}
+
+	return storedMergedAt, nil
+}
+
+func (s *Store) ReplaceReviewer(_ context.Context, prID string, oldReviewerID string, newReviewerID string) error {
+	const op = "internal.repository.memory.ReplaceReviewer"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.replaceReviewer(prID, oldReviewerID, newReviewerID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ReplaceReviewers removes all the old reviewers before adding the new ones, as the single postgres
+// statement does.
+func (s *Store) ReplaceReviewers(_ context.Context, replacements []domain.ReviewerReplacement) error {
+	const op = "internal.repository.memory.ReplaceReviewers"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The batch is checked against the reviewers it leaves before any change, so that a rejected one
+	// changes nothing, as a failed statement does not.
+	reviewerIDs := make(map[string][]string)
+	for _, replacement := range replacements {
+		prID := replacement.PullRequestID
+		if _, ok := reviewerIDs[prID]; !ok {
+			reviewerIDs[prID] = slices.Clone(s.data.reviewers[prID])
+		}
+
+		reviewerIDs[prID] = slices.DeleteFunc(reviewerIDs[prID], func(id string) bool {
+			return id == replacement.OldReviewerID
+		})
+	}
+
+	for _, replacement := range replacements {
+		prID := replacement.PullRequestID
+		if err := s.checkAssignment(prID, reviewerIDs[prID], replacement.NewReviewerID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		reviewerIDs[prID] = append(reviewerIDs[prID], replacement.NewReviewerID)
+	}
+
+	for _, replacement := range replacements {
+		s.removeReviewer(replacement.PullRequestID, replacement.OldReviewerID)
+	}
+
+	for _, replacement := range replacements {
+		s.addReviewer(replacement.PullRequestID, replacement.NewReviewerID)
+	}
+
+	return nil
+}
+
+// replaceReviewer must be called with s.mu held for writing.
+func (s *Store) replaceReviewer(prID string, oldReviewerID string, newReviewerID string) error {
+	reviewerIDs := slices.DeleteFunc(slices.Clone(s.data.reviewers[prID]), func(id string) bool {
+		return id == oldReviewerID
+	})
+
+	if err := s.checkAssignment(prID, reviewerIDs, newReviewerID); err != nil {
+		return err
+	}
+
+	s.removeReviewer(prID, oldReviewerID)
+	s.addReviewer(prID, newReviewerID)
+
+	return nil
+}
+
+// removeReviewer must be called with s.mu held for writing.
+func (s *Store) removeReviewer(prID string, reviewerID string) {
+	s.data.reviewers[prID] = slices.DeleteFunc(slices.Clone(s.data.reviewers[prID]), func(id string) bool {
+		return id == reviewerID
+	})
+	delete(s.data.reviews[prID], reviewerID)
+	delete(s.data.assignedAt[prID], reviewerID)
+	delete(s.data.firstReviewedAt[prID], reviewerID)
+}
+
+// addReviewer must be called with s.mu held for writing, after checkAssignment.
+func (s *Store) addReviewer(prID string, reviewerID string) {
+	s.data.reviewers[prID] = append(s.data.reviewers[prID], reviewerID)
+	s.data.setAssignedAt(prID, reviewerID)
+}
+
+func (s *Store) GetReviewAssignments(_ context.Context, userID string, customFields map[string]string) ([]domain.PullRequest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	prs := []domain.PullRequest{}
+
+	for prID, reviewerIDs := range s.data.reviewers {
+		if slices.Contains(reviewerIDs, userID) && customFieldsMatch(s.data.prs[prID].CustomFields, customFields) {
+			prs = append(prs, s.data.prs[prID])
+		}
+	}
+
+	slices.SortFunc(prs, func(a, b domain.PullRequest) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return prs, nil
+}
+
+// SearchPRs approximates the postgres full-text search: every query word not prefixed with a minus
+// must appear in the name or description, and a match in the name outranks a match in the description.
+func (s *Store) SearchPRs(_ context.Context, filter domain.PRSearchFilter) ([]domain.PullRequest, int, error) {
+	var include, exclude []string
+
+	for _, word := range searchWords(filter.Query) {
+		if excluded, ok := strings.CutPrefix(word, "-"); ok {
+			exclude = append(exclude, excluded)
+		} else {
+			include = append(include, word)
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type match struct {
+		pr   domain.PullRequest
+		rank int
+	}
+
+	var matches []match
+
+	for _, pr := range s.data.prs {
+		if filter.Status != "" && pr.Status != filter.Status || !customFieldsMatch(pr.CustomFields, filter.CustomFields) {
+			continue
+		}
+
+		nameWords := searchWords(pr.Name)
+		words := nameWords
+		if pr.Description != nil {
+			words = append(slices.Clone(nameWords), searchWords(*pr.Description)...)
+		}
+
+		matchesAll := len(include) > 0 && !slices.ContainsFunc(include, func(w string) bool { return !slices.Contains(words, w) })
+		if !matchesAll || slices.ContainsFunc(exclude, func(w string) bool { return slices.Contains(words, w) }) {
+			continue
+		}
+
+		// Name matches weigh more than description matches, like setweight 'A' and 'B' in postgres.
+		rank := 0
+		for i, word := range words {
+			if slices.Contains(include, word) {
+				if i < len(nameWords) {
+					rank += 4
+				} else {
+					rank++
+				}
+			}
+		}
+
+		matches = append(matches, match{pr: pr, rank: rank})
+	}
+
+	slices.SortFunc(matches, func(a, b match) int {
+		return cmp.Or(cmp.Compare(b.rank, a.rank), b.pr.CreatedAt.Compare(a.pr.CreatedAt), cmp.Compare(a.pr.ID, b.pr.ID))
+	})
+
+	prs := []domain.PullRequest{}
+	for _, m := range matches[min(filter.Offset, len(matches)):min(filter.Offset+filter.Limit, len(matches))] {
+		prs = append(prs, m.pr)
+	}
+
+	return prs, len(matches), nil
+}
+
+func (s *Store) ListPRs(_ context.Context, filter domain.PRListFilter) ([]domain.PullRequest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matches []domain.PullRequest
+
+	for _, pr := range s.data.prs {
+		switch {
+		case filter.Status != "" && pr.Status != filter.Status,
+			filter.AuthorID != "" && pr.AuthorID != filter.AuthorID,
+			filter.TeamID != 0 && s.data.users[pr.AuthorID].TeamID != filter.TeamID,
+			filter.CreatedFrom != nil && pr.CreatedAt.Before(*filter.CreatedFrom),
+			filter.CreatedTo != nil && !pr.CreatedAt.Before(*filter.CreatedTo),
+			filter.After != nil && comparePRPosition(pr, *filter.After) >= 0:
+			continue
+		}
+
+		matches = append(matches, pr)
+	}
+
+	slices.SortFunc(matches, func(a, b domain.PullRequest) int {
+		return comparePRPosition(b, domain.PRCursor{CreatedAt: a.CreatedAt, ID: a.ID})
+	})
+
+	prs := []domain.PullRequest{}
+	for _, pr := range matches[min(filter.Offset, len(matches)):min(filter.Offset+filter.Limit, len(matches))] {
+		pr.ReviewerIDs = append([]string{}, s.data.reviewers[pr.ID]...)
+		prs = append(prs, pr)
+	}
+
+	return prs, nil
+}
+
+// comparePRPosition orders pull requests by created_at and then by ID, like the row comparison in postgres;
+// the list runs in the reverse of this order.
+func comparePRPosition(pr domain.PullRequest, position domain.PRCursor) int {
+	return cmp.Or(pr.CreatedAt.Compare(position.CreatedAt), cmp.Compare(pr.ID, position.ID))
+}
+
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+}
+
+func (s *Store) GetUserStats(_ context.Context, period domain.StatsPeriod) ([]domain.Stats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.data.stats(func(domain.User) bool { return true }, period), nil
+}
+
+func (s *Store) GetOpenPRAgeStats(_ context.Context) ([]domain.OpenPRAgeStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now().UTC()
+	ages := make(map[string][]float64)
+
+	for _, pr := range s.data.prs {
+		if pr.Status != api.PullRequestStatusOPEN {
+			continue
+		}
+
+		teamName, ok := s.data.authorTeamName(pr)
+		if !ok {
+			continue
+		}
+
+		ages[teamName] = append(ages[teamName], now.Sub(pr.CreatedAt).Seconds())
+	}
+
+	stats := make([]domain.OpenPRAgeStats, 0, len(ages))
+
+	for teamName, teamAges := range ages {
+		slices.Sort(teamAges)
+
+		stats = append(stats, domain.OpenPRAgeStats{
+			TeamName:   teamName,
+			OpenPRs:    len(teamAges),
+			P50Seconds: percentile(teamAges, 0.5),
+			P90Seconds: percentile(teamAges, 0.9),
+			MaxSeconds: teamAges[len(teamAges)-1],
+		})
+	}
+
+	slices.SortFunc(stats, func(a, b domain.OpenPRAgeStats) int {
+		return cmp.Compare(a.TeamName, b.TeamName)
+	})
+
+	return stats, nil
+}
+
+func (s *Store) GetLeaderboard(_ context.Context, period domain.StatsPeriod, limit int) ([]domain.LeaderboardEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byUser := make(map[string]*domain.LeaderboardEntry)
+
+	for prID, reviewerIDs := range s.data.reviewers {
+		pr := s.data.prs[prID]
+		if pr.Status != api.PullRequestStatusMERGED || pr.MergedAt == nil || !inPeriod(pr.MergedAt, period) {
+			continue
+		}
+
+		for _, userID := range reviewerIDs {
+			user, ok := s.data.users[userID]
+			if !ok {
+				continue
+			}
+
+			entry, ok := byUser[userID]
+			if !ok {
+				entry = &domain.LeaderboardEntry{UserID: userID, Username: user.Username}
+				byUser[userID] = entry
+			}
+
+			entry.MergedReviews++
+		}
+	}
+
+	entries := make([]domain.LeaderboardEntry, 0, len(byUser))
+	for _, entry := range byUser {
+		entries = append(entries, *entry)
+	}
+
+	collator := newUsernameCollator()
+	slices.SortFunc(entries, func(a, b domain.LeaderboardEntry) int {
+		return cmp.Or(
+			cmp.Compare(b.MergedReviews, a.MergedReviews),
+			collator.CompareString(a.Username, b.Username),
+			cmp.Compare(a.UserID, b.UserID),
+		)
+	})
+
+	return entries[:min(limit, len(entries))], nil
+}
+
+// percentile interpolates linearly between the closest ranks of sorted values, like percentile_cont in PostgreSQL.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p * float64(len(sorted)-1)
+	lower := int(rank)
+
+	if lower+1 >= len(sorted) {
+		return sorted[lower]
+	}
+
+	return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+func (s *Store) GetStatsByUserIDs(_ context.Context, userIDs []string) ([]domain.Stats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.data.stats(func(user domain.User) bool {
+		return slices.Contains(userIDs, user.ID)
+	}, domain.StatsPeriod{}), nil
+}
+
+func (st *state) stats(include func(domain.User) bool, period domain.StatsPeriod) []domain.Stats {
+	byUser := make(map[string]*domain.Stats)
+	stats := []domain.Stats{}
+
+	for _, user := range st.users {
+		if include(user) {
+			byUser[user.ID] = &domain.Stats{UserID: user.ID, Username: user.Username}
+		}
+	}
+
+	firstReviews := make(map[string][]float64)
+	merges := make(map[string][]float64)
+
+	for prID, reviewerIDs := range st.reviewers {
+		pr := st.prs[prID]
+
+		for _, userID := range reviewerIDs {
+			firstReviewedAt, ok := st.firstReviewedAt[prID][userID]
+			if _, included := byUser[userID]; included && ok && inPeriod(&firstReviewedAt, period) {
+				firstReviews[userID] = append(firstReviews[userID], firstReviewedAt.Sub(st.assignedAt[prID][userID]).Seconds())
+			}
+		}
+
+		var at *time.Time
+
+		switch pr.Status {
+		case api.PullRequestStatusOPEN:
+			at = &pr.CreatedAt
+		case api.PullRequestStatusMERGED:
+			at = pr.MergedAt
+		}
+
+		if !inPeriod(at, period) {
+			continue
+		}
+
+		for _, userID := range reviewerIDs {
+			userStats, ok := byUser[userID]
+			if !ok {
+				continue
+			}
+
+			switch pr.Status {
+			case api.PullRequestStatusOPEN:
+				userStats.OpenReviews++
+			case api.PullRequestStatusMERGED:
+				userStats.MergedReviews++
+				merges[userID] = append(merges[userID], pr.MergedAt.Sub(st.assignedAt[prID][userID]).Seconds())
+			}
+		}
+	}
+
+	for userID, userStats := range byUser {
+		userStats.FirstReviews = len(firstReviews[userID])
+		userStats.FirstReviewAvgSeconds, userStats.FirstReviewP50Seconds, userStats.FirstReviewP90Seconds = durations(firstReviews[userID])
+		userStats.MergeAvgSeconds, userStats.MergeP50Seconds, userStats.MergeP90Seconds = durations(merges[userID])
+		stats = append(stats, *userStats)
+	}
+
+	collator := newUsernameCollator()
+	slices.SortFunc(stats, func(a, b domain.Stats) int {
+		return cmp.Or(collator.CompareString(a.Username, b.Username), cmp.Compare(a.UserID, b.UserID))
+	})
+
+	return stats
+}
+
+func (s *Store) GetTeamStats(_ context.Context, period domain.StatsPeriod) ([]domain.TeamStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.data.teamStats(period), nil
+}
+
+func (st *state) teamStats(period domain.StatsPeriod) []domain.TeamStats {
+	teamNames := make(map[string]bool)
+	firstReviews := make(map[string][]float64)
+	merges := make(map[string][]float64)
+
+	for _, pr := range st.prs {
+		teamName, ok := st.authorTeamName(pr)
+		if !ok {
+			continue
+		}
+
+		var firstReviewedAt *time.Time
+
+		for _, at := range st.firstReviewedAt[pr.ID] {
+			if firstReviewedAt == nil || at.Before(*firstReviewedAt) {
+				firstReviewedAt = &at
+			}
+		}
+
+		if firstReviewedAt != nil && inPeriod(firstReviewedAt, period) {
+			teamNames[teamName] = true
+			firstReviews[teamName] = append(firstReviews[teamName], firstReviewedAt.Sub(pr.CreatedAt).Seconds())
+		}
+
+		if pr.Status == api.PullRequestStatusMERGED && inPeriod(pr.MergedAt, period) {
+			teamNames[teamName] = true
+			merges[teamName] = append(merges[teamName], pr.MergedAt.Sub(pr.CreatedAt).Seconds())
+		}
+	}
+
+	stats := []domain.TeamStats{}
+
+	for _, teamName := range slices.Sorted(maps.Keys(teamNames)) {
+		teamStats := domain.TeamStats{
+			TeamName:         teamName,
+			FirstReviewedPRs: len(firstReviews[teamName]),
+			MergedPRs:        len(merges[teamName]),
+		}
+		teamStats.FirstReviewAvgSeconds, teamStats.FirstReviewP50Seconds, teamStats.FirstReviewP90Seconds = durations(firstReviews[teamName])
+		teamStats.MergeAvgSeconds, teamStats.MergeP50Seconds, teamStats.MergeP90Seconds = durations(merges[teamName])
+		stats = append(stats, teamStats)
+	}
+
+	return stats
+}
+
+// durations returns the average, median and 90th percentile of the values, or nils for no values,
+// like the aggregates in PostgreSQL.
+func durations(values []float64) (avg, p50, p90 *float64) {
+	if len(values) == 0 {
+		return nil, nil, nil
+	}
+
+	sorted := slices.Sorted(slices.Values(values))
+
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+
+	mean, median, high := sum/float64(len(sorted)), percentile(sorted, 0.5), percentile(sorted, 0.9)
+
+	return &mean, &median, &high
+}
+
+// inPeriod reports whether at falls within the period; an unbounded period contains any time, even a missing one.
+func inPeriod(at *time.Time, period domain.StatsPeriod) bool {
+	if period.From == nil && period.To == nil {
+		return true
+	}
+
+	return at != nil &&
+		(period.From == nil || !at.Before(*period.From)) &&
+		(period.To == nil || at.Before(*period.To))
+}
+
+func (s *Store) GetOpenPRsByReviewers(_ context.Context, userIDs []string) ([]domain.PullRequest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	prs := []domain.PullRequest{}
+
+	for prID, reviewerIDs := range s.data.reviewers {
+		pr := s.data.prs[prID]
+		if pr.Status != api.PullRequestStatusOPEN {
+			continue
+		}
+
+		if slices.ContainsFunc(reviewerIDs, func(id string) bool { return slices.Contains(userIDs, id) }) {
+			pr.ReviewerIDs = slices.Clone(reviewerIDs)
+			prs = append(prs, pr)
+		}
+	}
+
+	slices.SortFunc(prs, func(a, b domain.PullRequest) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return prs, nil
+}
+
+// CountOpenPRsByAuthor needs no lock: the caller's transaction already excludes all others.
+func (s *Store) CountOpenPRsByAuthor(_ context.Context, authorID string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+
+	for _, pr := range s.data.prs {
+		if pr.AuthorID == authorID && pr.Status == api.PullRequestStatusOPEN {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// LockActiveUsers needs no lock, as the transactions of the store run one at a time.
+func (s *Store) LockActiveUsers(_ context.Context, userIDs []string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	activeIDs := []string{}
+
+	for _, id := range userIDs {
+		if user, ok := s.data.users[id]; ok && user.IsActive {
+			activeIDs = append(activeIDs, id)
+		}
+	}
+
+	slices.Sort(activeIDs)
+
+	return slices.Compact(activeIDs), nil
+}
+
+func (s *Store) SetNeedMoreReviewers(_ context.Context, prID string, need bool) error {
+	const op = "internal.repository.memory.SetNeedMoreReviewers"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pr, ok := s.data.prs[prID]
+	if !ok {
+		return fmt.Errorf("%s: %w: PR with id '%s'", op, apperrors.ErrNotFound, prID)
+	}
+
+	pr.NeedMoreReviewers = need
+	s.data.prs[prID] = pr
+
+	return nil
+}
+
+// authorTeamName returns the name of the team of pr, the primary team of its author, the way GetPRTeamID does;
+// it reports false if the author or the team is deleted.
+func (st *state) authorTeamName(pr domain.PullRequest) (string, bool) {
+	author, ok := st.users[pr.AuthorID]
+	if !ok {
+		return "", false
+	}
+
+	team, ok := st.teams[author.TeamID]
+
+	return team.Name, ok
+}
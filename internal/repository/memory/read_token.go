@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) CreateReadToken(_ context.Context, token *domain.ReadToken) (*domain.ReadToken, error) {
+	var created domain.ReadToken
+
+	err := s.update(func(st *state) error {
+		st.nextReadTokenID++
+
+		created = *token
+		created.ID = st.nextReadTokenID
+		created.CreatedAt = timestampOrNow(token.CreatedAt)
+		created.LastUsedAt = nil
+		st.readTokens = append(st.readTokens, created)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (s *Store) GetReadTokenByHash(_ context.Context, hash string) (*domain.ReadToken, error) {
+	const op = "internal.repository.memory.GetReadTokenByHash"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := slices.IndexFunc(s.data.readTokens, func(t domain.ReadToken) bool { return t.TokenHash == hash })
+	if i < 0 {
+		return nil, fmt.Errorf("%s: %w: read token", op, apperrors.ErrNotFound)
+	}
+
+	token := s.data.readTokens[i]
+
+	return &token, nil
+}
+
+func (s *Store) ListReadTokens(_ context.Context) ([]domain.ReadToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]domain.ReadToken{}, s.data.readTokens...), nil
+}
+
+func (s *Store) TouchReadToken(_ context.Context, id int64, usedAt time.Time) error {
+	const op = "internal.repository.memory.TouchReadToken"
+
+	return s.update(func(st *state) error {
+		i := slices.IndexFunc(st.readTokens, func(t domain.ReadToken) bool { return t.ID == id })
+		if i < 0 {
+			return fmt.Errorf("%s: %w: read token %d", op, apperrors.ErrNotFound, id)
+		}
+
+		used := timestampOrNow(usedAt)
+		st.readTokens[i].LastUsedAt = &used
+
+		return nil
+	})
+}
+
+func (s *Store) DeleteReadToken(_ context.Context, id int64) error {
+	const op = "internal.repository.memory.DeleteReadToken"
+
+	return s.update(func(st *state) error {
+		i := slices.IndexFunc(st.readTokens, func(t domain.ReadToken) bool { return t.ID == id })
+		if i < 0 {
+			return fmt.Errorf("%s: %w: read token %d", op, apperrors.ErrNotFound, id)
+		}
+
+		st.readTokens = slices.Delete(st.readTokens, i, i+1)
+
+		return nil
+	})
+}
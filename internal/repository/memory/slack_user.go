@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) SetSlackUser(_ context.Context, mapping *domain.SlackUser) (*domain.SlackUser, error) {
+	const op = "internal.repository.memory.SetSlackUser"
+
+	stored := *mapping
+	stored.CreatedAt = timestampOrNow(mapping.CreatedAt)
+
+	err := s.update(func(st *state) error {
+		if _, ok := st.users[mapping.UserID]; !ok {
+			return fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, mapping.UserID)
+		}
+
+		st.slackUsers[stored.UserID] = stored
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+func (s *Store) GetSlackUser(_ context.Context, userID string) (*domain.SlackUser, error) {
+	const op = "internal.repository.memory.GetSlackUser"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mapping, ok := s.data.slackUsers[userID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w: slack user of '%s'", op, apperrors.ErrNotFound, userID)
+	}
+
+	return &mapping, nil
+}
+
+func (s *Store) ListSlackUsers(_ context.Context) ([]domain.SlackUser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mappings := make([]domain.SlackUser, 0, len(s.data.slackUsers))
+	for _, mapping := range s.data.slackUsers {
+		mappings = append(mappings, mapping)
+	}
+
+	slices.SortFunc(mappings, func(a, b domain.SlackUser) int {
+		return strings.Compare(a.UserID, b.UserID)
+	})
+
+	return mappings, nil
+}
+
+func (s *Store) DeleteSlackUser(_ context.Context, userID string) error {
+	const op = "internal.repository.memory.DeleteSlackUser"
+
+	return s.update(func(st *state) error {
+		if _, ok := st.slackUsers[userID]; !ok {
+			return fmt.Errorf("%s: %w: slack user of '%s'", op, apperrors.ErrNotFound, userID)
+		}
+
+		delete(st.slackUsers, userID)
+
+		return nil
+	})
+}
@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+// RefreshStatsView always refreshes, as the store lock keeps refreshes from running concurrently.
+func (s *Store) RefreshStatsView(_ context.Context) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.statsView = domain.StatsView{
+		Users:       s.data.stats(func(domain.User) bool { return true }, domain.StatsPeriod{}),
+		Teams:       s.data.teamStats(domain.StatsPeriod{}),
+		RefreshedAt: timestampOrNow(time.Time{}),
+	}
+
+	return true, nil
+}
+
+func (s *Store) GetStatsView(_ context.Context) (*domain.StatsView, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	view := s.data.statsView
+
+	return &view, nil
+}
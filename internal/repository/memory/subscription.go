@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) Subscribe(_ context.Context, prID string, userID string) (*domain.PRSubscription, bool, error) {
+	const op = "internal.repository.memory.Subscribe"
+
+	var (
+		sub     domain.PRSubscription
+		created bool
+	)
+
+	err := s.update(func(st *state) error {
+		if _, ok := st.prs[prID]; !ok {
+			return fmt.Errorf("%s: %w: pull request with id '%s'", op, apperrors.ErrNotFound, prID)
+		}
+
+		if _, ok := st.users[userID]; !ok {
+			return fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+		}
+
+		subs := st.subscriptions[prID]
+
+		i, found := slices.BinarySearchFunc(subs, userID, func(sub domain.PRSubscription, id string) int {
+			return strings.Compare(sub.UserID, id)
+		})
+		if found {
+			sub = subs[i]
+			return nil
+		}
+
+		sub = domain.PRSubscription{
+			PullRequestID: prID,
+			UserID:        userID,
+			CreatedAt:     timestampOrNow(time.Time{}),
+		}
+		st.subscriptions[prID] = slices.Insert(subs, i, sub)
+		created = true
+
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &sub, created, nil
+}
+
+func (s *Store) GetSubscriberIDs(_ context.Context, prID string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	userIDs := []string{}
+	for _, sub := range s.data.subscriptions[prID] {
+		userIDs = append(userIDs, sub.UserID)
+	}
+
+	return userIDs, nil
+}
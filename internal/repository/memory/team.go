@@ -0,0 +1,264 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// newUsernameCollator returns a collator ordering usernames like the "und-x-icu" collation of the postgres repository.
+// A collator is not safe for concurrent use, so each sort creates its own.
+func newUsernameCollator() *collate.Collator {
+	return collate.New(language.Und)
+}
+
+func (s *Store) CreateTeamWithUsers(_ context.Context, team api.Team) (*domain.TeamWithMembers, error) {
+	const op = "internal.repository.memory.CreateTeamWithUsers"
+	log := s.log.With(slog.String("op", op), slog.String("team_name", team.TeamName))
+	log.Info("creating team with users")
+
+	result := &domain.TeamWithMembers{
+		Name:    team.TeamName,
+		Members: make([]domain.User, len(team.Members)),
+	}
+
+	err := s.update(func(st *state) error {
+		if _, ok := st.teamByName(team.TeamName); ok {
+			return &apperrors.TeamAlreadyExistsError{TeamName: team.TeamName}
+		}
+
+		result.ID = st.nextTeamID
+		st.nextTeamID++
+		st.teams[result.ID] = domain.Team{ID: result.ID, Name: team.TeamName}
+
+		for i, member := range team.Members {
+			user := domain.User{
+				ID:       member.UserId,
+				Username: member.Username,
+				TeamID:   result.ID,
+				IsActive: member.IsActive,
+			}
+
+			// An existing user joins the team and keeps the primary team.
+			if existing, ok := st.users[user.ID]; ok {
+				user.TeamID = existing.TeamID
+			}
+
+			// Adding a deleted user to a team restores the user as a member of this team only.
+			if _, ok := st.deletedUsers[user.ID]; ok {
+				delete(st.deletedUsers, user.ID)
+				st.leaveTeams(user.ID)
+			}
+
+			st.users[user.ID] = user
+			st.memberships[membership{userID: user.ID, teamID: result.ID}] = struct{}{}
+			result.Members[i] = user
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("team created successfully", slog.Int("team_id", result.ID))
+
+	return result, nil
+}
+
+func (s *Store) GetTeamByName(_ context.Context, name string) (*domain.TeamWithMembers, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	team, ok := s.data.teamByName(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: team with name '%s'", apperrors.ErrNotFound, name)
+	}
+
+	return s.data.teamWithMembers(team), nil
+}
+
+func (s *Store) GetTeamByID(_ context.Context, id int) (*domain.TeamWithMembers, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	team, ok := s.data.teams[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: team with id %d", apperrors.ErrNotFound, id)
+	}
+
+	return s.data.teamWithMembers(team), nil
+}
+
+func (s *Store) RenameTeam(_ context.Context, id int, newName string) error {
+	return s.update(func(st *state) error {
+		team, ok := st.teams[id]
+		if !ok {
+			return fmt.Errorf("%w: team with id %d", apperrors.ErrNotFound, id)
+		}
+
+		if other, ok := st.teamByName(newName); ok && other.ID != id {
+			return &apperrors.TeamAlreadyExistsError{TeamName: newName}
+		}
+
+		team.Name = newName
+		st.teams[id] = team
+
+		return nil
+	})
+}
+
+// teamWithMembers returns the team along with its members ordered by username.
+func (st *state) teamWithMembers(team domain.Team) *domain.TeamWithMembers {
+	members := []domain.User{}
+	for _, user := range st.users {
+		if st.isMember(user.ID, team.ID) {
+			members = append(members, user)
+		}
+	}
+
+	collator := newUsernameCollator()
+	slices.SortFunc(members, func(a, b domain.User) int {
+		return collator.CompareString(a.Username, b.Username)
+	})
+
+	return &domain.TeamWithMembers{
+		ID:      team.ID,
+		Name:    team.Name,
+		Members: members,
+	}
+}
+
+func (s *Store) DeleteTeam(_ context.Context, teamID int, deletedAt time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	team, ok := s.data.teams[teamID]
+	if !ok {
+		return nil, fmt.Errorf("%w: team with id %d", apperrors.ErrNotFound, teamID)
+	}
+
+	deletedAt = timestampOrNow(deletedAt)
+
+	delete(s.data.teams, teamID)
+	s.data.deletedTeams[teamID] = deletedTeam{team: team, deletedAt: deletedAt}
+
+	deletedUserIDs := []string{}
+
+	// The members of other teams stay, and those of them whose primary team this was get another one.
+	for id, user := range s.data.users {
+		if !s.data.isMember(id, teamID) {
+			continue
+		}
+
+		if otherTeamIDs := s.data.otherTeamIDs(id, teamID); len(otherTeamIDs) > 0 {
+			if user.TeamID == teamID {
+				user.TeamID = slices.Min(otherTeamIDs)
+				s.data.users[id] = user
+			}
+
+			continue
+		}
+
+		delete(s.data.users, id)
+		s.data.deletedUsers[id] = deletedUser{user: user, deletedAt: deletedAt}
+		deletedUserIDs = append(deletedUserIDs, id)
+	}
+
+	slices.Sort(deletedUserIDs)
+
+	return deletedUserIDs, nil
+}
+
+func (s *Store) RestoreTeam(_ context.Context, teamID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted, ok := s.data.deletedTeams[teamID]
+	if !ok {
+		return fmt.Errorf("%w: deleted team with id %d", apperrors.ErrNotFound, teamID)
+	}
+
+	if _, ok := s.data.teamByName(deleted.team.Name); ok {
+		return &apperrors.TeamAlreadyExistsError{TeamName: deleted.team.Name}
+	}
+
+	delete(s.data.deletedTeams, teamID)
+	s.data.teams[teamID] = deleted.team
+
+	// The members deleted with the team share its deletion time; those deleted before it stay deleted.
+	for id, member := range s.data.deletedUsers {
+		if s.data.isMember(id, teamID) && member.deletedAt.Equal(deleted.deletedAt) {
+			delete(s.data.deletedUsers, id)
+			s.data.users[id] = member.user
+		}
+	}
+
+	return nil
+}
+
+// TryLockTeamForDeactivation always succeeds: the caller's transaction already excludes all others.
+func (s *Store) TryLockTeamForDeactivation(_ context.Context, _ int) (bool, error) {
+	return true, nil
+}
+
+func (st *state) isMember(userID string, teamID int) bool {
+	_, ok := st.memberships[membership{userID: userID, teamID: teamID}]
+
+	return ok
+}
+
+// otherTeamIDs returns the teams, not deleted, other than teamID that the user is a member of.
+func (st *state) otherTeamIDs(userID string, teamID int) []int {
+	var teamIDs []int
+
+	for m := range st.memberships {
+		if _, ok := st.teams[m.teamID]; ok && m.userID == userID && m.teamID != teamID {
+			teamIDs = append(teamIDs, m.teamID)
+		}
+	}
+
+	return teamIDs
+}
+
+// userTeamIDs returns the teams, not deleted, that the user is a member of, in ascending order.
+func (st *state) userTeamIDs(userID string) []int {
+	var teamIDs []int
+
+	for m := range st.memberships {
+		if _, ok := st.teams[m.teamID]; ok && m.userID == userID {
+			teamIDs = append(teamIDs, m.teamID)
+		}
+	}
+
+	slices.Sort(teamIDs)
+
+	return teamIDs
+}
+
+// leaveTeams removes all memberships of the user.
+func (st *state) leaveTeams(userID string) {
+	for m := range st.memberships {
+		if m.userID == userID {
+			delete(st.memberships, m)
+		}
+	}
+}
+
+func (st *state) teamByName(name string) (domain.Team, bool) {
+	for _, team := range st.teams {
+		if team.Name == name {
+			return team, true
+		}
+	}
+
+	return domain.Team{}, false
+}
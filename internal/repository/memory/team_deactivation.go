@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) RecordTeamDeactivation(_ context.Context, deactivation *domain.TeamDeactivation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deactivation.ID = int64(len(s.data.teamDeactivations) + 1)
+	deactivation.CreatedAt = timestampOrNow(deactivation.CreatedAt)
+
+	stored := *deactivation
+	stored.UserIDs = slices.Sorted(slices.Values(deactivation.UserIDs))
+	s.data.teamDeactivations = append(s.data.teamDeactivations, stored)
+
+	return nil
+}
+
+func (s *Store) GetLastTeamDeactivation(_ context.Context, teamID int) (*domain.TeamDeactivation, error) {
+	const op = "internal.repository.memory.GetLastTeamDeactivation"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := len(s.data.teamDeactivations) - 1; i >= 0; i-- {
+		deactivation := s.data.teamDeactivations[i]
+		if deactivation.TeamID == teamID && deactivation.ReactivatedAt == nil {
+			deactivation.UserIDs = slices.Clone(deactivation.UserIDs)
+			return &deactivation, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %w: deactivation of team %d", op, apperrors.ErrNotFound, teamID)
+}
+
+func (s *Store) MarkTeamReactivated(_ context.Context, id int64, reactivatedAt time.Time) error {
+	const op = "internal.repository.memory.MarkTeamReactivated"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id < 1 || id > int64(len(s.data.teamDeactivations)) {
+		return fmt.Errorf("%s: %w: team deactivation %d", op, apperrors.ErrNotFound, id)
+	}
+
+	at := timestampOrNow(reactivatedAt)
+	s.data.teamDeactivations[id-1].ReactivatedAt = &at
+
+	return nil
+}
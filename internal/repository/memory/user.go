@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+func (s *Store) SetIsActive(_ context.Context, userID string, isActive bool) (*api.User, bool, error) {
+	var (
+		result    *api.User
+		wasActive bool
+	)
+
+	err := s.update(func(st *state) error {
+		user, ok := st.users[userID]
+		if !ok {
+			return fmt.Errorf("%w: user with id '%s'", apperrors.ErrNotFound, userID)
+		}
+
+		wasActive = user.IsActive
+		user.IsActive = isActive
+		st.users[userID] = user
+
+		result = &api.User{
+			UserId:   user.ID,
+			Username: user.Username,
+			TeamName: st.teams[user.TeamID].Name,
+			TeamId:   user.TeamID,
+			IsActive: user.IsActive,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return result, wasActive, nil
+}
+
+func (s *Store) DeactivateUsersByTeamID(_ context.Context, teamID int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deactivatedUserIDs := []string{}
+
+	for id, user := range s.data.users {
+		// The members of other teams keep reviewing for them.
+		if s.data.isMember(id, teamID) && user.IsActive && len(s.data.otherTeamIDs(id, teamID)) == 0 {
+			user.IsActive = false
+			s.data.users[id] = user
+			deactivatedUserIDs = append(deactivatedUserIDs, id)
+		}
+	}
+
+	slices.Sort(deactivatedUserIDs)
+
+	return deactivatedUserIDs, nil
+}
+
+func (s *Store) ActivateUsers(_ context.Context, teamID int, userIDs []string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	activatedUserIDs := []string{}
+
+	for _, id := range userIDs {
+		if user, ok := s.data.users[id]; ok && s.data.isMember(id, teamID) && !user.IsActive {
+			user.IsActive = true
+			s.data.users[id] = user
+			activatedUserIDs = append(activatedUserIDs, id)
+		}
+	}
+
+	slices.Sort(activatedUserIDs)
+
+	return activatedUserIDs, nil
+}
+
+func (s *Store) SetUserRole(_ context.Context, userID string, role domain.UserRole) error {
+	return s.update(func(st *state) error {
+		if _, ok := st.users[userID]; !ok {
+			return fmt.Errorf("%w: user with id '%s'", apperrors.ErrNotFound, userID)
+		}
+
+		st.roles[userID] = role
+
+		return nil
+	})
+}
+
+func (s *Store) GetUserRole(_ context.Context, userID string) (domain.UserRole, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.data.users[userID]; !ok {
+		return "", fmt.Errorf("%w: user with id '%s'", apperrors.ErrNotFound, userID)
+	}
+
+	if role, ok := s.data.roles[userID]; ok {
+		return role, nil
+	}
+
+	return domain.RoleMember, nil
+}
+
+func (s *Store) TransferUser(_ context.Context, userID string, teamID int) (*api.User, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.data.users[userID]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: user with id '%s'", apperrors.ErrNotFound, userID)
+	}
+
+	team, ok := s.data.teams[teamID]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: team with id %d", apperrors.ErrNotFound, teamID)
+	}
+
+	previousTeamID := user.TeamID
+	user.TeamID = teamID
+	s.data.users[userID] = user
+
+	// The user leaves the previous team for the new one and stays in the other teams.
+	delete(s.data.memberships, membership{userID: userID, teamID: previousTeamID})
+	s.data.memberships[membership{userID: userID, teamID: teamID}] = struct{}{}
+
+	return &api.User{
+		UserId:   user.ID,
+		Username: user.Username,
+		TeamName: team.Name,
+		TeamId:   user.TeamID,
+		IsActive: user.IsActive,
+	}, previousTeamID, nil
+}
+
+func (s *Store) DeleteUser(_ context.Context, userID string, deletedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.data.users[userID]
+	if !ok {
+		return fmt.Errorf("%w: user with id '%s'", apperrors.ErrNotFound, userID)
+	}
+
+	delete(s.data.users, userID)
+	s.data.deletedUsers[userID] = deletedUser{user: user, deletedAt: timestampOrNow(deletedAt)}
+
+	return nil
+}
+
+func (s *Store) RestoreUser(_ context.Context, userID string) (*api.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted, ok := s.data.deletedUsers[userID]
+	if !ok {
+		return nil, fmt.Errorf("%w: deleted user with id '%s'", apperrors.ErrNotFound, userID)
+	}
+
+	team, ok := s.data.teams[deleted.user.TeamID]
+	if !ok {
+		return nil, fmt.Errorf("%w: deleted user with id '%s'", apperrors.ErrNotFound, userID)
+	}
+
+	delete(s.data.deletedUsers, userID)
+	s.data.users[userID] = deleted.user
+
+	return &api.User{
+		UserId:   deleted.user.ID,
+		Username: deleted.user.Username,
+		TeamName: team.Name,
+		TeamId:   deleted.user.TeamID,
+		IsActive: deleted.user.IsActive,
+	}, nil
+}
@@ -0,0 +1,278 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+func (s *Store) CreateWebhook(_ context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	var created domain.Webhook
+
+	err := s.update(func(st *state) error {
+		st.nextWebhookID++
+
+		created = *webhook
+		created.ID = st.nextWebhookID
+		created.Events = slices.Clone(webhook.Events)
+		created.CreatedAt = timestampOrNow(webhook.CreatedAt)
+		created.UpdatedAt = created.CreatedAt
+		st.webhooks = append(st.webhooks, created)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (s *Store) GetWebhook(_ context.Context, id int64) (*domain.Webhook, error) {
+	const op = "internal.repository.memory.GetWebhook"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := s.data.webhookIndex(id)
+	if i < 0 {
+		return nil, fmt.Errorf("%s: %w: webhook %d", op, apperrors.ErrNotFound, id)
+	}
+
+	webhook := s.data.webhooks[i]
+
+	return &webhook, nil
+}
+
+func (s *Store) ListWebhooks(_ context.Context) ([]domain.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Webhooks are appended with increasing IDs, so the slice is ordered by ID.
+	return slices.Clone(s.data.webhooks), nil
+}
+
+func (s *Store) UpdateWebhook(_ context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	const op = "internal.repository.memory.UpdateWebhook"
+
+	var updated domain.Webhook
+
+	err := s.update(func(st *state) error {
+		i := st.webhookIndex(webhook.ID)
+		if i < 0 {
+			return fmt.Errorf("%s: %w: webhook %d", op, apperrors.ErrNotFound, webhook.ID)
+		}
+
+		updated = st.webhooks[i]
+		updated.URL = webhook.URL
+		updated.Events = slices.Clone(webhook.Events)
+		updated.Secret = webhook.Secret
+		updated.UpdatedAt = timestampOrNow(webhook.UpdatedAt)
+		st.webhooks[i] = updated
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+func (s *Store) DeleteWebhook(_ context.Context, id int64) error {
+	const op = "internal.repository.memory.DeleteWebhook"
+
+	return s.update(func(st *state) error {
+		i := st.webhookIndex(id)
+		if i < 0 {
+			return fmt.Errorf("%s: %w: webhook %d", op, apperrors.ErrNotFound, id)
+		}
+
+		st.webhooks = slices.Delete(st.webhooks, i, i+1)
+		st.webhookDeliveries = slices.DeleteFunc(st.webhookDeliveries, func(d domain.WebhookDelivery) bool {
+			return d.WebhookID == id
+		})
+
+		return nil
+	})
+}
+
+func (s *Store) EnqueueWebhookDeliveries(_ context.Context, event *domain.WebhookEvent) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	occurredAt := timestampOrNow(event.OccurredAt)
+	queued := 0
+
+	for _, webhook := range s.data.webhooks {
+		if !slices.Contains(webhook.Events, event.Type) {
+			continue
+		}
+
+		s.data.nextWebhookDeliveryID++
+		s.data.webhookDeliveries = append(s.data.webhookDeliveries, domain.WebhookDelivery{
+			ID:            s.data.nextWebhookDeliveryID,
+			WebhookID:     webhook.ID,
+			Event:         event.Type,
+			PullRequestID: event.PullRequestID,
+			Payload:       slices.Clone(event.Payload),
+			Status:        domain.WebhookDeliveryPending,
+			NextAttemptAt: occurredAt,
+			CreatedAt:     occurredAt,
+			UpdatedAt:     occurredAt,
+		})
+		queued++
+	}
+
+	return queued, nil
+}
+
+func (s *Store) ClaimWebhookDeliveries(_ context.Context, limit int, now, leaseUntil time.Time) ([]domain.WebhookDelivery, error) {
+	claimed := []domain.WebhookDelivery{}
+
+	err := s.update(func(st *state) error {
+		due := []int{}
+
+		for i, d := range st.webhookDeliveries {
+			if d.Status == domain.WebhookDeliveryPending && !d.NextAttemptAt.After(now) {
+				due = append(due, i)
+			}
+		}
+
+		slices.SortStableFunc(due, func(a, b int) int {
+			return st.webhookDeliveries[a].NextAttemptAt.Compare(st.webhookDeliveries[b].NextAttemptAt)
+		})
+
+		for _, i := range due[:min(limit, len(due))] {
+			d := &st.webhookDeliveries[i]
+			d.Attempts++
+			d.NextAttemptAt = timestampOrNow(leaseUntil)
+			d.UpdatedAt = timestampOrNow(now)
+			claimed = append(claimed, *d)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(claimed, func(a, b domain.WebhookDelivery) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return claimed, nil
+}
+
+func (s *Store) FinishWebhookAttempt(_ context.Context, delivery *domain.WebhookDelivery) error {
+	const op = "internal.repository.memory.FinishWebhookAttempt"
+
+	return s.update(func(st *state) error {
+		i := st.webhookDeliveryIndex(delivery.ID)
+		if i < 0 {
+			return fmt.Errorf("%s: %w: webhook delivery %d", op, apperrors.ErrNotFound, delivery.ID)
+		}
+
+		stored := &st.webhookDeliveries[i]
+		stored.Status = delivery.Status
+		stored.NextAttemptAt = timestampOrNow(delivery.NextAttemptAt)
+		stored.LastError = delivery.LastError
+		stored.ResponseStatus = delivery.ResponseStatus
+		stored.UpdatedAt = timestampOrNow(delivery.UpdatedAt)
+
+		return nil
+	})
+}
+
+func (s *Store) GetWebhookDelivery(_ context.Context, id int64) (*domain.WebhookDelivery, error) {
+	const op = "internal.repository.memory.GetWebhookDelivery"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := s.data.webhookDeliveryIndex(id)
+	if i < 0 {
+		return nil, fmt.Errorf("%s: %w: webhook delivery %d", op, apperrors.ErrNotFound, id)
+	}
+
+	d := s.data.webhookDeliveries[i]
+
+	return &d, nil
+}
+
+func (s *Store) ListWebhookDeliveries(_ context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	deliveries := []domain.WebhookDelivery{}
+
+	for i := len(s.data.webhookDeliveries) - 1; i >= 0 && len(deliveries) < filter.Limit; i-- {
+		d := s.data.webhookDeliveries[i]
+
+		if filter.WebhookID != 0 && d.WebhookID != filter.WebhookID {
+			continue
+		}
+
+		if filter.Status != "" && d.Status != filter.Status {
+			continue
+		}
+
+		if filter.AfterID != 0 && d.ID >= filter.AfterID {
+			continue
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, nil
+}
+
+func (s *Store) RequeueWebhookDelivery(_ context.Context, id int64, dueAt time.Time) (*domain.WebhookDelivery, error) {
+	const op = "internal.repository.memory.RequeueWebhookDelivery"
+
+	var requeued domain.WebhookDelivery
+
+	err := s.update(func(st *state) error {
+		i := st.webhookDeliveryIndex(id)
+		if i < 0 {
+			return fmt.Errorf("%s: %w: webhook delivery %d", op, apperrors.ErrNotFound, id)
+		}
+
+		d := &st.webhookDeliveries[i]
+		if d.Status != domain.WebhookDeliveryDead {
+			return fmt.Errorf("%s: %w: webhook delivery %d", op, apperrors.ErrDeliveryNotDead, id)
+		}
+
+		d.Status = domain.WebhookDeliveryPending
+		d.Attempts = 0
+		d.NextAttemptAt = timestampOrNow(dueAt)
+		d.UpdatedAt = d.NextAttemptAt
+		requeued = *d
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &requeued, nil
+}
+
+// webhookIndex returns the position of the webhook with the given ID, or -1 if there is none.
+func (st *state) webhookIndex(id int64) int {
+	return slices.IndexFunc(st.webhooks, func(w domain.Webhook) bool {
+		return w.ID == id
+	})
+}
+
+// webhookDeliveryIndex returns the position of the delivery with the given ID, or -1 if there is none.
+func (st *state) webhookDeliveryIndex(id int64) int {
+	return slices.IndexFunc(st.webhookDeliveries, func(d domain.WebhookDelivery) bool {
+		return d.ID == id
+	})
+}
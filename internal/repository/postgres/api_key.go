@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type APIKeyRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewAPIKeyRepository(db *sqlx.DB, log *slog.Logger) *APIKeyRepository {
+	return &APIKeyRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// apiKeyColumns lists the api_keys columns that map onto apiKeyRow.
+var apiKeyColumns = []string{"id", "name", "key_hash", "scopes", "created_at", "expires_at", "last_used_at", "user_id"}
+
+// apiKeyRow is an API key as stored: the scopes are a text array.
+type apiKeyRow struct {
+	ID         int64          `db:"id"`
+	Name       string         `db:"name"`
+	KeyHash    string         `db:"key_hash"`
+	Scopes     pq.StringArray `db:"scopes"`
+	CreatedAt  time.Time      `db:"created_at"`
+	ExpiresAt  *time.Time     `db:"expires_at"`
+	LastUsedAt *time.Time     `db:"last_used_at"`
+	UserID     *string        `db:"user_id"`
+}
+
+func (r *apiKeyRow) toDomain() domain.APIKey {
+	scopes := make([]domain.APIKeyScope, len(r.Scopes))
+	for i, scope := range r.Scopes {
+		scopes[i] = domain.APIKeyScope(scope)
+	}
+
+	return domain.APIKey{
+		ID:         r.ID,
+		Name:       r.Name,
+		KeyHash:    r.KeyHash,
+		Scopes:     scopes,
+		CreatedAt:  r.CreatedAt,
+		ExpiresAt:  r.ExpiresAt,
+		LastUsedAt: r.LastUsedAt,
+		UserID:     r.UserID,
+	}
+}
+
+func apiKeyScopes(scopes []domain.APIKeyScope) any {
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = string(scope)
+	}
+
+	return pq.Array(names)
+}
+
+func (ar *APIKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	const op = "internal.repository.postgres.CreateAPIKey"
+
+	query, args, err := ar.sq.Insert("api_keys").
+		Columns("name", "key_hash", "scopes", "created_at", "expires_at", "user_id").
+		Values(key.Name, key.KeyHash, apiKeyScopes(key.Scopes), timestampOrNow(key.CreatedAt), key.ExpiresAt, key.UserID).
+		Suffix("RETURNING " + strings.Join(apiKeyColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var row apiKeyRow
+	if err := ar.db.GetContext(ctx, &row, query, args...); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return nil, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, *key.UserID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	created := row.toDomain()
+
+	return &created, nil
+}
+
+func (ar *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	const op = "internal.repository.postgres.GetAPIKeyByHash"
+
+	query, args, err := ar.sq.Select(apiKeyColumns...).
+		From("api_keys").
+		Where(sq.Eq{"key_hash": hash}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var row apiKeyRow
+	if err := ar.db.GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: api key", op, apperrors.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	key := row.toDomain()
+
+	return &key, nil
+}
+
+func (ar *APIKeyRepository) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	const op = "internal.repository.postgres.ListAPIKeys"
+
+	query, args, err := ar.sq.Select(apiKeyColumns...).
+		From("api_keys").
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var rows []apiKeyRow
+	if err := ar.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to list api keys: %w", op, err)
+	}
+
+	keys := make([]domain.APIKey, len(rows))
+	for i := range rows {
+		keys[i] = rows[i].toDomain()
+	}
+
+	return keys, nil
+}
+
+func (ar *APIKeyRepository) TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error {
+	const op = "internal.repository.postgres.TouchAPIKey"
+
+	query, args, err := ar.sq.Update("api_keys").
+		Set("last_used_at", usedAt.UTC()).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := ar.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%s: %w: api key %d", op, apperrors.ErrNotFound, id)
+	}
+
+	return nil
+}
+
+func (ar *APIKeyRepository) DeleteAPIKey(ctx context.Context, id int64) error {
+	const op = "internal.repository.postgres.DeleteAPIKey"
+
+	query, args, err := ar.sq.Delete("api_keys").
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build delete query: %w", op, err)
+	}
+
+	var deleted int64
+	if err := ar.db.GetContext(ctx, &deleted, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w: api key %d", op, apperrors.ErrNotFound, id)
+		}
+
+		return fmt.Errorf("%s: failed to execute delete: %w", op, err)
+	}
+
+	return nil
+}
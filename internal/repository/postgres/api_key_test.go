@@ -0,0 +1,84 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+
+	setupPRTest(t)
+	repo := NewAPIKeyRepository(testDB, logger)
+	ctx := context.Background()
+	createdAt := time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.AddDate(1, 0, 0)
+
+	ci, err := repo.CreateAPIKey(ctx, &domain.APIKey{
+		Name: "ci", KeyHash: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		Scopes: []domain.APIKeyScope{domain.ScopeRead, domain.ScopeWrite}, CreatedAt: createdAt, ExpiresAt: &expiresAt,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, ci.ID)
+	assert.Equal(t, []domain.APIKeyScope{domain.ScopeRead, domain.ScopeWrite}, ci.Scopes)
+	assert.Equal(t, createdAt, ci.CreatedAt.UTC())
+	require.NotNil(t, ci.ExpiresAt)
+	assert.Equal(t, expiresAt, ci.ExpiresAt.UTC())
+	assert.Nil(t, ci.LastUsedAt)
+
+	ops, err := repo.CreateAPIKey(ctx, &domain.APIKey{
+		Name: "ops", KeyHash: "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+		Scopes: []domain.APIKeyScope{domain.ScopeAdmin},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, ops.ExpiresAt)
+
+	found, err := repo.GetAPIKeyByHash(ctx, ops.KeyHash)
+	require.NoError(t, err)
+	assert.Equal(t, ops.ID, found.ID)
+	assert.Equal(t, []domain.APIKeyScope{domain.ScopeAdmin}, found.Scopes)
+
+	_, err = repo.GetAPIKeyByHash(ctx, "unknown")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	usedAt := createdAt.Add(time.Hour)
+	require.NoError(t, repo.TouchAPIKey(ctx, ci.ID, usedAt))
+	assert.ErrorIs(t, repo.TouchAPIKey(ctx, 42, usedAt), apperrors.ErrNotFound)
+
+	require.NoError(t, repo.DeleteAPIKey(ctx, ops.ID))
+	assert.ErrorIs(t, repo.DeleteAPIKey(ctx, ops.ID), apperrors.ErrNotFound)
+
+	keys, err := repo.ListAPIKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "ci", keys[0].Name)
+	require.NotNil(t, keys[0].LastUsedAt)
+	assert.Equal(t, usedAt, keys[0].LastUsedAt.UTC())
+
+	userID := "rev1"
+
+	bound, err := repo.CreateAPIKey(ctx, &domain.APIKey{
+		Name: "rev1", KeyHash: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		Scopes: []domain.APIKeyScope{domain.ScopeRead}, UserID: &userID,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, bound.UserID)
+	assert.Equal(t, userID, *bound.UserID)
+
+	ghost := "ghost"
+	_, err = repo.CreateAPIKey(ctx, &domain.APIKey{
+		Name: "ghost", KeyHash: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		Scopes: []domain.APIKeyScope{domain.ScopeRead}, UserID: &ghost,
+	})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
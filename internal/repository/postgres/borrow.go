@@ -0,0 +1,276 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+	"github.com/jmoiron/sqlx"
+)
+
+type BorrowRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewBorrowRepository(db *sqlx.DB, log *slog.Logger) *BorrowRepository {
+	return &BorrowRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+var borrowColumns = []string{
+	"b.id", "b.team_id", "t.name AS team_name", "b.lender_team_id", "lt.name AS lender_team_name",
+	"b.reviewer_count", "b.duration_hours", "b.status", "b.requested_at", "b.accepted_at", "b.expires_at",
+}
+
+func (br *BorrowRepository) borrowQuery() sq.SelectBuilder {
+	return br.sq.Select(borrowColumns...).
+		From("reviewer_borrows b").
+		Join("teams t ON t.id = b.team_id").
+		Join("teams lt ON lt.id = b.lender_team_id").
+		Where(sq.Eq{"t.deleted_at": nil, "lt.deleted_at": nil})
+}
+
+// teamPool matches the users that are picked as reviewers for a team: its members and the users
+// lent to it by an unexpired borrow. idColumn names the id column of users.
+func teamPool(idColumn string, teamID int) sq.Sqlizer {
+	return sq.Or{
+		memberOf(idColumn, teamID),
+		sq.Expr(idColumn+" IN (SELECT bu.user_id FROM borrowed_reviewers bu JOIN reviewer_borrows b ON b.id = bu.borrow_id"+
+			" WHERE b.team_id = ? AND b.expires_at > NOW())", teamID),
+	}
+}
+
+func (br *BorrowRepository) CreateBorrow(ctx context.Context, borrow *domain.ReviewerBorrow) (*domain.ReviewerBorrow, error) {
+	const op = "internal.repository.postgres.CreateBorrow"
+
+	query, args, err := br.sq.Insert("reviewer_borrows").
+		Columns("team_id", "lender_team_id", "reviewer_count", "duration_hours", "status").
+		Values(borrow.TeamID, borrow.LenderTeamID, borrow.Count, borrow.DurationHours, domain.BorrowRequested).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var id int64
+	if err := br.db.GetContext(ctx, &id, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	created, err := br.getBorrow(ctx, br.db, id, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return created, nil
+}
+
+func (br *BorrowRepository) AcceptBorrow(ctx context.Context, borrowID int64, acceptedAt time.Time) (*domain.ReviewerBorrow, error) {
+	const op = "internal.repository.postgres.AcceptBorrow"
+	log := br.log.With(slog.String("op", op), slog.Int64("borrow_id", borrowID))
+
+	tx, err := br.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Error("failed to rollback transaction", sl.Err(err))
+		}
+	}()
+
+	borrow, err := br.getBorrow(ctx, tx, borrowID, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if borrow.Status != domain.BorrowRequested {
+		return nil, fmt.Errorf("%s: %w: borrow %d is %s", op, apperrors.ErrBorrowNotPending, borrowID, borrow.Status)
+	}
+
+	reviewerIDs, err := br.lendableReviewers(ctx, tx, borrow.LenderTeamID, borrow.Count)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(reviewerIDs) == 0 {
+		return nil, fmt.Errorf("%s: %w: team '%s' has no active members to lend", op, apperrors.ErrInsufficientCapacity, borrow.LenderTeamName)
+	}
+
+	expiresAt := acceptedAt.Add(time.Duration(borrow.DurationHours) * time.Hour)
+
+	query, args, err := br.sq.Update("reviewer_borrows").
+		Set("status", domain.BorrowAccepted).
+		Set("accepted_at", acceptedAt.UTC()).
+		Set("expires_at", expiresAt.UTC()).
+		Where(sq.Eq{"id": borrowID}).
+		Suffix("RETURNING status, accepted_at, expires_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	if err := tx.QueryRowxContext(ctx, query, args...).Scan(&borrow.Status, &borrow.AcceptedAt, &borrow.ExpiresAt); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	insertBuilder := br.sq.Insert("borrowed_reviewers").Columns("borrow_id", "user_id")
+	for _, id := range reviewerIDs {
+		insertBuilder = insertBuilder.Values(borrowID, id)
+	}
+
+	query, args, err = insertBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	slices.Sort(reviewerIDs)
+	borrow.ReviewerIDs = reviewerIDs
+
+	log.Info("reviewer borrow accepted", slog.Any("reviewers", reviewerIDs))
+
+	return borrow, nil
+}
+
+func (br *BorrowRepository) ListBorrows(ctx context.Context, teamID int) ([]domain.ReviewerBorrow, error) {
+	const op = "internal.repository.postgres.ListBorrows"
+
+	query, args, err := br.borrowQuery().
+		Where(sq.Or{sq.Eq{"b.team_id": teamID}, sq.Eq{"b.lender_team_id": teamID}}).
+		Where(sq.Or{sq.Eq{"b.expires_at": nil}, sq.Expr("b.expires_at > NOW()")}).
+		OrderBy("b.id DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	borrows := []domain.ReviewerBorrow{}
+	if err := br.db.SelectContext(ctx, &borrows, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	if err := br.attachReviewers(ctx, br.db, borrows); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return borrows, nil
+}
+
+// getBorrow reads a borrow with its lent users; with lock set, the borrow row is locked for update.
+func (br *BorrowRepository) getBorrow(ctx context.Context, ext sqlx.ExtContext, borrowID int64, lock bool) (*domain.ReviewerBorrow, error) {
+	queryBuilder := br.borrowQuery().Where(sq.Eq{"b.id": borrowID})
+	if lock {
+		queryBuilder = queryBuilder.Suffix("FOR UPDATE OF b")
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build borrow query: %w", err)
+	}
+
+	var borrow domain.ReviewerBorrow
+	if err := sqlx.GetContext(ctx, ext, &borrow, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: reviewer borrow %d", apperrors.ErrNotFound, borrowID)
+		}
+
+		return nil, fmt.Errorf("failed to get borrow: %w", err)
+	}
+
+	borrows := []domain.ReviewerBorrow{borrow}
+	if err := br.attachReviewers(ctx, ext, borrows); err != nil {
+		return nil, err
+	}
+
+	return &borrows[0], nil
+}
+
+// attachReviewers fills in the lent users of the borrows.
+func (br *BorrowRepository) attachReviewers(ctx context.Context, ext sqlx.ExtContext, borrows []domain.ReviewerBorrow) error {
+	if len(borrows) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(borrows))
+	for i, borrow := range borrows {
+		ids[i] = borrow.ID
+	}
+
+	query, args, err := br.sq.Select("borrow_id", "user_id").
+		From("borrowed_reviewers").
+		Where(sq.Eq{"borrow_id": ids}).
+		OrderBy("user_id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build lent users query: %w", err)
+	}
+
+	var rows []struct {
+		BorrowID int64  `db:"borrow_id"`
+		UserID   string `db:"user_id"`
+	}
+	if err := sqlx.SelectContext(ctx, ext, &rows, query, args...); err != nil {
+		return fmt.Errorf("failed to get lent users: %w", err)
+	}
+
+	byBorrow := make(map[int64][]string, len(borrows))
+	for _, row := range rows {
+		byBorrow[row.BorrowID] = append(byBorrow[row.BorrowID], row.UserID)
+	}
+
+	for i := range borrows {
+		borrows[i].ReviewerIDs = byBorrow[borrows[i].ID]
+		if borrows[i].ReviewerIDs == nil {
+			borrows[i].ReviewerIDs = []string{}
+		}
+	}
+
+	return nil
+}
+
+// lendableReviewers picks up to count active members of the team, those with the fewest open reviews first.
+// Users the team has borrowed itself are not lent on.
+func (br *BorrowRepository) lendableReviewers(ctx context.Context, tx *sqlx.Tx, teamID int, count int) ([]string, error) {
+	query, args, err := br.sq.Select("u.id").
+		From("users u").
+		LeftJoin("reviewers r ON r.user_id = u.id").
+		LeftJoin("pull_requests pr ON pr.id = r.pull_request_id AND pr.status = 'OPEN'").
+		Where(memberOf("u.id", teamID)).
+		Where(sq.Eq{"u.is_active": true, "u.deleted_at": nil}).
+		GroupBy("u.id").
+		OrderBy("COUNT(pr.id)", "u.id").
+		Limit(uint64(count)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build lendable users query: %w", err)
+	}
+
+	reviewerIDs := []string{}
+	if err := tx.SelectContext(ctx, &reviewerIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get lendable users: %w", err)
+	}
+
+	return reviewerIDs, nil
+}
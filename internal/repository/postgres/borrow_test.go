@@ -0,0 +1,119 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBorrowRepository_RequestAcceptAndSelect(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	truncateTables(t, testDB)
+	ctx := context.Background()
+
+	teamRepo := NewTeamRepository(testDB, logger)
+	team, err := teamRepo.CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "backend",
+		Members: []api.TeamMember{
+			{UserId: "author", Username: "Author", IsActive: true},
+			{UserId: "rev1", Username: "Reviewer1", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	lender, err := teamRepo.CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "payments",
+		Members: []api.TeamMember{
+			{UserId: "pay1", Username: "Payments1", IsActive: true},
+			{UserId: "pay2-inactive", Username: "Payments2", IsActive: false},
+		},
+	})
+	require.NoError(t, err)
+
+	repo := NewBorrowRepository(testDB, logger)
+	prRepo := NewPullRequestRepository(testDB, logger)
+
+	borrow, err := repo.CreateBorrow(ctx, &domain.ReviewerBorrow{TeamID: team.ID, LenderTeamID: lender.ID, Count: 2, DurationHours: 1})
+	require.NoError(t, err)
+	assert.Equal(t, domain.BorrowRequested, borrow.Status)
+	assert.Equal(t, "payments", borrow.LenderTeamName)
+	assert.Empty(t, borrow.ReviewerIDs)
+
+	accepted, err := repo.AcceptBorrow(ctx, borrow.ID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, domain.BorrowAccepted, accepted.Status)
+	assert.Equal(t, []string{"pay1"}, accepted.ReviewerIDs)
+	require.NotNil(t, accepted.AcceptedAt)
+	require.NotNil(t, accepted.ExpiresAt)
+	assert.Equal(t, accepted.AcceptedAt.Add(time.Hour), *accepted.ExpiresAt)
+
+	reviewers, err := prRepo.GetRandomActiveReviewers(ctx, team.ID, []string{"author"}, 5)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"rev1", "pay1"}, reviewers)
+
+	reviewers, err = prRepo.GetLeastLoadedActiveReviewers(ctx, team.ID, []string{"author"}, 5)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"rev1", "pay1"}, reviewers)
+
+	_, err = repo.AcceptBorrow(ctx, borrow.ID, time.Now())
+	assert.ErrorIs(t, err, apperrors.ErrBorrowNotPending)
+
+	expired, err := repo.CreateBorrow(ctx, &domain.ReviewerBorrow{TeamID: lender.ID, LenderTeamID: team.ID, Count: 1, DurationHours: 1})
+	require.NoError(t, err)
+	_, err = repo.AcceptBorrow(ctx, expired.ID, time.Now().Add(-2*time.Hour))
+	require.NoError(t, err)
+
+	reviewers, err = prRepo.GetRandomActiveReviewers(ctx, lender.ID, nil, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pay1"}, reviewers, "expired borrow lends nobody")
+
+	borrows, err := repo.ListBorrows(ctx, lender.ID)
+	require.NoError(t, err)
+	require.Len(t, borrows, 1)
+	assert.Equal(t, borrow.ID, borrows[0].ID)
+	assert.Equal(t, []string{"pay1"}, borrows[0].ReviewerIDs)
+
+	_, err = repo.AcceptBorrow(ctx, 42, time.Now())
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestBorrowRepository_AcceptWithoutActiveMembers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	truncateTables(t, testDB)
+	ctx := context.Background()
+
+	teamRepo := NewTeamRepository(testDB, logger)
+	team, err := teamRepo.CreateTeamWithUsers(ctx, api.Team{TeamName: "backend"})
+	require.NoError(t, err)
+
+	lender, err := teamRepo.CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "payments",
+		Members:  []api.TeamMember{{UserId: "pay1", Username: "Payments1", IsActive: false}},
+	})
+	require.NoError(t, err)
+
+	repo := NewBorrowRepository(testDB, logger)
+
+	borrow, err := repo.CreateBorrow(ctx, &domain.ReviewerBorrow{TeamID: team.ID, LenderTeamID: lender.ID, Count: 1, DurationHours: 1})
+	require.NoError(t, err)
+
+	_, err = repo.AcceptBorrow(ctx, borrow.ID, time.Now())
+	assert.ErrorIs(t, err, apperrors.ErrInsufficientCapacity)
+
+	borrows, err := repo.ListBorrows(ctx, team.ID)
+	require.NoError(t, err)
+	require.Len(t, borrows, 1)
+	assert.Equal(t, domain.BorrowRequested, borrows[0].Status)
+}
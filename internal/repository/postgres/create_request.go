@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type CreatePRRequestRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewCreatePRRequestRepository(db *sqlx.DB, log *slog.Logger) *CreatePRRequestRepository {
+	return &CreatePRRequestRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+var createRequestColumns = []string{
+	"id", "pull_request_id", "pull_request_name", "author_id", "description", "external_url", "custom_fields", "assign_at", "status",
+	"attempts", "error_code", "error_message", "enqueued_at", "started_at", "finished_at",
+}
+
+func (cr *CreatePRRequestRepository) EnqueueCreatePR(ctx context.Context, req *domain.CreatePRRequest) (*domain.CreatePRRequest, error) {
+	const op = "internal.repository.postgres.EnqueueCreatePR"
+
+	query, args, err := cr.sq.Insert("pr_create_requests").
+		Columns("pull_request_id", "pull_request_name", "author_id", "description", "external_url", "custom_fields", "assign_at",
+			"status", "enqueued_at").
+		Values(req.PullRequestID, req.PullRequestName, req.AuthorID, req.Description, req.ExternalURL,
+			jsonObjectOrEmpty(req.CustomFields), req.AssignAt, domain.CreatePRQueued, timestampOrNow(req.EnqueuedAt)).
+		Suffix("RETURNING " + strings.Join(createRequestColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var created domain.CreatePRRequest
+	if err := cr.db.GetContext(ctx, &created, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return &created, nil
+}
+
+func (cr *CreatePRRequestRepository) ClaimCreatePRRequests(ctx context.Context, limit int, claimedAt time.Time, staleBefore time.Time) ([]domain.CreatePRRequest, error) {
+	const op = "internal.repository.postgres.ClaimCreatePRRequests"
+
+	// SKIP LOCKED lets concurrent workers claim disjoint batches instead of waiting for each other.
+	// The subquery keeps the default placeholders: the outer statement numbers them.
+	claimable := sq.Select("id").
+		From("pr_create_requests").
+		Where(sq.Or{
+			sq.Eq{"status": domain.CreatePRQueued},
+			sq.And{sq.Eq{"status": domain.CreatePRProcessing}, sq.Lt{"started_at": staleBefore.UTC()}},
+		}).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query, args, err := cr.sq.Update("pr_create_requests").
+		Set("status", domain.CreatePRProcessing).
+		Set("started_at", claimedAt.UTC()).
+		Set("attempts", sq.Expr("attempts + 1")).
+		Where(sq.Expr("id IN (?)", claimable)).
+		Suffix("RETURNING " + strings.Join(createRequestColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	claimed := []domain.CreatePRRequest{}
+	if err := cr.db.SelectContext(ctx, &claimed, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	// RETURNING does not keep the order of the subquery.
+	slices.SortFunc(claimed, func(a, b domain.CreatePRRequest) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return claimed, nil
+}
+
+func (cr *CreatePRRequestRepository) FinishCreatePRRequest(ctx context.Context, req *domain.CreatePRRequest) error {
+	const op = "internal.repository.postgres.FinishCreatePRRequest"
+
+	var finishedAt *time.Time
+	if req.FinishedAt != nil {
+		utc := req.FinishedAt.UTC()
+		finishedAt = &utc
+	}
+
+	query, args, err := cr.sq.Update("pr_create_requests").
+		Set("status", req.Status).
+		Set("error_code", req.ErrorCode).
+		Set("error_message", req.ErrorMessage).
+		Set("finished_at", finishedAt).
+		Where(sq.Eq{"id": req.ID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := cr.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%s: %w: pull request creation request %d", op, apperrors.ErrNotFound, req.ID)
+	}
+
+	return nil
+}
+
+func (cr *CreatePRRequestRepository) GetCreatePRRequest(ctx context.Context, id int64) (*domain.CreatePRRequest, error) {
+	const op = "internal.repository.postgres.GetCreatePRRequest"
+
+	query, args, err := cr.sq.Select(createRequestColumns...).
+		From("pr_create_requests").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var req domain.CreatePRRequest
+	if err := cr.db.GetContext(ctx, &req, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: pull request creation request %d", op, apperrors.ErrNotFound, id)
+		}
+
+		return nil, fmt.Errorf("%s: failed to get request: %w", op, err)
+	}
+
+	return &req, nil
+}
@@ -0,0 +1,75 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePRRequestRepository_Queue(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	truncateTables(t, testDB)
+	ctx := context.Background()
+	repo := NewCreatePRRequestRepository(testDB, logger)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	description := "Queued creation"
+	for _, prID := range []string{"pr-a", "pr-b", "pr-c"} {
+		req, err := repo.EnqueueCreatePR(ctx, &domain.CreatePRRequest{
+			PullRequestID: prID, PullRequestName: "Async " + prID, AuthorID: "author", Description: &description, EnqueuedAt: now,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, domain.CreatePRQueued, req.Status)
+		assert.True(t, now.Equal(req.EnqueuedAt))
+	}
+
+	claimed, err := repo.ClaimCreatePRRequests(ctx, 2, now, now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, []int64{1, 2}, []int64{claimed[0].ID, claimed[1].ID})
+	assert.Equal(t, domain.CreatePRProcessing, claimed[0].Status)
+	assert.Equal(t, 1, claimed[0].Attempts)
+	require.NotNil(t, claimed[0].Description)
+	assert.Equal(t, description, *claimed[0].Description)
+
+	code, message := "NOT_FOUND", "author not found or has no team"
+	finishedAt := now.Add(time.Second)
+	claimed[0].Status, claimed[0].ErrorCode, claimed[0].ErrorMessage, claimed[0].FinishedAt = domain.CreatePRFailed, &code, &message, &finishedAt
+	require.NoError(t, repo.FinishCreatePRRequest(ctx, &claimed[0]))
+
+	// Request 2 is still within its lease, so only request 3 is left to claim.
+	claimed, err = repo.ClaimCreatePRRequests(ctx, 5, now, now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, int64(3), claimed[0].ID)
+
+	later := now.Add(2 * time.Minute)
+	claimed, err = repo.ClaimCreatePRRequests(ctx, 5, later, later.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, int64(2), claimed[0].ID)
+	assert.Equal(t, 2, claimed[0].Attempts)
+
+	req, err := repo.GetCreatePRRequest(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CreatePRFailed, req.Status)
+	require.NotNil(t, req.ErrorCode)
+	assert.Equal(t, code, *req.ErrorCode)
+	require.NotNil(t, req.FinishedAt)
+	assert.True(t, finishedAt.Equal(*req.FinishedAt))
+
+	_, err = repo.GetCreatePRRequest(ctx, 42)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	err = repo.FinishCreatePRRequest(ctx, &domain.CreatePRRequest{ID: 42, Status: domain.CreatePRFailed})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
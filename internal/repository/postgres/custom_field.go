@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+	"github.com/jmoiron/sqlx"
+)
+
+type CustomFieldRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewCustomFieldRepository(db *sqlx.DB, log *slog.Logger) *CustomFieldRepository {
+	return &CustomFieldRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+var customFieldColumns = []string{"team_id", "key", "type", "required"}
+
+func (cr *CustomFieldRepository) GetCustomFields(ctx context.Context, teamID int) ([]domain.CustomField, error) {
+	const op = "internal.repository.postgres.GetCustomFields"
+
+	return cr.selectCustomFields(ctx, cr.db, op, teamID)
+}
+
+func (cr *CustomFieldRepository) ReplaceCustomFields(ctx context.Context, teamID int, fields []domain.CustomField) ([]domain.CustomField, error) {
+	const op = "internal.repository.postgres.ReplaceCustomFields"
+
+	tx, err := cr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			cr.log.Error("failed to rollback transaction", slog.String("op", op), sl.Err(err))
+		}
+	}()
+
+	// Locking the team serializes concurrent replacements of its fields.
+	lockQuery, args, err := cr.sq.Select("id").
+		From("teams").
+		Where(sq.Eq{"id": teamID, "deleted_at": nil}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build lock query: %w", op, err)
+	}
+
+	var lockedID int
+	if err := tx.GetContext(ctx, &lockedID, lockQuery, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: team with id '%d'", op, apperrors.ErrNotFound, teamID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to lock team: %w", op, err)
+	}
+
+	deleteQuery, args, err := cr.sq.Delete("team_custom_fields").
+		Where(sq.Eq{"team_id": teamID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build delete query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, deleteQuery, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute delete: %w", op, err)
+	}
+
+	if len(fields) > 0 {
+		insertBuilder := cr.sq.Insert("team_custom_fields").Columns(customFieldColumns...)
+		for _, field := range fields {
+			insertBuilder = insertBuilder.Values(teamID, field.Key, field.Type, field.Required)
+		}
+
+		insertQuery, args, err := insertBuilder.ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, insertQuery, args...); err != nil {
+			return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+		}
+	}
+
+	saved, err := cr.selectCustomFields(ctx, tx, op, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return saved, nil
+}
+
+func (cr *CustomFieldRepository) selectCustomFields(ctx context.Context, ext sqlx.ExtContext, op string, teamID int) ([]domain.CustomField, error) {
+	query, args, err := cr.sq.Select(customFieldColumns...).
+		From("team_custom_fields").
+		Where(sq.Eq{"team_id": teamID}).
+		OrderBy("key").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	fields := []domain.CustomField{}
+	if err := sqlx.SelectContext(ctx, ext, &fields, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,77 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomFieldRepository_ReplaceAndFilter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+
+	setupPRTest(t)
+	repo := NewCustomFieldRepository(testDB, logger)
+	prRepo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	teamID, err := prRepo.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	fields, err := repo.GetCustomFields(ctx, teamID)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+
+	saved, err := repo.ReplaceCustomFields(ctx, teamID, []domain.CustomField{
+		{Key: "story_points", Type: domain.CustomFieldNumber},
+		{Key: "risk", Type: domain.CustomFieldString, Required: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.CustomField{
+		{TeamID: teamID, Key: "risk", Type: domain.CustomFieldString, Required: true},
+		{TeamID: teamID, Key: "story_points", Type: domain.CustomFieldNumber},
+	}, saved)
+
+	saved, err = repo.ReplaceCustomFields(ctx, teamID, []domain.CustomField{{Key: "risk", Type: domain.CustomFieldString}})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.CustomField{{TeamID: teamID, Key: "risk", Type: domain.CustomFieldString}}, saved)
+
+	_, err = repo.ReplaceCustomFields(ctx, teamID+100, nil)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-high", Name: "Risky change", AuthorID: "author", Status: api.PullRequestStatusOPEN,
+		CustomFields: []byte(`{"risk":"high","story_points":3}`)}))
+	require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-low", Name: "Safe change", AuthorID: "author", Status: api.PullRequestStatusOPEN,
+		CustomFields: []byte(`{"risk":"low"}`)}))
+	require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-none", Name: "Plain change", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, prRepo.AssignReviewers(txctx.With(ctx, tx), "pr-high", []string{"rev1"}))
+	require.NoError(t, prRepo.AssignReviewers(txctx.With(ctx, tx), "pr-low", []string{"rev1"}))
+	require.NoError(t, tx.Commit())
+
+	prs, total, err := prRepo.SearchPRs(ctx, domain.PRSearchFilter{Query: "change", CustomFields: map[string]string{"risk": "high", "story_points": "3"}, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, prs, 1)
+	assert.Equal(t, "pr-high", prs[0].ID)
+	assert.JSONEq(t, `{"risk":"high","story_points":3}`, string(prs[0].CustomFields))
+
+	pr, err := prRepo.GetPRByID(ctx, "pr-none")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(pr.CustomFields))
+
+	assignments, err := prRepo.GetReviewAssignments(ctx, "rev1", map[string]string{"risk": "low"})
+	require.NoError(t, err)
+	require.Len(t, assignments, 1)
+	assert.Equal(t, "pr-low", assignments[0].ID)
+}
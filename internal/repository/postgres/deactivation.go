@@ -0,0 +1,286 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/jmoiron/sqlx"
+)
+
+type DeactivationJobRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewDeactivationJobRepository(db *sqlx.DB, log *slog.Logger) *DeactivationJobRepository {
+	return &DeactivationJobRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+var deactivationJobColumns = []string{
+	"j.id", "j.team_id", "t.name AS team_name", "j.status", "j.batch_size", "j.total_batches", "j.done_batches",
+	"j.total_prs", "j.reassigned_reviews", "j.created_at", "j.finished_at",
+}
+
+func (dr *DeactivationJobRepository) CreateDeactivationJob(ctx context.Context, job *domain.DeactivationJob, batches [][]string) (*domain.DeactivationJob, error) {
+	const op = "internal.repository.postgres.CreateDeactivationJob"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	totalPRs := 0
+	for _, batch := range batches {
+		totalPRs += len(batch)
+	}
+
+	status := domain.DeactivationJobRunning
+	var finishedAt any
+	if len(batches) == 0 {
+		status, finishedAt = domain.DeactivationJobSucceeded, sq.Expr("NOW()")
+	}
+
+	query, args, err := dr.sq.Insert("team_deactivation_jobs").
+		Columns("team_id", "status", "batch_size", "total_batches", "total_prs", "finished_at").
+		Values(job.TeamID, status, job.BatchSize, len(batches), totalPRs, finishedAt).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var id int64
+	if err := tx.GetContext(ctx, &id, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	if len(job.DeactivatedUserIDs) > 0 {
+		insertBuilder := dr.sq.Insert("team_deactivation_users").Columns("job_id", "user_id")
+		for _, userID := range job.DeactivatedUserIDs {
+			insertBuilder = insertBuilder.Values(id, userID)
+		}
+
+		if err := dr.exec(ctx, tx, insertBuilder); err != nil {
+			return nil, fmt.Errorf("%s: failed to insert users: %w", op, err)
+		}
+	}
+
+	if len(batches) > 0 {
+		batchBuilder := dr.sq.Insert("team_deactivation_batches").Columns("job_id", "batch_no")
+		prBuilder := dr.sq.Insert("team_deactivation_prs").Columns("job_id", "batch_no", "pull_request_id")
+
+		for batchNo, prIDs := range batches {
+			batchBuilder = batchBuilder.Values(id, batchNo+1)
+			for _, prID := range prIDs {
+				prBuilder = prBuilder.Values(id, batchNo+1, prID)
+			}
+		}
+
+		if err := dr.exec(ctx, tx, batchBuilder); err != nil {
+			return nil, fmt.Errorf("%s: failed to insert batches: %w", op, err)
+		}
+
+		if err := dr.exec(ctx, tx, prBuilder); err != nil {
+			return nil, fmt.Errorf("%s: failed to insert pull requests: %w", op, err)
+		}
+	}
+
+	created, err := dr.getJob(ctx, tx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return created, nil
+}
+
+func (dr *DeactivationJobRepository) ClaimDeactivationBatch(ctx context.Context) (*domain.DeactivationBatch, error) {
+	const op = "internal.repository.postgres.ClaimDeactivationBatch"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// SKIP LOCKED lets the workers claim different batches instead of waiting for each other.
+	query, args, err := dr.sq.Select("b.job_id", "b.batch_no", "j.team_id").
+		From("team_deactivation_batches b").
+		Join("team_deactivation_jobs j ON j.id = b.job_id").
+		Where(sq.Eq{"b.status": "pending"}).
+		OrderBy("b.job_id", "b.batch_no").
+		Limit(1).
+		Suffix("FOR UPDATE OF b SKIP LOCKED").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var batch domain.DeactivationBatch
+	if err := tx.GetContext(ctx, &batch, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: no pending deactivation batch", op, apperrors.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: failed to claim batch: %w", op, err)
+	}
+
+	query, args, err = dr.sq.Select("pull_request_id").
+		From("team_deactivation_prs").
+		Where(sq.Eq{"job_id": batch.JobID, "batch_no": batch.BatchNo}).
+		OrderBy("pull_request_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build pull requests query: %w", op, err)
+	}
+
+	if err := tx.SelectContext(ctx, &batch.PullRequestIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to get pull requests: %w", op, err)
+	}
+
+	batch.DeactivatedUserIDs, err = dr.deactivatedUsers(ctx, tx, batch.JobID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &batch, nil
+}
+
+func (dr *DeactivationJobRepository) FinishDeactivationBatch(ctx context.Context, batch *domain.DeactivationBatch, reassigned int, warnings []string, finishedAt time.Time) error {
+	const op = "internal.repository.postgres.FinishDeactivationBatch"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	finishedAt = finishedAt.UTC()
+
+	batchQuery := dr.sq.Update("team_deactivation_batches").
+		Set("status", "done").
+		Set("finished_at", finishedAt).
+		Where(sq.Eq{"job_id": batch.JobID, "batch_no": batch.BatchNo})
+
+	if err := dr.exec(ctx, tx, batchQuery); err != nil {
+		return fmt.Errorf("%s: failed to update batch: %w", op, err)
+	}
+
+	jobQuery := dr.sq.Update("team_deactivation_jobs").
+		Set("done_batches", sq.Expr("done_batches + 1")).
+		Set("reassigned_reviews", sq.Expr("reassigned_reviews + ?", reassigned)).
+		Set("status", sq.Expr("CASE WHEN done_batches + 1 >= total_batches THEN ? ELSE status END", domain.DeactivationJobSucceeded)).
+		Set("finished_at", sq.Expr("CASE WHEN done_batches + 1 >= total_batches THEN ?::timestamptz ELSE finished_at END", finishedAt)).
+		Where(sq.Eq{"id": batch.JobID})
+
+	if err := dr.exec(ctx, tx, jobQuery); err != nil {
+		return fmt.Errorf("%s: failed to update job: %w", op, err)
+	}
+
+	if len(warnings) > 0 {
+		insertBuilder := dr.sq.Insert("team_deactivation_warnings").Columns("job_id", "message")
+		for _, warning := range warnings {
+			insertBuilder = insertBuilder.Values(batch.JobID, warning)
+		}
+
+		if err := dr.exec(ctx, tx, insertBuilder); err != nil {
+			return fmt.Errorf("%s: failed to insert warnings: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (dr *DeactivationJobRepository) GetDeactivationJob(ctx context.Context, id int64) (*domain.DeactivationJob, error) {
+	const op = "internal.repository.postgres.GetDeactivationJob"
+
+	job, err := dr.getJob(ctx, dr.db, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return job, nil
+}
+
+// getJob reads a job with its deactivated users and warnings.
+func (dr *DeactivationJobRepository) getJob(ctx context.Context, ext sqlx.ExtContext, id int64) (*domain.DeactivationJob, error) {
+	query, args, err := dr.sq.Select(deactivationJobColumns...).
+		From("team_deactivation_jobs j").
+		Join("teams t ON t.id = j.team_id").
+		Where(sq.Eq{"j.id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build job query: %w", err)
+	}
+
+	var job domain.DeactivationJob
+	if err := sqlx.GetContext(ctx, ext, &job, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: team deactivation job %d", apperrors.ErrNotFound, id)
+		}
+
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	job.DeactivatedUserIDs, err = dr.deactivatedUsers(ctx, ext, id)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err = dr.sq.Select("message").
+		From("team_deactivation_warnings").
+		Where(sq.Eq{"job_id": id}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build warnings query: %w", err)
+	}
+
+	job.Warnings = []string{}
+	if err := sqlx.SelectContext(ctx, ext, &job.Warnings, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get warnings: %w", err)
+	}
+
+	return &job, nil
+}
+
+func (dr *DeactivationJobRepository) deactivatedUsers(ctx context.Context, ext sqlx.ExtContext, jobID int64) ([]string, error) {
+	query, args, err := dr.sq.Select("user_id").
+		From("team_deactivation_users").
+		Where(sq.Eq{"job_id": jobID}).
+		OrderBy("user_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build users query: %w", err)
+	}
+
+	userIDs := []string{}
+	if err := sqlx.SelectContext(ctx, ext, &userIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get deactivated users: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+func (dr *DeactivationJobRepository) exec(ctx context.Context, tx *sqlx.Tx, builder sq.Sqlizer) error {
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeactivationJobRepository_Batches(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	ctx := context.Background()
+
+	prRepo := NewPullRequestRepository(testDB, logger)
+	repo := NewDeactivationJobRepository(testDB, logger)
+
+	teamID, err := prRepo.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	for _, prID := range []string{"pr-1", "pr-2", "pr-3"} {
+		require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{
+			ID: prID, Name: "PR " + prID, AuthorID: "author", Status: api.PullRequestStatusOPEN,
+		}))
+	}
+
+	job, err := repo.CreateDeactivationJob(txctx.With(ctx, tx), &domain.DeactivationJob{
+		TeamID: teamID, BatchSize: 2, DeactivatedUserIDs: []string{"rev2", "rev1"},
+	}, [][]string{{"pr-1", "pr-2"}, {"pr-3"}})
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	assert.Equal(t, "pr-team", job.TeamName)
+	assert.Equal(t, domain.DeactivationJobRunning, job.Status)
+	assert.Equal(t, 2, job.TotalBatches)
+	assert.Equal(t, 3, job.TotalPRs)
+	assert.Equal(t, []string{"rev1", "rev2"}, job.DeactivatedUserIDs)
+	assert.Nil(t, job.FinishedAt)
+
+	finishedAt := time.Now().UTC().Truncate(time.Microsecond)
+
+	for batchNo, prIDs := range [][]string{{"pr-1", "pr-2"}, {"pr-3"}} {
+		tx, err := testDB.Beginx()
+		require.NoError(t, err)
+
+		batch, err := repo.ClaimDeactivationBatch(txctx.With(ctx, tx))
+		require.NoError(t, err)
+		assert.Equal(t, job.ID, batch.JobID)
+		assert.Equal(t, batchNo+1, batch.BatchNo)
+		assert.Equal(t, teamID, batch.TeamID)
+		assert.Equal(t, prIDs, batch.PullRequestIDs)
+		assert.Equal(t, []string{"rev1", "rev2"}, batch.DeactivatedUserIDs)
+
+		var warnings []string
+		if batchNo == 1 {
+			warnings = []string{"pull request 'pr-3': no active replacement for reviewer 'rev1'"}
+		}
+
+		require.NoError(t, repo.FinishDeactivationBatch(txctx.With(ctx, tx), batch, len(prIDs), warnings, finishedAt))
+		require.NoError(t, tx.Commit())
+
+		if batchNo == 0 {
+			got, err := repo.GetDeactivationJob(ctx, job.ID)
+			require.NoError(t, err)
+			assert.Equal(t, domain.DeactivationJobRunning, got.Status)
+			assert.Equal(t, 1, got.DoneBatches)
+		}
+	}
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+	_, err = repo.ClaimDeactivationBatch(txctx.With(ctx, tx))
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	require.NoError(t, tx.Rollback())
+
+	got, err := repo.GetDeactivationJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeactivationJobSucceeded, got.Status)
+	assert.Equal(t, 2, got.DoneBatches)
+	assert.Equal(t, 3, got.ReassignedReviews)
+	assert.Equal(t, []string{"pull request 'pr-3': no active replacement for reviewer 'rev1'"}, got.Warnings)
+	require.NotNil(t, got.FinishedAt)
+	assert.True(t, finishedAt.Equal(*got.FinishedAt))
+
+	_, err = repo.GetDeactivationJob(ctx, 42)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestDeactivationJobRepository_NoBatches(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	ctx := context.Background()
+
+	teamID, err := NewPullRequestRepository(testDB, logger).GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	repo := NewDeactivationJobRepository(testDB, logger)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	job, err := repo.CreateDeactivationJob(txctx.With(ctx, tx), &domain.DeactivationJob{TeamID: teamID, BatchSize: 10}, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	assert.Equal(t, domain.DeactivationJobSucceeded, job.Status)
+	assert.NotNil(t, job.FinishedAt)
+	assert.Empty(t, job.DeactivatedUserIDs)
+	assert.Equal(t, []string{}, job.Warnings)
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type FreezeWindowRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewFreezeWindowRepository(db *sqlx.DB, log *slog.Logger) *FreezeWindowRepository {
+	return &FreezeWindowRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (fr *FreezeWindowRepository) freezeQuery() sq.SelectBuilder {
+	return fr.sq.Select(
+		"fw.id", "fw.team_id", "t.name AS team_name", "fw.reason", "fw.starts_at", "fw.ends_at", "fw.created_at",
+	).
+		From("freeze_windows fw").
+		LeftJoin("teams t ON t.id = fw.team_id").
+		Where(sq.Or{sq.Eq{"fw.team_id": nil}, sq.Eq{"t.deleted_at": nil}})
+}
+
+func (fr *FreezeWindowRepository) CreateFreezeWindow(ctx context.Context, window *domain.FreezeWindow) (*domain.FreezeWindow, error) {
+	const op = "internal.repository.postgres.CreateFreezeWindow"
+
+	query, args, err := fr.sq.Insert("freeze_windows").
+		Columns("team_id", "reason", "starts_at", "ends_at", "created_at").
+		Values(window.TeamID, window.Reason, window.StartsAt, window.EndsAt, timestampOrNow(window.CreatedAt)).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var id int64
+	if err := fr.db.GetContext(ctx, &id, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	query, args, err = fr.freezeQuery().Where(sq.Eq{"fw.id": id}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var created domain.FreezeWindow
+	if err := fr.db.GetContext(ctx, &created, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to get created freeze window: %w", op, err)
+	}
+
+	return &created, nil
+}
+
+func (fr *FreezeWindowRepository) ListFreezeWindows(ctx context.Context, filter domain.FreezeWindowFilter) ([]domain.FreezeWindow, error) {
+	const op = "internal.repository.postgres.ListFreezeWindows"
+
+	builder := fr.freezeQuery().OrderBy("fw.starts_at", "fw.id")
+
+	if filter.TeamID != nil {
+		builder = builder.Where(sq.Or{sq.Eq{"fw.team_id": nil}, sq.Eq{"fw.team_id": *filter.TeamID}})
+	}
+
+	if filter.ActiveAt != nil {
+		builder = builder.Where(sq.LtOrEq{"fw.starts_at": *filter.ActiveAt}).Where(sq.Gt{"fw.ends_at": *filter.ActiveAt})
+	}
+
+	if filter.EndsAfter != nil {
+		builder = builder.Where(sq.Gt{"fw.ends_at": *filter.EndsAfter})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	windows := []domain.FreezeWindow{}
+	if err := fr.db.SelectContext(ctx, &windows, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to list freeze windows: %w", op, err)
+	}
+
+	return windows, nil
+}
+
+func (fr *FreezeWindowRepository) DeleteFreezeWindow(ctx context.Context, id int64) error {
+	const op = "internal.repository.postgres.DeleteFreezeWindow"
+
+	query, args, err := fr.sq.Delete("freeze_windows").
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build delete query: %w", op, err)
+	}
+
+	var deleted int64
+	if err := fr.db.GetContext(ctx, &deleted, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w: freeze window %d", op, apperrors.ErrNotFound, id)
+		}
+
+		return fmt.Errorf("%s: failed to execute delete: %w", op, err)
+	}
+
+	return nil
+}
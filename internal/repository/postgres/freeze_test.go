@@ -0,0 +1,73 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreezeWindowRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+
+	setupPRTest(t)
+	repo := NewFreezeWindowRepository(testDB, logger)
+	ctx := context.Background()
+	start := time.Date(2025, time.December, 22, 18, 0, 0, 0, time.UTC)
+
+	team, err := NewTeamRepository(testDB, logger).GetTeamByName(ctx, "pr-team")
+	require.NoError(t, err)
+
+	otherTeamID := team.ID + 1
+
+	teamWindow, err := repo.CreateFreezeWindow(ctx, &domain.FreezeWindow{
+		TeamID: &team.ID, Reason: "release", StartsAt: start.Add(time.Hour), EndsAt: start.Add(2 * time.Hour), CreatedAt: start,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), teamWindow.ID)
+	require.NotNil(t, teamWindow.TeamName)
+	assert.Equal(t, "pr-team", *teamWindow.TeamName)
+	assert.Equal(t, start.Add(2*time.Hour), teamWindow.EndsAt.UTC())
+
+	orgWindow, err := repo.CreateFreezeWindow(ctx, &domain.FreezeWindow{
+		Reason: "holidays", StartsAt: start, EndsAt: start.Add(3 * time.Hour), CreatedAt: start,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, orgWindow.TeamID)
+	assert.Nil(t, orgWindow.TeamName)
+
+	_, err = repo.CreateFreezeWindow(ctx, &domain.FreezeWindow{Reason: "backwards", StartsAt: start, EndsAt: start, CreatedAt: start})
+	assert.Error(t, err, "a window must end after it starts")
+
+	all, err := repo.ListFreezeWindows(ctx, domain.FreezeWindowFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, []int64{orgWindow.ID, teamWindow.ID}, []int64{all[0].ID, all[1].ID}, "windows are ordered by start")
+
+	activeAt := start.Add(90 * time.Minute)
+	active, err := repo.ListFreezeWindows(ctx, domain.FreezeWindowFilter{TeamID: &otherTeamID, ActiveAt: &activeAt})
+	require.NoError(t, err)
+	require.Len(t, active, 1, "another team is only subject to the organization window")
+	assert.Equal(t, orgWindow.ID, active[0].ID)
+
+	active, err = repo.ListFreezeWindows(ctx, domain.FreezeWindowFilter{TeamID: &team.ID, ActiveAt: &activeAt})
+	require.NoError(t, err)
+	assert.Len(t, active, 2)
+
+	endOfTeamWindow := start.Add(2 * time.Hour)
+	upcoming, err := repo.ListFreezeWindows(ctx, domain.FreezeWindowFilter{EndsAfter: &endOfTeamWindow})
+	require.NoError(t, err)
+	require.Len(t, upcoming, 1, "a window does not include its end")
+	assert.Equal(t, orgWindow.ID, upcoming[0].ID)
+
+	require.NoError(t, repo.DeleteFreezeWindow(ctx, orgWindow.ID))
+	assert.ErrorIs(t, repo.DeleteFreezeWindow(ctx, orgWindow.ID), apperrors.ErrNotFound)
+}
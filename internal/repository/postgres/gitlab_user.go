@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type GitLabUserRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewGitLabUserRepository(db *sqlx.DB, log *slog.Logger) *GitLabUserRepository {
+	return &GitLabUserRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (gr *GitLabUserRepository) SetGitLabUser(ctx context.Context, mapping *domain.GitLabUser) (*domain.GitLabUser, error) {
+	const op = "internal.repository.postgres.SetGitLabUser"
+
+	query, args, err := gr.sq.Insert("gitlab_users").
+		Columns("gitlab_username", "user_id", "created_at").
+		Values(mapping.GitLabUsername, mapping.UserID, timestampOrNow(mapping.CreatedAt)).
+		Suffix(`ON CONFLICT (gitlab_username) DO UPDATE SET user_id = EXCLUDED.user_id, created_at = EXCLUDED.created_at
+			RETURNING gitlab_username, user_id, created_at`).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var stored domain.GitLabUser
+	if err := gr.db.QueryRowxContext(ctx, query, args...).StructScan(&stored); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return nil, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, mapping.UserID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return &stored, nil
+}
+
+func (gr *GitLabUserRepository) GetGitLabUser(ctx context.Context, gitLabUsername string) (*domain.GitLabUser, error) {
+	const op = "internal.repository.postgres.GetGitLabUser"
+
+	query, args, err := gr.sq.Select("gitlab_username", "user_id", "created_at").
+		From("gitlab_users").
+		Where(sq.Eq{"gitlab_username": gitLabUsername}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var mapping domain.GitLabUser
+	if err := gr.db.GetContext(ctx, &mapping, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: gitlab user '%s'", op, apperrors.ErrNotFound, gitLabUsername)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &mapping, nil
+}
+
+func (gr *GitLabUserRepository) ListGitLabUsers(ctx context.Context) ([]domain.GitLabUser, error) {
+	const op = "internal.repository.postgres.ListGitLabUsers"
+
+	query, args, err := gr.sq.Select("gitlab_username", "user_id", "created_at").
+		From("gitlab_users").
+		OrderBy("gitlab_username").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	mappings := []domain.GitLabUser{}
+	if err := gr.db.SelectContext(ctx, &mappings, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to list gitlab users: %w", op, err)
+	}
+
+	return mappings, nil
+}
+
+func (gr *GitLabUserRepository) DeleteGitLabUser(ctx context.Context, gitLabUsername string) error {
+	const op = "internal.repository.postgres.DeleteGitLabUser"
+
+	query, args, err := gr.sq.Delete("gitlab_users").
+		Where(sq.Eq{"gitlab_username": gitLabUsername}).
+		Suffix("RETURNING gitlab_username").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build delete query: %w", op, err)
+	}
+
+	var deleted string
+	if err := gr.db.GetContext(ctx, &deleted, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w: gitlab user '%s'", op, apperrors.ErrNotFound, gitLabUsername)
+		}
+
+		return fmt.Errorf("%s: failed to execute delete: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitLabUserRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+
+	setupPRTest(t)
+	repo := NewGitLabUserRepository(testDB, logger)
+	ctx := context.Background()
+	createdAt := time.Date(2025, time.December, 22, 18, 0, 0, 0, time.UTC)
+
+	stored, err := repo.SetGitLabUser(ctx, &domain.GitLabUser{GitLabUsername: "jdoe", UserID: "author", CreatedAt: createdAt})
+	require.NoError(t, err)
+	assert.Equal(t, "author", stored.UserID)
+	assert.Equal(t, createdAt, stored.CreatedAt.UTC())
+
+	_, err = repo.SetGitLabUser(ctx, &domain.GitLabUser{GitLabUsername: "alice", UserID: "rev1"})
+	require.NoError(t, err)
+
+	remapped, err := repo.SetGitLabUser(ctx, &domain.GitLabUser{GitLabUsername: "jdoe", UserID: "rev2"})
+	require.NoError(t, err)
+	assert.Equal(t, "rev2", remapped.UserID, "a username is mapped to one user at a time")
+
+	_, err = repo.SetGitLabUser(ctx, &domain.GitLabUser{GitLabUsername: "ghost", UserID: "no-such-user"})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	mapping, err := repo.GetGitLabUser(ctx, "jdoe")
+	require.NoError(t, err)
+	assert.Equal(t, "rev2", mapping.UserID)
+
+	_, err = repo.GetGitLabUser(ctx, "ghost")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	all, err := repo.ListGitLabUsers(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, []string{"alice", "jdoe"}, []string{all[0].GitLabUsername, all[1].GitLabUsername})
+
+	require.NoError(t, repo.DeleteGitLabUser(ctx, "jdoe"))
+	assert.ErrorIs(t, repo.DeleteGitLabUser(ctx, "jdoe"), apperrors.ErrNotFound)
+}
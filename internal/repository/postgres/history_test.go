@@ -0,0 +1,181 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignmentHistoryRepository_RecordAssignments(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	ctx := context.Background()
+
+	prRepo := NewPullRequestRepository(testDB, logger)
+	repo := NewAssignmentHistoryRepository(testDB, logger)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{
+		ID: "pr-history", Name: "History PR", AuthorID: "author", Status: api.PullRequestStatusOPEN,
+	}))
+
+	replaced := "rev1"
+	require.NoError(t, repo.RecordAssignments(txctx.With(ctx, tx), []domain.AssignmentRecord{
+		{PullRequestID: "pr-history", UserID: "rev1", Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom},
+		{PullRequestID: "pr-history", UserID: "rev2", ReplacedUserID: &replaced, Strategy: domain.StrategyLeastLoaded, Reason: domain.ReasonLeastLoaded},
+	}))
+	require.NoError(t, repo.RecordAssignments(txctx.With(ctx, tx), nil))
+	require.NoError(t, tx.Commit())
+
+	var records []domain.AssignmentRecord
+	err = testDB.SelectContext(ctx, &records,
+		"SELECT id, pull_request_id, user_id, replaced_user_id, strategy, reason, created_at FROM assignment_history ORDER BY id")
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "rev1", records[0].UserID)
+	assert.Nil(t, records[0].ReplacedUserID)
+	assert.Equal(t, domain.StrategyLeastLoaded, records[1].Strategy)
+	assert.Equal(t, domain.ReasonLeastLoaded, records[1].Reason)
+	require.NotNil(t, records[1].ReplacedUserID)
+	assert.Equal(t, "rev1", *records[1].ReplacedUserID)
+}
+
+func TestAssignmentHistoryRepository_GetCurrentAssignments(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	ctx := context.Background()
+
+	prRepo := NewPullRequestRepository(testDB, logger)
+	repo := NewAssignmentHistoryRepository(testDB, logger)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{
+		ID: "pr-current", Name: "Current PR", AuthorID: "author", Status: api.PullRequestStatusOPEN,
+	}))
+	require.NoError(t, prRepo.AssignReviewers(txctx.With(ctx, tx), "pr-current", []string{"rev1", "rev2"}))
+	require.NoError(t, repo.RecordAssignments(txctx.With(ctx, tx), []domain.AssignmentRecord{
+		{PullRequestID: "pr-current", UserID: "rev1", Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom},
+		{PullRequestID: "pr-current", UserID: "rev2", Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom},
+	}))
+
+	replaced, note := "rev1", "handlers left"
+	require.NoError(t, prRepo.ReplaceReviewer(txctx.With(ctx, tx), "pr-current", "rev1", "rev4"))
+	require.NoError(t, repo.RecordAssignments(txctx.With(ctx, tx), []domain.AssignmentRecord{
+		{PullRequestID: "pr-current", UserID: "rev4", ReplacedUserID: &replaced, Strategy: domain.StrategyLeastLoaded, Reason: domain.ReasonLeastLoaded, HandoffNote: &note},
+	}))
+	require.NoError(t, tx.Commit())
+
+	records, err := repo.GetCurrentAssignments(ctx, "pr-current")
+	require.NoError(t, err)
+	require.Len(t, records, 2, "replaced reviewer must not be returned")
+
+	assert.Equal(t, "rev2", records[0].UserID)
+	assert.Equal(t, domain.ReasonRandom, records[0].Reason)
+	assert.Nil(t, records[0].HandoffNote)
+	assert.Equal(t, "rev4", records[1].UserID)
+	assert.Equal(t, domain.ReasonLeastLoaded, records[1].Reason)
+	require.NotNil(t, records[1].HandoffNote)
+	assert.Equal(t, note, *records[1].HandoffNote)
+}
+
+func TestAssignmentHistoryRepository_ListAssignmentHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	ctx := context.Background()
+
+	prRepo := NewPullRequestRepository(testDB, logger)
+	repo := NewAssignmentHistoryRepository(testDB, logger)
+	createdAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{
+		ID: "pr-timeline", Name: "Timeline PR", AuthorID: "author", Status: api.PullRequestStatusOPEN,
+	}))
+
+	replaced := "rev1"
+	require.NoError(t, repo.RecordAssignments(txctx.With(ctx, tx), []domain.AssignmentRecord{
+		{PullRequestID: "pr-timeline", UserID: "rev4", ReplacedUserID: &replaced, Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, Cause: domain.CauseTeamDeactivated, CreatedAt: createdAt.Add(time.Hour)},
+	}))
+	require.NoError(t, repo.RecordAssignments(txctx.With(ctx, tx), []domain.AssignmentRecord{
+		{PullRequestID: "pr-timeline", UserID: "rev1", Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, Cause: domain.CauseCreated, CreatedAt: createdAt},
+		{PullRequestID: "pr-timeline", UserID: "rev2", Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, Cause: domain.CauseCreated, CreatedAt: createdAt},
+	}))
+	require.NoError(t, tx.Commit())
+
+	_, err = testDB.ExecContext(ctx, `INSERT INTO assignment_history (pull_request_id, user_id, replaced_user_id, strategy, reason, created_at)
+		VALUES ('pr-timeline', 'rev1', 'rev2', 'least_loaded', 'least_loaded', $1)`, createdAt.Add(2*time.Hour))
+	require.NoError(t, err)
+
+	records, err := repo.ListAssignmentHistory(ctx, "pr-timeline")
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+
+	assert.Equal(t, []string{"rev1", "rev2", "rev4", "rev1"},
+		[]string{records[0].UserID, records[1].UserID, records[2].UserID, records[3].UserID}, "entries are ordered by time")
+	assert.Equal(t, domain.CauseCreated, records[0].Cause)
+	assert.Equal(t, domain.CauseTeamDeactivated, records[2].Cause)
+	require.NotNil(t, records[2].ReplacedUserID)
+	assert.Equal(t, "rev1", *records[2].ReplacedUserID)
+	assert.Empty(t, records[3].Cause, "entries recorded before the causes have none")
+
+	records, err = repo.ListAssignmentHistory(ctx, "pr-unknown")
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestAssignmentHistoryRepository_ListReplacementsOf(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	ctx := context.Background()
+
+	prRepo := NewPullRequestRepository(testDB, logger)
+	repo := NewAssignmentHistoryRepository(testDB, logger)
+	deactivatedAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+	replaced := "rev1"
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	for _, prID := range []string{"pr-1", "pr-2", "pr-3"} {
+		require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{
+			ID: prID, Name: "PR " + prID, AuthorID: "author", Status: api.PullRequestStatusOPEN,
+		}))
+	}
+
+	require.NoError(t, repo.RecordAssignments(txctx.With(ctx, tx), []domain.AssignmentRecord{
+		{PullRequestID: "pr-1", UserID: "rev2", ReplacedUserID: &replaced, Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, Cause: domain.CauseTeamDeactivated, CreatedAt: deactivatedAt.Add(time.Minute)},
+		{PullRequestID: "pr-2", UserID: "rev2", ReplacedUserID: &replaced, Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, Cause: domain.CauseReassign, CreatedAt: deactivatedAt.Add(time.Minute)},
+		{PullRequestID: "pr-3", UserID: "rev4", ReplacedUserID: &replaced, Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, Cause: domain.CauseTeamDeactivated, CreatedAt: deactivatedAt.Add(-time.Hour)},
+		{PullRequestID: "pr-3", UserID: "rev1", Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, Cause: domain.CauseCreated, CreatedAt: deactivatedAt},
+	}))
+	require.NoError(t, tx.Commit())
+
+	records, err := repo.ListReplacementsOf(ctx, []string{"rev1"}, domain.CauseTeamDeactivated, deactivatedAt)
+	require.NoError(t, err)
+	require.Len(t, records, 1, "only the replacements of the deactivation since then are listed")
+	assert.Equal(t, "pr-1", records[0].PullRequestID)
+	assert.Equal(t, "rev2", records[0].UserID)
+
+	records, err = repo.ListReplacementsOf(ctx, nil, domain.CauseTeamDeactivated, deactivatedAt)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type JobRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewJobRepository(db *sqlx.DB, log *slog.Logger) *JobRepository {
+	return &JobRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+var jobColumns = []string{
+	"id", "type", "status", "params", "result", "error", "progress_done", "progress_total", "cancel_requested",
+	"owner", "lease_expires_at", "attempts", "created_at", "started_at", "finished_at",
+}
+
+var jobReturning = "RETURNING " + strings.Join(jobColumns, ", ")
+
+func (jr *JobRepository) CreateJob(ctx context.Context, job *domain.Job) (*domain.Job, error) {
+	const op = "internal.repository.postgres.CreateJob"
+
+	// The JSON is passed as text: lib/pq would send a []byte as bytea.
+	query, args, err := jr.sq.Insert("jobs").
+		Columns("type", "status", "params", "created_at").
+		Values(job.Type, domain.JobQueued, string(job.Params), timestampOrNow(job.CreatedAt)).
+		Suffix(jobReturning).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var created domain.Job
+	if err := jr.db.GetContext(ctx, &created, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return &created, nil
+}
+
+func (jr *JobRepository) GetJob(ctx context.Context, id int64) (*domain.Job, error) {
+	const op = "internal.repository.postgres.GetJob"
+
+	query, args, err := jr.sq.Select(jobColumns...).
+		From("jobs").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var job domain.Job
+	if err := jr.db.GetContext(ctx, &job, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: job %d", op, apperrors.ErrNotFound, id)
+		}
+
+		return nil, fmt.Errorf("%s: failed to get job: %w", op, err)
+	}
+
+	return &job, nil
+}
+
+func (jr *JobRepository) ClaimJob(ctx context.Context, owner string, claimedAt time.Time, leaseUntil time.Time) (*domain.Job, error) {
+	const op = "internal.repository.postgres.ClaimJob"
+
+	// SKIP LOCKED lets concurrent workers claim different jobs instead of waiting for each other.
+	// The subquery keeps the default placeholders: the outer statement numbers them.
+	claimable := sq.Select("id").
+		From("jobs").
+		Where(sq.Or{
+			sq.Eq{"status": domain.JobQueued},
+			sq.And{sq.Eq{"status": domain.JobRunning}, sq.Lt{"lease_expires_at": claimedAt.UTC()}},
+		}).
+		OrderBy("id").
+		Limit(1).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query, args, err := jr.sq.Update("jobs").
+		Set("status", domain.JobRunning).
+		Set("owner", owner).
+		Set("lease_expires_at", leaseUntil.UTC()).
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("started_at", sq.Expr("COALESCE(started_at, ?)", claimedAt.UTC())).
+		Where(sq.Expr("id IN (?)", claimable)).
+		Suffix(jobReturning).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	var job domain.Job
+	if err := jr.db.GetContext(ctx, &job, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: no claimable job", op, apperrors.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: failed to claim job: %w", op, err)
+	}
+
+	return &job, nil
+}
+
+func (jr *JobRepository) RenewJobLease(ctx context.Context, id int64, owner string, done int, total int, leaseUntil time.Time) (bool, error) {
+	const op = "internal.repository.postgres.RenewJobLease"
+
+	query, args, err := jr.sq.Update("jobs").
+		Set("progress_done", done).
+		Set("progress_total", total).
+		Set("lease_expires_at", leaseUntil.UTC()).
+		Where(sq.Eq{"id": id, "owner": owner, "status": domain.JobRunning}).
+		Suffix("RETURNING cancel_requested").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	var cancelRequested bool
+	if err := jr.db.GetContext(ctx, &cancelRequested, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("%s: %w: job %d", op, apperrors.ErrJobLeaseLost, id)
+		}
+
+		return false, fmt.Errorf("%s: failed to renew lease: %w", op, err)
+	}
+
+	return cancelRequested, nil
+}
+
+func (jr *JobRepository) FinishJob(ctx context.Context, job *domain.Job, owner string) error {
+	const op = "internal.repository.postgres.FinishJob"
+
+	var result, finishedAt any
+	if job.Result != nil {
+		result = string(job.Result)
+	}
+
+	if job.FinishedAt != nil {
+		finishedAt = job.FinishedAt.UTC()
+	}
+
+	query, args, err := jr.sq.Update("jobs").
+		Set("status", job.Status).
+		Set("result", result).
+		Set("error", job.Error).
+		Set("progress_done", job.ProgressDone).
+		Set("progress_total", job.ProgressTotal).
+		Set("lease_expires_at", nil).
+		Set("finished_at", finishedAt).
+		Where(sq.Eq{"id": job.ID, "owner": owner, "status": domain.JobRunning}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := jr.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%s: %w: job %d", op, apperrors.ErrJobLeaseLost, job.ID)
+	}
+
+	return nil
+}
+
+func (jr *JobRepository) CancelJob(ctx context.Context, id int64, cancelledAt time.Time) (*domain.Job, error) {
+	const op = "internal.repository.postgres.CancelJob"
+
+	// A queued job has no worker to notice the request, so it is cancelled right away.
+	query, args, err := jr.sq.Update("jobs").
+		Set("status", sq.Expr("CASE WHEN status = ? THEN ? ELSE status END", domain.JobQueued, domain.JobCancelled)).
+		Set("finished_at", sq.Expr("CASE WHEN status = ? THEN ?::timestamptz ELSE finished_at END", domain.JobQueued, cancelledAt.UTC())).
+		Set("cancel_requested", true).
+		Where(sq.Eq{"id": id, "status": []domain.JobState{domain.JobQueued, domain.JobRunning}}).
+		Suffix(jobReturning).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	var job domain.Job
+	if err := jr.db.GetContext(ctx, &job, query, args...); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: failed to cancel job: %w", op, err)
+		}
+
+		// The job is either missing or already finished.
+		finished, err := jr.GetJob(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return finished, nil
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,101 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRepository_Lifecycle(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	truncateTables(t, testDB)
+	ctx := context.Background()
+
+	repo := NewJobRepository(testDB, logger)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	job, err := repo.CreateJob(ctx, &domain.Job{Type: domain.JobPendingBackfill, Params: []byte(`{"batch_size":10}`), CreatedAt: now})
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobQueued, job.Status)
+	assert.JSONEq(t, `{"batch_size":10}`, string(job.Params))
+
+	claimed, err := repo.ClaimJob(ctx, "worker-1", now, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, claimed.ID)
+	assert.Equal(t, domain.JobRunning, claimed.Status)
+	assert.Equal(t, 1, claimed.Attempts)
+
+	_, err = repo.ClaimJob(ctx, "worker-2", now, now.Add(time.Minute))
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	_, err = repo.RenewJobLease(ctx, job.ID, "worker-2", 1, 2, now.Add(time.Minute))
+	assert.ErrorIs(t, err, apperrors.ErrJobLeaseLost)
+
+	flagged, err := repo.CancelJob(ctx, job.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobRunning, flagged.Status)
+	assert.True(t, flagged.CancelRequested)
+
+	cancelRequested, err := repo.RenewJobLease(ctx, job.ID, "worker-1", 1, 2, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, cancelRequested)
+
+	// Once the lease expires, another worker takes the job over.
+	reclaimed, err := repo.ClaimJob(ctx, "worker-2", now.Add(2*time.Minute), now.Add(3*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 2, reclaimed.Attempts)
+	assert.True(t, now.Equal(*reclaimed.StartedAt))
+
+	finishedAt := now.Add(2 * time.Minute)
+	finished := *reclaimed
+	finished.Status, finished.FinishedAt, finished.Result = domain.JobCancelled, &finishedAt, []byte(`{"assigned_reviewers":1}`)
+	assert.ErrorIs(t, repo.FinishJob(ctx, &finished, "worker-1"), apperrors.ErrJobLeaseLost)
+	require.NoError(t, repo.FinishJob(ctx, &finished, "worker-2"))
+
+	got, err := repo.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobCancelled, got.Status)
+	assert.JSONEq(t, `{"assigned_reviewers":1}`, string(got.Result))
+	assert.Nil(t, got.LeaseExpiresAt)
+	assert.True(t, finishedAt.Equal(*got.FinishedAt))
+
+	// Cancelling a finished job leaves it unchanged.
+	unchanged, err := repo.CancelJob(ctx, job.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobCancelled, unchanged.Status)
+
+	_, err = repo.GetJob(ctx, 42)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestJobRepository_CancelQueued(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	truncateTables(t, testDB)
+	ctx := context.Background()
+
+	repo := NewJobRepository(testDB, logger)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	job, err := repo.CreateJob(ctx, &domain.Job{Type: domain.JobStatsExport, Params: []byte(`{}`), CreatedAt: now})
+	require.NoError(t, err)
+
+	cancelled, err := repo.CancelJob(ctx, job.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobCancelled, cancelled.Status)
+	require.NotNil(t, cancelled.FinishedAt)
+	assert.True(t, now.Equal(*cancelled.FinishedAt))
+
+	_, err = repo.ClaimJob(ctx, "worker-1", now, now.Add(time.Minute))
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
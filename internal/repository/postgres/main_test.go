@@ -50,7 +50,7 @@ func TestMain(m *testing.M) {
 		}
 	}()
 
-	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable", "timezone=UTC")
 	if err != nil {
 		log.Fatalf("failed to get connection string: %s", err)
 	}
@@ -84,7 +84,7 @@ func TestMain(m *testing.M) {
 
 func truncateTables(t *testing.T, db *sqlx.DB) {
 	t.Helper()
-	_, err := db.Exec("TRUNCATE TABLE teams, users, pull_requests, reviewers RESTART IDENTITY CASCADE")
+	_, err := db.Exec("TRUNCATE TABLE teams, users, pull_requests, reviewers, team_policies, assignment_history, pending_assignments, reviewer_borrows, borrowed_reviewers, pr_create_requests, team_deactivation_jobs, team_deactivation_users, team_deactivation_batches, team_deactivation_prs, team_deactivation_warnings, team_deactivations, team_deactivation_members, user_teams, jobs, team_custom_fields, pr_subscriptions, notification_deliveries, freeze_windows, gitlab_users, webhooks, webhook_deliveries, slack_users, outbox_events, read_tokens, api_keys, oidc_subjects RESTART IDENTITY CASCADE")
 	if err != nil {
 		t.Fatalf("failed to truncate tables: %v", err)
 	}
@@ -0,0 +1,87 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationDeliveryRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+
+	setupPRTest(t)
+	repo := NewNotificationDeliveryRepository(testDB, logger)
+	ctx := context.Background()
+	createdAt := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+	failure := "connection refused"
+
+	first, err := repo.CreateDelivery(ctx, &domain.NotificationDelivery{
+		Channel: "chat", RecipientID: "rev1", Event: domain.EventReviewersAssigned, PullRequestID: "pr-1",
+		Status: domain.DeliveryFailed, Error: &failure, Payload: []byte(`{"Type": "reviewers_assigned"}`), CreatedAt: createdAt,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), first.ID)
+	assert.Equal(t, 1, first.Attempts)
+	assert.Equal(t, createdAt, first.UpdatedAt.UTC())
+	assert.JSONEq(t, `{"Type": "reviewers_assigned"}`, string(first.Payload))
+
+	_, err = repo.CreateDelivery(ctx, &domain.NotificationDelivery{
+		Channel: "chat", RecipientID: "rev2", Event: domain.EventReviewersAssigned, PullRequestID: "pr-1",
+		Status: domain.DeliveryDelivered, Payload: []byte(`{}`), CreatedAt: createdAt,
+	})
+	require.NoError(t, err)
+
+	_, err = repo.CreateDelivery(ctx, &domain.NotificationDelivery{
+		Channel: "chat", RecipientID: "rev1", Event: domain.EventPRMerged, PullRequestID: "pr-2",
+		Status: domain.DeliveryDelivered, Payload: []byte(`{}`), CreatedAt: createdAt,
+	})
+	require.NoError(t, err)
+
+	deliveries, err := repo.ListDeliveries(ctx, domain.DeliveryFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 3)
+	assert.Equal(t, []int64{3, 2, 1}, []int64{deliveries[0].ID, deliveries[1].ID, deliveries[2].ID}, "newest first")
+
+	deliveries, err = repo.ListDeliveries(ctx, domain.DeliveryFilter{RecipientID: "rev1", Limit: 10, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, int64(1), deliveries[0].ID)
+
+	deliveries, err = repo.ListDeliveries(ctx, domain.DeliveryFilter{AfterID: 3, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, int64(2), deliveries[0].ID)
+
+	deliveries, err = repo.ListDeliveries(ctx, domain.DeliveryFilter{PullRequestID: "pr-1", Status: domain.DeliveryFailed, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, &failure, deliveries[0].Error)
+
+	retriedAt := createdAt.Add(time.Hour)
+	retried, err := repo.RecordDeliveryAttempt(ctx, first.ID, domain.DeliveryDelivered, nil, retriedAt)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeliveryDelivered, retried.Status)
+	assert.Nil(t, retried.Error)
+	assert.Equal(t, 2, retried.Attempts)
+	assert.Equal(t, retriedAt, retried.UpdatedAt.UTC())
+	assert.Equal(t, createdAt, retried.CreatedAt.UTC())
+
+	stored, err := repo.GetDelivery(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, retried, stored)
+
+	_, err = repo.GetDelivery(ctx, 42)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	_, err = repo.RecordDeliveryAttempt(ctx, 42, domain.DeliveryDelivered, nil, retriedAt)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type OIDCSubjectRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewOIDCSubjectRepository(db *sqlx.DB, log *slog.Logger) *OIDCSubjectRepository {
+	return &OIDCSubjectRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (osr *OIDCSubjectRepository) SetOIDCSubject(ctx context.Context, mapping *domain.OIDCSubject) (*domain.OIDCSubject, error) {
+	const op = "internal.repository.postgres.SetOIDCSubject"
+
+	query, args, err := osr.sq.Insert("oidc_subjects").
+		Columns("subject", "user_id", "created_at").
+		Values(mapping.Subject, mapping.UserID, timestampOrNow(mapping.CreatedAt)).
+		Suffix(`ON CONFLICT (subject) DO UPDATE SET user_id = EXCLUDED.user_id, created_at = EXCLUDED.created_at
+			RETURNING subject, user_id, created_at`).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var stored domain.OIDCSubject
+	if err := osr.db.QueryRowxContext(ctx, query, args...).StructScan(&stored); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return nil, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, mapping.UserID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return &stored, nil
+}
+
+func (osr *OIDCSubjectRepository) GetOIDCSubject(ctx context.Context, subject string) (*domain.OIDCSubject, error) {
+	const op = "internal.repository.postgres.GetOIDCSubject"
+
+	query, args, err := osr.sq.Select("subject", "user_id", "created_at").
+		From("oidc_subjects").
+		Where(sq.Eq{"subject": subject}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var mapping domain.OIDCSubject
+	if err := osr.db.GetContext(ctx, &mapping, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: oidc subject '%s'", op, apperrors.ErrNotFound, subject)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &mapping, nil
+}
+
+func (osr *OIDCSubjectRepository) ListOIDCSubjects(ctx context.Context) ([]domain.OIDCSubject, error) {
+	const op = "internal.repository.postgres.ListOIDCSubjects"
+
+	query, args, err := osr.sq.Select("subject", "user_id", "created_at").
+		From("oidc_subjects").
+		OrderBy("subject").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	mappings := []domain.OIDCSubject{}
+	if err := osr.db.SelectContext(ctx, &mappings, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to list oidc subjects: %w", op, err)
+	}
+
+	return mappings, nil
+}
+
+func (osr *OIDCSubjectRepository) DeleteOIDCSubject(ctx context.Context, subject string) error {
+	const op = "internal.repository.postgres.DeleteOIDCSubject"
+
+	query, args, err := osr.sq.Delete("oidc_subjects").
+		Where(sq.Eq{"subject": subject}).
+		Suffix("RETURNING subject").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build delete query: %w", op, err)
+	}
+
+	var deleted string
+	if err := osr.db.GetContext(ctx, &deleted, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w: oidc subject '%s'", op, apperrors.ErrNotFound, subject)
+		}
+
+		return fmt.Errorf("%s: failed to execute delete: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOIDCSubjectRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+
+	setupPRTest(t)
+	repo := NewOIDCSubjectRepository(testDB, logger)
+	ctx := context.Background()
+	createdAt := time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	stored, err := repo.SetOIDCSubject(ctx, &domain.OIDCSubject{Subject: "00uB", UserID: "author", CreatedAt: createdAt})
+	require.NoError(t, err)
+	assert.Equal(t, "author", stored.UserID)
+	assert.Equal(t, createdAt, stored.CreatedAt.UTC())
+
+	_, err = repo.SetOIDCSubject(ctx, &domain.OIDCSubject{Subject: "00uA", UserID: "rev1"})
+	require.NoError(t, err)
+
+	remapped, err := repo.SetOIDCSubject(ctx, &domain.OIDCSubject{Subject: "00uB", UserID: "rev2"})
+	require.NoError(t, err)
+	assert.Equal(t, "rev2", remapped.UserID, "a subject is mapped to one user at a time")
+
+	_, err = repo.SetOIDCSubject(ctx, &domain.OIDCSubject{Subject: "00uX", UserID: "no-such-user"})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	mapping, err := repo.GetOIDCSubject(ctx, "00uB")
+	require.NoError(t, err)
+	assert.Equal(t, "rev2", mapping.UserID)
+
+	_, err = repo.GetOIDCSubject(ctx, "00ub")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound, "subjects are compared exactly")
+
+	all, err := repo.ListOIDCSubjects(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, []string{"00uA", "00uB"}, []string{all[0].Subject, all[1].Subject})
+
+	require.NoError(t, repo.DeleteOIDCSubject(ctx, "00uB"))
+	assert.ErrorIs(t, repo.DeleteOIDCSubject(ctx, "00uB"), apperrors.ErrNotFound)
+}
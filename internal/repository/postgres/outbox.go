@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/jmoiron/sqlx"
+)
+
+type OutboxRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewOutboxRepository(db *sqlx.DB, log *slog.Logger) *OutboxRepository {
+	return &OutboxRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+var outboxEventReturning = "RETURNING " + strings.Join([]string{
+	"id", "event", "pull_request_id", "payload", "attempts", "next_attempt_at", "published_at", "last_error", "occurred_at",
+}, ", ")
+
+func (ob *OutboxRepository) AddOutboxEvent(ctx context.Context, event *domain.OutboxEvent) error {
+	const op = "internal.repository.postgres.AddOutboxEvent"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	occurredAt := timestampOrNow(event.OccurredAt)
+
+	query, args, err := ob.sq.Insert("outbox_events").
+		Columns("event", "pull_request_id", "payload", "next_attempt_at", "occurred_at").
+		Values(event.Type, event.PullRequestID, string(event.Payload), occurredAt, occurredAt).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	if err := tx.QueryRowxContext(ctx, query, args...).Scan(&event.ID); err != nil {
+		return fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return nil
+}
+
+func (ob *OutboxRepository) ClaimOutboxEvents(ctx context.Context, limit int, now, leaseUntil time.Time) ([]domain.OutboxEvent, error) {
+	const op = "internal.repository.postgres.ClaimOutboxEvents"
+
+	// SKIP LOCKED lets concurrent instances claim disjoint batches instead of waiting for each other.
+	claimable := sq.Select("id").
+		From("outbox_events").
+		Where(sq.Eq{"published_at": nil}).
+		Where(sq.LtOrEq{"next_attempt_at": now.UTC()}).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query, args, err := ob.sq.Update("outbox_events").
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("next_attempt_at", leaseUntil.UTC()).
+		Where(sq.Expr("id IN (?)", claimable)).
+		Suffix(outboxEventReturning).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	claimed := []domain.OutboxEvent{}
+	if err := ob.db.SelectContext(ctx, &claimed, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	// RETURNING does not keep the order of the subquery.
+	slices.SortFunc(claimed, func(a, b domain.OutboxEvent) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return claimed, nil
+}
+
+func (ob *OutboxRepository) FinishOutboxAttempt(ctx context.Context, event *domain.OutboxEvent) error {
+	const op = "internal.repository.postgres.FinishOutboxAttempt"
+
+	var publishedAt *time.Time
+	if event.PublishedAt != nil {
+		at := event.PublishedAt.UTC()
+		publishedAt = &at
+	}
+
+	query, args, err := ob.sq.Update("outbox_events").
+		Set("published_at", publishedAt).
+		Set("next_attempt_at", event.NextAttemptAt.UTC()).
+		Set("last_error", event.LastError).
+		Where(sq.Eq{"id": event.ID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := ob.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%s: %w: outbox event %d", op, apperrors.ErrNotFound, event.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+
+	truncateTables(t, testDB)
+	repo := NewOutboxRepository(testDB, logger)
+	ctx := context.Background()
+	occurredAt := time.Date(2026, time.January, 5, 9, 0, 0, 0, time.UTC)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.AddOutboxEvent(txctx.With(ctx, tx), &domain.OutboxEvent{
+		Type: domain.WebhookPRCreated, PullRequestID: "pr-0", Payload: []byte(`{"event":"pr.created"}`), OccurredAt: occurredAt,
+	}))
+	require.NoError(t, tx.Rollback())
+
+	claimed, err := repo.ClaimOutboxEvents(ctx, 10, occurredAt, occurredAt.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "a rolled back change writes nothing")
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+
+	created := &domain.OutboxEvent{
+		Type: domain.WebhookPRCreated, PullRequestID: "pr-1", Payload: []byte(`{"event":"pr.created"}`), OccurredAt: occurredAt,
+	}
+	require.NoError(t, repo.AddOutboxEvent(txctx.With(ctx, tx), created))
+	require.NoError(t, repo.AddOutboxEvent(txctx.With(ctx, tx), &domain.OutboxEvent{
+		Type: domain.WebhookPRMerged, PullRequestID: "pr-1", Payload: []byte(`{"event":"pr.merged"}`), OccurredAt: occurredAt,
+	}))
+	require.NoError(t, tx.Commit())
+	assert.Positive(t, created.ID)
+
+	leaseUntil := occurredAt.Add(time.Minute)
+
+	claimed, err = repo.ClaimOutboxEvents(ctx, 1, occurredAt, leaseUntil)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, created.ID, claimed[0].ID, "events are claimed in the order they were written")
+	assert.Equal(t, 1, claimed[0].Attempts)
+	assert.Equal(t, leaseUntil, claimed[0].NextAttemptAt.UTC())
+	assert.JSONEq(t, `{"event":"pr.created"}`, string(claimed[0].Payload))
+
+	publishedAt := occurredAt.Add(time.Second)
+	claimed[0].PublishedAt = &publishedAt
+	require.NoError(t, repo.FinishOutboxAttempt(ctx, &claimed[0]))
+
+	claimed, err = repo.ClaimOutboxEvents(ctx, 10, occurredAt, leaseUntil)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, domain.WebhookPRMerged, claimed[0].Type)
+
+	failure := "broker unavailable"
+	claimed[0].NextAttemptAt = occurredAt.Add(time.Hour)
+	claimed[0].LastError = &failure
+	require.NoError(t, repo.FinishOutboxAttempt(ctx, &claimed[0]))
+
+	claimed, err = repo.ClaimOutboxEvents(ctx, 10, leaseUntil, leaseUntil.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "a published event is never claimed, a failed one not before its next attempt")
+
+	claimed, err = repo.ClaimOutboxEvents(ctx, 10, occurredAt.Add(time.Hour), leaseUntil.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, 2, claimed[0].Attempts)
+	require.NotNil(t, claimed[0].LastError)
+	assert.Equal(t, failure, *claimed[0].LastError)
+
+	err = repo.FinishOutboxAttempt(ctx, &domain.OutboxEvent{ID: 42})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
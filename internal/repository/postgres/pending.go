@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/jmoiron/sqlx"
+)
+
+type PendingAssignmentRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewPendingAssignmentRepository(db *sqlx.DB, log *slog.Logger) *PendingAssignmentRepository {
+	return &PendingAssignmentRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+var pendingColumns = []string{
+	"pa.pull_request_id", "pr.name AS pull_request_name", "pr.author_id",
+	"pa.team_id", "t.name AS team_name", "pa.priority", "pa.enqueued_at", "pr.assign_at",
+}
+
+func (pr *PendingAssignmentRepository) pendingQuery() sq.SelectBuilder {
+	return pr.sq.Select(pendingColumns...).
+		From("pending_assignments pa").
+		Join("pull_requests pr ON pr.id = pa.pull_request_id").
+		Join("teams t ON t.id = pa.team_id")
+}
+
+func (pr *PendingAssignmentRepository) EnqueuePending(ctx context.Context, prID string, teamID int, priority int) error {
+	const op = "internal.repository.postgres.EnqueuePending"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := pr.sq.Insert("pending_assignments").
+		Columns("pull_request_id", "team_id", "priority").
+		Values(prID, teamID, priority).
+		Suffix("ON CONFLICT (pull_request_id) DO UPDATE SET priority = EXCLUDED.priority").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return nil
+}
+
+func (pr *PendingAssignmentRepository) DequeuePending(ctx context.Context, prID string) error {
+	const op = "internal.repository.postgres.DequeuePending"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := pr.sq.Delete("pending_assignments").
+		Where(sq.Eq{"pull_request_id": prID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build delete query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute delete: %w", op, err)
+	}
+
+	return nil
+}
+
+func (pr *PendingAssignmentRepository) GetPendingWithLock(ctx context.Context, prID string) (*domain.PendingAssignment, error) {
+	const op = "internal.repository.postgres.GetPendingWithLock"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := pr.pendingQuery().
+		Where(sq.Eq{"pa.pull_request_id": prID}).
+		Suffix("FOR UPDATE OF pa").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var entry domain.PendingAssignment
+	if err := tx.GetContext(ctx, &entry, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: pending assignment of PR '%s'", op, apperrors.ErrNotFound, prID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to get pending assignment with lock: %w", op, err)
+	}
+
+	return &entry, nil
+}
+
+func (pr *PendingAssignmentRepository) ListPending(ctx context.Context, teamName string, limit int) ([]domain.PendingAssignment, error) {
+	const op = "internal.repository.postgres.ListPending"
+
+	builder := pr.pendingQuery().
+		Where(sq.Eq{"t.deleted_at": nil}).
+		OrderBy("pa.priority DESC", "pa.enqueued_at", "pa.pull_request_id").
+		Limit(uint64(limit))
+
+	if teamName != "" {
+		builder = builder.Where(sq.Eq{"t.name": teamName})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	entries := []domain.PendingAssignment{}
+	if err := pr.db.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+func (pr *PendingAssignmentRepository) ListDuePending(ctx context.Context, now time.Time, limit int) ([]domain.PendingAssignment, error) {
+	const op = "internal.repository.postgres.ListDuePending"
+
+	query, args, err := pr.pendingQuery().
+		Where(sq.Or{sq.Eq{"pr.assign_at": nil}, sq.LtOrEq{"pr.assign_at": now}}).
+		Where(sq.Eq{"t.deleted_at": nil}).
+		OrderBy("pa.priority DESC", "pa.enqueued_at", "pa.pull_request_id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	entries := []domain.PendingAssignment{}
+	if err := pr.db.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+func (pr *PendingAssignmentRepository) ListUnqueuedNeedingReviewers(ctx context.Context, afterID string, limit int) ([]string, error) {
+	const op = "internal.repository.postgres.ListUnqueuedNeedingReviewers"
+
+	query, args, err := pr.sq.Select("pr.id").
+		From("pull_requests pr").
+		LeftJoin("pending_assignments pa ON pa.pull_request_id = pr.id").
+		Where(sq.Eq{"pr.status": api.PullRequestStatusOPEN, "pr.need_more_reviewers": true, "pa.pull_request_id": nil}).
+		Where(sq.Gt{"pr.id": afterID}).
+		OrderBy("pr.id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	ids := []string{}
+	if err := pr.db.SelectContext(ctx, &ids, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return ids, nil
+}
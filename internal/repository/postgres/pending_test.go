@@ -0,0 +1,171 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingAssignmentRepository_Queue(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	truncateTables(t, testDB)
+	ctx := context.Background()
+
+	team, err := NewTeamRepository(testDB, logger).CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "pending-team",
+		Members:  []api.TeamMember{{UserId: "pending-author", Username: "Author", IsActive: true}},
+	})
+	require.NoError(t, err)
+
+	prRepo := NewPullRequestRepository(testDB, logger)
+	repo := NewPendingAssignmentRepository(testDB, logger)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+
+	for _, id := range []string{"pr-1", "pr-2", "pr-3"} {
+		require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: id, Name: id, AuthorID: "pending-author", Status: api.PullRequestStatusOPEN}))
+	}
+
+	require.NoError(t, repo.EnqueuePending(txctx.With(ctx, tx), "pr-1", team.ID, 1))
+	require.NoError(t, repo.EnqueuePending(txctx.With(ctx, tx), "pr-2", team.ID, 2))
+	require.NoError(t, repo.EnqueuePending(txctx.With(ctx, tx), "pr-3", team.ID, 1))
+	require.NoError(t, tx.Commit())
+
+	entries, err := repo.ListPending(ctx, "pending-team", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "pr-2", entries[0].PullRequestID)
+	assert.Equal(t, "pending-team", entries[0].TeamName)
+	assert.Equal(t, "pending-author", entries[0].AuthorID)
+
+	entries, err = repo.ListPending(ctx, "", 1)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+
+	before, err := repo.GetPendingWithLock(txctx.With(ctx, tx), "pr-1")
+	require.NoError(t, err)
+
+	// Re-enqueueing changes the priority but keeps the place in the queue.
+	require.NoError(t, repo.EnqueuePending(txctx.With(ctx, tx), "pr-1", team.ID, 2))
+	require.NoError(t, repo.DequeuePending(txctx.With(ctx, tx), "pr-3"))
+
+	after, err := repo.GetPendingWithLock(txctx.With(ctx, tx), "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, after.Priority)
+	assert.True(t, before.EnqueuedAt.Equal(after.EnqueuedAt))
+
+	_, err = repo.GetPendingWithLock(txctx.With(ctx, tx), "pr-3")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	require.NoError(t, tx.Commit())
+}
+
+func TestPendingAssignmentRepository_ListDuePending(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	truncateTables(t, testDB)
+	ctx := context.Background()
+
+	team, err := NewTeamRepository(testDB, logger).CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "deferred-team",
+		Members:  []api.TeamMember{{UserId: "deferred-author", Username: "Author", IsActive: true}},
+	})
+	require.NoError(t, err)
+
+	prRepo := NewPullRequestRepository(testDB, logger)
+	repo := NewPendingAssignmentRepository(testDB, logger)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	earlier, later := now.Add(-time.Minute), now.Add(time.Hour)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+
+	require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-now", Name: "now", AuthorID: "deferred-author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-due", Name: "due", AuthorID: "deferred-author", Status: api.PullRequestStatusOPEN, AssignAt: &earlier}))
+	require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-later", Name: "later", AuthorID: "deferred-author", Status: api.PullRequestStatusOPEN, AssignAt: &later}))
+
+	for _, id := range []string{"pr-now", "pr-due", "pr-later"} {
+		require.NoError(t, repo.EnqueuePending(txctx.With(ctx, tx), id, team.ID, 2))
+	}
+	require.NoError(t, tx.Commit())
+
+	entries, err := repo.ListDuePending(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.ElementsMatch(t, []string{"pr-now", "pr-due"}, []string{entries[0].PullRequestID, entries[1].PullRequestID})
+
+	entries, err = repo.ListPending(ctx, "deferred-team", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	for _, entry := range entries {
+		if entry.PullRequestID == "pr-later" {
+			require.NotNil(t, entry.AssignAt)
+			assert.True(t, later.Equal(*entry.AssignAt))
+		}
+	}
+
+	pr, err := prRepo.GetPRByID(ctx, "pr-later")
+	require.NoError(t, err)
+	require.NotNil(t, pr.AssignAt)
+	assert.True(t, later.Equal(*pr.AssignAt))
+}
+
+func TestPendingAssignmentRepository_ListUnqueuedNeedingReviewers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	truncateTables(t, testDB)
+	ctx := context.Background()
+
+	team, err := NewTeamRepository(testDB, logger).CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "pending-team",
+		Members:  []api.TeamMember{{UserId: "pending-author", Username: "Author", IsActive: true}},
+	})
+	require.NoError(t, err)
+
+	prRepo := NewPullRequestRepository(testDB, logger)
+	repo := NewPendingAssignmentRepository(testDB, logger)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+
+	for _, pr := range []domain.PullRequest{
+		{ID: "pr-3", NeedMoreReviewers: true, Status: api.PullRequestStatusOPEN},
+		{ID: "pr-1", NeedMoreReviewers: true, Status: api.PullRequestStatusOPEN},
+		{ID: "pr-2", NeedMoreReviewers: true, Status: api.PullRequestStatusOPEN},
+		{ID: "pr-queued", NeedMoreReviewers: true, Status: api.PullRequestStatusOPEN},
+		{ID: "pr-full", Status: api.PullRequestStatusOPEN},
+	} {
+		pr.Name = pr.ID
+		pr.AuthorID = "pending-author"
+		require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &pr))
+	}
+
+	require.NoError(t, repo.EnqueuePending(txctx.With(ctx, tx), "pr-queued", team.ID, 1))
+	require.NoError(t, tx.Commit())
+
+	ids, err := repo.ListUnqueuedNeedingReviewers(ctx, "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr-1", "pr-2"}, ids)
+
+	ids, err = repo.ListUnqueuedNeedingReviewers(ctx, "pr-2", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr-3"}, ids)
+}
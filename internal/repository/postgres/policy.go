@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type PolicyRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewPolicyRepository(db *sqlx.DB, log *slog.Logger) *PolicyRepository {
+	return &PolicyRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+var policyColumns = []string{
+	"team_id", "strategy_weights", "author_open_pr_limit", "over_quota_action", "reactivation_rebalance", "updated_at",
+}
+
+type teamPolicyRow struct {
+	TeamID                int                  `db:"team_id"`
+	StrategyWeights       []byte               `db:"strategy_weights"`
+	AuthorOpenPRLimit     *int                 `db:"author_open_pr_limit"`
+	OverQuotaAction       domain.QuotaAction   `db:"over_quota_action"`
+	ReactivationRebalance domain.RebalanceMode `db:"reactivation_rebalance"`
+	UpdatedAt             time.Time            `db:"updated_at"`
+}
+
+func (row *teamPolicyRow) toDomain() (*domain.TeamPolicy, error) {
+	weights := make(map[domain.AssignmentStrategy]int)
+	if err := json.Unmarshal(row.StrategyWeights, &weights); err != nil {
+		return nil, fmt.Errorf("failed to decode strategy weights: %w", err)
+	}
+
+	return &domain.TeamPolicy{
+		TeamID:                row.TeamID,
+		StrategyWeights:       weights,
+		AuthorOpenPRLimit:     row.AuthorOpenPRLimit,
+		OverQuotaAction:       row.OverQuotaAction,
+		ReactivationRebalance: row.ReactivationRebalance,
+		UpdatedAt:             row.UpdatedAt,
+	}, nil
+}
+
+func (pr *PolicyRepository) GetTeamPolicy(ctx context.Context, teamID int) (*domain.TeamPolicy, error) {
+	const op = "internal.repository.postgres.GetTeamPolicy"
+
+	query, args, err := pr.sq.Select(policyColumns...).
+		From("team_policies").
+		Where(sq.Eq{"team_id": teamID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var row teamPolicyRow
+	if err := pr.db.GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &domain.TeamPolicy{
+				TeamID:                teamID,
+				StrategyWeights:       map[domain.AssignmentStrategy]int{},
+				OverQuotaAction:       domain.QuotaReject,
+				ReactivationRebalance: domain.RebalanceOff,
+			}, nil
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	policy, err := row.toDomain()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return policy, nil
+}
+
+func (pr *PolicyRepository) UpsertTeamPolicy(ctx context.Context, policy *domain.TeamPolicy) (*domain.TeamPolicy, error) {
+	const op = "internal.repository.postgres.UpsertTeamPolicy"
+
+	weights, err := json.Marshal(policy.StrategyWeights)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to encode strategy weights: %w", op, err)
+	}
+
+	action := policy.OverQuotaAction
+	if action == "" {
+		action = domain.QuotaReject
+	}
+
+	rebalance := policy.ReactivationRebalance
+	if rebalance == "" {
+		rebalance = domain.RebalanceOff
+	}
+
+	query, args, err := pr.sq.Insert("team_policies").
+		Columns("team_id", "strategy_weights", "author_open_pr_limit", "over_quota_action", "reactivation_rebalance").
+		Values(policy.TeamID, weights, policy.AuthorOpenPRLimit, action, rebalance).
+		Suffix(`
+        ON CONFLICT (team_id) DO UPDATE SET
+            strategy_weights = EXCLUDED.strategy_weights,
+            author_open_pr_limit = EXCLUDED.author_open_pr_limit,
+            over_quota_action = EXCLUDED.over_quota_action,
+            reactivation_rebalance = EXCLUDED.reactivation_rebalance,
+            updated_at = NOW()
+        RETURNING ` + strings.Join(policyColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build upsert query: %w", op, err)
+	}
+
+	var row teamPolicyRow
+	if err := pr.db.QueryRowxContext(ctx, query, args...).StructScan(&row); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return nil, fmt.Errorf("%s: %w: team with id '%d'", op, apperrors.ErrNotFound, policy.TeamID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute upsert: %w", op, err)
+	}
+
+	saved, err := row.toDomain()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return saved, nil
+}
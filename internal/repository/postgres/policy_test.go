@@ -0,0 +1,85 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyRepository_GetAndUpsert(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	truncateTables(t, testDB)
+	ctx := context.Background()
+
+	team, err := NewTeamRepository(testDB, logger).CreateTeamWithUsers(ctx, api.Team{TeamName: "policy-team"})
+	require.NoError(t, err)
+
+	repo := NewPolicyRepository(testDB, logger)
+
+	policy, err := repo.GetTeamPolicy(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Equal(t, team.ID, policy.TeamID)
+	assert.Empty(t, policy.StrategyWeights, "team without a stored policy should get an empty one")
+
+	saved, err := repo.UpsertTeamPolicy(ctx, &domain.TeamPolicy{
+		TeamID:          team.ID,
+		StrategyWeights: map[domain.AssignmentStrategy]int{domain.StrategyRandom: 80, domain.StrategyLeastLoaded: 20},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 80, saved.StrategyWeights[domain.StrategyRandom])
+	assert.False(t, saved.UpdatedAt.IsZero())
+
+	_, err = repo.UpsertTeamPolicy(ctx, &domain.TeamPolicy{
+		TeamID:          team.ID,
+		StrategyWeights: map[domain.AssignmentStrategy]int{domain.StrategyLeastLoaded: 1},
+	})
+	require.NoError(t, err)
+
+	policy, err = repo.GetTeamPolicy(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[domain.AssignmentStrategy]int{domain.StrategyLeastLoaded: 1}, policy.StrategyWeights)
+	assert.Nil(t, policy.AuthorOpenPRLimit)
+	assert.Equal(t, domain.QuotaReject, policy.OverQuotaAction)
+	assert.Equal(t, domain.RebalanceOff, policy.ReactivationRebalance)
+
+	limit := 3
+	_, err = repo.UpsertTeamPolicy(ctx, &domain.TeamPolicy{
+		TeamID:                team.ID,
+		StrategyWeights:       map[domain.AssignmentStrategy]int{},
+		AuthorOpenPRLimit:     &limit,
+		OverQuotaAction:       domain.QuotaQueue,
+		ReactivationRebalance: domain.RebalanceJob,
+	})
+	require.NoError(t, err)
+
+	policy, err = repo.GetTeamPolicy(ctx, team.ID)
+	require.NoError(t, err)
+	require.NotNil(t, policy.AuthorOpenPRLimit)
+	assert.Equal(t, 3, *policy.AuthorOpenPRLimit)
+	assert.Equal(t, domain.QuotaQueue, policy.OverQuotaAction)
+	assert.Equal(t, domain.RebalanceJob, policy.ReactivationRebalance)
+}
+
+func TestPolicyRepository_Upsert_TeamNotFound(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	truncateTables(t, testDB)
+
+	repo := NewPolicyRepository(testDB, logger)
+
+	_, err := repo.UpsertTeamPolicy(context.Background(), &domain.TeamPolicy{
+		TeamID:          999,
+		StrategyWeights: map[domain.AssignmentStrategy]int{domain.StrategyRandom: 1},
+	})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
@@ -1,24 +1,59 @@
 package postgres
 
 import (
+	"database/sql"
 	"fmt"
 	"log/slog"
+	"net/url"
+	"strings"
+	"time"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/tracing"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 func NewDB(cfg config.Postgres, log *slog.Logger) (*sqlx.DB, error) {
-	connStr := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+	// The session time zone is UTC, so that lib/pq returns the timestamps it reads in time.UTC.
+	connStr := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable&timezone=UTC",
 		cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.Database,
 	)
 
-	db, err := sqlx.Connect("postgres", connStr)
+	db, err := open(connStr, cfg)
 	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
 		return nil, fmt.Errorf("can't connect to database: %w", err)
 	}
 
+	return db, nil
+}
+
+// NewReplicaDB opens the pool of the read replica at cfg.ReadDSN with the pool settings of the primary.
+// Unlike NewDB it does not require the replica to answer: Replica reads from the primary until it does.
+func NewReplicaDB(cfg config.Postgres) (*sqlx.DB, error) {
+	connStr, err := withUTCTimezone(cfg.ReadDSN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid replica config: %w", err)
+	}
+
+	return open(connStr, cfg)
+}
+
+func open(connStr string, cfg config.Postgres) (*sqlx.DB, error) {
+	connector, err := pq.NewConnector(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database config: %w", err)
+	}
+
+	// The queries are traced when tracing is set up, see internal/tracing.
+	db := sqlx.NewDb(sql.OpenDB(tracing.WrapConnector(connector, "postgresql")), "postgres")
+
 	db.SetMaxOpenConns(cfg.MaxOpenConns)
 	db.SetMaxIdleConns(cfg.MaxIdleConns)
 	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
@@ -26,3 +61,51 @@ func NewDB(cfg config.Postgres, log *slog.Logger) (*sqlx.DB, error) {
 
 	return db, nil
 }
+
+// withUTCTimezone sets the session time zone of a URL or key=value connection string to UTC, as NewDB does.
+func withUTCTimezone(dsn string) (string, error) {
+	if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+		return dsn + " timezone=UTC", nil
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set("timezone", "UTC")
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// timestampOrNow returns t in UTC as an insert value; a zero t leaves the timestamp to the database clock.
+func timestampOrNow(t time.Time) any {
+	if t.IsZero() {
+		return sq.Expr("NOW()")
+	}
+
+	return t.UTC()
+}
+
+// jsonObjectOrEmpty returns raw as an insert value for a JSONB object column; an empty raw is stored as an empty object.
+// The JSON is passed as text: lib/pq would send a []byte as bytea.
+func jsonObjectOrEmpty(raw []byte) string {
+	if len(raw) == 0 {
+		return "{}"
+	}
+
+	return string(raw)
+}
+
+// jsonOrNull is like jsonObjectOrEmpty for a nullable JSONB column: an empty raw is stored as NULL.
+func jsonOrNull(raw []byte) *string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	value := string(raw)
+
+	return &value
+}
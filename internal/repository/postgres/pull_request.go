@@ -6,12 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"math/rand"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
 	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
 	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
 	"github.com/jmoiron/sqlx"
@@ -22,6 +25,8 @@ type PullRequestRepository struct {
 	db  *sqlx.DB
 	log *slog.Logger
 	sq  sq.StatementBuilderType
+	// replica serves the queries outside a transaction, see WithReplica.
+	replica *Replica
 }
 
 func NewPullRequestRepository(db *sqlx.DB, log *slog.Logger) *PullRequestRepository {
@@ -32,19 +37,40 @@ func NewPullRequestRepository(db *sqlx.DB, log *slog.Logger) *PullRequestReposit
 	}
 }
 
+// WithReplica returns a copy of the repository whose queries of PRQueryRepository read outside a transaction
+// from replica, or from the primary while the replica is down. A nil replica leaves them on the primary.
+// The copy is meant for the query services only: the write paths must read their own writes.
+func (r *PullRequestRepository) WithReplica(replica *Replica) *PullRequestRepository {
+	copied := *r
+	copied.replica = replica
+
+	return &copied
+}
+
+// reads returns the pool of the queries outside a transaction.
+func (r *PullRequestRepository) reads() *sqlx.DB {
+	if r.replica == nil {
+		return r.db
+	}
+
+	return r.replica.DB()
+}
+
 func (r *PullRequestRepository) GetAuthorTeamID(ctx context.Context, authorID string) (int, error) {
 	const op = "internal.repository.postgres.GetAuthorTeamID"
 
+	ext := txctx.Ext(ctx, r.db)
+
 	query, args, err := r.sq.Select("team_id").
 		From("users").
-		Where(sq.Eq{"id": authorID}).
+		Where(sq.Eq{"id": authorID, "deleted_at": nil}).
 		ToSql()
 	if err != nil {
 		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
 	}
 
 	var teamID int
-	if err := r.db.GetContext(ctx, &teamID, query, args...); err != nil {
+	if err := sqlx.GetContext(ctx, ext, &teamID, query, args...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return 0, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, authorID)
 		}
@@ -55,68 +81,208 @@ func (r *PullRequestRepository) GetAuthorTeamID(ctx context.Context, authorID st
 	return teamID, nil
 }
 
+func (r *PullRequestRepository) IsUserActive(ctx context.Context, userID string) (bool, error) {
+	const op = "internal.repository.postgres.IsUserActive"
+
+	ext := txctx.Ext(ctx, r.db)
+
+	query, args, err := r.sq.Select("is_active").
+		From("users").
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var isActive bool
+	if err := sqlx.GetContext(ctx, ext, &isActive, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+		}
+
+		return false, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return isActive, nil
+}
+
+// GetRandomActiveReviewers samples the reviewers in the database, so that only the chosen IDs leave it
+// however big the team is. TABLESAMPLE is not used: it samples the table before the filters, and could
+// return fewer reviewers than the team has to offer.
 func (r *PullRequestRepository) GetRandomActiveReviewers(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error) {
 	const op = "internal.repository.postgres.GetRandomActiveReviewers"
 
+	ext := txctx.Ext(ctx, r.db)
+
 	queryBuilder := r.sq.Select("id").
 		From("users").
-		Where(sq.Eq{"team_id": teamID, "is_active": true})
+		Where(sq.Eq{"is_active": true, "deleted_at": nil}).
+		Where(teamPool("id", teamID))
 
 	if len(excludeUserIDs) > 0 {
 		queryBuilder = queryBuilder.Where(sq.NotEq{"id": excludeUserIDs})
 	}
 
-	query, args, err := queryBuilder.ToSql()
+	query, args, err := queryBuilder.
+		OrderBy("random()").
+		Limit(uint64(count)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	candidateIDs := []string{}
+	if err := sqlx.SelectContext(ctx, ext, &candidateIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return candidateIDs, nil
+}
+
+func (r *PullRequestRepository) GetLeastLoadedActiveReviewers(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error) {
+	const op = "internal.repository.postgres.GetLeastLoadedActiveReviewers"
+
+	ext := txctx.Ext(ctx, r.db)
+
+	queryBuilder := r.sq.Select("u.id").
+		From("users u").
+		LeftJoin("reviewers r ON r.user_id = u.id").
+		LeftJoin("pull_requests pr ON pr.id = r.pull_request_id AND pr.status = 'OPEN'").
+		Where(sq.Eq{"u.is_active": true, "u.deleted_at": nil}).
+		Where(teamPool("u.id", teamID))
+
+	if len(excludeUserIDs) > 0 {
+		queryBuilder = queryBuilder.Where(sq.NotEq{"u.id": excludeUserIDs})
+	}
+
+	query, args, err := queryBuilder.
+		GroupBy("u.id").
+		OrderBy("COUNT(pr.id)", "random()").
+		Limit(uint64(count)).
+		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
 	}
 
-	var allCandidateIDs []string
-	if err := r.db.SelectContext(ctx, &allCandidateIDs, query, args...); err != nil {
+	candidateIDs := []string{}
+	if err := sqlx.SelectContext(ctx, ext, &candidateIDs, query, args...); err != nil {
 		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
 	}
 
-	numCandidates := len(allCandidateIDs)
-	if numCandidates == 0 {
-		return []string{}, nil
+	return candidateIDs, nil
+}
+
+func (r *PullRequestRepository) GetLeastRecentlyAssignedActiveReviewers(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error) {
+	const op = "internal.repository.postgres.GetLeastRecentlyAssignedActiveReviewers"
+
+	ext := txctx.Ext(ctx, r.db)
+
+	queryBuilder := r.sq.Select("u.id").
+		From("users u").
+		LeftJoin("assignment_history h ON h.user_id = u.id").
+		Where(sq.Eq{"u.is_active": true, "u.deleted_at": nil}).
+		Where(teamPool("u.id", teamID))
+
+	if len(excludeUserIDs) > 0 {
+		queryBuilder = queryBuilder.Where(sq.NotEq{"u.id": excludeUserIDs})
+	}
+
+	query, args, err := queryBuilder.
+		GroupBy("u.id").
+		OrderBy("MAX(h.created_at) NULLS FIRST", "u.id").
+		Limit(uint64(count)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	candidateIDs := []string{}
+	if err := sqlx.SelectContext(ctx, ext, &candidateIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
 	}
 
-	if numCandidates <= count {
-		return allCandidateIDs, nil
+	return candidateIDs, nil
+}
+
+func (r *PullRequestRepository) GetReplacementAlternatives(ctx context.Context, teamID int, excludeUserIDs []string, limit int) ([]domain.ReplacementAlternative, error) {
+	const op = "internal.repository.postgres.GetReplacementAlternatives"
+
+	ext := txctx.Ext(ctx, r.db)
+
+	// An alternative is reported with the primary team of the user.
+	const isMember = "u.id IN (SELECT user_id FROM user_teams WHERE team_id = ?)"
+
+	ranked := r.sq.Select("u.id AS user_id", "u.username", "u.team_id", "t.name AS team_name").
+		Column(sq.Expr("CASE WHEN "+isMember+" THEN ? ELSE ? END AS reason", teamID, domain.AlternativeInactive, domain.AlternativeOtherTeam)).
+		Column("COUNT(pr.id) AS open_reviews").
+		Column(sq.Expr("ROW_NUMBER() OVER (PARTITION BY "+isMember+" ORDER BY COUNT(pr.id), u.id) AS rank", teamID)).
+		From("users u").
+		Join("teams t ON t.id = u.team_id").
+		LeftJoin("reviewers r ON r.user_id = u.id").
+		LeftJoin("pull_requests pr ON pr.id = r.pull_request_id AND pr.status = 'OPEN'").
+		Where(sq.Eq{"u.deleted_at": nil, "t.deleted_at": nil}).
+		Where(sq.Or{
+			sq.And{sq.Expr(isMember, teamID), sq.Eq{"u.is_active": false}},
+			sq.And{sq.Expr("NOT "+isMember, teamID), sq.Eq{"u.is_active": true}},
+		}).
+		GroupBy("u.id", "u.username", "u.team_id", "t.name")
+
+	if len(excludeUserIDs) > 0 {
+		ranked = ranked.Where(sq.NotEq{"u.id": excludeUserIDs})
 	}
 
-	result := make([]string, count)
+	query, args, err := r.sq.Select("user_id", "username", "team_id", "team_name", "reason", "open_reviews").
+		FromSelect(ranked, "ranked").
+		Where(sq.LtOrEq{"rank": limit}).
+		OrderBy("reason", "open_reviews", "user_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
 
-	for i := 0; i < count; i++ {
-		idx := rand.Intn(numCandidates-i) + i
-		allCandidateIDs[i], allCandidateIDs[idx] = allCandidateIDs[idx], allCandidateIDs[i]
-		result[i] = allCandidateIDs[i]
+	alternatives := []domain.ReplacementAlternative{}
+	if err := sqlx.SelectContext(ctx, ext, &alternatives, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
 	}
 
-	return result, nil
+	return alternatives, nil
+}
+
+// prColumns lists the pull_requests columns that map onto domain.PullRequest.
+var prColumns = []string{
+	"id", "name", "author_id", "status", "description", "external_url", "custom_fields", "assignment_policy", "assign_at",
+	"need_more_reviewers", "created_at", "merged_at",
 }
 
-func (r *PullRequestRepository) CreatePR(ctx context.Context, tx *sqlx.Tx, pr *domain.PullRequest) error {
+func (r *PullRequestRepository) CreatePR(ctx context.Context, pr *domain.PullRequest) error {
 	const op = "internal.repository.postgres.CreatePR"
 
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	query, args, err := r.sq.Insert("pull_requests").
-		Columns("id", "name", "author_id", "status", "need_more_reviewers").
-		Values(pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.NeedMoreReviewers).
+		Columns("id", "name", "author_id", "status", "description", "external_url", "custom_fields", "assignment_policy",
+			"assign_at", "need_more_reviewers", "created_at").
+		Values(pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.Description, pr.ExternalURL, jsonObjectOrEmpty(pr.CustomFields),
+			jsonOrNull(pr.AssignmentPolicy), pr.AssignAt, pr.NeedMoreReviewers, timestampOrNow(pr.CreatedAt)).
+		Suffix("ON CONFLICT (id) DO NOTHING RETURNING created_at").
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("%s: failed to build insert query: %w", op, err)
 	}
 
-	_, err = tx.ExecContext(ctx, query, args...)
-	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			if pqErr.Code == "23505" {
-				return &apperrors.PRAlreadyExistsError{PRID: pr.ID}
-			}
+	// ON CONFLICT makes a concurrent duplicate wait for the first insert to finish
+	// instead of aborting the transaction with a unique violation; the duplicate returns no row.
+	// The stored created_at is read back, so that the caller reports the timestamp at the precision of the column.
+	if err := tx.QueryRowxContext(ctx, query, args...).Scan(&pr.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &apperrors.PRAlreadyExistsError{PRID: pr.ID}
+		}
 
-			if pqErr.Code == "23503" {
-				return fmt.Errorf("%s: %w: author with id '%s' not found", op, apperrors.ErrNotFound, pr.AuthorID)
-			}
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return fmt.Errorf("%s: %w: author with id '%s' not found", op, apperrors.ErrNotFound, pr.AuthorID)
 		}
 
 		return fmt.Errorf("%s: failed to execute insert: %w", op, err)
@@ -125,9 +291,14 @@ func (r *PullRequestRepository) CreatePR(ctx context.Context, tx *sqlx.Tx, pr *d
 	return nil
 }
 
-func (r *PullRequestRepository) AssignReviewers(ctx context.Context, tx *sqlx.Tx, prID string, reviewerIDs []string) error {
+func (r *PullRequestRepository) AssignReviewers(ctx context.Context, prID string, reviewerIDs []string) error {
 	const op = "internal.repository.postgres.AssignReviewers"
 
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	insertBuilder := r.sq.Insert("reviewers").
 		Columns("pull_request_id", "user_id")
 
@@ -141,15 +312,39 @@ func (r *PullRequestRepository) AssignReviewers(ctx context.Context, tx *sqlx.Tx
 	}
 
 	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		if assignmentErr := reviewerConstraintError(err, prID); assignmentErr != nil {
+			return fmt.Errorf("%s: %w", op, assignmentErr)
+		}
+
 		return fmt.Errorf("%s: failed to execute insert: %w", op, err)
 	}
 
 	return nil
 }
 
-func (r *PullRequestRepository) GetReviewerIDs(ctx context.Context, ext sqlx.ExtContext, prID string) ([]string, error) {
+// reviewerConstraintError translates a violation of the constraints of the reviewers table
+// into an InvalidAssignmentError, or returns nil if err is not one.
+func reviewerConstraintError(err error, prID string) error {
+	pqErr, ok := err.(*pq.Error)
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case pqErr.Code == "23505" && pqErr.Constraint == "reviewers_pkey":
+		return &apperrors.InvalidAssignmentError{PRID: prID, Reason: "reviewer is already assigned"}
+	case pqErr.Code == "23514" && pqErr.Constraint == "reviewers_not_author":
+		return &apperrors.InvalidAssignmentError{PRID: prID, Reason: "author cannot review their own pull request"}
+	default:
+		return nil
+	}
+}
+
+func (r *PullRequestRepository) GetReviewerIDs(ctx context.Context, prID string) ([]string, error) {
 	const op = "internal.repository.postgres.GetReviewerIDs"
 
+	ext := txctx.Ext(ctx, r.reads())
+
 	query, args, err := r.sq.Select("user_id").
 		From("reviewers").
 		Where(sq.Eq{"pull_request_id": prID}).
@@ -166,6 +361,28 @@ func (r *PullRequestRepository) GetReviewerIDs(ctx context.Context, ext sqlx.Ext
 	return reviewerIDs, nil
 }
 
+func (r *PullRequestRepository) GetReviews(ctx context.Context, prID string) ([]domain.Review, error) {
+	const op = "internal.repository.postgres.GetReviews"
+
+	ext := txctx.Ext(ctx, r.reads())
+
+	query, args, err := r.sq.Select("user_id", "review_state", "reviewed_at").
+		From("reviewers").
+		Where(sq.Eq{"pull_request_id": prID}).
+		OrderBy("user_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	reviews := []domain.Review{}
+	if err := sqlx.SelectContext(ctx, ext, &reviews, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to select reviews: %w", op, err)
+	}
+
+	return reviews, nil
+}
+
 func (r *PullRequestRepository) GetPRByIDWithReviewers(ctx context.Context, prID string) (*domain.PullRequest, error) {
 	const op = "internal.repository.postgres.GetPRByIDWithReviewers"
 
@@ -174,21 +391,32 @@ func (r *PullRequestRepository) GetPRByIDWithReviewers(ctx context.Context, prID
 		return nil, err
 	}
 
-	reviewerIDs, err := r.GetReviewerIDs(ctx, r.db, prID)
+	reviewerIDs, err := r.GetReviewerIDs(ctx, prID)
 	if err != nil {
 		r.log.Error("failed to get reviewers for PR", sl.Err(err), slog.String("pr_id", prID))
 		return nil, fmt.Errorf("%s: failed to get reviewers: %w", op, err)
 	}
 
+	reviews, err := r.GetReviews(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	pr.ReviewerIDs = reviewerIDs
+	pr.Reviews = reviews
 
 	return pr, nil
 }
 
-func (r *PullRequestRepository) GetPRByIDWithLock(ctx context.Context, tx *sqlx.Tx, prID string) (*domain.PullRequest, error) {
+func (r *PullRequestRepository) GetPRByIDWithLock(ctx context.Context, prID string) (*domain.PullRequest, error) {
 	const op = "internal.repository.postgres.GetPRByIDWithLock"
 
-	query, args, err := r.sq.Select("id", "name", "author_id", "status", "need_more_reviewers", "created_at", "merged_at").
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := r.sq.Select(prColumns...).
 		From("pull_requests").
 		Where(sq.Eq{"id": prID}).
 		Suffix("FOR UPDATE").
@@ -212,7 +440,7 @@ func (r *PullRequestRepository) GetPRByIDWithLock(ctx context.Context, tx *sqlx.
 func (r *PullRequestRepository) GetPRByID(ctx context.Context, prID string) (*domain.PullRequest, error) {
 	const op = "internal.repository.postgres.GetPRByID"
 
-	query, args, err := r.sq.Select("id", "name", "author_id", "status", "need_more_reviewers", "created_at", "merged_at").
+	query, args, err := r.sq.Select(prColumns...).
 		From("pull_requests").
 		Where(sq.Eq{"id": prID}).
 		ToSql()
@@ -221,7 +449,7 @@ func (r *PullRequestRepository) GetPRByID(ctx context.Context, prID string) (*do
 	}
 
 	var pr domain.PullRequest
-	if err := r.db.GetContext(ctx, &pr, query, args...); err != nil {
+	if err := r.reads().GetContext(ctx, &pr, query, args...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("%s: %w: PR with id '%s'", op, apperrors.ErrNotFound, prID)
 		}
@@ -232,49 +460,91 @@ func (r *PullRequestRepository) GetPRByID(ctx context.Context, prID string) (*do
 	return &pr, nil
 }
 
-func (r *PullRequestRepository) UpdatePRStatus(ctx context.Context, tx *sqlx.Tx, prID string, status api.PullRequestStatus, mergedAt time.Time) error {
+func (r *PullRequestRepository) UpdatePRStatus(ctx context.Context, prID string, status api.PullRequestStatus, mergedAt time.Time) (*time.Time, error) {
 	const op = "internal.repository.postgres.UpdatePRStatus"
 
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	updateBuilder := r.sq.Update("pull_requests").
 		Set("status", status).
-		Where(sq.Eq{"id": prID})
+		Where(sq.Eq{"id": prID}).
+		Suffix("RETURNING merged_at")
 
-	if status == api.PullRequestStatusMERGED {
-		updateBuilder = updateBuilder.Set("merged_at", mergedAt).Set("need_more_reviewers", false)
+	switch status {
+	case api.PullRequestStatusMERGED:
+		updateBuilder = updateBuilder.Set("merged_at", mergedAt.UTC()).Set("need_more_reviewers", false)
+	case api.PullRequestStatusCLOSED:
+		updateBuilder = updateBuilder.Set("need_more_reviewers", false)
 	}
 
 	query, args, err := updateBuilder.ToSql()
 	if err != nil {
-		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
 	}
 
-	res, err := tx.ExecContext(ctx, query, args...)
+	var storedMergedAt *time.Time
+	if err := tx.QueryRowxContext(ctx, query, args...).Scan(&storedMergedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: PR with id '%s'", op, apperrors.ErrNotFound, prID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	return storedMergedAt, nil
+}
+
+func (r *PullRequestRepository) GetUserTeamIDs(ctx context.Context, userID string) ([]int, error) {
+	const op = "internal.repository.postgres.GetUserTeamIDs"
+
+	ext := txctx.Ext(ctx, r.db)
+
+	query, args, err := r.sq.Select("ut.team_id").
+		From("users u").
+		Join("user_teams ut ON ut.user_id = u.id").
+		Join("teams t ON t.id = ut.team_id").
+		Where(sq.Eq{"u.id": userID, "u.deleted_at": nil, "t.deleted_at": nil}).
+		OrderBy("ut.team_id").
+		ToSql()
 	if err != nil {
-		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
 	}
 
-	if rowsAffected, err := res.RowsAffected(); err == nil && rowsAffected == 0 {
-		return fmt.Errorf("%s: %w: PR with id '%s'", op, apperrors.ErrNotFound, prID)
+	teamIDs := []int{}
+	if err := sqlx.SelectContext(ctx, ext, &teamIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
 	}
 
-	return nil
+	// A user always belongs to a team, so a user without one is deleted or does not exist.
+	if len(teamIDs) == 0 {
+		return nil, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+	}
+
+	return teamIDs, nil
 }
 
-func (r *PullRequestRepository) GetReviewerTeamID(ctx context.Context, reviewerID string) (int, error) {
-	const op = "internal.repository.postgres.GetReviewerTeamID"
+func (r *PullRequestRepository) GetPRTeamID(ctx context.Context, prID string) (int, error) {
+	const op = "internal.repository.postgres.GetPRTeamID"
 
-	query, args, err := r.sq.Select("team_id").
-		From("users").
-		Where(sq.Eq{"id": reviewerID}).
+	ext := txctx.Ext(ctx, r.db)
+
+	query, args, err := r.sq.Select("u.team_id").
+		From("pull_requests pr").
+		Join("users u ON u.id = pr.author_id").
+		Where(sq.Eq{"pr.id": prID}).
+		Where(sq.NotEq{"u.team_id": nil}).
 		ToSql()
 	if err != nil {
 		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
 	}
 
 	var teamID int
-	if err := r.db.GetContext(ctx, &teamID, query, args...); err != nil {
+	if err := sqlx.GetContext(ctx, ext, &teamID, query, args...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return 0, fmt.Errorf("%s: %w: reviewer user with id '%s'", op, apperrors.ErrNotFound, reviewerID)
+			return 0, fmt.Errorf("%s: %w: team of PR with id '%s'", op, apperrors.ErrNotFound, prID)
 		}
 
 		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
@@ -283,9 +553,14 @@ func (r *PullRequestRepository) GetReviewerTeamID(ctx context.Context, reviewerI
 	return teamID, nil
 }
 
-func (r *PullRequestRepository) ReplaceReviewer(ctx context.Context, tx *sqlx.Tx, prID string, oldReviewerID string, newReviewerID string) error {
+func (r *PullRequestRepository) ReplaceReviewer(ctx context.Context, prID string, oldReviewerID string, newReviewerID string) error {
 	const op = "internal.repository.postgres.ReplaceReviewer"
 
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	deleteQuery, deleteArgs, err := r.sq.Delete("reviewers").
 		Where(sq.Eq{"pull_request_id": prID, "user_id": oldReviewerID}).
 		ToSql()
@@ -306,21 +581,90 @@ func (r *PullRequestRepository) ReplaceReviewer(ctx context.Context, tx *sqlx.Tx
 	}
 
 	if _, err := tx.ExecContext(ctx, insertQuery, insertArgs...); err != nil {
+		if assignmentErr := reviewerConstraintError(err, prID); assignmentErr != nil {
+			return fmt.Errorf("%s: %w", op, assignmentErr)
+		}
+
 		return fmt.Errorf("%s: failed to execute insert: %w", op, err)
 	}
 
 	return nil
 }
 
-func (r *PullRequestRepository) GetReviewAssignments(ctx context.Context, userID string) ([]domain.PullRequest, error) {
+// ReplaceReviewers deletes the old reviewers and inserts the new ones joined with a VALUES list of the
+// replacements. A data-modifying CTE the main query does not read runs after it, so the insert reads
+// the deleted rows to be ordered after the delete: a batch may hand a review over to a user who hands
+// their own review over in the same batch.
+func (r *PullRequestRepository) ReplaceReviewers(ctx context.Context, replacements []domain.ReviewerReplacement) error {
+	const op = "internal.repository.postgres.ReplaceReviewers"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(replacements) == 0 {
+		return nil
+	}
+
+	rows := make([]string, 0, len(replacements))
+	args := make([]any, 0, 3*len(replacements))
+
+	for _, replacement := range replacements {
+		rows = append(rows, "(?, ?, ?)")
+		args = append(args, replacement.PullRequestID, replacement.OldReviewerID, replacement.NewReviewerID)
+	}
+
+	query, args, err := r.sq.Insert("reviewers").
+		Prefix(
+			"WITH replacements (pull_request_id, old_user_id, new_user_id) AS (VALUES "+strings.Join(rows, ", ")+"), "+
+				"removed AS (DELETE FROM reviewers r USING replacements v "+
+				"WHERE r.pull_request_id = v.pull_request_id AND r.user_id = v.old_user_id RETURNING r.user_id)",
+			args...,
+		).
+		Columns("pull_request_id", "user_id").
+		Select(sq.Select("v.pull_request_id", "v.new_user_id").
+			From("replacements v").
+			Where("(SELECT count(*) FROM removed) >= 0")).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		if assignmentErr := reviewerConstraintError(err, replacedPRID(err, replacements)); assignmentErr != nil {
+			return fmt.Errorf("%s: %w", op, assignmentErr)
+		}
+
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
+
+// replacedPRID finds the pull request of a batch of replacements a constraint violation is about. The
+// detail of the violation starts with the pull request ID, the first column of the reviewers table.
+func replacedPRID(err error, replacements []domain.ReviewerReplacement) string {
+	if pqErr, ok := err.(*pq.Error); ok {
+		for _, replacement := range replacements {
+			if strings.Contains(pqErr.Detail, "("+replacement.PullRequestID+",") {
+				return replacement.PullRequestID
+			}
+		}
+	}
+
+	return replacements[0].PullRequestID
+}
+
+func (r *PullRequestRepository) GetReviewAssignments(ctx context.Context, userID string, customFields map[string]string) ([]domain.PullRequest, error) {
 	const op = "internal.repository.postgres.GetReviewAssignments"
 	log := r.log.With(slog.String("op", op), slog.String("user_id", userID))
 
 	query, args, err := r.sq.Select(
-		"pr.id", "pr.name", "pr.author_id", "pr.status",
+		"pr.id", "pr.name", "pr.author_id", "pr.status", "pr.custom_fields",
 	).From("pull_requests pr").
 		Join("reviewers r ON pr.id = r.pull_request_id").
-		Where(sq.Eq{"r.user_id": userID}).
+		Where(append(sq.And{sq.Eq{"r.user_id": userID}}, customFieldsCondition("pr.custom_fields", customFields)...)).
 		OrderBy("pr.created_at DESC").
 		ToSql()
 	if err != nil {
@@ -328,7 +672,7 @@ func (r *PullRequestRepository) GetReviewAssignments(ctx context.Context, userID
 	}
 
 	var prs []domain.PullRequest
-	if err := r.db.SelectContext(ctx, &prs, query, args...); err != nil {
+	if err := r.reads().SelectContext(ctx, &prs, query, args...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			log.Info("no review assignments found")
 			return []domain.PullRequest{}, nil
@@ -340,28 +684,214 @@ func (r *PullRequestRepository) GetReviewAssignments(ctx context.Context, userID
 	return prs, nil
 }
 
-func (r *PullRequestRepository) GetUserStats(ctx context.Context) ([]domain.Stats, error) {
-	const op = "internal.repository.postgres.GetUserStats"
+// searchCondition matches pull requests against a web search style query, e.g. `search -draft "add api"`.
+func searchCondition(filter domain.PRSearchFilter) sq.And {
+	cond := sq.And{sq.Expr("search_vector @@ websearch_to_tsquery('simple', ?)", filter.Query)}
+	if filter.Status != "" {
+		cond = append(cond, sq.Eq{"status": filter.Status})
+	}
 
-	query, args, err := r.sq.Select(
-		"u.id as user_id",
-		"u.username",
-		"COUNT(CASE WHEN pr.status = 'OPEN' THEN 1 END) as open_reviews",
-		"COUNT(CASE WHEN pr.status = 'MERGED' THEN 1 END) as merged_reviews",
-	).
+	return append(cond, customFieldsCondition("custom_fields", filter.CustomFields)...)
+}
+
+// customFieldsCondition matches pull requests whose custom fields, read as text with ->>, equal the given values.
+// Comparing text lets one filter serve teams that gave the same key different types.
+func customFieldsCondition(column string, customFields map[string]string) sq.And {
+	cond := sq.And{}
+	for _, key := range slices.Sorted(maps.Keys(customFields)) {
+		cond = append(cond, sq.Expr(column+" ->> ? = ?", key, customFields[key]))
+	}
+
+	return cond
+}
+
+func (r *PullRequestRepository) SearchPRs(ctx context.Context, filter domain.PRSearchFilter) ([]domain.PullRequest, int, error) {
+	const op = "internal.repository.postgres.SearchPRs"
+
+	countQuery, args, err := r.sq.Select("COUNT(*)").
+		From("pull_requests").
+		Where(searchCondition(filter)).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to build count query: %w", op, err)
+	}
+
+	var total int
+	if err := r.reads().GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to count matches: %w", op, err)
+	}
+
+	if total == 0 {
+		return []domain.PullRequest{}, 0, nil
+	}
+
+	query, args, err := r.sq.Select(prColumns...).
+		From("pull_requests").
+		Where(searchCondition(filter)).
+		OrderByClause("ts_rank(search_vector, websearch_to_tsquery('simple', ?)) DESC", filter.Query).
+		OrderBy("created_at DESC", "id").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset)).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	prs := []domain.PullRequest{}
+	if err := r.reads().SelectContext(ctx, &prs, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return prs, total, nil
+}
+
+// listCondition composes the filters of a pull request list; the filters left unset are omitted.
+func listCondition(filter domain.PRListFilter) sq.And {
+	cond := sq.And{}
+	if filter.Status != "" {
+		cond = append(cond, sq.Eq{"status": filter.Status})
+	}
+
+	if filter.AuthorID != "" {
+		cond = append(cond, sq.Eq{"author_id": filter.AuthorID})
+	}
+
+	if filter.TeamID != 0 {
+		cond = append(cond, sq.Expr("author_id IN (SELECT id FROM users WHERE team_id = ? AND deleted_at IS NULL)", filter.TeamID))
+	}
+
+	if filter.CreatedFrom != nil {
+		cond = append(cond, sq.GtOrEq{"created_at": *filter.CreatedFrom})
+	}
+
+	if filter.CreatedTo != nil {
+		cond = append(cond, sq.Lt{"created_at": *filter.CreatedTo})
+	}
+
+	if filter.After != nil {
+		cond = append(cond, sq.Expr("(created_at, id) < (?, ?)", filter.After.CreatedAt, filter.After.ID))
+	}
+
+	return cond
+}
+
+func (r *PullRequestRepository) ListPRs(ctx context.Context, filter domain.PRListFilter) ([]domain.PullRequest, error) {
+	const op = "internal.repository.postgres.ListPRs"
+
+	query, args, err := r.sq.Select(prColumns...).
+		From("pull_requests").
+		Where(listCondition(filter)).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	prs := []domain.PullRequest{}
+	if err := r.reads().SelectContext(ctx, &prs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	if len(prs) == 0 {
+		return prs, nil
+	}
+
+	prIDs := make([]string, len(prs))
+	for i := range prs {
+		prIDs[i] = prs[i].ID
+		prs[i].ReviewerIDs = []string{}
+	}
+
+	reviewersQuery, args, err := r.sq.Select("pull_request_id", "user_id").
+		From("reviewers").
+		Where(sq.Eq{"pull_request_id": prIDs}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build reviewers query: %w", op, err)
+	}
+
+	var reviewers []domain.Reviewer
+	if err := r.reads().SelectContext(ctx, &reviewers, reviewersQuery, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to select reviewers: %w", op, err)
+	}
+
+	return mapReviewersToPRs(prs, reviewers), nil
+}
+
+func (r *PullRequestRepository) statsQuery(period domain.StatsPeriod) sq.SelectBuilder {
+	openCond := sq.And{sq.Eq{"pr.status": api.PullRequestStatusOPEN}}
+	openCond = append(openCond, periodCond("pr.created_at", period)...)
+
+	mergedCond := sq.And{sq.Eq{"pr.status": api.PullRequestStatusMERGED}}
+	mergedCond = append(mergedCond, periodCond("pr.merged_at", period)...)
+
+	firstReviewCond := sq.And{sq.NotEq{"r.first_reviewed_at": nil}}
+	firstReviewCond = append(firstReviewCond, periodCond("r.first_reviewed_at", period)...)
+
+	query := r.sq.Select("u.id as user_id", "u.username").
+		Column(sq.Expr("COUNT(CASE WHEN ? THEN 1 END) as open_reviews", openCond)).
+		Column(sq.Expr("COUNT(CASE WHEN ? THEN 1 END) as merged_reviews", mergedCond)).
+		Column(sq.Expr("COUNT(CASE WHEN ? THEN 1 END) as first_reviews", firstReviewCond))
+
+	for _, column := range durationColumns("first_review", "r.first_reviewed_at - r.assigned_at", firstReviewCond) {
+		query = query.Column(column)
+	}
+
+	for _, column := range durationColumns("merge", "pr.merged_at - r.assigned_at", mergedCond) {
+		query = query.Column(column)
+	}
+
+	return query.
 		From("users u").
 		LeftJoin("reviewers r ON u.id = r.user_id").
 		LeftJoin("pull_requests pr ON r.pull_request_id = pr.id").
+		Where(sq.Eq{"u.deleted_at": nil}).
 		GroupBy("u.id", "u.username").
-		OrderBy("u.username").
-		ToSql()
+		OrderBy("u.username COLLATE " + usernameCollation)
+}
+
+// durationColumns summarizes the interval over the rows matching cond into the prefix_avg_seconds,
+// prefix_p50_seconds and prefix_p90_seconds columns, which are NULL when no row matches.
+func durationColumns(prefix, interval string, cond sq.Sqlizer) []sq.Sqlizer {
+	seconds := "CASE WHEN ? THEN EXTRACT(EPOCH FROM " + interval + ") END"
+
+	return []sq.Sqlizer{
+		sq.Expr("AVG("+seconds+")::float8 AS "+prefix+"_avg_seconds", cond),
+		sq.Expr("percentile_cont(0.5) WITHIN GROUP (ORDER BY "+seconds+") AS "+prefix+"_p50_seconds", cond),
+		sq.Expr("percentile_cont(0.9) WITHIN GROUP (ORDER BY "+seconds+") AS "+prefix+"_p90_seconds", cond),
+	}
+}
+
+// periodCond restricts column to the period; it is empty for an unbounded period.
+func periodCond(column string, period domain.StatsPeriod) sq.And {
+	cond := sq.And{}
+
+	if period.From != nil {
+		cond = append(cond, sq.GtOrEq{column: *period.From})
+	}
+
+	if period.To != nil {
+		cond = append(cond, sq.Lt{column: *period.To})
+	}
+
+	return cond
+}
+
+func (r *PullRequestRepository) GetUserStats(ctx context.Context, period domain.StatsPeriod) ([]domain.Stats, error) {
+	const op = "internal.repository.postgres.GetUserStats"
+
+	ext := txctx.Ext(ctx, r.reads())
+
+	query, args, err := r.statsQuery(period).ToSql()
 
 	if err != nil {
 		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
 	}
 
 	var stats []domain.Stats
-	if err := r.db.SelectContext(ctx, &stats, query, args...); err != nil {
+	if err := sqlx.SelectContext(ctx, ext, &stats, query, args...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return []domain.Stats{}, nil
 		}
@@ -372,6 +902,135 @@ func (r *PullRequestRepository) GetUserStats(ctx context.Context) ([]domain.Stat
 	return stats, nil
 }
 
+func (r *PullRequestRepository) GetLeaderboard(ctx context.Context, period domain.StatsPeriod, limit int) ([]domain.LeaderboardEntry, error) {
+	const op = "internal.repository.postgres.GetLeaderboard"
+
+	mergedCond := sq.And{sq.Eq{"pr.status": api.PullRequestStatusMERGED}}
+	mergedCond = append(mergedCond, periodCond("pr.merged_at", period)...)
+
+	query, args, err := r.sq.Select("u.id AS user_id", "u.username", "COUNT(*) AS merged_reviews").
+		From("pull_requests pr").
+		Join("reviewers r ON r.pull_request_id = pr.id").
+		Join("users u ON u.id = r.user_id").
+		Where(mergedCond).
+		Where(sq.Eq{"u.deleted_at": nil}).
+		GroupBy("u.id", "u.username").
+		OrderBy("merged_reviews DESC", "u.username COLLATE "+usernameCollation, "u.id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	entries := []domain.LeaderboardEntry{}
+	if err := r.reads().SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+func (r *PullRequestRepository) GetOpenPRAgeStats(ctx context.Context) ([]domain.OpenPRAgeStats, error) {
+	const op = "internal.repository.postgres.GetOpenPRAgeStats"
+
+	const age = "EXTRACT(EPOCH FROM NOW() - pr.created_at)"
+
+	query, args, err := r.sq.Select(
+		"t.name AS team_name",
+		"COUNT(*) AS open_prs",
+		"percentile_cont(0.5) WITHIN GROUP (ORDER BY "+age+") AS p50_seconds",
+		"percentile_cont(0.9) WITHIN GROUP (ORDER BY "+age+") AS p90_seconds",
+		"MAX("+age+") AS max_seconds",
+	).
+		From("pull_requests pr").
+		Join("users u ON u.id = pr.author_id").
+		Join("teams t ON t.id = u.team_id").
+		Where(sq.Eq{"pr.status": api.PullRequestStatusOPEN, "u.deleted_at": nil, "t.deleted_at": nil}).
+		GroupBy("t.name").
+		OrderBy("t.name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	stats := []domain.OpenPRAgeStats{}
+	if err := r.db.SelectContext(ctx, &stats, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+func (r *PullRequestRepository) GetTeamStats(ctx context.Context, period domain.StatsPeriod) ([]domain.TeamStats, error) {
+	const op = "internal.repository.postgres.GetTeamStats"
+
+	ext := txctx.Ext(ctx, r.reads())
+
+	firstReviewCond := sq.And{sq.NotEq{"fr.first_reviewed_at": nil}}
+	firstReviewCond = append(firstReviewCond, periodCond("fr.first_reviewed_at", period)...)
+
+	mergedCond := sq.And{sq.Eq{"pr.status": api.PullRequestStatusMERGED}}
+	mergedCond = append(mergedCond, periodCond("pr.merged_at", period)...)
+
+	builder := r.sq.Select("t.name AS team_name").
+		Column(sq.Expr("COUNT(CASE WHEN ? THEN 1 END) AS first_reviewed_prs", firstReviewCond))
+
+	for _, column := range durationColumns("first_review", "fr.first_reviewed_at - pr.created_at", firstReviewCond) {
+		builder = builder.Column(column)
+	}
+
+	builder = builder.Column(sq.Expr("COUNT(CASE WHEN ? THEN 1 END) AS merged_prs", mergedCond))
+
+	for _, column := range durationColumns("merge", "pr.merged_at - pr.created_at", mergedCond) {
+		builder = builder.Column(column)
+	}
+
+	query, args, err := builder.
+		From("pull_requests pr").
+		Join("users u ON u.id = pr.author_id").
+		Join("teams t ON t.id = u.team_id").
+		LeftJoin("(SELECT pull_request_id, MIN(first_reviewed_at) AS first_reviewed_at FROM reviewers GROUP BY pull_request_id) fr ON fr.pull_request_id = pr.id").
+		Where(sq.Or{firstReviewCond, mergedCond}).
+		Where(sq.Eq{"u.deleted_at": nil, "t.deleted_at": nil}).
+		GroupBy("t.name").
+		OrderBy("t.name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	stats := []domain.TeamStats{}
+	if err := sqlx.SelectContext(ctx, ext, &stats, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+func (r *PullRequestRepository) GetStatsByUserIDs(ctx context.Context, userIDs []string) ([]domain.Stats, error) {
+	const op = "internal.repository.postgres.GetStatsByUserIDs"
+
+	ext := txctx.Ext(ctx, r.reads())
+
+	if len(userIDs) == 0 {
+		return []domain.Stats{}, nil
+	}
+
+	query, args, err := r.statsQuery(domain.StatsPeriod{}).
+		Where(sq.Eq{"u.id": userIDs}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	stats := []domain.Stats{}
+	if err := sqlx.SelectContext(ctx, ext, &stats, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return stats, nil
+}
+
 func mapReviewersToPRs(prs []domain.PullRequest, reviewers []domain.Reviewer) []domain.PullRequest {
 	prMap := make(map[string]*domain.PullRequest, len(prs))
 	for i := range prs {
@@ -384,17 +1043,17 @@ func mapReviewersToPRs(prs []domain.PullRequest, reviewers []domain.Reviewer) []
 		}
 	}
 
-	result := make([]domain.PullRequest, 0, len(prMap))
-	for _, pr := range prMap {
-		result = append(result, *pr)
-	}
-
-	return result
+	return prs
 }
 
-func (r *PullRequestRepository) GetOpenPRsByReviewers(ctx context.Context, tx *sqlx.Tx, userIDs []string) ([]domain.PullRequest, error) {
+func (r *PullRequestRepository) GetOpenPRsByReviewers(ctx context.Context, userIDs []string) ([]domain.PullRequest, error) {
 	const op = "internal.repository.postgres.GetOpenPRsByReviewers"
 
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	prIDsQuery, args, err := r.sq.Select("DISTINCT pull_request_id").
 		From("reviewers").
 		Join("pull_requests pr ON pr.id = reviewers.pull_request_id").
@@ -413,9 +1072,12 @@ func (r *PullRequestRepository) GetOpenPRsByReviewers(ctx context.Context, tx *s
 		return []domain.PullRequest{}, nil
 	}
 
+	// Rows are locked in id order so that concurrent transactions over overlapping
+	// sets of PRs wait for each other instead of deadlocking.
 	prsQuery, args, err := r.sq.Select("id", "name", "author_id", "status").
 		From("pull_requests").
 		Where(sq.Eq{"id": prIDs}).
+		OrderBy("id").
 		Suffix("FOR UPDATE").
 		ToSql()
 	if err != nil {
@@ -444,3 +1106,174 @@ func (r *PullRequestRepository) GetOpenPRsByReviewers(ctx context.Context, tx *s
 
 	return resultPRs, nil
 }
+
+// CountOpenPRsByAuthor locks the author with an advisory lock rather than a row lock on the user:
+// team deactivation locks user rows too, and an advisory lock cannot take part in that lock order.
+func (r *PullRequestRepository) CountOpenPRsByAuthor(ctx context.Context, authorID string) (int, error) {
+	const op = "internal.repository.postgres.CountOpenPRsByAuthor"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1, hashtext($2))", advisoryLockAuthorOpenPRs, authorID); err != nil {
+		return 0, fmt.Errorf("%s: failed to acquire advisory lock: %w", op, err)
+	}
+
+	query, args, err := r.sq.Select("COUNT(*)").
+		From("pull_requests").
+		Where(sq.Eq{"author_id": authorID, "status": api.PullRequestStatusOPEN}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var count int
+	if err := tx.GetContext(ctx, &count, query, args...); err != nil {
+		return 0, fmt.Errorf("%s: failed to count open PRs: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (r *PullRequestRepository) LockActiveUsers(ctx context.Context, userIDs []string) ([]string, error) {
+	const op = "internal.repository.postgres.LockActiveUsers"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	activeIDs := []string{}
+	if len(userIDs) == 0 {
+		return activeIDs, nil
+	}
+
+	// The rows are locked in the order of their IDs, as the deactivations lock them, to avoid deadlocks.
+	// A row locked by a deactivation is waited for and then checked again, so it is left out once deactivated.
+	query, args, err := r.sq.Select("id").
+		From("users").
+		Where(sq.Eq{"id": userIDs, "is_active": true, "deleted_at": nil}).
+		OrderBy("id").
+		Suffix("FOR SHARE").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if err := tx.SelectContext(ctx, &activeIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to lock users: %w", op, err)
+	}
+
+	return activeIDs, nil
+}
+
+func (r *PullRequestRepository) SetNeedMoreReviewers(ctx context.Context, prID string, need bool) error {
+	const op = "internal.repository.postgres.SetNeedMoreReviewers"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := r.sq.Update("pull_requests").
+		Set("need_more_reviewers", need).
+		Where(sq.Eq{"id": prID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rowsAffected, err := res.RowsAffected(); err == nil && rowsAffected == 0 {
+		return fmt.Errorf("%s: %w: PR with id '%s'", op, apperrors.ErrNotFound, prID)
+	}
+
+	return nil
+}
+
+func (r *PullRequestRepository) SetReviewState(ctx context.Context, prID string, reviewerID string, state domain.ReviewState, reviewedAt time.Time) error {
+	const op = "internal.repository.postgres.SetReviewState"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := r.sq.Update("reviewers").
+		Set("review_state", state).
+		Set("reviewed_at", reviewedAt.UTC()).
+		Set("first_reviewed_at", sq.Expr("COALESCE(first_reviewed_at, ?)", reviewedAt.UTC())).
+		Where(sq.Eq{"pull_request_id": prID, "user_id": reviewerID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rowsAffected, err := res.RowsAffected(); err == nil && rowsAffected == 0 {
+		return fmt.Errorf("%s: %w: user '%s' does not review PR '%s'", op, apperrors.ErrReviewerNotAssigned, reviewerID, prID)
+	}
+
+	return nil
+}
+
+func (r *PullRequestRepository) BackfillReviewerDurations(ctx context.Context, afterID string, limit int) (string, int, error) {
+	const op = "internal.repository.postgres.BackfillReviewerDurations"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	batchQuery, batchArgs, err := sq.Select("id", "created_at").
+		From("pull_requests").
+		Where(sq.Gt{"id": afterID}).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: failed to build batch query: %w", op, err)
+	}
+
+	// Only the reviewers whose times change are updated, so that a backfill run again rewrites nothing.
+	query, args, err := r.sq.Select().
+		Prefix(
+			"WITH batch AS ("+batchQuery+"), "+
+				"recomputed AS (SELECT r.pull_request_id, r.user_id, "+
+				"COALESCE((SELECT MAX(h.created_at) FROM assignment_history h "+
+				"WHERE h.pull_request_id = r.pull_request_id AND h.user_id = r.user_id), b.created_at) AS assigned_at, "+
+				"COALESCE(r.first_reviewed_at, r.reviewed_at) AS first_reviewed_at "+
+				"FROM reviewers r JOIN batch b ON b.id = r.pull_request_id), "+
+				"updated AS (UPDATE reviewers r SET assigned_at = c.assigned_at, first_reviewed_at = c.first_reviewed_at "+
+				"FROM recomputed c WHERE r.pull_request_id = c.pull_request_id AND r.user_id = c.user_id "+
+				"AND (r.assigned_at, r.first_reviewed_at) IS DISTINCT FROM (c.assigned_at, c.first_reviewed_at) RETURNING 1)",
+			batchArgs...,
+		).
+		Column("COALESCE((SELECT MAX(id) FROM batch), '') AS last_id").
+		Column("(SELECT count(*) FROM updated) AS updated").
+		ToSql()
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var result struct {
+		LastID  string `db:"last_id"`
+		Updated int    `db:"updated"`
+	}
+
+	if err := tx.GetContext(ctx, &result, query, args...); err != nil {
+		return "", 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return result.LastID, result.Updated, nil
+}
@@ -4,12 +4,16 @@ package postgres
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
 	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -58,9 +62,9 @@ func TestPullRequestRepository_Flow(t *testing.T) {
 	}
 	tx, err := testDB.Beginx()
 	require.NoError(t, err)
-	err = repo.CreatePR(ctx, tx, prToCreate)
+	err = repo.CreatePR(txctx.With(ctx, tx), prToCreate)
 	require.NoError(t, err)
-	err = repo.AssignReviewers(ctx, tx, "pr-1", reviewers)
+	err = repo.AssignReviewers(txctx.With(ctx, tx), "pr-1", reviewers)
 	require.NoError(t, err)
 	require.NoError(t, tx.Commit())
 
@@ -78,7 +82,7 @@ func TestPullRequestRepository_Flow(t *testing.T) {
 
 	tx, err = testDB.Beginx()
 	require.NoError(t, err)
-	err = repo.ReplaceReviewer(ctx, tx, "pr-1", oldReviewer, newReviewer)
+	err = repo.ReplaceReviewer(txctx.With(ctx, tx), "pr-1", oldReviewer, newReviewer)
 	require.NoError(t, err)
 	require.NoError(t, tx.Commit())
 
@@ -89,7 +93,7 @@ func TestPullRequestRepository_Flow(t *testing.T) {
 
 	tx, err = testDB.Beginx()
 	require.NoError(t, err)
-	err = repo.UpdatePRStatus(ctx, tx, "pr-1", api.PullRequestStatusMERGED, time.Now())
+	_, err = repo.UpdatePRStatus(txctx.With(ctx, tx), "pr-1", api.PullRequestStatusMERGED, time.Now())
 	require.NoError(t, err)
 	require.NoError(t, tx.Commit())
 
@@ -98,12 +102,119 @@ func TestPullRequestRepository_Flow(t *testing.T) {
 	assert.Equal(t, api.PullRequestStatusMERGED, pr.Status)
 	assert.NotNil(t, pr.MergedAt)
 
-	assignments, err := repo.GetReviewAssignments(ctx, newReviewer)
+	assignments, err := repo.GetReviewAssignments(ctx, newReviewer, nil)
 	require.NoError(t, err)
 	require.Len(t, assignments, 1)
 	assert.Equal(t, "pr-1", assignments[0].ID)
 }
 
+func TestPullRequestRepository_RejectsInvalidAssignments(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-1", []string{"rev1"}))
+	require.NoError(t, tx.Commit())
+
+	testCases := []struct {
+		name   string
+		assign func(tx *sqlx.Tx) error
+		reason string
+	}{
+		{
+			name: "Duplicate reviewer",
+			assign: func(tx *sqlx.Tx) error {
+				return repo.AssignReviewers(txctx.With(ctx, tx), "pr-1", []string{"rev2", "rev1"})
+			},
+			reason: "reviewer is already assigned",
+		},
+		{
+			name:   "Author assigned",
+			assign: func(tx *sqlx.Tx) error { return repo.AssignReviewers(txctx.With(ctx, tx), "pr-1", []string{"author"}) },
+			reason: "author cannot review their own pull request",
+		},
+		{
+			name:   "Author as replacement",
+			assign: func(tx *sqlx.Tx) error { return repo.ReplaceReviewer(txctx.With(ctx, tx), "pr-1", "rev1", "author") },
+			reason: "author cannot review their own pull request",
+		},
+		{
+			name: "Author in a batch of replacements",
+			assign: func(tx *sqlx.Tx) error {
+				return repo.ReplaceReviewers(txctx.With(ctx, tx), []domain.ReviewerReplacement{
+					{PullRequestID: "pr-1", OldReviewerID: "rev1", NewReviewerID: "author"},
+				})
+			},
+			reason: "author cannot review their own pull request",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx, err := testDB.Beginx()
+			require.NoError(t, err)
+			defer func() { _ = tx.Rollback() }()
+
+			var assignmentErr *apperrors.InvalidAssignmentError
+			require.ErrorAs(t, tc.assign(tx), &assignmentErr)
+			assert.Equal(t, "pr-1", assignmentErr.PRID)
+			assert.Equal(t, tc.reason, assignmentErr.Reason)
+		})
+	}
+
+	reviewerIDs, err := repo.GetReviewerIDs(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev1"}, reviewerIDs)
+}
+
+func TestPullRequestRepository_ReplaceReviewers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	for _, prID := range []string{"pr-1", "pr-2"} {
+		require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: prID, Name: prID, AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	}
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-1", []string{"rev1", "rev2"}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-2", []string{"rev1"}))
+	require.NoError(t, tx.Commit())
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+	// rev2 takes over the review of rev1 on pr-1 while handing their own over to rev4.
+	require.NoError(t, repo.ReplaceReviewers(txctx.With(ctx, tx), []domain.ReviewerReplacement{
+		{PullRequestID: "pr-1", OldReviewerID: "rev1", NewReviewerID: "rev2"},
+		{PullRequestID: "pr-1", OldReviewerID: "rev2", NewReviewerID: "rev4"},
+		{PullRequestID: "pr-2", OldReviewerID: "rev1", NewReviewerID: "rev2"},
+	}))
+	require.NoError(t, repo.ReplaceReviewers(txctx.With(ctx, tx), nil))
+	require.NoError(t, tx.Commit())
+
+	reviewerIDs, err := repo.GetReviewerIDs(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"rev2", "rev4"}, reviewerIDs)
+
+	reviewerIDs, err = repo.GetReviewerIDs(ctx, "pr-2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev2"}, reviewerIDs)
+
+	err = repo.ReplaceReviewers(ctx, []domain.ReviewerReplacement{{PullRequestID: "pr-2", OldReviewerID: "rev2", NewReviewerID: "rev1"}})
+	assert.ErrorIs(t, err, txctx.ErrNoTransaction)
+}
+
 func TestPullRequestRepository_GetUserStats(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test in short mode.")
@@ -118,32 +229,716 @@ func TestPullRequestRepository_GetUserStats(t *testing.T) {
 
 	tx, err := testDB.Beginx()
 	require.NoError(t, err)
-	require.NoError(t, repo.CreatePR(ctx, tx, pr1))
-	require.NoError(t, repo.CreatePR(ctx, tx, pr2))
-	require.NoError(t, repo.CreatePR(ctx, tx, pr3))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), pr1))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), pr2))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), pr3))
+
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-1", []string{"rev1"}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-2", []string{"rev1"}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-3", []string{"rev2"}))
+	require.NoError(t, tx.Commit())
+
+	stats, err := repo.GetUserStats(ctx, domain.StatsPeriod{})
+	require.NoError(t, err)
+
+	statsMap := make(map[string]domain.Stats)
+	for _, s := range stats {
+		statsMap[s.UserID] = s
+	}
+
+	assert.Equal(t, 1, statsMap["rev1"].OpenReviews)
+	assert.Equal(t, 1, statsMap["rev1"].MergedReviews)
+	assert.Equal(t, 1, statsMap["rev2"].OpenReviews)
+	assert.Equal(t, 0, statsMap["rev2"].MergedReviews)
+	assert.Equal(t, 0, statsMap["author"].OpenReviews)
+	assert.Equal(t, 0, statsMap["author"].MergedReviews)
+}
+
+func TestPullRequestRepository_GetUserStatsForPeriod(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	sprintStart := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	sprintEnd := sprintStart.Add(14 * 24 * time.Hour)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+
+	for _, pr := range []*domain.PullRequest{
+		{ID: "pr-open-before", Name: "Open before", AuthorID: "author", Status: api.PullRequestStatusOPEN},
+		{ID: "pr-open-during", Name: "Open during", AuthorID: "author", Status: api.PullRequestStatusOPEN},
+		{ID: "pr-merged-during", Name: "Merged during", AuthorID: "author", Status: api.PullRequestStatusMERGED},
+		{ID: "pr-merged-at-end", Name: "Merged at end", AuthorID: "author", Status: api.PullRequestStatusMERGED},
+	} {
+		require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), pr))
+		require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), pr.ID, []string{"rev1"}))
+	}
+	require.NoError(t, tx.Commit())
+
+	// The PRs merged during the sprint were opened before it: merged reviews are counted by the time of the merge.
+	for id, times := range map[string][2]time.Time{
+		"pr-open-before":   {sprintStart.Add(-time.Hour)},
+		"pr-open-during":   {sprintStart},
+		"pr-merged-during": {sprintStart.Add(-time.Hour), sprintStart.Add(time.Hour)},
+		"pr-merged-at-end": {sprintStart.Add(-time.Hour), sprintEnd},
+	} {
+		var mergedAt *time.Time
+		if !times[1].IsZero() {
+			mergedAt = &times[1]
+		}
 
-	require.NoError(t, repo.AssignReviewers(ctx, tx, "pr-1", []string{"rev1"}))
-	require.NoError(t, repo.AssignReviewers(ctx, tx, "pr-2", []string{"rev1"}))
-	require.NoError(t, repo.AssignReviewers(ctx, tx, "pr-3", []string{"rev2"}))
+		_, err = testDB.ExecContext(ctx, `UPDATE pull_requests SET created_at = $1, merged_at = $2 WHERE id = $3`, times[0], mergedAt, id)
+		require.NoError(t, err)
+	}
+
+	stats, err := repo.GetUserStats(ctx, domain.StatsPeriod{From: &sprintStart, To: &sprintEnd})
+	require.NoError(t, err)
+
+	statsMap := make(map[string]domain.Stats)
+	for _, s := range stats {
+		statsMap[s.UserID] = s
+	}
+
+	assert.Equal(t, 1, statsMap["rev1"].OpenReviews)
+	assert.Equal(t, 1, statsMap["rev1"].MergedReviews, "the end of the period is exclusive")
+	assert.Contains(t, statsMap, "rev2", "users without reviews in the period are still listed")
+
+	stats, err = repo.GetUserStats(ctx, domain.StatsPeriod{From: &sprintEnd})
+	require.NoError(t, err)
+
+	for _, s := range stats {
+		statsMap[s.UserID] = s
+	}
+
+	assert.Equal(t, 0, statsMap["rev1"].OpenReviews)
+	assert.Equal(t, 1, statsMap["rev1"].MergedReviews)
+}
+
+func TestPullRequestRepository_ReviewDurations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	createdAt := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-timed", Name: "Timed", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-timed", []string{"rev1", "rev2"}))
+	require.NoError(t, tx.Commit())
+
+	_, err = testDB.ExecContext(ctx, `UPDATE pull_requests SET created_at = $1 WHERE id = 'pr-timed'`, createdAt)
+	require.NoError(t, err)
+	_, err = testDB.ExecContext(ctx, `UPDATE reviewers SET assigned_at = $1 WHERE pull_request_id = 'pr-timed'`, createdAt)
+	require.NoError(t, err)
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.SetReviewState(txctx.With(ctx, tx), "pr-timed", "rev1", domain.ReviewChangesRequested, createdAt.Add(time.Hour)))
+	require.NoError(t, repo.SetReviewState(txctx.With(ctx, tx), "pr-timed", "rev1", domain.ReviewApproved, createdAt.Add(3*time.Hour)))
+	require.NoError(t, repo.SetReviewState(txctx.With(ctx, tx), "pr-timed", "rev2", domain.ReviewApproved, createdAt.Add(2*time.Hour)))
+	_, err = repo.UpdatePRStatus(txctx.With(ctx, tx), "pr-timed", api.PullRequestStatusMERGED, createdAt.Add(4*time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	stats, err := repo.GetUserStats(ctx, domain.StatsPeriod{})
+	require.NoError(t, err)
+
+	statsMap := make(map[string]domain.Stats)
+	for _, s := range stats {
+		statsMap[s.UserID] = s
+	}
+
+	rev1 := statsMap["rev1"]
+	assert.Equal(t, 1, rev1.FirstReviews, "later decisions do not move the first one")
+	assert.InDelta(t, time.Hour.Seconds(), *rev1.FirstReviewAvgSeconds, 1e-6)
+	assert.InDelta(t, (4 * time.Hour).Seconds(), *rev1.MergeP50Seconds, 1e-6)
+	assert.InDelta(t, (2 * time.Hour).Seconds(), *statsMap["rev2"].FirstReviewP90Seconds, 1e-6)
+	assert.Nil(t, statsMap["rev4"].FirstReviewAvgSeconds)
+	assert.Nil(t, statsMap["rev4"].MergeAvgSeconds)
+
+	teamStats, err := repo.GetTeamStats(ctx, domain.StatsPeriod{})
+	require.NoError(t, err)
+	require.Len(t, teamStats, 1)
+	assert.Equal(t, "pr-team", teamStats[0].TeamName)
+	assert.Equal(t, 1, teamStats[0].FirstReviewedPRs)
+	assert.InDelta(t, time.Hour.Seconds(), *teamStats[0].FirstReviewAvgSeconds, 1e-6, "a pull request is first reviewed by its fastest reviewer")
+	assert.Equal(t, 1, teamStats[0].MergedPRs)
+	assert.InDelta(t, (4 * time.Hour).Seconds(), *teamStats[0].MergeAvgSeconds, 1e-6)
+
+	from := createdAt.Add(90 * time.Minute)
+	period := domain.StatsPeriod{From: &from}
+
+	stats, err = repo.GetUserStats(ctx, period)
+	require.NoError(t, err)
+
+	for _, s := range stats {
+		statsMap[s.UserID] = s
+	}
+
+	assert.Zero(t, statsMap["rev1"].FirstReviews, "the first review was decided before the period")
+	assert.Equal(t, 1, statsMap["rev2"].FirstReviews)
+
+	teamStats, err = repo.GetTeamStats(ctx, period)
+	require.NoError(t, err)
+	require.Len(t, teamStats, 1)
+	assert.Zero(t, teamStats[0].FirstReviewedPRs)
+	assert.Nil(t, teamStats[0].FirstReviewAvgSeconds)
+	assert.Equal(t, 1, teamStats[0].MergedPRs)
+}
+
+func TestPullRequestRepository_BackfillReviewerDurations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	history := NewAssignmentHistoryRepository(testDB, logger)
+	ctx := context.Background()
+
+	createdAt := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-backfill-1", Name: "Backfill 1", AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedAt: createdAt}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-backfill-1", []string{"rev1", "rev2"}))
+	require.NoError(t, repo.SetReviewState(txctx.With(ctx, tx), "pr-backfill-1", "rev1", domain.ReviewApproved, createdAt.Add(3*time.Hour)))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-backfill-2", Name: "Backfill 2", AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedAt: createdAt}))
+	require.NoError(t, history.RecordAssignments(txctx.With(ctx, tx), []domain.AssignmentRecord{
+		{PullRequestID: "pr-backfill-1", UserID: "rev1", Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, CreatedAt: createdAt.Add(time.Hour)},
+		{PullRequestID: "pr-backfill-1", UserID: "rev1", Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, CreatedAt: createdAt.Add(2 * time.Hour)},
+	}))
+	require.NoError(t, tx.Commit())
+
+	// The reviewers predate the recorded times.
+	_, err = testDB.ExecContext(ctx, `UPDATE reviewers SET assigned_at = now(), first_reviewed_at = NULL WHERE pull_request_id = 'pr-backfill-1'`)
+	require.NoError(t, err)
+
+	backfill := func(afterID string, limit int) (string, int) {
+		tx, err := testDB.Beginx()
+		require.NoError(t, err)
+
+		lastID, updated, err := repo.BackfillReviewerDurations(txctx.With(ctx, tx), afterID, limit)
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+
+		return lastID, updated
+	}
+
+	lastID, updated := backfill("pr-backfill", 1)
+	assert.Equal(t, "pr-backfill-1", lastID)
+	assert.Equal(t, 2, updated)
+
+	var times []struct {
+		UserID          string     `db:"user_id"`
+		AssignedAt      time.Time  `db:"assigned_at"`
+		FirstReviewedAt *time.Time `db:"first_reviewed_at"`
+	}
+	require.NoError(t, testDB.SelectContext(ctx, &times, `SELECT user_id, assigned_at, first_reviewed_at FROM reviewers WHERE pull_request_id = 'pr-backfill-1' ORDER BY user_id`))
+	require.Len(t, times, 2)
+	assert.True(t, times[0].AssignedAt.Equal(createdAt.Add(2*time.Hour)), "the latest assignment wins")
+	require.NotNil(t, times[0].FirstReviewedAt)
+	assert.True(t, times[0].FirstReviewedAt.Equal(createdAt.Add(3*time.Hour)))
+	assert.True(t, times[1].AssignedAt.Equal(createdAt), "reviewers without history date from the PR")
+	assert.Nil(t, times[1].FirstReviewedAt)
+
+	lastID, updated = backfill(lastID, 1)
+	assert.Equal(t, "pr-backfill-2", lastID)
+	assert.Zero(t, updated)
+
+	lastID, updated = backfill("pr-backfill", 10)
+	assert.Equal(t, "pr-backfill-2", lastID)
+	assert.Zero(t, updated, "the backfill is idempotent")
+
+	lastID, _ = backfill("pr-backfill-2", 10)
+	assert.Empty(t, lastID)
+
+	_, _, err = repo.BackfillReviewerDurations(ctx, "", 10)
+	require.Error(t, err, "the backfill needs a transaction")
+}
+
+func TestPullRequestRepository_GetLeaderboard(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	week := domain.LeaderboardWeek.Bucket(time.Date(2025, 11, 5, 12, 0, 0, 0, time.UTC))
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+
+	for id, merge := range map[string]struct {
+		reviewers []string
+		mergedAt  time.Time
+	}{
+		"pr-1":         {[]string{"rev1", "rev2"}, week.From.Add(time.Hour)},
+		"pr-2":         {[]string{"rev2"}, week.To.Add(-time.Hour)},
+		"pr-next-week": {[]string{"rev1"}, *week.To},
+	} {
+		require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: id, Name: id, AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+		require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), id, merge.reviewers))
+		_, err = repo.UpdatePRStatus(txctx.With(ctx, tx), id, api.PullRequestStatusMERGED, merge.mergedAt)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tx.Commit())
+
+	entries, err := repo.GetLeaderboard(ctx, week, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.LeaderboardEntry{
+		{UserID: "rev2", Username: "Reviewer2", MergedReviews: 2},
+		{UserID: "rev1", Username: "Reviewer1", MergedReviews: 1},
+	}, entries, "the end of the week is exclusive")
+
+	entries, err = repo.GetLeaderboard(ctx, week, 1)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestPullRequestRepository_GetOpenPRAgeStats(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-age-1", Name: "Age 1", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-age-2", Name: "Age 2", AuthorID: "rev1", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-age-3", Name: "Age 3", AuthorID: "author", Status: api.PullRequestStatusMERGED}))
+	require.NoError(t, tx.Commit())
+
+	_, err = testDB.ExecContext(ctx, `UPDATE pull_requests SET created_at = NOW() - INTERVAL '10 minutes' WHERE id = 'pr-age-1'`)
+	require.NoError(t, err)
+
+	stats, err := repo.GetOpenPRAgeStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, "pr-team", stats[0].TeamName)
+	assert.Equal(t, 2, stats[0].OpenPRs, "merged PRs are not counted")
+	assert.InDelta(t, 600, stats[0].MaxSeconds, 5)
+	assert.InDelta(t, 300, stats[0].P50Seconds, 5)
+}
+
+func TestPullRequestRepository_GetStatsByUserIDs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	pr1 := &domain.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "author", Status: api.PullRequestStatusOPEN}
+	pr2 := &domain.PullRequest{ID: "pr-2", Name: "PR 2", AuthorID: "author", Status: api.PullRequestStatusMERGED}
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), pr1))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), pr2))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-1", []string{"rev1", "rev2"}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-2", []string{"rev1"}))
+	require.NoError(t, tx.Commit())
+
+	stats, err := repo.GetStatsByUserIDs(ctx, []string{"rev1", "rev2"})
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	statsMap := make(map[string]domain.Stats)
+	for _, s := range stats {
+		statsMap[s.UserID] = s
+	}
+
+	assert.Equal(t, 1, statsMap["rev1"].OpenReviews)
+	assert.Equal(t, 1, statsMap["rev1"].MergedReviews)
+	assert.Equal(t, 1, statsMap["rev2"].OpenReviews)
+	assert.Equal(t, 0, statsMap["rev2"].MergedReviews)
+
+	stats, err = repo.GetStatsByUserIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, stats)
+}
+
+func TestPullRequestRepository_SearchPRs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-1", Name: "Add search endpoint", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-2", Name: "Search: search index for search page", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-3", Name: "Fix login", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-4", Name: "Search cleanup", AuthorID: "author", Status: api.PullRequestStatusMERGED}))
+	require.NoError(t, tx.Commit())
+
+	prs, total, err := repo.SearchPRs(ctx, domain.PRSearchFilter{Query: "search", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, prs, 3)
+	assert.Equal(t, "pr-2", prs[0].ID, "more occurrences rank higher")
+
+	prs, total, err = repo.SearchPRs(ctx, domain.PRSearchFilter{Query: "search", Status: api.PullRequestStatusMERGED, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, prs, 1)
+	assert.Equal(t, "pr-4", prs[0].ID)
+
+	prs, total, err = repo.SearchPRs(ctx, domain.PRSearchFilter{Query: "search -cleanup", Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, prs, 1)
+
+	prs, total, err = repo.SearchPRs(ctx, domain.PRSearchFilter{Query: "nothing", Limit: 10})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, prs)
+}
+
+func TestPullRequestRepository_ListPRs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	other, err := NewTeamRepository(testDB, logger).CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "other-team",
+		Members:  []api.TeamMember{{UserId: "outsider", Username: "Outsider", IsActive: true}},
+	})
+	require.NoError(t, err)
+
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-a", Name: "A", AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedAt: base}))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-b", Name: "B", AuthorID: "author", Status: api.PullRequestStatusMERGED, CreatedAt: base.Add(time.Hour)}))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-c", Name: "C", AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedAt: base.Add(time.Hour)}))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-d", Name: "D", AuthorID: "outsider", Status: api.PullRequestStatusOPEN, CreatedAt: base.Add(2 * time.Hour)}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-c", []string{"rev1", "rev2"}))
+	require.NoError(t, tx.Commit())
+
+	ids := func(prs []domain.PullRequest) []string {
+		result := []string{}
+		for _, pr := range prs {
+			result = append(result, pr.ID)
+		}
+
+		return result
+	}
+
+	prs, err := repo.ListPRs(ctx, domain.PRListFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr-d", "pr-c", "pr-b", "pr-a"}, ids(prs))
+	assert.ElementsMatch(t, []string{"rev1", "rev2"}, prs[1].ReviewerIDs)
+	assert.Equal(t, []string{}, prs[0].ReviewerIDs)
+
+	prs, err = repo.ListPRs(ctx, domain.PRListFilter{TeamID: other.ID, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr-d"}, ids(prs))
+
+	from, to := base.Add(time.Hour), base.Add(2*time.Hour)
+	prs, err = repo.ListPRs(ctx, domain.PRListFilter{AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedFrom: &from, CreatedTo: &to, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr-c"}, ids(prs))
+
+	prs, err = repo.ListPRs(ctx, domain.PRListFilter{After: &domain.PRCursor{CreatedAt: base.Add(time.Hour), ID: "pr-c"}, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr-b", "pr-a"}, ids(prs))
+
+	prs, err = repo.ListPRs(ctx, domain.PRListFilter{Limit: 2, Offset: 3})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr-a"}, ids(prs))
+}
+
+func TestPullRequestRepository_DescriptionAndExternalURL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	description := "Speeds up the index rebuild"
+	url := "https://github.com/org/repo/pull/5"
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{
+		ID: "pr-5", Name: "Rebuild tuning", AuthorID: "author", Status: api.PullRequestStatusOPEN,
+		Description: &description, ExternalURL: &url,
+	}))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-6", Name: "Index cleanup", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, tx.Commit())
+
+	pr, err := repo.GetPRByID(ctx, "pr-5")
+	require.NoError(t, err)
+	require.NotNil(t, pr.Description)
+	require.NotNil(t, pr.ExternalURL)
+	assert.Equal(t, description, *pr.Description)
+	assert.Equal(t, url, *pr.ExternalURL)
+
+	pr, err = repo.GetPRByID(ctx, "pr-6")
+	require.NoError(t, err)
+	assert.Nil(t, pr.Description)
+	assert.Nil(t, pr.ExternalURL)
+
+	prs, total, err := repo.SearchPRs(ctx, domain.PRSearchFilter{Query: "index", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, prs, 2)
+	assert.Equal(t, "pr-6", prs[0].ID, "name matches rank above description matches")
+	assert.Equal(t, "pr-5", prs[1].ID)
+}
+
+func TestPullRequestRepository_AssignmentPolicy(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	snapshot := `{"team_id":1,"strategy_weights":{"random":1},"strategy":"random","reviewers_per_pr":2,"over_quota_action":"reject"}`
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{
+		ID: "pr-7", Name: "Snapshot", AuthorID: "author", Status: api.PullRequestStatusOPEN, AssignmentPolicy: []byte(snapshot),
+	}))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-8", Name: "Legacy", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, tx.Commit())
+
+	pr, err := repo.GetPRByIDWithReviewers(ctx, "pr-7")
+	require.NoError(t, err)
+	assert.JSONEq(t, snapshot, string(pr.AssignmentPolicy))
+
+	pr, err = repo.GetPRByID(ctx, "pr-8")
+	require.NoError(t, err)
+	assert.Nil(t, pr.AssignmentPolicy)
+}
+
+func TestPullRequestRepository_CreatePR_Constraints(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	pr := &domain.PullRequest{
+		ID:       "pr-duplicate",
+		Name:     "Test",
+		AuthorID: "author",
+		Status:   api.PullRequestStatusOPEN,
+	}
+	tx, _ := testDB.Beginx()
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), pr))
+	require.NoError(t, tx.Commit())
+
+	tx, _ = testDB.Beginx()
+	err := repo.CreatePR(txctx.With(ctx, tx), pr)
+	require.Error(t, err)
+	var prExistsErr *apperrors.PRAlreadyExistsError
+	assert.ErrorAs(t, err, &prExistsErr, "expected PRAlreadyExistsError")
+	tx.Rollback()
+
+	prInvalidAuthor := &domain.PullRequest{
+		ID:       "pr-invalid-author",
+		Name:     "Test",
+		AuthorID: "non-existent-author",
+		Status:   api.PullRequestStatusOPEN,
+	}
+	tx, _ = testDB.Beginx()
+	err = repo.CreatePR(txctx.With(ctx, tx), prInvalidAuthor)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, apperrors.ErrNotFound.Error())
+	tx.Rollback()
+}
+
+func TestPullRequestRepository_CreatePR_ConcurrentDuplicate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	pr := &domain.PullRequest{
+		ID:       "pr-concurrent",
+		Name:     "Concurrent",
+		AuthorID: "author",
+		Status:   api.PullRequestStatusOPEN,
+	}
+
+	first, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, first), pr))
+
+	second, err := testDB.Beginx()
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- repo.CreatePR(txctx.With(ctx, second), pr)
+	}()
+
+	// The second insert waits for the first transaction instead of failing right away.
+	select {
+	case err := <-done:
+		t.Fatalf("duplicate insert returned before the first transaction finished: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	require.NoError(t, first.Commit())
+
+	err = <-done
+	var prExistsErr *apperrors.PRAlreadyExistsError
+	require.ErrorAs(t, err, &prExistsErr)
+
+	// The duplicate must not abort the second transaction.
+	_, err = repo.GetReviewerIDs(txctx.With(ctx, second), pr.ID)
+	assert.NoError(t, err)
+	require.NoError(t, second.Rollback())
+}
+
+func TestPullRequestRepository_TimestampsAreUTC(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+	createdAt := time.Date(2025, time.March, 14, 15, 9, 26, 535897932, time.FixedZone("UTC+3", 3*60*60))
+	storedCreatedAt := time.Date(2025, time.March, 14, 12, 9, 26, 535897000, time.UTC)
+
+	pr := &domain.PullRequest{ID: "pr-utc", Name: "UTC", AuthorID: "author", Status: api.PullRequestStatusOPEN, CreatedAt: createdAt}
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), pr))
+	assert.Equal(t, storedCreatedAt, pr.CreatedAt)
+
+	mergedAt, err := repo.UpdatePRStatus(txctx.With(ctx, tx), "pr-utc", api.PullRequestStatusMERGED, createdAt.Add(time.Hour))
+	require.NoError(t, err)
+	require.NotNil(t, mergedAt)
+	assert.Equal(t, storedCreatedAt.Add(time.Hour), *mergedAt)
+	require.NoError(t, tx.Commit())
+
+	stored, err := repo.GetPRByID(ctx, "pr-utc")
+	require.NoError(t, err)
+	assert.Equal(t, storedCreatedAt, stored.CreatedAt)
+	require.NotNil(t, stored.MergedAt)
+	assert.Equal(t, *mergedAt, *stored.MergedAt)
+}
+
+func TestPullRequestRepository_ClosedPRLeavesWorkload(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-closed", Name: "Abandoned", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-closed", []string{"rev1"}))
+	require.NoError(t, repo.SetNeedMoreReviewers(txctx.With(ctx, tx), "pr-closed", true))
+	mergedAt, err := repo.UpdatePRStatus(txctx.With(ctx, tx), "pr-closed", api.PullRequestStatusCLOSED, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	assert.Nil(t, mergedAt)
+
+	pr, err := repo.GetPRByID(ctx, "pr-closed")
+	require.NoError(t, err)
+	assert.Equal(t, api.PullRequestStatusCLOSED, pr.Status)
+	assert.False(t, pr.NeedMoreReviewers)
+	assert.Nil(t, pr.MergedAt)
+
+	stats, err := repo.GetStatsByUserIDs(ctx, []string{"rev1"})
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Zero(t, stats[0].OpenReviews)
+	assert.Zero(t, stats[0].MergedReviews)
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	openPRs, err := repo.CountOpenPRsByAuthor(txctx.With(ctx, tx), "author")
+	require.NoError(t, err)
+	assert.Zero(t, openPRs)
+}
+
+func TestPullRequestRepository_Reviews(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+	reviewedAt := time.Date(2025, 11, 1, 15, 0, 0, 0, time.UTC)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-reviewed", Name: "Reviewed", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-reviewed", []string{"rev2", "rev1"}))
+	require.NoError(t, tx.Commit())
+
+	reviews, err := repo.GetReviews(ctx, "pr-reviewed")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Review{
+		{UserID: "rev1", State: domain.ReviewPending},
+		{UserID: "rev2", State: domain.ReviewPending},
+	}, reviews)
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.SetReviewState(txctx.With(ctx, tx), "pr-reviewed", "rev1", domain.ReviewApproved, reviewedAt))
+	require.NoError(t, repo.SetReviewState(txctx.With(ctx, tx), "pr-reviewed", "rev2", domain.ReviewChangesRequested, reviewedAt))
+	err = repo.SetReviewState(txctx.With(ctx, tx), "pr-reviewed", "author", domain.ReviewApproved, reviewedAt)
+	assert.ErrorIs(t, err, apperrors.ErrReviewerNotAssigned)
+	require.NoError(t, tx.Commit())
+
+	pr, err := repo.GetPRByIDWithReviewers(ctx, "pr-reviewed")
+	require.NoError(t, err)
+	require.Len(t, pr.Reviews, 2)
+	assert.Equal(t, domain.ReviewApproved, pr.Reviews[0].State)
+	require.NotNil(t, pr.Reviews[0].ReviewedAt)
+	assert.True(t, reviewedAt.Equal(*pr.Reviews[0].ReviewedAt))
+	assert.Equal(t, domain.ReviewChangesRequested, pr.Reviews[1].State)
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.ReplaceReviewer(txctx.With(ctx, tx), "pr-reviewed", "rev2", "rev4"))
 	require.NoError(t, tx.Commit())
 
-	stats, err := repo.GetUserStats(ctx)
+	reviews, err = repo.GetReviews(ctx, "pr-reviewed")
 	require.NoError(t, err)
-
-	statsMap := make(map[string]domain.Stats)
-	for _, s := range stats {
-		statsMap[s.UserID] = s
-	}
-
-	assert.Equal(t, 1, statsMap["rev1"].OpenReviews)
-	assert.Equal(t, 1, statsMap["rev1"].MergedReviews)
-	assert.Equal(t, 1, statsMap["rev2"].OpenReviews)
-	assert.Equal(t, 0, statsMap["rev2"].MergedReviews)
-	assert.Equal(t, 0, statsMap["author"].OpenReviews)
-	assert.Equal(t, 0, statsMap["author"].MergedReviews)
+	require.Len(t, reviews, 2)
+	assert.Equal(t, "rev1", reviews[0].UserID)
+	assert.Equal(t, domain.ReviewApproved, reviews[0].State)
+	assert.Equal(t, domain.Review{UserID: "rev4", State: domain.ReviewPending}, reviews[1], "the new reviewer starts with a pending review")
 }
 
-func TestPullRequestRepository_CreatePR_Constraints(t *testing.T) {
+func TestPullRequestRepository_UpdatePRStatus_NotFound(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
 	}
@@ -151,37 +946,14 @@ func TestPullRequestRepository_CreatePR_Constraints(t *testing.T) {
 	repo := NewPullRequestRepository(testDB, logger)
 	ctx := context.Background()
 
-	pr := &domain.PullRequest{
-		ID:       "pr-duplicate",
-		Name:     "Test",
-		AuthorID: "author",
-		Status:   api.PullRequestStatusOPEN,
-	}
 	tx, _ := testDB.Beginx()
-	require.NoError(t, repo.CreatePR(ctx, tx, pr))
-	require.NoError(t, tx.Commit())
-
-	tx, _ = testDB.Beginx()
-	err := repo.CreatePR(ctx, tx, pr)
-	require.Error(t, err)
-	var prExistsErr *apperrors.PRAlreadyExistsError
-	assert.ErrorAs(t, err, &prExistsErr, "expected PRAlreadyExistsError")
-	tx.Rollback()
-
-	prInvalidAuthor := &domain.PullRequest{
-		ID:       "pr-invalid-author",
-		Name:     "Test",
-		AuthorID: "non-existent-author",
-		Status:   api.PullRequestStatusOPEN,
-	}
-	tx, _ = testDB.Beginx()
-	err = repo.CreatePR(ctx, tx, prInvalidAuthor)
+	_, err := repo.UpdatePRStatus(txctx.With(ctx, tx), "non-existent-pr", api.PullRequestStatusMERGED, time.Now())
 	require.Error(t, err)
-	assert.ErrorContains(t, err, apperrors.ErrNotFound.Error())
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
 	tx.Rollback()
 }
 
-func TestPullRequestRepository_UpdatePRStatus_NotFound(t *testing.T) {
+func TestPullRequestRepository_GetAuthorTeamID_NotFound(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
 	}
@@ -189,14 +961,12 @@ func TestPullRequestRepository_UpdatePRStatus_NotFound(t *testing.T) {
 	repo := NewPullRequestRepository(testDB, logger)
 	ctx := context.Background()
 
-	tx, _ := testDB.Beginx()
-	err := repo.UpdatePRStatus(ctx, tx, "non-existent-pr", api.PullRequestStatusMERGED, time.Now())
+	_, err := repo.GetAuthorTeamID(ctx, "non-existent-author")
 	require.Error(t, err)
 	assert.ErrorIs(t, err, apperrors.ErrNotFound)
-	tx.Rollback()
 }
 
-func TestPullRequestRepository_GetAuthorTeamID_NotFound(t *testing.T) {
+func TestPullRequestRepository_IsUserActive(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
 	}
@@ -204,12 +974,19 @@ func TestPullRequestRepository_GetAuthorTeamID_NotFound(t *testing.T) {
 	repo := NewPullRequestRepository(testDB, logger)
 	ctx := context.Background()
 
-	_, err := repo.GetAuthorTeamID(ctx, "non-existent-author")
-	require.Error(t, err)
+	active, err := repo.IsUserActive(ctx, "author")
+	require.NoError(t, err)
+	assert.True(t, active)
+
+	active, err = repo.IsUserActive(ctx, "rev3-inactive")
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	_, err = repo.IsUserActive(ctx, "non-existent-user")
 	assert.ErrorIs(t, err, apperrors.ErrNotFound)
 }
 
-func TestPullRequestRepository_GetReviewerTeamID(t *testing.T) {
+func TestPullRequestRepository_GetPRTeamID(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
 	}
@@ -221,13 +998,28 @@ func TestPullRequestRepository_GetReviewerTeamID(t *testing.T) {
 	err := testDB.Get(&expectedTeamID, "SELECT id FROM teams WHERE name = 'pr-team'")
 	require.NoError(t, err)
 
-	actualTeamID, err := repo.GetReviewerTeamID(ctx, "rev1")
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, tx.Commit())
+
+	actualTeamID, err := repo.GetPRTeamID(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, expectedTeamID, actualTeamID)
+
+	// The team of the pull request outlives its author.
+	require.NoError(t, NewUserRepository(testDB, logger).DeleteUser(ctx, "author", time.Now()))
+
+	actualTeamID, err = repo.GetPRTeamID(ctx, "pr-1")
 	require.NoError(t, err)
 	assert.Equal(t, expectedTeamID, actualTeamID)
 
-	_, err = repo.GetReviewerTeamID(ctx, "non-existent-reviewer")
+	_, err = repo.GetPRTeamID(ctx, "non-existent-pr")
 	require.Error(t, err)
 	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	_, err = repo.GetUserTeamIDs(ctx, "non-existent-reviewer")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
 }
 
 func TestPullRequestRepository_GetPRByIDWithLock(t *testing.T) {
@@ -246,13 +1038,13 @@ func TestPullRequestRepository_GetPRByIDWithLock(t *testing.T) {
 	}
 	tx, err := testDB.Beginx()
 	require.NoError(t, err)
-	require.NoError(t, repo.CreatePR(ctx, tx, prToCreate))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), prToCreate))
 	require.NoError(t, tx.Commit())
 
 	tx, err = testDB.Beginx()
 	require.NoError(t, err)
 
-	pr, err := repo.GetPRByIDWithLock(ctx, tx, "pr-for-lock")
+	pr, err := repo.GetPRByIDWithLock(txctx.With(ctx, tx), "pr-for-lock")
 	require.NoError(t, err)
 	require.NotNil(t, pr)
 	assert.Equal(t, "pr-for-lock", pr.ID)
@@ -263,13 +1055,154 @@ func TestPullRequestRepository_GetPRByIDWithLock(t *testing.T) {
 	tx, err = testDB.Beginx()
 	require.NoError(t, err)
 
-	_, err = repo.GetPRByIDWithLock(ctx, tx, "non-existent-pr-for-lock")
+	_, err = repo.GetPRByIDWithLock(txctx.With(ctx, tx), "non-existent-pr-for-lock")
 	require.Error(t, err)
 	assert.ErrorIs(t, err, apperrors.ErrNotFound)
 
 	require.NoError(t, tx.Rollback())
 }
 
+func TestPullRequestRepository_CountOpenPRsByAuthor(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	for _, id := range []string{"pr-open-1", "pr-open-2", "pr-merged"} {
+		require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: id, Name: id, AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	}
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-other", Name: "pr-other", AuthorID: "rev1", Status: api.PullRequestStatusOPEN}))
+	_, err = repo.UpdatePRStatus(txctx.With(ctx, tx), "pr-merged", api.PullRequestStatusMERGED, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	count, err := repo.CountOpenPRsByAuthor(txctx.With(ctx, tx), "author")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// The author stays locked until the transaction ends, so a concurrent count has to wait.
+	counted := make(chan struct{})
+	go func() {
+		defer close(counted)
+
+		otherTx, err := testDB.Beginx()
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer otherTx.Rollback()
+
+		_, err = repo.CountOpenPRsByAuthor(txctx.With(ctx, otherTx), "author")
+		assert.NoError(t, err)
+	}()
+
+	select {
+	case <-counted:
+		t.Fatal("concurrent count of the same author did not wait for the lock")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	require.NoError(t, tx.Rollback())
+	<-counted
+}
+
+func TestPullRequestRepository_LockActiveUsers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	userRepo := NewUserRepository(testDB, logger)
+	ctx := context.Background()
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	activeIDs, err := repo.LockActiveUsers(txctx.With(ctx, tx), []string{"rev2", "rev1", "rev3-inactive", "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev1", "rev2"}, activeIDs)
+
+	activeIDs, err = repo.LockActiveUsers(txctx.With(ctx, tx), nil)
+	require.NoError(t, err)
+	assert.Empty(t, activeIDs)
+
+	// A locked candidate cannot be deactivated until the assigning transaction ends.
+	deactivated := make(chan struct{})
+	go func() {
+		defer close(deactivated)
+
+		otherTx, err := testDB.Beginx()
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer otherTx.Rollback()
+
+		_, _, err = userRepo.SetIsActive(txctx.With(ctx, otherTx), "rev1", false)
+		assert.NoError(t, err)
+	}()
+
+	select {
+	case <-deactivated:
+		t.Fatal("deactivation of a locked candidate did not wait for the lock")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	require.NoError(t, tx.Rollback())
+	<-deactivated
+}
+
+func TestPullRequestRepository_LockActiveUsers_WaitsForDeactivation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	userRepo := NewUserRepository(testDB, logger)
+	ctx := context.Background()
+
+	deactivateTx, err := testDB.Beginx()
+	require.NoError(t, err)
+	defer deactivateTx.Rollback()
+
+	_, _, err = userRepo.SetIsActive(txctx.With(ctx, deactivateTx), "rev1", false)
+	require.NoError(t, err)
+
+	// The candidates were selected before the deactivation commits, so the lock has to wait for it
+	// and then leave the deactivated user out.
+	locked := make(chan []string, 1)
+	go func() {
+		defer close(locked)
+
+		tx, err := testDB.Beginx()
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer tx.Rollback()
+
+		activeIDs, err := repo.LockActiveUsers(txctx.With(ctx, tx), []string{"rev1", "rev2"})
+		if assert.NoError(t, err) {
+			locked <- activeIDs
+		}
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("lock of a user being deactivated did not wait for the deactivation")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	require.NoError(t, deactivateTx.Commit())
+	assert.Equal(t, []string{"rev2"}, <-locked)
+}
+
 func TestPullRequestRepository_GetOpenPRsByReviewers(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test in short mode.")
@@ -285,21 +1218,21 @@ func TestPullRequestRepository_GetOpenPRsByReviewers(t *testing.T) {
 
 	tx, err := testDB.Beginx()
 	require.NoError(t, err)
-	require.NoError(t, repo.CreatePR(ctx, tx, pr1))
-	require.NoError(t, repo.CreatePR(ctx, tx, pr2))
-	require.NoError(t, repo.CreatePR(ctx, tx, pr3))
-	require.NoError(t, repo.CreatePR(ctx, tx, pr4))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), pr1))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), pr2))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), pr3))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), pr4))
 
-	require.NoError(t, repo.AssignReviewers(ctx, tx, "pr-open-1", []string{"rev1"}))
-	require.NoError(t, repo.AssignReviewers(ctx, tx, "pr-open-2", []string{"rev1", "rev2"}))
-	require.NoError(t, repo.AssignReviewers(ctx, tx, "pr-merged-1", []string{"rev1"}))
-	require.NoError(t, repo.AssignReviewers(ctx, tx, "pr-open-other", []string{"rev4"}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-open-1", []string{"rev1"}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-open-2", []string{"rev1", "rev2"}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-merged-1", []string{"rev1"}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-open-other", []string{"rev4"}))
 	require.NoError(t, tx.Commit())
 
 	tx, err = testDB.Beginx()
 	require.NoError(t, err)
 
-	openPRs, err := repo.GetOpenPRsByReviewers(ctx, tx, []string{"rev1"})
+	openPRs, err := repo.GetOpenPRsByReviewers(txctx.With(ctx, tx), []string{"rev1"})
 	require.NoError(t, err)
 	require.Len(t, openPRs, 2, "rev1 should have two open PRs")
 
@@ -315,9 +1248,247 @@ func TestPullRequestRepository_GetOpenPRsByReviewers(t *testing.T) {
 		}
 	}
 
-	noOpenPRs, err := repo.GetOpenPRsByReviewers(ctx, tx, []string{"author"})
+	noOpenPRs, err := repo.GetOpenPRsByReviewers(txctx.With(ctx, tx), []string{"author"})
 	require.NoError(t, err)
 	assert.Empty(t, noOpenPRs)
 
 	require.NoError(t, tx.Rollback())
 }
+
+func TestPullRequestRepository_GetRandomActiveReviewers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	teamID, err := repo.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	reviewers, err := repo.GetRandomActiveReviewers(ctx, teamID, []string{"author"}, 5)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"rev1", "rev2", "rev4"}, reviewers, "a short pool is returned whole")
+
+	reviewers, err = repo.GetRandomActiveReviewers(ctx, teamID, []string{"author", "rev1", "rev2", "rev4"}, 2)
+	require.NoError(t, err)
+	assert.Empty(t, reviewers)
+	assert.NotNil(t, reviewers)
+
+	drawn := map[string]bool{}
+	for range 100 {
+		reviewers, err = repo.GetRandomActiveReviewers(ctx, teamID, []string{"author"}, 1)
+		require.NoError(t, err)
+		require.Len(t, reviewers, 1)
+		drawn[reviewers[0]] = true
+	}
+	assert.Equal(t, map[string]bool{"rev1": true, "rev2": true, "rev4": true}, drawn, "every candidate gets drawn")
+
+	// The reviewers are read in the transaction of ctx, which sees the users it has deactivated before it commits.
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, `UPDATE users SET is_active = FALSE WHERE id = 'rev1'`)
+	require.NoError(t, err)
+
+	reviewers, err = repo.GetRandomActiveReviewers(txctx.With(ctx, tx), teamID, []string{"author"}, 5)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"rev2", "rev4"}, reviewers)
+}
+
+func TestPullRequestRepository_GetLeastLoadedActiveReviewers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	teamID, err := repo.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	pr1 := &domain.PullRequest{ID: "pr-load-1", Name: "Load PR 1", AuthorID: "author", Status: api.PullRequestStatusOPEN}
+	pr2 := &domain.PullRequest{ID: "pr-load-2", Name: "Load PR 2", AuthorID: "author", Status: api.PullRequestStatusOPEN}
+	pr3 := &domain.PullRequest{ID: "pr-load-merged", Name: "Merged PR", AuthorID: "author", Status: api.PullRequestStatusMERGED}
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), pr1))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), pr2))
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), pr3))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-load-1", []string{"rev1", "rev2"}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-load-2", []string{"rev1"}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-load-merged", []string{"rev4"}))
+	require.NoError(t, tx.Commit())
+
+	reviewers, err := repo.GetLeastLoadedActiveReviewers(ctx, teamID, []string{"author"}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev4", "rev2"}, reviewers, "merged PRs must not count towards the load")
+
+	reviewers, err = repo.GetLeastLoadedActiveReviewers(ctx, teamID, []string{"author", "rev4"}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev2"}, reviewers)
+}
+
+func TestPullRequestRepository_GetLeastRecentlyAssignedActiveReviewers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	historyRepo := NewAssignmentHistoryRepository(testDB, logger)
+	ctx := context.Background()
+	assignedAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	teamID, err := repo.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-rotation", Name: "Rotation", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, historyRepo.RecordAssignments(txctx.With(ctx, tx), []domain.AssignmentRecord{
+		{PullRequestID: "pr-rotation", UserID: "rev1", Strategy: domain.StrategyRoundRobin, Reason: domain.ReasonRoundRobin, CreatedAt: assignedAt},
+		{PullRequestID: "pr-rotation", UserID: "rev4", Strategy: domain.StrategyRoundRobin, Reason: domain.ReasonRoundRobin, CreatedAt: assignedAt.Add(-time.Hour)},
+		{PullRequestID: "pr-rotation", UserID: "rev4", Strategy: domain.StrategyRoundRobin, Reason: domain.ReasonRoundRobin, CreatedAt: assignedAt.Add(time.Hour)},
+	}))
+	require.NoError(t, tx.Commit())
+
+	next, err := repo.GetLeastRecentlyAssignedActiveReviewers(ctx, teamID, []string{"author"}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev2", "rev1", "rev4"}, next, "never assigned first, then by the latest assignment")
+
+	next, err = repo.GetLeastRecentlyAssignedActiveReviewers(ctx, teamID, []string{"author", "rev2"}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev1"}, next)
+}
+
+func TestPullRequestRepository_GetReplacementAlternatives(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	otherTeam, err := NewTeamRepository(testDB, logger).CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "other-team",
+		Members: []api.TeamMember{
+			{UserId: "other1", Username: "Other1", IsActive: true},
+			{UserId: "other2", Username: "Other2", IsActive: true},
+			{UserId: "other3-inactive", Username: "Other3", IsActive: false},
+		},
+	})
+	require.NoError(t, err)
+
+	teamID, err := repo.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-alt-1", Name: "Alt PR", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-alt-1", []string{"other1"}))
+	require.NoError(t, tx.Commit())
+
+	alternatives, err := repo.GetReplacementAlternatives(ctx, teamID, []string{"author", "rev1"}, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ReplacementAlternative{
+		{UserID: "rev3-inactive", Username: "Reviewer3", TeamID: teamID, TeamName: "pr-team", Reason: domain.AlternativeInactive},
+		{UserID: "other2", Username: "Other2", TeamID: otherTeam.ID, TeamName: "other-team", Reason: domain.AlternativeOtherTeam},
+		{UserID: "other1", Username: "Other1", TeamID: otherTeam.ID, TeamName: "other-team", Reason: domain.AlternativeOtherTeam, OpenReviews: 1},
+	}, alternatives, "active teammates and inactive users of other teams are not alternatives")
+
+	alternatives, err = repo.GetReplacementAlternatives(ctx, teamID, nil, 1)
+	require.NoError(t, err)
+	require.Len(t, alternatives, 2, "the limit applies to each kind separately")
+	assert.Equal(t, "other2", alternatives[1].UserID)
+}
+
+func TestPullRequestRepository_ConcurrentDeactivations_NoDeadlock(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	truncateTables(t, testDB)
+	ctx := context.Background()
+
+	teamRepo := NewTeamRepository(testDB, logger)
+	userRepo := NewUserRepository(testDB, logger)
+	prRepo := NewPullRequestRepository(testDB, logger)
+
+	teamA, err := teamRepo.CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "team-a",
+		Members: []api.TeamMember{
+			{UserId: "a-author", Username: "A Author", IsActive: true},
+			{UserId: "a-rev", Username: "A Reviewer", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	teamB, err := teamRepo.CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "team-b",
+		Members:  []api.TeamMember{{UserId: "b-rev", Username: "B Reviewer", IsActive: true}},
+	})
+	require.NoError(t, err)
+
+	// Every PR is reviewed by members of both teams, so both deactivations lock the same PR rows.
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	for i := range 20 {
+		prID := fmt.Sprintf("pr-shared-%02d", i)
+		require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{
+			ID: prID, Name: "Shared PR", AuthorID: "a-author", Status: api.PullRequestStatusOPEN,
+		}))
+		require.NoError(t, prRepo.AssignReviewers(txctx.With(ctx, tx), prID, []string{"a-rev", "b-rev"}))
+	}
+	require.NoError(t, tx.Commit())
+
+	deactivate := func(teamID int) error {
+		tx, err := testDB.Beginx()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		userIDs, err := userRepo.DeactivateUsersByTeamID(txctx.With(ctx, tx), teamID)
+		if err != nil {
+			return err
+		}
+
+		prs, err := prRepo.GetOpenPRsByReviewers(txctx.With(ctx, tx), userIDs)
+		if err != nil {
+			return err
+		}
+
+		for i := 1; i < len(prs); i++ {
+			if prs[i-1].ID > prs[i].ID {
+				return fmt.Errorf("prs are not ordered by id: %s before %s", prs[i-1].ID, prs[i].ID)
+			}
+		}
+
+		time.Sleep(50 * time.Millisecond)
+
+		return tx.Commit()
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+
+	for _, teamID := range []int{teamA.ID, teamB.ID} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- deactivate(teamID)
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	var activeCount int
+	require.NoError(t, testDB.Get(&activeCount, "SELECT COUNT(*) FROM users WHERE is_active"))
+	assert.Zero(t, activeCount)
+}
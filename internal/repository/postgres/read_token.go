@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type ReadTokenRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewReadTokenRepository(db *sqlx.DB, log *slog.Logger) *ReadTokenRepository {
+	return &ReadTokenRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// readTokenColumns lists the read_tokens columns that map onto domain.ReadToken.
+var readTokenColumns = []string{"id", "name", "token_hash", "created_at", "expires_at", "last_used_at"}
+
+func (rr *ReadTokenRepository) CreateReadToken(ctx context.Context, token *domain.ReadToken) (*domain.ReadToken, error) {
+	const op = "internal.repository.postgres.CreateReadToken"
+
+	query, args, err := rr.sq.Insert("read_tokens").
+		Columns("name", "token_hash", "created_at", "expires_at").
+		Values(token.Name, token.TokenHash, timestampOrNow(token.CreatedAt), token.ExpiresAt).
+		Suffix("RETURNING id, name, token_hash, created_at, expires_at, last_used_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var created domain.ReadToken
+	if err := rr.db.QueryRowxContext(ctx, query, args...).StructScan(&created); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return &created, nil
+}
+
+func (rr *ReadTokenRepository) GetReadTokenByHash(ctx context.Context, hash string) (*domain.ReadToken, error) {
+	const op = "internal.repository.postgres.GetReadTokenByHash"
+
+	query, args, err := rr.sq.Select(readTokenColumns...).
+		From("read_tokens").
+		Where(sq.Eq{"token_hash": hash}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var token domain.ReadToken
+	if err := rr.db.GetContext(ctx, &token, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: read token", op, apperrors.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &token, nil
+}
+
+func (rr *ReadTokenRepository) ListReadTokens(ctx context.Context) ([]domain.ReadToken, error) {
+	const op = "internal.repository.postgres.ListReadTokens"
+
+	query, args, err := rr.sq.Select(readTokenColumns...).
+		From("read_tokens").
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	tokens := []domain.ReadToken{}
+	if err := rr.db.SelectContext(ctx, &tokens, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to list read tokens: %w", op, err)
+	}
+
+	return tokens, nil
+}
+
+func (rr *ReadTokenRepository) TouchReadToken(ctx context.Context, id int64, usedAt time.Time) error {
+	const op = "internal.repository.postgres.TouchReadToken"
+
+	query, args, err := rr.sq.Update("read_tokens").
+		Set("last_used_at", usedAt.UTC()).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := rr.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%s: %w: read token %d", op, apperrors.ErrNotFound, id)
+	}
+
+	return nil
+}
+
+func (rr *ReadTokenRepository) DeleteReadToken(ctx context.Context, id int64) error {
+	const op = "internal.repository.postgres.DeleteReadToken"
+
+	query, args, err := rr.sq.Delete("read_tokens").
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build delete query: %w", op, err)
+	}
+
+	var deleted int64
+	if err := rr.db.GetContext(ctx, &deleted, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w: read token %d", op, apperrors.ErrNotFound, id)
+		}
+
+		return fmt.Errorf("%s: failed to execute delete: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTokenRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+
+	setupPRTest(t)
+	repo := NewReadTokenRepository(testDB, logger)
+	ctx := context.Background()
+	createdAt := time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.AddDate(0, 3, 0)
+
+	dashboard, err := repo.CreateReadToken(ctx, &domain.ReadToken{
+		Name: "grafana", TokenHash: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		CreatedAt: createdAt, ExpiresAt: &expiresAt,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, dashboard.ID)
+	assert.Equal(t, createdAt, dashboard.CreatedAt.UTC())
+	require.NotNil(t, dashboard.ExpiresAt)
+	assert.Equal(t, expiresAt, dashboard.ExpiresAt.UTC())
+	assert.Nil(t, dashboard.LastUsedAt)
+
+	script, err := repo.CreateReadToken(ctx, &domain.ReadToken{
+		Name: "weekly report", TokenHash: "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+	})
+	require.NoError(t, err)
+	assert.Nil(t, script.ExpiresAt)
+
+	found, err := repo.GetReadTokenByHash(ctx, dashboard.TokenHash)
+	require.NoError(t, err)
+	assert.Equal(t, dashboard.ID, found.ID)
+
+	_, err = repo.GetReadTokenByHash(ctx, "unknown")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	usedAt := createdAt.Add(time.Hour)
+	require.NoError(t, repo.TouchReadToken(ctx, dashboard.ID, usedAt))
+	assert.ErrorIs(t, repo.TouchReadToken(ctx, 42, usedAt), apperrors.ErrNotFound)
+
+	require.NoError(t, repo.DeleteReadToken(ctx, script.ID))
+	assert.ErrorIs(t, repo.DeleteReadToken(ctx, script.ID), apperrors.ErrNotFound)
+
+	tokens, err := repo.ListReadTokens(ctx)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "grafana", tokens[0].Name)
+	require.NotNil(t, tokens[0].LastUsedAt)
+	assert.Equal(t, usedAt, tokens[0].LastUsedAt.UTC())
+}
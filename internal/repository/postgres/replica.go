@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+	"github.com/jmoiron/sqlx"
+)
+
+// replicaPingTimeout bounds a health check of the replica.
+const replicaPingTimeout = 2 * time.Second
+
+// Replica routes the reads of the query repositories to a read replica while it answers its health checks,
+// and to the primary while it does not. The repositories get it through WithReplica; the transactions
+// begun on it are read-only snapshots of the query services, and every write goes to the primary.
+//
+// The replica lags behind the primary, so a read through it may not see a write committed just before.
+// The write paths read the primary only.
+type Replica struct {
+	primary *sqlx.DB
+	replica *sqlx.DB
+	log     *slog.Logger
+	// down is set while the replica fails its health checks.
+	down atomic.Bool
+}
+
+// NewReplica creates a router over the pools of the primary and the replica. The replica is considered up
+// until a health check fails.
+func NewReplica(primary, replica *sqlx.DB, log *slog.Logger) *Replica {
+	return &Replica{
+		primary: primary,
+		replica: replica,
+		log:     log.With(slog.String("component", "replica")),
+	}
+}
+
+// DB returns the pool the reads go to: the replica, or the primary while the replica is down.
+func (r *Replica) DB() *sqlx.DB {
+	if r.down.Load() {
+		return r.primary
+	}
+
+	return r.replica
+}
+
+// BeginTxx begins a transaction on the pool the reads go to, so that a query service can take its
+// snapshots from the replica.
+func (r *Replica) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	return r.DB().BeginTxx(ctx, opts)
+}
+
+// Check pings the replica and routes the reads to it or to the primary by the outcome.
+func (r *Replica) Check(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+	defer cancel()
+
+	err := r.replica.PingContext(pingCtx)
+	if ctx.Err() != nil {
+		// The service is stopping; the ping failed for that, not for the replica.
+		return
+	}
+
+	switch wasDown := r.down.Load(); {
+	case err != nil && !wasDown:
+		r.log.Error("replica is down, reading from the primary", sl.Err(err))
+	case err == nil && wasDown:
+		r.log.Info("replica is up again, reading from the replica")
+	}
+
+	r.down.Store(err != nil)
+}
+
+// Run checks the replica every interval until ctx is done.
+func (r *Replica) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Check(ctx)
+		}
+	}
+}
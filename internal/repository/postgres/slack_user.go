@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type SlackUserRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewSlackUserRepository(db *sqlx.DB, log *slog.Logger) *SlackUserRepository {
+	return &SlackUserRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (sr *SlackUserRepository) SetSlackUser(ctx context.Context, mapping *domain.SlackUser) (*domain.SlackUser, error) {
+	const op = "internal.repository.postgres.SetSlackUser"
+
+	query, args, err := sr.sq.Insert("slack_users").
+		Columns("user_id", "slack_user_id", "created_at").
+		Values(mapping.UserID, mapping.SlackUserID, timestampOrNow(mapping.CreatedAt)).
+		Suffix(`ON CONFLICT (user_id) DO UPDATE SET slack_user_id = EXCLUDED.slack_user_id, created_at = EXCLUDED.created_at
+			RETURNING user_id, slack_user_id, created_at`).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var stored domain.SlackUser
+	if err := sr.db.QueryRowxContext(ctx, query, args...).StructScan(&stored); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return nil, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, mapping.UserID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return &stored, nil
+}
+
+func (sr *SlackUserRepository) GetSlackUser(ctx context.Context, userID string) (*domain.SlackUser, error) {
+	const op = "internal.repository.postgres.GetSlackUser"
+
+	query, args, err := sr.sq.Select("user_id", "slack_user_id", "created_at").
+		From("slack_users").
+		Where(sq.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var mapping domain.SlackUser
+	if err := sr.db.GetContext(ctx, &mapping, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: slack user of '%s'", op, apperrors.ErrNotFound, userID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &mapping, nil
+}
+
+func (sr *SlackUserRepository) ListSlackUsers(ctx context.Context) ([]domain.SlackUser, error) {
+	const op = "internal.repository.postgres.ListSlackUsers"
+
+	query, args, err := sr.sq.Select("user_id", "slack_user_id", "created_at").
+		From("slack_users").
+		OrderBy("user_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	mappings := []domain.SlackUser{}
+	if err := sr.db.SelectContext(ctx, &mappings, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to list slack users: %w", op, err)
+	}
+
+	return mappings, nil
+}
+
+func (sr *SlackUserRepository) DeleteSlackUser(ctx context.Context, userID string) error {
+	const op = "internal.repository.postgres.DeleteSlackUser"
+
+	query, args, err := sr.sq.Delete("slack_users").
+		Where(sq.Eq{"user_id": userID}).
+		Suffix("RETURNING user_id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build delete query: %w", op, err)
+	}
+
+	var deleted string
+	if err := sr.db.GetContext(ctx, &deleted, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w: slack user of '%s'", op, apperrors.ErrNotFound, userID)
+		}
+
+		return fmt.Errorf("%s: failed to execute delete: %w", op, err)
+	}
+
+	return nil
+}
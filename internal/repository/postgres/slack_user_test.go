@@ -0,0 +1,55 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackUserRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+
+	setupPRTest(t)
+	repo := NewSlackUserRepository(testDB, logger)
+	ctx := context.Background()
+	createdAt := time.Date(2026, time.January, 12, 10, 0, 0, 0, time.UTC)
+
+	stored, err := repo.SetSlackUser(ctx, &domain.SlackUser{UserID: "rev1", SlackUserID: "U012AB3CD", CreatedAt: createdAt})
+	require.NoError(t, err)
+	assert.Equal(t, "U012AB3CD", stored.SlackUserID)
+	assert.Equal(t, createdAt, stored.CreatedAt.UTC())
+
+	_, err = repo.SetSlackUser(ctx, &domain.SlackUser{UserID: "author", SlackUserID: "U0AUTHOR"})
+	require.NoError(t, err)
+
+	remapped, err := repo.SetSlackUser(ctx, &domain.SlackUser{UserID: "rev1", SlackUserID: "W0ENTERPRISE"})
+	require.NoError(t, err)
+	assert.Equal(t, "W0ENTERPRISE", remapped.SlackUserID, "a user is mapped to one Slack member at a time")
+
+	_, err = repo.SetSlackUser(ctx, &domain.SlackUser{UserID: "no-such-user", SlackUserID: "U0GHOST"})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	mapping, err := repo.GetSlackUser(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, "W0ENTERPRISE", mapping.SlackUserID)
+
+	_, err = repo.GetSlackUser(ctx, "rev2")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	all, err := repo.ListSlackUsers(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, []string{"author", "rev1"}, []string{all[0].UserID, all[1].UserID})
+
+	require.NoError(t, repo.DeleteSlackUser(ctx, "rev1"))
+	assert.ErrorIs(t, repo.DeleteSlackUser(ctx, "rev1"), apperrors.ErrNotFound)
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+	"github.com/jmoiron/sqlx"
+)
+
+// statsViewColumns are the columns of the precomputed views read back into domain.Stats and domain.TeamStats.
+var statsViewColumns = []string{
+	"first_review_avg_seconds", "first_review_p50_seconds", "first_review_p90_seconds",
+	"merge_avg_seconds", "merge_p50_seconds", "merge_p90_seconds",
+}
+
+func (r *PullRequestRepository) RefreshStatsView(ctx context.Context) (bool, error) {
+	const op = "internal.repository.postgres.RefreshStatsView"
+	log := r.log.With(slog.String("op", op))
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Error("failed to rollback transaction", sl.Err(err))
+		}
+	}()
+
+	// Concurrent refreshes would only repeat the same work, so the instances that lose the race skip it.
+	var locked bool
+	if err := tx.GetContext(ctx, &locked, "SELECT pg_try_advisory_xact_lock($1, $2)", advisoryLockStatsRefresh, 0); err != nil {
+		return false, fmt.Errorf("%s: failed to acquire advisory lock: %w", op, err)
+	}
+
+	if !locked {
+		return false, nil
+	}
+
+	// A concurrent refresh lets GetStatsView read the previous contents while the views are recomputed.
+	for _, view := range []string{"user_review_stats", "team_review_stats"} {
+		if _, err := tx.ExecContext(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY "+view); err != nil {
+			return false, fmt.Errorf("%s: failed to refresh %s: %w", op, view, err)
+		}
+	}
+
+	// NOW() is the start of the transaction, so the statistics are at least as fresh as it says.
+	if _, err := tx.ExecContext(ctx, "UPDATE stats_refresh SET refreshed_at = NOW()"); err != nil {
+		return false, fmt.Errorf("%s: failed to record refresh time: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return true, nil
+}
+
+func (r *PullRequestRepository) GetStatsView(ctx context.Context) (*domain.StatsView, error) {
+	const op = "internal.repository.postgres.GetStatsView"
+
+	ext := txctx.Ext(ctx, r.reads())
+
+	usersQuery, args, err := r.sq.Select("user_id", "username", "open_reviews", "merged_reviews", "first_reviews").
+		Columns(statsViewColumns...).
+		From("user_review_stats").
+		OrderBy("username COLLATE " + usernameCollation).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build users query: %w", op, err)
+	}
+
+	view := &domain.StatsView{Users: []domain.Stats{}, Teams: []domain.TeamStats{}}
+	if err := sqlx.SelectContext(ctx, ext, &view.Users, usersQuery, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to select user stats: %w", op, err)
+	}
+
+	teamsQuery, args, err := r.sq.Select("team_name", "first_reviewed_prs", "merged_prs").
+		Columns(statsViewColumns...).
+		From("team_review_stats").
+		OrderBy("team_name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build teams query: %w", op, err)
+	}
+
+	if err := sqlx.SelectContext(ctx, ext, &view.Teams, teamsQuery, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to select team stats: %w", op, err)
+	}
+
+	if err := sqlx.GetContext(ctx, ext, &view.RefreshedAt, "SELECT refreshed_at FROM stats_refresh"); err != nil {
+		return nil, fmt.Errorf("%s: failed to get refresh time: %w", op, err)
+	}
+
+	return view, nil
+}
@@ -0,0 +1,89 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestRepository_StatsView(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	refreshed, err := repo.RefreshStatsView(ctx)
+	require.NoError(t, err)
+	require.True(t, refreshed)
+
+	before, err := repo.GetStatsView(ctx)
+	require.NoError(t, err)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-viewed", Name: "Viewed", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, repo.AssignReviewers(txctx.With(ctx, tx), "pr-viewed", []string{"rev1", "rev2"}))
+	require.NoError(t, repo.SetReviewState(txctx.With(ctx, tx), "pr-viewed", "rev1", domain.ReviewApproved, time.Now().Add(time.Hour)))
+	_, err = repo.UpdatePRStatus(txctx.With(ctx, tx), "pr-viewed", api.PullRequestStatusMERGED, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	view, err := repo.GetStatsView(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, before, view, "the view does not change until it is refreshed")
+
+	refreshed, err = repo.RefreshStatsView(ctx)
+	require.NoError(t, err)
+	require.True(t, refreshed)
+
+	view, err = repo.GetStatsView(ctx)
+	require.NoError(t, err)
+	assert.False(t, view.RefreshedAt.Before(before.RefreshedAt))
+
+	// The refreshed view holds what the live queries compute.
+	stats, err := repo.GetUserStats(ctx, domain.StatsPeriod{})
+	require.NoError(t, err)
+	assert.Equal(t, stats, view.Users)
+
+	teamStats, err := repo.GetTeamStats(ctx, domain.StatsPeriod{})
+	require.NoError(t, err)
+	require.Len(t, view.Teams, 1)
+	assert.Equal(t, teamStats, view.Teams)
+}
+
+func TestPullRequestRepository_RefreshStatsView_Concurrent(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	repo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	// Another instance is in the middle of a refresh.
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+
+	var locked bool
+	require.NoError(t, tx.GetContext(ctx, &locked, "SELECT pg_try_advisory_xact_lock($1, $2)", advisoryLockStatsRefresh, 0))
+	require.True(t, locked)
+
+	refreshed, err := repo.RefreshStatsView(ctx)
+	require.NoError(t, err)
+	assert.False(t, refreshed, "the refresh running elsewhere is not waited for")
+
+	require.NoError(t, tx.Rollback())
+
+	refreshed, err = repo.RefreshStatsView(ctx)
+	require.NoError(t, err)
+	assert.True(t, refreshed)
+}
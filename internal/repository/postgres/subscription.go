@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type SubscriptionRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewSubscriptionRepository(db *sqlx.DB, log *slog.Logger) *SubscriptionRepository {
+	return &SubscriptionRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// subscriptionUserConstraint is the foreign key of pr_subscriptions to users,
+// which tells an unknown user from an unknown pull request.
+const subscriptionUserConstraint = "pr_subscriptions_user_id_fkey"
+
+func (sr *SubscriptionRepository) Subscribe(ctx context.Context, prID string, userID string) (*domain.PRSubscription, bool, error) {
+	const op = "internal.repository.postgres.Subscribe"
+
+	query, args, err := sr.sq.Insert("pr_subscriptions").
+		Columns("pull_request_id", "user_id").
+		Values(prID, userID).
+		Suffix("ON CONFLICT (pull_request_id, user_id) DO NOTHING RETURNING pull_request_id, user_id, created_at").
+		ToSql()
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var sub domain.PRSubscription
+	if err := sr.db.QueryRowxContext(ctx, query, args...).StructScan(&sub); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			existing, err := sr.getSubscription(ctx, prID, userID)
+			if err != nil {
+				return nil, false, fmt.Errorf("%s: %w", op, err)
+			}
+
+			return existing, false, nil
+		}
+
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			if pqErr.Constraint == subscriptionUserConstraint {
+				return nil, false, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+			}
+
+			return nil, false, fmt.Errorf("%s: %w: pull request with id '%s'", op, apperrors.ErrNotFound, prID)
+		}
+
+		return nil, false, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return &sub, true, nil
+}
+
+func (sr *SubscriptionRepository) GetSubscriberIDs(ctx context.Context, prID string) ([]string, error) {
+	const op = "internal.repository.postgres.GetSubscriberIDs"
+
+	query, args, err := sr.sq.Select("user_id").
+		From("pr_subscriptions").
+		Where(sq.Eq{"pull_request_id": prID}).
+		OrderBy("user_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	userIDs := []string{}
+	if err := sr.db.SelectContext(ctx, &userIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return userIDs, nil
+}
+
+func (sr *SubscriptionRepository) getSubscription(ctx context.Context, prID string, userID string) (*domain.PRSubscription, error) {
+	query, args, err := sr.sq.Select("pull_request_id", "user_id", "created_at").
+		From("pr_subscriptions").
+		Where(sq.Eq{"pull_request_id": prID, "user_id": userID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build subscription query: %w", err)
+	}
+
+	var sub domain.PRSubscription
+	if err := sr.db.GetContext(ctx, &sub, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	return &sub, nil
+}
@@ -0,0 +1,60 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionRepository_Subscribe(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+
+	setupPRTest(t)
+	repo := NewSubscriptionRepository(testDB, logger)
+	prRepo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-watched", Name: "Watched", AuthorID: "author", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, tx.Commit())
+
+	subscriberIDs, err := repo.GetSubscriberIDs(ctx, "pr-watched")
+	require.NoError(t, err)
+	assert.Empty(t, subscriberIDs)
+
+	first, created, err := repo.Subscribe(ctx, "pr-watched", "rev2")
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "pr-watched", first.PullRequestID)
+	assert.Equal(t, "rev2", first.UserID)
+	assert.False(t, first.CreatedAt.IsZero())
+
+	_, _, err = repo.Subscribe(ctx, "pr-watched", "rev1")
+	require.NoError(t, err)
+
+	again, created, err := repo.Subscribe(ctx, "pr-watched", "rev2")
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, first, again)
+
+	subscriberIDs, err = repo.GetSubscriberIDs(ctx, "pr-watched")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev1", "rev2"}, subscriberIDs)
+
+	_, _, err = repo.Subscribe(ctx, "pr-unknown", "rev1")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	_, _, err = repo.Subscribe(ctx, "pr-watched", "ghost")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
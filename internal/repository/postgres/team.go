@@ -6,20 +6,38 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
 	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
 	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 )
 
+// The first keys of the advisory locks taken by the repositories.
+const (
+	// advisoryLockTeamDeactivation serializes deactivations of a team; the second key is the team ID.
+	advisoryLockTeamDeactivation = 1
+	// advisoryLockAuthorOpenPRs serializes open PR quota checks of an author; the second key is hashtext(author ID).
+	advisoryLockAuthorOpenPRs = 2
+	// advisoryLockStatsRefresh serializes refreshes of the precomputed statistics; the second key is always 0.
+	advisoryLockStatsRefresh = 3
+)
+
+// usernameCollation orders usernames by the Unicode collation algorithm, so that Cyrillic and Latin names
+// are sorted alphabetically regardless of case instead of by code point. It matches idx_users_team_username.
+const usernameCollation = `"und-x-icu"`
+
 type TeamRepository struct {
 	db  *sqlx.DB
 	log *slog.Logger
 	sq  sq.StatementBuilderType
+	// replica serves the reads outside a transaction, see WithReplica.
+	replica *Replica
 }
 
 func NewTeamRepository(db *sqlx.DB, log *slog.Logger) *TeamRepository {
@@ -30,6 +48,25 @@ func NewTeamRepository(db *sqlx.DB, log *slog.Logger) *TeamRepository {
 	}
 }
 
+// WithReplica returns a copy of the repository whose GetTeamByName and GetTeamByID read outside a transaction
+// from replica, or from the primary while the replica is down. A nil replica leaves them on the primary.
+// The copy is meant for the query paths only: the write paths must read their own writes.
+func (tr *TeamRepository) WithReplica(replica *Replica) *TeamRepository {
+	copied := *tr
+	copied.replica = replica
+
+	return &copied
+}
+
+// reads returns the pool of the reads outside a transaction.
+func (tr *TeamRepository) reads() *sqlx.DB {
+	if tr.replica == nil {
+		return tr.db
+	}
+
+	return tr.replica.DB()
+}
+
 func (tr *TeamRepository) CreateTeamWithUsers(ctx context.Context, team api.Team) (*domain.TeamWithMembers, error) {
 	const op = "internal.repository.postgres.CreateTeamWithUsers"
 	log := tr.log.With(slog.String("op", op), slog.String("team_name", team.TeamName))
@@ -108,8 +145,32 @@ func (tr *TeamRepository) insertTeam(ctx context.Context, tx *sqlx.Tx, teamName
 }
 
 func (tr *TeamRepository) upsertTeamMembers(ctx context.Context, tx *sqlx.Tx, teamID int, members []api.TeamMember) error {
+	userIDs := make([]string, len(members))
+	for i, member := range members {
+		userIDs[i] = member.UserId
+	}
+
+	// Adding a deleted user to a team restores the user as a member of this team only.
+	deletedIDs := sq.Select("id").
+		From("users").
+		Where(sq.Eq{"id": userIDs}).
+		Where(sq.NotEq{"deleted_at": nil})
+
+	query, args, err := tr.sq.Delete("user_teams").
+		Where(sq.Expr("user_id IN (?)", deletedIDs)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build memberships delete query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to execute memberships delete: %w", err)
+	}
+
 	insertBuilder := tr.sq.Insert("users").
 		Columns("id", "username", "team_id", "is_active")
+	membershipsBuilder := tr.sq.Insert("user_teams").
+		Columns("user_id", "team_id")
 
 	for _, member := range members {
 		insertBuilder = insertBuilder.Values(
@@ -118,13 +179,16 @@ func (tr *TeamRepository) upsertTeamMembers(ctx context.Context, tx *sqlx.Tx, te
 			teamID,
 			member.IsActive,
 		)
+		membershipsBuilder = membershipsBuilder.Values(member.UserId, teamID)
 	}
 
-	query, args, err := insertBuilder.Suffix(`
+	// An existing user joins the team and keeps the primary team.
+	query, args, err = insertBuilder.Suffix(`
         ON CONFLICT (id) DO UPDATE SET
             username = EXCLUDED.username,
-            team_id = EXCLUDED.team_id,
-            is_active = EXCLUDED.is_active`).
+            team_id = CASE WHEN users.deleted_at IS NULL THEN COALESCE(users.team_id, EXCLUDED.team_id) ELSE EXCLUDED.team_id END,
+            is_active = EXCLUDED.is_active,
+            deleted_at = NULL`).
 		ToSql()
 
 	if err != nil {
@@ -136,17 +200,100 @@ func (tr *TeamRepository) upsertTeamMembers(ctx context.Context, tx *sqlx.Tx, te
 		return fmt.Errorf("failed to execute bulk users upsert: %w", err)
 	}
 
+	query, args, err = membershipsBuilder.Suffix("ON CONFLICT DO NOTHING").ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build memberships insert query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to execute memberships insert: %w", err)
+	}
+
 	return nil
 }
 
-func (tr *TeamRepository) GetTeamByName(ctx context.Context, ext sqlx.ExtContext, name string) (*domain.TeamWithMembers, error) {
+func (tr *TeamRepository) GetTeamByName(ctx context.Context, name string) (*domain.TeamWithMembers, error) {
 	const op = "internal.repository.postgres.GetTeamByName"
+
+	ext := txctx.Ext(ctx, tr.reads())
+
 	log := tr.log.With(slog.String("op", op), slog.String("team_name", name))
 	log.Info("getting team by name")
 
+	team, err := tr.getTeamWithMembers(ctx, ext, sq.Eq{"name": name, "deleted_at": nil}, fmt.Sprintf("team with name '%s'", name))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("team getting successful")
+
+	return team, nil
+}
+
+func (tr *TeamRepository) GetTeamByID(ctx context.Context, id int) (*domain.TeamWithMembers, error) {
+	const op = "internal.repository.postgres.GetTeamByID"
+
+	ext := txctx.Ext(ctx, tr.reads())
+
+	log := tr.log.With(slog.String("op", op), slog.Int("team_id", id))
+	log.Info("getting team by id")
+
+	team, err := tr.getTeamWithMembers(ctx, ext, sq.Eq{"id": id, "deleted_at": nil}, fmt.Sprintf("team with id %d", id))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("team getting successful")
+
+	return team, nil
+}
+
+func (tr *TeamRepository) RenameTeam(ctx context.Context, id int, newName string) error {
+	const op = "internal.repository.postgres.RenameTeam"
+	log := tr.log.With(slog.String("op", op), slog.Int("team_id", id), slog.String("new_team_name", newName))
+	log.Info("renaming team")
+
+	query, args, err := tr.sq.Update("teams").
+		Set("name", newName).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build team rename query: %w", err)
+	}
+
+	res, err := tr.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return &apperrors.TeamAlreadyExistsError{TeamName: newName}
+		}
+
+		return fmt.Errorf("failed to execute team rename: %w", err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%w: team with id %d", apperrors.ErrNotFound, id)
+	}
+
+	log.Info("team renamed successfully")
+
+	return nil
+}
+
+// memberOf matches the users that are members of the team; idColumn names the id column of users.
+func memberOf(idColumn string, teamID int) sq.Sqlizer {
+	return sq.Expr(idColumn+" IN (SELECT user_id FROM user_teams WHERE team_id = ?)", teamID)
+}
+
+// otherTeams selects the memberships of users.id in the teams, not deleted, other than the one given
+// by the placeholder.
+const otherTeams = "SELECT 1 FROM user_teams ut JOIN teams t ON t.id = ut.team_id" +
+	" WHERE ut.user_id = users.id AND ut.team_id <> ? AND t.deleted_at IS NULL"
+
+// getTeamWithMembers reads the team matching where and its members; notFound describes the team in apperrors.ErrNotFound.
+func (tr *TeamRepository) getTeamWithMembers(ctx context.Context, ext sqlx.ExtContext, where sq.Eq, notFound string) (*domain.TeamWithMembers, error) {
 	query, args, err := tr.sq.Select("id", "name").
 		From("teams").
-		Where(sq.Eq{"name": name}).
+		Where(where).
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build select team query: %w", err)
@@ -155,16 +302,17 @@ func (tr *TeamRepository) GetTeamByName(ctx context.Context, ext sqlx.ExtContext
 	var team domain.Team
 	if err := sqlx.GetContext(ctx, ext, &team, query, args...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("%w: team with name '%s'", apperrors.ErrNotFound, name)
+			return nil, fmt.Errorf("%w: %s", apperrors.ErrNotFound, notFound)
 		}
 
-		return nil, fmt.Errorf("failed to get team by name: %w", err)
+		return nil, fmt.Errorf("failed to get team: %w", err)
 	}
 
 	queryMembers, args, err := tr.sq.Select("id", "username", "team_id", "is_active").
 		From("users").
-		Where(sq.Eq{"team_id": team.ID}).
-		OrderBy("username").
+		Where(memberOf("id", team.ID)).
+		Where(sq.Eq{"deleted_at": nil}).
+		OrderBy("username COLLATE " + usernameCollation).
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build select members query: %w", err)
@@ -175,11 +323,155 @@ func (tr *TeamRepository) GetTeamByName(ctx context.Context, ext sqlx.ExtContext
 		return nil, fmt.Errorf("failed to get team members: %w", err)
 	}
 
-	log.Info("team getting successful")
-
 	return &domain.TeamWithMembers{
 		ID:      team.ID,
 		Name:    team.Name,
 		Members: members,
 	}, nil
 }
+
+func (tr *TeamRepository) TryLockTeamForDeactivation(ctx context.Context, teamID int) (bool, error) {
+	const op = "internal.repository.postgres.TryLockTeamForDeactivation"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var locked bool
+	if err := tx.GetContext(ctx, &locked, "SELECT pg_try_advisory_xact_lock($1, $2)", advisoryLockTeamDeactivation, teamID); err != nil {
+		return false, fmt.Errorf("%s: failed to acquire advisory lock: %w", op, err)
+	}
+
+	return locked, nil
+}
+
+func (tr *TeamRepository) DeleteTeam(ctx context.Context, teamID int, deletedAt time.Time) ([]string, error) {
+	const op = "internal.repository.postgres.DeleteTeam"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := tr.sq.Update("teams").
+		Set("deleted_at", deletedAt).
+		Where(sq.Eq{"id": teamID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build team update query: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute team update: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return nil, fmt.Errorf("%s: %w: team with id %d", op, apperrors.ErrNotFound, teamID)
+	}
+
+	// As in DeactivateUsersByTeamID, the rows are locked by id in a subquery first. The members of other teams
+	// stay; the team is already deleted, so it no longer counts among their teams.
+	lockedIDs := sq.Select("id").
+		From("users").
+		Where(memberOf("id", teamID)).
+		Where(sq.Eq{"deleted_at": nil}).
+		Where(sq.Expr("NOT EXISTS ("+otherTeams+")", teamID)).
+		OrderBy("id").
+		Suffix("FOR UPDATE")
+
+	query, args, err = tr.sq.Update("users").
+		Set("deleted_at", deletedAt).
+		Where(sq.Expr("id IN (?)", lockedIDs)).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build members update query: %w", op, err)
+	}
+
+	deletedUserIDs := []string{}
+	if err := tx.SelectContext(ctx, &deletedUserIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute members update: %w", op, err)
+	}
+
+	// The members staying in other teams get one of them as their primary team.
+	query, args, err = tr.sq.Update("users").
+		Set("team_id", sq.Expr("(SELECT MIN(ut.team_id) FROM user_teams ut JOIN teams t ON t.id = ut.team_id"+
+			" WHERE ut.user_id = users.id AND t.deleted_at IS NULL)")).
+		Where(sq.Eq{"team_id": teamID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build primary team update query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute primary team update: %w", op, err)
+	}
+
+	return deletedUserIDs, nil
+}
+
+func (tr *TeamRepository) RestoreTeam(ctx context.Context, teamID int) error {
+	const op = "internal.repository.postgres.RestoreTeam"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := tr.sq.Select("name", "deleted_at").
+		From("teams").
+		Where(sq.Eq{"id": teamID}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build team query: %w", op, err)
+	}
+
+	var deleted struct {
+		Name      string    `db:"name"`
+		DeletedAt time.Time `db:"deleted_at"`
+	}
+
+	if err := tx.GetContext(ctx, &deleted, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w: deleted team with id %d", op, apperrors.ErrNotFound, teamID)
+		}
+
+		return fmt.Errorf("%s: failed to get team: %w", op, err)
+	}
+
+	query, args, err = tr.sq.Update("teams").
+		Set("deleted_at", nil).
+		Where(sq.Eq{"id": teamID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build team update query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return &apperrors.TeamAlreadyExistsError{TeamName: deleted.Name}
+		}
+
+		return fmt.Errorf("%s: failed to execute team update: %w", op, err)
+	}
+
+	// The members deleted with the team share its deletion time; those deleted before it stay deleted.
+	query, args, err = tr.sq.Update("users").
+		Set("deleted_at", nil).
+		Where(memberOf("id", teamID)).
+		Where(sq.Eq{"deleted_at": deleted.DeletedAt}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build members update query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute members update: %w", op, err)
+	}
+
+	return nil
+}
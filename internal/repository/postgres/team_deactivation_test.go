@@ -0,0 +1,68 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamDeactivationRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+	setupPRTest(t)
+	ctx := context.Background()
+
+	repo := NewTeamDeactivationRepository(testDB, logger)
+	teamID, err := NewPullRequestRepository(testDB, logger).GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	createdAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	// The deactivations are recorded and looked up with the team locked in a transaction.
+	require.ErrorIs(t, repo.RecordTeamDeactivation(ctx, &domain.TeamDeactivation{TeamID: teamID}), txctx.ErrNoTransaction)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+
+	_, err = repo.GetLastTeamDeactivation(txctx.With(ctx, tx), teamID)
+	require.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	first := &domain.TeamDeactivation{TeamID: teamID, CreatedAt: createdAt, UserIDs: []string{"rev1"}}
+	require.NoError(t, repo.RecordTeamDeactivation(txctx.With(ctx, tx), first))
+	second := &domain.TeamDeactivation{TeamID: teamID, CreatedAt: createdAt.Add(time.Hour), UserIDs: []string{"rev2", "author"}}
+	require.NoError(t, repo.RecordTeamDeactivation(txctx.With(ctx, tx), second))
+	require.NoError(t, tx.Commit())
+
+	assert.NotZero(t, first.ID)
+	assert.Greater(t, second.ID, first.ID)
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+
+	last, err := repo.GetLastTeamDeactivation(txctx.With(ctx, tx), teamID)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, last.ID)
+	assert.Nil(t, last.JobID)
+	assert.True(t, createdAt.Add(time.Hour).Equal(last.CreatedAt))
+	assert.Equal(t, []string{"author", "rev2"}, last.UserIDs)
+
+	// Undoing the last deactivation makes the one before it the last.
+	require.NoError(t, repo.MarkTeamReactivated(txctx.With(ctx, tx), second.ID, createdAt.Add(2*time.Hour)))
+
+	last, err = repo.GetLastTeamDeactivation(txctx.With(ctx, tx), teamID)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, last.ID)
+	assert.Equal(t, []string{"rev1"}, last.UserIDs)
+
+	assert.ErrorIs(t, repo.MarkTeamReactivated(ctx, 4242, createdAt), apperrors.ErrNotFound)
+}
@@ -5,8 +5,11 @@ package postgres
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -41,7 +44,7 @@ func TestTeamRepository_CreateTeamWithUsers_And_GetTeamByName(t *testing.T) {
 	assert.ErrorAs(t, err, &teamExistsErr, "expected TeamAlreadyExistsError")
 	assert.Equal(t, "backend", teamExistsErr.TeamName)
 
-	fetchedTeam, err := repo.GetTeamByName(ctx, testDB, "backend")
+	fetchedTeam, err := repo.GetTeamByName(ctx, "backend")
 	require.NoError(t, err)
 	assert.Equal(t, createdTeam.ID, fetchedTeam.ID)
 	assert.Equal(t, "backend", fetchedTeam.Name)
@@ -50,9 +53,16 @@ func TestTeamRepository_CreateTeamWithUsers_And_GetTeamByName(t *testing.T) {
 	assert.Equal(t, "Bob", fetchedTeam.Members[1].Username)
 	assert.False(t, fetchedTeam.Members[1].IsActive)
 
-	_, err = repo.GetTeamByName(ctx, testDB, "non-existent")
+	_, err = repo.GetTeamByName(ctx, "non-existent")
 	require.Error(t, err)
 	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	fetchedByID, err := repo.GetTeamByID(ctx, createdTeam.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fetchedTeam, fetchedByID)
+
+	_, err = repo.GetTeamByID(ctx, createdTeam.ID+1)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
 }
 
 func TestTeamRepository_CreateTeam_NoMembers(t *testing.T) {
@@ -74,7 +84,7 @@ func TestTeamRepository_CreateTeam_NoMembers(t *testing.T) {
 	assert.Equal(t, "empty-team", createdTeam.Name)
 	assert.Empty(t, createdTeam.Members)
 
-	fetchedTeam, err := repo.GetTeamByName(ctx, testDB, "empty-team")
+	fetchedTeam, err := repo.GetTeamByName(ctx, "empty-team")
 	require.NoError(t, err)
 	assert.Equal(t, createdTeam.ID, fetchedTeam.ID)
 	assert.Empty(t, fetchedTeam.Members)
@@ -93,25 +103,302 @@ func TestTeamRepository_UpsertTeamMembers(t *testing.T) {
 		TeamName: "team-alpha",
 		Members:  []api.TeamMember{{UserId: "u1", Username: "Alice", IsActive: true}},
 	}
-	_, err := repo.CreateTeamWithUsers(ctx, team1)
+	createdTeam1, err := repo.CreateTeamWithUsers(ctx, team1)
 	require.NoError(t, err)
 
 	team2 := api.Team{
 		TeamName: "team-beta",
 		Members:  []api.TeamMember{{UserId: "u1", Username: "Alice-Updated", IsActive: false}},
 	}
-	createdTeam2, err := repo.CreateTeamWithUsers(ctx, team2)
+	_, err = repo.CreateTeamWithUsers(ctx, team2)
 	require.NoError(t, err)
 
-	fetchedTeam2, err := repo.GetTeamByName(ctx, testDB, "team-beta")
+	// u1 joins team-beta and stays in team-alpha, its primary team.
+	fetchedTeam2, err := repo.GetTeamByName(ctx, "team-beta")
 	require.NoError(t, err)
 	require.Len(t, fetchedTeam2.Members, 1)
 	assert.Equal(t, "u1", fetchedTeam2.Members[0].ID)
 	assert.Equal(t, "Alice-Updated", fetchedTeam2.Members[0].Username)
 	assert.False(t, fetchedTeam2.Members[0].IsActive)
-	assert.Equal(t, createdTeam2.ID, fetchedTeam2.Members[0].TeamID)
+	assert.Equal(t, createdTeam1.ID, fetchedTeam2.Members[0].TeamID)
+
+	fetchedTeam1, err := repo.GetTeamByName(ctx, "team-alpha")
+	require.NoError(t, err)
+	require.Len(t, fetchedTeam1.Members, 1)
+	assert.Equal(t, "Alice-Updated", fetchedTeam1.Members[0].Username)
+}
+
+func TestTeamRepository_RenameTeam(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	truncateTables(t, testDB)
+	repo := NewTeamRepository(testDB, logger)
+	ctx := context.Background()
+
+	team, err := repo.CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "backend",
+		Members:  []api.TeamMember{{UserId: "u1", Username: "Alice", IsActive: true}},
+	})
+	require.NoError(t, err)
+	_, err = repo.CreateTeamWithUsers(ctx, api.Team{TeamName: "payments"})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.RenameTeam(ctx, team.ID, "platform"))
+
+	renamed, err := repo.GetTeamByName(ctx, "platform")
+	require.NoError(t, err)
+	assert.Equal(t, team.ID, renamed.ID)
+	assert.Equal(t, team.Members, renamed.Members)
+
+	_, err = repo.GetTeamByName(ctx, "backend")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	err = repo.RenameTeam(ctx, team.ID, "payments")
+	var existsErr *apperrors.TeamAlreadyExistsError
+	require.ErrorAs(t, err, &existsErr)
+	assert.Equal(t, "payments", existsErr.TeamName)
+
+	err = repo.RenameTeam(ctx, team.ID+100, "orphan")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestTeamRepository_MembersOrderedByCollation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	truncateTables(t, testDB)
+	repo := NewTeamRepository(testDB, logger)
+	ctx := context.Background()
+
+	_, err := repo.CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "mixed-team",
+		Members: []api.TeamMember{
+			{UserId: "u1", Username: "яна", IsActive: true},
+			{UserId: "u2", Username: "Ёжиков", IsActive: true},
+			{UserId: "u3", Username: "bob", IsActive: true},
+			{UserId: "u4", Username: "Жанна", IsActive: true},
+			{UserId: "u5", Username: "Alice", IsActive: true},
+			{UserId: "u6", Username: "елена", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	team, err := repo.GetTeamByName(ctx, "mixed-team")
+	require.NoError(t, err)
+
+	var usernames []string
+	for _, member := range team.Members {
+		usernames = append(usernames, member.Username)
+	}
+
+	assert.Equal(t, []string{"Alice", "bob", "Ёжиков", "елена", "Жанна", "яна"}, usernames)
+}
+
+func TestTeamRepository_TryLockTeamForDeactivation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	truncateTables(t, testDB)
+	repo := NewTeamRepository(testDB, logger)
+	ctx := context.Background()
+
+	team, err := repo.CreateTeamWithUsers(ctx, api.Team{TeamName: "locked-team"})
+	require.NoError(t, err)
+
+	first, err := testDB.Beginx()
+	require.NoError(t, err)
+	defer first.Rollback()
+
+	locked, err := repo.TryLockTeamForDeactivation(txctx.With(ctx, first), team.ID)
+	require.NoError(t, err)
+	assert.True(t, locked)
+
+	second, err := testDB.Beginx()
+	require.NoError(t, err)
+
+	locked, err = repo.TryLockTeamForDeactivation(txctx.With(ctx, second), team.ID)
+	require.NoError(t, err)
+	assert.False(t, locked, "the lock must not be granted while the first transaction is open")
+
+	locked, err = repo.TryLockTeamForDeactivation(txctx.With(ctx, second), team.ID+1)
+	require.NoError(t, err)
+	assert.True(t, locked, "locks of other teams must be independent")
+	require.NoError(t, second.Rollback())
+
+	require.NoError(t, first.Commit())
+
+	third, err := testDB.Beginx()
+	require.NoError(t, err)
+	defer third.Rollback()
+
+	locked, err = repo.TryLockTeamForDeactivation(txctx.With(ctx, third), team.ID)
+	require.NoError(t, err)
+	assert.True(t, locked, "the lock must be released when the transaction ends")
+}
+
+func TestTeamRepository_DeleteAndRestoreTeam(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	setupPRTest(t)
+	repo := NewTeamRepository(testDB, logger)
+	userRepo := NewUserRepository(testDB, logger)
+	ctx := context.Background()
+	deletedAt := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+
+	team, err := repo.GetTeamByName(ctx, "pr-team")
+	require.NoError(t, err)
+
+	// rev4 was deleted before the team and stays deleted when the team is restored.
+	require.NoError(t, userRepo.DeleteUser(ctx, "rev4", deletedAt.Add(-time.Hour)))
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+
+	deleted, err := repo.DeleteTeam(txctx.With(ctx, tx), team.ID, deletedAt)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"author", "rev1", "rev2", "rev3-inactive"}, deleted)
+	require.NoError(t, tx.Commit())
+
+	_, err = repo.GetTeamByID(ctx, team.ID)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	_, err = NewPullRequestRepository(testDB, logger).GetAuthorTeamID(ctx, "author")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	// The name of a deleted team is free, so the team cannot come back while another team holds it.
+	reused, err := repo.CreateTeamWithUsers(ctx, api.Team{TeamName: "pr-team"})
+	require.NoError(t, err)
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+
+	err = repo.RestoreTeam(txctx.With(ctx, tx), team.ID)
+	assert.ErrorIs(t, err, apperrors.ErrAlreadyExists)
+	require.NoError(t, tx.Rollback())
+
+	require.NoError(t, repo.RenameTeam(ctx, reused.ID, "new-team"))
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.RestoreTeam(txctx.With(ctx, tx), team.ID))
+	require.NoError(t, tx.Commit())
+
+	restored, err := repo.GetTeamByID(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "pr-team", restored.Name)
+	assert.Len(t, restored.Members, 4)
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	err = repo.RestoreTeam(txctx.With(ctx, tx), team.ID)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestTeamRepository_MultipleTeams(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	setupPRTest(t)
+	repo := NewTeamRepository(testDB, logger)
+	userRepo := NewUserRepository(testDB, logger)
+	prRepo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	team, err := repo.GetTeamByName(ctx, "pr-team")
+	require.NoError(t, err)
+
+	// rev1 joins other-team and stays in pr-team, its primary team.
+	other, err := repo.CreateTeamWithUsers(ctx, api.Team{
+		TeamName: "other-team",
+		Members: []api.TeamMember{
+			{UserId: "rev1", Username: "Reviewer1", IsActive: true},
+			{UserId: "other", Username: "Other", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	team, err = repo.GetTeamByID(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Len(t, team.Members, 5)
+
+	teamID, err := prRepo.GetAuthorTeamID(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, team.ID, teamID)
+
+	reviewers, err := prRepo.GetRandomActiveReviewers(ctx, other.ID, []string{"other"}, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev1"}, reviewers, "rev1 reviews for both teams")
+
+	teamIDs, err := prRepo.GetUserTeamIDs(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, []int{team.ID, other.ID}, teamIDs)
+
+	// The reviewers of the pull request of other come from other-team, not from the primary team of rev1,
+	// and the pull requests of rev1 count toward its primary team only.
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "other", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, prRepo.AssignReviewers(txctx.With(ctx, tx), "pr-1", []string{"rev1"}))
+	require.NoError(t, prRepo.CreatePR(txctx.With(ctx, tx), &domain.PullRequest{ID: "pr-2", Name: "PR 2", AuthorID: "rev1", Status: api.PullRequestStatusOPEN}))
+	require.NoError(t, tx.Commit())
+
+	prTeamID, err := prRepo.GetPRTeamID(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, prTeamID)
+
+	ageStats, err := prRepo.GetOpenPRAgeStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, ageStats, 2)
+	assert.Equal(t, "other-team", ageStats[0].TeamName)
+	assert.Equal(t, 1, ageStats[0].OpenPRs)
+	assert.Equal(t, "pr-team", ageStats[1].TeamName)
+	assert.Equal(t, 1, ageStats[1].OpenPRs)
+
+	// The primary team of a user must be one of the memberships, which the commit checks.
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+	_, err = tx.Exec("DELETE FROM user_teams WHERE user_id = 'rev1' AND team_id = $1", team.ID)
+	require.NoError(t, err)
+
+	// The memberships are read in the transaction, which sees its own changes.
+	teamIDs, err = prRepo.GetUserTeamIDs(txctx.With(ctx, tx), "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, []int{other.ID}, teamIDs)
+	require.Error(t, tx.Commit())
+
+	// rev1 keeps reviewing for pr-team when other-team is deactivated.
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+
+	deactivated, err := userRepo.DeactivateUsersByTeamID(txctx.With(ctx, tx), other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other"}, deactivated)
+
+	// rev1 is not deleted with pr-team, and other-team becomes its primary team.
+	deleted, err := repo.DeleteTeam(txctx.With(ctx, tx), team.ID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"author", "rev2", "rev3-inactive", "rev4"}, deleted)
+	require.NoError(t, tx.Commit())
+
+	teamID, err = prRepo.GetAuthorTeamID(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, teamID)
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, repo.RestoreTeam(txctx.With(ctx, tx), team.ID))
+	require.NoError(t, tx.Commit())
 
-	fetchedTeam1, err := repo.GetTeamByName(ctx, testDB, "team-alpha")
+	restored, err := repo.GetTeamByID(ctx, team.ID)
 	require.NoError(t, err)
-	assert.Empty(t, fetchedTeam1.Members)
+	assert.Len(t, restored.Members, 5)
 }
@@ -6,9 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
 	"github.com/jmoiron/sqlx"
 )
@@ -30,37 +33,56 @@ func NewUserRepository(db *sqlx.DB, log *slog.Logger) *UserRepository {
 type userWithTeamName struct {
 	UserID   string `db:"user_id"`
 	Username string `db:"username"`
+	TeamID   int    `db:"team_id"`
 	TeamName string `db:"team_name"`
 	IsActive bool   `db:"is_active"`
+	// WasActive is the status the user had before the update.
+	WasActive bool `db:"was_active"`
+	// PreviousTeamID is the team the user was in before TransferUser.
+	PreviousTeamID int `db:"previous_team_id"`
 }
 
-func (ur *UserRepository) SetIsActive(ctx context.Context, userID string, isActive bool) (*api.User, error) {
+func (ur *UserRepository) SetIsActive(ctx context.Context, userID string, isActive bool) (*api.User, bool, error) {
 	const op = "internal.repository.postgres.SetIsActive"
 
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
 	ur.log.With(slog.String("op", op))
 	ur.log.Info("setting", slog.String("userID", userID), slog.Bool("is active", isActive))
 
+	// The previous status is read from a locked subquery, as RETURNING only sees the updated row.
+	previous := sq.Select("id", "is_active").
+		From("users").
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		Suffix("FOR UPDATE")
+
 	query, args, err := ur.sq.Update("users").
 		Set("is_active", isActive).
-		Where(sq.Eq{"id": userID}).
+		FromSelect(previous, "previous").
+		Where("users.id = previous.id").
 		Suffix(`RETURNING 
             users.id as user_id, 
             users.username, 
+            users.team_id, 
             (SELECT name FROM teams WHERE id = users.team_id) as team_name, 
-            users.is_active`).
+            users.is_active,
+            previous.is_active as was_active`).
 		ToSql()
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to build update user query: %w", err)
+		return nil, false, fmt.Errorf("failed to build update user query: %w", err)
 	}
 
 	var dbUser userWithTeamName
-	if err = ur.db.QueryRowxContext(ctx, query, args...).StructScan(&dbUser); err != nil {
+	if err = tx.QueryRowxContext(ctx, query, args...).StructScan(&dbUser); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("%w: user with id '%s'", apperrors.ErrNotFound, userID)
+			return nil, false, fmt.Errorf("%w: user with id '%s'", apperrors.ErrNotFound, userID)
 		}
 
-		return nil, fmt.Errorf("failed to execute update user status: %w", err)
+		return nil, false, fmt.Errorf("failed to execute update user status: %w", err)
 	}
 
 	ur.log.Info("setting completed successfully")
@@ -69,16 +91,32 @@ func (ur *UserRepository) SetIsActive(ctx context.Context, userID string, isActi
 		UserId:   dbUser.UserID,
 		Username: dbUser.Username,
 		TeamName: dbUser.TeamName,
+		TeamId:   dbUser.TeamID,
 		IsActive: dbUser.IsActive,
-	}, nil
+	}, dbUser.WasActive, nil
 }
 
-func (ur *UserRepository) DeactivateUsersByTeamID(ctx context.Context, tx *sqlx.Tx, teamID int) ([]string, error) {
+func (ur *UserRepository) DeactivateUsersByTeamID(ctx context.Context, teamID int) ([]string, error) {
 	const op = "internal.repository.postgres.DeactivateUsersByTeamID"
 
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// UPDATE locks rows in an unspecified order, so the rows are locked by id in a subquery first.
+	// The members of other teams keep reviewing for them.
+	lockedIDs := sq.Select("id").
+		From("users").
+		Where(memberOf("id", teamID)).
+		Where(sq.Eq{"is_active": true, "deleted_at": nil}).
+		Where(sq.Expr("NOT EXISTS ("+otherTeams+")", teamID)).
+		OrderBy("id").
+		Suffix("FOR UPDATE")
+
 	query, args, err := ur.sq.Update("users").
 		Set("is_active", false).
-		Where(sq.Eq{"team_id": teamID, "is_active": true}).
+		Where(sq.Expr("id IN (?)", lockedIDs)).
 		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
@@ -92,3 +130,219 @@ func (ur *UserRepository) DeactivateUsersByTeamID(ctx context.Context, tx *sqlx.
 
 	return deactivatedUserIDs, nil
 }
+
+func (ur *UserRepository) ActivateUsers(ctx context.Context, teamID int, userIDs []string) ([]string, error) {
+	const op = "internal.repository.postgres.ActivateUsers"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	activatedUserIDs := []string{}
+	if len(userIDs) == 0 {
+		return activatedUserIDs, nil
+	}
+
+	// As in DeactivateUsersByTeamID, the rows are locked by id in a subquery first.
+	lockedIDs := sq.Select("id").
+		From("users").
+		Where(memberOf("id", teamID)).
+		Where(sq.Eq{"id": userIDs, "is_active": false, "deleted_at": nil}).
+		OrderBy("id").
+		Suffix("FOR UPDATE")
+
+	query, args, err := ur.sq.Update("users").
+		Set("is_active", true).
+		Where(sq.Expr("id IN (?)", lockedIDs)).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	if err := tx.SelectContext(ctx, &activatedUserIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	return activatedUserIDs, nil
+}
+
+func (ur *UserRepository) SetUserRole(ctx context.Context, userID string, role domain.UserRole) error {
+	const op = "internal.repository.postgres.SetUserRole"
+
+	query, args, err := ur.sq.Update("users").
+		Set("role", string(role)).
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := ur.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+	}
+
+	return nil
+}
+
+func (ur *UserRepository) GetUserRole(ctx context.Context, userID string) (domain.UserRole, error) {
+	const op = "internal.repository.postgres.GetUserRole"
+
+	query, args, err := ur.sq.Select("role").
+		From("users").
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var role domain.UserRole
+	if err := ur.db.GetContext(ctx, &role, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+		}
+
+		return "", fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return role, nil
+}
+
+func (ur *UserRepository) TransferUser(ctx context.Context, userID string, teamID int) (*api.User, int, error) {
+	const op = "internal.repository.postgres.TransferUser"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// As in SetIsActive, the previous team is read from a locked subquery.
+	previous := sq.Select("id", "team_id").
+		From("users").
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		Suffix("FOR UPDATE")
+
+	query, args, err := ur.sq.Update("users").
+		Set("team_id", teamID).
+		FromSelect(previous, "previous").
+		Where("users.id = previous.id").
+		Suffix(`RETURNING
+            users.id as user_id,
+            users.username,
+            users.team_id,
+            (SELECT name FROM teams WHERE id = users.team_id) as team_name,
+            users.is_active,
+            previous.team_id as previous_team_id`).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	var dbUser userWithTeamName
+	if err := tx.QueryRowxContext(ctx, query, args...).StructScan(&dbUser); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, 0, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+		}
+
+		return nil, 0, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	// The user leaves the previous team for the new one and stays in the other teams.
+	query, args, err = ur.sq.Delete("user_teams").
+		Where(sq.Eq{"user_id": userID, "team_id": dbUser.PreviousTeamID}).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to build membership delete query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to execute membership delete: %w", op, err)
+	}
+
+	query, args, err = ur.sq.Insert("user_teams").
+		Columns("user_id", "team_id").
+		Values(userID, teamID).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to build membership insert query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to execute membership insert: %w", op, err)
+	}
+
+	return &api.User{
+		UserId:   dbUser.UserID,
+		Username: dbUser.Username,
+		TeamName: dbUser.TeamName,
+		TeamId:   dbUser.TeamID,
+		IsActive: dbUser.IsActive,
+	}, dbUser.PreviousTeamID, nil
+}
+
+func (ur *UserRepository) DeleteUser(ctx context.Context, userID string, deletedAt time.Time) error {
+	const op = "internal.repository.postgres.DeleteUser"
+
+	query, args, err := ur.sq.Update("users").
+		Set("deleted_at", deletedAt).
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := txctx.Ext(ctx, ur.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+	}
+
+	return nil
+}
+
+func (ur *UserRepository) RestoreUser(ctx context.Context, userID string) (*api.User, error) {
+	const op = "internal.repository.postgres.RestoreUser"
+
+	query, args, err := ur.sq.Update("users").
+		Set("deleted_at", nil).
+		Where(sq.Eq{"id": userID}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		Where("EXISTS (SELECT 1 FROM teams WHERE id = users.team_id AND deleted_at IS NULL)").
+		Suffix(`RETURNING
+            users.id as user_id,
+            users.username,
+            users.team_id,
+            (SELECT name FROM teams WHERE id = users.team_id) as team_name,
+            users.is_active`).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	var dbUser userWithTeamName
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, ur.db), &dbUser, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: deleted user with id '%s'", op, apperrors.ErrNotFound, userID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	return &api.User{
+		UserId:   dbUser.UserID,
+		Username: dbUser.Username,
+		TeamName: dbUser.TeamName,
+		TeamId:   dbUser.TeamID,
+		IsActive: dbUser.IsActive,
+	}, nil
+}
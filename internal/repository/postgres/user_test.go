@@ -5,8 +5,11 @@ package postgres
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,7 +25,7 @@ func TestUserRepository_SetIsActive(t *testing.T) {
 	userRepo := NewUserRepository(testDB, logger)
 	ctx := context.Background()
 
-	_, err := teamRepo.CreateTeamWithUsers(ctx, api.Team{
+	team, err := teamRepo.CreateTeamWithUsers(ctx, api.Team{
 		TeamName: "test-team",
 		Members: []api.TeamMember{
 			{UserId: "user-to-deactivate", Username: "Test User", IsActive: true},
@@ -30,10 +33,16 @@ func TestUserRepository_SetIsActive(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	updatedUser, err := userRepo.SetIsActive(ctx, "user-to-deactivate", false)
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+
+	updatedUser, wasActive, err := userRepo.SetIsActive(txctx.With(ctx, tx), "user-to-deactivate", false)
 	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	assert.True(t, wasActive)
 	assert.Equal(t, "user-to-deactivate", updatedUser.UserId)
 	assert.Equal(t, "test-team", updatedUser.TeamName)
+	assert.Equal(t, team.ID, updatedUser.TeamId)
 	assert.False(t, updatedUser.IsActive)
 
 	var isActive bool
@@ -41,15 +50,54 @@ func TestUserRepository_SetIsActive(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, isActive)
 
-	updatedUser, err = userRepo.SetIsActive(ctx, "user-to-deactivate", true)
+	tx, err = testDB.Beginx()
 	require.NoError(t, err)
+
+	updatedUser, wasActive, err = userRepo.SetIsActive(txctx.With(ctx, tx), "user-to-deactivate", true)
+	require.NoError(t, err)
+	assert.False(t, wasActive)
 	assert.True(t, updatedUser.IsActive)
 
-	_, err = userRepo.SetIsActive(ctx, "non-existent-user", false)
+	_, wasActive, err = userRepo.SetIsActive(txctx.With(ctx, tx), "user-to-deactivate", true)
+	require.NoError(t, err)
+	assert.True(t, wasActive, "the user is already active within the transaction")
+	require.NoError(t, tx.Commit())
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+
+	defer tx.Rollback()
+
+	_, _, err = userRepo.SetIsActive(txctx.With(ctx, tx), "non-existent-user", false)
 	require.Error(t, err)
 	assert.ErrorContains(t, err, apperrors.ErrNotFound.Error())
 }
 
+func TestUserRepository_UserRole(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	setupPRTest(t)
+	userRepo := NewUserRepository(testDB, logger)
+	ctx := context.Background()
+
+	role, err := userRepo.GetUserRole(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleMember, role, "a new user is a member")
+
+	require.NoError(t, userRepo.SetUserRole(ctx, "rev1", domain.RoleAdmin))
+
+	role, err = userRepo.GetUserRole(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleAdmin, role)
+
+	assert.ErrorIs(t, userRepo.SetUserRole(ctx, "ghost", domain.RoleLead), apperrors.ErrNotFound)
+
+	_, err = userRepo.GetUserRole(ctx, "ghost")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
 func TestUserRepository_DeactivateUsersByTeamID(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test in short mode")
@@ -81,7 +129,7 @@ func TestUserRepository_DeactivateUsersByTeamID(t *testing.T) {
 	tx, err := testDB.Beginx()
 	require.NoError(t, err)
 
-	deactivatedIDs, err := userRepo.DeactivateUsersByTeamID(ctx, tx, targetTeam.ID)
+	deactivatedIDs, err := userRepo.DeactivateUsersByTeamID(txctx.With(ctx, tx), targetTeam.ID)
 	require.NoError(t, err)
 
 	assert.ElementsMatch(t, []string{"u1-active", "u2-active"}, deactivatedIDs)
@@ -97,9 +145,125 @@ func TestUserRepository_DeactivateUsersByTeamID(t *testing.T) {
 	assert.False(t, u3active, "u3 should remain inactive")
 	assert.True(t, u4active, "u4 from other team should not be affected")
 
-	deactivatedAgain, err := userRepo.DeactivateUsersByTeamID(ctx, tx, targetTeam.ID)
+	deactivatedAgain, err := userRepo.DeactivateUsersByTeamID(txctx.With(ctx, tx), targetTeam.ID)
 	require.NoError(t, err)
 	assert.Empty(t, deactivatedAgain, "calling again on the same team should return no users")
 
 	require.NoError(t, tx.Rollback())
 }
+
+func TestUserRepository_ActivateUsers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	setupPRTest(t)
+	teamRepo := NewTeamRepository(testDB, logger)
+	userRepo := NewUserRepository(testDB, logger)
+	ctx := context.Background()
+
+	team, err := teamRepo.GetTeamByName(ctx, "pr-team")
+	require.NoError(t, err)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = userRepo.DeactivateUsersByTeamID(txctx.With(ctx, tx), team.ID)
+	require.NoError(t, err)
+
+	// rev1 comes back by hand, and rev3-inactive was inactive before.
+	_, err = tx.Exec("UPDATE users SET is_active = TRUE WHERE id = 'rev1'")
+	require.NoError(t, err)
+
+	activatedIDs, err := userRepo.ActivateUsers(txctx.With(ctx, tx), team.ID, []string{"author", "rev1", "rev2", "ghost"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"author", "rev2"}, activatedIDs)
+
+	var active bool
+	require.NoError(t, tx.Get(&active, "SELECT is_active FROM users WHERE id = 'rev2'"))
+	assert.True(t, active)
+
+	activatedIDs, err = userRepo.ActivateUsers(txctx.With(ctx, tx), team.ID+1, []string{"rev4"})
+	require.NoError(t, err)
+	assert.Empty(t, activatedIDs, "the users of other teams are left alone")
+
+	activatedIDs, err = userRepo.ActivateUsers(txctx.With(ctx, tx), team.ID, nil)
+	require.NoError(t, err)
+	assert.Empty(t, activatedIDs)
+}
+
+func TestUserRepository_DeleteAndRestoreUser(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	setupPRTest(t)
+	userRepo := NewUserRepository(testDB, logger)
+	prRepo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	teamID, err := prRepo.GetAuthorTeamID(ctx, "author")
+	require.NoError(t, err)
+
+	require.NoError(t, userRepo.DeleteUser(ctx, "rev1", time.Now()))
+	assert.ErrorIs(t, userRepo.DeleteUser(ctx, "rev1", time.Now()), apperrors.ErrNotFound)
+
+	// A deleted user is left out of the team and of the reviewer selection.
+	team, err := NewTeamRepository(testDB, logger).GetTeamByID(ctx, teamID)
+	require.NoError(t, err)
+	assert.Len(t, team.Members, 4)
+
+	reviewers, err := prRepo.GetRandomActiveReviewers(ctx, teamID, []string{"author"}, 5)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"rev2", "rev4"}, reviewers)
+
+	_, _, err = userRepo.SetIsActive(ctx, "rev1", true)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	user, err := userRepo.RestoreUser(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, "pr-team", user.TeamName)
+	assert.True(t, user.IsActive, "deletion alone does not deactivate a user")
+
+	_, err = userRepo.RestoreUser(ctx, "rev1")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestUserRepository_TransferUser(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	setupPRTest(t)
+	userRepo := NewUserRepository(testDB, logger)
+	prRepo := NewPullRequestRepository(testDB, logger)
+	ctx := context.Background()
+
+	teamID, err := prRepo.GetAuthorTeamID(ctx, "rev1")
+	require.NoError(t, err)
+	other, err := NewTeamRepository(testDB, logger).CreateTeamWithUsers(ctx, api.Team{TeamName: "other-team"})
+	require.NoError(t, err)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+
+	user, previousTeamID, err := userRepo.TransferUser(txctx.With(ctx, tx), "rev1", other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, teamID, previousTeamID)
+	assert.Equal(t, "other-team", user.TeamName)
+	assert.Equal(t, other.ID, user.TeamId)
+
+	_, _, err = userRepo.TransferUser(txctx.With(ctx, tx), "ghost", other.ID)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	require.NoError(t, tx.Commit())
+
+	// The reviewers of the user's pull requests now come from the new team.
+	newTeamID, err := prRepo.GetAuthorTeamID(ctx, "rev1")
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, newTeamID)
+
+	reviewers, err := prRepo.GetRandomActiveReviewers(ctx, teamID, []string{"author"}, 5)
+	require.NoError(t, err)
+	assert.NotContains(t, reviewers, "rev1")
+}
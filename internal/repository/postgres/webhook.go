@@ -0,0 +1,391 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type WebhookRepository struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+}
+
+func NewWebhookRepository(db *sqlx.DB, log *slog.Logger) *WebhookRepository {
+	return &WebhookRepository{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+var webhookReturning = "RETURNING id, url, events, secret, created_at, updated_at"
+
+var webhookDeliveryColumns = []string{
+	"id", "webhook_id", "event", "pull_request_id", "payload", "status", "attempts", "next_attempt_at",
+	"last_error", "response_status", "created_at", "updated_at",
+}
+
+var webhookDeliveryReturning = "RETURNING " + strings.Join(webhookDeliveryColumns, ", ")
+
+// webhookRow is a webhook as stored: the events are a text array.
+type webhookRow struct {
+	ID        int64          `db:"id"`
+	URL       string         `db:"url"`
+	Events    pq.StringArray `db:"events"`
+	Secret    string         `db:"secret"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+func (r *webhookRow) toDomain() *domain.Webhook {
+	events := make([]domain.WebhookEventType, len(r.Events))
+	for i, event := range r.Events {
+		events[i] = domain.WebhookEventType(event)
+	}
+
+	return &domain.Webhook{
+		ID:        r.ID,
+		URL:       r.URL,
+		Events:    events,
+		Secret:    r.Secret,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+func webhookEvents(events []domain.WebhookEventType) any {
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = string(event)
+	}
+
+	return pq.Array(names)
+}
+
+func (wr *WebhookRepository) CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	const op = "internal.repository.postgres.CreateWebhook"
+
+	createdAt := timestampOrNow(webhook.CreatedAt)
+
+	query, args, err := wr.sq.Insert("webhooks").
+		Columns("url", "events", "secret", "created_at", "updated_at").
+		Values(webhook.URL, webhookEvents(webhook.Events), webhook.Secret, createdAt, createdAt).
+		Suffix(webhookReturning).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var created webhookRow
+	if err := wr.db.GetContext(ctx, &created, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return created.toDomain(), nil
+}
+
+func (wr *WebhookRepository) GetWebhook(ctx context.Context, id int64) (*domain.Webhook, error) {
+	const op = "internal.repository.postgres.GetWebhook"
+
+	query, args, err := wr.sq.Select("id", "url", "events", "secret", "created_at", "updated_at").
+		From("webhooks").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var row webhookRow
+	if err := wr.db.GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: webhook %d", op, apperrors.ErrNotFound, id)
+		}
+
+		return nil, fmt.Errorf("%s: failed to get webhook: %w", op, err)
+	}
+
+	return row.toDomain(), nil
+}
+
+func (wr *WebhookRepository) ListWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	const op = "internal.repository.postgres.ListWebhooks"
+
+	query, args, err := wr.sq.Select("id", "url", "events", "secret", "created_at", "updated_at").
+		From("webhooks").
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	rows := []webhookRow{}
+	if err := wr.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to list webhooks: %w", op, err)
+	}
+
+	webhooks := make([]domain.Webhook, len(rows))
+	for i := range rows {
+		webhooks[i] = *rows[i].toDomain()
+	}
+
+	return webhooks, nil
+}
+
+func (wr *WebhookRepository) UpdateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	const op = "internal.repository.postgres.UpdateWebhook"
+
+	query, args, err := wr.sq.Update("webhooks").
+		Set("url", webhook.URL).
+		Set("events", webhookEvents(webhook.Events)).
+		Set("secret", webhook.Secret).
+		Set("updated_at", timestampOrNow(webhook.UpdatedAt)).
+		Where(sq.Eq{"id": webhook.ID}).
+		Suffix(webhookReturning).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	var updated webhookRow
+	if err := wr.db.GetContext(ctx, &updated, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: webhook %d", op, apperrors.ErrNotFound, webhook.ID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	return updated.toDomain(), nil
+}
+
+func (wr *WebhookRepository) DeleteWebhook(ctx context.Context, id int64) error {
+	const op = "internal.repository.postgres.DeleteWebhook"
+
+	query, args, err := wr.sq.Delete("webhooks").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build delete query: %w", op, err)
+	}
+
+	res, err := wr.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute delete: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%s: %w: webhook %d", op, apperrors.ErrNotFound, id)
+	}
+
+	return nil
+}
+
+func (wr *WebhookRepository) EnqueueWebhookDeliveries(ctx context.Context, event *domain.WebhookEvent) (int, error) {
+	const op = "internal.repository.postgres.EnqueueWebhookDeliveries"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	occurredAt := event.OccurredAt.UTC()
+
+	// The values are cast, as the select list does not tell Postgres their types.
+	// The subquery keeps the default placeholders: the outer statement numbers them.
+	subscribed := sq.Select("id").
+		Column("?::varchar", event.Type).
+		Column("?::varchar", event.PullRequestID).
+		Column("?::jsonb", string(event.Payload)).
+		Column("?::timestamptz", occurredAt).
+		Column("?::timestamptz", occurredAt).
+		Column("?::timestamptz", occurredAt).
+		From("webhooks").
+		Where("?::text = ANY(events)", event.Type).
+		OrderBy("id")
+
+	query, args, err := wr.sq.Insert("webhook_deliveries").
+		Columns("webhook_id", "event", "pull_request_id", "payload", "next_attempt_at", "created_at", "updated_at").
+		Select(subscribed).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	queued, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to count queued deliveries: %w", op, err)
+	}
+
+	return int(queued), nil
+}
+
+func (wr *WebhookRepository) ClaimWebhookDeliveries(ctx context.Context, limit int, now, leaseUntil time.Time) ([]domain.WebhookDelivery, error) {
+	const op = "internal.repository.postgres.ClaimWebhookDeliveries"
+
+	// SKIP LOCKED lets concurrent workers claim disjoint batches instead of waiting for each other.
+	claimable := sq.Select("id").
+		From("webhook_deliveries").
+		Where(sq.Eq{"status": domain.WebhookDeliveryPending}).
+		Where(sq.LtOrEq{"next_attempt_at": now.UTC()}).
+		OrderBy("next_attempt_at", "id").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query, args, err := wr.sq.Update("webhook_deliveries").
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("next_attempt_at", leaseUntil.UTC()).
+		Set("updated_at", now.UTC()).
+		Where(sq.Expr("id IN (?)", claimable)).
+		Suffix(webhookDeliveryReturning).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	claimed := []domain.WebhookDelivery{}
+	if err := wr.db.SelectContext(ctx, &claimed, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	// RETURNING does not keep the order of the subquery.
+	slices.SortFunc(claimed, func(a, b domain.WebhookDelivery) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return claimed, nil
+}
+
+func (wr *WebhookRepository) FinishWebhookAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	const op = "internal.repository.postgres.FinishWebhookAttempt"
+
+	query, args, err := wr.sq.Update("webhook_deliveries").
+		Set("status", delivery.Status).
+		Set("next_attempt_at", delivery.NextAttemptAt.UTC()).
+		Set("last_error", delivery.LastError).
+		Set("response_status", delivery.ResponseStatus).
+		Set("updated_at", timestampOrNow(delivery.UpdatedAt)).
+		Where(sq.Eq{"id": delivery.ID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := wr.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%s: %w: webhook delivery %d", op, apperrors.ErrNotFound, delivery.ID)
+	}
+
+	return nil
+}
+
+func (wr *WebhookRepository) GetWebhookDelivery(ctx context.Context, id int64) (*domain.WebhookDelivery, error) {
+	const op = "internal.repository.postgres.GetWebhookDelivery"
+
+	query, args, err := wr.sq.Select(webhookDeliveryColumns...).
+		From("webhook_deliveries").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var d domain.WebhookDelivery
+	if err := wr.db.GetContext(ctx, &d, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: webhook delivery %d", op, apperrors.ErrNotFound, id)
+		}
+
+		return nil, fmt.Errorf("%s: failed to get webhook delivery: %w", op, err)
+	}
+
+	return &d, nil
+}
+
+func (wr *WebhookRepository) ListWebhookDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	const op = "internal.repository.postgres.ListWebhookDeliveries"
+
+	builder := wr.sq.Select(webhookDeliveryColumns...).
+		From("webhook_deliveries").
+		OrderBy("id DESC").
+		Limit(uint64(filter.Limit))
+
+	if filter.WebhookID != 0 {
+		builder = builder.Where(sq.Eq{"webhook_id": filter.WebhookID})
+	}
+
+	if filter.Status != "" {
+		builder = builder.Where(sq.Eq{"status": filter.Status})
+	}
+
+	if filter.AfterID != 0 {
+		builder = builder.Where(sq.Lt{"id": filter.AfterID})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	deliveries := []domain.WebhookDelivery{}
+	if err := wr.db.SelectContext(ctx, &deliveries, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to list webhook deliveries: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+func (wr *WebhookRepository) RequeueWebhookDelivery(ctx context.Context, id int64, dueAt time.Time) (*domain.WebhookDelivery, error) {
+	const op = "internal.repository.postgres.RequeueWebhookDelivery"
+
+	query, args, err := wr.sq.Update("webhook_deliveries").
+		Set("status", domain.WebhookDeliveryPending).
+		Set("attempts", 0).
+		Set("next_attempt_at", dueAt.UTC()).
+		Set("updated_at", dueAt.UTC()).
+		Where(sq.Eq{"id": id, "status": domain.WebhookDeliveryDead}).
+		Suffix(webhookDeliveryReturning).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	var d domain.WebhookDelivery
+	if err := wr.db.GetContext(ctx, &d, query, args...); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: failed to execute update: %w", op, err)
+		}
+
+		// Nothing was updated: the delivery either does not exist or is not dead.
+		if _, err := wr.GetWebhookDelivery(ctx, id); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil, fmt.Errorf("%s: %w: webhook delivery %d", op, apperrors.ErrDeliveryNotDead, id)
+	}
+
+	return &d, nil
+}
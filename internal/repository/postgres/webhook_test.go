@@ -0,0 +1,149 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+	}
+
+	setupPRTest(t)
+	repo := NewWebhookRepository(testDB, logger)
+	ctx := context.Background()
+	createdAt := time.Date(2026, time.January, 5, 9, 0, 0, 0, time.UTC)
+
+	merges, err := repo.CreateWebhook(ctx, &domain.Webhook{
+		URL: "https://ci.example.com/hooks", Events: []domain.WebhookEventType{domain.WebhookPRMerged},
+		Secret: "0123456789abcdef", CreatedAt: createdAt,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), merges.ID)
+	assert.Equal(t, createdAt, merges.UpdatedAt.UTC())
+
+	all, err := repo.CreateWebhook(ctx, &domain.Webhook{
+		URL: "https://chat.example.com/hooks", Secret: "fedcba9876543210", CreatedAt: createdAt,
+		Events: []domain.WebhookEventType{domain.WebhookPRCreated, domain.WebhookPRMerged, domain.WebhookReviewerReassigned},
+	})
+	require.NoError(t, err)
+
+	webhooks, err := repo.ListWebhooks(ctx)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 2)
+	assert.Equal(t, []domain.WebhookEventType{domain.WebhookPRMerged}, webhooks[0].Events)
+
+	updatedAt := createdAt.Add(time.Hour)
+	updated, err := repo.UpdateWebhook(ctx, &domain.Webhook{
+		ID: merges.ID, URL: "https://ci.example.com/v2/hooks", Secret: merges.Secret, UpdatedAt: updatedAt,
+		Events: []domain.WebhookEventType{domain.WebhookPRMerged, domain.WebhookReviewerReassigned},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://ci.example.com/v2/hooks", updated.URL)
+	assert.Equal(t, createdAt, updated.CreatedAt.UTC())
+	assert.Equal(t, updatedAt, updated.UpdatedAt.UTC())
+
+	_, err = repo.UpdateWebhook(ctx, &domain.Webhook{ID: 42, URL: "https://example.com"})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	occurredAt := createdAt.Add(2 * time.Hour)
+
+	tx, err := testDB.Beginx()
+	require.NoError(t, err)
+	queued, err := repo.EnqueueWebhookDeliveries(txctx.With(ctx, tx), &domain.WebhookEvent{
+		Type: domain.WebhookPRCreated, PullRequestID: "pr-1", Payload: []byte(`{"event":"pr.created"}`), OccurredAt: occurredAt,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, queued, "only the webhook subscribed to pr.created")
+	require.NoError(t, tx.Rollback())
+
+	deliveries, err := repo.ListWebhookDeliveries(ctx, domain.WebhookDeliveryFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, deliveries, "a rolled back change queues nothing")
+
+	tx, err = testDB.Beginx()
+	require.NoError(t, err)
+	queued, err = repo.EnqueueWebhookDeliveries(txctx.With(ctx, tx), &domain.WebhookEvent{
+		Type: domain.WebhookPRMerged, PullRequestID: "pr-1", Payload: []byte(`{"event":"pr.merged"}`), OccurredAt: occurredAt,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, queued)
+	require.NoError(t, tx.Commit())
+
+	claimed, err := repo.ClaimWebhookDeliveries(ctx, 10, occurredAt.Add(-time.Second), occurredAt.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "nothing is due before the event occurred")
+
+	leaseUntil := occurredAt.Add(time.Minute)
+	claimed, err = repo.ClaimWebhookDeliveries(ctx, 10, occurredAt, leaseUntil)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, []int64{merges.ID, all.ID}, []int64{claimed[0].WebhookID, claimed[1].WebhookID})
+	assert.Equal(t, 1, claimed[0].Attempts)
+	assert.Equal(t, leaseUntil, claimed[0].NextAttemptAt.UTC())
+	assert.JSONEq(t, `{"event":"pr.merged"}`, string(claimed[0].Payload))
+
+	claimed2, err := repo.ClaimWebhookDeliveries(ctx, 10, occurredAt, leaseUntil)
+	require.NoError(t, err)
+	assert.Empty(t, claimed2, "claimed deliveries are leased")
+
+	delivered := claimed[0]
+	delivered.Status = domain.WebhookDeliveryDelivered
+	delivered.UpdatedAt = occurredAt
+	require.NoError(t, repo.FinishWebhookAttempt(ctx, &delivered))
+
+	failure, status := "webhook responded with status 500", 500
+	dead := claimed[1]
+	dead.Status = domain.WebhookDeliveryDead
+	dead.LastError = &failure
+	dead.ResponseStatus = &status
+	dead.UpdatedAt = occurredAt
+	require.NoError(t, repo.FinishWebhookAttempt(ctx, &dead))
+
+	stored, err := repo.GetWebhookDelivery(ctx, dead.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.WebhookDeliveryDead, stored.Status)
+	assert.Equal(t, &failure, stored.LastError)
+	assert.Equal(t, &status, stored.ResponseStatus)
+
+	deliveries, err = repo.ListWebhookDeliveries(ctx, domain.WebhookDeliveryFilter{WebhookID: all.ID, Status: domain.WebhookDeliveryDead, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, dead.ID, deliveries[0].ID)
+
+	deliveries, err = repo.ListWebhookDeliveries(ctx, domain.WebhookDeliveryFilter{AfterID: dead.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, delivered.ID, deliveries[0].ID)
+
+	_, err = repo.RequeueWebhookDelivery(ctx, delivered.ID, occurredAt)
+	assert.ErrorIs(t, err, apperrors.ErrDeliveryNotDead)
+
+	_, err = repo.RequeueWebhookDelivery(ctx, 42, occurredAt)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	requeuedAt := occurredAt.Add(time.Hour)
+	requeued, err := repo.RequeueWebhookDelivery(ctx, dead.ID, requeuedAt)
+	require.NoError(t, err)
+	assert.Equal(t, domain.WebhookDeliveryPending, requeued.Status)
+	assert.Equal(t, 0, requeued.Attempts)
+	assert.Equal(t, requeuedAt, requeued.NextAttemptAt.UTC())
+
+	require.NoError(t, repo.DeleteWebhook(ctx, all.ID))
+	assert.ErrorIs(t, repo.DeleteWebhook(ctx, all.ID), apperrors.ErrNotFound)
+
+	_, err = repo.GetWebhookDelivery(ctx, dead.ID)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound, "the deliveries are deleted with their webhook")
+
+	assert.ErrorIs(t, repo.FinishWebhookAttempt(ctx, &dead), apperrors.ErrNotFound)
+}
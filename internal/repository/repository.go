@@ -1,5 +1,14 @@
 // package repository defines the interfaces for the data persistence layer.
 // These interfaces abstract the underlying database implementation from the service layer.
+//
+// Locking conventions: transactions that lock several rows must acquire the locks in a fixed order,
+// otherwise two transactions over overlapping rows can deadlock. Implementations lock users before
+// pull requests, pull requests before pending assignments, and lock rows of the same table
+// in ascending primary key order.
+//
+// Transactions: the methods run their queries in the transaction carried by their context, see txctx,
+// and on the connection pool outside of one. The methods documented as running in a transaction
+// return txctx.ErrNoTransaction without one.
 package repository
 
 import (
@@ -8,32 +17,81 @@ import (
 
 	"github.com/YusovID/pr-reviewer-service/internal/domain"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
-	"github.com/jmoiron/sqlx"
 )
 
 // TeamRepository defines the contract for interacting with team and user data.
 type TeamRepository interface {
-	// CreateTeamWithUsers creates a new team and upserts its members.
-	// This operation is expected to be transactional.
+	// CreateTeamWithUsers creates a new team and upserts its members. Existing members join the team
+	// and keep their primary teams. This operation is expected to be transactional.
 	// It returns apperrors.ErrAlreadyExists if a team with the same name already exists.
 	CreateTeamWithUsers(ctx context.Context, team api.Team) (*domain.TeamWithMembers, error)
 
 	// GetTeamByName retrieves a team by its unique name, along with its list of members.
-	// The ext argument allows this method to be executed within a transaction (*sqlx.Tx)
-	// or directly on a DB connection (*sqlx.DB).
 	// It returns apperrors.ErrNotFound if the team is not found.
-	GetTeamByName(ctx context.Context, ext sqlx.ExtContext, name string) (*domain.TeamWithMembers, error)
+	GetTeamByName(ctx context.Context, name string) (*domain.TeamWithMembers, error)
+
+	// GetTeamByID is GetTeamByName for the team ID, which unlike the name never changes.
+	GetTeamByID(ctx context.Context, id int) (*domain.TeamWithMembers, error)
+
+	// RenameTeam changes the name of a team in place, so its members and everything else referencing its ID stay with it.
+	// It returns apperrors.ErrNotFound if the team does not exist and apperrors.ErrAlreadyExists if another team has the name.
+	RenameTeam(ctx context.Context, id int, newName string) error
+
+	// TryLockTeamForDeactivation takes a transaction-scoped lock that serializes deactivations of a team.
+	// It does not wait: false is returned if another transaction holds the lock.
+	// The lock is released when the transaction ends.
+	TryLockTeamForDeactivation(ctx context.Context, teamID int) (bool, error)
+
+	// DeleteTeam soft-deletes a team together with its members that are not deleted yet and not members
+	// of other teams, marking them deleted at deletedAt, and returns the IDs of those members. The members
+	// of other teams whose primary team it was get one of those instead.
+	// Deleted teams and users are left out by every read.
+	// This method is intended to be run within a transaction. It returns apperrors.ErrNotFound if the team
+	// does not exist or is already deleted.
+	DeleteTeam(ctx context.Context, teamID int, deletedAt time.Time) ([]string, error)
+
+	// RestoreTeam undoes DeleteTeam: it restores the team and the members deleted with it, which stay inactive.
+	// It returns apperrors.ErrNotFound if the team is not deleted and apperrors.ErrAlreadyExists if another
+	// team has taken its name since.
+	RestoreTeam(ctx context.Context, teamID int) error
 }
 
 // UserRepository defines the contract for user-specific data operations.
 type UserRepository interface {
-	// SetIsActive updates the active status of a user.
+	// SetIsActive updates the active status of a user in a transaction and reports whether the user was active before.
+	// The user is locked for the rest of the transaction.
 	// It returns apperrors.ErrNotFound if the user does not exist.
-	SetIsActive(ctx context.Context, userID string, isActive bool) (*api.User, error)
+	SetIsActive(ctx context.Context, userID string, isActive bool) (*api.User, bool, error)
 
-	// DeactivateUsersByTeamID deactivates all active users belonging to a specific team ID.
+	// DeactivateUsersByTeamID deactivates the active members of a team that are not members of other teams.
 	// This method is intended to be run within a transaction and returns the IDs of the deactivated users.
-	DeactivateUsersByTeamID(ctx context.Context, tx *sqlx.Tx, teamID int) ([]string, error)
+	// The users are locked in ascending ID order.
+	DeactivateUsersByTeamID(ctx context.Context, teamID int) ([]string, error)
+
+	// ActivateUsers activates the inactive users among userIDs that are still members of the team and returns their IDs.
+	// This method is intended to be run within a transaction. The users are locked in ascending ID order.
+	ActivateUsers(ctx context.Context, teamID int, userIDs []string) ([]string, error)
+
+	// SetUserRole sets the role of a user.
+	// It returns apperrors.ErrNotFound if the user does not exist.
+	SetUserRole(ctx context.Context, userID string, role domain.UserRole) error
+
+	// GetUserRole returns the role of a user.
+	// It returns apperrors.ErrNotFound if the user does not exist.
+	GetUserRole(ctx context.Context, userID string) (domain.UserRole, error)
+
+	// DeleteUser soft-deletes a user, marking the user deleted at deletedAt.
+	// It returns apperrors.ErrNotFound if the user does not exist or is already deleted.
+	DeleteUser(ctx context.Context, userID string, deletedAt time.Time) error
+
+	// TransferUser moves a user from the primary team to another one, which becomes the primary team,
+	// and returns the user as updated together with the ID of the previous primary team. This method is intended to be run within a transaction.
+	// It returns apperrors.ErrNotFound if the user does not exist.
+	TransferUser(ctx context.Context, userID string, teamID int) (*api.User, int, error)
+
+	// RestoreUser undoes DeleteUser and returns the user, who stays inactive.
+	// It returns apperrors.ErrNotFound if the user is not deleted or the team of the user is.
+	RestoreUser(ctx context.Context, userID string) (*api.User, error)
 }
 
 // PRQueryRepository defines the contract for read-only pull request operations, following the CQRS pattern.
@@ -42,45 +100,102 @@ type PRQueryRepository interface {
 	// Returns apperrors.ErrNotFound if the PR is not found.
 	GetPRByID(ctx context.Context, prID string) (*domain.PullRequest, error)
 
-	// GetPRByIDWithReviewers retrieves a pull request and its assigned reviewers.
+	// GetPRByIDWithReviewers retrieves a pull request and its assigned reviewers with their reviews.
 	// Returns apperrors.ErrNotFound if the PR is not found.
 	GetPRByIDWithReviewers(ctx context.Context, prID string) (*domain.PullRequest, error)
 
 	// GetReviewerIDs retrieves the IDs of all reviewers for a given pull request.
-	// The ext argument allows this method to be executed within a transaction or on a direct DB connection.
-	GetReviewerIDs(ctx context.Context, ext sqlx.ExtContext, prID string) ([]string, error)
+	GetReviewerIDs(ctx context.Context, prID string) ([]string, error)
+
+	// GetReviews returns the reviews of the reviewers currently assigned to a pull request, ordered by reviewer ID.
+	GetReviews(ctx context.Context, prID string) ([]domain.Review, error)
 
 	// GetReviewAssignments retrieves all pull requests assigned to a specific user for review.
-	GetReviewAssignments(ctx context.Context, userID string) ([]domain.PullRequest, error)
+	// A non-empty customFields limits them to the pull requests whose custom fields, formatted as text, hold the given values.
+	GetReviewAssignments(ctx context.Context, userID string, customFields map[string]string) ([]domain.PullRequest, error)
+
+	// SearchPRs performs a full-text search over pull request names and descriptions and returns the requested page
+	// of matches, most relevant first, together with the total number of matches.
+	SearchPRs(ctx context.Context, filter domain.PRSearchFilter) ([]domain.PullRequest, int, error)
+
+	// ListPRs returns the requested page of pull requests matching the filter, newest first, with their reviewers.
+	ListPRs(ctx context.Context, filter domain.PRListFilter) ([]domain.PullRequest, error)
 
-	// GetUserStats retrieves review statistics for all users.
-	GetUserStats(ctx context.Context) ([]domain.Stats, error)
+	// GetUserStats retrieves review statistics for all users, limited to the period.
+	GetUserStats(ctx context.Context, period domain.StatsPeriod) ([]domain.Stats, error)
+
+	// GetTeamStats returns how fast the pull requests of every team with any first review or merge within the period
+	// got reviewed and merged, grouping pull requests by their team, the one GetPRTeamID returns. Percentiles are interpolated linearly.
+	GetTeamStats(ctx context.Context, period domain.StatsPeriod) ([]domain.TeamStats, error)
+
+	// GetLeaderboard returns up to limit users with the most reviews of the pull requests merged within the period,
+	// by the number of reviews and then by username. Users without any are left out.
+	GetLeaderboard(ctx context.Context, period domain.StatsPeriod, limit int) ([]domain.LeaderboardEntry, error)
+
+	// GetOpenPRAgeStats returns the age distribution of open pull requests for every team that has any,
+	// grouping pull requests by their team, the one GetPRTeamID returns. Percentiles are interpolated linearly.
+	GetOpenPRAgeStats(ctx context.Context) ([]domain.OpenPRAgeStats, error)
+
+	// GetStatsByUserIDs retrieves review statistics for the specified users.
+	GetStatsByUserIDs(ctx context.Context, userIDs []string) ([]domain.Stats, error)
 
 	// GetOpenPRsByReviewers finds all open pull requests where any of the specified user IDs are reviewers.
 	// This method is intended for transactional use to ensure data consistency during reassignments.
-	GetOpenPRsByReviewers(ctx context.Context, tx *sqlx.Tx, userIDs []string) ([]domain.PullRequest, error)
+	// The pull requests are locked and returned in ascending ID order.
+	GetOpenPRsByReviewers(ctx context.Context, userIDs []string) ([]domain.PullRequest, error)
 }
 
 // PRCommandRepository defines the contract for write and locking operations on pull requests, following the CQRS pattern.
-// All methods are expected to be executed within a transaction.
+// All methods run in a transaction.
 type PRCommandRepository interface {
-	// CreatePR inserts a new pull request record.
+	// CreatePR inserts a new pull request record and sets pr.CreatedAt to the value as stored.
 	// It returns apperrors.ErrAlreadyExists if a PR with the same ID already exists.
-	CreatePR(ctx context.Context, tx *sqlx.Tx, pr *domain.PullRequest) error
+	CreatePR(ctx context.Context, pr *domain.PullRequest) error
 
 	// AssignReviewers associates a list of reviewers with a pull request.
-	AssignReviewers(ctx context.Context, tx *sqlx.Tx, prID string, reviewerIDs []string) error
+	AssignReviewers(ctx context.Context, prID string, reviewerIDs []string) error
 
 	// GetPRByIDWithLock retrieves a pull request by its ID and acquires a row-level lock ("FOR UPDATE").
 	// This prevents concurrent modifications to the PR record within the transaction.
 	// It returns apperrors.ErrNotFound if the PR is not found.
-	GetPRByIDWithLock(ctx context.Context, tx *sqlx.Tx, prID string) (*domain.PullRequest, error)
+	GetPRByIDWithLock(ctx context.Context, prID string) (*domain.PullRequest, error)
 
 	// UpdatePRStatus updates the status and potentially the merged_at timestamp of a pull request.
-	UpdatePRStatus(ctx context.Context, tx *sqlx.Tx, prID string, status api.PullRequestStatus, mergedAt time.Time) error
+	// A merged or closed pull request no longer needs more reviewers.
+	// It returns merged_at as stored, which is nil unless the pull request is merged.
+	UpdatePRStatus(ctx context.Context, prID string, status api.PullRequestStatus, mergedAt time.Time) (*time.Time, error)
 
 	// ReplaceReviewer atomically replaces an old reviewer with a new one for a specific pull request.
-	ReplaceReviewer(ctx context.Context, tx *sqlx.Tx, prID string, oldReviewerID string, newReviewerID string) error
+	// The review of the new reviewer is pending.
+	ReplaceReviewer(ctx context.Context, prID string, oldReviewerID string, newReviewerID string) error
+
+	// ReplaceReviewers applies a batch of replacements in a single statement, so that deactivating a
+	// large team does not take a round trip per reviewer. It must run in a transaction. The reviews of
+	// the new reviewers are pending.
+	ReplaceReviewers(ctx context.Context, replacements []domain.ReviewerReplacement) error
+
+	// CountOpenPRsByAuthor returns the number of open pull requests of the author.
+	// It first takes a transaction-scoped lock on the author, so that concurrent creations
+	// by the same author are counted one after another. The lock is released when the transaction ends.
+	CountOpenPRsByAuthor(ctx context.Context, authorID string) (int, error)
+
+	// LockActiveUsers returns those of the users that are active, ordered by ID, and takes a shared lock on them,
+	// so that they cannot be deactivated until the transaction ends. A deactivation in progress is waited for.
+	LockActiveUsers(ctx context.Context, userIDs []string) ([]string, error)
+
+	// SetNeedMoreReviewers updates the flag telling that a pull request lacks reviewers.
+	SetNeedMoreReviewers(ctx context.Context, prID string, need bool) error
+
+	// SetReviewState records the decision of a reviewer made at reviewedAt, replacing the previous one.
+	// It returns apperrors.ErrReviewerNotAssigned if the user is not a reviewer of the pull request.
+	SetReviewState(ctx context.Context, prID string, reviewerID string, state domain.ReviewState, reviewedAt time.Time) error
+
+	// BackfillReviewerDurations recomputes the assignment and first review times of the reviewers of up to limit
+	// pull requests with IDs greater than afterID, the way migration 000036 derived them: a reviewer was assigned
+	// by their latest assignment in the assignment history, or else with the pull request, and first reviewed
+	// by their decision unless an earlier one is recorded. It returns the ID of the last of the pull requests,
+	// empty if there were none, and the number of reviewers whose times changed.
+	BackfillReviewerDurations(ctx context.Context, afterID string, limit int) (string, int, error)
 }
 
 // UserPRRepository defines a contract for operations that cross the User and PullRequest domains,
@@ -90,11 +205,405 @@ type UserPRRepository interface {
 	// It returns apperrors.ErrNotFound if the user is not found.
 	GetAuthorTeamID(ctx context.Context, authorID string) (int, error)
 
-	// GetReviewerTeamID returns the team ID for a given user ID (reviewer).
+	// IsUserActive reports whether a user is active.
+	// It returns apperrors.ErrNotFound if the user is not found.
+	IsUserActive(ctx context.Context, userID string) (bool, error)
+
+	// GetUserTeamIDs returns the IDs of the teams a user is a member of, in ascending order.
 	// It returns apperrors.ErrNotFound if the user is not found.
-	GetReviewerTeamID(ctx context.Context, reviewerID string) (int, error)
+	GetUserTeamIDs(ctx context.Context, userID string) ([]int, error)
+
+	// GetPRTeamID returns the team the reviewers of a pull request are picked from: the primary team of its author,
+	// also if the author has been deleted since. It returns apperrors.ErrNotFound if the pull request is not found.
+	GetPRTeamID(ctx context.Context, prID string) (int, error)
 
 	// GetRandomActiveReviewers selects a specified number of random, active reviewers from a team,
-	// excluding a list of provided user IDs.
+	// excluding a list of provided user IDs. Users lent to the team by an unexpired borrow count as its members.
 	GetRandomActiveReviewers(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error)
+
+	// GetLeastLoadedActiveReviewers selects a specified number of active reviewers from a team
+	// with the fewest open review assignments, excluding a list of provided user IDs.
+	// Users lent to the team by an unexpired borrow count as its members. Ties are broken randomly.
+	GetLeastLoadedActiveReviewers(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error)
+
+	// GetLeastRecentlyAssignedActiveReviewers selects a specified number of active reviewers from a team
+	// whose latest assignment in the assignment history is the oldest, excluding a list of provided user IDs.
+	// Users never assigned come first and ties are broken by user ID, so that repeated calls take turns through the team.
+	// Users lent to the team by an unexpired borrow count as its members.
+	GetLeastRecentlyAssignedActiveReviewers(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error)
+
+	// GetReplacementAlternatives returns the users that the automatic selection does not consider
+	// for a team: its inactive members and the active members of other teams, excluding a list of provided user IDs.
+	// Up to limit users of each kind are returned, those with the fewest open reviews first.
+	GetReplacementAlternatives(ctx context.Context, teamID int, excludeUserIDs []string, limit int) ([]domain.ReplacementAlternative, error)
+}
+
+// StatsViewRepository defines the contract for the precomputed all-time review statistics,
+// which spare the readers the joins over every pull request that GetUserStats and GetTeamStats do.
+type StatsViewRepository interface {
+	// RefreshStatsView recomputes the precomputed statistics from the current data without blocking their readers.
+	// It does not wait for a refresh running elsewhere: false is returned and nothing is recomputed in that case.
+	RefreshStatsView(ctx context.Context) (bool, error)
+
+	// GetStatsView returns the statistics as of the last refresh.
+	GetStatsView(ctx context.Context) (*domain.StatsView, error)
+}
+
+// PolicyRepository defines the contract for storing per-team assignment policies.
+type PolicyRepository interface {
+	// GetTeamPolicy returns the assignment policy of a team.
+	// A team without a stored policy gets an empty policy rather than an error.
+	GetTeamPolicy(ctx context.Context, teamID int) (*domain.TeamPolicy, error)
+
+	// UpsertTeamPolicy creates or replaces the assignment policy of a team.
+	UpsertTeamPolicy(ctx context.Context, policy *domain.TeamPolicy) (*domain.TeamPolicy, error)
+}
+
+// CustomFieldRepository defines the contract for storing the custom pull request fields of teams.
+type CustomFieldRepository interface {
+	// GetCustomFields returns the custom fields of a team ordered by key.
+	// A team without custom fields gets an empty slice rather than an error.
+	GetCustomFields(ctx context.Context, teamID int) ([]domain.CustomField, error)
+
+	// ReplaceCustomFields replaces all custom fields of a team with fields and returns them ordered by key.
+	// It returns apperrors.ErrNotFound if the team does not exist.
+	ReplaceCustomFields(ctx context.Context, teamID int, fields []domain.CustomField) ([]domain.CustomField, error)
+}
+
+// BorrowRepository defines the contract for reviewer borrows between teams.
+type BorrowRepository interface {
+	// CreateBorrow stores a new borrow request and returns it with its ID and request time.
+	CreateBorrow(ctx context.Context, borrow *domain.ReviewerBorrow) (*domain.ReviewerBorrow, error)
+
+	// AcceptBorrow lends up to the requested number of active members of the lender team,
+	// those with the fewest open reviews first, until acceptedAt plus the borrow duration.
+	// This operation is expected to be transactional.
+	// It returns apperrors.ErrNotFound if the borrow does not exist, apperrors.ErrBorrowNotPending
+	// if it has already been accepted and apperrors.ErrInsufficientCapacity if the lender team has no active members.
+	AcceptBorrow(ctx context.Context, borrowID int64, acceptedAt time.Time) (*domain.ReviewerBorrow, error)
+
+	// ListBorrows returns the borrows the team takes part in as either side, except the expired ones, newest first.
+	ListBorrows(ctx context.Context, teamID int) ([]domain.ReviewerBorrow, error)
+}
+
+// CreatePRRequestRepository defines the contract for the queue of asynchronous pull request creations.
+// Requests are served in the order they were accepted.
+type CreatePRRequestRepository interface {
+	// EnqueueCreatePR stores a new queued request and returns it with its ID and enqueue time.
+	EnqueueCreatePR(ctx context.Context, req *domain.CreatePRRequest) (*domain.CreatePRRequest, error)
+
+	// ClaimCreatePRRequests marks up to limit of the oldest queued requests as processing at claimedAt and returns them.
+	// Requests left processing since before staleBefore, whose worker is presumed dead, are claimed again.
+	// Concurrent claims never return the same request.
+	ClaimCreatePRRequests(ctx context.Context, limit int, claimedAt time.Time, staleBefore time.Time) ([]domain.CreatePRRequest, error)
+
+	// FinishCreatePRRequest stores the status, error and finish time of a claimed request.
+	// A request put back to the queued status is claimed again by a later call of ClaimCreatePRRequests.
+	FinishCreatePRRequest(ctx context.Context, req *domain.CreatePRRequest) error
+
+	// GetCreatePRRequest retrieves a request by its ID. It returns apperrors.ErrNotFound if there is no such request.
+	GetCreatePRRequest(ctx context.Context, id int64) (*domain.CreatePRRequest, error)
+}
+
+// DeactivationJobRepository defines the contract for batched team deactivations.
+type DeactivationJobRepository interface {
+	// CreateDeactivationJob stores a running job together with its deactivated users and its batches of pull requests,
+	// and returns it with its ID and creation time. A job without batches is stored as succeeded.
+	// This method is intended to be run within the transaction that deactivates the users.
+	CreateDeactivationJob(ctx context.Context, job *domain.DeactivationJob, batches [][]string) (*domain.DeactivationJob, error)
+
+	// ClaimDeactivationBatch locks the oldest pending batch for the rest of the transaction and returns it.
+	// Batches locked by other transactions are skipped, so concurrent workers never claim the same batch.
+	// It returns apperrors.ErrNotFound if no batch is pending.
+	ClaimDeactivationBatch(ctx context.Context) (*domain.DeactivationBatch, error)
+
+	// FinishDeactivationBatch marks a claimed batch as done, adds its reassigned reviews and warnings to the job
+	// and marks the job as succeeded at finishedAt once its last batch is done.
+	FinishDeactivationBatch(ctx context.Context, batch *domain.DeactivationBatch, reassigned int, warnings []string, finishedAt time.Time) error
+
+	// GetDeactivationJob retrieves a job with its warnings. It returns apperrors.ErrNotFound if there is no such job.
+	GetDeactivationJob(ctx context.Context, id int64) (*domain.DeactivationJob, error)
+}
+
+// TeamDeactivationRepository defines the contract for the records of the team deactivations that can be undone.
+type TeamDeactivationRepository interface {
+	// RecordTeamDeactivation stores a deactivation with its users and sets its ID.
+	// It is intended to be run within the transaction that deactivates the users.
+	RecordTeamDeactivation(ctx context.Context, deactivation *domain.TeamDeactivation) error
+
+	// GetLastTeamDeactivation retrieves the latest deactivation of a team that has not been undone, with its users,
+	// and locks it for the rest of the transaction. It returns apperrors.ErrNotFound if there is none.
+	GetLastTeamDeactivation(ctx context.Context, teamID int) (*domain.TeamDeactivation, error)
+
+	// MarkTeamReactivated marks a deactivation as undone at reactivatedAt.
+	MarkTeamReactivated(ctx context.Context, id int64, reactivatedAt time.Time) error
+}
+
+// JobRepository defines the contract for the long-running jobs and the leases of the workers running them.
+// Every state change follows domain.JobState.CanTransitionTo.
+type JobRepository interface {
+	// CreateJob stores a new queued job and returns it with its ID.
+	CreateJob(ctx context.Context, job *domain.Job) (*domain.Job, error)
+
+	// GetJob retrieves a job by its ID. It returns apperrors.ErrNotFound if there is no such job.
+	GetJob(ctx context.Context, id int64) (*domain.Job, error)
+
+	// ClaimJob marks the oldest queued job, or a running job whose lease expired before claimedAt,
+	// as running under owner until leaseUntil and returns it. Concurrent claims never return the same job.
+	// It returns apperrors.ErrNotFound if no job can be claimed.
+	ClaimJob(ctx context.Context, owner string, claimedAt time.Time, leaseUntil time.Time) (*domain.Job, error)
+
+	// RenewJobLease stores the progress of a running job, extends its lease to leaseUntil and reports
+	// whether its cancellation has been requested. It returns apperrors.ErrJobLeaseLost if owner no longer runs the job.
+	RenewJobLease(ctx context.Context, id int64, owner string, done int, total int, leaseUntil time.Time) (bool, error)
+
+	// FinishJob stores the final status, result, error, progress and finish time of a job run by owner
+	// and releases its lease. It returns apperrors.ErrJobLeaseLost if owner no longer runs the job.
+	FinishJob(ctx context.Context, job *domain.Job, owner string) error
+
+	// CancelJob cancels a queued job at cancelledAt and requests the cancellation of a running one.
+	// It returns the job as stored afterwards; a finished job is returned unchanged.
+	// It returns apperrors.ErrNotFound if there is no such job.
+	CancelJob(ctx context.Context, id int64, cancelledAt time.Time) (*domain.Job, error)
+}
+
+// AssignmentHistoryRepository defines the contract for the append-only log of reviewer assignments.
+type AssignmentHistoryRepository interface {
+	// RecordAssignments appends entries to the assignment history.
+	// It is intended to be run within the transaction that changes the reviewers.
+	RecordAssignments(ctx context.Context, records []domain.AssignmentRecord) error
+
+	// GetCurrentAssignments returns the latest history entry for every reviewer currently assigned to the pull request.
+	// Reviewers assigned before the history was introduced have no entry and are not returned.
+	GetCurrentAssignments(ctx context.Context, prID string) ([]domain.AssignmentRecord, error)
+
+	// ListAssignmentHistory returns every history entry of the pull request, oldest first.
+	// It returns an empty slice for a pull request without entries.
+	ListAssignmentHistory(ctx context.Context, prID string) ([]domain.AssignmentRecord, error)
+
+	// ListReplacementsOf returns the history entries recorded for cause since the given time in which a user
+	// among replacedUserIDs was replaced, oldest first.
+	ListReplacementsOf(ctx context.Context, replacedUserIDs []string, cause domain.AssignmentCause, since time.Time) ([]domain.AssignmentRecord, error)
+}
+
+// PendingAssignmentRepository defines the contract for the queue of pull requests waiting for reviewers.
+// Entries are served by descending priority and, within a priority, in the order they were queued.
+type PendingAssignmentRepository interface {
+	// EnqueuePending adds a pull request to the queue or updates the priority of its entry.
+	// An updated entry keeps its place among the entries of the same priority.
+	EnqueuePending(ctx context.Context, prID string, teamID int, priority int) error
+
+	// DequeuePending removes the entry of a pull request; it does nothing if the pull request is not queued.
+	DequeuePending(ctx context.Context, prID string) error
+
+	// GetPendingWithLock retrieves the entry of a pull request and acquires a row-level lock ("FOR UPDATE").
+	// It returns apperrors.ErrNotFound if the pull request is not queued.
+	GetPendingWithLock(ctx context.Context, prID string) (*domain.PendingAssignment, error)
+
+	// ListPending returns up to limit entries in serving order. A non-empty teamName
+	// limits the entries to the pull requests of that team.
+	ListPending(ctx context.Context, teamName string, limit int) ([]domain.PendingAssignment, error)
+
+	// ListDuePending is like ListPending over all teams but skips the pull requests
+	// whose deferred assignment is later than now.
+	ListDuePending(ctx context.Context, now time.Time, limit int) ([]domain.PendingAssignment, error)
+
+	// ListUnqueuedNeedingReviewers returns the IDs of up to limit open pull requests flagged with
+	// need_more_reviewers that are not queued, in ascending order and greater than afterID.
+	ListUnqueuedNeedingReviewers(ctx context.Context, afterID string, limit int) ([]string, error)
+}
+
+// SubscriptionRepository defines the contract for the subscriptions of users to pull request notifications.
+type SubscriptionRepository interface {
+	// Subscribe subscribes a user to a pull request and returns the subscription. A repeated subscription
+	// is returned unchanged with created set to false.
+	// It returns apperrors.ErrNotFound if the pull request or the user does not exist.
+	Subscribe(ctx context.Context, prID string, userID string) (sub *domain.PRSubscription, created bool, err error)
+
+	// GetSubscriberIDs returns the IDs of the users subscribed to a pull request in ascending order.
+	GetSubscriberIDs(ctx context.Context, prID string) ([]string, error)
+}
+
+// NotificationDeliveryRepository defines the contract for the log of notification deliveries.
+type NotificationDeliveryRepository interface {
+	// CreateDelivery stores the outcome of the first attempt of a delivery and returns it with its ID.
+	CreateDelivery(ctx context.Context, delivery *domain.NotificationDelivery) (*domain.NotificationDelivery, error)
+
+	// GetDelivery retrieves a delivery by its ID. It returns apperrors.ErrNotFound if there is no such delivery.
+	GetDelivery(ctx context.Context, id int64) (*domain.NotificationDelivery, error)
+
+	// ListDeliveries returns the deliveries matching filter, newest first.
+	ListDeliveries(ctx context.Context, filter domain.DeliveryFilter) ([]domain.NotificationDelivery, error)
+
+	// RecordDeliveryAttempt stores the outcome of another attempt of a delivery made at attemptedAt
+	// and returns the delivery as stored afterwards. It returns apperrors.ErrNotFound if there is no such delivery.
+	RecordDeliveryAttempt(ctx context.Context, id int64, status domain.DeliveryStatus, deliveryErr *string, attemptedAt time.Time) (*domain.NotificationDelivery, error)
+}
+
+// FreezeWindowRepository defines the contract for the periods during which pull requests may not be merged.
+type FreezeWindowRepository interface {
+	// CreateFreezeWindow stores a window and returns it with its ID and team name.
+	CreateFreezeWindow(ctx context.Context, window *domain.FreezeWindow) (*domain.FreezeWindow, error)
+
+	// ListFreezeWindows returns the windows matching filter in the order they start.
+	ListFreezeWindows(ctx context.Context, filter domain.FreezeWindowFilter) ([]domain.FreezeWindow, error)
+
+	// DeleteFreezeWindow removes a window. It returns apperrors.ErrNotFound if there is no such window.
+	DeleteFreezeWindow(ctx context.Context, id int64) error
+}
+
+// GitLabUserRepository defines the contract for the mapping of GitLab usernames to user IDs.
+type GitLabUserRepository interface {
+	// SetGitLabUser maps a GitLab username to a user, replacing its previous mapping, and returns the mapping as stored.
+	// It returns apperrors.ErrNotFound if the user does not exist.
+	SetGitLabUser(ctx context.Context, mapping *domain.GitLabUser) (*domain.GitLabUser, error)
+
+	// GetGitLabUser returns the mapping of a GitLab username.
+	// It returns apperrors.ErrNotFound if the username is not mapped.
+	GetGitLabUser(ctx context.Context, gitLabUsername string) (*domain.GitLabUser, error)
+
+	// ListGitLabUsers returns every mapping ordered by GitLab username.
+	ListGitLabUsers(ctx context.Context) ([]domain.GitLabUser, error)
+
+	// DeleteGitLabUser removes the mapping of a GitLab username.
+	// It returns apperrors.ErrNotFound if the username is not mapped.
+	DeleteGitLabUser(ctx context.Context, gitLabUsername string) error
+}
+
+// OIDCSubjectRepository defines the contract for the mapping of the subjects of the identity provider to user IDs.
+type OIDCSubjectRepository interface {
+	// SetOIDCSubject maps a subject to a user, replacing its previous mapping, and returns the mapping as stored.
+	// It returns apperrors.ErrNotFound if the user does not exist.
+	SetOIDCSubject(ctx context.Context, mapping *domain.OIDCSubject) (*domain.OIDCSubject, error)
+
+	// GetOIDCSubject returns the mapping of a subject.
+	// It returns apperrors.ErrNotFound if the subject is not mapped.
+	GetOIDCSubject(ctx context.Context, subject string) (*domain.OIDCSubject, error)
+
+	// ListOIDCSubjects returns every mapping ordered by subject.
+	ListOIDCSubjects(ctx context.Context) ([]domain.OIDCSubject, error)
+
+	// DeleteOIDCSubject removes the mapping of a subject.
+	// It returns apperrors.ErrNotFound if the subject is not mapped.
+	DeleteOIDCSubject(ctx context.Context, subject string) error
+}
+
+// SlackUserRepository defines the contract for the mapping of users to Slack members.
+type SlackUserRepository interface {
+	// SetSlackUser maps a user to a Slack member, replacing its previous mapping, and returns the mapping as stored.
+	// It returns apperrors.ErrNotFound if the user does not exist.
+	SetSlackUser(ctx context.Context, mapping *domain.SlackUser) (*domain.SlackUser, error)
+
+	// GetSlackUser returns the mapping of a user.
+	// It returns apperrors.ErrNotFound if the user is not mapped.
+	GetSlackUser(ctx context.Context, userID string) (*domain.SlackUser, error)
+
+	// ListSlackUsers returns every mapping ordered by user ID.
+	ListSlackUsers(ctx context.Context) ([]domain.SlackUser, error)
+
+	// DeleteSlackUser removes the mapping of a user.
+	// It returns apperrors.ErrNotFound if the user is not mapped.
+	DeleteSlackUser(ctx context.Context, userID string) error
+}
+
+// WebhookRepository defines the contract for the outbound webhooks and the queue of their deliveries.
+type WebhookRepository interface {
+	// CreateWebhook stores a webhook and returns it with its ID.
+	CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error)
+
+	// GetWebhook retrieves a webhook by its ID. It returns apperrors.ErrNotFound if there is no such webhook.
+	GetWebhook(ctx context.Context, id int64) (*domain.Webhook, error)
+
+	// ListWebhooks returns every webhook ordered by ID.
+	ListWebhooks(ctx context.Context) ([]domain.Webhook, error)
+
+	// UpdateWebhook replaces the URL, events and secret of a webhook and returns it as stored.
+	// It returns apperrors.ErrNotFound if there is no such webhook.
+	UpdateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error)
+
+	// DeleteWebhook removes a webhook together with its deliveries.
+	// It returns apperrors.ErrNotFound if there is no such webhook.
+	DeleteWebhook(ctx context.Context, id int64) error
+
+	// EnqueueWebhookDeliveries queues a pending delivery of event to every webhook subscribed to it and returns
+	// how many were queued. It runs in the transaction of the change the event reports, so that the event
+	// is delivered if and only if the change is committed.
+	EnqueueWebhookDeliveries(ctx context.Context, event *domain.WebhookEvent) (int, error)
+
+	// ClaimWebhookDeliveries claims up to limit pending deliveries due at now, oldest first: their attempts are
+	// counted and they are not due again until leaseUntil. Concurrent callers claim disjoint deliveries.
+	ClaimWebhookDeliveries(ctx context.Context, limit int, now, leaseUntil time.Time) ([]domain.WebhookDelivery, error)
+
+	// FinishWebhookAttempt stores the status, next attempt, error and response status of a claimed delivery.
+	// It returns apperrors.ErrNotFound if there is no such delivery, e.g. because its webhook was deleted.
+	FinishWebhookAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error
+
+	// GetWebhookDelivery retrieves a delivery by its ID. It returns apperrors.ErrNotFound if there is no such delivery.
+	GetWebhookDelivery(ctx context.Context, id int64) (*domain.WebhookDelivery, error)
+
+	// ListWebhookDeliveries returns the deliveries matching filter, newest first.
+	ListWebhookDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error)
+
+	// RequeueWebhookDelivery moves a dead delivery back to pending with its attempts reset, due at dueAt,
+	// and returns it as stored. It returns apperrors.ErrNotFound if there is no such delivery
+	// and apperrors.ErrDeliveryNotDead if the delivery is not dead.
+	RequeueWebhookDelivery(ctx context.Context, id int64, dueAt time.Time) (*domain.WebhookDelivery, error)
+}
+
+// OutboxRepository defines the contract for the transactional outbox of the PR events published to the event sinks.
+type OutboxRepository interface {
+	// AddOutboxEvent writes event to the outbox, due at its OccurredAt. It runs in the transaction of the change
+	// the event reports, so that the event is published if and only if the change is committed.
+	AddOutboxEvent(ctx context.Context, event *domain.OutboxEvent) error
+
+	// ClaimOutboxEvents claims up to limit unpublished events due at now in the order they were written:
+	// their attempts are counted and they are not due again until leaseUntil. Concurrent callers claim disjoint events.
+	ClaimOutboxEvents(ctx context.Context, limit int, now, leaseUntil time.Time) ([]domain.OutboxEvent, error)
+
+	// FinishOutboxAttempt stores the publication time, next attempt and error of a claimed event.
+	// It returns apperrors.ErrNotFound if there is no such event.
+	FinishOutboxAttempt(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// ReadTokenRepository defines the contract for the read-only API tokens.
+type ReadTokenRepository interface {
+	// CreateReadToken stores a token and returns it with its ID.
+	CreateReadToken(ctx context.Context, token *domain.ReadToken) (*domain.ReadToken, error)
+
+	// GetReadTokenByHash retrieves a token by the hash of its value.
+	// It returns apperrors.ErrNotFound if there is no such token.
+	GetReadTokenByHash(ctx context.Context, hash string) (*domain.ReadToken, error)
+
+	// ListReadTokens returns every token in creation order.
+	ListReadTokens(ctx context.Context) ([]domain.ReadToken, error)
+
+	// TouchReadToken records that the token was used at the given time.
+	// It returns apperrors.ErrNotFound if there is no such token.
+	TouchReadToken(ctx context.Context, id int64, usedAt time.Time) error
+
+	// DeleteReadToken removes a token, which stops it being accepted at once.
+	// It returns apperrors.ErrNotFound if there is no such token.
+	DeleteReadToken(ctx context.Context, id int64) error
+}
+
+// APIKeyRepository defines the contract for the API keys stored in the database.
+type APIKeyRepository interface {
+	// CreateAPIKey stores a key and returns it with its ID.
+	// It returns apperrors.ErrNotFound if the key acts for a user that does not exist.
+	CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error)
+
+	// GetAPIKeyByHash retrieves a key by the hash of its value.
+	// It returns apperrors.ErrNotFound if there is no such key.
+	GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error)
+
+	// ListAPIKeys returns every key in creation order.
+	ListAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+
+	// TouchAPIKey records that the key was used at the given time.
+	// It returns apperrors.ErrNotFound if there is no such key.
+	TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error
+
+	// DeleteAPIKey removes a key, which stops it being accepted at once.
+	// It returns apperrors.ErrNotFound if there is no such key.
+	DeleteAPIKey(ctx context.Context, id int64) error
 }
@@ -0,0 +1,257 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/jmoiron/sqlx"
+)
+
+var borrowColumns = []string{
+	"b.id", "b.team_id", "t.name AS team_name", "b.lender_team_id", "lt.name AS lender_team_name",
+	"b.reviewer_count", "b.duration_hours", "b.status", "b.requested_at", "b.accepted_at", "b.expires_at",
+}
+
+func (s *Store) borrowQuery() sq.SelectBuilder {
+	return s.sq.Select(borrowColumns...).
+		From("reviewer_borrows b").
+		Join("teams t ON t.id = b.team_id").
+		Join("teams lt ON lt.id = b.lender_team_id").
+		Where(sq.Eq{"t.deleted_at": nil, "lt.deleted_at": nil})
+}
+
+// teamPool matches the users that are picked as reviewers for a team: its members and the users
+// lent to it by an unexpired borrow. idColumn names the id column of users.
+func teamPool(idColumn string, teamID int) sq.Sqlizer {
+	return sq.Or{
+		memberOf(idColumn, teamID),
+		sq.Expr(idColumn+" IN (SELECT bu.user_id FROM borrowed_reviewers bu JOIN reviewer_borrows b ON b.id = bu.borrow_id"+
+			" WHERE b.team_id = ? AND b.expires_at > ?)", teamID, timestampOrNow(time.Time{})),
+	}
+}
+
+func (s *Store) CreateBorrow(ctx context.Context, borrow *domain.ReviewerBorrow) (*domain.ReviewerBorrow, error) {
+	const op = "internal.repository.sqlite.CreateBorrow"
+
+	ext := txctx.Ext(ctx, s.db)
+
+	query, args, err := s.sq.Insert("reviewer_borrows").
+		Columns("team_id", "lender_team_id", "reviewer_count", "duration_hours", "status", "requested_at").
+		Values(borrow.TeamID, borrow.LenderTeamID, borrow.Count, borrow.DurationHours, domain.BorrowRequested, timestampOrNow(time.Time{})).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var id int64
+	if err := sqlx.GetContext(ctx, ext, &id, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	created, err := s.getBorrow(ctx, ext, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return created, nil
+}
+
+func (s *Store) AcceptBorrow(ctx context.Context, borrowID int64, acceptedAt time.Time) (*domain.ReviewerBorrow, error) {
+	const op = "internal.repository.sqlite.AcceptBorrow"
+	log := s.log.With(slog.String("op", op), slog.Int64("borrow_id", borrowID))
+
+	var borrow *domain.ReviewerBorrow
+
+	err := s.tx.Do(ctx, nil, func(ctx context.Context) error {
+		ext := txctx.Ext(ctx, s.db)
+
+		var err error
+		if borrow, err = s.getBorrow(ctx, ext, borrowID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if borrow.Status != domain.BorrowRequested {
+			return fmt.Errorf("%s: %w: borrow %d is %s", op, apperrors.ErrBorrowNotPending, borrowID, borrow.Status)
+		}
+
+		reviewerIDs, err := s.lendableReviewers(ctx, ext, borrow.LenderTeamID, borrow.Count)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if len(reviewerIDs) == 0 {
+			return fmt.Errorf("%s: %w: team '%s' has no active members to lend", op, apperrors.ErrInsufficientCapacity, borrow.LenderTeamName)
+		}
+
+		expiresAt := acceptedAt.Add(time.Duration(borrow.DurationHours) * time.Hour)
+
+		query, args, err := s.sq.Update("reviewer_borrows").
+			Set("status", domain.BorrowAccepted).
+			Set("accepted_at", timestamp(acceptedAt)).
+			Set("expires_at", timestamp(expiresAt)).
+			Where(sq.Eq{"id": borrowID}).
+			Suffix("RETURNING status, accepted_at, expires_at").
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("%s: failed to build update query: %w", op, err)
+		}
+
+		if err := ext.QueryRowxContext(ctx, query, args...).Scan(&borrow.Status, &borrow.AcceptedAt, &borrow.ExpiresAt); err != nil {
+			return fmt.Errorf("%s: failed to execute update: %w", op, err)
+		}
+
+		insertBuilder := s.sq.Insert("borrowed_reviewers").Columns("borrow_id", "user_id")
+		for _, id := range reviewerIDs {
+			insertBuilder = insertBuilder.Values(borrowID, id)
+		}
+
+		query, args, err = insertBuilder.ToSql()
+		if err != nil {
+			return fmt.Errorf("%s: failed to build insert query: %w", op, err)
+		}
+
+		if _, err := ext.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("%s: failed to execute insert: %w", op, err)
+		}
+
+		slices.Sort(reviewerIDs)
+		borrow.ReviewerIDs = reviewerIDs
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("reviewer borrow accepted", slog.Any("reviewers", borrow.ReviewerIDs))
+
+	return borrow, nil
+}
+
+func (s *Store) ListBorrows(ctx context.Context, teamID int) ([]domain.ReviewerBorrow, error) {
+	const op = "internal.repository.sqlite.ListBorrows"
+
+	ext := txctx.Ext(ctx, s.db)
+
+	query, args, err := s.borrowQuery().
+		Where(sq.Or{sq.Eq{"b.team_id": teamID}, sq.Eq{"b.lender_team_id": teamID}}).
+		Where(sq.Or{sq.Eq{"b.expires_at": nil}, sq.Gt{"b.expires_at": timestampOrNow(time.Time{})}}).
+		OrderBy("b.id DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	borrows := []domain.ReviewerBorrow{}
+	if err := sqlx.SelectContext(ctx, ext, &borrows, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	if err := s.attachReviewers(ctx, ext, borrows); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return borrows, nil
+}
+
+// getBorrow reads a borrow with its lent users.
+func (s *Store) getBorrow(ctx context.Context, ext sqlx.ExtContext, borrowID int64) (*domain.ReviewerBorrow, error) {
+	query, args, err := s.borrowQuery().Where(sq.Eq{"b.id": borrowID}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build borrow query: %w", err)
+	}
+
+	var borrow domain.ReviewerBorrow
+	if err := sqlx.GetContext(ctx, ext, &borrow, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: reviewer borrow %d", apperrors.ErrNotFound, borrowID)
+		}
+
+		return nil, fmt.Errorf("failed to get borrow: %w", err)
+	}
+
+	borrows := []domain.ReviewerBorrow{borrow}
+	if err := s.attachReviewers(ctx, ext, borrows); err != nil {
+		return nil, err
+	}
+
+	return &borrows[0], nil
+}
+
+// attachReviewers fills in the lent users of the borrows.
+func (s *Store) attachReviewers(ctx context.Context, ext sqlx.ExtContext, borrows []domain.ReviewerBorrow) error {
+	if len(borrows) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(borrows))
+	for i, borrow := range borrows {
+		ids[i] = borrow.ID
+	}
+
+	query, args, err := s.sq.Select("borrow_id", "user_id").
+		From("borrowed_reviewers").
+		Where(sq.Eq{"borrow_id": ids}).
+		OrderBy("user_id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build lent users query: %w", err)
+	}
+
+	var rows []struct {
+		BorrowID int64  `db:"borrow_id"`
+		UserID   string `db:"user_id"`
+	}
+	if err := sqlx.SelectContext(ctx, ext, &rows, query, args...); err != nil {
+		return fmt.Errorf("failed to get lent users: %w", err)
+	}
+
+	byBorrow := make(map[int64][]string, len(borrows))
+	for _, row := range rows {
+		byBorrow[row.BorrowID] = append(byBorrow[row.BorrowID], row.UserID)
+	}
+
+	for i := range borrows {
+		borrows[i].ReviewerIDs = byBorrow[borrows[i].ID]
+		if borrows[i].ReviewerIDs == nil {
+			borrows[i].ReviewerIDs = []string{}
+		}
+	}
+
+	return nil
+}
+
+// lendableReviewers picks up to count active members of the team, those with the fewest open reviews first.
+// Users the team has borrowed itself are not lent on.
+func (s *Store) lendableReviewers(ctx context.Context, ext sqlx.ExtContext, teamID int, count int) ([]string, error) {
+	query, args, err := s.sq.Select("u.id").
+		From("users u").
+		LeftJoin("reviewers r ON r.user_id = u.id").
+		LeftJoin("pull_requests pr ON pr.id = r.pull_request_id AND pr.status = 'OPEN'").
+		Where(memberOf("u.id", teamID)).
+		Where(sq.Eq{"u.is_active": true, "u.deleted_at": nil}).
+		GroupBy("u.id").
+		OrderBy("COUNT(pr.id)", "u.id").
+		Limit(uint64(count)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build lendable users query: %w", err)
+	}
+
+	reviewerIDs := []string{}
+	if err := sqlx.SelectContext(ctx, ext, &reviewerIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get lendable users: %w", err)
+	}
+
+	return reviewerIDs, nil
+}
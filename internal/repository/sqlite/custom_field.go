@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/jmoiron/sqlx"
+)
+
+var customFieldColumns = []string{"team_id", "key", "type", "required"}
+
+func (s *Store) GetCustomFields(ctx context.Context, teamID int) ([]domain.CustomField, error) {
+	const op = "internal.repository.sqlite.GetCustomFields"
+
+	return s.selectCustomFields(ctx, txctx.Ext(ctx, s.db), op, teamID)
+}
+
+func (s *Store) ReplaceCustomFields(ctx context.Context, teamID int, fields []domain.CustomField) ([]domain.CustomField, error) {
+	const op = "internal.repository.sqlite.ReplaceCustomFields"
+
+	var saved []domain.CustomField
+
+	err := s.tx.Do(ctx, nil, func(ctx context.Context) error {
+		ext := txctx.Ext(ctx, s.db)
+
+		query, args, err := s.sq.Select("id").
+			From("teams").
+			Where(sq.Eq{"id": teamID, "deleted_at": nil}).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("%s: failed to build team query: %w", op, err)
+		}
+
+		var id int
+		if err := sqlx.GetContext(ctx, ext, &id, query, args...); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%s: %w: team with id '%d'", op, apperrors.ErrNotFound, teamID)
+			}
+
+			return fmt.Errorf("%s: failed to get team: %w", op, err)
+		}
+
+		query, args, err = s.sq.Delete("team_custom_fields").
+			Where(sq.Eq{"team_id": teamID}).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("%s: failed to build delete query: %w", op, err)
+		}
+
+		if _, err := ext.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("%s: failed to execute delete: %w", op, err)
+		}
+
+		if len(fields) > 0 {
+			insertBuilder := s.sq.Insert("team_custom_fields").Columns(customFieldColumns...)
+			for _, field := range fields {
+				insertBuilder = insertBuilder.Values(teamID, field.Key, field.Type, field.Required)
+			}
+
+			query, args, err := insertBuilder.ToSql()
+			if err != nil {
+				return fmt.Errorf("%s: failed to build insert query: %w", op, err)
+			}
+
+			if _, err := ext.ExecContext(ctx, query, args...); err != nil {
+				return fmt.Errorf("%s: failed to execute insert: %w", op, err)
+			}
+		}
+
+		saved, err = s.selectCustomFields(ctx, ext, op, teamID)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+func (s *Store) selectCustomFields(ctx context.Context, ext sqlx.ExtContext, op string, teamID int) ([]domain.CustomField, error) {
+	query, args, err := s.sq.Select(customFieldColumns...).
+		From("team_custom_fields").
+		Where(sq.Eq{"team_id": teamID}).
+		OrderBy("key").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	fields := []domain.CustomField{}
+	if err := sqlx.SelectContext(ctx, ext, &fields, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return fields, nil
+}
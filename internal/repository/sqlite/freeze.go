@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/jmoiron/sqlx"
+)
+
+func (s *Store) freezeQuery() sq.SelectBuilder {
+	return s.sq.Select(
+		"fw.id", "fw.team_id", "t.name AS team_name", "fw.reason", "fw.starts_at", "fw.ends_at", "fw.created_at",
+	).
+		From("freeze_windows fw").
+		LeftJoin("teams t ON t.id = fw.team_id").
+		Where(sq.Or{sq.Eq{"fw.team_id": nil}, sq.Eq{"t.deleted_at": nil}})
+}
+
+func (s *Store) CreateFreezeWindow(ctx context.Context, window *domain.FreezeWindow) (*domain.FreezeWindow, error) {
+	const op = "internal.repository.sqlite.CreateFreezeWindow"
+
+	ext := txctx.Ext(ctx, s.db)
+
+	query, args, err := s.sq.Insert("freeze_windows").
+		Columns("team_id", "reason", "starts_at", "ends_at", "created_at").
+		Values(window.TeamID, window.Reason, timestamp(window.StartsAt), timestamp(window.EndsAt), timestampOrNow(window.CreatedAt)).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var id int64
+	if err := sqlx.GetContext(ctx, ext, &id, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	query, args, err = s.freezeQuery().Where(sq.Eq{"fw.id": id}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var created domain.FreezeWindow
+	if err := sqlx.GetContext(ctx, ext, &created, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to get created freeze window: %w", op, err)
+	}
+
+	return &created, nil
+}
+
+func (s *Store) ListFreezeWindows(ctx context.Context, filter domain.FreezeWindowFilter) ([]domain.FreezeWindow, error) {
+	const op = "internal.repository.sqlite.ListFreezeWindows"
+
+	builder := s.freezeQuery().OrderBy("fw.starts_at", "fw.id")
+
+	if filter.TeamID != nil {
+		builder = builder.Where(sq.Or{sq.Eq{"fw.team_id": nil}, sq.Eq{"fw.team_id": *filter.TeamID}})
+	}
+
+	if filter.ActiveAt != nil {
+		activeAt := timestamp(*filter.ActiveAt)
+		builder = builder.Where(sq.LtOrEq{"fw.starts_at": activeAt}).Where(sq.Gt{"fw.ends_at": activeAt})
+	}
+
+	if filter.EndsAfter != nil {
+		builder = builder.Where(sq.Gt{"fw.ends_at": timestamp(*filter.EndsAfter)})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	windows := []domain.FreezeWindow{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &windows, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to list freeze windows: %w", op, err)
+	}
+
+	return windows, nil
+}
+
+func (s *Store) DeleteFreezeWindow(ctx context.Context, id int64) error {
+	const op = "internal.repository.sqlite.DeleteFreezeWindow"
+
+	query, args, err := s.sq.Delete("freeze_windows").
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build delete query: %w", op, err)
+	}
+
+	var deleted int64
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &deleted, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w: freeze window %d", op, apperrors.ErrNotFound, id)
+		}
+
+		return fmt.Errorf("%s: failed to execute delete: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/jmoiron/sqlx"
+)
+
+func (s *Store) SetGitLabUser(ctx context.Context, mapping *domain.GitLabUser) (*domain.GitLabUser, error) {
+	const op = "internal.repository.sqlite.SetGitLabUser"
+
+	query, args, err := s.sq.Insert("gitlab_users").
+		Columns("gitlab_username", "user_id", "created_at").
+		Values(mapping.GitLabUsername, mapping.UserID, timestampOrNow(mapping.CreatedAt)).
+		Suffix(`ON CONFLICT (gitlab_username) DO UPDATE SET user_id = excluded.user_id, created_at = excluded.created_at
+			RETURNING gitlab_username, user_id, created_at`).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var stored domain.GitLabUser
+	if err := txctx.Ext(ctx, s.db).QueryRowxContext(ctx, query, args...).StructScan(&stored); err != nil {
+		if isConstraintError(err, codeForeignKey) {
+			return nil, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, mapping.UserID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return &stored, nil
+}
+
+func (s *Store) GetGitLabUser(ctx context.Context, gitLabUsername string) (*domain.GitLabUser, error) {
+	const op = "internal.repository.sqlite.GetGitLabUser"
+
+	query, args, err := s.sq.Select("gitlab_username", "user_id", "created_at").
+		From("gitlab_users").
+		Where(sq.Eq{"gitlab_username": gitLabUsername}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var mapping domain.GitLabUser
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &mapping, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: gitlab user '%s'", op, apperrors.ErrNotFound, gitLabUsername)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &mapping, nil
+}
+
+func (s *Store) ListGitLabUsers(ctx context.Context) ([]domain.GitLabUser, error) {
+	const op = "internal.repository.sqlite.ListGitLabUsers"
+
+	query, args, err := s.sq.Select("gitlab_username", "user_id", "created_at").
+		From("gitlab_users").
+		OrderBy("gitlab_username").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	mappings := []domain.GitLabUser{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &mappings, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to list gitlab users: %w", op, err)
+	}
+
+	return mappings, nil
+}
+
+func (s *Store) DeleteGitLabUser(ctx context.Context, gitLabUsername string) error {
+	const op = "internal.repository.sqlite.DeleteGitLabUser"
+
+	query, args, err := s.sq.Delete("gitlab_users").
+		Where(sq.Eq{"gitlab_username": gitLabUsername}).
+		Suffix("RETURNING gitlab_username").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build delete query: %w", op, err)
+	}
+
+	var deleted string
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &deleted, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w: gitlab user '%s'", op, apperrors.ErrNotFound, gitLabUsername)
+		}
+
+		return fmt.Errorf("%s: failed to execute delete: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/jmoiron/sqlx"
+)
+
+var historyColumns = []string{
+	"id", "pull_request_id", "user_id", "replaced_user_id", "strategy", "reason",
+	"COALESCE(cause, '') AS cause", "handoff_note", "created_at",
+}
+
+func (s *Store) RecordAssignments(ctx context.Context, records []domain.AssignmentRecord) error {
+	const op = "internal.repository.sqlite.RecordAssignments"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	insertBuilder := s.sq.Insert("assignment_history").
+		Columns("pull_request_id", "user_id", "replaced_user_id", "strategy", "reason", "cause", "handoff_note", "created_at")
+
+	for _, record := range records {
+		insertBuilder = insertBuilder.Values(record.PullRequestID, record.UserID, record.ReplacedUserID, record.Strategy, record.Reason, record.Cause, record.HandoffNote, timestampOrNow(record.CreatedAt))
+	}
+
+	query, args, err := insertBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Store) GetCurrentAssignments(ctx context.Context, prID string) ([]domain.AssignmentRecord, error) {
+	const op = "internal.repository.sqlite.GetCurrentAssignments"
+
+	// The latest record of each reviewer, which SQLite has no DISTINCT ON for.
+	latest := sq.Select("MAX(id)").
+		From("assignment_history").
+		Where(sq.Eq{"pull_request_id": prID}).
+		GroupBy("user_id")
+
+	query, args, err := s.sq.Select(
+		"h.id", "h.pull_request_id", "h.user_id", "h.replaced_user_id",
+		"h.strategy", "h.reason", "h.handoff_note", "h.created_at",
+	).
+		From("assignment_history h").
+		Join("reviewers r ON r.pull_request_id = h.pull_request_id AND r.user_id = h.user_id").
+		Where(sq.Expr("h.id IN (?)", latest)).
+		OrderBy("h.user_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	records := []domain.AssignmentRecord{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &records, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return records, nil
+}
+
+func (s *Store) ListAssignmentHistory(ctx context.Context, prID string) ([]domain.AssignmentRecord, error) {
+	const op = "internal.repository.sqlite.ListAssignmentHistory"
+
+	query, args, err := s.sq.Select(historyColumns...).
+		From("assignment_history").
+		Where(sq.Eq{"pull_request_id": prID}).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	records := []domain.AssignmentRecord{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &records, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return records, nil
+}
+
+func (s *Store) ListReplacementsOf(ctx context.Context, replacedUserIDs []string, cause domain.AssignmentCause, since time.Time) ([]domain.AssignmentRecord, error) {
+	const op = "internal.repository.sqlite.ListReplacementsOf"
+
+	records := []domain.AssignmentRecord{}
+	if len(replacedUserIDs) == 0 {
+		return records, nil
+	}
+
+	query, args, err := s.sq.Select(historyColumns...).
+		From("assignment_history").
+		Where(sq.Eq{"replaced_user_id": replacedUserIDs, "cause": string(cause)}).
+		Where(sq.GtOrEq{"created_at": timestamp(since)}).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &records, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,147 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/jmoiron/sqlx"
+)
+
+var deliveryColumns = []string{
+	"id", "channel", "recipient_id", "event", "pull_request_id", "status", "error", "attempts", "payload",
+	"created_at", "updated_at",
+}
+
+var deliveryReturning = "RETURNING " + strings.Join(deliveryColumns, ", ")
+
+func (s *Store) CreateDelivery(ctx context.Context, d *domain.NotificationDelivery) (*domain.NotificationDelivery, error) {
+	const op = "internal.repository.sqlite.CreateDelivery"
+
+	createdAt := timestampOrNow(d.CreatedAt)
+
+	query, args, err := s.sq.Insert("notification_deliveries").
+		Columns("channel", "recipient_id", "event", "pull_request_id", "status", "error", "payload", "created_at", "updated_at").
+		Values(d.Channel, d.RecipientID, d.Event, d.PullRequestID, d.Status, d.Error, string(d.Payload), createdAt, createdAt).
+		Suffix(deliveryReturning).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var created domain.NotificationDelivery
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &created, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return &created, nil
+}
+
+func (s *Store) GetDelivery(ctx context.Context, id int64) (*domain.NotificationDelivery, error) {
+	const op = "internal.repository.sqlite.GetDelivery"
+
+	query, args, err := s.sq.Select(deliveryColumns...).
+		From("notification_deliveries").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var d domain.NotificationDelivery
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &d, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: notification delivery %d", op, apperrors.ErrNotFound, id)
+		}
+
+		return nil, fmt.Errorf("%s: failed to get notification delivery: %w", op, err)
+	}
+
+	return &d, nil
+}
+
+func (s *Store) ListDeliveries(ctx context.Context, filter domain.DeliveryFilter) ([]domain.NotificationDelivery, error) {
+	const op = "internal.repository.sqlite.ListDeliveries"
+
+	builder := s.sq.Select(deliveryColumns...).
+		From("notification_deliveries").
+		OrderBy("id DESC").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset))
+
+	if filter.Channel != "" {
+		builder = builder.Where(sq.Eq{"channel": filter.Channel})
+	}
+
+	if filter.RecipientID != "" {
+		builder = builder.Where(sq.Eq{"recipient_id": filter.RecipientID})
+	}
+
+	if filter.PullRequestID != "" {
+		builder = builder.Where(sq.Eq{"pull_request_id": filter.PullRequestID})
+	}
+
+	if filter.Event != "" {
+		builder = builder.Where(sq.Eq{"event": filter.Event})
+	}
+
+	if filter.Status != "" {
+		builder = builder.Where(sq.Eq{"status": filter.Status})
+	}
+
+	if filter.AfterID != 0 {
+		builder = builder.Where(sq.Lt{"id": filter.AfterID})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	deliveries := []domain.NotificationDelivery{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &deliveries, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to list notification deliveries: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+func (s *Store) RecordDeliveryAttempt(
+	ctx context.Context,
+	id int64,
+	status domain.DeliveryStatus,
+	deliveryErr *string,
+	attemptedAt time.Time,
+) (*domain.NotificationDelivery, error) {
+	const op = "internal.repository.sqlite.RecordDeliveryAttempt"
+
+	query, args, err := s.sq.Update("notification_deliveries").
+		Set("status", status).
+		Set("error", deliveryErr).
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("updated_at", timestampOrNow(attemptedAt)).
+		Where(sq.Eq{"id": id}).
+		Suffix(deliveryReturning).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	var d domain.NotificationDelivery
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &d, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: notification delivery %d", op, apperrors.ErrNotFound, id)
+		}
+
+		return nil, fmt.Errorf("%s: failed to update notification delivery: %w", op, err)
+	}
+
+	return &d, nil
+}
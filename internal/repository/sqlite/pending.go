@@ -0,0 +1,170 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/jmoiron/sqlx"
+)
+
+var pendingColumns = []string{
+	"pa.pull_request_id", "pr.name AS pull_request_name", "pr.author_id",
+	"pa.team_id", "t.name AS team_name", "pa.priority", "pa.enqueued_at", "pr.assign_at",
+}
+
+func (s *Store) pendingQuery() sq.SelectBuilder {
+	return s.sq.Select(pendingColumns...).
+		From("pending_assignments pa").
+		Join("pull_requests pr ON pr.id = pa.pull_request_id").
+		Join("teams t ON t.id = pa.team_id")
+}
+
+func (s *Store) EnqueuePending(ctx context.Context, prID string, teamID int, priority int) error {
+	const op = "internal.repository.sqlite.EnqueuePending"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := s.sq.Insert("pending_assignments").
+		Columns("pull_request_id", "team_id", "priority", "enqueued_at").
+		Values(prID, teamID, priority, timestampOrNow(time.Time{})).
+		Suffix("ON CONFLICT (pull_request_id) DO UPDATE SET priority = excluded.priority").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Store) DequeuePending(ctx context.Context, prID string) error {
+	const op = "internal.repository.sqlite.DequeuePending"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := s.sq.Delete("pending_assignments").
+		Where(sq.Eq{"pull_request_id": prID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build delete query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute delete: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Store) GetPendingWithLock(ctx context.Context, prID string) (*domain.PendingAssignment, error) {
+	const op = "internal.repository.sqlite.GetPendingWithLock"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := s.pendingQuery().
+		Where(sq.Eq{"pa.pull_request_id": prID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var entry domain.PendingAssignment
+	if err := tx.GetContext(ctx, &entry, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: pending assignment of PR '%s'", op, apperrors.ErrNotFound, prID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to get pending assignment: %w", op, err)
+	}
+
+	return &entry, nil
+}
+
+func (s *Store) ListPending(ctx context.Context, teamName string, limit int) ([]domain.PendingAssignment, error) {
+	const op = "internal.repository.sqlite.ListPending"
+
+	builder := s.pendingQuery().
+		Where(sq.Eq{"t.deleted_at": nil}).
+		OrderBy("pa.priority DESC", "pa.enqueued_at", "pa.pull_request_id").
+		Limit(uint64(limit))
+
+	if teamName != "" {
+		builder = builder.Where(sq.Eq{"t.name": teamName})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	entries := []domain.PendingAssignment{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &entries, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+func (s *Store) ListDuePending(ctx context.Context, now time.Time, limit int) ([]domain.PendingAssignment, error) {
+	const op = "internal.repository.sqlite.ListDuePending"
+
+	query, args, err := s.pendingQuery().
+		Where(sq.Or{sq.Eq{"pr.assign_at": nil}, sq.LtOrEq{"pr.assign_at": timestamp(now)}}).
+		Where(sq.Eq{"t.deleted_at": nil}).
+		OrderBy("pa.priority DESC", "pa.enqueued_at", "pa.pull_request_id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	entries := []domain.PendingAssignment{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &entries, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+func (s *Store) ListUnqueuedNeedingReviewers(ctx context.Context, afterID string, limit int) ([]string, error) {
+	const op = "internal.repository.sqlite.ListUnqueuedNeedingReviewers"
+
+	query, args, err := s.sq.Select("pr.id").
+		From("pull_requests pr").
+		LeftJoin("pending_assignments pa ON pa.pull_request_id = pr.id").
+		Where(sq.Eq{"pr.status": api.PullRequestStatusOPEN, "pr.need_more_reviewers": true, "pa.pull_request_id": nil}).
+		Where(sq.Gt{"pr.id": afterID}).
+		OrderBy("pr.id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	ids := []string{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &ids, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return ids, nil
+}
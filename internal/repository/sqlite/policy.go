@@ -0,0 +1,130 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/jmoiron/sqlx"
+)
+
+var policyColumns = []string{
+	"team_id", "strategy_weights", "author_open_pr_limit", "over_quota_action", "reactivation_rebalance", "updated_at",
+}
+
+type teamPolicyRow struct {
+	TeamID                int                  `db:"team_id"`
+	StrategyWeights       []byte               `db:"strategy_weights"`
+	AuthorOpenPRLimit     *int                 `db:"author_open_pr_limit"`
+	OverQuotaAction       domain.QuotaAction   `db:"over_quota_action"`
+	ReactivationRebalance domain.RebalanceMode `db:"reactivation_rebalance"`
+	UpdatedAt             time.Time            `db:"updated_at"`
+}
+
+func (row *teamPolicyRow) toDomain() (*domain.TeamPolicy, error) {
+	weights := make(map[domain.AssignmentStrategy]int)
+	if err := json.Unmarshal(row.StrategyWeights, &weights); err != nil {
+		return nil, fmt.Errorf("failed to decode strategy weights: %w", err)
+	}
+
+	return &domain.TeamPolicy{
+		TeamID:                row.TeamID,
+		StrategyWeights:       weights,
+		AuthorOpenPRLimit:     row.AuthorOpenPRLimit,
+		OverQuotaAction:       row.OverQuotaAction,
+		ReactivationRebalance: row.ReactivationRebalance,
+		UpdatedAt:             row.UpdatedAt,
+	}, nil
+}
+
+func (s *Store) GetTeamPolicy(ctx context.Context, teamID int) (*domain.TeamPolicy, error) {
+	const op = "internal.repository.sqlite.GetTeamPolicy"
+
+	query, args, err := s.sq.Select(policyColumns...).
+		From("team_policies").
+		Where(sq.Eq{"team_id": teamID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var row teamPolicyRow
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &domain.TeamPolicy{
+				TeamID:                teamID,
+				StrategyWeights:       map[domain.AssignmentStrategy]int{},
+				OverQuotaAction:       domain.QuotaReject,
+				ReactivationRebalance: domain.RebalanceOff,
+			}, nil
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	policy, err := row.toDomain()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return policy, nil
+}
+
+func (s *Store) UpsertTeamPolicy(ctx context.Context, policy *domain.TeamPolicy) (*domain.TeamPolicy, error) {
+	const op = "internal.repository.sqlite.UpsertTeamPolicy"
+
+	weights, err := json.Marshal(policy.StrategyWeights)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to encode strategy weights: %w", op, err)
+	}
+
+	action := policy.OverQuotaAction
+	if action == "" {
+		action = domain.QuotaReject
+	}
+
+	rebalance := policy.ReactivationRebalance
+	if rebalance == "" {
+		rebalance = domain.RebalanceOff
+	}
+
+	query, args, err := s.sq.Insert("team_policies").
+		Columns(policyColumns...).
+		Values(policy.TeamID, string(weights), policy.AuthorOpenPRLimit, action, rebalance, timestampOrNow(time.Time{})).
+		Suffix(`
+        ON CONFLICT (team_id) DO UPDATE SET
+            strategy_weights = excluded.strategy_weights,
+            author_open_pr_limit = excluded.author_open_pr_limit,
+            over_quota_action = excluded.over_quota_action,
+            reactivation_rebalance = excluded.reactivation_rebalance,
+            updated_at = excluded.updated_at
+        RETURNING ` + strings.Join(policyColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build upsert query: %w", op, err)
+	}
+
+	var row teamPolicyRow
+	if err := txctx.Ext(ctx, s.db).QueryRowxContext(ctx, query, args...).StructScan(&row); err != nil {
+		if isConstraintError(err, codeForeignKey) {
+			return nil, fmt.Errorf("%s: %w: team with id '%d'", op, apperrors.ErrNotFound, policy.TeamID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute upsert: %w", op, err)
+	}
+
+	saved, err := row.toDomain()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return saved, nil
+}
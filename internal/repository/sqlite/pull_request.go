@@ -0,0 +1,1233 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+	"github.com/jmoiron/sqlx"
+	modernc "modernc.org/sqlite"
+)
+
+func (s *Store) GetAuthorTeamID(ctx context.Context, authorID string) (int, error) {
+	const op = "internal.repository.sqlite.GetAuthorTeamID"
+
+	query, args, err := s.sq.Select("team_id").
+		From("users").
+		Where(sq.Eq{"id": authorID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var teamID int
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &teamID, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, authorID)
+		}
+
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return teamID, nil
+}
+
+func (s *Store) IsUserActive(ctx context.Context, userID string) (bool, error) {
+	const op = "internal.repository.sqlite.IsUserActive"
+
+	query, args, err := s.sq.Select("is_active").
+		From("users").
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var isActive bool
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &isActive, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+		}
+
+		return false, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return isActive, nil
+}
+
+// selectReviewers runs a query of the reviewer pickers, which select the candidate IDs.
+func (s *Store) selectReviewers(ctx context.Context, op string, builder sq.SelectBuilder, count int) ([]string, error) {
+	query, args, err := builder.Limit(uint64(count)).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	candidateIDs := []string{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &candidateIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return candidateIDs, nil
+}
+
+func (s *Store) GetRandomActiveReviewers(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error) {
+	const op = "internal.repository.sqlite.GetRandomActiveReviewers"
+
+	builder := s.sq.Select("id").
+		From("users").
+		Where(sq.Eq{"is_active": true, "deleted_at": nil}).
+		Where(teamPool("id", teamID))
+
+	if len(excludeUserIDs) > 0 {
+		builder = builder.Where(sq.NotEq{"id": excludeUserIDs})
+	}
+
+	return s.selectReviewers(ctx, op, builder.OrderBy("random()"), count)
+}
+
+func (s *Store) GetLeastLoadedActiveReviewers(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error) {
+	const op = "internal.repository.sqlite.GetLeastLoadedActiveReviewers"
+
+	builder := s.sq.Select("u.id").
+		From("users u").
+		LeftJoin("reviewers r ON r.user_id = u.id").
+		LeftJoin("pull_requests pr ON pr.id = r.pull_request_id AND pr.status = 'OPEN'").
+		Where(sq.Eq{"u.is_active": true, "u.deleted_at": nil}).
+		Where(teamPool("u.id", teamID))
+
+	if len(excludeUserIDs) > 0 {
+		builder = builder.Where(sq.NotEq{"u.id": excludeUserIDs})
+	}
+
+	return s.selectReviewers(ctx, op, builder.GroupBy("u.id").OrderBy("COUNT(pr.id)", "random()"), count)
+}
+
+func (s *Store) GetLeastRecentlyAssignedActiveReviewers(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error) {
+	const op = "internal.repository.sqlite.GetLeastRecentlyAssignedActiveReviewers"
+
+	builder := s.sq.Select("u.id").
+		From("users u").
+		LeftJoin("assignment_history h ON h.user_id = u.id").
+		Where(sq.Eq{"u.is_active": true, "u.deleted_at": nil}).
+		Where(teamPool("u.id", teamID))
+
+	if len(excludeUserIDs) > 0 {
+		builder = builder.Where(sq.NotEq{"u.id": excludeUserIDs})
+	}
+
+	return s.selectReviewers(ctx, op, builder.GroupBy("u.id").OrderBy("MAX(h.created_at) NULLS FIRST", "u.id"), count)
+}
+
+func (s *Store) GetReplacementAlternatives(ctx context.Context, teamID int, excludeUserIDs []string, limit int) ([]domain.ReplacementAlternative, error) {
+	const op = "internal.repository.sqlite.GetReplacementAlternatives"
+
+	// An alternative is reported with the primary team of the user.
+	const isMember = "u.id IN (SELECT user_id FROM user_teams WHERE team_id = ?)"
+
+	ranked := s.sq.Select("u.id AS user_id", "u.username", "u.team_id", "t.name AS team_name").
+		Column(sq.Expr("CASE WHEN "+isMember+" THEN ? ELSE ? END AS reason", teamID, domain.AlternativeInactive, domain.AlternativeOtherTeam)).
+		Column("COUNT(pr.id) AS open_reviews").
+		Column(sq.Expr("ROW_NUMBER() OVER (PARTITION BY "+isMember+" ORDER BY COUNT(pr.id), u.id) AS rank", teamID)).
+		From("users u").
+		Join("teams t ON t.id = u.team_id").
+		LeftJoin("reviewers r ON r.user_id = u.id").
+		LeftJoin("pull_requests pr ON pr.id = r.pull_request_id AND pr.status = 'OPEN'").
+		Where(sq.Eq{"u.deleted_at": nil, "t.deleted_at": nil}).
+		Where(sq.Or{
+			sq.And{sq.Expr(isMember, teamID), sq.Eq{"u.is_active": false}},
+			sq.And{sq.Expr("NOT "+isMember, teamID), sq.Eq{"u.is_active": true}},
+		}).
+		GroupBy("u.id", "u.username", "u.team_id", "t.name")
+
+	if len(excludeUserIDs) > 0 {
+		ranked = ranked.Where(sq.NotEq{"u.id": excludeUserIDs})
+	}
+
+	query, args, err := s.sq.Select("user_id", "username", "team_id", "team_name", "reason", "open_reviews").
+		FromSelect(ranked, "ranked").
+		Where(sq.LtOrEq{"rank": limit}).
+		OrderBy("reason", "open_reviews", "user_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	alternatives := []domain.ReplacementAlternative{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &alternatives, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return alternatives, nil
+}
+
+// prColumns lists the pull_requests columns that map onto domain.PullRequest.
+var prColumns = []string{
+	"id", "name", "author_id", "status", "description", "external_url", "custom_fields", "assignment_policy", "assign_at",
+	"need_more_reviewers", "created_at", "merged_at",
+}
+
+// qualified prefixes the columns with the alias of their table.
+func qualified(alias string, columns []string) []string {
+	result := make([]string, len(columns))
+	for i, column := range columns {
+		result[i] = alias + "." + column
+	}
+
+	return result
+}
+
+func (s *Store) CreatePR(ctx context.Context, pr *domain.PullRequest) error {
+	const op = "internal.repository.sqlite.CreatePR"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var assignAt *time.Time
+	if pr.AssignAt != nil {
+		utc := timestamp(*pr.AssignAt)
+		assignAt = &utc
+	}
+
+	query, args, err := s.sq.Insert("pull_requests").
+		Columns("id", "name", "author_id", "status", "description", "external_url", "custom_fields", "assignment_policy",
+			"assign_at", "need_more_reviewers", "created_at").
+		Values(pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.Description, pr.ExternalURL, jsonObjectOrEmpty(pr.CustomFields),
+			jsonOrNull(pr.AssignmentPolicy), assignAt, pr.NeedMoreReviewers, timestampOrNow(pr.CreatedAt)).
+		Suffix("ON CONFLICT (id) DO NOTHING RETURNING created_at").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	// A duplicate returns no row. The stored created_at is read back, so that the caller reports it in UTC.
+	if err := tx.QueryRowxContext(ctx, query, args...).Scan(&pr.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &apperrors.PRAlreadyExistsError{PRID: pr.ID}
+		}
+
+		if isConstraintError(err, codeForeignKey) {
+			return fmt.Errorf("%s: %w: author with id '%s' not found", op, apperrors.ErrNotFound, pr.AuthorID)
+		}
+
+		return fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Store) AssignReviewers(ctx context.Context, prID string, reviewerIDs []string) error {
+	const op = "internal.repository.sqlite.AssignReviewers"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := timestampOrNow(time.Time{})
+	insertBuilder := s.sq.Insert("reviewers").
+		Columns("pull_request_id", "user_id", "assigned_at")
+
+	for _, userID := range reviewerIDs {
+		insertBuilder = insertBuilder.Values(prID, userID, now)
+	}
+
+	query, args, err := insertBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		if assignmentErr := reviewerConstraintError(err, prID); assignmentErr != nil {
+			return fmt.Errorf("%s: %w", op, assignmentErr)
+		}
+
+		return fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return nil
+}
+
+// reviewerConstraintError translates a violation of the constraints of the reviewers table
+// into an InvalidAssignmentError, or returns nil if err is not one.
+func reviewerConstraintError(err error, prID string) error {
+	var sqliteErr *modernc.Error
+	if !errors.As(err, &sqliteErr) {
+		return nil
+	}
+
+	switch {
+	case sqliteErr.Code() == codePrimaryKey:
+		return &apperrors.InvalidAssignmentError{PRID: prID, Reason: "reviewer is already assigned"}
+	case sqliteErr.Code() == codeTrigger && strings.Contains(sqliteErr.Error(), "reviewers_not_author"):
+		return &apperrors.InvalidAssignmentError{PRID: prID, Reason: "author cannot review their own pull request"}
+	default:
+		return nil
+	}
+}
+
+func (s *Store) GetReviewerIDs(ctx context.Context, prID string) ([]string, error) {
+	const op = "internal.repository.sqlite.GetReviewerIDs"
+
+	query, args, err := s.sq.Select("user_id").
+		From("reviewers").
+		Where(sq.Eq{"pull_request_id": prID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var reviewerIDs []string
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &reviewerIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to select reviewers: %w", op, err)
+	}
+
+	return reviewerIDs, nil
+}
+
+func (s *Store) GetReviews(ctx context.Context, prID string) ([]domain.Review, error) {
+	const op = "internal.repository.sqlite.GetReviews"
+
+	query, args, err := s.sq.Select("user_id", "review_state", "reviewed_at").
+		From("reviewers").
+		Where(sq.Eq{"pull_request_id": prID}).
+		OrderBy("user_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	reviews := []domain.Review{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &reviews, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to select reviews: %w", op, err)
+	}
+
+	return reviews, nil
+}
+
+func (s *Store) GetPRByIDWithReviewers(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	const op = "internal.repository.sqlite.GetPRByIDWithReviewers"
+
+	pr, err := s.GetPRByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewerIDs, err := s.GetReviewerIDs(ctx, prID)
+	if err != nil {
+		s.log.Error("failed to get reviewers for PR", sl.Err(err), slog.String("pr_id", prID))
+		return nil, fmt.Errorf("%s: failed to get reviewers: %w", op, err)
+	}
+
+	reviews, err := s.GetReviews(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pr.ReviewerIDs = reviewerIDs
+	pr.Reviews = reviews
+
+	return pr, nil
+}
+
+// GetPRByIDWithLock is GetPRByID in a transaction, whose write lock keeps the pull request from changing.
+func (s *Store) GetPRByIDWithLock(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	const op = "internal.repository.sqlite.GetPRByIDWithLock"
+
+	if _, err := txctx.Required(ctx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.GetPRByID(ctx, prID)
+}
+
+func (s *Store) GetPRByID(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	const op = "internal.repository.sqlite.GetPRByID"
+
+	query, args, err := s.sq.Select(prColumns...).
+		From("pull_requests").
+		Where(sq.Eq{"id": prID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var pr domain.PullRequest
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &pr, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: PR with id '%s'", op, apperrors.ErrNotFound, prID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to get PR: %w", op, err)
+	}
+
+	return &pr, nil
+}
+
+func (s *Store) UpdatePRStatus(ctx context.Context, prID string, status api.PullRequestStatus, mergedAt time.Time) (*time.Time, error) {
+	const op = "internal.repository.sqlite.UpdatePRStatus"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	updateBuilder := s.sq.Update("pull_requests").
+		Set("status", status).
+		Where(sq.Eq{"id": prID}).
+		Suffix("RETURNING merged_at")
+
+	switch status {
+	case api.PullRequestStatusMERGED:
+		updateBuilder = updateBuilder.Set("merged_at", timestamp(mergedAt)).Set("need_more_reviewers", false)
+	case api.PullRequestStatusCLOSED:
+		updateBuilder = updateBuilder.Set("need_more_reviewers", false)
+	}
+
+	query, args, err := updateBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	var storedMergedAt *time.Time
+	if err := tx.QueryRowxContext(ctx, query, args...).Scan(&storedMergedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: PR with id '%s'", op, apperrors.ErrNotFound, prID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	return storedMergedAt, nil
+}
+
+func (s *Store) GetUserTeamIDs(ctx context.Context, userID string) ([]int, error) {
+	const op = "internal.repository.sqlite.GetUserTeamIDs"
+
+	query, args, err := s.sq.Select("ut.team_id").
+		From("users u").
+		Join("user_teams ut ON ut.user_id = u.id").
+		Join("teams t ON t.id = ut.team_id").
+		Where(sq.Eq{"u.id": userID, "u.deleted_at": nil, "t.deleted_at": nil}).
+		OrderBy("ut.team_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	teamIDs := []int{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &teamIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	// A user always belongs to a team, so a user without one is deleted or does not exist.
+	if len(teamIDs) == 0 {
+		return nil, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+	}
+
+	return teamIDs, nil
+}
+
+func (s *Store) GetPRTeamID(ctx context.Context, prID string) (int, error) {
+	const op = "internal.repository.sqlite.GetPRTeamID"
+
+	query, args, err := s.sq.Select("u.team_id").
+		From("pull_requests pr").
+		Join("users u ON u.id = pr.author_id").
+		Where(sq.Eq{"pr.id": prID}).
+		Where(sq.NotEq{"u.team_id": nil}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var teamID int
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &teamID, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w: team of PR with id '%s'", op, apperrors.ErrNotFound, prID)
+		}
+
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return teamID, nil
+}
+
+func (s *Store) ReplaceReviewer(ctx context.Context, prID string, oldReviewerID string, newReviewerID string) error {
+	const op = "internal.repository.sqlite.ReplaceReviewer"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.replaceReviewers(ctx, tx, op, []domain.ReviewerReplacement{
+		{PullRequestID: prID, OldReviewerID: oldReviewerID, NewReviewerID: newReviewerID},
+	})
+}
+
+// ReplaceReviewers deletes all the old reviewers before inserting the new ones, so that a batch may hand
+// a review over to a user who hands their own review over in the same batch.
+func (s *Store) ReplaceReviewers(ctx context.Context, replacements []domain.ReviewerReplacement) error {
+	const op = "internal.repository.sqlite.ReplaceReviewers"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.replaceReviewers(ctx, tx, op, replacements)
+}
+
+// replaceReviewers deletes the old reviewers and inserts the new ones under a savepoint: a failed statement
+// does not abort the transaction as in postgres, so the deletes are rolled back when an insert fails.
+func (s *Store) replaceReviewers(ctx context.Context, tx *sqlx.Tx, op string, replacements []domain.ReviewerReplacement) error {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT replace_reviewers"); err != nil {
+		return fmt.Errorf("%s: failed to create savepoint: %w", op, err)
+	}
+
+	if err := s.swapReviewers(ctx, tx, op, replacements); err != nil {
+		if _, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO replace_reviewers"); rollbackErr != nil {
+			s.log.Error("failed to roll back to savepoint", slog.String("op", op), sl.Err(rollbackErr))
+		}
+
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE replace_reviewers"); err != nil {
+		return fmt.Errorf("%s: failed to release savepoint: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Store) swapReviewers(ctx context.Context, tx *sqlx.Tx, op string, replacements []domain.ReviewerReplacement) error {
+	for _, replacement := range replacements {
+		query, args, err := s.sq.Delete("reviewers").
+			Where(sq.Eq{"pull_request_id": replacement.PullRequestID, "user_id": replacement.OldReviewerID}).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("%s: failed to build delete query: %w", op, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("%s: failed to execute delete: %w", op, err)
+		}
+	}
+
+	now := timestampOrNow(time.Time{})
+
+	for _, replacement := range replacements {
+		query, args, err := s.sq.Insert("reviewers").
+			Columns("pull_request_id", "user_id", "assigned_at").
+			Values(replacement.PullRequestID, replacement.NewReviewerID, now).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("%s: failed to build insert query: %w", op, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			if assignmentErr := reviewerConstraintError(err, replacement.PullRequestID); assignmentErr != nil {
+				return fmt.Errorf("%s: %w", op, assignmentErr)
+			}
+
+			return fmt.Errorf("%s: failed to execute insert: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) GetReviewAssignments(ctx context.Context, userID string, customFields map[string]string) ([]domain.PullRequest, error) {
+	const op = "internal.repository.sqlite.GetReviewAssignments"
+
+	query, args, err := s.sq.Select("pr.id", "pr.name", "pr.author_id", "pr.status", "pr.custom_fields").
+		From("pull_requests pr").
+		Join("reviewers r ON pr.id = r.pull_request_id").
+		Where(append(sq.And{sq.Eq{"r.user_id": userID}}, customFieldsCondition("pr.custom_fields", customFields)...)).
+		OrderBy("pr.created_at DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	prs := []domain.PullRequest{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &prs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return prs, nil
+}
+
+// customFieldsCondition matches pull requests whose custom fields, formatted as text, equal the given values:
+// strings as they are and the other values as JSON, the way ->> formats them in postgres.
+// Comparing text lets one filter serve teams that gave the same key different types.
+func customFieldsCondition(column string, customFields map[string]string) sq.And {
+	cond := sq.And{}
+	for _, key := range slices.Sorted(maps.Keys(customFields)) {
+		path := `$."` + key + `"`
+		cond = append(cond, sq.Expr(
+			"CASE json_type("+column+", ?) WHEN 'text' THEN "+column+" ->> ? ELSE "+column+" -> ? END = ?",
+			path, path, path, customFields[key],
+		))
+	}
+
+	return cond
+}
+
+// searchCondition matches pull requests against the full-text query built by searchQuery.
+func searchCondition(match string, filter domain.PRSearchFilter) sq.And {
+	cond := sq.And{sq.Expr("pull_requests_search MATCH ?", match)}
+	if filter.Status != "" {
+		cond = append(cond, sq.Eq{"pr.status": filter.Status})
+	}
+
+	return append(cond, customFieldsCondition("pr.custom_fields", filter.CustomFields)...)
+}
+
+// searchQuery translates a web search style query, e.g. `search -draft "add api"`, into an FTS5 query:
+// the words and quoted phrases are all required, and those prefixed with a minus are excluded.
+// It returns an empty query if nothing is required, which matches no pull request.
+func searchQuery(query string) string {
+	var include, exclude []string
+
+	for _, term := range searchTerms(query) {
+		text, excluded := strings.CutPrefix(term, "-")
+		if !strings.ContainsFunc(text, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) {
+			continue
+		}
+
+		phrase := `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+		if excluded {
+			exclude = append(exclude, phrase)
+		} else {
+			include = append(include, phrase)
+		}
+	}
+
+	if len(include) == 0 {
+		return ""
+	}
+
+	match := strings.Join(include, " ")
+	for _, phrase := range exclude {
+		match += " NOT " + phrase
+	}
+
+	return match
+}
+
+// searchTerms splits a query into its words and quoted phrases, which keep a leading minus.
+func searchTerms(query string) []string {
+	var (
+		terms   []string
+		term    strings.Builder
+		inQuote bool
+	)
+
+	flush := func() {
+		if term.Len() > 0 {
+			terms = append(terms, term.String())
+			term.Reset()
+		}
+	}
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			if inQuote {
+				flush()
+			}
+
+			inQuote = !inQuote
+		case unicode.IsSpace(r) && !inQuote:
+			flush()
+		default:
+			term.WriteRune(r)
+		}
+	}
+
+	flush()
+
+	return terms
+}
+
+func (s *Store) SearchPRs(ctx context.Context, filter domain.PRSearchFilter) ([]domain.PullRequest, int, error) {
+	const op = "internal.repository.sqlite.SearchPRs"
+
+	ext := txctx.Ext(ctx, s.db)
+
+	match := searchQuery(filter.Query)
+	if match == "" {
+		return []domain.PullRequest{}, 0, nil
+	}
+
+	countQuery, args, err := s.sq.Select("COUNT(*)").
+		From("pull_requests pr").
+		Join("pull_requests_search ON pull_requests_search.rowid = pr.rowid").
+		Where(searchCondition(match, filter)).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to build count query: %w", op, err)
+	}
+
+	var total int
+	if err := sqlx.GetContext(ctx, ext, &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to count matches: %w", op, err)
+	}
+
+	if total == 0 {
+		return []domain.PullRequest{}, 0, nil
+	}
+
+	// bm25 is lower for better matches; the name weighs more than the description,
+	// like setweight 'A' and 'B' in postgres.
+	query, args, err := s.sq.Select(qualified("pr", prColumns)...).
+		From("pull_requests pr").
+		Join("pull_requests_search ON pull_requests_search.rowid = pr.rowid").
+		Where(searchCondition(match, filter)).
+		OrderBy("bm25(pull_requests_search, 4.0, 1.0)", "pr.created_at DESC", "pr.id").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset)).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	prs := []domain.PullRequest{}
+	if err := sqlx.SelectContext(ctx, ext, &prs, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return prs, total, nil
+}
+
+// listCondition composes the filters of a pull request list; the filters left unset are omitted.
+func listCondition(filter domain.PRListFilter) sq.And {
+	cond := sq.And{}
+	if filter.Status != "" {
+		cond = append(cond, sq.Eq{"status": filter.Status})
+	}
+
+	if filter.AuthorID != "" {
+		cond = append(cond, sq.Eq{"author_id": filter.AuthorID})
+	}
+
+	if filter.TeamID != 0 {
+		cond = append(cond, sq.Expr("author_id IN (SELECT id FROM users WHERE team_id = ? AND deleted_at IS NULL)", filter.TeamID))
+	}
+
+	if filter.CreatedFrom != nil {
+		cond = append(cond, sq.GtOrEq{"created_at": timestamp(*filter.CreatedFrom)})
+	}
+
+	if filter.CreatedTo != nil {
+		cond = append(cond, sq.Lt{"created_at": timestamp(*filter.CreatedTo)})
+	}
+
+	if filter.After != nil {
+		cond = append(cond, sq.Expr("(created_at, id) < (?, ?)", timestamp(filter.After.CreatedAt), filter.After.ID))
+	}
+
+	return cond
+}
+
+func (s *Store) ListPRs(ctx context.Context, filter domain.PRListFilter) ([]domain.PullRequest, error) {
+	const op = "internal.repository.sqlite.ListPRs"
+
+	ext := txctx.Ext(ctx, s.db)
+
+	query, args, err := s.sq.Select(prColumns...).
+		From("pull_requests").
+		Where(listCondition(filter)).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	prs := []domain.PullRequest{}
+	if err := sqlx.SelectContext(ctx, ext, &prs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	if len(prs) == 0 {
+		return prs, nil
+	}
+
+	prIDs := make([]string, len(prs))
+	for i := range prs {
+		prIDs[i] = prs[i].ID
+		prs[i].ReviewerIDs = []string{}
+	}
+
+	reviewers, err := s.selectReviewersOf(ctx, ext, prIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return mapReviewersToPRs(prs, reviewers), nil
+}
+
+// selectReviewersOf reads the reviewers of the pull requests.
+func (s *Store) selectReviewersOf(ctx context.Context, ext sqlx.ExtContext, prIDs []string) ([]domain.Reviewer, error) {
+	query, args, err := s.sq.Select("pull_request_id", "user_id").
+		From("reviewers").
+		Where(sq.Eq{"pull_request_id": prIDs}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build reviewers query: %w", err)
+	}
+
+	var reviewers []domain.Reviewer
+	if err := sqlx.SelectContext(ctx, ext, &reviewers, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to select reviewers: %w", err)
+	}
+
+	return reviewers, nil
+}
+
+func (s *Store) statsQuery(period domain.StatsPeriod) sq.SelectBuilder {
+	openCond := sq.And{sq.Eq{"pr.status": api.PullRequestStatusOPEN}}
+	openCond = append(openCond, periodCond("pr.created_at", period)...)
+
+	mergedCond := sq.And{sq.Eq{"pr.status": api.PullRequestStatusMERGED}}
+	mergedCond = append(mergedCond, periodCond("pr.merged_at", period)...)
+
+	firstReviewCond := sq.And{sq.NotEq{"r.first_reviewed_at": nil}}
+	firstReviewCond = append(firstReviewCond, periodCond("r.first_reviewed_at", period)...)
+
+	query := s.sq.Select("u.id as user_id", "u.username").
+		Column(sq.Expr("COUNT(CASE WHEN ? THEN 1 END) as open_reviews", openCond)).
+		Column(sq.Expr("COUNT(CASE WHEN ? THEN 1 END) as merged_reviews", mergedCond)).
+		Column(sq.Expr("COUNT(CASE WHEN ? THEN 1 END) as first_reviews", firstReviewCond))
+
+	for _, column := range durationColumns("first_review", "r.first_reviewed_at", "r.assigned_at", firstReviewCond) {
+		query = query.Column(column)
+	}
+
+	for _, column := range durationColumns("merge", "pr.merged_at", "r.assigned_at", mergedCond) {
+		query = query.Column(column)
+	}
+
+	return query.
+		From("users u").
+		LeftJoin("reviewers r ON u.id = r.user_id").
+		LeftJoin("pull_requests pr ON r.pull_request_id = pr.id").
+		Where(sq.Eq{"u.deleted_at": nil}).
+		GroupBy("u.id", "u.username").
+		OrderBy("u.username COLLATE " + usernameCollation)
+}
+
+// secondsBetween is the SQL expression of the seconds from the timestamp start to the timestamp end.
+func secondsBetween(end, start string) string {
+	return "(unixepoch(" + end + ", 'subsec') - unixepoch(" + start + ", 'subsec'))"
+}
+
+// durationColumns summarizes the time from start to end over the rows matching cond into the prefix_avg_seconds,
+// prefix_p50_seconds and prefix_p90_seconds columns, which are NULL when no row matches.
+func durationColumns(prefix, end, start string, cond sq.Sqlizer) []sq.Sqlizer {
+	seconds := "CASE WHEN ? THEN " + secondsBetween(end, start) + " END"
+
+	return []sq.Sqlizer{
+		sq.Expr("AVG("+seconds+") AS "+prefix+"_avg_seconds", cond),
+		sq.Expr("percentile_cont(0.5, "+seconds+") AS "+prefix+"_p50_seconds", cond),
+		sq.Expr("percentile_cont(0.9, "+seconds+") AS "+prefix+"_p90_seconds", cond),
+	}
+}
+
+// periodCond restricts column to the period; it is empty for an unbounded period.
+func periodCond(column string, period domain.StatsPeriod) sq.And {
+	cond := sq.And{}
+
+	if period.From != nil {
+		cond = append(cond, sq.GtOrEq{column: timestamp(*period.From)})
+	}
+
+	if period.To != nil {
+		cond = append(cond, sq.Lt{column: timestamp(*period.To)})
+	}
+
+	return cond
+}
+
+func (s *Store) GetUserStats(ctx context.Context, period domain.StatsPeriod) ([]domain.Stats, error) {
+	const op = "internal.repository.sqlite.GetUserStats"
+
+	query, args, err := s.statsQuery(period).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	stats := []domain.Stats{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &stats, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+func (s *Store) GetLeaderboard(ctx context.Context, period domain.StatsPeriod, limit int) ([]domain.LeaderboardEntry, error) {
+	const op = "internal.repository.sqlite.GetLeaderboard"
+
+	mergedCond := sq.And{sq.Eq{"pr.status": api.PullRequestStatusMERGED}}
+	mergedCond = append(mergedCond, periodCond("pr.merged_at", period)...)
+
+	query, args, err := s.sq.Select("u.id AS user_id", "u.username", "COUNT(*) AS merged_reviews").
+		From("pull_requests pr").
+		Join("reviewers r ON r.pull_request_id = pr.id").
+		Join("users u ON u.id = r.user_id").
+		Where(mergedCond).
+		Where(sq.Eq{"u.deleted_at": nil}).
+		GroupBy("u.id", "u.username").
+		OrderBy("merged_reviews DESC", "u.username COLLATE "+usernameCollation, "u.id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	entries := []domain.LeaderboardEntry{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &entries, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+func (s *Store) GetOpenPRAgeStats(ctx context.Context) ([]domain.OpenPRAgeStats, error) {
+	const op = "internal.repository.sqlite.GetOpenPRAgeStats"
+
+	now := timestampOrNow(time.Time{})
+	age := secondsBetween("?", "pr.created_at")
+
+	query, args, err := s.sq.Select("t.name AS team_name", "COUNT(*) AS open_prs").
+		Column(sq.Expr("percentile_cont(0.5, "+age+") AS p50_seconds", now)).
+		Column(sq.Expr("percentile_cont(0.9, "+age+") AS p90_seconds", now)).
+		Column(sq.Expr("MAX("+age+") AS max_seconds", now)).
+		From("pull_requests pr").
+		Join("users u ON u.id = pr.author_id").
+		Join("teams t ON t.id = u.team_id").
+		Where(sq.Eq{"pr.status": api.PullRequestStatusOPEN, "u.deleted_at": nil, "t.deleted_at": nil}).
+		GroupBy("t.name").
+		OrderBy("t.name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	stats := []domain.OpenPRAgeStats{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &stats, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+func (s *Store) GetTeamStats(ctx context.Context, period domain.StatsPeriod) ([]domain.TeamStats, error) {
+	const op = "internal.repository.sqlite.GetTeamStats"
+
+	firstReviewCond := sq.And{sq.NotEq{"fr.first_reviewed_at": nil}}
+	firstReviewCond = append(firstReviewCond, periodCond("fr.first_reviewed_at", period)...)
+
+	mergedCond := sq.And{sq.Eq{"pr.status": api.PullRequestStatusMERGED}}
+	mergedCond = append(mergedCond, periodCond("pr.merged_at", period)...)
+
+	builder := s.sq.Select("t.name AS team_name").
+		Column(sq.Expr("COUNT(CASE WHEN ? THEN 1 END) AS first_reviewed_prs", firstReviewCond))
+
+	for _, column := range durationColumns("first_review", "fr.first_reviewed_at", "pr.created_at", firstReviewCond) {
+		builder = builder.Column(column)
+	}
+
+	builder = builder.Column(sq.Expr("COUNT(CASE WHEN ? THEN 1 END) AS merged_prs", mergedCond))
+
+	for _, column := range durationColumns("merge", "pr.merged_at", "pr.created_at", mergedCond) {
+		builder = builder.Column(column)
+	}
+
+	query, args, err := builder.
+		From("pull_requests pr").
+		Join("users u ON u.id = pr.author_id").
+		Join("teams t ON t.id = u.team_id").
+		LeftJoin("(SELECT pull_request_id, MIN(first_reviewed_at) AS first_reviewed_at FROM reviewers GROUP BY pull_request_id) fr ON fr.pull_request_id = pr.id").
+		Where(sq.Or{firstReviewCond, mergedCond}).
+		Where(sq.Eq{"u.deleted_at": nil, "t.deleted_at": nil}).
+		GroupBy("t.name").
+		OrderBy("t.name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	stats := []domain.TeamStats{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &stats, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+func (s *Store) GetStatsByUserIDs(ctx context.Context, userIDs []string) ([]domain.Stats, error) {
+	const op = "internal.repository.sqlite.GetStatsByUserIDs"
+
+	if len(userIDs) == 0 {
+		return []domain.Stats{}, nil
+	}
+
+	query, args, err := s.statsQuery(domain.StatsPeriod{}).
+		Where(sq.Eq{"u.id": userIDs}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	stats := []domain.Stats{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &stats, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+func mapReviewersToPRs(prs []domain.PullRequest, reviewers []domain.Reviewer) []domain.PullRequest {
+	prMap := make(map[string]*domain.PullRequest, len(prs))
+	for i := range prs {
+		prMap[prs[i].ID] = &prs[i]
+	}
+
+	for _, reviewer := range reviewers {
+		if pr, ok := prMap[reviewer.PullRequestID]; ok {
+			pr.ReviewerIDs = append(pr.ReviewerIDs, reviewer.UserID)
+		}
+	}
+
+	return prs
+}
+
+func (s *Store) GetOpenPRsByReviewers(ctx context.Context, userIDs []string) ([]domain.PullRequest, error) {
+	const op = "internal.repository.sqlite.GetOpenPRsByReviewers"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := s.sq.Select("DISTINCT pr.id", "pr.name", "pr.author_id", "pr.status").
+		From("pull_requests pr").
+		Join("reviewers r ON r.pull_request_id = pr.id").
+		Where(sq.Eq{"r.user_id": userIDs, "pr.status": api.PullRequestStatusOPEN}).
+		OrderBy("pr.id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build prs query: %w", op, err)
+	}
+
+	prs := []domain.PullRequest{}
+	if err := tx.SelectContext(ctx, &prs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to select prs: %w", op, err)
+	}
+
+	if len(prs) == 0 {
+		return prs, nil
+	}
+
+	prIDs := make([]string, len(prs))
+	for i := range prs {
+		prIDs[i] = prs[i].ID
+	}
+
+	reviewers, err := s.selectReviewersOf(ctx, tx, prIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return mapReviewersToPRs(prs, reviewers), nil
+}
+
+func (s *Store) CountOpenPRsByAuthor(ctx context.Context, authorID string) (int, error) {
+	const op = "internal.repository.sqlite.CountOpenPRsByAuthor"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := s.sq.Select("COUNT(*)").
+		From("pull_requests").
+		Where(sq.Eq{"author_id": authorID, "status": api.PullRequestStatusOPEN}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var count int
+	if err := tx.GetContext(ctx, &count, query, args...); err != nil {
+		return 0, fmt.Errorf("%s: failed to count open PRs: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (s *Store) LockActiveUsers(ctx context.Context, userIDs []string) ([]string, error) {
+	const op = "internal.repository.sqlite.LockActiveUsers"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	activeIDs := []string{}
+	if len(userIDs) == 0 {
+		return activeIDs, nil
+	}
+
+	query, args, err := s.sq.Select("id").
+		From("users").
+		Where(sq.Eq{"id": userIDs, "is_active": true, "deleted_at": nil}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if err := tx.SelectContext(ctx, &activeIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to select users: %w", op, err)
+	}
+
+	return activeIDs, nil
+}
+
+func (s *Store) SetNeedMoreReviewers(ctx context.Context, prID string, need bool) error {
+	const op = "internal.repository.sqlite.SetNeedMoreReviewers"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := s.sq.Update("pull_requests").
+		Set("need_more_reviewers", need).
+		Where(sq.Eq{"id": prID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rowsAffected, err := res.RowsAffected(); err == nil && rowsAffected == 0 {
+		return fmt.Errorf("%s: %w: PR with id '%s'", op, apperrors.ErrNotFound, prID)
+	}
+
+	return nil
+}
+
+func (s *Store) SetReviewState(ctx context.Context, prID string, reviewerID string, state domain.ReviewState, reviewedAt time.Time) error {
+	const op = "internal.repository.sqlite.SetReviewState"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := s.sq.Update("reviewers").
+		Set("review_state", state).
+		Set("reviewed_at", timestamp(reviewedAt)).
+		Set("first_reviewed_at", sq.Expr("COALESCE(first_reviewed_at, ?)", timestamp(reviewedAt))).
+		Where(sq.Eq{"pull_request_id": prID, "user_id": reviewerID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rowsAffected, err := res.RowsAffected(); err == nil && rowsAffected == 0 {
+		return fmt.Errorf("%s: %w: user '%s' does not review PR '%s'", op, apperrors.ErrReviewerNotAssigned, reviewerID, prID)
+	}
+
+	return nil
+}
+
+func (s *Store) BackfillReviewerDurations(ctx context.Context, afterID string, limit int) (string, int, error) {
+	const op = "internal.repository.sqlite.BackfillReviewerDurations"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := s.sq.Select("id").
+		From("pull_requests").
+		Where(sq.Gt{"id": afterID}).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: failed to build batch query: %w", op, err)
+	}
+
+	var batch []string
+	if err := tx.SelectContext(ctx, &batch, query, args...); err != nil {
+		return "", 0, fmt.Errorf("%s: failed to select batch: %w", op, err)
+	}
+
+	if len(batch) == 0 {
+		return "", 0, nil
+	}
+
+	recomputed := sq.Select("r.pull_request_id", "r.user_id").
+		Column("COALESCE((SELECT MAX(h.created_at) FROM assignment_history h " +
+			"WHERE h.pull_request_id = r.pull_request_id AND h.user_id = r.user_id), pr.created_at) AS assigned_at").
+		Column("COALESCE(r.first_reviewed_at, r.reviewed_at) AS first_reviewed_at").
+		From("reviewers r").
+		Join("pull_requests pr ON pr.id = r.pull_request_id").
+		Where(sq.Eq{"r.pull_request_id": batch})
+
+	// Only the reviewers whose times change are updated, so that a backfill run again rewrites nothing.
+	query, args, err = s.sq.Update("reviewers").
+		Set("assigned_at", sq.Expr("c.assigned_at")).
+		Set("first_reviewed_at", sq.Expr("c.first_reviewed_at")).
+		FromSelect(recomputed, "c").
+		Where("reviewers.pull_request_id = c.pull_request_id AND reviewers.user_id = c.user_id").
+		Where("(reviewers.assigned_at IS NOT c.assigned_at OR reviewers.first_reviewed_at IS NOT c.first_reviewed_at)").
+		ToSql()
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: failed to count updated reviewers: %w", op, err)
+	}
+
+	return batch[len(batch)-1], int(updated), nil
+}
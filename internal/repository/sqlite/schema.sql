@@ -0,0 +1,236 @@
+-- The schema of the embedded store. It follows the PostgreSQL migrations, leaving out the tables of the
+-- features the demo does not run. The timestamps are written by the store in UTC as text, which orders them.
+
+CREATE TABLE IF NOT EXISTS teams (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    deleted_at TIMESTAMP
+);
+
+-- The name of a deleted team can be taken by a new one.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_teams_name ON teams (name) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS users (
+    id TEXT PRIMARY KEY,
+    username TEXT NOT NULL UNIQUE,
+    team_id INTEGER REFERENCES teams(id) ON DELETE SET NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('member', 'lead', 'admin')),
+    deleted_at TIMESTAMP
+);
+
+-- users.team_id stays the primary team of a user, which is also among the memberships.
+CREATE TABLE IF NOT EXISTS user_teams (
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, team_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_teams_team_id ON user_teams (team_id, user_id);
+
+CREATE TABLE IF NOT EXISTS pull_requests (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    author_id TEXT NOT NULL REFERENCES users(id),
+    status TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'MERGED', 'CLOSED')),
+    need_more_reviewers BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL,
+    merged_at TIMESTAMP,
+    description TEXT,
+    external_url TEXT,
+    custom_fields TEXT NOT NULL DEFAULT '{}',
+    assignment_policy TEXT,
+    assign_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_open_author ON pull_requests (author_id) WHERE status = 'OPEN';
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created_at ON pull_requests (created_at DESC, id DESC);
+
+-- The full-text index of the names and descriptions, kept up to date by the triggers below.
+CREATE VIRTUAL TABLE IF NOT EXISTS pull_requests_search USING fts5 (
+    name, description, content = 'pull_requests', content_rowid = 'rowid'
+);
+
+CREATE TRIGGER IF NOT EXISTS pull_requests_search_insert AFTER INSERT ON pull_requests BEGIN
+    INSERT INTO pull_requests_search (rowid, name, description) VALUES (NEW.rowid, NEW.name, NEW.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS pull_requests_search_update AFTER UPDATE OF name, description ON pull_requests BEGIN
+    INSERT INTO pull_requests_search (pull_requests_search, rowid, name, description)
+    VALUES ('delete', OLD.rowid, OLD.name, OLD.description);
+    INSERT INTO pull_requests_search (rowid, name, description) VALUES (NEW.rowid, NEW.name, NEW.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS pull_requests_search_delete AFTER DELETE ON pull_requests BEGIN
+    INSERT INTO pull_requests_search (pull_requests_search, rowid, name, description)
+    VALUES ('delete', OLD.rowid, OLD.name, OLD.description);
+END;
+
+CREATE TABLE IF NOT EXISTS reviewers (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    review_state TEXT NOT NULL DEFAULT 'PENDING' CHECK (review_state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED')),
+    reviewed_at TIMESTAMP,
+    assigned_at TIMESTAMP NOT NULL,
+    first_reviewed_at TIMESTAMP,
+    PRIMARY KEY (pull_request_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviewers_user_id ON reviewers (user_id);
+
+-- A check constraint cannot look at pull_requests, so the author is rejected by a trigger.
+CREATE TRIGGER IF NOT EXISTS reviewers_not_author BEFORE INSERT ON reviewers
+WHEN EXISTS (SELECT 1 FROM pull_requests WHERE id = NEW.pull_request_id AND author_id = NEW.user_id)
+BEGIN
+    SELECT RAISE(ABORT, 'reviewers_not_author');
+END;
+
+CREATE TABLE IF NOT EXISTS team_policies (
+    team_id INTEGER PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    strategy_weights TEXT NOT NULL DEFAULT '{}',
+    author_open_pr_limit INTEGER CHECK (author_open_pr_limit > 0),
+    over_quota_action TEXT NOT NULL DEFAULT 'reject' CHECK (over_quota_action IN ('reject', 'queue')),
+    reactivation_rebalance TEXT NOT NULL DEFAULT 'off' CHECK (reactivation_rebalance IN ('off', 'immediate', 'job')),
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS assignment_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    replaced_user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    strategy TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    cause TEXT,
+    handoff_note TEXT,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_history_pr_timeline ON assignment_history (pull_request_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_assignment_history_user_created ON assignment_history (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_assignment_history_replaced_user_id ON assignment_history (replaced_user_id, created_at)
+    WHERE replaced_user_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS pending_assignments (
+    pull_request_id TEXT PRIMARY KEY REFERENCES pull_requests(id) ON DELETE CASCADE,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    priority INTEGER NOT NULL CHECK (priority > 0),
+    enqueued_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_assignments_order ON pending_assignments (priority DESC, enqueued_at, pull_request_id);
+
+CREATE TABLE IF NOT EXISTS reviewer_borrows (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    lender_team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    reviewer_count INTEGER NOT NULL CHECK (reviewer_count > 0),
+    duration_hours INTEGER NOT NULL CHECK (duration_hours > 0),
+    status TEXT NOT NULL DEFAULT 'requested' CHECK (status IN ('requested', 'accepted')),
+    requested_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    CHECK (team_id <> lender_team_id)
+);
+
+CREATE TABLE IF NOT EXISTS borrowed_reviewers (
+    borrow_id INTEGER NOT NULL REFERENCES reviewer_borrows(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (borrow_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS team_custom_fields (
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('string', 'number', 'boolean')),
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (team_id, key)
+);
+
+CREATE TABLE IF NOT EXISTS pr_subscriptions (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (pull_request_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    channel TEXT NOT NULL,
+    recipient_id TEXT NOT NULL,
+    event TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('delivered', 'failed')),
+    error TEXT,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS freeze_windows (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_id INTEGER REFERENCES teams(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE TABLE IF NOT EXISTS gitlab_users (
+    gitlab_username TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS slack_users (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    slack_user_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- The events of a webhook are a JSON array of their names.
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url TEXT NOT NULL,
+    events TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT,
+    response_status INTEGER,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, id);
+
+-- job_id refers to the deactivation jobs of the PostgreSQL store; the demo deactivates teams without them.
+CREATE TABLE IF NOT EXISTS team_deactivations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    job_id INTEGER,
+    created_at TIMESTAMP NOT NULL,
+    reactivated_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS team_deactivation_members (
+    deactivation_id INTEGER NOT NULL REFERENCES team_deactivations(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (deactivation_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_deactivations_team_id ON team_deactivations (team_id, created_at DESC, id DESC);
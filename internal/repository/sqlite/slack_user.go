@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/jmoiron/sqlx"
+)
+
+func (s *Store) SetSlackUser(ctx context.Context, mapping *domain.SlackUser) (*domain.SlackUser, error) {
+	const op = "internal.repository.sqlite.SetSlackUser"
+
+	query, args, err := s.sq.Insert("slack_users").
+		Columns("user_id", "slack_user_id", "created_at").
+		Values(mapping.UserID, mapping.SlackUserID, timestampOrNow(mapping.CreatedAt)).
+		Suffix(`ON CONFLICT (user_id) DO UPDATE SET slack_user_id = excluded.slack_user_id, created_at = excluded.created_at
+			RETURNING user_id, slack_user_id, created_at`).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var stored domain.SlackUser
+	if err := txctx.Ext(ctx, s.db).QueryRowxContext(ctx, query, args...).StructScan(&stored); err != nil {
+		if isConstraintError(err, codeForeignKey) {
+			return nil, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, mapping.UserID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return &stored, nil
+}
+
+func (s *Store) GetSlackUser(ctx context.Context, userID string) (*domain.SlackUser, error) {
+	const op = "internal.repository.sqlite.GetSlackUser"
+
+	query, args, err := s.sq.Select("user_id", "slack_user_id", "created_at").
+		From("slack_users").
+		Where(sq.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var mapping domain.SlackUser
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &mapping, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: slack user of '%s'", op, apperrors.ErrNotFound, userID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &mapping, nil
+}
+
+func (s *Store) ListSlackUsers(ctx context.Context) ([]domain.SlackUser, error) {
+	const op = "internal.repository.sqlite.ListSlackUsers"
+
+	query, args, err := s.sq.Select("user_id", "slack_user_id", "created_at").
+		From("slack_users").
+		OrderBy("user_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	mappings := []domain.SlackUser{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &mappings, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to list slack users: %w", op, err)
+	}
+
+	return mappings, nil
+}
+
+func (s *Store) DeleteSlackUser(ctx context.Context, userID string) error {
+	const op = "internal.repository.sqlite.DeleteSlackUser"
+
+	query, args, err := s.sq.Delete("slack_users").
+		Where(sq.Eq{"user_id": userID}).
+		Suffix("RETURNING user_id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build delete query: %w", op, err)
+	}
+
+	var deleted string
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &deleted, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w: slack user of '%s'", op, apperrors.ErrNotFound, userID)
+		}
+
+		return fmt.Errorf("%s: failed to execute delete: %w", op, err)
+	}
+
+	return nil
+}
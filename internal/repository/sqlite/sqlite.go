@@ -0,0 +1,213 @@
+// Package sqlite implements the repository interfaces the demo mode needs on top of an embedded SQLite
+// database, so that "pr-reviewer demo" runs without PostgreSQL. The database is a single file and the driver
+// is written in pure Go, so the binary still builds without cgo.
+//
+// Transactions begin with BEGIN IMMEDIATE and are serialized by the write lock of the database, which takes
+// the place of the row locks, advisory locks and SKIP LOCKED claims of the postgres repository. Every method
+// runs its queries in the transaction carried by its context, see txctx: a query on another connection would
+// wait for the write lock that transaction holds.
+package sqlite
+
+import (
+	"database/sql/driver"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	modernc "modernc.org/sqlite"
+	sqlitelib "modernc.org/sqlite/lib"
+)
+
+//go:embed schema.sql
+var schema string
+
+// usernameCollation orders usernames by the Unicode collation algorithm, like the "und-x-icu" collation
+// of the postgres repository.
+const usernameCollation = "und"
+
+func init() {
+	modernc.MustRegisterCollationUtf8(usernameCollation, compareUsernames)
+	modernc.MustRegisterFunction("percentile_cont", &modernc.FunctionImpl{
+		NArgs:         2,
+		Deterministic: true,
+		MakeAggregate: func(modernc.FunctionContext) (modernc.AggregateFunction, error) {
+			return &percentileCont{}, nil
+		},
+	})
+}
+
+var (
+	// collatorMu guards collator, which is not safe for concurrent use.
+	collatorMu sync.Mutex
+	collator   = collate.New(language.Und)
+)
+
+func compareUsernames(left, right string) int {
+	collatorMu.Lock()
+	defer collatorMu.Unlock()
+
+	return collator.CompareString(left, right)
+}
+
+// percentileCont is the percentile_cont aggregate of postgres written as percentile_cont(fraction, value):
+// it interpolates linearly between the values around the fraction, skips NULLs and is NULL without values.
+type percentileCont struct {
+	fraction float64
+	values   []float64
+}
+
+func (p *percentileCont) Step(_ *modernc.FunctionContext, args []driver.Value) error {
+	fraction, ok := toFloat(args[0])
+	if !ok || fraction < 0 || fraction > 1 {
+		return fmt.Errorf("percentile_cont: fraction %v is not between 0 and 1", args[0])
+	}
+
+	p.fraction = fraction
+
+	if value, ok := toFloat(args[1]); ok {
+		p.values = append(p.values, value)
+	}
+
+	return nil
+}
+
+func (p *percentileCont) WindowInverse(*modernc.FunctionContext, []driver.Value) error {
+	return errors.New("percentile_cont: not supported as a window function")
+}
+
+func (p *percentileCont) WindowValue(*modernc.FunctionContext) (driver.Value, error) {
+	if len(p.values) == 0 {
+		return nil, nil
+	}
+
+	slices.Sort(p.values)
+
+	pos := p.fraction * float64(len(p.values)-1)
+	lower, upper := p.values[int(math.Floor(pos))], p.values[int(math.Ceil(pos))]
+
+	return lower + (pos-math.Floor(pos))*(upper-lower), nil
+}
+
+func (p *percentileCont) Final(*modernc.FunctionContext) {}
+
+// toFloat converts a numeric SQL value; it reports false for NULL.
+func toFloat(value driver.Value) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// Store keeps the service data in an SQLite database and implements the repository interfaces of the demo mode.
+type Store struct {
+	db  *sqlx.DB
+	log *slog.Logger
+	sq  sq.StatementBuilderType
+	// tx runs the methods that the postgres repository runs in transactions of their own;
+	// called in a transaction, they join it.
+	tx *txctx.Manager
+}
+
+// NewStore opens the database in the file at path, creating the file and the schema if they do not exist yet.
+func NewStore(path string, log *slog.Logger) (*Store, error) {
+	// The timestamps are written in UTC with a fixed layout, so that comparing them as text orders them.
+	// A writer waits for the write lock instead of failing at once, and WAL keeps the readers off it.
+	params := url.Values{}
+	params.Set("_txlock", "immediate")
+	params.Set("_time_format", "sqlite")
+	params.Set("_timezone", "UTC")
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(10000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+
+	db, err := sqlx.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("can't open database: %w", err)
+	}
+
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("can't create schema: %w", err)
+	}
+
+	return &Store{
+		db:  db,
+		log: log,
+		sq:  sq.StatementBuilder,
+		tx:  txctx.NewManager(db, log),
+	}, nil
+}
+
+// DB returns the database, whose transactions the services begin through txctx.
+func (s *Store) DB() *sqlx.DB {
+	return s.db
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// timestamp returns t as a query value: in UTC and truncated to microseconds, the precision of the postgres columns.
+func timestamp(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+// timestampOrNow is like timestamp for an insert value; a zero t is replaced with the current time,
+// as the postgres columns default to NOW().
+func timestampOrNow(t time.Time) time.Time {
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	return timestamp(t)
+}
+
+// jsonObjectOrEmpty returns raw as an insert value for a JSON object column; an empty raw is stored as an empty object.
+func jsonObjectOrEmpty(raw []byte) string {
+	if len(raw) == 0 {
+		return "{}"
+	}
+
+	return string(raw)
+}
+
+// jsonOrNull is like jsonObjectOrEmpty for a nullable JSON column: an empty raw is stored as NULL.
+func jsonOrNull(raw []byte) *string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	value := string(raw)
+
+	return &value
+}
+
+// isConstraintError reports whether err is a violation of a constraint of the kind given by the extended result code.
+func isConstraintError(err error, code int) bool {
+	var sqliteErr *modernc.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == code
+}
+
+// Extended result codes of the constraint violations told apart by the store.
+const (
+	codeUnique     = sqlitelib.SQLITE_CONSTRAINT_UNIQUE
+	codePrimaryKey = sqlitelib.SQLITE_CONSTRAINT_PRIMARYKEY
+	codeForeignKey = sqlitelib.SQLITE_CONSTRAINT_FOREIGNKEY
+	codeTrigger    = sqlitelib.SQLITE_CONSTRAINT_TRIGGER
+)
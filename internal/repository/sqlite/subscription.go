@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/jmoiron/sqlx"
+)
+
+func (s *Store) Subscribe(ctx context.Context, prID string, userID string) (*domain.PRSubscription, bool, error) {
+	const op = "internal.repository.sqlite.Subscribe"
+
+	ext := txctx.Ext(ctx, s.db)
+
+	query, args, err := s.sq.Insert("pr_subscriptions").
+		Columns("pull_request_id", "user_id", "created_at").
+		Values(prID, userID, timestampOrNow(time.Time{})).
+		Suffix("ON CONFLICT (pull_request_id, user_id) DO NOTHING RETURNING pull_request_id, user_id, created_at").
+		ToSql()
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var sub domain.PRSubscription
+	if err := ext.QueryRowxContext(ctx, query, args...).StructScan(&sub); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			existing, err := s.getSubscription(ctx, ext, prID, userID)
+			if err != nil {
+				return nil, false, fmt.Errorf("%s: %w", op, err)
+			}
+
+			return existing, false, nil
+		}
+
+		if isConstraintError(err, codeForeignKey) {
+			return nil, false, fmt.Errorf("%s: %w", op, s.missingSubscriptionReference(ctx, ext, prID, userID))
+		}
+
+		return nil, false, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return &sub, true, nil
+}
+
+// missingSubscriptionReference tells an unknown user from an unknown pull request, as the foreign key
+// violations of SQLite do not name the constraint.
+func (s *Store) missingSubscriptionReference(ctx context.Context, ext sqlx.ExtContext, prID string, userID string) error {
+	var userExists bool
+	if err := sqlx.GetContext(ctx, ext, &userExists, "SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)", userID); err != nil {
+		return fmt.Errorf("failed to check user: %w", err)
+	}
+
+	if !userExists {
+		return fmt.Errorf("%w: user with id '%s'", apperrors.ErrNotFound, userID)
+	}
+
+	return fmt.Errorf("%w: pull request with id '%s'", apperrors.ErrNotFound, prID)
+}
+
+func (s *Store) GetSubscriberIDs(ctx context.Context, prID string) ([]string, error) {
+	const op = "internal.repository.sqlite.GetSubscriberIDs"
+
+	query, args, err := s.sq.Select("user_id").
+		From("pr_subscriptions").
+		Where(sq.Eq{"pull_request_id": prID}).
+		OrderBy("user_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	userIDs := []string{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &userIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return userIDs, nil
+}
+
+func (s *Store) getSubscription(ctx context.Context, ext sqlx.ExtContext, prID string, userID string) (*domain.PRSubscription, error) {
+	query, args, err := s.sq.Select("pull_request_id", "user_id", "created_at").
+		From("pr_subscriptions").
+		Where(sq.Eq{"pull_request_id": prID, "user_id": userID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build subscription query: %w", err)
+	}
+
+	var sub domain.PRSubscription
+	if err := sqlx.GetContext(ctx, ext, &sub, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	return &sub, nil
+}
@@ -0,0 +1,395 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/jmoiron/sqlx"
+)
+
+func (s *Store) CreateTeamWithUsers(ctx context.Context, team api.Team) (*domain.TeamWithMembers, error) {
+	const op = "internal.repository.sqlite.CreateTeamWithUsers"
+	log := s.log.With(slog.String("op", op), slog.String("team_name", team.TeamName))
+	log.Info("creating team with users")
+
+	var createdTeam *domain.Team
+
+	err := s.tx.Do(ctx, nil, func(ctx context.Context) error {
+		ext := txctx.Ext(ctx, s.db)
+
+		var err error
+		if createdTeam, err = s.insertTeam(ctx, ext, team.TeamName); err != nil {
+			return err
+		}
+
+		if len(team.Members) > 0 {
+			return s.upsertTeamMembers(ctx, ext, createdTeam.ID, team.Members)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	domainMembers := make([]domain.User, len(team.Members))
+	for i, member := range team.Members {
+		domainMembers[i] = domain.User{
+			ID:       member.UserId,
+			Username: member.Username,
+			TeamID:   createdTeam.ID,
+			IsActive: member.IsActive,
+		}
+	}
+
+	log.Info("team created successfully", slog.Int("team_id", createdTeam.ID))
+
+	return &domain.TeamWithMembers{
+		ID:      createdTeam.ID,
+		Name:    createdTeam.Name,
+		Members: domainMembers,
+	}, nil
+}
+
+func (s *Store) insertTeam(ctx context.Context, ext sqlx.ExtContext, teamName string) (*domain.Team, error) {
+	query, args, err := s.sq.Insert("teams").
+		Columns("name").
+		Values(teamName).
+		Suffix("RETURNING id, name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build team insert query: %w", err)
+	}
+
+	var createdTeam domain.Team
+	if err := sqlx.GetContext(ctx, ext, &createdTeam, query, args...); err != nil {
+		if isConstraintError(err, codeUnique) {
+			return nil, &apperrors.TeamAlreadyExistsError{TeamName: teamName}
+		}
+
+		return nil, fmt.Errorf("failed to execute team insert: %w", err)
+	}
+
+	return &createdTeam, nil
+}
+
+func (s *Store) upsertTeamMembers(ctx context.Context, ext sqlx.ExtContext, teamID int, members []api.TeamMember) error {
+	userIDs := make([]string, len(members))
+	for i, member := range members {
+		userIDs[i] = member.UserId
+	}
+
+	// Adding a deleted user to a team restores the user as a member of this team only.
+	deletedIDs := sq.Select("id").
+		From("users").
+		Where(sq.Eq{"id": userIDs}).
+		Where(sq.NotEq{"deleted_at": nil})
+
+	query, args, err := s.sq.Delete("user_teams").
+		Where(sq.Expr("user_id IN (?)", deletedIDs)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build memberships delete query: %w", err)
+	}
+
+	if _, err := ext.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to execute memberships delete: %w", err)
+	}
+
+	insertBuilder := s.sq.Insert("users").
+		Columns("id", "username", "team_id", "is_active")
+	membershipsBuilder := s.sq.Insert("user_teams").
+		Columns("user_id", "team_id")
+
+	for _, member := range members {
+		insertBuilder = insertBuilder.Values(member.UserId, member.Username, teamID, member.IsActive)
+		membershipsBuilder = membershipsBuilder.Values(member.UserId, teamID)
+	}
+
+	// An existing user joins the team and keeps the primary team.
+	query, args, err = insertBuilder.Suffix(`
+        ON CONFLICT (id) DO UPDATE SET
+            username = excluded.username,
+            team_id = CASE WHEN users.deleted_at IS NULL THEN COALESCE(users.team_id, excluded.team_id) ELSE excluded.team_id END,
+            is_active = excluded.is_active,
+            deleted_at = NULL`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build bulk users upsert query: %w", err)
+	}
+
+	if _, err := ext.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to execute bulk users upsert: %w", err)
+	}
+
+	query, args, err = membershipsBuilder.Suffix("ON CONFLICT DO NOTHING").ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build memberships insert query: %w", err)
+	}
+
+	if _, err := ext.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to execute memberships insert: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) GetTeamByName(ctx context.Context, name string) (*domain.TeamWithMembers, error) {
+	const op = "internal.repository.sqlite.GetTeamByName"
+	log := s.log.With(slog.String("op", op), slog.String("team_name", name))
+	log.Info("getting team by name")
+
+	team, err := s.getTeamWithMembers(ctx, sq.Eq{"name": name, "deleted_at": nil}, fmt.Sprintf("team with name '%s'", name))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("team getting successful")
+
+	return team, nil
+}
+
+func (s *Store) GetTeamByID(ctx context.Context, id int) (*domain.TeamWithMembers, error) {
+	const op = "internal.repository.sqlite.GetTeamByID"
+	log := s.log.With(slog.String("op", op), slog.Int("team_id", id))
+	log.Info("getting team by id")
+
+	team, err := s.getTeamWithMembers(ctx, sq.Eq{"id": id, "deleted_at": nil}, fmt.Sprintf("team with id %d", id))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("team getting successful")
+
+	return team, nil
+}
+
+func (s *Store) RenameTeam(ctx context.Context, id int, newName string) error {
+	const op = "internal.repository.sqlite.RenameTeam"
+	log := s.log.With(slog.String("op", op), slog.Int("team_id", id), slog.String("new_team_name", newName))
+	log.Info("renaming team")
+
+	query, args, err := s.sq.Update("teams").
+		Set("name", newName).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build team rename query: %w", err)
+	}
+
+	res, err := txctx.Ext(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		if isConstraintError(err, codeUnique) {
+			return &apperrors.TeamAlreadyExistsError{TeamName: newName}
+		}
+
+		return fmt.Errorf("failed to execute team rename: %w", err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%w: team with id %d", apperrors.ErrNotFound, id)
+	}
+
+	log.Info("team renamed successfully")
+
+	return nil
+}
+
+// memberOf matches the users that are members of the team; idColumn names the id column of users.
+func memberOf(idColumn string, teamID int) sq.Sqlizer {
+	return sq.Expr(idColumn+" IN (SELECT user_id FROM user_teams WHERE team_id = ?)", teamID)
+}
+
+// otherTeams selects the memberships of users.id in the teams, not deleted, other than the one given
+// by the placeholder.
+const otherTeams = "SELECT 1 FROM user_teams ut JOIN teams t ON t.id = ut.team_id" +
+	" WHERE ut.user_id = users.id AND ut.team_id <> ? AND t.deleted_at IS NULL"
+
+// getTeamWithMembers reads the team matching where and its members; notFound describes the team in apperrors.ErrNotFound.
+func (s *Store) getTeamWithMembers(ctx context.Context, where sq.Eq, notFound string) (*domain.TeamWithMembers, error) {
+	ext := txctx.Ext(ctx, s.db)
+
+	query, args, err := s.sq.Select("id", "name").
+		From("teams").
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select team query: %w", err)
+	}
+
+	var team domain.Team
+	if err := sqlx.GetContext(ctx, ext, &team, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", apperrors.ErrNotFound, notFound)
+		}
+
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+
+	query, args, err = s.sq.Select("id", "username", "team_id", "is_active").
+		From("users").
+		Where(memberOf("id", team.ID)).
+		Where(sq.Eq{"deleted_at": nil}).
+		OrderBy("username COLLATE " + usernameCollation).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select members query: %w", err)
+	}
+
+	var members []domain.User
+	if err := sqlx.SelectContext(ctx, ext, &members, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get team members: %w", err)
+	}
+
+	return &domain.TeamWithMembers{
+		ID:      team.ID,
+		Name:    team.Name,
+		Members: members,
+	}, nil
+}
+
+// TryLockTeamForDeactivation always succeeds in a transaction: the transaction holds the write lock of the
+// database, so no other deactivation can run until it ends.
+func (s *Store) TryLockTeamForDeactivation(ctx context.Context, _ int) (bool, error) {
+	const op = "internal.repository.sqlite.TryLockTeamForDeactivation"
+
+	if _, err := txctx.Required(ctx); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return true, nil
+}
+
+func (s *Store) DeleteTeam(ctx context.Context, teamID int, deletedAt time.Time) ([]string, error) {
+	const op = "internal.repository.sqlite.DeleteTeam"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := s.sq.Update("teams").
+		Set("deleted_at", timestamp(deletedAt)).
+		Where(sq.Eq{"id": teamID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build team update query: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute team update: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return nil, fmt.Errorf("%s: %w: team with id %d", op, apperrors.ErrNotFound, teamID)
+	}
+
+	// The members of other teams stay; the team is already deleted, so it no longer counts among their teams.
+	query, args, err = s.sq.Update("users").
+		Set("deleted_at", timestamp(deletedAt)).
+		Where(memberOf("id", teamID)).
+		Where(sq.Eq{"deleted_at": nil}).
+		Where(sq.Expr("NOT EXISTS ("+otherTeams+")", teamID)).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build members update query: %w", op, err)
+	}
+
+	deletedUserIDs := []string{}
+	if err := tx.SelectContext(ctx, &deletedUserIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute members update: %w", op, err)
+	}
+
+	slices.Sort(deletedUserIDs)
+
+	// The members staying in other teams get one of them as their primary team.
+	query, args, err = s.sq.Update("users").
+		Set("team_id", sq.Expr("(SELECT MIN(ut.team_id) FROM user_teams ut JOIN teams t ON t.id = ut.team_id"+
+			" WHERE ut.user_id = users.id AND t.deleted_at IS NULL)")).
+		Where(sq.Eq{"team_id": teamID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build primary team update query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute primary team update: %w", op, err)
+	}
+
+	return deletedUserIDs, nil
+}
+
+func (s *Store) RestoreTeam(ctx context.Context, teamID int) error {
+	const op = "internal.repository.sqlite.RestoreTeam"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := s.sq.Select("name", "deleted_at").
+		From("teams").
+		Where(sq.Eq{"id": teamID}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build team query: %w", op, err)
+	}
+
+	var deleted struct {
+		Name      string    `db:"name"`
+		DeletedAt time.Time `db:"deleted_at"`
+	}
+
+	if err := tx.GetContext(ctx, &deleted, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w: deleted team with id %d", op, apperrors.ErrNotFound, teamID)
+		}
+
+		return fmt.Errorf("%s: failed to get team: %w", op, err)
+	}
+
+	query, args, err = s.sq.Update("teams").
+		Set("deleted_at", nil).
+		Where(sq.Eq{"id": teamID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build team update query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		if isConstraintError(err, codeUnique) {
+			return &apperrors.TeamAlreadyExistsError{TeamName: deleted.Name}
+		}
+
+		return fmt.Errorf("%s: failed to execute team update: %w", op, err)
+	}
+
+	// The members deleted with the team share its deletion time; those deleted before it stay deleted.
+	query, args, err = s.sq.Update("users").
+		Set("deleted_at", nil).
+		Where(memberOf("id", teamID)).
+		Where(sq.Eq{"deleted_at": deleted.DeletedAt}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build members update query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute members update: %w", op, err)
+	}
+
+	return nil
+}
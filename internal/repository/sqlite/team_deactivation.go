@@ -0,0 +1,123 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+)
+
+func (s *Store) RecordTeamDeactivation(ctx context.Context, deactivation *domain.TeamDeactivation) error {
+	const op = "internal.repository.sqlite.RecordTeamDeactivation"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := s.sq.Insert("team_deactivations").
+		Columns("team_id", "job_id", "created_at").
+		Values(deactivation.TeamID, deactivation.JobID, timestamp(deactivation.CreatedAt)).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	if err := tx.GetContext(ctx, &deactivation.ID, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	if len(deactivation.UserIDs) == 0 {
+		return nil
+	}
+
+	insertBuilder := s.sq.Insert("team_deactivation_members").Columns("deactivation_id", "user_id")
+	for _, userID := range deactivation.UserIDs {
+		insertBuilder = insertBuilder.Values(deactivation.ID, userID)
+	}
+
+	query, args, err = insertBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build members query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to insert members: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Store) GetLastTeamDeactivation(ctx context.Context, teamID int) (*domain.TeamDeactivation, error) {
+	const op = "internal.repository.sqlite.GetLastTeamDeactivation"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := s.sq.Select("id", "team_id", "job_id", "created_at", "reactivated_at").
+		From("team_deactivations").
+		Where(sq.Eq{"team_id": teamID, "reactivated_at": nil}).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var deactivation domain.TeamDeactivation
+	if err := tx.GetContext(ctx, &deactivation, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: deactivation of team %d", op, apperrors.ErrNotFound, teamID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	query, args, err = s.sq.Select("user_id").
+		From("team_deactivation_members").
+		Where(sq.Eq{"deactivation_id": deactivation.ID}).
+		OrderBy("user_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build members query: %w", op, err)
+	}
+
+	deactivation.UserIDs = []string{}
+	if err := tx.SelectContext(ctx, &deactivation.UserIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to get members: %w", op, err)
+	}
+
+	return &deactivation, nil
+}
+
+func (s *Store) MarkTeamReactivated(ctx context.Context, id int64, reactivatedAt time.Time) error {
+	const op = "internal.repository.sqlite.MarkTeamReactivated"
+
+	query, args, err := s.sq.Update("team_deactivations").
+		Set("reactivated_at", timestamp(reactivatedAt)).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := txctx.Ext(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%s: %w: team deactivation %d", op, apperrors.ErrNotFound, id)
+	}
+
+	return nil
+}
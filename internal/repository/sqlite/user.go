@@ -0,0 +1,352 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/jmoiron/sqlx"
+)
+
+type userWithTeamName struct {
+	UserID   string `db:"user_id"`
+	Username string `db:"username"`
+	TeamID   int    `db:"team_id"`
+	TeamName string `db:"team_name"`
+	IsActive bool   `db:"is_active"`
+}
+
+func (u userWithTeamName) toAPI() *api.User {
+	return &api.User{
+		UserId:   u.UserID,
+		Username: u.Username,
+		TeamName: u.TeamName,
+		TeamId:   u.TeamID,
+		IsActive: u.IsActive,
+	}
+}
+
+// getUserWithTeamName reads a user, deleted or not, with the name of the primary team.
+func (s *Store) getUserWithTeamName(ctx context.Context, ext sqlx.ExtContext, userID string) (*userWithTeamName, error) {
+	query, args, err := s.sq.Select("u.id AS user_id", "u.username", "u.team_id", "t.name AS team_name", "u.is_active").
+		From("users u").
+		Join("teams t ON t.id = u.team_id").
+		Where(sq.Eq{"u.id": userID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build select user query: %w", err)
+	}
+
+	var user userWithTeamName
+	if err := sqlx.GetContext(ctx, ext, &user, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (s *Store) SetIsActive(ctx context.Context, userID string, isActive bool) (*api.User, bool, error) {
+	const op = "internal.repository.sqlite.SetIsActive"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log := s.log.With(slog.String("op", op))
+	log.Info("setting", slog.String("userID", userID), slog.Bool("is active", isActive))
+
+	// RETURNING only sees the updated row, so the previous status is read first.
+	query, args, err := s.sq.Select("is_active").
+		From("users").
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: failed to build select user query: %w", op, err)
+	}
+
+	var wasActive bool
+	if err := tx.GetContext(ctx, &wasActive, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, fmt.Errorf("%w: user with id '%s'", apperrors.ErrNotFound, userID)
+		}
+
+		return nil, false, fmt.Errorf("%s: failed to get user: %w", op, err)
+	}
+
+	query, args, err = s.sq.Update("users").
+		Set("is_active", isActive).
+		Where(sq.Eq{"id": userID}).
+		ToSql()
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: failed to build update user query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, false, fmt.Errorf("%s: failed to execute update user status: %w", op, err)
+	}
+
+	user, err := s.getUserWithTeamName(ctx, tx, userID)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("setting completed successfully")
+
+	return user.toAPI(), wasActive, nil
+}
+
+func (s *Store) DeactivateUsersByTeamID(ctx context.Context, teamID int) ([]string, error) {
+	const op = "internal.repository.sqlite.DeactivateUsersByTeamID"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The members of other teams keep reviewing for them.
+	query, args, err := s.sq.Update("users").
+		Set("is_active", false).
+		Where(memberOf("id", teamID)).
+		Where(sq.Eq{"is_active": true, "deleted_at": nil}).
+		Where(sq.Expr("NOT EXISTS ("+otherTeams+")", teamID)).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	var deactivatedUserIDs []string
+	if err := tx.SelectContext(ctx, &deactivatedUserIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	slices.Sort(deactivatedUserIDs)
+
+	return deactivatedUserIDs, nil
+}
+
+func (s *Store) ActivateUsers(ctx context.Context, teamID int, userIDs []string) ([]string, error) {
+	const op = "internal.repository.sqlite.ActivateUsers"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	activatedUserIDs := []string{}
+	if len(userIDs) == 0 {
+		return activatedUserIDs, nil
+	}
+
+	query, args, err := s.sq.Update("users").
+		Set("is_active", true).
+		Where(memberOf("id", teamID)).
+		Where(sq.Eq{"id": userIDs, "is_active": false, "deleted_at": nil}).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	if err := tx.SelectContext(ctx, &activatedUserIDs, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	slices.Sort(activatedUserIDs)
+
+	return activatedUserIDs, nil
+}
+
+func (s *Store) SetUserRole(ctx context.Context, userID string, role domain.UserRole) error {
+	const op = "internal.repository.sqlite.SetUserRole"
+
+	query, args, err := s.sq.Update("users").
+		Set("role", string(role)).
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := txctx.Ext(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+	}
+
+	return nil
+}
+
+func (s *Store) GetUserRole(ctx context.Context, userID string) (domain.UserRole, error) {
+	const op = "internal.repository.sqlite.GetUserRole"
+
+	query, args, err := s.sq.Select("role").
+		From("users").
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var role domain.UserRole
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &role, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+		}
+
+		return "", fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return role, nil
+}
+
+func (s *Store) TransferUser(ctx context.Context, userID string, teamID int) (*api.User, int, error) {
+	const op = "internal.repository.sqlite.TransferUser"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// As in SetIsActive, the previous team is read first.
+	query, args, err := s.sq.Select("team_id").
+		From("users").
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to build select user query: %w", op, err)
+	}
+
+	var previousTeamID int
+	if err := tx.GetContext(ctx, &previousTeamID, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, 0, fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+		}
+
+		return nil, 0, fmt.Errorf("%s: failed to get user: %w", op, err)
+	}
+
+	query, args, err = s.sq.Update("users").
+		Set("team_id", teamID).
+		Where(sq.Eq{"id": userID}).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		if isConstraintError(err, codeForeignKey) {
+			return nil, 0, fmt.Errorf("%s: %w: team with id %d", op, apperrors.ErrNotFound, teamID)
+		}
+
+		return nil, 0, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	// The user leaves the previous team for the new one and stays in the other teams.
+	query, args, err = s.sq.Delete("user_teams").
+		Where(sq.Eq{"user_id": userID, "team_id": previousTeamID}).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to build membership delete query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to execute membership delete: %w", op, err)
+	}
+
+	query, args, err = s.sq.Insert("user_teams").
+		Columns("user_id", "team_id").
+		Values(userID, teamID).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to build membership insert query: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to execute membership insert: %w", op, err)
+	}
+
+	user, err := s.getUserWithTeamName(ctx, tx, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user.toAPI(), previousTeamID, nil
+}
+
+func (s *Store) DeleteUser(ctx context.Context, userID string, deletedAt time.Time) error {
+	const op = "internal.repository.sqlite.DeleteUser"
+
+	query, args, err := s.sq.Update("users").
+		Set("deleted_at", timestamp(deletedAt)).
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := txctx.Ext(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%s: %w: user with id '%s'", op, apperrors.ErrNotFound, userID)
+	}
+
+	return nil
+}
+
+func (s *Store) RestoreUser(ctx context.Context, userID string) (*api.User, error) {
+	const op = "internal.repository.sqlite.RestoreUser"
+
+	var user *userWithTeamName
+
+	err := s.tx.Do(ctx, nil, func(ctx context.Context) error {
+		ext := txctx.Ext(ctx, s.db)
+
+		query, args, err := s.sq.Update("users").
+			Set("deleted_at", nil).
+			Where(sq.Eq{"id": userID}).
+			Where(sq.NotEq{"deleted_at": nil}).
+			Where("EXISTS (SELECT 1 FROM teams WHERE id = users.team_id AND deleted_at IS NULL)").
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("%s: failed to build update query: %w", op, err)
+		}
+
+		res, err := ext.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("%s: failed to execute update: %w", op, err)
+		}
+
+		if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+			return fmt.Errorf("%s: %w: deleted user with id '%s'", op, apperrors.ErrNotFound, userID)
+		}
+
+		if user, err = s.getUserWithTeamName(ctx, ext, userID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user.toAPI(), nil
+}
@@ -0,0 +1,402 @@
+package sqlite
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/jmoiron/sqlx"
+)
+
+var webhookReturning = "RETURNING id, url, events, secret, created_at, updated_at"
+
+var webhookDeliveryColumns = []string{
+	"id", "webhook_id", "event", "pull_request_id", "payload", "status", "attempts", "next_attempt_at",
+	"last_error", "response_status", "created_at", "updated_at",
+}
+
+var webhookDeliveryReturning = "RETURNING " + strings.Join(webhookDeliveryColumns, ", ")
+
+// eventNames are the events of a webhook as stored: a JSON array of strings.
+type eventNames []string
+
+func (e *eventNames) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), e)
+	case []byte:
+		return json.Unmarshal(v, e)
+	default:
+		return fmt.Errorf("can't scan %T into webhook events", src)
+	}
+}
+
+// webhookRow is a webhook as stored.
+type webhookRow struct {
+	ID        int64      `db:"id"`
+	URL       string     `db:"url"`
+	Events    eventNames `db:"events"`
+	Secret    string     `db:"secret"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
+
+func (r *webhookRow) toDomain() *domain.Webhook {
+	events := make([]domain.WebhookEventType, len(r.Events))
+	for i, event := range r.Events {
+		events[i] = domain.WebhookEventType(event)
+	}
+
+	return &domain.Webhook{
+		ID:        r.ID,
+		URL:       r.URL,
+		Events:    events,
+		Secret:    r.Secret,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+func webhookEvents(events []domain.WebhookEventType) (string, error) {
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = string(event)
+	}
+
+	encoded, err := json.Marshal(names)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode webhook events: %w", err)
+	}
+
+	return string(encoded), nil
+}
+
+func (s *Store) CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	const op = "internal.repository.sqlite.CreateWebhook"
+
+	events, err := webhookEvents(webhook.Events)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	createdAt := timestampOrNow(webhook.CreatedAt)
+
+	query, args, err := s.sq.Insert("webhooks").
+		Columns("url", "events", "secret", "created_at", "updated_at").
+		Values(webhook.URL, events, webhook.Secret, createdAt, createdAt).
+		Suffix(webhookReturning).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	var created webhookRow
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &created, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	return created.toDomain(), nil
+}
+
+func (s *Store) GetWebhook(ctx context.Context, id int64) (*domain.Webhook, error) {
+	const op = "internal.repository.sqlite.GetWebhook"
+
+	query, args, err := s.sq.Select("id", "url", "events", "secret", "created_at", "updated_at").
+		From("webhooks").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var row webhookRow
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: webhook %d", op, apperrors.ErrNotFound, id)
+		}
+
+		return nil, fmt.Errorf("%s: failed to get webhook: %w", op, err)
+	}
+
+	return row.toDomain(), nil
+}
+
+func (s *Store) ListWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	const op = "internal.repository.sqlite.ListWebhooks"
+
+	query, args, err := s.sq.Select("id", "url", "events", "secret", "created_at", "updated_at").
+		From("webhooks").
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	rows := []webhookRow{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to list webhooks: %w", op, err)
+	}
+
+	webhooks := make([]domain.Webhook, len(rows))
+	for i := range rows {
+		webhooks[i] = *rows[i].toDomain()
+	}
+
+	return webhooks, nil
+}
+
+func (s *Store) UpdateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	const op = "internal.repository.sqlite.UpdateWebhook"
+
+	events, err := webhookEvents(webhook.Events)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query, args, err := s.sq.Update("webhooks").
+		Set("url", webhook.URL).
+		Set("events", events).
+		Set("secret", webhook.Secret).
+		Set("updated_at", timestampOrNow(webhook.UpdatedAt)).
+		Where(sq.Eq{"id": webhook.ID}).
+		Suffix(webhookReturning).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	var updated webhookRow
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &updated, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: webhook %d", op, apperrors.ErrNotFound, webhook.ID)
+		}
+
+		return nil, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	return updated.toDomain(), nil
+}
+
+func (s *Store) DeleteWebhook(ctx context.Context, id int64) error {
+	const op = "internal.repository.sqlite.DeleteWebhook"
+
+	query, args, err := s.sq.Delete("webhooks").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build delete query: %w", op, err)
+	}
+
+	res, err := txctx.Ext(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute delete: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%s: %w: webhook %d", op, apperrors.ErrNotFound, id)
+	}
+
+	return nil
+}
+
+func (s *Store) EnqueueWebhookDeliveries(ctx context.Context, event *domain.WebhookEvent) (int, error) {
+	const op = "internal.repository.sqlite.EnqueueWebhookDeliveries"
+
+	tx, err := txctx.Required(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	occurredAt := timestamp(event.OccurredAt)
+
+	subscribed := s.sq.Select("id").
+		Column("?", event.Type).
+		Column("?", event.PullRequestID).
+		Column("?", string(event.Payload)).
+		Column("?", occurredAt).
+		Column("?", occurredAt).
+		Column("?", occurredAt).
+		From("webhooks").
+		Where("EXISTS (SELECT 1 FROM json_each(webhooks.events) WHERE value = ?)", event.Type).
+		OrderBy("id")
+
+	query, args, err := s.sq.Insert("webhook_deliveries").
+		Columns("webhook_id", "event", "pull_request_id", "payload", "next_attempt_at", "created_at", "updated_at").
+		Select(subscribed).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build insert query: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to execute insert: %w", op, err)
+	}
+
+	queued, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to count queued deliveries: %w", op, err)
+	}
+
+	return int(queued), nil
+}
+
+func (s *Store) ClaimWebhookDeliveries(ctx context.Context, limit int, now, leaseUntil time.Time) ([]domain.WebhookDelivery, error) {
+	const op = "internal.repository.sqlite.ClaimWebhookDeliveries"
+
+	// The update holds the write lock, so concurrent workers claim one batch after the other.
+	claimable := s.sq.Select("id").
+		From("webhook_deliveries").
+		Where(sq.Eq{"status": domain.WebhookDeliveryPending}).
+		Where(sq.LtOrEq{"next_attempt_at": timestamp(now)}).
+		OrderBy("next_attempt_at", "id").
+		Limit(uint64(limit))
+
+	query, args, err := s.sq.Update("webhook_deliveries").
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("next_attempt_at", timestamp(leaseUntil)).
+		Set("updated_at", timestamp(now)).
+		Where(sq.Expr("id IN (?)", claimable)).
+		Suffix(webhookDeliveryReturning).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	claimed := []domain.WebhookDelivery{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &claimed, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	// RETURNING does not keep the order of the subquery.
+	slices.SortFunc(claimed, func(a, b domain.WebhookDelivery) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return claimed, nil
+}
+
+func (s *Store) FinishWebhookAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	const op = "internal.repository.sqlite.FinishWebhookAttempt"
+
+	query, args, err := s.sq.Update("webhook_deliveries").
+		Set("status", delivery.Status).
+		Set("next_attempt_at", timestamp(delivery.NextAttemptAt)).
+		Set("last_error", delivery.LastError).
+		Set("response_status", delivery.ResponseStatus).
+		Set("updated_at", timestampOrNow(delivery.UpdatedAt)).
+		Where(sq.Eq{"id": delivery.ID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	res, err := txctx.Ext(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute update: %w", op, err)
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("%s: %w: webhook delivery %d", op, apperrors.ErrNotFound, delivery.ID)
+	}
+
+	return nil
+}
+
+func (s *Store) GetWebhookDelivery(ctx context.Context, id int64) (*domain.WebhookDelivery, error) {
+	const op = "internal.repository.sqlite.GetWebhookDelivery"
+
+	query, args, err := s.sq.Select(webhookDeliveryColumns...).
+		From("webhook_deliveries").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var d domain.WebhookDelivery
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &d, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w: webhook delivery %d", op, apperrors.ErrNotFound, id)
+		}
+
+		return nil, fmt.Errorf("%s: failed to get webhook delivery: %w", op, err)
+	}
+
+	return &d, nil
+}
+
+func (s *Store) ListWebhookDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	const op = "internal.repository.sqlite.ListWebhookDeliveries"
+
+	builder := s.sq.Select(webhookDeliveryColumns...).
+		From("webhook_deliveries").
+		OrderBy("id DESC").
+		Limit(uint64(filter.Limit))
+
+	if filter.WebhookID != 0 {
+		builder = builder.Where(sq.Eq{"webhook_id": filter.WebhookID})
+	}
+
+	if filter.Status != "" {
+		builder = builder.Where(sq.Eq{"status": filter.Status})
+	}
+
+	if filter.AfterID != 0 {
+		builder = builder.Where(sq.Lt{"id": filter.AfterID})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	deliveries := []domain.WebhookDelivery{}
+	if err := sqlx.SelectContext(ctx, txctx.Ext(ctx, s.db), &deliveries, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to list webhook deliveries: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+func (s *Store) RequeueWebhookDelivery(ctx context.Context, id int64, dueAt time.Time) (*domain.WebhookDelivery, error) {
+	const op = "internal.repository.sqlite.RequeueWebhookDelivery"
+
+	query, args, err := s.sq.Update("webhook_deliveries").
+		Set("status", domain.WebhookDeliveryPending).
+		Set("attempts", 0).
+		Set("next_attempt_at", timestamp(dueAt)).
+		Set("updated_at", timestamp(dueAt)).
+		Where(sq.Eq{"id": id, "status": domain.WebhookDeliveryDead}).
+		Suffix(webhookDeliveryReturning).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build update query: %w", op, err)
+	}
+
+	var d domain.WebhookDelivery
+	if err := sqlx.GetContext(ctx, txctx.Ext(ctx, s.db), &d, query, args...); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: failed to execute update: %w", op, err)
+		}
+
+		// Nothing was updated: the delivery either does not exist or is not dead.
+		if _, err := s.GetWebhookDelivery(ctx, id); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil, fmt.Errorf("%s: %w: webhook delivery %d", op, apperrors.ErrDeliveryNotDead, id)
+	}
+
+	return &d, nil
+}
@@ -0,0 +1,125 @@
+// Package txctx carries the database transaction of a unit of work in its context. The services run
+// their units of work through a Manager, and the repositories called with the context the unit of work
+// got run their queries in its transaction, so that neither passes *sqlx.Tx around.
+package txctx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrNoTransaction is returned by the repository methods that must run in a transaction when their
+// context carries none.
+var ErrNoTransaction = errors.New("no transaction in context")
+
+type (
+	txKey    struct{}
+	hooksKey struct{}
+)
+
+// commitHooks are the functions registered by AfterCommit to run once the transaction is committed.
+type commitHooks struct {
+	funcs []func()
+}
+
+// Beginner begins the transactions of a Manager; *sqlx.DB and *postgres.Replica are ones.
+type Beginner interface {
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+}
+
+// Manager runs units of work in transactions begun on a database.
+type Manager struct {
+	db  Beginner
+	log *slog.Logger
+}
+
+// NewManager creates a manager beginning its transactions on db.
+func NewManager(db Beginner, log *slog.Logger) *Manager {
+	return &Manager{db: db, log: log}
+}
+
+// Do runs fn in a transaction begun with opts and carried by the context passed to fn. The transaction
+// is committed if fn returns nil and rolled back otherwise. If ctx already carries a transaction,
+// fn joins it, and it is committed or rolled back with the unit of work that began it.
+// The functions registered with AfterCommit run after the commit; those of a rolled back transaction are dropped.
+func (m *Manager) Do(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	if _, ok := From(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTxx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			m.log.ErrorContext(ctx, "failed to rollback transaction", sl.Err(err))
+		}
+	}()
+
+	hooks := &commitHooks{}
+
+	if err := fn(context.WithValue(With(ctx, tx), hooksKey{}, hooks)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	for _, hook := range hooks.funcs {
+		hook()
+	}
+
+	return nil
+}
+
+// AfterCommit runs fn once the transaction begun by the Manager carried by ctx is committed, and never if it
+// is rolled back. Outside such a transaction fn runs at once, as the writes preceding it are already visible.
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(hooksKey{}).(*commitHooks)
+	if !ok {
+		fn()
+		return
+	}
+
+	hooks.funcs = append(hooks.funcs, fn)
+}
+
+// With returns a copy of ctx carrying tx.
+func With(ctx context.Context, tx *sqlx.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// From returns the transaction carried by ctx.
+func From(ctx context.Context) (*sqlx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sqlx.Tx)
+	return tx, ok
+}
+
+// Required returns the transaction carried by ctx, or ErrNoTransaction, for the queries that only make sense
+// in one, such as those taking row locks.
+func Required(ctx context.Context) (*sqlx.Tx, error) {
+	tx, ok := From(ctx)
+	if !ok {
+		return nil, ErrNoTransaction
+	}
+
+	return tx, nil
+}
+
+// Ext returns the transaction carried by ctx, or db outside a transaction.
+func Ext(ctx context.Context, db sqlx.ExtContext) sqlx.ExtContext {
+	if tx, ok := From(ctx); ok {
+		return tx
+	}
+
+	return db
+}
@@ -0,0 +1,141 @@
+package txctx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager(t *testing.T) (*Manager, sqlmock.Sqlmock) {
+	t.Helper()
+
+	mockDB, smock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	return NewManager(sqlx.NewDb(mockDB, "sqlmock"), slog.New(slog.DiscardHandler)), smock
+}
+
+func TestManager_Do_CommitsOnSuccess(t *testing.T) {
+	manager, smock := newTestManager(t)
+	smock.ExpectBegin()
+	smock.ExpectCommit()
+
+	err := manager.Do(context.Background(), nil, func(ctx context.Context) error {
+		tx, err := Required(ctx)
+		require.NoError(t, err)
+		assert.NotNil(t, tx)
+
+		return nil
+	})
+	require.NoError(t, err)
+	assert.NoError(t, smock.ExpectationsWereMet())
+}
+
+func TestManager_Do_RollsBackOnError(t *testing.T) {
+	manager, smock := newTestManager(t)
+	smock.ExpectBegin()
+	smock.ExpectRollback()
+
+	errFailed := errors.New("failed")
+
+	err := manager.Do(context.Background(), nil, func(context.Context) error { return errFailed })
+	assert.ErrorIs(t, err, errFailed)
+	assert.NoError(t, smock.ExpectationsWereMet())
+}
+
+func TestManager_Do_JoinsTransactionInContext(t *testing.T) {
+	manager, smock := newTestManager(t)
+	smock.ExpectBegin()
+	smock.ExpectCommit()
+
+	err := manager.Do(context.Background(), nil, func(ctx context.Context) error {
+		outer, _ := From(ctx)
+
+		return manager.Do(ctx, nil, func(ctx context.Context) error {
+			inner, _ := From(ctx)
+			assert.Same(t, outer, inner)
+
+			return nil
+		})
+	})
+	require.NoError(t, err)
+	assert.NoError(t, smock.ExpectationsWereMet(), "the inner unit of work begins no transaction of its own")
+}
+
+func TestAfterCommit(t *testing.T) {
+	manager, smock := newTestManager(t)
+	smock.ExpectBegin()
+	smock.ExpectCommit()
+	smock.ExpectBegin()
+	smock.ExpectRollback()
+
+	var calls []string
+
+	err := manager.Do(context.Background(), nil, func(ctx context.Context) error {
+		AfterCommit(ctx, func() { calls = append(calls, "outer") })
+
+		err := manager.Do(ctx, nil, func(ctx context.Context) error {
+			AfterCommit(ctx, func() { calls = append(calls, "joined") })
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Empty(t, calls, "the hooks wait for the commit")
+
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outer", "joined"}, calls)
+
+	errFailed := errors.New("failed")
+
+	err = manager.Do(context.Background(), nil, func(ctx context.Context) error {
+		AfterCommit(ctx, func() { calls = append(calls, "rolled back") })
+		return errFailed
+	})
+	require.ErrorIs(t, err, errFailed)
+	assert.Equal(t, []string{"outer", "joined"}, calls)
+
+	AfterCommit(context.Background(), func() { calls = append(calls, "no transaction") })
+	assert.Equal(t, []string{"outer", "joined", "no transaction"}, calls)
+	assert.NoError(t, smock.ExpectationsWereMet())
+}
+
+func TestManager_Do_BeginFails(t *testing.T) {
+	manager, smock := newTestManager(t)
+	smock.ExpectBegin().WillReturnError(errors.New("connection refused"))
+
+	called := false
+	err := manager.Do(context.Background(), nil, func(context.Context) error {
+		called = true
+		return nil
+	})
+	require.ErrorContains(t, err, "failed to begin transaction")
+	assert.False(t, called)
+}
+
+func TestRequired_WithoutTransaction(t *testing.T) {
+	_, err := Required(context.Background())
+	assert.ErrorIs(t, err, ErrNoTransaction)
+}
+
+func TestExt(t *testing.T) {
+	manager, smock := newTestManager(t)
+	smock.ExpectBegin()
+	smock.ExpectRollback()
+
+	db := manager.db.(*sqlx.DB)
+	assert.Same(t, db, Ext(context.Background(), db))
+
+	tx, err := db.Beginx()
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+
+	assert.Same(t, tx, Ext(With(context.Background(), tx), db))
+}
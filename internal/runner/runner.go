@@ -0,0 +1,87 @@
+// package runner runs the long-running jobs created through POST /jobs.
+// A fixed number of workers bounds how many jobs run at once; each worker owns the job it runs
+// through a lease, so that the job of a worker that died is taken over by another one.
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// JobRunner is the part of service.JobService the runner drives.
+type JobRunner interface {
+	RunNextJob(ctx context.Context, owner string) (bool, error)
+}
+
+// Runner polls for jobs and runs them with a pool of workers.
+type Runner struct {
+	log      *slog.Logger
+	jobs     JobRunner
+	interval time.Duration
+	workers  int
+	// instance identifies the process in the owner of the jobs its workers run.
+	instance string
+}
+
+func New(log *slog.Logger, jobs JobRunner, interval time.Duration, workers int) *Runner {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return &Runner{
+		log:      log.With(slog.String("component", "runner")),
+		jobs:     jobs,
+		interval: interval,
+		workers:  workers,
+		instance: fmt.Sprintf("%s-%d", host, os.Getpid()),
+	}
+}
+
+// Run starts the workers and blocks until ctx is cancelled and every worker has stopped.
+// Each worker polls on its own, so a long job does not hold up the other workers.
+func (r *Runner) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for i := range r.workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			r.work(ctx, fmt.Sprintf("%s-%d", r.instance, i+1))
+		}()
+	}
+
+	wg.Wait()
+}
+
+// work runs jobs one after another and waits for the next interval whenever none is waiting.
+func (r *Runner) work(ctx context.Context, owner string) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			ran, err := r.jobs.RunNextJob(ctx, owner)
+			if err != nil && ctx.Err() == nil {
+				r.log.Error("failed to run job", slog.String("owner", owner), sl.Err(err))
+			}
+
+			if !ran || err != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeJobs hands out a fixed number of jobs and fails every fourth call.
+type fakeJobs struct {
+	pending atomic.Int32
+	calls   atomic.Int32
+
+	running    atomic.Int32
+	maxRunning atomic.Int32
+
+	mu     sync.Mutex
+	owners map[string]bool
+}
+
+func (f *fakeJobs) RunNextJob(_ context.Context, owner string) (bool, error) {
+	running := f.running.Add(1)
+	defer f.running.Add(-1)
+
+	for {
+		peak := f.maxRunning.Load()
+		if running <= peak || f.maxRunning.CompareAndSwap(peak, running) {
+			break
+		}
+	}
+
+	f.mu.Lock()
+	f.owners[owner] = true
+	f.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	if f.calls.Add(1)%4 == 0 {
+		return false, errors.New("db is down")
+	}
+
+	if f.pending.Add(-1) < 0 {
+		f.pending.Store(0)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func TestRunner_RunsJobsWithBoundedWorkers(t *testing.T) {
+	jobs := &fakeJobs{owners: make(map[string]bool)}
+	jobs.pending.Store(20)
+
+	r := New(slog.New(slog.NewTextHandler(io.Discard, nil)), jobs, time.Millisecond, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+
+	// Failed calls are logged and retried on a later poll.
+	assert.Eventually(t, func() bool { return jobs.pending.Load() == 0 }, time.Second, time.Millisecond)
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("runner did not stop after cancellation")
+	}
+
+	assert.LessOrEqual(t, jobs.maxRunning.Load(), int32(3))
+
+	jobs.mu.Lock()
+	defer jobs.mu.Unlock()
+	assert.Len(t, jobs.owners, 3, "every worker must run jobs under an owner of its own")
+}
@@ -0,0 +1,19 @@
+package sampler
+
+import (
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Values of the quantile label of the open_pull_request_age_seconds metric; the maximum is quantile 1.
+const (
+	quantileP50 = "0.5"
+	quantileP90 = "0.9"
+	quantileMax = "1"
+)
+
+var (
+	openPRs = promauto.NewGaugeVec(metrics.OpenPullRequests.GaugeOpts(), metrics.OpenPullRequests.Labels)
+
+	openPRAge = promauto.NewGaugeVec(metrics.OpenPullRequestAge.GaugeOpts(), metrics.OpenPullRequestAge.Labels)
+)
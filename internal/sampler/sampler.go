@@ -0,0 +1,86 @@
+// package sampler periodically exports metrics that describe the stored data rather than the requests served,
+// such as the age of open pull requests. Computing them on a schedule keeps the cost independent of the
+// scrape rate and lets alerts watch the review backlog without calling the stats API.
+package sampler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// OpenPRAgeSource is the part of service.PRQueryService the sampler reads.
+type OpenPRAgeSource interface {
+	GetOpenPRAgeStats(ctx context.Context) ([]domain.OpenPRAgeStats, error)
+}
+
+// Sampler refreshes the open pull request gauges periodically.
+type Sampler struct {
+	log      *slog.Logger
+	prs      OpenPRAgeSource
+	interval time.Duration
+
+	// teams holds the teams exported by the previous sample, so that teams
+	// without open pull requests are dropped from the gauges.
+	teams map[string]bool
+}
+
+func New(log *slog.Logger, prs OpenPRAgeSource, interval time.Duration) *Sampler {
+	return &Sampler{
+		log:      log.With(slog.String("component", "sampler")),
+		prs:      prs,
+		interval: interval,
+		teams:    make(map[string]bool),
+	}
+}
+
+// Run takes a sample right away and then once per interval until ctx is cancelled.
+func (s *Sampler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.sample(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Sampler) sample(ctx context.Context) {
+	stats, err := s.prs.GetOpenPRAgeStats(ctx)
+	if err != nil {
+		// The gauges keep the previous sample until a query succeeds.
+		if ctx.Err() == nil {
+			s.log.Error("failed to sample open pull request ages", sl.Err(err))
+		}
+
+		return
+	}
+
+	current := make(map[string]bool, len(stats))
+
+	for _, team := range stats {
+		current[team.TeamName] = true
+
+		openPRs.WithLabelValues(team.TeamName).Set(float64(team.OpenPRs))
+		openPRAge.WithLabelValues(team.TeamName, quantileP50).Set(team.P50Seconds)
+		openPRAge.WithLabelValues(team.TeamName, quantileP90).Set(team.P90Seconds)
+		openPRAge.WithLabelValues(team.TeamName, quantileMax).Set(team.MaxSeconds)
+	}
+
+	for team := range s.teams {
+		if !current[team] {
+			openPRs.DeleteLabelValues(team)
+			openPRAge.DeletePartialMatch(map[string]string{"team": team})
+		}
+	}
+
+	s.teams = current
+}
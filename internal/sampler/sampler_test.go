@@ -0,0 +1,52 @@
+package sampler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSource struct {
+	stats []domain.OpenPRAgeStats
+	err   error
+}
+
+func (f *fakeSource) GetOpenPRAgeStats(_ context.Context) ([]domain.OpenPRAgeStats, error) {
+	return f.stats, f.err
+}
+
+func TestSampler_Sample(t *testing.T) {
+	openPRs.Reset()
+	openPRAge.Reset()
+
+	source := &fakeSource{stats: []domain.OpenPRAgeStats{
+		{TeamName: "backend", OpenPRs: 3, P50Seconds: 60, P90Seconds: 600, MaxSeconds: 900},
+		{TeamName: "frontend", OpenPRs: 1, P50Seconds: 30, P90Seconds: 30, MaxSeconds: 30},
+	}}
+	s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), source, time.Minute)
+
+	s.sample(context.Background())
+
+	assert.Equal(t, float64(3), testutil.ToFloat64(openPRs.WithLabelValues("backend")))
+	assert.Equal(t, float64(600), testutil.ToFloat64(openPRAge.WithLabelValues("backend", quantileP90)))
+	assert.Equal(t, float64(900), testutil.ToFloat64(openPRAge.WithLabelValues("backend", quantileMax)))
+	assert.Equal(t, 6, testutil.CollectAndCount(openPRAge))
+
+	// A failed query keeps the previous sample.
+	source.err = errors.New("db is down")
+	s.sample(context.Background())
+	assert.Equal(t, 2, testutil.CollectAndCount(openPRs))
+
+	// A team whose pull requests were all merged disappears from the gauges.
+	source.stats, source.err = source.stats[:1], nil
+	s.sample(context.Background())
+	assert.Equal(t, 1, testutil.CollectAndCount(openPRs))
+	assert.Equal(t, 3, testutil.CollectAndCount(openPRAge))
+}
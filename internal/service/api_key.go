@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// apiKeyPrefix marks the stored API keys, so that a leaked key is easy to recognize and is not looked up
+// as a read token.
+const apiKeyPrefix = "prk_"
+
+// APIKeyService manages the API keys stored in the database. The static keys of AUTH_API_KEYS are checked
+// by the HTTP server itself.
+type APIKeyService interface {
+	// CreateAPIKey issues a key opening the operations of scopes; the value is returned only here and is not stored.
+	// A key bound to userID acts for the user and opens only the operations its role allows.
+	// Returns apperrors.ErrValidation if the name is blank, the scopes are empty or unknown,
+	// or expiresAt is not in the future, and apperrors.ErrNotFound if the user does not exist.
+	CreateAPIKey(ctx context.Context, name string, scopes []string, expiresAt *time.Time, userID *string) (*api.ApiKeyCreatedResponse, error)
+	// ListAPIKeys returns every stored key in creation order, without the values.
+	ListAPIKeys(ctx context.Context) (*api.ListApiKeysResponse, error)
+	// DeleteAPIKey revokes a key.
+	DeleteAPIKey(ctx context.Context, id int64) error
+	// AuthenticateAPIKey returns the key with the given value and records its use.
+	// Returns apperrors.ErrInvalidToken if there is no such key or it has expired.
+	AuthenticateAPIKey(ctx context.Context, key string) (*domain.APIKey, error)
+}
+
+type APIKeyServiceImpl struct {
+	BaseService
+	repo repository.APIKeyRepository
+}
+
+// APIKeyServiceOption configures optional behaviour of APIKeyServiceImpl.
+type APIKeyServiceOption func(*APIKeyServiceImpl)
+
+// WithAPIKeyClock makes the service take the current time from c instead of the system clock.
+func WithAPIKeyClock(c Clock) APIKeyServiceOption {
+	return func(s *APIKeyServiceImpl) {
+		s.clock = c
+	}
+}
+
+// NewAPIKeyService creates a new instance of APIKeyServiceImpl.
+func NewAPIKeyService(repo repository.APIKeyRepository, log *slog.Logger, opts ...APIKeyServiceOption) *APIKeyServiceImpl {
+	s := &APIKeyServiceImpl{
+		BaseService: NewBaseService(nil, log),
+		repo:        repo,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *APIKeyServiceImpl) CreateAPIKey(ctx context.Context, name string, scopes []string, expiresAt *time.Time, userID *string) (*api.ApiKeyCreatedResponse, error) {
+	const op = "internal.service.api_key.CreateAPIKey"
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name must not be blank", apperrors.ErrValidation)
+	}
+
+	keyScopes, err := parseAPIKeyScopes(scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if expiresAt != nil {
+		if !expiresAt.After(now) {
+			return nil, fmt.Errorf("%w: expires_at must be in the future", apperrors.ErrValidation)
+		}
+
+		utc := expiresAt.UTC()
+		expiresAt = &utc
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("%s: failed to generate key: %w", op, err)
+	}
+
+	value := apiKeyPrefix + hex.EncodeToString(secret)
+
+	created, err := s.repo.CreateAPIKey(ctx, &domain.APIKey{
+		Name:      name,
+		KeyHash:   hashReadToken(value),
+		Scopes:    keyScopes,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+		UserID:    userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to create api key: %w", op, err)
+	}
+
+	s.log.Info("api key created", slog.String("op", op), slog.Int64("key_id", created.ID), slog.String("name", name),
+		slog.Any("scopes", scopes))
+
+	return &api.ApiKeyCreatedResponse{ApiKey: toAPIKey(created), Key: value}, nil
+}
+
+func (s *APIKeyServiceImpl) ListAPIKeys(ctx context.Context) (*api.ListApiKeysResponse, error) {
+	const op = "internal.service.api_key.ListAPIKeys"
+
+	keys, err := s.repo.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list api keys: %w", op, err)
+	}
+
+	items := make([]api.ApiKey, len(keys))
+	for i := range keys {
+		items[i] = toAPIKey(&keys[i])
+	}
+
+	return &api.ListApiKeysResponse{Items: items, TotalEstimate: totalOf(items)}, nil
+}
+
+func (s *APIKeyServiceImpl) DeleteAPIKey(ctx context.Context, id int64) error {
+	const op = "internal.service.api_key.DeleteAPIKey"
+
+	if err := s.repo.DeleteAPIKey(ctx, id); err != nil {
+		return fmt.Errorf("%s: failed to delete api key: %w", op, err)
+	}
+
+	s.log.Info("api key deleted", slog.String("op", op), slog.Int64("key_id", id))
+
+	return nil
+}
+
+func (s *APIKeyServiceImpl) AuthenticateAPIKey(ctx context.Context, value string) (*domain.APIKey, error) {
+	const op = "internal.service.api_key.AuthenticateAPIKey"
+
+	if !strings.HasPrefix(value, apiKeyPrefix) {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	key, err := s.repo.GetAPIKeyByHash(ctx, hashReadToken(value))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.ErrInvalidToken
+		}
+
+		return nil, fmt.Errorf("%s: failed to get api key: %w", op, err)
+	}
+
+	now := s.now()
+	if key.ExpiresAt != nil && !now.Before(*key.ExpiresAt) {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= readTokenTouchInterval {
+		// As with the read tokens, a failure to record the use does not reject the request.
+		if err := s.repo.TouchAPIKey(ctx, key.ID, now); err != nil {
+			s.log.Warn("failed to record api key use", slog.String("op", op), slog.Int64("key_id", key.ID), sl.Err(err))
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+
+	return key, nil
+}
+
+// parseAPIKeyScopes converts the names of API key scopes, dropping repeated ones.
+// Returns apperrors.ErrValidation if there are none or a name is unknown.
+func parseAPIKeyScopes(names []string) ([]domain.APIKeyScope, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", apperrors.ErrValidation)
+	}
+
+	scopes := make([]domain.APIKeyScope, 0, len(names))
+	for _, name := range names {
+		scope := domain.APIKeyScope(strings.TrimSpace(name))
+		if !scope.IsValid() {
+			return nil, fmt.Errorf("%w: unknown scope '%s'", apperrors.ErrValidation, name)
+		}
+
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes, nil
+}
+
+func toAPIKey(k *domain.APIKey) api.ApiKey {
+	scopes := make([]api.ApiKeyScope, len(k.Scopes))
+	for i, scope := range k.Scopes {
+		scopes[i] = api.ApiKeyScope(scope)
+	}
+
+	return api.ApiKey{
+		KeyId:      k.ID,
+		Name:       k.Name,
+		Scopes:     scopes,
+		CreatedAt:  k.CreatedAt,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		UserId:     k.UserID,
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyServiceImpl_CreateAPIKey(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	t.Run("Only the hash is stored", func(t *testing.T) {
+		var stored *domain.APIKey
+
+		repo := new(APIKeyRepositoryMock)
+		repo.On("CreateAPIKey", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.APIKey)
+		}).Return(&domain.APIKey{
+			ID: 4, Name: "ci", Scopes: []domain.APIKeyScope{domain.ScopeRead, domain.ScopeWrite}, CreatedAt: testNow,
+		}, nil).Once()
+
+		created, err := NewAPIKeyService(repo, logger, WithAPIKeyClock(fixedClock(testNow))).
+			CreateAPIKey(ctx, " ci ", []string{"read", "write", "read"}, nil, nil)
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(created.Key, apiKeyPrefix))
+		assert.Equal(t, int64(4), created.ApiKey.KeyId)
+		assert.Equal(t, []api.ApiKeyScope{api.ApiKeyScopeRead, api.ApiKeyScopeWrite}, created.ApiKey.Scopes)
+		require.NotNil(t, stored)
+		assert.Equal(t, "ci", stored.Name)
+		assert.Equal(t, []domain.APIKeyScope{domain.ScopeRead, domain.ScopeWrite}, stored.Scopes, "repeated scopes are dropped")
+		assert.Equal(t, testNow.UTC(), stored.CreatedAt)
+		assert.Equal(t, hashReadToken(created.Key), stored.KeyHash)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("The key is bound to the user", func(t *testing.T) {
+		userID := "u1"
+
+		repo := new(APIKeyRepositoryMock)
+		repo.On("CreateAPIKey", ctx, mock.MatchedBy(func(key *domain.APIKey) bool {
+			return key.UserID != nil && *key.UserID == userID
+		})).Return(&domain.APIKey{
+			ID: 5, Name: "alice", Scopes: []domain.APIKeyScope{domain.ScopeRead}, UserID: &userID, CreatedAt: testNow,
+		}, nil).Once()
+
+		created, err := NewAPIKeyService(repo, logger, WithAPIKeyClock(fixedClock(testNow))).
+			CreateAPIKey(ctx, "alice", []string{"read"}, nil, &userID)
+
+		require.NoError(t, err)
+		assert.Equal(t, &userID, created.ApiKey.UserId)
+		repo.AssertExpectations(t)
+	})
+
+	past := testNow.Add(-time.Minute)
+
+	testCases := []struct {
+		name      string
+		keyName   string
+		scopes    []string
+		expiresAt *time.Time
+	}{
+		{name: "Blank name", keyName: "  ", scopes: []string{"read"}},
+		{name: "No scopes", keyName: "ci"},
+		{name: "Unknown scope", keyName: "ci", scopes: []string{"read", "deploy"}},
+		{name: "Expiry in the past", keyName: "ci", scopes: []string{"read"}, expiresAt: &past},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewAPIKeyService(new(APIKeyRepositoryMock), logger, WithAPIKeyClock(fixedClock(testNow))).
+				CreateAPIKey(ctx, tc.keyName, tc.scopes, tc.expiresAt, nil)
+
+			assert.ErrorIs(t, err, apperrors.ErrValidation)
+		})
+	}
+}
+
+func TestAPIKeyServiceImpl_AuthenticateAPIKey(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	const value = apiKeyPrefix + "0123456789abcdef"
+	now := testNow.UTC()
+
+	recently := testNow.Add(-10 * time.Second)
+	expired := testNow.Add(-time.Second)
+
+	t.Run("First use is recorded", func(t *testing.T) {
+		repo := new(APIKeyRepositoryMock)
+		repo.On("GetAPIKeyByHash", ctx, hashReadToken(value)).
+			Return(&domain.APIKey{ID: 1, Scopes: []domain.APIKeyScope{domain.ScopeRead}}, nil).Once()
+		repo.On("TouchAPIKey", ctx, int64(1), now).Return(nil).Once()
+
+		key, err := NewAPIKeyService(repo, logger, WithAPIKeyClock(fixedClock(testNow))).AuthenticateAPIKey(ctx, value)
+
+		require.NoError(t, err)
+		assert.True(t, key.HasScope(domain.ScopeRead))
+		require.NotNil(t, key.LastUsedAt)
+		assert.Equal(t, now, *key.LastUsedAt)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Recent use is not written again", func(t *testing.T) {
+		repo := new(APIKeyRepositoryMock)
+		repo.On("GetAPIKeyByHash", ctx, hashReadToken(value)).Return(&domain.APIKey{ID: 1, LastUsedAt: &recently}, nil).Once()
+
+		_, err := NewAPIKeyService(repo, logger, WithAPIKeyClock(fixedClock(testNow))).AuthenticateAPIKey(ctx, value)
+
+		require.NoError(t, err)
+		repo.AssertNotCalled(t, "TouchAPIKey", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		repo := new(APIKeyRepositoryMock)
+		repo.On("GetAPIKeyByHash", ctx, hashReadToken(value)).Return(&domain.APIKey{ID: 1, ExpiresAt: &expired}, nil).Once()
+
+		_, err := NewAPIKeyService(repo, logger, WithAPIKeyClock(fixedClock(testNow))).AuthenticateAPIKey(ctx, value)
+
+		assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		repo := new(APIKeyRepositoryMock)
+		repo.On("GetAPIKeyByHash", ctx, hashReadToken(value)).Return(nil, apperrors.ErrNotFound).Once()
+
+		_, err := NewAPIKeyService(repo, logger).AuthenticateAPIKey(ctx, value)
+
+		assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+	})
+
+	t.Run("Read token is not looked up", func(t *testing.T) {
+		_, err := NewAPIKeyService(new(APIKeyRepositoryMock), logger).AuthenticateAPIKey(ctx, readTokenPrefix+"0123456789abcdef")
+
+		assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+	})
+}
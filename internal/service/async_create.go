@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// maxCreatePRAttempts is how many times a request failing on an unexpected error is claimed before it fails for good.
+const maxCreatePRAttempts = 3
+
+// maxCreatePRClaim caps the number of requests claimed at once.
+const maxCreatePRClaim = 100
+
+// Codes of the error of a failed asynchronous creation that have no counterpart among the API error codes.
+const (
+	asyncCreateValidationFailed = "VALIDATION_FAILED"
+	asyncCreateInternalError    = "INTERNAL_ERROR"
+)
+
+func (s *PullRequestServiceImpl) EnqueueCreatePR(ctx context.Context, prID string, prName string, authorID string, details PRDetails) (*api.AsyncCreateRequest, error) {
+	const op = "internal.service.pullrequest.EnqueueCreatePR"
+
+	if s.createRequests == nil {
+		return nil, fmt.Errorf("%w: asynchronous pull request creation is disabled", apperrors.ErrValidation)
+	}
+
+	// The time is checked again, against the clock of the moment, when the request is processed.
+	if _, err := s.assignmentTime(details.AssignAt); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The values are checked against the fields of the team when the request is processed.
+	var customFields []byte
+
+	if len(details.CustomFields) > 0 {
+		var err error
+
+		customFields, err = json.Marshal(details.CustomFields)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to encode custom fields: %w", op, err)
+		}
+	}
+
+	req, err := s.createRequests.EnqueueCreatePR(ctx, &domain.CreatePRRequest{
+		PullRequestID:   prID,
+		PullRequestName: prName,
+		AuthorID:        authorID,
+		Description:     details.Description,
+		ExternalURL:     details.ExternalURL,
+		CustomFields:    customFields,
+		AssignAt:        details.AssignAt,
+		EnqueuedAt:      s.now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to enqueue request: %w", op, err)
+	}
+
+	s.log.Info("pr creation queued", slog.String("op", op), slog.Int64("request_id", req.ID), slog.String("pr_id", prID))
+
+	return toAPIAsyncCreateRequest(req), nil
+}
+
+func (s *PullRequestServiceImpl) GetCreatePRRequest(ctx context.Context, requestID int64) (*api.AsyncCreateRequest, error) {
+	const op = "internal.service.pullrequest.GetCreatePRRequest"
+
+	if s.createRequests == nil {
+		return nil, fmt.Errorf("%w: pull request creation request %d", apperrors.ErrNotFound, requestID)
+	}
+
+	req, err := s.createRequests.GetCreatePRRequest(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get request: %w", op, err)
+	}
+
+	resp := toAPIAsyncCreateRequest(req)
+
+	if req.Status == domain.CreatePRSucceeded {
+		resp.Pr, err = s.GetPR(ctx, req.PullRequestID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *PullRequestServiceImpl) ClaimCreatePRRequests(ctx context.Context, limit int) ([]domain.CreatePRRequest, error) {
+	const op = "internal.service.pullrequest.ClaimCreatePRRequests"
+
+	if s.createRequests == nil {
+		return nil, nil
+	}
+
+	if limit < 1 || limit > maxCreatePRClaim {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", apperrors.ErrValidation, maxCreatePRClaim)
+	}
+
+	now := s.now()
+
+	claimed, err := s.createRequests.ClaimCreatePRRequests(ctx, limit, now, now.Add(-s.createLease))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to claim requests: %w", op, err)
+	}
+
+	return claimed, nil
+}
+
+func (s *PullRequestServiceImpl) ProcessCreatePRRequest(ctx context.Context, req domain.CreatePRRequest) error {
+	const op = "internal.service.pullrequest.ProcessCreatePRRequest"
+	log := s.log.With(slog.String("op", op), slog.Int64("request_id", req.ID), slog.String("pr_id", req.PullRequestID))
+
+	details := PRDetails{Description: req.Description, ExternalURL: req.ExternalURL, AssignAt: req.AssignAt}
+
+	// Values that cannot be decoded fail the request like values that do not match the fields of the team.
+	customFields, err := decodeCustomFields(req.CustomFields)
+	if err != nil {
+		err = fmt.Errorf("%w: %v", apperrors.ErrValidation, err)
+	} else {
+		details.CustomFields = customFields
+		_, _, err = s.CreatePR(ctx, req.PullRequestID, req.PullRequestName, req.AuthorID, details)
+	}
+
+	if err != nil && req.Attempts > 1 && errors.Is(err, apperrors.ErrAlreadyExists) {
+		// An earlier attempt may have created the PR and died before recording it.
+		_, _, err = s.existingPR(ctx, req.PullRequestID, req.AuthorID, err)
+	}
+
+	if err != nil && ctx.Err() != nil {
+		// The request stays claimed and is picked up again once its lease expires.
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	finishedAt := s.now()
+	req.Status, req.ErrorCode, req.ErrorMessage, req.FinishedAt = domain.CreatePRSucceeded, nil, nil, &finishedAt
+
+	if err != nil {
+		code, permanent := asyncCreateErrorCode(err)
+		message := err.Error()
+		req.Status, req.ErrorCode, req.ErrorMessage = domain.CreatePRFailed, &code, &message
+
+		if !permanent && req.Attempts < maxCreatePRAttempts {
+			req.Status, req.FinishedAt = domain.CreatePRQueued, nil
+		}
+
+		log.Warn("asynchronous pr creation failed", slog.String("status", string(req.Status)),
+			slog.Int("attempts", req.Attempts), slog.String("code", code), slog.String("error", message))
+	}
+
+	if err := s.createRequests.FinishCreatePRRequest(ctx, &req); err != nil {
+		return fmt.Errorf("%s: failed to record outcome: %w", op, err)
+	}
+
+	return nil
+}
+
+// asyncCreateErrorCode returns the code stored for a failed creation and whether retrying cannot help.
+func asyncCreateErrorCode(err error) (string, bool) {
+	var (
+		quotaErr    *apperrors.AuthorQuotaExceededError
+		inactiveErr *apperrors.AuthorInactiveError
+		teamErr     *apperrors.TeamTooSmallError
+	)
+
+	switch {
+	case errors.Is(err, apperrors.ErrAlreadyExists):
+		return string(api.PREXISTS), true
+	case errors.Is(err, apperrors.ErrNotFound):
+		return string(api.NOTFOUND), true
+	case errors.As(err, &quotaErr):
+		return string(api.AUTHORQUOTAEXCEEDED), true
+	case errors.As(err, &inactiveErr):
+		return string(api.AUTHORINACTIVE), true
+	case errors.As(err, &teamErr):
+		return string(api.TEAMTOOSMALL), true
+	case errors.Is(err, apperrors.ErrValidation):
+		return asyncCreateValidationFailed, true
+	default:
+		return asyncCreateInternalError, false
+	}
+}
+
+func toAPIAsyncCreateRequest(req *domain.CreatePRRequest) *api.AsyncCreateRequest {
+	resp := &api.AsyncCreateRequest{
+		RequestId:     req.ID,
+		PullRequestId: req.PullRequestID,
+		Status:        api.AsyncCreateRequestStatus(req.Status),
+		Attempts:      req.Attempts,
+		EnqueuedAt:    req.EnqueuedAt,
+		StartedAt:     req.StartedAt,
+		FinishedAt:    req.FinishedAt,
+	}
+
+	if req.ErrorCode != nil {
+		resp.Error = &api.AsyncCreateError{Code: *req.ErrorCode}
+		if req.ErrorMessage != nil {
+			resp.Error.Message = *req.ErrorMessage
+		}
+	}
+
+	return resp
+}
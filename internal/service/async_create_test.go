@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestServiceImpl_ProcessCreatePRRequest(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	// duplicate makes the creation collide with an existing PR of the given author.
+	duplicate := func(author string) func(*TransactorMock, *PRCommandRepositoryMock, *PRQueryRepositoryMock, *UserPRRepositoryMock) {
+		return func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock) {
+			_, mockedTx, smock := newMockDBAndTx(t)
+			smock.ExpectRollback()
+
+			transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+			userPR.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
+			userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{"rev-1"}, nil).Once()
+			prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"rev-1"}).Return([]string{"rev-1"}, nil).Once()
+			prCmd.On("CreatePR", inTx(mockedTx), mock.Anything).Return(&apperrors.PRAlreadyExistsError{PRID: "pr-1"}).Once()
+			prQuery.On("GetPRByIDWithReviewers", ctx, "pr-1").Return(&domain.PullRequest{ID: "pr-1", AuthorID: author}, nil).Maybe()
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		attempts       int
+		setupMocks     func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock)
+		expectedStatus domain.CreatePRState
+		expectedCode   string
+	}{
+		{
+			name:     "Created PR succeeds",
+			attempts: 1,
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock) {
+				_, mockedTx, smock := newMockDBAndTx(t)
+				smock.ExpectCommit()
+
+				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+				userPR.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{}, nil).Once()
+				prCmd.On("CreatePR", inTx(mockedTx), mock.Anything).Return(nil).Once()
+			},
+			expectedStatus: domain.CreatePRSucceeded,
+		},
+		{
+			name:     "Unknown author fails for good",
+			attempts: 1,
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock) {
+				userPR.On("GetAuthorTeamID", ctx, "author-1").Return(0, apperrors.ErrNotFound).Once()
+			},
+			expectedStatus: domain.CreatePRFailed,
+			expectedCode:   string(api.NOTFOUND),
+		},
+		{
+			name:     "Unexpected error is retried",
+			attempts: 1,
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock) {
+				userPR.On("GetAuthorTeamID", ctx, "author-1").Return(0, errors.New("connection reset")).Once()
+			},
+			expectedStatus: domain.CreatePRQueued,
+			expectedCode:   asyncCreateInternalError,
+		},
+		{
+			name:     "Unexpected error on the last attempt fails",
+			attempts: maxCreatePRAttempts,
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock) {
+				userPR.On("GetAuthorTeamID", ctx, "author-1").Return(0, errors.New("connection reset")).Once()
+			},
+			expectedStatus: domain.CreatePRFailed,
+			expectedCode:   asyncCreateInternalError,
+		},
+		{
+			name:           "Existing PR on the first attempt fails",
+			attempts:       1,
+			setupMocks:     duplicate("author-1"),
+			expectedStatus: domain.CreatePRFailed,
+			expectedCode:   string(api.PREXISTS),
+		},
+		{
+			name:           "PR created by an earlier attempt succeeds",
+			attempts:       2,
+			setupMocks:     duplicate("author-1"),
+			expectedStatus: domain.CreatePRSucceeded,
+		},
+		{
+			name:           "PR of another author on a retry fails",
+			attempts:       2,
+			setupMocks:     duplicate("author-2"),
+			expectedStatus: domain.CreatePRFailed,
+			expectedCode:   string(api.PREXISTS),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transactorMock := new(TransactorMock)
+			prCmdMock := new(PRCommandRepositoryMock)
+			prQueryMock := new(PRQueryRepositoryMock)
+			userPRMock := new(UserPRRepositoryMock)
+			historyMock := new(AssignmentHistoryRepositoryMock)
+			requestsMock := new(CreatePRRequestRepositoryMock)
+			tc.setupMocks(transactorMock, prCmdMock, prQueryMock, userPRMock)
+
+			var finished *domain.CreatePRRequest
+			requestsMock.On("FinishCreatePRRequest", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				finished = args.Get(1).(*domain.CreatePRRequest)
+			}).Return(nil).Once()
+
+			service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, userPRMock, nil, historyMock,
+				WithClock(fixedClock(testNow)), WithAsyncCreate(requestsMock, time.Minute))
+
+			err := service.ProcessCreatePRRequest(ctx, domain.CreatePRRequest{
+				ID: 7, PullRequestID: "pr-1", PullRequestName: "feat: async", AuthorID: "author-1",
+				Status: domain.CreatePRProcessing, Attempts: tc.attempts,
+			})
+			require.NoError(t, err)
+
+			require.NotNil(t, finished)
+			assert.Equal(t, int64(7), finished.ID)
+			assert.Equal(t, tc.expectedStatus, finished.Status)
+
+			if tc.expectedCode == "" {
+				assert.Nil(t, finished.ErrorCode)
+			} else {
+				require.NotNil(t, finished.ErrorCode)
+				assert.Equal(t, tc.expectedCode, *finished.ErrorCode)
+			}
+
+			if tc.expectedStatus == domain.CreatePRQueued {
+				assert.Nil(t, finished.FinishedAt)
+			} else {
+				require.NotNil(t, finished.FinishedAt)
+				assert.Equal(t, testNow.UTC(), *finished.FinishedAt)
+			}
+
+			prCmdMock.AssertExpectations(t)
+			prQueryMock.AssertExpectations(t)
+			userPRMock.AssertExpectations(t)
+			requestsMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPullRequestServiceImpl_ClaimCreatePRRequests(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	requestsMock := new(CreatePRRequestRepositoryMock)
+	requestsMock.On("ClaimCreatePRRequests", ctx, 4, testNow.UTC(), testNow.UTC().Add(-time.Minute)).
+		Return([]domain.CreatePRRequest{{ID: 1}}, nil).Once()
+
+	service := NewPullRequestService(nil, logger, nil, nil, nil, nil, nil,
+		WithClock(fixedClock(testNow)), WithAsyncCreate(requestsMock, time.Minute))
+
+	claimed, err := service.ClaimCreatePRRequests(ctx, 4)
+	require.NoError(t, err)
+	assert.Len(t, claimed, 1)
+
+	_, err = service.ClaimCreatePRRequests(ctx, 0)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	requestsMock.AssertExpectations(t)
+}
+
+func TestPullRequestServiceImpl_EnqueueCreatePR_Disabled(t *testing.T) {
+	service := NewPullRequestService(nil, slog.New(slog.NewTextHandler(os.Stdout, nil)), nil, nil, nil, nil, nil)
+
+	_, err := service.EnqueueCreatePR(context.Background(), "pr-1", "feat: async", "author-1", PRDetails{})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
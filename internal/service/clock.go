@@ -0,0 +1,12 @@
+package service
+
+import "time"
+
+// Clock tells the services the current time. Tests inject a fixed clock instead of depending on the wall clock.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// Limits of the custom pull request fields.
+const (
+	maxCustomFields       = 50
+	maxCustomFieldFilters = 10
+)
+
+// customFieldKey restricts keys to snake_case, which the /v1 field renaming leaves untouched.
+var customFieldKey = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+func (s *TeamServiceImpl) SetCustomFields(ctx context.Context, fields api.TeamCustomFields) (*api.TeamCustomFields, error) {
+	if len(fields.Fields) > maxCustomFields {
+		return nil, fmt.Errorf("%w: a team can define at most %d custom fields", apperrors.ErrValidation, maxCustomFields)
+	}
+
+	defined := make([]domain.CustomField, len(fields.Fields))
+	keys := make(map[string]bool, len(fields.Fields))
+
+	for i, field := range fields.Fields {
+		if !customFieldKey.MatchString(field.Key) {
+			return nil, fmt.Errorf("%w: custom field key '%s' must be snake_case of up to 64 characters", apperrors.ErrValidation, field.Key)
+		}
+
+		if keys[field.Key] {
+			return nil, fmt.Errorf("%w: custom field '%s' is listed twice", apperrors.ErrValidation, field.Key)
+		}
+
+		fieldType := domain.CustomFieldType(field.Type)
+		if !fieldType.IsValid() {
+			return nil, fmt.Errorf("%w: unknown type '%s' of custom field '%s'", apperrors.ErrValidation, field.Type, field.Key)
+		}
+
+		keys[field.Key] = true
+		defined[i] = domain.CustomField{Key: field.Key, Type: fieldType, Required: field.Required}
+	}
+
+	team, err := s.repo.GetTeamByName(ctx, fields.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetTeamByName failed: %w", err)
+	}
+
+	saved, err := s.fieldRepo.ReplaceCustomFields(ctx, team.ID, defined)
+	if err != nil {
+		return nil, fmt.Errorf("fieldRepo.ReplaceCustomFields failed: %w", err)
+	}
+
+	return toAPITeamCustomFields(team, saved), nil
+}
+
+func (s *TeamServiceImpl) GetCustomFields(ctx context.Context, teamName string) (*api.TeamCustomFields, error) {
+	team, err := s.repo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetTeamByName failed: %w", err)
+	}
+
+	fields, err := s.fieldRepo.GetCustomFields(ctx, team.ID)
+	if err != nil {
+		return nil, fmt.Errorf("fieldRepo.GetCustomFields failed: %w", err)
+	}
+
+	return toAPITeamCustomFields(team, fields), nil
+}
+
+// customFieldValues checks the custom field values of a new pull request against the fields of the author's team
+// and returns them as the JSON to store. No values are stored as nil.
+func (s *PullRequestServiceImpl) customFieldValues(ctx context.Context, teamID int, values map[string]any) ([]byte, error) {
+	var fields []domain.CustomField
+
+	if s.customFields != nil {
+		var err error
+
+		fields, err = s.customFields.GetCustomFields(ctx, teamID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get custom fields of the team: %w", err)
+		}
+	}
+
+	defined := make(map[string]domain.CustomField, len(fields))
+	for _, field := range fields {
+		defined[field.Key] = field
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		field, ok := defined[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown custom field '%s'", apperrors.ErrValidation, key)
+		}
+
+		if !field.Type.Accepts(values[key]) {
+			return nil, fmt.Errorf("%w: custom field '%s' must be a %s", apperrors.ErrValidation, key, field.Type)
+		}
+	}
+
+	for _, field := range fields {
+		if _, ok := values[field.Key]; field.Required && !ok {
+			return nil, fmt.Errorf("%w: custom field '%s' is required", apperrors.ErrValidation, field.Key)
+		}
+	}
+
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode custom fields: %w", err)
+	}
+
+	return encoded, nil
+}
+
+// parseCustomFieldFilters parses custom_field query parameters of the form key:value.
+// The value may itself contain colons; no filters are returned as nil.
+func parseCustomFieldFilters(filters []string) (map[string]string, error) {
+	if len(filters) > maxCustomFieldFilters {
+		return nil, fmt.Errorf("%w: at most %d custom field filters are allowed", apperrors.ErrValidation, maxCustomFieldFilters)
+	}
+
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	parsed := make(map[string]string, len(filters))
+
+	for _, filter := range filters {
+		key, value, ok := strings.Cut(filter, ":")
+		if !ok || !customFieldKey.MatchString(key) {
+			return nil, fmt.Errorf("%w: custom field filter '%s' must look like key:value", apperrors.ErrValidation, filter)
+		}
+
+		if _, ok := parsed[key]; ok {
+			return nil, fmt.Errorf("%w: custom field '%s' is filtered twice", apperrors.ErrValidation, key)
+		}
+
+		parsed[key] = value
+	}
+
+	return parsed, nil
+}
+
+// decodeCustomFields decodes the custom field values stored with a pull request or a creation request.
+func decodeCustomFields(raw []byte) (map[string]any, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("failed to decode custom fields: %w", err)
+	}
+
+	return values, nil
+}
+
+// toAPICustomFields returns the custom field values of a pull request, or nil if it has none.
+// The stored values were encoded by the service, so a value that does not decode is left out as well.
+func toAPICustomFields(raw []byte) *map[string]interface{} {
+	values, err := decodeCustomFields(raw)
+	if err != nil || len(values) == 0 {
+		return nil
+	}
+
+	return &values
+}
+
+func toAPITeamCustomFields(team *domain.TeamWithMembers, fields []domain.CustomField) *api.TeamCustomFields {
+	apiFields := make([]api.CustomField, len(fields))
+	for i, field := range fields {
+		apiFields[i] = api.CustomField{
+			Key:      field.Key,
+			Type:     api.CustomFieldType(field.Type),
+			Required: field.Required,
+		}
+	}
+
+	return &api.TeamCustomFields{TeamName: team.Name, TeamId: team.ID, Fields: apiFields}
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamServiceImpl_SetCustomFields(t *testing.T) {
+	ctx := context.Background()
+	team := &domain.TeamWithMembers{ID: 1, Name: "backend"}
+
+	testCases := []struct {
+		name            string
+		fields          []api.CustomField
+		setupMocks      func(teamRepo *TeamRepositoryMock, fieldRepo *CustomFieldRepositoryMock)
+		expected        *api.TeamCustomFields
+		expectedErrorIs error
+	}{
+		{
+			name: "Success",
+			fields: []api.CustomField{
+				{Key: "risk", Type: api.CustomFieldString, Required: true},
+				{Key: "story_points", Type: api.CustomFieldNumber},
+			},
+			setupMocks: func(teamRepo *TeamRepositoryMock, fieldRepo *CustomFieldRepositoryMock) {
+				teamRepo.On("GetTeamByName", mock.Anything, "backend").Return(team, nil).Once()
+				fieldRepo.On("ReplaceCustomFields", ctx, 1, []domain.CustomField{
+					{Key: "risk", Type: domain.CustomFieldString, Required: true},
+					{Key: "story_points", Type: domain.CustomFieldNumber},
+				}).Return([]domain.CustomField{
+					{TeamID: 1, Key: "risk", Type: domain.CustomFieldString, Required: true},
+					{TeamID: 1, Key: "story_points", Type: domain.CustomFieldNumber},
+				}, nil).Once()
+			},
+			expected: &api.TeamCustomFields{
+				TeamName: "backend",
+				TeamId:   1,
+				Fields: []api.CustomField{
+					{Key: "risk", Type: api.CustomFieldString, Required: true},
+					{Key: "story_points", Type: api.CustomFieldNumber},
+				},
+			},
+		},
+		{
+			name:   "Success - Removing all fields",
+			fields: []api.CustomField{},
+			setupMocks: func(teamRepo *TeamRepositoryMock, fieldRepo *CustomFieldRepositoryMock) {
+				teamRepo.On("GetTeamByName", mock.Anything, "backend").Return(team, nil).Once()
+				fieldRepo.On("ReplaceCustomFields", ctx, 1, []domain.CustomField{}).Return([]domain.CustomField{}, nil).Once()
+			},
+			expected: &api.TeamCustomFields{TeamName: "backend", TeamId: 1, Fields: []api.CustomField{}},
+		},
+		{
+			name:            "Failure - Key is not snake_case",
+			fields:          []api.CustomField{{Key: "Risk-Level", Type: api.CustomFieldString}},
+			expectedErrorIs: apperrors.ErrValidation,
+		},
+		{
+			name: "Failure - Key is listed twice",
+			fields: []api.CustomField{
+				{Key: "risk", Type: api.CustomFieldString},
+				{Key: "risk", Type: api.CustomFieldBoolean},
+			},
+			expectedErrorIs: apperrors.ErrValidation,
+		},
+		{
+			name:            "Failure - Unknown type",
+			fields:          []api.CustomField{{Key: "risk", Type: "date"}},
+			expectedErrorIs: apperrors.ErrValidation,
+		},
+		{
+			name:   "Failure - Team not found",
+			fields: []api.CustomField{{Key: "risk", Type: api.CustomFieldString}},
+			setupMocks: func(teamRepo *TeamRepositoryMock, fieldRepo *CustomFieldRepositoryMock) {
+				teamRepo.On("GetTeamByName", mock.Anything, "backend").Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedErrorIs: apperrors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			teamRepoMock := new(TeamRepositoryMock)
+			fieldRepoMock := new(CustomFieldRepositoryMock)
+
+			if tc.setupMocks != nil {
+				tc.setupMocks(teamRepoMock, fieldRepoMock)
+			}
+
+			service := NewTeamService(teamRepoMock, nil, nil, fieldRepoMock)
+			result, err := service.SetCustomFields(ctx, api.TeamCustomFields{TeamName: "backend", Fields: tc.fields})
+
+			if tc.expectedErrorIs != nil {
+				assert.ErrorIs(t, err, tc.expectedErrorIs)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, result)
+			}
+
+			teamRepoMock.AssertExpectations(t)
+			fieldRepoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPullRequestServiceImpl_CreatePR_CustomFields(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	fields := []domain.CustomField{
+		{TeamID: 1, Key: "risk", Type: domain.CustomFieldString, Required: true},
+		{TeamID: 1, Key: "story_points", Type: domain.CustomFieldNumber},
+		{TeamID: 1, Key: "hotfix", Type: domain.CustomFieldBoolean},
+	}
+
+	testCases := []struct {
+		name             string
+		values           map[string]any
+		expectedStored   string
+		expectedErrorMsg string
+	}{
+		{
+			name:           "Success - Values are stored",
+			values:         map[string]any{"risk": "high", "story_points": float64(3), "hotfix": true},
+			expectedStored: `{"hotfix":true,"risk":"high","story_points":3}`,
+		},
+		{
+			name:             "Failure - Unknown field",
+			values:           map[string]any{"risk": "high", "owner": "alice"},
+			expectedErrorMsg: "unknown custom field 'owner'",
+		},
+		{
+			name:             "Failure - Wrong type",
+			values:           map[string]any{"risk": "high", "story_points": "three"},
+			expectedErrorMsg: "custom field 'story_points' must be a number",
+		},
+		{
+			name:             "Failure - Required field is missing",
+			values:           map[string]any{"hotfix": false},
+			expectedErrorMsg: "custom field 'risk' is required",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transactorMock := new(TransactorMock)
+			prCmdMock := new(PRCommandRepositoryMock)
+			userPRMock := new(UserPRRepositoryMock)
+			historyMock := new(AssignmentHistoryRepositoryMock)
+			fieldRepoMock := new(CustomFieldRepositoryMock)
+
+			userPRMock.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
+			fieldRepoMock.On("GetCustomFields", ctx, 1).Return(fields, nil).Once()
+
+			if tc.expectedErrorMsg == "" {
+				_, mockedTx, smock := newMockDBAndTx(t)
+				smock.ExpectCommit()
+
+				transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+				userPRMock.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{"rev-1"}, nil).Once()
+				prCmdMock.On("CreatePR", inTx(mockedTx), mock.MatchedBy(func(pr *domain.PullRequest) bool {
+					return string(pr.CustomFields) == tc.expectedStored
+				})).Return(nil).Once()
+				prCmdMock.On("LockActiveUsers", inTx(mockedTx), []string{"rev-1"}).Return([]string{"rev-1"}, nil).Once()
+				prCmdMock.On("AssignReviewers", inTx(mockedTx), "pr-1", []string{"rev-1"}).Return(nil).Once()
+				historyMock.On("RecordAssignments", inTx(mockedTx), mock.Anything).Return(nil).Once()
+			}
+
+			service := NewPullRequestService(transactorMock, logger, prCmdMock, nil, userPRMock, nil, historyMock,
+				WithClock(fixedClock(testNow)), WithCustomFields(fieldRepoMock))
+			pr, _, err := service.CreatePR(ctx, "pr-1", "feat: fields", "author-1", PRDetails{CustomFields: tc.values})
+
+			if tc.expectedErrorMsg != "" {
+				assert.ErrorIs(t, err, apperrors.ErrValidation)
+				assert.ErrorContains(t, err, tc.expectedErrorMsg)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, pr.CustomFields)
+				assert.Equal(t, tc.values, *pr.CustomFields)
+			}
+
+			transactorMock.AssertExpectations(t)
+			prCmdMock.AssertExpectations(t)
+			userPRMock.AssertExpectations(t)
+			fieldRepoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestParseCustomFieldFilters(t *testing.T) {
+	testCases := []struct {
+		name          string
+		filters       []string
+		expected      map[string]string
+		expectedError bool
+	}{
+		{
+			name:     "No filters",
+			expected: nil,
+		},
+		{
+			name:     "Value with a colon",
+			filters:  []string{"risk:high", "ticket:JIRA:42"},
+			expected: map[string]string{"risk": "high", "ticket": "JIRA:42"},
+		},
+		{
+			name:     "Empty value",
+			filters:  []string{"risk:"},
+			expected: map[string]string{"risk": ""},
+		},
+		{
+			name:          "Missing colon",
+			filters:       []string{"risk"},
+			expectedError: true,
+		},
+		{
+			name:          "Invalid key",
+			filters:       []string{"Risk:high"},
+			expectedError: true,
+		},
+		{
+			name:          "Key filtered twice",
+			filters:       []string{"risk:high", "risk:low"},
+			expectedError: true,
+		},
+		{
+			name:          "Too many filters",
+			filters:       strings.Split(strings.Repeat("risk:high,", maxCustomFieldFilters+1), ",")[:maxCustomFieldFilters+1],
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := parseCustomFieldFilters(tc.filters)
+
+			if tc.expectedError {
+				assert.ErrorIs(t, err, apperrors.ErrValidation)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, parsed)
+			}
+		})
+	}
+}
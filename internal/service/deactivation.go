@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// maxDeactivationBatchSize bounds the number of pull requests reassigned in a single transaction.
+const maxDeactivationBatchSize = 1000
+
+func (s *UserServiceImpl) DeactivateTeamInBatches(ctx context.Context, teamName string, batchSize int) (*api.DeactivationJob, error) {
+	const op = "internal.service.user.DeactivateTeamInBatches"
+	log := s.log.With(slog.String("op", op), slog.String("team_name", teamName))
+
+	if s.jobs == nil {
+		return nil, fmt.Errorf("%w: batched team deactivation is disabled", apperrors.ErrValidation)
+	}
+
+	if batchSize < 1 || batchSize > maxDeactivationBatchSize {
+		return nil, fmt.Errorf("%w: batch size must be between 1 and %d", apperrors.ErrValidation, maxDeactivationBatchSize)
+	}
+
+	var job *domain.DeactivationJob
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		team, deactivatedUserIDs, prs, err := s.deactivateMembers(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		// The pull requests are already in ascending ID order, the order every batch locks them in.
+		var batches [][]string
+		for start := 0; start < len(prs); start += batchSize {
+			batch := make([]string, 0, batchSize)
+			for _, pr := range prs[start:min(start+batchSize, len(prs))] {
+				batch = append(batch, pr.ID)
+			}
+
+			batches = append(batches, batch)
+		}
+
+		job, err = s.jobs.CreateDeactivationJob(ctx, &domain.DeactivationJob{
+			TeamID:             team.ID,
+			BatchSize:          batchSize,
+			DeactivatedUserIDs: deactivatedUserIDs,
+		}, batches)
+		if err != nil {
+			return fmt.Errorf("failed to create job: %w", err)
+		}
+
+		return s.recordDeactivation(ctx, team.ID, deactivatedUserIDs, &job.ID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("team deactivated, reviews are reassigned in batches",
+		slog.Int64("job_id", job.ID), slog.Int("users", len(job.DeactivatedUserIDs)),
+		slog.Int("prs", job.TotalPRs), slog.Int("batches", job.TotalBatches))
+
+	return toAPIDeactivationJob(job), nil
+}
+
+func (s *UserServiceImpl) GetDeactivationJob(ctx context.Context, jobID int64) (*api.DeactivationJob, error) {
+	const op = "internal.service.user.GetDeactivationJob"
+
+	if s.jobs == nil {
+		return nil, fmt.Errorf("%w: team deactivation job %d", apperrors.ErrNotFound, jobID)
+	}
+
+	job, err := s.jobs.GetDeactivationJob(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get job: %w", op, err)
+	}
+
+	return toAPIDeactivationJob(job), nil
+}
+
+func (s *UserServiceImpl) ProcessDeactivationBatch(ctx context.Context) (bool, error) {
+	const op = "internal.service.user.ProcessDeactivationBatch"
+
+	if s.jobs == nil {
+		return false, nil
+	}
+
+	var (
+		batch        *domain.DeactivationBatch
+		replacements []domain.AssignmentRecord
+		warnings     []string
+	)
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		var err error
+
+		warnings = nil
+
+		batch, err = s.jobs.ClaimDeactivationBatch(ctx)
+		if err != nil {
+			return err
+		}
+
+		prs, err := s.lockBatchPRs(ctx, batch.PullRequestIDs)
+		if err != nil {
+			return err
+		}
+
+		deactivatedSet := make(map[string]struct{}, len(batch.DeactivatedUserIDs))
+		for _, id := range batch.DeactivatedUserIDs {
+			deactivatedSet[id] = struct{}{}
+		}
+
+		var unplaced []unplacedReview
+
+		replacements, unplaced, err = s.planReplacements(ctx, prs, deactivatedSet, domain.CauseTeamDeactivated)
+		if err != nil {
+			return fmt.Errorf("failed to plan PR reassignment: %w", err)
+		}
+
+		for _, review := range unplaced {
+			warnings = append(warnings, review.warning())
+		}
+
+		if err := s.applyReplacements(ctx, replacements); err != nil {
+			return fmt.Errorf("failed during PR reassignment: %w", err)
+		}
+
+		if err := s.jobs.FinishDeactivationBatch(ctx, batch, len(replacements), warnings, s.now()); err != nil {
+			return fmt.Errorf("failed to finish batch: %w", err)
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, apperrors.ErrNotFound) && batch == nil {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, record := range replacements {
+		reviewerReassignmentsTotal.WithLabelValues(string(record.Strategy)).Inc()
+	}
+
+	s.log.Info("deactivation batch reassigned", slog.String("op", op),
+		slog.Int64("job_id", batch.JobID), slog.Int("batch_no", batch.BatchNo),
+		slog.Int("reassigned_reviews", len(replacements)), slog.Int("warnings", len(warnings)))
+
+	return true, nil
+}
+
+// lockBatchPRs locks the pull requests of a batch in the given order and returns the open ones with their
+// current reviewers. Pull requests merged since the deactivation no longer need a reassignment.
+func (s *UserServiceImpl) lockBatchPRs(ctx context.Context, prIDs []string) ([]domain.PullRequest, error) {
+	prs := make([]domain.PullRequest, 0, len(prIDs))
+
+	for _, prID := range prIDs {
+		pr, err := s.prCmd.GetPRByIDWithLock(ctx, prID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock pr %s: %w", prID, err)
+		}
+
+		if pr.Status != api.PullRequestStatusOPEN {
+			continue
+		}
+
+		pr.ReviewerIDs, err = s.prQuery.GetReviewerIDs(ctx, prID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reviewers of pr %s: %w", prID, err)
+		}
+
+		prs = append(prs, *pr)
+	}
+
+	return prs, nil
+}
+
+func toAPIDeactivationJob(job *domain.DeactivationJob) *api.DeactivationJob {
+	warnings := job.Warnings
+	if warnings == nil {
+		warnings = []string{}
+	}
+
+	return &api.DeactivationJob{
+		JobId:                  job.ID,
+		TeamName:               job.TeamName,
+		TeamId:                 job.TeamID,
+		Status:                 api.DeactivationJobStatus(job.Status),
+		BatchSize:              job.BatchSize,
+		TotalBatches:           job.TotalBatches,
+		DoneBatches:            job.DoneBatches,
+		DeactivatedUsersCount:  len(job.DeactivatedUserIDs),
+		TotalPrs:               job.TotalPRs,
+		ReassignedReviewsCount: job.ReassignedReviews,
+		Warnings:               warnings,
+		CreatedAt:              job.CreatedAt,
+		FinishedAt:             job.FinishedAt,
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserServiceImpl_DeactivateTeamInBatches(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	m := &mocks{
+		userRepo:    new(UserRepositoryMock),
+		teamRepo:    new(TeamRepositoryMock),
+		prQueryRepo: new(PRQueryRepositoryMock),
+		transactor:  new(TransactorMock),
+	}
+	jobs := new(DeactivationJobRepositoryMock)
+
+	prs := make([]domain.PullRequest, 5)
+	for i := range prs {
+		prs[i] = domain.PullRequest{ID: fmt.Sprintf("pr-%d", i+1)}
+	}
+
+	_, tx, smock := newMockDBAndTx(t)
+	smock.ExpectCommit()
+	m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+	m.teamRepo.On("GetTeamByName", inTx(tx), "big-team").Return(&domain.TeamWithMembers{ID: 1, Name: "big-team"}, nil).Once()
+	m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil).Once()
+	m.userRepo.On("DeactivateUsersByTeamID", inTx(tx), 1).Return([]string{"u1", "u2"}, nil).Once()
+	m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{"u1", "u2"}).Return(prs, nil).Once()
+
+	batches := [][]string{{"pr-1", "pr-2"}, {"pr-3", "pr-4"}, {"pr-5"}}
+	jobs.On("CreateDeactivationJob", inTx(tx), &domain.DeactivationJob{TeamID: 1, BatchSize: 2, DeactivatedUserIDs: []string{"u1", "u2"}}, batches).
+		Return(&domain.DeactivationJob{
+			ID: 7, TeamID: 1, TeamName: "big-team", Status: domain.DeactivationJobRunning, BatchSize: 2,
+			TotalBatches: 3, TotalPRs: 5, DeactivatedUserIDs: []string{"u1", "u2"},
+		}, nil).Once()
+
+	service := NewUserService(m.userRepo, m.teamRepo, m.prQueryRepo, nil, nil, nil, nil, m.transactor, logger, WithDeactivationJobs(jobs))
+
+	job, err := service.DeactivateTeamInBatches(ctx, "big-team", 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), job.JobId)
+	assert.Equal(t, api.DeactivationJobRunning, job.Status)
+	assert.Equal(t, 2, job.DeactivatedUsersCount)
+	assert.Equal(t, 3, job.TotalBatches)
+	assert.Equal(t, []string{}, job.Warnings)
+
+	_, err = service.DeactivateTeamInBatches(ctx, "big-team", 0)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	m.teamRepo.AssertExpectations(t)
+	m.userRepo.AssertExpectations(t)
+	m.prQueryRepo.AssertExpectations(t)
+	jobs.AssertExpectations(t)
+	require.NoError(t, smock.ExpectationsWereMet())
+}
+
+func TestUserServiceImpl_ProcessDeactivationBatch(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	batch := &domain.DeactivationBatch{
+		JobID: 7, BatchNo: 2, TeamID: 1,
+		PullRequestIDs:     []string{"pr-1", "pr-2", "pr-3"},
+		DeactivatedUserIDs: []string{"u1", "u2"},
+	}
+
+	testCases := []struct {
+		name               string
+		setupMocks         func(m *mocks, jobs *DeactivationJobRepositoryMock)
+		expectedProcessed  bool
+		expectedReassigned int
+		expectedWarnings   []string
+	}{
+		{
+			name: "Open PRs are reassigned and merged ones skipped",
+			setupMocks: func(m *mocks, jobs *DeactivationJobRepositoryMock) {
+				jobs.On("ClaimDeactivationBatch", mock.Anything).Return(batch, nil).Once()
+				m.prCmdRepo.On("GetPRByIDWithLock", mock.Anything, "pr-1").
+					Return(&domain.PullRequest{ID: "pr-1", AuthorID: "author-1", Status: api.PullRequestStatusOPEN}, nil).Once()
+				m.prCmdRepo.On("GetPRByIDWithLock", mock.Anything, "pr-2").
+					Return(&domain.PullRequest{ID: "pr-2", AuthorID: "author-1", Status: api.PullRequestStatusMERGED}, nil).Once()
+				m.prCmdRepo.On("GetPRByIDWithLock", mock.Anything, "pr-3").
+					Return(&domain.PullRequest{ID: "pr-3", AuthorID: "author-2", Status: api.PullRequestStatusOPEN}, nil).Once()
+				m.prQueryRepo.On("GetReviewerIDs", mock.Anything, "pr-1").Return([]string{"u1", "u3"}, nil).Once()
+				m.prQueryRepo.On("GetReviewerIDs", mock.Anything, "pr-3").Return([]string{"u2"}, nil).Once()
+				m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil).Once()
+				m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-3").Return(1, nil).Once()
+				m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil).Once()
+				m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, sameIDs("author-1", "u1", "u3"), 1).Return([]string{"new-rev"}, nil).Once()
+				m.prCmdRepo.On("LockActiveUsers", mock.Anything, []string{"new-rev"}).Return([]string{"new-rev"}, nil).Once()
+				m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, sameIDs("author-2", "u2"), 1).Return([]string{}, nil).Once()
+				m.prCmdRepo.On("ReplaceReviewers", mock.Anything, []domain.ReviewerReplacement{{PullRequestID: "pr-1", OldReviewerID: "u1", NewReviewerID: "new-rev"}}).Return(nil).Once()
+				m.historyRepo.On("RecordAssignments", mock.Anything, mock.Anything).Return(nil).Once()
+				jobs.On("FinishDeactivationBatch", mock.Anything, batch, 1,
+					[]string{"pull request 'pr-3': no active replacement for reviewer 'u2'"}, testNow.UTC()).Return(nil).Once()
+			},
+			expectedProcessed: true,
+		},
+		{
+			name: "Nothing pending",
+			setupMocks: func(m *mocks, jobs *DeactivationJobRepositoryMock) {
+				jobs.On("ClaimDeactivationBatch", mock.Anything).
+					Return(nil, fmt.Errorf("claim: %w: no pending deactivation batch", apperrors.ErrNotFound)).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &mocks{
+				prQueryRepo: new(PRQueryRepositoryMock),
+				prCmdRepo:   new(PRCommandRepositoryMock),
+				userPRRepo:  new(UserPRRepositoryMock),
+				policyRepo:  new(PolicyRepositoryMock),
+				historyRepo: new(AssignmentHistoryRepositoryMock),
+				transactor:  new(TransactorMock),
+			}
+			jobs := new(DeactivationJobRepositoryMock)
+
+			_, tx, smock := newMockDBAndTx(t)
+			if tc.expectedProcessed {
+				smock.ExpectCommit()
+			} else {
+				smock.ExpectRollback()
+			}
+
+			m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+			tc.setupMocks(m, jobs)
+
+			service := NewUserService(nil, nil, m.prQueryRepo, m.prCmdRepo, m.userPRRepo, m.policyRepo, m.historyRepo, m.transactor, logger,
+				WithUserClock(fixedClock(testNow)), WithDeactivationJobs(jobs))
+
+			processed, err := service.ProcessDeactivationBatch(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedProcessed, processed)
+
+			m.prCmdRepo.AssertExpectations(t)
+			m.prQueryRepo.AssertExpectations(t)
+			m.userPRRepo.AssertExpectations(t)
+			m.historyRepo.AssertExpectations(t)
+			jobs.AssertExpectations(t)
+			require.NoError(t, smock.ExpectationsWereMet())
+		})
+	}
+}
+
+// sameIDs matches a slice holding exactly the given IDs in any order.
+func sameIDs(ids ...string) any {
+	return mock.MatchedBy(func(actual []string) bool {
+		sorted := slices.Clone(actual)
+		slices.Sort(sorted)
+
+		return slices.Equal(sorted, ids)
+	})
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+func (s *UserServiceImpl) DeleteUser(ctx context.Context, userID string) (*api.DeleteUserResponse, error) {
+	const op = "internal.service.user.DeleteUser"
+
+	var (
+		user         *api.User
+		replacements []domain.AssignmentRecord
+		unplaced     []unplacedReview
+	)
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		var err error
+
+		user, _, err = s.repo.SetIsActive(ctx, userID, false)
+		if err != nil {
+			return fmt.Errorf("repo.SetIsActive failed: %w", err)
+		}
+
+		// An inactive user may still hold the reviews nobody could take over when the user was deactivated.
+		replacements, unplaced, err = s.reassignReviews(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to reassign reviews: %w", err)
+		}
+
+		if err := s.repo.DeleteUser(ctx, userID, s.now()); err != nil {
+			return fmt.Errorf("repo.DeleteUser failed: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range replacements {
+		reviewerReassignmentsTotal.WithLabelValues(string(record.Strategy)).Inc()
+	}
+
+	s.log.Info("user deleted", slog.String("op", op), slog.String("user_id", userID), slog.Int("reassigned_reviews", len(replacements)))
+
+	resp := &api.DeleteUserResponse{
+		User:          *user,
+		Reassignments: toAPIReviewerMoves(replacements),
+	}
+
+	if len(unplaced) > 0 {
+		warnings := make([]string, len(unplaced))
+		for i, review := range unplaced {
+			s.log.Warn("no replacement candidate found", slog.String("op", op), slog.String("pr_id", review.prID),
+				slog.String("old_reviewer_id", review.reviewerID))
+			warnings[i] = review.warning()
+		}
+
+		resp.Warnings = &warnings
+	}
+
+	return resp, nil
+}
+
+func (s *UserServiceImpl) RestoreUser(ctx context.Context, userID string) (*api.User, error) {
+	const op = "internal.service.user.RestoreUser"
+
+	var user *api.User
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		var err error
+
+		user, err = s.repo.RestoreUser(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("repo.RestoreUser failed: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("user restored", slog.String("op", op), slog.String("user_id", userID))
+
+	return user, nil
+}
+
+func (s *UserServiceImpl) DeleteTeam(ctx context.Context, teamName string, force bool) (*api.DeleteTeamResponse, error) {
+	const op = "internal.service.user.DeleteTeam"
+	log := s.log.With(slog.String("op", op), slog.String("team_name", teamName))
+
+	var (
+		deactivation   *teamDeactivation
+		deletedUserIDs []string
+	)
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		var err error
+
+		deactivation, err = s.deactivateTeamMembers(ctx, log, teamName, force)
+		if err != nil {
+			return err
+		}
+
+		deletedUserIDs, err = s.teamRepo.DeleteTeam(ctx, deactivation.team.ID, s.now())
+		if err != nil {
+			return fmt.Errorf("failed to delete team: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range deactivation.replacements {
+		reviewerReassignmentsTotal.WithLabelValues(string(record.Strategy)).Inc()
+	}
+
+	log.Info("team deleted", slog.Int("team_id", deactivation.team.ID), slog.Int("users", len(deletedUserIDs)))
+
+	resp := &api.DeleteTeamResponse{
+		TeamId:             deactivation.team.ID,
+		DeletedUsersCount:  len(deletedUserIDs),
+		ReassignedPrsCount: deactivation.reassignedCount,
+	}
+
+	if len(deactivation.warnings) > 0 {
+		resp.Warnings = &deactivation.warnings
+	}
+
+	return resp, nil
+}
+
+func (s *UserServiceImpl) RestoreTeam(ctx context.Context, teamID int) (*api.Team, error) {
+	const op = "internal.service.user.RestoreTeam"
+
+	var team *domain.TeamWithMembers
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		if err := s.teamRepo.RestoreTeam(ctx, teamID); err != nil {
+			return fmt.Errorf("failed to restore team: %w", err)
+		}
+
+		var err error
+
+		team, err = s.teamRepo.GetTeamByID(ctx, teamID)
+		if err != nil {
+			return fmt.Errorf("repo.GetTeamByID failed: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("team restored", slog.String("op", op), slog.Int("team_id", teamID), slog.String("team_name", team.Name))
+
+	return toAPITeam(team), nil
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newUserTestMocks() *mocks {
+	return &mocks{
+		userRepo:    new(UserRepositoryMock),
+		teamRepo:    new(TeamRepositoryMock),
+		prQueryRepo: new(PRQueryRepositoryMock),
+		prCmdRepo:   new(PRCommandRepositoryMock),
+		userPRRepo:  new(UserPRRepositoryMock),
+		policyRepo:  new(PolicyRepositoryMock),
+		historyRepo: new(AssignmentHistoryRepositoryMock),
+		transactor:  new(TransactorMock),
+	}
+}
+
+func newUserTestService(m *mocks) *UserServiceImpl {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	return NewUserService(
+		m.userRepo, m.teamRepo, m.prQueryRepo, m.prCmdRepo, m.userPRRepo, m.policyRepo, m.historyRepo, m.transactor, logger,
+		WithUserClock(fixedClock(testNow)),
+	)
+}
+
+func TestUserServiceImpl_DeleteUser(t *testing.T) {
+	ctx := context.Background()
+	user := &api.User{UserId: "u1", Username: "Alice", TeamName: "backend", TeamId: 1}
+
+	t.Run("Success: Open reviews are reassigned before the user is deleted", func(t *testing.T) {
+		m := newUserTestMocks()
+		_, tx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+		m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+		m.userRepo.On("SetIsActive", inTx(tx), "u1", false).Return(user, true, nil).Once()
+		m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{"u1"}).Return([]domain.PullRequest{
+			{ID: "pr-1", AuthorID: "author-1", ReviewerIDs: []string{"u1", "u3"}},
+			{ID: "pr-2", AuthorID: "author-2", ReviewerIDs: []string{"u1"}},
+		}, nil).Once()
+		m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil).Once()
+		m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-2").Return(1, nil).Once()
+		m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil).Once()
+		m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, sameIDs("author-1", "u1", "u3"), 1).Return([]string{"u4"}, nil).Once()
+		m.prCmdRepo.On("LockActiveUsers", mock.Anything, []string{"u4"}).Return([]string{"u4"}, nil).Once()
+		m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, sameIDs("author-2", "u1"), 1).Return([]string{}, nil).Once()
+		m.prCmdRepo.On("ReplaceReviewers", inTx(tx), []domain.ReviewerReplacement{{PullRequestID: "pr-1", OldReviewerID: "u1", NewReviewerID: "u4"}}).Return(nil).Once()
+		m.historyRepo.On("RecordAssignments", inTx(tx), mock.MatchedBy(func(records []domain.AssignmentRecord) bool {
+			return len(records) == 1 && records[0].Cause == domain.CauseUserDeactivated
+		})).Return(nil).Once()
+		m.userRepo.On("DeleteUser", inTx(tx), "u1", testNow.UTC()).Return(nil).Once()
+
+		resp, err := newUserTestService(m).DeleteUser(ctx, "u1")
+		require.NoError(t, err)
+		assert.Equal(t, &api.DeleteUserResponse{
+			User:          *user,
+			Reassignments: []api.ReviewerMove{{PullRequestId: "pr-1", FromUserId: "u1", ToUserId: "u4"}},
+			Warnings:      &[]string{"pull request 'pr-2': no active replacement for reviewer 'u1'"},
+		}, resp)
+
+		m.userRepo.AssertExpectations(t)
+		m.prCmdRepo.AssertExpectations(t)
+		m.historyRepo.AssertExpectations(t)
+		assert.NoError(t, smock.ExpectationsWereMet())
+	})
+
+	t.Run("Success: Each review is replaced from the team of its pull request", func(t *testing.T) {
+		m := newUserTestMocks()
+		_, tx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+		m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+		m.userRepo.On("SetIsActive", inTx(tx), "u1", false).Return(user, true, nil).Once()
+		// u1 reviews pr-2 as a member of team 2, which is not the primary team of u1.
+		m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{"u1"}).Return([]domain.PullRequest{
+			{ID: "pr-1", AuthorID: "author-1", ReviewerIDs: []string{"u1"}},
+			{ID: "pr-2", AuthorID: "author-2", ReviewerIDs: []string{"u1"}},
+		}, nil).Once()
+		m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil).Once()
+		m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-2").Return(2, nil).Once()
+		m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil).Once()
+		m.policyRepo.On("GetTeamPolicy", mock.Anything, 2).Return(&domain.TeamPolicy{TeamID: 2}, nil).Once()
+		m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, sameIDs("author-1", "u1"), 1).Return([]string{"u3"}, nil).Once()
+		m.prCmdRepo.On("LockActiveUsers", mock.Anything, []string{"u3"}).Return([]string{"u3"}, nil).Once()
+		m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 2, sameIDs("author-2", "u1"), 1).Return([]string{"u5"}, nil).Once()
+		m.prCmdRepo.On("LockActiveUsers", mock.Anything, []string{"u5"}).Return([]string{"u5"}, nil).Once()
+		m.prCmdRepo.On("ReplaceReviewers", inTx(tx), []domain.ReviewerReplacement{
+			{PullRequestID: "pr-1", OldReviewerID: "u1", NewReviewerID: "u3"},
+			{PullRequestID: "pr-2", OldReviewerID: "u1", NewReviewerID: "u5"},
+		}).Return(nil).Once()
+		m.historyRepo.On("RecordAssignments", inTx(tx), mock.Anything).Return(nil).Once()
+		m.userRepo.On("DeleteUser", inTx(tx), "u1", testNow.UTC()).Return(nil).Once()
+
+		resp, err := newUserTestService(m).DeleteUser(ctx, "u1")
+		require.NoError(t, err)
+		assert.Equal(t, []api.ReviewerMove{
+			{PullRequestId: "pr-1", FromUserId: "u1", ToUserId: "u3"},
+			{PullRequestId: "pr-2", FromUserId: "u1", ToUserId: "u5"},
+		}, resp.Reassignments)
+
+		m.userPRRepo.AssertExpectations(t)
+		m.policyRepo.AssertExpectations(t)
+		m.prCmdRepo.AssertExpectations(t)
+		assert.NoError(t, smock.ExpectationsWereMet())
+	})
+
+	t.Run("Success: An inactive user gives up the reviews left with the user", func(t *testing.T) {
+		m := newUserTestMocks()
+		_, tx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+		m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+		m.userRepo.On("SetIsActive", inTx(tx), "u1", false).Return(user, false, nil).Once()
+		m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{"u1"}).Return([]domain.PullRequest{}, nil).Once()
+		m.userRepo.On("DeleteUser", inTx(tx), "u1", testNow.UTC()).Return(nil).Once()
+
+		resp, err := newUserTestService(m).DeleteUser(ctx, "u1")
+		require.NoError(t, err)
+		assert.Equal(t, []api.ReviewerMove{}, resp.Reassignments)
+		assert.Nil(t, resp.Warnings)
+
+		m.userRepo.AssertExpectations(t)
+		m.prQueryRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure: User not found", func(t *testing.T) {
+		m := newUserTestMocks()
+		_, tx, smock := newMockDBAndTx(t)
+		smock.ExpectRollback()
+		m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+		m.userRepo.On("SetIsActive", inTx(tx), "u1", false).Return(nil, false, apperrors.ErrNotFound).Once()
+
+		resp, err := newUserTestService(m).DeleteUser(ctx, "u1")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+		assert.Nil(t, resp)
+		m.userRepo.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserServiceImpl_RestoreUser(t *testing.T) {
+	ctx := context.Background()
+
+	m := newUserTestMocks()
+	_, tx, smock := newMockDBAndTx(t)
+	smock.ExpectCommit()
+	m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+
+	restored := &api.User{UserId: "u1", Username: "Alice", TeamName: "backend", TeamId: 1}
+	m.userRepo.On("RestoreUser", inTx(tx), "u1").Return(restored, nil).Once()
+
+	user, err := newUserTestService(m).RestoreUser(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, restored, user)
+	assert.NoError(t, smock.ExpectationsWereMet())
+}
+
+func TestUserServiceImpl_DeleteTeam(t *testing.T) {
+	ctx := context.Background()
+	teamInDB := &domain.TeamWithMembers{ID: 1, Name: "test-team"}
+	// planReplacements swaps the reviewers of the pull requests in place, so every case gets its own.
+	prsToReassign := func() []domain.PullRequest {
+		return []domain.PullRequest{{ID: "pr-1", AuthorID: "author-1", ReviewerIDs: []string{"u1", "u3"}}}
+	}
+
+	t.Run("Success: The team is deactivated and deleted with its members", func(t *testing.T) {
+		m := newUserTestMocks()
+		_, tx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+		m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+		m.teamRepo.On("GetTeamByName", inTx(tx), "test-team").Return(teamInDB, nil).Once()
+		m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil).Once()
+		m.userRepo.On("DeactivateUsersByTeamID", inTx(tx), 1).Return([]string{"u1", "u2"}, nil).Once()
+		m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{"u1", "u2"}).Return(prsToReassign(), nil).Once()
+		m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil).Once()
+		m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil).Once()
+		m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, mock.Anything, 1).Return([]string{"u3-replacement"}, nil).Once()
+		m.prCmdRepo.On("LockActiveUsers", mock.Anything, []string{"u3-replacement"}).Return([]string{"u3-replacement"}, nil).Once()
+		m.prCmdRepo.On("ReplaceReviewers", inTx(tx), []domain.ReviewerReplacement{{PullRequestID: "pr-1", OldReviewerID: "u1", NewReviewerID: "u3-replacement"}}).Return(nil).Once()
+		m.historyRepo.On("RecordAssignments", inTx(tx), mock.Anything).Return(nil).Once()
+		// The members deactivated before stay with the team and are deleted with it.
+		m.teamRepo.On("DeleteTeam", inTx(tx), 1, testNow.UTC()).Return([]string{"u1", "u2", "u9"}, nil).Once()
+
+		resp, err := newUserTestService(m).DeleteTeam(ctx, "test-team", false)
+		require.NoError(t, err)
+		assert.Equal(t, &api.DeleteTeamResponse{TeamId: 1, DeletedUsersCount: 3, ReassignedPrsCount: 1}, resp)
+
+		m.teamRepo.AssertExpectations(t)
+		m.userRepo.AssertExpectations(t)
+		m.prCmdRepo.AssertExpectations(t)
+		assert.NoError(t, smock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure: Without force a team whose reviews cannot all be reassigned is kept", func(t *testing.T) {
+		m := newUserTestMocks()
+		_, tx, smock := newMockDBAndTx(t)
+		smock.ExpectRollback()
+		m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+		m.teamRepo.On("GetTeamByName", inTx(tx), "test-team").Return(teamInDB, nil).Once()
+		m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil).Once()
+		m.userRepo.On("DeactivateUsersByTeamID", inTx(tx), 1).Return([]string{"u1", "u2"}, nil).Once()
+		m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{"u1", "u2"}).Return(prsToReassign(), nil).Once()
+		m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil).Once()
+		m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil).Once()
+		m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, mock.Anything, 1).Return([]string{}, nil).Once()
+
+		resp, err := newUserTestService(m).DeleteTeam(ctx, "test-team", false)
+		assert.ErrorIs(t, err, apperrors.ErrInsufficientCapacity)
+		assert.Nil(t, resp)
+		m.teamRepo.AssertNotCalled(t, "DeleteTeam", mock.Anything, mock.Anything, mock.Anything)
+		assert.NoError(t, smock.ExpectationsWereMet())
+	})
+
+	t.Run("Success: With force the unplaced reviews are reported", func(t *testing.T) {
+		m := newUserTestMocks()
+		_, tx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+		m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+		m.teamRepo.On("GetTeamByName", inTx(tx), "test-team").Return(teamInDB, nil).Once()
+		m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil).Once()
+		m.userRepo.On("DeactivateUsersByTeamID", inTx(tx), 1).Return([]string{"u1", "u2"}, nil).Once()
+		m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{"u1", "u2"}).Return(prsToReassign(), nil).Once()
+		m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil).Once()
+		m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil).Once()
+		m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, mock.Anything, 1).Return([]string{}, nil).Once()
+		m.historyRepo.On("RecordAssignments", inTx(tx), []domain.AssignmentRecord(nil)).Return(nil).Once()
+		m.teamRepo.On("DeleteTeam", inTx(tx), 1, testNow.UTC()).Return([]string{"u1", "u2"}, nil).Once()
+
+		resp, err := newUserTestService(m).DeleteTeam(ctx, "test-team", true)
+		require.NoError(t, err)
+		assert.Equal(t, &api.DeleteTeamResponse{
+			TeamId:             1,
+			DeletedUsersCount:  2,
+			ReassignedPrsCount: 1,
+			Warnings:           &[]string{"pull request 'pr-1': no active replacement for reviewer 'u1'"},
+		}, resp)
+	})
+}
+
+func TestUserServiceImpl_RestoreTeam(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success: The team comes back with its members", func(t *testing.T) {
+		m := newUserTestMocks()
+		_, tx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+		m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+		m.teamRepo.On("RestoreTeam", inTx(tx), 1).Return(nil).Once()
+		m.teamRepo.On("GetTeamByID", inTx(tx), 1).Return(&domain.TeamWithMembers{
+			ID:      1,
+			Name:    "test-team",
+			Members: []domain.User{{ID: "u1", Username: "Alice", TeamID: 1}},
+		}, nil).Once()
+
+		team, err := newUserTestService(m).RestoreTeam(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "test-team", team.TeamName)
+		require.Len(t, team.Members, 1)
+		assert.False(t, team.Members[0].IsActive)
+		assert.NoError(t, smock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure: The name has been taken", func(t *testing.T) {
+		m := newUserTestMocks()
+		_, tx, smock := newMockDBAndTx(t)
+		smock.ExpectRollback()
+		m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+		m.teamRepo.On("RestoreTeam", inTx(tx), 1).Return(&apperrors.TeamAlreadyExistsError{TeamName: "test-team"}).Once()
+
+		team, err := newUserTestService(m).RestoreTeam(ctx, 1)
+		assert.ErrorIs(t, err, apperrors.ErrAlreadyExists)
+		assert.Nil(t, team)
+		assert.NoError(t, smock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// Outcomes of a merge attempted during a freeze window, counted by the merges_frozen_total metric.
+const (
+	freezeRejected   = "rejected"
+	freezeOverridden = "overridden"
+)
+
+// FreezeService manages the freeze windows during which MergePR rejects pull requests.
+type FreezeService interface {
+	// CreateFreezeWindow freezes the merges of the team's pull requests from startsAt until endsAt,
+	// or of every pull request if teamName is empty.
+	// Returns apperrors.ErrValidation if the window does not end after it starts or has already ended.
+	CreateFreezeWindow(ctx context.Context, teamName string, reason string, startsAt, endsAt time.Time) (*api.FreezeWindow, error)
+	// ListFreezeWindows returns the windows applying to the team, or all windows if teamName is empty,
+	// in the order they start. The ended windows are left out unless includeEnded is set.
+	ListFreezeWindows(ctx context.Context, teamName string, includeEnded bool) (*api.ListFreezeWindowsResponse, error)
+	// DeleteFreezeWindow removes a window, which lifts the freeze at once if it is in effect.
+	DeleteFreezeWindow(ctx context.Context, id int64) error
+}
+
+type FreezeServiceImpl struct {
+	BaseService
+	repo  repository.FreezeWindowRepository
+	teams repository.TeamRepository
+}
+
+// FreezeServiceOption configures optional behaviour of FreezeServiceImpl.
+type FreezeServiceOption func(*FreezeServiceImpl)
+
+// WithFreezeClock makes the service take the current time from c instead of the system clock.
+func WithFreezeClock(c Clock) FreezeServiceOption {
+	return func(s *FreezeServiceImpl) {
+		s.clock = c
+	}
+}
+
+// NewFreezeService creates a new instance of FreezeServiceImpl. Teams are looked up through teams.
+func NewFreezeService(
+	repo repository.FreezeWindowRepository,
+	teams repository.TeamRepository,
+	log *slog.Logger,
+	opts ...FreezeServiceOption,
+) *FreezeServiceImpl {
+	s := &FreezeServiceImpl{
+		BaseService: NewBaseService(nil, log),
+		repo:        repo,
+		teams:       teams,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *FreezeServiceImpl) CreateFreezeWindow(ctx context.Context, teamName string, reason string, startsAt, endsAt time.Time) (*api.FreezeWindow, error) {
+	const op = "internal.service.freeze.CreateFreezeWindow"
+
+	if !endsAt.After(startsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", apperrors.ErrValidation)
+	}
+
+	now := s.now()
+	if !endsAt.After(now) {
+		return nil, fmt.Errorf("%w: the freeze window has already ended", apperrors.ErrValidation)
+	}
+
+	window := &domain.FreezeWindow{Reason: reason, StartsAt: startsAt.UTC(), EndsAt: endsAt.UTC(), CreatedAt: now}
+
+	if teamName != "" {
+		team, err := s.teams.GetTeamByName(ctx, teamName)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to get team: %w", op, err)
+		}
+
+		window.TeamID = &team.ID
+	}
+
+	created, err := s.repo.CreateFreezeWindow(ctx, window)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to create freeze window: %w", op, err)
+	}
+
+	s.log.Info("freeze window created", slog.String("op", op), slog.Int64("freeze_id", created.ID),
+		slog.String("team_name", teamName), slog.Time("starts_at", created.StartsAt), slog.Time("ends_at", created.EndsAt))
+
+	return toAPIFreezeWindow(created), nil
+}
+
+func (s *FreezeServiceImpl) ListFreezeWindows(ctx context.Context, teamName string, includeEnded bool) (*api.ListFreezeWindowsResponse, error) {
+	const op = "internal.service.freeze.ListFreezeWindows"
+
+	filter := domain.FreezeWindowFilter{}
+
+	if teamName != "" {
+		team, err := s.teams.GetTeamByName(ctx, teamName)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to get team: %w", op, err)
+		}
+
+		filter.TeamID = &team.ID
+	}
+
+	if !includeEnded {
+		now := s.now()
+		filter.EndsAfter = &now
+	}
+
+	windows, err := s.repo.ListFreezeWindows(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list freeze windows: %w", op, err)
+	}
+
+	items := make([]api.FreezeWindow, len(windows))
+	for i := range windows {
+		items[i] = *toAPIFreezeWindow(&windows[i])
+	}
+
+	return &api.ListFreezeWindowsResponse{Items: items, Freezes: items, TotalEstimate: totalOf(items)}, nil
+}
+
+func (s *FreezeServiceImpl) DeleteFreezeWindow(ctx context.Context, id int64) error {
+	const op = "internal.service.freeze.DeleteFreezeWindow"
+
+	if err := s.repo.DeleteFreezeWindow(ctx, id); err != nil {
+		return fmt.Errorf("%s: failed to delete freeze window: %w", op, err)
+	}
+
+	s.log.Info("freeze window deleted", slog.String("op", op), slog.Int64("freeze_id", id))
+
+	return nil
+}
+
+// checkFreeze returns an apperrors.MergeFrozenError if a freeze window of the author's team or of the organization
+// is in effect at mergedAt. With override the merge goes ahead and the override is logged instead.
+// A pull request whose author has left every team is only subject to the windows of the organization.
+func (s *PullRequestServiceImpl) checkFreeze(ctx context.Context, pr *domain.PullRequest, mergedAt time.Time, override bool) error {
+	if s.freezes == nil {
+		return nil
+	}
+
+	teamID, err := s.userPR.GetAuthorTeamID(ctx, pr.AuthorID)
+	if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		return fmt.Errorf("failed to get author team id: %w", err)
+	}
+
+	windows, err := s.freezes.ListFreezeWindows(ctx, domain.FreezeWindowFilter{TeamID: &teamID, ActiveAt: &mergedAt})
+	if err != nil {
+		return fmt.Errorf("failed to list freeze windows: %w", err)
+	}
+
+	if len(windows) == 0 {
+		return nil
+	}
+
+	// The window ending last is reported, so that the caller knows when merges resume.
+	window := windows[0]
+	for _, w := range windows[1:] {
+		if w.EndsAt.After(window.EndsAt) {
+			window = w
+		}
+	}
+
+	if override {
+		mergesFrozenTotal.WithLabelValues(freezeOverridden).Inc()
+
+		s.log.Warn("merge freeze overridden", slog.String("pr_id", pr.ID), slog.Int64("freeze_id", window.ID),
+			slog.String("reason", window.Reason), slog.Time("ends_at", window.EndsAt))
+
+		return nil
+	}
+
+	mergesFrozenTotal.WithLabelValues(freezeRejected).Inc()
+
+	return &apperrors.MergeFrozenError{PRID: pr.ID, Reason: window.Reason, Until: window.EndsAt}
+}
+
+func toAPIFreezeWindow(w *domain.FreezeWindow) *api.FreezeWindow {
+	return &api.FreezeWindow{
+		FreezeId:  w.ID,
+		TeamName:  w.TeamName,
+		TeamId:    w.TeamID,
+		Reason:    w.Reason,
+		StartsAt:  w.StartsAt,
+		EndsAt:    w.EndsAt,
+		CreatedAt: w.CreatedAt,
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreezeServiceImpl_CreateFreezeWindow(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	now := testNow.UTC()
+
+	t.Run("Team window", func(t *testing.T) {
+		repo := new(FreezeWindowRepositoryMock)
+		teams := new(TeamRepositoryMock)
+		teamID, teamName := 7, "backend"
+
+		teams.On("GetTeamByName", ctx, "backend").Return(&domain.TeamWithMembers{ID: 7, Name: "backend"}, nil).Once()
+		repo.On("CreateFreezeWindow", ctx, &domain.FreezeWindow{
+			TeamID: &teamID, Reason: "release", StartsAt: now, EndsAt: now.Add(time.Hour), CreatedAt: now,
+		}).Return(&domain.FreezeWindow{
+			ID: 1, TeamID: &teamID, TeamName: &teamName, Reason: "release", StartsAt: now, EndsAt: now.Add(time.Hour), CreatedAt: now,
+		}, nil).Once()
+
+		s := NewFreezeService(repo, teams, logger, WithFreezeClock(fixedClock(testNow)))
+		window, err := s.CreateFreezeWindow(ctx, "backend", "release", testNow, testNow.Add(time.Hour))
+
+		require.NoError(t, err)
+		assert.Equal(t, &api.FreezeWindow{
+			FreezeId: 1, TeamId: &teamID, TeamName: &teamName, Reason: "release", StartsAt: now, EndsAt: now.Add(time.Hour), CreatedAt: now,
+		}, window)
+		repo.AssertExpectations(t)
+		teams.AssertExpectations(t)
+	})
+
+	t.Run("Unknown team", func(t *testing.T) {
+		teams := new(TeamRepositoryMock)
+		teams.On("GetTeamByName", ctx, "ghosts").Return(nil, apperrors.ErrNotFound).Once()
+
+		s := NewFreezeService(new(FreezeWindowRepositoryMock), teams, logger, WithFreezeClock(fixedClock(testNow)))
+		_, err := s.CreateFreezeWindow(ctx, "ghosts", "release", testNow, testNow.Add(time.Hour))
+
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	for _, tc := range []struct {
+		name             string
+		startsAt, endsAt time.Time
+	}{
+		{name: "Ends before it starts", startsAt: testNow.Add(time.Hour), endsAt: testNow},
+		{name: "Already ended", startsAt: testNow.Add(-2 * time.Hour), endsAt: testNow.Add(-time.Hour)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewFreezeService(new(FreezeWindowRepositoryMock), new(TeamRepositoryMock), logger, WithFreezeClock(fixedClock(testNow)))
+			_, err := s.CreateFreezeWindow(ctx, "", "release", tc.startsAt, tc.endsAt)
+
+			assert.ErrorIs(t, err, apperrors.ErrValidation)
+		})
+	}
+}
+
+func TestFreezeServiceImpl_ListFreezeWindows(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	now := testNow.UTC()
+
+	repo := new(FreezeWindowRepositoryMock)
+	teams := new(TeamRepositoryMock)
+	teamID := 7
+
+	teams.On("GetTeamByName", ctx, "backend").Return(&domain.TeamWithMembers{ID: 7, Name: "backend"}, nil).Once()
+	repo.On("ListFreezeWindows", ctx, domain.FreezeWindowFilter{TeamID: &teamID, EndsAfter: &now}).
+		Return([]domain.FreezeWindow{{ID: 1, Reason: "release"}}, nil).Once()
+	repo.On("ListFreezeWindows", ctx, domain.FreezeWindowFilter{}).Return([]domain.FreezeWindow{}, nil).Once()
+
+	s := NewFreezeService(repo, teams, logger, WithFreezeClock(fixedClock(testNow)))
+
+	resp, err := s.ListFreezeWindows(ctx, "backend", false)
+	require.NoError(t, err)
+	assert.Equal(t, []api.FreezeWindow{{FreezeId: 1, Reason: "release"}}, resp.Items)
+	assert.Equal(t, resp.Items, resp.Freezes)
+	require.NotNil(t, resp.TotalEstimate)
+	assert.Equal(t, 1, *resp.TotalEstimate)
+
+	resp, err = s.ListFreezeWindows(ctx, "", true)
+	require.NoError(t, err)
+	assert.Empty(t, resp.Items)
+	repo.AssertExpectations(t)
+}
+
+func TestPullRequestServiceImpl_MergePR_Freeze(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	prID := "pr-frozen"
+	mergedAt := testNow.UTC()
+	teamID := 7
+
+	frozen := []domain.FreezeWindow{
+		{ID: 1, TeamID: &teamID, Reason: "release", StartsAt: mergedAt.Add(-time.Hour), EndsAt: mergedAt.Add(time.Hour)},
+		{ID: 2, Reason: "holidays", StartsAt: mergedAt.Add(-time.Hour), EndsAt: mergedAt.Add(48 * time.Hour)},
+	}
+
+	testCases := []struct {
+		name          string
+		windows       []domain.FreezeWindow
+		authorErr     error
+		opts          MergeOptions
+		expectedError error
+		outcome       string
+	}{
+		{name: "No window in effect", windows: []domain.FreezeWindow{}},
+		{name: "Author without a team", windows: []domain.FreezeWindow{}, authorErr: apperrors.ErrNotFound},
+		{name: "Frozen", windows: frozen, expectedError: apperrors.ErrMergeFrozen, outcome: freezeRejected},
+		{name: "Freeze overridden", windows: frozen, opts: MergeOptions{OverrideFreeze: true}, outcome: freezeOverridden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transactorMock := new(TransactorMock)
+			prCmdMock := new(PRCommandRepositoryMock)
+			prQueryMock := new(PRQueryRepositoryMock)
+			userPRMock := new(UserPRRepositoryMock)
+			freezesMock := new(FreezeWindowRepositoryMock)
+
+			_, mockedTx, smock := newMockDBAndTx(t)
+
+			transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+			prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).
+				Return(&domain.PullRequest{ID: prID, AuthorID: "u1", Status: api.PullRequestStatusOPEN}, nil).Once()
+
+			authorTeamID := teamID
+			if tc.authorErr != nil {
+				authorTeamID = 0
+			}
+
+			userPRMock.On("GetAuthorTeamID", mock.Anything, "u1").Return(authorTeamID, tc.authorErr).Once()
+			freezesMock.On("ListFreezeWindows", mock.Anything, domain.FreezeWindowFilter{TeamID: &authorTeamID, ActiveAt: &mergedAt}).
+				Return(tc.windows, nil).Once()
+
+			if tc.expectedError != nil {
+				smock.ExpectRollback()
+			} else {
+				smock.ExpectCommit()
+				prCmdMock.On("UpdatePRStatus", inTx(mockedTx), prID, api.PullRequestStatusMERGED, mergedAt).Return(nil, nil).Once()
+				prQueryMock.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{}, nil).Once()
+				prQueryMock.On("GetStatsByUserIDs", inTx(mockedTx), []string{}).Return([]domain.Stats{}, nil).Once()
+			}
+
+			var before float64
+			if tc.outcome != "" {
+				before = testutil.ToFloat64(mergesFrozenTotal.WithLabelValues(tc.outcome))
+			}
+
+			service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, userPRMock, nil, nil,
+				WithMergeFreezes(freezesMock), WithClock(fixedClock(testNow)))
+			_, err := service.MergePR(ctx, prID, tc.opts)
+
+			if tc.expectedError != nil {
+				require.ErrorIs(t, err, tc.expectedError)
+
+				var frozenErr *apperrors.MergeFrozenError
+				require.True(t, errors.As(err, &frozenErr))
+				assert.Equal(t, "holidays", frozenErr.Reason, "the window ending last is reported")
+				assert.Equal(t, mergedAt.Add(48*time.Hour), frozenErr.Until)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tc.outcome != "" {
+				assert.Equal(t, before+1, testutil.ToFloat64(mergesFrozenTotal.WithLabelValues(tc.outcome)))
+			}
+
+			prCmdMock.AssertExpectations(t)
+			freezesMock.AssertExpectations(t)
+			assert.NoError(t, smock.ExpectationsWereMet())
+		})
+	}
+}
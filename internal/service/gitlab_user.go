@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// GitLabUserService manages the mapping of GitLab usernames to user IDs, through which the GitLab webhook
+// finds the author of a merge request. GitLab usernames are case-insensitive, so they are stored in lower case.
+type GitLabUserService interface {
+	// SetGitLabUser maps a GitLab username to a user, replacing its previous mapping.
+	// Returns apperrors.ErrNotFound if the user does not exist.
+	SetGitLabUser(ctx context.Context, gitLabUsername, userID string) (*api.GitLabUser, error)
+	// ResolveGitLabUser returns the ID of the user a GitLab username is mapped to.
+	// Returns apperrors.ErrNotFound if the username is not mapped.
+	ResolveGitLabUser(ctx context.Context, gitLabUsername string) (string, error)
+	// ListGitLabUsers returns every mapping ordered by GitLab username.
+	ListGitLabUsers(ctx context.Context) (*api.ListGitLabUsersResponse, error)
+	// DeleteGitLabUser removes the mapping of a GitLab username.
+	DeleteGitLabUser(ctx context.Context, gitLabUsername string) error
+}
+
+type GitLabUserServiceImpl struct {
+	BaseService
+	repo repository.GitLabUserRepository
+}
+
+// NewGitLabUserService creates a new instance of GitLabUserServiceImpl.
+func NewGitLabUserService(repo repository.GitLabUserRepository, log *slog.Logger) *GitLabUserServiceImpl {
+	return &GitLabUserServiceImpl{
+		BaseService: NewBaseService(nil, log),
+		repo:        repo,
+	}
+}
+
+func (s *GitLabUserServiceImpl) SetGitLabUser(ctx context.Context, gitLabUsername, userID string) (*api.GitLabUser, error) {
+	const op = "internal.service.gitlab_user.SetGitLabUser"
+
+	username := normalizeGitLabUsername(gitLabUsername)
+	if username == "" {
+		return nil, fmt.Errorf("%w: gitlab_username must not be blank", apperrors.ErrValidation)
+	}
+
+	stored, err := s.repo.SetGitLabUser(ctx, &domain.GitLabUser{GitLabUsername: username, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to set gitlab user: %w", op, err)
+	}
+
+	s.log.Info("gitlab user mapped", slog.String("op", op), slog.String("gitlab_username", username),
+		slog.String("user_id", userID))
+
+	return toAPIGitLabUser(stored), nil
+}
+
+func (s *GitLabUserServiceImpl) ResolveGitLabUser(ctx context.Context, gitLabUsername string) (string, error) {
+	const op = "internal.service.gitlab_user.ResolveGitLabUser"
+
+	mapping, err := s.repo.GetGitLabUser(ctx, normalizeGitLabUsername(gitLabUsername))
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to get gitlab user: %w", op, err)
+	}
+
+	return mapping.UserID, nil
+}
+
+func (s *GitLabUserServiceImpl) ListGitLabUsers(ctx context.Context) (*api.ListGitLabUsersResponse, error) {
+	const op = "internal.service.gitlab_user.ListGitLabUsers"
+
+	mappings, err := s.repo.ListGitLabUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list gitlab users: %w", op, err)
+	}
+
+	items := make([]api.GitLabUser, len(mappings))
+	for i := range mappings {
+		items[i] = *toAPIGitLabUser(&mappings[i])
+	}
+
+	return &api.ListGitLabUsersResponse{Items: items, TotalEstimate: totalOf(items)}, nil
+}
+
+func (s *GitLabUserServiceImpl) DeleteGitLabUser(ctx context.Context, gitLabUsername string) error {
+	const op = "internal.service.gitlab_user.DeleteGitLabUser"
+
+	username := normalizeGitLabUsername(gitLabUsername)
+
+	if err := s.repo.DeleteGitLabUser(ctx, username); err != nil {
+		return fmt.Errorf("%s: failed to delete gitlab user: %w", op, err)
+	}
+
+	s.log.Info("gitlab user unmapped", slog.String("op", op), slog.String("gitlab_username", username))
+
+	return nil
+}
+
+func normalizeGitLabUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+func toAPIGitLabUser(m *domain.GitLabUser) *api.GitLabUser {
+	return &api.GitLabUser{GitlabUsername: m.GitLabUsername, UserId: m.UserID, CreatedAt: m.CreatedAt}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitLabUserServiceImpl_SetGitLabUser(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	now := testNow.UTC()
+
+	t.Run("Username is stored in lower case", func(t *testing.T) {
+		repo := new(GitLabUserRepositoryMock)
+		repo.On("SetGitLabUser", ctx, &domain.GitLabUser{GitLabUsername: "jdoe", UserID: "u1"}).
+			Return(&domain.GitLabUser{GitLabUsername: "jdoe", UserID: "u1", CreatedAt: now}, nil).Once()
+
+		mapping, err := NewGitLabUserService(repo, logger).SetGitLabUser(ctx, " JDoe ", "u1")
+
+		require.NoError(t, err)
+		assert.Equal(t, &api.GitLabUser{GitlabUsername: "jdoe", UserId: "u1", CreatedAt: now}, mapping)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		repo := new(GitLabUserRepositoryMock)
+		repo.On("SetGitLabUser", ctx, &domain.GitLabUser{GitLabUsername: "jdoe", UserID: "ghost"}).Return(nil, apperrors.ErrNotFound).Once()
+
+		_, err := NewGitLabUserService(repo, logger).SetGitLabUser(ctx, "jdoe", "ghost")
+
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("Blank username", func(t *testing.T) {
+		_, err := NewGitLabUserService(new(GitLabUserRepositoryMock), logger).SetGitLabUser(ctx, "  ", "u1")
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+	})
+}
+
+func TestGitLabUserServiceImpl_ResolveGitLabUser(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	repo := new(GitLabUserRepositoryMock)
+	repo.On("GetGitLabUser", ctx, "jdoe").Return(&domain.GitLabUser{GitLabUsername: "jdoe", UserID: "u1"}, nil).Once()
+	repo.On("GetGitLabUser", ctx, "ghost").Return(nil, apperrors.ErrNotFound).Once()
+
+	s := NewGitLabUserService(repo, logger)
+
+	userID, err := s.ResolveGitLabUser(ctx, "JDoe")
+	require.NoError(t, err)
+	assert.Equal(t, "u1", userID)
+
+	_, err = s.ResolveGitLabUser(ctx, "ghost")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	repo.AssertExpectations(t)
+}
+
+func TestGitLabUserServiceImpl_ListGitLabUsers(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	repo := new(GitLabUserRepositoryMock)
+	repo.On("ListGitLabUsers", ctx).Return([]domain.GitLabUser{{GitLabUsername: "jdoe", UserID: "u1"}}, nil).Once()
+
+	resp, err := NewGitLabUserService(repo, logger).ListGitLabUsers(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, []api.GitLabUser{{GitlabUsername: "jdoe", UserId: "u1"}}, resp.Items)
+	assert.Nil(t, resp.NextCursor)
+	require.NotNil(t, resp.TotalEstimate)
+	assert.Equal(t, 1, *resp.TotalEstimate)
+}
+
+func TestGitLabUserServiceImpl_DeleteGitLabUser(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	repo := new(GitLabUserRepositoryMock)
+	repo.On("DeleteGitLabUser", ctx, "jdoe").Return(nil).Once()
+	repo.On("DeleteGitLabUser", ctx, "ghost").Return(apperrors.ErrNotFound).Once()
+
+	s := NewGitLabUserService(repo, logger)
+
+	require.NoError(t, s.DeleteGitLabUser(ctx, "JDoe"))
+	assert.ErrorIs(t, s.DeleteGitLabUser(ctx, "ghost"), apperrors.ErrNotFound)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// Mutations after which the reviewers are checked, the values of the operation label.
+const (
+	checkedCreate      = "create"
+	checkedReassign    = "reassign"
+	checkedMerge       = "merge"
+	checkedFillPending = "fill_pending"
+)
+
+// Reviewer invariant violations, the values of the violation label.
+const (
+	violationDuplicateReviewer = "duplicate_reviewer"
+	violationAuthorReviewer    = "author_reviewer"
+	violationTooManyReviewers  = "too_many_reviewers"
+	violationTooFewReviewers   = "too_few_reviewers"
+)
+
+// WithInvariantChecks makes the service read back the pull request after the given share of assignments,
+// reassignments and merges, rate between 0 and 1, and report reviewer sets that break the invariants
+// in the log and the reviewer_invariant_violations_total metric. A violation does not fail the mutation.
+func WithInvariantChecks(rate float64) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.invariantRate = rate
+	}
+}
+
+// checkReviewerInvariants reads back a committed pull request, if the mutation is sampled, and reports
+// every invariant its reviewers break. The invariants depend only on the stored state, so a concurrent
+// mutation of the same pull request cannot make the check report a violation that is not there.
+func (s *PullRequestServiceImpl) checkReviewerInvariants(ctx context.Context, operation string, prID string) {
+	const op = "internal.service.pullrequest.checkReviewerInvariants"
+
+	if s.invariantRate <= 0 || rand.Float64() >= s.invariantRate {
+		return
+	}
+
+	log := s.log.With(slog.String("op", op), slog.String("operation", operation), slog.String("pr_id", prID))
+
+	pr, err := s.prQuery.GetPRByIDWithReviewers(ctx, prID)
+	if err != nil {
+		log.Warn("failed to read pr for the reviewer invariant check", sl.Err(err))
+		return
+	}
+
+	for _, violation := range reviewerViolations(pr) {
+		reviewerInvariantViolationsTotal.WithLabelValues(operation, violation).Inc()
+
+		log.Error("reviewer invariant violated",
+			slog.String("violation", violation),
+			slog.String("author_id", pr.AuthorID),
+			slog.String("status", string(pr.Status)),
+			slog.Bool("need_more_reviewers", pr.NeedMoreReviewers),
+			slog.Any("reviewers", pr.ReviewerIDs),
+		)
+	}
+}
+
+// reviewerViolations returns the reviewer invariants the pull request breaks: a reviewer is assigned once,
+// the author never reviews their own pull request, and an open pull request has reviewersPerPR reviewers
+// unless it is flagged as needing more. A merged pull request keeps whatever reviewers it had.
+func reviewerViolations(pr *domain.PullRequest) []string {
+	var violations []string
+
+	unique := slices.Clone(pr.ReviewerIDs)
+	slices.Sort(unique)
+	unique = slices.Compact(unique)
+
+	if len(unique) != len(pr.ReviewerIDs) {
+		violations = append(violations, violationDuplicateReviewer)
+	}
+
+	if slices.Contains(unique, pr.AuthorID) {
+		violations = append(violations, violationAuthorReviewer)
+	}
+
+	if len(unique) > reviewersPerPR {
+		violations = append(violations, violationTooManyReviewers)
+	}
+
+	if pr.Status == api.PullRequestStatusOPEN && !pr.NeedMoreReviewers && len(unique) < reviewersPerPR {
+		violations = append(violations, violationTooFewReviewers)
+	}
+
+	return violations
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewerViolations(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pr       domain.PullRequest
+		expected []string
+	}{
+		{
+			name: "Open PR with two reviewers",
+			pr:   domain.PullRequest{AuthorID: "author", Status: api.PullRequestStatusOPEN, ReviewerIDs: []string{"rev1", "rev2"}},
+		},
+		{
+			name: "Open PR flagged as needing more reviewers",
+			pr:   domain.PullRequest{AuthorID: "author", Status: api.PullRequestStatusOPEN, NeedMoreReviewers: true, ReviewerIDs: []string{"rev1"}},
+		},
+		{
+			name: "Merged PR with one reviewer",
+			pr:   domain.PullRequest{AuthorID: "author", Status: api.PullRequestStatusMERGED, ReviewerIDs: []string{"rev1"}},
+		},
+		{
+			name:     "Duplicate reviewer",
+			pr:       domain.PullRequest{AuthorID: "author", Status: api.PullRequestStatusOPEN, ReviewerIDs: []string{"rev1", "rev1"}},
+			expected: []string{violationDuplicateReviewer, violationTooFewReviewers},
+		},
+		{
+			name:     "Author reviews their own PR",
+			pr:       domain.PullRequest{AuthorID: "author", Status: api.PullRequestStatusMERGED, ReviewerIDs: []string{"author", "rev1"}},
+			expected: []string{violationAuthorReviewer},
+		},
+		{
+			name:     "Too many reviewers",
+			pr:       domain.PullRequest{AuthorID: "author", Status: api.PullRequestStatusOPEN, ReviewerIDs: []string{"rev1", "rev2", "rev3"}},
+			expected: []string{violationTooManyReviewers},
+		},
+		{
+			name:     "Open PR without reviewers and not flagged",
+			pr:       domain.PullRequest{AuthorID: "author", Status: api.PullRequestStatusOPEN},
+			expected: []string{violationTooFewReviewers},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, reviewerViolations(&tc.pr))
+		})
+	}
+}
+
+func TestPullRequestServiceImpl_CheckReviewerInvariants(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	violations := reviewerInvariantViolationsTotal.WithLabelValues(checkedReassign, violationAuthorReviewer)
+
+	prQueryMock := new(PRQueryRepositoryMock)
+	prQueryMock.On("GetPRByIDWithReviewers", ctx, "pr-bad").Return(&domain.PullRequest{
+		ID: "pr-bad", AuthorID: "author", Status: api.PullRequestStatusOPEN, ReviewerIDs: []string{"author", "rev1"},
+	}, nil).Once()
+	prQueryMock.On("GetPRByIDWithReviewers", ctx, "pr-gone").Return(nil, errors.New("connection reset")).Once()
+
+	before := testutil.ToFloat64(violations)
+
+	unchecked := NewPullRequestService(nil, logger, nil, prQueryMock, nil, nil, nil)
+	unchecked.checkReviewerInvariants(ctx, checkedReassign, "pr-bad")
+	assert.Equal(t, before, testutil.ToFloat64(violations), "checks are disabled by default")
+
+	checked := NewPullRequestService(nil, logger, nil, prQueryMock, nil, nil, nil, WithInvariantChecks(1))
+	checked.checkReviewerInvariants(ctx, checkedReassign, "pr-bad")
+	assert.Equal(t, before+1, testutil.ToFloat64(violations))
+
+	checked.checkReviewerInvariants(ctx, checkedReassign, "pr-gone")
+	assert.Equal(t, before+1, testutil.ToFloat64(violations), "a failed read reports no violation")
+
+	prQueryMock.AssertExpectations(t)
+}
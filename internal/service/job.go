@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// JobService runs long-running operations in the background and reports their progress.
+type JobService interface {
+	// CreateJob queues a job of the given type. Returns apperrors.ErrValidation for a type
+	// that has no handler or parameters the handler rejects.
+	CreateJob(ctx context.Context, jobType api.JobType, params map[string]any) (*api.Job, error)
+	// GetJob returns the state and progress of a job.
+	GetJob(ctx context.Context, jobID int64) (*api.Job, error)
+	// CancelJob cancels a queued job and asks the worker of a running job to stop.
+	// Cancelling a cancelled job changes nothing. Returns apperrors.ErrJobFinished if the job has already succeeded or failed.
+	CancelJob(ctx context.Context, jobID int64) (*api.Job, error)
+	// RunNextJob claims a job for owner and runs it to the end, renewing its lease meanwhile.
+	// It reports false if no job was waiting.
+	RunNextJob(ctx context.Context, owner string) (bool, error)
+}
+
+// JobHandler runs the jobs of one type.
+type JobHandler interface {
+	// Validate checks the JSON parameters of a job before it is queued.
+	Validate(params []byte) error
+	// Run does the work of a job and returns its result, which must marshal to a JSON object.
+	// ctx is cancelled once the job must stop, and Report returns the reason; a stopped handler
+	// returns that error together with the result of the work done so far.
+	Run(ctx context.Context, params []byte, progress JobProgress) (any, error)
+}
+
+// JobProgress records how much of its job a handler has done.
+type JobProgress interface {
+	// Report stores the progress, done out of total, and returns an error if the job must stop.
+	// A zero total means the amount of work is not known in advance.
+	Report(ctx context.Context, done int, total int) error
+}
+
+// maxJobAttempts is how many times a job may be claimed; a job whose workers keep losing the lease is failed.
+const maxJobAttempts = 3
+
+// errJobCancelled stops a handler whose job has been cancelled.
+var errJobCancelled = errors.New("job cancelled")
+
+type JobServiceImpl struct {
+	BaseService
+	repo     repository.JobRepository
+	handlers map[domain.JobType]JobHandler
+	lease    time.Duration
+}
+
+// JobServiceOption configures optional behaviour of JobServiceImpl.
+type JobServiceOption func(*JobServiceImpl)
+
+// WithJobClock makes the service take the current time from c instead of the system clock.
+func WithJobClock(c Clock) JobServiceOption {
+	return func(s *JobServiceImpl) {
+		s.clock = c
+	}
+}
+
+// WithJobHandler makes the service accept and run jobs of jobType with h.
+func WithJobHandler(jobType domain.JobType, h JobHandler) JobServiceOption {
+	return func(s *JobServiceImpl) {
+		s.handlers[jobType] = h
+	}
+}
+
+// NewJobService creates a new instance of JobServiceImpl. A worker that has not renewed
+// the lease of its job for lease is presumed dead, and the job is claimed by another worker.
+func NewJobService(repo repository.JobRepository, lease time.Duration, log *slog.Logger, opts ...JobServiceOption) *JobServiceImpl {
+	s := &JobServiceImpl{
+		BaseService: NewBaseService(nil, log),
+		repo:        repo,
+		handlers:    make(map[domain.JobType]JobHandler),
+		lease:       lease,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *JobServiceImpl) CreateJob(ctx context.Context, jobType api.JobType, params map[string]any) (*api.Job, error) {
+	const op = "internal.service.job.CreateJob"
+
+	handler, ok := s.handlers[domain.JobType(jobType)]
+	if !ok {
+		return nil, fmt.Errorf("%w: jobs of type '%s' are not run by this service", apperrors.ErrValidation, jobType)
+	}
+
+	if params == nil {
+		params = map[string]any{}
+	}
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid params: %v", apperrors.ErrValidation, err)
+	}
+
+	if err := handler.Validate(raw); err != nil {
+		return nil, err
+	}
+
+	job, err := s.repo.CreateJob(ctx, &domain.Job{Type: domain.JobType(jobType), Params: raw, CreatedAt: s.now()})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to create job: %w", op, err)
+	}
+
+	s.log.Info("job queued", slog.String("op", op), slog.Int64("job_id", job.ID), slog.String("type", string(job.Type)))
+
+	return toAPIJob(job)
+}
+
+func (s *JobServiceImpl) GetJob(ctx context.Context, jobID int64) (*api.Job, error) {
+	const op = "internal.service.job.GetJob"
+
+	job, err := s.repo.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get job: %w", op, err)
+	}
+
+	return toAPIJob(job)
+}
+
+func (s *JobServiceImpl) CancelJob(ctx context.Context, jobID int64) (*api.Job, error) {
+	const op = "internal.service.job.CancelJob"
+
+	job, err := s.repo.CancelJob(ctx, jobID, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to cancel job: %w", op, err)
+	}
+
+	if job.Status == domain.JobSucceeded || job.Status == domain.JobFailed {
+		return nil, fmt.Errorf("%s: %w: job %d is %s", op, apperrors.ErrJobFinished, jobID, job.Status)
+	}
+
+	s.log.Info("job cancellation requested", slog.String("op", op), slog.Int64("job_id", jobID), slog.String("status", string(job.Status)))
+
+	return toAPIJob(job)
+}
+
+func (s *JobServiceImpl) RunNextJob(ctx context.Context, owner string) (bool, error) {
+	const op = "internal.service.job.RunNextJob"
+
+	now := s.now()
+
+	job, err := s.repo.ClaimJob(ctx, owner, now, now.Add(s.lease))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("%s: failed to claim job: %w", op, err)
+	}
+
+	log := s.log.With(slog.String("op", op), slog.Int64("job_id", job.ID), slog.String("type", string(job.Type)),
+		slog.String("owner", owner), slog.Int("attempt", job.Attempts))
+
+	handler, ok := s.handlers[job.Type]
+
+	switch {
+	case !ok:
+		return true, s.finishJob(ctx, job, owner, nil, fmt.Errorf("no handler for jobs of type '%s'", job.Type))
+	case job.Attempts > maxJobAttempts:
+		return true, s.finishJob(ctx, job, owner, nil, fmt.Errorf("abandoned by %d workers", maxJobAttempts))
+	}
+
+	log.Info("job started")
+
+	result, runErr := s.run(ctx, job, owner, handler)
+
+	switch {
+	case ctx.Err() != nil:
+		// The job stays running and is claimed again once its lease expires.
+		return true, fmt.Errorf("%s: %w", op, ctx.Err())
+	case errors.Is(runErr, apperrors.ErrJobLeaseLost):
+		log.Warn("job lease lost, leaving the job to its new owner")
+		return true, nil
+	}
+
+	if err := s.finishJob(ctx, job, owner, result, runErr); err != nil {
+		return true, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("job finished", slog.String("status", string(job.Status)))
+
+	return true, nil
+}
+
+// run runs the handler of a claimed job while renewing its lease in the background.
+// The handler is stopped when the job is cancelled or the lease is lost.
+func (s *JobServiceImpl) run(ctx context.Context, job *domain.Job, owner string, handler JobHandler) (any, error) {
+	runCtx, stop := context.WithCancelCause(ctx)
+	defer stop(nil)
+
+	progress := &jobProgress{s: s, jobID: job.ID, owner: owner, stop: stop, done: job.ProgressDone, total: job.ProgressTotal}
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		progress.heartbeat(runCtx, s.lease/3)
+	}()
+
+	result, err := handler.Run(runCtx, job.Params, progress)
+	if err != nil && runCtx.Err() != nil && ctx.Err() == nil {
+		// Report the reason the handler was stopped rather than the bare context error.
+		err = context.Cause(runCtx)
+	}
+
+	stop(nil)
+	wg.Wait()
+
+	job.ProgressDone, job.ProgressTotal = progress.current()
+
+	return result, err
+}
+
+// finishJob records the outcome of a job: succeeded without runErr, cancelled if the handler
+// was stopped on request and failed otherwise.
+func (s *JobServiceImpl) finishJob(ctx context.Context, job *domain.Job, owner string, result any, runErr error) error {
+	finishedAt := s.now()
+	job.Status, job.Error, job.FinishedAt = domain.JobSucceeded, nil, &finishedAt
+
+	switch {
+	case runErr == nil:
+		if job.ProgressTotal > 0 {
+			job.ProgressDone = job.ProgressTotal
+		}
+	case errors.Is(runErr, errJobCancelled):
+		job.Status = domain.JobCancelled
+	default:
+		message := runErr.Error()
+		job.Status, job.Error = domain.JobFailed, &message
+	}
+
+	job.Result = nil
+
+	if result != nil {
+		raw, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode job result: %w", err)
+		}
+
+		job.Result = raw
+	}
+
+	if err := s.repo.FinishJob(ctx, job, owner); err != nil {
+		return fmt.Errorf("failed to finish job: %w", err)
+	}
+
+	return nil
+}
+
+// jobProgress is the JobProgress of a running job. It keeps the last reported progress
+// and stores it with every renewal of the lease.
+type jobProgress struct {
+	s     *JobServiceImpl
+	jobID int64
+	owner string
+	stop  context.CancelCauseFunc
+
+	mu    sync.Mutex
+	done  int
+	total int
+}
+
+func (p *jobProgress) Report(ctx context.Context, done int, total int) error {
+	p.mu.Lock()
+	p.done, p.total = done, total
+	p.mu.Unlock()
+
+	return p.renew(ctx)
+}
+
+func (p *jobProgress) current() (int, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.done, p.total
+}
+
+// heartbeat renews the lease every interval until ctx is done, so that a handler busy
+// with a long step does not lose its job.
+func (p *jobProgress) heartbeat(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = p.renew(ctx)
+		}
+	}
+}
+
+// renew stores the progress and extends the lease. It stops the handler and returns the reason
+// if the job has been cancelled or another worker has taken it over.
+func (p *jobProgress) renew(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+
+	done, total := p.current()
+
+	cancelRequested, err := p.s.repo.RenewJobLease(ctx, p.jobID, p.owner, done, total, p.s.now().Add(p.s.lease))
+
+	switch {
+	case errors.Is(err, apperrors.ErrJobLeaseLost):
+		p.stop(err)
+		return err
+	case err != nil:
+		// The lease is still valid for a while; the next renewal tries again.
+		if ctx.Err() == nil {
+			p.s.log.Warn("failed to renew job lease", slog.Int64("job_id", p.jobID), sl.Err(err))
+		}
+
+		return nil
+	case cancelRequested:
+		p.stop(errJobCancelled)
+		return errJobCancelled
+	}
+
+	return nil
+}
+
+func toAPIJob(job *domain.Job) (*api.Job, error) {
+	resp := &api.Job{
+		JobId:           job.ID,
+		Type:            api.JobType(job.Type),
+		Status:          api.JobStatus(job.Status),
+		Params:          map[string]any{},
+		Progress:        api.JobProgress{Done: job.ProgressDone, Total: job.ProgressTotal},
+		Error:           job.Error,
+		CancelRequested: job.CancelRequested,
+		Attempts:        job.Attempts,
+		CreatedAt:       job.CreatedAt,
+		StartedAt:       job.StartedAt,
+		FinishedAt:      job.FinishedAt,
+	}
+
+	if len(job.Params) > 0 {
+		if err := json.Unmarshal(job.Params, &resp.Params); err != nil {
+			return nil, fmt.Errorf("failed to decode params of job %d: %w", job.ID, err)
+		}
+	}
+
+	if len(job.Result) > 0 {
+		var result map[string]any
+		if err := json.Unmarshal(job.Result, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode result of job %d: %w", job.ID, err)
+		}
+
+		resp.Result = &result
+	}
+
+	return resp, nil
+}
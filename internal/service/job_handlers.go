@@ -0,0 +1,406 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// maxImportTeams caps the number of teams a single team_import job creates.
+const maxImportTeams = 1000
+
+// decodeJobParams decodes the JSON parameters of a job into v, rejecting unknown fields.
+func decodeJobParams(params []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(params))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%w: invalid params: %v", apperrors.ErrValidation, err)
+	}
+
+	return nil
+}
+
+type teamImportParams struct {
+	Teams []api.Team `json:"teams"`
+}
+
+type teamImportResult struct {
+	Created []string `json:"created"`
+	// Skipped lists the teams that already existed.
+	Skipped []string `json:"skipped"`
+}
+
+type teamImportJob struct {
+	teams TeamService
+}
+
+// NewTeamImportJob returns the handler of team_import jobs, which create the teams of params.teams
+// one by one and skip the teams that already exist, so that a job run again does not fail on its own work.
+func NewTeamImportJob(teams TeamService) JobHandler {
+	return &teamImportJob{teams: teams}
+}
+
+func (j *teamImportJob) Validate(params []byte) error {
+	var p teamImportParams
+	if err := decodeJobParams(params, &p); err != nil {
+		return err
+	}
+
+	if len(p.Teams) == 0 || len(p.Teams) > maxImportTeams {
+		return fmt.Errorf("%w: teams must hold between 1 and %d teams", apperrors.ErrValidation, maxImportTeams)
+	}
+
+	names := make(map[string]bool, len(p.Teams))
+
+	for _, team := range p.Teams {
+		if team.TeamName == "" {
+			return fmt.Errorf("%w: team_name is required", apperrors.ErrValidation)
+		}
+
+		if names[team.TeamName] {
+			return fmt.Errorf("%w: team '%s' is listed twice", apperrors.ErrValidation, team.TeamName)
+		}
+
+		names[team.TeamName] = true
+	}
+
+	return nil
+}
+
+func (j *teamImportJob) Run(ctx context.Context, params []byte, progress JobProgress) (any, error) {
+	var p teamImportParams
+	if err := decodeJobParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	result := &teamImportResult{Created: []string{}, Skipped: []string{}}
+
+	for i, team := range p.Teams {
+		if err := progress.Report(ctx, i, len(p.Teams)); err != nil {
+			return result, err
+		}
+
+		_, err := j.teams.CreateTeamWithUsers(ctx, team)
+
+		switch {
+		case errors.Is(err, apperrors.ErrAlreadyExists):
+			result.Skipped = append(result.Skipped, team.TeamName)
+		case err != nil:
+			return result, fmt.Errorf("failed to create team '%s': %w", team.TeamName, err)
+		default:
+			result.Created = append(result.Created, team.TeamName)
+		}
+	}
+
+	return result, nil
+}
+
+type teamDeactivationParams struct {
+	TeamName  string `json:"team_name"`
+	BatchSize int    `json:"batch_size"`
+}
+
+type teamDeactivationJob struct {
+	users UserService
+	poll  time.Duration
+}
+
+// NewTeamDeactivationJob returns the handler of team_deactivation jobs, which start a batched deactivation
+// of params.team_name and follow it every poll until all its batches are reassigned.
+// The batches are reassigned by the deactivation workers, so a job cancelled after the members have been
+// deactivated only stops following it.
+func NewTeamDeactivationJob(users UserService, poll time.Duration) JobHandler {
+	return &teamDeactivationJob{users: users, poll: poll}
+}
+
+func (j *teamDeactivationJob) Validate(params []byte) error {
+	var p teamDeactivationParams
+	if err := decodeJobParams(params, &p); err != nil {
+		return err
+	}
+
+	if p.TeamName == "" {
+		return fmt.Errorf("%w: team_name is required", apperrors.ErrValidation)
+	}
+
+	if p.BatchSize < 1 || p.BatchSize > maxDeactivationBatchSize {
+		return fmt.Errorf("%w: batch_size must be between 1 and %d", apperrors.ErrValidation, maxDeactivationBatchSize)
+	}
+
+	return nil
+}
+
+func (j *teamDeactivationJob) Run(ctx context.Context, params []byte, progress JobProgress) (any, error) {
+	var p teamDeactivationParams
+	if err := decodeJobParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	if err := progress.Report(ctx, 0, 0); err != nil {
+		return nil, err
+	}
+
+	deactivation, err := j.users.DeactivateTeamInBatches(ctx, p.TeamName, p.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deactivate team '%s': %w", p.TeamName, err)
+	}
+
+	ticker := time.NewTicker(j.poll)
+	defer ticker.Stop()
+
+	for {
+		if err := progress.Report(ctx, deactivation.DoneBatches, deactivation.TotalBatches); err != nil {
+			return deactivation, err
+		}
+
+		if deactivation.Status == api.DeactivationJobSucceeded {
+			return deactivation, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return deactivation, context.Cause(ctx)
+		case <-ticker.C:
+		}
+
+		deactivation, err = j.users.GetDeactivationJob(ctx, deactivation.JobId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deactivation progress: %w", err)
+		}
+	}
+}
+
+type pendingBackfillParams struct {
+	BatchSize *int `json:"batch_size"`
+}
+
+type pendingBackfillResult struct {
+	RequeuedPullRequests int `json:"requeued_pull_requests"`
+	AssignedReviewers    int `json:"assigned_reviewers"`
+}
+
+type pendingBackfillJob struct {
+	prs PRCommandService
+}
+
+// NewPendingBackfillJob returns the handler of pending_backfill jobs, which queue the unqueued pull requests
+// needing reviewers and then fill the pending assignment queue in batches of params.batch_size pull requests
+// (100 by default) until a batch assigns no reviewer.
+func NewPendingBackfillJob(prs PRCommandService) JobHandler {
+	return &pendingBackfillJob{prs: prs}
+}
+
+func (j *pendingBackfillJob) Validate(params []byte) error {
+	var p pendingBackfillParams
+	if err := decodeJobParams(params, &p); err != nil {
+		return err
+	}
+
+	if p.BatchSize != nil && (*p.BatchSize < 1 || *p.BatchSize > maxPendingLimit) {
+		return fmt.Errorf("%w: batch_size must be between 1 and %d", apperrors.ErrValidation, maxPendingLimit)
+	}
+
+	return nil
+}
+
+func (j *pendingBackfillJob) Run(ctx context.Context, params []byte, progress JobProgress) (any, error) {
+	p := pendingBackfillParams{}
+	if err := decodeJobParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	batchSize := maxPendingLimit
+	if p.BatchSize != nil {
+		batchSize = *p.BatchSize
+	}
+
+	result := &pendingBackfillResult{}
+
+	requeued, err := j.prs.RequeueNeedingReviewers(ctx, batchSize)
+	result.RequeuedPullRequests = requeued
+
+	if err != nil {
+		return result, fmt.Errorf("failed to requeue pull requests needing reviewers: %w", err)
+	}
+
+	for {
+		if err := progress.Report(ctx, result.AssignedReviewers, 0); err != nil {
+			return result, err
+		}
+
+		assigned, err := j.prs.FillPendingAssignments(ctx, batchSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to fill pending assignments: %w", err)
+		}
+
+		if assigned == 0 {
+			return result, nil
+		}
+
+		result.AssignedReviewers += assigned
+	}
+}
+
+type statsExportJob struct {
+	prs PRQueryService
+}
+
+// NewStatsExportJob returns the handler of stats_export jobs, which store the review statistics
+// of /stats as their result.
+func NewStatsExportJob(prs PRQueryService) JobHandler {
+	return &statsExportJob{prs: prs}
+}
+
+func (j *statsExportJob) Validate(params []byte) error {
+	var p struct{}
+	return decodeJobParams(params, &p)
+}
+
+func (j *statsExportJob) Run(ctx context.Context, _ []byte, progress JobProgress) (any, error) {
+	if err := progress.Report(ctx, 0, 1); err != nil {
+		return nil, err
+	}
+
+	stats, err := j.prs.GetStats(ctx, domain.StatsPeriod{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// defaultStatsBackfillBatchSize is the number of pull requests a stats_backfill job backfills per transaction
+// unless its params say otherwise.
+const defaultStatsBackfillBatchSize = 100
+
+type statsBackfillParams struct {
+	BatchSize *int `json:"batch_size"`
+}
+
+type statsBackfillResult struct {
+	Batches          int `json:"batches"`
+	UpdatedReviewers int `json:"updated_reviewers"`
+	// StatsRefreshed is false if the precomputed statistics are disabled or were being refreshed elsewhere;
+	// the next refresh picks the backfilled durations up then.
+	StatsRefreshed bool `json:"stats_refreshed"`
+}
+
+type statsBackfillJob struct {
+	prs PRCommandService
+}
+
+// NewStatsBackfillJob returns the handler of stats_backfill jobs, which recompute the assignment and first review
+// times of the reviewers of every pull request from the assignment history, params.batch_size pull requests
+// (100 by default) per transaction in ID order, and then refresh the precomputed statistics of /stats.
+// The backfill changes nothing it has already done, so a job claimed again after a lost lease starts over.
+func NewStatsBackfillJob(prs PRCommandService) JobHandler {
+	return &statsBackfillJob{prs: prs}
+}
+
+func (j *statsBackfillJob) Validate(params []byte) error {
+	var p statsBackfillParams
+	if err := decodeJobParams(params, &p); err != nil {
+		return err
+	}
+
+	if p.BatchSize != nil && (*p.BatchSize < 1 || *p.BatchSize > maxStatsBackfillBatchSize) {
+		return fmt.Errorf("%w: batch_size must be between 1 and %d", apperrors.ErrValidation, maxStatsBackfillBatchSize)
+	}
+
+	return nil
+}
+
+func (j *statsBackfillJob) Run(ctx context.Context, params []byte, progress JobProgress) (any, error) {
+	var p statsBackfillParams
+	if err := decodeJobParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	batchSize := defaultStatsBackfillBatchSize
+	if p.BatchSize != nil {
+		batchSize = *p.BatchSize
+	}
+
+	result := &statsBackfillResult{}
+
+	for afterID := ""; ; {
+		if err := progress.Report(ctx, result.Batches, 0); err != nil {
+			return result, err
+		}
+
+		lastID, updated, err := j.prs.BackfillReviewerDurations(ctx, afterID, batchSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to backfill the pull requests after '%s': %w", afterID, err)
+		}
+
+		if lastID == "" {
+			break
+		}
+
+		result.Batches++
+		result.UpdatedReviewers += updated
+		afterID = lastID
+	}
+
+	refreshed, err := j.prs.RefreshStats(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to refresh stats: %w", err)
+	}
+
+	result.StatsRefreshed = refreshed
+
+	return result, nil
+}
+
+type reviewerRebalanceParams struct {
+	UserID string `json:"user_id"`
+}
+
+type reviewerRebalanceResult struct {
+	Moves []api.ReviewerMove `json:"moves"`
+}
+
+type reviewerRebalanceJob struct {
+	users UserService
+}
+
+// NewReviewerRebalanceJob returns the handler of reviewer_rebalance jobs, which move reviews of the most loaded
+// teammates of params.user_id to the user, as UserService.RebalanceReviews does.
+func NewReviewerRebalanceJob(users UserService) JobHandler {
+	return &reviewerRebalanceJob{users: users}
+}
+
+func (j *reviewerRebalanceJob) Validate(params []byte) error {
+	var p reviewerRebalanceParams
+	if err := decodeJobParams(params, &p); err != nil {
+		return err
+	}
+
+	if p.UserID == "" {
+		return fmt.Errorf("%w: user_id is required", apperrors.ErrValidation)
+	}
+
+	return nil
+}
+
+func (j *reviewerRebalanceJob) Run(ctx context.Context, params []byte, _ JobProgress) (any, error) {
+	var p reviewerRebalanceParams
+	if err := decodeJobParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	moves, err := j.users.RebalanceReviews(ctx, p.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rebalance reviews of '%s': %w", p.UserID, err)
+	}
+
+	return &reviewerRebalanceResult{Moves: moves}, nil
+}
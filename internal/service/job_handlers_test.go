@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordedProgress records the reports and stops the job at report number stopAt, if set.
+type recordedProgress struct {
+	reports [][2]int
+	stopAt  int
+}
+
+func (p *recordedProgress) Report(_ context.Context, done int, total int) error {
+	p.reports = append(p.reports, [2]int{done, total})
+	if len(p.reports) == p.stopAt {
+		return errJobCancelled
+	}
+
+	return nil
+}
+
+type stubTeamService struct {
+	TeamService
+	existing map[string]bool
+}
+
+func (s *stubTeamService) CreateTeamWithUsers(_ context.Context, team api.Team) (*api.Team, error) {
+	if s.existing[team.TeamName] {
+		return nil, &apperrors.TeamAlreadyExistsError{TeamName: team.TeamName}
+	}
+
+	s.existing[team.TeamName] = true
+
+	return &team, nil
+}
+
+type stubUserService struct {
+	UserService
+	progress []*api.DeactivationJob
+	moves    []api.ReviewerMove
+}
+
+func (s *stubUserService) DeactivateTeamInBatches(_ context.Context, _ string, _ int) (*api.DeactivationJob, error) {
+	return s.progress[0], nil
+}
+
+func (s *stubUserService) GetDeactivationJob(_ context.Context, _ int64) (*api.DeactivationJob, error) {
+	s.progress = s.progress[1:]
+	return s.progress[0], nil
+}
+
+func (s *stubUserService) RebalanceReviews(_ context.Context, _ string) ([]api.ReviewerMove, error) {
+	return s.moves, nil
+}
+
+type stubPRService struct {
+	PullRequestService
+	fills    []int
+	requeued int
+	// backfills are the last pull request IDs of the backfilled batches; every batch updates one reviewer.
+	backfills []string
+	refreshed bool
+}
+
+func (s *stubPRService) RequeueNeedingReviewers(_ context.Context, _ int) (int, error) {
+	return s.requeued, nil
+}
+
+func (s *stubPRService) FillPendingAssignments(_ context.Context, _ int) (int, error) {
+	assigned := s.fills[0]
+	s.fills = s.fills[1:]
+
+	return assigned, nil
+}
+
+func (s *stubPRService) BackfillReviewerDurations(_ context.Context, _ string, _ int) (string, int, error) {
+	if len(s.backfills) == 0 {
+		return "", 0, nil
+	}
+
+	lastID := s.backfills[0]
+	s.backfills = s.backfills[1:]
+
+	return lastID, 1, nil
+}
+
+func (s *stubPRService) RefreshStats(_ context.Context) (bool, error) {
+	return s.refreshed, nil
+}
+
+func TestTeamImportJob_Validate(t *testing.T) {
+	job := NewTeamImportJob(nil)
+
+	assert.NoError(t, job.Validate([]byte(`{"teams":[{"team_name":"backend","members":[]}]}`)))
+
+	for _, params := range []string{
+		`{}`,
+		`{"teams":[]}`,
+		`{"teams":[{"team_name":"","members":[]}]}`,
+		`{"teams":[{"team_name":"backend","members":[]},{"team_name":"backend","members":[]}]}`,
+		`{"teams":[{"team_name":"backend","members":[]}],"force":true}`,
+	} {
+		assert.ErrorIs(t, job.Validate([]byte(params)), apperrors.ErrValidation, params)
+	}
+}
+
+func TestTeamImportJob_Run(t *testing.T) {
+	ctx := context.Background()
+	params := []byte(`{"teams":[{"team_name":"backend","members":[]},{"team_name":"payments","members":[]},{"team_name":"mobile","members":[]}]}`)
+
+	teams := &stubTeamService{existing: map[string]bool{"payments": true}}
+	progress := &recordedProgress{}
+
+	result, err := NewTeamImportJob(teams).Run(ctx, params, progress)
+	require.NoError(t, err)
+	assert.Equal(t, &teamImportResult{Created: []string{"backend", "mobile"}, Skipped: []string{"payments"}}, result)
+	assert.Equal(t, [][2]int{{0, 3}, {1, 3}, {2, 3}}, progress.reports)
+
+	// A cancelled import returns what it has done so far.
+	teams = &stubTeamService{existing: map[string]bool{}}
+
+	result, err = NewTeamImportJob(teams).Run(ctx, params, &recordedProgress{stopAt: 2})
+	assert.ErrorIs(t, err, errJobCancelled)
+	assert.Equal(t, &teamImportResult{Created: []string{"backend"}, Skipped: []string{}}, result)
+}
+
+func TestTeamDeactivationJob_Run(t *testing.T) {
+	ctx := context.Background()
+
+	users := &stubUserService{progress: []*api.DeactivationJob{
+		{JobId: 5, Status: api.DeactivationJobRunning, TotalBatches: 2},
+		{JobId: 5, Status: api.DeactivationJobRunning, TotalBatches: 2, DoneBatches: 1},
+		{JobId: 5, Status: api.DeactivationJobSucceeded, TotalBatches: 2, DoneBatches: 2},
+	}}
+	progress := &recordedProgress{}
+
+	job := NewTeamDeactivationJob(users, time.Millisecond)
+	require.NoError(t, job.Validate([]byte(`{"team_name":"backend","batch_size":10}`)))
+	assert.ErrorIs(t, job.Validate([]byte(`{"team_name":"backend","batch_size":0}`)), apperrors.ErrValidation)
+
+	result, err := job.Run(ctx, []byte(`{"team_name":"backend","batch_size":10}`), progress)
+	require.NoError(t, err)
+	assert.Equal(t, api.DeactivationJobSucceeded, result.(*api.DeactivationJob).Status)
+	assert.Equal(t, [][2]int{{0, 0}, {0, 2}, {1, 2}, {2, 2}}, progress.reports)
+}
+
+func TestPendingBackfillJob_Run(t *testing.T) {
+	ctx := context.Background()
+	job := NewPendingBackfillJob(&stubPRService{fills: []int{3, 2, 0}, requeued: 4})
+
+	assert.ErrorIs(t, job.Validate([]byte(`{"batch_size":101}`)), apperrors.ErrValidation)
+
+	progress := &recordedProgress{}
+
+	result, err := job.Run(ctx, []byte(`{}`), progress)
+	require.NoError(t, err)
+	assert.Equal(t, &pendingBackfillResult{RequeuedPullRequests: 4, AssignedReviewers: 5}, result)
+	assert.Equal(t, fmt.Sprint([][2]int{{0, 0}, {3, 0}, {5, 0}}), fmt.Sprint(progress.reports))
+}
+
+func TestStatsBackfillJob_Run(t *testing.T) {
+	ctx := context.Background()
+	job := NewStatsBackfillJob(&stubPRService{backfills: []string{"pr-2", "pr-4"}, refreshed: true})
+
+	require.NoError(t, job.Validate([]byte(`{}`)))
+	require.NoError(t, job.Validate([]byte(`{"batch_size":1000}`)))
+	assert.ErrorIs(t, job.Validate([]byte(`{"batch_size":1001}`)), apperrors.ErrValidation)
+	assert.ErrorIs(t, job.Validate([]byte(`{"after_id":"pr-2"}`)), apperrors.ErrValidation)
+
+	progress := &recordedProgress{}
+
+	result, err := job.Run(ctx, []byte(`{"batch_size":2}`), progress)
+	require.NoError(t, err)
+	assert.Equal(t, &statsBackfillResult{Batches: 2, UpdatedReviewers: 2, StatsRefreshed: true}, result)
+	assert.Equal(t, [][2]int{{0, 0}, {1, 0}, {2, 0}}, progress.reports)
+
+	// A cancelled backfill returns what it has done so far and leaves the statistics to the refresher.
+	job = NewStatsBackfillJob(&stubPRService{backfills: []string{"pr-2", "pr-4"}, refreshed: true})
+
+	result, err = job.Run(ctx, []byte(`{}`), &recordedProgress{stopAt: 2})
+	assert.ErrorIs(t, err, errJobCancelled)
+	assert.Equal(t, &statsBackfillResult{Batches: 1, UpdatedReviewers: 1}, result)
+}
+
+func TestReviewerRebalanceJob_Run(t *testing.T) {
+	ctx := context.Background()
+	job := NewReviewerRebalanceJob(&stubUserService{moves: []api.ReviewerMove{{PullRequestId: "pr-3", FromUserId: "u2", ToUserId: "u1"}}})
+
+	assert.ErrorIs(t, job.Validate([]byte(`{}`)), apperrors.ErrValidation)
+	require.NoError(t, job.Validate([]byte(`{"user_id":"u1"}`)))
+
+	result, err := job.Run(ctx, []byte(`{"user_id":"u1"}`), &recordedProgress{})
+	require.NoError(t, err)
+	assert.Equal(t, &reviewerRebalanceResult{Moves: []api.ReviewerMove{{PullRequestId: "pr-3", FromUserId: "u2", ToUserId: "u1"}}}, result)
+}
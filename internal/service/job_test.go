@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// jobHandlerFunc is a JobHandler that accepts any parameters and runs the function.
+type jobHandlerFunc func(ctx context.Context, params []byte, progress JobProgress) (any, error)
+
+func (f jobHandlerFunc) Validate([]byte) error { return nil }
+
+func (f jobHandlerFunc) Run(ctx context.Context, params []byte, progress JobProgress) (any, error) {
+	return f(ctx, params, progress)
+}
+
+func TestJobServiceImpl_CreateJob(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	repo := new(JobRepositoryMock)
+	repo.On("CreateJob", ctx, &domain.Job{Type: domain.JobStatsExport, Params: []byte(`{}`), CreatedAt: testNow.UTC()}).
+		Return(&domain.Job{ID: 3, Type: domain.JobStatsExport, Status: domain.JobQueued, Params: []byte(`{}`), CreatedAt: testNow.UTC()}, nil).Once()
+
+	service := NewJobService(repo, time.Minute, logger,
+		WithJobClock(fixedClock(testNow)), WithJobHandler(domain.JobStatsExport, NewStatsExportJob(nil)))
+
+	job, err := service.CreateJob(ctx, api.JobTypeStatsExport, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), job.JobId)
+	assert.Equal(t, api.JobQueued, job.Status)
+	assert.Equal(t, map[string]any{}, job.Params)
+	assert.Nil(t, job.Result)
+
+	_, err = service.CreateJob(ctx, api.JobTypeStatsExport, map[string]any{"format": "csv"})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	_, err = service.CreateJob(ctx, api.JobTypeTeamImport, map[string]any{})
+	assert.ErrorIs(t, err, apperrors.ErrValidation, "a type without a handler is rejected")
+
+	repo.AssertExpectations(t)
+}
+
+func TestJobServiceImpl_CancelJob(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	repo := new(JobRepositoryMock)
+	repo.On("CancelJob", ctx, int64(1), testNow.UTC()).
+		Return(&domain.Job{ID: 1, Type: domain.JobStatsExport, Status: domain.JobRunning, CancelRequested: true}, nil).Once()
+	repo.On("CancelJob", ctx, int64(2), testNow.UTC()).
+		Return(&domain.Job{ID: 2, Type: domain.JobStatsExport, Status: domain.JobSucceeded}, nil).Once()
+	repo.On("CancelJob", ctx, int64(3), testNow.UTC()).
+		Return(nil, fmt.Errorf("cancel: %w", apperrors.ErrNotFound)).Once()
+
+	service := NewJobService(repo, time.Minute, logger, WithJobClock(fixedClock(testNow)))
+
+	job, err := service.CancelJob(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, api.JobRunning, job.Status)
+	assert.True(t, job.CancelRequested)
+
+	_, err = service.CancelJob(ctx, 2)
+	assert.ErrorIs(t, err, apperrors.ErrJobFinished)
+
+	_, err = service.CancelJob(ctx, 3)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	repo.AssertExpectations(t)
+}
+
+func TestJobServiceImpl_RunNextJob(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	now, leaseUntil := testNow.UTC(), testNow.UTC().Add(time.Minute)
+
+	claimed := func(attempts int) *domain.Job {
+		return &domain.Job{ID: 7, Type: domain.JobTeamImport, Status: domain.JobRunning, Params: []byte(`{}`), Attempts: attempts}
+	}
+
+	// reportAndReturn reports one of two steps done and returns what the handler would.
+	reportAndReturn := func(result any, err error) jobHandlerFunc {
+		return func(ctx context.Context, _ []byte, progress JobProgress) (any, error) {
+			if reportErr := progress.Report(ctx, 1, 2); reportErr != nil {
+				return result, reportErr
+			}
+
+			return result, err
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		handler     jobHandlerFunc
+		setupMocks  func(repo *JobRepositoryMock)
+		expectedRan bool
+	}{
+		{
+			name: "Nothing to run",
+			setupMocks: func(repo *JobRepositoryMock) {
+				repo.On("ClaimJob", ctx, "w1", now, leaseUntil).Return(nil, fmt.Errorf("claim: %w", apperrors.ErrNotFound)).Once()
+			},
+		},
+		{
+			name:    "Success stores the result and full progress",
+			handler: reportAndReturn(map[string]int{"created": 2}, nil),
+			setupMocks: func(repo *JobRepositoryMock) {
+				repo.On("ClaimJob", ctx, "w1", now, leaseUntil).Return(claimed(1), nil).Once()
+				repo.On("RenewJobLease", mock.Anything, int64(7), "w1", 1, 2, leaseUntil).Return(false, nil).Once()
+				repo.On("FinishJob", ctx, mock.MatchedBy(func(job *domain.Job) bool {
+					return job.Status == domain.JobSucceeded && string(job.Result) == `{"created":2}` &&
+						job.ProgressDone == 2 && job.ProgressTotal == 2 && job.Error == nil && now.Equal(*job.FinishedAt)
+				}), "w1").Return(nil).Once()
+			},
+			expectedRan: true,
+		},
+		{
+			name:    "Cancellation stops the handler",
+			handler: reportAndReturn(map[string]int{"created": 1}, nil),
+			setupMocks: func(repo *JobRepositoryMock) {
+				repo.On("ClaimJob", ctx, "w1", now, leaseUntil).Return(claimed(1), nil).Once()
+				repo.On("RenewJobLease", mock.Anything, int64(7), "w1", 1, 2, leaseUntil).Return(true, nil).Once()
+				repo.On("FinishJob", ctx, mock.MatchedBy(func(job *domain.Job) bool {
+					return job.Status == domain.JobCancelled && string(job.Result) == `{"created":1}` && job.ProgressDone == 1
+				}), "w1").Return(nil).Once()
+			},
+			expectedRan: true,
+		},
+		{
+			name:    "Handler error fails the job",
+			handler: reportAndReturn(nil, errors.New("team 'backend': boom")),
+			setupMocks: func(repo *JobRepositoryMock) {
+				repo.On("ClaimJob", ctx, "w1", now, leaseUntil).Return(claimed(1), nil).Once()
+				repo.On("RenewJobLease", mock.Anything, int64(7), "w1", 1, 2, leaseUntil).Return(false, nil).Once()
+				repo.On("FinishJob", ctx, mock.MatchedBy(func(job *domain.Job) bool {
+					return job.Status == domain.JobFailed && job.Result == nil && *job.Error == "team 'backend': boom"
+				}), "w1").Return(nil).Once()
+			},
+			expectedRan: true,
+		},
+		{
+			name:    "Lost lease leaves the job to its new owner",
+			handler: reportAndReturn(nil, nil),
+			setupMocks: func(repo *JobRepositoryMock) {
+				repo.On("ClaimJob", ctx, "w1", now, leaseUntil).Return(claimed(2), nil).Once()
+				repo.On("RenewJobLease", mock.Anything, int64(7), "w1", 1, 2, leaseUntil).
+					Return(false, fmt.Errorf("renew: %w", apperrors.ErrJobLeaseLost)).Once()
+			},
+			expectedRan: true,
+		},
+		{
+			name: "Job abandoned too often is failed without running",
+			handler: func(context.Context, []byte, JobProgress) (any, error) {
+				panic("must not run")
+			},
+			setupMocks: func(repo *JobRepositoryMock) {
+				repo.On("ClaimJob", ctx, "w1", now, leaseUntil).Return(claimed(maxJobAttempts+1), nil).Once()
+				repo.On("FinishJob", ctx, mock.MatchedBy(func(job *domain.Job) bool {
+					return job.Status == domain.JobFailed && *job.Error == "abandoned by 3 workers"
+				}), "w1").Return(nil).Once()
+			},
+			expectedRan: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(JobRepositoryMock)
+			tc.setupMocks(repo)
+
+			service := NewJobService(repo, time.Minute, logger,
+				WithJobClock(fixedClock(testNow)), WithJobHandler(domain.JobTeamImport, tc.handler))
+
+			ran, err := service.RunNextJob(ctx, "w1")
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRan, ran)
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package service
 
 import (
+	"context"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
 	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -23,3 +26,11 @@ func newMockDBAndTx(t *testing.T) (*sqlx.DB, *sqlx.Tx, sqlmock.Sqlmock) {
 
 	return sqlxDB, tx, smock
 }
+
+// inTx matches the context of a repository call made in the transaction tx.
+func inTx(tx *sqlx.Tx) any {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		ctxTx, ok := txctx.From(ctx)
+		return ok && ctxTx == tx
+	})
+}
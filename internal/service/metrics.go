@@ -0,0 +1,26 @@
+package service
+
+import (
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	prsCreatedTotal = promauto.NewCounter(metrics.PullRequestsCreated.CounterOpts())
+	prsMergedTotal  = promauto.NewCounter(metrics.PullRequestsMerged.CounterOpts())
+	prsClosedTotal  = promauto.NewCounter(metrics.PullRequestsClosed.CounterOpts())
+
+	mergesFrozenTotal = promauto.NewCounterVec(metrics.MergesFrozen.CounterOpts(), metrics.MergesFrozen.Labels)
+
+	reviewersAssignedTotal     = promauto.NewCounterVec(metrics.ReviewersAssigned.CounterOpts(), metrics.ReviewersAssigned.Labels)
+	reviewerReassignmentsTotal = promauto.NewCounterVec(metrics.ReviewerReassignments.CounterOpts(), metrics.ReviewerReassignments.Labels)
+	reviewsRebalancedTotal     = promauto.NewCounter(metrics.ReviewsRebalanced.CounterOpts())
+
+	pendingBackfillPRsTotal     = promauto.NewCounterVec(metrics.PendingBackfillPullRequests.CounterOpts(), metrics.PendingBackfillPullRequests.Labels)
+	pendingReviewersFilledTotal = promauto.NewCounter(metrics.PendingReviewersFilled.CounterOpts())
+
+	webhookDeliveryAttemptsTotal = promauto.NewCounterVec(metrics.WebhookDeliveryAttempts.CounterOpts(), metrics.WebhookDeliveryAttempts.Labels)
+	outboxPublishAttemptsTotal   = promauto.NewCounterVec(metrics.OutboxPublishAttempts.CounterOpts(), metrics.OutboxPublishAttempts.Labels)
+
+	reviewerInvariantViolationsTotal = promauto.NewCounterVec(metrics.ReviewerInvariantViolations.CounterOpts(), metrics.ReviewerInvariantViolations.Labels)
+)
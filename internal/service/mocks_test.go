@@ -3,10 +3,12 @@ package service
 import (
 	"context"
 	"database/sql"
+	"log/slog"
 	"time"
 
 	"github.com/YusovID/pr-reviewer-service/internal/domain"
 	"github.com/YusovID/pr-reviewer-service/internal/repository"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/mock"
@@ -18,8 +20,8 @@ type TeamRepositoryMock struct {
 
 var _ repository.TeamRepository = (*TeamRepositoryMock)(nil)
 
-func (m *TeamRepositoryMock) GetTeamByName(ctx context.Context, ext sqlx.ExtContext, name string) (*domain.TeamWithMembers, error) {
-	args := m.Called(ctx, ext, name)
+func (m *TeamRepositoryMock) GetTeamByName(ctx context.Context, name string) (*domain.TeamWithMembers, error) {
+	args := m.Called(ctx, name)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -27,6 +29,39 @@ func (m *TeamRepositoryMock) GetTeamByName(ctx context.Context, ext sqlx.ExtCont
 	return args.Get(0).(*domain.TeamWithMembers), args.Error(1)
 }
 
+func (m *TeamRepositoryMock) GetTeamByID(ctx context.Context, id int) (*domain.TeamWithMembers, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.TeamWithMembers), args.Error(1)
+}
+
+func (m *TeamRepositoryMock) RenameTeam(ctx context.Context, id int, newName string) error {
+	args := m.Called(ctx, id, newName)
+	return args.Error(0)
+}
+
+func (m *TeamRepositoryMock) TryLockTeamForDeactivation(ctx context.Context, teamID int) (bool, error) {
+	args := m.Called(ctx, teamID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *TeamRepositoryMock) DeleteTeam(ctx context.Context, teamID int, deletedAt time.Time) ([]string, error) {
+	args := m.Called(ctx, teamID, deletedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *TeamRepositoryMock) RestoreTeam(ctx context.Context, teamID int) error {
+	args := m.Called(ctx, teamID)
+	return args.Error(0)
+}
+
 func (m *TeamRepositoryMock) CreateTeamWithUsers(ctx context.Context, team api.Team) (*domain.TeamWithMembers, error) {
 	args := m.Called(ctx, team)
 	if args.Get(0) == nil {
@@ -42,14 +77,14 @@ type UserRepositoryMock struct {
 
 var _ repository.UserRepository = (*UserRepositoryMock)(nil)
 
-func (m *UserRepositoryMock) SetIsActive(ctx context.Context, userID string, isActive bool) (*api.User, error) {
+func (m *UserRepositoryMock) SetIsActive(ctx context.Context, userID string, isActive bool) (*api.User, bool, error) {
 	args := m.Called(ctx, userID, isActive)
 
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, false, args.Error(2)
 	}
 
-	return args.Get(0).(*api.User), args.Error(1)
+	return args.Get(0).(*api.User), args.Bool(1), args.Error(2)
 }
 
 type TxMock struct {
@@ -70,31 +105,64 @@ type PRCommandRepositoryMock struct {
 	mock.Mock
 }
 
-func (m *PRCommandRepositoryMock) CreatePR(ctx context.Context, tx *sqlx.Tx, pr *domain.PullRequest) error {
-	args := m.Called(ctx, tx, pr)
+func (m *PRCommandRepositoryMock) CreatePR(ctx context.Context, pr *domain.PullRequest) error {
+	args := m.Called(ctx, pr)
 	return args.Error(0)
 }
-func (m *PRCommandRepositoryMock) AssignReviewers(ctx context.Context, tx *sqlx.Tx, prID string, reviewerIDs []string) error {
-	args := m.Called(ctx, tx, prID, reviewerIDs)
+func (m *PRCommandRepositoryMock) AssignReviewers(ctx context.Context, prID string, reviewerIDs []string) error {
+	args := m.Called(ctx, prID, reviewerIDs)
 	return args.Error(0)
 }
-func (m *PRCommandRepositoryMock) GetPRByIDWithLock(ctx context.Context, tx *sqlx.Tx, prID string) (*domain.PullRequest, error) {
-	args := m.Called(ctx, tx, prID)
+func (m *PRCommandRepositoryMock) LockActiveUsers(ctx context.Context, userIDs []string) ([]string, error) {
+	args := m.Called(ctx, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]string), args.Error(1)
+}
+func (m *PRCommandRepositoryMock) GetPRByIDWithLock(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	args := m.Called(ctx, prID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 
 	return args.Get(0).(*domain.PullRequest), args.Error(1)
 }
-func (m *PRCommandRepositoryMock) UpdatePRStatus(ctx context.Context, tx *sqlx.Tx, prID string, status api.PullRequestStatus, mergedAt time.Time) error {
-	args := m.Called(ctx, tx, prID, status, mergedAt)
+func (m *PRCommandRepositoryMock) UpdatePRStatus(ctx context.Context, prID string, status api.PullRequestStatus, mergedAt time.Time) (*time.Time, error) {
+	args := m.Called(ctx, prID, status, mergedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+func (m *PRCommandRepositoryMock) ReplaceReviewer(ctx context.Context, prID string, oldReviewerID string, newReviewerID string) error {
+	args := m.Called(ctx, prID, oldReviewerID, newReviewerID)
 	return args.Error(0)
 }
-func (m *PRCommandRepositoryMock) ReplaceReviewer(ctx context.Context, tx *sqlx.Tx, prID string, oldReviewerID string, newReviewerID string) error {
-	args := m.Called(ctx, tx, prID, oldReviewerID, newReviewerID)
+func (m *PRCommandRepositoryMock) ReplaceReviewers(ctx context.Context, replacements []domain.ReviewerReplacement) error {
+	args := m.Called(ctx, replacements)
+	return args.Error(0)
+}
+func (m *PRCommandRepositoryMock) CountOpenPRsByAuthor(ctx context.Context, authorID string) (int, error) {
+	args := m.Called(ctx, authorID)
+	return args.Int(0), args.Error(1)
+}
+func (m *PRCommandRepositoryMock) SetNeedMoreReviewers(ctx context.Context, prID string, need bool) error {
+	args := m.Called(ctx, prID, need)
 	return args.Error(0)
 }
 
+func (m *PRCommandRepositoryMock) SetReviewState(ctx context.Context, prID string, reviewerID string, state domain.ReviewState, reviewedAt time.Time) error {
+	return m.Called(ctx, prID, reviewerID, state, reviewedAt).Error(0)
+}
+
+func (m *PRCommandRepositoryMock) BackfillReviewerDurations(ctx context.Context, afterID string, limit int) (string, int, error) {
+	args := m.Called(ctx, afterID, limit)
+	return args.String(0), args.Int(1), args.Error(2)
+}
+
 type PRQueryRepositoryMock struct {
 	mock.Mock
 }
@@ -107,6 +175,15 @@ func (m *PRQueryRepositoryMock) GetPRByID(ctx context.Context, prID string) (*do
 
 	return args.Get(0).(*domain.PullRequest), args.Error(1)
 }
+func (m *PRQueryRepositoryMock) GetStatsByUserIDs(ctx context.Context, userIDs []string) ([]domain.Stats, error) {
+	args := m.Called(ctx, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.Stats), args.Error(1)
+}
+
 func (m *PRQueryRepositoryMock) GetPRByIDWithReviewers(ctx context.Context, prID string) (*domain.PullRequest, error) {
 	args := m.Called(ctx, prID)
 	if args.Get(0) == nil {
@@ -115,16 +192,25 @@ func (m *PRQueryRepositoryMock) GetPRByIDWithReviewers(ctx context.Context, prID
 
 	return args.Get(0).(*domain.PullRequest), args.Error(1)
 }
-func (m *PRQueryRepositoryMock) GetReviewerIDs(ctx context.Context, ext sqlx.ExtContext, prID string) ([]string, error) {
-	args := m.Called(ctx, ext, prID)
+func (m *PRQueryRepositoryMock) GetReviewerIDs(ctx context.Context, prID string) ([]string, error) {
+	args := m.Called(ctx, prID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 
 	return args.Get(0).([]string), args.Error(1)
 }
-func (m *PRQueryRepositoryMock) GetReviewAssignments(ctx context.Context, userID string) ([]domain.PullRequest, error) {
-	args := m.Called(ctx, userID)
+func (m *PRQueryRepositoryMock) GetReviews(ctx context.Context, prID string) ([]domain.Review, error) {
+	args := m.Called(ctx, prID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.Review), args.Error(1)
+}
+
+func (m *PRQueryRepositoryMock) GetReviewAssignments(ctx context.Context, userID string, customFields map[string]string) ([]domain.PullRequest, error) {
+	args := m.Called(ctx, userID, customFields)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -132,8 +218,8 @@ func (m *PRQueryRepositoryMock) GetReviewAssignments(ctx context.Context, userID
 	return args.Get(0).([]domain.PullRequest), args.Error(1)
 }
 
-func (m *PRQueryRepositoryMock) GetOpenPRsByReviewers(ctx context.Context, tx *sqlx.Tx, userIDs []string) ([]domain.PullRequest, error) {
-	args := m.Called(ctx, tx, userIDs)
+func (m *PRQueryRepositoryMock) GetOpenPRsByReviewers(ctx context.Context, userIDs []string) ([]domain.PullRequest, error) {
+	args := m.Called(ctx, userIDs)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -149,8 +235,20 @@ func (m *UserPRRepositoryMock) GetAuthorTeamID(ctx context.Context, authorID str
 	args := m.Called(ctx, authorID)
 	return args.Int(0), args.Error(1)
 }
-func (m *UserPRRepositoryMock) GetReviewerTeamID(ctx context.Context, reviewerID string) (int, error) {
-	args := m.Called(ctx, reviewerID)
+func (m *UserPRRepositoryMock) IsUserActive(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+func (m *UserPRRepositoryMock) GetUserTeamIDs(ctx context.Context, userID string) ([]int, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]int), args.Error(1)
+}
+func (m *UserPRRepositoryMock) GetPRTeamID(ctx context.Context, prID string) (int, error) {
+	args := m.Called(ctx, prID)
 	return args.Int(0), args.Error(1)
 }
 func (m *UserPRRepositoryMock) GetRandomActiveReviewers(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error) {
@@ -177,8 +275,31 @@ func (m *TransactorMock) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sq
 	return tx, args.Error(1)
 }
 
-func (m *PRQueryRepositoryMock) GetUserStats(ctx context.Context) ([]domain.Stats, error) {
-	args := m.Called(ctx)
+// Do runs fn in a transaction begun through BeginTxx, as txctx.Manager does on a database.
+func (m *TransactorMock) Do(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	return txctx.NewManager(m, slog.New(slog.DiscardHandler)).Do(ctx, opts, fn)
+}
+
+func (m *PRQueryRepositoryMock) SearchPRs(ctx context.Context, filter domain.PRSearchFilter) ([]domain.PullRequest, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+
+	return args.Get(0).([]domain.PullRequest), args.Int(1), args.Error(2)
+}
+
+func (m *PRQueryRepositoryMock) ListPRs(ctx context.Context, filter domain.PRListFilter) ([]domain.PullRequest, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.PullRequest), args.Error(1)
+}
+
+func (m *PRQueryRepositoryMock) GetUserStats(ctx context.Context, period domain.StatsPeriod) ([]domain.Stats, error) {
+	args := m.Called(ctx, period)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -186,11 +307,908 @@ func (m *PRQueryRepositoryMock) GetUserStats(ctx context.Context) ([]domain.Stat
 	return args.Get(0).([]domain.Stats), args.Error(1)
 }
 
-func (m *UserRepositoryMock) DeactivateUsersByTeamID(ctx context.Context, tx *sqlx.Tx, teamID int) ([]string, error) {
-	args := m.Called(ctx, tx, teamID)
+func (m *PRQueryRepositoryMock) GetTeamStats(ctx context.Context, period domain.StatsPeriod) ([]domain.TeamStats, error) {
+	args := m.Called(ctx, period)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.TeamStats), args.Error(1)
+}
+
+func (m *PRQueryRepositoryMock) GetLeaderboard(ctx context.Context, period domain.StatsPeriod, limit int) ([]domain.LeaderboardEntry, error) {
+	args := m.Called(ctx, period, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.LeaderboardEntry), args.Error(1)
+}
+
+func (m *PRQueryRepositoryMock) GetOpenPRAgeStats(ctx context.Context) ([]domain.OpenPRAgeStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.OpenPRAgeStats), args.Error(1)
+}
+
+func (m *UserRepositoryMock) SetUserRole(ctx context.Context, userID string, role domain.UserRole) error {
+	args := m.Called(ctx, userID, role)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) GetUserRole(ctx context.Context, userID string) (domain.UserRole, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(domain.UserRole), args.Error(1)
+}
+
+func (m *UserRepositoryMock) TransferUser(ctx context.Context, userID string, teamID int) (*api.User, int, error) {
+	args := m.Called(ctx, userID, teamID)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+
+	return args.Get(0).(*api.User), args.Int(1), args.Error(2)
+}
+
+func (m *UserRepositoryMock) DeleteUser(ctx context.Context, userID string, deletedAt time.Time) error {
+	args := m.Called(ctx, userID, deletedAt)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) RestoreUser(ctx context.Context, userID string) (*api.User, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.User), args.Error(1)
+}
+
+func (m *UserRepositoryMock) DeactivateUsersByTeamID(ctx context.Context, teamID int) ([]string, error) {
+	args := m.Called(ctx, teamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *UserRepositoryMock) ActivateUsers(ctx context.Context, teamID int, userIDs []string) ([]string, error) {
+	args := m.Called(ctx, teamID, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *UserPRRepositoryMock) GetLeastLoadedActiveReviewers(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error) {
+	args := m.Called(ctx, teamID, excludeUserIDs, count)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *UserPRRepositoryMock) GetLeastRecentlyAssignedActiveReviewers(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error) {
+	args := m.Called(ctx, teamID, excludeUserIDs, count)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 
 	return args.Get(0).([]string), args.Error(1)
 }
+
+func (m *UserPRRepositoryMock) GetReplacementAlternatives(ctx context.Context, teamID int, excludeUserIDs []string, limit int) ([]domain.ReplacementAlternative, error) {
+	args := m.Called(ctx, teamID, excludeUserIDs, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.ReplacementAlternative), args.Error(1)
+}
+
+type PolicyRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.PolicyRepository = (*PolicyRepositoryMock)(nil)
+
+func (m *PolicyRepositoryMock) GetTeamPolicy(ctx context.Context, teamID int) (*domain.TeamPolicy, error) {
+	args := m.Called(ctx, teamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.TeamPolicy), args.Error(1)
+}
+
+func (m *PolicyRepositoryMock) UpsertTeamPolicy(ctx context.Context, policy *domain.TeamPolicy) (*domain.TeamPolicy, error) {
+	args := m.Called(ctx, policy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.TeamPolicy), args.Error(1)
+}
+
+type CustomFieldRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.CustomFieldRepository = (*CustomFieldRepositoryMock)(nil)
+
+func (m *CustomFieldRepositoryMock) GetCustomFields(ctx context.Context, teamID int) ([]domain.CustomField, error) {
+	args := m.Called(ctx, teamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.CustomField), args.Error(1)
+}
+
+func (m *CustomFieldRepositoryMock) ReplaceCustomFields(ctx context.Context, teamID int, fields []domain.CustomField) ([]domain.CustomField, error) {
+	args := m.Called(ctx, teamID, fields)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.CustomField), args.Error(1)
+}
+
+type BorrowRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.BorrowRepository = (*BorrowRepositoryMock)(nil)
+
+func (m *BorrowRepositoryMock) CreateBorrow(ctx context.Context, borrow *domain.ReviewerBorrow) (*domain.ReviewerBorrow, error) {
+	args := m.Called(ctx, borrow)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.ReviewerBorrow), args.Error(1)
+}
+
+func (m *BorrowRepositoryMock) AcceptBorrow(ctx context.Context, borrowID int64, acceptedAt time.Time) (*domain.ReviewerBorrow, error) {
+	args := m.Called(ctx, borrowID, acceptedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.ReviewerBorrow), args.Error(1)
+}
+
+func (m *BorrowRepositoryMock) ListBorrows(ctx context.Context, teamID int) ([]domain.ReviewerBorrow, error) {
+	args := m.Called(ctx, teamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.ReviewerBorrow), args.Error(1)
+}
+
+type AssignmentHistoryRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.AssignmentHistoryRepository = (*AssignmentHistoryRepositoryMock)(nil)
+
+func (m *AssignmentHistoryRepositoryMock) RecordAssignments(ctx context.Context, records []domain.AssignmentRecord) error {
+	args := m.Called(ctx, records)
+	return args.Error(0)
+}
+
+func (m *AssignmentHistoryRepositoryMock) GetCurrentAssignments(ctx context.Context, prID string) ([]domain.AssignmentRecord, error) {
+	args := m.Called(ctx, prID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.AssignmentRecord), args.Error(1)
+}
+
+func (m *AssignmentHistoryRepositoryMock) ListAssignmentHistory(ctx context.Context, prID string) ([]domain.AssignmentRecord, error) {
+	args := m.Called(ctx, prID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.AssignmentRecord), args.Error(1)
+}
+
+func (m *AssignmentHistoryRepositoryMock) ListReplacementsOf(ctx context.Context, replacedUserIDs []string, cause domain.AssignmentCause, since time.Time) ([]domain.AssignmentRecord, error) {
+	args := m.Called(ctx, replacedUserIDs, cause, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.AssignmentRecord), args.Error(1)
+}
+
+type PendingAssignmentRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.PendingAssignmentRepository = (*PendingAssignmentRepositoryMock)(nil)
+
+func (m *PendingAssignmentRepositoryMock) EnqueuePending(ctx context.Context, prID string, teamID int, priority int) error {
+	args := m.Called(ctx, prID, teamID, priority)
+	return args.Error(0)
+}
+
+func (m *PendingAssignmentRepositoryMock) DequeuePending(ctx context.Context, prID string) error {
+	args := m.Called(ctx, prID)
+	return args.Error(0)
+}
+
+func (m *PendingAssignmentRepositoryMock) GetPendingWithLock(ctx context.Context, prID string) (*domain.PendingAssignment, error) {
+	args := m.Called(ctx, prID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.PendingAssignment), args.Error(1)
+}
+
+func (m *PendingAssignmentRepositoryMock) ListPending(ctx context.Context, teamName string, limit int) ([]domain.PendingAssignment, error) {
+	args := m.Called(ctx, teamName, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.PendingAssignment), args.Error(1)
+}
+
+func (m *PendingAssignmentRepositoryMock) ListDuePending(ctx context.Context, now time.Time, limit int) ([]domain.PendingAssignment, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.PendingAssignment), args.Error(1)
+}
+
+func (m *PendingAssignmentRepositoryMock) ListUnqueuedNeedingReviewers(ctx context.Context, afterID string, limit int) ([]string, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
+type CreatePRRequestRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.CreatePRRequestRepository = (*CreatePRRequestRepositoryMock)(nil)
+
+func (m *CreatePRRequestRepositoryMock) EnqueueCreatePR(ctx context.Context, req *domain.CreatePRRequest) (*domain.CreatePRRequest, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.CreatePRRequest), args.Error(1)
+}
+
+func (m *CreatePRRequestRepositoryMock) ClaimCreatePRRequests(ctx context.Context, limit int, claimedAt time.Time, staleBefore time.Time) ([]domain.CreatePRRequest, error) {
+	args := m.Called(ctx, limit, claimedAt, staleBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.CreatePRRequest), args.Error(1)
+}
+
+func (m *CreatePRRequestRepositoryMock) FinishCreatePRRequest(ctx context.Context, req *domain.CreatePRRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *CreatePRRequestRepositoryMock) GetCreatePRRequest(ctx context.Context, id int64) (*domain.CreatePRRequest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.CreatePRRequest), args.Error(1)
+}
+
+type DeactivationJobRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.DeactivationJobRepository = (*DeactivationJobRepositoryMock)(nil)
+
+func (m *DeactivationJobRepositoryMock) CreateDeactivationJob(ctx context.Context, job *domain.DeactivationJob, batches [][]string) (*domain.DeactivationJob, error) {
+	args := m.Called(ctx, job, batches)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.DeactivationJob), args.Error(1)
+}
+
+func (m *DeactivationJobRepositoryMock) ClaimDeactivationBatch(ctx context.Context) (*domain.DeactivationBatch, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.DeactivationBatch), args.Error(1)
+}
+
+func (m *DeactivationJobRepositoryMock) FinishDeactivationBatch(ctx context.Context, batch *domain.DeactivationBatch, reassigned int, warnings []string, finishedAt time.Time) error {
+	args := m.Called(ctx, batch, reassigned, warnings, finishedAt)
+	return args.Error(0)
+}
+
+func (m *DeactivationJobRepositoryMock) GetDeactivationJob(ctx context.Context, id int64) (*domain.DeactivationJob, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.DeactivationJob), args.Error(1)
+}
+
+type TeamDeactivationRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.TeamDeactivationRepository = (*TeamDeactivationRepositoryMock)(nil)
+
+func (m *TeamDeactivationRepositoryMock) RecordTeamDeactivation(ctx context.Context, deactivation *domain.TeamDeactivation) error {
+	args := m.Called(ctx, deactivation)
+	return args.Error(0)
+}
+
+func (m *TeamDeactivationRepositoryMock) GetLastTeamDeactivation(ctx context.Context, teamID int) (*domain.TeamDeactivation, error) {
+	args := m.Called(ctx, teamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.TeamDeactivation), args.Error(1)
+}
+
+func (m *TeamDeactivationRepositoryMock) MarkTeamReactivated(ctx context.Context, id int64, reactivatedAt time.Time) error {
+	args := m.Called(ctx, id, reactivatedAt)
+	return args.Error(0)
+}
+
+type JobRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.JobRepository = (*JobRepositoryMock)(nil)
+
+func (m *JobRepositoryMock) CreateJob(ctx context.Context, job *domain.Job) (*domain.Job, error) {
+	args := m.Called(ctx, job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *JobRepositoryMock) GetJob(ctx context.Context, id int64) (*domain.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *JobRepositoryMock) ClaimJob(ctx context.Context, owner string, claimedAt time.Time, leaseUntil time.Time) (*domain.Job, error) {
+	args := m.Called(ctx, owner, claimedAt, leaseUntil)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *JobRepositoryMock) RenewJobLease(ctx context.Context, id int64, owner string, done int, total int, leaseUntil time.Time) (bool, error) {
+	args := m.Called(ctx, id, owner, done, total, leaseUntil)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *JobRepositoryMock) FinishJob(ctx context.Context, job *domain.Job, owner string) error {
+	args := m.Called(ctx, job, owner)
+	return args.Error(0)
+}
+
+func (m *JobRepositoryMock) CancelJob(ctx context.Context, id int64, cancelledAt time.Time) (*domain.Job, error) {
+	args := m.Called(ctx, id, cancelledAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+type SubscriptionRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.SubscriptionRepository = (*SubscriptionRepositoryMock)(nil)
+
+func (m *SubscriptionRepositoryMock) Subscribe(ctx context.Context, prID string, userID string) (*domain.PRSubscription, bool, error) {
+	args := m.Called(ctx, prID, userID)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+
+	return args.Get(0).(*domain.PRSubscription), args.Bool(1), args.Error(2)
+}
+
+func (m *SubscriptionRepositoryMock) GetSubscriberIDs(ctx context.Context, prID string) ([]string, error) {
+	args := m.Called(ctx, prID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
+type NotifierMock struct {
+	mock.Mock
+}
+
+var _ Notifier = (*NotifierMock)(nil)
+
+func (m *NotifierMock) Notify(ctx context.Context, event domain.Event) {
+	m.Called(ctx, event)
+}
+
+// testNow is the time reported by fixedClock; it is deliberately not in UTC.
+var testNow = time.Date(2025, time.March, 14, 15, 9, 26, 0, time.FixedZone("UTC+3", 3*60*60))
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+// fixedIDs is an IDGenerator returning the same ID every time.
+type fixedIDs struct{ id string }
+
+func (g fixedIDs) NewID() (string, error) { return g.id, nil }
+
+type NotificationDeliveryRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.NotificationDeliveryRepository = (*NotificationDeliveryRepositoryMock)(nil)
+
+func (m *NotificationDeliveryRepositoryMock) CreateDelivery(ctx context.Context, d *domain.NotificationDelivery) (*domain.NotificationDelivery, error) {
+	args := m.Called(ctx, d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.NotificationDelivery), args.Error(1)
+}
+
+func (m *NotificationDeliveryRepositoryMock) GetDelivery(ctx context.Context, id int64) (*domain.NotificationDelivery, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.NotificationDelivery), args.Error(1)
+}
+
+func (m *NotificationDeliveryRepositoryMock) ListDeliveries(ctx context.Context, filter domain.DeliveryFilter) ([]domain.NotificationDelivery, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.NotificationDelivery), args.Error(1)
+}
+
+func (m *NotificationDeliveryRepositoryMock) RecordDeliveryAttempt(
+	ctx context.Context,
+	id int64,
+	status domain.DeliveryStatus,
+	deliveryErr *string,
+	attemptedAt time.Time,
+) (*domain.NotificationDelivery, error) {
+	args := m.Called(ctx, id, status, deliveryErr, attemptedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.NotificationDelivery), args.Error(1)
+}
+
+type FreezeWindowRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.FreezeWindowRepository = (*FreezeWindowRepositoryMock)(nil)
+
+func (m *FreezeWindowRepositoryMock) CreateFreezeWindow(ctx context.Context, window *domain.FreezeWindow) (*domain.FreezeWindow, error) {
+	args := m.Called(ctx, window)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.FreezeWindow), args.Error(1)
+}
+
+func (m *FreezeWindowRepositoryMock) ListFreezeWindows(ctx context.Context, filter domain.FreezeWindowFilter) ([]domain.FreezeWindow, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.FreezeWindow), args.Error(1)
+}
+
+func (m *FreezeWindowRepositoryMock) DeleteFreezeWindow(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type StatsViewRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.StatsViewRepository = (*StatsViewRepositoryMock)(nil)
+
+func (m *StatsViewRepositoryMock) RefreshStatsView(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *StatsViewRepositoryMock) GetStatsView(ctx context.Context) (*domain.StatsView, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.StatsView), args.Error(1)
+}
+
+type GitLabUserRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.GitLabUserRepository = (*GitLabUserRepositoryMock)(nil)
+
+func (m *GitLabUserRepositoryMock) SetGitLabUser(ctx context.Context, mapping *domain.GitLabUser) (*domain.GitLabUser, error) {
+	args := m.Called(ctx, mapping)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.GitLabUser), args.Error(1)
+}
+
+func (m *GitLabUserRepositoryMock) GetGitLabUser(ctx context.Context, gitLabUsername string) (*domain.GitLabUser, error) {
+	args := m.Called(ctx, gitLabUsername)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.GitLabUser), args.Error(1)
+}
+
+func (m *GitLabUserRepositoryMock) ListGitLabUsers(ctx context.Context) ([]domain.GitLabUser, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.GitLabUser), args.Error(1)
+}
+
+func (m *GitLabUserRepositoryMock) DeleteGitLabUser(ctx context.Context, gitLabUsername string) error {
+	args := m.Called(ctx, gitLabUsername)
+	return args.Error(0)
+}
+
+type OIDCSubjectRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.OIDCSubjectRepository = (*OIDCSubjectRepositoryMock)(nil)
+
+func (m *OIDCSubjectRepositoryMock) SetOIDCSubject(ctx context.Context, mapping *domain.OIDCSubject) (*domain.OIDCSubject, error) {
+	args := m.Called(ctx, mapping)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.OIDCSubject), args.Error(1)
+}
+
+func (m *OIDCSubjectRepositoryMock) GetOIDCSubject(ctx context.Context, subject string) (*domain.OIDCSubject, error) {
+	args := m.Called(ctx, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.OIDCSubject), args.Error(1)
+}
+
+func (m *OIDCSubjectRepositoryMock) ListOIDCSubjects(ctx context.Context) ([]domain.OIDCSubject, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.OIDCSubject), args.Error(1)
+}
+
+func (m *OIDCSubjectRepositoryMock) DeleteOIDCSubject(ctx context.Context, subject string) error {
+	args := m.Called(ctx, subject)
+	return args.Error(0)
+}
+
+type SlackUserRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.SlackUserRepository = (*SlackUserRepositoryMock)(nil)
+
+func (m *SlackUserRepositoryMock) SetSlackUser(ctx context.Context, mapping *domain.SlackUser) (*domain.SlackUser, error) {
+	args := m.Called(ctx, mapping)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.SlackUser), args.Error(1)
+}
+
+func (m *SlackUserRepositoryMock) GetSlackUser(ctx context.Context, userID string) (*domain.SlackUser, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.SlackUser), args.Error(1)
+}
+
+func (m *SlackUserRepositoryMock) ListSlackUsers(ctx context.Context) ([]domain.SlackUser, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.SlackUser), args.Error(1)
+}
+
+func (m *SlackUserRepositoryMock) DeleteSlackUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type WebhookRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.WebhookRepository = (*WebhookRepositoryMock)(nil)
+
+func (m *WebhookRepositoryMock) CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	args := m.Called(ctx, webhook)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.Webhook), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) GetWebhook(ctx context.Context, id int64) (*domain.Webhook, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.Webhook), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) ListWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.Webhook), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) UpdateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	args := m.Called(ctx, webhook)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.Webhook), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) DeleteWebhook(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) EnqueueWebhookDeliveries(ctx context.Context, event *domain.WebhookEvent) (int, error) {
+	args := m.Called(ctx, event)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) ClaimWebhookDeliveries(ctx context.Context, limit int, now, leaseUntil time.Time) ([]domain.WebhookDelivery, error) {
+	args := m.Called(ctx, limit, now, leaseUntil)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) FinishWebhookAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) GetWebhookDelivery(ctx context.Context, id int64) (*domain.WebhookDelivery, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) ListWebhookDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) RequeueWebhookDelivery(ctx context.Context, id int64, dueAt time.Time) (*domain.WebhookDelivery, error) {
+	args := m.Called(ctx, id, dueAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.WebhookDelivery), args.Error(1)
+}
+
+type OutboxRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.OutboxRepository = (*OutboxRepositoryMock)(nil)
+
+func (m *OutboxRepositoryMock) AddOutboxEvent(ctx context.Context, event *domain.OutboxEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *OutboxRepositoryMock) ClaimOutboxEvents(ctx context.Context, limit int, now, leaseUntil time.Time) ([]domain.OutboxEvent, error) {
+	args := m.Called(ctx, limit, now, leaseUntil)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.OutboxEvent), args.Error(1)
+}
+
+func (m *OutboxRepositoryMock) FinishOutboxAttempt(ctx context.Context, event *domain.OutboxEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+type EventPublisherMock struct {
+	mock.Mock
+}
+
+var _ EventPublisher = (*EventPublisherMock)(nil)
+
+func (m *EventPublisherMock) Name() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *EventPublisherMock) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+type ReadTokenRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.ReadTokenRepository = (*ReadTokenRepositoryMock)(nil)
+
+func (m *ReadTokenRepositoryMock) CreateReadToken(ctx context.Context, token *domain.ReadToken) (*domain.ReadToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.ReadToken), args.Error(1)
+}
+
+func (m *ReadTokenRepositoryMock) GetReadTokenByHash(ctx context.Context, tokenHash string) (*domain.ReadToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.ReadToken), args.Error(1)
+}
+
+func (m *ReadTokenRepositoryMock) ListReadTokens(ctx context.Context) ([]domain.ReadToken, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.ReadToken), args.Error(1)
+}
+
+func (m *ReadTokenRepositoryMock) TouchReadToken(ctx context.Context, id int64, usedAt time.Time) error {
+	args := m.Called(ctx, id, usedAt)
+	return args.Error(0)
+}
+
+func (m *ReadTokenRepositoryMock) DeleteReadToken(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type APIKeyRepositoryMock struct {
+	mock.Mock
+}
+
+var _ repository.APIKeyRepository = (*APIKeyRepositoryMock)(nil)
+
+func (m *APIKeyRepositoryMock) CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *APIKeyRepositoryMock) GetAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *APIKeyRepositoryMock) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.APIKey), args.Error(1)
+}
+
+func (m *APIKeyRepositoryMock) TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error {
+	args := m.Called(ctx, id, usedAt)
+	return args.Error(0)
+}
+
+func (m *APIKeyRepositoryMock) DeleteAPIKey(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
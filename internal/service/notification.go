@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/cursor"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// NotificationChannel delivers events to users through one medium, e.g. a chat or e-mail.
+type NotificationChannel interface {
+	// Name identifies the channel in the delivery log; it must not change between releases,
+	// since failed deliveries are retried through the channel of the same name.
+	Name() string
+	// Deliver sends event to a single recipient.
+	Deliver(ctx context.Context, recipientID string, event domain.Event) error
+}
+
+// EventFilter is implemented by a NotificationChannel that delivers only some of the events, e.g. a chat
+// messaging just the reviewers asked to act. The deliveries it rejects are neither made nor recorded.
+type EventFilter interface {
+	// Accepts reports whether the channel delivers event to the recipient.
+	Accepts(recipientID string, event domain.Event) bool
+}
+
+// NotificationService is a Notifier that records the outcome of every delivery,
+// so that support can find out why a user was not notified and retry the failed deliveries.
+type NotificationService interface {
+	Notifier
+	// ListDeliveries returns the recorded deliveries matching query, newest first.
+	ListDeliveries(ctx context.Context, query DeliveryQuery) (*api.ListNotificationDeliveriesResponse, error)
+	// RetryDelivery delivers the event of a failed delivery again and records the outcome.
+	// Returns apperrors.ErrDeliveryNotFailed if the delivery has succeeded.
+	RetryDelivery(ctx context.Context, id int64) (*api.NotificationDelivery, error)
+}
+
+// DeliveryQuery selects the deliveries returned by ListDeliveries. Zero values do not filter.
+type DeliveryQuery struct {
+	Channel       string
+	RecipientID   string
+	PullRequestID string
+	Event         string
+	Status        string
+	// Cursor is the next_cursor of the previous page. It cannot be combined with a non-zero Offset.
+	Cursor string
+	Limit  int
+	Offset int
+}
+
+type NotificationServiceImpl struct {
+	BaseService
+	repo repository.NotificationDeliveryRepository
+	// channels lists the channels in the order every event is delivered through them.
+	channels []NotificationChannel
+}
+
+// NotificationServiceOption configures optional behaviour of NotificationServiceImpl.
+type NotificationServiceOption func(*NotificationServiceImpl)
+
+// WithNotificationClock makes the service take the current time from c instead of the system clock.
+func WithNotificationClock(c Clock) NotificationServiceOption {
+	return func(s *NotificationServiceImpl) {
+		s.clock = c
+	}
+}
+
+// WithNotificationChannel makes the service deliver every event through c as well.
+func WithNotificationChannel(c NotificationChannel) NotificationServiceOption {
+	return func(s *NotificationServiceImpl) {
+		s.channels = append(s.channels, c)
+	}
+}
+
+// NewNotificationService creates a new instance of NotificationServiceImpl.
+// Without channels it delivers nothing and records nothing.
+func NewNotificationService(repo repository.NotificationDeliveryRepository, log *slog.Logger, opts ...NotificationServiceOption) *NotificationServiceImpl {
+	s := &NotificationServiceImpl{
+		BaseService: NewBaseService(nil, log),
+		repo:        repo,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Notify delivers event to its recipients and subscribers through every channel. A failed delivery is recorded
+// and logged, and does not stop the others; as for any Notifier, the operation that caused the event is not failed.
+func (s *NotificationServiceImpl) Notify(ctx context.Context, event domain.Event) {
+	const op = "internal.service.notification.Notify"
+
+	if len(s.channels) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		s.log.Error("failed to encode notification", slog.String("op", op), slog.String("pr_id", event.PullRequestID), sl.Err(err))
+		return
+	}
+
+	recipients := append(append([]string(nil), event.UserIDs...), event.SubscriberIDs...)
+
+	for _, recipientID := range recipients {
+		for _, channel := range s.channels {
+			if filter, ok := channel.(EventFilter); ok && !filter.Accepts(recipientID, event) {
+				continue
+			}
+
+			status, deliveryErr := s.deliver(ctx, channel, recipientID, event)
+
+			_, err := s.repo.CreateDelivery(ctx, &domain.NotificationDelivery{
+				Channel:       channel.Name(),
+				RecipientID:   recipientID,
+				Event:         event.Type,
+				PullRequestID: event.PullRequestID,
+				Status:        status,
+				Error:         deliveryErr,
+				Payload:       payload,
+				CreatedAt:     s.now(),
+			})
+			if err != nil {
+				s.log.Error("failed to record notification delivery", slog.String("op", op),
+					slog.String("channel", channel.Name()), slog.String("recipient_id", recipientID), sl.Err(err))
+			}
+		}
+	}
+}
+
+// deliver sends event through channel and returns the outcome to record.
+func (s *NotificationServiceImpl) deliver(ctx context.Context, channel NotificationChannel, recipientID string, event domain.Event) (domain.DeliveryStatus, *string) {
+	if err := channel.Deliver(ctx, recipientID, event); err != nil {
+		s.log.Warn("notification delivery failed", slog.String("channel", channel.Name()), slog.String("recipient_id", recipientID),
+			slog.String("event", string(event.Type)), slog.String("pr_id", event.PullRequestID), sl.Err(err))
+
+		message := err.Error()
+
+		return domain.DeliveryFailed, &message
+	}
+
+	return domain.DeliveryDelivered, nil
+}
+
+func (s *NotificationServiceImpl) ListDeliveries(ctx context.Context, query DeliveryQuery) (*api.ListNotificationDeliveriesResponse, error) {
+	const op = "internal.service.notification.ListDeliveries"
+
+	switch api.NotificationEvent(query.Event) {
+	case "", api.NotificationReviewersAssigned, api.NotificationReviewerReassigned, api.NotificationPRMerged,
+		api.NotificationPRClosed, api.NotificationReviewApproved, api.NotificationChangesRequested:
+	default:
+		return nil, fmt.Errorf("%w: unknown event '%s'", apperrors.ErrValidation, query.Event)
+	}
+
+	switch domain.DeliveryStatus(query.Status) {
+	case "", domain.DeliveryDelivered, domain.DeliveryFailed:
+	default:
+		return nil, fmt.Errorf("%w: unknown status '%s'", apperrors.ErrValidation, query.Status)
+	}
+
+	if query.Limit < 1 || query.Limit > maxListLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", apperrors.ErrValidation, maxListLimit)
+	}
+
+	if query.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", apperrors.ErrValidation)
+	}
+
+	filter := domain.DeliveryFilter{
+		Channel:       query.Channel,
+		RecipientID:   query.RecipientID,
+		PullRequestID: query.PullRequestID,
+		Event:         domain.EventType(query.Event),
+		Status:        domain.DeliveryStatus(query.Status),
+		// One more delivery than requested tells whether there is a next page.
+		Limit:  query.Limit + 1,
+		Offset: query.Offset,
+	}
+
+	if query.Cursor != "" {
+		if query.Offset != 0 {
+			return nil, fmt.Errorf("%w: cursor and offset are mutually exclusive", apperrors.ErrValidation)
+		}
+
+		afterID, err := cursor.DecodeInt(query.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+		}
+
+		filter.AfterID = afterID
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list notification deliveries: %w", op, err)
+	}
+
+	resp := &api.ListNotificationDeliveriesResponse{}
+
+	if len(deliveries) > query.Limit {
+		deliveries = deliveries[:query.Limit]
+		next := cursor.EncodeInt(deliveries[len(deliveries)-1].ID)
+		resp.NextCursor = &next
+	}
+
+	resp.Items = make([]api.NotificationDelivery, len(deliveries))
+	for i := range deliveries {
+		resp.Items[i] = *toAPINotificationDelivery(&deliveries[i])
+	}
+
+	resp.Deliveries = resp.Items
+
+	return resp, nil
+}
+
+func (s *NotificationServiceImpl) RetryDelivery(ctx context.Context, id int64) (*api.NotificationDelivery, error) {
+	const op = "internal.service.notification.RetryDelivery"
+
+	delivery, err := s.repo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get notification delivery: %w", op, err)
+	}
+
+	if delivery.Status != domain.DeliveryFailed {
+		return nil, fmt.Errorf("%s: %w: delivery %d", op, apperrors.ErrDeliveryNotFailed, id)
+	}
+
+	channel := s.channel(delivery.Channel)
+	if channel == nil {
+		return nil, fmt.Errorf("%w: notification channel '%s' is not configured", apperrors.ErrValidation, delivery.Channel)
+	}
+
+	var event domain.Event
+	if err := json.Unmarshal(delivery.Payload, &event); err != nil {
+		return nil, fmt.Errorf("%s: failed to decode event of delivery %d: %w", op, id, err)
+	}
+
+	status, deliveryErr := s.deliver(ctx, channel, delivery.RecipientID, event)
+
+	delivery, err = s.repo.RecordDeliveryAttempt(ctx, id, status, deliveryErr, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to record notification delivery: %w", op, err)
+	}
+
+	s.log.Info("notification delivery retried", slog.String("op", op), slog.Int64("delivery_id", id),
+		slog.String("status", string(delivery.Status)), slog.Int("attempts", delivery.Attempts))
+
+	return toAPINotificationDelivery(delivery), nil
+}
+
+func (s *NotificationServiceImpl) channel(name string) NotificationChannel {
+	for _, c := range s.channels {
+		if c.Name() == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func toAPINotificationDelivery(d *domain.NotificationDelivery) *api.NotificationDelivery {
+	return &api.NotificationDelivery{
+		DeliveryId:    d.ID,
+		Channel:       d.Channel,
+		RecipientId:   d.RecipientID,
+		Event:         api.NotificationEvent(d.Event),
+		PullRequestId: d.PullRequestID,
+		Status:        api.NotificationDeliveryStatus(d.Status),
+		Error:         d.Error,
+		Attempts:      d.Attempts,
+		CreatedAt:     d.CreatedAt,
+		UpdatedAt:     d.UpdatedAt,
+	}
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testChannel is a NotificationChannel failing the deliveries to the recipients in failFor.
+type testChannel struct {
+	name      string
+	failFor   map[string]bool
+	delivered []string
+}
+
+func (c *testChannel) Name() string { return c.name }
+
+func (c *testChannel) Deliver(_ context.Context, recipientID string, _ domain.Event) error {
+	if c.failFor[recipientID] {
+		return errors.New("connection refused")
+	}
+
+	c.delivered = append(c.delivered, recipientID)
+
+	return nil
+}
+
+func TestNotificationServiceImpl_Notify(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	event := domain.Event{
+		Type:          domain.EventReviewersAssigned,
+		PullRequestID: "pr-1",
+		UserIDs:       []string{"u2", "u3"},
+		SubscriberIDs: []string{"u4"},
+	}
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	channel := &testChannel{name: "chat", failFor: map[string]bool{"u3": true}}
+	failure := "connection refused"
+
+	repo := new(NotificationDeliveryRepositoryMock)
+	for _, recipientID := range []string{"u2", "u4"} {
+		repo.On("CreateDelivery", ctx, &domain.NotificationDelivery{
+			Channel: "chat", RecipientID: recipientID, Event: domain.EventReviewersAssigned, PullRequestID: "pr-1",
+			Status: domain.DeliveryDelivered, Payload: payload, CreatedAt: testNow.UTC(),
+		}).Return(&domain.NotificationDelivery{}, nil).Once()
+	}
+
+	repo.On("CreateDelivery", ctx, &domain.NotificationDelivery{
+		Channel: "chat", RecipientID: "u3", Event: domain.EventReviewersAssigned, PullRequestID: "pr-1",
+		Status: domain.DeliveryFailed, Error: &failure, Payload: payload, CreatedAt: testNow.UTC(),
+	}).Return(nil, errors.New("db is down")).Once()
+
+	service := NewNotificationService(repo, logger, WithNotificationClock(fixedClock(testNow)), WithNotificationChannel(channel))
+	service.Notify(ctx, event)
+
+	assert.Equal(t, []string{"u2", "u4"}, channel.delivered, "a failed delivery does not stop the others")
+	repo.AssertExpectations(t)
+
+	t.Run("No channels", func(t *testing.T) {
+		repo := new(NotificationDeliveryRepositoryMock)
+		NewNotificationService(repo, logger).Notify(ctx, event)
+		repo.AssertNotCalled(t, "CreateDelivery", mock.Anything, mock.Anything)
+	})
+}
+
+// filteringChannel is a testChannel delivering only to the recipients the event is addressed to.
+type filteringChannel struct {
+	testChannel
+}
+
+func (c *filteringChannel) Accepts(recipientID string, event domain.Event) bool {
+	return slices.Contains(event.UserIDs, recipientID)
+}
+
+func TestNotificationServiceImpl_NotifyFilteredChannel(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	event := domain.Event{
+		Type:          domain.EventReviewersAssigned,
+		PullRequestID: "pr-1",
+		UserIDs:       []string{"u2"},
+		SubscriberIDs: []string{"u4"},
+	}
+
+	channel := &filteringChannel{testChannel{name: "slack"}}
+
+	repo := new(NotificationDeliveryRepositoryMock)
+	repo.On("CreateDelivery", ctx, mock.MatchedBy(func(d *domain.NotificationDelivery) bool {
+		return d.Channel == "slack" && d.RecipientID == "u2"
+	})).Return(&domain.NotificationDelivery{}, nil).Once()
+
+	NewNotificationService(repo, logger, WithNotificationChannel(channel)).Notify(ctx, event)
+
+	assert.Equal(t, []string{"u2"}, channel.delivered, "the rejected deliveries are neither made nor recorded")
+	repo.AssertExpectations(t)
+}
+
+func TestNotificationServiceImpl_ListDeliveries(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	t.Run("Filters are passed to the repository", func(t *testing.T) {
+		failure := "connection refused"
+
+		repo := new(NotificationDeliveryRepositoryMock)
+		repo.On("ListDeliveries", ctx, domain.DeliveryFilter{
+			RecipientID: "u2", Event: domain.EventPRMerged, Status: domain.DeliveryFailed, Limit: 11, Offset: 20,
+		}).Return([]domain.NotificationDelivery{{
+			ID: 7, Channel: "chat", RecipientID: "u2", Event: domain.EventPRMerged, PullRequestID: "pr-1",
+			Status: domain.DeliveryFailed, Error: &failure, Attempts: 2, CreatedAt: testNow, UpdatedAt: testNow,
+		}}, nil).Once()
+
+		resp, err := NewNotificationService(repo, logger).ListDeliveries(ctx, DeliveryQuery{
+			RecipientID: "u2", Event: "pr_merged", Status: "failed", Limit: 10, Offset: 20,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []api.NotificationDelivery{{
+			DeliveryId: 7, Channel: "chat", RecipientId: "u2", Event: api.NotificationPRMerged, PullRequestId: "pr-1",
+			Status: api.DeliveryFailed, Error: &failure, Attempts: 2, CreatedAt: testNow, UpdatedAt: testNow,
+		}}, resp.Items)
+		assert.Equal(t, resp.Items, resp.Deliveries)
+		assert.Nil(t, resp.NextCursor)
+	})
+
+	t.Run("Pages follow each other through the cursor", func(t *testing.T) {
+		repo := new(NotificationDeliveryRepositoryMock)
+		repo.On("ListDeliveries", ctx, domain.DeliveryFilter{Limit: 3}).
+			Return([]domain.NotificationDelivery{{ID: 9}, {ID: 8}, {ID: 5}}, nil).Once()
+		repo.On("ListDeliveries", ctx, domain.DeliveryFilter{AfterID: 8, Limit: 3}).
+			Return([]domain.NotificationDelivery{{ID: 5}}, nil).Once()
+
+		service := NewNotificationService(repo, logger)
+
+		first, err := service.ListDeliveries(ctx, DeliveryQuery{Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, first.Items, 2)
+		require.NotNil(t, first.NextCursor)
+
+		second, err := service.ListDeliveries(ctx, DeliveryQuery{Limit: 2, Cursor: *first.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, second.Items, 1)
+		assert.Equal(t, int64(5), second.Items[0].DeliveryId)
+		assert.Nil(t, second.NextCursor)
+
+		repo.AssertExpectations(t)
+	})
+
+	testCases := []struct {
+		name  string
+		query DeliveryQuery
+	}{
+		{name: "Unknown event", query: DeliveryQuery{Event: "pr_opened", Limit: 10}},
+		{name: "Unknown status", query: DeliveryQuery{Status: "pending", Limit: 10}},
+		{name: "Limit out of range", query: DeliveryQuery{Limit: maxListLimit + 1}},
+		{name: "Negative offset", query: DeliveryQuery{Limit: 10, Offset: -1}},
+		{name: "Cursor with offset", query: DeliveryQuery{Limit: 10, Offset: 10, Cursor: "OA"}},
+		{name: "Malformed cursor", query: DeliveryQuery{Limit: 10, Cursor: "not a cursor"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewNotificationService(new(NotificationDeliveryRepositoryMock), logger).ListDeliveries(ctx, tc.query)
+			assert.ErrorIs(t, err, apperrors.ErrValidation)
+		})
+	}
+}
+
+func TestNotificationServiceImpl_RetryDelivery(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	payload, err := json.Marshal(domain.Event{Type: domain.EventPRClosed, PullRequestID: "pr-1", UserIDs: []string{"u2"}})
+	require.NoError(t, err)
+
+	failure := "connection refused"
+	failed := &domain.NotificationDelivery{
+		ID: 7, Channel: "chat", RecipientID: "u2", Event: domain.EventPRClosed, PullRequestID: "pr-1",
+		Status: domain.DeliveryFailed, Error: &failure, Attempts: 1, Payload: payload,
+	}
+
+	t.Run("Delivered on retry", func(t *testing.T) {
+		channel := &testChannel{name: "chat"}
+
+		repo := new(NotificationDeliveryRepositoryMock)
+		repo.On("GetDelivery", ctx, int64(7)).Return(failed, nil).Once()
+		repo.On("RecordDeliveryAttempt", ctx, int64(7), domain.DeliveryDelivered, (*string)(nil), testNow.UTC()).
+			Return(&domain.NotificationDelivery{ID: 7, Channel: "chat", Status: domain.DeliveryDelivered, Attempts: 2}, nil).Once()
+
+		service := NewNotificationService(repo, logger, WithNotificationClock(fixedClock(testNow)), WithNotificationChannel(channel))
+
+		delivery, err := service.RetryDelivery(ctx, 7)
+		require.NoError(t, err)
+		assert.Equal(t, api.DeliveryDelivered, delivery.Status)
+		assert.Equal(t, 2, delivery.Attempts)
+		assert.Equal(t, []string{"u2"}, channel.delivered)
+	})
+
+	t.Run("Failed again", func(t *testing.T) {
+		channel := &testChannel{name: "chat", failFor: map[string]bool{"u2": true}}
+
+		repo := new(NotificationDeliveryRepositoryMock)
+		repo.On("GetDelivery", ctx, int64(7)).Return(failed, nil).Once()
+		repo.On("RecordDeliveryAttempt", ctx, int64(7), domain.DeliveryFailed, &failure, testNow.UTC()).
+			Return(&domain.NotificationDelivery{ID: 7, Channel: "chat", Status: domain.DeliveryFailed, Error: &failure, Attempts: 2}, nil).Once()
+
+		service := NewNotificationService(repo, logger, WithNotificationClock(fixedClock(testNow)), WithNotificationChannel(channel))
+
+		delivery, err := service.RetryDelivery(ctx, 7)
+		require.NoError(t, err)
+		assert.Equal(t, api.DeliveryFailed, delivery.Status)
+	})
+
+	t.Run("Already delivered", func(t *testing.T) {
+		repo := new(NotificationDeliveryRepositoryMock)
+		repo.On("GetDelivery", ctx, int64(8)).Return(&domain.NotificationDelivery{ID: 8, Channel: "chat", Status: domain.DeliveryDelivered}, nil).Once()
+
+		_, err := NewNotificationService(repo, logger, WithNotificationChannel(&testChannel{name: "chat"})).RetryDelivery(ctx, 8)
+		assert.ErrorIs(t, err, apperrors.ErrDeliveryNotFailed)
+	})
+
+	t.Run("Channel no longer configured", func(t *testing.T) {
+		repo := new(NotificationDeliveryRepositoryMock)
+		repo.On("GetDelivery", ctx, int64(7)).Return(failed, nil).Once()
+
+		_, err := NewNotificationService(repo, logger, WithNotificationChannel(&testChannel{name: "email"})).RetryDelivery(ctx, 7)
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		repo := new(NotificationDeliveryRepositoryMock)
+		repo.On("GetDelivery", ctx, int64(9)).Return(nil, apperrors.ErrNotFound).Once()
+
+		_, err := NewNotificationService(repo, logger).RetryDelivery(ctx, 9)
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+)
+
+// Notifier delivers pull request events to the users they concern.
+// Delivery is best effort: a failed notification must not fail the operation that caused it,
+// so implementations handle their own errors.
+type Notifier interface {
+	Notify(ctx context.Context, event domain.Event)
+}
+
+type noopNotifier struct{}
+
+func (noopNotifier) Notify(context.Context, domain.Event) {}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// OIDCSubjectService manages the mapping of the subjects of the identity provider to user IDs, through which
+// an ID token of the provider acts for a user. Subjects are compared exactly, as the provider issues them.
+type OIDCSubjectService interface {
+	// SetOIDCSubject maps a subject to a user, replacing its previous mapping.
+	// Returns apperrors.ErrNotFound if the user does not exist.
+	SetOIDCSubject(ctx context.Context, subject, userID string) (*api.OidcSubject, error)
+	// ResolveOIDCSubject returns the ID of the user a subject is mapped to.
+	// Returns apperrors.ErrNotFound if the subject is not mapped.
+	ResolveOIDCSubject(ctx context.Context, subject string) (string, error)
+	// ListOIDCSubjects returns every mapping ordered by subject.
+	ListOIDCSubjects(ctx context.Context) (*api.ListOidcSubjectsResponse, error)
+	// DeleteOIDCSubject removes the mapping of a subject.
+	DeleteOIDCSubject(ctx context.Context, subject string) error
+}
+
+type OIDCSubjectServiceImpl struct {
+	BaseService
+	repo repository.OIDCSubjectRepository
+}
+
+// NewOIDCSubjectService creates a new instance of OIDCSubjectServiceImpl.
+func NewOIDCSubjectService(repo repository.OIDCSubjectRepository, log *slog.Logger) *OIDCSubjectServiceImpl {
+	return &OIDCSubjectServiceImpl{
+		BaseService: NewBaseService(nil, log),
+		repo:        repo,
+	}
+}
+
+func (s *OIDCSubjectServiceImpl) SetOIDCSubject(ctx context.Context, subject, userID string) (*api.OidcSubject, error) {
+	const op = "internal.service.oidc_subject.SetOIDCSubject"
+
+	if strings.TrimSpace(subject) == "" {
+		return nil, fmt.Errorf("%w: subject must not be blank", apperrors.ErrValidation)
+	}
+
+	stored, err := s.repo.SetOIDCSubject(ctx, &domain.OIDCSubject{Subject: subject, UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to set oidc subject: %w", op, err)
+	}
+
+	s.log.Info("oidc subject mapped", slog.String("op", op), slog.String("subject", subject), slog.String("user_id", userID))
+
+	return toAPIOIDCSubject(stored), nil
+}
+
+func (s *OIDCSubjectServiceImpl) ResolveOIDCSubject(ctx context.Context, subject string) (string, error) {
+	const op = "internal.service.oidc_subject.ResolveOIDCSubject"
+
+	mapping, err := s.repo.GetOIDCSubject(ctx, subject)
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to get oidc subject: %w", op, err)
+	}
+
+	return mapping.UserID, nil
+}
+
+func (s *OIDCSubjectServiceImpl) ListOIDCSubjects(ctx context.Context) (*api.ListOidcSubjectsResponse, error) {
+	const op = "internal.service.oidc_subject.ListOIDCSubjects"
+
+	mappings, err := s.repo.ListOIDCSubjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list oidc subjects: %w", op, err)
+	}
+
+	items := make([]api.OidcSubject, len(mappings))
+	for i := range mappings {
+		items[i] = *toAPIOIDCSubject(&mappings[i])
+	}
+
+	return &api.ListOidcSubjectsResponse{Items: items, TotalEstimate: totalOf(items)}, nil
+}
+
+func (s *OIDCSubjectServiceImpl) DeleteOIDCSubject(ctx context.Context, subject string) error {
+	const op = "internal.service.oidc_subject.DeleteOIDCSubject"
+
+	if err := s.repo.DeleteOIDCSubject(ctx, subject); err != nil {
+		return fmt.Errorf("%s: failed to delete oidc subject: %w", op, err)
+	}
+
+	s.log.Info("oidc subject unmapped", slog.String("op", op), slog.String("subject", subject))
+
+	return nil
+}
+
+func toAPIOIDCSubject(m *domain.OIDCSubject) *api.OidcSubject {
+	return &api.OidcSubject{Subject: m.Subject, UserId: m.UserID, CreatedAt: m.CreatedAt}
+}
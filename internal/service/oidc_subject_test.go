@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOIDCSubjectServiceImpl_SetOIDCSubject(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	now := testNow.UTC()
+
+	t.Run("Subject is stored as issued", func(t *testing.T) {
+		repo := new(OIDCSubjectRepositoryMock)
+		repo.On("SetOIDCSubject", ctx, &domain.OIDCSubject{Subject: "00uAbC", UserID: "u1"}).
+			Return(&domain.OIDCSubject{Subject: "00uAbC", UserID: "u1", CreatedAt: now}, nil).Once()
+
+		mapping, err := NewOIDCSubjectService(repo, logger).SetOIDCSubject(ctx, "00uAbC", "u1")
+
+		require.NoError(t, err)
+		assert.Equal(t, &api.OidcSubject{Subject: "00uAbC", UserId: "u1", CreatedAt: now}, mapping)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		repo := new(OIDCSubjectRepositoryMock)
+		repo.On("SetOIDCSubject", ctx, &domain.OIDCSubject{Subject: "00uAbC", UserID: "ghost"}).Return(nil, apperrors.ErrNotFound).Once()
+
+		_, err := NewOIDCSubjectService(repo, logger).SetOIDCSubject(ctx, "00uAbC", "ghost")
+
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("Blank subject", func(t *testing.T) {
+		_, err := NewOIDCSubjectService(new(OIDCSubjectRepositoryMock), logger).SetOIDCSubject(ctx, "  ", "u1")
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+	})
+}
+
+func TestOIDCSubjectServiceImpl_ResolveOIDCSubject(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	repo := new(OIDCSubjectRepositoryMock)
+	repo.On("GetOIDCSubject", ctx, "00uAbC").Return(&domain.OIDCSubject{Subject: "00uAbC", UserID: "u1"}, nil).Once()
+	repo.On("GetOIDCSubject", ctx, "00uabc").Return(nil, apperrors.ErrNotFound).Once()
+
+	s := NewOIDCSubjectService(repo, logger)
+
+	userID, err := s.ResolveOIDCSubject(ctx, "00uAbC")
+	require.NoError(t, err)
+	assert.Equal(t, "u1", userID)
+
+	_, err = s.ResolveOIDCSubject(ctx, "00uabc")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound, "subjects are compared exactly")
+	repo.AssertExpectations(t)
+}
+
+func TestOIDCSubjectServiceImpl_ListOIDCSubjects(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	repo := new(OIDCSubjectRepositoryMock)
+	repo.On("ListOIDCSubjects", ctx).Return([]domain.OIDCSubject{{Subject: "00uAbC", UserID: "u1"}}, nil).Once()
+
+	resp, err := NewOIDCSubjectService(repo, logger).ListOIDCSubjects(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, []api.OidcSubject{{Subject: "00uAbC", UserId: "u1"}}, resp.Items)
+	assert.Nil(t, resp.NextCursor)
+	require.NotNil(t, resp.TotalEstimate)
+	assert.Equal(t, 1, *resp.TotalEstimate)
+}
+
+func TestOIDCSubjectServiceImpl_DeleteOIDCSubject(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	repo := new(OIDCSubjectRepositoryMock)
+	repo.On("DeleteOIDCSubject", ctx, "00uAbC").Return(nil).Once()
+	repo.On("DeleteOIDCSubject", ctx, "ghost").Return(apperrors.ErrNotFound).Once()
+
+	s := NewOIDCSubjectService(repo, logger)
+
+	require.NoError(t, s.DeleteOIDCSubject(ctx, "00uAbC"))
+	assert.ErrorIs(t, s.DeleteOIDCSubject(ctx, "ghost"), apperrors.ErrNotFound)
+	repo.AssertExpectations(t)
+}
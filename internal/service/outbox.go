@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// Outcomes of the publication attempts, counted by the outbox_publish_attempts_total metric.
+const (
+	outboxAttemptPublished = "published"
+	outboxAttemptFailed    = "failed"
+)
+
+// Defaults of the publication policy, see WithOutboxPublishPolicy.
+const (
+	defaultOutboxLease          = time.Minute
+	defaultOutboxRetryBaseDelay = time.Second
+	defaultOutboxRetryMaxDelay  = 5 * time.Minute
+)
+
+// EventPublisher publishes the PR events written to the outbox to one sink, e.g. a message broker.
+type EventPublisher interface {
+	// Name identifies the sink in the logs and metrics.
+	Name() string
+	// Publish hands event over to the sink; it returns once the sink has accepted it.
+	// An event may be published more than once, so the consumers deduplicate the events by their ID.
+	Publish(ctx context.Context, event domain.OutboxEvent) error
+}
+
+// OutboxService publishes the PR events written to the outbox to the event sinks. The events are written
+// by PullRequestServiceImpl in the transactions of the changes they report, see WithOutbox.
+type OutboxService interface {
+	// ClaimOutboxEvents claims at most limit due events for publication. A claimed event that is not published
+	// within the lease is claimed again, so a crashed worker delays it only.
+	ClaimOutboxEvents(ctx context.Context, limit int) ([]domain.OutboxEvent, error)
+	// PublishOutboxEvent publishes a claimed event to every sink and records the outcome. An event that any sink
+	// fails to accept is published to every sink again after an exponential backoff; it is never given up.
+	PublishOutboxEvent(ctx context.Context, event domain.OutboxEvent) error
+}
+
+type OutboxServiceImpl struct {
+	BaseService
+	repo           repository.OutboxRepository
+	publishers     []EventPublisher
+	lease          time.Duration
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+}
+
+// OutboxServiceOption configures optional behaviour of OutboxServiceImpl.
+type OutboxServiceOption func(*OutboxServiceImpl)
+
+// WithOutboxClock makes the service take the current time from c instead of the system clock.
+func WithOutboxClock(c Clock) OutboxServiceOption {
+	return func(s *OutboxServiceImpl) {
+		s.clock = c
+	}
+}
+
+// WithOutboxPublishPolicy sets how long a claimed event stays with its worker and the bounds
+// of the exponential backoff between the attempts to publish an event.
+func WithOutboxPublishPolicy(lease, retryBaseDelay, retryMaxDelay time.Duration) OutboxServiceOption {
+	return func(s *OutboxServiceImpl) {
+		s.lease = lease
+		s.retryBaseDelay = retryBaseDelay
+		s.retryMaxDelay = retryMaxDelay
+	}
+}
+
+// NewOutboxService creates a new instance of OutboxServiceImpl that publishes the events to publishers.
+func NewOutboxService(repo repository.OutboxRepository, publishers []EventPublisher, log *slog.Logger, opts ...OutboxServiceOption) *OutboxServiceImpl {
+	s := &OutboxServiceImpl{
+		BaseService:    NewBaseService(nil, log),
+		repo:           repo,
+		publishers:     publishers,
+		lease:          defaultOutboxLease,
+		retryBaseDelay: defaultOutboxRetryBaseDelay,
+		retryMaxDelay:  defaultOutboxRetryMaxDelay,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *OutboxServiceImpl) ClaimOutboxEvents(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
+	const op = "internal.service.outbox.ClaimOutboxEvents"
+
+	now := s.now()
+
+	events, err := s.repo.ClaimOutboxEvents(ctx, limit, now, now.Add(s.lease))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to claim outbox events: %w", op, err)
+	}
+
+	return events, nil
+}
+
+func (s *OutboxServiceImpl) PublishOutboxEvent(ctx context.Context, event domain.OutboxEvent) error {
+	const op = "internal.service.outbox.PublishOutboxEvent"
+	log := s.log.With(slog.String("op", op), slog.Int64("event_id", event.ID), slog.String("event", string(event.Type)),
+		slog.Int("attempt", event.Attempts))
+
+	var errs []error
+
+	for _, publisher := range s.publishers {
+		err := publisher.Publish(ctx, event)
+		if err != nil && ctx.Err() != nil {
+			// A publication cut short by a shutdown is made again after the lease.
+			return fmt.Errorf("%s: publication interrupted: %w", op, err)
+		}
+
+		outcome := outboxAttemptPublished
+		if err != nil {
+			outcome = outboxAttemptFailed
+			errs = append(errs, fmt.Errorf("%s: %w", publisher.Name(), err))
+		}
+
+		outboxPublishAttemptsTotal.WithLabelValues(publisher.Name(), outcome).Inc()
+	}
+
+	now := s.now()
+
+	if err := errors.Join(errs...); err != nil {
+		message := err.Error()
+		event.LastError = &message
+		event.NextAttemptAt = now.Add(s.retryDelay(event.Attempts))
+
+		log.Warn("failed to publish outbox event", slog.Time("next_attempt_at", event.NextAttemptAt), sl.Err(err))
+	} else {
+		event.PublishedAt = &now
+		event.LastError = nil
+	}
+
+	err := s.repo.FinishOutboxAttempt(ctx, &event)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: failed to record outbox publication: %w", op, err)
+	}
+
+	return nil
+}
+
+// retryDelay is the backoff before the attempt following the given one: the base delay doubled
+// with every failed attempt, capped at the maximum delay.
+func (s *OutboxServiceImpl) retryDelay(attempts int) time.Duration {
+	delay := s.retryBaseDelay
+
+	for i := 1; i < attempts && delay < s.retryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, s.retryMaxDelay)
+}
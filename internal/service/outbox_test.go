@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxServiceImpl_ClaimOutboxEvents(t *testing.T) {
+	ctx := context.Background()
+	now := testNow.UTC()
+	events := []domain.OutboxEvent{{ID: 1, Type: domain.WebhookPRCreated}}
+
+	repo := new(OutboxRepositoryMock)
+	repo.On("ClaimOutboxEvents", ctx, 50, now, now.Add(time.Minute)).Return(events, nil).Once()
+
+	s := NewOutboxService(repo, nil, slog.New(slog.NewJSONHandler(os.Stdout, nil)), WithOutboxClock(fixedClock(testNow)))
+	claimed, err := s.ClaimOutboxEvents(ctx, 50)
+
+	require.NoError(t, err)
+	assert.Equal(t, events, claimed)
+	repo.AssertExpectations(t)
+}
+
+func TestOutboxServiceImpl_PublishOutboxEvent(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	now := testNow.UTC()
+	lastError := "broker unavailable"
+	event := domain.OutboxEvent{
+		ID:            5,
+		Type:          domain.WebhookPRMerged,
+		PullRequestID: "pr-1",
+		Payload:       []byte(`{"event":"pr.merged"}`),
+		Attempts:      3,
+		NextAttemptAt: now.Add(time.Minute),
+		LastError:     &lastError,
+	}
+
+	newPublisher := func(name string, err error) *EventPublisherMock {
+		publisher := new(EventPublisherMock)
+		publisher.On("Name").Return(name)
+		publisher.On("Publish", ctx, event).Return(err).Once()
+
+		return publisher
+	}
+
+	t.Run("Event accepted by every sink is published", func(t *testing.T) {
+		published := event
+		published.PublishedAt = &now
+		published.LastError = nil
+
+		repo := new(OutboxRepositoryMock)
+		repo.On("FinishOutboxAttempt", ctx, &published).Return(nil).Once()
+
+		first, second := newPublisher("log", nil), newPublisher("kafka", nil)
+		s := NewOutboxService(repo, []EventPublisher{first, second}, logger, WithOutboxClock(fixedClock(testNow)))
+
+		require.NoError(t, s.PublishOutboxEvent(ctx, event))
+		repo.AssertExpectations(t)
+		first.AssertExpectations(t)
+		second.AssertExpectations(t)
+	})
+
+	t.Run("Event rejected by a sink is retried with backoff", func(t *testing.T) {
+		message := "kafka: broker unavailable"
+		retried := event
+		retried.NextAttemptAt = now.Add(4 * time.Second)
+		retried.LastError = &message
+
+		repo := new(OutboxRepositoryMock)
+		repo.On("FinishOutboxAttempt", ctx, &retried).Return(nil).Once()
+
+		first, second := newPublisher("log", nil), newPublisher("kafka", errors.New("broker unavailable"))
+		s := NewOutboxService(repo, []EventPublisher{first, second}, logger, WithOutboxClock(fixedClock(testNow)),
+			WithOutboxPublishPolicy(time.Minute, time.Second, 30*time.Second))
+
+		require.NoError(t, s.PublishOutboxEvent(ctx, event))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Interrupted publication is not recorded", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		publisher := new(EventPublisherMock)
+		publisher.On("Publish", cancelled, event).Return(context.Canceled).Once()
+
+		repo := new(OutboxRepositoryMock)
+		s := NewOutboxService(repo, []EventPublisher{publisher}, logger)
+
+		assert.ErrorIs(t, s.PublishOutboxEvent(cancelled, event), context.Canceled)
+		repo.AssertNotCalled(t, "FinishOutboxAttempt", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Event gone from the outbox is dropped", func(t *testing.T) {
+		repo := new(OutboxRepositoryMock)
+		repo.On("FinishOutboxAttempt", ctx, mock.Anything).Return(apperrors.ErrNotFound).Once()
+
+		s := NewOutboxService(repo, []EventPublisher{newPublisher("log", nil)}, logger)
+
+		require.NoError(t, s.PublishOutboxEvent(ctx, event))
+	})
+}
+
+func TestOutboxServiceImpl_retryDelay(t *testing.T) {
+	s := NewOutboxService(nil, nil, slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+		WithOutboxPublishPolicy(time.Minute, time.Second, 5*time.Minute))
+
+	assert.Equal(t, time.Second, s.retryDelay(1))
+	assert.Equal(t, 2*time.Second, s.retryDelay(2))
+	assert.Equal(t, 256*time.Second, s.retryDelay(9))
+	assert.Equal(t, 5*time.Minute, s.retryDelay(10))
+	assert.Equal(t, 5*time.Minute, s.retryDelay(1000))
+}
+
+func TestPullRequestServiceImpl_MergePR_WritesOutboxEvent(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	prID := "pr-to-merge"
+
+	t.Run("Merging an OPEN PR writes pr.merged in the transaction", func(t *testing.T) {
+		transactorMock := new(TransactorMock)
+		prCmdMock := new(PRCommandRepositoryMock)
+		prQueryMock := new(PRQueryRepositoryMock)
+		outboxMock := new(OutboxRepositoryMock)
+
+		_, mockedTx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+
+		transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+		prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).
+			Return(&domain.PullRequest{ID: prID, Name: "Add feature", AuthorID: "u1", Status: api.PullRequestStatusOPEN}, nil).Once()
+		prCmdMock.On("UpdatePRStatus", inTx(mockedTx), prID, api.PullRequestStatusMERGED, testNow.UTC()).Return(nil, nil).Once()
+		prQueryMock.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{"rev1"}, nil).Once()
+		prQueryMock.On("GetStatsByUserIDs", inTx(mockedTx), []string{"rev1"}).Return([]domain.Stats{}, nil).Once()
+		outboxMock.On("AddOutboxEvent", inTx(mockedTx), mock.MatchedBy(func(event *domain.OutboxEvent) bool {
+			return event.Type == domain.WebhookPRMerged && event.PullRequestID == prID && event.OccurredAt.Equal(testNow) &&
+				len(event.Payload) > 0
+		})).Return(nil).Once()
+
+		service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil, nil, nil,
+			WithOutbox(outboxMock), WithClock(fixedClock(testNow)))
+		_, err := service.MergePR(ctx, prID, MergeOptions{})
+
+		require.NoError(t, err)
+		outboxMock.AssertExpectations(t)
+	})
+
+	t.Run("Failure to write rolls the merge back", func(t *testing.T) {
+		transactorMock := new(TransactorMock)
+		prCmdMock := new(PRCommandRepositoryMock)
+		prQueryMock := new(PRQueryRepositoryMock)
+		outboxMock := new(OutboxRepositoryMock)
+		errWrite := errors.New("db error")
+
+		_, mockedTx, smock := newMockDBAndTx(t)
+		smock.ExpectRollback()
+
+		transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+		prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(&domain.PullRequest{ID: prID, Status: api.PullRequestStatusOPEN}, nil).Once()
+		prCmdMock.On("UpdatePRStatus", inTx(mockedTx), prID, api.PullRequestStatusMERGED, mock.Anything).Return(nil, nil).Once()
+		prQueryMock.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{}, nil).Once()
+		prQueryMock.On("GetStatsByUserIDs", inTx(mockedTx), []string{}).Return([]domain.Stats{}, nil).Once()
+		outboxMock.On("AddOutboxEvent", inTx(mockedTx), mock.Anything).Return(errWrite).Once()
+
+		service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil, nil, nil, WithOutbox(outboxMock))
+		_, err := service.MergePR(ctx, prID, MergeOptions{})
+
+		assert.ErrorIs(t, err, errWrite)
+	})
+}
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// maxPendingLimit caps the number of queue entries listed or filled at once.
+const maxPendingLimit = 100
+
+// maxAssignDelay bounds how far ahead the assignment of the reviewers can be deferred.
+const maxAssignDelay = 30 * 24 * time.Hour
+
+func (s *PullRequestServiceImpl) GetPendingAssignments(ctx context.Context, teamName string, limit int) (*api.PendingAssignmentsResponse, error) {
+	const op = "internal.service.pullrequest.GetPendingAssignments"
+
+	if limit < 1 || limit > maxPendingLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", apperrors.ErrValidation, maxPendingLimit)
+	}
+
+	resp := &api.PendingAssignmentsResponse{Items: []api.PendingAssignment{}, PendingAssignments: []api.PendingAssignment{}}
+
+	if s.pending == nil {
+		return resp, nil
+	}
+
+	entries, err := s.pending.ListPending(ctx, teamName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list pending assignments: %w", op, err)
+	}
+
+	now := s.now()
+
+	for _, entry := range entries {
+		resp.Items = append(resp.Items, api.PendingAssignment{
+			PullRequestId:   entry.PullRequestID,
+			PullRequestName: entry.PullRequestName,
+			AuthorId:        entry.AuthorID,
+			TeamName:        entry.TeamName,
+			TeamId:          entry.TeamID,
+			Priority:        entry.Priority,
+			EnqueuedAt:      entry.EnqueuedAt,
+			AssignAt:        entry.AssignAt,
+			WaitingSeconds:  int(now.Sub(entry.EnqueuedAt).Seconds()),
+		})
+	}
+
+	resp.PendingAssignments = resp.Items
+
+	return resp, nil
+}
+
+func (s *PullRequestServiceImpl) FillPendingAssignments(ctx context.Context, limit int) (int, error) {
+	const op = "internal.service.pullrequest.FillPendingAssignments"
+
+	if s.pending == nil {
+		return 0, nil
+	}
+
+	if limit < 1 || limit > maxPendingLimit {
+		return 0, fmt.Errorf("%w: limit must be between 1 and %d", apperrors.ErrValidation, maxPendingLimit)
+	}
+
+	entries, err := s.pending.ListDuePending(ctx, s.now(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to list pending assignments: %w", op, err)
+	}
+
+	var (
+		assignedCount int
+		errs          []error
+	)
+
+	// Entries are handled one by one in queue order, each in its own transaction,
+	// so that older entries take the free reviewers first and one failure does not hold up the rest.
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+
+		assigned, err := s.fillPending(ctx, entry)
+		if err != nil {
+			s.log.Error("failed to fill pending assignment", slog.String("op", op),
+				slog.String("pr_id", entry.PullRequestID), sl.Err(err))
+			errs = append(errs, err)
+
+			continue
+		}
+
+		assignedCount += assigned
+	}
+
+	return assignedCount, errors.Join(errs...)
+}
+
+// fillPending assigns as many of the missing reviewers of a queued pull request as its team can provide
+// and returns their number. The entry leaves the queue once the pull request has all its reviewers or is merged.
+func (s *PullRequestServiceImpl) fillPending(ctx context.Context, entry domain.PendingAssignment) (int, error) {
+	const op = "internal.service.pullrequest.fillPending"
+
+	var (
+		pr          *domain.PullRequest
+		assignedIDs []string
+		strategy    domain.AssignmentStrategy
+	)
+
+	assignedAt := s.now()
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		var err error
+
+		assignedIDs = nil
+
+		pr, err = s.prCmd.GetPRByIDWithLock(ctx, entry.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("failed to get pr with lock: %w", err)
+		}
+
+		// Another instance may have drained the entry since the queue was listed.
+		if _, err := s.pending.GetPendingWithLock(ctx, pr.ID); err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				return nil
+			}
+
+			return fmt.Errorf("failed to lock pending assignment: %w", err)
+		}
+
+		if pr.Status != api.PullRequestStatusOPEN {
+			return s.pending.DequeuePending(ctx, pr.ID)
+		}
+
+		if pr.AssignAt != nil && pr.AssignAt.After(assignedAt) {
+			return nil
+		}
+
+		currentIDs, err := s.prQuery.GetReviewerIDs(ctx, pr.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get current reviewers: %w", err)
+		}
+
+		missing := reviewersPerPR - len(currentIDs)
+
+		if missing > 0 {
+			assignedIDs, strategy, err = s.selector.selectReviewers(ctx, entry.TeamID, excludeIDs(pr, currentIDs), missing)
+			if err != nil {
+				return fmt.Errorf("failed to select reviewers: %w", err)
+			}
+
+			// A candidate deactivated since its selection is left for a later fill to replace.
+			assignedIDs, err = keepActiveReviewers(ctx, s.prCmd, s.log, pr.ID, assignedIDs)
+			if err != nil {
+				return err
+			}
+
+			if len(assignedIDs) == 0 {
+				return nil
+			}
+
+			if err := s.prCmd.AssignReviewers(ctx, pr.ID, assignedIDs); err != nil {
+				return fmt.Errorf("failed to assign reviewers: %w", err)
+			}
+
+			if err := s.history.RecordAssignments(ctx, assignmentRecords(pr.ID, assignedIDs, strategy, domain.CausePending, assignedAt)); err != nil {
+				return fmt.Errorf("failed to record assignment history: %w", err)
+			}
+
+			missing -= len(assignedIDs)
+		}
+
+		pr.ReviewerIDs = append(currentIDs, assignedIDs...)
+
+		if missing > 0 {
+			return s.pending.EnqueuePending(ctx, pr.ID, entry.TeamID, missing)
+		}
+
+		if err := s.prCmd.SetNeedMoreReviewers(ctx, pr.ID, false); err != nil {
+			return fmt.Errorf("failed to clear need_more_reviewers: %w", err)
+		}
+
+		return s.pending.DequeuePending(ctx, pr.ID)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(assignedIDs) > 0 {
+		s.log.Info("assigned reviewers to queued pr", slog.String("op", op), slog.String("pr_id", pr.ID),
+			slog.Any("reviewers", assignedIDs), slog.String("strategy", string(strategy)))
+
+		reviewersAssignedTotal.WithLabelValues(string(strategy)).Add(float64(len(assignedIDs)))
+		pendingReviewersFilledTotal.Add(float64(len(assignedIDs)))
+
+		s.notify(ctx, newEvent(domain.EventReviewersAssigned, pr, assignedIDs, assignedAt))
+
+		s.checkReviewerInvariants(ctx, checkedFillPending, pr.ID)
+	}
+
+	return len(assignedIDs), nil
+}
+
+// assignmentTime returns the time the reviewers of a new pull request are to be assigned at: the requested time,
+// or now if it has passed, moved into the working hours. It returns nil if the reviewers are to be assigned
+// right away, and apperrors.ErrValidation if the time is too far ahead or there is no queue to defer them in.
+func (s *PullRequestServiceImpl) assignmentTime(requested *time.Time) (*time.Time, error) {
+	if requested == nil {
+		return nil, nil
+	}
+
+	if s.pending == nil {
+		return nil, fmt.Errorf("%w: deferred reviewer assignment is disabled", apperrors.ErrValidation)
+	}
+
+	now := s.now()
+	if requested.After(now.Add(maxAssignDelay)) {
+		return nil, fmt.Errorf("%w: assign_at must be within %d days", apperrors.ErrValidation, int(maxAssignDelay/(24*time.Hour)))
+	}
+
+	at := requested.UTC()
+	if at.Before(now) {
+		at = now
+	}
+
+	if s.workingHours != nil {
+		at = s.workingHours.Next(at)
+	}
+
+	if !at.After(now) {
+		return nil, nil
+	}
+
+	return &at, nil
+}
+
+// Outcomes of requeuePR, counted by the pending_backfill_pull_requests_total metric.
+const (
+	backfillRequeued = "requeued"
+	backfillCleared  = "cleared"
+	backfillHeld     = "held"
+)
+
+func (s *PullRequestServiceImpl) RequeueNeedingReviewers(ctx context.Context, limit int) (int, error) {
+	const op = "internal.service.pullrequest.RequeueNeedingReviewers"
+
+	if s.pending == nil {
+		return 0, nil
+	}
+
+	if limit < 1 || limit > maxPendingLimit {
+		return 0, fmt.Errorf("%w: limit must be between 1 and %d", apperrors.ErrValidation, maxPendingLimit)
+	}
+
+	var (
+		requeued int
+		afterID  string
+		errs     []error
+	)
+
+	// The pull requests are paged by ID, so that those held back by the quota do not fill every page.
+	for {
+		ids, err := s.pending.ListUnqueuedNeedingReviewers(ctx, afterID, limit)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: failed to list pull requests needing reviewers: %w", op, err))
+			break
+		}
+
+		for _, prID := range ids {
+			if ctx.Err() != nil {
+				return requeued, errors.Join(append(errs, ctx.Err())...)
+			}
+
+			outcome, err := s.requeuePR(ctx, prID)
+			if err != nil {
+				s.log.Error("failed to requeue pr needing reviewers", slog.String("op", op),
+					slog.String("pr_id", prID), sl.Err(err))
+				errs = append(errs, err)
+
+				continue
+			}
+
+			if outcome == "" {
+				continue
+			}
+
+			pendingBackfillPRsTotal.WithLabelValues(outcome).Inc()
+
+			if outcome == backfillRequeued {
+				requeued++
+			}
+		}
+
+		if len(ids) < limit {
+			break
+		}
+
+		afterID = ids[len(ids)-1]
+	}
+
+	return requeued, errors.Join(errs...)
+}
+
+// requeuePR puts an unqueued pull request flagged with need_more_reviewers into the pending assignment queue,
+// so that FillPendingAssignments assigns its missing reviewers. Such pull requests were either created before
+// the queue existed or held back by the author quota, and the latter stay out of the queue while the author
+// still has too many other open pull requests. It returns an empty outcome if the pull request needs nothing.
+func (s *PullRequestServiceImpl) requeuePR(ctx context.Context, prID string) (string, error) {
+	const op = "internal.service.pullrequest.requeuePR"
+
+	var outcome string
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		outcome = ""
+
+		pr, err := s.prCmd.GetPRByIDWithLock(ctx, prID)
+		if err != nil {
+			return fmt.Errorf("failed to get pr with lock: %w", err)
+		}
+
+		// The pull request may have changed since it was listed.
+		if pr.Status != api.PullRequestStatusOPEN || !pr.NeedMoreReviewers {
+			return nil
+		}
+
+		// Another instance or CreatePR may have queued it meanwhile.
+		_, err = s.pending.GetPendingWithLock(ctx, pr.ID)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, apperrors.ErrNotFound) {
+			return fmt.Errorf("failed to lock pending assignment: %w", err)
+		}
+
+		currentIDs, err := s.prQuery.GetReviewerIDs(ctx, pr.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get current reviewers: %w", err)
+		}
+
+		missing := reviewersPerPR - len(currentIDs)
+		if missing <= 0 {
+			if err := s.prCmd.SetNeedMoreReviewers(ctx, pr.ID, false); err != nil {
+				return fmt.Errorf("failed to clear need_more_reviewers: %w", err)
+			}
+
+			outcome = backfillCleared
+
+			return nil
+		}
+
+		teamID, err := s.userPR.GetAuthorTeamID(ctx, pr.AuthorID)
+		if err != nil {
+			return fmt.Errorf("failed to get author team id: %w", err)
+		}
+
+		policy, err := s.selector.policy(ctx, teamID)
+		if err != nil {
+			return fmt.Errorf("failed to get team policy: %w", err)
+		}
+
+		if policy.AuthorOpenPRLimit != nil {
+			count, err := s.prCmd.CountOpenPRsByAuthor(ctx, pr.AuthorID)
+			if err != nil {
+				return fmt.Errorf("failed to count open PRs of the author: %w", err)
+			}
+
+			// The pull request itself is open, so only the others count towards the quota.
+			if count-1 >= *policy.AuthorOpenPRLimit {
+				outcome = backfillHeld
+
+				return nil
+			}
+		}
+
+		if err := s.pending.EnqueuePending(ctx, pr.ID, teamID, missing); err != nil {
+			return fmt.Errorf("failed to queue pr for assignment: %w", err)
+		}
+
+		outcome = backfillRequeued
+
+		return nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if outcome == backfillRequeued {
+		s.log.Info("queued pr needing reviewers", slog.String("op", op), slog.String("pr_id", prID))
+	}
+
+	return outcome, nil
+}
@@ -0,0 +1,470 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestServiceImpl_FillPendingAssignments(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	entry := domain.PendingAssignment{PullRequestID: "pr-1", AuthorID: "author-1", TeamID: 1, Priority: 2}
+	openPR := &domain.PullRequest{ID: "pr-1", AuthorID: "author-1", Status: api.PullRequestStatusOPEN, NeedMoreReviewers: true}
+	later := testNow.Add(time.Minute)
+
+	testCases := []struct {
+		name          string
+		setupMocks    func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock, pending *PendingAssignmentRepositoryMock, notifier *NotifierMock)
+		expectedCount int
+	}{
+		{
+			name: "Complete fill leaves the queue",
+			setupMocks: func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock, pending *PendingAssignmentRepositoryMock, notifier *NotifierMock) {
+				prCmd.On("GetPRByIDWithLock", mock.Anything, "pr-1").Return(openPR, nil).Once()
+				pending.On("GetPendingWithLock", mock.Anything, "pr-1").Return(&entry, nil).Once()
+				prQuery.On("GetReviewerIDs", mock.Anything, "pr-1").Return([]string{}, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{"rev-1", "rev-2"}, nil).Once()
+				prCmd.On("LockActiveUsers", mock.Anything, []string{"rev-1", "rev-2"}).Return([]string{"rev-1", "rev-2"}, nil).Once()
+				prCmd.On("AssignReviewers", mock.Anything, "pr-1", []string{"rev-1", "rev-2"}).Return(nil).Once()
+				history.On("RecordAssignments", mock.Anything, mock.Anything).Return(nil).Once()
+				prCmd.On("SetNeedMoreReviewers", mock.Anything, "pr-1", false).Return(nil).Once()
+				pending.On("DequeuePending", mock.Anything, "pr-1").Return(nil).Once()
+				notifier.On("Notify", ctx, mock.Anything).Once()
+			},
+			expectedCount: 2,
+		},
+		{
+			name: "Partial fill lowers the priority",
+			setupMocks: func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock, pending *PendingAssignmentRepositoryMock, notifier *NotifierMock) {
+				prCmd.On("GetPRByIDWithLock", mock.Anything, "pr-1").Return(openPR, nil).Once()
+				pending.On("GetPendingWithLock", mock.Anything, "pr-1").Return(&entry, nil).Once()
+				prQuery.On("GetReviewerIDs", mock.Anything, "pr-1").Return([]string{}, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{"rev-1"}, nil).Once()
+				prCmd.On("LockActiveUsers", mock.Anything, []string{"rev-1"}).Return([]string{"rev-1"}, nil).Once()
+				prCmd.On("AssignReviewers", mock.Anything, "pr-1", []string{"rev-1"}).Return(nil).Once()
+				history.On("RecordAssignments", mock.Anything, mock.Anything).Return(nil).Once()
+				pending.On("EnqueuePending", mock.Anything, "pr-1", 1, 1).Return(nil).Once()
+				notifier.On("Notify", ctx, mock.Anything).Once()
+			},
+			expectedCount: 1,
+		},
+		{
+			name: "Candidate deactivated since its selection is skipped",
+			setupMocks: func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock, pending *PendingAssignmentRepositoryMock, notifier *NotifierMock) {
+				prCmd.On("GetPRByIDWithLock", mock.Anything, "pr-1").Return(openPR, nil).Once()
+				pending.On("GetPendingWithLock", mock.Anything, "pr-1").Return(&entry, nil).Once()
+				prQuery.On("GetReviewerIDs", mock.Anything, "pr-1").Return([]string{}, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{"rev-2", "rev-1"}, nil).Once()
+				prCmd.On("LockActiveUsers", mock.Anything, []string{"rev-2", "rev-1"}).Return([]string{"rev-1"}, nil).Once()
+				prCmd.On("AssignReviewers", mock.Anything, "pr-1", []string{"rev-1"}).Return(nil).Once()
+				history.On("RecordAssignments", mock.Anything, mock.Anything).Return(nil).Once()
+				pending.On("EnqueuePending", mock.Anything, "pr-1", 1, 1).Return(nil).Once()
+				notifier.On("Notify", ctx, mock.Anything).Once()
+			},
+			expectedCount: 1,
+		},
+		{
+			name: "No candidates keeps the entry",
+			setupMocks: func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock, pending *PendingAssignmentRepositoryMock, notifier *NotifierMock) {
+				prCmd.On("GetPRByIDWithLock", mock.Anything, "pr-1").Return(openPR, nil).Once()
+				pending.On("GetPendingWithLock", mock.Anything, "pr-1").Return(&entry, nil).Once()
+				prQuery.On("GetReviewerIDs", mock.Anything, "pr-1").Return([]string{}, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{}, nil).Once()
+			},
+		},
+		{
+			name: "Merged PR leaves the queue",
+			setupMocks: func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock, pending *PendingAssignmentRepositoryMock, notifier *NotifierMock) {
+				prCmd.On("GetPRByIDWithLock", mock.Anything, "pr-1").
+					Return(&domain.PullRequest{ID: "pr-1", AuthorID: "author-1", Status: api.PullRequestStatusMERGED}, nil).Once()
+				pending.On("GetPendingWithLock", mock.Anything, "pr-1").Return(&entry, nil).Once()
+				pending.On("DequeuePending", mock.Anything, "pr-1").Return(nil).Once()
+			},
+		},
+		{
+			name: "Closed PR leaves the queue",
+			setupMocks: func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock, pending *PendingAssignmentRepositoryMock, notifier *NotifierMock) {
+				prCmd.On("GetPRByIDWithLock", mock.Anything, "pr-1").
+					Return(&domain.PullRequest{ID: "pr-1", AuthorID: "author-1", Status: api.PullRequestStatusCLOSED}, nil).Once()
+				pending.On("GetPendingWithLock", mock.Anything, "pr-1").Return(&entry, nil).Once()
+				pending.On("DequeuePending", mock.Anything, "pr-1").Return(nil).Once()
+			},
+		},
+		{
+			name: "PR deferred since the queue was listed is skipped",
+			setupMocks: func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock, pending *PendingAssignmentRepositoryMock, notifier *NotifierMock) {
+				prCmd.On("GetPRByIDWithLock", mock.Anything, "pr-1").
+					Return(&domain.PullRequest{ID: "pr-1", AuthorID: "author-1", Status: api.PullRequestStatusOPEN, AssignAt: &later}, nil).Once()
+				pending.On("GetPendingWithLock", mock.Anything, "pr-1").Return(&entry, nil).Once()
+			},
+		},
+		{
+			name: "Entry drained concurrently is skipped",
+			setupMocks: func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock, pending *PendingAssignmentRepositoryMock, notifier *NotifierMock) {
+				prCmd.On("GetPRByIDWithLock", mock.Anything, "pr-1").Return(openPR, nil).Once()
+				pending.On("GetPendingWithLock", mock.Anything, "pr-1").Return(nil, apperrors.ErrNotFound).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transactorMock := new(TransactorMock)
+			prCmdMock := new(PRCommandRepositoryMock)
+			prQueryMock := new(PRQueryRepositoryMock)
+			userPRMock := new(UserPRRepositoryMock)
+			historyMock := new(AssignmentHistoryRepositoryMock)
+			pendingMock := new(PendingAssignmentRepositoryMock)
+			notifierMock := new(NotifierMock)
+
+			_, mockedTx, smock := newMockDBAndTx(t)
+			smock.ExpectCommit()
+
+			transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+			pendingMock.On("ListDuePending", ctx, testNow.UTC(), 10).Return([]domain.PendingAssignment{entry}, nil).Once()
+			tc.setupMocks(prCmdMock, prQueryMock, userPRMock, historyMock, pendingMock, notifierMock)
+
+			service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, userPRMock, nil, historyMock,
+				WithPendingAssignments(pendingMock), WithNotifier(notifierMock), WithClock(fixedClock(testNow)))
+
+			assigned, err := service.FillPendingAssignments(ctx, 10)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCount, assigned)
+
+			prCmdMock.AssertExpectations(t)
+			prQueryMock.AssertExpectations(t)
+			userPRMock.AssertExpectations(t)
+			historyMock.AssertExpectations(t)
+			pendingMock.AssertExpectations(t)
+			notifierMock.AssertExpectations(t)
+			require.NoError(t, smock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPullRequestServiceImpl_RequeueNeedingReviewers(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	openPR := &domain.PullRequest{ID: "pr-1", AuthorID: "author-1", Status: api.PullRequestStatusOPEN, NeedMoreReviewers: true}
+	limit := 2
+
+	testCases := []struct {
+		name          string
+		setupMocks    func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, policy *PolicyRepositoryMock, pending *PendingAssignmentRepositoryMock)
+		expectedCount int
+	}{
+		{
+			name: "PR missing a reviewer is queued",
+			setupMocks: func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, policy *PolicyRepositoryMock, pending *PendingAssignmentRepositoryMock) {
+				prCmd.On("GetPRByIDWithLock", mock.Anything, "pr-1").Return(openPR, nil).Once()
+				pending.On("GetPendingWithLock", mock.Anything, "pr-1").Return(nil, apperrors.ErrNotFound).Once()
+				prQuery.On("GetReviewerIDs", mock.Anything, "pr-1").Return([]string{"rev-1"}, nil).Once()
+				userPR.On("GetAuthorTeamID", mock.Anything, "author-1").Return(1, nil).Once()
+				policy.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil).Once()
+				pending.On("EnqueuePending", mock.Anything, "pr-1", 1, 1).Return(nil).Once()
+			},
+			expectedCount: 1,
+		},
+		{
+			name: "PR released by the author quota is queued",
+			setupMocks: func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, policy *PolicyRepositoryMock, pending *PendingAssignmentRepositoryMock) {
+				prCmd.On("GetPRByIDWithLock", mock.Anything, "pr-1").Return(openPR, nil).Once()
+				pending.On("GetPendingWithLock", mock.Anything, "pr-1").Return(nil, apperrors.ErrNotFound).Once()
+				prQuery.On("GetReviewerIDs", mock.Anything, "pr-1").Return([]string{}, nil).Once()
+				userPR.On("GetAuthorTeamID", mock.Anything, "author-1").Return(1, nil).Once()
+				policy.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1, AuthorOpenPRLimit: &limit}, nil).Once()
+				prCmd.On("CountOpenPRsByAuthor", mock.Anything, "author-1").Return(2, nil).Once()
+				pending.On("EnqueuePending", mock.Anything, "pr-1", 1, 2).Return(nil).Once()
+			},
+			expectedCount: 1,
+		},
+		{
+			name: "PR held back by the author quota stays unqueued",
+			setupMocks: func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, policy *PolicyRepositoryMock, pending *PendingAssignmentRepositoryMock) {
+				prCmd.On("GetPRByIDWithLock", mock.Anything, "pr-1").Return(openPR, nil).Once()
+				pending.On("GetPendingWithLock", mock.Anything, "pr-1").Return(nil, apperrors.ErrNotFound).Once()
+				prQuery.On("GetReviewerIDs", mock.Anything, "pr-1").Return([]string{}, nil).Once()
+				userPR.On("GetAuthorTeamID", mock.Anything, "author-1").Return(1, nil).Once()
+				policy.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1, AuthorOpenPRLimit: &limit}, nil).Once()
+				prCmd.On("CountOpenPRsByAuthor", mock.Anything, "author-1").Return(3, nil).Once()
+			},
+		},
+		{
+			name: "Stale flag of a fully reviewed PR is cleared",
+			setupMocks: func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, policy *PolicyRepositoryMock, pending *PendingAssignmentRepositoryMock) {
+				prCmd.On("GetPRByIDWithLock", mock.Anything, "pr-1").Return(openPR, nil).Once()
+				pending.On("GetPendingWithLock", mock.Anything, "pr-1").Return(nil, apperrors.ErrNotFound).Once()
+				prQuery.On("GetReviewerIDs", mock.Anything, "pr-1").Return([]string{"rev-1", "rev-2"}, nil).Once()
+				prCmd.On("SetNeedMoreReviewers", mock.Anything, "pr-1", false).Return(nil).Once()
+			},
+		},
+		{
+			name: "PR queued concurrently is skipped",
+			setupMocks: func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, policy *PolicyRepositoryMock, pending *PendingAssignmentRepositoryMock) {
+				prCmd.On("GetPRByIDWithLock", mock.Anything, "pr-1").Return(openPR, nil).Once()
+				pending.On("GetPendingWithLock", mock.Anything, "pr-1").Return(&domain.PendingAssignment{PullRequestID: "pr-1"}, nil).Once()
+			},
+		},
+		{
+			name: "PR merged since it was listed is skipped",
+			setupMocks: func(prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, policy *PolicyRepositoryMock, pending *PendingAssignmentRepositoryMock) {
+				prCmd.On("GetPRByIDWithLock", mock.Anything, "pr-1").
+					Return(&domain.PullRequest{ID: "pr-1", AuthorID: "author-1", Status: api.PullRequestStatusMERGED, NeedMoreReviewers: true}, nil).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transactorMock := new(TransactorMock)
+			prCmdMock := new(PRCommandRepositoryMock)
+			prQueryMock := new(PRQueryRepositoryMock)
+			userPRMock := new(UserPRRepositoryMock)
+			policyMock := new(PolicyRepositoryMock)
+			pendingMock := new(PendingAssignmentRepositoryMock)
+
+			_, mockedTx, smock := newMockDBAndTx(t)
+			smock.ExpectCommit()
+
+			transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+			// A full page is followed by the next one.
+			pendingMock.On("ListUnqueuedNeedingReviewers", ctx, "", 1).Return([]string{"pr-1"}, nil).Once()
+			pendingMock.On("ListUnqueuedNeedingReviewers", ctx, "pr-1", 1).Return([]string{}, nil).Once()
+			tc.setupMocks(prCmdMock, prQueryMock, userPRMock, policyMock, pendingMock)
+
+			service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, userPRMock, policyMock, nil,
+				WithPendingAssignments(pendingMock))
+
+			requeued, err := service.RequeueNeedingReviewers(ctx, 1)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCount, requeued)
+
+			prCmdMock.AssertExpectations(t)
+			prQueryMock.AssertExpectations(t)
+			userPRMock.AssertExpectations(t)
+			policyMock.AssertExpectations(t)
+			pendingMock.AssertExpectations(t)
+			require.NoError(t, smock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPullRequestServiceImpl_CreatePR_EnqueuesPending(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	transactorMock := new(TransactorMock)
+	prCmdMock := new(PRCommandRepositoryMock)
+	userPRMock := new(UserPRRepositoryMock)
+	historyMock := new(AssignmentHistoryRepositoryMock)
+	pendingMock := new(PendingAssignmentRepositoryMock)
+	notifierMock := new(NotifierMock)
+
+	_, mockedTx, smock := newMockDBAndTx(t)
+	smock.ExpectCommit()
+
+	transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+	userPRMock.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
+	userPRMock.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{"rev-1"}, nil).Once()
+	prCmdMock.On("CreatePR", inTx(mockedTx), mock.AnythingOfType("*domain.PullRequest")).Return(nil).Once()
+	prCmdMock.On("LockActiveUsers", inTx(mockedTx), []string{"rev-1"}).Return([]string{"rev-1"}, nil).Once()
+	prCmdMock.On("AssignReviewers", inTx(mockedTx), "pr-1", []string{"rev-1"}).Return(nil).Once()
+	historyMock.On("RecordAssignments", inTx(mockedTx), mock.Anything).Return(nil).Once()
+	pendingMock.On("EnqueuePending", inTx(mockedTx), "pr-1", 1, 1).Return(nil).Once()
+	notifierMock.On("Notify", ctx, mock.Anything).Once()
+
+	service := NewPullRequestService(transactorMock, logger, prCmdMock, nil, userPRMock, nil, historyMock,
+		WithPendingAssignments(pendingMock), WithNotifier(notifierMock))
+
+	pr, _, err := service.CreatePR(ctx, "pr-1", "feat: small team", "author-1", PRDetails{})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rev-1"}, pr.AssignedReviewers)
+
+	prCmdMock.AssertExpectations(t)
+	historyMock.AssertExpectations(t)
+	pendingMock.AssertExpectations(t)
+	require.NoError(t, smock.ExpectationsWereMet())
+}
+
+func TestPullRequestServiceImpl_CreatePR_DeferredAssignment(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	// testNow is Friday 12:09 UTC; ten hours later the working day is over until Monday.
+	assignAt := testNow.Add(10 * time.Hour)
+	monday := time.Date(2025, time.March, 17, 9, 0, 0, 0, time.UTC)
+
+	transactorMock := new(TransactorMock)
+	prCmdMock := new(PRCommandRepositoryMock)
+	userPRMock := new(UserPRRepositoryMock)
+	historyMock := new(AssignmentHistoryRepositoryMock)
+	pendingMock := new(PendingAssignmentRepositoryMock)
+	notifierMock := new(NotifierMock)
+
+	_, mockedTx, smock := newMockDBAndTx(t)
+	smock.ExpectCommit()
+
+	var stored *domain.PullRequest
+
+	transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+	userPRMock.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
+	prCmdMock.On("CreatePR", inTx(mockedTx), mock.AnythingOfType("*domain.PullRequest")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*domain.PullRequest)
+	}).Return(nil).Once()
+	pendingMock.On("EnqueuePending", inTx(mockedTx), "pr-1", 1, 2).Return(nil).Once()
+
+	service := NewPullRequestService(transactorMock, logger, prCmdMock, nil, userPRMock, nil, historyMock,
+		WithPendingAssignments(pendingMock), WithNotifier(notifierMock), WithClock(fixedClock(testNow)),
+		WithWorkingHours(domain.WorkingHours{
+			Location: time.UTC, Start: 9 * time.Hour, End: 18 * time.Hour,
+			Days: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		}))
+
+	pr, _, err := service.CreatePR(ctx, "pr-1", "feat: nightly build", "author-1", PRDetails{AssignAt: &assignAt})
+
+	require.NoError(t, err)
+	assert.Empty(t, pr.AssignedReviewers)
+	require.NotNil(t, pr.AssignAt)
+	assert.Equal(t, monday, *pr.AssignAt)
+	require.NotNil(t, stored)
+	assert.True(t, stored.NeedMoreReviewers)
+
+	userPRMock.AssertNotCalled(t, "GetRandomActiveReviewers", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	notifierMock.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+	prCmdMock.AssertExpectations(t)
+	pendingMock.AssertExpectations(t)
+	require.NoError(t, smock.ExpectationsWereMet())
+}
+
+func TestPullRequestServiceImpl_assignmentTime(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	now := testNow.UTC()
+
+	// Working hours in Moscow: 09:00-18:00 UTC+3 on weekdays, testNow being Friday 15:09 there.
+	moscow := time.FixedZone("UTC+3", 3*60*60)
+	hours := domain.WorkingHours{
+		Location: moscow, Start: 9 * time.Hour, End: 18 * time.Hour,
+		Days: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	}
+
+	monday := time.Date(2025, time.March, 17, 6, 0, 0, 0, time.UTC)
+
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	testCases := []struct {
+		name         string
+		requested    *time.Time
+		hours        *domain.WorkingHours
+		expected     *time.Time
+		expectedErr  error
+		withoutQueue bool
+	}{
+		{name: "Not deferred"},
+		{name: "Later time is kept", requested: at(2 * time.Hour), expected: at(2 * time.Hour)},
+		{name: "Past time assigns right away", requested: at(-time.Hour)},
+		{name: "Later time within the working hours", requested: at(2 * time.Hour), hours: &hours, expected: at(2 * time.Hour)},
+		{
+			name: "Evening moves to the next working morning", requested: at(5 * time.Hour), hours: &hours,
+			expected: &monday,
+		},
+		{
+			name: "Weekend moves to Monday", requested: at(24 * time.Hour), hours: &hours,
+			expected: &monday,
+		},
+		{name: "Past time within the working hours assigns right away", requested: at(-time.Hour), hours: &hours},
+		{name: "Too far ahead", requested: at(31 * 24 * time.Hour), expectedErr: apperrors.ErrValidation},
+		{name: "No queue", requested: at(time.Hour), withoutQueue: true, expectedErr: apperrors.ErrValidation},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := []PullRequestServiceOption{WithClock(fixedClock(testNow))}
+			if !tc.withoutQueue {
+				opts = append(opts, WithPendingAssignments(new(PendingAssignmentRepositoryMock)))
+			}
+
+			if tc.hours != nil {
+				opts = append(opts, WithWorkingHours(*tc.hours))
+			}
+
+			service := NewPullRequestService(nil, logger, nil, nil, nil, nil, nil, opts...)
+
+			assignAt, err := service.assignmentTime(tc.requested)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tc.expected == nil {
+				assert.Nil(t, assignAt)
+			} else {
+				require.NotNil(t, assignAt)
+				assert.True(t, tc.expected.Equal(*assignAt), "expected %s, got %s", tc.expected, assignAt)
+			}
+		})
+	}
+}
+
+func TestPullRequestServiceImpl_GetPendingAssignments(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("Entries are listed with their waiting time", func(t *testing.T) {
+		pendingMock := new(PendingAssignmentRepositoryMock)
+		enqueuedAt := testNow.Add(-90 * time.Second)
+
+		pendingMock.On("ListPending", ctx, "backend", 20).Return([]domain.PendingAssignment{{
+			PullRequestID:   "pr-1",
+			PullRequestName: "feat: queue",
+			AuthorID:        "author-1",
+			TeamID:          1,
+			TeamName:        "backend",
+			Priority:        2,
+			EnqueuedAt:      enqueuedAt,
+		}}, nil).Once()
+
+		service := NewPullRequestService(nil, logger, nil, nil, nil, nil, nil, WithPendingAssignments(pendingMock), WithClock(fixedClock(testNow)))
+
+		resp, err := service.GetPendingAssignments(ctx, "backend", 20)
+
+		require.NoError(t, err)
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, "pr-1", resp.Items[0].PullRequestId)
+		assert.Equal(t, 2, resp.Items[0].Priority)
+		assert.Equal(t, 90, resp.Items[0].WaitingSeconds)
+		assert.Equal(t, resp.Items, resp.PendingAssignments)
+		assert.Nil(t, resp.NextCursor)
+
+		pendingMock.AssertExpectations(t)
+	})
+
+	t.Run("Limit out of range is rejected", func(t *testing.T) {
+		service := NewPullRequestService(nil, logger, nil, nil, nil, nil, nil, WithPendingAssignments(new(PendingAssignmentRepositoryMock)))
+
+		_, err := service.GetPendingAssignments(ctx, "", maxPendingLimit+1)
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+	})
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/cursor"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// maxListLimit caps the page size of ListPRs.
+const maxListLimit = 100
+
+// PRListQuery selects the pull requests returned by ListPRs. Zero values do not filter.
+type PRListQuery struct {
+	Status   string
+	AuthorID string
+	// TeamID matches the pull requests whose author is currently a member of the team.
+	TeamID int
+	// CreatedFrom is inclusive and CreatedTo is exclusive.
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// Cursor is the next_cursor of the previous page. It cannot be combined with a non-zero Offset.
+	Cursor string
+	Limit  int
+	Offset int
+}
+
+func (s *PullRequestServiceImpl) ListPRs(ctx context.Context, query PRListQuery) (*api.ListPullRequestsResponse, error) {
+	const op = "internal.service.pullrequest.ListPRs"
+
+	switch api.PullRequestStatus(query.Status) {
+	case "", api.PullRequestStatusOPEN, api.PullRequestStatusMERGED, api.PullRequestStatusCLOSED:
+	default:
+		return nil, fmt.Errorf("%w: unknown status '%s'", apperrors.ErrValidation, query.Status)
+	}
+
+	if query.CreatedFrom != nil && query.CreatedTo != nil && !query.CreatedFrom.Before(*query.CreatedTo) {
+		return nil, fmt.Errorf("%w: created_from must be before created_to", apperrors.ErrValidation)
+	}
+
+	if query.Limit < 1 || query.Limit > maxListLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", apperrors.ErrValidation, maxListLimit)
+	}
+
+	if query.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", apperrors.ErrValidation)
+	}
+
+	filter := domain.PRListFilter{
+		Status:      api.PullRequestStatus(query.Status),
+		AuthorID:    query.AuthorID,
+		TeamID:      query.TeamID,
+		CreatedFrom: query.CreatedFrom,
+		CreatedTo:   query.CreatedTo,
+		// One more pull request than requested tells whether there is a next page.
+		Limit:  query.Limit + 1,
+		Offset: query.Offset,
+	}
+
+	if query.Cursor != "" {
+		if query.Offset != 0 {
+			return nil, fmt.Errorf("%w: cursor and offset are mutually exclusive", apperrors.ErrValidation)
+		}
+
+		after, err := decodePRCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		filter.After = after
+	}
+
+	prs, err := s.prQuery.ListPRs(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list prs: %w", op, err)
+	}
+
+	resp := &api.ListPullRequestsResponse{Items: []api.PullRequest{}}
+
+	if len(prs) > query.Limit {
+		prs = prs[:query.Limit]
+		next := encodePRCursor(prs[len(prs)-1])
+		resp.NextCursor = &next
+	}
+
+	for i := range prs {
+		resp.Items = append(resp.Items, *toAPIPullRequest(&prs[i]))
+	}
+
+	resp.PullRequests = resp.Items
+
+	return resp, nil
+}
+
+// totalOf is the total_estimate of a list returned whole, which is exact.
+func totalOf[T any](items []T) *int {
+	total := len(items)
+
+	return &total
+}
+
+// encodePRCursor returns the opaque cursor of the position right after pr.
+func encodePRCursor(pr domain.PullRequest) string {
+	return cursor.EncodeTime(pr.CreatedAt, pr.ID)
+}
+
+// decodePRCursor parses a cursor made by encodePRCursor.
+func decodePRCursor(c string) (*domain.PRCursor, error) {
+	createdAt, id, err := cursor.DecodeTime(c)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+	}
+
+	return &domain.PRCursor{CreatedAt: createdAt, ID: id}, nil
+}
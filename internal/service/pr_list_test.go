@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestServiceImpl_ListPRs(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	newer := domain.PullRequest{ID: "pr-2", Name: "Newer", AuthorID: "u1", Status: api.PullRequestStatusOPEN, CreatedAt: from.Add(2 * time.Hour), ReviewerIDs: []string{"u2"}}
+	older := domain.PullRequest{ID: "pr-1", Name: "Older", AuthorID: "u1", Status: api.PullRequestStatusOPEN, CreatedAt: from.Add(time.Hour), ReviewerIDs: []string{}}
+	oldest := domain.PullRequest{ID: "pr-0", Name: "Oldest", AuthorID: "u1", Status: api.PullRequestStatusOPEN, CreatedAt: from}
+
+	t.Run("Pages follow each other through the cursor", func(t *testing.T) {
+		prQueryMock := new(PRQueryRepositoryMock)
+		prQueryMock.On("ListPRs", ctx, domain.PRListFilter{
+			Status: api.PullRequestStatusOPEN, AuthorID: "u1", TeamID: 3, CreatedFrom: &from, CreatedTo: &to, Limit: 3,
+		}).Return([]domain.PullRequest{newer, older, oldest}, nil).Once()
+		prQueryMock.On("ListPRs", ctx, domain.PRListFilter{
+			Status: api.PullRequestStatusOPEN, AuthorID: "u1", TeamID: 3, CreatedFrom: &from, CreatedTo: &to, Limit: 3,
+			After: &domain.PRCursor{CreatedAt: older.CreatedAt, ID: older.ID},
+		}).Return([]domain.PullRequest{oldest}, nil).Once()
+
+		service := NewPullRequestService(nil, logger, nil, prQueryMock, nil, nil, nil)
+		query := PRListQuery{Status: "OPEN", AuthorID: "u1", TeamID: 3, CreatedFrom: &from, CreatedTo: &to, Limit: 2}
+
+		first, err := service.ListPRs(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, first.Items, 2)
+		assert.Equal(t, "pr-2", first.Items[0].PullRequestId)
+		assert.Equal(t, []string{"u2"}, first.Items[0].AssignedReviewers)
+		assert.Equal(t, "pr-1", first.Items[1].PullRequestId)
+		assert.Equal(t, first.Items, first.PullRequests)
+		require.NotNil(t, first.NextCursor)
+
+		query.Cursor = *first.NextCursor
+
+		second, err := service.ListPRs(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, second.Items, 1)
+		assert.Equal(t, "pr-0", second.Items[0].PullRequestId)
+		assert.Nil(t, second.NextCursor)
+
+		prQueryMock.AssertExpectations(t)
+	})
+
+	t.Run("Empty page", func(t *testing.T) {
+		prQueryMock := new(PRQueryRepositoryMock)
+		prQueryMock.On("ListPRs", ctx, domain.PRListFilter{Limit: 21, Offset: 40}).Return([]domain.PullRequest{}, nil).Once()
+
+		service := NewPullRequestService(nil, logger, nil, prQueryMock, nil, nil, nil)
+
+		resp, err := service.ListPRs(ctx, PRListQuery{Limit: 20, Offset: 40})
+		require.NoError(t, err)
+		assert.Equal(t, &api.ListPullRequestsResponse{Items: []api.PullRequest{}, PullRequests: []api.PullRequest{}}, resp)
+
+		prQueryMock.AssertExpectations(t)
+	})
+
+	testCases := []struct {
+		name  string
+		query PRListQuery
+	}{
+		{name: "Unknown status", query: PRListQuery{Status: "DRAFT", Limit: 20}},
+		{name: "Empty creation range", query: PRListQuery{CreatedFrom: &to, CreatedTo: &from, Limit: 20}},
+		{name: "Limit out of range", query: PRListQuery{Limit: maxListLimit + 1}},
+		{name: "Negative offset", query: PRListQuery{Limit: 20, Offset: -1}},
+		{name: "Cursor with offset", query: PRListQuery{Limit: 20, Offset: 20, Cursor: encodePRCursor(older)}},
+		{name: "Malformed cursor", query: PRListQuery{Limit: 20, Cursor: "not a cursor"}},
+		{name: "Cursor without ID", query: PRListQuery{Limit: 20, Cursor: "MjAyNS0wMy0wMVQwMDowMDowMFp8"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run("Failure - "+tc.name, func(t *testing.T) {
+			service := NewPullRequestService(nil, logger, nil, new(PRQueryRepositoryMock), nil, nil, nil)
+
+			_, err := service.ListPRs(ctx, tc.query)
+			assert.ErrorIs(t, err, apperrors.ErrValidation)
+		})
+	}
+}
+
+func TestPRCursor_RoundTrip(t *testing.T) {
+	pr := domain.PullRequest{ID: "pr|with-separator", CreatedAt: time.Date(2025, 3, 14, 12, 0, 0, 123456000, time.FixedZone("MSK", 3*3600))}
+
+	position, err := decodePRCursor(encodePRCursor(pr))
+	require.NoError(t, err)
+	assert.Equal(t, pr.ID, position.ID)
+	assert.True(t, pr.CreatedAt.Equal(position.CreatedAt))
+}
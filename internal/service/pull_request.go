@@ -2,142 +2,681 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/cursor"
 	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/idgen"
+	"github.com/YusovID/pr-reviewer-service/internal/localcache"
 	"github.com/YusovID/pr-reviewer-service/internal/repository"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
-	"github.com/jmoiron/sqlx"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
 )
 
 // PullRequestService defines the application's business logic for pull requests.
+// It is split into commands and queries like the repositories, so that a consumer can depend only
+// on the half it uses and a read-only deployment can serve the queries from a replica.
 type PullRequestService interface {
+	PRCommandService
+	PRQueryService
+}
+
+// PRCommandService defines the operations that change pull requests, their reviewers and their queues.
+type PRCommandService interface {
 	// CreatePR creates a new pull request and automatically assigns up to two active reviewers
-	// from the author's team.
-	CreatePR(ctx context.Context, prID string, prName string, authorID string) (*api.PullRequest, error)
+	// from the author's team. Custom field values that do not match the fields of the team
+	// yield apperrors.ErrValidation. If the team policy limits open PRs per author and the author
+	// has reached the limit, it returns apperrors.ErrAuthorQuotaExceeded or creates the PR
+	// without reviewers, as the policy says.
+	// The created flag is false when the PR already existed and the service is configured
+	// to return its current state instead of apperrors.ErrAlreadyExists. An empty prID is replaced
+	// with a generated one, see WithIDGenerator.
+	CreatePR(ctx context.Context, prID string, prName string, authorID string, details PRDetails) (pr *api.PullRequest, created bool, err error)
 	// MergePR marks a pull request as 'MERGED'. The operation is idempotent.
-	MergePR(ctx context.Context, prID string) (*api.PullRequest, error)
+	// The response carries the reviewers' review counters as of the merge.
+	// Returns apperrors.ErrPRClosed if the PR has been closed, and apperrors.ErrApprovalRequired
+	// if the service requires approvals and a reviewer has not approved the PR.
+	// During a freeze window of the author's team or of the organization it returns apperrors.ErrMergeFrozen
+	// unless opts.OverrideFreeze is set.
+	MergePR(ctx context.Context, prID string, opts MergeOptions) (*api.MergeResponse, error)
+	// ClosePR marks an abandoned pull request as 'CLOSED', so that it no longer counts towards
+	// the open reviews of its reviewers. The operation is idempotent.
+	// Returns apperrors.ErrPRMerged if the PR has already been merged.
+	ClosePR(ctx context.Context, prID string) (*api.PullRequest, error)
+	// ApprovePR records the approval of an assigned reviewer and notifies the author.
+	// Returns apperrors.ErrReviewerNotAssigned if the user does not review the PR,
+	// and apperrors.ErrPRMerged or apperrors.ErrPRClosed if the PR is no longer open.
+	ApprovePR(ctx context.Context, prID string, reviewerID string) (*api.PullRequest, error)
+	// RequestChanges is ApprovePR for a reviewer asking the author for changes.
+	RequestChanges(ctx context.Context, prID string, reviewerID string) (*api.PullRequest, error)
 	// ReassignReviewer replaces an assigned reviewer with another active member from the same team.
-	// Returns an error if the PR is already merged, the reviewer is not assigned,
-	// or no replacement candidate is available.
-	ReassignReviewer(ctx context.Context, prID string, oldReviewerID string) (*api.ReassignResponse, error)
+	// Returns an error if the PR is already merged or closed, the reviewer is not assigned,
+	// or no replacement candidate is available. A non-blank note is kept in the assignment history
+	// and delivered to the new reviewer with the notification.
+	ReassignReviewer(ctx context.Context, prID string, oldReviewerID string, note string) (*api.ReassignResponse, error)
+	// FillPendingAssignments assigns reviewers to up to limit queued pull requests as far as the teams have
+	// active members to spare, and returns the number of reviewers assigned.
+	FillPendingAssignments(ctx context.Context, limit int) (int, error)
+	// RequeueNeedingReviewers queues the open pull requests flagged with need_more_reviewers that are not
+	// in the pending assignment queue, looking at limit of them at a time, and returns the number queued.
+	RequeueNeedingReviewers(ctx context.Context, limit int) (int, error)
+	// RefreshStats recomputes the precomputed statistics GetStats reads, and reports whether it did:
+	// a refresh already running on another instance is not repeated. It does nothing without WithStatsView.
+	RefreshStats(ctx context.Context) (bool, error)
+	// BackfillReviewerDurations recomputes, in a transaction of its own, the assignment and first review times
+	// GetStats reports durations from for the reviewers of up to limit pull requests with IDs greater than afterID.
+	// It returns the ID of the last of the pull requests, empty once none are left, and the number of reviewers updated.
+	BackfillReviewerDurations(ctx context.Context, afterID string, limit int) (string, int, error)
+	// EnqueueCreatePR queues the creation of a pull request and returns the request to poll for its outcome.
+	// Returns apperrors.ErrValidation if asynchronous creation is disabled.
+	EnqueueCreatePR(ctx context.Context, prID string, prName string, authorID string, details PRDetails) (*api.AsyncCreateRequest, error)
+	// ClaimCreatePRRequests takes up to limit queued creations, and creations whose processing outlived the lease,
+	// for processing.
+	ClaimCreatePRRequests(ctx context.Context, limit int) ([]domain.CreatePRRequest, error)
+	// ProcessCreatePRRequest creates the pull request of a claimed creation and records the outcome.
+	// A creation failing on an unexpected error is queued again until it runs out of attempts.
+	ProcessCreatePRRequest(ctx context.Context, req domain.CreatePRRequest) error
+	// SubscribePR subscribes a user to the notifications about every state change of a pull request.
+	// The created flag is false when the user was already subscribed.
+	// Returns apperrors.ErrNotFound if the PR or the user does not exist
+	// and apperrors.ErrValidation if subscriptions are disabled.
+	SubscribePR(ctx context.Context, prID string, userID string) (sub *api.PullRequestSubscription, created bool, err error)
+}
+
+// PRQueryService defines the read-only operations on pull requests. None of them writes to the database.
+type PRQueryService interface {
+	// GetPR returns a pull request with its assigned reviewers and their reviews.
+	GetPR(ctx context.Context, prID string) (*api.PullRequest, error)
+	// ExpandReviewers fills the Reviewers field of the pull request with the reason
+	// and time of each current assignment, taken from the assignment history.
+	ExpandReviewers(ctx context.Context, pr *api.PullRequest) error
+	// GetAssignmentHistory returns every assignment and replacement of the reviewers of a pull request, oldest first.
+	// Returns apperrors.ErrNotFound if the pull request does not exist.
+	GetAssignmentHistory(ctx context.Context, prID string) (*api.AssignmentHistoryResponse, error)
 	// GetReviewAssignments returns a list of pull requests assigned to a specific user for review.
-	GetReviewAssignments(ctx context.Context, userID string) (*api.GetReviewResponse, error)
-	// GetStats retrieves review statistics for all users.
-	GetStats(ctx context.Context) (*api.StatsResponse, error)
+	// customFields are key:value filters on the custom fields of the pull requests;
+	// a malformed filter yields apperrors.ErrValidation.
+	GetReviewAssignments(ctx context.Context, userID string, customFields []string) (*api.GetReviewResponse, error)
+	// SearchPRs finds pull requests by their names, most relevant first, optionally filtered by key:value custom fields.
+	// Returns apperrors.ErrValidation for a blank query, an unknown status, a malformed filter or cursor,
+	// an out of range page or a cursor combined with an offset.
+	SearchPRs(ctx context.Context, query PRSearchQuery) (*api.SearchPullRequestsResponse, error)
+	// ListPRs returns a page of pull requests, newest first, that match the filters of query.
+	// Returns apperrors.ErrValidation for an unknown status, an empty creation range, a malformed cursor,
+	// an out of range page or a cursor combined with an offset.
+	ListPRs(ctx context.Context, query PRListQuery) (*api.ListPullRequestsResponse, error)
+	// GetStats retrieves review statistics for all users, limited to the period. The statistics are read
+	// from a single snapshot; with WithStatsView, those of an unbounded period are the precomputed ones.
+	// Returns apperrors.ErrValidation for an empty period.
+	GetStats(ctx context.Context, period domain.StatsPeriod) (*api.StatsResponse, error)
+	// GetLeaderboard ranks up to limit users by their reviews of the pull requests merged in the current calendar
+	// week or month. Returns apperrors.ErrValidation for an unknown period or an out of range limit.
+	GetLeaderboard(ctx context.Context, period domain.LeaderboardPeriod, limit int) (*api.LeaderboardResponse, error)
+	// GetPendingAssignments returns up to limit pull requests waiting for reviewers, in the order they are served.
+	// A non-empty teamName limits the queue to the pull requests of that team.
+	GetPendingAssignments(ctx context.Context, teamName string, limit int) (*api.PendingAssignmentsResponse, error)
+	// GetOpenPRAgeStats returns the age distribution of open pull requests per team of their authors.
+	GetOpenPRAgeStats(ctx context.Context) ([]domain.OpenPRAgeStats, error)
+	// GetCreatePRRequest returns the state of a queued creation, with the pull request once it has been created.
+	GetCreatePRRequest(ctx context.Context, requestID int64) (*api.AsyncCreateRequest, error)
+	// PreviewAssignment selects the reviewers a new pull request of the author would get now, as CreatePR does,
+	// without creating the pull request or assigning anyone. The author quota of the team policy is not checked.
+	// Returns apperrors.ErrNotFound if the author does not exist or has no team.
+	PreviewAssignment(ctx context.Context, authorID string) (*AssignmentPreview, error)
+}
+
+// reviewersPerPR is the number of reviewers a pull request gets.
+const reviewersPerPR = 2
+
+// PRDetails holds the optional descriptive fields of a pull request.
+type PRDetails struct {
+	Description *string
+	ExternalURL *string
+	// CustomFields holds the values of the custom fields defined by the author's team, decoded from JSON.
+	CustomFields map[string]any
+	// AssignAt defers the assignment of the reviewers to the given time, moved into the working hours.
+	// A time that has passed assigns them right away, unless that is outside the working hours.
+	AssignAt *time.Time
+}
+
+// AssignmentPreview holds the reviewers selected by PreviewAssignment.
+type AssignmentPreview struct {
+	TeamID      int
+	ReviewerIDs []string
+	Strategy    domain.AssignmentStrategy
+	// Missing is the number of reviewers the team could not provide; CreatePR would queue the pull request for them.
+	Missing int
+}
+
+// MergeOptions holds the optional parameters of a merge.
+type MergeOptions struct {
+	// OverrideFreeze merges the pull request even during a freeze window; the override is logged.
+	OverrideFreeze bool
 }
 
 type PullRequestServiceImpl struct {
 	BaseService
-	prCmd   repository.PRCommandRepository
-	prQuery repository.PRQueryRepository
-	userPR  repository.UserPRRepository
+	prCmd          repository.PRCommandRepository
+	prQuery        repository.PRQueryRepository
+	userPR         repository.UserPRRepository
+	history        repository.AssignmentHistoryRepository
+	pending        repository.PendingAssignmentRepository
+	createRequests repository.CreatePRRequestRepository
+	createLease    time.Duration
+	customFields   repository.CustomFieldRepository
+	subscriptions  repository.SubscriptionRepository
+	freezes        repository.FreezeWindowRepository
+	statsView      repository.StatsViewRepository
+	webhooks       repository.WebhookRepository
+	outbox         repository.OutboxRepository
+	selector       *reviewerSelector
+	notifier       Notifier
+	// ids generates the IDs of the pull requests created without one.
+	ids            IDGenerator
+	returnExisting bool
+	// requireApprovals makes MergePR wait for the approval of every reviewer.
+	requireApprovals bool
+	// strict makes CreatePR check the author and the size of their team before creating anything.
+	strict        bool
+	invariantRate float64
+	// workingHours is the working time the deferred assignments are moved into; nil leaves them as requested.
+	workingHours *domain.WorkingHours
+}
+
+// PullRequestServiceOption configures optional behaviour of PullRequestServiceImpl.
+type PullRequestServiceOption func(*PullRequestServiceImpl)
+
+// WithReturnExistingOnDuplicate makes CreatePR answer a repeated request for an existing PR
+// of the same author with the current PR state instead of apperrors.ErrAlreadyExists.
+func WithReturnExistingOnDuplicate() PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.returnExisting = true
+	}
+}
+
+// WithRequiredApprovals makes MergePR reject an open pull request until every assigned reviewer has approved it.
+func WithRequiredApprovals() PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.requireApprovals = true
+	}
+}
+
+// WithStrictChecks makes CreatePR reject a pull request of an inactive author with apperrors.AuthorInactiveError,
+// and one that would get fewer reviewers than required with apperrors.TeamTooSmallError, instead of creating
+// it and leaving it waiting for reviewers.
+func WithStrictChecks() PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.strict = true
+	}
+}
+
+// WithNotifier makes the service report assignments, reassignments, merges and closes to n.
+func WithNotifier(n Notifier) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.notifier = n
+	}
+}
+
+// WithPendingAssignments makes CreatePR queue pull requests that got fewer reviewers than needed,
+// so that FillPendingAssignments can complete them later. Without it such PRs are only flagged.
+func WithPendingAssignments(repo repository.PendingAssignmentRepository) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.pending = repo
+	}
+}
+
+// WithWorkingHours makes CreatePR move the deferred assignments of reviewers into the working hours.
+func WithWorkingHours(hours domain.WorkingHours) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.workingHours = &hours
+	}
+}
+
+// WithAsyncCreate enables EnqueueCreatePR. A claimed creation that is still processing after lease
+// is considered abandoned and is claimed again.
+func WithAsyncCreate(repo repository.CreatePRRequestRepository, lease time.Duration) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.createRequests = repo
+		s.createLease = lease
+	}
+}
+
+// WithCustomFields makes CreatePR check custom field values against the fields of the author's team.
+// Without it no custom fields are defined, so a pull request with custom field values is rejected.
+func WithCustomFields(repo repository.CustomFieldRepository) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.customFields = repo
+	}
+}
+
+// WithSubscriptions enables SubscribePR and makes every event reported to the notifier
+// also list the users subscribed to its pull request.
+func WithSubscriptions(repo repository.SubscriptionRepository) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.subscriptions = repo
+	}
+}
+
+// WithMergeFreezes makes MergePR reject pull requests during the freeze windows stored in repo.
+func WithMergeFreezes(repo repository.FreezeWindowRepository) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.freezes = repo
+	}
+}
+
+// WithStatsView makes GetStats answer for an unbounded period from the statistics precomputed in repo,
+// which are as fresh as the last RefreshStats, instead of computing them on every call.
+func WithStatsView(repo repository.StatsViewRepository) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.statsView = repo
+	}
+}
+
+// WithReadCache makes GetReviewAssignments read through cache, which the committed writes of the service invalidate.
+// The writes of the user and team services must invalidate the same cache.
+func WithReadCache(cache *localcache.Cache) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.readCache = cache
+	}
+}
+
+// WithWebhookEvents makes CreatePR, MergePR and ReassignReviewer queue the deliveries of their events
+// to the outbound webhooks stored in repo, in the same transaction as the change they report.
+func WithWebhookEvents(repo repository.WebhookRepository) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.webhooks = repo
+	}
+}
+
+// WithOutbox makes CreatePR, MergePR and ReassignReviewer write their events to the outbox stored in repo,
+// in the same transaction as the change they report, for OutboxService to publish them to the event sinks.
+func WithOutbox(repo repository.OutboxRepository) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.outbox = repo
+	}
+}
+
+// WithDefaultStrategy makes the service pick reviewers with the named strategy for teams whose policy
+// sets no strategy weights. The name must be a built-in strategy or one added with WithAssignmentStrategy.
+func WithDefaultStrategy(name domain.AssignmentStrategy) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.selector.fallback = name
+	}
+}
+
+// WithAssignmentStrategy adds strategy to the ones the service picks reviewers with,
+// replacing the built-in strategy of the same name.
+func WithAssignmentStrategy(strategy AssignmentStrategy) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.selector.register(strategy)
+	}
+}
+
+// WithClock makes the service take the current time from c instead of the system clock.
+func WithClock(c Clock) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.clock = c
+	}
+}
+
+// WithTxMaxAttempts sets how many times a transaction aborted by a deadlock or a serialization failure
+// is run before its error is returned; 1 disables the retries.
+func WithTxMaxAttempts(n int) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.txMaxAttempts = max(n, 1)
+	}
+}
+
+// IDGenerator makes the IDs of new entities; *idgen.Generator is the one used in production.
+type IDGenerator interface {
+	NewID() (string, error)
+}
+
+// WithIDGenerator makes CreatePR take the IDs of the pull requests created without one from g
+// instead of generating prefixed UUIDv7s.
+func WithIDGenerator(g IDGenerator) PullRequestServiceOption {
+	return func(s *PullRequestServiceImpl) {
+		s.ids = g
+	}
 }
 
 // NewPullRequestService creates a new instance of PullRequestServiceImpl.
 func NewPullRequestService(
-	db Transactor,
+	db TxManager,
 	log *slog.Logger,
 	prCmd repository.PRCommandRepository,
 	prQuery repository.PRQueryRepository,
 	userPR repository.UserPRRepository,
+	policies repository.PolicyRepository,
+	history repository.AssignmentHistoryRepository,
+	opts ...PullRequestServiceOption,
 ) *PullRequestServiceImpl {
-	return &PullRequestServiceImpl{
+	s := &PullRequestServiceImpl{
 		BaseService: NewBaseService(db, log),
 		prCmd:       prCmd,
 		prQuery:     prQuery,
 		userPR:      userPR,
+		history:     history,
+		selector:    newReviewerSelector(policies, userPR),
+		notifier:    noopNotifier{},
+		ids:         idgen.New(idgen.PullRequestPrefix),
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
-func (s *PullRequestServiceImpl) CreatePR(ctx context.Context, prID string, prName string, authorID string) (*api.PullRequest, error) {
+func (s *PullRequestServiceImpl) CreatePR(ctx context.Context, prID string, prName string, authorID string, details PRDetails) (*api.PullRequest, bool, error) {
 	const op = "internal.service.pullrequest.CreatePR"
+
+	if prID == "" {
+		id, err := s.ids.NewID()
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", op, err)
+		}
+
+		prID = id
+	}
+
 	log := s.log.With(slog.String("op", op), slog.String("pr_id", prID), slog.String("author_id", authorID))
 
 	teamID, err := s.userPR.GetAuthorTeamID(ctx, authorID)
 	if err != nil {
 		if errors.Is(err, apperrors.ErrNotFound) {
-			return nil, fmt.Errorf("%w: author not found or has no team", apperrors.ErrNotFound)
+			return nil, false, fmt.Errorf("%w: author not found or has no team", apperrors.ErrNotFound)
 		}
 
-		return nil, fmt.Errorf("%s: failed to get author team id: %w", op, err)
+		return nil, false, fmt.Errorf("%s: failed to get author team id: %w", op, err)
+	}
+
+	if s.strict {
+		active, err := s.userPR.IsUserActive(ctx, authorID)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: failed to check author: %w", op, err)
+		}
+
+		if !active {
+			return nil, false, &apperrors.AuthorInactiveError{AuthorID: authorID}
+		}
+	}
+
+	customFields, err := s.customFieldValues(ctx, teamID, details.CustomFields)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	assignAt, err := s.assignmentTime(details.AssignAt)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
 	}
 
-	reviewerIDs, err := s.userPR.GetRandomActiveReviewers(ctx, teamID, []string{authorID}, 2)
+	policy, err := s.selector.policy(ctx, teamID)
 	if err != nil {
-		return nil, fmt.Errorf("%s: failed to get random reviewers: %w", op, err)
+		return nil, false, fmt.Errorf("%s: failed to get team policy: %w", op, err)
 	}
 
-	log.Info("found reviewers", slog.Any("reviewers", reviewerIDs))
+	var reviewerIDs []string
+
+	chosen := s.selector.choose(policy)
+	strategy := chosen.Name()
+
+	if assignAt != nil {
+		// The reviewers are selected by the filler once the PR is due, from the team as it is then.
+		log.Info("reviewer assignment deferred", slog.Time("assign_at", *assignAt))
+	}
+
+	assignmentPolicy, err := json.Marshal(s.selector.snapshot(policy, strategy, reviewersPerPR))
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: failed to encode assignment policy: %w", op, err)
+	}
 
 	pr := &domain.PullRequest{
-		ID:                prID,
-		Name:              prName,
-		AuthorID:          authorID,
-		Status:            api.PullRequestStatusOPEN,
-		NeedMoreReviewers: len(reviewerIDs) < 2,
-		CreatedAt:         time.Now().UTC(),
+		ID:               prID,
+		Name:             prName,
+		AuthorID:         authorID,
+		Description:      details.Description,
+		ExternalURL:      details.ExternalURL,
+		CustomFields:     customFields,
+		AssignmentPolicy: assignmentPolicy,
+		AssignAt:         assignAt,
+		Status:           api.PullRequestStatusOPEN,
+		CreatedAt:        s.now(),
 	}
 
-	err = s.transaction(ctx, op, func(tx *sqlx.Tx) error {
-		if err := s.prCmd.CreatePR(ctx, tx, pr); err != nil {
+	err = s.transaction(ctx, op, func(ctx context.Context) error {
+		overQuota, err := s.authorOverQuota(ctx, policy, authorID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		reject := overQuota && policy.OverQuotaAction != domain.QuotaQueue
+		if overQuota && !reject {
+			log.Info("author reached the open PR quota, creating PR without reviewers", slog.Int("limit", *policy.AuthorOpenPRLimit))
+
+			reviewerIDs = nil
+		}
+
+		// The strategies read the candidates without locking them, so the chosen reviewers are locked
+		// before they are assigned, and those deactivated in the meantime are replaced.
+		if !overQuota && assignAt == nil {
+			reviewerIDs, err = s.selectActiveReviewers(ctx, prID, chosen, teamID, []string{authorID}, reviewersPerPR)
+			if err != nil {
+				return fmt.Errorf("%s: failed to select reviewers: %w", op, err)
+			}
+
+			log.Info("found reviewers", slog.Any("reviewers", reviewerIDs), slog.String("strategy", string(strategy)))
+
+			if s.strict && len(reviewerIDs) < reviewersPerPR {
+				return &apperrors.TeamTooSmallError{AuthorID: authorID, Required: reviewersPerPR, Available: len(reviewerIDs)}
+			}
+		}
+
+		pr.NeedMoreReviewers = len(reviewerIDs) < reviewersPerPR
+
+		// The PR is inserted even when it is going to be rejected, so that a duplicate ID
+		// is reported as such; returning the quota error rolls the insert back.
+		if err := s.prCmd.CreatePR(ctx, pr); err != nil {
 			return err
 		}
 
+		if reject {
+			return &apperrors.AuthorQuotaExceededError{AuthorID: authorID, Limit: *policy.AuthorOpenPRLimit}
+		}
+
 		if len(reviewerIDs) > 0 {
-			if err := s.prCmd.AssignReviewers(ctx, tx, prID, reviewerIDs); err != nil {
+			if err := s.prCmd.AssignReviewers(ctx, prID, reviewerIDs); err != nil {
 				return fmt.Errorf("%s: failed to assign reviewers: %w", op, err)
 			}
+
+			if err := s.history.RecordAssignments(ctx, assignmentRecords(prID, reviewerIDs, strategy, domain.CauseCreated, pr.CreatedAt)); err != nil {
+				return fmt.Errorf("%s: failed to record assignment history: %w", op, err)
+			}
+		}
+
+		// A PR held back by the author quota waits for the author's other PRs rather than for team capacity.
+		if pr.NeedMoreReviewers && !overQuota && s.pending != nil {
+			if err := s.pending.EnqueuePending(ctx, prID, teamID, reviewersPerPR-len(reviewerIDs)); err != nil {
+				return fmt.Errorf("%s: failed to queue pr for assignment: %w", op, err)
+			}
+		}
+
+		if err := s.queueEvent(ctx, newWebhookPayload(domain.WebhookPRCreated, pr, reviewerIDs, pr.CreatedAt)); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
 		}
 
 		return nil
 	})
 
 	if err != nil {
-		return nil, err
+		if s.returnExisting && errors.Is(err, apperrors.ErrAlreadyExists) {
+			return s.existingPR(ctx, prID, authorID, err)
+		}
+
+		return nil, false, err
 	}
 
 	log.Info("pr created successfully")
 
+	prsCreatedTotal.Inc()
+	reviewersAssignedTotal.WithLabelValues(string(strategy)).Add(float64(len(reviewerIDs)))
+
 	pr.ReviewerIDs = reviewerIDs
 
-	return toAPIPullRequest(pr), nil
+	if len(reviewerIDs) > 0 {
+		s.notify(ctx, newEvent(domain.EventReviewersAssigned, pr, reviewerIDs, pr.CreatedAt))
+	}
+
+	s.checkReviewerInvariants(ctx, checkedCreate, prID)
+
+	return toAPIPullRequest(pr), true, nil
 }
 
-func (s *PullRequestServiceImpl) MergePR(ctx context.Context, prID string) (*api.PullRequest, error) {
+func (s *PullRequestServiceImpl) PreviewAssignment(ctx context.Context, authorID string) (*AssignmentPreview, error) {
+	const op = "internal.service.pullrequest.PreviewAssignment"
+
+	teamID, err := s.userPR.GetAuthorTeamID(ctx, authorID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, fmt.Errorf("%w: author not found or has no team", apperrors.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: failed to get author team id: %w", op, err)
+	}
+
+	policy, err := s.selector.policy(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get team policy: %w", op, err)
+	}
+
+	reviewerIDs, strategy, err := s.selector.selectWithPolicy(ctx, policy, []string{authorID}, reviewersPerPR)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to select reviewers: %w", op, err)
+	}
+
+	return &AssignmentPreview{
+		TeamID:      teamID,
+		ReviewerIDs: reviewerIDs,
+		Strategy:    strategy,
+		Missing:     reviewersPerPR - len(reviewerIDs),
+	}, nil
+}
+
+// authorOverQuota reports whether the author already has as many open PRs as the team policy allows.
+// Without a limit the open PRs are not counted at all.
+func (s *PullRequestServiceImpl) authorOverQuota(ctx context.Context, policy *domain.TeamPolicy, authorID string) (bool, error) {
+	if policy.AuthorOpenPRLimit == nil {
+		return false, nil
+	}
+
+	count, err := s.prCmd.CountOpenPRsByAuthor(ctx, authorID)
+	if err != nil {
+		return false, fmt.Errorf("failed to count open PRs of the author: %w", err)
+	}
+
+	return count >= *policy.AuthorOpenPRLimit, nil
+}
+
+// existingPR reads back a PR that a duplicate create request collided with.
+// A PR of another author is not the same request, so the original conflict error is kept.
+func (s *PullRequestServiceImpl) existingPR(ctx context.Context, prID, authorID string, conflictErr error) (*api.PullRequest, bool, error) {
+	const op = "internal.service.pullrequest.existingPR"
+
+	existing, err := s.prQuery.GetPRByIDWithReviewers(ctx, prID)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: failed to get existing pr: %w", op, err)
+	}
+
+	if existing.AuthorID != authorID {
+		return nil, false, conflictErr
+	}
+
+	s.log.Info("pr already exists, returning current state", slog.String("op", op), slog.String("pr_id", prID))
+
+	return toAPIPullRequest(existing), false, nil
+}
+
+func (s *PullRequestServiceImpl) MergePR(ctx context.Context, prID string, opts MergeOptions) (*api.MergeResponse, error) {
 	const op = "internal.service.pullrequest.MergePR"
 	log := s.log.With(slog.String("op", op), slog.String("pr_id", prID))
 
 	var (
-		pr          *domain.PullRequest
-		reviewerIDs []string
+		pr            *domain.PullRequest
+		reviewerIDs   []string
+		reviewerStats []domain.Stats
 	)
 
-	mergedAt := time.Now().UTC()
+	mergedAt := s.now()
 
-	err := s.transaction(ctx, op, func(tx *sqlx.Tx) error {
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
 		var err error
 
-		pr, err = s.prCmd.GetPRByIDWithLock(ctx, tx, prID)
+		pr, err = s.prCmd.GetPRByIDWithLock(ctx, prID)
 		if err != nil {
 			return fmt.Errorf("%s: failed to get pr with lock: %w", op, err)
 		}
 
+		if pr.Status == api.PullRequestStatusCLOSED {
+			return apperrors.ErrPRClosed
+		}
+
 		if pr.Status != api.PullRequestStatusMERGED {
-			if err := s.prCmd.UpdatePRStatus(ctx, tx, prID, api.PullRequestStatusMERGED, mergedAt); err != nil {
+			if err := s.checkFreeze(ctx, pr, mergedAt, opts.OverrideFreeze); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			if s.requireApprovals {
+				if err := s.checkApproved(ctx, prID); err != nil {
+					return fmt.Errorf("%s: %w", op, err)
+				}
+			}
+
+			storedMergedAt, err := s.prCmd.UpdatePRStatus(ctx, prID, api.PullRequestStatusMERGED, mergedAt)
+			if err != nil {
 				return fmt.Errorf("%s: failed to update PR status: %w", op, err)
 			}
+
+			// The response reports merged_at as stored rather than the clock reading.
+			if storedMergedAt != nil {
+				mergedAt = *storedMergedAt
+			}
+
+			if s.pending != nil {
+				if err := s.pending.DequeuePending(ctx, prID); err != nil {
+					return fmt.Errorf("%s: failed to remove PR from the assignment queue: %w", op, err)
+				}
+			}
 		}
 
-		reviewerIDs, err = s.prQuery.GetReviewerIDs(ctx, tx, prID)
+		reviewerIDs, err = s.prQuery.GetReviewerIDs(ctx, prID)
 		if err != nil {
 			return fmt.Errorf("%s: failed to get reviewers: %w", op, err)
 		}
 
+		reviewerStats, err = s.prQuery.GetStatsByUserIDs(ctx, reviewerIDs)
+		if err != nil {
+			return fmt.Errorf("%s: failed to get reviewer stats: %w", op, err)
+		}
+
+		if pr.Status != api.PullRequestStatusMERGED {
+			merged := *pr
+			merged.Status = api.PullRequestStatusMERGED
+			merged.MergedAt = &mergedAt
+
+			if err := s.queueEvent(ctx, newWebhookPayload(domain.WebhookPRMerged, &merged, reviewerIDs, mergedAt)); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
 		return nil
 	})
 
@@ -150,8 +689,81 @@ func (s *PullRequestServiceImpl) MergePR(ctx context.Context, prID string) (*api
 	} else {
 		log.Info("PR merged successfully")
 
+		prsMergedTotal.Inc()
+
 		pr.Status = api.PullRequestStatusMERGED
 		pr.MergedAt = &mergedAt
+
+		s.notify(ctx, newEvent(domain.EventPRMerged, pr, reviewerIDs, mergedAt))
+
+		s.checkReviewerInvariants(ctx, checkedMerge, prID)
+	}
+
+	pr.ReviewerIDs = reviewerIDs
+
+	return &api.MergeResponse{
+		Pr:            *toAPIPullRequest(pr),
+		ReviewerStats: toAPIUserStats(reviewerStats),
+	}, nil
+}
+
+func (s *PullRequestServiceImpl) ClosePR(ctx context.Context, prID string) (*api.PullRequest, error) {
+	const op = "internal.service.pullrequest.ClosePR"
+	log := s.log.With(slog.String("op", op), slog.String("pr_id", prID))
+
+	var (
+		pr          *domain.PullRequest
+		reviewerIDs []string
+	)
+
+	closedAt := s.now()
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		var err error
+
+		pr, err = s.prCmd.GetPRByIDWithLock(ctx, prID)
+		if err != nil {
+			return fmt.Errorf("%s: failed to get pr with lock: %w", op, err)
+		}
+
+		if pr.Status == api.PullRequestStatusMERGED {
+			return apperrors.ErrPRMerged
+		}
+
+		if pr.Status == api.PullRequestStatusOPEN {
+			if _, err := s.prCmd.UpdatePRStatus(ctx, prID, api.PullRequestStatusCLOSED, closedAt); err != nil {
+				return fmt.Errorf("%s: failed to update PR status: %w", op, err)
+			}
+
+			if s.pending != nil {
+				if err := s.pending.DequeuePending(ctx, prID); err != nil {
+					return fmt.Errorf("%s: failed to remove PR from the assignment queue: %w", op, err)
+				}
+			}
+		}
+
+		reviewerIDs, err = s.prQuery.GetReviewerIDs(ctx, prID)
+		if err != nil {
+			return fmt.Errorf("%s: failed to get reviewers: %w", op, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.Status == api.PullRequestStatusCLOSED {
+		log.Info("PR already closed, returning current state")
+	} else {
+		log.Info("PR closed successfully")
+
+		prsClosedTotal.Inc()
+
+		pr.Status = api.PullRequestStatusCLOSED
+
+		s.notify(ctx, newEvent(domain.EventPRClosed, pr, reviewerIDs, closedAt))
 	}
 
 	pr.ReviewerIDs = reviewerIDs
@@ -159,33 +771,56 @@ func (s *PullRequestServiceImpl) MergePR(ctx context.Context, prID string) (*api
 	return toAPIPullRequest(pr), nil
 }
 
-func (s *PullRequestServiceImpl) ReassignReviewer(ctx context.Context, prID string, oldReviewerID string) (*api.ReassignResponse, error) {
+func (s *PullRequestServiceImpl) ReassignReviewer(ctx context.Context, prID string, oldReviewerID string, note string) (*api.ReassignResponse, error) {
 	const op = "internal.service.pullrequest.ReassignReviewer"
 	log := s.log.With(slog.String("op", op), slog.String("pr_id", prID), slog.String("old_reviewer_id", oldReviewerID))
 
 	var (
 		pr                 *domain.PullRequest
 		newReviewerID      string
+		strategy           domain.AssignmentStrategy
 		updatedReviewerIDs []string
 	)
 
-	err := s.transaction(ctx, op, func(tx *sqlx.Tx) error {
+	reassignedAt := s.now()
+
+	var handoffNote *string
+	if note = strings.TrimSpace(note); note != "" {
+		handoffNote = &note
+	}
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
 		var err error
 
-		newReviewerID, pr, err = s.validateAndFindReplacement(ctx, tx, prID, oldReviewerID)
+		newReviewerID, strategy, pr, err = s.validateAndFindReplacement(ctx, prID, oldReviewerID)
 		if err != nil {
 			return err
 		}
 
-		if err := s.prCmd.ReplaceReviewer(ctx, tx, prID, oldReviewerID, newReviewerID); err != nil {
+		if err := s.prCmd.ReplaceReviewer(ctx, prID, oldReviewerID, newReviewerID); err != nil {
 			return fmt.Errorf("%s: failed to replace reviewer: %w", op, err)
 		}
 
-		updatedReviewerIDs, err = s.prQuery.GetReviewerIDs(ctx, tx, prID)
+		record := replacementRecord(prID, oldReviewerID, newReviewerID, strategy, domain.CauseReassign, reassignedAt)
+		record.HandoffNote = handoffNote
+
+		if err := s.history.RecordAssignments(ctx, []domain.AssignmentRecord{record}); err != nil {
+			return fmt.Errorf("%s: failed to record assignment history: %w", op, err)
+		}
+
+		updatedReviewerIDs, err = s.prQuery.GetReviewerIDs(ctx, prID)
 		if err != nil {
 			return fmt.Errorf("%s: failed to get updated reviewers: %w", op, err)
 		}
 
+		payload := newWebhookPayload(domain.WebhookReviewerReassigned, pr, updatedReviewerIDs, reassignedAt)
+		payload.OldReviewerId = &oldReviewerID
+		payload.NewReviewerId = &newReviewerID
+
+		if err := s.queueEvent(ctx, payload); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
 		return nil
 	})
 
@@ -195,74 +830,382 @@ func (s *PullRequestServiceImpl) ReassignReviewer(ctx context.Context, prID stri
 
 	log.Info("reviewer reassigned successfully", slog.String("new_reviewer_id", newReviewerID))
 
+	reviewerReassignmentsTotal.WithLabelValues(string(strategy)).Inc()
+
 	pr.ReviewerIDs = updatedReviewerIDs
 
+	event := newEvent(domain.EventReviewerReassigned, pr, []string{newReviewerID}, reassignedAt)
+	event.HandoffNote = handoffNote
+
+	s.notify(ctx, event)
+
+	s.checkReviewerInvariants(ctx, checkedReassign, prID)
+
 	return &api.ReassignResponse{
 		Pr:         *toAPIPullRequest(pr),
 		ReplacedBy: newReviewerID,
 	}, nil
 }
 
-func (s *PullRequestServiceImpl) GetReviewAssignments(ctx context.Context, userID string) (*api.GetReviewResponse, error) {
+func (s *PullRequestServiceImpl) GetReviewAssignments(ctx context.Context, userID string, customFields []string) (*api.GetReviewResponse, error) {
 	const op = "internal.service.pullrequest.GetReviewAssignments"
 
-	prs, err := s.prQuery.GetReviewAssignments(ctx, userID)
+	filters, err := parseCustomFieldFilters(customFields)
 	if err != nil {
-		return nil, fmt.Errorf("%s: failed to get review assignments: %w", op, err)
+		return nil, err
 	}
 
-	apiPRs := make([]api.PullRequestShort, len(prs))
-	for i, pr := range prs {
-		apiPRs[i] = api.PullRequestShort{
-			PullRequestId:   pr.ID,
-			PullRequestName: pr.Name,
-			AuthorId:        pr.AuthorID,
-			Status:          api.PullRequestShortStatus(pr.Status),
-		}
+	key := userID + "\x00" + strings.Join(customFields, "\x00")
+
+	prs, err := localcache.Load(ctx, s.readCache, "review_assignments", key, func() ([]domain.PullRequest, error) {
+		return s.prQuery.GetReviewAssignments(ctx, userID, filters)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get review assignments: %w", op, err)
 	}
 
+	items := toAPIPullRequestsShort(prs)
+
 	return &api.GetReviewResponse{
-		UserId:       userID,
-		PullRequests: apiPRs,
+		UserId:        userID,
+		Items:         items,
+		PullRequests:  items,
+		TotalEstimate: totalOf(items),
 	}, nil
 }
 
-func (s *PullRequestServiceImpl) GetStats(ctx context.Context) (*api.StatsResponse, error) {
+// maxSearchLimit caps the page size of SearchPRs.
+const maxSearchLimit = 100
+
+// PRSearchQuery selects the pull requests returned by SearchPRs.
+type PRSearchQuery struct {
+	Query  string
+	Status string
+	// CustomFields are key:value filters on the custom fields of the pull requests.
+	CustomFields []string
+	// Cursor is the next_cursor of the previous page. It cannot be combined with a non-zero Offset.
+	Cursor string
+	Limit  int
+	Offset int
+}
+
+func (s *PullRequestServiceImpl) SearchPRs(ctx context.Context, query PRSearchQuery) (*api.SearchPullRequestsResponse, error) {
+	const op = "internal.service.pullrequest.SearchPRs"
+
+	text := strings.TrimSpace(query.Query)
+	if text == "" {
+		return nil, fmt.Errorf("%w: search query must not be blank", apperrors.ErrValidation)
+	}
+
+	switch api.PullRequestStatus(query.Status) {
+	case "", api.PullRequestStatusOPEN, api.PullRequestStatusMERGED, api.PullRequestStatusCLOSED:
+	default:
+		return nil, fmt.Errorf("%w: unknown status '%s'", apperrors.ErrValidation, query.Status)
+	}
+
+	if query.Limit < 1 || query.Limit > maxSearchLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", apperrors.ErrValidation, maxSearchLimit)
+	}
+
+	if query.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", apperrors.ErrValidation)
+	}
+
+	offset := query.Offset
+
+	if query.Cursor != "" {
+		if query.Offset != 0 {
+			return nil, fmt.Errorf("%w: cursor and offset are mutually exclusive", apperrors.ErrValidation)
+		}
+
+		// The results are ordered by a relevance computed per query, which is no key to seek by,
+		// so the cursor of a search holds the position in the results.
+		position, err := cursor.DecodeInt(query.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+		}
+
+		offset = int(position)
+	}
+
+	filters, err := parseCustomFieldFilters(query.CustomFields)
+	if err != nil {
+		return nil, err
+	}
+
+	prs, total, err := s.prQuery.SearchPRs(ctx, domain.PRSearchFilter{
+		Query:        text,
+		Status:       api.PullRequestStatus(query.Status),
+		CustomFields: filters,
+		Limit:        query.Limit,
+		Offset:       offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to search prs: %w", op, err)
+	}
+
+	items := toAPIPullRequestsShort(prs)
+	resp := &api.SearchPullRequestsResponse{Items: items, PullRequests: items, Total: total, TotalEstimate: &total}
+
+	if next := offset + len(prs); len(prs) > 0 && next < total {
+		c := cursor.EncodeInt(int64(next))
+		resp.NextCursor = &c
+	}
+
+	return resp, nil
+}
+
+func (s *PullRequestServiceImpl) GetStats(ctx context.Context, period domain.StatsPeriod) (*api.StatsResponse, error) {
 	const op = "internal.service.pullrequest.GetStats"
 
-	stats, err := s.prQuery.GetUserStats(ctx)
+	if period.From != nil && period.To != nil && !period.From.Before(*period.To) {
+		return nil, fmt.Errorf("%w: from must be before to", apperrors.ErrValidation)
+	}
+
+	var (
+		stats       []domain.Stats
+		teamStats   []domain.TeamStats
+		refreshedAt *time.Time
+	)
+
+	// Every read of the report goes through the snapshot, so that the figures agree with each other.
+	err := s.readSnapshot(ctx, op, func(ctx context.Context) error {
+		if s.statsView != nil && period.From == nil && period.To == nil {
+			view, err := s.statsView.GetStatsView(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get precomputed stats: %w", err)
+			}
+
+			stats, teamStats, refreshedAt = view.Users, view.Teams, &view.RefreshedAt
+
+			return nil
+		}
+
+		var err error
+
+		stats, err = s.prQuery.GetUserStats(ctx, period)
+		if err != nil {
+			return fmt.Errorf("failed to get user stats: %w", err)
+		}
+
+		teamStats, err = s.prQuery.GetTeamStats(ctx, period)
+		if err != nil {
+			return fmt.Errorf("failed to get team stats: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("%s: failed to get user stats: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	userStats := make([]api.UserStats, len(stats))
-	for i, stat := range stats {
-		userStats[i] = api.UserStats{
-			UserId:        stat.UserID,
-			Username:      stat.Username,
-			OpenReviews:   stat.OpenReviews,
-			MergedReviews: stat.MergedReviews,
+	items := toAPIUserStats(stats)
+
+	teams := make([]api.TeamStats, len(teamStats))
+	for i, team := range teamStats {
+		teams[i] = api.TeamStats{
+			TeamName:          team.TeamName,
+			TimeToFirstReview: toAPIDurationStats(team.FirstReviewedPRs, team.FirstReviewAvgSeconds, team.FirstReviewP50Seconds, team.FirstReviewP90Seconds),
+			TimeToMerge:       toAPIDurationStats(team.MergedPRs, team.MergeAvgSeconds, team.MergeP50Seconds, team.MergeP90Seconds),
+		}
+	}
+
+	return &api.StatsResponse{Items: items, UserStats: items, TeamStats: teams, RefreshedAt: refreshedAt, TotalEstimate: totalOf(items)}, nil
+}
+
+func (s *PullRequestServiceImpl) RefreshStats(ctx context.Context) (bool, error) {
+	const op = "internal.service.pullrequest.RefreshStats"
+
+	if s.statsView == nil {
+		return false, nil
+	}
+
+	refreshed, err := s.statsView.RefreshStatsView(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to refresh stats: %w", op, err)
+	}
+
+	return refreshed, nil
+}
+
+// maxStatsBackfillBatchSize bounds the number of pull requests whose reviewers are backfilled in a single transaction.
+const maxStatsBackfillBatchSize = 1000
+
+func (s *PullRequestServiceImpl) BackfillReviewerDurations(ctx context.Context, afterID string, limit int) (string, int, error) {
+	const op = "internal.service.pullrequest.BackfillReviewerDurations"
+
+	if limit < 1 || limit > maxStatsBackfillBatchSize {
+		return "", 0, fmt.Errorf("%w: limit must be between 1 and %d", apperrors.ErrValidation, maxStatsBackfillBatchSize)
+	}
+
+	var (
+		lastID  string
+		updated int
+	)
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		var err error
+
+		lastID, updated, err = s.prCmd.BackfillReviewerDurations(ctx, afterID, limit)
+
+		return err
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: failed to backfill reviewer durations: %w", op, err)
+	}
+
+	return lastID, updated, nil
+}
+
+func (s *PullRequestServiceImpl) GetLeaderboard(ctx context.Context, period domain.LeaderboardPeriod, limit int) (*api.LeaderboardResponse, error) {
+	const op = "internal.service.pullrequest.GetLeaderboard"
+
+	if !period.IsValid() {
+		return nil, fmt.Errorf("%w: unknown period '%s'", apperrors.ErrValidation, period)
+	}
+
+	if limit < 1 || limit > maxListLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", apperrors.ErrValidation, maxListLimit)
+	}
+
+	bucket := period.Bucket(s.now())
+
+	entries, err := s.prQuery.GetLeaderboard(ctx, bucket, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get leaderboard: %w", op, err)
+	}
+
+	items := make([]api.LeaderboardEntry, len(entries))
+	for i, entry := range entries {
+		// Users with as many reviews as the one above share their rank.
+		rank := i + 1
+		if i > 0 && entry.MergedReviews == entries[i-1].MergedReviews {
+			rank = items[i-1].Rank
+		}
+
+		items[i] = api.LeaderboardEntry{
+			Rank:          rank,
+			UserId:        entry.UserID,
+			Username:      entry.Username,
+			MergedReviews: entry.MergedReviews,
+		}
+	}
+
+	return &api.LeaderboardResponse{
+		Period:        api.LeaderboardPeriod(period),
+		From:          *bucket.From,
+		To:            *bucket.To,
+		Items:         items,
+		TotalEstimate: totalOf(items),
+	}, nil
+}
+
+func (s *PullRequestServiceImpl) GetOpenPRAgeStats(ctx context.Context) ([]domain.OpenPRAgeStats, error) {
+	const op = "internal.service.pullrequest.GetOpenPRAgeStats"
+
+	stats, err := s.prQuery.GetOpenPRAgeStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get open pr age stats: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+func (s *PullRequestServiceImpl) GetPR(ctx context.Context, prID string) (*api.PullRequest, error) {
+	const op = "internal.service.pullrequest.GetPR"
+
+	pr, err := s.prQuery.GetPRByIDWithReviewers(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get pr: %w", op, err)
+	}
+
+	apiPR := toAPIPullRequest(pr)
+	apiPR.AssignmentPolicy = toAPIAssignmentPolicy(pr.AssignmentPolicy)
+
+	return apiPR, nil
+}
+
+func (s *PullRequestServiceImpl) ExpandReviewers(ctx context.Context, pr *api.PullRequest) error {
+	const op = "internal.service.pullrequest.ExpandReviewers"
+
+	records, err := s.history.GetCurrentAssignments(ctx, pr.PullRequestId)
+	if err != nil {
+		return fmt.Errorf("%s: failed to get current assignments: %w", op, err)
+	}
+
+	byUser := make(map[string]domain.AssignmentRecord, len(records))
+	for _, record := range records {
+		byUser[record.UserID] = record
+	}
+
+	reviewers := make([]api.ReviewerAssignment, len(pr.AssignedReviewers))
+	for i, id := range pr.AssignedReviewers {
+		reviewers[i] = api.ReviewerAssignment{UserId: id}
+
+		if record, ok := byUser[id]; ok {
+			reason := api.ReviewerAssignmentReason(record.Reason)
+			assignedAt := record.CreatedAt
+
+			reviewers[i].Reason = &reason
+			reviewers[i].AssignedAt = &assignedAt
+			reviewers[i].HandoffNote = record.HandoffNote
 		}
 	}
 
-	return &api.StatsResponse{UserStats: userStats}, nil
+	pr.Reviewers = &reviewers
+
+	return nil
 }
 
-func (s *PullRequestServiceImpl) validateAndFindReplacement(ctx context.Context, tx *sqlx.Tx, prID, oldReviewerID string) (string, *domain.PullRequest, error) {
+func (s *PullRequestServiceImpl) GetAssignmentHistory(ctx context.Context, prID string) (*api.AssignmentHistoryResponse, error) {
+	const op = "internal.service.pullrequest.GetAssignmentHistory"
+
+	if _, err := s.prQuery.GetPRByID(ctx, prID); err != nil {
+		return nil, fmt.Errorf("%s: failed to get pr: %w", op, err)
+	}
+
+	records, err := s.history.ListAssignmentHistory(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list assignment history: %w", op, err)
+	}
+
+	items := make([]api.AssignmentHistoryEntry, len(records))
+	for i, record := range records {
+		items[i] = api.AssignmentHistoryEntry{
+			UserId:         record.UserID,
+			ReplacedUserId: record.ReplacedUserID,
+			Reason:         api.AssignmentHistoryEntryReason(record.Reason),
+			HandoffNote:    record.HandoffNote,
+			AssignedAt:     record.CreatedAt,
+		}
+
+		if record.Cause != "" {
+			cause := api.AssignmentHistoryEntryCause(record.Cause)
+			items[i].Cause = &cause
+		}
+	}
+
+	return &api.AssignmentHistoryResponse{PullRequestId: prID, Items: items, TotalEstimate: totalOf(items)}, nil
+}
+
+func (s *PullRequestServiceImpl) validateAndFindReplacement(ctx context.Context, prID, oldReviewerID string) (string, domain.AssignmentStrategy, *domain.PullRequest, error) {
 	const op = "internal.service.pullrequest.validateAndFindReplacement"
 
-	pr, err := s.prCmd.GetPRByIDWithLock(ctx, tx, prID)
+	pr, err := s.prCmd.GetPRByIDWithLock(ctx, prID)
 	if err != nil {
-		return "", nil, fmt.Errorf("%s: failed to get pr with lock: %w", op, err)
+		return "", "", nil, fmt.Errorf("%s: failed to get pr with lock: %w", op, err)
 	}
 
-	if pr.Status == api.PullRequestStatusMERGED {
-		return "", nil, apperrors.ErrPRMerged
+	switch pr.Status {
+	case api.PullRequestStatusMERGED:
+		return "", "", nil, apperrors.ErrPRMerged
+	case api.PullRequestStatusCLOSED:
+		return "", "", nil, apperrors.ErrPRClosed
 	}
 
-	currentReviewerIDs, err := s.prQuery.GetReviewerIDs(ctx, tx, prID)
+	currentReviewerIDs, err := s.prQuery.GetReviewerIDs(ctx, prID)
 	if err != nil {
-		return "", nil, fmt.Errorf("%s: failed to get current reviewers: %w", op, err)
+		return "", "", nil, fmt.Errorf("%s: failed to get current reviewers: %w", op, err)
 	}
 
 	var isAssigned bool
@@ -275,26 +1218,132 @@ func (s *PullRequestServiceImpl) validateAndFindReplacement(ctx context.Context,
 	}
 
 	if !isAssigned {
-		return "", nil, apperrors.ErrReviewerNotAssigned
+		return "", "", nil, apperrors.ErrReviewerNotAssigned
 	}
 
-	teamID, err := s.userPR.GetReviewerTeamID(ctx, oldReviewerID)
+	// The replacement comes from the team the reviewers of the pull request were picked from,
+	// which need not be the team of the reviewer, who may be a member of several.
+	teamID, err := s.userPR.GetPRTeamID(ctx, prID)
 	if err != nil {
-		return "", nil, fmt.Errorf("%s: failed to get reviewer team: %w", op, err)
+		return "", "", nil, fmt.Errorf("%s: failed to get pr team: %w", op, err)
 	}
 
 	excludedIDs := excludeIDs(pr, currentReviewerIDs)
 
-	newReviewerCandidates, err := s.userPR.GetRandomActiveReviewers(ctx, teamID, excludedIDs, 1)
+	// A candidate deactivated since it was selected is excluded and another one is selected,
+	// which ends as every selection excludes one more user.
+	for {
+		newReviewerCandidates, strategy, err := s.selector.selectReviewers(ctx, teamID, excludedIDs, 1)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("%s: failed to select reviewers: %w", op, err)
+		}
+
+		if len(newReviewerCandidates) == 0 {
+			return "", "", nil, s.noCandidateError(ctx, teamID, pr, excludedIDs)
+		}
+
+		activeIDs, err := keepActiveReviewers(ctx, s.prCmd, s.log, prID, newReviewerCandidates)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if len(activeIDs) > 0 {
+			return activeIDs[0], strategy, pr, nil
+		}
+
+		excludedIDs = append(excludedIDs, newReviewerCandidates...)
+	}
+}
+
+// selectActiveReviewers selects up to count reviewers from the team with the strategy and locks them until
+// the transaction of ctx ends. A candidate deactivated since the strategy read the team is excluded and another
+// one is selected in its place, which ends as every selection excludes more users.
+func (s *PullRequestServiceImpl) selectActiveReviewers(
+	ctx context.Context,
+	prID string,
+	strategy AssignmentStrategy,
+	teamID int,
+	excludedIDs []string,
+	count int,
+) ([]string, error) {
+	excludedIDs = slices.Clone(excludedIDs)
+
+	var selected []string
+
+	for len(selected) < count {
+		candidateIDs, err := strategy.Select(ctx, teamID, excludedIDs, count-len(selected))
+		if err != nil {
+			return nil, fmt.Errorf("strategy %s failed: %w", strategy.Name(), err)
+		}
+
+		activeIDs, err := keepActiveReviewers(ctx, s.prCmd, s.log, prID, candidateIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		selected = append(selected, activeIDs...)
+
+		// With none of the candidates lost, the strategy has nobody else to offer.
+		if len(activeIDs) == len(candidateIDs) {
+			break
+		}
+
+		excludedIDs = append(excludedIDs, candidateIDs...)
+	}
+
+	return selected, nil
+}
+
+// keepActiveReviewers returns the candidates that are still active, in the order they were selected,
+// and locks them until the transaction of ctx ends. The strategies read the candidates without locking them,
+// so a candidate may have been deactivated since, after its reviews were reassigned; with the lock, a concurrent
+// deactivation either ends first and the candidate is left out, or waits for the transaction and then reassigns
+// the new review too.
+func keepActiveReviewers(
+	ctx context.Context,
+	prCmd repository.PRCommandRepository,
+	log *slog.Logger,
+	prID string,
+	candidateIDs []string,
+) ([]string, error) {
+	if len(candidateIDs) == 0 {
+		return candidateIDs, nil
+	}
+
+	activeIDs, err := prCmd.LockActiveUsers(ctx, candidateIDs)
 	if err != nil {
-		return "", nil, fmt.Errorf("%s: failed to get random reviewers: %w", op, err)
+		return nil, fmt.Errorf("failed to lock reviewers: %w", err)
+	}
+
+	kept := make([]string, 0, len(candidateIDs))
+	for _, id := range candidateIDs {
+		if slices.Contains(activeIDs, id) {
+			kept = append(kept, id)
+		}
+	}
+
+	if len(kept) < len(candidateIDs) {
+		log.Warn("skipped reviewers deactivated since their selection", slog.String("pr_id", prID),
+			slog.Any("candidates", candidateIDs), slog.Any("active", kept))
 	}
 
-	if len(newReviewerCandidates) == 0 {
-		return "", nil, apperrors.ErrNoCandidate
+	return kept, nil
+}
+
+// maxReplacementAlternatives caps the number of alternatives of each kind reported with a NO_CANDIDATE error.
+const maxReplacementAlternatives = 5
+
+// noCandidateError describes a failed replacement together with the users an administrator could assign instead.
+// The alternatives are advisory, so failing to look them up does not change the outcome of the request.
+func (s *PullRequestServiceImpl) noCandidateError(ctx context.Context, teamID int, pr *domain.PullRequest, excludedIDs []string) error {
+	const op = "internal.service.pullrequest.noCandidateError"
+
+	alternatives, err := s.userPR.GetReplacementAlternatives(ctx, teamID, excludedIDs, maxReplacementAlternatives)
+	if err != nil {
+		s.log.Warn("failed to get replacement alternatives", slog.String("op", op), slog.String("pr_id", pr.ID), sl.Err(err))
 	}
 
-	return newReviewerCandidates[0], pr, nil
+	return &apperrors.NoCandidateError{PRID: pr.ID, Alternatives: alternatives}
 }
 
 func excludeIDs(pr *domain.PullRequest, currentReviewerIDs []string) []string {
@@ -314,13 +1363,104 @@ func excludeIDs(pr *domain.PullRequest, currentReviewerIDs []string) []string {
 }
 
 func toAPIPullRequest(pr *domain.PullRequest) *api.PullRequest {
-	return &api.PullRequest{
+	apiPR := &api.PullRequest{
 		PullRequestId:     pr.ID,
 		PullRequestName:   pr.Name,
 		AuthorId:          pr.AuthorID,
+		Description:       pr.Description,
+		ExternalUrl:       pr.ExternalURL,
+		CustomFields:      toAPICustomFields(pr.CustomFields),
 		Status:            pr.Status,
 		AssignedReviewers: pr.ReviewerIDs,
 		CreatedAt:         &pr.CreatedAt,
 		MergedAt:          pr.MergedAt,
+		AssignAt:          pr.AssignAt,
+	}
+
+	if pr.Reviews != nil {
+		apiPR.Reviews = toAPIReviews(pr.Reviews)
+	}
+
+	return apiPR
+}
+
+// toAPIAssignmentPolicy decodes the stored policy snapshot. PRs created before the snapshots were stored,
+// and snapshots that cannot be decoded, have none.
+func toAPIAssignmentPolicy(raw []byte) *api.AssignmentPolicySnapshot {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var snapshot domain.AssignmentPolicySnapshot
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return nil
+	}
+
+	weights := make(map[string]int, len(snapshot.StrategyWeights))
+	for name, weight := range snapshot.StrategyWeights {
+		weights[string(name)] = weight
+	}
+
+	return &api.AssignmentPolicySnapshot{
+		TeamId:            snapshot.TeamID,
+		Strategy:          string(snapshot.Strategy),
+		StrategyWeights:   weights,
+		ReviewersPerPr:    snapshot.ReviewersPerPR,
+		AuthorOpenPrLimit: snapshot.AuthorOpenPRLimit,
+		OverQuotaAction:   api.TeamPolicyOverQuotaAction(snapshot.OverQuotaAction),
+		PolicyUpdatedAt:   snapshot.PolicyUpdatedAt,
+	}
+}
+
+func toAPIUserStats(stats []domain.Stats) []api.UserStats {
+	userStats := make([]api.UserStats, len(stats))
+	for i, stat := range stats {
+		userStats[i] = api.UserStats{
+			UserId:        stat.UserID,
+			Username:      stat.Username,
+			OpenReviews:   stat.OpenReviews,
+			MergedReviews: stat.MergedReviews,
+
+			TimeToFirstReview: toAPIDurationStats(stat.FirstReviews, stat.FirstReviewAvgSeconds, stat.FirstReviewP50Seconds, stat.FirstReviewP90Seconds),
+			TimeToMerge:       toAPIDurationStats(stat.MergedReviews, stat.MergeAvgSeconds, stat.MergeP50Seconds, stat.MergeP90Seconds),
+		}
+	}
+
+	return userStats
+}
+
+// toAPIDurationStats returns nil when no duration was counted.
+func toAPIDurationStats(count int, avg, p50, p90 *float64) *api.DurationStats {
+	if count == 0 || avg == nil || p50 == nil || p90 == nil {
+		return nil
+	}
+
+	return &api.DurationStats{Count: count, AvgSeconds: *avg, P50Seconds: *p50, P90Seconds: *p90}
+}
+
+func toAPIPullRequestsShort(prs []domain.PullRequest) []api.PullRequestShort {
+	apiPRs := make([]api.PullRequestShort, len(prs))
+	for i, pr := range prs {
+		apiPRs[i] = api.PullRequestShort{
+			PullRequestId:   pr.ID,
+			PullRequestName: pr.Name,
+			AuthorId:        pr.AuthorID,
+			Status:          api.PullRequestShortStatus(pr.Status),
+			CustomFields:    toAPICustomFields(pr.CustomFields),
+		}
+	}
+
+	return apiPRs
+}
+
+func newEvent(eventType domain.EventType, pr *domain.PullRequest, userIDs []string, at time.Time) domain.Event {
+	return domain.Event{
+		Type:            eventType,
+		PullRequestID:   pr.ID,
+		PullRequestName: pr.Name,
+		Description:     pr.Description,
+		ExternalURL:     pr.ExternalURL,
+		UserIDs:         userIDs,
+		OccurredAt:      at,
 	}
 }
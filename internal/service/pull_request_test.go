@@ -4,14 +4,21 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
 	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/localcache"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -20,30 +27,43 @@ import (
 func TestPullRequestServiceImpl_CreatePR(t *testing.T) {
 	ctx := context.Background()
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	// storedCreatedAt is created_at as the repository stored it, at a lower precision than the clock.
+	storedCreatedAt := testNow.UTC().Add(-time.Microsecond)
 
 	testCases := []struct {
-		name          string
-		setupMocks    func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock)
-		prID          string
-		prName        string
-		authorID      string
-		expectedPR    *api.PullRequest
-		expectedError bool
+		name            string
+		setupMocks      func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock)
+		setupQuery      func(prQuery *PRQueryRepositoryMock)
+		opts            []PullRequestServiceOption
+		prID            string
+		prName          string
+		authorID        string
+		expectedPR      *api.PullRequest
+		expectedCreated bool
+		expectedError   bool
+		expectedErrorIs error
 	}{
 		{
 			name:     "Success with 2 reviewers",
 			prID:     "pr-1",
 			prName:   "feat: new logic",
 			authorID: "author-1",
-			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock) {
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
 				_, mockedTx, smock := newMockDBAndTx(t)
 				smock.ExpectCommit()
 
 				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
 				userPR.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
-				userPR.On("GetRandomActiveReviewers", ctx, 1, []string{"author-1"}, 2).Return([]string{"rev-1", "rev-2"}, nil).Once()
-				prCmd.On("CreatePR", ctx, mockedTx, mock.AnythingOfType("*domain.PullRequest")).Return(nil).Once()
-				prCmd.On("AssignReviewers", ctx, mockedTx, "pr-1", []string{"rev-1", "rev-2"}).Return(nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{"rev-1", "rev-2"}, nil).Once()
+				prCmd.On("CreatePR", inTx(mockedTx), mock.MatchedBy(func(pr *domain.PullRequest) bool {
+					return pr.CreatedAt == testNow.UTC()
+				})).Return(nil).Once()
+				prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"rev-1", "rev-2"}).Return([]string{"rev-1", "rev-2"}, nil).Once()
+				prCmd.On("AssignReviewers", inTx(mockedTx), "pr-1", []string{"rev-1", "rev-2"}).Return(nil).Once()
+				history.On("RecordAssignments", inTx(mockedTx), []domain.AssignmentRecord{
+					{PullRequestID: "pr-1", UserID: "rev-1", Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, Cause: domain.CauseCreated, CreatedAt: testNow.UTC()},
+					{PullRequestID: "pr-1", UserID: "rev-2", Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, Cause: domain.CauseCreated, CreatedAt: testNow.UTC()},
+				}).Return(nil).Once()
 			},
 			expectedPR: &api.PullRequest{
 				PullRequestId:     "pr-1",
@@ -52,24 +72,30 @@ func TestPullRequestServiceImpl_CreatePR(t *testing.T) {
 				Status:            "OPEN",
 				AssignedReviewers: []string{"rev-1", "rev-2"},
 			},
-			expectedError: false,
+			expectedCreated: true,
 		},
 		{
 			name:     "Success with 1 reviewer",
 			prID:     "pr-2",
 			prName:   "fix: bug",
 			authorID: "author-2",
-			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock) {
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
 				_, mockedTx, smock := newMockDBAndTx(t)
 				smock.ExpectCommit()
 
 				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
 				userPR.On("GetAuthorTeamID", ctx, "author-2").Return(2, nil).Once()
-				userPR.On("GetRandomActiveReviewers", ctx, 2, []string{"author-2"}, 2).Return([]string{"rev-3"}, nil).Once()
-				prCmd.On("CreatePR", ctx, mockedTx, mock.MatchedBy(func(pr *domain.PullRequest) bool {
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 2, []string{"author-2"}, 2).Return([]string{"rev-3"}, nil).Once()
+				prCmd.On("CreatePR", inTx(mockedTx), mock.MatchedBy(func(pr *domain.PullRequest) bool {
 					return pr.NeedMoreReviewers
-				})).Return(nil).Once()
-				prCmd.On("AssignReviewers", ctx, mockedTx, "pr-2", []string{"rev-3"}).Return(nil).Once()
+				})).Run(func(args mock.Arguments) {
+					args.Get(1).(*domain.PullRequest).CreatedAt = storedCreatedAt
+				}).Return(nil).Once()
+				prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"rev-3"}).Return([]string{"rev-3"}, nil).Once()
+				prCmd.On("AssignReviewers", inTx(mockedTx), "pr-2", []string{"rev-3"}).Return(nil).Once()
+				history.On("RecordAssignments", inTx(mockedTx), []domain.AssignmentRecord{
+					{PullRequestID: "pr-2", UserID: "rev-3", Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, Cause: domain.CauseCreated, CreatedAt: storedCreatedAt},
+				}).Return(nil).Once()
 			},
 			expectedPR: &api.PullRequest{
 				PullRequestId:     "pr-2",
@@ -77,13 +103,130 @@ func TestPullRequestServiceImpl_CreatePR(t *testing.T) {
 				AuthorId:          "author-2",
 				Status:            "OPEN",
 				AssignedReviewers: []string{"rev-3"},
+				CreatedAt:         &storedCreatedAt,
+			},
+			expectedCreated: true,
+		},
+		{
+			name:     "Generated ID",
+			prName:   "feat: new logic",
+			authorID: "author-1",
+			opts:     []PullRequestServiceOption{WithIDGenerator(fixedIDs{"pr-generated"})},
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
+				_, mockedTx, smock := newMockDBAndTx(t)
+				smock.ExpectCommit()
+
+				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+				userPR.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{"rev-1"}, nil).Once()
+				prCmd.On("CreatePR", inTx(mockedTx), mock.MatchedBy(func(pr *domain.PullRequest) bool {
+					return pr.ID == "pr-generated"
+				})).Return(nil).Once()
+				prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"rev-1"}).Return([]string{"rev-1"}, nil).Once()
+				prCmd.On("AssignReviewers", inTx(mockedTx), "pr-generated", []string{"rev-1"}).Return(nil).Once()
+				history.On("RecordAssignments", inTx(mockedTx), mock.Anything).Return(nil).Once()
+			},
+			expectedPR: &api.PullRequest{
+				PullRequestId:     "pr-generated",
+				PullRequestName:   "feat: new logic",
+				AuthorId:          "author-1",
+				Status:            "OPEN",
+				AssignedReviewers: []string{"rev-1"},
+			},
+			expectedCreated: true,
+		},
+		{
+			name:     "Strict inactive author",
+			prID:     "pr-strict-1",
+			authorID: "author-1",
+			opts:     []PullRequestServiceOption{WithStrictChecks()},
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
+				userPR.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
+				userPR.On("IsUserActive", ctx, "author-1").Return(false, nil).Once()
+			},
+			expectedError:   true,
+			expectedErrorIs: apperrors.ErrAuthorInactive,
+		},
+		{
+			name:     "Strict team too small",
+			prID:     "pr-strict-2",
+			authorID: "author-1",
+			opts:     []PullRequestServiceOption{WithStrictChecks()},
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
+				_, mockedTx, smock := newMockDBAndTx(t)
+				smock.ExpectRollback()
+
+				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+				userPR.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
+				userPR.On("IsUserActive", ctx, "author-1").Return(true, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{"rev-1"}, nil).Once()
+				prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"rev-1"}).Return([]string{"rev-1"}, nil).Once()
+			},
+			expectedError:   true,
+			expectedErrorIs: apperrors.ErrTeamTooSmall,
+		},
+		{
+			name:     "Reviewer deactivated since its selection is replaced",
+			prID:     "pr-raced",
+			prName:   "feat: new logic",
+			authorID: "author-1",
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
+				_, mockedTx, smock := newMockDBAndTx(t)
+				smock.ExpectCommit()
+
+				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+				userPR.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{"rev-1", "rev-2"}, nil).Once()
+				prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"rev-1", "rev-2"}).Return([]string{"rev-2"}, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1", "rev-1", "rev-2"}, 1).Return([]string{"rev-3"}, nil).Once()
+				prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"rev-3"}).Return([]string{"rev-3"}, nil).Once()
+				prCmd.On("CreatePR", inTx(mockedTx), mock.MatchedBy(func(pr *domain.PullRequest) bool {
+					return !pr.NeedMoreReviewers
+				})).Return(nil).Once()
+				prCmd.On("AssignReviewers", inTx(mockedTx), "pr-raced", []string{"rev-2", "rev-3"}).Return(nil).Once()
+				history.On("RecordAssignments", inTx(mockedTx), mock.Anything).Return(nil).Once()
+			},
+			expectedPR: &api.PullRequest{
+				PullRequestId:     "pr-raced",
+				PullRequestName:   "feat: new logic",
+				AuthorId:          "author-1",
+				Status:            "OPEN",
+				AssignedReviewers: []string{"rev-2", "rev-3"},
+			},
+			expectedCreated: true,
+		},
+		{
+			name:     "Strict success",
+			prID:     "pr-strict-3",
+			prName:   "feat: new logic",
+			authorID: "author-1",
+			opts:     []PullRequestServiceOption{WithStrictChecks()},
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
+				_, mockedTx, smock := newMockDBAndTx(t)
+				smock.ExpectCommit()
+
+				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+				userPR.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
+				userPR.On("IsUserActive", ctx, "author-1").Return(true, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{"rev-1", "rev-2"}, nil).Once()
+				prCmd.On("CreatePR", inTx(mockedTx), mock.AnythingOfType("*domain.PullRequest")).Return(nil).Once()
+				prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"rev-1", "rev-2"}).Return([]string{"rev-1", "rev-2"}, nil).Once()
+				prCmd.On("AssignReviewers", inTx(mockedTx), "pr-strict-3", []string{"rev-1", "rev-2"}).Return(nil).Once()
+				history.On("RecordAssignments", inTx(mockedTx), mock.Anything).Return(nil).Once()
+			},
+			expectedPR: &api.PullRequest{
+				PullRequestId:     "pr-strict-3",
+				PullRequestName:   "feat: new logic",
+				AuthorId:          "author-1",
+				Status:            "OPEN",
+				AssignedReviewers: []string{"rev-1", "rev-2"},
 			},
-			expectedError: false,
+			expectedCreated: true,
 		},
 		{
 			name:     "Failure on GetAuthorTeamID",
 			authorID: "author-3",
-			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock) {
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
 				userPR.On("GetAuthorTeamID", ctx, "author-3").Return(0, errors.New("db error")).Once()
 			},
 			expectedError: true,
@@ -91,9 +234,8 @@ func TestPullRequestServiceImpl_CreatePR(t *testing.T) {
 		{
 			name:     "Failure on BeginTxx",
 			authorID: "author-4",
-			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock) {
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
 				userPR.On("GetAuthorTeamID", ctx, "author-4").Return(1, nil).Once()
-				userPR.On("GetRandomActiveReviewers", ctx, 1, []string{"author-4"}, 2).Return([]string{"rev-1"}, nil).Once()
 				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(nil, errors.New("cannot begin tx")).Once()
 			},
 			expectedError: true,
@@ -102,48 +244,332 @@ func TestPullRequestServiceImpl_CreatePR(t *testing.T) {
 			name:     "Failure on CreatePR in repo",
 			prID:     "pr-5",
 			authorID: "author-5",
-			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock) {
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
 				_, mockedTx, smock := newMockDBAndTx(t)
 				smock.ExpectRollback()
 
 				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
 				userPR.On("GetAuthorTeamID", ctx, "author-5").Return(1, nil).Once()
-				userPR.On("GetRandomActiveReviewers", ctx, 1, []string{"author-5"}, 2).Return([]string{"rev-1"}, nil).Once()
-				prCmd.On("CreatePR", ctx, mockedTx, mock.Anything).Return(errors.New("repo create failed")).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-5"}, 2).Return([]string{"rev-1"}, nil).Once()
+				prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"rev-1"}).Return([]string{"rev-1"}, nil).Once()
+				prCmd.On("CreatePR", inTx(mockedTx), mock.Anything).Return(errors.New("repo create failed")).Once()
 			},
 			expectedError: true,
 		},
+		{
+			name:     "Duplicate returns conflict by default",
+			prID:     "pr-dup",
+			prName:   "feat: dup",
+			authorID: "author-1",
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
+				_, mockedTx, smock := newMockDBAndTx(t)
+				smock.ExpectRollback()
+
+				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+				userPR.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{"rev-1"}, nil).Once()
+				prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"rev-1"}).Return([]string{"rev-1"}, nil).Once()
+				prCmd.On("CreatePR", inTx(mockedTx), mock.Anything).Return(&apperrors.PRAlreadyExistsError{PRID: "pr-dup"}).Once()
+			},
+			expectedError:   true,
+			expectedErrorIs: apperrors.ErrAlreadyExists,
+		},
+		{
+			name:     "Duplicate returns existing PR when configured",
+			prID:     "pr-dup",
+			prName:   "feat: dup",
+			authorID: "author-1",
+			opts:     []PullRequestServiceOption{WithReturnExistingOnDuplicate()},
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
+				_, mockedTx, smock := newMockDBAndTx(t)
+				smock.ExpectRollback()
+
+				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+				userPR.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{"rev-2"}, nil).Once()
+				prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"rev-2"}).Return([]string{"rev-2"}, nil).Once()
+				prCmd.On("CreatePR", inTx(mockedTx), mock.Anything).Return(&apperrors.PRAlreadyExistsError{PRID: "pr-dup"}).Once()
+			},
+			setupQuery: func(prQuery *PRQueryRepositoryMock) {
+				prQuery.On("GetPRByIDWithReviewers", ctx, "pr-dup").Return(&domain.PullRequest{
+					ID:          "pr-dup",
+					Name:        "feat: dup",
+					AuthorID:    "author-1",
+					Status:      api.PullRequestStatusOPEN,
+					ReviewerIDs: []string{"rev-1"},
+				}, nil).Once()
+			},
+			expectedPR: &api.PullRequest{
+				PullRequestId:     "pr-dup",
+				PullRequestName:   "feat: dup",
+				AuthorId:          "author-1",
+				Status:            "OPEN",
+				AssignedReviewers: []string{"rev-1"},
+			},
+			expectedCreated: false,
+		},
+		{
+			name:     "Duplicate of another author's PR keeps the conflict",
+			prID:     "pr-dup",
+			prName:   "feat: dup",
+			authorID: "author-2",
+			opts:     []PullRequestServiceOption{WithReturnExistingOnDuplicate()},
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
+				_, mockedTx, smock := newMockDBAndTx(t)
+				smock.ExpectRollback()
+
+				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+				userPR.On("GetAuthorTeamID", ctx, "author-2").Return(1, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-2"}, 2).Return([]string{"rev-1"}, nil).Once()
+				prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"rev-1"}).Return([]string{"rev-1"}, nil).Once()
+				prCmd.On("CreatePR", inTx(mockedTx), mock.Anything).Return(&apperrors.PRAlreadyExistsError{PRID: "pr-dup"}).Once()
+			},
+			setupQuery: func(prQuery *PRQueryRepositoryMock) {
+				prQuery.On("GetPRByIDWithReviewers", ctx, "pr-dup").Return(&domain.PullRequest{
+					ID:       "pr-dup",
+					AuthorID: "author-1",
+					Status:   api.PullRequestStatusOPEN,
+				}, nil).Once()
+			},
+			expectedError:   true,
+			expectedErrorIs: apperrors.ErrAlreadyExists,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			transactorMock := new(TransactorMock)
 			prCmdMock := new(PRCommandRepositoryMock)
+			prQueryMock := new(PRQueryRepositoryMock)
 			userPRMock := new(UserPRRepositoryMock)
-			tc.setupMocks(transactorMock, prCmdMock, userPRMock)
+			historyMock := new(AssignmentHistoryRepositoryMock)
+			tc.setupMocks(transactorMock, prCmdMock, userPRMock, historyMock)
+
+			if tc.setupQuery != nil {
+				tc.setupQuery(prQueryMock)
+			}
 
-			service := NewPullRequestService(transactorMock, logger, prCmdMock, nil, userPRMock)
-			pr, err := service.CreatePR(ctx, tc.prID, tc.prName, tc.authorID)
+			opts := append([]PullRequestServiceOption{WithClock(fixedClock(testNow))}, tc.opts...)
+			service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, userPRMock, nil, historyMock, opts...)
+			pr, created, err := service.CreatePR(ctx, tc.prID, tc.prName, tc.authorID, PRDetails{})
 
 			if tc.expectedError {
 				assert.Error(t, err)
+
+				if tc.expectedErrorIs != nil {
+					assert.ErrorIs(t, err, tc.expectedErrorIs)
+				}
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCreated, created)
 				assert.NotNil(t, pr)
 				assert.Equal(t, tc.expectedPR.PullRequestId, pr.PullRequestId)
 				assert.Equal(t, tc.expectedPR.PullRequestName, pr.PullRequestName)
 				assert.Equal(t, tc.expectedPR.AuthorId, pr.AuthorId)
 				assert.Equal(t, tc.expectedPR.Status, pr.Status)
 				assert.ElementsMatch(t, tc.expectedPR.AssignedReviewers, pr.AssignedReviewers)
+
+				if tc.expectedPR.CreatedAt != nil {
+					require.NotNil(t, pr.CreatedAt)
+					assert.Equal(t, *tc.expectedPR.CreatedAt, *pr.CreatedAt)
+				}
 			}
 
 			transactorMock.AssertExpectations(t)
 			prCmdMock.AssertExpectations(t)
+			prQueryMock.AssertExpectations(t)
 			userPRMock.AssertExpectations(t)
+			historyMock.AssertExpectations(t)
 		})
 	}
 }
 
+func TestPullRequestServiceImpl_CreatePR_AuthorQuota(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	limit := 3
+
+	testCases := []struct {
+		name              string
+		action            domain.QuotaAction
+		openPRs           int
+		expectedReviewers []string
+		expectedErrorIs   error
+	}{
+		{
+			name:              "Under the limit assigns reviewers",
+			action:            domain.QuotaReject,
+			openPRs:           2,
+			expectedReviewers: []string{"rev-1", "rev-2"},
+		},
+		{
+			name:            "Reject refuses a PR beyond the limit",
+			action:          domain.QuotaReject,
+			openPRs:         3,
+			expectedErrorIs: apperrors.ErrAuthorQuotaExceeded,
+		},
+		{
+			name:              "Queue creates a PR beyond the limit without reviewers",
+			action:            domain.QuotaQueue,
+			openPRs:           3,
+			expectedReviewers: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transactorMock := new(TransactorMock)
+			prCmdMock := new(PRCommandRepositoryMock)
+			userPRMock := new(UserPRRepositoryMock)
+			policyMock := new(PolicyRepositoryMock)
+			historyMock := new(AssignmentHistoryRepositoryMock)
+			notifierMock := new(NotifierMock)
+
+			_, mockedTx, smock := newMockDBAndTx(t)
+			if tc.expectedErrorIs != nil {
+				smock.ExpectRollback()
+			} else {
+				smock.ExpectCommit()
+			}
+
+			transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+			userPRMock.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
+			policyMock.On("GetTeamPolicy", ctx, 1).Return(&domain.TeamPolicy{
+				TeamID:            1,
+				AuthorOpenPRLimit: &limit,
+				OverQuotaAction:   tc.action,
+			}, nil).Once()
+			userPRMock.On("GetRandomActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{"rev-1", "rev-2"}, nil).Once()
+			prCmdMock.On("CountOpenPRsByAuthor", inTx(mockedTx), "author-1").Return(tc.openPRs, nil).Once()
+			// The PR is inserted before the quota error rolls the transaction back.
+			prCmdMock.On("CreatePR", inTx(mockedTx), mock.AnythingOfType("*domain.PullRequest")).Return(nil).Once()
+
+			if len(tc.expectedReviewers) > 0 {
+				prCmdMock.On("LockActiveUsers", inTx(mockedTx), tc.expectedReviewers).Return(tc.expectedReviewers, nil).Once()
+				prCmdMock.On("AssignReviewers", inTx(mockedTx), "pr-1", tc.expectedReviewers).Return(nil).Once()
+				historyMock.On("RecordAssignments", inTx(mockedTx), mock.Anything).Return(nil).Once()
+				notifierMock.On("Notify", ctx, mock.Anything).Once()
+			}
+
+			service := NewPullRequestService(transactorMock, logger, prCmdMock, nil, userPRMock, policyMock, historyMock, WithNotifier(notifierMock))
+			pr, _, err := service.CreatePR(ctx, "pr-1", "feat: flood", "author-1", PRDetails{})
+
+			if tc.expectedErrorIs != nil {
+				require.ErrorIs(t, err, tc.expectedErrorIs)
+
+				var quotaErr *apperrors.AuthorQuotaExceededError
+				require.ErrorAs(t, err, &quotaErr)
+				assert.Equal(t, limit, quotaErr.Limit)
+			} else {
+				require.NoError(t, err)
+				assert.ElementsMatch(t, tc.expectedReviewers, pr.AssignedReviewers)
+			}
+
+			prCmdMock.AssertExpectations(t)
+			historyMock.AssertExpectations(t)
+			notifierMock.AssertExpectations(t)
+			require.NoError(t, smock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPullRequestServiceImpl_CreatePR_AssignmentPolicySnapshot(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	updatedAt := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	transactorMock := new(TransactorMock)
+	prCmdMock := new(PRCommandRepositoryMock)
+	prQueryMock := new(PRQueryRepositoryMock)
+	userPRMock := new(UserPRRepositoryMock)
+	policyMock := new(PolicyRepositoryMock)
+	historyMock := new(AssignmentHistoryRepositoryMock)
+
+	_, mockedTx, smock := newMockDBAndTx(t)
+	smock.ExpectCommit()
+
+	transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+	userPRMock.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
+	policyMock.On("GetTeamPolicy", ctx, 1).Return(&domain.TeamPolicy{
+		TeamID: 1,
+		// Unknown strategies and non-positive weights never take part in the draw and are left out of the snapshot.
+		StrategyWeights: map[domain.AssignmentStrategy]int{domain.StrategyLeastLoaded: 80, domain.StrategyRandom: 0, "by_tags": 20},
+		UpdatedAt:       updatedAt,
+	}, nil).Once()
+	userPRMock.On("GetLeastLoadedActiveReviewers", mock.Anything, 1, []string{"author-1"}, 2).Return([]string{"rev-1", "rev-2"}, nil).Once()
+	prCmdMock.On("LockActiveUsers", inTx(mockedTx), []string{"rev-1", "rev-2"}).Return([]string{"rev-1", "rev-2"}, nil).Once()
+	prCmdMock.On("AssignReviewers", inTx(mockedTx), "pr-1", []string{"rev-1", "rev-2"}).Return(nil).Once()
+	historyMock.On("RecordAssignments", inTx(mockedTx), mock.Anything).Return(nil).Once()
+
+	var stored *domain.PullRequest
+	prCmdMock.On("CreatePR", inTx(mockedTx), mock.AnythingOfType("*domain.PullRequest")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*domain.PullRequest)
+	}).Return(nil).Once()
+
+	service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, userPRMock, policyMock, historyMock)
+	created, _, err := service.CreatePR(ctx, "pr-1", "feat: snapshot", "author-1", PRDetails{})
+	require.NoError(t, err)
+	assert.Nil(t, created.AssignmentPolicy, "the snapshot is served by /pullRequest/get only")
+	require.NotNil(t, stored)
+
+	prQueryMock.On("GetPRByIDWithReviewers", ctx, "pr-1").Return(stored, nil).Once()
+
+	pr, err := service.GetPR(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, &api.AssignmentPolicySnapshot{
+		TeamId:          1,
+		Strategy:        string(domain.StrategyLeastLoaded),
+		StrategyWeights: map[string]int{string(domain.StrategyLeastLoaded): 80},
+		ReviewersPerPr:  2,
+		OverQuotaAction: api.TeamPolicyOverQuotaAction(domain.QuotaReject),
+		PolicyUpdatedAt: &updatedAt,
+	}, pr.AssignmentPolicy)
+
+	prCmdMock.AssertExpectations(t)
+	require.NoError(t, smock.ExpectationsWereMet())
+}
+
+func TestPullRequestServiceImpl_PreviewAssignment(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("Reviewers are selected without a transaction", func(t *testing.T) {
+		transactorMock := new(TransactorMock)
+		prCmdMock := new(PRCommandRepositoryMock)
+		userPRMock := new(UserPRRepositoryMock)
+		policyMock := new(PolicyRepositoryMock)
+
+		userPRMock.On("GetAuthorTeamID", ctx, "author-1").Return(1, nil).Once()
+		policyMock.On("GetTeamPolicy", ctx, 1).Return(&domain.TeamPolicy{
+			TeamID:          1,
+			StrategyWeights: map[domain.AssignmentStrategy]int{domain.StrategyLeastLoaded: 100},
+		}, nil).Once()
+		userPRMock.On("GetLeastLoadedActiveReviewers", ctx, 1, []string{"author-1"}, 2).Return([]string{"rev-1"}, nil).Once()
+
+		service := NewPullRequestService(transactorMock, logger, prCmdMock, new(PRQueryRepositoryMock), userPRMock, policyMock, nil)
+		preview, err := service.PreviewAssignment(ctx, "author-1")
+		require.NoError(t, err)
+		assert.Equal(t, &AssignmentPreview{
+			TeamID: 1, ReviewerIDs: []string{"rev-1"}, Strategy: domain.StrategyLeastLoaded, Missing: 1,
+		}, preview)
+
+		// The mocks fail on any BeginTxx, CreatePR or AssignReviewers call.
+		transactorMock.AssertExpectations(t)
+		prCmdMock.AssertExpectations(t)
+		userPRMock.AssertExpectations(t)
+	})
+
+	t.Run("Author without a team", func(t *testing.T) {
+		userPRMock := new(UserPRRepositoryMock)
+		userPRMock.On("GetAuthorTeamID", ctx, "author-1").Return(0, apperrors.ErrNotFound).Once()
+
+		service := NewPullRequestService(new(TransactorMock), logger, new(PRCommandRepositoryMock), new(PRQueryRepositoryMock),
+			userPRMock, nil, nil)
+		_, err := service.PreviewAssignment(ctx, "author-1")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
+
 func TestPullRequestServiceImpl_MergePR(t *testing.T) {
 	ctx := context.Background()
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
@@ -153,17 +579,19 @@ func TestPullRequestServiceImpl_MergePR(t *testing.T) {
 		ID:     prID,
 		Status: api.PullRequestStatusOPEN,
 	}
+	errStats := errors.New("db error")
 	mergedPR := &domain.PullRequest{
 		ID:       prID,
 		Status:   api.PullRequestStatusMERGED,
 		MergedAt: &time.Time{},
 	}
+	storedMergedAt := time.Date(2025, time.March, 14, 12, 9, 26, 123456000, time.UTC)
 
 	testCases := []struct {
 		name          string
 		setupMocks    func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock)
 		expectedError error
-		assertResult  func(t *testing.T, pr *api.PullRequest)
+		assertResult  func(t *testing.T, resp *api.MergeResponse)
 	}{
 		{
 			name: "Success - Merge an OPEN PR",
@@ -172,14 +600,20 @@ func TestPullRequestServiceImpl_MergePR(t *testing.T) {
 				smock.ExpectCommit()
 
 				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
-				prCmd.On("GetPRByIDWithLock", mock.Anything, mockedTx, prID).Return(openPR, nil).Once()
-				prCmd.On("UpdatePRStatus", mock.Anything, mockedTx, prID, api.PullRequestStatusMERGED, mock.AnythingOfType("time.Time")).Return(nil).Once()
-				prQuery.On("GetReviewerIDs", mock.Anything, mockedTx, prID).Return([]string{"rev1"}, nil).Once()
+				prCmd.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(openPR, nil).Once()
+				prCmd.On("UpdatePRStatus", inTx(mockedTx), prID, api.PullRequestStatusMERGED, mock.AnythingOfType("time.Time")).Return(&storedMergedAt, nil).Once()
+				prQuery.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{"rev1"}, nil).Once()
+				prQuery.On("GetStatsByUserIDs", inTx(mockedTx), []string{"rev1"}).
+					Return([]domain.Stats{{UserID: "rev1", Username: "Bob", OpenReviews: 2, MergedReviews: 5}}, nil).Once()
 			},
-			assertResult: func(t *testing.T, pr *api.PullRequest) {
-				assert.Equal(t, api.PullRequestStatusMERGED, pr.Status)
-				assert.NotNil(t, pr.MergedAt)
-				assert.Contains(t, pr.AssignedReviewers, "rev1")
+			assertResult: func(t *testing.T, resp *api.MergeResponse) {
+				assert.Equal(t, api.PullRequestStatusMERGED, resp.Pr.Status)
+				require.NotNil(t, resp.Pr.MergedAt)
+				assert.Equal(t, storedMergedAt, *resp.Pr.MergedAt)
+				assert.Contains(t, resp.Pr.AssignedReviewers, "rev1")
+				assert.Equal(t, []api.UserStats{
+					{UserId: "rev1", Username: "Bob", OpenReviews: 2, MergedReviews: 5},
+				}, resp.ReviewerStats)
 			},
 		},
 		{
@@ -189,12 +623,29 @@ func TestPullRequestServiceImpl_MergePR(t *testing.T) {
 				smock.ExpectCommit()
 
 				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
-				prCmd.On("GetPRByIDWithLock", mock.Anything, mockedTx, prID).Return(mergedPR, nil).Once()
-				prQuery.On("GetReviewerIDs", mock.Anything, mockedTx, prID).Return([]string{"rev1"}, nil).Once()
+				prCmd.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(mergedPR, nil).Once()
+				prQuery.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{"rev1"}, nil).Once()
+				prQuery.On("GetStatsByUserIDs", inTx(mockedTx), []string{"rev1"}).
+					Return([]domain.Stats{{UserID: "rev1", Username: "Bob", MergedReviews: 1}}, nil).Once()
+			},
+			assertResult: func(t *testing.T, resp *api.MergeResponse) {
+				assert.Equal(t, api.PullRequestStatusMERGED, resp.Pr.Status)
+				assert.Len(t, resp.ReviewerStats, 1)
 			},
-			assertResult: func(t *testing.T, pr *api.PullRequest) {
-				assert.Equal(t, api.PullRequestStatusMERGED, pr.Status)
+		},
+		{
+			name: "Failure - Reviewer stats error rolls back",
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock) {
+				_, mockedTx, smock := newMockDBAndTx(t)
+				smock.ExpectRollback()
+
+				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+				prCmd.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(&domain.PullRequest{ID: prID, Status: api.PullRequestStatusOPEN}, nil).Once()
+				prCmd.On("UpdatePRStatus", inTx(mockedTx), prID, api.PullRequestStatusMERGED, mock.AnythingOfType("time.Time")).Return(nil, nil).Once()
+				prQuery.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{"rev1"}, nil).Once()
+				prQuery.On("GetStatsByUserIDs", inTx(mockedTx), []string{"rev1"}).Return(nil, errStats).Once()
 			},
+			expectedError: errStats,
 		},
 		{
 			name: "Failure - PR not found",
@@ -203,10 +654,21 @@ func TestPullRequestServiceImpl_MergePR(t *testing.T) {
 				smock.ExpectRollback()
 
 				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
-				prCmd.On("GetPRByIDWithLock", mock.Anything, mockedTx, prID).Return(nil, apperrors.ErrNotFound).Once()
+				prCmd.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(nil, apperrors.ErrNotFound).Once()
 			},
 			expectedError: apperrors.ErrNotFound,
 		},
+		{
+			name: "Failure - PR is closed",
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock) {
+				_, mockedTx, smock := newMockDBAndTx(t)
+				smock.ExpectRollback()
+
+				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+				prCmd.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(&domain.PullRequest{ID: prID, Status: api.PullRequestStatusCLOSED}, nil).Once()
+			},
+			expectedError: apperrors.ErrPRClosed,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -216,15 +678,15 @@ func TestPullRequestServiceImpl_MergePR(t *testing.T) {
 			prQueryMock := new(PRQueryRepositoryMock)
 			tc.setupMocks(transactorMock, prCmdMock, prQueryMock)
 
-			service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil)
-			pr, err := service.MergePR(ctx, prID)
+			service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil, nil, nil)
+			resp, err := service.MergePR(ctx, prID, MergeOptions{})
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
 				assert.True(t, errors.Is(err, tc.expectedError))
 			} else {
 				assert.NoError(t, err)
-				tc.assertResult(t, pr)
+				tc.assertResult(t, resp)
 			}
 
 			transactorMock.AssertExpectations(t)
@@ -234,6 +696,156 @@ func TestPullRequestServiceImpl_MergePR(t *testing.T) {
 	}
 }
 
+func TestPullRequestServiceImpl_MergePR_Notifies(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	prID := "pr-to-merge"
+
+	t.Run("Merging an OPEN PR notifies its reviewers at the clock time", func(t *testing.T) {
+		transactorMock := new(TransactorMock)
+		prCmdMock := new(PRCommandRepositoryMock)
+		prQueryMock := new(PRQueryRepositoryMock)
+		notifierMock := new(NotifierMock)
+
+		_, mockedTx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+
+		transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+		prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(&domain.PullRequest{ID: prID, Status: api.PullRequestStatusOPEN}, nil).Once()
+		prCmdMock.On("UpdatePRStatus", inTx(mockedTx), prID, api.PullRequestStatusMERGED, testNow.UTC()).Return(nil, nil).Once()
+		prQueryMock.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{"rev1", "rev2"}, nil).Once()
+		prQueryMock.On("GetStatsByUserIDs", inTx(mockedTx), []string{"rev1", "rev2"}).Return([]domain.Stats{}, nil).Once()
+		notifierMock.On("Notify", mock.Anything, mock.MatchedBy(func(event domain.Event) bool {
+			return event.Type == domain.EventPRMerged && event.PullRequestID == prID &&
+				assert.ObjectsAreEqual([]string{"rev1", "rev2"}, event.UserIDs) && event.OccurredAt == testNow.UTC()
+		})).Once()
+
+		service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil, nil, nil,
+			WithNotifier(notifierMock), WithClock(fixedClock(testNow)))
+		_, err := service.MergePR(ctx, prID, MergeOptions{})
+
+		require.NoError(t, err)
+		notifierMock.AssertExpectations(t)
+	})
+
+	t.Run("Repeated merge does not notify", func(t *testing.T) {
+		transactorMock := new(TransactorMock)
+		prCmdMock := new(PRCommandRepositoryMock)
+		prQueryMock := new(PRQueryRepositoryMock)
+		notifierMock := new(NotifierMock)
+
+		_, mockedTx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+
+		transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+		prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(&domain.PullRequest{ID: prID, Status: api.PullRequestStatusMERGED}, nil).Once()
+		prQueryMock.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{"rev1"}, nil).Once()
+		prQueryMock.On("GetStatsByUserIDs", inTx(mockedTx), []string{"rev1"}).Return([]domain.Stats{}, nil).Once()
+
+		service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil, nil, nil, WithNotifier(notifierMock))
+		_, err := service.MergePR(ctx, prID, MergeOptions{})
+
+		require.NoError(t, err)
+		notifierMock.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPullRequestServiceImpl_ClosePR(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	prID := "pr-to-close"
+
+	t.Run("Closing an OPEN PR removes it from the queue and notifies its reviewers", func(t *testing.T) {
+		transactorMock := new(TransactorMock)
+		prCmdMock := new(PRCommandRepositoryMock)
+		prQueryMock := new(PRQueryRepositoryMock)
+		pendingMock := new(PendingAssignmentRepositoryMock)
+		notifierMock := new(NotifierMock)
+
+		_, mockedTx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+
+		transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+		prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).
+			Return(&domain.PullRequest{ID: prID, Name: "Abandoned", Status: api.PullRequestStatusOPEN}, nil).Once()
+		prCmdMock.On("UpdatePRStatus", inTx(mockedTx), prID, api.PullRequestStatusCLOSED, testNow.UTC()).Return(nil, nil).Once()
+		pendingMock.On("DequeuePending", inTx(mockedTx), prID).Return(nil).Once()
+		prQueryMock.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{"rev1", "rev2"}, nil).Once()
+		notifierMock.On("Notify", mock.Anything, mock.MatchedBy(func(event domain.Event) bool {
+			return event.Type == domain.EventPRClosed && event.PullRequestID == prID &&
+				assert.ObjectsAreEqual([]string{"rev1", "rev2"}, event.UserIDs) && event.OccurredAt == testNow.UTC()
+		})).Once()
+
+		service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil, nil, nil,
+			WithPendingAssignments(pendingMock), WithNotifier(notifierMock), WithClock(fixedClock(testNow)))
+		pr, err := service.ClosePR(ctx, prID)
+
+		require.NoError(t, err)
+		assert.Equal(t, api.PullRequestStatusCLOSED, pr.Status)
+		assert.Equal(t, []string{"rev1", "rev2"}, pr.AssignedReviewers)
+		assert.Nil(t, pr.MergedAt)
+
+		prCmdMock.AssertExpectations(t)
+		pendingMock.AssertExpectations(t)
+		notifierMock.AssertExpectations(t)
+	})
+
+	t.Run("Repeated close returns the current state without notifying", func(t *testing.T) {
+		transactorMock := new(TransactorMock)
+		prCmdMock := new(PRCommandRepositoryMock)
+		prQueryMock := new(PRQueryRepositoryMock)
+		notifierMock := new(NotifierMock)
+
+		_, mockedTx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+
+		transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+		prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(&domain.PullRequest{ID: prID, Status: api.PullRequestStatusCLOSED}, nil).Once()
+		prQueryMock.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{"rev1"}, nil).Once()
+
+		service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil, nil, nil, WithNotifier(notifierMock))
+		pr, err := service.ClosePR(ctx, prID)
+
+		require.NoError(t, err)
+		assert.Equal(t, api.PullRequestStatusCLOSED, pr.Status)
+		prCmdMock.AssertNotCalled(t, "UpdatePRStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		notifierMock.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Merged PR cannot be closed", func(t *testing.T) {
+		transactorMock := new(TransactorMock)
+		prCmdMock := new(PRCommandRepositoryMock)
+
+		_, mockedTx, smock := newMockDBAndTx(t)
+		smock.ExpectRollback()
+
+		transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+		prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(&domain.PullRequest{ID: prID, Status: api.PullRequestStatusMERGED}, nil).Once()
+
+		service := NewPullRequestService(transactorMock, logger, prCmdMock, new(PRQueryRepositoryMock), nil, nil, nil)
+		_, err := service.ClosePR(ctx, prID)
+
+		assert.ErrorIs(t, err, apperrors.ErrPRMerged)
+		prCmdMock.AssertExpectations(t)
+	})
+
+	t.Run("Unknown PR", func(t *testing.T) {
+		transactorMock := new(TransactorMock)
+		prCmdMock := new(PRCommandRepositoryMock)
+
+		_, mockedTx, smock := newMockDBAndTx(t)
+		smock.ExpectRollback()
+
+		transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+		prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(nil, apperrors.ErrNotFound).Once()
+
+		service := NewPullRequestService(transactorMock, logger, prCmdMock, new(PRQueryRepositoryMock), nil, nil, nil)
+		_, err := service.ClosePR(ctx, prID)
+
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
+
 func TestPullRequestServiceImpl_ReassignReviewer(t *testing.T) {
 	ctx := context.Background()
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
@@ -246,7 +858,7 @@ func TestPullRequestServiceImpl_ReassignReviewer(t *testing.T) {
 
 	testCases := []struct {
 		name             string
-		setupMocks       func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock)
+		setupMocks       func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock)
 		prID             string
 		oldReviewerID    string
 		expectedResponse *api.ReassignResponse
@@ -256,17 +868,86 @@ func TestPullRequestServiceImpl_ReassignReviewer(t *testing.T) {
 			name:          "Success reassign",
 			prID:          "pr-1",
 			oldReviewerID: "old-rev",
-			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock) {
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
 				_, mockedTx, smock := newMockDBAndTx(t)
 				smock.ExpectCommit()
 
 				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
-				prCmd.On("GetPRByIDWithLock", mock.Anything, mockedTx, "pr-1").Return(prInDB, nil).Once()
-				prQuery.On("GetReviewerIDs", mock.Anything, mockedTx, "pr-1").Return([]string{"old-rev", "other-rev"}, nil).Once()
-				userPR.On("GetReviewerTeamID", ctx, "old-rev").Return(1, nil).Once()
-				userPR.On("GetRandomActiveReviewers", ctx, 1, mock.Anything, 1).Return([]string{"new-rev"}, nil).Once()
-				prCmd.On("ReplaceReviewer", mock.Anything, mockedTx, "pr-1", "old-rev", "new-rev").Return(nil).Once()
-				prQuery.On("GetReviewerIDs", mock.Anything, mockedTx, "pr-1").Return([]string{"new-rev", "other-rev"}, nil).Once()
+				prCmd.On("GetPRByIDWithLock", inTx(mockedTx), "pr-1").Return(prInDB, nil).Once()
+				prQuery.On("GetReviewerIDs", inTx(mockedTx), "pr-1").Return([]string{"old-rev", "other-rev"}, nil).Once()
+				userPR.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, mock.Anything, 1).Return([]string{"new-rev"}, nil).Once()
+				prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"new-rev"}).Return([]string{"new-rev"}, nil).Once()
+				prCmd.On("ReplaceReviewer", inTx(mockedTx), "pr-1", "old-rev", "new-rev").Return(nil).Once()
+				history.On("RecordAssignments", inTx(mockedTx), mock.MatchedBy(func(records []domain.AssignmentRecord) bool {
+					return len(records) == 1 && records[0].UserID == "new-rev" && records[0].Cause == domain.CauseReassign &&
+						records[0].ReplacedUserID != nil && *records[0].ReplacedUserID == "old-rev"
+				})).Return(nil).Once()
+				prQuery.On("GetReviewerIDs", inTx(mockedTx), "pr-1").Return([]string{"new-rev", "other-rev"}, nil).Once()
+			},
+			expectedResponse: &api.ReassignResponse{
+				Pr: api.PullRequest{
+					PullRequestId:     "pr-1",
+					AuthorId:          "author-1",
+					Status:            api.PullRequestStatusOPEN,
+					AssignedReviewers: []string{"new-rev", "other-rev"},
+				},
+				ReplacedBy: "new-rev",
+			},
+		},
+		{
+			name:          "Reviewer of another primary team is replaced from the team of the author",
+			prID:          "pr-1",
+			oldReviewerID: "old-rev",
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
+				_, mockedTx, smock := newMockDBAndTx(t)
+				smock.ExpectCommit()
+
+				// old-rev reviews for team 2 of the author, and team 1 is the primary team of old-rev.
+				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+				prCmd.On("GetPRByIDWithLock", inTx(mockedTx), "pr-1").Return(prInDB, nil).Once()
+				prQuery.On("GetReviewerIDs", inTx(mockedTx), "pr-1").Return([]string{"old-rev", "other-rev"}, nil).Once()
+				userPR.On("GetPRTeamID", mock.Anything, "pr-1").Return(2, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 2, mock.Anything, 1).Return([]string{"new-rev"}, nil).Once()
+				prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"new-rev"}).Return([]string{"new-rev"}, nil).Once()
+				prCmd.On("ReplaceReviewer", inTx(mockedTx), "pr-1", "old-rev", "new-rev").Return(nil).Once()
+				history.On("RecordAssignments", inTx(mockedTx), mock.Anything).Return(nil).Once()
+				prQuery.On("GetReviewerIDs", inTx(mockedTx), "pr-1").Return([]string{"new-rev", "other-rev"}, nil).Once()
+			},
+			expectedResponse: &api.ReassignResponse{
+				Pr: api.PullRequest{
+					PullRequestId:     "pr-1",
+					AuthorId:          "author-1",
+					Status:            api.PullRequestStatusOPEN,
+					AssignedReviewers: []string{"new-rev", "other-rev"},
+				},
+				ReplacedBy: "new-rev",
+			},
+		},
+		{
+			name:          "Candidate deactivated since its selection is skipped",
+			prID:          "pr-1",
+			oldReviewerID: "old-rev",
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
+				_, mockedTx, smock := newMockDBAndTx(t)
+				smock.ExpectCommit()
+
+				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+				prCmd.On("GetPRByIDWithLock", inTx(mockedTx), "pr-1").Return(prInDB, nil).Once()
+				prQuery.On("GetReviewerIDs", inTx(mockedTx), "pr-1").Return([]string{"old-rev", "other-rev"}, nil).Once()
+				userPR.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, mock.MatchedBy(func(ids []string) bool {
+					return !slices.Contains(ids, "gone-rev")
+				}), 1).Return([]string{"gone-rev"}, nil).Once()
+				prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"gone-rev"}).Return([]string{}, nil).Once()
+				// The deactivated candidate is excluded from the next selection.
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, mock.MatchedBy(func(ids []string) bool {
+					return slices.Contains(ids, "gone-rev")
+				}), 1).Return([]string{"new-rev"}, nil).Once()
+				prCmd.On("LockActiveUsers", inTx(mockedTx), []string{"new-rev"}).Return([]string{"new-rev"}, nil).Once()
+				prCmd.On("ReplaceReviewer", inTx(mockedTx), "pr-1", "old-rev", "new-rev").Return(nil).Once()
+				history.On("RecordAssignments", inTx(mockedTx), mock.Anything).Return(nil).Once()
+				prQuery.On("GetReviewerIDs", inTx(mockedTx), "pr-1").Return([]string{"new-rev", "other-rev"}, nil).Once()
 			},
 			expectedResponse: &api.ReassignResponse{
 				Pr: api.PullRequest{
@@ -282,7 +963,7 @@ func TestPullRequestServiceImpl_ReassignReviewer(t *testing.T) {
 			name:          "Failure - PR is merged",
 			prID:          "pr-merged",
 			oldReviewerID: "old-rev",
-			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock) {
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
 				_, mockedTx, smock := newMockDBAndTx(t)
 				smock.ExpectRollback()
 
@@ -290,21 +971,37 @@ func TestPullRequestServiceImpl_ReassignReviewer(t *testing.T) {
 				mergedPR := *prInDB
 				mergedPR.ID = "pr-merged"
 				mergedPR.Status = api.PullRequestStatusMERGED
-				prCmd.On("GetPRByIDWithLock", mock.Anything, mockedTx, "pr-merged").Return(&mergedPR, nil).Once()
+				prCmd.On("GetPRByIDWithLock", inTx(mockedTx), "pr-merged").Return(&mergedPR, nil).Once()
 			},
 			expectedErrorIs: apperrors.ErrPRMerged,
 		},
+		{
+			name:          "Failure - PR is closed",
+			prID:          "pr-closed",
+			oldReviewerID: "old-rev",
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
+				_, mockedTx, smock := newMockDBAndTx(t)
+				smock.ExpectRollback()
+
+				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+				closedPR := *prInDB
+				closedPR.ID = "pr-closed"
+				closedPR.Status = api.PullRequestStatusCLOSED
+				prCmd.On("GetPRByIDWithLock", inTx(mockedTx), "pr-closed").Return(&closedPR, nil).Once()
+			},
+			expectedErrorIs: apperrors.ErrPRClosed,
+		},
 		{
 			name:          "Failure - Reviewer not assigned",
 			prID:          "pr-1",
 			oldReviewerID: "not-assigned-rev",
-			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock) {
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
 				_, mockedTx, smock := newMockDBAndTx(t)
 				smock.ExpectRollback()
 
 				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
-				prCmd.On("GetPRByIDWithLock", mock.Anything, mockedTx, "pr-1").Return(prInDB, nil).Once()
-				prQuery.On("GetReviewerIDs", mock.Anything, mockedTx, "pr-1").Return([]string{"old-rev", "other-rev"}, nil).Once()
+				prCmd.On("GetPRByIDWithLock", inTx(mockedTx), "pr-1").Return(prInDB, nil).Once()
+				prQuery.On("GetReviewerIDs", inTx(mockedTx), "pr-1").Return([]string{"old-rev", "other-rev"}, nil).Once()
 			},
 			expectedErrorIs: apperrors.ErrReviewerNotAssigned,
 		},
@@ -312,15 +1009,35 @@ func TestPullRequestServiceImpl_ReassignReviewer(t *testing.T) {
 			name:          "Failure - No candidate for replacement",
 			prID:          "pr-1",
 			oldReviewerID: "old-rev",
-			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock) {
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
+				_, mockedTx, smock := newMockDBAndTx(t)
+				smock.ExpectRollback()
+
+				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+				prCmd.On("GetPRByIDWithLock", inTx(mockedTx), "pr-1").Return(prInDB, nil).Once()
+				prQuery.On("GetReviewerIDs", inTx(mockedTx), "pr-1").Return([]string{"old-rev", "other-rev"}, nil).Once()
+				userPR.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, mock.Anything, 1).Return([]string{}, nil).Once()
+				userPR.On("GetReplacementAlternatives", mock.Anything, 1, mock.Anything, maxReplacementAlternatives).
+					Return([]domain.ReplacementAlternative{}, nil).Once()
+			},
+			expectedErrorIs: apperrors.ErrNoCandidate,
+		},
+		{
+			name:          "Failure - No candidate even if alternatives are unavailable",
+			prID:          "pr-1",
+			oldReviewerID: "old-rev",
+			setupMocks: func(transactor *TransactorMock, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, userPR *UserPRRepositoryMock, history *AssignmentHistoryRepositoryMock) {
 				_, mockedTx, smock := newMockDBAndTx(t)
 				smock.ExpectRollback()
 
 				transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
-				prCmd.On("GetPRByIDWithLock", mock.Anything, mockedTx, "pr-1").Return(prInDB, nil).Once()
-				prQuery.On("GetReviewerIDs", mock.Anything, mockedTx, "pr-1").Return([]string{"old-rev", "other-rev"}, nil).Once()
-				userPR.On("GetReviewerTeamID", ctx, "old-rev").Return(1, nil).Once()
-				userPR.On("GetRandomActiveReviewers", ctx, 1, mock.Anything, 1).Return([]string{}, nil).Once()
+				prCmd.On("GetPRByIDWithLock", inTx(mockedTx), "pr-1").Return(prInDB, nil).Once()
+				prQuery.On("GetReviewerIDs", inTx(mockedTx), "pr-1").Return([]string{"old-rev", "other-rev"}, nil).Once()
+				userPR.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil).Once()
+				userPR.On("GetRandomActiveReviewers", mock.Anything, 1, mock.Anything, 1).Return([]string{}, nil).Once()
+				userPR.On("GetReplacementAlternatives", mock.Anything, 1, mock.Anything, maxReplacementAlternatives).
+					Return(nil, errors.New("db error")).Once()
 			},
 			expectedErrorIs: apperrors.ErrNoCandidate,
 		},
@@ -332,10 +1049,11 @@ func TestPullRequestServiceImpl_ReassignReviewer(t *testing.T) {
 			prCmdMock := new(PRCommandRepositoryMock)
 			prQueryMock := new(PRQueryRepositoryMock)
 			userPRMock := new(UserPRRepositoryMock)
-			tc.setupMocks(transactorMock, prCmdMock, prQueryMock, userPRMock)
+			historyMock := new(AssignmentHistoryRepositoryMock)
+			tc.setupMocks(transactorMock, prCmdMock, prQueryMock, userPRMock, historyMock)
 
-			service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, userPRMock)
-			resp, err := service.ReassignReviewer(ctx, tc.prID, tc.oldReviewerID)
+			service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, userPRMock, nil, historyMock)
+			resp, err := service.ReassignReviewer(ctx, tc.prID, tc.oldReviewerID, "")
 
 			if tc.expectedErrorIs != nil {
 				assert.Error(t, err)
@@ -351,10 +1069,92 @@ func TestPullRequestServiceImpl_ReassignReviewer(t *testing.T) {
 			prCmdMock.AssertExpectations(t)
 			prQueryMock.AssertExpectations(t)
 			userPRMock.AssertExpectations(t)
+			historyMock.AssertExpectations(t)
 		})
 	}
 }
 
+func TestPullRequestServiceImpl_ReassignReviewer_NoCandidateAlternatives(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	transactorMock := new(TransactorMock)
+	prCmdMock := new(PRCommandRepositoryMock)
+	prQueryMock := new(PRQueryRepositoryMock)
+	userPRMock := new(UserPRRepositoryMock)
+
+	_, mockedTx, smock := newMockDBAndTx(t)
+	smock.ExpectRollback()
+
+	alternatives := []domain.ReplacementAlternative{
+		{UserID: "u4", Username: "Dave", TeamName: "backend", Reason: domain.AlternativeInactive},
+		{UserID: "u7", Username: "Grace", TeamName: "frontend", Reason: domain.AlternativeOtherTeam, OpenReviews: 1},
+	}
+
+	transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+	prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), "pr-1").
+		Return(&domain.PullRequest{ID: "pr-1", AuthorID: "author-1", Status: api.PullRequestStatusOPEN}, nil).Once()
+	prQueryMock.On("GetReviewerIDs", inTx(mockedTx), "pr-1").Return([]string{"old-rev", "other-rev"}, nil).Once()
+	userPRMock.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil).Once()
+	userPRMock.On("GetRandomActiveReviewers", mock.Anything, 1, mock.Anything, 1).Return([]string{}, nil).Once()
+	// The author and the current reviewers are not offered as alternatives either.
+	userPRMock.On("GetReplacementAlternatives", mock.Anything, 1, mock.MatchedBy(func(ids []string) bool {
+		return assert.ElementsMatch(t, []string{"author-1", "old-rev", "other-rev"}, ids)
+	}), maxReplacementAlternatives).Return(alternatives, nil).Once()
+
+	service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, userPRMock, nil, nil)
+	_, err := service.ReassignReviewer(ctx, "pr-1", "old-rev", "")
+
+	var noCandErr *apperrors.NoCandidateError
+	require.ErrorAs(t, err, &noCandErr)
+	assert.ErrorIs(t, err, apperrors.ErrNoCandidate)
+	assert.Equal(t, "pr-1", noCandErr.PRID)
+	assert.Equal(t, alternatives, noCandErr.Alternatives)
+
+	userPRMock.AssertExpectations(t)
+	require.NoError(t, smock.ExpectationsWereMet())
+}
+
+func TestPullRequestServiceImpl_ReassignReviewer_HandoffNote(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	transactorMock := new(TransactorMock)
+	prCmdMock := new(PRCommandRepositoryMock)
+	prQueryMock := new(PRQueryRepositoryMock)
+	userPRMock := new(UserPRRepositoryMock)
+	historyMock := new(AssignmentHistoryRepositoryMock)
+	notifierMock := new(NotifierMock)
+
+	_, mockedTx, smock := newMockDBAndTx(t)
+	smock.ExpectCommit()
+
+	transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+	prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), "pr-1").
+		Return(&domain.PullRequest{ID: "pr-1", AuthorID: "author-1", Status: api.PullRequestStatusOPEN}, nil).Once()
+	prQueryMock.On("GetReviewerIDs", inTx(mockedTx), "pr-1").Return([]string{"old-rev", "other-rev"}, nil).Once()
+	userPRMock.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil).Once()
+	userPRMock.On("GetRandomActiveReviewers", mock.Anything, 1, mock.Anything, 1).Return([]string{"new-rev"}, nil).Once()
+	prCmdMock.On("LockActiveUsers", inTx(mockedTx), []string{"new-rev"}).Return([]string{"new-rev"}, nil).Once()
+	prCmdMock.On("ReplaceReviewer", inTx(mockedTx), "pr-1", "old-rev", "new-rev").Return(nil).Once()
+	// The note is stored trimmed with the assignment of the new reviewer.
+	historyMock.On("RecordAssignments", inTx(mockedTx), mock.MatchedBy(func(records []domain.AssignmentRecord) bool {
+		return len(records) == 1 && records[0].HandoffNote != nil && *records[0].HandoffNote == "handlers left"
+	})).Return(nil).Once()
+	prQueryMock.On("GetReviewerIDs", inTx(mockedTx), "pr-1").Return([]string{"new-rev", "other-rev"}, nil).Once()
+	notifierMock.On("Notify", mock.Anything, mock.MatchedBy(func(event domain.Event) bool {
+		return event.Type == domain.EventReviewerReassigned && assert.ObjectsAreEqual([]string{"new-rev"}, event.UserIDs) &&
+			event.HandoffNote != nil && *event.HandoffNote == "handlers left"
+	})).Once()
+
+	service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, userPRMock, nil, historyMock, WithNotifier(notifierMock))
+	_, err := service.ReassignReviewer(ctx, "pr-1", "old-rev", "  handlers left\n")
+
+	require.NoError(t, err)
+	historyMock.AssertExpectations(t)
+	notifierMock.AssertExpectations(t)
+}
+
 func TestPullRequestServiceImpl_GetReviewAssignments(t *testing.T) {
 	ctx := context.Background()
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
@@ -373,7 +1173,7 @@ func TestPullRequestServiceImpl_GetReviewAssignments(t *testing.T) {
 					{ID: "pr-1", Name: "Feature A", AuthorID: "author-A", Status: api.PullRequestStatusOPEN},
 					{ID: "pr-2", Name: "Fix B", AuthorID: "author-B", Status: api.PullRequestStatusMERGED},
 				}
-				prQuery.On("GetReviewAssignments", ctx, userID).Return(prs, nil).Once()
+				prQuery.On("GetReviewAssignments", ctx, userID, map[string]string(nil)).Return(prs, nil).Once()
 			},
 			expectedResp: &api.GetReviewResponse{
 				UserId: userID,
@@ -386,7 +1186,7 @@ func TestPullRequestServiceImpl_GetReviewAssignments(t *testing.T) {
 		{
 			name: "Success - User has no assignments",
 			setupMocks: func(prQuery *PRQueryRepositoryMock) {
-				prQuery.On("GetReviewAssignments", ctx, userID).Return([]domain.PullRequest{}, nil).Once()
+				prQuery.On("GetReviewAssignments", ctx, userID, map[string]string(nil)).Return([]domain.PullRequest{}, nil).Once()
 			},
 			expectedResp: &api.GetReviewResponse{
 				UserId:       userID,
@@ -396,7 +1196,7 @@ func TestPullRequestServiceImpl_GetReviewAssignments(t *testing.T) {
 		{
 			name: "Failure - Repository returns error",
 			setupMocks: func(prQuery *PRQueryRepositoryMock) {
-				prQuery.On("GetReviewAssignments", ctx, userID).Return(nil, errors.New("database connection failed")).Once()
+				prQuery.On("GetReviewAssignments", ctx, userID, map[string]string(nil)).Return(nil, errors.New("database connection failed")).Once()
 			},
 			expectedError: true,
 		},
@@ -407,8 +1207,8 @@ func TestPullRequestServiceImpl_GetReviewAssignments(t *testing.T) {
 			prQueryMock := new(PRQueryRepositoryMock)
 			tc.setupMocks(prQueryMock)
 
-			service := NewPullRequestService(nil, logger, nil, prQueryMock, nil)
-			resp, err := service.GetReviewAssignments(ctx, userID)
+			service := NewPullRequestService(nil, logger, nil, prQueryMock, nil, nil, nil)
+			resp, err := service.GetReviewAssignments(ctx, userID, nil)
 
 			if tc.expectedError {
 				assert.Error(t, err)
@@ -423,29 +1223,576 @@ func TestPullRequestServiceImpl_GetReviewAssignments(t *testing.T) {
 	}
 }
 
+func TestPullRequestServiceImpl_GetReviewAssignments_ReadCache(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	userID := "user-with-reviews"
+
+	mockDB, smock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	db := txctx.NewManager(sqlx.NewDb(mockDB, "sqlmock"), logger)
+
+	prQueryMock := new(PRQueryRepositoryMock)
+	prQueryMock.On("GetReviewAssignments", ctx, userID, map[string]string(nil)).
+		Return([]domain.PullRequest{{ID: "pr-1", Name: "Feature A", AuthorID: "author-A", Status: api.PullRequestStatusOPEN}}, nil).Once()
+	prQueryMock.On("GetReviewAssignments", ctx, userID, map[string]string{"area": "billing"}).
+		Return([]domain.PullRequest{}, nil).Once()
+
+	service := NewPullRequestService(db, logger, nil, prQueryMock, nil, nil, nil, WithReadCache(localcache.New(10, time.Minute)))
+
+	for range 2 {
+		resp, err := service.GetReviewAssignments(ctx, userID, nil)
+		require.NoError(t, err)
+		assert.Len(t, resp.PullRequests, 1)
+	}
+
+	resp, err := service.GetReviewAssignments(ctx, userID, []string{"area:billing"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.PullRequests, "the custom field filters are part of the key")
+
+	// A read-only transaction leaves the cache as it is.
+	smock.ExpectBegin()
+	smock.ExpectCommit()
+	require.NoError(t, service.readSnapshot(ctx, "test", func(context.Context) error { return nil }))
+
+	resp, err = service.GetReviewAssignments(ctx, userID, nil)
+	require.NoError(t, err)
+	assert.Len(t, resp.PullRequests, 1)
+
+	// A committed write invalidates it.
+	smock.ExpectBegin()
+	smock.ExpectCommit()
+	require.NoError(t, service.transaction(ctx, "test", func(context.Context) error { return nil }))
+
+	prQueryMock.On("GetReviewAssignments", ctx, userID, map[string]string(nil)).Return([]domain.PullRequest{}, nil).Once()
+
+	resp, err = service.GetReviewAssignments(ctx, userID, nil)
+	require.NoError(t, err)
+	assert.Empty(t, resp.PullRequests)
+
+	// A rolled back write does not.
+	smock.ExpectBegin()
+	smock.ExpectRollback()
+	require.Error(t, service.transaction(ctx, "test", func(context.Context) error { return errors.New("conflict") }))
+
+	resp, err = service.GetReviewAssignments(ctx, userID, nil)
+	require.NoError(t, err)
+	assert.Empty(t, resp.PullRequests)
+
+	prQueryMock.AssertExpectations(t)
+	require.NoError(t, smock.ExpectationsWereMet())
+}
+
+func TestPullRequestServiceImpl_TransactionRetriesConflicts(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	deadlock := &pq.Error{Code: "40P01", Message: "deadlock detected"}
+
+	mockDB, smock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	service := NewPullRequestService(txctx.NewManager(sqlx.NewDb(mockDB, "sqlmock"), logger), logger, nil, nil, nil, nil, nil)
+
+	// A deadlock victim is run again and commits.
+	smock.ExpectBegin()
+	smock.ExpectRollback()
+	smock.ExpectBegin()
+	smock.ExpectCommit()
+
+	var runs int
+	err = service.transaction(ctx, "test", func(context.Context) error {
+		runs++
+		if runs == 1 {
+			return fmt.Errorf("failed to lock pr: %w", deadlock)
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, runs)
+
+	// A serialization failure on commit is retried too, until the attempts run out.
+	for range defaultTxMaxAttempts {
+		smock.ExpectBegin()
+		smock.ExpectCommit().WillReturnError(&pq.Error{Code: "40001", Message: "could not serialize access"})
+	}
+
+	runs = 0
+	err = service.transaction(ctx, "test", func(context.Context) error {
+		runs++
+		return nil
+	})
+	var pqErr *pq.Error
+	require.ErrorAs(t, err, &pqErr)
+	assert.Equal(t, pq.ErrorCode("40001"), pqErr.Code)
+	assert.Equal(t, defaultTxMaxAttempts, runs)
+
+	// Other errors are returned at once.
+	smock.ExpectBegin()
+	smock.ExpectRollback()
+
+	runs = 0
+	err = service.transaction(ctx, "test", func(context.Context) error {
+		runs++
+		return apperrors.ErrPRMerged
+	})
+	require.ErrorIs(t, err, apperrors.ErrPRMerged)
+	assert.Equal(t, 1, runs)
+
+	// A transaction joined to the caller's is not run again on its own: the caller runs its transaction again.
+	smock.ExpectBegin()
+	smock.ExpectRollback()
+	smock.ExpectBegin()
+	smock.ExpectCommit()
+
+	var outerRuns, innerRuns int
+	err = service.transaction(ctx, "outer", func(ctx context.Context) error {
+		outerRuns++
+
+		return service.transaction(ctx, "inner", func(context.Context) error {
+			innerRuns++
+			if innerRuns == 1 {
+				return deadlock
+			}
+
+			return nil
+		})
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, outerRuns)
+	assert.Equal(t, 2, innerRuns)
+
+	// A single attempt disables the retries.
+	service = NewPullRequestService(txctx.NewManager(sqlx.NewDb(mockDB, "sqlmock"), logger), logger, nil, nil, nil, nil, nil, WithTxMaxAttempts(1))
+
+	smock.ExpectBegin()
+	smock.ExpectRollback()
+
+	err = service.transaction(ctx, "test", func(context.Context) error { return deadlock })
+	require.ErrorIs(t, err, deadlock)
+	require.NoError(t, smock.ExpectationsWereMet())
+}
+
+func TestPullRequestServiceImpl_SearchPRs(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	found := []domain.PullRequest{{ID: "pr-1", Name: "Add search", AuthorID: "u1", Status: api.PullRequestStatusOPEN}}
+	foundShort := []api.PullRequestShort{
+		{PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1", Status: api.PullRequestShortStatusOPEN},
+	}
+	total := 11
+	nextCursor := "MTA"
+
+	testCases := []struct {
+		name            string
+		query           PRSearchQuery
+		setupMocks      func(prQuery *PRQueryRepositoryMock)
+		expectedResp    *api.SearchPullRequestsResponse
+		expectedErrorIs error
+	}{
+		{
+			name:  "Success",
+			query: PRSearchQuery{Query: "  add search ", Status: "OPEN", Limit: 10, Offset: 10},
+			setupMocks: func(prQuery *PRQueryRepositoryMock) {
+				filter := domain.PRSearchFilter{Query: "add search", Status: api.PullRequestStatusOPEN, Limit: 10, Offset: 10}
+				prQuery.On("SearchPRs", ctx, filter).Return(found, total, nil).Once()
+			},
+			expectedResp: &api.SearchPullRequestsResponse{Items: foundShort, PullRequests: foundShort, Total: total, TotalEstimate: &total},
+		},
+		{
+			name:  "Success - Page with a next page",
+			query: PRSearchQuery{Query: "search", Limit: 1, Cursor: "OQ"},
+			setupMocks: func(prQuery *PRQueryRepositoryMock) {
+				filter := domain.PRSearchFilter{Query: "search", Limit: 1, Offset: 9}
+				prQuery.On("SearchPRs", ctx, filter).Return(found, total, nil).Once()
+			},
+			expectedResp: &api.SearchPullRequestsResponse{
+				Items: foundShort, PullRequests: foundShort, Total: total, TotalEstimate: &total, NextCursor: &nextCursor,
+			},
+		},
+		{
+			name:            "Failure - Blank query",
+			query:           PRSearchQuery{Query: "   ", Limit: 20},
+			expectedErrorIs: apperrors.ErrValidation,
+		},
+		{
+			name:            "Failure - Unknown status",
+			query:           PRSearchQuery{Query: "search", Status: "DRAFT", Limit: 20},
+			expectedErrorIs: apperrors.ErrValidation,
+		},
+		{
+			name:            "Failure - Limit out of range",
+			query:           PRSearchQuery{Query: "search", Limit: maxSearchLimit + 1},
+			expectedErrorIs: apperrors.ErrValidation,
+		},
+		{
+			name:            "Failure - Negative offset",
+			query:           PRSearchQuery{Query: "search", Limit: 20, Offset: -1},
+			expectedErrorIs: apperrors.ErrValidation,
+		},
+		{
+			name:            "Failure - Cursor with offset",
+			query:           PRSearchQuery{Query: "search", Limit: 20, Offset: 20, Cursor: "OQ"},
+			expectedErrorIs: apperrors.ErrValidation,
+		},
+		{
+			name:            "Failure - Malformed cursor",
+			query:           PRSearchQuery{Query: "search", Limit: 20, Cursor: "not a cursor"},
+			expectedErrorIs: apperrors.ErrValidation,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prQueryMock := new(PRQueryRepositoryMock)
+			if tc.setupMocks != nil {
+				tc.setupMocks(prQueryMock)
+			}
+
+			service := NewPullRequestService(nil, logger, nil, prQueryMock, nil, nil, nil)
+			resp, err := service.SearchPRs(ctx, tc.query)
+
+			if tc.expectedErrorIs != nil {
+				assert.True(t, errors.Is(err, tc.expectedErrorIs))
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedResp, resp)
+			}
+
+			prQueryMock.AssertExpectations(t)
+		})
+	}
+}
+
 func TestPullRequestServiceImpl_GetStats(t *testing.T) {
 	ctx := context.Background()
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	transactorMock := new(TransactorMock)
 	prQueryMock := new(PRQueryRepositoryMock)
 
-	service := NewPullRequestService(nil, logger, nil, prQueryMock, nil)
+	service := NewPullRequestService(transactorMock, logger, nil, prQueryMock, nil, nil, nil)
 
+	firstReviewAvg, firstReviewP50, firstReviewP90 := 5400.0, 3600.0, 14400.0
 	domainStats := []domain.Stats{
-		{UserID: "u1", Username: "Alice", OpenReviews: 1, MergedReviews: 10},
+		{
+			UserID: "u1", Username: "Alice", OpenReviews: 1, MergedReviews: 10,
+			FirstReviews: 4, FirstReviewAvgSeconds: &firstReviewAvg, FirstReviewP50Seconds: &firstReviewP50, FirstReviewP90Seconds: &firstReviewP90,
+		},
 	}
-	prQueryMock.On("GetUserStats", ctx).Return(domainStats, nil).Once()
+	teamStats := []domain.TeamStats{
+		{TeamName: "backend", FirstReviewedPRs: 4, FirstReviewAvgSeconds: &firstReviewAvg, FirstReviewP50Seconds: &firstReviewP50, FirstReviewP90Seconds: &firstReviewP90},
+	}
+
+	_, mockedTx, smock := newMockDBAndTx(t)
+	smock.ExpectCommit()
 
-	statsResp, err := service.GetStats(ctx)
+	// The statistics are read in a read-only snapshot rather than with separate statements.
+	transactorMock.On("BeginTxx", mock.Anything, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}).Return(mockedTx, nil).Once()
+	prQueryMock.On("GetUserStats", inTx(mockedTx), domain.StatsPeriod{}).Return(domainStats, nil).Once()
+	prQueryMock.On("GetTeamStats", inTx(mockedTx), domain.StatsPeriod{}).Return(teamStats, nil).Once()
+
+	statsResp, err := service.GetStats(ctx, domain.StatsPeriod{})
 	require.NoError(t, err)
 	require.NotNil(t, statsResp)
 	require.Len(t, statsResp.UserStats, 1)
-	assert.Equal(t, "u1", statsResp.UserStats[0].UserId)
-	assert.Equal(t, 10, statsResp.UserStats[0].MergedReviews)
+	assert.Equal(t, "u1", statsResp.Items[0].UserId)
+	assert.Equal(t, 10, statsResp.Items[0].MergedReviews)
+	assert.Equal(t, &api.DurationStats{Count: 4, AvgSeconds: 5400, P50Seconds: 3600, P90Seconds: 14400}, statsResp.Items[0].TimeToFirstReview)
+	assert.Nil(t, statsResp.Items[0].TimeToMerge, "the merge durations are missing")
+	assert.Equal(t, statsResp.Items, statsResp.UserStats)
+	require.Len(t, statsResp.TeamStats, 1)
+	assert.Equal(t, "backend", statsResp.TeamStats[0].TeamName)
+	assert.Equal(t, statsResp.Items[0].TimeToFirstReview, statsResp.TeamStats[0].TimeToFirstReview)
+	assert.Nil(t, statsResp.TeamStats[0].TimeToMerge, "no pull request of the team was merged")
+	prQueryMock.AssertExpectations(t)
+	require.NoError(t, smock.ExpectationsWereMet())
+
+	_, mockedTx, smock = newMockDBAndTx(t)
+	smock.ExpectRollback()
+
+	transactorMock.On("BeginTxx", mock.Anything, mock.Anything).Return(mockedTx, nil).Once()
+	prQueryMock.On("GetUserStats", inTx(mockedTx), domain.StatsPeriod{}).Return(nil, errors.New("db error")).Once()
+
+	_, err = service.GetStats(ctx, domain.StatsPeriod{})
+	require.Error(t, err)
+	prQueryMock.AssertExpectations(t)
+	transactorMock.AssertExpectations(t)
+	require.NoError(t, smock.ExpectationsWereMet())
+
+	from := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	_, err = service.GetStats(ctx, domain.StatsPeriod{From: &from, To: &from})
+	assert.ErrorIs(t, err, apperrors.ErrValidation, "the period must not be empty")
+}
+
+func TestPullRequestServiceImpl_GetStats_StatsView(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	transactorMock := new(TransactorMock)
+	prQueryMock := new(PRQueryRepositoryMock)
+	statsViewMock := new(StatsViewRepositoryMock)
+
+	service := NewPullRequestService(transactorMock, logger, nil, prQueryMock, nil, nil, nil, WithStatsView(statsViewMock))
+
+	refreshedAt := time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC)
+	mergeAvg := 7200.0
+
+	_, mockedTx, smock := newMockDBAndTx(t)
+	smock.ExpectCommit()
+
+	transactorMock.On("BeginTxx", mock.Anything, mock.Anything).Return(mockedTx, nil).Once()
+	statsViewMock.On("GetStatsView", inTx(mockedTx)).Return(&domain.StatsView{
+		Users:       []domain.Stats{{UserID: "u1", Username: "Alice", MergedReviews: 3, MergeAvgSeconds: &mergeAvg, MergeP50Seconds: &mergeAvg, MergeP90Seconds: &mergeAvg}},
+		Teams:       []domain.TeamStats{{TeamName: "backend", MergedPRs: 3, MergeAvgSeconds: &mergeAvg, MergeP50Seconds: &mergeAvg, MergeP90Seconds: &mergeAvg}},
+		RefreshedAt: refreshedAt,
+	}, nil).Once()
+
+	statsResp, err := service.GetStats(ctx, domain.StatsPeriod{})
+	require.NoError(t, err)
+	require.Len(t, statsResp.Items, 1)
+	assert.Equal(t, 3, statsResp.Items[0].MergedReviews)
+	assert.Equal(t, &api.DurationStats{Count: 3, AvgSeconds: 7200, P50Seconds: 7200, P90Seconds: 7200}, statsResp.TeamStats[0].TimeToMerge)
+	assert.Equal(t, &refreshedAt, statsResp.RefreshedAt)
+	require.NoError(t, smock.ExpectationsWereMet())
+
+	// A bounded period cannot be precomputed, so it is computed from the current data.
+	from := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	period := domain.StatsPeriod{From: &from}
+
+	_, mockedTx, smock = newMockDBAndTx(t)
+	smock.ExpectCommit()
+
+	transactorMock.On("BeginTxx", mock.Anything, mock.Anything).Return(mockedTx, nil).Once()
+	prQueryMock.On("GetUserStats", inTx(mockedTx), period).Return([]domain.Stats{}, nil).Once()
+	prQueryMock.On("GetTeamStats", inTx(mockedTx), period).Return([]domain.TeamStats{}, nil).Once()
+
+	statsResp, err = service.GetStats(ctx, period)
+	require.NoError(t, err)
+	assert.Nil(t, statsResp.RefreshedAt)
+	require.NoError(t, smock.ExpectationsWereMet())
+
+	statsViewMock.AssertExpectations(t)
 	prQueryMock.AssertExpectations(t)
+}
+
+func TestPullRequestServiceImpl_RefreshStats(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	statsViewMock := new(StatsViewRepositoryMock)
+
+	refreshed, err := NewPullRequestService(nil, logger, nil, nil, nil, nil, nil).RefreshStats(ctx)
+	require.NoError(t, err)
+	assert.False(t, refreshed, "there is nothing to refresh without the stats view")
+
+	service := NewPullRequestService(nil, logger, nil, nil, nil, nil, nil, WithStatsView(statsViewMock))
 
-	prQueryMock.On("GetUserStats", ctx).Return(nil, errors.New("db error")).Once()
+	statsViewMock.On("RefreshStatsView", ctx).Return(false, nil).Once()
 
-	_, err = service.GetStats(ctx)
+	refreshed, err = service.RefreshStats(ctx)
+	require.NoError(t, err)
+	assert.False(t, refreshed, "another instance is refreshing")
+
+	statsViewMock.On("RefreshStatsView", ctx).Return(false, errors.New("db error")).Once()
+
+	_, err = service.RefreshStats(ctx)
 	require.Error(t, err)
+	statsViewMock.AssertExpectations(t)
+}
+
+func TestPullRequestServiceImpl_BackfillReviewerDurations(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	mockDB, smock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	prCmdMock := new(PRCommandRepositoryMock)
+	service := NewPullRequestService(txctx.NewManager(sqlx.NewDb(mockDB, "sqlmock"), logger), logger, prCmdMock, nil, nil, nil, nil)
+
+	_, _, err = service.BackfillReviewerDurations(ctx, "", maxStatsBackfillBatchSize+1)
+	require.ErrorIs(t, err, apperrors.ErrValidation)
+
+	// Every batch runs in a transaction of its own.
+	smock.ExpectBegin()
+	smock.ExpectCommit()
+	prCmdMock.On("BackfillReviewerDurations", mock.Anything, "pr-1", 2).Return("pr-3", 3, nil).Once()
+
+	lastID, updated, err := service.BackfillReviewerDurations(ctx, "pr-1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, "pr-3", lastID)
+	assert.Equal(t, 3, updated)
+
+	smock.ExpectBegin()
+	smock.ExpectRollback()
+	prCmdMock.On("BackfillReviewerDurations", mock.Anything, "pr-3", 2).Return("", 0, errors.New("db error")).Once()
+
+	_, _, err = service.BackfillReviewerDurations(ctx, "pr-3", 2)
+	require.Error(t, err)
+
+	prCmdMock.AssertExpectations(t)
+	require.NoError(t, smock.ExpectationsWereMet())
+}
+
+func TestPullRequestServiceImpl_GetLeaderboard(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	prQueryMock := new(PRQueryRepositoryMock)
+
+	// testNow is a Friday afternoon in UTC.
+	service := NewPullRequestService(nil, logger, nil, prQueryMock, nil, nil, nil, WithClock(fixedClock(testNow)))
+
+	weekStart := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+	weekEnd := time.Date(2025, time.March, 17, 0, 0, 0, 0, time.UTC)
+
+	prQueryMock.On("GetLeaderboard", ctx, domain.StatsPeriod{From: &weekStart, To: &weekEnd}, 3).Return([]domain.LeaderboardEntry{
+		{UserID: "u2", Username: "Bob", MergedReviews: 7},
+		{UserID: "u1", Username: "Alice", MergedReviews: 4},
+		{UserID: "u3", Username: "Carol", MergedReviews: 4},
+	}, nil).Once()
+
+	resp, err := service.GetLeaderboard(ctx, domain.LeaderboardWeek, 3)
+	require.NoError(t, err)
+	assert.Equal(t, api.LeaderboardWeek, resp.Period)
+	assert.Equal(t, weekStart, resp.From)
+	assert.Equal(t, weekEnd, resp.To)
+	require.Len(t, resp.Items, 3)
+	assert.Equal(t, api.LeaderboardEntry{Rank: 1, UserId: "u2", Username: "Bob", MergedReviews: 7}, resp.Items[0])
+	assert.Equal(t, 2, resp.Items[1].Rank)
+	assert.Equal(t, 2, resp.Items[2].Rank, "users with as many reviews share the rank")
+
+	monthStart := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	prQueryMock.On("GetLeaderboard", ctx, domain.StatsPeriod{From: &monthStart, To: &monthEnd}, 20).Return([]domain.LeaderboardEntry{}, nil).Once()
+
+	resp, err = service.GetLeaderboard(ctx, domain.LeaderboardMonth, 20)
+	require.NoError(t, err)
+	assert.Empty(t, resp.Items)
+	assert.Equal(t, monthEnd, resp.To)
+
+	// Monday 01:00 at UTC+3 is still Sunday in UTC, the last day of the previous week.
+	sunday := NewPullRequestService(nil, logger, nil, prQueryMock, nil, nil, nil,
+		WithClock(fixedClock(time.Date(2025, time.March, 17, 1, 0, 0, 0, time.FixedZone("UTC+3", 3*60*60)))))
+
+	prQueryMock.On("GetLeaderboard", ctx, domain.StatsPeriod{From: &weekStart, To: &weekEnd}, 1).Return([]domain.LeaderboardEntry{}, nil).Once()
+
+	resp, err = sunday.GetLeaderboard(ctx, domain.LeaderboardWeek, 1)
+	require.NoError(t, err)
+	assert.Equal(t, weekStart, resp.From)
+
+	_, err = service.GetLeaderboard(ctx, "year", 10)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	_, err = service.GetLeaderboard(ctx, domain.LeaderboardWeek, 101)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	prQueryMock.AssertExpectations(t)
+}
+
+func TestPullRequestServiceImpl_GetPR(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	prQueryMock := new(PRQueryRepositoryMock)
+
+	service := NewPullRequestService(nil, logger, nil, prQueryMock, nil, nil, nil)
+
+	prQueryMock.On("GetPRByIDWithReviewers", ctx, "pr-1").Return(&domain.PullRequest{
+		ID:          "pr-1",
+		Name:        "feat: search",
+		AuthorID:    "author-1",
+		Status:      api.PullRequestStatusOPEN,
+		ReviewerIDs: []string{"rev-1"},
+	}, nil).Once()
+
+	pr, err := service.GetPR(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, "pr-1", pr.PullRequestId)
+	assert.Equal(t, []string{"rev-1"}, pr.AssignedReviewers)
+	assert.Nil(t, pr.Reviewers)
+	assert.Nil(t, pr.AssignmentPolicy, "PRs created before the snapshots were stored have none")
+
+	prQueryMock.On("GetPRByIDWithReviewers", ctx, "missing").Return(nil, apperrors.ErrNotFound).Once()
+
+	_, err = service.GetPR(ctx, "missing")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	prQueryMock.AssertExpectations(t)
+}
+
+func TestPullRequestServiceImpl_ExpandReviewers(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	assignedAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Reasons come from the history", func(t *testing.T) {
+		historyMock := new(AssignmentHistoryRepositoryMock)
+		note := "handlers left"
+		historyMock.On("GetCurrentAssignments", ctx, "pr-1").Return([]domain.AssignmentRecord{
+			{PullRequestID: "pr-1", UserID: "rev-2", Strategy: domain.StrategyLeastLoaded, Reason: domain.ReasonLeastLoaded, HandoffNote: &note, CreatedAt: assignedAt},
+		}, nil).Once()
+
+		service := NewPullRequestService(nil, logger, nil, nil, nil, nil, historyMock)
+		pr := &api.PullRequest{PullRequestId: "pr-1", AssignedReviewers: []string{"rev-1", "rev-2"}}
+
+		require.NoError(t, service.ExpandReviewers(ctx, pr))
+		require.NotNil(t, pr.Reviewers)
+
+		reason := api.LeastLoaded
+		assert.Equal(t, []api.ReviewerAssignment{
+			{UserId: "rev-1"},
+			{UserId: "rev-2", Reason: &reason, AssignedAt: &assignedAt, HandoffNote: &note},
+		}, *pr.Reviewers)
+		historyMock.AssertExpectations(t)
+	})
+
+	t.Run("History error is returned", func(t *testing.T) {
+		historyMock := new(AssignmentHistoryRepositoryMock)
+		historyMock.On("GetCurrentAssignments", ctx, "pr-1").Return(nil, errors.New("db error")).Once()
+
+		service := NewPullRequestService(nil, logger, nil, nil, nil, nil, historyMock)
+		pr := &api.PullRequest{PullRequestId: "pr-1", AssignedReviewers: []string{"rev-1"}}
+
+		assert.Error(t, service.ExpandReviewers(ctx, pr))
+		assert.Nil(t, pr.Reviewers)
+		historyMock.AssertExpectations(t)
+	})
+}
+
+func TestPullRequestServiceImpl_GetAssignmentHistory(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	createdAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+	replacedAt := createdAt.Add(time.Hour)
+
+	prQueryMock := new(PRQueryRepositoryMock)
+	historyMock := new(AssignmentHistoryRepositoryMock)
+	service := NewPullRequestService(nil, logger, nil, prQueryMock, nil, nil, historyMock)
+
+	replaced, note := "rev-1", "handlers left"
+	prQueryMock.On("GetPRByID", ctx, "pr-1").Return(&domain.PullRequest{ID: "pr-1"}, nil).Once()
+	historyMock.On("ListAssignmentHistory", ctx, "pr-1").Return([]domain.AssignmentRecord{
+		{PullRequestID: "pr-1", UserID: "rev-1", Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, Cause: domain.CauseCreated, CreatedAt: createdAt},
+		{PullRequestID: "pr-1", UserID: "rev-2", ReplacedUserID: &replaced, Strategy: domain.StrategyLeastLoaded, Reason: domain.ReasonLeastLoaded, Cause: domain.CauseReassign, HandoffNote: &note, CreatedAt: replacedAt},
+		{PullRequestID: "pr-1", UserID: "rev-3", ReplacedUserID: &replaced, Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, CreatedAt: replacedAt},
+	}, nil).Once()
+
+	history, err := service.GetAssignmentHistory(ctx, "pr-1")
+	require.NoError(t, err)
+
+	created, reassign := api.CauseCreated, api.CauseReassign
+	assert.Equal(t, "pr-1", history.PullRequestId)
+	assert.Equal(t, []api.AssignmentHistoryEntry{
+		{UserId: "rev-1", Reason: api.HistoryReasonRandom, Cause: &created, AssignedAt: createdAt},
+		{UserId: "rev-2", ReplacedUserId: &replaced, Reason: api.HistoryReasonLeastLoaded, Cause: &reassign, HandoffNote: &note, AssignedAt: replacedAt},
+		{UserId: "rev-3", ReplacedUserId: &replaced, Reason: api.HistoryReasonRandom, AssignedAt: replacedAt},
+	}, history.Items, "replacements recorded before the causes have none")
+	require.NotNil(t, history.TotalEstimate)
+	assert.Equal(t, 3, *history.TotalEstimate)
+
+	prQueryMock.On("GetPRByID", ctx, "missing").Return(nil, apperrors.ErrNotFound).Once()
+
+	_, err = service.GetAssignmentHistory(ctx, "missing")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
 	prQueryMock.AssertExpectations(t)
+	historyMock.AssertExpectations(t)
 }
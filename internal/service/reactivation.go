@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// recordDeactivation records the users a team deactivation deactivated, so that ReactivateTeam can undo it.
+// A deactivation that deactivated nobody is not recorded: undoing it would do nothing.
+func (s *UserServiceImpl) recordDeactivation(ctx context.Context, teamID int, userIDs []string, jobID *int64) error {
+	if s.deactivations == nil || len(userIDs) == 0 {
+		return nil
+	}
+
+	err := s.deactivations.RecordTeamDeactivation(ctx, &domain.TeamDeactivation{
+		TeamID:    teamID,
+		JobID:     jobID,
+		CreatedAt: s.now(),
+		UserIDs:   userIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record deactivation: %w", err)
+	}
+
+	return nil
+}
+
+func (s *UserServiceImpl) ReactivateTeam(ctx context.Context, teamName string, restoreReviewers bool) (*api.ReactivateTeamResponse, error) {
+	const op = "internal.service.user.ReactivateTeam"
+	log := s.log.With(slog.String("op", op), slog.String("team_name", teamName))
+
+	if s.deactivations == nil {
+		return nil, fmt.Errorf("%w: team reactivation is disabled", apperrors.ErrValidation)
+	}
+
+	var (
+		activatedUserIDs []string
+		restores         []domain.AssignmentRecord
+	)
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		activatedUserIDs, restores = nil, nil
+
+		team, err := s.teamRepo.GetTeamByName(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		// The lock of the deactivations keeps a deactivation from starting while this one is undone.
+		locked, err := s.teamRepo.TryLockTeamForDeactivation(ctx, team.ID)
+		if err != nil {
+			return fmt.Errorf("failed to lock team: %w", err)
+		}
+
+		if !locked {
+			return apperrors.ErrDeactivationInProgress
+		}
+
+		deactivation, err := s.deactivations.GetLastTeamDeactivation(ctx, team.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get last deactivation: %w", err)
+		}
+
+		if deactivation.JobID != nil && s.jobs != nil {
+			job, err := s.jobs.GetDeactivationJob(ctx, *deactivation.JobID)
+			if err != nil {
+				return fmt.Errorf("failed to get deactivation job: %w", err)
+			}
+
+			// The batches left would take the reviews of the users back from them.
+			if job.Status == domain.DeactivationJobRunning {
+				return apperrors.ErrDeactivationInProgress
+			}
+		}
+
+		activatedUserIDs, err = s.repo.ActivateUsers(ctx, team.ID, deactivation.UserIDs)
+		if err != nil {
+			return fmt.Errorf("failed to activate users: %w", err)
+		}
+
+		if restoreReviewers && len(activatedUserIDs) > 0 {
+			restores, err = s.planRestores(ctx, activatedUserIDs, deactivation.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("failed to plan restored reviews: %w", err)
+			}
+
+			if err := s.applyReplacements(ctx, restores); err != nil {
+				return fmt.Errorf("failed to restore reviews: %w", err)
+			}
+		}
+
+		if err := s.deactivations.MarkTeamReactivated(ctx, deactivation.ID, s.now()); err != nil {
+			return fmt.Errorf("failed to mark deactivation undone: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("team deactivation undone", slog.Int("users", len(activatedUserIDs)), slog.Int("restored_reviews", len(restores)))
+
+	return &api.ReactivateTeamResponse{
+		ReactivatedUsersCount: len(activatedUserIDs),
+		RestoredReviews:       toAPIReviewerMoves(restores),
+	}, nil
+}
+
+// planRestores plans handing back to the reactivated users the reviews the deactivation since the given time
+// reassigned from them. Only the reviews of open pull requests still held by the user who took them over
+// are handed back; the others have changed hands since and stay as they are.
+func (s *UserServiceImpl) planRestores(ctx context.Context, userIDs []string, since time.Time) ([]domain.AssignmentRecord, error) {
+	records, err := s.history.ListReplacementsOf(ctx, userIDs, domain.CauseTeamDeactivated, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replacements: %w", err)
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	var takerIDs []string
+	for _, record := range records {
+		if !slices.Contains(takerIDs, record.UserID) {
+			takerIDs = append(takerIDs, record.UserID)
+		}
+	}
+
+	prs, err := s.prQuery.GetOpenPRsByReviewers(ctx, takerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open PRs: %w", err)
+	}
+
+	reviewers := make(map[string][]string, len(prs))
+	for _, pr := range prs {
+		reviewers[pr.ID] = pr.ReviewerIDs
+	}
+
+	var (
+		restores []domain.AssignmentRecord
+		at       = s.now()
+	)
+
+	for _, record := range records {
+		reviewerIDs, open := reviewers[record.PullRequestID]
+		originalID := *record.ReplacedUserID
+
+		if !open || !slices.Contains(reviewerIDs, record.UserID) || slices.Contains(reviewerIDs, originalID) {
+			continue
+		}
+
+		reviewerIDs[slices.Index(reviewerIDs, record.UserID)] = originalID
+		restores = append(restores, restoreRecord(record, at))
+	}
+
+	return restores, nil
+}
+
+// restoreRecord is the assignment history record of a review handed back to the reviewer replaced in record.
+func restoreRecord(record domain.AssignmentRecord, at time.Time) domain.AssignmentRecord {
+	restore := replacementRecord(record.PullRequestID, record.UserID, *record.ReplacedUserID, record.Strategy, domain.CauseTeamReactivated, at)
+	restore.Reason = domain.ReasonRestored
+
+	return restore
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserServiceImpl_ReactivateTeam(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	teamInDB := &domain.TeamWithMembers{ID: 1, Name: "test-team"}
+	deactivatedAt := testNow.Add(-24 * time.Hour)
+	jobID := int64(7)
+
+	replaced := func(prID, original, taker string) domain.AssignmentRecord {
+		return domain.AssignmentRecord{
+			PullRequestID: prID, UserID: taker, ReplacedUserID: &original,
+			Strategy: domain.StrategyRandom, Reason: domain.ReasonRandom, Cause: domain.CauseTeamDeactivated, CreatedAt: deactivatedAt,
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		restoreReviewers bool
+		disabled         bool
+		setupMocks       func(m *mocks, deactivations *TeamDeactivationRepositoryMock, jobs *DeactivationJobRepositoryMock)
+		expectedCount    int
+		expectedRestored []api.ReviewerMove
+		expectedError    error
+	}{
+		{
+			name:             "Success: Users reactivated and their reviews restored",
+			restoreReviewers: true,
+			setupMocks: func(m *mocks, deactivations *TeamDeactivationRepositoryMock, jobs *DeactivationJobRepositoryMock) {
+				_, tx, smock := newMockDBAndTx(t)
+				smock.ExpectCommit()
+				m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+				m.teamRepo.On("GetTeamByName", inTx(tx), "test-team").Return(teamInDB, nil).Once()
+				m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil).Once()
+				deactivations.On("GetLastTeamDeactivation", inTx(tx), 1).
+					Return(&domain.TeamDeactivation{ID: 3, TeamID: 1, JobID: &jobID, CreatedAt: deactivatedAt, UserIDs: []string{"u1", "u2", "u3"}}, nil).Once()
+				jobs.On("GetDeactivationJob", inTx(tx), jobID).Return(&domain.DeactivationJob{ID: jobID, Status: domain.DeactivationJobSucceeded}, nil).Once()
+				// u3 was activated again by hand since.
+				m.userRepo.On("ActivateUsers", inTx(tx), 1, []string{"u1", "u2", "u3"}).Return([]string{"u1", "u2"}, nil).Once()
+				m.historyRepo.On("ListReplacementsOf", inTx(tx), []string{"u1", "u2"}, domain.CauseTeamDeactivated, deactivatedAt).
+					Return([]domain.AssignmentRecord{
+						replaced("pr-1", "u1", "u4"),
+						// pr-2 has been reassigned again since.
+						replaced("pr-2", "u1", "u5"),
+						// pr-3 has been merged.
+						replaced("pr-3", "u2", "u4"),
+						replaced("pr-4", "u2", "u5"),
+					}, nil).Once()
+				m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{"u4", "u5"}).Return([]domain.PullRequest{
+					{ID: "pr-1", ReviewerIDs: []string{"u4", "u6"}},
+					{ID: "pr-2", ReviewerIDs: []string{"u4"}},
+					{ID: "pr-4", ReviewerIDs: []string{"u5"}},
+				}, nil).Once()
+				m.prCmdRepo.On("ReplaceReviewers", inTx(tx), []domain.ReviewerReplacement{
+					{PullRequestID: "pr-1", OldReviewerID: "u4", NewReviewerID: "u1"},
+					{PullRequestID: "pr-4", OldReviewerID: "u5", NewReviewerID: "u2"},
+				}).Return(nil).Once()
+				m.historyRepo.On("RecordAssignments", inTx(tx), mock.MatchedBy(func(records []domain.AssignmentRecord) bool {
+					return len(records) == 2 && records[0].UserID == "u1" && *records[0].ReplacedUserID == "u4" &&
+						records[0].Reason == domain.ReasonRestored && records[0].Cause == domain.CauseTeamReactivated
+				})).Return(nil).Once()
+				deactivations.On("MarkTeamReactivated", inTx(tx), int64(3), testNow.UTC()).Return(nil).Once()
+			},
+			expectedCount: 2,
+			expectedRestored: []api.ReviewerMove{
+				{PullRequestId: "pr-1", FromUserId: "u4", ToUserId: "u1"},
+				{PullRequestId: "pr-4", FromUserId: "u5", ToUserId: "u2"},
+			},
+		},
+		{
+			name: "Success: Users reactivated without their reviews",
+			setupMocks: func(m *mocks, deactivations *TeamDeactivationRepositoryMock, jobs *DeactivationJobRepositoryMock) {
+				_, tx, smock := newMockDBAndTx(t)
+				smock.ExpectCommit()
+				m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+				m.teamRepo.On("GetTeamByName", inTx(tx), "test-team").Return(teamInDB, nil).Once()
+				m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil).Once()
+				deactivations.On("GetLastTeamDeactivation", inTx(tx), 1).
+					Return(&domain.TeamDeactivation{ID: 3, TeamID: 1, CreatedAt: deactivatedAt, UserIDs: []string{"u1", "u2"}}, nil).Once()
+				m.userRepo.On("ActivateUsers", inTx(tx), 1, []string{"u1", "u2"}).Return([]string{"u1", "u2"}, nil).Once()
+				deactivations.On("MarkTeamReactivated", inTx(tx), int64(3), testNow.UTC()).Return(nil).Once()
+			},
+			expectedCount:    2,
+			expectedRestored: []api.ReviewerMove{},
+		},
+		{
+			name: "Failure: Batches still running",
+			setupMocks: func(m *mocks, deactivations *TeamDeactivationRepositoryMock, jobs *DeactivationJobRepositoryMock) {
+				_, tx, smock := newMockDBAndTx(t)
+				smock.ExpectRollback()
+				m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+				m.teamRepo.On("GetTeamByName", inTx(tx), "test-team").Return(teamInDB, nil).Once()
+				m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil).Once()
+				deactivations.On("GetLastTeamDeactivation", inTx(tx), 1).
+					Return(&domain.TeamDeactivation{ID: 3, TeamID: 1, JobID: &jobID, CreatedAt: deactivatedAt, UserIDs: []string{"u1"}}, nil).Once()
+				jobs.On("GetDeactivationJob", inTx(tx), jobID).Return(&domain.DeactivationJob{ID: jobID, Status: domain.DeactivationJobRunning}, nil).Once()
+			},
+			expectedError: apperrors.ErrDeactivationInProgress,
+		},
+		{
+			name: "Failure: Team deactivation in progress",
+			setupMocks: func(m *mocks, deactivations *TeamDeactivationRepositoryMock, jobs *DeactivationJobRepositoryMock) {
+				_, tx, smock := newMockDBAndTx(t)
+				smock.ExpectRollback()
+				m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+				m.teamRepo.On("GetTeamByName", inTx(tx), "test-team").Return(teamInDB, nil).Once()
+				m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(false, nil).Once()
+			},
+			expectedError: apperrors.ErrDeactivationInProgress,
+		},
+		{
+			name: "Failure: Nothing to undo",
+			setupMocks: func(m *mocks, deactivations *TeamDeactivationRepositoryMock, jobs *DeactivationJobRepositoryMock) {
+				_, tx, smock := newMockDBAndTx(t)
+				smock.ExpectRollback()
+				m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+				m.teamRepo.On("GetTeamByName", inTx(tx), "test-team").Return(teamInDB, nil).Once()
+				m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil).Once()
+				deactivations.On("GetLastTeamDeactivation", inTx(tx), 1).
+					Return(nil, fmt.Errorf("get: %w: deactivation of team 1", apperrors.ErrNotFound)).Once()
+			},
+			expectedError: apperrors.ErrNotFound,
+		},
+		{
+			name:          "Failure: Reactivation disabled",
+			disabled:      true,
+			setupMocks:    func(*mocks, *TeamDeactivationRepositoryMock, *DeactivationJobRepositoryMock) {},
+			expectedError: apperrors.ErrValidation,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &mocks{
+				userRepo:    new(UserRepositoryMock),
+				teamRepo:    new(TeamRepositoryMock),
+				prQueryRepo: new(PRQueryRepositoryMock),
+				prCmdRepo:   new(PRCommandRepositoryMock),
+				historyRepo: new(AssignmentHistoryRepositoryMock),
+				transactor:  new(TransactorMock),
+			}
+			deactivations := new(TeamDeactivationRepositoryMock)
+			jobs := new(DeactivationJobRepositoryMock)
+			tc.setupMocks(m, deactivations, jobs)
+
+			opts := []UserServiceOption{WithUserClock(fixedClock(testNow)), WithDeactivationJobs(jobs)}
+			if !tc.disabled {
+				opts = append(opts, WithTeamDeactivations(deactivations))
+			}
+
+			service := NewUserService(m.userRepo, m.teamRepo, m.prQueryRepo, m.prCmdRepo, nil, nil, m.historyRepo, m.transactor, logger, opts...)
+			resp, err := service.ReactivateTeam(ctx, "test-team", tc.restoreReviewers)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, resp)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedCount, resp.ReactivatedUsersCount)
+				assert.Equal(t, tc.expectedRestored, resp.RestoredReviews)
+			}
+
+			m.teamRepo.AssertExpectations(t)
+			m.userRepo.AssertExpectations(t)
+			m.prQueryRepo.AssertExpectations(t)
+			m.prCmdRepo.AssertExpectations(t)
+			m.historyRepo.AssertExpectations(t)
+			deactivations.AssertExpectations(t)
+			jobs.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServiceImpl_DeactivateTeam_RecordsDeactivation(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	m := &mocks{
+		userRepo:    new(UserRepositoryMock),
+		teamRepo:    new(TeamRepositoryMock),
+		prQueryRepo: new(PRQueryRepositoryMock),
+		policyRepo:  new(PolicyRepositoryMock),
+		historyRepo: new(AssignmentHistoryRepositoryMock),
+		transactor:  new(TransactorMock),
+	}
+	deactivations := new(TeamDeactivationRepositoryMock)
+
+	_, tx, smock := newMockDBAndTx(t)
+	smock.ExpectCommit()
+	m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+	m.teamRepo.On("GetTeamByName", inTx(tx), "test-team").Return(&domain.TeamWithMembers{ID: 1, Name: "test-team"}, nil).Once()
+	m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil).Once()
+	m.userRepo.On("DeactivateUsersByTeamID", inTx(tx), 1).Return([]string{"u1", "u2"}, nil).Once()
+	m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{"u1", "u2"}).Return([]domain.PullRequest{}, nil).Once()
+	deactivations.On("RecordTeamDeactivation", inTx(tx), &domain.TeamDeactivation{TeamID: 1, CreatedAt: testNow.UTC(), UserIDs: []string{"u1", "u2"}}).Return(nil).Once()
+	m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil).Maybe()
+	m.historyRepo.On("RecordAssignments", inTx(tx), mock.Anything).Return(nil).Maybe()
+
+	service := NewUserService(m.userRepo, m.teamRepo, m.prQueryRepo, nil, nil, m.policyRepo, m.historyRepo, m.transactor, logger,
+		WithUserClock(fixedClock(testNow)), WithTeamDeactivations(deactivations))
+
+	resp, err := service.DeactivateTeam(ctx, "test-team", false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.DeactivatedUsersCount)
+
+	deactivations.AssertExpectations(t)
+	require.NoError(t, smock.ExpectationsWereMet())
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// readTokenPrefix marks the read-only tokens, so that a leaked token is easy to recognize, e.g. by secret scanners.
+const readTokenPrefix = "prr_"
+
+// readTokenTouchInterval bounds how often the last use of a token is written, so that a busy dashboard
+// does not turn every read into a write.
+const readTokenTouchInterval = time.Minute
+
+// ReadTokenService manages the read-only API tokens handed out to dashboards and scripts.
+type ReadTokenService interface {
+	// CreateReadToken issues a token; the value is returned only here and is not stored.
+	// Returns apperrors.ErrValidation if the name is blank or expiresAt is not in the future.
+	CreateReadToken(ctx context.Context, name string, expiresAt *time.Time) (*api.ReadTokenCreatedResponse, error)
+	// ListReadTokens returns every token in creation order, without the values.
+	ListReadTokens(ctx context.Context) (*api.ListReadTokensResponse, error)
+	// DeleteReadToken revokes a token.
+	DeleteReadToken(ctx context.Context, id int64) error
+	// AuthenticateReadToken returns the token with the given value and records its use.
+	// Returns apperrors.ErrInvalidToken if there is no such token or it has expired.
+	AuthenticateReadToken(ctx context.Context, token string) (*domain.ReadToken, error)
+}
+
+type ReadTokenServiceImpl struct {
+	BaseService
+	repo repository.ReadTokenRepository
+}
+
+// ReadTokenServiceOption configures optional behaviour of ReadTokenServiceImpl.
+type ReadTokenServiceOption func(*ReadTokenServiceImpl)
+
+// WithReadTokenClock makes the service take the current time from c instead of the system clock.
+func WithReadTokenClock(c Clock) ReadTokenServiceOption {
+	return func(s *ReadTokenServiceImpl) {
+		s.clock = c
+	}
+}
+
+// NewReadTokenService creates a new instance of ReadTokenServiceImpl.
+func NewReadTokenService(repo repository.ReadTokenRepository, log *slog.Logger, opts ...ReadTokenServiceOption) *ReadTokenServiceImpl {
+	s := &ReadTokenServiceImpl{
+		BaseService: NewBaseService(nil, log),
+		repo:        repo,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *ReadTokenServiceImpl) CreateReadToken(ctx context.Context, name string, expiresAt *time.Time) (*api.ReadTokenCreatedResponse, error) {
+	const op = "internal.service.read_token.CreateReadToken"
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name must not be blank", apperrors.ErrValidation)
+	}
+
+	now := s.now()
+	if expiresAt != nil {
+		if !expiresAt.After(now) {
+			return nil, fmt.Errorf("%w: expires_at must be in the future", apperrors.ErrValidation)
+		}
+
+		utc := expiresAt.UTC()
+		expiresAt = &utc
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("%s: failed to generate token: %w", op, err)
+	}
+
+	value := readTokenPrefix + hex.EncodeToString(secret)
+
+	created, err := s.repo.CreateReadToken(ctx, &domain.ReadToken{
+		Name:      name,
+		TokenHash: hashReadToken(value),
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to create read token: %w", op, err)
+	}
+
+	s.log.Info("read token created", slog.String("op", op), slog.Int64("token_id", created.ID), slog.String("name", name))
+
+	return &api.ReadTokenCreatedResponse{ReadToken: toAPIReadToken(created), Token: value}, nil
+}
+
+func (s *ReadTokenServiceImpl) ListReadTokens(ctx context.Context) (*api.ListReadTokensResponse, error) {
+	const op = "internal.service.read_token.ListReadTokens"
+
+	tokens, err := s.repo.ListReadTokens(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list read tokens: %w", op, err)
+	}
+
+	items := make([]api.ReadToken, len(tokens))
+	for i := range tokens {
+		items[i] = toAPIReadToken(&tokens[i])
+	}
+
+	return &api.ListReadTokensResponse{Items: items, TotalEstimate: totalOf(items)}, nil
+}
+
+func (s *ReadTokenServiceImpl) DeleteReadToken(ctx context.Context, id int64) error {
+	const op = "internal.service.read_token.DeleteReadToken"
+
+	if err := s.repo.DeleteReadToken(ctx, id); err != nil {
+		return fmt.Errorf("%s: failed to delete read token: %w", op, err)
+	}
+
+	s.log.Info("read token deleted", slog.String("op", op), slog.Int64("token_id", id))
+
+	return nil
+}
+
+func (s *ReadTokenServiceImpl) AuthenticateReadToken(ctx context.Context, value string) (*domain.ReadToken, error) {
+	const op = "internal.service.read_token.AuthenticateReadToken"
+
+	if !strings.HasPrefix(value, readTokenPrefix) {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	token, err := s.repo.GetReadTokenByHash(ctx, hashReadToken(value))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.ErrInvalidToken
+		}
+
+		return nil, fmt.Errorf("%s: failed to get read token: %w", op, err)
+	}
+
+	now := s.now()
+	if token.ExpiresAt != nil && !now.Before(*token.ExpiresAt) {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= readTokenTouchInterval {
+		// The use is bookkeeping only; a failure, e.g. on a read-only replica, does not reject the request.
+		if err := s.repo.TouchReadToken(ctx, token.ID, now); err != nil {
+			s.log.Warn("failed to record read token use", slog.String("op", op), slog.Int64("token_id", token.ID), sl.Err(err))
+		} else {
+			token.LastUsedAt = &now
+		}
+	}
+
+	return token, nil
+}
+
+// hashReadToken returns the hex SHA-256 of a token value, under which the token is stored.
+// The values are random, so an unsalted fast hash is enough to keep a database dump from revealing them.
+func hashReadToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func toAPIReadToken(t *domain.ReadToken) api.ReadToken {
+	return api.ReadToken{
+		TokenId:    t.ID,
+		Name:       t.Name,
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+	}
+}
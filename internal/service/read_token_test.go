@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTokenServiceImpl_CreateReadToken(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	expiresAt := testNow.Add(30 * 24 * time.Hour)
+
+	t.Run("Only the hash is stored", func(t *testing.T) {
+		var stored *domain.ReadToken
+
+		repo := new(ReadTokenRepositoryMock)
+		repo.On("CreateReadToken", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.ReadToken)
+		}).Return(&domain.ReadToken{ID: 4, Name: "grafana", CreatedAt: testNow, ExpiresAt: &expiresAt}, nil).Once()
+
+		created, err := NewReadTokenService(repo, logger, WithReadTokenClock(fixedClock(testNow))).
+			CreateReadToken(ctx, " grafana ", &expiresAt)
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(created.Token, readTokenPrefix))
+		assert.Equal(t, int64(4), created.ReadToken.TokenId)
+		require.NotNil(t, stored)
+		assert.Equal(t, "grafana", stored.Name)
+		assert.Equal(t, testNow.UTC(), stored.CreatedAt)
+		assert.Equal(t, hashReadToken(created.Token), stored.TokenHash)
+		assert.NotContains(t, stored.TokenHash, created.Token)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Blank name", func(t *testing.T) {
+		_, err := NewReadTokenService(new(ReadTokenRepositoryMock), logger).CreateReadToken(ctx, "  ", nil)
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+	})
+
+	t.Run("Expiry in the past", func(t *testing.T) {
+		past := testNow.Add(-time.Minute)
+
+		_, err := NewReadTokenService(new(ReadTokenRepositoryMock), logger, WithReadTokenClock(fixedClock(testNow))).
+			CreateReadToken(ctx, "grafana", &past)
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+	})
+}
+
+func TestReadTokenServiceImpl_AuthenticateReadToken(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	const value = readTokenPrefix + "0123456789abcdef"
+	now := testNow.UTC()
+
+	recently := testNow.Add(-10 * time.Second)
+	long := testNow.Add(-time.Hour)
+	expired := testNow.Add(-time.Second)
+
+	t.Run("First use is recorded", func(t *testing.T) {
+		repo := new(ReadTokenRepositoryMock)
+		repo.On("GetReadTokenByHash", ctx, hashReadToken(value)).Return(&domain.ReadToken{ID: 1}, nil).Once()
+		repo.On("TouchReadToken", ctx, int64(1), now).Return(nil).Once()
+
+		token, err := NewReadTokenService(repo, logger, WithReadTokenClock(fixedClock(testNow))).AuthenticateReadToken(ctx, value)
+
+		require.NoError(t, err)
+		require.NotNil(t, token.LastUsedAt)
+		assert.Equal(t, now, *token.LastUsedAt)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Recent use is not written again", func(t *testing.T) {
+		repo := new(ReadTokenRepositoryMock)
+		repo.On("GetReadTokenByHash", ctx, hashReadToken(value)).Return(&domain.ReadToken{ID: 1, LastUsedAt: &recently}, nil).Once()
+
+		_, err := NewReadTokenService(repo, logger, WithReadTokenClock(fixedClock(testNow))).AuthenticateReadToken(ctx, value)
+
+		require.NoError(t, err)
+		repo.AssertNotCalled(t, "TouchReadToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failed touch does not reject the request", func(t *testing.T) {
+		repo := new(ReadTokenRepositoryMock)
+		repo.On("GetReadTokenByHash", ctx, hashReadToken(value)).Return(&domain.ReadToken{ID: 1, LastUsedAt: &long}, nil).Once()
+		repo.On("TouchReadToken", ctx, int64(1), now).Return(errors.New("read-only transaction")).Once()
+
+		_, err := NewReadTokenService(repo, logger, WithReadTokenClock(fixedClock(testNow))).AuthenticateReadToken(ctx, value)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		repo := new(ReadTokenRepositoryMock)
+		repo.On("GetReadTokenByHash", ctx, hashReadToken(value)).Return(&domain.ReadToken{ID: 1, ExpiresAt: &expired}, nil).Once()
+
+		_, err := NewReadTokenService(repo, logger, WithReadTokenClock(fixedClock(testNow))).AuthenticateReadToken(ctx, value)
+
+		assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		repo := new(ReadTokenRepositoryMock)
+		repo.On("GetReadTokenByHash", ctx, hashReadToken(value)).Return(nil, apperrors.ErrNotFound).Once()
+
+		_, err := NewReadTokenService(repo, logger).AuthenticateReadToken(ctx, value)
+
+		assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+	})
+
+	t.Run("Not a read token", func(t *testing.T) {
+		_, err := NewReadTokenService(new(ReadTokenRepositoryMock), logger).AuthenticateReadToken(ctx, "service-key-0123456789")
+
+		assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+	})
+}
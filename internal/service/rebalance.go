@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+func (s *UserServiceImpl) RebalanceReviews(ctx context.Context, userID string) ([]api.ReviewerMove, error) {
+	const op = "internal.service.user.RebalanceReviews"
+
+	var moves []domain.AssignmentRecord
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		var err error
+
+		moves, err = s.rebalanceTeams(ctx, userID)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reviewsRebalancedTotal.Add(float64(len(moves)))
+
+	return toAPIReviewerMoves(moves), nil
+}
+
+// rebalanceTeams rebalances the reviews of every team the user is a member of, one team after another.
+func (s *UserServiceImpl) rebalanceTeams(ctx context.Context, userID string) ([]domain.AssignmentRecord, error) {
+	teamIDs, err := s.userPR.GetUserTeamIDs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user teams: %w", err)
+	}
+
+	var moves []domain.AssignmentRecord
+
+	for _, teamID := range teamIDs {
+		teamMoves, err := s.rebalance(ctx, teamID, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		moves = append(moves, teamMoves...)
+	}
+
+	return moves, nil
+}
+
+// rebalance moves reviews to userID from the most loaded active members of the team, one review at a time,
+// while the user has fewer open reviews than the fair share of the team and a teammate has more.
+// The fair share is the number of open reviews of the active members divided among them, rounded down.
+// Only the reviews of pull requests authored in the team, by members whose primary team it is, are moved,
+// and never to their author or to a user who already reviews them. A user who is not an active member
+// takes over nothing.
+func (s *UserServiceImpl) rebalance(ctx context.Context, teamID int, userID string) ([]domain.AssignmentRecord, error) {
+	team, err := s.teamRepo.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+
+	members := make(map[string]bool, len(team.Members))
+	load := make(map[string]int, len(team.Members))
+
+	var activeIDs []string
+
+	for _, member := range team.Members {
+		// The reviewers of the pull requests of a member come from the primary team of the member.
+		members[member.ID] = member.TeamID == teamID
+
+		if member.IsActive {
+			activeIDs = append(activeIDs, member.ID)
+			load[member.ID] = 0
+		}
+	}
+
+	if _, active := load[userID]; !active || len(activeIDs) < 2 {
+		return nil, nil
+	}
+
+	prs, err := s.prQuery.GetOpenPRsByReviewers(ctx, activeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open PRs: %w", err)
+	}
+
+	total := 0
+
+	for _, pr := range prs {
+		for _, reviewerID := range pr.ReviewerIDs {
+			if _, active := load[reviewerID]; active {
+				load[reviewerID]++
+				total++
+			}
+		}
+	}
+
+	fairShare := total / len(activeIDs)
+	exhausted := make(map[string]bool)
+	movedAt := s.now()
+
+	var moves []domain.AssignmentRecord
+
+	for load[userID] < fairShare {
+		donorID := ""
+
+		for _, id := range activeIDs {
+			if id == userID || exhausted[id] || load[id] <= fairShare {
+				continue
+			}
+
+			if donorID == "" || load[id] > load[donorID] {
+				donorID = id
+			}
+		}
+
+		if donorID == "" {
+			break
+		}
+
+		pr := movablePR(prs, members, donorID, userID)
+		if pr == nil {
+			exhausted[donorID] = true
+			continue
+		}
+
+		for i, id := range pr.ReviewerIDs {
+			if id == donorID {
+				pr.ReviewerIDs[i] = userID
+				break
+			}
+		}
+
+		load[donorID]--
+		load[userID]++
+
+		moves = append(moves, rebalanceRecord(pr.ID, donorID, userID, movedAt))
+	}
+
+	if len(moves) == 0 {
+		return nil, nil
+	}
+
+	if err := s.applyReplacements(ctx, moves); err != nil {
+		return nil, fmt.Errorf("failed to move reviews: %w", err)
+	}
+
+	s.log.Info("reviews rebalanced", slog.String("user_id", userID), slog.Int("team_id", teamID),
+		slog.Int("moves", len(moves)), slog.Int("fair_share", fairShare))
+
+	return moves, nil
+}
+
+// movablePR returns the first pull request whose review can move from donorID to userID.
+func movablePR(prs []domain.PullRequest, members map[string]bool, donorID, userID string) *domain.PullRequest {
+	for i := range prs {
+		pr := &prs[i]
+
+		if pr.AuthorID == userID || !members[pr.AuthorID] {
+			continue
+		}
+
+		reviewsDonor, reviewsUser := false, false
+
+		for _, id := range pr.ReviewerIDs {
+			reviewsDonor = reviewsDonor || id == donorID
+			reviewsUser = reviewsUser || id == userID
+		}
+
+		if reviewsDonor && !reviewsUser {
+			return pr
+		}
+	}
+
+	return nil
+}
+
+// rebalanceRecord is the assignment history record of a review moved to a user who became active again,
+// the least loaded member of the team at the time.
+func rebalanceRecord(prID, fromUserID, toUserID string, at time.Time) domain.AssignmentRecord {
+	record := replacementRecord(prID, fromUserID, toUserID, domain.StrategyLeastLoaded, domain.CauseRebalance, at)
+	record.Reason = domain.ReasonRebalance
+
+	return record
+}
+
+// queueRebalance queues a reviewer_rebalance job for userID.
+func (s *UserServiceImpl) queueRebalance(ctx context.Context, userID string) (*domain.Job, error) {
+	params, err := json.Marshal(reviewerRebalanceParams{UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job params: %w", err)
+	}
+
+	job, err := s.rebalanceJobs.CreateJob(ctx, &domain.Job{Type: domain.JobReviewerRebalance, Params: params, CreatedAt: s.now()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	return job, nil
+}
+
+func toAPIReviewerMoves(records []domain.AssignmentRecord) []api.ReviewerMove {
+	moves := make([]api.ReviewerMove, len(records))
+	for i, record := range records {
+		moves[i] = api.ReviewerMove{PullRequestId: record.PullRequestID, FromUserId: *record.ReplacedUserID, ToUserId: record.UserID}
+	}
+
+	return moves
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// rebalanceTeam is a team in which u1 has become active again. u4 is inactive, so the fair share of
+// the eight open reviews of u1, u2 and u3 is two.
+var rebalanceTeam = &domain.TeamWithMembers{ID: 1, Name: "backend", Members: []domain.User{
+	{ID: "u1", TeamID: 1, IsActive: true},
+	{ID: "u2", TeamID: 1, IsActive: true},
+	{ID: "u3", TeamID: 1, IsActive: true},
+	{ID: "u4", TeamID: 1, IsActive: false},
+}}
+
+// rebalancePRs returns the open pull requests of rebalanceTeam. u2 has four reviews and gives one up:
+// not pr-1, which u1 wrote, nor pr-2, written outside the team, but pr-3.
+func rebalancePRs() []domain.PullRequest {
+	return []domain.PullRequest{
+		{ID: "pr-1", AuthorID: "u1", ReviewerIDs: []string{"u2", "u3"}},
+		{ID: "pr-2", AuthorID: "external", ReviewerIDs: []string{"u2"}},
+		{ID: "pr-3", AuthorID: "u4", ReviewerIDs: []string{"u2", "u3"}},
+		{ID: "pr-4", AuthorID: "u3", ReviewerIDs: []string{"u2"}},
+		{ID: "pr-5", AuthorID: "u4", ReviewerIDs: []string{"u3", "u1"}},
+	}
+}
+
+func TestUserServiceImpl_SetIsActive_Rebalance(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	user := &api.User{UserId: "u1", Username: "Alice", TeamName: "backend", TeamId: 1, IsActive: true}
+	moved := rebalanceRecord("pr-3", "u2", "u1", testNow.UTC())
+	jobID := int64(9)
+
+	expectMove := func(m *mocks, tx *sqlx.Tx) {
+		m.userPRRepo.On("GetUserTeamIDs", inTx(tx), "u1").Return([]int{1}, nil).Once()
+		m.teamRepo.On("GetTeamByID", inTx(tx), 1).Return(rebalanceTeam, nil).Once()
+		m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{"u1", "u2", "u3"}).Return(rebalancePRs(), nil).Once()
+		m.prCmdRepo.On("ReplaceReviewers", inTx(tx), []domain.ReviewerReplacement{{PullRequestID: "pr-3", OldReviewerID: "u2", NewReviewerID: "u1"}}).Return(nil).Once()
+		m.historyRepo.On("RecordAssignments", inTx(tx), []domain.AssignmentRecord{moved}).Return(nil).Once()
+	}
+
+	testCases := []struct {
+		name         string
+		wasActive    bool
+		mode         domain.RebalanceMode
+		withJobs     bool
+		setupMocks   func(m *mocks, tx *sqlx.Tx, jobs *JobRepositoryMock)
+		expectedResp *api.SetIsActiveResponse
+	}{
+		{
+			name:       "Immediate mode moves reviews in the same transaction",
+			mode:       domain.RebalanceImmediate,
+			setupMocks: func(m *mocks, tx *sqlx.Tx, _ *JobRepositoryMock) { expectMove(m, tx) },
+			expectedResp: &api.SetIsActiveResponse{User: *user, Rebalance: &api.RebalanceResult{
+				Moves: []api.ReviewerMove{{PullRequestId: "pr-3", FromUserId: "u2", ToUserId: "u1"}},
+			}},
+		},
+		{
+			name:     "Job mode queues a job",
+			mode:     domain.RebalanceJob,
+			withJobs: true,
+			setupMocks: func(_ *mocks, _ *sqlx.Tx, jobs *JobRepositoryMock) {
+				jobs.On("CreateJob", ctx, &domain.Job{
+					Type: domain.JobReviewerRebalance, Params: []byte(`{"user_id":"u1"}`), CreatedAt: testNow.UTC(),
+				}).Return(&domain.Job{ID: jobID, Type: domain.JobReviewerRebalance}, nil).Once()
+			},
+			expectedResp: &api.SetIsActiveResponse{User: *user, Rebalance: &api.RebalanceResult{Moves: []api.ReviewerMove{}, JobId: &jobID}},
+		},
+		{
+			name:       "Job mode without job queue rebalances at once",
+			mode:       domain.RebalanceJob,
+			setupMocks: func(m *mocks, tx *sqlx.Tx, _ *JobRepositoryMock) { expectMove(m, tx) },
+			expectedResp: &api.SetIsActiveResponse{User: *user, Rebalance: &api.RebalanceResult{
+				Moves: []api.ReviewerMove{{PullRequestId: "pr-3", FromUserId: "u2", ToUserId: "u1"}},
+			}},
+		},
+		{
+			name:         "Off mode moves nothing",
+			mode:         domain.RebalanceOff,
+			setupMocks:   func(_ *mocks, _ *sqlx.Tx, _ *JobRepositoryMock) {},
+			expectedResp: &api.SetIsActiveResponse{User: *user},
+		},
+		{
+			name:         "Already active user moves nothing",
+			wasActive:    true,
+			mode:         domain.RebalanceImmediate,
+			setupMocks:   func(_ *mocks, _ *sqlx.Tx, _ *JobRepositoryMock) {},
+			expectedResp: &api.SetIsActiveResponse{User: *user},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &mocks{
+				userRepo:    new(UserRepositoryMock),
+				teamRepo:    new(TeamRepositoryMock),
+				prQueryRepo: new(PRQueryRepositoryMock),
+				prCmdRepo:   new(PRCommandRepositoryMock),
+				userPRRepo:  new(UserPRRepositoryMock),
+				policyRepo:  new(PolicyRepositoryMock),
+				historyRepo: new(AssignmentHistoryRepositoryMock),
+				transactor:  new(TransactorMock),
+			}
+			jobs := new(JobRepositoryMock)
+
+			_, tx, smock := newMockDBAndTx(t)
+			smock.ExpectCommit()
+
+			m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+			m.userRepo.On("SetIsActive", inTx(tx), "u1", true).Return(user, tc.wasActive, nil).Once()
+			m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1, ReactivationRebalance: tc.mode}, nil).Maybe()
+			tc.setupMocks(m, tx, jobs)
+
+			opts := []UserServiceOption{WithUserClock(fixedClock(testNow))}
+			if tc.withJobs {
+				opts = append(opts, WithRebalanceJobs(jobs))
+			}
+
+			service := NewUserService(m.userRepo, m.teamRepo, m.prQueryRepo, m.prCmdRepo, m.userPRRepo, m.policyRepo, m.historyRepo,
+				m.transactor, logger, opts...)
+
+			resp, err := service.SetIsActive(ctx, "u1", true)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedResp, resp)
+
+			m.userRepo.AssertExpectations(t)
+			m.teamRepo.AssertExpectations(t)
+			m.prQueryRepo.AssertExpectations(t)
+			m.prCmdRepo.AssertExpectations(t)
+			m.historyRepo.AssertExpectations(t)
+			jobs.AssertExpectations(t)
+			require.NoError(t, smock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserServiceImpl_RebalanceReviews(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	t.Run("Reviews move to a returning user", func(t *testing.T) {
+		m := &mocks{
+			teamRepo:    new(TeamRepositoryMock),
+			prQueryRepo: new(PRQueryRepositoryMock),
+			prCmdRepo:   new(PRCommandRepositoryMock),
+			userPRRepo:  new(UserPRRepositoryMock),
+			historyRepo: new(AssignmentHistoryRepositoryMock),
+			transactor:  new(TransactorMock),
+		}
+
+		_, tx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+
+		m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+		m.userPRRepo.On("GetUserTeamIDs", inTx(tx), "u1").Return([]int{1}, nil).Once()
+		m.teamRepo.On("GetTeamByID", inTx(tx), 1).Return(rebalanceTeam, nil).Once()
+		m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{"u1", "u2", "u3"}).Return(rebalancePRs(), nil).Once()
+		m.prCmdRepo.On("ReplaceReviewers", inTx(tx), []domain.ReviewerReplacement{{PullRequestID: "pr-3", OldReviewerID: "u2", NewReviewerID: "u1"}}).Return(nil).Once()
+		m.historyRepo.On("RecordAssignments", inTx(tx), mock.MatchedBy(func(records []domain.AssignmentRecord) bool {
+			return len(records) == 1 && records[0].Reason == domain.ReasonRebalance
+		})).Return(nil).Once()
+
+		service := NewUserService(nil, m.teamRepo, m.prQueryRepo, m.prCmdRepo, m.userPRRepo, nil, m.historyRepo, m.transactor, logger)
+
+		moves, err := service.RebalanceReviews(ctx, "u1")
+		require.NoError(t, err)
+		assert.Equal(t, []api.ReviewerMove{{PullRequestId: "pr-3", FromUserId: "u2", ToUserId: "u1"}}, moves)
+
+		m.prCmdRepo.AssertExpectations(t)
+		m.historyRepo.AssertExpectations(t)
+		require.NoError(t, smock.ExpectationsWereMet())
+	})
+
+	t.Run("Inactive user takes over nothing", func(t *testing.T) {
+		m := &mocks{
+			teamRepo:   new(TeamRepositoryMock),
+			userPRRepo: new(UserPRRepositoryMock),
+			transactor: new(TransactorMock),
+		}
+
+		_, tx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+
+		m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+		m.userPRRepo.On("GetUserTeamIDs", inTx(tx), "u4").Return([]int{1}, nil).Once()
+		m.teamRepo.On("GetTeamByID", inTx(tx), 1).Return(rebalanceTeam, nil).Once()
+
+		service := NewUserService(nil, m.teamRepo, nil, nil, m.userPRRepo, nil, nil, m.transactor, logger)
+
+		moves, err := service.RebalanceReviews(ctx, "u4")
+		require.NoError(t, err)
+		assert.Empty(t, moves)
+		require.NoError(t, smock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+func (s *PullRequestServiceImpl) ApprovePR(ctx context.Context, prID string, reviewerID string) (*api.PullRequest, error) {
+	return s.submitReview(ctx, prID, reviewerID, domain.ReviewApproved)
+}
+
+func (s *PullRequestServiceImpl) RequestChanges(ctx context.Context, prID string, reviewerID string) (*api.PullRequest, error) {
+	return s.submitReview(ctx, prID, reviewerID, domain.ReviewChangesRequested)
+}
+
+// submitReview records the decision of a reviewer on an open pull request and tells the author about it.
+// Repeating the current decision changes nothing, so the author is not notified twice.
+func (s *PullRequestServiceImpl) submitReview(ctx context.Context, prID string, reviewerID string, state domain.ReviewState) (*api.PullRequest, error) {
+	const op = "internal.service.pullrequest.submitReview"
+	log := s.log.With(slog.String("op", op), slog.String("pr_id", prID),
+		slog.String("reviewer_id", reviewerID), slog.String("state", string(state)))
+
+	var (
+		pr          *domain.PullRequest
+		reviewerIDs []string
+		reviews     []domain.Review
+		changed     bool
+	)
+
+	reviewedAt := s.now()
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		var err error
+
+		changed = false
+
+		pr, err = s.prCmd.GetPRByIDWithLock(ctx, prID)
+		if err != nil {
+			return fmt.Errorf("%s: failed to get pr with lock: %w", op, err)
+		}
+
+		switch pr.Status {
+		case api.PullRequestStatusMERGED:
+			return apperrors.ErrPRMerged
+		case api.PullRequestStatusCLOSED:
+			return apperrors.ErrPRClosed
+		}
+
+		reviews, err = s.prQuery.GetReviews(ctx, prID)
+		if err != nil {
+			return fmt.Errorf("%s: failed to get reviews: %w", op, err)
+		}
+
+		i := findReview(reviews, reviewerID)
+		if i < 0 {
+			return fmt.Errorf("%s: %w: user '%s' does not review PR '%s'", op, apperrors.ErrReviewerNotAssigned, reviewerID, prID)
+		}
+
+		if reviews[i].State != state {
+			if err := s.prCmd.SetReviewState(ctx, prID, reviewerID, state, reviewedAt); err != nil {
+				return fmt.Errorf("%s: failed to set review state: %w", op, err)
+			}
+
+			reviews[i].State = state
+			reviews[i].ReviewedAt = &reviewedAt
+			changed = true
+		}
+
+		reviewerIDs, err = s.prQuery.GetReviewerIDs(ctx, prID)
+		if err != nil {
+			return fmt.Errorf("%s: failed to get reviewers: %w", op, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	pr.ReviewerIDs = reviewerIDs
+	pr.Reviews = reviews
+
+	if changed {
+		log.Info("review submitted")
+
+		eventType := domain.EventReviewApproved
+		if state == domain.ReviewChangesRequested {
+			eventType = domain.EventChangesRequested
+		}
+
+		s.notify(ctx, newEvent(eventType, pr, []string{pr.AuthorID}, reviewedAt))
+	} else {
+		log.Info("review unchanged, returning current state")
+	}
+
+	return toAPIPullRequest(pr), nil
+}
+
+// checkApproved returns an apperrors.ApprovalRequiredError if any reviewer of the pull request has not approved it.
+// A pull request without reviewers has nobody to wait for.
+func (s *PullRequestServiceImpl) checkApproved(ctx context.Context, prID string) error {
+	reviews, err := s.prQuery.GetReviews(ctx, prID)
+	if err != nil {
+		return fmt.Errorf("failed to get reviews: %w", err)
+	}
+
+	var waitingFor []string
+
+	for _, review := range reviews {
+		if review.State != domain.ReviewApproved {
+			waitingFor = append(waitingFor, review.UserID)
+		}
+	}
+
+	if len(waitingFor) > 0 {
+		return &apperrors.ApprovalRequiredError{PRID: prID, ReviewerIDs: waitingFor}
+	}
+
+	return nil
+}
+
+func findReview(reviews []domain.Review, reviewerID string) int {
+	for i, review := range reviews {
+		if review.UserID == reviewerID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func toAPIReviews(reviews []domain.Review) *[]api.Review {
+	apiReviews := make([]api.Review, len(reviews))
+	for i, review := range reviews {
+		apiReviews[i] = api.Review{
+			UserId:     review.UserID,
+			State:      api.ReviewState(review.State),
+			ReviewedAt: review.ReviewedAt,
+		}
+	}
+
+	return &apiReviews
+}
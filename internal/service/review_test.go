@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestServiceImpl_SubmitReview(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	prID := "pr-reviewed"
+	reviewedAt := testNow.UTC()
+
+	openPR := func() *domain.PullRequest {
+		return &domain.PullRequest{ID: prID, AuthorID: "author", Status: api.PullRequestStatusOPEN}
+	}
+	pendingReviews := func() []domain.Review {
+		return []domain.Review{
+			{UserID: "rev1", State: domain.ReviewPending},
+			{UserID: "rev2", State: domain.ReviewPending},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		requestChanges  bool
+		reviewerID      string
+		setupMocks      func(tx *sqlx.Tx, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, notifier *NotifierMock)
+		expectedErr     error
+		expectedReviews []api.Review
+	}{
+		{
+			name:       "Approval notifies the author",
+			reviewerID: "rev1",
+			setupMocks: func(tx *sqlx.Tx, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, notifier *NotifierMock) {
+				prCmd.On("GetPRByIDWithLock", inTx(tx), prID).Return(openPR(), nil).Once()
+				prQuery.On("GetReviews", inTx(tx), prID).Return(pendingReviews(), nil).Once()
+				prCmd.On("SetReviewState", inTx(tx), prID, "rev1", domain.ReviewApproved, reviewedAt).Return(nil).Once()
+				prQuery.On("GetReviewerIDs", inTx(tx), prID).Return([]string{"rev1", "rev2"}, nil).Once()
+				notifier.On("Notify", mock.Anything, mock.MatchedBy(func(event domain.Event) bool {
+					return event.Type == domain.EventReviewApproved && assert.ObjectsAreEqual([]string{"author"}, event.UserIDs)
+				})).Once()
+			},
+			expectedReviews: []api.Review{
+				{UserId: "rev1", State: api.APPROVED, ReviewedAt: &reviewedAt},
+				{UserId: "rev2", State: api.PENDING},
+			},
+		},
+		{
+			name:           "Changes requested after an approval",
+			requestChanges: true,
+			reviewerID:     "rev2",
+			setupMocks: func(tx *sqlx.Tx, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, notifier *NotifierMock) {
+				reviews := pendingReviews()
+				reviews[1] = domain.Review{UserID: "rev2", State: domain.ReviewApproved, ReviewedAt: &testNow}
+
+				prCmd.On("GetPRByIDWithLock", inTx(tx), prID).Return(openPR(), nil).Once()
+				prQuery.On("GetReviews", inTx(tx), prID).Return(reviews, nil).Once()
+				prCmd.On("SetReviewState", inTx(tx), prID, "rev2", domain.ReviewChangesRequested, reviewedAt).Return(nil).Once()
+				prQuery.On("GetReviewerIDs", inTx(tx), prID).Return([]string{"rev1", "rev2"}, nil).Once()
+				notifier.On("Notify", mock.Anything, mock.MatchedBy(func(event domain.Event) bool {
+					return event.Type == domain.EventChangesRequested
+				})).Once()
+			},
+			expectedReviews: []api.Review{
+				{UserId: "rev1", State: api.PENDING},
+				{UserId: "rev2", State: api.CHANGESREQUESTED, ReviewedAt: &reviewedAt},
+			},
+		},
+		{
+			name:       "Repeated approval changes nothing",
+			reviewerID: "rev1",
+			setupMocks: func(tx *sqlx.Tx, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, notifier *NotifierMock) {
+				reviews := pendingReviews()
+				reviews[0] = domain.Review{UserID: "rev1", State: domain.ReviewApproved, ReviewedAt: &reviewedAt}
+
+				prCmd.On("GetPRByIDWithLock", inTx(tx), prID).Return(openPR(), nil).Once()
+				prQuery.On("GetReviews", inTx(tx), prID).Return(reviews, nil).Once()
+				prQuery.On("GetReviewerIDs", inTx(tx), prID).Return([]string{"rev1", "rev2"}, nil).Once()
+			},
+			expectedReviews: []api.Review{
+				{UserId: "rev1", State: api.APPROVED, ReviewedAt: &reviewedAt},
+				{UserId: "rev2", State: api.PENDING},
+			},
+		},
+		{
+			name:       "User is not a reviewer",
+			reviewerID: "author",
+			setupMocks: func(tx *sqlx.Tx, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, notifier *NotifierMock) {
+				prCmd.On("GetPRByIDWithLock", inTx(tx), prID).Return(openPR(), nil).Once()
+				prQuery.On("GetReviews", inTx(tx), prID).Return(pendingReviews(), nil).Once()
+			},
+			expectedErr: apperrors.ErrReviewerNotAssigned,
+		},
+		{
+			name:       "Merged PR",
+			reviewerID: "rev1",
+			setupMocks: func(tx *sqlx.Tx, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, notifier *NotifierMock) {
+				prCmd.On("GetPRByIDWithLock", inTx(tx), prID).Return(&domain.PullRequest{ID: prID, Status: api.PullRequestStatusMERGED}, nil).Once()
+			},
+			expectedErr: apperrors.ErrPRMerged,
+		},
+		{
+			name:       "Closed PR",
+			reviewerID: "rev1",
+			setupMocks: func(tx *sqlx.Tx, prCmd *PRCommandRepositoryMock, prQuery *PRQueryRepositoryMock, notifier *NotifierMock) {
+				prCmd.On("GetPRByIDWithLock", inTx(tx), prID).Return(&domain.PullRequest{ID: prID, Status: api.PullRequestStatusCLOSED}, nil).Once()
+			},
+			expectedErr: apperrors.ErrPRClosed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transactorMock := new(TransactorMock)
+			prCmdMock := new(PRCommandRepositoryMock)
+			prQueryMock := new(PRQueryRepositoryMock)
+			notifierMock := new(NotifierMock)
+
+			_, mockedTx, smock := newMockDBAndTx(t)
+			if tc.expectedErr != nil {
+				smock.ExpectRollback()
+			} else {
+				smock.ExpectCommit()
+			}
+
+			transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+			tc.setupMocks(mockedTx, prCmdMock, prQueryMock, notifierMock)
+
+			service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil, nil, nil,
+				WithNotifier(notifierMock), WithClock(fixedClock(testNow)))
+
+			submit := service.ApprovePR
+			if tc.requestChanges {
+				submit = service.RequestChanges
+			}
+
+			pr, err := submit(ctx, prID, tc.reviewerID)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.Nil(t, pr)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, pr.Reviews)
+				assert.Equal(t, tc.expectedReviews, *pr.Reviews)
+				assert.Equal(t, []string{"rev1", "rev2"}, pr.AssignedReviewers)
+			}
+
+			prCmdMock.AssertExpectations(t)
+			prQueryMock.AssertExpectations(t)
+			notifierMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPullRequestServiceImpl_MergePR_RequiredApprovals(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	prID := "pr-to-merge"
+
+	t.Run("Merge waits for every reviewer", func(t *testing.T) {
+		transactorMock := new(TransactorMock)
+		prCmdMock := new(PRCommandRepositoryMock)
+		prQueryMock := new(PRQueryRepositoryMock)
+
+		_, mockedTx, smock := newMockDBAndTx(t)
+		smock.ExpectRollback()
+
+		transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+		prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(&domain.PullRequest{ID: prID, Status: api.PullRequestStatusOPEN}, nil).Once()
+		prQueryMock.On("GetReviews", inTx(mockedTx), prID).Return([]domain.Review{
+			{UserID: "rev1", State: domain.ReviewApproved},
+			{UserID: "rev2", State: domain.ReviewChangesRequested},
+			{UserID: "rev3", State: domain.ReviewPending},
+		}, nil).Once()
+
+		service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil, nil, nil, WithRequiredApprovals())
+		_, err := service.MergePR(ctx, prID, MergeOptions{})
+
+		var approvalErr *apperrors.ApprovalRequiredError
+		require.ErrorAs(t, err, &approvalErr)
+		assert.ErrorIs(t, err, apperrors.ErrApprovalRequired)
+		assert.Equal(t, []string{"rev2", "rev3"}, approvalErr.ReviewerIDs)
+		prCmdMock.AssertNotCalled(t, "UpdatePRStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Approved PR is merged", func(t *testing.T) {
+		transactorMock := new(TransactorMock)
+		prCmdMock := new(PRCommandRepositoryMock)
+		prQueryMock := new(PRQueryRepositoryMock)
+
+		_, mockedTx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+
+		transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+		prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(&domain.PullRequest{ID: prID, Status: api.PullRequestStatusOPEN}, nil).Once()
+		prQueryMock.On("GetReviews", inTx(mockedTx), prID).Return([]domain.Review{
+			{UserID: "rev1", State: domain.ReviewApproved},
+		}, nil).Once()
+		prCmdMock.On("UpdatePRStatus", inTx(mockedTx), prID, api.PullRequestStatusMERGED, mock.AnythingOfType("time.Time")).Return(nil, nil).Once()
+		prQueryMock.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{"rev1"}, nil).Once()
+		prQueryMock.On("GetStatsByUserIDs", inTx(mockedTx), []string{"rev1"}).Return([]domain.Stats{}, nil).Once()
+
+		service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil, nil, nil, WithRequiredApprovals())
+		resp, err := service.MergePR(ctx, prID, MergeOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, api.PullRequestStatusMERGED, resp.Pr.Status)
+		prCmdMock.AssertExpectations(t)
+	})
+
+	t.Run("Already merged PR is not checked again", func(t *testing.T) {
+		transactorMock := new(TransactorMock)
+		prCmdMock := new(PRCommandRepositoryMock)
+		prQueryMock := new(PRQueryRepositoryMock)
+
+		_, mockedTx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+
+		transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+		prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(&domain.PullRequest{ID: prID, Status: api.PullRequestStatusMERGED}, nil).Once()
+		prQueryMock.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{"rev1"}, nil).Once()
+		prQueryMock.On("GetStatsByUserIDs", inTx(mockedTx), []string{"rev1"}).Return([]domain.Stats{}, nil).Once()
+
+		service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil, nil, nil, WithRequiredApprovals())
+		_, err := service.MergePR(ctx, prID, MergeOptions{})
+
+		require.NoError(t, err)
+		prQueryMock.AssertNotCalled(t, "GetReviews", mock.Anything, mock.Anything)
+	})
+}
@@ -6,46 +6,168 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
+	"time"
 
+	"github.com/YusovID/pr-reviewer-service/internal/localcache"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/internal/tracing"
 	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
-	"github.com/jmoiron/sqlx"
 )
 
-type Transactor interface {
-	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+// TxManager runs units of work in transactions carried by their contexts; the repositories called with
+// the context passed to fn run their queries in its transaction. txctx.Manager is the implementation.
+type TxManager interface {
+	Do(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error
 }
 
 type BaseService struct {
-	db  Transactor
-	log *slog.Logger
+	db    TxManager
+	log   *slog.Logger
+	clock Clock
+	// readCache holds the results of the hot reads; every committed write transaction invalidates it.
+	readCache *localcache.Cache
+	// txMaxAttempts is how many times a transaction aborted by a conflict with another one is run.
+	txMaxAttempts int
 }
 
-func NewBaseService(db Transactor, log *slog.Logger) BaseService {
+func NewBaseService(db TxManager, log *slog.Logger) BaseService {
 	return BaseService{
-		db:  db,
-		log: log,
+		db:            db,
+		log:           log,
+		clock:         systemClock{},
+		txMaxAttempts: defaultTxMaxAttempts,
 	}
 }
 
-func (s *BaseService) transaction(ctx context.Context, op string, fn func(tx *sqlx.Tx) error) error {
-	tx, err := s.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+// now returns the current time of the service clock in UTC; timestamps written by the services all come from it.
+func (s *BaseService) now() time.Time {
+	return s.clock.Now().UTC()
+}
+
+// snapshotTxOptions make every read of a transaction see the same snapshot of the data.
+var snapshotTxOptions = &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+
+func (s *BaseService) transaction(ctx context.Context, op string, fn func(ctx context.Context) error) error {
+	return s.transactionWithOptions(ctx, op, nil, fn)
+}
+
+// readSnapshot runs fn in a read-only REPEATABLE READ transaction. Reports that combine several queries
+// use it so that their numbers are consistent with each other even while the data is being changed.
+func (s *BaseService) readSnapshot(ctx context.Context, op string, fn func(ctx context.Context) error) error {
+	return s.transactionWithOptions(ctx, op, snapshotTxOptions, fn)
+}
+
+// transactionWithOptions runs fn in a transaction traced as a span named by op, under which the spans
+// of its queries nest.
+//
+// A transaction that PostgreSQL aborts as a deadlock victim or for a serialization failure is run again,
+// up to txMaxAttempts times in all, after a short random backoff. fn may therefore run more than once and
+// must set the results it passes out from scratch on every run. Only the call that begins the transaction
+// runs it again: fn joining a transaction of its caller runs once, and its error aborts the caller's
+// transaction, which the caller runs again as a whole.
+func (s *BaseService) transactionWithOptions(ctx context.Context, op string, opts *sql.TxOptions, fn func(ctx context.Context) error) (err error) {
+	ctx, span := tracing.Start(ctx, op)
+	defer func() { tracing.End(span, err) }()
+
+	if _, joined := txctx.From(ctx); joined {
+		return s.runTransaction(ctx, op, opts, fn)
 	}
 
-	defer func() {
-		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
-			s.log.Error("failed to rollback transaction", sl.Err(err))
+	for attempt := 1; ; attempt++ {
+		err = s.runTransaction(ctx, op, opts, fn)
+		if err == nil || attempt >= s.txMaxAttempts || !isTxConflict(err) {
+			return err
+		}
+
+		delay := txRetryDelay(attempt)
+		s.log.WarnContext(ctx, "transaction aborted by a conflict, retrying", slog.String("op", op),
+			slog.Int("attempt", attempt+1), slog.Duration("delay", delay), sl.Err(err))
+
+		if err := sleep(ctx, delay); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+}
+
+// errRollback is returned by fn to roll the transaction back without failing it, as a dry run does after
+// making its changes to see their outcome. A transaction joined to one begun by the caller has no rollback
+// of its own, so a dry run must begin its transaction.
+var errRollback = errors.New("transaction rolled back")
+
+// runTransaction makes a single attempt of transactionWithOptions.
+func (s *BaseService) runTransaction(ctx context.Context, op string, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	var fnErr error
+
+	err := s.db.Do(ctx, opts, func(ctx context.Context) error {
+		fnErr = fn(ctx)
+		return fnErr
+	})
+	if err != nil {
+		if errors.Is(fnErr, errRollback) {
+			return nil
+		}
+
+		if fnErr == nil {
+			// The transaction failed to begin or to commit.
+			return fmt.Errorf("%s: %w", op, err)
 		}
-	}()
 
-	if err := fn(tx); err != nil {
 		return err
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	if opts == nil || !opts.ReadOnly {
+		s.readCache.Invalidate()
 	}
 
 	return nil
 }
+
+const (
+	defaultTxMaxAttempts = 3
+	// txRetryBaseDelay and txRetryMaxDelay bound the exponential backoff with full jitter between the attempts
+	// of a transaction, so that the transactions that conflicted do not run into each other again.
+	txRetryBaseDelay = 10 * time.Millisecond
+	txRetryMaxDelay  = 200 * time.Millisecond
+)
+
+// SQLSTATE codes of the PostgreSQL errors that abort a transaction which succeeds when run again.
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// isTxConflict reports whether err aborted a transaction for a conflict with a concurrent one.
+// The errors of the driver, such as *pq.Error, report their SQLSTATE code.
+func isTxConflict(err error) bool {
+	var stateErr interface{ SQLState() string }
+	if !errors.As(err, &stateErr) {
+		return false
+	}
+
+	switch stateErr.SQLState() {
+	case sqlStateSerializationFailure, sqlStateDeadlockDetected:
+		return true
+	}
+
+	return false
+}
+
+// txRetryDelay returns the delay before the attempt following the given one.
+func txRetryDelay(attempt int) time.Duration {
+	delay := min(txRetryBaseDelay<<min(attempt-1, 16), txRetryMaxDelay)
+
+	return time.Duration(rand.Int64N(int64(delay) + 1))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// slackMemberIDPattern matches a Slack member ID such as U024BE7LH; workspaces on Enterprise Grid
+// issue IDs starting with W.
+var slackMemberIDPattern = regexp.MustCompile(`^[UW][A-Z0-9]+$`)
+
+// SlackUserService manages the mapping of users to Slack member IDs, through which the Slack notifier
+// finds the reviewer to send a direct message to.
+type SlackUserService interface {
+	// SetSlackUser maps a user to a Slack member ID, replacing its previous mapping.
+	// Returns apperrors.ErrNotFound if the user does not exist.
+	SetSlackUser(ctx context.Context, userID, slackUserID string) (*api.SlackUser, error)
+	// ListSlackUsers returns every mapping ordered by user ID.
+	ListSlackUsers(ctx context.Context) (*api.ListSlackUsersResponse, error)
+	// DeleteSlackUser removes the mapping of a user.
+	DeleteSlackUser(ctx context.Context, userID string) error
+}
+
+type SlackUserServiceImpl struct {
+	BaseService
+	repo repository.SlackUserRepository
+}
+
+// NewSlackUserService creates a new instance of SlackUserServiceImpl.
+func NewSlackUserService(repo repository.SlackUserRepository, log *slog.Logger) *SlackUserServiceImpl {
+	return &SlackUserServiceImpl{
+		BaseService: NewBaseService(nil, log),
+		repo:        repo,
+	}
+}
+
+func (s *SlackUserServiceImpl) SetSlackUser(ctx context.Context, userID, slackUserID string) (*api.SlackUser, error) {
+	const op = "internal.service.slack_user.SetSlackUser"
+
+	slackID := strings.ToUpper(strings.TrimSpace(slackUserID))
+	if !slackMemberIDPattern.MatchString(slackID) {
+		return nil, fmt.Errorf("%w: slack_user_id must be a Slack member ID such as U024BE7LH", apperrors.ErrValidation)
+	}
+
+	stored, err := s.repo.SetSlackUser(ctx, &domain.SlackUser{UserID: userID, SlackUserID: slackID})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to set slack user: %w", op, err)
+	}
+
+	s.log.Info("slack user mapped", slog.String("op", op), slog.String("user_id", userID),
+		slog.String("slack_user_id", slackID))
+
+	return toAPISlackUser(stored), nil
+}
+
+func (s *SlackUserServiceImpl) ListSlackUsers(ctx context.Context) (*api.ListSlackUsersResponse, error) {
+	const op = "internal.service.slack_user.ListSlackUsers"
+
+	mappings, err := s.repo.ListSlackUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list slack users: %w", op, err)
+	}
+
+	items := make([]api.SlackUser, len(mappings))
+	for i := range mappings {
+		items[i] = *toAPISlackUser(&mappings[i])
+	}
+
+	return &api.ListSlackUsersResponse{Items: items, TotalEstimate: totalOf(items)}, nil
+}
+
+func (s *SlackUserServiceImpl) DeleteSlackUser(ctx context.Context, userID string) error {
+	const op = "internal.service.slack_user.DeleteSlackUser"
+
+	if err := s.repo.DeleteSlackUser(ctx, userID); err != nil {
+		return fmt.Errorf("%s: failed to delete slack user: %w", op, err)
+	}
+
+	s.log.Info("slack user unmapped", slog.String("op", op), slog.String("user_id", userID))
+
+	return nil
+}
+
+func toAPISlackUser(m *domain.SlackUser) *api.SlackUser {
+	return &api.SlackUser{UserId: m.UserID, SlackUserId: m.SlackUserID, CreatedAt: m.CreatedAt}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackUserServiceImpl_SetSlackUser(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	now := testNow.UTC()
+
+	t.Run("Member ID is stored in upper case", func(t *testing.T) {
+		repo := new(SlackUserRepositoryMock)
+		repo.On("SetSlackUser", ctx, &domain.SlackUser{UserID: "u1", SlackUserID: "U024BE7LH"}).
+			Return(&domain.SlackUser{UserID: "u1", SlackUserID: "U024BE7LH", CreatedAt: now}, nil).Once()
+
+		mapping, err := NewSlackUserService(repo, logger).SetSlackUser(ctx, "u1", " u024be7lh ")
+
+		require.NoError(t, err)
+		assert.Equal(t, &api.SlackUser{UserId: "u1", SlackUserId: "U024BE7LH", CreatedAt: now}, mapping)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		repo := new(SlackUserRepositoryMock)
+		repo.On("SetSlackUser", ctx, &domain.SlackUser{UserID: "ghost", SlackUserID: "U024BE7LH"}).Return(nil, apperrors.ErrNotFound).Once()
+
+		_, err := NewSlackUserService(repo, logger).SetSlackUser(ctx, "ghost", "U024BE7LH")
+
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	for _, slackUserID := range []string{"", "  ", "@jdoe", "C024BE7LH", "U024-BE7"} {
+		t.Run("Invalid member ID "+slackUserID, func(t *testing.T) {
+			_, err := NewSlackUserService(new(SlackUserRepositoryMock), logger).SetSlackUser(ctx, "u1", slackUserID)
+
+			assert.ErrorIs(t, err, apperrors.ErrValidation)
+		})
+	}
+}
+
+func TestSlackUserServiceImpl_ListSlackUsers(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	repo := new(SlackUserRepositoryMock)
+	repo.On("ListSlackUsers", ctx).Return([]domain.SlackUser{{UserID: "u1", SlackUserID: "U024BE7LH"}}, nil).Once()
+
+	resp, err := NewSlackUserService(repo, logger).ListSlackUsers(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, []api.SlackUser{{UserId: "u1", SlackUserId: "U024BE7LH"}}, resp.Items)
+	assert.Nil(t, resp.NextCursor)
+	require.NotNil(t, resp.TotalEstimate)
+	assert.Equal(t, 1, *resp.TotalEstimate)
+}
+
+func TestSlackUserServiceImpl_DeleteSlackUser(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	repo := new(SlackUserRepositoryMock)
+	repo.On("DeleteSlackUser", ctx, "u1").Return(nil).Once()
+	repo.On("DeleteSlackUser", ctx, "ghost").Return(apperrors.ErrNotFound).Once()
+
+	s := NewSlackUserService(repo, logger)
+
+	require.NoError(t, s.DeleteSlackUser(ctx, "u1"))
+	assert.ErrorIs(t, s.DeleteSlackUser(ctx, "ghost"), apperrors.ErrNotFound)
+	repo.AssertExpectations(t)
+}
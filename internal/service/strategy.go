@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository"
+)
+
+// AssignmentStrategy selects reviewer candidates from a team.
+type AssignmentStrategy interface {
+	// Name identifies the strategy in team policies and in the assignment history.
+	Name() domain.AssignmentStrategy
+	// Select returns up to count active reviewers from the team, skipping the excluded users.
+	Select(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error)
+}
+
+type randomStrategy struct {
+	repo repository.UserPRRepository
+}
+
+func (s *randomStrategy) Name() domain.AssignmentStrategy { return domain.StrategyRandom }
+
+func (s *randomStrategy) Select(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error) {
+	return s.repo.GetRandomActiveReviewers(ctx, teamID, excludeUserIDs, count)
+}
+
+type leastLoadedStrategy struct {
+	repo repository.UserPRRepository
+}
+
+func (s *leastLoadedStrategy) Name() domain.AssignmentStrategy { return domain.StrategyLeastLoaded }
+
+func (s *leastLoadedStrategy) Select(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error) {
+	return s.repo.GetLeastLoadedActiveReviewers(ctx, teamID, excludeUserIDs, count)
+}
+
+type roundRobinStrategy struct {
+	repo repository.UserPRRepository
+}
+
+func (s *roundRobinStrategy) Name() domain.AssignmentStrategy { return domain.StrategyRoundRobin }
+
+func (s *roundRobinStrategy) Select(ctx context.Context, teamID int, excludeUserIDs []string, count int) ([]string, error) {
+	return s.repo.GetLeastRecentlyAssignedActiveReviewers(ctx, teamID, excludeUserIDs, count)
+}
+
+// reviewerSelector resolves a team's policy and dispatches each assignment
+// to a strategy drawn according to the policy's strategy weights.
+type reviewerSelector struct {
+	policies   repository.PolicyRepository
+	strategies map[domain.AssignmentStrategy]AssignmentStrategy
+	fallback   domain.AssignmentStrategy
+	intn       func(n int) int
+}
+
+func newReviewerSelector(policies repository.PolicyRepository, userPR repository.UserPRRepository) *reviewerSelector {
+	strategies := []AssignmentStrategy{
+		&randomStrategy{repo: userPR},
+		&leastLoadedStrategy{repo: userPR},
+		&roundRobinStrategy{repo: userPR},
+	}
+
+	byName := make(map[domain.AssignmentStrategy]AssignmentStrategy, len(strategies))
+	for _, strategy := range strategies {
+		byName[strategy.Name()] = strategy
+	}
+
+	return &reviewerSelector{
+		policies:   policies,
+		strategies: byName,
+		fallback:   domain.StrategyRandom,
+		intn:       rand.Intn,
+	}
+}
+
+// register adds strategy to the selector, replacing the strategy of the same name if there is one.
+func (s *reviewerSelector) register(strategy AssignmentStrategy) {
+	s.strategies[strategy.Name()] = strategy
+}
+
+// isKnownStrategy reports whether a strategy name can be used in a team policy.
+func (s *reviewerSelector) isKnownStrategy(name domain.AssignmentStrategy) bool {
+	_, ok := s.strategies[name]
+	return ok
+}
+
+// policy loads the team policy. Without a policy repository every team gets an empty policy.
+func (s *reviewerSelector) policy(ctx context.Context, teamID int) (*domain.TeamPolicy, error) {
+	if s.policies == nil {
+		return &domain.TeamPolicy{TeamID: teamID}, nil
+	}
+
+	return s.policies.GetTeamPolicy(ctx, teamID)
+}
+
+// choose draws a strategy proportionally to the policy weights.
+// Unknown strategies and non-positive weights are ignored; if nothing is left, the fallback strategy is used.
+func (s *reviewerSelector) choose(policy *domain.TeamPolicy) AssignmentStrategy {
+	total := 0
+
+	for name, weight := range policy.StrategyWeights {
+		if weight > 0 && s.isKnownStrategy(name) {
+			total += weight
+		}
+	}
+
+	if total == 0 {
+		return s.strategies[s.fallback]
+	}
+
+	point := s.intn(total)
+
+	// Iterate in a fixed order so that the draw is reproducible for a given random number.
+	for _, name := range s.orderedNames() {
+		weight := policy.StrategyWeights[name]
+		if weight <= 0 {
+			continue
+		}
+
+		if point < weight {
+			return s.strategies[name]
+		}
+
+		point -= weight
+	}
+
+	return s.strategies[s.fallback]
+}
+
+func (s *reviewerSelector) orderedNames() []domain.AssignmentStrategy {
+	return slices.Sorted(maps.Keys(s.strategies))
+}
+
+// selectReviewers picks up to count reviewers from the team with a strategy drawn from the team policy.
+// It returns the selected user IDs together with the name of the strategy that produced them.
+func (s *reviewerSelector) selectReviewers(
+	ctx context.Context,
+	teamID int,
+	excludeUserIDs []string,
+	count int,
+) ([]string, domain.AssignmentStrategy, error) {
+	policy, err := s.policy(ctx, teamID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get team policy: %w", err)
+	}
+
+	return s.selectWithPolicy(ctx, policy, excludeUserIDs, count)
+}
+
+// selectWithPolicy is like selectReviewers but uses an already loaded policy,
+// which lets bulk operations resolve the policy once per team.
+func (s *reviewerSelector) selectWithPolicy(
+	ctx context.Context,
+	policy *domain.TeamPolicy,
+	excludeUserIDs []string,
+	count int,
+) ([]string, domain.AssignmentStrategy, error) {
+	strategy := s.choose(policy)
+
+	reviewerIDs, err := strategy.Select(ctx, policy.TeamID, excludeUserIDs, count)
+	if err != nil {
+		return nil, "", fmt.Errorf("strategy %s failed: %w", strategy.Name(), err)
+	}
+
+	return reviewerIDs, strategy.Name(), nil
+}
+
+// snapshot records the policy the strategy was drawn from, with the weights the selector actually applied.
+func (s *reviewerSelector) snapshot(policy *domain.TeamPolicy, strategy domain.AssignmentStrategy, count int) domain.AssignmentPolicySnapshot {
+	weights := make(map[domain.AssignmentStrategy]int, len(policy.StrategyWeights))
+	for name, weight := range policy.StrategyWeights {
+		if weight > 0 && s.isKnownStrategy(name) {
+			weights[name] = weight
+		}
+	}
+
+	if len(weights) == 0 {
+		weights[s.fallback] = 1
+	}
+
+	action := domain.QuotaReject
+	if policy.OverQuotaAction != "" {
+		action = policy.OverQuotaAction
+	}
+
+	snapshot := domain.AssignmentPolicySnapshot{
+		TeamID:            policy.TeamID,
+		StrategyWeights:   weights,
+		Strategy:          strategy,
+		ReviewersPerPR:    count,
+		AuthorOpenPRLimit: policy.AuthorOpenPRLimit,
+		OverQuotaAction:   action,
+	}
+
+	if !policy.UpdatedAt.IsZero() {
+		updatedAt := policy.UpdatedAt
+		snapshot.PolicyUpdatedAt = &updatedAt
+	}
+
+	return snapshot
+}
+
+func assignmentRecords(prID string, reviewerIDs []string, strategy domain.AssignmentStrategy, cause domain.AssignmentCause, at time.Time) []domain.AssignmentRecord {
+	records := make([]domain.AssignmentRecord, len(reviewerIDs))
+	for i, id := range reviewerIDs {
+		records[i] = domain.AssignmentRecord{
+			PullRequestID: prID,
+			UserID:        id,
+			Strategy:      strategy,
+			Reason:        strategy.Reason(),
+			Cause:         cause,
+			CreatedAt:     at,
+		}
+	}
+
+	return records
+}
+
+func replacementRecord(prID, oldReviewerID, newReviewerID string, strategy domain.AssignmentStrategy, cause domain.AssignmentCause, at time.Time) domain.AssignmentRecord {
+	replaced := oldReviewerID
+
+	return domain.AssignmentRecord{
+		PullRequestID:  prID,
+		UserID:         newReviewerID,
+		ReplacedUserID: &replaced,
+		Strategy:       strategy,
+		Reason:         strategy.Reason(),
+		Cause:          cause,
+		CreatedAt:      at,
+	}
+}
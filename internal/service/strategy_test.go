@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewerSelector_Choose(t *testing.T) {
+	testCases := []struct {
+		name     string
+		weights  map[domain.AssignmentStrategy]int
+		draw     int
+		expected domain.AssignmentStrategy
+	}{
+		{
+			name:     "Empty policy falls back to random",
+			weights:  nil,
+			expected: domain.StrategyRandom,
+		},
+		{
+			name:     "Only zero weights fall back to random",
+			weights:  map[domain.AssignmentStrategy]int{domain.StrategyLeastLoaded: 0},
+			expected: domain.StrategyRandom,
+		},
+		{
+			name:     "Unknown strategies are ignored",
+			weights:  map[domain.AssignmentStrategy]int{"unknown": 10, domain.StrategyLeastLoaded: 1},
+			draw:     0,
+			expected: domain.StrategyLeastLoaded,
+		},
+		{
+			name:     "Low draw hits the first strategy",
+			weights:  map[domain.AssignmentStrategy]int{domain.StrategyRandom: 90, domain.StrategyLeastLoaded: 10},
+			draw:     5,
+			expected: domain.StrategyLeastLoaded,
+		},
+		{
+			name:     "High draw hits the second strategy",
+			weights:  map[domain.AssignmentStrategy]int{domain.StrategyRandom: 90, domain.StrategyLeastLoaded: 10},
+			draw:     10,
+			expected: domain.StrategyRandom,
+		},
+		{
+			name:     "Round robin by weight",
+			weights:  map[domain.AssignmentStrategy]int{domain.StrategyRoundRobin: 1},
+			expected: domain.StrategyRoundRobin,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			selector := newReviewerSelector(nil, nil)
+			selector.intn = func(n int) int { return tc.draw }
+
+			strategy := selector.choose(&domain.TeamPolicy{TeamID: 1, StrategyWeights: tc.weights})
+
+			assert.Equal(t, tc.expected, strategy.Name())
+		})
+	}
+}
+
+// fixedStrategy is a custom strategy that always selects the same reviewers.
+type fixedStrategy struct {
+	name        domain.AssignmentStrategy
+	reviewerIDs []string
+}
+
+func (s *fixedStrategy) Name() domain.AssignmentStrategy { return s.name }
+
+func (s *fixedStrategy) Select(context.Context, int, []string, int) ([]string, error) {
+	return s.reviewerIDs, nil
+}
+
+func TestPullRequestServiceImpl_StrategyOptions(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	emptyPolicy := &domain.TeamPolicy{TeamID: 1}
+
+	t.Run("Default strategy", func(t *testing.T) {
+		userPRMock := new(UserPRRepositoryMock)
+		userPRMock.On("GetLeastRecentlyAssignedActiveReviewers", mock.Anything, 1, []string{"u1"}, 2).
+			Return([]string{"u3", "u2"}, nil).Once()
+
+		s := NewPullRequestService(nil, log, nil, nil, userPRMock, nil, nil, WithDefaultStrategy(domain.StrategyRoundRobin))
+
+		reviewerIDs, strategy, err := s.selector.selectWithPolicy(context.Background(), emptyPolicy, []string{"u1"}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u3", "u2"}, reviewerIDs)
+		assert.Equal(t, domain.StrategyRoundRobin, strategy)
+		userPRMock.AssertExpectations(t)
+	})
+
+	t.Run("Custom strategy replaces the built-in one", func(t *testing.T) {
+		custom := &fixedStrategy{name: domain.StrategyRandom, reviewerIDs: []string{"u9"}}
+		s := NewPullRequestService(nil, log, nil, nil, new(UserPRRepositoryMock), nil, nil, WithAssignmentStrategy(custom))
+
+		reviewerIDs, strategy, err := s.selector.selectWithPolicy(context.Background(), emptyPolicy, []string{"u1"}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u9"}, reviewerIDs)
+		assert.Equal(t, domain.StrategyRandom, strategy)
+	})
+
+	t.Run("Custom strategy as the default", func(t *testing.T) {
+		custom := &fixedStrategy{name: "by_tenure", reviewerIDs: []string{"u7", "u8"}}
+		s := NewPullRequestService(nil, log, nil, nil, new(UserPRRepositoryMock), nil, nil,
+			WithAssignmentStrategy(custom), WithDefaultStrategy("by_tenure"))
+
+		reviewerIDs, strategy, err := s.selector.selectWithPolicy(context.Background(), emptyPolicy, []string{"u1"}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u7", "u8"}, reviewerIDs)
+		assert.Equal(t, domain.AssignmentStrategy("by_tenure"), strategy)
+	})
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+func (s *PullRequestServiceImpl) SubscribePR(ctx context.Context, prID string, userID string) (*api.PullRequestSubscription, bool, error) {
+	const op = "internal.service.pullrequest.SubscribePR"
+
+	if s.subscriptions == nil {
+		return nil, false, fmt.Errorf("%w: pull request subscriptions are disabled", apperrors.ErrValidation)
+	}
+
+	sub, created, err := s.subscriptions.Subscribe(ctx, prID, userID)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if created {
+		s.log.Info("user subscribed to pr", slog.String("op", op), slog.String("pr_id", prID), slog.String("user_id", userID))
+	}
+
+	return toAPIPullRequestSubscription(sub), created, nil
+}
+
+// notify hands event to the notifier together with the subscribers of its pull request.
+// Like the delivery itself, looking up the subscribers is best effort: if it fails,
+// the event still reaches the users it is addressed to.
+func (s *PullRequestServiceImpl) notify(ctx context.Context, event domain.Event) {
+	if s.subscriptions != nil {
+		subscriberIDs, err := s.subscriptions.GetSubscriberIDs(ctx, event.PullRequestID)
+		if err != nil {
+			s.log.Warn("failed to get pr subscribers", slog.String("pr_id", event.PullRequestID), sl.Err(err))
+		}
+
+		for _, id := range subscriberIDs {
+			if !slices.Contains(event.UserIDs, id) {
+				event.SubscriberIDs = append(event.SubscriberIDs, id)
+			}
+		}
+	}
+
+	s.notifier.Notify(ctx, event)
+}
+
+func toAPIPullRequestSubscription(sub *domain.PRSubscription) *api.PullRequestSubscription {
+	return &api.PullRequestSubscription{
+		PullRequestId: sub.PullRequestID,
+		UserId:        sub.UserID,
+		CreatedAt:     sub.CreatedAt,
+	}
+}
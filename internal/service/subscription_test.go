@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestServiceImpl_SubscribePR(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	sub := &domain.PRSubscription{PullRequestID: "pr-1", UserID: "stakeholder", CreatedAt: testNow.UTC()}
+
+	testCases := []struct {
+		name            string
+		setupMocks      func(subs *SubscriptionRepositoryMock)
+		disabled        bool
+		expectedCreated bool
+		expectedErr     error
+	}{
+		{
+			name: "New subscription",
+			setupMocks: func(subs *SubscriptionRepositoryMock) {
+				subs.On("Subscribe", mock.Anything, "pr-1", "stakeholder").Return(sub, true, nil).Once()
+			},
+			expectedCreated: true,
+		},
+		{
+			name: "Repeated subscription",
+			setupMocks: func(subs *SubscriptionRepositoryMock) {
+				subs.On("Subscribe", mock.Anything, "pr-1", "stakeholder").Return(sub, false, nil).Once()
+			},
+		},
+		{
+			name: "Unknown PR or user",
+			setupMocks: func(subs *SubscriptionRepositoryMock) {
+				subs.On("Subscribe", mock.Anything, "pr-1", "stakeholder").Return(nil, false, apperrors.ErrNotFound).Once()
+			},
+			expectedErr: apperrors.ErrNotFound,
+		},
+		{
+			name:        "Subscriptions disabled",
+			setupMocks:  func(subs *SubscriptionRepositoryMock) {},
+			disabled:    true,
+			expectedErr: apperrors.ErrValidation,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			subsMock := new(SubscriptionRepositoryMock)
+			tc.setupMocks(subsMock)
+
+			var opts []PullRequestServiceOption
+			if !tc.disabled {
+				opts = append(opts, WithSubscriptions(subsMock))
+			}
+
+			service := NewPullRequestService(new(TransactorMock), logger, nil, nil, nil, nil, nil, opts...)
+			got, created, err := service.SubscribePR(ctx, "pr-1", "stakeholder")
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedCreated, created)
+				assert.Equal(t, &api.PullRequestSubscription{PullRequestId: "pr-1", UserId: "stakeholder", CreatedAt: testNow.UTC()}, got)
+			}
+
+			subsMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPullRequestServiceImpl_NotifiesSubscribers(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	prID := "pr-watched"
+
+	testCases := []struct {
+		name                  string
+		subscriberIDs         []string
+		subscribersErr        error
+		expectedSubscriberIDs []string
+	}{
+		{
+			name:                  "Subscribers who are not reviewers are added to the event",
+			subscriberIDs:         []string{"rev1", "stakeholder1", "stakeholder2"},
+			expectedSubscriberIDs: []string{"stakeholder1", "stakeholder2"},
+		},
+		{
+			name:          "Without subscribers",
+			subscriberIDs: []string{},
+		},
+		{
+			name:           "Failed lookup still notifies the reviewers",
+			subscribersErr: errors.New("db is down"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transactorMock := new(TransactorMock)
+			prCmdMock := new(PRCommandRepositoryMock)
+			prQueryMock := new(PRQueryRepositoryMock)
+			subsMock := new(SubscriptionRepositoryMock)
+			notifierMock := new(NotifierMock)
+
+			_, mockedTx, smock := newMockDBAndTx(t)
+			smock.ExpectCommit()
+
+			transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+			prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).
+				Return(&domain.PullRequest{ID: prID, Status: api.PullRequestStatusOPEN}, nil).Once()
+			prCmdMock.On("UpdatePRStatus", inTx(mockedTx), prID, api.PullRequestStatusCLOSED, testNow.UTC()).Return(nil, nil).Once()
+			prQueryMock.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{"rev1"}, nil).Once()
+			subsMock.On("GetSubscriberIDs", mock.Anything, prID).Return(tc.subscriberIDs, tc.subscribersErr).Once()
+			notifierMock.On("Notify", mock.Anything, mock.MatchedBy(func(event domain.Event) bool {
+				return event.Type == domain.EventPRClosed &&
+					assert.ObjectsAreEqual([]string{"rev1"}, event.UserIDs) &&
+					assert.ObjectsAreEqual(tc.expectedSubscriberIDs, event.SubscriberIDs)
+			})).Once()
+
+			service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil, nil, nil,
+				WithSubscriptions(subsMock), WithNotifier(notifierMock), WithClock(fixedClock(testNow)))
+			_, err := service.ClosePR(ctx, prID)
+
+			require.NoError(t, err)
+			subsMock.AssertExpectations(t)
+			notifierMock.AssertExpectations(t)
+		})
+	}
+}
@@ -4,52 +4,451 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
 	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/localcache"
 	"github.com/YusovID/pr-reviewer-service/internal/repository"
+	"github.com/YusovID/pr-reviewer-service/internal/validation"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
-	"github.com/jmoiron/sqlx"
 )
 
 // TeamService defines the application's business logic for managing teams.
 type TeamService interface {
 	// CreateTeamWithUsers handles the creation of a new team and its members.
 	// It ensures that a team with the same name does not already exist.
+	// With WithCaseInsensitiveUsernames it returns apperrors.ErrValidation for members whose usernames differ only in case.
 	CreateTeamWithUsers(ctx context.Context, team api.Team) (*api.Team, error)
 	// GetTeam retrieves a team by its name, including all its members.
 	GetTeam(ctx context.Context, name string) (*api.Team, error)
+	// GetTeamByID retrieves a team by its ID, including all its members.
+	GetTeamByID(ctx context.Context, id int) (*api.Team, error)
+	// RenameTeam changes the name of a team while keeping its ID, members, policy and pull requests.
+	// Returns apperrors.ErrAlreadyExists if another team already has the new name.
+	RenameTeam(ctx context.Context, teamName, newTeamName string) (*api.Team, error)
+	// SetTeamPolicy replaces the reviewer assignment policy of a team.
+	// Returns apperrors.ErrValidation for unknown strategies or negative weights.
+	SetTeamPolicy(ctx context.Context, policy api.TeamPolicy) (*api.TeamPolicy, error)
+	// GetTeamPolicy returns the reviewer assignment policy of a team.
+	GetTeamPolicy(ctx context.Context, teamName string) (*api.TeamPolicy, error)
+	// GetEffectivePolicy returns the policy the pull requests of a team are actually handled with:
+	// the defaults of the service overlaid with the team policy, with the source of every setting.
+	GetEffectivePolicy(ctx context.Context, teamName string) (*api.EffectivePolicy, error)
+	// SetCustomFields replaces the custom fields that the pull requests of a team carry.
+	// Returns apperrors.ErrValidation for a malformed key, an unknown type, a key listed twice or too many fields.
+	SetCustomFields(ctx context.Context, fields api.TeamCustomFields) (*api.TeamCustomFields, error)
+	// GetCustomFields returns the custom pull request fields of a team.
+	GetCustomFields(ctx context.Context, teamName string) (*api.TeamCustomFields, error)
+	// RequestReviewerBorrow asks the lender team for count reviewers for durationHours.
+	// Returns apperrors.ErrValidation for a count or duration out of range or a team borrowing from itself.
+	RequestReviewerBorrow(ctx context.Context, teamName, lenderTeamName string, count, durationHours int) (*api.ReviewerBorrow, error)
+	// AcceptReviewerBorrow lends the requested reviewers, which makes them eligible for the borrowing team until the borrow expires.
+	// Returns apperrors.ErrBorrowNotPending if the borrow has already been accepted.
+	AcceptReviewerBorrow(ctx context.Context, borrowID int64) (*api.ReviewerBorrow, error)
+	// ListReviewerBorrows returns the unexpired borrows of a team, either as the borrower or as the lender.
+	ListReviewerBorrows(ctx context.Context, teamName string) ([]api.ReviewerBorrow, error)
+}
+
+// Limits of a reviewer borrow request.
+const (
+	maxBorrowCount         = 10
+	maxBorrowDurationHours = 30 * 24
+)
+
+// PolicyDefaults are the settings of the service that apply to the pull requests of every team
+// unless the team policy overrides them.
+type PolicyDefaults struct {
+	// DefaultStrategy picks the reviewers of teams whose policy sets no strategy weights.
+	DefaultStrategy domain.AssignmentStrategy
+	// RequireApprovals and StrictChecks mirror WithRequiredApprovals and WithStrictChecks; no team overrides them.
+	RequireApprovals bool
+	StrictChecks     bool
 }
 
 type TeamServiceImpl struct {
-	repo repository.TeamRepository
-	db   *sqlx.DB
+	repo       repository.TeamRepository
+	policyRepo repository.PolicyRepository
+	borrowRepo repository.BorrowRepository
+	fieldRepo  repository.CustomFieldRepository
+	clock      Clock
+	defaults   PolicyDefaults
+	// queries serves GetTeam and GetTeamByID, see WithTeamQueries.
+	queries repository.TeamRepository
+	// readCache holds the teams returned by GetTeam; the writes of the service invalidate it.
+	readCache *localcache.Cache
+
+	caseInsensitiveUsernames bool
+}
+
+// TeamServiceOption configures optional behaviour of TeamServiceImpl.
+type TeamServiceOption func(*TeamServiceImpl)
+
+// WithCaseInsensitiveUsernames makes CreateTeamWithUsers reject a team whose members' usernames
+// differ only in case, such as "Иван" and "иван".
+func WithCaseInsensitiveUsernames() TeamServiceOption {
+	return func(s *TeamServiceImpl) {
+		s.caseInsensitiveUsernames = true
+	}
+}
+
+// WithPolicyDefaults sets the defaults GetEffectivePolicy overlays with the team policies. They only describe
+// the settings, so they must match the options the pull request service is created with.
+func WithPolicyDefaults(defaults PolicyDefaults) TeamServiceOption {
+	return func(s *TeamServiceImpl) {
+		s.defaults = defaults
+	}
+}
+
+// WithTeamQueries makes GetTeam and GetTeamByID read through repo instead of the repository the service
+// is created with, e.g. through a repository reading from a replica. The other methods read the teams they
+// change through the latter.
+func WithTeamQueries(repo repository.TeamRepository) TeamServiceOption {
+	return func(s *TeamServiceImpl) {
+		s.queries = repo
+	}
+}
+
+// WithTeamReadCache makes GetTeam read through cache, which CreateTeamWithUsers and RenameTeam invalidate.
+// The writes of the user and pull request services must invalidate the same cache.
+func WithTeamReadCache(cache *localcache.Cache) TeamServiceOption {
+	return func(s *TeamServiceImpl) {
+		s.readCache = cache
+	}
 }
 
 // NewTeamService creates a new instance of TeamServiceImpl.
-func NewTeamService(repo repository.TeamRepository, db *sqlx.DB) *TeamServiceImpl {
-	return &TeamServiceImpl{
-		repo: repo,
-		db:   db,
+func NewTeamService(
+	repo repository.TeamRepository,
+	policyRepo repository.PolicyRepository,
+	borrowRepo repository.BorrowRepository,
+	fieldRepo repository.CustomFieldRepository,
+	opts ...TeamServiceOption,
+) *TeamServiceImpl {
+	s := &TeamServiceImpl{
+		repo:       repo,
+		queries:    repo,
+		policyRepo: policyRepo,
+		borrowRepo: borrowRepo,
+		fieldRepo:  fieldRepo,
+		clock:      systemClock{},
+		defaults:   PolicyDefaults{DefaultStrategy: domain.StrategyRandom},
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
 }
 
 func (s *TeamServiceImpl) CreateTeamWithUsers(ctx context.Context, team api.Team) (*api.Team, error) {
+	if s.caseInsensitiveUsernames {
+		if err := checkUsernamesUnique(team.Members); err != nil {
+			return nil, err
+		}
+	}
+
 	domainTeamWithMembers, err := s.repo.CreateTeamWithUsers(ctx, team)
 	if err != nil {
 		return nil, fmt.Errorf("repo.CreateTeamWithUsers failed: %w", err)
 	}
 
+	s.readCache.Invalidate()
+
 	return toAPITeam(domainTeamWithMembers), nil
 }
 
 func (s *TeamServiceImpl) GetTeam(ctx context.Context, name string) (*api.Team, error) {
-	domainTeam, err := s.repo.GetTeamByName(ctx, s.db, name)
+	domainTeam, err := localcache.Load(ctx, s.readCache, "team", name, func() (*domain.TeamWithMembers, error) {
+		return s.queries.GetTeamByName(ctx, name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetTeamByName failed: %w", err)
+	}
+
+	return toAPITeam(domainTeam), nil
+}
+
+func (s *TeamServiceImpl) GetTeamByID(ctx context.Context, id int) (*api.Team, error) {
+	domainTeam, err := s.queries.GetTeamByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetTeamByID failed: %w", err)
+	}
+
+	return toAPITeam(domainTeam), nil
+}
+
+func (s *TeamServiceImpl) RenameTeam(ctx context.Context, teamName, newTeamName string) (*api.Team, error) {
+	domainTeam, err := s.repo.GetTeamByName(ctx, teamName)
 	if err != nil {
 		return nil, fmt.Errorf("repo.GetTeamByName failed: %w", err)
 	}
 
+	if newTeamName != domainTeam.Name {
+		if err := s.repo.RenameTeam(ctx, domainTeam.ID, newTeamName); err != nil {
+			return nil, fmt.Errorf("repo.RenameTeam failed: %w", err)
+		}
+
+		s.readCache.Invalidate()
+
+		domainTeam.Name = newTeamName
+	}
+
 	return toAPITeam(domainTeam), nil
 }
 
+func (s *TeamServiceImpl) SetTeamPolicy(ctx context.Context, policy api.TeamPolicy) (*api.TeamPolicy, error) {
+	weights := make(map[domain.AssignmentStrategy]int, len(policy.StrategyWeights))
+
+	for name, weight := range policy.StrategyWeights {
+		strategy := domain.AssignmentStrategy(name)
+		if !strategy.IsValid() {
+			return nil, fmt.Errorf("%w: unknown strategy '%s'", apperrors.ErrValidation, name)
+		}
+
+		if weight < 0 {
+			return nil, fmt.Errorf("%w: weight of strategy '%s' must not be negative", apperrors.ErrValidation, name)
+		}
+
+		weights[strategy] = weight
+	}
+
+	if policy.AuthorOpenPrLimit != nil && *policy.AuthorOpenPrLimit < 1 {
+		return nil, fmt.Errorf("%w: author open PR limit must be positive", apperrors.ErrValidation)
+	}
+
+	action := domain.QuotaReject
+	if policy.OverQuotaAction != nil {
+		action = domain.QuotaAction(*policy.OverQuotaAction)
+		if !action.IsValid() {
+			return nil, fmt.Errorf("%w: unknown over quota action '%s'", apperrors.ErrValidation, action)
+		}
+	}
+
+	rebalance := domain.RebalanceOff
+	if policy.ReactivationRebalance != nil {
+		rebalance = domain.RebalanceMode(*policy.ReactivationRebalance)
+		if !rebalance.IsValid() {
+			return nil, fmt.Errorf("%w: unknown reactivation rebalance mode '%s'", apperrors.ErrValidation, rebalance)
+		}
+	}
+
+	team, err := s.repo.GetTeamByName(ctx, policy.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetTeamByName failed: %w", err)
+	}
+
+	saved, err := s.policyRepo.UpsertTeamPolicy(ctx, &domain.TeamPolicy{
+		TeamID:                team.ID,
+		StrategyWeights:       weights,
+		AuthorOpenPRLimit:     policy.AuthorOpenPrLimit,
+		OverQuotaAction:       action,
+		ReactivationRebalance: rebalance,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("policyRepo.UpsertTeamPolicy failed: %w", err)
+	}
+
+	return toAPITeamPolicy(team, saved), nil
+}
+
+func (s *TeamServiceImpl) GetTeamPolicy(ctx context.Context, teamName string) (*api.TeamPolicy, error) {
+	team, err := s.repo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetTeamByName failed: %w", err)
+	}
+
+	policy, err := s.policyRepo.GetTeamPolicy(ctx, team.ID)
+	if err != nil {
+		return nil, fmt.Errorf("policyRepo.GetTeamPolicy failed: %w", err)
+	}
+
+	return toAPITeamPolicy(team, policy), nil
+}
+
+func (s *TeamServiceImpl) GetEffectivePolicy(ctx context.Context, teamName string) (*api.EffectivePolicy, error) {
+	team, err := s.repo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetTeamByName failed: %w", err)
+	}
+
+	policy, err := s.policyRepo.GetTeamPolicy(ctx, team.ID)
+	if err != nil {
+		return nil, fmt.Errorf("policyRepo.GetTeamPolicy failed: %w", err)
+	}
+
+	return s.effectivePolicy(team, policy), nil
+}
+
+// effectivePolicy overlays the defaults with policy. A team without a stored policy has a zero UpdatedAt,
+// so every setting then comes from the defaults.
+func (s *TeamServiceImpl) effectivePolicy(team *domain.TeamWithMembers, policy *domain.TeamPolicy) *api.EffectivePolicy {
+	stored := !policy.UpdatedAt.IsZero()
+
+	source := func(fromTeam bool) api.PolicySource {
+		if fromTeam {
+			return api.PolicySourceTeam
+		}
+
+		return api.PolicySourceDefault
+	}
+
+	// The selector skips non-positive weights and falls back to the default strategy when none is left.
+	weights := make(map[string]int, len(policy.StrategyWeights))
+	for strategy, weight := range policy.StrategyWeights {
+		if weight > 0 {
+			weights[string(strategy)] = weight
+		}
+	}
+
+	teamWeights := len(weights) > 0
+	if !teamWeights {
+		weights[string(s.defaults.DefaultStrategy)] = 1
+	}
+
+	action := api.TeamPolicyOverQuotaAction(domain.QuotaReject)
+	if stored && policy.OverQuotaAction != "" {
+		action = api.TeamPolicyOverQuotaAction(policy.OverQuotaAction)
+	}
+
+	rebalance := api.TeamPolicyReactivationRebalance(domain.RebalanceOff)
+	if stored && policy.ReactivationRebalance != "" {
+		rebalance = api.TeamPolicyReactivationRebalance(policy.ReactivationRebalance)
+	}
+
+	return &api.EffectivePolicy{
+		TeamName:              team.Name,
+		TeamId:                team.ID,
+		ReviewersPerPr:        reviewersPerPR,
+		StrategyWeights:       weights,
+		AuthorOpenPrLimit:     policy.AuthorOpenPRLimit,
+		OverQuotaAction:       action,
+		ReactivationRebalance: rebalance,
+		RequireApprovals:      s.defaults.RequireApprovals,
+		StrictChecks:          s.defaults.StrictChecks,
+		Sources: map[string]api.PolicySource{
+			"reviewers_per_pr":       api.PolicySourceDefault,
+			"strategy_weights":       source(teamWeights),
+			"author_open_pr_limit":   source(policy.AuthorOpenPRLimit != nil),
+			"over_quota_action":      source(stored),
+			"reactivation_rebalance": source(stored),
+			"require_approvals":      api.PolicySourceDefault,
+			"strict_checks":          api.PolicySourceDefault,
+		},
+	}
+}
+
+func (s *TeamServiceImpl) RequestReviewerBorrow(ctx context.Context, teamName, lenderTeamName string, count, durationHours int) (*api.ReviewerBorrow, error) {
+	if count < 1 || count > maxBorrowCount {
+		return nil, fmt.Errorf("%w: count must be between 1 and %d", apperrors.ErrValidation, maxBorrowCount)
+	}
+
+	if durationHours < 1 || durationHours > maxBorrowDurationHours {
+		return nil, fmt.Errorf("%w: duration must be between 1 and %d hours", apperrors.ErrValidation, maxBorrowDurationHours)
+	}
+
+	if teamName == lenderTeamName {
+		return nil, fmt.Errorf("%w: a team cannot borrow reviewers from itself", apperrors.ErrValidation)
+	}
+
+	team, err := s.repo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetTeamByName failed: %w", err)
+	}
+
+	lender, err := s.repo.GetTeamByName(ctx, lenderTeamName)
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetTeamByName failed: %w", err)
+	}
+
+	borrow, err := s.borrowRepo.CreateBorrow(ctx, &domain.ReviewerBorrow{
+		TeamID:        team.ID,
+		LenderTeamID:  lender.ID,
+		Count:         count,
+		DurationHours: durationHours,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("borrowRepo.CreateBorrow failed: %w", err)
+	}
+
+	return toAPIReviewerBorrow(borrow), nil
+}
+
+func (s *TeamServiceImpl) AcceptReviewerBorrow(ctx context.Context, borrowID int64) (*api.ReviewerBorrow, error) {
+	borrow, err := s.borrowRepo.AcceptBorrow(ctx, borrowID, s.clock.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("borrowRepo.AcceptBorrow failed: %w", err)
+	}
+
+	return toAPIReviewerBorrow(borrow), nil
+}
+
+func (s *TeamServiceImpl) ListReviewerBorrows(ctx context.Context, teamName string) ([]api.ReviewerBorrow, error) {
+	team, err := s.repo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetTeamByName failed: %w", err)
+	}
+
+	borrows, err := s.borrowRepo.ListBorrows(ctx, team.ID)
+	if err != nil {
+		return nil, fmt.Errorf("borrowRepo.ListBorrows failed: %w", err)
+	}
+
+	result := make([]api.ReviewerBorrow, len(borrows))
+	for i := range borrows {
+		result[i] = *toAPIReviewerBorrow(&borrows[i])
+	}
+
+	return result, nil
+}
+
+func toAPIReviewerBorrow(borrow *domain.ReviewerBorrow) *api.ReviewerBorrow {
+	reviewerIDs := borrow.ReviewerIDs
+	if reviewerIDs == nil {
+		reviewerIDs = []string{}
+	}
+
+	return &api.ReviewerBorrow{
+		BorrowId:       borrow.ID,
+		TeamName:       borrow.TeamName,
+		TeamId:         borrow.TeamID,
+		LenderTeamName: borrow.LenderTeamName,
+		LenderTeamId:   borrow.LenderTeamID,
+		Count:          borrow.Count,
+		DurationHours:  borrow.DurationHours,
+		Status:         api.ReviewerBorrowStatus(borrow.Status),
+		RequestedAt:    borrow.RequestedAt,
+		AcceptedAt:     borrow.AcceptedAt,
+		ExpiresAt:      borrow.ExpiresAt,
+		ReviewerIds:    reviewerIDs,
+	}
+}
+
+func toAPITeamPolicy(team *domain.TeamWithMembers, policy *domain.TeamPolicy) *api.TeamPolicy {
+	weights := make(map[string]int, len(policy.StrategyWeights))
+	for strategy, weight := range policy.StrategyWeights {
+		weights[string(strategy)] = weight
+	}
+
+	action := api.TeamPolicyOverQuotaAction(domain.QuotaReject)
+	if policy.OverQuotaAction != "" {
+		action = api.TeamPolicyOverQuotaAction(policy.OverQuotaAction)
+	}
+
+	rebalance := api.TeamPolicyReactivationRebalance(domain.RebalanceOff)
+	if policy.ReactivationRebalance != "" {
+		rebalance = api.TeamPolicyReactivationRebalance(policy.ReactivationRebalance)
+	}
+
+	return &api.TeamPolicy{
+		TeamName:              team.Name,
+		TeamId:                team.ID,
+		StrategyWeights:       weights,
+		AuthorOpenPrLimit:     policy.AuthorOpenPRLimit,
+		OverQuotaAction:       &action,
+		ReactivationRebalance: &rebalance,
+	}
+}
+
 func toAPITeam(domainTeam *domain.TeamWithMembers) *api.Team {
 	apiMembers := make([]api.TeamMember, len(domainTeam.Members))
 	for i, member := range domainTeam.Members {
@@ -62,6 +461,26 @@ func toAPITeam(domainTeam *domain.TeamWithMembers) *api.Team {
 
 	return &api.Team{
 		TeamName: domainTeam.Name,
+		TeamId:   &domainTeam.ID,
 		Members:  apiMembers,
 	}
 }
+
+// checkUsernamesUnique returns apperrors.ErrValidation if two members have the same username up to case.
+// A user listed twice under the same ID is not a clash.
+func checkUsernamesUnique(members []api.TeamMember) error {
+	owners := make(map[string]string, len(members))
+
+	for _, m := range members {
+		key := validation.UsernameKey(m.Username)
+
+		if owner, ok := owners[key]; ok && owner != m.UserId {
+			return fmt.Errorf("%w: username '%s' of user '%s' differs only in case from the username of user '%s'",
+				apperrors.ErrValidation, m.Username, m.UserId, owner)
+		}
+
+		owners[key] = m.UserId
+	}
+
+	return nil
+}
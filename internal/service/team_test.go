@@ -5,16 +5,20 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
 	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/localcache"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTeamServiceImpl_CreateTeam(t *testing.T) {
 	ctx := context.Background()
+	teamID := 1
 
 	inputTeam := api.Team{
 		TeamName: "test-team",
@@ -46,6 +50,7 @@ func TestTeamServiceImpl_CreateTeam(t *testing.T) {
 			inputTeam: inputTeam,
 			expectedTeam: &api.Team{
 				TeamName: "test-team",
+				TeamId:   &teamID,
 				Members: []api.TeamMember{
 					{UserId: "u1", Username: "Test User", IsActive: true},
 				},
@@ -68,7 +73,7 @@ func TestTeamServiceImpl_CreateTeam(t *testing.T) {
 			repoMock := new(TeamRepositoryMock)
 			tc.setupMock(repoMock)
 
-			service := NewTeamService(repoMock, nil)
+			service := NewTeamService(repoMock, nil, nil, nil)
 
 			resultTeam, err := service.CreateTeamWithUsers(ctx, tc.inputTeam)
 
@@ -85,9 +90,65 @@ func TestTeamServiceImpl_CreateTeam(t *testing.T) {
 	}
 }
 
+func TestTeamServiceImpl_CreateTeam_CaseInsensitiveUsernames(t *testing.T) {
+	ctx := context.Background()
+
+	clashing := api.Team{
+		TeamName: "backend",
+		Members: []api.TeamMember{
+			{UserId: "u1", Username: "Иван", IsActive: true},
+			{UserId: "u2", Username: "иВАН", IsActive: true},
+		},
+	}
+
+	t.Run("Failure: Usernames differ only in case", func(t *testing.T) {
+		repoMock := new(TeamRepositoryMock)
+		service := NewTeamService(repoMock, nil, nil, nil, WithCaseInsensitiveUsernames())
+
+		_, err := service.CreateTeamWithUsers(ctx, clashing)
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+		assert.ErrorContains(t, err, "username 'иВАН' of user 'u2' differs only in case from the username of user 'u1'")
+
+		repoMock.AssertNotCalled(t, "CreateTeamWithUsers", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success: Same user listed twice", func(t *testing.T) {
+		team := api.Team{
+			TeamName: "backend",
+			Members: []api.TeamMember{
+				{UserId: "u1", Username: "Иван", IsActive: true},
+				{UserId: "u1", Username: "ИВАН", IsActive: false},
+			},
+		}
+
+		repoMock := new(TeamRepositoryMock)
+		repoMock.On("CreateTeamWithUsers", mock.Anything, team).Return(&domain.TeamWithMembers{ID: 1, Name: "backend"}, nil).Once()
+
+		service := NewTeamService(repoMock, nil, nil, nil, WithCaseInsensitiveUsernames())
+
+		_, err := service.CreateTeamWithUsers(ctx, team)
+		require.NoError(t, err)
+
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("Success: Case-sensitive by default", func(t *testing.T) {
+		repoMock := new(TeamRepositoryMock)
+		repoMock.On("CreateTeamWithUsers", mock.Anything, clashing).Return(&domain.TeamWithMembers{ID: 1, Name: "backend"}, nil).Once()
+
+		service := NewTeamService(repoMock, nil, nil, nil)
+
+		_, err := service.CreateTeamWithUsers(ctx, clashing)
+		require.NoError(t, err)
+
+		repoMock.AssertExpectations(t)
+	})
+}
+
 func TestTeamServiceImpl_GetTeam(t *testing.T) {
 	ctx := context.Background()
 	teamName := "existing-team"
+	teamID := 1
 
 	domainTeamWithMembers := &domain.TeamWithMembers{
 		ID:   1,
@@ -100,6 +161,7 @@ func TestTeamServiceImpl_GetTeam(t *testing.T) {
 
 	expectedAPITeam := &api.Team{
 		TeamName: teamName,
+		TeamId:   &teamID,
 		Members: []api.TeamMember{
 			{UserId: "u1", Username: "Alice", IsActive: true},
 			{UserId: "u2", Username: "Bob", IsActive: true},
@@ -117,7 +179,7 @@ func TestTeamServiceImpl_GetTeam(t *testing.T) {
 			name:     "Success: Team is found",
 			teamName: teamName,
 			setupMock: func(repoMock *TeamRepositoryMock) {
-				repoMock.On("GetTeamByName", ctx, mock.Anything, teamName).Return(domainTeamWithMembers, nil).Once()
+				repoMock.On("GetTeamByName", mock.Anything, teamName).Return(domainTeamWithMembers, nil).Once()
 			},
 			expectedTeam:  expectedAPITeam,
 			expectedError: nil,
@@ -126,7 +188,7 @@ func TestTeamServiceImpl_GetTeam(t *testing.T) {
 			name:     "Failure: Team not found",
 			teamName: "non-existent-team",
 			setupMock: func(repoMock *TeamRepositoryMock) {
-				repoMock.On("GetTeamByName", ctx, mock.Anything, "non-existent-team").Return(nil, apperrors.ErrNotFound).Once()
+				repoMock.On("GetTeamByName", mock.Anything, "non-existent-team").Return(nil, apperrors.ErrNotFound).Once()
 			},
 			expectedTeam:  nil,
 			expectedError: apperrors.ErrNotFound,
@@ -135,7 +197,7 @@ func TestTeamServiceImpl_GetTeam(t *testing.T) {
 			name:     "Failure: Repository returns a generic error",
 			teamName: "any-team",
 			setupMock: func(repoMock *TeamRepositoryMock) {
-				repoMock.On("GetTeamByName", ctx, mock.Anything, "any-team").Return(nil, errors.New("internal db error")).Once()
+				repoMock.On("GetTeamByName", mock.Anything, "any-team").Return(nil, errors.New("internal db error")).Once()
 			},
 			expectedTeam:  nil,
 			expectedError: errors.New("internal db error"),
@@ -147,7 +209,7 @@ func TestTeamServiceImpl_GetTeam(t *testing.T) {
 			repoMock := new(TeamRepositoryMock)
 			tc.setupMock(repoMock)
 
-			service := NewTeamService(repoMock, nil)
+			service := NewTeamService(repoMock, nil, nil, nil)
 
 			resultTeam, err := service.GetTeam(ctx, tc.teamName)
 
@@ -164,3 +226,646 @@ func TestTeamServiceImpl_GetTeam(t *testing.T) {
 		})
 	}
 }
+
+func TestTeamServiceImpl_GetTeam_ReadCache(t *testing.T) {
+	ctx := context.Background()
+	teamName := "backend"
+
+	before := &domain.TeamWithMembers{ID: 1, Name: teamName, Members: []domain.User{{ID: "u1", Username: "Alice", TeamID: 1, IsActive: true}}}
+	after := &domain.TeamWithMembers{ID: 1, Name: teamName, Members: []domain.User{{ID: "u1", Username: "Alice", TeamID: 1, IsActive: false}}}
+
+	repoMock := new(TeamRepositoryMock)
+	repoMock.On("GetTeamByName", mock.Anything, teamName).Return(before, nil).Once()
+
+	service := NewTeamService(repoMock, nil, nil, nil, WithTeamReadCache(localcache.New(10, time.Minute)))
+
+	for range 2 {
+		team, err := service.GetTeam(ctx, teamName)
+		require.NoError(t, err)
+		assert.True(t, team.Members[0].IsActive)
+	}
+
+	newTeam := api.Team{TeamName: "frontend", Members: []api.TeamMember{{UserId: "u1", Username: "Alice", IsActive: false}}}
+	repoMock.On("CreateTeamWithUsers", ctx, newTeam).Return(&domain.TeamWithMembers{ID: 2, Name: "frontend"}, nil).Once()
+	repoMock.On("GetTeamByName", mock.Anything, teamName).Return(after, nil).Once()
+
+	_, err := service.CreateTeamWithUsers(ctx, newTeam)
+	require.NoError(t, err)
+
+	team, err := service.GetTeam(ctx, teamName)
+	require.NoError(t, err)
+	assert.False(t, team.Members[0].IsActive, "creating a team invalidates the cache")
+
+	repoMock.AssertExpectations(t)
+}
+
+func TestTeamServiceImpl_TeamQueries(t *testing.T) {
+	ctx := context.Background()
+	team := &domain.TeamWithMembers{ID: 1, Name: "backend", Members: []domain.User{}}
+
+	repoMock := new(TeamRepositoryMock)
+	repoMock.On("GetTeamByName", mock.Anything, "backend").Return(team, nil).Once()
+	repoMock.On("RenameTeam", ctx, 1, "platform").Return(nil).Once()
+
+	queriesMock := new(TeamRepositoryMock)
+	queriesMock.On("GetTeamByName", mock.Anything, "backend").Return(team, nil).Once()
+	queriesMock.On("GetTeamByID", mock.Anything, 1).Return(team, nil).Once()
+
+	service := NewTeamService(repoMock, nil, nil, nil, WithTeamQueries(queriesMock))
+
+	_, err := service.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+
+	_, err = service.GetTeamByID(ctx, 1)
+	require.NoError(t, err)
+
+	// The writes read the team they change through the primary repository.
+	renamed, err := service.RenameTeam(ctx, "backend", "platform")
+	require.NoError(t, err)
+	assert.Equal(t, "platform", renamed.TeamName)
+
+	repoMock.AssertExpectations(t)
+	queriesMock.AssertExpectations(t)
+}
+
+func TestTeamServiceImpl_GetTeamByID(t *testing.T) {
+	ctx := context.Background()
+	teamID := 7
+
+	repoMock := new(TeamRepositoryMock)
+	repoMock.On("GetTeamByID", mock.Anything, teamID).Return(&domain.TeamWithMembers{
+		ID:      teamID,
+		Name:    "renamed-team",
+		Members: []domain.User{{ID: "u1", Username: "Alice", TeamID: teamID, IsActive: true}},
+	}, nil).Once()
+	repoMock.On("GetTeamByID", mock.Anything, 8).Return(nil, apperrors.ErrNotFound).Once()
+
+	service := NewTeamService(repoMock, nil, nil, nil)
+
+	team, err := service.GetTeamByID(ctx, teamID)
+	require.NoError(t, err)
+	assert.Equal(t, &api.Team{
+		TeamName: "renamed-team",
+		TeamId:   &teamID,
+		Members:  []api.TeamMember{{UserId: "u1", Username: "Alice", IsActive: true}},
+	}, team)
+
+	_, err = service.GetTeamByID(ctx, 8)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	repoMock.AssertExpectations(t)
+}
+
+func TestTeamServiceImpl_RenameTeam(t *testing.T) {
+	ctx := context.Background()
+	teamID := 1
+
+	team := func() *domain.TeamWithMembers {
+		return &domain.TeamWithMembers{
+			ID:      teamID,
+			Name:    "backend",
+			Members: []domain.User{{ID: "u1", Username: "Alice", TeamID: teamID, IsActive: true}},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		newTeamName   string
+		setupMock     func(repoMock *TeamRepositoryMock)
+		expectedTeam  *api.Team
+		expectedError error
+	}{
+		{
+			name:        "Success: Team keeps its ID and members",
+			newTeamName: "platform",
+			setupMock: func(repoMock *TeamRepositoryMock) {
+				repoMock.On("GetTeamByName", mock.Anything, "backend").Return(team(), nil).Once()
+				repoMock.On("RenameTeam", ctx, teamID, "platform").Return(nil).Once()
+			},
+			expectedTeam: &api.Team{
+				TeamName: "platform",
+				TeamId:   &teamID,
+				Members:  []api.TeamMember{{UserId: "u1", Username: "Alice", IsActive: true}},
+			},
+		},
+		{
+			name:        "Success: Same name is a no-op",
+			newTeamName: "backend",
+			setupMock: func(repoMock *TeamRepositoryMock) {
+				repoMock.On("GetTeamByName", mock.Anything, "backend").Return(team(), nil).Once()
+			},
+			expectedTeam: &api.Team{
+				TeamName: "backend",
+				TeamId:   &teamID,
+				Members:  []api.TeamMember{{UserId: "u1", Username: "Alice", IsActive: true}},
+			},
+		},
+		{
+			name:        "Failure: New name is taken",
+			newTeamName: "payments",
+			setupMock: func(repoMock *TeamRepositoryMock) {
+				repoMock.On("GetTeamByName", mock.Anything, "backend").Return(team(), nil).Once()
+				repoMock.On("RenameTeam", ctx, teamID, "payments").Return(&apperrors.TeamAlreadyExistsError{TeamName: "payments"}).Once()
+			},
+			expectedError: apperrors.ErrAlreadyExists,
+		},
+		{
+			name:        "Failure: Team not found",
+			newTeamName: "platform",
+			setupMock: func(repoMock *TeamRepositoryMock) {
+				repoMock.On("GetTeamByName", mock.Anything, "backend").Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedError: apperrors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repoMock := new(TeamRepositoryMock)
+			tc.setupMock(repoMock)
+
+			service := NewTeamService(repoMock, nil, nil, nil)
+
+			renamed, err := service.RenameTeam(ctx, "backend", tc.newTeamName)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, renamed)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTeam, renamed)
+			}
+
+			repoMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTeamServiceImpl_SetTeamPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	team := &domain.TeamWithMembers{ID: 1, Name: "backend"}
+	limit, zero := 5, 0
+	reject, queue := api.Reject, api.Queue
+	unknownAction := api.TeamPolicyOverQuotaAction("drop")
+	off, immediate := api.RebalanceOff, api.RebalanceImmediate
+	unknownRebalance := api.TeamPolicyReactivationRebalance("later")
+
+	testCases := []struct {
+		name           string
+		input          api.TeamPolicy
+		setupMocks     func(repoMock *TeamRepositoryMock, policyMock *PolicyRepositoryMock)
+		expectedPolicy *api.TeamPolicy
+		expectedError  error
+	}{
+		{
+			name: "Success: Policy is saved",
+			input: api.TeamPolicy{
+				TeamName:        "backend",
+				StrategyWeights: map[string]int{"random": 80, "least_loaded": 20},
+			},
+			setupMocks: func(repoMock *TeamRepositoryMock, policyMock *PolicyRepositoryMock) {
+				weights := map[domain.AssignmentStrategy]int{domain.StrategyRandom: 80, domain.StrategyLeastLoaded: 20}
+				repoMock.On("GetTeamByName", mock.Anything, "backend").Return(team, nil).Once()
+				saved := &domain.TeamPolicy{
+					TeamID: 1, StrategyWeights: weights, OverQuotaAction: domain.QuotaReject, ReactivationRebalance: domain.RebalanceOff,
+				}
+				policyMock.On("UpsertTeamPolicy", ctx, saved).Return(saved, nil).Once()
+			},
+			expectedPolicy: &api.TeamPolicy{
+				TeamName:              "backend",
+				TeamId:                1,
+				StrategyWeights:       map[string]int{"random": 80, "least_loaded": 20},
+				OverQuotaAction:       &reject,
+				ReactivationRebalance: &off,
+			},
+		},
+		{
+			name: "Success: Reactivation rebalance is saved",
+			input: api.TeamPolicy{
+				TeamName:              "backend",
+				StrategyWeights:       map[string]int{},
+				ReactivationRebalance: &immediate,
+			},
+			setupMocks: func(repoMock *TeamRepositoryMock, policyMock *PolicyRepositoryMock) {
+				saved := &domain.TeamPolicy{
+					TeamID:                1,
+					StrategyWeights:       map[domain.AssignmentStrategy]int{},
+					OverQuotaAction:       domain.QuotaReject,
+					ReactivationRebalance: domain.RebalanceImmediate,
+				}
+				repoMock.On("GetTeamByName", mock.Anything, "backend").Return(team, nil).Once()
+				policyMock.On("UpsertTeamPolicy", ctx, saved).Return(saved, nil).Once()
+			},
+			expectedPolicy: &api.TeamPolicy{
+				TeamName:              "backend",
+				TeamId:                1,
+				StrategyWeights:       map[string]int{},
+				OverQuotaAction:       &reject,
+				ReactivationRebalance: &immediate,
+			},
+		},
+		{
+			name: "Success: Author quota is saved",
+			input: api.TeamPolicy{
+				TeamName:          "backend",
+				StrategyWeights:   map[string]int{},
+				AuthorOpenPrLimit: &limit,
+				OverQuotaAction:   &queue,
+			},
+			setupMocks: func(repoMock *TeamRepositoryMock, policyMock *PolicyRepositoryMock) {
+				saved := &domain.TeamPolicy{
+					TeamID:                1,
+					StrategyWeights:       map[domain.AssignmentStrategy]int{},
+					AuthorOpenPRLimit:     &limit,
+					OverQuotaAction:       domain.QuotaQueue,
+					ReactivationRebalance: domain.RebalanceOff,
+				}
+				repoMock.On("GetTeamByName", mock.Anything, "backend").Return(team, nil).Once()
+				policyMock.On("UpsertTeamPolicy", ctx, saved).Return(saved, nil).Once()
+			},
+			expectedPolicy: &api.TeamPolicy{
+				TeamName:              "backend",
+				TeamId:                1,
+				StrategyWeights:       map[string]int{},
+				AuthorOpenPrLimit:     &limit,
+				OverQuotaAction:       &queue,
+				ReactivationRebalance: &off,
+			},
+		},
+		{
+			name: "Failure: Non-positive author quota",
+			input: api.TeamPolicy{
+				TeamName:          "backend",
+				StrategyWeights:   map[string]int{},
+				AuthorOpenPrLimit: &zero,
+			},
+			setupMocks:    func(repoMock *TeamRepositoryMock, policyMock *PolicyRepositoryMock) {},
+			expectedError: apperrors.ErrValidation,
+		},
+		{
+			name: "Failure: Unknown over quota action",
+			input: api.TeamPolicy{
+				TeamName:        "backend",
+				StrategyWeights: map[string]int{},
+				OverQuotaAction: &unknownAction,
+			},
+			setupMocks:    func(repoMock *TeamRepositoryMock, policyMock *PolicyRepositoryMock) {},
+			expectedError: apperrors.ErrValidation,
+		},
+		{
+			name: "Failure: Unknown reactivation rebalance mode",
+			input: api.TeamPolicy{
+				TeamName:              "backend",
+				StrategyWeights:       map[string]int{},
+				ReactivationRebalance: &unknownRebalance,
+			},
+			setupMocks:    func(repoMock *TeamRepositoryMock, policyMock *PolicyRepositoryMock) {},
+			expectedError: apperrors.ErrValidation,
+		},
+		{
+			name: "Failure: Unknown strategy",
+			input: api.TeamPolicy{
+				TeamName:        "backend",
+				StrategyWeights: map[string]int{"alphabetical": 10},
+			},
+			setupMocks:    func(repoMock *TeamRepositoryMock, policyMock *PolicyRepositoryMock) {},
+			expectedError: apperrors.ErrValidation,
+		},
+		{
+			name: "Failure: Negative weight",
+			input: api.TeamPolicy{
+				TeamName:        "backend",
+				StrategyWeights: map[string]int{"random": -1},
+			},
+			setupMocks:    func(repoMock *TeamRepositoryMock, policyMock *PolicyRepositoryMock) {},
+			expectedError: apperrors.ErrValidation,
+		},
+		{
+			name: "Failure: Team not found",
+			input: api.TeamPolicy{
+				TeamName:        "unknown",
+				StrategyWeights: map[string]int{"random": 1},
+			},
+			setupMocks: func(repoMock *TeamRepositoryMock, policyMock *PolicyRepositoryMock) {
+				repoMock.On("GetTeamByName", mock.Anything, "unknown").Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedError: apperrors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repoMock := new(TeamRepositoryMock)
+			policyMock := new(PolicyRepositoryMock)
+			tc.setupMocks(repoMock, policyMock)
+
+			service := NewTeamService(repoMock, policyMock, nil, nil)
+
+			policy, err := service.SetTeamPolicy(ctx, tc.input)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, policy)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedPolicy, policy)
+			}
+
+			repoMock.AssertExpectations(t)
+			policyMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTeamServiceImpl_GetTeamPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	repoMock := new(TeamRepositoryMock)
+	policyMock := new(PolicyRepositoryMock)
+
+	repoMock.On("GetTeamByName", mock.Anything, "backend").Return(&domain.TeamWithMembers{ID: 1, Name: "backend"}, nil).Once()
+	policyMock.On("GetTeamPolicy", ctx, 1).Return(&domain.TeamPolicy{
+		TeamID:          1,
+		StrategyWeights: map[domain.AssignmentStrategy]int{domain.StrategyLeastLoaded: 1},
+	}, nil).Once()
+
+	service := NewTeamService(repoMock, policyMock, nil, nil)
+
+	policy, err := service.GetTeamPolicy(ctx, "backend")
+
+	assert.NoError(t, err)
+	reject, off := api.Reject, api.RebalanceOff
+	assert.Equal(t, &api.TeamPolicy{
+		TeamName: "backend", TeamId: 1, StrategyWeights: map[string]int{"least_loaded": 1}, OverQuotaAction: &reject, ReactivationRebalance: &off,
+	}, policy)
+
+	repoMock.AssertExpectations(t)
+	policyMock.AssertExpectations(t)
+}
+
+func TestTeamServiceImpl_GetEffectivePolicy(t *testing.T) {
+	ctx := context.Background()
+	team := &domain.TeamWithMembers{ID: 1, Name: "backend"}
+	limit := 3
+
+	testCases := []struct {
+		name     string
+		policy   *domain.TeamPolicy
+		expected *api.EffectivePolicy
+	}{
+		{
+			name: "Team without a policy gets the defaults",
+			policy: &domain.TeamPolicy{
+				TeamID: 1, StrategyWeights: map[domain.AssignmentStrategy]int{},
+				OverQuotaAction: domain.QuotaReject, ReactivationRebalance: domain.RebalanceOff,
+			},
+			expected: &api.EffectivePolicy{
+				TeamName: "backend", TeamId: 1, ReviewersPerPr: 2,
+				StrategyWeights:       map[string]int{"least_loaded": 1},
+				OverQuotaAction:       api.Reject,
+				ReactivationRebalance: api.RebalanceOff,
+				RequireApprovals:      true,
+				Sources: map[string]api.PolicySource{
+					"reviewers_per_pr": api.PolicySourceDefault, "strategy_weights": api.PolicySourceDefault,
+					"author_open_pr_limit": api.PolicySourceDefault, "over_quota_action": api.PolicySourceDefault,
+					"reactivation_rebalance": api.PolicySourceDefault, "require_approvals": api.PolicySourceDefault,
+					"strict_checks": api.PolicySourceDefault,
+				},
+			},
+		},
+		{
+			name: "Team policy overrides the defaults",
+			policy: &domain.TeamPolicy{
+				TeamID:                1,
+				StrategyWeights:       map[domain.AssignmentStrategy]int{domain.StrategyRandom: 20, domain.StrategyRoundRobin: 0},
+				AuthorOpenPRLimit:     &limit,
+				OverQuotaAction:       domain.QuotaQueue,
+				ReactivationRebalance: domain.RebalanceJob,
+				UpdatedAt:             testNow,
+			},
+			expected: &api.EffectivePolicy{
+				TeamName: "backend", TeamId: 1, ReviewersPerPr: 2,
+				StrategyWeights:       map[string]int{"random": 20},
+				AuthorOpenPrLimit:     &limit,
+				OverQuotaAction:       api.Queue,
+				ReactivationRebalance: api.RebalanceJob,
+				RequireApprovals:      true,
+				Sources: map[string]api.PolicySource{
+					"reviewers_per_pr": api.PolicySourceDefault, "strategy_weights": api.PolicySourceTeam,
+					"author_open_pr_limit": api.PolicySourceTeam, "over_quota_action": api.PolicySourceTeam,
+					"reactivation_rebalance": api.PolicySourceTeam, "require_approvals": api.PolicySourceDefault,
+					"strict_checks": api.PolicySourceDefault,
+				},
+			},
+		},
+		{
+			name: "Stored policy with zero weights keeps the default strategy",
+			policy: &domain.TeamPolicy{
+				TeamID:                1,
+				StrategyWeights:       map[domain.AssignmentStrategy]int{domain.StrategyRandom: 0},
+				OverQuotaAction:       domain.QuotaReject,
+				ReactivationRebalance: domain.RebalanceImmediate,
+				UpdatedAt:             testNow,
+			},
+			expected: &api.EffectivePolicy{
+				TeamName: "backend", TeamId: 1, ReviewersPerPr: 2,
+				StrategyWeights:       map[string]int{"least_loaded": 1},
+				OverQuotaAction:       api.Reject,
+				ReactivationRebalance: api.RebalanceImmediate,
+				RequireApprovals:      true,
+				Sources: map[string]api.PolicySource{
+					"reviewers_per_pr": api.PolicySourceDefault, "strategy_weights": api.PolicySourceDefault,
+					"author_open_pr_limit": api.PolicySourceDefault, "over_quota_action": api.PolicySourceTeam,
+					"reactivation_rebalance": api.PolicySourceTeam, "require_approvals": api.PolicySourceDefault,
+					"strict_checks": api.PolicySourceDefault,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repoMock := new(TeamRepositoryMock)
+			policyMock := new(PolicyRepositoryMock)
+
+			repoMock.On("GetTeamByName", mock.Anything, "backend").Return(team, nil).Once()
+			policyMock.On("GetTeamPolicy", ctx, 1).Return(tc.policy, nil).Once()
+
+			service := NewTeamService(repoMock, policyMock, nil, nil, WithPolicyDefaults(PolicyDefaults{
+				DefaultStrategy: domain.StrategyLeastLoaded, RequireApprovals: true,
+			}))
+
+			policy, err := service.GetEffectivePolicy(ctx, "backend")
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, policy)
+		})
+	}
+
+	t.Run("Unknown team", func(t *testing.T) {
+		repoMock := new(TeamRepositoryMock)
+		repoMock.On("GetTeamByName", mock.Anything, "ghost").Return(nil, apperrors.ErrNotFound).Once()
+
+		_, err := NewTeamService(repoMock, nil, nil, nil).GetEffectivePolicy(ctx, "ghost")
+
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
+
+func TestTeamServiceImpl_RequestReviewerBorrow(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name          string
+		teamName      string
+		lenderName    string
+		count         int
+		durationHours int
+		setupMocks    func(repoMock *TeamRepositoryMock, borrowMock *BorrowRepositoryMock)
+		expectedError error
+	}{
+		{
+			name:          "Success",
+			teamName:      "backend",
+			lenderName:    "payments",
+			count:         2,
+			durationHours: 48,
+			setupMocks: func(repoMock *TeamRepositoryMock, borrowMock *BorrowRepositoryMock) {
+				repoMock.On("GetTeamByName", mock.Anything, "backend").Return(&domain.TeamWithMembers{ID: 1, Name: "backend"}, nil).Once()
+				repoMock.On("GetTeamByName", mock.Anything, "payments").Return(&domain.TeamWithMembers{ID: 2, Name: "payments"}, nil).Once()
+				borrowMock.On("CreateBorrow", ctx, &domain.ReviewerBorrow{TeamID: 1, LenderTeamID: 2, Count: 2, DurationHours: 48}).
+					Return(&domain.ReviewerBorrow{
+						ID: 7, TeamID: 1, TeamName: "backend", LenderTeamID: 2, LenderTeamName: "payments",
+						Count: 2, DurationHours: 48, Status: domain.BorrowRequested,
+					}, nil).Once()
+			},
+		},
+		{
+			name:          "Failure: Count out of range",
+			teamName:      "backend",
+			lenderName:    "payments",
+			count:         maxBorrowCount + 1,
+			durationHours: 48,
+			setupMocks:    func(repoMock *TeamRepositoryMock, borrowMock *BorrowRepositoryMock) {},
+			expectedError: apperrors.ErrValidation,
+		},
+		{
+			name:          "Failure: Duration out of range",
+			teamName:      "backend",
+			lenderName:    "payments",
+			count:         1,
+			durationHours: 0,
+			setupMocks:    func(repoMock *TeamRepositoryMock, borrowMock *BorrowRepositoryMock) {},
+			expectedError: apperrors.ErrValidation,
+		},
+		{
+			name:          "Failure: Borrowing from itself",
+			teamName:      "backend",
+			lenderName:    "backend",
+			count:         1,
+			durationHours: 1,
+			setupMocks:    func(repoMock *TeamRepositoryMock, borrowMock *BorrowRepositoryMock) {},
+			expectedError: apperrors.ErrValidation,
+		},
+		{
+			name:          "Failure: Lender team not found",
+			teamName:      "backend",
+			lenderName:    "unknown",
+			count:         1,
+			durationHours: 1,
+			setupMocks: func(repoMock *TeamRepositoryMock, borrowMock *BorrowRepositoryMock) {
+				repoMock.On("GetTeamByName", mock.Anything, "backend").Return(&domain.TeamWithMembers{ID: 1, Name: "backend"}, nil).Once()
+				repoMock.On("GetTeamByName", mock.Anything, "unknown").Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedError: apperrors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repoMock := new(TeamRepositoryMock)
+			borrowMock := new(BorrowRepositoryMock)
+			tc.setupMocks(repoMock, borrowMock)
+
+			service := NewTeamService(repoMock, nil, borrowMock, nil)
+
+			borrow, err := service.RequestReviewerBorrow(ctx, tc.teamName, tc.lenderName, tc.count, tc.durationHours)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, borrow)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, &api.ReviewerBorrow{
+					BorrowId: 7, TeamName: "backend", TeamId: 1, LenderTeamName: "payments", LenderTeamId: 2,
+					Count: 2, DurationHours: 48, Status: api.Requested, ReviewerIds: []string{},
+				}, borrow)
+			}
+
+			repoMock.AssertExpectations(t)
+			borrowMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTeamServiceImpl_AcceptReviewerBorrow(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Borrow is accepted at the clock time", func(t *testing.T) {
+		borrowMock := new(BorrowRepositoryMock)
+		acceptedAt := testNow.UTC()
+		expiresAt := acceptedAt.Add(48 * time.Hour)
+
+		borrowMock.On("AcceptBorrow", ctx, int64(7), acceptedAt).Return(&domain.ReviewerBorrow{
+			ID: 7, TeamName: "backend", LenderTeamName: "payments", Count: 2, DurationHours: 48,
+			Status: domain.BorrowAccepted, AcceptedAt: &acceptedAt, ExpiresAt: &expiresAt, ReviewerIDs: []string{"u7"},
+		}, nil).Once()
+
+		service := NewTeamService(nil, nil, borrowMock, nil)
+		service.clock = fixedClock(testNow)
+
+		borrow, err := service.AcceptReviewerBorrow(ctx, 7)
+
+		require.NoError(t, err)
+		assert.Equal(t, api.Accepted, borrow.Status)
+		assert.Equal(t, []string{"u7"}, borrow.ReviewerIds)
+		assert.Equal(t, &expiresAt, borrow.ExpiresAt)
+		borrowMock.AssertExpectations(t)
+	})
+
+	t.Run("Accepted borrow cannot be accepted again", func(t *testing.T) {
+		borrowMock := new(BorrowRepositoryMock)
+		borrowMock.On("AcceptBorrow", ctx, int64(7), mock.AnythingOfType("time.Time")).Return(nil, apperrors.ErrBorrowNotPending).Once()
+
+		service := NewTeamService(nil, nil, borrowMock, nil)
+
+		_, err := service.AcceptReviewerBorrow(ctx, 7)
+
+		assert.ErrorIs(t, err, apperrors.ErrBorrowNotPending)
+	})
+}
+
+func TestTeamServiceImpl_ListReviewerBorrows(t *testing.T) {
+	ctx := context.Background()
+
+	repoMock := new(TeamRepositoryMock)
+	borrowMock := new(BorrowRepositoryMock)
+
+	repoMock.On("GetTeamByName", mock.Anything, "backend").Return(&domain.TeamWithMembers{ID: 1, Name: "backend"}, nil).Once()
+	borrowMock.On("ListBorrows", ctx, 1).Return([]domain.ReviewerBorrow{
+		{ID: 8, TeamName: "payments", LenderTeamName: "backend", Count: 1, DurationHours: 2, Status: domain.BorrowRequested},
+	}, nil).Once()
+
+	service := NewTeamService(repoMock, nil, borrowMock, nil)
+
+	borrows, err := service.ListReviewerBorrows(ctx, "backend")
+
+	require.NoError(t, err)
+	require.Len(t, borrows, 1)
+	assert.Equal(t, int64(8), borrows[0].BorrowId)
+	assert.Equal(t, "backend", borrows[0].LenderTeamName)
+
+	repoMock.AssertExpectations(t)
+	borrowMock.AssertExpectations(t)
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+func (s *UserServiceImpl) TransferUser(ctx context.Context, userID string, teamName string, reassignReviews bool) (*api.TransferUserResponse, error) {
+	const op = "internal.service.user.TransferUser"
+
+	var (
+		resp         api.TransferUserResponse
+		replacements []domain.AssignmentRecord
+		unplaced     []unplacedReview
+	)
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		resp = api.TransferUserResponse{}
+		replacements, unplaced = nil, nil
+
+		team, err := s.teamRepo.GetTeamByName(ctx, teamName)
+		if err != nil {
+			return fmt.Errorf("repo.GetTeamByName failed: %w", err)
+		}
+
+		user, previousTeamID, err := s.repo.TransferUser(ctx, userID, team.ID)
+		if err != nil {
+			return fmt.Errorf("repo.TransferUser failed: %w", err)
+		}
+
+		previousTeam, err := s.teamRepo.GetTeamByID(ctx, previousTeamID)
+		if err != nil {
+			return fmt.Errorf("repo.GetTeamByID failed: %w", err)
+		}
+
+		resp.User = *user
+		resp.PreviousTeamId = previousTeam.ID
+		resp.PreviousTeamName = previousTeam.Name
+
+		if !reassignReviews || previousTeamID == team.ID {
+			return nil
+		}
+
+		replacements, unplaced, err = s.reassignTeamReviews(ctx, previousTeam, userID)
+		if err != nil {
+			return fmt.Errorf("failed to reassign reviews: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range replacements {
+		reviewerReassignmentsTotal.WithLabelValues(string(record.Strategy)).Inc()
+	}
+
+	s.log.Info("user transferred", slog.String("op", op), slog.String("user_id", userID),
+		slog.String("previous_team_name", resp.PreviousTeamName), slog.String("team_name", teamName),
+		slog.Int("reassigned_reviews", len(replacements)))
+
+	if reassignReviews {
+		reassignments := toAPIReviewerMoves(replacements)
+		resp.Reassignments = &reassignments
+
+		if len(unplaced) > 0 {
+			warnings := make([]string, len(unplaced))
+			for i, review := range unplaced {
+				s.log.Warn("no replacement candidate found", slog.String("op", op), slog.String("pr_id", review.prID),
+					slog.String("old_reviewer_id", review.reviewerID))
+				warnings[i] = review.warning()
+			}
+
+			resp.Warnings = &warnings
+		}
+	}
+
+	return &resp, nil
+}
+
+// reassignTeamReviews replaces userID, who has just left team, on the reviews of open pull requests of members
+// whose primary team it is with its active members. The reviews of pull requests of other teams stay with the user.
+// It returns the replacements made and the reviews left with the user.
+func (s *UserServiceImpl) reassignTeamReviews(ctx context.Context, team *domain.TeamWithMembers, userID string) ([]domain.AssignmentRecord, []unplacedReview, error) {
+	prs, err := s.prQuery.GetOpenPRsByReviewers(ctx, []string{userID})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get open PRs: %w", err)
+	}
+
+	members := make(map[string]struct{}, len(team.Members))
+	for _, member := range team.Members {
+		if member.TeamID == team.ID {
+			members[member.ID] = struct{}{}
+		}
+	}
+
+	var teamPRs []domain.PullRequest
+	for _, pr := range prs {
+		if _, ok := members[pr.AuthorID]; ok {
+			teamPRs = append(teamPRs, pr)
+		}
+	}
+
+	if len(teamPRs) == 0 {
+		return nil, nil, nil
+	}
+
+	replacements, unplaced, err := s.planReplacements(ctx, teamPRs, map[string]struct{}{userID: {}}, domain.CauseUserTransferred)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to plan PR reassignment: %w", err)
+	}
+
+	if len(replacements) > 0 {
+		if err := s.applyReplacements(ctx, replacements); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return replacements, unplaced, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserServiceImpl_TransferUser(t *testing.T) {
+	ctx := context.Background()
+	backend := &domain.TeamWithMembers{
+		ID:      1,
+		Name:    "backend",
+		Members: []domain.User{{ID: "author-1", TeamID: 1}, {ID: "u3", TeamID: 1, IsActive: true}},
+	}
+	payments := &domain.TeamWithMembers{ID: 2, Name: "payments"}
+	transferred := &api.User{UserId: "u1", Username: "Alice", TeamName: "payments", TeamId: 2, IsActive: true}
+
+	t.Run("Success: Reviews in the previous team are reassigned", func(t *testing.T) {
+		m := newUserTestMocks()
+		_, tx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+		m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+		m.teamRepo.On("GetTeamByName", inTx(tx), "payments").Return(payments, nil).Once()
+		m.userRepo.On("TransferUser", inTx(tx), "u1", 2).Return(transferred, 1, nil).Once()
+		m.teamRepo.On("GetTeamByID", inTx(tx), 1).Return(backend, nil).Once()
+		m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{"u1"}).Return([]domain.PullRequest{
+			{ID: "pr-1", AuthorID: "author-1", ReviewerIDs: []string{"u1"}},
+			// pr-2 is of another team, which keeps the user as its reviewer.
+			{ID: "pr-2", AuthorID: "author-2", ReviewerIDs: []string{"u1"}},
+		}, nil).Once()
+		m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil).Once()
+		m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil).Once()
+		m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, sameIDs("author-1", "u1"), 1).Return([]string{"u3"}, nil).Once()
+		m.prCmdRepo.On("LockActiveUsers", mock.Anything, []string{"u3"}).Return([]string{"u3"}, nil).Once()
+		m.prCmdRepo.On("ReplaceReviewers", inTx(tx), []domain.ReviewerReplacement{{PullRequestID: "pr-1", OldReviewerID: "u1", NewReviewerID: "u3"}}).Return(nil).Once()
+		m.historyRepo.On("RecordAssignments", inTx(tx), mock.MatchedBy(func(records []domain.AssignmentRecord) bool {
+			return len(records) == 1 && records[0].Cause == domain.CauseUserTransferred
+		})).Return(nil).Once()
+
+		resp, err := newUserTestService(m).TransferUser(ctx, "u1", "payments", true)
+		require.NoError(t, err)
+		assert.Equal(t, &api.TransferUserResponse{
+			User:             *transferred,
+			PreviousTeamId:   1,
+			PreviousTeamName: "backend",
+			Reassignments:    &[]api.ReviewerMove{{PullRequestId: "pr-1", FromUserId: "u1", ToUserId: "u3"}},
+		}, resp)
+
+		m.userRepo.AssertExpectations(t)
+		m.prCmdRepo.AssertExpectations(t)
+		m.historyRepo.AssertExpectations(t)
+		assert.NoError(t, smock.ExpectationsWereMet())
+	})
+
+	t.Run("Success: Without reassign_reviews the user keeps the reviews", func(t *testing.T) {
+		m := newUserTestMocks()
+		_, tx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+		m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+		m.teamRepo.On("GetTeamByName", inTx(tx), "payments").Return(payments, nil).Once()
+		m.userRepo.On("TransferUser", inTx(tx), "u1", 2).Return(transferred, 1, nil).Once()
+		m.teamRepo.On("GetTeamByID", inTx(tx), 1).Return(backend, nil).Once()
+
+		resp, err := newUserTestService(m).TransferUser(ctx, "u1", "payments", false)
+		require.NoError(t, err)
+		assert.Equal(t, "backend", resp.PreviousTeamName)
+		assert.Nil(t, resp.Reassignments)
+		m.prQueryRepo.AssertNotCalled(t, "GetOpenPRsByReviewers", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success: Unplaced reviews are reported", func(t *testing.T) {
+		m := newUserTestMocks()
+		_, tx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+		m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+		m.teamRepo.On("GetTeamByName", inTx(tx), "payments").Return(payments, nil).Once()
+		m.userRepo.On("TransferUser", inTx(tx), "u1", 2).Return(transferred, 1, nil).Once()
+		m.teamRepo.On("GetTeamByID", inTx(tx), 1).Return(backend, nil).Once()
+		m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{"u1"}).Return([]domain.PullRequest{
+			{ID: "pr-1", AuthorID: "author-1", ReviewerIDs: []string{"u1"}},
+		}, nil).Once()
+		m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil).Once()
+		m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil).Once()
+		m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, mock.Anything, 1).Return([]string{}, nil).Once()
+
+		resp, err := newUserTestService(m).TransferUser(ctx, "u1", "payments", true)
+		require.NoError(t, err)
+		assert.Equal(t, []api.ReviewerMove{}, *resp.Reassignments)
+		assert.Equal(t, &[]string{"pull request 'pr-1': no active replacement for reviewer 'u1'"}, resp.Warnings)
+		m.prCmdRepo.AssertNotCalled(t, "ReplaceReviewers", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure: Unknown team", func(t *testing.T) {
+		m := newUserTestMocks()
+		_, tx, smock := newMockDBAndTx(t)
+		smock.ExpectRollback()
+		m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil).Once()
+		m.teamRepo.On("GetTeamByName", inTx(tx), "ghost-team").Return(nil, apperrors.ErrNotFound).Once()
+
+		resp, err := newUserTestService(m).TransferUser(ctx, "u1", "ghost-team", true)
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+		assert.Nil(t, resp)
+		m.userRepo.AssertNotCalled(t, "TransferUser", mock.Anything, mock.Anything, mock.Anything)
+		assert.NoError(t, smock.ExpectationsWereMet())
+	})
+}
@@ -4,20 +4,76 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
 	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/localcache"
 	"github.com/YusovID/pr-reviewer-service/internal/repository"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
-	"github.com/jmoiron/sqlx"
 )
 
 // UserService defines the application's business logic for managing users and their team-wide state.
 type UserService interface {
-	// SetIsActive updates a user's active status.
-	SetIsActive(ctx context.Context, userID string, isActive bool) (*api.User, error)
-	// DeactivateTeam deactivates all members of a team and safely reassigns their open pull request reviews.
+	// SetIsActive updates a user's active status. A deactivated user's open reviews are reassigned to active
+	// teammates in the same transaction, as DeactivateTeam does with force: the reviews nobody can take over are
+	// left as they are and listed in the warnings. A user who becomes active again takes over reviews of the most
+	// loaded teammates if the team policy asks for it: at once, with the moves in the response, or in a
+	// reviewer_rebalance job, whose ID is in the response.
+	SetIsActive(ctx context.Context, userID string, isActive bool) (*api.SetIsActiveResponse, error)
+	// SetRole sets the role a user acts with through the API keys bound to the user.
+	// Returns apperrors.ErrValidation for an unknown role.
+	SetRole(ctx context.Context, userID string, role string) (*api.SetUserRoleResponse, error)
+	// GetRole returns the role of a user.
+	GetRole(ctx context.Context, userID string) (domain.UserRole, error)
+	// RebalanceReviews moves reviews of the most loaded active teammates of a user to the user until the user
+	// has a fair share of the open reviews of the team, and returns the moves. An inactive user takes over nothing.
+	RebalanceReviews(ctx context.Context, userID string) ([]api.ReviewerMove, error)
+	// DeactivateTeam deactivates the members of a team that are not in other teams and safely reassigns
+	// their open pull request reviews.
 	// Returns the count of deactivated users and reassigned PRs.
-	DeactivateTeam(ctx context.Context, teamName string) (deactivatedCount int, reassignedCount int, err error)
+	// Returns apperrors.ErrDeactivationInProgress if the same team is being deactivated concurrently.
+	// If some reviews cannot be taken over by an active user, it returns an *apperrors.InsufficientCapacityError
+	// and changes nothing, unless force is set: then the reviews are left as they are and listed in the warnings.
+	DeactivateTeam(ctx context.Context, teamName string, force bool) (*api.DeactivateTeamResponse, error)
+	// PreviewTeamDeactivation runs DeactivateTeam and rolls it back, so that nothing changes. It also returns
+	// the reassignments DeactivateTeam would make.
+	PreviewTeamDeactivation(ctx context.Context, teamName string, force bool) (*api.DeactivateTeamResponse, error)
+	// DeactivateTeamInBatches deactivates all members of a team at once and leaves the reassignment of their
+	// open pull request reviews to ProcessDeactivationBatch, in batches of up to batchSize pull requests.
+	// Reviews that no active user can take over are reported in the job warnings, as DeactivateTeam does with force.
+	// Returns apperrors.ErrValidation if batched deactivation is disabled.
+	DeactivateTeamInBatches(ctx context.Context, teamName string, batchSize int) (*api.DeactivationJob, error)
+	// ReactivateTeam undoes the latest team deactivation not undone yet: it activates its users that are still
+	// inactive members of the team and, if restoreReviewers is set, hands back to them the reviews of open pull
+	// requests that the deactivation reassigned and that have not changed hands since.
+	// Returns apperrors.ErrNotFound if the team has no deactivation to undo, apperrors.ErrDeactivationInProgress
+	// while the team is being deactivated or its batches are being reassigned, and apperrors.ErrValidation if
+	// reactivation is disabled.
+	ReactivateTeam(ctx context.Context, teamName string, restoreReviewers bool) (*api.ReactivateTeamResponse, error)
+	// GetDeactivationJob returns the progress of a batched team deactivation.
+	GetDeactivationJob(ctx context.Context, jobID int64) (*api.DeactivationJob, error)
+	// ProcessDeactivationBatch reassigns the reviews of the oldest pending batch in a transaction of its own.
+	// It reports false if no batch was pending. Concurrent calls process different batches.
+	ProcessDeactivationBatch(ctx context.Context) (bool, error)
+	// TransferUser moves a user to another team, so that the assignments from then on treat the user as its member.
+	// If reassignReviews is set, the open reviews of the user on pull requests of the members whose primary team is
+	// the previous team are reassigned to active members of that team in the same transaction; the reviews nobody
+	// can take over are left with the user and listed in the warnings.
+	TransferUser(ctx context.Context, userID string, teamName string, reassignReviews bool) (*api.TransferUserResponse, error)
+	// DeleteUser deactivates a user, reassigns the user's open reviews as SetIsActive does and then deletes
+	// the user. A deleted user is left out of teams, reviewer selection and statistics, but keeps the history.
+	DeleteUser(ctx context.Context, userID string) (*api.DeleteUserResponse, error)
+	// RestoreUser brings a deleted user back, inactive. Returns apperrors.ErrNotFound if the user is not
+	// deleted or the team of the user is.
+	RestoreUser(ctx context.Context, userID string) (*api.User, error)
+	// DeleteTeam deactivates a team as DeactivateTeam does and deletes it with its members. The name of
+	// a deleted team is free for a new team.
+	DeleteTeam(ctx context.Context, teamName string, force bool) (*api.DeleteTeamResponse, error)
+	// RestoreTeam brings a deleted team back with the members deleted with it, all inactive: ReactivateTeam
+	// activates them again. Returns apperrors.ErrNotFound if the team is not deleted and
+	// an *apperrors.TeamAlreadyExistsError if another team has taken its name.
+	RestoreTeam(ctx context.Context, teamID int) (*api.Team, error)
 }
 
 type UserServiceImpl struct {
@@ -27,6 +83,74 @@ type UserServiceImpl struct {
 	prQuery  repository.PRQueryRepository
 	prCmd    repository.PRCommandRepository
 	userPR   repository.UserPRRepository
+	history  repository.AssignmentHistoryRepository
+	jobs     repository.DeactivationJobRepository
+	selector *reviewerSelector
+	// deactivations records the team deactivations for ReactivateTeam; without it they cannot be undone.
+	deactivations repository.TeamDeactivationRepository
+	// rebalanceJobs queues the reviewer_rebalance jobs; without it the reviews are rebalanced at once.
+	rebalanceJobs repository.JobRepository
+}
+
+// UserServiceOption configures optional behaviour of UserServiceImpl.
+type UserServiceOption func(*UserServiceImpl)
+
+// WithUserClock makes the service take the current time from c instead of the system clock.
+func WithUserClock(c Clock) UserServiceOption {
+	return func(s *UserServiceImpl) {
+		s.clock = c
+	}
+}
+
+// WithUserTxMaxAttempts is WithTxMaxAttempts for the transactions of the user service.
+func WithUserTxMaxAttempts(n int) UserServiceOption {
+	return func(s *UserServiceImpl) {
+		s.txMaxAttempts = max(n, 1)
+	}
+}
+
+// WithUserDefaultStrategy is WithDefaultStrategy for the reviewers replacing deactivated users.
+func WithUserDefaultStrategy(name domain.AssignmentStrategy) UserServiceOption {
+	return func(s *UserServiceImpl) {
+		s.selector.fallback = name
+	}
+}
+
+// WithUserAssignmentStrategy is WithAssignmentStrategy for the reviewers replacing deactivated users.
+func WithUserAssignmentStrategy(strategy AssignmentStrategy) UserServiceOption {
+	return func(s *UserServiceImpl) {
+		s.selector.register(strategy)
+	}
+}
+
+// WithUserReadCache makes the committed writes of the service invalidate cache, which WithReadCache
+// and WithTeamReadCache read through.
+func WithUserReadCache(cache *localcache.Cache) UserServiceOption {
+	return func(s *UserServiceImpl) {
+		s.readCache = cache
+	}
+}
+
+// WithDeactivationJobs enables DeactivateTeamInBatches, storing its jobs in repo.
+func WithDeactivationJobs(repo repository.DeactivationJobRepository) UserServiceOption {
+	return func(s *UserServiceImpl) {
+		s.jobs = repo
+	}
+}
+
+// WithTeamDeactivations records the team deactivations in repo and enables ReactivateTeam, which undoes them.
+func WithTeamDeactivations(repo repository.TeamDeactivationRepository) UserServiceOption {
+	return func(s *UserServiceImpl) {
+		s.deactivations = repo
+	}
+}
+
+// WithRebalanceJobs queues the reviewer_rebalance jobs of teams with the job rebalance mode in repo.
+// Without it the reviews of such teams are rebalanced at once, as with the immediate mode.
+func WithRebalanceJobs(repo repository.JobRepository) UserServiceOption {
+	return func(s *UserServiceImpl) {
+		s.rebalanceJobs = repo
+	}
 }
 
 // NewUserService creates a new instance of UserServiceImpl.
@@ -36,120 +160,477 @@ func NewUserService(
 	prQuery repository.PRQueryRepository,
 	prCmd repository.PRCommandRepository,
 	userPR repository.UserPRRepository,
-	db Transactor,
+	policies repository.PolicyRepository,
+	history repository.AssignmentHistoryRepository,
+	db TxManager,
 	log *slog.Logger,
+	opts ...UserServiceOption,
 ) *UserServiceImpl {
-	return &UserServiceImpl{
+	s := &UserServiceImpl{
 		BaseService: NewBaseService(db, log),
 		repo:        repo,
 		teamRepo:    teamRepo,
 		prQuery:     prQuery,
 		prCmd:       prCmd,
 		userPR:      userPR,
+		history:     history,
+		selector:    newReviewerSelector(policies, userPR),
 	}
-}
 
-func (s *UserServiceImpl) SetIsActive(ctx context.Context, userID string, isActive bool) (*api.User, error) {
-	user, err := s.repo.SetIsActive(ctx, userID, isActive)
-	if err != nil {
-		return nil, fmt.Errorf("repo.SetIsActive failed: %w", err)
+	for _, opt := range opts {
+		opt(s)
 	}
 
-	return user, nil
+	return s
 }
 
-func (s *UserServiceImpl) DeactivateTeam(ctx context.Context, teamName string) (deactivatedCount int, reassignedCount int, err error) {
-	const op = "internal.service.user.DeactivateTeam"
-	log := s.log.With(slog.String("op", op), slog.String("team_name", teamName))
+func (s *UserServiceImpl) SetIsActive(ctx context.Context, userID string, isActive bool) (*api.SetIsActiveResponse, error) {
+	const op = "internal.service.user.SetIsActive"
 
-	err = s.transaction(ctx, op, func(tx *sqlx.Tx) error {
-		team, err := s.teamRepo.GetTeamByName(ctx, tx, teamName)
+	var (
+		resp         api.SetIsActiveResponse
+		moves        []domain.AssignmentRecord
+		replacements []domain.AssignmentRecord
+		unplaced     []unplacedReview
+		deactivated  bool
+		queueMode    bool
+	)
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		resp = api.SetIsActiveResponse{}
+		moves, replacements, unplaced = nil, nil, nil
+		deactivated, queueMode = false, false
+
+		user, wasActive, err := s.repo.SetIsActive(ctx, userID, isActive)
 		if err != nil {
-			return err
+			return fmt.Errorf("repo.SetIsActive failed: %w", err)
 		}
 
-		deactivatedUserIDs, err := s.repo.DeactivateUsersByTeamID(ctx, tx, team.ID)
-		if err != nil {
-			return fmt.Errorf("failed to deactivate users: %w", err)
+		resp.User = *user
+
+		if isActive == wasActive {
+			return nil
 		}
 
-		deactivatedCount = len(deactivatedUserIDs)
+		if !isActive {
+			deactivated = true
+
+			replacements, unplaced, err = s.reassignReviews(ctx, userID)
+			if err != nil {
+				return fmt.Errorf("failed to reassign reviews: %w", err)
+			}
 
-		if deactivatedCount == 0 {
-			log.Info("no active users to deactivate in this team")
 			return nil
 		}
 
-		prsToReassign, err := s.prQuery.GetOpenPRsByReviewers(ctx, tx, deactivatedUserIDs)
+		policy, err := s.selector.policy(ctx, user.TeamId)
 		if err != nil {
-			return fmt.Errorf("failed to get open PRs: %w", err)
+			return fmt.Errorf("failed to get team policy: %w", err)
 		}
 
-		reassignedCount = len(prsToReassign)
-		if reassignedCount == 0 {
-			log.Info("no open PRs to reassign for deactivated users")
-			return nil
+		switch {
+		case policy.ReactivationRebalance == domain.RebalanceJob && s.rebalanceJobs != nil:
+			queueMode = true
+		case policy.ReactivationRebalance == domain.RebalanceImmediate, policy.ReactivationRebalance == domain.RebalanceJob:
+			moves, err = s.rebalanceTeams(ctx, userID)
+			if err != nil {
+				return fmt.Errorf("failed to rebalance reviews: %w", err)
+			}
+
+			resp.Rebalance = &api.RebalanceResult{Moves: toAPIReviewerMoves(moves)}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reviewsRebalancedTotal.Add(float64(len(moves)))
+
+	for _, record := range replacements {
+		reviewerReassignmentsTotal.WithLabelValues(string(record.Strategy)).Inc()
+	}
+
+	if deactivated {
+		reassignments := toAPIReviewerMoves(replacements)
+		resp.Reassignments = &reassignments
+
+		if len(unplaced) > 0 {
+			warnings := make([]string, len(unplaced))
+			for i, review := range unplaced {
+				s.log.Warn("no replacement candidate found", slog.String("op", op), slog.String("pr_id", review.prID),
+					slog.String("old_reviewer_id", review.reviewerID))
+				warnings[i] = review.warning()
+			}
+
+			resp.Warnings = &warnings
 		}
+	}
 
-		deactivatedSet := make(map[string]struct{}, len(deactivatedUserIDs))
-		for _, id := range deactivatedUserIDs {
-			deactivatedSet[id] = struct{}{}
+	if queueMode {
+		// The job is queued once the user is active, so that it cannot run before the activation commits.
+		job, err := s.queueRebalance(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: user activated, but failed to queue rebalance: %w", op, err)
 		}
 
-		if err := s.reassignPRsForDeactivatedUsers(ctx, tx, team, prsToReassign, deactivatedSet); err != nil {
-			return fmt.Errorf("failed during PR reassignment: %w", err)
+		s.log.Info("review rebalance queued", slog.String("op", op), slog.String("user_id", userID), slog.Int64("job_id", job.ID))
+
+		resp.Rebalance = &api.RebalanceResult{Moves: []api.ReviewerMove{}, JobId: &job.ID}
+	}
+
+	return &resp, nil
+}
+
+// reassignReviews replaces userID, who has just been deactivated, on the reviews of open pull requests
+// with active members of their teams. It returns the replacements made and the reviews left with the user.
+func (s *UserServiceImpl) reassignReviews(ctx context.Context, userID string) ([]domain.AssignmentRecord, []unplacedReview, error) {
+	prs, err := s.prQuery.GetOpenPRsByReviewers(ctx, []string{userID})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get open PRs: %w", err)
+	}
+
+	if len(prs) == 0 {
+		return nil, nil, nil
+	}
+
+	replacements, unplaced, err := s.planReplacements(ctx, prs, map[string]struct{}{userID: {}}, domain.CauseUserDeactivated)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to plan PR reassignment: %w", err)
+	}
+
+	if len(replacements) > 0 {
+		if err := s.applyReplacements(ctx, replacements); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return replacements, unplaced, nil
+}
+
+// SetRole validates role and assigns it to the user.
+func (s *UserServiceImpl) SetRole(ctx context.Context, userID string, role string) (*api.SetUserRoleResponse, error) {
+	const op = "internal.service.user.SetRole"
+
+	userRole := domain.UserRole(role)
+	if !userRole.IsValid() {
+		return nil, fmt.Errorf("%w: unknown role '%s'", apperrors.ErrValidation, role)
+	}
+
+	if err := s.repo.SetUserRole(ctx, userID, userRole); err != nil {
+		return nil, fmt.Errorf("%s: failed to set role: %w", op, err)
+	}
+
+	s.log.Info("user role set", slog.String("op", op), slog.String("user_id", userID), slog.String("role", role))
+
+	return &api.SetUserRoleResponse{UserId: userID, Role: api.UserRole(userRole)}, nil
+}
+
+// GetRole returns the role of the user.
+func (s *UserServiceImpl) GetRole(ctx context.Context, userID string) (domain.UserRole, error) {
+	const op = "internal.service.user.GetRole"
+
+	role, err := s.repo.GetUserRole(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to get role: %w", op, err)
+	}
+
+	return role, nil
+}
+
+func (s *UserServiceImpl) DeactivateTeam(ctx context.Context, teamName string, force bool) (*api.DeactivateTeamResponse, error) {
+	const op = "internal.service.user.DeactivateTeam"
+
+	return s.deactivateTeam(ctx, op, teamName, force, false)
+}
+
+func (s *UserServiceImpl) PreviewTeamDeactivation(ctx context.Context, teamName string, force bool) (*api.DeactivateTeamResponse, error) {
+	const op = "internal.service.user.PreviewTeamDeactivation"
+
+	return s.deactivateTeam(ctx, op, teamName, force, true)
+}
+
+// deactivateTeam deactivates the team in a transaction that a dry run rolls back.
+func (s *UserServiceImpl) deactivateTeam(ctx context.Context, op string, teamName string, force bool, dryRun bool) (*api.DeactivateTeamResponse, error) {
+	log := s.log.With(slog.String("op", op), slog.String("team_name", teamName))
+
+	var deactivation *teamDeactivation
+
+	err := s.transaction(ctx, op, func(ctx context.Context) error {
+		var err error
+
+		deactivation, err = s.deactivateTeamMembers(ctx, log, teamName, force)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errRollback
 		}
 
 		return nil
 	})
 
 	if err != nil {
-		return 0, 0, err
+		return nil, err
+	}
+
+	resp := &api.DeactivateTeamResponse{
+		DeactivatedUsersCount: deactivation.deactivatedCount,
+		ReassignedPrsCount:    deactivation.reassignedCount,
+	}
+
+	if dryRun {
+		moves := toAPIReviewerMoves(deactivation.replacements)
+		resp.Reassignments = &moves
+	} else {
+		for _, record := range deactivation.replacements {
+			reviewerReassignmentsTotal.WithLabelValues(string(record.Strategy)).Inc()
+		}
+	}
+
+	if len(deactivation.warnings) > 0 {
+		resp.Warnings = &deactivation.warnings
+	}
+
+	return resp, nil
+}
+
+// teamDeactivation is what deactivateTeamMembers did to a team.
+type teamDeactivation struct {
+	team             *domain.TeamWithMembers
+	deactivatedCount int
+	reassignedCount  int
+	replacements     []domain.AssignmentRecord
+	warnings         []string
+}
+
+// deactivateTeamMembers deactivates the active members of a team and reassigns their open reviews
+// in the transaction of ctx. Without force it refuses, with an *apperrors.InsufficientCapacityError,
+// a deactivation that would leave reviews nobody can take over.
+func (s *UserServiceImpl) deactivateTeamMembers(ctx context.Context, log *slog.Logger, teamName string, force bool) (*teamDeactivation, error) {
+	team, deactivatedUserIDs, prsToReassign, err := s.deactivateMembers(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	deactivation := &teamDeactivation{team: team, deactivatedCount: len(deactivatedUserIDs)}
+
+	if deactivation.deactivatedCount == 0 {
+		log.Info("no active users to deactivate in this team")
+		return deactivation, nil
+	}
+
+	if err := s.recordDeactivation(ctx, team.ID, deactivatedUserIDs, nil); err != nil {
+		return nil, err
+	}
+
+	deactivation.reassignedCount = len(prsToReassign)
+	if deactivation.reassignedCount == 0 {
+		log.Info("no open PRs to reassign for deactivated users")
+		return deactivation, nil
+	}
+
+	deactivatedSet := make(map[string]struct{}, len(deactivatedUserIDs))
+	for _, id := range deactivatedUserIDs {
+		deactivatedSet[id] = struct{}{}
+	}
+
+	replacements, unplaced, err := s.planReplacements(ctx, prsToReassign, deactivatedSet, domain.CauseTeamDeactivated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan PR reassignment: %w", err)
 	}
 
-	return deactivatedCount, reassignedCount, nil
+	if len(unplaced) > 0 {
+		if !force {
+			return nil, &apperrors.InsufficientCapacityError{TeamName: team.Name, PRIDs: unplacedPRIDs(unplaced)}
+		}
+
+		for _, review := range unplaced {
+			log.Warn("no replacement candidate found", "pr_id", review.prID, "old_reviewer_id", review.reviewerID)
+			deactivation.warnings = append(deactivation.warnings, review.warning())
+		}
+	}
+
+	if err := s.applyReplacements(ctx, replacements); err != nil {
+		return nil, fmt.Errorf("failed during PR reassignment: %w", err)
+	}
+
+	deactivation.replacements = replacements
+
+	return deactivation, nil
 }
 
-func (s *UserServiceImpl) reassignPRsForDeactivatedUsers(
+// deactivateMembers locks the team against concurrent deactivations, deactivates its active members
+// and returns them with their open pull requests, locked for the rest of the transaction.
+func (s *UserServiceImpl) deactivateMembers(ctx context.Context, teamName string) (*domain.TeamWithMembers, []string, []domain.PullRequest, error) {
+	team, err := s.teamRepo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	locked, err := s.teamRepo.TryLockTeamForDeactivation(ctx, team.ID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to lock team: %w", err)
+	}
+
+	if !locked {
+		return nil, nil, nil, apperrors.ErrDeactivationInProgress
+	}
+
+	deactivatedUserIDs, err := s.repo.DeactivateUsersByTeamID(ctx, team.ID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to deactivate users: %w", err)
+	}
+
+	if len(deactivatedUserIDs) == 0 {
+		return team, deactivatedUserIDs, nil, nil
+	}
+
+	prs, err := s.prQuery.GetOpenPRsByReviewers(ctx, deactivatedUserIDs)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get open PRs: %w", err)
+	}
+
+	return team, deactivatedUserIDs, prs, nil
+}
+
+// unplacedReview is a review of a deactivated user that no active teammate can take over.
+type unplacedReview struct {
+	prID       string
+	reviewerID string
+}
+
+func (r unplacedReview) warning() string {
+	return fmt.Sprintf("pull request '%s': no active replacement for reviewer '%s'", r.prID, r.reviewerID)
+}
+
+func unplacedPRIDs(reviews []unplacedReview) []string {
+	prIDs := make([]string, 0, len(reviews))
+	for _, review := range reviews {
+		if !slices.Contains(prIDs, review.prID) {
+			prIDs = append(prIDs, review.prID)
+		}
+	}
+
+	return prIDs
+}
+
+// planReplacements picks a replacement for every review of a deactivated user before anything is changed,
+// so that a deactivation without enough capacity can be refused as a whole. The replacement on a pull request
+// comes from the team its reviewers were picked from, which need not be a team of the deactivated user.
+// It returns the replacements that can be made, recorded with cause, and the reviews that no active user can take over.
+func (s *UserServiceImpl) planReplacements(
 	ctx context.Context,
-	tx *sqlx.Tx,
-	team *domain.TeamWithMembers,
 	prsToReassign []domain.PullRequest,
 	deactivatedSet map[string]struct{},
-) error {
-	log := s.log.With(slog.String("op", "internal.service.user.reassignPRs"))
+	cause domain.AssignmentCause,
+) ([]domain.AssignmentRecord, []unplacedReview, error) {
+	var (
+		replacements []domain.AssignmentRecord
+		unplaced     []unplacedReview
+		replacedAt   = s.now()
+		policies     = make(map[int]*domain.TeamPolicy)
+	)
 
 	for _, pr := range prsToReassign {
+		teamID, err := s.userPR.GetPRTeamID(ctx, pr.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get team of pr %s: %w", pr.ID, err)
+		}
+
+		policy, ok := policies[teamID]
+		if !ok {
+			policy, err = s.selector.policy(ctx, teamID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get team policy: %w", err)
+			}
+
+			policies[teamID] = policy
+		}
+
 		originalReviewers := make([]string, len(pr.ReviewerIDs))
 		copy(originalReviewers, pr.ReviewerIDs)
 
 		for _, oldReviewerID := range originalReviewers {
-			if _, isDeactivated := deactivatedSet[oldReviewerID]; isDeactivated {
-				excludeIDs := excludeIDs(&pr, pr.ReviewerIDs)
+			if _, isDeactivated := deactivatedSet[oldReviewerID]; !isDeactivated {
+				continue
+			}
 
-				candidates, err := s.userPR.GetRandomActiveReviewers(ctx, team.ID, excludeIDs, 1)
-				if err != nil {
-					return fmt.Errorf("failed to find replacement for pr %s: %w", pr.ID, err)
-				}
+			newReviewerID, strategy, err := s.selectReplacement(ctx, policy, &pr)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to find replacement for pr %s: %w", pr.ID, err)
+			}
+
+			if newReviewerID == "" {
+				unplaced = append(unplaced, unplacedReview{prID: pr.ID, reviewerID: oldReviewerID})
+				continue
+			}
 
-				if len(candidates) > 0 {
-					newReviewerID := candidates[0]
-					if err := s.prCmd.ReplaceReviewer(ctx, tx, pr.ID, oldReviewerID, newReviewerID); err != nil {
-						return fmt.Errorf("failed to replace reviewer for pr %s: %w", pr.ID, err)
-					}
-
-					for i, id := range pr.ReviewerIDs {
-						if id == oldReviewerID {
-							pr.ReviewerIDs[i] = newReviewerID
-							break
-						}
-					}
-				} else {
-					log.Warn("no replacement candidate found", "pr_id", pr.ID, "old_reviewer_id", oldReviewerID)
+			replacements = append(replacements, replacementRecord(pr.ID, oldReviewerID, newReviewerID, strategy, cause, replacedAt))
+
+			for i, id := range pr.ReviewerIDs {
+				if id == oldReviewerID {
+					pr.ReviewerIDs[i] = newReviewerID
+					break
 				}
 			}
 		}
 	}
 
+	return replacements, unplaced, nil
+}
+
+// selectReplacement selects an active user to take over a review of pr and locks the user until the transaction
+// of ctx ends, so that the user cannot be deactivated before the replacement is made. A candidate deactivated
+// concurrently is excluded and another one is selected. It returns an empty ID when nobody can take the review over.
+func (s *UserServiceImpl) selectReplacement(ctx context.Context, policy *domain.TeamPolicy, pr *domain.PullRequest) (string, domain.AssignmentStrategy, error) {
+	excludedIDs := excludeIDs(pr, pr.ReviewerIDs)
+
+	for {
+		candidates, strategy, err := s.selector.selectWithPolicy(ctx, policy, excludedIDs, 1)
+		if err != nil {
+			return "", "", err
+		}
+
+		if len(candidates) == 0 {
+			return "", "", nil
+		}
+
+		activeIDs, err := keepActiveReviewers(ctx, s.prCmd, s.log, pr.ID, candidates)
+		if err != nil {
+			return "", "", err
+		}
+
+		if len(activeIDs) > 0 {
+			return activeIDs[0], strategy, nil
+		}
+
+		excludedIDs = append(excludedIDs, candidates...)
+	}
+}
+
+// applyReplacements swaps the reviewers as planned in one batch and records the changes in the assignment history.
+func (s *UserServiceImpl) applyReplacements(ctx context.Context, replacements []domain.AssignmentRecord) error {
+	if len(replacements) > 0 {
+		batch := make([]domain.ReviewerReplacement, 0, len(replacements))
+		for _, record := range replacements {
+			batch = append(batch, domain.ReviewerReplacement{
+				PullRequestID: record.PullRequestID,
+				OldReviewerID: *record.ReplacedUserID,
+				NewReviewerID: record.UserID,
+			})
+		}
+
+		if err := s.prCmd.ReplaceReviewers(ctx, batch); err != nil {
+			return fmt.Errorf("failed to replace reviewers: %w", err)
+		}
+	}
+
+	if err := s.history.RecordAssignments(ctx, replacements); err != nil {
+		return fmt.Errorf("failed to record assignment history: %w", err)
+	}
+
 	return nil
 }
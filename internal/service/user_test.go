@@ -3,15 +3,19 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log/slog"
 	"os"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
 	"github.com/YusovID/pr-reviewer-service/internal/domain"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // Helper struct to hold all mocks, defined at the package level
@@ -21,6 +25,8 @@ type mocks struct {
 	prQueryRepo *PRQueryRepositoryMock
 	prCmdRepo   *PRCommandRepositoryMock
 	userPRRepo  *UserPRRepositoryMock
+	policyRepo  *PolicyRepositoryMock
+	historyRepo *AssignmentHistoryRepositoryMock
 	transactor  *TransactorMock
 }
 
@@ -31,59 +37,141 @@ func TestUserServiceImpl_SetIsActive(t *testing.T) {
 		UserId:   testUserID,
 		Username: "Test User",
 		TeamName: "backend",
+		TeamId:   1,
 		IsActive: false,
 	}
+	noReassignments := []api.ReviewerMove{}
 
 	testCases := []struct {
 		name          string
-		setupMock     func(repoMock *UserRepositoryMock)
+		setupMock     func(m *mocks, tx *sqlx.Tx, smock sqlmock.Sqlmock)
 		userID        string
 		isActive      bool
-		expectedUser  *api.User
+		expectedResp  *api.SetIsActiveResponse
 		expectedError bool
 	}{
 		{
 			name: "Success: User status is updated",
-			setupMock: func(repoMock *UserRepositoryMock) {
-				repoMock.On(
+			setupMock: func(m *mocks, tx *sqlx.Tx, smock sqlmock.Sqlmock) {
+				smock.ExpectCommit()
+				m.userRepo.On(
 					"SetIsActive",
-					mock.Anything,
+					inTx(tx),
 					testUserID,
 					false,
-				).Return(expectedUser, nil)
+				).Return(expectedUser, true, nil)
+				m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{testUserID}).Return([]domain.PullRequest{}, nil).Once()
 			},
 			userID:        testUserID,
 			isActive:      false,
-			expectedUser:  expectedUser,
+			expectedResp:  &api.SetIsActiveResponse{User: *expectedUser, Reassignments: &noReassignments},
+			expectedError: false,
+		},
+		{
+			name: "Success: Open reviews of deactivated user are reassigned",
+			setupMock: func(m *mocks, tx *sqlx.Tx, smock sqlmock.Sqlmock) {
+				smock.ExpectCommit()
+				m.userRepo.On("SetIsActive", inTx(tx), testUserID, false).Return(expectedUser, true, nil)
+				m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{testUserID}).Return([]domain.PullRequest{
+					{ID: "pr-1", AuthorID: "author-1", ReviewerIDs: []string{testUserID, "u3"}},
+					{ID: "pr-2", AuthorID: "author-2", ReviewerIDs: []string{testUserID}},
+				}, nil).Once()
+				m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil).Once()
+				m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-2").Return(1, nil).Once()
+				m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil).Once()
+				m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, sameIDs("author-1", "u1", "u3"), 1).Return([]string{"u4"}, nil).Once()
+				m.prCmdRepo.On("LockActiveUsers", mock.Anything, []string{"u4"}).Return([]string{"u4"}, nil).Once()
+				m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, sameIDs("author-2", "u1"), 1).Return([]string{}, nil).Once()
+				m.prCmdRepo.On("ReplaceReviewers", inTx(tx), []domain.ReviewerReplacement{{PullRequestID: "pr-1", OldReviewerID: testUserID, NewReviewerID: "u4"}}).Return(nil).Once()
+				m.historyRepo.On("RecordAssignments", inTx(tx), mock.MatchedBy(func(records []domain.AssignmentRecord) bool {
+					return len(records) == 1 && records[0].PullRequestID == "pr-1" && records[0].Cause == domain.CauseUserDeactivated
+				})).Return(nil).Once()
+			},
+			userID:   testUserID,
+			isActive: false,
+			expectedResp: &api.SetIsActiveResponse{
+				User:          *expectedUser,
+				Reassignments: &[]api.ReviewerMove{{PullRequestId: "pr-1", FromUserId: testUserID, ToUserId: "u4"}},
+				Warnings:      &[]string{"pull request 'pr-2': no active replacement for reviewer 'u1'"},
+			},
+			expectedError: false,
+		},
+		{
+			name: "Success: Replacement deactivated since its selection is skipped",
+			setupMock: func(m *mocks, tx *sqlx.Tx, smock sqlmock.Sqlmock) {
+				smock.ExpectCommit()
+				m.userRepo.On("SetIsActive", inTx(tx), testUserID, false).Return(expectedUser, true, nil)
+				m.prQueryRepo.On("GetOpenPRsByReviewers", inTx(tx), []string{testUserID}).Return([]domain.PullRequest{
+					{ID: "pr-1", AuthorID: "author-1", ReviewerIDs: []string{testUserID}},
+				}, nil).Once()
+				m.userPRRepo.On("GetPRTeamID", inTx(tx), "pr-1").Return(1, nil).Once()
+				m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil).Once()
+				m.userPRRepo.On("GetRandomActiveReviewers", inTx(tx), 1, sameIDs("author-1", "u1"), 1).Return([]string{"u5"}, nil).Once()
+				m.prCmdRepo.On("LockActiveUsers", inTx(tx), []string{"u5"}).Return([]string{}, nil).Once()
+				m.userPRRepo.On("GetRandomActiveReviewers", inTx(tx), 1, sameIDs("author-1", "u1", "u5"), 1).Return([]string{"u4"}, nil).Once()
+				m.prCmdRepo.On("LockActiveUsers", inTx(tx), []string{"u4"}).Return([]string{"u4"}, nil).Once()
+				m.prCmdRepo.On("ReplaceReviewers", inTx(tx), []domain.ReviewerReplacement{{PullRequestID: "pr-1", OldReviewerID: testUserID, NewReviewerID: "u4"}}).Return(nil).Once()
+				m.historyRepo.On("RecordAssignments", inTx(tx), mock.Anything).Return(nil).Once()
+			},
+			userID:   testUserID,
+			isActive: false,
+			expectedResp: &api.SetIsActiveResponse{
+				User:          *expectedUser,
+				Reassignments: &[]api.ReviewerMove{{PullRequestId: "pr-1", FromUserId: testUserID, ToUserId: "u4"}},
+			},
+			expectedError: false,
+		},
+		{
+			name: "Success: Inactive user is left as is",
+			setupMock: func(m *mocks, tx *sqlx.Tx, smock sqlmock.Sqlmock) {
+				smock.ExpectCommit()
+				m.userRepo.On("SetIsActive", inTx(tx), testUserID, false).Return(expectedUser, false, nil)
+			},
+			userID:        testUserID,
+			isActive:      false,
+			expectedResp:  &api.SetIsActiveResponse{User: *expectedUser},
 			expectedError: false,
 		},
 		{
 			name: "Failure: User not found in repository",
-			setupMock: func(repoMock *UserRepositoryMock) {
-				repoMock.On(
+			setupMock: func(m *mocks, tx *sqlx.Tx, smock sqlmock.Sqlmock) {
+				smock.ExpectRollback()
+				m.userRepo.On(
 					"SetIsActive",
-					mock.Anything,
+					inTx(tx),
 					testUserID,
 					false,
-				).Return(nil, apperrors.ErrNotFound)
+				).Return(nil, false, apperrors.ErrNotFound)
 			},
 			userID:        testUserID,
 			isActive:      false,
-			expectedUser:  nil,
+			expectedResp:  nil,
 			expectedError: true,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			repoMock := new(UserRepositoryMock)
-			tc.setupMock(repoMock)
+			m := &mocks{
+				userRepo:    new(UserRepositoryMock),
+				prQueryRepo: new(PRQueryRepositoryMock),
+				prCmdRepo:   new(PRCommandRepositoryMock),
+				userPRRepo:  new(UserPRRepositoryMock),
+				policyRepo:  new(PolicyRepositoryMock),
+				historyRepo: new(AssignmentHistoryRepositoryMock),
+				transactor:  new(TransactorMock),
+			}
+			_, mockedTx, smock := newMockDBAndTx(t)
+
+			m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+			tc.setupMock(m, mockedTx, smock)
 
-			service := NewUserService(repoMock, nil, nil, nil, nil, nil, slog.Default())
+			service := NewUserService(m.userRepo, nil, m.prQueryRepo, m.prCmdRepo, m.userPRRepo, m.policyRepo, m.historyRepo,
+				m.transactor, slog.Default())
 
-			resultUser, err := service.SetIsActive(ctx, tc.userID, tc.isActive)
+			resp, err := service.SetIsActive(ctx, tc.userID, tc.isActive)
 
-			assert.Equal(t, tc.expectedUser, resultUser)
+			assert.Equal(t, tc.expectedResp, resp)
 
 			if tc.expectedError {
 				assert.Error(t, err)
@@ -92,7 +180,12 @@ func TestUserServiceImpl_SetIsActive(t *testing.T) {
 				assert.NoError(t, err)
 			}
 
-			repoMock.AssertExpectations(t)
+			m.userRepo.AssertExpectations(t)
+			m.prQueryRepo.AssertExpectations(t)
+			m.prCmdRepo.AssertExpectations(t)
+			m.userPRRepo.AssertExpectations(t)
+			m.historyRepo.AssertExpectations(t)
+			assert.NoError(t, smock.ExpectationsWereMet())
 		})
 	}
 }
@@ -110,9 +203,13 @@ func TestUserServiceImpl_DeactivateTeam(t *testing.T) {
 	testCases := []struct {
 		name                     string
 		teamName                 string
+		force                    bool
+		dryRun                   bool
 		setupMocks               func(m *mocks)
 		expectedDeactivatedCount int
 		expectedReassignedCount  int
+		expectedReassignments    []api.ReviewerMove
+		expectedWarnings         []string
 		expectedError            error
 	}{
 		{
@@ -126,16 +223,51 @@ func TestUserServiceImpl_DeactivateTeam(t *testing.T) {
 				_, tx, smock := newMockDBAndTx(t)
 				smock.ExpectCommit()
 				m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil)
-				m.teamRepo.On("GetTeamByName", ctx, mock.Anything, "test-team").Return(teamInDB, nil)
-				m.userRepo.On("DeactivateUsersByTeamID", ctx, mock.Anything, 1).Return(deactivatedUserIDs, nil)
-				m.prQueryRepo.On("GetOpenPRsByReviewers", ctx, mock.Anything, mock.Anything).Return(prsToReassign, nil)
-				m.userPRRepo.On("GetRandomActiveReviewers", ctx, 1, mock.Anything, 1).Return([]string{"new-rev"}, nil)
-				m.prCmdRepo.On("ReplaceReviewer", ctx, mock.Anything, "pr-1", "u1", "new-rev").Return(nil)
+				m.teamRepo.On("GetTeamByName", mock.Anything, "test-team").Return(teamInDB, nil)
+				m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil)
+				m.userRepo.On("DeactivateUsersByTeamID", mock.Anything, 1).Return(deactivatedUserIDs, nil)
+				m.prQueryRepo.On("GetOpenPRsByReviewers", mock.Anything, mock.Anything).Return(prsToReassign, nil)
+				m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil)
+				m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil)
+				m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, mock.Anything, 1).Return([]string{"new-rev"}, nil)
+				m.prCmdRepo.On("LockActiveUsers", mock.Anything, []string{"new-rev"}).Return([]string{"new-rev"}, nil)
+				m.prCmdRepo.On("ReplaceReviewers", mock.Anything, []domain.ReviewerReplacement{{PullRequestID: "pr-1", OldReviewerID: "u1", NewReviewerID: "new-rev"}}).Return(nil)
+				m.historyRepo.On("RecordAssignments", mock.Anything, mock.MatchedBy(func(records []domain.AssignmentRecord) bool {
+					return len(records) == 1 && records[0].UserID == "new-rev" && records[0].Strategy == domain.StrategyRandom &&
+						records[0].Cause == domain.CauseTeamDeactivated
+				})).Return(nil)
 			},
 			expectedDeactivatedCount: 2,
 			expectedReassignedCount:  1,
 			expectedError:            nil,
 		},
+		{
+			name:     "Success: Dry run rolls the deactivation back",
+			teamName: "test-team",
+			dryRun:   true,
+			setupMocks: func(m *mocks) {
+				prsToReassign := []domain.PullRequest{
+					{ID: "pr-1", AuthorID: "author-1", ReviewerIDs: []string{"u1", "u3"}},
+				}
+
+				_, tx, smock := newMockDBAndTx(t)
+				smock.ExpectRollback()
+				m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil)
+				m.teamRepo.On("GetTeamByName", mock.Anything, "test-team").Return(teamInDB, nil)
+				m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil)
+				m.userRepo.On("DeactivateUsersByTeamID", mock.Anything, 1).Return(deactivatedUserIDs, nil)
+				m.prQueryRepo.On("GetOpenPRsByReviewers", mock.Anything, mock.Anything).Return(prsToReassign, nil)
+				m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil)
+				m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil)
+				m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, mock.Anything, 1).Return([]string{"new-rev"}, nil)
+				m.prCmdRepo.On("LockActiveUsers", mock.Anything, []string{"new-rev"}).Return([]string{"new-rev"}, nil)
+				m.prCmdRepo.On("ReplaceReviewers", mock.Anything, []domain.ReviewerReplacement{{PullRequestID: "pr-1", OldReviewerID: "u1", NewReviewerID: "new-rev"}}).Return(nil)
+				m.historyRepo.On("RecordAssignments", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedDeactivatedCount: 2,
+			expectedReassignedCount:  1,
+			expectedReassignments:    []api.ReviewerMove{{PullRequestId: "pr-1", FromUserId: "u1", ToUserId: "new-rev"}},
+		},
 		{
 			name:     "Failure: Team not found",
 			teamName: "unknown-team",
@@ -143,10 +275,22 @@ func TestUserServiceImpl_DeactivateTeam(t *testing.T) {
 				_, tx, smock := newMockDBAndTx(t)
 				smock.ExpectRollback()
 				m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil)
-				m.teamRepo.On("GetTeamByName", ctx, mock.Anything, "unknown-team").Return(nil, apperrors.ErrNotFound)
+				m.teamRepo.On("GetTeamByName", mock.Anything, "unknown-team").Return(nil, apperrors.ErrNotFound)
 			},
 			expectedError: apperrors.ErrNotFound,
 		},
+		{
+			name:     "Failure: Deactivation already in progress",
+			teamName: "test-team",
+			setupMocks: func(m *mocks) {
+				_, tx, smock := newMockDBAndTx(t)
+				smock.ExpectRollback()
+				m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil)
+				m.teamRepo.On("GetTeamByName", mock.Anything, "test-team").Return(teamInDB, nil)
+				m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(false, nil)
+			},
+			expectedError: apperrors.ErrDeactivationInProgress,
+		},
 		{
 			name:     "Success: No active users to deactivate",
 			teamName: "test-team",
@@ -154,8 +298,9 @@ func TestUserServiceImpl_DeactivateTeam(t *testing.T) {
 				_, tx, smock := newMockDBAndTx(t)
 				smock.ExpectCommit()
 				m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil)
-				m.teamRepo.On("GetTeamByName", ctx, mock.Anything, "test-team").Return(teamInDB, nil)
-				m.userRepo.On("DeactivateUsersByTeamID", ctx, mock.Anything, 1).Return([]string{}, nil)
+				m.teamRepo.On("GetTeamByName", mock.Anything, "test-team").Return(teamInDB, nil)
+				m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil)
+				m.userRepo.On("DeactivateUsersByTeamID", mock.Anything, 1).Return([]string{}, nil)
 			},
 			expectedDeactivatedCount: 0,
 			expectedReassignedCount:  0,
@@ -167,31 +312,84 @@ func TestUserServiceImpl_DeactivateTeam(t *testing.T) {
 				_, tx, smock := newMockDBAndTx(t)
 				smock.ExpectCommit()
 				m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil)
-				m.teamRepo.On("GetTeamByName", ctx, mock.Anything, "test-team").Return(teamInDB, nil)
-				m.userRepo.On("DeactivateUsersByTeamID", ctx, mock.Anything, 1).Return(deactivatedUserIDs, nil)
-				m.prQueryRepo.On("GetOpenPRsByReviewers", ctx, mock.Anything, mock.Anything).Return([]domain.PullRequest{}, nil)
+				m.teamRepo.On("GetTeamByName", mock.Anything, "test-team").Return(teamInDB, nil)
+				m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil)
+				m.userRepo.On("DeactivateUsersByTeamID", mock.Anything, 1).Return(deactivatedUserIDs, nil)
+				m.prQueryRepo.On("GetOpenPRsByReviewers", mock.Anything, mock.Anything).Return([]domain.PullRequest{}, nil)
 			},
 			expectedDeactivatedCount: 2,
 			expectedReassignedCount:  0,
 		},
 		{
-			name:     "Success: No replacement candidate found",
+			name:     "Failure: No replacement candidate found",
 			teamName: "test-team",
 			setupMocks: func(m *mocks) {
 				prsToReassign := []domain.PullRequest{
 					{ID: "pr-1", AuthorID: "author-1", ReviewerIDs: []string{"u1", "u3"}},
 				}
 
+				_, tx, smock := newMockDBAndTx(t)
+				smock.ExpectRollback()
+				m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil)
+				m.teamRepo.On("GetTeamByName", mock.Anything, "test-team").Return(teamInDB, nil)
+				m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil)
+				m.userRepo.On("DeactivateUsersByTeamID", mock.Anything, 1).Return(deactivatedUserIDs, nil)
+				m.prQueryRepo.On("GetOpenPRsByReviewers", mock.Anything, mock.Anything).Return(prsToReassign, nil)
+				m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil)
+				m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil)
+				m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, mock.Anything, 1).Return([]string{}, nil)
+			},
+			expectedError: apperrors.ErrInsufficientCapacity,
+		},
+		{
+			name:     "Failure: Capacity check runs before any reviewer is replaced",
+			teamName: "test-team",
+			setupMocks: func(m *mocks) {
+				prsToReassign := []domain.PullRequest{
+					{ID: "pr-1", AuthorID: "author-1", ReviewerIDs: []string{"u1", "u3"}},
+					{ID: "pr-2", AuthorID: "author-2", ReviewerIDs: []string{"u2"}},
+				}
+
+				_, tx, smock := newMockDBAndTx(t)
+				smock.ExpectRollback()
+				m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil)
+				m.teamRepo.On("GetTeamByName", mock.Anything, "test-team").Return(teamInDB, nil)
+				m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil)
+				m.userRepo.On("DeactivateUsersByTeamID", mock.Anything, 1).Return(deactivatedUserIDs, nil)
+				m.prQueryRepo.On("GetOpenPRsByReviewers", mock.Anything, mock.Anything).Return(prsToReassign, nil)
+				m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil)
+				m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-2").Return(1, nil)
+				m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil)
+				m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, mock.Anything, 1).Return([]string{"new-rev"}, nil).Once()
+				m.prCmdRepo.On("LockActiveUsers", mock.Anything, []string{"new-rev"}).Return([]string{"new-rev"}, nil).Once()
+				m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, mock.Anything, 1).Return([]string{}, nil).Once()
+			},
+			expectedError: &apperrors.InsufficientCapacityError{TeamName: "test-team", PRIDs: []string{"pr-2"}},
+		},
+		{
+			name:     "Success: Forced deactivation reports unplaced reviews",
+			teamName: "test-team",
+			force:    true,
+			setupMocks: func(m *mocks) {
+				prsToReassign := []domain.PullRequest{
+					{ID: "pr-1", AuthorID: "author-1", ReviewerIDs: []string{"u1", "u3"}},
+				}
+
 				_, tx, smock := newMockDBAndTx(t)
 				smock.ExpectCommit()
 				m.transactor.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(tx, nil)
-				m.teamRepo.On("GetTeamByName", ctx, mock.Anything, "test-team").Return(teamInDB, nil)
-				m.userRepo.On("DeactivateUsersByTeamID", ctx, mock.Anything, 1).Return(deactivatedUserIDs, nil)
-				m.prQueryRepo.On("GetOpenPRsByReviewers", ctx, mock.Anything, mock.Anything).Return(prsToReassign, nil)
-				m.userPRRepo.On("GetRandomActiveReviewers", ctx, 1, mock.Anything, 1).Return([]string{}, nil)
+				m.teamRepo.On("GetTeamByName", mock.Anything, "test-team").Return(teamInDB, nil)
+				m.teamRepo.On("TryLockTeamForDeactivation", inTx(tx), 1).Return(true, nil)
+				m.userRepo.On("DeactivateUsersByTeamID", mock.Anything, 1).Return(deactivatedUserIDs, nil)
+				m.prQueryRepo.On("GetOpenPRsByReviewers", mock.Anything, mock.Anything).Return(prsToReassign, nil)
+				m.userPRRepo.On("GetPRTeamID", mock.Anything, "pr-1").Return(1, nil)
+				m.policyRepo.On("GetTeamPolicy", mock.Anything, 1).Return(&domain.TeamPolicy{TeamID: 1}, nil)
+				m.userPRRepo.On("GetRandomActiveReviewers", mock.Anything, 1, mock.Anything, 1).Return([]string{}, nil)
+				m.historyRepo.On("RecordAssignments", mock.Anything, []domain.AssignmentRecord(nil)).Return(nil)
 			},
 			expectedDeactivatedCount: 2,
 			expectedReassignedCount:  1,
+			expectedWarnings:         []string{"pull request 'pr-1': no active replacement for reviewer 'u1'"},
 		},
 	}
 
@@ -203,22 +401,53 @@ func TestUserServiceImpl_DeactivateTeam(t *testing.T) {
 				prQueryRepo: new(PRQueryRepositoryMock),
 				prCmdRepo:   new(PRCommandRepositoryMock),
 				userPRRepo:  new(UserPRRepositoryMock),
+				policyRepo:  new(PolicyRepositoryMock),
+				historyRepo: new(AssignmentHistoryRepositoryMock),
 				transactor:  new(TransactorMock),
 			}
 			tc.setupMocks(m)
 
-			service := NewUserService(m.userRepo, m.teamRepo, m.prQueryRepo, m.prCmdRepo, m.userPRRepo, m.transactor, logger)
+			service := NewUserService(
+				m.userRepo, m.teamRepo, m.prQueryRepo, m.prCmdRepo, m.userPRRepo, m.policyRepo, m.historyRepo, m.transactor, logger,
+			)
 
-			deactivated, reassigned, err := service.DeactivateTeam(ctx, tc.teamName)
+			deactivate := service.DeactivateTeam
+			if tc.dryRun {
+				deactivate = service.PreviewTeamDeactivation
+			}
 
-			assert.Equal(t, tc.expectedDeactivatedCount, deactivated)
-			assert.Equal(t, tc.expectedReassignedCount, reassigned)
+			resp, err := deactivate(ctx, tc.teamName, tc.force)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
-				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, resp)
+
+				var capacityErr *apperrors.InsufficientCapacityError
+				if errors.As(tc.expectedError, &capacityErr) {
+					var gotErr *apperrors.InsufficientCapacityError
+					require.ErrorAs(t, err, &gotErr)
+					assert.Equal(t, capacityErr, gotErr)
+				} else {
+					assert.ErrorIs(t, err, tc.expectedError)
+				}
 			} else {
-				assert.NoError(t, err)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedDeactivatedCount, resp.DeactivatedUsersCount)
+				assert.Equal(t, tc.expectedReassignedCount, resp.ReassignedPrsCount)
+
+				if tc.expectedReassignments == nil {
+					assert.Nil(t, resp.Reassignments)
+				} else {
+					require.NotNil(t, resp.Reassignments)
+					assert.Equal(t, tc.expectedReassignments, *resp.Reassignments)
+				}
+
+				if tc.expectedWarnings == nil {
+					assert.Nil(t, resp.Warnings)
+				} else {
+					require.NotNil(t, resp.Warnings)
+					assert.Equal(t, tc.expectedWarnings, *resp.Warnings)
+				}
 			}
 
 			m.userRepo.AssertExpectations(t)
@@ -226,7 +455,40 @@ func TestUserServiceImpl_DeactivateTeam(t *testing.T) {
 			m.prQueryRepo.AssertExpectations(t)
 			m.prCmdRepo.AssertExpectations(t)
 			m.userPRRepo.AssertExpectations(t)
+			m.policyRepo.AssertExpectations(t)
+			m.historyRepo.AssertExpectations(t)
 			m.transactor.AssertExpectations(t)
 		})
 	}
 }
+
+func TestUserServiceImpl_SetRole(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	t.Run("Success: Role is stored", func(t *testing.T) {
+		userRepo := new(UserRepositoryMock)
+		userRepo.On("SetUserRole", ctx, "u1", domain.RoleLead).Return(nil).Once()
+
+		resp, err := NewUserService(userRepo, nil, nil, nil, nil, nil, nil, nil, logger).SetRole(ctx, "u1", "lead")
+
+		require.NoError(t, err)
+		assert.Equal(t, &api.SetUserRoleResponse{UserId: "u1", Role: api.UserRoleLead}, resp)
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure: Unknown role", func(t *testing.T) {
+		_, err := NewUserService(new(UserRepositoryMock), nil, nil, nil, nil, nil, nil, nil, logger).SetRole(ctx, "u1", "owner")
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+	})
+
+	t.Run("Failure: User not found", func(t *testing.T) {
+		userRepo := new(UserRepositoryMock)
+		userRepo.On("SetUserRole", ctx, "ghost", domain.RoleAdmin).Return(apperrors.ErrNotFound).Once()
+
+		_, err := NewUserService(userRepo, nil, nil, nil, nil, nil, nil, nil, logger).SetRole(ctx, "ghost", "admin")
+
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
@@ -0,0 +1,578 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/cursor"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/repository"
+	"github.com/YusovID/pr-reviewer-service/internal/signature"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// Headers of a webhook delivery besides those of its signature.
+const (
+	webhookEventHeader    = "X-Webhook-Event"
+	webhookDeliveryHeader = "X-Webhook-Delivery"
+)
+
+// Outcomes of the delivery attempts, counted by the webhook_delivery_attempts_total metric.
+const (
+	webhookAttemptDelivered = "delivered"
+	webhookAttemptRetried   = "retried"
+	webhookAttemptDead      = "dead"
+)
+
+// minWebhookSecretLength keeps the secrets of the webhooks long enough to resist guessing.
+const minWebhookSecretLength = 16
+
+// Defaults of the delivery policy, see WithWebhookDeliveryPolicy.
+const (
+	defaultWebhookLease          = time.Minute
+	defaultWebhookMaxAttempts    = 8
+	defaultWebhookRetryBaseDelay = 30 * time.Second
+	defaultWebhookRetryMaxDelay  = time.Hour
+)
+
+// WebhookClient sends the deliveries. *httpclient.Client satisfies it.
+type WebhookClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// WebhookService manages the outbound webhooks and delivers the PR events queued for them.
+// The events are queued by PullRequestServiceImpl in the transactions of the changes they report,
+// see WithWebhookEvents.
+type WebhookService interface {
+	// CreateWebhook registers url to receive the events, signed with secret.
+	// Returns apperrors.ErrValidation if the URL, the events or the secret are invalid.
+	CreateWebhook(ctx context.Context, input WebhookInput) (*api.Webhook, error)
+	// ListWebhooks returns every webhook ordered by ID.
+	ListWebhooks(ctx context.Context) (*api.ListWebhooksResponse, error)
+	GetWebhook(ctx context.Context, id int64) (*api.Webhook, error)
+	// UpdateWebhook replaces the URL and the events of a webhook, and its secret unless input has none.
+	// The deliveries queued already are sent to the new URL with the new secret.
+	UpdateWebhook(ctx context.Context, id int64, input WebhookInput) (*api.Webhook, error)
+	// DeleteWebhook removes a webhook together with its deliveries, including the pending ones.
+	DeleteWebhook(ctx context.Context, id int64) error
+	// ListWebhookDeliveries returns the deliveries of a webhook matching query, newest first.
+	ListWebhookDeliveries(ctx context.Context, query WebhookDeliveryQuery) (*api.ListWebhookDeliveriesResponse, error)
+	// RedeliverWebhookDelivery queues a dead delivery of the webhook again with a fresh set of attempts.
+	// Returns apperrors.ErrDeliveryNotDead if the delivery is pending or delivered.
+	RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID int64) (*api.WebhookDelivery, error)
+	// ClaimWebhookDeliveries claims at most limit due deliveries for an attempt. A claimed delivery whose
+	// attempt is not finished within the lease is claimed again, so a crashed worker delays it only.
+	ClaimWebhookDeliveries(ctx context.Context, limit int) ([]domain.WebhookDelivery, error)
+	// ProcessWebhookDelivery makes the attempt of a claimed delivery and records its outcome: a failed
+	// attempt is retried with exponential backoff until the attempts run out and the delivery is dead.
+	ProcessWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) error
+}
+
+// WebhookInput holds the fields of a created or updated webhook.
+type WebhookInput struct {
+	URL    string
+	Events []string
+	// Secret is required when a webhook is created; nil keeps the secret of an updated webhook.
+	Secret *string
+}
+
+// WebhookDeliveryQuery selects the deliveries returned by ListWebhookDeliveries. Zero values do not filter.
+type WebhookDeliveryQuery struct {
+	WebhookID int64
+	Status    string
+	// Cursor is the next_cursor of the previous page.
+	Cursor string
+	Limit  int
+}
+
+type WebhookServiceImpl struct {
+	BaseService
+	repo   repository.WebhookRepository
+	client WebhookClient
+	lease  time.Duration
+	// maxAttempts is the number of attempts after which a failing delivery is dead.
+	maxAttempts    int
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+}
+
+// WebhookServiceOption configures optional behaviour of WebhookServiceImpl.
+type WebhookServiceOption func(*WebhookServiceImpl)
+
+// WithWebhookClock makes the service take the current time from c instead of the system clock.
+func WithWebhookClock(c Clock) WebhookServiceOption {
+	return func(s *WebhookServiceImpl) {
+		s.clock = c
+	}
+}
+
+// WithWebhookDeliveryPolicy sets how long a claimed delivery stays with its worker, after how many
+// attempts a failing delivery is dead and the bounds of the exponential backoff between the attempts.
+func WithWebhookDeliveryPolicy(lease time.Duration, maxAttempts int, retryBaseDelay, retryMaxDelay time.Duration) WebhookServiceOption {
+	return func(s *WebhookServiceImpl) {
+		s.lease = lease
+		s.maxAttempts = maxAttempts
+		s.retryBaseDelay = retryBaseDelay
+		s.retryMaxDelay = retryMaxDelay
+	}
+}
+
+// NewWebhookService creates a new instance of WebhookServiceImpl that sends the deliveries with client.
+func NewWebhookService(repo repository.WebhookRepository, client WebhookClient, log *slog.Logger, opts ...WebhookServiceOption) *WebhookServiceImpl {
+	s := &WebhookServiceImpl{
+		BaseService:    NewBaseService(nil, log),
+		repo:           repo,
+		client:         client,
+		lease:          defaultWebhookLease,
+		maxAttempts:    defaultWebhookMaxAttempts,
+		retryBaseDelay: defaultWebhookRetryBaseDelay,
+		retryMaxDelay:  defaultWebhookRetryMaxDelay,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *WebhookServiceImpl) CreateWebhook(ctx context.Context, input WebhookInput) (*api.Webhook, error) {
+	const op = "internal.service.webhook.CreateWebhook"
+
+	if input.Secret == nil {
+		return nil, fmt.Errorf("%w: secret is required", apperrors.ErrValidation)
+	}
+
+	webhook, err := newWebhook(input)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.CreatedAt = s.now()
+
+	created, err := s.repo.CreateWebhook(ctx, webhook)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to create webhook: %w", op, err)
+	}
+
+	s.log.Info("webhook created", slog.String("op", op), slog.Int64("webhook_id", created.ID),
+		slog.String("url", created.URL), slog.Any("events", created.Events))
+
+	return toAPIWebhook(created), nil
+}
+
+func (s *WebhookServiceImpl) ListWebhooks(ctx context.Context) (*api.ListWebhooksResponse, error) {
+	const op = "internal.service.webhook.ListWebhooks"
+
+	webhooks, err := s.repo.ListWebhooks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list webhooks: %w", op, err)
+	}
+
+	items := make([]api.Webhook, len(webhooks))
+	for i := range webhooks {
+		items[i] = *toAPIWebhook(&webhooks[i])
+	}
+
+	return &api.ListWebhooksResponse{Items: items, TotalEstimate: totalOf(items)}, nil
+}
+
+func (s *WebhookServiceImpl) GetWebhook(ctx context.Context, id int64) (*api.Webhook, error) {
+	const op = "internal.service.webhook.GetWebhook"
+
+	webhook, err := s.repo.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get webhook: %w", op, err)
+	}
+
+	return toAPIWebhook(webhook), nil
+}
+
+func (s *WebhookServiceImpl) UpdateWebhook(ctx context.Context, id int64, input WebhookInput) (*api.Webhook, error) {
+	const op = "internal.service.webhook.UpdateWebhook"
+
+	webhook, err := newWebhook(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Secret == nil {
+		current, err := s.repo.GetWebhook(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to get webhook: %w", op, err)
+		}
+
+		webhook.Secret = current.Secret
+	}
+
+	webhook.ID = id
+	webhook.UpdatedAt = s.now()
+
+	updated, err := s.repo.UpdateWebhook(ctx, webhook)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to update webhook: %w", op, err)
+	}
+
+	s.log.Info("webhook updated", slog.String("op", op), slog.Int64("webhook_id", id),
+		slog.String("url", updated.URL), slog.Any("events", updated.Events), slog.Bool("secret_changed", input.Secret != nil))
+
+	return toAPIWebhook(updated), nil
+}
+
+func (s *WebhookServiceImpl) DeleteWebhook(ctx context.Context, id int64) error {
+	const op = "internal.service.webhook.DeleteWebhook"
+
+	if err := s.repo.DeleteWebhook(ctx, id); err != nil {
+		return fmt.Errorf("%s: failed to delete webhook: %w", op, err)
+	}
+
+	s.log.Info("webhook deleted", slog.String("op", op), slog.Int64("webhook_id", id))
+
+	return nil
+}
+
+func (s *WebhookServiceImpl) ListWebhookDeliveries(ctx context.Context, query WebhookDeliveryQuery) (*api.ListWebhookDeliveriesResponse, error) {
+	const op = "internal.service.webhook.ListWebhookDeliveries"
+
+	switch domain.WebhookDeliveryStatus(query.Status) {
+	case "", domain.WebhookDeliveryPending, domain.WebhookDeliveryDelivered, domain.WebhookDeliveryDead:
+	default:
+		return nil, fmt.Errorf("%w: unknown status '%s'", apperrors.ErrValidation, query.Status)
+	}
+
+	if query.Limit < 1 || query.Limit > maxListLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", apperrors.ErrValidation, maxListLimit)
+	}
+
+	filter := domain.WebhookDeliveryFilter{
+		WebhookID: query.WebhookID,
+		Status:    domain.WebhookDeliveryStatus(query.Status),
+		// One more delivery than requested tells whether there is a next page.
+		Limit: query.Limit + 1,
+	}
+
+	if query.Cursor != "" {
+		afterID, err := cursor.DecodeInt(query.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+		}
+
+		filter.AfterID = afterID
+	}
+
+	// An unknown webhook is reported as such rather than as one without deliveries.
+	if _, err := s.repo.GetWebhook(ctx, query.WebhookID); err != nil {
+		return nil, fmt.Errorf("%s: failed to get webhook: %w", op, err)
+	}
+
+	deliveries, err := s.repo.ListWebhookDeliveries(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list webhook deliveries: %w", op, err)
+	}
+
+	resp := &api.ListWebhookDeliveriesResponse{}
+
+	if len(deliveries) > query.Limit {
+		deliveries = deliveries[:query.Limit]
+		next := cursor.EncodeInt(deliveries[len(deliveries)-1].ID)
+		resp.NextCursor = &next
+	}
+
+	resp.Items = make([]api.WebhookDelivery, len(deliveries))
+	for i := range deliveries {
+		resp.Items[i] = *toAPIWebhookDelivery(&deliveries[i])
+	}
+
+	return resp, nil
+}
+
+func (s *WebhookServiceImpl) RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID int64) (*api.WebhookDelivery, error) {
+	const op = "internal.service.webhook.RedeliverWebhookDelivery"
+
+	delivery, err := s.repo.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get webhook delivery: %w", op, err)
+	}
+
+	if delivery.WebhookID != webhookID {
+		return nil, fmt.Errorf("%s: %w: webhook %d has no delivery %d", op, apperrors.ErrNotFound, webhookID, deliveryID)
+	}
+
+	delivery, err = s.repo.RequeueWebhookDelivery(ctx, deliveryID, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to requeue webhook delivery: %w", op, err)
+	}
+
+	s.log.Info("webhook delivery requeued", slog.String("op", op), slog.Int64("webhook_id", webhookID),
+		slog.Int64("delivery_id", deliveryID))
+
+	return toAPIWebhookDelivery(delivery), nil
+}
+
+func (s *WebhookServiceImpl) ClaimWebhookDeliveries(ctx context.Context, limit int) ([]domain.WebhookDelivery, error) {
+	const op = "internal.service.webhook.ClaimWebhookDeliveries"
+
+	now := s.now()
+
+	deliveries, err := s.repo.ClaimWebhookDeliveries(ctx, limit, now, now.Add(s.lease))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to claim webhook deliveries: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+func (s *WebhookServiceImpl) ProcessWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	const op = "internal.service.webhook.ProcessWebhookDelivery"
+	log := s.log.With(slog.String("op", op), slog.Int64("delivery_id", delivery.ID), slog.Int64("webhook_id", delivery.WebhookID),
+		slog.String("event", string(delivery.Event)), slog.Int("attempt", delivery.Attempts))
+
+	webhook, err := s.repo.GetWebhook(ctx, delivery.WebhookID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		// The webhook was deleted after the delivery was claimed, and its deliveries with it.
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: failed to get webhook: %w", op, err)
+	}
+
+	responseStatus, sendErr := s.send(ctx, webhook, &delivery)
+	if sendErr != nil && ctx.Err() != nil {
+		// An attempt cut short by a shutdown is not counted against the webhook; it is made again after the lease.
+		return fmt.Errorf("%s: attempt interrupted: %w", op, sendErr)
+	}
+
+	now := s.now()
+	delivery.UpdatedAt = now
+	outcome := webhookAttemptDelivered
+
+	switch {
+	case sendErr == nil:
+		delivery.Status = domain.WebhookDeliveryDelivered
+		delivery.LastError = nil
+		delivery.ResponseStatus = nil
+	case delivery.Attempts >= s.maxAttempts:
+		outcome = webhookAttemptDead
+		delivery.Status = domain.WebhookDeliveryDead
+	default:
+		outcome = webhookAttemptRetried
+		delivery.Status = domain.WebhookDeliveryPending
+		delivery.NextAttemptAt = now.Add(s.retryDelay(delivery.Attempts))
+	}
+
+	if sendErr != nil {
+		message := sendErr.Error()
+		delivery.LastError = &message
+		delivery.ResponseStatus = responseStatus
+
+		log.Warn("webhook delivery failed", slog.String("outcome", outcome), slog.Time("next_attempt_at", delivery.NextAttemptAt), sl.Err(sendErr))
+	}
+
+	webhookDeliveryAttemptsTotal.WithLabelValues(string(delivery.Event), outcome).Inc()
+
+	err = s.repo.FinishWebhookAttempt(ctx, &delivery)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: failed to record webhook delivery attempt: %w", op, err)
+	}
+
+	return nil
+}
+
+// send POSTs the payload of delivery to the webhook, signed with its secret. It returns the response status
+// of a rejected delivery along with the error. A fresh signature is made for every attempt, since the nonce
+// of a signature is accepted once; the delivery ID in the headers lets the receiver drop a duplicate.
+func (s *WebhookServiceImpl) send(ctx context.Context, webhook *domain.Webhook, delivery *domain.WebhookDelivery) (*int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, string(delivery.Event))
+	req.Header.Set(webhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+
+	if err := signature.NewSigner([]byte(webhook.Secret)).SignRequest(req, delivery.Payload); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		// The body is drained so that the connection can be reused.
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		status := resp.StatusCode
+		return &status, fmt.Errorf("webhook responded with status %d", status)
+	}
+
+	return nil, nil
+}
+
+// retryDelay is the backoff before the attempt following the given one: the base delay doubled
+// with every failed attempt, capped at the maximum delay.
+func (s *WebhookServiceImpl) retryDelay(attempts int) time.Duration {
+	delay := s.retryBaseDelay
+
+	for i := 1; i < attempts && delay < s.retryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, s.retryMaxDelay)
+}
+
+// newWebhook checks the URL and the events of input and returns the webhook they describe.
+// The events are deduplicated keeping their order.
+func newWebhook(input WebhookInput) (*domain.Webhook, error) {
+	u, err := url.Parse(input.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http or https URL", apperrors.ErrValidation)
+	}
+
+	if len(input.Events) == 0 {
+		return nil, fmt.Errorf("%w: at least one event is required", apperrors.ErrValidation)
+	}
+
+	webhook := &domain.Webhook{URL: input.URL}
+
+	for _, name := range input.Events {
+		event := domain.WebhookEventType(name)
+		if !event.IsValid() {
+			return nil, fmt.Errorf("%w: unknown event '%s'", apperrors.ErrValidation, name)
+		}
+
+		if !slices.Contains(webhook.Events, event) {
+			webhook.Events = append(webhook.Events, event)
+		}
+	}
+
+	if input.Secret != nil {
+		if len(*input.Secret) < minWebhookSecretLength {
+			return nil, fmt.Errorf("%w: secret must be at least %d characters long", apperrors.ErrValidation, minWebhookSecretLength)
+		}
+
+		webhook.Secret = *input.Secret
+	}
+
+	return webhook, nil
+}
+
+// queueEvent queues the delivery of an event to the webhooks subscribed to it and writes it to the outbox
+// of the event sinks. It runs in the transaction of the change the event reports, so that an event
+// is delivered if and only if the change is committed.
+func (s *PullRequestServiceImpl) queueEvent(ctx context.Context, payload api.WebhookPayload) error {
+	if s.webhooks == nil && s.outbox == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	eventType := domain.WebhookEventType(payload.Event)
+
+	if s.webhooks != nil {
+		event := &domain.WebhookEvent{
+			Type:          eventType,
+			PullRequestID: payload.PullRequest.PullRequestId,
+			Payload:       body,
+			OccurredAt:    payload.OccurredAt,
+		}
+
+		if _, err := s.webhooks.EnqueueWebhookDeliveries(ctx, event); err != nil {
+			return fmt.Errorf("failed to queue webhook deliveries: %w", err)
+		}
+	}
+
+	if s.outbox != nil {
+		event := &domain.OutboxEvent{
+			Type:          eventType,
+			PullRequestID: payload.PullRequest.PullRequestId,
+			Payload:       body,
+			OccurredAt:    payload.OccurredAt,
+		}
+
+		if err := s.outbox.AddOutboxEvent(ctx, event); err != nil {
+			return fmt.Errorf("failed to write outbox event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// newWebhookPayload describes an event of pr, whose reviewers are reviewerIDs, for the webhooks.
+func newWebhookPayload(event domain.WebhookEventType, pr *domain.PullRequest, reviewerIDs []string, at time.Time) api.WebhookPayload {
+	if reviewerIDs == nil {
+		reviewerIDs = []string{}
+	}
+
+	return api.WebhookPayload{
+		Event:      api.WebhookEvent(event),
+		OccurredAt: at,
+		PullRequest: api.WebhookPullRequest{
+			PullRequestId:     pr.ID,
+			PullRequestName:   pr.Name,
+			AuthorId:          pr.AuthorID,
+			Status:            api.WebhookPullRequestStatus(pr.Status),
+			Description:       pr.Description,
+			ExternalUrl:       pr.ExternalURL,
+			AssignedReviewers: reviewerIDs,
+			CreatedAt:         pr.CreatedAt,
+			MergedAt:          pr.MergedAt,
+		},
+	}
+}
+
+func toAPIWebhook(w *domain.Webhook) *api.Webhook {
+	events := make([]api.WebhookEvent, len(w.Events))
+	for i, event := range w.Events {
+		events[i] = api.WebhookEvent(event)
+	}
+
+	return &api.Webhook{
+		WebhookId: w.ID,
+		Url:       w.URL,
+		Events:    events,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+func toAPIWebhookDelivery(d *domain.WebhookDelivery) *api.WebhookDelivery {
+	return &api.WebhookDelivery{
+		DeliveryId:     d.ID,
+		WebhookId:      d.WebhookID,
+		Event:          api.WebhookEvent(d.Event),
+		PullRequestId:  d.PullRequestID,
+		Status:         api.WebhookDeliveryStatus(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastError:      d.LastError,
+		ResponseStatus: d.ResponseStatus,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
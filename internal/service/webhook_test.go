@@ -0,0 +1,410 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/signature"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookSecret = "0123456789abcdef"
+
+func TestWebhookServiceImpl_CreateWebhook(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	now := testNow.UTC()
+	secret := testWebhookSecret
+	shortSecret := "too-short"
+
+	t.Run("Events are deduplicated and the secret is not returned", func(t *testing.T) {
+		repo := new(WebhookRepositoryMock)
+		repo.On("CreateWebhook", ctx, &domain.Webhook{
+			URL:       "https://ci.example.com/hook",
+			Events:    []domain.WebhookEventType{domain.WebhookPRMerged, domain.WebhookPRCreated},
+			Secret:    secret,
+			CreatedAt: now,
+		}).Return(&domain.Webhook{
+			ID:        7,
+			URL:       "https://ci.example.com/hook",
+			Events:    []domain.WebhookEventType{domain.WebhookPRMerged, domain.WebhookPRCreated},
+			Secret:    secret,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}, nil).Once()
+
+		webhook, err := NewWebhookService(repo, nil, logger, WithWebhookClock(fixedClock(testNow))).CreateWebhook(ctx, WebhookInput{
+			URL:    "https://ci.example.com/hook",
+			Events: []string{"pr.merged", "pr.created", "pr.merged"},
+			Secret: &secret,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, &api.Webhook{
+			WebhookId: 7,
+			Url:       "https://ci.example.com/hook",
+			Events:    []api.WebhookEvent{api.WebhookPRMerged, api.WebhookPRCreated},
+			CreatedAt: now,
+			UpdatedAt: now,
+		}, webhook)
+		repo.AssertExpectations(t)
+	})
+
+	testCases := []struct {
+		name  string
+		input WebhookInput
+	}{
+		{name: "Missing secret", input: WebhookInput{URL: "https://ci.example.com", Events: []string{"pr.created"}}},
+		{name: "Short secret", input: WebhookInput{URL: "https://ci.example.com", Events: []string{"pr.created"}, Secret: &shortSecret}},
+		{name: "Relative URL", input: WebhookInput{URL: "/hook", Events: []string{"pr.created"}, Secret: &secret}},
+		{name: "Unsupported scheme", input: WebhookInput{URL: "ftp://ci.example.com", Events: []string{"pr.created"}, Secret: &secret}},
+		{name: "No events", input: WebhookInput{URL: "https://ci.example.com", Secret: &secret}},
+		{name: "Unknown event", input: WebhookInput{URL: "https://ci.example.com", Events: []string{"pr.closed"}, Secret: &secret}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(WebhookRepositoryMock)
+
+			_, err := NewWebhookService(repo, nil, logger).CreateWebhook(ctx, tc.input)
+
+			assert.ErrorIs(t, err, apperrors.ErrValidation)
+			repo.AssertNotCalled(t, "CreateWebhook", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestWebhookServiceImpl_UpdateWebhook_KeepsSecret(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	now := testNow.UTC()
+
+	repo := new(WebhookRepositoryMock)
+	repo.On("GetWebhook", ctx, int64(7)).Return(&domain.Webhook{ID: 7, Secret: testWebhookSecret}, nil).Once()
+	repo.On("UpdateWebhook", ctx, &domain.Webhook{
+		ID:        7,
+		URL:       "http://ci.internal/hook",
+		Events:    []domain.WebhookEventType{domain.WebhookReviewerReassigned},
+		Secret:    testWebhookSecret,
+		UpdatedAt: now,
+	}).Return(&domain.Webhook{ID: 7, URL: "http://ci.internal/hook", Secret: testWebhookSecret}, nil).Once()
+
+	_, err := NewWebhookService(repo, nil, logger, WithWebhookClock(fixedClock(testNow))).UpdateWebhook(ctx, 7, WebhookInput{
+		URL:    "http://ci.internal/hook",
+		Events: []string{"reviewer.reassigned"},
+	})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookServiceImpl_ListWebhookDeliveries(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	t.Run("Pages are linked by the cursor", func(t *testing.T) {
+		repo := new(WebhookRepositoryMock)
+		repo.On("GetWebhook", ctx, int64(7)).Return(&domain.Webhook{ID: 7}, nil).Twice()
+		repo.On("ListWebhookDeliveries", ctx, domain.WebhookDeliveryFilter{WebhookID: 7, Status: domain.WebhookDeliveryDead, Limit: 3}).
+			Return([]domain.WebhookDelivery{{ID: 9}, {ID: 8}, {ID: 5}}, nil).Once()
+		repo.On("ListWebhookDeliveries", ctx, domain.WebhookDeliveryFilter{WebhookID: 7, Status: domain.WebhookDeliveryDead, AfterID: 8, Limit: 3}).
+			Return([]domain.WebhookDelivery{{ID: 5}}, nil).Once()
+
+		s := NewWebhookService(repo, nil, logger)
+
+		first, err := s.ListWebhookDeliveries(ctx, WebhookDeliveryQuery{WebhookID: 7, Status: "dead", Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, first.Items, 2)
+		require.NotNil(t, first.NextCursor)
+
+		second, err := s.ListWebhookDeliveries(ctx, WebhookDeliveryQuery{WebhookID: 7, Status: "dead", Limit: 2, Cursor: *first.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, second.Items, 1)
+		assert.Equal(t, int64(5), second.Items[0].DeliveryId)
+		assert.Nil(t, second.NextCursor)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown webhook", func(t *testing.T) {
+		repo := new(WebhookRepositoryMock)
+		repo.On("GetWebhook", ctx, int64(7)).Return(nil, apperrors.ErrNotFound).Once()
+
+		_, err := NewWebhookService(repo, nil, logger).ListWebhookDeliveries(ctx, WebhookDeliveryQuery{WebhookID: 7, Limit: 20})
+
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("Unknown status", func(t *testing.T) {
+		_, err := NewWebhookService(new(WebhookRepositoryMock), nil, logger).
+			ListWebhookDeliveries(ctx, WebhookDeliveryQuery{WebhookID: 7, Status: "failed", Limit: 20})
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+	})
+
+	t.Run("Malformed cursor", func(t *testing.T) {
+		_, err := NewWebhookService(new(WebhookRepositoryMock), nil, logger).
+			ListWebhookDeliveries(ctx, WebhookDeliveryQuery{WebhookID: 7, Limit: 20, Cursor: "!"})
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+	})
+}
+
+func TestWebhookServiceImpl_RedeliverWebhookDelivery(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	now := testNow.UTC()
+
+	t.Run("Dead delivery is queued again", func(t *testing.T) {
+		repo := new(WebhookRepositoryMock)
+		repo.On("GetWebhookDelivery", ctx, int64(3)).Return(&domain.WebhookDelivery{ID: 3, WebhookID: 7, Status: domain.WebhookDeliveryDead}, nil).Once()
+		repo.On("RequeueWebhookDelivery", ctx, int64(3), now).
+			Return(&domain.WebhookDelivery{ID: 3, WebhookID: 7, Status: domain.WebhookDeliveryPending, NextAttemptAt: now}, nil).Once()
+
+		delivery, err := NewWebhookService(repo, nil, logger, WithWebhookClock(fixedClock(testNow))).RedeliverWebhookDelivery(ctx, 7, 3)
+
+		require.NoError(t, err)
+		assert.Equal(t, api.WebhookDeliveryPending, delivery.Status)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Delivery of another webhook", func(t *testing.T) {
+		repo := new(WebhookRepositoryMock)
+		repo.On("GetWebhookDelivery", ctx, int64(3)).Return(&domain.WebhookDelivery{ID: 3, WebhookID: 8, Status: domain.WebhookDeliveryDead}, nil).Once()
+
+		_, err := NewWebhookService(repo, nil, logger).RedeliverWebhookDelivery(ctx, 7, 3)
+
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+		repo.AssertNotCalled(t, "RequeueWebhookDelivery", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Delivery that is not dead", func(t *testing.T) {
+		repo := new(WebhookRepositoryMock)
+		repo.On("GetWebhookDelivery", ctx, int64(3)).Return(&domain.WebhookDelivery{ID: 3, WebhookID: 7, Status: domain.WebhookDeliveryDelivered}, nil).Once()
+		repo.On("RequeueWebhookDelivery", ctx, int64(3), mock.Anything).Return(nil, apperrors.ErrDeliveryNotDead).Once()
+
+		_, err := NewWebhookService(repo, nil, logger).RedeliverWebhookDelivery(ctx, 7, 3)
+
+		assert.ErrorIs(t, err, apperrors.ErrDeliveryNotDead)
+	})
+}
+
+func TestWebhookServiceImpl_ProcessWebhookDelivery(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	now := testNow.UTC()
+	payload := []byte(`{"event":"pr.created"}`)
+
+	testCases := []struct {
+		name           string
+		responseStatus int
+		attempts       int
+		expected       func(d domain.WebhookDelivery) domain.WebhookDelivery
+	}{
+		{
+			name:           "Accepted delivery is delivered",
+			responseStatus: http.StatusNoContent,
+			attempts:       2,
+			expected: func(d domain.WebhookDelivery) domain.WebhookDelivery {
+				d.Status = domain.WebhookDeliveryDelivered
+				d.LastError = nil
+
+				return d
+			},
+		},
+		{
+			name:           "Rejected delivery is retried with backoff",
+			responseStatus: http.StatusInternalServerError,
+			attempts:       3,
+			expected: func(d domain.WebhookDelivery) domain.WebhookDelivery {
+				message := "webhook responded with status 500"
+				status := http.StatusInternalServerError
+				d.NextAttemptAt = now.Add(4 * time.Second)
+				d.LastError = &message
+				d.ResponseStatus = &status
+
+				return d
+			},
+		},
+		{
+			name:           "Delivery out of attempts is dead",
+			responseStatus: http.StatusGone,
+			attempts:       5,
+			expected: func(d domain.WebhookDelivery) domain.WebhookDelivery {
+				message := "webhook responded with status 410"
+				status := http.StatusGone
+				d.Status = domain.WebhookDeliveryDead
+				d.LastError = &message
+				d.ResponseStatus = &status
+
+				return d
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			verifier := signature.NewVerifier([]byte(testWebhookSecret))
+			received := make(chan *http.Request, 1)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := verifier.VerifyRequest(r)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(payload), string(body))
+
+				received <- r
+				w.WriteHeader(tc.responseStatus)
+			}))
+			defer server.Close()
+
+			lastError := "connection refused"
+			delivery := domain.WebhookDelivery{
+				ID:            11,
+				WebhookID:     7,
+				Event:         domain.WebhookPRCreated,
+				PullRequestID: "pr-1",
+				Payload:       payload,
+				Status:        domain.WebhookDeliveryPending,
+				Attempts:      tc.attempts,
+				NextAttemptAt: now.Add(time.Minute),
+				LastError:     &lastError,
+			}
+
+			finished := tc.expected(delivery)
+			finished.UpdatedAt = now
+
+			repo := new(WebhookRepositoryMock)
+			repo.On("GetWebhook", ctx, int64(7)).Return(&domain.Webhook{ID: 7, URL: server.URL, Secret: testWebhookSecret}, nil).Once()
+			repo.On("FinishWebhookAttempt", ctx, &finished).Return(nil).Once()
+
+			s := NewWebhookService(repo, server.Client(), logger, WithWebhookClock(fixedClock(testNow)),
+				WithWebhookDeliveryPolicy(time.Minute, 5, time.Second, 30*time.Second))
+
+			require.NoError(t, s.ProcessWebhookDelivery(ctx, delivery))
+
+			r := <-received
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "pr.created", r.Header.Get("X-Webhook-Event"))
+			assert.Equal(t, "11", r.Header.Get("X-Webhook-Delivery"))
+			repo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Delivery of a deleted webhook is dropped", func(t *testing.T) {
+		repo := new(WebhookRepositoryMock)
+		repo.On("GetWebhook", ctx, int64(7)).Return(nil, apperrors.ErrNotFound).Once()
+
+		err := NewWebhookService(repo, nil, logger).ProcessWebhookDelivery(ctx, domain.WebhookDelivery{ID: 11, WebhookID: 7})
+
+		require.NoError(t, err)
+		repo.AssertNotCalled(t, "FinishWebhookAttempt", mock.Anything, mock.Anything)
+	})
+}
+
+func TestWebhookServiceImpl_retryDelay(t *testing.T) {
+	s := NewWebhookService(nil, nil, slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+		WithWebhookDeliveryPolicy(time.Minute, 8, 30*time.Second, time.Hour))
+
+	assert.Equal(t, 30*time.Second, s.retryDelay(1))
+	assert.Equal(t, time.Minute, s.retryDelay(2))
+	assert.Equal(t, 16*time.Minute, s.retryDelay(6))
+	assert.Equal(t, 32*time.Minute, s.retryDelay(7))
+	assert.Equal(t, time.Hour, s.retryDelay(8))
+	assert.Equal(t, time.Hour, s.retryDelay(100))
+}
+
+func TestPullRequestServiceImpl_MergePR_QueuesWebhookEvent(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	prID := "pr-to-merge"
+
+	t.Run("Merging an OPEN PR queues pr.merged in the transaction", func(t *testing.T) {
+		transactorMock := new(TransactorMock)
+		prCmdMock := new(PRCommandRepositoryMock)
+		prQueryMock := new(PRQueryRepositoryMock)
+		webhooksMock := new(WebhookRepositoryMock)
+
+		_, mockedTx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+
+		transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+		prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).
+			Return(&domain.PullRequest{ID: prID, Name: "Add feature", AuthorID: "u1", Status: api.PullRequestStatusOPEN}, nil).Once()
+		prCmdMock.On("UpdatePRStatus", inTx(mockedTx), prID, api.PullRequestStatusMERGED, testNow.UTC()).Return(nil, nil).Once()
+		prQueryMock.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{"rev1"}, nil).Once()
+		prQueryMock.On("GetStatsByUserIDs", inTx(mockedTx), []string{"rev1"}).Return([]domain.Stats{}, nil).Once()
+		webhooksMock.On("EnqueueWebhookDeliveries", inTx(mockedTx), mock.MatchedBy(func(event *domain.WebhookEvent) bool {
+			var payload api.WebhookPayload
+			if err := json.Unmarshal(event.Payload, &payload); err != nil {
+				return false
+			}
+
+			return event.Type == domain.WebhookPRMerged && event.PullRequestID == prID && event.OccurredAt.Equal(testNow) &&
+				payload.PullRequest.Status == api.WebhookPullRequestStatusMERGED && payload.PullRequest.MergedAt != nil &&
+				assert.ObjectsAreEqual([]string{"rev1"}, payload.PullRequest.AssignedReviewers)
+		})).Return(1, nil).Once()
+
+		service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil, nil, nil,
+			WithWebhookEvents(webhooksMock), WithClock(fixedClock(testNow)))
+		_, err := service.MergePR(ctx, prID, MergeOptions{})
+
+		require.NoError(t, err)
+		webhooksMock.AssertExpectations(t)
+	})
+
+	t.Run("Failure to queue rolls the merge back", func(t *testing.T) {
+		transactorMock := new(TransactorMock)
+		prCmdMock := new(PRCommandRepositoryMock)
+		prQueryMock := new(PRQueryRepositoryMock)
+		webhooksMock := new(WebhookRepositoryMock)
+		errQueue := errors.New("db error")
+
+		_, mockedTx, smock := newMockDBAndTx(t)
+		smock.ExpectRollback()
+
+		transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+		prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(&domain.PullRequest{ID: prID, Status: api.PullRequestStatusOPEN}, nil).Once()
+		prCmdMock.On("UpdatePRStatus", inTx(mockedTx), prID, api.PullRequestStatusMERGED, mock.Anything).Return(nil, nil).Once()
+		prQueryMock.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{}, nil).Once()
+		prQueryMock.On("GetStatsByUserIDs", inTx(mockedTx), []string{}).Return([]domain.Stats{}, nil).Once()
+		webhooksMock.On("EnqueueWebhookDeliveries", inTx(mockedTx), mock.Anything).Return(0, errQueue).Once()
+
+		service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil, nil, nil, WithWebhookEvents(webhooksMock))
+		_, err := service.MergePR(ctx, prID, MergeOptions{})
+
+		assert.ErrorIs(t, err, errQueue)
+	})
+
+	t.Run("Repeated merge queues nothing", func(t *testing.T) {
+		transactorMock := new(TransactorMock)
+		prCmdMock := new(PRCommandRepositoryMock)
+		prQueryMock := new(PRQueryRepositoryMock)
+		webhooksMock := new(WebhookRepositoryMock)
+
+		_, mockedTx, smock := newMockDBAndTx(t)
+		smock.ExpectCommit()
+
+		transactorMock.On("BeginTxx", mock.Anything, (*sql.TxOptions)(nil)).Return(mockedTx, nil).Once()
+		prCmdMock.On("GetPRByIDWithLock", inTx(mockedTx), prID).Return(&domain.PullRequest{ID: prID, Status: api.PullRequestStatusMERGED}, nil).Once()
+		prQueryMock.On("GetReviewerIDs", inTx(mockedTx), prID).Return([]string{"rev1"}, nil).Once()
+		prQueryMock.On("GetStatsByUserIDs", inTx(mockedTx), []string{"rev1"}).Return([]domain.Stats{}, nil).Once()
+
+		service := NewPullRequestService(transactorMock, logger, prCmdMock, prQueryMock, nil, nil, nil, WithWebhookEvents(webhooksMock))
+		_, err := service.MergePR(ctx, prID, MergeOptions{})
+
+		require.NoError(t, err)
+		webhooksMock.AssertNotCalled(t, "EnqueueWebhookDeliveries", mock.Anything, mock.Anything)
+	})
+}
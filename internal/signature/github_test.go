@@ -0,0 +1,141 @@
+package signature
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDelivery = "72d3162e-cc78-11e3-81ab-4c9367dc0958"
+
+func gitHubHeader(secret []byte, delivery string, body []byte) http.Header {
+	header := http.Header{}
+	header.Set(GitHubSignatureHeader, SignGitHub(secret, body))
+	header.Set(GitHubDeliveryHeader, delivery)
+
+	return header
+}
+
+func TestSignGitHub(t *testing.T) {
+	// Reference value: printf '{"zen":"Design for failure."}' | openssl dgst -sha256 -hmac current-secret
+	signature := SignGitHub(testSecret, []byte(`{"zen":"Design for failure."}`))
+	assert.Equal(t, "sha256=c03f7c47d40f725e837a7da3033aed4b269791052996c906c40fb492ff3bd2cc", signature)
+}
+
+func TestVerifier_VerifyGitHub(t *testing.T) {
+	body := []byte(`{"action":"opened"}`)
+
+	testCases := []struct {
+		name        string
+		header      func() http.Header
+		body        []byte
+		opts        []VerifierOption
+		expectedErr error
+	}{
+		{
+			name:   "Valid signature",
+			header: func() http.Header { return gitHubHeader(testSecret, testDelivery, body) },
+		},
+		{
+			name:   "Previous secret during rotation",
+			header: func() http.Header { return gitHubHeader([]byte("previous-secret"), testDelivery, body) },
+			opts:   []VerifierOption{WithPreviousSecret([]byte("previous-secret"))},
+		},
+		{
+			name:        "Unknown secret",
+			header:      func() http.Header { return gitHubHeader([]byte("guessed-secret"), testDelivery, body) },
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name:        "Tampered body",
+			header:      func() http.Header { return gitHubHeader(testSecret, testDelivery, body) },
+			body:        []byte(`{"action":"closed"}`),
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name: "Missing delivery",
+			header: func() http.Header {
+				header := gitHubHeader(testSecret, testDelivery, body)
+				header.Del(GitHubDeliveryHeader)
+
+				return header
+			},
+			expectedErr: ErrMissingSignature,
+		},
+		{
+			name: "SHA-1 signature only",
+			header: func() http.Header {
+				header := gitHubHeader(testSecret, testDelivery, body)
+				header.Set(GitHubSignatureHeader, "sha1="+strings.Repeat("00", 20))
+
+				return header
+			},
+			expectedErr: ErrMalformedSignature,
+		},
+		{
+			name: "Truncated signature",
+			header: func() http.Header {
+				header := gitHubHeader(testSecret, testDelivery, body)
+				header.Set(GitHubSignatureHeader, header.Get(GitHubSignatureHeader)[:20])
+
+				return header
+			},
+			expectedErr: ErrMalformedSignature,
+		},
+		{
+			name:        "Delivery with a separator",
+			header:      func() http.Header { return gitHubHeader(testSecret, "72d3162e.cc78.11e3.81ab", body) },
+			expectedErr: ErrMalformedSignature,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqBody := body
+			if tc.body != nil {
+				reqBody = tc.body
+			}
+
+			err := newTestVerifier(tc.opts...).VerifyGitHub(tc.header(), reqBody)
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestVerifier_VerifyGitHubRejectsReplays(t *testing.T) {
+	body := []byte(`{"action":"opened"}`)
+	verifier := newTestVerifier()
+
+	require.NoError(t, verifier.VerifyGitHub(gitHubHeader(testSecret, testDelivery, body), body))
+	assert.ErrorIs(t, verifier.VerifyGitHub(gitHubHeader(testSecret, testDelivery, body), body), ErrReplayed)
+
+	// A redelivery made once the tolerance has passed is accepted.
+	verifier.now = func() time.Time { return testNow.Add(DefaultTolerance) }
+	assert.NoError(t, verifier.VerifyGitHub(gitHubHeader(testSecret, testDelivery, body), body))
+}
+
+func TestVerifier_VerifyGitHubRequest(t *testing.T) {
+	body := `{"action":"opened"}`
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(body))
+	req.Header = gitHubHeader(testSecret, testDelivery, []byte(body))
+
+	verified, err := newTestVerifier().VerifyGitHubRequest(req)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(verified))
+
+	req = httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(body))
+	req.Header = gitHubHeader(testSecret, testDelivery, []byte(body))
+
+	_, err = newTestVerifier(WithMaxBodyBytes(8)).VerifyGitHubRequest(req)
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+}
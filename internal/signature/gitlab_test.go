@@ -0,0 +1,80 @@
+package signature
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gitLabHeader(token string) http.Header {
+	header := http.Header{}
+	header.Set(GitLabTokenHeader, token)
+
+	return header
+}
+
+func TestVerifier_VerifyGitLab(t *testing.T) {
+	testCases := []struct {
+		name        string
+		header      http.Header
+		opts        []VerifierOption
+		expectedErr error
+	}{
+		{
+			name:   "Valid token",
+			header: gitLabHeader(string(testSecret)),
+		},
+		{
+			name:   "Previous token during rotation",
+			header: gitLabHeader("previous-secret"),
+			opts:   []VerifierOption{WithPreviousSecret([]byte("previous-secret"))},
+		},
+		{
+			name:        "Unknown token",
+			header:      gitLabHeader("guessed-secret"),
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name:        "Token prefix",
+			header:      gitLabHeader(string(testSecret[:4])),
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name:        "Missing token",
+			header:      http.Header{},
+			expectedErr: ErrMissingSignature,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := newTestVerifier(tc.opts...).VerifyGitLab(tc.header)
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestVerifier_VerifyGitLabRequest(t *testing.T) {
+	body := `{"object_kind":"merge_request"}`
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/gitlab", strings.NewReader(body))
+	req.Header = gitLabHeader(string(testSecret))
+
+	verified, err := newTestVerifier().VerifyGitLabRequest(req)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(verified))
+
+	req = httptest.NewRequest(http.MethodPost, "/webhooks/gitlab", strings.NewReader(body))
+	req.Header = gitLabHeader(string(testSecret))
+
+	_, err = newTestVerifier(WithMaxBodyBytes(8)).VerifyGitLabRequest(req)
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+}
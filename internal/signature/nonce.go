@@ -0,0 +1,87 @@
+package signature
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNonceCacheFull is returned by MemoryNonceCache when every remembered nonce is still valid.
+// Forgetting one would let its request be replayed, so the new request is rejected instead.
+var ErrNonceCacheFull = errors.New("nonce cache is full")
+
+// NonceCache remembers the nonces of accepted requests.
+type NonceCache interface {
+	// Add remembers nonce until expiresAt. It returns ErrReplayed if nonce is already remembered at now.
+	Add(nonce string, now, expiresAt time.Time) error
+}
+
+// MemoryNonceCache is a NonceCache of a single instance holding at most a fixed number of nonces.
+// It is safe for concurrent use.
+type MemoryNonceCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	expires map[string]time.Time
+	// order lists the nonces in the order they were added, so that the oldest ones are dropped first.
+	order []string
+}
+
+// NewMemoryNonceCache creates a cache holding at most maxEntries nonces.
+func NewMemoryNonceCache(maxEntries int) *MemoryNonceCache {
+	return &MemoryNonceCache{
+		maxEntries: maxEntries,
+		expires:    make(map[string]time.Time),
+	}
+}
+
+func (c *MemoryNonceCache) Add(nonce string, now, expiresAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if expiry, ok := c.expires[nonce]; ok && now.Before(expiry) {
+		return ErrReplayed
+	}
+
+	if len(c.expires) >= c.maxEntries {
+		c.dropExpired(now)
+	}
+
+	if len(c.expires) >= c.maxEntries {
+		return ErrNonceCacheFull
+	}
+
+	if _, ok := c.expires[nonce]; !ok {
+		c.order = append(c.order, nonce)
+	}
+
+	c.expires[nonce] = expiresAt
+
+	return nil
+}
+
+// dropExpired forgets the nonces that have expired at now.
+func (c *MemoryNonceCache) dropExpired(now time.Time) {
+	kept := c.order[:0]
+
+	for _, nonce := range c.order {
+		if now.Before(c.expires[nonce]) {
+			kept = append(kept, nonce)
+			continue
+		}
+
+		delete(c.expires, nonce)
+	}
+
+	// The dropped tail is cleared so that the backing array does not keep the strings alive.
+	clear(c.order[len(kept):])
+	c.order = kept
+}
+
+// Len returns the number of remembered nonces, including the expired ones not dropped yet.
+func (c *MemoryNonceCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.expires)
+}
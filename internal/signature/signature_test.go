@@ -0,0 +1,343 @@
+package signature
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testSecret = []byte("current-secret")
+	testNow    = time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+)
+
+const testNonce = "0123456789abcdef0123456789abcdef"
+
+func newTestVerifier(opts ...VerifierOption) *Verifier {
+	v := NewVerifier(testSecret, opts...)
+	v.now = func() time.Time { return testNow }
+
+	return v
+}
+
+// signedHeader returns the headers of body signed with secret at timestamp.
+func signedHeader(secret []byte, timestamp time.Time, nonce string, body []byte) http.Header {
+	header := http.Header{}
+	header.Set(SignatureHeader, Sign(secret, timestamp, nonce, body))
+	header.Set(TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	header.Set(NonceHeader, nonce)
+
+	return header
+}
+
+func TestSign(t *testing.T) {
+	// Reference value: printf '1741953600.0123456789abcdef0123456789abcdef.{"event":"opened"}' | openssl dgst -sha256 -hmac current-secret
+	signature := Sign(testSecret, testNow, testNonce, []byte(`{"event":"opened"}`))
+	assert.Equal(t, "v1=8e3c3f66e6a6189cab1f7f5091a0ba136838f3ffddbfdebe901679ea4e61fcf3", signature)
+}
+
+func TestSigner_SignRequest(t *testing.T) {
+	signer := NewSigner(testSecret)
+	signer.now = func() time.Time { return testNow }
+
+	body := []byte(`{"event":"merged"}`)
+	first, err := http.NewRequest(http.MethodPost, "http://example.com/hook", nil)
+	require.NoError(t, err)
+	second, err := http.NewRequest(http.MethodPost, "http://example.com/hook", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, signer.SignRequest(first, body))
+	require.NoError(t, signer.SignRequest(second, body))
+
+	assert.Equal(t, "1741953600", first.Header.Get(TimestampHeader))
+	assert.Len(t, first.Header.Get(NonceHeader), 32)
+	assert.NotEqual(t, first.Header.Get(NonceHeader), second.Header.Get(NonceHeader), "every request gets its own nonce")
+
+	verifier := newTestVerifier()
+	assert.NoError(t, verifier.Verify(first.Header, body))
+	assert.NoError(t, verifier.Verify(second.Header, body))
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	body := []byte(`{"event":"opened"}`)
+	valid := Sign(testSecret, testNow, testNonce, body)
+
+	testCases := []struct {
+		name        string
+		header      func() http.Header
+		body        []byte
+		opts        []VerifierOption
+		expectedErr error
+	}{
+		{
+			name:   "Valid signature",
+			header: func() http.Header { return signedHeader(testSecret, testNow, testNonce, body) },
+		},
+		{
+			name: "Timestamp within tolerance",
+			header: func() http.Header {
+				return signedHeader(testSecret, testNow.Add(-DefaultTolerance), testNonce, body)
+			},
+		},
+		{
+			name:   "Previous secret during rotation",
+			header: func() http.Header { return signedHeader([]byte("previous-secret"), testNow, testNonce, body) },
+			opts:   []VerifierOption{WithPreviousSecret([]byte("previous-secret"))},
+		},
+		{
+			name: "Matching signature among several",
+			header: func() http.Header {
+				header := signedHeader(testSecret, testNow, testNonce, body)
+				header.Set(SignatureHeader, "v0=legacy, v1="+strings.Repeat("00", macSize)+", "+valid)
+
+				return header
+			},
+		},
+		{
+			name:        "Unknown secret",
+			header:      func() http.Header { return signedHeader([]byte("guessed-secret"), testNow, testNonce, body) },
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name:        "Tampered body",
+			header:      func() http.Header { return signedHeader(testSecret, testNow, testNonce, body) },
+			body:        []byte(`{"event":"merged"}`),
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name: "Replaced timestamp",
+			header: func() http.Header {
+				header := signedHeader(testSecret, testNow, testNonce, body)
+				header.Set(TimestampHeader, strconv.FormatInt(testNow.Unix()+1, 10))
+
+				return header
+			},
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name: "Replaced nonce",
+			header: func() http.Header {
+				header := signedHeader(testSecret, testNow, testNonce, body)
+				header.Set(NonceHeader, "fedcba9876543210fedcba9876543210")
+
+				return header
+			},
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name: "Missing signature",
+			header: func() http.Header {
+				header := signedHeader(testSecret, testNow, testNonce, body)
+				header.Del(SignatureHeader)
+
+				return header
+			},
+			expectedErr: ErrMissingSignature,
+		},
+		{
+			name: "Missing nonce",
+			header: func() http.Header {
+				header := signedHeader(testSecret, testNow, testNonce, body)
+				header.Del(NonceHeader)
+
+				return header
+			},
+			expectedErr: ErrMissingSignature,
+		},
+		{
+			name: "Expired timestamp",
+			header: func() http.Header {
+				return signedHeader(testSecret, testNow.Add(-DefaultTolerance-time.Second), testNonce, body)
+			},
+			expectedErr: ErrTimestampOutOfRange,
+		},
+		{
+			name: "Timestamp in the future",
+			header: func() http.Header {
+				return signedHeader(testSecret, testNow.Add(time.Hour), testNonce, body)
+			},
+			expectedErr: ErrTimestampOutOfRange,
+		},
+		{
+			name: "Custom tolerance",
+			header: func() http.Header {
+				return signedHeader(testSecret, testNow.Add(-time.Minute), testNonce, body)
+			},
+			opts:        []VerifierOption{WithTolerance(30 * time.Second)},
+			expectedErr: ErrTimestampOutOfRange,
+		},
+		{
+			name: "Timestamp with a sign",
+			header: func() http.Header {
+				header := signedHeader(testSecret, testNow, testNonce, body)
+				header.Set(TimestampHeader, "+"+header.Get(TimestampHeader))
+
+				return header
+			},
+			expectedErr: ErrMalformedSignature,
+		},
+		{
+			name: "Short nonce",
+			header: func() http.Header {
+				return signedHeader(testSecret, testNow, "abc", body)
+			},
+			expectedErr: ErrMalformedSignature,
+		},
+		{
+			name: "Nonce with a separator",
+			header: func() http.Header {
+				return signedHeader(testSecret, testNow, "0123456789abcdef.0123456789", body)
+			},
+			expectedErr: ErrMalformedSignature,
+		},
+		{
+			name: "Truncated signature",
+			header: func() http.Header {
+				header := signedHeader(testSecret, testNow, testNonce, body)
+				header.Set(SignatureHeader, valid[:len(valid)-2])
+
+				return header
+			},
+			expectedErr: ErrMalformedSignature,
+		},
+		{
+			name: "No signature of a known scheme",
+			header: func() http.Header {
+				header := signedHeader(testSecret, testNow, testNonce, body)
+				header.Set(SignatureHeader, "v2=abcdef")
+
+				return header
+			},
+			expectedErr: ErrMalformedSignature,
+		},
+		{
+			name: "Too many signatures",
+			header: func() http.Header {
+				header := signedHeader(testSecret, testNow, testNonce, body)
+				guesses := strings.Repeat("v1="+strings.Repeat("00", macSize)+",", maxSignatures)
+				header.Set(SignatureHeader, guesses+valid)
+
+				return header
+			},
+			expectedErr: ErrMalformedSignature,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqBody := body
+			if tc.body != nil {
+				reqBody = tc.body
+			}
+
+			err := newTestVerifier(tc.opts...).Verify(tc.header(), reqBody)
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestVerifier_RejectsReplays(t *testing.T) {
+	body := []byte(`{"event":"opened"}`)
+	verifier := newTestVerifier()
+
+	require.NoError(t, verifier.Verify(signedHeader(testSecret, testNow, testNonce, body), body))
+	assert.ErrorIs(t, verifier.Verify(signedHeader(testSecret, testNow, testNonce, body), body), ErrReplayed)
+
+	otherNonce := "fedcba9876543210fedcba9876543210"
+	assert.NoError(t, verifier.Verify(signedHeader(testSecret, testNow, otherNonce, body), body))
+
+	// Once the tolerance has passed, the replay is rejected by its timestamp instead.
+	verifier.now = func() time.Time { return testNow.Add(DefaultTolerance + time.Second) }
+	assert.ErrorIs(t, verifier.Verify(signedHeader(testSecret, testNow, testNonce, body), body), ErrTimestampOutOfRange)
+}
+
+func TestVerifier_InvalidRequestsAreNotRemembered(t *testing.T) {
+	body := []byte(`{"event":"opened"}`)
+	cache := NewMemoryNonceCache(10)
+	verifier := newTestVerifier(WithNonceCache(cache))
+
+	for i := range 20 {
+		nonce := fmt.Sprintf("%032d", i)
+		assert.ErrorIs(t, verifier.Verify(signedHeader([]byte("guessed-secret"), testNow, nonce, body), body), ErrInvalidSignature)
+	}
+
+	assert.Zero(t, cache.Len(), "unsigned requests cannot fill the nonce cache")
+	assert.NoError(t, verifier.Verify(signedHeader(testSecret, testNow, testNonce, body), body))
+}
+
+func TestVerifier_ConcurrentReplays(t *testing.T) {
+	body := []byte(`{"event":"opened"}`)
+	verifier := newTestVerifier()
+
+	var accepted atomic.Int32
+	var wg sync.WaitGroup
+
+	for range 50 {
+		wg.Go(func() {
+			if verifier.Verify(signedHeader(testSecret, testNow, testNonce, body), body) == nil {
+				accepted.Add(1)
+			}
+		})
+	}
+
+	wg.Wait()
+	assert.Equal(t, int32(1), accepted.Load(), "a nonce is accepted exactly once")
+}
+
+func TestVerifier_VerifyRequest(t *testing.T) {
+	body := `{"event":"opened"}`
+
+	t.Run("Body is kept for the handler", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(body))
+		req.Header = signedHeader(testSecret, testNow, testNonce, []byte(body))
+
+		verified, err := newTestVerifier().VerifyRequest(req)
+		require.NoError(t, err)
+		assert.Equal(t, body, string(verified))
+
+		again, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, body, string(again))
+	})
+
+	t.Run("Body over the limit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(body))
+		req.Header = signedHeader(testSecret, testNow, testNonce, []byte(body))
+
+		_, err := newTestVerifier(WithMaxBodyBytes(8)).VerifyRequest(req)
+		assert.ErrorIs(t, err, ErrBodyTooLarge)
+	})
+}
+
+func TestMemoryNonceCache(t *testing.T) {
+	cache := NewMemoryNonceCache(2)
+
+	require.NoError(t, cache.Add("a", testNow, testNow.Add(time.Minute)))
+	require.NoError(t, cache.Add("b", testNow, testNow.Add(2*time.Minute)))
+	assert.ErrorIs(t, cache.Add("a", testNow.Add(30*time.Second), testNow.Add(time.Hour)), ErrReplayed)
+
+	assert.ErrorIs(t, cache.Add("c", testNow, testNow.Add(time.Minute)), ErrNonceCacheFull,
+		"no nonce is forgotten while it can still be replayed")
+
+	later := testNow.Add(time.Minute)
+	require.NoError(t, cache.Add("c", later, later.Add(time.Minute)), "expired nonce makes room")
+	assert.Equal(t, 2, cache.Len())
+
+	evenLater := testNow.Add(2 * time.Minute)
+	require.NoError(t, cache.Add("a", evenLater, evenLater.Add(time.Minute)), "expired nonce can be used again")
+	assert.Equal(t, 1, cache.Len())
+}
@@ -0,0 +1,236 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults and limits of a Verifier.
+const (
+	// DefaultTolerance is how far the timestamp of a request may be from the current time.
+	DefaultTolerance = 5 * time.Minute
+	// DefaultMaxBodyBytes bounds the body read by VerifyRequest.
+	DefaultMaxBodyBytes = 1 << 20
+	// defaultNonceCacheSize bounds the nonces remembered by the default nonce cache.
+	defaultNonceCacheSize = 100_000
+
+	// maxSignatures bounds the signatures of a request, so that a single request cannot make
+	// the verifier compare a large number of guesses.
+	maxSignatures = 5
+	// minNonceLength and maxNonceLength bound the length of a nonce.
+	minNonceLength = 16
+	maxNonceLength = 128
+)
+
+// Verifier checks the signatures of inbound webhook requests. It is safe for concurrent use.
+type Verifier struct {
+	secrets      [][]byte
+	tolerance    time.Duration
+	maxBodyBytes int64
+	nonces       NonceCache
+	now          func() time.Time
+}
+
+type VerifierOption func(*Verifier)
+
+// WithPreviousSecret makes the verifier also accept signatures made with secret,
+// so that the sender can switch to a new secret without failing requests.
+func WithPreviousSecret(secret []byte) VerifierOption {
+	return func(v *Verifier) {
+		v.secrets = append(v.secrets, secret)
+	}
+}
+
+// WithTolerance sets how far the timestamp of a request may be from the current time; see DefaultTolerance.
+func WithTolerance(d time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.tolerance = d
+	}
+}
+
+// WithMaxBodyBytes bounds the body read by VerifyRequest; see DefaultMaxBodyBytes.
+func WithMaxBodyBytes(n int64) VerifierOption {
+	return func(v *Verifier) {
+		v.maxBodyBytes = n
+	}
+}
+
+// WithNonceCache makes the verifier remember accepted nonces in c, e.g. one shared by several instances.
+// By default each verifier has its own MemoryNonceCache.
+func WithNonceCache(c NonceCache) VerifierOption {
+	return func(v *Verifier) {
+		v.nonces = c
+	}
+}
+
+// NewVerifier creates a verifier of requests signed with secret.
+func NewVerifier(secret []byte, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		secrets:      [][]byte{secret},
+		tolerance:    DefaultTolerance,
+		maxBodyBytes: DefaultMaxBodyBytes,
+		now:          time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	if v.nonces == nil {
+		v.nonces = NewMemoryNonceCache(defaultNonceCacheSize)
+	}
+
+	return v
+}
+
+// VerifyRequest reads the body of r, verifies it with Verify and returns it.
+// The body of r is replaced with a copy, so that the handler can decode it again.
+func (v *Verifier) VerifyRequest(r *http.Request) ([]byte, error) {
+	body, err := v.readBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.Verify(r.Header, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// readBody reads the body of r up to the limit and replaces it with a copy.
+func (v *Verifier) readBody(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, v.maxBodyBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+
+	if int64(len(body)) > v.maxBodyBytes {
+		return nil, fmt.Errorf("%w: body exceeds %d bytes", ErrBodyTooLarge, v.maxBodyBytes)
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+// Verify checks the signature headers of a request with body. The cheap checks of the headers and
+// the timestamp come first; the nonce is remembered only once a signature matches, so that unsigned
+// requests cannot fill the nonce cache.
+func (v *Verifier) Verify(header http.Header, body []byte) error {
+	rawSignatures := header.Get(SignatureHeader)
+	rawTimestamp := header.Get(TimestampHeader)
+	nonce := header.Get(NonceHeader)
+
+	if rawSignatures == "" || rawTimestamp == "" || nonce == "" {
+		return ErrMissingSignature
+	}
+
+	timestamp, err := parseTimestamp(rawTimestamp)
+	if err != nil {
+		return err
+	}
+
+	if !validNonce(nonce) {
+		return fmt.Errorf("%w: nonce must be %d to %d letters, digits, hyphens or underscores",
+			ErrMalformedSignature, minNonceLength, maxNonceLength)
+	}
+
+	signatures, err := parseSignatures(rawSignatures)
+	if err != nil {
+		return err
+	}
+
+	now := v.now()
+	if skew := now.Sub(timestamp).Abs(); skew > v.tolerance {
+		return fmt.Errorf("%w: signed %s away from now, tolerance is %s", ErrTimestampOutOfRange, skew, v.tolerance)
+	}
+
+	if !v.matches(rawTimestamp, nonce, body, signatures) {
+		return ErrInvalidSignature
+	}
+
+	// The timestamp check rejects the nonce once its timestamp leaves the tolerance, so it need not be remembered longer.
+	return v.nonces.Add(nonce, now, timestamp.Add(v.tolerance))
+}
+
+// matches reports whether any of signatures is the MAC of the payload under any of the secrets.
+// Every pair is compared in constant time and the loop does not stop at the first match,
+// so the time taken does not tell which signature or secret matched.
+func (v *Verifier) matches(timestamp, nonce string, body []byte, signatures [][]byte) bool {
+	matched := false
+
+	for _, secret := range v.secrets {
+		expected := mac(secret, timestamp, nonce, body)
+
+		for _, signature := range signatures {
+			if hmac.Equal(expected, signature) {
+				matched = true
+			}
+		}
+	}
+
+	return matched
+}
+
+// parseTimestamp parses Unix seconds written with digits only, so that e.g. "+1" or "1e9" is rejected.
+func parseTimestamp(raw string) (time.Time, error) {
+	if len(raw) > 12 || strings.ContainsFunc(raw, func(r rune) bool { return r < '0' || r > '9' }) {
+		return time.Time{}, fmt.Errorf("%w: timestamp must be Unix seconds", ErrMalformedSignature)
+	}
+
+	seconds, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: timestamp must be Unix seconds", ErrMalformedSignature)
+	}
+
+	return time.Unix(seconds, 0), nil
+}
+
+func validNonce(nonce string) bool {
+	if len(nonce) < minNonceLength || len(nonce) > maxNonceLength {
+		return false
+	}
+
+	return !strings.ContainsFunc(nonce, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_')
+	})
+}
+
+// parseSignatures decodes the "v1=" signatures of the header. Signatures of other schemes are skipped,
+// so that a sender can add a new scheme before the verifier supports it.
+func parseSignatures(raw string) ([][]byte, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) > maxSignatures {
+		return nil, fmt.Errorf("%w: at most %d signatures are accepted", ErrMalformedSignature, maxSignatures)
+	}
+
+	var signatures [][]byte
+
+	for _, part := range parts {
+		encoded, ok := strings.CutPrefix(strings.TrimSpace(part), signatureScheme)
+		if !ok {
+			continue
+		}
+
+		signature, err := hex.DecodeString(encoded)
+		if err != nil || len(signature) != macSize {
+			return nil, fmt.Errorf("%w: signature must be %d hex-encoded bytes", ErrMalformedSignature, macSize)
+		}
+
+		signatures = append(signatures, signature)
+	}
+
+	if len(signatures) == 0 {
+		return nil, fmt.Errorf("%w: no %s signature", ErrMalformedSignature, strings.TrimSuffix(signatureScheme, "="))
+	}
+
+	return signatures, nil
+}
@@ -0,0 +1,21 @@
+package simulator
+
+import (
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Outcomes of a simulated event recorded in the simulator_events_total metric.
+const (
+	outcomeCreated    = "created"
+	outcomeMerged     = "merged"
+	outcomeReassigned = "reassigned"
+	outcomeFailed     = "failed"
+)
+
+var (
+	eventsTotal = promauto.NewCounterVec(metrics.SimulatorEvents.CounterOpts(), metrics.SimulatorEvents.Labels)
+
+	stepDuration = promauto.NewHistogram(metrics.SimulatorStepDuration.HistogramOpts(prometheus.DefBuckets))
+)
@@ -0,0 +1,97 @@
+package simulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+)
+
+// sampleOutcome is the state a sample pull request is left in.
+type sampleOutcome int
+
+const (
+	sampleOpen sampleOutcome = iota
+	sampleApproved
+	sampleChangesRequested
+	sampleMerged
+	sampleClosed
+)
+
+type samplePR struct {
+	ID          string
+	Name        string
+	AuthorID    string
+	Description string
+	Outcome     sampleOutcome
+}
+
+// samplePRs are created by SeedSamples so that every state of a pull request can be looked at right away.
+var samplePRs = []samplePR{
+	{ID: "demo-101", Name: "Add rate limiting to the public API", AuthorID: "be-alice", Description: "Limits every token to 100 requests per minute.", Outcome: sampleOpen},
+	{ID: "demo-102", Name: "Fix N+1 query in team stats", AuthorID: "be-bob", Description: "Loads the review counters of all members in one query.", Outcome: sampleApproved},
+	{ID: "demo-103", Name: "Build with Go 1.25", AuthorID: "be-carol", Description: "Bumps the toolchain in go.mod, the Dockerfile and CI.", Outcome: sampleMerged},
+	{ID: "demo-104", Name: "Redesign the review dashboard", AuthorID: "fe-erin", Description: "Groups the assigned pull requests by their status.", Outcome: sampleChangesRequested},
+	{ID: "demo-105", Name: "Dark mode for the settings page", AuthorID: "fe-frank", Description: "Follows the color scheme of the operating system.", Outcome: sampleMerged},
+	{ID: "demo-106", Name: "Drop legacy polyfills", AuthorID: "fe-grace", Description: "Superseded by the browser support policy.", Outcome: sampleClosed},
+}
+
+// SeedSamples creates the sample pull requests of the demo teams, which must be seeded first, and brings each
+// to its sample state: open, reviewed, merged or closed. Samples that already exist are left as they are.
+func (s *Simulator) SeedSamples(ctx context.Context) error {
+	for _, sample := range samplePRs {
+		if err := s.seedSample(ctx, sample); err != nil {
+			return fmt.Errorf("failed to seed sample pull request '%s': %w", sample.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Simulator) seedSample(ctx context.Context, sample samplePR) error {
+	description := sample.Description
+	url := "https://git.example.com/demo/pulls/" + sample.ID
+
+	pr, _, err := s.prs.CreatePR(ctx, sample.ID, sample.Name, sample.AuthorID, service.PRDetails{
+		Description: &description,
+		ExternalURL: &url,
+	})
+	if errors.Is(err, apperrors.ErrAlreadyExists) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	reviewers := pr.AssignedReviewers
+
+	switch sample.Outcome {
+	case sampleApproved, sampleChangesRequested:
+		if len(reviewers) == 0 {
+			break
+		}
+
+		submit := s.prs.ApprovePR
+		if sample.Outcome == sampleChangesRequested {
+			submit = s.prs.RequestChanges
+		}
+
+		if _, err := submit(ctx, sample.ID, reviewers[0]); err != nil {
+			return err
+		}
+	case sampleMerged:
+		_, err := s.prs.MergePR(ctx, sample.ID, service.MergeOptions{})
+		return err
+	case sampleClosed:
+		_, err := s.prs.ClosePR(ctx, sample.ID)
+		return err
+	}
+
+	// Open samples are left to the simulation, which may merge them or reassign their reviewers later.
+	s.track(sample.ID, reviewers)
+
+	return nil
+}
@@ -0,0 +1,267 @@
+// package simulator generates synthetic pull request traffic for the dev composition mode.
+// It drives the services directly, the way webhooks of a code hosting platform would drive the API.
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+const (
+	defaultEvents = 10
+	maxEvents     = 1000
+)
+
+// demoTeams are created by Seed so that simulated authors have teammates to review their PRs.
+var demoTeams = []api.Team{
+	{
+		TeamName: "backend",
+		Members: []api.TeamMember{
+			{UserId: "be-alice", Username: "Alice", IsActive: true},
+			{UserId: "be-bob", Username: "Bob", IsActive: true},
+			{UserId: "be-carol", Username: "Carol", IsActive: true},
+			{UserId: "be-dave", Username: "Dave", IsActive: true},
+		},
+	},
+	{
+		TeamName: "frontend",
+		Members: []api.TeamMember{
+			{UserId: "fe-erin", Username: "Erin", IsActive: true},
+			{UserId: "fe-frank", Username: "Frank", IsActive: true},
+			{UserId: "fe-grace", Username: "Grace", IsActive: true},
+			{UserId: "fe-heidi", Username: "Heidi", IsActive: true},
+		},
+	},
+}
+
+// Result counts the outcomes of a simulation run.
+type Result struct {
+	Created    int `json:"created"`
+	Merged     int `json:"merged"`
+	Reassigned int `json:"reassigned"`
+	Failed     int `json:"failed"`
+}
+
+// Simulator opens, reassigns and merges pull requests on behalf of the demo teams.
+type Simulator struct {
+	log   *slog.Logger
+	teams service.TeamService
+	prs   service.PRCommandService
+
+	// mu guards rand, seq and open.
+	mu   sync.Mutex
+	rand *rand.Rand
+	seq  int
+	// open holds the reviewers of the simulated PRs that are not merged yet.
+	open map[string][]string
+}
+
+func New(log *slog.Logger, teams service.TeamService, prs service.PRCommandService) *Simulator {
+	return &Simulator{
+		log:   log.With(slog.String("component", "simulator")),
+		teams: teams,
+		prs:   prs,
+		rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
+		open:  make(map[string][]string),
+	}
+}
+
+// Seed creates the demo teams. Teams that already exist are left as they are.
+func (s *Simulator) Seed(ctx context.Context) error {
+	for _, team := range demoTeams {
+		if _, err := s.teams.CreateTeamWithUsers(ctx, team); err != nil && !errors.Is(err, apperrors.ErrAlreadyExists) {
+			return fmt.Errorf("failed to create demo team '%s': %w", team.TeamName, err)
+		}
+	}
+
+	return nil
+}
+
+// Run simulates n events. Every event opens a new PR or, when simulated PRs are open,
+// may merge one of them or reassign one of its reviewers instead.
+func (s *Simulator) Run(ctx context.Context, n int) Result {
+	var result Result
+
+	for range n {
+		if ctx.Err() != nil {
+			break
+		}
+
+		s.step(ctx, &result)
+	}
+
+	s.log.Info("simulation finished",
+		slog.Int("created", result.Created),
+		slog.Int("merged", result.Merged),
+		slog.Int("reassigned", result.Reassigned),
+		slog.Int("failed", result.Failed),
+	)
+
+	return result
+}
+
+// RunEvery simulates one event per interval until ctx is cancelled.
+func (s *Simulator) RunEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			var result Result
+			s.step(ctx, &result)
+		}
+	}
+}
+
+// ServeHTTP runs a simulation of the number of events given by the "events" query parameter
+// and responds with its Result.
+func (s *Simulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	events := defaultEvents
+
+	if raw := r.URL.Query().Get("events"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxEvents {
+			http.Error(w, fmt.Sprintf("events must be an integer between 1 and %d", maxEvents), http.StatusBadRequest)
+			return
+		}
+
+		events = n
+	}
+
+	result := s.Run(r.Context(), events)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		s.log.Error("failed to encode simulation result", sl.Err(err))
+	}
+}
+
+type action int
+
+const (
+	actionCreate action = iota
+	actionMerge
+	actionReassign
+)
+
+func (s *Simulator) step(ctx context.Context, result *Result) {
+	start := time.Now()
+	defer func() { stepDuration.Observe(time.Since(start).Seconds()) }()
+
+	act, prID, reviewerID, authorID := s.next()
+
+	var err error
+
+	switch act {
+	case actionCreate:
+		var pr *api.PullRequest
+
+		description := "Generated by the dev traffic simulator."
+		url := "https://git.example.com/demo/pulls/" + prID
+		details := service.PRDetails{
+			Description: &description,
+			ExternalURL: &url,
+		}
+
+		pr, _, err = s.prs.CreatePR(ctx, prID, "Synthetic change "+prID, authorID, details)
+		if err == nil {
+			s.track(prID, pr.AssignedReviewers)
+			result.Created++
+			eventsTotal.WithLabelValues(outcomeCreated).Inc()
+		}
+	case actionMerge:
+		_, err = s.prs.MergePR(ctx, prID, service.MergeOptions{})
+		if err == nil {
+			s.untrack(prID)
+			result.Merged++
+			eventsTotal.WithLabelValues(outcomeMerged).Inc()
+		}
+	case actionReassign:
+		var resp *api.ReassignResponse
+
+		resp, err = s.prs.ReassignReviewer(ctx, prID, reviewerID, "")
+		if err == nil {
+			s.track(prID, resp.Pr.AssignedReviewers)
+			result.Reassigned++
+			eventsTotal.WithLabelValues(outcomeReassigned).Inc()
+		}
+	}
+
+	if err != nil {
+		s.log.Warn("simulated event failed", slog.String("pr_id", prID), sl.Err(err))
+		result.Failed++
+		eventsTotal.WithLabelValues(outcomeFailed).Inc()
+	}
+}
+
+// next picks the action of the next event along with the PR and user it applies to.
+func (s *Simulator) next() (act action, prID, reviewerID, authorID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.open) > 0 {
+		roll := s.rand.Intn(10)
+
+		prIDs := make([]string, 0, len(s.open))
+		for id := range s.open {
+			prIDs = append(prIDs, id)
+		}
+
+		slices.Sort(prIDs)
+		prID = prIDs[s.rand.Intn(len(prIDs))]
+		reviewers := s.open[prID]
+
+		switch {
+		case roll < 3:
+			return actionMerge, prID, "", ""
+		case roll < 5 && len(reviewers) > 0:
+			return actionReassign, prID, reviewers[s.rand.Intn(len(reviewers))], ""
+		}
+	}
+
+	team := demoTeams[s.rand.Intn(len(demoTeams))]
+	author := team.Members[s.rand.Intn(len(team.Members))]
+
+	s.seq++
+
+	return actionCreate, fmt.Sprintf("sim-%d-%d", time.Now().Unix(), s.seq), "", author.UserId
+}
+
+func (s *Simulator) track(prID string, reviewers []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.open[prID] = reviewers
+}
+
+func (s *Simulator) untrack(prID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.open, prID)
+}
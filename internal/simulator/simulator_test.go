@@ -0,0 +1,108 @@
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/repository/memory"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSimulator(t *testing.T) *Simulator {
+	t.Helper()
+
+	return newTestSimulatorWithLog(t, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// newTestSimulatorWithLog returns a simulator whose services check the reviewer invariants after every mutation.
+func newTestSimulatorWithLog(t *testing.T, log *slog.Logger) *Simulator {
+	t.Helper()
+
+	store := memory.NewStore(log)
+	txManager := txctx.NewManager(store.DB(), log)
+
+	teamService := service.NewTeamService(store, store, store, store)
+	prService := service.NewPullRequestService(txManager, log, store, store, store, store, store, service.WithInvariantChecks(1))
+
+	sim := New(log, teamService, prService)
+	require.NoError(t, sim.Seed(context.Background()))
+
+	return sim
+}
+
+func TestSimulator_Run(t *testing.T) {
+	sim := newTestSimulator(t)
+
+	result := sim.Run(context.Background(), 50)
+
+	assert.Equal(t, 50, result.Created+result.Merged+result.Reassigned+result.Failed)
+	assert.Positive(t, result.Created)
+	assert.Zero(t, result.Failed)
+}
+
+func TestSimulator_RunKeepsReviewerInvariants(t *testing.T) {
+	var logs bytes.Buffer
+	sim := newTestSimulatorWithLog(t, slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelError})))
+
+	result := sim.Run(context.Background(), 200)
+
+	assert.Positive(t, result.Reassigned)
+	assert.NotContains(t, logs.String(), "reviewer invariant violated")
+}
+
+func TestSimulator_ServeHTTP(t *testing.T) {
+	sim := newTestSimulator(t)
+
+	testCases := []struct {
+		name               string
+		method             string
+		target             string
+		expectedStatusCode int
+		expectedEvents     int
+	}{
+		{name: "Default number of events", method: http.MethodPost, target: "/", expectedStatusCode: http.StatusOK, expectedEvents: defaultEvents},
+		{name: "Explicit number of events", method: http.MethodPost, target: "/?events=3", expectedStatusCode: http.StatusOK, expectedEvents: 3},
+		{name: "Invalid number of events", method: http.MethodPost, target: "/?events=0", expectedStatusCode: http.StatusBadRequest},
+		{name: "Wrong method", method: http.MethodGet, target: "/", expectedStatusCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			sim.ServeHTTP(rr, httptest.NewRequest(tc.method, tc.target, nil))
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+
+			if tc.expectedStatusCode == http.StatusOK {
+				var result Result
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+				assert.Equal(t, tc.expectedEvents, result.Created+result.Merged+result.Reassigned+result.Failed)
+			}
+		})
+	}
+}
+
+func TestSimulator_SeedSamples(t *testing.T) {
+	sim := newTestSimulator(t)
+	ctx := context.Background()
+
+	require.NoError(t, sim.SeedSamples(ctx))
+	assert.ElementsMatch(t, []string{"demo-101", "demo-102", "demo-104"}, slices.Collect(maps.Keys(sim.open)),
+		"open samples are left to the simulation")
+
+	require.NoError(t, sim.SeedSamples(ctx), "existing samples are left as they are")
+
+	result := sim.Run(ctx, 20)
+	assert.Zero(t, result.Failed)
+}
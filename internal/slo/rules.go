@@ -0,0 +1,136 @@
+package slo
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+)
+
+// RuleFile is a Prometheus rule file.
+type RuleFile struct {
+	Groups []RuleGroup `yaml:"groups"`
+}
+
+type RuleGroup struct {
+	Name  string `yaml:"name"`
+	Rules []Rule `yaml:"rules"`
+}
+
+type Rule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// burnWindow is a pair of windows of a multiwindow burn-rate alert: the alert fires when
+// the given share of the error budget is spent within the long window and the burn still goes on
+// within the short one.
+type burnWindow struct {
+	long, short time.Duration
+	budgetSpent float64
+	severity    string
+}
+
+// burnWindows are the windows recommended by the Google SRE workbook: for a 30 day window they give
+// burn rates of 14.4 and 6 for paging and of 3 and 1 for tickets.
+var burnWindows = []burnWindow{
+	{long: time.Hour, short: 5 * time.Minute, budgetSpent: 0.02, severity: "page"},
+	{long: 6 * time.Hour, short: 30 * time.Minute, budgetSpent: 0.05, severity: "page"},
+	{long: 24 * time.Hour, short: 2 * time.Hour, budgetSpent: 0.1, severity: "ticket"},
+	{long: 72 * time.Hour, short: 6 * time.Hour, budgetSpent: 0.1, severity: "ticket"},
+}
+
+// Rules builds multiwindow, multi-burn-rate alerts for every objective.
+func Rules(cfg config.SLO) RuleFile {
+	group := RuleGroup{Name: "pr-reviewer-service-slo", Rules: []Rule{}}
+
+	for _, o := range cfg.Objectives {
+		if o.Availability != 0 {
+			for _, w := range burnWindows {
+				group.Rules = append(group.Rules, burnRateRule(cfg.Window, o, "availability", o.Availability, w, availabilityErrorRatio))
+			}
+		}
+
+		if o.Latency != 0 {
+			for _, w := range burnWindows {
+				group.Rules = append(group.Rules, burnRateRule(cfg.Window, o, "latency", o.LatencyTarget, w, latencyErrorRatio))
+			}
+		}
+	}
+
+	return RuleFile{Groups: []RuleGroup{group}}
+}
+
+func burnRateRule(
+	window time.Duration,
+	o config.SLOObjective,
+	sli string,
+	target float64,
+	w burnWindow,
+	errorRatio func(o config.SLOObjective, window string) string,
+) Rule {
+	burnRate := w.budgetSpent * window.Hours() / w.long.Hours()
+	threshold := formatFloat(burnRate * (1 - target))
+
+	expr := fmt.Sprintf("(%s) > %s\nand\n(%s) > %s",
+		errorRatio(o, promDuration(w.long)), threshold,
+		errorRatio(o, promDuration(w.short)), threshold,
+	)
+
+	return Rule{
+		Alert: "SLOErrorBudgetBurn",
+		Expr:  expr,
+		Labels: map[string]string{
+			"slo":         o.Name,
+			"sli":         sli,
+			"severity":    w.severity,
+			"long_window": promDuration(w.long),
+		},
+		Annotations: map[string]string{
+			"summary": fmt.Sprintf("%s %s: %s error budget is burning %sx too fast", o.Method, o.Path, sli, formatFloat(burnRate)),
+			"description": fmt.Sprintf("%s%% of the %s error budget of %s was spent within the last %s.",
+				formatFloat(w.budgetSpent*100), promDuration(window), o.Name, promDuration(w.long)),
+		},
+	}
+}
+
+func availabilityErrorRatio(o config.SLOObjective, window string) string {
+	selector := endpointSelector(o)
+
+	return fmt.Sprintf(`sum(rate(%s{%s,status=~"5.."}[%s])) / sum(rate(%s{%s}[%s]))`,
+		metrics.HTTPRequests.Name, selector, window, metrics.HTTPRequests.Name, selector, window)
+}
+
+func latencyErrorRatio(o config.SLOObjective, window string) string {
+	selector := endpointSelector(o)
+	name := metrics.HTTPRequestDuration.Name
+
+	return fmt.Sprintf(`1 - sum(rate(%s_bucket{%s,le="%s"}[%s])) / sum(rate(%s_count{%s}[%s]))`,
+		name, selector, formatFloat(o.Latency.Seconds()), window, name, selector, window)
+}
+
+func endpointSelector(o config.SLOObjective) string {
+	return fmt.Sprintf(`path=%q,method=%q`, o.Path, o.Method)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
+
+// promDuration formats d in the Prometheus duration syntax, e.g. 30m, 6h or 30d.
+func promDuration(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
@@ -0,0 +1,162 @@
+// Package slo turns the service level objectives from the config into Prometheus alerting rules
+// and reports how well the running instance meets them.
+package slo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Report is the compliance of every objective, computed from the in-process metrics,
+// i.e. over the requests served since the instance started.
+type Report struct {
+	Since      time.Time         `json:"since"`
+	Objectives []ObjectiveStatus `json:"objectives"`
+}
+
+// ObjectiveStatus is the compliance of a single objective.
+type ObjectiveStatus struct {
+	Name         string     `json:"name"`
+	Method       string     `json:"method"`
+	Path         string     `json:"path"`
+	Requests     uint64     `json:"requests"`
+	Availability *SLIStatus `json:"availability,omitempty"`
+	Latency      *SLIStatus `json:"latency,omitempty"`
+}
+
+// SLIStatus compares the measured share of good requests with the target.
+type SLIStatus struct {
+	// ThresholdSeconds is the latency a request must fit into; it is only set for latency.
+	ThresholdSeconds float64 `json:"threshold_seconds,omitempty"`
+	Target           float64 `json:"target"`
+	Current          float64 `json:"current"`
+	Met              bool    `json:"met"`
+	// ErrorBudgetRemaining is the unspent share of the error budget; it is negative once the budget is exceeded.
+	ErrorBudgetRemaining float64 `json:"error_budget_remaining"`
+}
+
+// endpointCounts holds the request counters of one endpoint.
+type endpointCounts struct {
+	total  uint64
+	errors uint64
+	// fast maps a histogram bucket bound to the number of requests that fit into it.
+	fast     map[float64]uint64
+	observed uint64
+}
+
+// Compliance computes the report from the HTTP metrics gathered from g.
+func Compliance(g prometheus.Gatherer, cfg config.SLO, since time.Time) (*Report, error) {
+	families, err := g.Gather()
+	if err != nil {
+		return nil, fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	counts := collectCounts(families)
+
+	report := &Report{Since: since, Objectives: make([]ObjectiveStatus, 0, len(cfg.Objectives))}
+
+	for _, o := range cfg.Objectives {
+		c := counts[endpointKey(o.Method, o.Path)]
+		if c == nil {
+			c = &endpointCounts{}
+		}
+
+		status := ObjectiveStatus{
+			Name:     o.Name,
+			Method:   o.Method,
+			Path:     o.Path,
+			Requests: c.total,
+		}
+
+		if o.Availability != 0 {
+			status.Availability = sliStatus(o.Availability, c.total-c.errors, c.total)
+		}
+
+		if o.Latency != 0 {
+			threshold := o.Latency.Seconds()
+			status.Latency = sliStatus(o.LatencyTarget, c.fast[threshold], c.observed)
+			status.Latency.ThresholdSeconds = threshold
+		}
+
+		report.Objectives = append(report.Objectives, status)
+	}
+
+	return report, nil
+}
+
+func sliStatus(target float64, good, total uint64) *SLIStatus {
+	current := 1.0
+	if total > 0 {
+		current = float64(good) / float64(total)
+	}
+
+	return &SLIStatus{
+		Target:               target,
+		Current:              current,
+		Met:                  current >= target,
+		ErrorBudgetRemaining: 1 - (1-current)/(1-target),
+	}
+}
+
+func endpointKey(method, path string) string {
+	return method + " " + path
+}
+
+func collectCounts(families []*dto.MetricFamily) map[string]*endpointCounts {
+	counts := make(map[string]*endpointCounts)
+
+	get := func(m *dto.Metric) *endpointCounts {
+		labels := make(map[string]string, len(m.GetLabel()))
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+
+		key := endpointKey(labels["method"], labels["path"])
+		if counts[key] == nil {
+			counts[key] = &endpointCounts{fast: make(map[float64]uint64)}
+		}
+
+		return counts[key]
+	}
+
+	for _, family := range families {
+		switch family.GetName() {
+		case metrics.HTTPRequests.Name:
+			for _, m := range family.GetMetric() {
+				c := get(m)
+				value := uint64(m.GetCounter().GetValue())
+				c.total += value
+
+				if isServerError(m) {
+					c.errors += value
+				}
+			}
+		case metrics.HTTPRequestDuration.Name:
+			for _, m := range family.GetMetric() {
+				c := get(m)
+				c.observed += m.GetHistogram().GetSampleCount()
+
+				for _, b := range m.GetHistogram().GetBucket() {
+					c.fast[b.GetUpperBound()] += b.GetCumulativeCount()
+				}
+			}
+		}
+	}
+
+	return counts
+}
+
+func isServerError(m *dto.Metric) bool {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == "status" {
+			return len(l.GetValue()) == 3 && l.GetValue()[0] == '5'
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,95 @@
+package slo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSLO = config.SLO{
+	Window: 30 * 24 * time.Hour,
+	Objectives: []config.SLOObjective{
+		{
+			Name: "create-pr", Method: "POST", Path: "/pullRequest/create",
+			Availability: 0.99, Latency: 250 * time.Millisecond, LatencyTarget: 0.9,
+		},
+		{Name: "get-review", Method: "GET", Path: "/users/getReview", Availability: 0.999},
+	},
+}
+
+func TestCompliance(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	requests := prometheus.NewCounterVec(metrics.HTTPRequests.CounterOpts(), metrics.HTTPRequests.Labels)
+	durations := prometheus.NewHistogramVec(metrics.HTTPRequestDuration.HistogramOpts(metrics.HTTPDurationBuckets), metrics.HTTPRequestDuration.Labels)
+	registry.MustRegister(requests, durations)
+
+	requests.WithLabelValues("/pullRequest/create", "POST", "201").Add(95)
+	requests.WithLabelValues("/pullRequest/create", "POST", "409").Add(3)
+	requests.WithLabelValues("/pullRequest/create", "POST", "500").Add(2)
+	requests.WithLabelValues("/pullRequest/merge", "POST", "500").Add(10)
+
+	for i := 0; i < 80; i++ {
+		durations.WithLabelValues("/pullRequest/create", "POST").Observe(0.1)
+	}
+
+	for i := 0; i < 20; i++ {
+		durations.WithLabelValues("/pullRequest/create", "POST").Observe(0.4)
+	}
+
+	since := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	report, err := Compliance(registry, testSLO, since)
+	require.NoError(t, err)
+	assert.Equal(t, since, report.Since)
+	require.Len(t, report.Objectives, 2)
+
+	create := report.Objectives[0]
+	assert.Equal(t, uint64(100), create.Requests)
+	require.NotNil(t, create.Availability)
+	assert.InDelta(t, 0.98, create.Availability.Current, 1e-9)
+	assert.False(t, create.Availability.Met)
+	assert.InDelta(t, -1, create.Availability.ErrorBudgetRemaining, 1e-9)
+	require.NotNil(t, create.Latency)
+	assert.Equal(t, 0.25, create.Latency.ThresholdSeconds)
+	assert.InDelta(t, 0.8, create.Latency.Current, 1e-9)
+	assert.False(t, create.Latency.Met)
+
+	review := report.Objectives[1]
+	assert.Zero(t, review.Requests)
+	require.NotNil(t, review.Availability)
+	assert.Equal(t, 1.0, review.Availability.Current)
+	assert.True(t, review.Availability.Met)
+	assert.Nil(t, review.Latency)
+}
+
+func TestRules(t *testing.T) {
+	file := Rules(testSLO)
+	require.Len(t, file.Groups, 1)
+
+	// create-pr has two SLIs and get-review one, each with four burn-rate windows.
+	rules := file.Groups[0].Rules
+	require.Len(t, rules, 12)
+
+	first := rules[0]
+	assert.Equal(t, "SLOErrorBudgetBurn", first.Alert)
+	assert.Equal(t, map[string]string{"slo": "create-pr", "sli": "availability", "severity": "page", "long_window": "1h"}, first.Labels)
+	assert.Equal(t,
+		`(sum(rate(http_requests_total{path="/pullRequest/create",method="POST",status=~"5.."}[1h])) / sum(rate(http_requests_total{path="/pullRequest/create",method="POST"}[1h]))) > 0.144`+"\nand\n"+
+			`(sum(rate(http_requests_total{path="/pullRequest/create",method="POST",status=~"5.."}[5m])) / sum(rate(http_requests_total{path="/pullRequest/create",method="POST"}[5m]))) > 0.144`,
+		first.Expr)
+
+	latency := rules[4]
+	assert.Equal(t, "latency", latency.Labels["sli"])
+	assert.Contains(t, latency.Expr, `http_request_duration_seconds_bucket{path="/pullRequest/create",method="POST",le="0.25"}[1h]`)
+	assert.Contains(t, latency.Expr, "> 1.44")
+
+	ticket := rules[3]
+	assert.Equal(t, "ticket", ticket.Labels["severity"])
+	assert.Equal(t, "3d", ticket.Labels["long_window"])
+	assert.Contains(t, ticket.Expr, "> 0.01")
+}
@@ -0,0 +1,11 @@
+// Package soak holds the soak tests of the background subsystems: the job runner, the outbox relay and the
+// pending assignment filler run against a Postgres container for SOAK_DURATION while faults are injected,
+// and the tests check that no goroutine leaks, no worker starves on a lock and the heap stays bounded.
+//
+// The service has no SLA checker, so the soak run cannot include one. The pending assignment filler takes
+// its place as the periodic checker of review state: like an SLA checker would, it scans the open pull requests
+// on a timer and changes those that need it. A future SLA checker belongs in TestSoak_BackgroundSubsystems
+// next to it.
+//
+// The tests carry the integration build tag and only run when SOAK_DURATION is set, see make test-soak.
+package soak
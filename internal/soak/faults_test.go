@@ -0,0 +1,136 @@
+//go:build integration
+
+package soak
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	errInjectedPublish = errors.New("injected publish failure")
+	errInjectedJob     = errors.New("injected job failure")
+)
+
+// flakyPublisher is an event sink that rejects a share of the events and is slow to accept the others.
+// It remembers the events it accepted, so that the test can check that none was lost.
+type flakyPublisher struct {
+	failRate float64
+
+	mu       sync.Mutex
+	accepted map[int64]int
+}
+
+func newFlakyPublisher(failRate float64) *flakyPublisher {
+	return &flakyPublisher{failRate: failRate, accepted: make(map[int64]int)}
+}
+
+func (p *flakyPublisher) Name() string {
+	return "flaky"
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	if rand.Float64() < p.failRate {
+		return errInjectedPublish
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Duration(rand.Intn(5)) * time.Millisecond):
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.accepted[event.ID]++
+
+	return nil
+}
+
+// acceptedIDs returns the IDs of the events accepted at least once.
+func (p *flakyPublisher) acceptedIDs() map[int64]bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids := make(map[int64]bool, len(p.accepted))
+	for id := range p.accepted {
+		ids[id] = true
+	}
+
+	return ids
+}
+
+// flakyJob is a job handler that works in a few steps, reporting its progress after each, and fails a share
+// of the jobs at the end.
+type flakyJob struct {
+	failRate float64
+}
+
+func (flakyJob) Validate([]byte) error {
+	return nil
+}
+
+func (j flakyJob) Run(ctx context.Context, _ []byte, progress service.JobProgress) (any, error) {
+	const steps = 5
+
+	for step := range steps {
+		select {
+		case <-ctx.Done():
+			return map[string]int{"done": step}, ctx.Err()
+		case <-time.After(time.Duration(rand.Intn(20)) * time.Millisecond):
+		}
+
+		if err := progress.Report(ctx, step+1, steps); err != nil {
+			return map[string]int{"done": step + 1}, err
+		}
+	}
+
+	if rand.Float64() < j.failRate {
+		return nil, errInjectedJob
+	}
+
+	return map[string]int{"done": steps}, nil
+}
+
+// killConnections terminates every connection of the subsystems under test once per interval, the way a
+// database failover or a restarted connection pooler drops them, until ctx is cancelled.
+func killConnections(ctx context.Context, admin *sqlx.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// An error here only means a round of the fault is skipped.
+			_, _ = admin.ExecContext(ctx,
+				"SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE application_name = $1", serviceApplicationName)
+		}
+	}
+}
+
+// churnReviewers deactivates and reactivates the given users at random once per interval until ctx is
+// cancelled, so that pull requests keep falling short of reviewers and the filler keeps finding new ones.
+func churnReviewers(ctx context.Context, admin *sqlx.DB, userIDs []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			userID := userIDs[rand.Intn(len(userIDs))]
+			_, _ = admin.ExecContext(ctx, "UPDATE users SET is_active = NOT is_active WHERE id = $1", userID)
+		}
+	}
+}
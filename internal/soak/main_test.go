@@ -0,0 +1,108 @@
+//go:build integration
+
+package soak
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jmoiron/sqlx"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// serviceApplicationName marks the connections of the subsystems under test, so that the fault injector
+// and the lock probe leave the connection of the test itself alone.
+const serviceApplicationName = "pr-reviewer-soak"
+
+var (
+	// testDB is the connection of the test itself; nil when the soak tests are not asked for.
+	testDB *sqlx.DB
+	// serviceConnStr connects the subsystems under test.
+	serviceConnStr string
+	// soakDuration is how long the load runs.
+	soakDuration time.Duration
+	logger       *slog.Logger
+)
+
+func TestMain(m *testing.M) {
+	raw := os.Getenv("SOAK_DURATION")
+	if raw == "" {
+		// The soak tests run for minutes, so a plain integration run skips them.
+		os.Exit(m.Run())
+	}
+
+	var err error
+
+	soakDuration, err = time.ParseDuration(raw)
+	if err != nil || soakDuration <= 0 {
+		log.Fatalf("invalid SOAK_DURATION '%s': a positive duration such as 10m is expected", raw)
+	}
+
+	ctx := context.Background()
+	// Every job and event is logged at info level, which would bury the failures of a long run.
+	logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	pgContainer, err := postgres.RunContainer(ctx,
+		testcontainers.WithImage("postgres:17"),
+		postgres.WithDatabase("soak-db"),
+		postgres.WithUsername("user"),
+		postgres.WithPassword("password"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(5*time.Second),
+		),
+	)
+	if err != nil {
+		log.Fatalf("could not start postgres container: %s", err)
+	}
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable", "timezone=UTC")
+	if err != nil {
+		log.Fatalf("failed to get connection string: %s", err)
+	}
+
+	serviceConnStr, err = pgContainer.ConnectionString(ctx, "sslmode=disable", "timezone=UTC",
+		"application_name="+serviceApplicationName)
+	if err != nil {
+		log.Fatalf("failed to get connection string: %s", err)
+	}
+
+	testDB, err = sqlx.Connect("postgres", connStr)
+	if err != nil {
+		log.Fatalf("failed to connect to test postgres: %s", err)
+	}
+
+	_, b, _, _ := runtime.Caller(0)
+	sourceURL := "file://" + filepath.ToSlash(filepath.Join(filepath.Dir(b), "../../migrations"))
+
+	migrator, err := migrate.New(sourceURL, connStr)
+	if err != nil {
+		log.Fatalf("failed to create migrator with url '%s': %s", sourceURL, err)
+	}
+
+	if err = migrator.Up(); err != nil {
+		log.Fatalf("failed to run migrations: %s", err)
+	}
+
+	code := m.Run()
+
+	testDB.Close()
+
+	if err := pgContainer.Terminate(ctx); err != nil {
+		log.Fatalf("could not stop postgres container: %s", err)
+	}
+
+	os.Exit(code)
+}
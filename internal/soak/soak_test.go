@@ -0,0 +1,301 @@
+//go:build integration
+
+package soak
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/filler"
+	"github.com/YusovID/pr-reviewer-service/internal/relay"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/postgres"
+	"github.com/YusovID/pr-reviewer-service/internal/repository/txctx"
+	"github.com/YusovID/pr-reviewer-service/internal/runner"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Bounds the subsystems must stay within for the whole run.
+const (
+	// maxLatency bounds how long a job waits to finish and an event waits to be published,
+	// retries after the injected faults included.
+	maxLatency = 30 * time.Second
+	// maxLockWait bounds how long a connection of the subsystems waits for a row lock.
+	maxLockWait = 5 * time.Second
+	// maxHeapGrowth bounds how much the live heap grows after the warm-up.
+	maxHeapGrowth = 64 << 20
+	// drainTimeout bounds how long the subsystems take to finish the work left once the load stops.
+	drainTimeout = 2 * time.Minute
+	// stopTimeout bounds how long the subsystems take to stop once their context is cancelled.
+	stopTimeout = 10 * time.Second
+)
+
+func TestSoak_BackgroundSubsystems(t *testing.T) {
+	if testDB == nil {
+		t.Skip("SOAK_DURATION is not set")
+	}
+
+	ctx := context.Background()
+
+	// Taken before the pool of the subsystems is opened: once it is closed, every goroutine started
+	// from here on must be gone.
+	baseGoroutines := runtime.NumGoroutine()
+
+	db, err := sqlx.Connect("postgres", serviceConnStr)
+	require.NoError(t, err)
+	// A small pool makes the subsystems compete for connections as they would in production.
+	db.SetMaxOpenConns(10)
+
+	authors := []string{"soak-author-1", "soak-author-2"}
+	churned := []string{"soak-rev-1", "soak-rev-2", "soak-rev-3", "soak-rev-4"}
+	seedTeam(t, db, append(append([]string{}, authors...), churned...))
+
+	prRepo := postgres.NewPullRequestRepository(db, logger)
+	outboxRepo := postgres.NewOutboxRepository(db, logger)
+	prService := service.NewPullRequestService(txctx.NewManager(db, logger), logger, prRepo, prRepo, prRepo,
+		postgres.NewPolicyRepository(db, logger), postgres.NewAssignmentHistoryRepository(db, logger),
+		service.WithPendingAssignments(postgres.NewPendingAssignmentRepository(db, logger)), service.WithOutbox(outboxRepo))
+
+	publisher := newFlakyPublisher(0.2)
+	outboxService := service.NewOutboxService(outboxRepo, []service.EventPublisher{publisher}, logger,
+		service.WithOutboxPublishPolicy(2*time.Second, 50*time.Millisecond, time.Second))
+	jobService := service.NewJobService(postgres.NewJobRepository(db, logger), 2*time.Second, logger,
+		service.WithJobHandler(domain.JobStatsExport, flakyJob{failRate: 0.1}))
+
+	subsystemsCtx, stopSubsystems := context.WithCancel(ctx)
+	loadCtx, stopLoad := context.WithCancel(ctx)
+
+	var subsystems, load sync.WaitGroup
+
+	run := func(wg *sync.WaitGroup, ctx context.Context, f func(context.Context)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			f(ctx)
+		}()
+	}
+
+	run(&subsystems, subsystemsCtx, runner.New(logger, jobService, 50*time.Millisecond, 4).Run)
+	run(&subsystems, subsystemsCtx, relay.New(logger, outboxService, 50*time.Millisecond, 20).Run)
+	// The filler stands in for the SLA checker, which the service does not have, see the package doc.
+	run(&subsystems, subsystemsCtx, filler.New(logger, prService, 100*time.Millisecond, 20).Run)
+
+	run(&load, loadCtx, func(ctx context.Context) {
+		createPullRequests(ctx, prService, authors, 20*time.Millisecond)
+	})
+	run(&load, loadCtx, func(ctx context.Context) {
+		createJobs(ctx, jobService, 100*time.Millisecond)
+	})
+	run(&load, loadCtx, func(ctx context.Context) {
+		churnReviewers(ctx, testDB, churned, 200*time.Millisecond)
+	})
+	run(&load, loadCtx, func(ctx context.Context) {
+		killConnections(ctx, testDB, 3*time.Second)
+	})
+
+	probe := newProbe()
+	run(&load, loadCtx, func(ctx context.Context) {
+		probe.run(ctx, time.Second)
+	})
+
+	t.Logf("soaking for %s", soakDuration)
+	time.Sleep(soakDuration)
+
+	stopLoad()
+	load.Wait()
+
+	// With every reviewer back, nothing stops the queued pull requests from getting their reviewers.
+	_, err = testDB.ExecContext(ctx, "UPDATE users SET is_active = TRUE")
+	require.NoError(t, err)
+
+	unfinished := func() (jobs, events, pending int) {
+		return count(t, "SELECT COUNT(*) FROM jobs WHERE status IN ('queued', 'running')"),
+			count(t, "SELECT COUNT(*) FROM outbox_events WHERE published_at IS NULL"),
+			count(t, "SELECT COUNT(*) FROM pending_assignments")
+	}
+
+	drained := assert.Eventually(t, func() bool {
+		jobs, events, pending := unfinished()
+		return jobs+events+pending == 0
+	}, drainTimeout, time.Second)
+	if !drained {
+		jobs, events, pending := unfinished()
+		t.Fatalf("the subsystems did not finish the work left within %s: %d jobs, %d events, %d pending assignments",
+			drainTimeout, jobs, events, pending)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		stopSubsystems()
+		subsystems.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(stopTimeout):
+		t.Fatalf("the subsystems did not stop within %s:\n%s", stopTimeout, goroutineDump())
+	}
+
+	require.NoError(t, db.Close())
+
+	// Polled by hand: the helpers of testify run their conditions in goroutines of their own.
+	for deadline := time.Now().Add(5 * time.Second); runtime.NumGoroutine() > baseGoroutines && time.Now().Before(deadline); {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if n := runtime.NumGoroutine(); n > baseGoroutines {
+		t.Errorf("%d goroutines left, %d before the run:\n%s", n, baseGoroutines, goroutineDump())
+	}
+
+	t.Run("Every event reaches the sink", func(t *testing.T) {
+		var ids []int64
+		require.NoError(t, testDB.SelectContext(ctx, &ids, "SELECT id FROM outbox_events"))
+		require.NotEmpty(t, ids, "no event was written, the load did not run")
+
+		accepted := publisher.acceptedIDs()
+		for _, id := range ids {
+			assert.True(t, accepted[id], "event %d is marked published but never reached the sink", id)
+		}
+	})
+
+	t.Run("No job or event starves", func(t *testing.T) {
+		assert.LessOrEqual(t, seconds(t, "SELECT MAX(finished_at - created_at) FROM jobs"), maxLatency.Seconds(),
+			"a job waited too long to finish")
+		assert.LessOrEqual(t, seconds(t, "SELECT MAX(published_at - occurred_at) FROM outbox_events"), maxLatency.Seconds(),
+			"an event waited too long to be published")
+		assert.LessOrEqual(t, probe.longestLockWait, maxLockWait, "a connection waited too long for a lock")
+		assert.Zero(t, count(t, "SELECT COUNT(*) FROM pull_requests WHERE status = 'OPEN' AND need_more_reviewers"),
+			"a pull request was left without its reviewers")
+	})
+
+	t.Run("Heap stays bounded", func(t *testing.T) {
+		require.NotEmpty(t, probe.heap)
+
+		// The first quarter of the run warms up the pools and caches.
+		warm := probe.heap[len(probe.heap)/4]
+		last := probe.heap[len(probe.heap)-1]
+		assert.LessOrEqual(t, last, warm+maxHeapGrowth, "the heap grew from %d to %d bytes", warm, last)
+	})
+}
+
+// seedTeam creates the team of the run with userIDs as its active members.
+func seedTeam(t *testing.T, db *sqlx.DB, userIDs []string) {
+	t.Helper()
+
+	team := api.Team{TeamName: "soak"}
+	for _, id := range userIDs {
+		team.Members = append(team.Members, api.TeamMember{UserId: id, Username: id, IsActive: true})
+	}
+
+	_, err := postgres.NewTeamRepository(db, logger).CreateTeamWithUsers(context.Background(), team)
+	require.NoError(t, err)
+}
+
+// createPullRequests opens a pull request once per interval until ctx is cancelled. The errors caused
+// by the injected faults are expected: the pull request is simply not created.
+func createPullRequests(ctx context.Context, prs service.PullRequestService, authors []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for i := 0; ; i++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			prID := fmt.Sprintf("soak-pr-%d", i)
+			_, _, _ = prs.CreatePR(ctx, prID, "soak "+prID, authors[i%len(authors)], service.PRDetails{})
+		}
+	}
+}
+
+// createJobs queues a job once per interval until ctx is cancelled.
+func createJobs(ctx context.Context, jobs service.JobService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = jobs.CreateJob(ctx, api.JobType(domain.JobStatsExport), nil)
+		}
+	}
+}
+
+// probe samples the live heap of the process and the lock waits of the subsystems.
+type probe struct {
+	heap            []uint64
+	longestLockWait time.Duration
+}
+
+func newProbe() *probe {
+	return &probe{}
+}
+
+// run takes a sample once per interval until ctx is cancelled.
+func (p *probe) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Collected first, so that the sample is the live heap rather than the garbage not swept yet.
+			runtime.GC()
+
+			var stats runtime.MemStats
+			runtime.ReadMemStats(&stats)
+			p.heap = append(p.heap, stats.HeapAlloc)
+
+			var wait float64
+
+			err := testDB.GetContext(ctx, &wait, `
+				SELECT COALESCE(MAX(EXTRACT(EPOCH FROM NOW() - query_start)), 0) FROM pg_stat_activity
+				WHERE application_name = $1 AND wait_event_type = 'Lock'`, serviceApplicationName)
+			if err == nil {
+				p.longestLockWait = max(p.longestLockWait, time.Duration(wait*float64(time.Second)))
+			}
+		}
+	}
+}
+
+func count(t *testing.T, query string) int {
+	t.Helper()
+
+	var n int
+	require.NoError(t, testDB.Get(&n, query))
+
+	return n
+}
+
+// seconds runs a query returning an interval and returns it in seconds.
+func seconds(t *testing.T, query string) float64 {
+	t.Helper()
+
+	var s float64
+	require.NoError(t, testDB.Get(&s, "SELECT COALESCE(EXTRACT(EPOCH FROM ("+query+")), 0)"))
+
+	return s
+}
+
+func goroutineDump() string {
+	var buf bytes.Buffer
+	_ = pprof.Lookup("goroutine").WriteTo(&buf, 1)
+
+	return buf.String()
+}
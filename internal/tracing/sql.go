@@ -0,0 +1,161 @@
+package tracing
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WrapConnector traces the queries and the transactions of the connections of c, whose driver must support
+// the context-aware interfaces of database/sql/driver, as lib/pq does. A span is named by the
+// operation of its statement, e.g. SELECT, and carries the statement; the arguments are left out, as they may
+// hold personal data. Errors of the driver are passed on unchanged.
+func WrapConnector(c driver.Connector, system string) driver.Connector {
+	return &connector{Connector: c, system: system}
+}
+
+type connector struct {
+	driver.Connector
+	system string
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	cn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conn{Conn: cn, system: c.system}, nil
+}
+
+// conn traces the statements executed directly on the connection. Prepared statements are passed through
+// untraced; database/sql prepares them only when the driver cannot execute a statement directly.
+type conn struct {
+	driver.Conn
+	system string
+}
+
+func (c *conn) startQuery(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	operation = strings.ToUpper(operation)
+
+	return Start(ctx, operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		semconv.DBSystemNameKey.String(c.system),
+		semconv.DBOperationName(operation),
+		semconv.DBQueryText(query),
+	))
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := c.startQuery(ctx, query)
+	rows, err := queryer.QueryContext(ctx, query, args)
+	End(span, skipped(err))
+
+	return rows, err
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := c.startQuery(ctx, query)
+	result, err := execer.ExecContext(ctx, query, args)
+	End(span, skipped(err))
+
+	return result, err
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+
+	return c.Prepare(query)
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	beginner, ok := c.Conn.(driver.ConnBeginTx)
+	if !ok {
+		return nil, errors.New("tracing: the driver does not support BeginTx")
+	}
+
+	beginCtx, span := Start(ctx, "BEGIN", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNameKey.String(c.system)))
+	tx, err := beginner.BeginTx(beginCtx, opts)
+	End(span, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction{Tx: tx, ctx: ctx, system: c.system}, nil
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c *conn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+
+	return true
+}
+
+// transaction traces the end of a transaction next to its BEGIN, in the trace of the operation that began it.
+type transaction struct {
+	driver.Tx
+	ctx    context.Context
+	system string
+}
+
+func (t *transaction) Commit() error {
+	_, span := Start(t.ctx, "COMMIT", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNameKey.String(t.system)))
+	err := t.Tx.Commit()
+	End(span, err)
+
+	return err
+}
+
+func (t *transaction) Rollback() error {
+	_, span := Start(t.ctx, "ROLLBACK", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNameKey.String(t.system)))
+	err := t.Tx.Rollback()
+	End(span, err)
+
+	return err
+}
+
+// skipped hides driver.ErrSkip, with which database/sql retries the statement as a prepared one, from the span.
+func skipped(err error) error {
+	if errors.Is(err, driver.ErrSkip) {
+		return nil
+	}
+
+	return err
+}
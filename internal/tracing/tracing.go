@@ -0,0 +1,66 @@
+// Package tracing sets up the OpenTelemetry traces of the service: the spans of the HTTP requests, of the
+// service operations and of the SQL queries, exported over OTLP/HTTP, and the trace IDs in the log records,
+// so that the logs of a request can be found from its trace and the other way round.
+//
+// Until Setup is called the global tracer provider of OpenTelemetry is a no-op one, and the spans cost next
+// to nothing; the instrumented code does not need to know whether tracing is on.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer of the service.
+const instrumentationName = "github.com/YusovID/pr-reviewer-service"
+
+// Setup exports the traces to the collector of cfg and makes them the global tracer provider, along with
+// the W3C trace context propagation. The returned function flushes the buffered spans and stops the export;
+// it is to be called on shutdown.
+func Setup(ctx context.Context, cfg config.Tracing, version string) (func(context.Context) error, error) {
+	const op = "internal.tracing.Setup"
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to create exporter: %w", op, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(version),
+		)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// Start starts a span of the service, named e.g. by the op of the operation it covers.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End ends span, marking it failed with err if err is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,120 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// recordSpans makes a recorder the global tracer provider for the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+// dsnConnector opens the connections of a driver, as sql.Open does.
+type dsnConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func TestWrapConnector(t *testing.T) {
+	recorder := recordSpans(t)
+
+	mockDB, mock, err := sqlmock.NewWithDSN("tracing-test")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	db := sqlx.NewDb(sql.OpenDB(WrapConnector(dsnConnector{driver: mockDB.Driver(), dsn: "tracing-test"}, "postgresql")), "postgres")
+	t.Cleanup(func() { _ = db.Close() })
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT name FROM teams").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("backend"))
+	mock.ExpectExec("(?i)update teams").WillReturnError(errors.New("deadlock detected"))
+	mock.ExpectRollback()
+
+	ctx, parent := Start(context.Background(), "internal.service.team.RenameTeam")
+
+	tx, err := db.BeginTxx(ctx, nil)
+	require.NoError(t, err)
+
+	var name string
+	require.NoError(t, tx.GetContext(ctx, &name, "SELECT name FROM teams WHERE id = $1", 1))
+	assert.Equal(t, "backend", name)
+
+	_, err = tx.ExecContext(ctx, "  update teams SET name = 'x'")
+	assert.EqualError(t, err, "deadlock detected", "the errors of the driver are passed on unchanged")
+
+	require.NoError(t, tx.Rollback())
+	parent.End()
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 5)
+
+	names := make([]string, 0, len(spans))
+	for _, span := range spans[:4] {
+		names = append(names, span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID(), span.Name())
+	}
+
+	assert.Equal(t, []string{"BEGIN", "SELECT", "UPDATE", "ROLLBACK"}, names)
+	assert.Contains(t, spans[1].Attributes(), semconv.DBQueryText("SELECT name FROM teams WHERE id = $1"))
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+}
+
+func TestLogHandler(t *testing.T) {
+	recordSpans(t)
+
+	var buf bytes.Buffer
+	log := slog.New(NewLogHandler(slog.NewJSONHandler(&buf, nil))).With(slog.String("op", "test"))
+
+	ctx, span := Start(context.Background(), "request")
+	log.InfoContext(ctx, "traced")
+	span.End()
+
+	log.Info("untraced")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var traced, untraced map[string]any
+	require.NoError(t, json.Unmarshal(lines[0], &traced))
+	require.NoError(t, json.Unmarshal(lines[1], &untraced))
+
+	assert.Equal(t, span.SpanContext().TraceID().String(), traced["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), traced["span_id"])
+	assert.Equal(t, "test", traced["op"])
+	assert.NotContains(t, untraced, "trace_id")
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+)
+
+// WithAPIKeys serves the /admin/apiKeys endpoints with ks and lets WithAuth accept the stored API keys.
+func WithAPIKeys(ks service.APIKeyService) ServerOption {
+	return func(s *Server) {
+		s.apiKeys = ks
+	}
+}
+
+func (s *Server) GetAdminApiKeys(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.GetAdminApiKeys"
+
+	resp, err := s.apiKeys.ListAPIKeys(r.Context())
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) PostAdminApiKeys(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostAdminApiKeys"
+
+	var req createAPIKeyRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	created, err := s.apiKeys.CreateAPIKey(r.Context(), req.Name, req.Scopes, req.ExpiresAt, req.UserID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusCreated, created)
+}
+
+func (s *Server) DeleteAdminApiKeysKeyId(w http.ResponseWriter, r *http.Request, keyID int64) {
+	const op = "internal.transport.http.DeleteAdminApiKeysKeyId"
+
+	if err := s.apiKeys.DeleteAPIKey(r.Context(), keyID); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestServer_AdminAPIKeys(t *testing.T) {
+	createdAt := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	key := api.ApiKey{KeyId: 4, Name: "ci", Scopes: []api.ApiKeyScope{api.ApiKeyScopeRead, api.ApiKeyScopeWrite}, CreatedAt: createdAt}
+	total := 1
+
+	keysMock := new(APIKeyServiceMock)
+	keysMock.On("CreateAPIKey", mock.Anything, "ci", []string{"read", "write"}, (*time.Time)(nil), (*string)(nil)).
+		Return(&api.ApiKeyCreatedResponse{ApiKey: key, Key: "prk_secret"}, nil).Once()
+	keysMock.On("CreateAPIKey", mock.Anything, "ci", []string{"deploy"}, (*time.Time)(nil), (*string)(nil)).Return(nil, apperrors.ErrValidation).Once()
+	keysMock.On("ListAPIKeys", mock.Anything).
+		Return(&api.ListApiKeysResponse{Items: []api.ApiKey{key}, TotalEstimate: &total}, nil).Once()
+	keysMock.On("DeleteAPIKey", mock.Anything, int64(4)).Return(nil).Once()
+	keysMock.On("DeleteAPIKey", mock.Anything, int64(9)).Return(apperrors.ErrNotFound).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithAPIKeys(keysMock))
+	router := api.Handler(server)
+
+	expectedKey := `{"key_id":4,"name":"ci","scopes":["read","write"],"created_at":"2026-03-02T09:00:00Z"}`
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/apiKeys",
+		strings.NewReader(`{"name":"ci","scopes":["read","write"]}`)))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.JSONEq(t, `{"api_key":`+expectedKey+`,"key":"prk_secret"}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/apiKeys", strings.NewReader(`{"name":"ci","scopes":["deploy"]}`)))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/apiKeys", strings.NewReader(`{"name":"ci","scopes":[]}`)))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/apiKeys", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"items":[`+expectedKey+`],"next_cursor":null,"total_estimate":1}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/admin/apiKeys/4", nil))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/admin/apiKeys/9", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	keysMock.AssertExpectations(t)
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Reasons of rejected requests recorded by the authorization audit.
+const (
+	authFailureUnauthenticated = "unauthenticated"
+	authFailureForbidden       = "forbidden"
+)
+
+const (
+	defaultAuthBurstThreshold = 20
+	defaultAuthBurstWindow    = time.Minute
+)
+
+var (
+	authFailuresTotal      = promauto.NewCounterVec(metrics.AuthFailures.CounterOpts(), metrics.AuthFailures.Labels)
+	authFailureBurstsTotal = promauto.NewCounterVec(metrics.AuthFailureBursts.CounterOpts(), metrics.AuthFailureBursts.Labels)
+)
+
+// burstDetector counts events per key in fixed time windows and reports the moment
+// the count within a window reaches the threshold.
+type burstDetector struct {
+	mu        sync.Mutex
+	threshold int
+	window    time.Duration
+	now       func() time.Time
+	windows   map[string]*burstWindow
+}
+
+type burstWindow struct {
+	start time.Time
+	count int
+}
+
+func newBurstDetector(threshold int, window time.Duration) *burstDetector {
+	return &burstDetector{
+		threshold: threshold,
+		window:    window,
+		now:       time.Now,
+		windows:   make(map[string]*burstWindow),
+	}
+}
+
+// observe registers an event and returns true once per window, when the event count reaches the threshold.
+func (d *burstDetector) observe(key string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+
+	w, ok := d.windows[key]
+	if !ok || now.Sub(w.start) >= d.window {
+		w = &burstWindow{start: now}
+		d.windows[key] = w
+	}
+
+	w.count++
+
+	return w.count == d.threshold
+}
+
+// recordAuthFailure audits a request rejected for the given reason and warns when such rejections spike,
+// which usually means a misconfigured integrator or an attack.
+func (s *Server) recordAuthFailure(r *http.Request, reason string) {
+	authFailuresTotal.WithLabelValues(r.URL.Path, r.Method, reason).Inc()
+
+	s.log.Warn("request rejected by auth",
+		slog.String("request_id", getRequestID(r.Context())),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.String("remote_addr", r.RemoteAddr),
+		slog.String("reason", reason),
+	)
+
+	if s.authBursts.observe(reason) {
+		authFailureBurstsTotal.WithLabelValues(reason).Inc()
+
+		s.log.Warn("burst of auth failures detected",
+			slog.String("reason", reason),
+			slog.Int("threshold", s.authBursts.threshold),
+			slog.String("window", s.authBursts.window.String()),
+		)
+	}
+}
+
+// recordTeamRename audits a team rename, so that the old name of a team can be traced back after it is reused.
+func (s *Server) recordTeamRename(r *http.Request, oldName string, team *api.Team) {
+	s.log.Info("team renamed",
+		slog.String("request_id", getRequestID(r.Context())),
+		slog.String("remote_addr", r.RemoteAddr),
+		slog.Int("team_id", *team.TeamId),
+		slog.String("old_team_name", oldName),
+		slog.String("new_team_name", team.TeamName),
+	)
+}
+
+// recordTeamDeletion audits a team deletion, so that a deleted team can be restored by its ID after its name is reused.
+func (s *Server) recordTeamDeletion(r *http.Request, teamName string, teamID int) {
+	s.log.Info("team deleted",
+		slog.String("request_id", getRequestID(r.Context())),
+		slog.String("remote_addr", r.RemoteAddr),
+		slog.Int("team_id", teamID),
+		slog.String("team_name", teamName),
+	)
+}
+
+// auditAuth records responses that reject the caller as unauthenticated or forbidden.
+// The reason is derived from the status code.
+func (s *Server) auditAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wrapper := newResponseWriterWrapper(w)
+		next.ServeHTTP(wrapper, r)
+
+		switch wrapper.statusCode {
+		case http.StatusUnauthorized:
+			s.recordAuthFailure(r, authFailureUnauthenticated)
+		case http.StatusForbidden:
+			s.recordAuthFailure(r, authFailureForbidden)
+		}
+	})
+}
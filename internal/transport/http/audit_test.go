@@ -0,0 +1,67 @@
+package http
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBurstDetector(t *testing.T) {
+	now := time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC)
+
+	d := newBurstDetector(3, time.Minute)
+	d.now = func() time.Time { return now }
+
+	assert.False(t, d.observe("forbidden"))
+	assert.False(t, d.observe("forbidden"))
+	assert.False(t, d.observe("unauthenticated"), "keys are counted separately")
+	assert.True(t, d.observe("forbidden"), "reaching the threshold reports a burst")
+	assert.False(t, d.observe("forbidden"), "a burst is reported once per window")
+
+	now = now.Add(time.Minute)
+
+	assert.False(t, d.observe("forbidden"), "a new window starts from zero")
+	assert.False(t, d.observe("forbidden"))
+	assert.True(t, d.observe("forbidden"))
+}
+
+func TestAuditAuthMiddleware(t *testing.T) {
+	var logBuffer bytes.Buffer
+
+	server := &Server{
+		log:        slog.New(slog.NewTextHandler(&logBuffer, nil)),
+		authBursts: newBurstDetector(2, time.Minute),
+	}
+
+	status := http.StatusOK
+	handler := server.auditAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+
+	serve := func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/audit/test", nil))
+	}
+
+	failures := authFailuresTotal.WithLabelValues("/audit/test", http.MethodPost, authFailureForbidden)
+	bursts := authFailureBurstsTotal.WithLabelValues(authFailureForbidden)
+	failuresBefore := testutil.ToFloat64(failures)
+	burstsBefore := testutil.ToFloat64(bursts)
+
+	serve()
+	assert.Equal(t, failuresBefore, testutil.ToFloat64(failures), "successful requests are not audited")
+
+	status = http.StatusForbidden
+	serve()
+	serve()
+
+	assert.Equal(t, failuresBefore+2, testutil.ToFloat64(failures))
+	assert.Equal(t, burstsBefore+1, testutil.ToFloat64(bursts))
+	assert.Contains(t, logBuffer.String(), "reason=forbidden")
+	assert.Contains(t, logBuffer.String(), "burst of auth failures detected")
+}
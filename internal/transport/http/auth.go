@@ -0,0 +1,320 @@
+package http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Outcomes of the requests made with read tokens, counted by the read_token_requests_total metric.
+const (
+	readTokenAllowed     = "allowed"
+	readTokenRateLimited = "rate_limited"
+)
+
+var readTokenRequestsTotal = promauto.NewCounterVec(metrics.ReadTokenRequests.CounterOpts(), metrics.ReadTokenRequests.Labels)
+
+// authenticator holds the credentials the API accepts besides the stored API keys and the read tokens.
+type authenticator struct {
+	serviceKeys [][]byte
+	apiKeys     []staticAPIKey
+	limiter     *rateLimiter
+}
+
+// staticAPIKey is an API key of the configuration.
+type staticAPIKey struct {
+	key    []byte
+	scopes []domain.APIKeyScope
+}
+
+// WithAuth requires every API request to carry a service key, an API key of a scope the operation belongs to or,
+// for reads, a read token as a bearer token. Without it the API is open.
+func WithAuth(cfg config.Auth) ServerOption {
+	return func(s *Server) {
+		keys := make([][]byte, len(cfg.ServiceKeys))
+		for i, key := range cfg.ServiceKeys {
+			keys[i] = []byte(key)
+		}
+
+		// config.Load has validated the API keys; should they still be malformed, none of them is accepted.
+		static, _ := cfg.StaticAPIKeys()
+
+		apiKeys := make([]staticAPIKey, len(static))
+		for i, key := range static {
+			apiKeys[i] = staticAPIKey{key: []byte(key.Key), scopes: key.Scopes}
+		}
+
+		s.auth = &authenticator{
+			serviceKeys: keys,
+			apiKeys:     apiKeys,
+			limiter:     newRateLimiter(cfg.ReadTokenRateLimit, cfg.ReadTokenRateWindow),
+		}
+	}
+}
+
+// isServiceKey reports whether token is one of the service keys, comparing in constant time.
+func (a *authenticator) isServiceKey(token string) bool {
+	found := false
+
+	for _, key := range a.serviceKeys {
+		if subtle.ConstantTimeCompare(key, []byte(token)) == 1 {
+			found = true
+		}
+	}
+
+	return found
+}
+
+// staticAPIKeyScopes returns the scopes of the configured API key token, comparing in constant time.
+func (a *authenticator) staticAPIKeyScopes(token string) ([]domain.APIKeyScope, bool) {
+	var scopes []domain.APIKeyScope
+
+	found := false
+
+	for _, key := range a.apiKeys {
+		if subtle.ConstantTimeCompare(key.key, []byte(token)) == 1 {
+			scopes, found = key.scopes, true
+		}
+	}
+
+	return scopes, found
+}
+
+// requiredScope returns the API key scope an operation belongs to.
+func requiredScope(r *http.Request) domain.APIKeyScope {
+	switch {
+	case hasPathPrefix(r.URL.Path, "/admin"):
+		return domain.ScopeAdmin
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return domain.ScopeRead
+	default:
+		return domain.ScopeWrite
+	}
+}
+
+// requiredRole returns the role a user needs for an operation: admin for /admin, creating, deactivating,
+// reactivating, deleting and restoring teams, deleting, restoring and transferring users and setting roles,
+// member for the reads and lead for every other change.
+func requiredRole(r *http.Request) domain.UserRole {
+	switch {
+	case hasPathPrefix(r.URL.Path, "/admin"), hasPathPrefix(r.URL.Path, "/team/add"),
+		hasPathPrefix(r.URL.Path, "/team/deactivate"), hasPathPrefix(r.URL.Path, "/team/reactivate"),
+		hasPathPrefix(r.URL.Path, "/team/restore"), hasPathPrefix(r.URL.Path, "/users/restore"),
+		hasPathPrefix(r.URL.Path, "/users/setRole"), hasPathPrefix(r.URL.Path, "/users/transfer"):
+		return domain.RoleAdmin
+	case r.Method == http.MethodDelete && (hasPathPrefix(r.URL.Path, "/team") || hasPathPrefix(r.URL.Path, "/users")):
+		return domain.RoleAdmin
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return domain.RoleMember
+	default:
+		return domain.RoleLead
+	}
+}
+
+// authenticate checks the bearer token of an API operation. The inbound webhooks verify their own signatures
+// and the sign-in endpoints are where a token is obtained, so neither needs one. A service key opens every
+// operation; an API key, configured or stored, opens the operations of its scopes, and a stored key bound to a user
+// only those the current role of the user allows as well; an ID token of the identity provider opens the operations
+// the role of the user its subject is mapped to allows; a read token opens only the GET and HEAD operations outside
+// /admin and is subject to the rate limit of the read tokens.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hasPathPrefix(r.URL.Path, "/webhooks") || hasPathPrefix(r.URL.Path, "/auth") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := bearerToken(r)
+		if !ok {
+			s.respondAPIError(w, http.StatusUnauthorized, api.UNAUTHORIZED, "missing bearer token")
+			return
+		}
+
+		if s.auth.isServiceKey(token) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var userID *string
+
+		scopes, ok := s.auth.staticAPIKeyScopes(token)
+		if !ok && s.oidcEnabled() && isIDToken(token) {
+			s.authenticateIDToken(w, r, next, token)
+			return
+		}
+
+		if !ok && s.apiKeys != nil {
+			key, err := s.apiKeys.AuthenticateAPIKey(r.Context(), token)
+			if err != nil && !errors.Is(err, apperrors.ErrInvalidToken) {
+				s.handleServiceError(w, r, "internal.transport.http.authenticate", err)
+				return
+			}
+
+			if err == nil {
+				scopes, userID, ok = key.Scopes, key.UserID, true
+			}
+		}
+
+		if ok {
+			if scope := requiredScope(r); !slices.Contains(scopes, scope) {
+				s.respondAPIError(w, http.StatusForbidden, api.FORBIDDEN, fmt.Sprintf("api key lacks the %s scope", scope))
+				return
+			}
+
+			if userID != nil && !s.authorizeRole(w, r, *userID) {
+				return
+			}
+
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		if s.readTokens == nil {
+			s.respondAPIError(w, http.StatusUnauthorized, api.UNAUTHORIZED, apperrors.ErrInvalidToken.Error())
+			return
+		}
+
+		readToken, err := s.readTokens.AuthenticateReadToken(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrInvalidToken) {
+				s.respondAPIError(w, http.StatusUnauthorized, api.UNAUTHORIZED, apperrors.ErrInvalidToken.Error())
+			} else {
+				s.handleServiceError(w, r, "internal.transport.http.authenticate", err)
+			}
+
+			return
+		}
+
+		if hasPathPrefix(r.URL.Path, "/admin") || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			s.respondAPIError(w, http.StatusForbidden, api.FORBIDDEN, "read tokens may only read")
+			return
+		}
+
+		if allowed, retryAfter := s.auth.limiter.allow(readToken.ID); !allowed {
+			readTokenRequestsTotal.WithLabelValues(readTokenRateLimited).Inc()
+
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			s.respondAPIError(w, http.StatusTooManyRequests, api.RATELIMITED, "read token rate limit exceeded")
+
+			return
+		}
+
+		readTokenRequestsTotal.WithLabelValues(readTokenAllowed).Inc()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticateIDToken checks an ID token of the identity provider and the role of the user its subject is mapped to.
+func (s *Server) authenticateIDToken(w http.ResponseWriter, r *http.Request, next http.Handler, token string) {
+	const op = "internal.transport.http.authenticateIDToken"
+
+	// The nonce was checked at the sign-in; the token is presented again on every request.
+	claims, err := s.oidc.Verify(r.Context(), token, "")
+	if err != nil {
+		s.respondOIDCError(w, r, op, err)
+		return
+	}
+
+	userID, err := s.oidcSubjects.ResolveOIDCSubject(r.Context(), claims.Subject)
+	if err != nil {
+		s.respondOIDCError(w, r, op, err)
+		return
+	}
+
+	if s.authorizeRole(w, r, userID) {
+		next.ServeHTTP(w, r)
+	}
+}
+
+// authorizeRole reports whether the current role of a user allows the operation, answering with 403 if it does not.
+// The role is looked up on every request, so that a changed role takes effect at once.
+func (s *Server) authorizeRole(w http.ResponseWriter, r *http.Request, userID string) bool {
+	role, err := s.userService.GetRole(r.Context(), userID)
+	if err != nil {
+		s.handleServiceError(w, r, "internal.transport.http.authorizeRole", err)
+		return false
+	}
+
+	if required := requiredRole(r); !role.Includes(required) {
+		s.respondAPIError(w, http.StatusForbidden, api.FORBIDDEN, fmt.Sprintf("the %s role is required", required))
+		return false
+	}
+
+	return true
+}
+
+// hasPathPrefix reports whether path, with or without the /v1 prefix, lies under prefix.
+func hasPathPrefix(path, prefix string) bool {
+	path = strings.TrimPrefix(path, "/v1")
+
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// bearerToken returns the token of the Authorization header.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+
+	return token, token != ""
+}
+
+// rateLimiter admits up to limit requests per key in fixed time windows.
+type rateLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	now     func() time.Time
+	windows map[int64]*burstWindow
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		window:  window,
+		now:     time.Now,
+		windows: make(map[int64]*burstWindow),
+	}
+}
+
+// allow counts a request of key and reports whether it is within the limit. A rejected request
+// is told how long to wait for the next window, at least a second.
+func (l *rateLimiter) allow(key int64) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &burstWindow{start: now}
+		l.windows[key] = w
+	}
+
+	if w.count >= l.limit {
+		return false, max(w.start.Add(l.window).Sub(now), time.Second)
+	}
+
+	w.count++
+
+	return true, 0
+}
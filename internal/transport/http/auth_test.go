@@ -0,0 +1,259 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Auth(t *testing.T) {
+	const serviceKey = "service-key-0123456789"
+
+	team := &api.Team{TeamName: "backend", Members: []api.TeamMember{}}
+
+	teamsMock := new(TeamServiceMock)
+	teamsMock.On("GetTeam", mock.Anything, "backend").Return(team, nil)
+
+	tokensMock := new(ReadTokenServiceMock)
+	tokensMock.On("AuthenticateReadToken", mock.Anything, "prr_dashboard").Return(&domain.ReadToken{ID: 1, Name: "dashboard"}, nil)
+	tokensMock.On("AuthenticateReadToken", mock.Anything, "prr_revoked").Return(nil, apperrors.ErrInvalidToken)
+
+	routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamsMock, nil, nil,
+		WithReadTokens(tokensMock),
+		WithAuth(config.Auth{ServiceKeys: []string{serviceKey}, ReadTokenRateLimit: 2, ReadTokenRateWindow: time.Minute}),
+	).Routes()
+
+	serve := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{}`))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := serve(http.MethodGet, "/team/get?team_name=backend", "")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.JSONEq(t, `{"error":{"code":"UNAUTHORIZED","message":"missing bearer token"}}`, rr.Body.String())
+
+	rr = serve(http.MethodGet, "/team/get?team_name=backend", "prr_revoked")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = serve(http.MethodGet, "/v1/team/get?team_name=backend", serviceKey)
+	assert.Equal(t, http.StatusOK, rr.Code, "a service key opens every operation")
+
+	rr = serve(http.MethodGet, "/admin/readTokens", "prr_dashboard")
+	assert.Equal(t, http.StatusForbidden, rr.Code, "a read token does not open the administration")
+
+	rr = serve(http.MethodPost, "/team/add", "prr_dashboard")
+	assert.Equal(t, http.StatusForbidden, rr.Code, "a read token does not open mutations")
+
+	rr = serve(http.MethodPost, "/webhooks/github", "")
+	assert.NotEqual(t, http.StatusUnauthorized, rr.Code, "the webhooks verify their own signatures")
+
+	allowed := readTokenRequestsTotal.WithLabelValues(readTokenAllowed)
+	limited := readTokenRequestsTotal.WithLabelValues(readTokenRateLimited)
+	allowedBefore, limitedBefore := testutil.ToFloat64(allowed), testutil.ToFloat64(limited)
+
+	for range 2 {
+		rr = serve(http.MethodGet, "/team/get?team_name=backend", "prr_dashboard")
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+
+	rr = serve(http.MethodGet, "/team/get?team_name=backend", "prr_dashboard")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.JSONEq(t, `{"error":{"code":"RATE_LIMITED","message":"read token rate limit exceeded"}}`, rr.Body.String())
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+
+	rr = serve(http.MethodGet, "/team/get?team_name=backend", serviceKey)
+	assert.Equal(t, http.StatusOK, rr.Code, "service keys are not rate limited")
+
+	assert.Equal(t, allowedBefore+2, testutil.ToFloat64(allowed))
+	assert.Equal(t, limitedBefore+1, testutil.ToFloat64(limited))
+}
+
+func TestServer_AuthAPIKeys(t *testing.T) {
+	const ciKey = "ci-key-0123456789abcdef"
+
+	team := &api.Team{TeamName: "backend", Members: []api.TeamMember{}}
+
+	teamsMock := new(TeamServiceMock)
+	teamsMock.On("GetTeam", mock.Anything, "backend").Return(team, nil)
+
+	keysMock := new(APIKeyServiceMock)
+	keysMock.On("AuthenticateAPIKey", mock.Anything, "prk_ops").
+		Return(&domain.APIKey{ID: 1, Name: "ops", Scopes: []domain.APIKeyScope{domain.ScopeAdmin}}, nil)
+	keysMock.On("AuthenticateAPIKey", mock.Anything, "prk_broken").Return(nil, errors.New("connection refused"))
+	keysMock.On("AuthenticateAPIKey", mock.Anything, mock.Anything).Return(nil, apperrors.ErrInvalidToken)
+	keysMock.On("ListAPIKeys", mock.Anything).Return(&api.ListApiKeysResponse{Items: []api.ApiKey{}}, nil)
+
+	tokensMock := new(ReadTokenServiceMock)
+	tokensMock.On("AuthenticateReadToken", mock.Anything, "prr_dashboard").Return(&domain.ReadToken{ID: 1, Name: "dashboard"}, nil)
+	tokensMock.On("AuthenticateReadToken", mock.Anything, mock.Anything).Return(nil, apperrors.ErrInvalidToken)
+
+	routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamsMock, nil, nil,
+		WithAPIKeys(keysMock),
+		WithReadTokens(tokensMock),
+		WithAuth(config.Auth{APIKeys: []string{"ci:read+write:" + ciKey}, ReadTokenRateLimit: 60, ReadTokenRateWindow: time.Minute}),
+	).Routes()
+
+	serve := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := serve(http.MethodGet, "/team/get?team_name=backend", ciKey)
+	assert.Equal(t, http.StatusOK, rr.Code, "the read scope opens reads")
+
+	rr = serve(http.MethodPost, "/team/add", ciKey)
+	assert.Equal(t, http.StatusBadRequest, rr.Code, "the write scope opens mutations")
+
+	rr = serve(http.MethodGet, "/admin/apiKeys", ciKey)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.JSONEq(t, `{"error":{"code":"FORBIDDEN","message":"api key lacks the admin scope"}}`, rr.Body.String())
+
+	rr = serve(http.MethodGet, "/v1/admin/apiKeys", "prk_ops")
+	assert.Equal(t, http.StatusOK, rr.Code, "the admin scope opens the administration")
+
+	rr = serve(http.MethodGet, "/team/get?team_name=backend", "prk_ops")
+	assert.Equal(t, http.StatusForbidden, rr.Code, "the admin scope does not imply the read scope")
+	assert.JSONEq(t, `{"error":{"code":"FORBIDDEN","message":"api key lacks the read scope"}}`, rr.Body.String())
+
+	rr = serve(http.MethodGet, "/team/get?team_name=backend", "prr_dashboard")
+	assert.Equal(t, http.StatusOK, rr.Code, "the read tokens are still accepted")
+
+	rr = serve(http.MethodGet, "/team/get?team_name=backend", "prk_revoked")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = serve(http.MethodGet, "/team/get?team_name=backend", "prk_broken")
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+func TestServer_AuthUserRoles(t *testing.T) {
+	team := &api.Team{TeamName: "backend", Members: []api.TeamMember{}}
+
+	teamsMock := new(TeamServiceMock)
+	teamsMock.On("GetTeam", mock.Anything, "backend").Return(team, nil)
+
+	allScopes := []domain.APIKeyScope{domain.ScopeRead, domain.ScopeWrite, domain.ScopeAdmin}
+	keyOf := func(userID string) *domain.APIKey {
+		return &domain.APIKey{ID: 1, Name: userID, Scopes: allScopes, UserID: &userID}
+	}
+
+	keysMock := new(APIKeyServiceMock)
+	keysMock.On("AuthenticateAPIKey", mock.Anything, "prk_member").Return(keyOf("u-member"), nil)
+	keysMock.On("AuthenticateAPIKey", mock.Anything, "prk_lead").Return(keyOf("u-lead"), nil)
+	keysMock.On("AuthenticateAPIKey", mock.Anything, "prk_admin").Return(keyOf("u-admin"), nil)
+	keysMock.On("AuthenticateAPIKey", mock.Anything, "prk_broken").Return(keyOf("u-broken"), nil)
+	keysMock.On("ListAPIKeys", mock.Anything).Return(&api.ListApiKeysResponse{Items: []api.ApiKey{}}, nil)
+
+	usersMock := new(UserServiceMock)
+	usersMock.On("GetRole", mock.Anything, "u-member").Return(domain.RoleMember, nil)
+	usersMock.On("GetRole", mock.Anything, "u-lead").Return(domain.RoleLead, nil)
+	usersMock.On("GetRole", mock.Anything, "u-admin").Return(domain.RoleAdmin, nil)
+	usersMock.On("GetRole", mock.Anything, "u-broken").Return(domain.UserRole(""), errors.New("connection refused"))
+
+	routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamsMock, usersMock, nil,
+		WithAPIKeys(keysMock),
+		WithAuth(config.Auth{ReadTokenRateLimit: 60, ReadTokenRateWindow: time.Minute}),
+	).Routes()
+
+	serve := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	// The handlers reject the empty bodies, so 400 means the request got past the role check.
+	testCases := []struct {
+		name         string
+		method, path string
+		token        string
+		expectedCode int
+	}{
+		{name: "A member reads", method: http.MethodGet, path: "/team/get?team_name=backend", token: "prk_member", expectedCode: http.StatusOK},
+		{name: "A member may not reassign", method: http.MethodPost, path: "/pullRequest/reassign", token: "prk_member", expectedCode: http.StatusForbidden},
+		{name: "A lead reassigns", method: http.MethodPost, path: "/pullRequest/reassign", token: "prk_lead", expectedCode: http.StatusBadRequest},
+		{name: "A lead may not create teams", method: http.MethodPost, path: "/team/add", token: "prk_lead", expectedCode: http.StatusForbidden},
+		{name: "A lead may not deactivate teams", method: http.MethodPost, path: "/v1/team/deactivate", token: "prk_lead", expectedCode: http.StatusForbidden},
+		{name: "A lead may not reactivate teams", method: http.MethodPost, path: "/v1/team/reactivate", token: "prk_lead", expectedCode: http.StatusForbidden},
+		{name: "A lead may not delete teams", method: http.MethodDelete, path: "/team?team_name=backend", token: "prk_lead", expectedCode: http.StatusForbidden},
+		{name: "A lead may not delete users", method: http.MethodDelete, path: "/v1/users?user_id=u1", token: "prk_lead", expectedCode: http.StatusForbidden},
+		{name: "A lead may not restore teams", method: http.MethodPost, path: "/team/restore", token: "prk_lead", expectedCode: http.StatusForbidden},
+		{name: "A lead may not restore users", method: http.MethodPost, path: "/users/restore", token: "prk_lead", expectedCode: http.StatusForbidden},
+		{name: "A lead may not transfer users", method: http.MethodPost, path: "/users/transfer", token: "prk_lead", expectedCode: http.StatusForbidden},
+		{name: "A lead may not administer", method: http.MethodGet, path: "/admin/apiKeys", token: "prk_lead", expectedCode: http.StatusForbidden},
+		{name: "An admin creates teams", method: http.MethodPost, path: "/team/add", token: "prk_admin", expectedCode: http.StatusBadRequest},
+		{name: "An admin sets roles", method: http.MethodPost, path: "/users/setRole", token: "prk_admin", expectedCode: http.StatusBadRequest},
+		{name: "An admin restores users", method: http.MethodPost, path: "/users/restore", token: "prk_admin", expectedCode: http.StatusBadRequest},
+		{name: "An admin administers", method: http.MethodGet, path: "/admin/apiKeys", token: "prk_admin", expectedCode: http.StatusOK},
+		{name: "An admin reads", method: http.MethodGet, path: "/team/get?team_name=backend", token: "prk_admin", expectedCode: http.StatusOK},
+		{name: "The role cannot be looked up", method: http.MethodGet, path: "/team/get?team_name=backend", token: "prk_broken", expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := serve(tc.method, tc.path, tc.token)
+			assert.Equal(t, tc.expectedCode, rr.Code, rr.Body.String())
+		})
+	}
+
+	rr := serve(http.MethodPost, "/team/add", "prk_member")
+	assert.JSONEq(t, `{"error":{"code":"FORBIDDEN","message":"the admin role is required"}}`, rr.Body.String())
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+
+	l := newRateLimiter(2, time.Minute)
+	l.now = func() time.Time { return now }
+
+	for range 2 {
+		allowed, _ := l.allow(1)
+		require.True(t, allowed)
+	}
+
+	allowed, retryAfter := l.allow(1)
+	assert.False(t, allowed)
+	assert.Equal(t, time.Minute, retryAfter)
+
+	allowed, _ = l.allow(2)
+	assert.True(t, allowed, "every token has its own limit")
+
+	now = now.Add(59*time.Second + 500*time.Millisecond)
+
+	allowed, retryAfter = l.allow(1)
+	assert.False(t, allowed)
+	assert.Equal(t, time.Second, retryAfter, "the wait is at least a second")
+
+	now = now.Add(time.Second)
+
+	allowed, _ = l.allow(1)
+	assert.True(t, allowed, "the next window starts afresh")
+}
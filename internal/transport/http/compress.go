@@ -0,0 +1,195 @@
+package http
+
+import (
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// compression gzips the responses of the allowed media types from a minimal size on.
+type compression struct {
+	minSize      int
+	contentTypes []string
+	writers      sync.Pool
+}
+
+// WithCompression gzips the responses of cfg to the clients that accept it, e.g. the large /stats
+// and list responses.
+func WithCompression(cfg config.Compression) ServerOption {
+	return func(s *Server) {
+		s.compression = &compression{
+			minSize:      cfg.MinSize,
+			contentTypes: cfg.ContentTypes,
+			writers: sync.Pool{New: func() any {
+				// The level is checked by config.Compression.Validate.
+				w, _ := gzip.NewWriterLevel(nil, cfg.Level)
+				return w
+			}},
+		}
+	}
+}
+
+// compresses reports whether the responses of contentType are compressed.
+func (c *compression) compresses(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range c.contentTypes {
+		if allowed == mediaType || (strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*"))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compress gzips the responses to the requests accepting gzip. A response is held back until it reaches
+// the minimal size, so that the small ones go out as they are.
+func (s *Server) compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		if r.Method == http.MethodHead || !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressingWriter{ResponseWriter: w, c: s.compression}
+		next.ServeHTTP(cw, r)
+
+		if err := cw.close(); err != nil {
+			s.log.Error("failed to write response", sl.Err(err))
+		}
+	})
+}
+
+// acceptsGzip reports whether an Accept-Encoding header accepts gzip, by name or as "*".
+func acceptsGzip(acceptEncoding string) bool {
+	for _, coding := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(coding, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if name != "gzip" && name != "*" {
+			continue
+		}
+
+		q, found := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q=")
+		if !found {
+			return true
+		}
+
+		if weight, err := strconv.ParseFloat(q, 64); err == nil && weight > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compressingWriter holds a response back until it reaches the minimal size, and then writes it
+// through gzip if its media type is compressed.
+type compressingWriter struct {
+	http.ResponseWriter
+	c          *compression
+	statusCode int
+	buf        []byte
+	// decided is set once the response is written compressed, through gz, or as it is.
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *compressingWriter) WriteHeader(code int) {
+	if w.statusCode == 0 {
+		w.statusCode = code
+	}
+}
+
+func (w *compressingWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+
+	switch {
+	case w.gz != nil:
+		return w.gz.Write(b)
+	case w.decided:
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) < w.c.minSize {
+		return len(b), nil
+	}
+
+	if err := w.start(true); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// start writes the header and the held back body, compressed if large is set and the response can be.
+func (w *compressingWriter) start(large bool) error {
+	w.decided = true
+
+	h := w.Header()
+	if h.Get("Content-Type") == "" && len(w.buf) > 0 {
+		// As net/http would, since the header is written before the body now.
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	compressed := large && h.Get("Content-Encoding") == "" && w.statusCode != http.StatusNoContent &&
+		w.statusCode != http.StatusNotModified && w.statusCode != http.StatusPartialContent &&
+		w.c.compresses(h.Get("Content-Type"))
+
+	if compressed {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+
+		w.gz = w.c.writers.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(w.statusCode)
+
+	buf := w.buf
+	w.buf = nil
+
+	if compressed {
+		_, err := w.gz.Write(buf)
+		return err
+	}
+
+	_, err := w.ResponseWriter.Write(buf)
+
+	return err
+}
+
+// close writes what is held back of a small response, or ends the gzip stream of a large one.
+func (w *compressingWriter) close() error {
+	if !w.decided {
+		if w.statusCode == 0 {
+			return nil
+		}
+
+		return w.start(false)
+	}
+
+	if w.gz == nil {
+		return nil
+	}
+
+	err := w.gz.Close()
+	w.c.writers.Put(w.gz)
+	w.gz = nil
+
+	return err
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Compression(t *testing.T) {
+	stats := &api.StatsResponse{Items: make([]api.UserStats, 100)}
+	for i := range stats.Items {
+		stats.Items[i] = api.UserStats{UserId: fmt.Sprintf("u%d", i), Username: fmt.Sprintf("user-%d", i), OpenReviews: i}
+	}
+
+	prsMock := new(PullRequestServiceMock)
+	prsMock.On("GetStats", mock.Anything, domain.StatsPeriod{}).Return(stats, nil)
+
+	routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prsMock,
+		WithCompression(config.Compression{MinSize: 1024, Level: 5, ContentTypes: []string{"application/json", "text/*"}}),
+	).Routes()
+
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	t.Run("Large response", func(t *testing.T) {
+		rr := get("/stats", "br, gzip")
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+		assert.Contains(t, rr.Header().Values("Vary"), "Accept-Encoding")
+		assert.Equal(t, "application/json; charset=utf-8", rr.Header().Get("Content-Type"))
+
+		plain := get("/stats", "")
+
+		gz, err := gzip.NewReader(rr.Body)
+		require.NoError(t, err)
+
+		var got api.StatsResponse
+		require.NoError(t, json.NewDecoder(gz).Decode(&got))
+		assert.Equal(t, stats.Items, got.Items)
+		assert.Less(t, rr.Body.Len(), plain.Body.Len())
+	})
+
+	t.Run("Client without gzip", func(t *testing.T) {
+		for _, acceptEncoding := range []string{"", "br", "gzip;q=0", "*;q=0"} {
+			rr := get("/stats", acceptEncoding)
+
+			assert.Empty(t, rr.Header().Get("Content-Encoding"), acceptEncoding)
+			assert.Contains(t, rr.Header().Values("Vary"), "Accept-Encoding", acceptEncoding)
+			assert.True(t, json.Valid(rr.Body.Bytes()), acceptEncoding)
+		}
+	})
+
+	t.Run("Small response", func(t *testing.T) {
+		rr := get("/version", "gzip")
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.True(t, json.Valid(rr.Body.Bytes()), rr.Body.String())
+	})
+
+	t.Run("Media type not compressed", func(t *testing.T) {
+		rr := get("/swagger/favicon-32x32.png", "gzip")
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+	})
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	testCases := []struct {
+		acceptEncoding string
+		expected       bool
+	}{
+		{acceptEncoding: "gzip", expected: true},
+		{acceptEncoding: "deflate, GZIP;q=0.5", expected: true},
+		{acceptEncoding: "*", expected: true},
+		{acceptEncoding: "gzip; q=1.0", expected: true},
+		{acceptEncoding: "", expected: false},
+		{acceptEncoding: "identity", expected: false},
+		{acceptEncoding: "gzip;q=0", expected: false},
+		{acceptEncoding: "x-gzip", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.acceptEncoding, func(t *testing.T) {
+			assert.Equal(t, tc.expected, acceptsGzip(tc.acceptEncoding))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+)
+
+// corsExposedHeaders are the response headers of the service the pages of other origins may read.
+var corsExposedHeaders = strings.Join([]string{
+	requestIDHeader, fieldNamingHeader, appVersionHeader, "Deprecation", "Sunset", "Link", "Retry-After", "Location",
+}, ", ")
+
+// corsPolicy holds the origins, methods and headers the pages of other origins may use.
+type corsPolicy struct {
+	anyOrigin bool
+	origins   []string
+	methods   string
+	headers   string
+	maxAge    string
+}
+
+// WithCORS lets the pages of the origins of cfg call the API from browsers. Without it the service sends no CORS
+// headers, and the browsers keep the API to the pages of its own origin.
+func WithCORS(cfg config.CORS) ServerOption {
+	return func(s *Server) {
+		origins := make([]string, 0, len(cfg.AllowedOrigins))
+		for _, origin := range cfg.AllowedOrigins {
+			origins = append(origins, strings.ToLower(strings.TrimSuffix(origin, "/")))
+		}
+
+		s.cors = &corsPolicy{
+			anyOrigin: slices.Contains(origins, "*"),
+			origins:   origins,
+			methods:   strings.Join(cfg.AllowedMethods, ", "),
+			headers:   strings.Join(cfg.AllowedHeaders, ", "),
+			maxAge:    strconv.Itoa(int(cfg.MaxAge.Seconds())),
+		}
+	}
+}
+
+// allows reports whether the pages of origin may call the API.
+func (p *corsPolicy) allows(origin string) bool {
+	return p.anyOrigin || slices.Contains(p.origins, strings.ToLower(origin))
+}
+
+// allowCORS adds the CORS headers to the responses to the allowed origins and answers their preflight requests.
+// It runs ahead of the authentication and of the read-only mode: the browsers send preflight requests without
+// credentials, and with the OPTIONS method.
+func (s *Server) allowCORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Add("Vary", "Origin")
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		if !s.cors.allows(origin) {
+			if preflight {
+				// Without the CORS headers the browser refuses to send the request.
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		if s.cors.anyOrigin {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+
+		if !preflight {
+			h.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+		h.Set("Access-Control-Allow-Methods", s.cors.methods)
+		h.Set("Access-Control-Allow-Headers", s.cors.headers)
+		h.Set("Access-Control-Max-Age", s.cors.maxAge)
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestServer_CORS(t *testing.T) {
+	cfg := config.CORS{
+		AllowedOrigins: []string{"https://dash.example.com/"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+		MaxAge:         10 * time.Minute,
+	}
+
+	teamsMock := new(TeamServiceMock)
+	teamsMock.On("GetTeam", mock.Anything, "backend").Return(&api.Team{TeamName: "backend", Members: []api.TeamMember{}}, nil)
+
+	routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamsMock, nil, nil,
+		WithCORS(cfg), WithReadOnly(),
+	).Routes()
+
+	t.Run("Request of an allowed origin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/team/get?team_name=backend", nil)
+		req.Header.Set("Origin", "https://Dash.example.com")
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "https://Dash.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, rr.Header().Get("Access-Control-Expose-Headers"), requestIDHeader)
+		assert.Contains(t, rr.Header().Values("Vary"), "Origin")
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Credentials"), "the API takes bearer tokens, not cookies")
+	})
+
+	t.Run("Request of another origin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/team/get?team_name=backend", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code, "the browser, not the service, keeps the answer from the page")
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, rr.Header().Values("Vary"), "Origin")
+	})
+
+	t.Run("Request without an origin", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/team/get?team_name=backend", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("Preflight of an allowed origin in read-only mode", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/v1/team/add", nil)
+		req.Header.Set("Origin", "https://dash.example.com")
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		req.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+		assert.Equal(t, "https://dash.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, POST", rr.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Authorization, Content-Type", rr.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", rr.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("Preflight of another origin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/team/add", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		req.Header.Set("Access-Control-Request-Method", "POST")
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Methods"))
+	})
+}
+
+func TestServer_CORSAnyOrigin(t *testing.T) {
+	routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil,
+		WithCORS(config.CORS{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}),
+	).Routes()
+
+	req := httptest.NewRequest(http.MethodOptions, "/stats", nil)
+	req.Header.Set("Origin", "https://anything.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+
+	rr := httptest.NewRecorder()
+	routes.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "0", rr.Header().Get("Access-Control-Max-Age"))
+}
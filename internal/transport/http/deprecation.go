@@ -0,0 +1,184 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// deprecation declares that an endpoint, or a single field of its response, is being phased out.
+type deprecation struct {
+	Method string
+	Path   string
+	// Field names a top-level field of the response body. An empty Field deprecates the whole endpoint.
+	Field string
+	// Since is the moment the deprecation was announced.
+	Since time.Time
+	// Sunset is the moment the endpoint or field stops being served. Zero means it is not scheduled yet.
+	Sunset time.Time
+	// Link points to the migration notes.
+	Link string
+	// Message is returned to clients in the "warnings" field of the response body.
+	Message string
+}
+
+// deprecations is the registry of deprecated endpoints and fields.
+// Add an entry before changing or removing a part of the API, so that clients are warned in advance.
+var deprecations = []deprecation{
+	legacyListField("/pullRequest/list", "pull_requests"),
+	legacyListField("/pullRequest/search", "pull_requests"),
+	legacyListField("/pullRequest/pending", "pending_assignments"),
+	legacyListField("/users/getReview", "pull_requests"),
+	legacyListField("/stats", "user_stats"),
+	legacyListField("/team/borrows", "borrows"),
+	legacyListField("/admin/notifications", "deliveries"),
+	legacyListField("/admin/freezes", "freezes"),
+	{
+		Method:  http.MethodGet,
+		Path:    "/team/deactivationJob",
+		Since:   time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC),
+		Message: "GET /team/deactivationJob is deprecated, use GET /jobs/{job_id}",
+	},
+}
+
+// listEnvelopeSince is when the list endpoints started to return their elements in the items field
+// of the Page envelope.
+var listEnvelopeSince = time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+// legacyListField deprecates the field in which a list endpoint returned its elements before items.
+func legacyListField(path, field string) deprecation {
+	return deprecation{
+		Method:  http.MethodGet,
+		Path:    path,
+		Field:   field,
+		Since:   listEnvelopeSince,
+		Message: fmt.Sprintf("field %s is deprecated, use items", field),
+	}
+}
+
+// deprecationKey identifies the endpoint a deprecation belongs to.
+func deprecationKey(method, path string) string {
+	return method + " " + path
+}
+
+func indexDeprecations(list []deprecation) map[string][]deprecation {
+	index := make(map[string][]deprecation, len(list))
+	for _, d := range list {
+		key := deprecationKey(d.Method, d.Path)
+		index[key] = append(index[key], d)
+	}
+
+	return index
+}
+
+// deprecationNotice signals deprecations of the requested endpoint to the client.
+// A deprecated endpoint gets the Deprecation (RFC 9745), Sunset (RFC 8594) and Link headers;
+// a message about every deprecated endpoint or returned field is added to the "warnings"
+// field of a JSON object response. The endpoints are the same with and without the /v1 prefix.
+func (s *Server) deprecationNotice(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notices := s.deprecations[deprecationKey(r.Method, strings.TrimPrefix(r.URL.Path, "/v1"))]
+		if len(notices) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		for _, d := range notices {
+			if d.Field == "" {
+				setDeprecationHeaders(w.Header(), d)
+			}
+		}
+
+		buffered := &bufferingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(buffered, r)
+
+		body := addDeprecationWarnings(buffered.body.Bytes(), notices)
+
+		w.Header().Del("Content-Length")
+		w.WriteHeader(buffered.statusCode)
+
+		if _, err := w.Write(body); err != nil {
+			s.log.Error("failed to write response", sl.Err(err))
+		}
+	})
+}
+
+func setDeprecationHeaders(h http.Header, d deprecation) {
+	h.Set("Deprecation", fmt.Sprintf("@%d", d.Since.Unix()))
+
+	if !d.Sunset.IsZero() {
+		h.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+
+	if d.Link != "" {
+		h.Add("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"", d.Link))
+	}
+}
+
+// addDeprecationWarnings returns body with the messages of the notices that apply to it
+// in its "warnings" field, after the warnings the handler has already put there.
+// Bodies that are not JSON objects are returned unchanged.
+func addDeprecationWarnings(body []byte, notices []deprecation) []byte {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil || object == nil {
+		return body
+	}
+
+	var warnings []string
+	if existing, ok := object["warnings"]; ok {
+		if err := json.Unmarshal(existing, &warnings); err != nil {
+			return body
+		}
+	}
+
+	added := false
+
+	for _, d := range notices {
+		if d.Field != "" {
+			if _, ok := object[d.Field]; !ok {
+				continue
+			}
+		}
+
+		warnings = append(warnings, d.Message)
+		added = true
+	}
+
+	if !added {
+		return body
+	}
+
+	encodedWarnings, err := json.Marshal(warnings)
+	if err != nil {
+		return body
+	}
+
+	object["warnings"] = encodedWarnings
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(object); err != nil {
+		return body
+	}
+
+	return buf.Bytes()
+}
+
+// bufferingResponseWriter holds the response back so that it can be amended before it is sent.
+type bufferingResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *bufferingResponseWriter) WriteHeader(code int) {
+	w.statusCode = code
+}
+
+func (w *bufferingResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
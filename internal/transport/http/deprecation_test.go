@@ -0,0 +1,141 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeprecationNoticeMiddleware(t *testing.T) {
+	since := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	server := &Server{
+		log: slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		deprecations: indexDeprecations([]deprecation{
+			{
+				Method:  http.MethodPost,
+				Path:    "/old",
+				Since:   since,
+				Sunset:  sunset,
+				Link:    "https://example.com/migration",
+				Message: "POST /old is deprecated, use POST /new",
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/fields",
+				Field:   "legacy",
+				Since:   since,
+				Message: "field legacy is deprecated",
+			},
+		}),
+	}
+
+	body := `{"result":"ok"}`
+	handler := server.deprecationNotice(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.respond(w, http.StatusCreated, map[string]string{"result": "ok"})
+	}))
+
+	testCases := []struct {
+		name              string
+		method            string
+		path              string
+		handler           http.Handler
+		expectedBody      string
+		expectDeprecation bool
+	}{
+		{
+			name:         "Endpoint without deprecations is untouched",
+			method:       http.MethodPost,
+			path:         "/new",
+			handler:      handler,
+			expectedBody: body,
+		},
+		{
+			name:              "Deprecated endpoint gets headers and a warning",
+			method:            http.MethodPost,
+			path:              "/old",
+			handler:           handler,
+			expectedBody:      `{"result":"ok","warnings":["POST /old is deprecated, use POST /new"]}`,
+			expectDeprecation: true,
+		},
+		{
+			name:              "Deprecated endpoint under /v1 gets headers and a warning",
+			method:            http.MethodPost,
+			path:              "/v1/old",
+			handler:           handler,
+			expectedBody:      `{"result":"ok","warnings":["POST /old is deprecated, use POST /new"]}`,
+			expectDeprecation: true,
+		},
+		{
+			name:   "Deprecation warning follows the handler's own warnings",
+			method: http.MethodPost,
+			path:   "/old",
+			handler: server.deprecationNotice(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				server.respond(w, http.StatusCreated, map[string]any{"result": "ok", "warnings": []string{"partial"}})
+			})),
+			expectedBody:      `{"result":"ok","warnings":["partial","POST /old is deprecated, use POST /new"]}`,
+			expectDeprecation: true,
+		},
+		{
+			name:         "Deprecated field absent from the response is not reported",
+			method:       http.MethodGet,
+			path:         "/fields",
+			handler:      handler,
+			expectedBody: body,
+		},
+		{
+			name:   "Deprecated field in the response is reported",
+			method: http.MethodGet,
+			path:   "/fields",
+			handler: server.deprecationNotice(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				server.respond(w, http.StatusCreated, map[string]string{"legacy": "x"})
+			})),
+			expectedBody: `{"legacy":"x","warnings":["field legacy is deprecated"]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			tc.handler.ServeHTTP(rr, httptest.NewRequest(tc.method, tc.path, nil))
+
+			assert.Equal(t, http.StatusCreated, rr.Code)
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String())
+
+			if tc.expectDeprecation {
+				assert.Equal(t, "@1761955200", rr.Header().Get("Deprecation"))
+				assert.Equal(t, "Fri, 01 May 2026 00:00:00 GMT", rr.Header().Get("Sunset"))
+				assert.Equal(t, `<https://example.com/migration>; rel="deprecation"`, rr.Header().Get("Link"))
+			} else {
+				assert.Empty(t, rr.Header().Get("Deprecation"))
+			}
+		})
+	}
+}
+
+func TestDeprecationNotice_LegacyListFields(t *testing.T) {
+	teamServiceMock := new(TeamServiceMock)
+	teamServiceMock.On("ListReviewerBorrows", mock.Anything, "backend").Return([]api.ReviewerBorrow{}, nil).Twice()
+
+	router := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamServiceMock, nil, nil).Routes()
+
+	for _, path := range []string{"/team/borrows", "/v1/team/borrows"} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path+"?team_name=backend", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"items":[],"borrows":[],"next_cursor":null,"total_estimate":0,
+			"warnings":["field borrows is deprecated, use items"]}`, rr.Body.String())
+		assert.Empty(t, rr.Header().Get("Deprecation"), "only a field is deprecated, not the endpoint")
+	}
+
+	teamServiceMock.AssertExpectations(t)
+}
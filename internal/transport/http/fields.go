@@ -0,0 +1,186 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+)
+
+// maxSelectedFields bounds the fields query parameter, which is parsed on every request.
+const maxSelectedFields = 50
+
+// fieldSelection is a parsed fields query parameter (sparse fieldset). It maps each selected field
+// to the selection of its own fields, which is nil if the field is selected as a whole.
+// A nil fieldSelection selects everything.
+type fieldSelection map[string]fieldSelection
+
+var jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+
+// selectFields parses fields, a comma-separated list of dot-separated paths such as
+// "team_name,members.user_id", against the JSON fields of the api struct T.
+// Unknown fields are a validation error, so that a typo does not silently empty the response.
+// Fields renamed under /v1 may be given by either name.
+func selectFields[T any](fields *string) (fieldSelection, error) {
+	if fields == nil {
+		return nil, nil
+	}
+
+	paths := strings.Split(*fields, ",")
+	if len(paths) > maxSelectedFields {
+		return nil, fmt.Errorf("%w: at most %d fields can be selected", apperrors.ErrValidation, maxSelectedFields)
+	}
+
+	selection := fieldSelection{}
+	resource := reflect.TypeFor[T]()
+
+	for _, path := range paths {
+		if err := selection.add(resource, strings.TrimSpace(path)); err != nil {
+			return nil, err
+		}
+	}
+
+	return selection, nil
+}
+
+// add selects path, checking each of its segments against the fields of t.
+func (sel fieldSelection) add(t reflect.Type, path string) error {
+	current := sel
+
+	for segments := strings.Split(path, "."); len(segments) > 0; segments = segments[1:] {
+		name, last := segments[0], len(segments) == 1
+
+		if name == "" {
+			return fmt.Errorf("%w: malformed field '%s'", apperrors.ErrValidation, path)
+		}
+
+		field, ok := jsonField(t, name)
+		if !ok {
+			return fmt.Errorf("%w: unknown field '%s'", apperrors.ErrValidation, path)
+		}
+
+		sub, selected := current[field.name]
+
+		switch {
+		case last:
+			current[field.name] = nil
+		case selected && sub == nil:
+			// The field is already selected as a whole.
+			return nil
+		case !selected:
+			sub = fieldSelection{}
+			current[field.name] = sub
+		}
+
+		current, t = sub, field.typ
+	}
+
+	return nil
+}
+
+type selectableField struct {
+	name string
+	typ  reflect.Type
+}
+
+// jsonField looks up the field that t serializes under name. Objects without a fixed set of fields,
+// such as maps, accept any name.
+func jsonField(t reflect.Type, name string) (selectableField, bool) {
+	t = elemType(t)
+
+	switch {
+	case t.Kind() == reflect.Map:
+		return selectableField{name: name, typ: t.Elem()}, true
+	case t.Kind() == reflect.Interface:
+		return selectableField{name: name, typ: t}, true
+	case t.Kind() != reflect.Struct || t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return selectableField{}, false
+	}
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tagName, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+
+		switch {
+		case tagName == "-":
+			continue
+		case f.Anonymous && tagName == "":
+			if field, ok := jsonField(f.Type, name); ok {
+				return field, true
+			}
+
+			continue
+		case tagName == "":
+			tagName = f.Name
+		}
+
+		if tagName == name || legacyFieldNames[tagName] == name {
+			return selectableField{name: tagName, typ: f.Type}, true
+		}
+	}
+
+	return selectableField{}, false
+}
+
+// elemType strips the pointers and slices around the type of a JSON object.
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// apply returns the JSON form of value with only the selected fields, applying the selection to each item of an array.
+// The projected objects are maps, so their fields are encoded in alphabetical order.
+func (sel fieldSelection) apply(value any) (any, error) {
+	if sel == nil {
+		return value, nil
+	}
+
+	body, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value for field selection: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var projected any
+	if err := decoder.Decode(&projected); err != nil {
+		return nil, fmt.Errorf("failed to decode value for field selection: %w", err)
+	}
+
+	sel.project(projected)
+
+	return projected, nil
+}
+
+// project removes the fields that are not selected from value in place.
+func (sel fieldSelection) project(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for name, field := range v {
+			sub, ok := sel[name]
+			if !ok {
+				delete(v, name)
+				continue
+			}
+
+			if sub != nil {
+				sub.project(field)
+			}
+		}
+	case []any:
+		for _, item := range v {
+			sel.project(item)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectFields(t *testing.T) {
+	createdAt := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+	description := "Adds the feature"
+	pr := &api.PullRequest{
+		PullRequestId:     "pr-1",
+		PullRequestName:   "Feature",
+		AuthorId:          "author-1",
+		Status:            api.PullRequestStatusOPEN,
+		AssignedReviewers: []string{"reviewer-1"},
+		CreatedAt:         &createdAt,
+		Description:       &description,
+		CustomFields:      &map[string]any{"ticket": "PAY-1", "points": 3},
+	}
+
+	testCases := []struct {
+		name         string
+		fields       string
+		expectedBody string
+		expectedErr  string
+	}{
+		{
+			name:         "Top-level fields",
+			fields:       "pull_request_id,status",
+			expectedBody: `{"pull_request_id":"pr-1","status":"OPEN"}`,
+		},
+		{
+			name:         "Renamed field by its /v1 name",
+			fields:       "pull_request_id,created_at",
+			expectedBody: `{"pull_request_id":"pr-1","createdAt":"2025-03-14T12:00:00Z"}`,
+		},
+		{
+			name:         "Key of a map field",
+			fields:       "custom_fields.ticket",
+			expectedBody: `{"custom_fields":{"ticket":"PAY-1"}}`,
+		},
+		{
+			name:         "Whole field wins over its subfields",
+			fields:       "custom_fields.ticket,custom_fields",
+			expectedBody: `{"custom_fields":{"ticket":"PAY-1","points":3}}`,
+		},
+		{
+			name:         "Spaces around fields",
+			fields:       " pull_request_id , description ",
+			expectedBody: `{"pull_request_id":"pr-1","description":"Adds the feature"}`,
+		},
+		{
+			name:        "Unknown field",
+			fields:      "pull_request_id,title",
+			expectedErr: "unknown field 'title'",
+		},
+		{
+			name:        "Subfield of a scalar",
+			fields:      "status.value",
+			expectedErr: "unknown field 'status.value'",
+		},
+		{
+			name:        "Subfield of a time",
+			fields:      "createdAt.year",
+			expectedErr: "unknown field 'createdAt.year'",
+		},
+		{
+			name:        "Empty segment",
+			fields:      "custom_fields.",
+			expectedErr: "malformed field 'custom_fields.'",
+		},
+		{
+			name:        "Too many fields",
+			fields:      strings.Repeat("status,", maxSelectedFields) + "status",
+			expectedErr: "at most 50 fields can be selected",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			selection, err := selectFields[api.PullRequest](&tc.fields)
+			if tc.expectedErr != "" {
+				assert.ErrorIs(t, err, apperrors.ErrValidation)
+				assert.ErrorContains(t, err, tc.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			projected, err := selection.apply(pr)
+			require.NoError(t, err)
+
+			body, err := json.Marshal(projected)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expectedBody, string(body))
+		})
+	}
+}
+
+func TestSelectFields_Absent(t *testing.T) {
+	selection, err := selectFields[api.Team](nil)
+	require.NoError(t, err)
+
+	team := &api.Team{TeamName: "backend"}
+	projected, err := selection.apply(team)
+	require.NoError(t, err)
+	assert.Same(t, team, projected)
+}
+
+func TestSelectFields_Array(t *testing.T) {
+	fields := "username,merged_reviews"
+	selection, err := selectFields[api.UserStats](&fields)
+	require.NoError(t, err)
+
+	projected, err := selection.apply([]api.UserStats{
+		{UserId: "u1", Username: "Alice", OpenReviews: 1, MergedReviews: 5},
+		{UserId: "u2", Username: "Bob", OpenReviews: 0, MergedReviews: 2},
+	})
+	require.NoError(t, err)
+
+	body, err := json.Marshal(projected)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"username":"Alice","merged_reviews":5},{"username":"Bob","merged_reviews":2}]`, string(body))
+}
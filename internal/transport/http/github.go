@@ -0,0 +1,203 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/YusovID/pr-reviewer-service/internal/signature"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// gitHubPullRequestEventType is the X-GitHub-Event of the events the webhook handles.
+const gitHubPullRequestEventType = "pull_request"
+
+// Outcomes of the GitHub webhook deliveries that are not an api.GitHubWebhookResultOutcome.
+const (
+	gitHubDeliveryRejected = "rejected"
+	gitHubDeliveryFailed   = "failed"
+)
+
+var gitHubWebhookDeliveriesTotal = promauto.NewCounterVec(
+	metrics.GitHubWebhookDeliveries.CounterOpts(), metrics.GitHubWebhookDeliveries.Labels,
+)
+
+// WithGitHubWebhook serves POST /webhooks/github, accepting the deliveries verified by v.
+// Without it the endpoint responds with 404.
+func WithGitHubWebhook(v *signature.Verifier) ServerOption {
+	return func(s *Server) {
+		s.gitHubWebhook = v
+	}
+}
+
+func (s *Server) PostWebhooksGithub(w http.ResponseWriter, r *http.Request, params api.PostWebhooksGithubParams) {
+	const op = "internal.transport.http.PostWebhooksGithub"
+
+	if s.gitHubWebhook == nil {
+		s.respondAPIError(w, http.StatusNotFound, api.NOTFOUND, "github webhook is not configured")
+		return
+	}
+
+	log := s.log.With(slog.String("op", op), slog.String("delivery_id", r.Header.Get(signature.GitHubDeliveryHeader)),
+		slog.String("event", params.XGitHubEvent))
+
+	if _, err := s.gitHubWebhook.VerifyGitHubRequest(r); err != nil {
+		s.countGitHubDelivery(gitHubDeliveryRejected)
+		log.Warn("github delivery rejected", slog.String("reason", err.Error()))
+
+		if errors.Is(err, signature.ErrBodyTooLarge) {
+			s.respondError(w, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+
+		s.respondAPIError(w, http.StatusUnauthorized, api.INVALIDSIGNATURE, err.Error())
+
+		return
+	}
+
+	if params.XGitHubEvent != gitHubPullRequestEventType {
+		s.respondGitHubResult(w, &api.GitHubWebhookResult{Outcome: api.GitHubIgnored})
+		return
+	}
+
+	var event gitHubPullRequestEvent
+	if err := s.decodeAndValidate(r, &event); err != nil {
+		s.countGitHubDelivery(gitHubDeliveryFailed)
+		s.handleServiceError(w, r, op, err)
+
+		return
+	}
+
+	var (
+		result *api.GitHubWebhookResult
+		err    error
+	)
+
+	if s.webhookShadow.GitHub {
+		result, err = s.shadowGitHubPullRequest(r.Context(), log, &event)
+	} else {
+		result, err = s.handleGitHubPullRequest(r.Context(), &event)
+	}
+
+	if err != nil {
+		s.countGitHubDelivery(gitHubDeliveryFailed)
+		s.handleServiceError(w, r, op, err)
+
+		return
+	}
+
+	log.Info("github delivery handled", slog.String("action", event.Action), slog.String("outcome", string(result.Outcome)),
+		slog.Bool("shadow", s.webhookShadow.GitHub))
+
+	s.respondGitHubResult(w, result)
+}
+
+// handleGitHubPullRequest creates the pull request of an opened event and merges or closes the pull request
+// of a closed one. The other actions are ignored.
+func (s *Server) handleGitHubPullRequest(ctx context.Context, event *gitHubPullRequestEvent) (*api.GitHubWebhookResult, error) {
+	prID := gitHubPullRequestID(event)
+	result := &api.GitHubWebhookResult{PullRequestId: &prID}
+
+	switch {
+	case event.Action == "opened":
+		details := service.PRDetails{ExternalURL: event.PullRequest.HTMLURL}
+		if body := event.PullRequest.Body; body != nil && *body != "" {
+			details.Description = body
+		}
+
+		_, created, err := s.prCommands.CreatePR(ctx, prID, event.PullRequest.Title, event.PullRequest.User.Login, details)
+
+		var exists *apperrors.PRAlreadyExistsError
+
+		switch {
+		case errors.As(err, &exists), err == nil && !created:
+			result.Outcome = api.GitHubDuplicate
+		case err != nil:
+			return nil, err
+		default:
+			result.Outcome = api.GitHubCreated
+		}
+	case event.Action == "closed" && event.PullRequest.Merged:
+		// The pull request is merged in GitHub already, so a freeze window cannot stop it; the merge is
+		// recorded as an override, which keeps it visible.
+		if _, err := s.prCommands.MergePR(ctx, prID, service.MergeOptions{OverrideFreeze: true}); err != nil {
+			return nil, err
+		}
+
+		result.Outcome = api.GitHubMerged
+	case event.Action == "closed":
+		if _, err := s.prCommands.ClosePR(ctx, prID); err != nil {
+			return nil, err
+		}
+
+		result.Outcome = api.GitHubClosed
+	default:
+		result.Outcome = api.GitHubIgnored
+	}
+
+	return result, nil
+}
+
+// shadowGitHubPullRequest is handleGitHubPullRequest in shadow mode: the reviewers of an opened pull request
+// are computed but not assigned, and a closed one is left as it is.
+func (s *Server) shadowGitHubPullRequest(ctx context.Context, log *slog.Logger,
+	event *gitHubPullRequestEvent,
+) (*api.GitHubWebhookResult, error) {
+	prID := gitHubPullRequestID(event)
+	shadow := true
+	result := &api.GitHubWebhookResult{PullRequestId: &prID, Shadow: &shadow}
+
+	switch {
+	case event.Action == "opened":
+		preview, err := s.previewShadowAssignment(ctx, log, shadowProviderGitHub, prID, event.PullRequest.User.Login)
+		if err != nil {
+			return nil, err
+		}
+
+		if preview.exists {
+			result.Outcome = api.GitHubDuplicate
+			break
+		}
+
+		strategy := string(preview.preview.Strategy)
+		result.Outcome = api.GitHubCreated
+		result.Reviewers = &preview.preview.ReviewerIDs
+		result.Strategy = &strategy
+	case event.Action == "closed" && event.PullRequest.Merged:
+		result.Outcome = api.GitHubMerged
+	case event.Action == "closed":
+		result.Outcome = api.GitHubClosed
+	default:
+		result.Outcome = api.GitHubIgnored
+	}
+
+	return result, nil
+}
+
+func (s *Server) respondGitHubResult(w http.ResponseWriter, result *api.GitHubWebhookResult) {
+	s.countGitHubDelivery(string(result.Outcome))
+	s.respond(w, http.StatusOK, result)
+}
+
+// countGitHubDelivery counts a delivery by outcome. The deliveries handled in shadow mode are counted apart,
+// so that the delivery counter only reflects the ones that changed pull requests.
+func (s *Server) countGitHubDelivery(outcome string) {
+	if s.webhookShadow.GitHub {
+		countShadowEvent(shadowProviderGitHub, outcome)
+		return
+	}
+
+	gitHubWebhookDeliveriesTotal.WithLabelValues(outcome).Inc()
+}
+
+// gitHubPullRequestID is the ID of a GitHub pull request in the service. The repository is identified
+// by its ID rather than its name, so that a renamed repository keeps its pull requests.
+func gitHubPullRequestID(event *gitHubPullRequestEvent) string {
+	return fmt.Sprintf("github-%d-%d", event.Repository.ID, event.PullRequest.Number)
+}
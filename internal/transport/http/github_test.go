@@ -0,0 +1,279 @@
+package http
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/YusovID/pr-reviewer-service/internal/signature"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var gitHubSecret = []byte("github-secret")
+
+// gitHubDelivery returns a request of a GitHub webhook delivery of body, signed with secret.
+func gitHubDelivery(event, delivery string, secret []byte, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(body))
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set(signature.GitHubDeliveryHeader, delivery)
+	req.Header.Set(signature.GitHubSignatureHeader, signature.SignGitHub(secret, []byte(body)))
+
+	return req
+}
+
+func gitHubPullRequestBody(action string, merged bool) string {
+	return fmt.Sprintf(`{"action":%q,"number":1347,"pull_request":{"number":1347,"title":"Add search","body":"Adds full-text search",
+		"html_url":"https://github.com/octocat/Hello-World/pull/1347","merged":%t,"user":{"login":"u1"}},
+		"repository":{"id":1296269,"full_name":"octocat/Hello-World"}}`, action, merged)
+}
+
+func TestServer_PostWebhooksGithub(t *testing.T) {
+	const prID = "github-1296269-1347"
+
+	description := "Adds full-text search"
+	externalURL := "https://github.com/octocat/Hello-World/pull/1347"
+	details := service.PRDetails{Description: &description, ExternalURL: &externalURL}
+
+	testCases := []struct {
+		name                 string
+		request              func(delivery string) *http.Request
+		setupMocks           func(prs *PullRequestServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Opened pull request is created",
+			request: func(delivery string) *http.Request {
+				return gitHubDelivery("pull_request", delivery, gitHubSecret, gitHubPullRequestBody("opened", false))
+			},
+			setupMocks: func(prs *PullRequestServiceMock) {
+				prs.On("CreatePR", mock.Anything, prID, "Add search", "u1", details).Return(&api.PullRequest{}, true, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"outcome":"created","pull_request_id":"github-1296269-1347"}`,
+		},
+		{
+			name: "Opened pull request that exists is a duplicate",
+			request: func(delivery string) *http.Request {
+				return gitHubDelivery("pull_request", delivery, gitHubSecret, gitHubPullRequestBody("opened", false))
+			},
+			setupMocks: func(prs *PullRequestServiceMock) {
+				prs.On("CreatePR", mock.Anything, prID, "Add search", "u1", details).
+					Return(nil, false, &apperrors.PRAlreadyExistsError{PRID: prID}).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"outcome":"duplicate","pull_request_id":"github-1296269-1347"}`,
+		},
+		{
+			name: "Merged pull request is merged despite a freeze",
+			request: func(delivery string) *http.Request {
+				return gitHubDelivery("pull_request", delivery, gitHubSecret, gitHubPullRequestBody("closed", true))
+			},
+			setupMocks: func(prs *PullRequestServiceMock) {
+				prs.On("MergePR", mock.Anything, prID, service.MergeOptions{OverrideFreeze: true}).Return(&api.MergeResponse{}, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"outcome":"merged","pull_request_id":"github-1296269-1347"}`,
+		},
+		{
+			name: "Closed pull request is closed",
+			request: func(delivery string) *http.Request {
+				return gitHubDelivery("pull_request", delivery, gitHubSecret, gitHubPullRequestBody("closed", false))
+			},
+			setupMocks: func(prs *PullRequestServiceMock) {
+				prs.On("ClosePR", mock.Anything, prID).Return(&api.PullRequest{}, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"outcome":"closed","pull_request_id":"github-1296269-1347"}`,
+		},
+		{
+			name: "Closed pull request unknown to the service",
+			request: func(delivery string) *http.Request {
+				return gitHubDelivery("pull_request", delivery, gitHubSecret, gitHubPullRequestBody("closed", false))
+			},
+			setupMocks: func(prs *PullRequestServiceMock) {
+				prs.On("ClosePR", mock.Anything, prID).Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+		{
+			name: "Other action is ignored",
+			request: func(delivery string) *http.Request {
+				return gitHubDelivery("pull_request", delivery, gitHubSecret, gitHubPullRequestBody("labeled", false))
+			},
+			setupMocks:           func(prs *PullRequestServiceMock) {},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"outcome":"ignored","pull_request_id":"github-1296269-1347"}`,
+		},
+		{
+			name: "Ping is ignored",
+			request: func(delivery string) *http.Request {
+				return gitHubDelivery("ping", delivery, gitHubSecret, `{"zen":"Design for failure."}`)
+			},
+			setupMocks:           func(prs *PullRequestServiceMock) {},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"outcome":"ignored"}`,
+		},
+		{
+			name: "Author login that is not an ID",
+			request: func(delivery string) *http.Request {
+				body := strings.Replace(gitHubPullRequestBody("opened", false), `"login":"u1"`, `"login":"u1[bot]"`, 1)
+				return gitHubDelivery("pull_request", delivery, gitHubSecret, body)
+			},
+			setupMocks:         func(prs *PullRequestServiceMock) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Unknown secret",
+			request: func(delivery string) *http.Request {
+				return gitHubDelivery("pull_request", delivery, []byte("guessed-secret"), gitHubPullRequestBody("opened", false))
+			},
+			setupMocks:           func(prs *PullRequestServiceMock) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":{"code":"INVALID_SIGNATURE","message":"invalid signature"}}`,
+		},
+		{
+			name: "Missing signature",
+			request: func(delivery string) *http.Request {
+				req := gitHubDelivery("pull_request", delivery, gitHubSecret, gitHubPullRequestBody("opened", false))
+				req.Header.Del(signature.GitHubSignatureHeader)
+
+				return req
+			},
+			setupMocks:           func(prs *PullRequestServiceMock) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":{"code":"INVALID_SIGNATURE","message":"missing signature"}}`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prsMock := new(PullRequestServiceMock)
+			tc.setupMocks(prsMock)
+
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prsMock,
+				WithGitHubWebhook(signature.NewVerifier(gitHubSecret)))
+
+			rr := httptest.NewRecorder()
+			api.Handler(server).ServeHTTP(rr, tc.request(fmt.Sprintf("delivery-%016d", i)))
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			if tc.expectedResponseBody != "" {
+				assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			}
+
+			prsMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_PostWebhooksGithubShadow(t *testing.T) {
+	const prID = "github-1296269-1347"
+
+	testCases := []struct {
+		name                 string
+		body                 string
+		setupMocks           func(prs *PullRequestServiceMock)
+		expectedResponseBody string
+	}{
+		{
+			name: "Opened pull request gets its reviewers previewed",
+			body: gitHubPullRequestBody("opened", false),
+			setupMocks: func(prs *PullRequestServiceMock) {
+				prs.On("GetPR", mock.Anything, prID).Return(nil, apperrors.ErrNotFound).Once()
+				prs.On("PreviewAssignment", mock.Anything, "u1").Return(&service.AssignmentPreview{
+					TeamID: 1, ReviewerIDs: []string{"u2", "u3"}, Strategy: domain.StrategyLeastLoaded,
+				}, nil).Once()
+			},
+			expectedResponseBody: `{"outcome":"created","pull_request_id":"github-1296269-1347","shadow":true,
+				"reviewers":["u2","u3"],"strategy":"least_loaded"}`,
+		},
+		{
+			name: "Opened pull request that exists is a duplicate",
+			body: gitHubPullRequestBody("opened", false),
+			setupMocks: func(prs *PullRequestServiceMock) {
+				prs.On("GetPR", mock.Anything, prID).Return(&api.PullRequest{PullRequestId: prID}, nil).Once()
+			},
+			expectedResponseBody: `{"outcome":"duplicate","pull_request_id":"github-1296269-1347","shadow":true}`,
+		},
+		{
+			name:                 "Merged pull request is left open",
+			body:                 gitHubPullRequestBody("closed", true),
+			setupMocks:           func(prs *PullRequestServiceMock) {},
+			expectedResponseBody: `{"outcome":"merged","pull_request_id":"github-1296269-1347","shadow":true}`,
+		},
+		{
+			name:                 "Closed pull request is left open",
+			body:                 gitHubPullRequestBody("closed", false),
+			setupMocks:           func(prs *PullRequestServiceMock) {},
+			expectedResponseBody: `{"outcome":"closed","pull_request_id":"github-1296269-1347","shadow":true}`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prsMock := new(PullRequestServiceMock)
+			tc.setupMocks(prsMock)
+
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prsMock,
+				WithGitHubWebhook(signature.NewVerifier(gitHubSecret)), WithWebhookShadow(config.WebhookShadow{GitHub: true}))
+
+			live := testutil.ToFloat64(gitHubWebhookDeliveriesTotal.WithLabelValues(string(api.GitHubCreated)))
+
+			rr := httptest.NewRecorder()
+			api.Handler(server).ServeHTTP(rr, gitHubDelivery("pull_request", fmt.Sprintf("shadow-%016d", i), gitHubSecret, tc.body))
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			assert.Equal(t, live, testutil.ToFloat64(gitHubWebhookDeliveriesTotal.WithLabelValues(string(api.GitHubCreated))))
+
+			// Nothing is persisted in shadow mode: the mock fails on any CreatePR, MergePR or ClosePR call.
+			prsMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_PostWebhooksGithubRejectsReplays(t *testing.T) {
+	prsMock := new(PullRequestServiceMock)
+	prsMock.On("ClosePR", mock.Anything, "github-1296269-1347").Return(&api.PullRequest{}, nil).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prsMock,
+		WithGitHubWebhook(signature.NewVerifier(gitHubSecret)))
+	router := server.Routes()
+
+	rejected := testutil.ToFloat64(gitHubWebhookDeliveriesTotal.WithLabelValues(gitHubDeliveryRejected))
+	body := gitHubPullRequestBody("closed", false)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, gitHubDelivery("pull_request", "72d3162e-cc78-11e3-81ab-4c9367dc0958", gitHubSecret, body))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, gitHubDelivery("pull_request", "72d3162e-cc78-11e3-81ab-4c9367dc0958", gitHubSecret, body))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.JSONEq(t, `{"error":{"code":"INVALID_SIGNATURE","message":"replayed request"}}`, rr.Body.String())
+
+	assert.Equal(t, rejected+1, testutil.ToFloat64(gitHubWebhookDeliveriesTotal.WithLabelValues(gitHubDeliveryRejected)))
+	prsMock.AssertExpectations(t)
+}
+
+func TestServer_PostWebhooksGithubNotConfigured(t *testing.T) {
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil)
+
+	rr := httptest.NewRecorder()
+	api.Handler(server).ServeHTTP(rr, gitHubDelivery("pull_request", "72d3162e-cc78-11e3-81ab-4c9367dc0958", gitHubSecret, `{}`))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,272 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/YusovID/pr-reviewer-service/internal/signature"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// gitLabMergeRequestEventType is the X-Gitlab-Event of the events the webhook handles.
+const gitLabMergeRequestEventType = "Merge Request Hook"
+
+// Outcomes of the GitLab webhook deliveries that are not an api.GitLabWebhookResultOutcome.
+const (
+	gitLabDeliveryRejected = "rejected"
+	gitLabDeliveryFailed   = "failed"
+)
+
+var gitLabWebhookDeliveriesTotal = promauto.NewCounterVec(
+	metrics.GitLabWebhookDeliveries.CounterOpts(), metrics.GitLabWebhookDeliveries.Labels,
+)
+
+// WithGitLabWebhook serves POST /webhooks/gitlab, accepting the deliveries verified by v.
+// The authors of merge requests are resolved through the service passed to WithGitLabUsers.
+// Without both options the endpoint responds with 404.
+func WithGitLabWebhook(v *signature.Verifier) ServerOption {
+	return func(s *Server) {
+		s.gitLabWebhook = v
+	}
+}
+
+// WithGitLabUsers serves the /admin/gitlabUsers endpoints with gs.
+func WithGitLabUsers(gs service.GitLabUserService) ServerOption {
+	return func(s *Server) {
+		s.gitLabUsers = gs
+	}
+}
+
+func (s *Server) PostWebhooksGitlab(w http.ResponseWriter, r *http.Request, params api.PostWebhooksGitlabParams) {
+	const op = "internal.transport.http.PostWebhooksGitlab"
+
+	if s.gitLabWebhook == nil || s.gitLabUsers == nil {
+		s.respondAPIError(w, http.StatusNotFound, api.NOTFOUND, "gitlab webhook is not configured")
+		return
+	}
+
+	log := s.log.With(slog.String("op", op), slog.String("event", params.XGitlabEvent))
+
+	if _, err := s.gitLabWebhook.VerifyGitLabRequest(r); err != nil {
+		s.countGitLabDelivery(gitLabDeliveryRejected)
+		log.Warn("gitlab delivery rejected", slog.String("reason", err.Error()))
+
+		if errors.Is(err, signature.ErrBodyTooLarge) {
+			s.respondError(w, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+
+		s.respondAPIError(w, http.StatusUnauthorized, api.INVALIDSIGNATURE, err.Error())
+
+		return
+	}
+
+	if params.XGitlabEvent != gitLabMergeRequestEventType {
+		s.respondGitLabResult(w, &api.GitLabWebhookResult{Outcome: api.GitLabIgnored})
+		return
+	}
+
+	var event gitLabMergeRequestEvent
+	if err := s.decodeAndValidate(r, &event); err != nil {
+		s.countGitLabDelivery(gitLabDeliveryFailed)
+		s.handleServiceError(w, r, op, err)
+
+		return
+	}
+
+	var (
+		result *api.GitLabWebhookResult
+		err    error
+	)
+
+	if s.webhookShadow.GitLab {
+		result, err = s.shadowGitLabMergeRequest(r.Context(), log, &event)
+	} else {
+		result, err = s.handleGitLabMergeRequest(r.Context(), &event)
+	}
+
+	if err != nil {
+		s.countGitLabDelivery(gitLabDeliveryFailed)
+		s.handleServiceError(w, r, op, err)
+
+		return
+	}
+
+	log.Info("gitlab delivery handled", slog.String("action", event.ObjectAttributes.Action),
+		slog.String("gitlab_username", event.User.Username), slog.String("outcome", string(result.Outcome)),
+		slog.Bool("shadow", s.webhookShadow.GitLab))
+
+	s.respondGitLabResult(w, result)
+}
+
+// handleGitLabMergeRequest creates the pull request of an opened merge request and merges or closes the pull
+// request of a merged or closed one. The other actions are ignored.
+func (s *Server) handleGitLabMergeRequest(ctx context.Context, event *gitLabMergeRequestEvent) (*api.GitLabWebhookResult, error) {
+	prID := gitLabPullRequestID(event)
+	result := &api.GitLabWebhookResult{PullRequestId: &prID}
+
+	switch event.ObjectAttributes.Action {
+	case "open":
+		// The user of an open event is the one who opened the merge request, that is its author.
+		authorID, err := s.gitLabUsers.ResolveGitLabUser(ctx, event.User.Username)
+		if errors.Is(err, apperrors.ErrNotFound) {
+			// GitLab disables a webhook that keeps failing, so an unmapped author is reported rather than rejected.
+			result.Outcome = api.GitLabUnmapped
+			return result, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		details := service.PRDetails{ExternalURL: event.ObjectAttributes.URL}
+		if description := event.ObjectAttributes.Description; description != nil && *description != "" {
+			details.Description = description
+		}
+
+		_, created, err := s.prCommands.CreatePR(ctx, prID, event.ObjectAttributes.Title, authorID, details)
+
+		var exists *apperrors.PRAlreadyExistsError
+
+		switch {
+		case errors.As(err, &exists), err == nil && !created:
+			result.Outcome = api.GitLabDuplicate
+		case err != nil:
+			return nil, err
+		default:
+			result.Outcome = api.GitLabCreated
+		}
+	case "merge":
+		// As with GitHub, the merge request is merged in GitLab already, so it is recorded as a freeze override.
+		if _, err := s.prCommands.MergePR(ctx, prID, service.MergeOptions{OverrideFreeze: true}); err != nil {
+			return nil, err
+		}
+
+		result.Outcome = api.GitLabMerged
+	case "close":
+		if _, err := s.prCommands.ClosePR(ctx, prID); err != nil {
+			return nil, err
+		}
+
+		result.Outcome = api.GitLabClosed
+	default:
+		result.Outcome = api.GitLabIgnored
+	}
+
+	return result, nil
+}
+
+// shadowGitLabMergeRequest is handleGitLabMergeRequest in shadow mode: the reviewers of an opened merge request
+// are computed but not assigned, and a merged or closed one is left as it is.
+func (s *Server) shadowGitLabMergeRequest(ctx context.Context, log *slog.Logger,
+	event *gitLabMergeRequestEvent,
+) (*api.GitLabWebhookResult, error) {
+	prID := gitLabPullRequestID(event)
+	shadow := true
+	result := &api.GitLabWebhookResult{PullRequestId: &prID, Shadow: &shadow}
+
+	switch event.ObjectAttributes.Action {
+	case "open":
+		authorID, err := s.gitLabUsers.ResolveGitLabUser(ctx, event.User.Username)
+		if errors.Is(err, apperrors.ErrNotFound) {
+			result.Outcome = api.GitLabUnmapped
+			return result, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		preview, err := s.previewShadowAssignment(ctx, log, shadowProviderGitLab, prID, authorID)
+		if err != nil {
+			return nil, err
+		}
+
+		if preview.exists {
+			result.Outcome = api.GitLabDuplicate
+			break
+		}
+
+		strategy := string(preview.preview.Strategy)
+		result.Outcome = api.GitLabCreated
+		result.Reviewers = &preview.preview.ReviewerIDs
+		result.Strategy = &strategy
+	case "merge":
+		result.Outcome = api.GitLabMerged
+	case "close":
+		result.Outcome = api.GitLabClosed
+	default:
+		result.Outcome = api.GitLabIgnored
+	}
+
+	return result, nil
+}
+
+func (s *Server) respondGitLabResult(w http.ResponseWriter, result *api.GitLabWebhookResult) {
+	s.countGitLabDelivery(string(result.Outcome))
+	s.respond(w, http.StatusOK, result)
+}
+
+// countGitLabDelivery counts a delivery by outcome, apart from the live ones in shadow mode.
+func (s *Server) countGitLabDelivery(outcome string) {
+	if s.webhookShadow.GitLab {
+		countShadowEvent(shadowProviderGitLab, outcome)
+		return
+	}
+
+	gitLabWebhookDeliveriesTotal.WithLabelValues(outcome).Inc()
+}
+
+// gitLabPullRequestID is the ID of a GitLab merge request in the service. The project is identified
+// by its ID rather than its path, so that a moved project keeps its pull requests.
+func gitLabPullRequestID(event *gitLabMergeRequestEvent) string {
+	return fmt.Sprintf("gitlab-%d-%d", event.Project.ID, event.ObjectAttributes.IID)
+}
+
+func (s *Server) GetAdminGitlabUsers(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.GetAdminGitlabUsers"
+
+	resp, err := s.gitLabUsers.ListGitLabUsers(r.Context())
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) PostAdminGitlabUsers(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostAdminGitlabUsers"
+
+	var req setGitLabUserRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	mapping, err := s.gitLabUsers.SetGitLabUser(r.Context(), req.GitLabUsername, req.UserID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, api.GitLabUserResponse{GitlabUser: *mapping})
+}
+
+func (s *Server) DeleteAdminGitlabUsersGitlabUsername(w http.ResponseWriter, r *http.Request, gitLabUsername string) {
+	const op = "internal.transport.http.DeleteAdminGitlabUsersGitlabUsername"
+
+	if err := s.gitLabUsers.DeleteGitLabUser(r.Context(), gitLabUsername); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,306 @@
+package http
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/YusovID/pr-reviewer-service/internal/signature"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const gitLabToken = "gitlab-token"
+
+// gitLabDelivery returns a request of a GitLab webhook delivery of body carrying token.
+func gitLabDelivery(event, token, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/gitlab", strings.NewReader(body))
+	req.Header.Set("X-Gitlab-Event", event)
+	req.Header.Set(signature.GitLabTokenHeader, token)
+
+	return req
+}
+
+func gitLabMergeRequestBody(action string) string {
+	return fmt.Sprintf(`{"object_kind":"merge_request","user":{"username":"JDoe"},
+		"project":{"id":15,"path_with_namespace":"group/service"},
+		"object_attributes":{"iid":42,"title":"Add search","description":"Adds full-text search",
+		"url":"https://gitlab.example.com/group/service/-/merge_requests/42","action":%q}}`, action)
+}
+
+func TestServer_PostWebhooksGitlab(t *testing.T) {
+	const prID = "gitlab-15-42"
+
+	description := "Adds full-text search"
+	externalURL := "https://gitlab.example.com/group/service/-/merge_requests/42"
+	details := service.PRDetails{Description: &description, ExternalURL: &externalURL}
+
+	testCases := []struct {
+		name                 string
+		request              *http.Request
+		setupMocks           func(prs *PullRequestServiceMock, users *GitLabUserServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:    "Opened merge request is created for the mapped author",
+			request: gitLabDelivery("Merge Request Hook", gitLabToken, gitLabMergeRequestBody("open")),
+			setupMocks: func(prs *PullRequestServiceMock, users *GitLabUserServiceMock) {
+				users.On("ResolveGitLabUser", mock.Anything, "JDoe").Return("u1", nil).Once()
+				prs.On("CreatePR", mock.Anything, prID, "Add search", "u1", details).Return(&api.PullRequest{}, true, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"outcome":"created","pull_request_id":"gitlab-15-42"}`,
+		},
+		{
+			name:    "Opened merge request that exists is a duplicate",
+			request: gitLabDelivery("Merge Request Hook", gitLabToken, gitLabMergeRequestBody("open")),
+			setupMocks: func(prs *PullRequestServiceMock, users *GitLabUserServiceMock) {
+				users.On("ResolveGitLabUser", mock.Anything, "JDoe").Return("u1", nil).Once()
+				prs.On("CreatePR", mock.Anything, prID, "Add search", "u1", details).
+					Return(nil, false, &apperrors.PRAlreadyExistsError{PRID: prID}).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"outcome":"duplicate","pull_request_id":"gitlab-15-42"}`,
+		},
+		{
+			name:    "Opened merge request of an unmapped author",
+			request: gitLabDelivery("Merge Request Hook", gitLabToken, gitLabMergeRequestBody("open")),
+			setupMocks: func(prs *PullRequestServiceMock, users *GitLabUserServiceMock) {
+				users.On("ResolveGitLabUser", mock.Anything, "JDoe").Return("", apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"outcome":"unmapped","pull_request_id":"gitlab-15-42"}`,
+		},
+		{
+			name:    "Merged merge request is merged despite a freeze",
+			request: gitLabDelivery("Merge Request Hook", gitLabToken, gitLabMergeRequestBody("merge")),
+			setupMocks: func(prs *PullRequestServiceMock, users *GitLabUserServiceMock) {
+				prs.On("MergePR", mock.Anything, prID, service.MergeOptions{OverrideFreeze: true}).Return(&api.MergeResponse{}, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"outcome":"merged","pull_request_id":"gitlab-15-42"}`,
+		},
+		{
+			name:    "Closed merge request is closed",
+			request: gitLabDelivery("Merge Request Hook", gitLabToken, gitLabMergeRequestBody("close")),
+			setupMocks: func(prs *PullRequestServiceMock, users *GitLabUserServiceMock) {
+				prs.On("ClosePR", mock.Anything, prID).Return(&api.PullRequest{}, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"outcome":"closed","pull_request_id":"gitlab-15-42"}`,
+		},
+		{
+			name:    "Closed merge request unknown to the service",
+			request: gitLabDelivery("Merge Request Hook", gitLabToken, gitLabMergeRequestBody("close")),
+			setupMocks: func(prs *PullRequestServiceMock, users *GitLabUserServiceMock) {
+				prs.On("ClosePR", mock.Anything, prID).Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+		{
+			name:                 "Other action is ignored",
+			request:              gitLabDelivery("Merge Request Hook", gitLabToken, gitLabMergeRequestBody("approved")),
+			setupMocks:           func(prs *PullRequestServiceMock, users *GitLabUserServiceMock) {},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"outcome":"ignored","pull_request_id":"gitlab-15-42"}`,
+		},
+		{
+			name:                 "Push event is ignored",
+			request:              gitLabDelivery("Push Hook", gitLabToken, `{"object_kind":"push"}`),
+			setupMocks:           func(prs *PullRequestServiceMock, users *GitLabUserServiceMock) {},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"outcome":"ignored"}`,
+		},
+		{
+			name:               "Merge request without a project",
+			request:            gitLabDelivery("Merge Request Hook", gitLabToken, `{"object_kind":"merge_request","object_attributes":{"iid":42,"title":"Add search","action":"open"}}`),
+			setupMocks:         func(prs *PullRequestServiceMock, users *GitLabUserServiceMock) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:                 "Unknown token",
+			request:              gitLabDelivery("Merge Request Hook", "guessed-token", gitLabMergeRequestBody("open")),
+			setupMocks:           func(prs *PullRequestServiceMock, users *GitLabUserServiceMock) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":{"code":"INVALID_SIGNATURE","message":"invalid signature"}}`,
+		},
+		{
+			name:                 "Missing token",
+			request:              gitLabDelivery("Merge Request Hook", "", gitLabMergeRequestBody("open")),
+			setupMocks:           func(prs *PullRequestServiceMock, users *GitLabUserServiceMock) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":{"code":"INVALID_SIGNATURE","message":"missing signature"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prsMock := new(PullRequestServiceMock)
+			usersMock := new(GitLabUserServiceMock)
+			tc.setupMocks(prsMock, usersMock)
+
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prsMock,
+				WithGitLabWebhook(signature.NewVerifier([]byte(gitLabToken))), WithGitLabUsers(usersMock))
+
+			rr := httptest.NewRecorder()
+			api.Handler(server).ServeHTTP(rr, tc.request)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			if tc.expectedResponseBody != "" {
+				assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			}
+
+			prsMock.AssertExpectations(t)
+			usersMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_PostWebhooksGitlabShadow(t *testing.T) {
+	const prID = "gitlab-15-42"
+
+	testCases := []struct {
+		name                 string
+		request              *http.Request
+		setupMocks           func(prs *PullRequestServiceMock, users *GitLabUserServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:    "Opened merge request gets its reviewers previewed",
+			request: gitLabDelivery("Merge Request Hook", gitLabToken, gitLabMergeRequestBody("open")),
+			setupMocks: func(prs *PullRequestServiceMock, users *GitLabUserServiceMock) {
+				users.On("ResolveGitLabUser", mock.Anything, "JDoe").Return("u1", nil).Once()
+				prs.On("GetPR", mock.Anything, prID).Return(nil, apperrors.ErrNotFound).Once()
+				prs.On("PreviewAssignment", mock.Anything, "u1").Return(&service.AssignmentPreview{
+					TeamID: 1, ReviewerIDs: []string{"u2"}, Strategy: domain.StrategyRandom, Missing: 1,
+				}, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"outcome":"created","pull_request_id":"gitlab-15-42","shadow":true,
+				"reviewers":["u2"],"strategy":"random"}`,
+		},
+		{
+			name:    "Opened merge request of an unmapped author",
+			request: gitLabDelivery("Merge Request Hook", gitLabToken, gitLabMergeRequestBody("open")),
+			setupMocks: func(prs *PullRequestServiceMock, users *GitLabUserServiceMock) {
+				users.On("ResolveGitLabUser", mock.Anything, "JDoe").Return("", apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"outcome":"unmapped","pull_request_id":"gitlab-15-42","shadow":true}`,
+		},
+		{
+			name:    "Opened merge request of an author without a team",
+			request: gitLabDelivery("Merge Request Hook", gitLabToken, gitLabMergeRequestBody("open")),
+			setupMocks: func(prs *PullRequestServiceMock, users *GitLabUserServiceMock) {
+				users.On("ResolveGitLabUser", mock.Anything, "JDoe").Return("u1", nil).Once()
+				prs.On("GetPR", mock.Anything, prID).Return(nil, apperrors.ErrNotFound).Once()
+				prs.On("PreviewAssignment", mock.Anything, "u1").Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:                 "Merged merge request is left open",
+			request:              gitLabDelivery("Merge Request Hook", gitLabToken, gitLabMergeRequestBody("merge")),
+			setupMocks:           func(prs *PullRequestServiceMock, users *GitLabUserServiceMock) {},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"outcome":"merged","pull_request_id":"gitlab-15-42","shadow":true}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prsMock := new(PullRequestServiceMock)
+			usersMock := new(GitLabUserServiceMock)
+			tc.setupMocks(prsMock, usersMock)
+
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prsMock,
+				WithGitLabWebhook(signature.NewVerifier([]byte(gitLabToken))), WithGitLabUsers(usersMock),
+				WithWebhookShadow(config.WebhookShadow{GitLab: true}))
+
+			rr := httptest.NewRecorder()
+			api.Handler(server).ServeHTTP(rr, tc.request)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			if tc.expectedResponseBody != "" {
+				assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			}
+
+			prsMock.AssertExpectations(t)
+			usersMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_PostWebhooksGitlabNotConfigured(t *testing.T) {
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithGitLabUsers(new(GitLabUserServiceMock)))
+
+	rr := httptest.NewRecorder()
+	api.Handler(server).ServeHTTP(rr, gitLabDelivery("Merge Request Hook", gitLabToken, `{}`))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestServer_AdminGitlabUsers(t *testing.T) {
+	createdAt := time.Date(2025, time.December, 22, 18, 0, 0, 0, time.UTC)
+	mapping := api.GitLabUser{GitlabUsername: "jdoe", UserId: "u1", CreatedAt: createdAt}
+	total := 1
+
+	usersMock := new(GitLabUserServiceMock)
+	usersMock.On("SetGitLabUser", mock.Anything, "JDoe", "u1").Return(&mapping, nil).Once()
+	usersMock.On("SetGitLabUser", mock.Anything, "jdoe", "ghost").Return(nil, apperrors.ErrNotFound).Once()
+	usersMock.On("ListGitLabUsers", mock.Anything).
+		Return(&api.ListGitLabUsersResponse{Items: []api.GitLabUser{mapping}, TotalEstimate: &total}, nil).Once()
+	usersMock.On("DeleteGitLabUser", mock.Anything, "jdoe").Return(nil).Once()
+	usersMock.On("DeleteGitLabUser", mock.Anything, "ghost").Return(apperrors.ErrNotFound).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithGitLabUsers(usersMock))
+	router := api.Handler(server)
+
+	expectedMapping := `{"gitlab_username":"jdoe","user_id":"u1","created_at":"2025-12-22T18:00:00Z"}`
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/gitlabUsers", strings.NewReader(`{"gitlab_username":"JDoe","user_id":"u1"}`)))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"gitlab_user":`+expectedMapping+`}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/gitlabUsers", strings.NewReader(`{"gitlab_username":"jdoe","user_id":"ghost"}`)))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/gitlabUsers", strings.NewReader(`{"gitlab_username":"jdoe"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/gitlabUsers", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"items":[`+expectedMapping+`],"next_cursor":null,"total_estimate":1}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/admin/gitlabUsers/jdoe", nil))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/admin/gitlabUsers/ghost", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	usersMock.AssertExpectations(t)
+}
@@ -3,30 +3,22 @@ package http
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
-	"github.com/prometheus/client_golang/prometheus"
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/go-chi/chi/v5"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 var (
 	// Метрика общего количества запросов (Counter)
-	httpRequestsTotal = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "http_requests_total",
-			Help: "Total number of HTTP requests",
-		},
-		[]string{"path", "method", "status"},
-	)
+	httpRequestsTotal = promauto.NewCounterVec(metrics.HTTPRequests.CounterOpts(), metrics.HTTPRequests.Labels)
 
 	// Метрика времени выполнения запросов (Histogram)
 	httpRequestDuration = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "http_request_duration_seconds",
-			Help:    "Duration of HTTP requests in seconds",
-			Buckets: prometheus.DefBuckets,
-		},
-		[]string{"path", "method"},
+		metrics.HTTPRequestDuration.HistogramOpts(metrics.HTTPDurationBuckets),
+		metrics.HTTPRequestDuration.Labels,
 	)
 )
 
@@ -58,10 +50,19 @@ func (s *Server) metricsMiddleware(next http.Handler) http.Handler {
 		// Записываем метрики
 		statusCode := strconv.Itoa(wrapper.statusCode)
 
+		if isSwaggerRequest(r) {
+			swaggerRequestsTotal.WithLabelValues(statusCode).Inc()
+			return
+		}
+
 		// Используем route pattern (если возможно) или r.URL.Path
 		// Примечание: в чистом chi сложно получить pattern в middleware без дополнительных усилий,
 		// поэтому для простоты используем Path. В продакшене лучше группировать ID.
 		path := r.URL.Path
+		// Маршруты с параметрами в пути (/jobs/{job_id}) помечаем шаблоном, чтобы число серий не росло с числом ID.
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && strings.Contains(rctx.RoutePattern(), "{") {
+			path = rctx.RoutePattern()
+		}
 
 		httpRequestsTotal.WithLabelValues(path, r.Method, statusCode).Inc()
 		httpRequestDuration.WithLabelValues(path, r.Method).Observe(duration)
@@ -20,13 +20,19 @@ func (s *Server) logRequest(next http.Handler) http.Handler {
 			slog.String("remote_addr", r.RemoteAddr),
 			slog.String("user_agent", r.UserAgent()),
 		)
-		log.Info("request started")
+		// The swagger UI loads a dozen assets per page view, which would drown the API requests.
+		level := slog.LevelInfo
+		if isSwaggerRequest(r) {
+			level = slog.LevelDebug
+		}
+
+		log.Log(r.Context(), level, "request started")
 
 		t1 := time.Now()
 
 		next.ServeHTTP(w, r)
 
-		log.Info("request completed",
+		log.Log(r.Context(), level, "request completed",
 			slog.String("duration", time.Since(t1).String()),
 		)
 	})
@@ -2,7 +2,11 @@ package http
 
 import (
 	"context"
+	"time"
 
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/oidc"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
 	"github.com/stretchr/testify/mock"
 )
@@ -29,33 +33,156 @@ func (m *TeamServiceMock) GetTeam(ctx context.Context, name string) (*api.Team,
 	return args.Get(0).(*api.Team), args.Error(1)
 }
 
+func (m *TeamServiceMock) GetTeamByID(ctx context.Context, id int) (*api.Team, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.Team), args.Error(1)
+}
+
+func (m *TeamServiceMock) RenameTeam(ctx context.Context, teamName, newTeamName string) (*api.Team, error) {
+	args := m.Called(ctx, teamName, newTeamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.Team), args.Error(1)
+}
+
+func (m *TeamServiceMock) SetTeamPolicy(ctx context.Context, policy api.TeamPolicy) (*api.TeamPolicy, error) {
+	args := m.Called(ctx, policy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.TeamPolicy), args.Error(1)
+}
+
+func (m *TeamServiceMock) GetTeamPolicy(ctx context.Context, teamName string) (*api.TeamPolicy, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.TeamPolicy), args.Error(1)
+}
+
+func (m *TeamServiceMock) GetEffectivePolicy(ctx context.Context, teamName string) (*api.EffectivePolicy, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.EffectivePolicy), args.Error(1)
+}
+
+func (m *TeamServiceMock) SetCustomFields(ctx context.Context, fields api.TeamCustomFields) (*api.TeamCustomFields, error) {
+	args := m.Called(ctx, fields)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.TeamCustomFields), args.Error(1)
+}
+
+func (m *TeamServiceMock) GetCustomFields(ctx context.Context, teamName string) (*api.TeamCustomFields, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.TeamCustomFields), args.Error(1)
+}
+
+func (m *TeamServiceMock) RequestReviewerBorrow(ctx context.Context, teamName, lenderTeamName string, count, durationHours int) (*api.ReviewerBorrow, error) {
+	args := m.Called(ctx, teamName, lenderTeamName, count, durationHours)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.ReviewerBorrow), args.Error(1)
+}
+
+func (m *TeamServiceMock) AcceptReviewerBorrow(ctx context.Context, borrowID int64) (*api.ReviewerBorrow, error) {
+	args := m.Called(ctx, borrowID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.ReviewerBorrow), args.Error(1)
+}
+
+func (m *TeamServiceMock) ListReviewerBorrows(ctx context.Context, teamName string) ([]api.ReviewerBorrow, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]api.ReviewerBorrow), args.Error(1)
+}
+
 type UserServiceMock struct {
 	mock.Mock
 }
 
-func (m *UserServiceMock) SetIsActive(ctx context.Context, userID string, isActive bool) (*api.User, error) {
+func (m *UserServiceMock) SetIsActive(ctx context.Context, userID string, isActive bool) (*api.SetIsActiveResponse, error) {
 	args := m.Called(ctx, userID, isActive)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 
-	return args.Get(0).(*api.User), args.Error(1)
+	return args.Get(0).(*api.SetIsActiveResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) SetRole(ctx context.Context, userID string, role string) (*api.SetUserRoleResponse, error) {
+	args := m.Called(ctx, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.SetUserRoleResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) GetRole(ctx context.Context, userID string) (domain.UserRole, error) {
+	args := m.Called(ctx, userID)
+
+	return args.Get(0).(domain.UserRole), args.Error(1)
+}
+
+func (m *UserServiceMock) RebalanceReviews(ctx context.Context, userID string) ([]api.ReviewerMove, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]api.ReviewerMove), args.Error(1)
 }
 
 type PullRequestServiceMock struct {
 	mock.Mock
 }
 
-func (m *PullRequestServiceMock) CreatePR(ctx context.Context, prID string, prName string, authorID string) (*api.PullRequest, error) {
-	args := m.Called(ctx, prID, prName, authorID)
+func (m *PullRequestServiceMock) CreatePR(ctx context.Context, prID string, prName string, authorID string, details service.PRDetails) (*api.PullRequest, bool, error) {
+	args := m.Called(ctx, prID, prName, authorID, details)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+
+	return args.Get(0).(*api.PullRequest), args.Bool(1), args.Error(2)
+}
+
+func (m *PullRequestServiceMock) MergePR(ctx context.Context, prID string, opts service.MergeOptions) (*api.MergeResponse, error) {
+	args := m.Called(ctx, prID, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 
-	return args.Get(0).(*api.PullRequest), args.Error(1)
+	return args.Get(0).(*api.MergeResponse), args.Error(1)
 }
 
-func (m *PullRequestServiceMock) MergePR(ctx context.Context, prID string) (*api.PullRequest, error) {
+func (m *PullRequestServiceMock) ClosePR(ctx context.Context, prID string) (*api.PullRequest, error) {
 	args := m.Called(ctx, prID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -64,8 +191,35 @@ func (m *PullRequestServiceMock) MergePR(ctx context.Context, prID string) (*api
 	return args.Get(0).(*api.PullRequest), args.Error(1)
 }
 
-func (m *PullRequestServiceMock) ReassignReviewer(ctx context.Context, prID string, oldReviewerID string) (*api.ReassignResponse, error) {
-	args := m.Called(ctx, prID, oldReviewerID)
+func (m *PullRequestServiceMock) ApprovePR(ctx context.Context, prID string, reviewerID string) (*api.PullRequest, error) {
+	args := m.Called(ctx, prID, reviewerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.PullRequest), args.Error(1)
+}
+
+func (m *PullRequestServiceMock) RequestChanges(ctx context.Context, prID string, reviewerID string) (*api.PullRequest, error) {
+	args := m.Called(ctx, prID, reviewerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.PullRequest), args.Error(1)
+}
+
+func (m *PullRequestServiceMock) SubscribePR(ctx context.Context, prID string, userID string) (*api.PullRequestSubscription, bool, error) {
+	args := m.Called(ctx, prID, userID)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+
+	return args.Get(0).(*api.PullRequestSubscription), args.Bool(1), args.Error(2)
+}
+
+func (m *PullRequestServiceMock) ReassignReviewer(ctx context.Context, prID string, oldReviewerID string, note string) (*api.ReassignResponse, error) {
+	args := m.Called(ctx, prID, oldReviewerID, note)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -73,8 +227,8 @@ func (m *PullRequestServiceMock) ReassignReviewer(ctx context.Context, prID stri
 	return args.Get(0).(*api.ReassignResponse), args.Error(1)
 }
 
-func (m *PullRequestServiceMock) GetReviewAssignments(ctx context.Context, userID string) (*api.GetReviewResponse, error) {
-	args := m.Called(ctx, userID)
+func (m *PullRequestServiceMock) GetReviewAssignments(ctx context.Context, userID string, customFields []string) (*api.GetReviewResponse, error) {
+	args := m.Called(ctx, userID, customFields)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -82,16 +236,576 @@ func (m *PullRequestServiceMock) GetReviewAssignments(ctx context.Context, userI
 	return args.Get(0).(*api.GetReviewResponse), args.Error(1)
 }
 
-func (m *PullRequestServiceMock) GetStats(ctx context.Context) (*api.StatsResponse, error) {
+func (m *PullRequestServiceMock) SearchPRs(ctx context.Context, query service.PRSearchQuery) (*api.SearchPullRequestsResponse, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.SearchPullRequestsResponse), args.Error(1)
+}
+
+func (m *PullRequestServiceMock) ListPRs(ctx context.Context, query service.PRListQuery) (*api.ListPullRequestsResponse, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.ListPullRequestsResponse), args.Error(1)
+}
+
+func (m *PullRequestServiceMock) GetPendingAssignments(ctx context.Context, teamName string, limit int) (*api.PendingAssignmentsResponse, error) {
+	args := m.Called(ctx, teamName, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.PendingAssignmentsResponse), args.Error(1)
+}
+
+func (m *PullRequestServiceMock) FillPendingAssignments(ctx context.Context, limit int) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *PullRequestServiceMock) RequeueNeedingReviewers(ctx context.Context, limit int) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *PullRequestServiceMock) RefreshStats(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *PullRequestServiceMock) BackfillReviewerDurations(ctx context.Context, afterID string, limit int) (string, int, error) {
+	args := m.Called(ctx, afterID, limit)
+	return args.String(0), args.Int(1), args.Error(2)
+}
+
+func (m *PullRequestServiceMock) GetOpenPRAgeStats(ctx context.Context) ([]domain.OpenPRAgeStats, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 
+	return args.Get(0).([]domain.OpenPRAgeStats), args.Error(1)
+}
+
+func (m *PullRequestServiceMock) EnqueueCreatePR(ctx context.Context, prID string, prName string, authorID string, details service.PRDetails) (*api.AsyncCreateRequest, error) {
+	args := m.Called(ctx, prID, prName, authorID, details)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.AsyncCreateRequest), args.Error(1)
+}
+
+func (m *PullRequestServiceMock) GetCreatePRRequest(ctx context.Context, requestID int64) (*api.AsyncCreateRequest, error) {
+	args := m.Called(ctx, requestID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.AsyncCreateRequest), args.Error(1)
+}
+
+func (m *PullRequestServiceMock) PreviewAssignment(ctx context.Context, authorID string) (*service.AssignmentPreview, error) {
+	args := m.Called(ctx, authorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*service.AssignmentPreview), args.Error(1)
+}
+
+func (m *PullRequestServiceMock) ClaimCreatePRRequests(ctx context.Context, limit int) ([]domain.CreatePRRequest, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.CreatePRRequest), args.Error(1)
+}
+
+func (m *PullRequestServiceMock) ProcessCreatePRRequest(ctx context.Context, req domain.CreatePRRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *PullRequestServiceMock) GetStats(ctx context.Context, period domain.StatsPeriod) (*api.StatsResponse, error) {
+	args := m.Called(ctx, period)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
 	return args.Get(0).(*api.StatsResponse), args.Error(1)
 }
 
-func (m *UserServiceMock) DeactivateTeam(ctx context.Context, teamName string) (deactivatedCount int, reassignedCount int, err error) {
-	args := m.Called(ctx, teamName)
-	return args.Int(0), args.Int(1), args.Error(2)
+func (m *PullRequestServiceMock) GetLeaderboard(ctx context.Context, period domain.LeaderboardPeriod, limit int) (*api.LeaderboardResponse, error) {
+	args := m.Called(ctx, period, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.LeaderboardResponse), args.Error(1)
+}
+
+func (m *PullRequestServiceMock) GetPR(ctx context.Context, prID string) (*api.PullRequest, error) {
+	args := m.Called(ctx, prID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.PullRequest), args.Error(1)
+}
+
+func (m *PullRequestServiceMock) ExpandReviewers(ctx context.Context, pr *api.PullRequest) error {
+	args := m.Called(ctx, pr)
+	return args.Error(0)
+}
+
+func (m *PullRequestServiceMock) GetAssignmentHistory(ctx context.Context, prID string) (*api.AssignmentHistoryResponse, error) {
+	args := m.Called(ctx, prID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.AssignmentHistoryResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) DeactivateTeam(ctx context.Context, teamName string, force bool) (*api.DeactivateTeamResponse, error) {
+	args := m.Called(ctx, teamName, force)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.DeactivateTeamResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) PreviewTeamDeactivation(ctx context.Context, teamName string, force bool) (*api.DeactivateTeamResponse, error) {
+	args := m.Called(ctx, teamName, force)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.DeactivateTeamResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) ReactivateTeam(ctx context.Context, teamName string, restoreReviewers bool) (*api.ReactivateTeamResponse, error) {
+	args := m.Called(ctx, teamName, restoreReviewers)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.ReactivateTeamResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) DeactivateTeamInBatches(ctx context.Context, teamName string, batchSize int) (*api.DeactivationJob, error) {
+	args := m.Called(ctx, teamName, batchSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.DeactivationJob), args.Error(1)
+}
+
+func (m *UserServiceMock) GetDeactivationJob(ctx context.Context, jobID int64) (*api.DeactivationJob, error) {
+	args := m.Called(ctx, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.DeactivationJob), args.Error(1)
+}
+
+func (m *UserServiceMock) TransferUser(ctx context.Context, userID string, teamName string, reassignReviews bool) (*api.TransferUserResponse, error) {
+	args := m.Called(ctx, userID, teamName, reassignReviews)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.TransferUserResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) DeleteUser(ctx context.Context, userID string) (*api.DeleteUserResponse, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.DeleteUserResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) RestoreUser(ctx context.Context, userID string) (*api.User, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.User), args.Error(1)
+}
+
+func (m *UserServiceMock) DeleteTeam(ctx context.Context, teamName string, force bool) (*api.DeleteTeamResponse, error) {
+	args := m.Called(ctx, teamName, force)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.DeleteTeamResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) RestoreTeam(ctx context.Context, teamID int) (*api.Team, error) {
+	args := m.Called(ctx, teamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.Team), args.Error(1)
+}
+
+func (m *UserServiceMock) ProcessDeactivationBatch(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+type JobServiceMock struct {
+	mock.Mock
+}
+
+func (m *JobServiceMock) CreateJob(ctx context.Context, jobType api.JobType, params map[string]any) (*api.Job, error) {
+	args := m.Called(ctx, jobType, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.Job), args.Error(1)
+}
+
+func (m *JobServiceMock) GetJob(ctx context.Context, id int64) (*api.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.Job), args.Error(1)
+}
+
+func (m *JobServiceMock) CancelJob(ctx context.Context, id int64) (*api.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.Job), args.Error(1)
+}
+
+func (m *JobServiceMock) RunNextJob(ctx context.Context, owner string) (bool, error) {
+	args := m.Called(ctx, owner)
+	return args.Bool(0), args.Error(1)
+}
+
+type NotificationServiceMock struct {
+	mock.Mock
+}
+
+func (m *NotificationServiceMock) Notify(ctx context.Context, event domain.Event) {
+	m.Called(ctx, event)
+}
+
+func (m *NotificationServiceMock) ListDeliveries(ctx context.Context, query service.DeliveryQuery) (*api.ListNotificationDeliveriesResponse, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.ListNotificationDeliveriesResponse), args.Error(1)
+}
+
+func (m *NotificationServiceMock) RetryDelivery(ctx context.Context, id int64) (*api.NotificationDelivery, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.NotificationDelivery), args.Error(1)
+}
+
+type FreezeServiceMock struct {
+	mock.Mock
+}
+
+func (m *FreezeServiceMock) CreateFreezeWindow(ctx context.Context, teamName string, reason string, startsAt, endsAt time.Time) (*api.FreezeWindow, error) {
+	args := m.Called(ctx, teamName, reason, startsAt, endsAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.FreezeWindow), args.Error(1)
+}
+
+func (m *FreezeServiceMock) ListFreezeWindows(ctx context.Context, teamName string, includeEnded bool) (*api.ListFreezeWindowsResponse, error) {
+	args := m.Called(ctx, teamName, includeEnded)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.ListFreezeWindowsResponse), args.Error(1)
+}
+
+func (m *FreezeServiceMock) DeleteFreezeWindow(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type GitLabUserServiceMock struct {
+	mock.Mock
+}
+
+func (m *GitLabUserServiceMock) SetGitLabUser(ctx context.Context, gitLabUsername, userID string) (*api.GitLabUser, error) {
+	args := m.Called(ctx, gitLabUsername, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.GitLabUser), args.Error(1)
+}
+
+func (m *GitLabUserServiceMock) ResolveGitLabUser(ctx context.Context, gitLabUsername string) (string, error) {
+	args := m.Called(ctx, gitLabUsername)
+	return args.String(0), args.Error(1)
+}
+
+func (m *GitLabUserServiceMock) ListGitLabUsers(ctx context.Context) (*api.ListGitLabUsersResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.ListGitLabUsersResponse), args.Error(1)
+}
+
+func (m *GitLabUserServiceMock) DeleteGitLabUser(ctx context.Context, gitLabUsername string) error {
+	args := m.Called(ctx, gitLabUsername)
+	return args.Error(0)
+}
+
+type OIDCSubjectServiceMock struct {
+	mock.Mock
+}
+
+func (m *OIDCSubjectServiceMock) SetOIDCSubject(ctx context.Context, subject, userID string) (*api.OidcSubject, error) {
+	args := m.Called(ctx, subject, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.OidcSubject), args.Error(1)
+}
+
+func (m *OIDCSubjectServiceMock) ResolveOIDCSubject(ctx context.Context, subject string) (string, error) {
+	args := m.Called(ctx, subject)
+	return args.String(0), args.Error(1)
+}
+
+func (m *OIDCSubjectServiceMock) ListOIDCSubjects(ctx context.Context) (*api.ListOidcSubjectsResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.ListOidcSubjectsResponse), args.Error(1)
+}
+
+func (m *OIDCSubjectServiceMock) DeleteOIDCSubject(ctx context.Context, subject string) error {
+	args := m.Called(ctx, subject)
+	return args.Error(0)
+}
+
+type OIDCProviderMock struct {
+	mock.Mock
+}
+
+func (m *OIDCProviderMock) AuthCodeURL(state, nonce, verifier string) string {
+	return "https://idp.example.com/authorize?state=" + state + "&nonce=" + nonce + "&verifier=" + verifier
+}
+
+func (m *OIDCProviderMock) Exchange(ctx context.Context, code, verifier string) (string, error) {
+	args := m.Called(ctx, code, verifier)
+	return args.String(0), args.Error(1)
+}
+
+func (m *OIDCProviderMock) Verify(ctx context.Context, rawIDToken, nonce string) (*oidc.Claims, error) {
+	args := m.Called(ctx, rawIDToken, nonce)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*oidc.Claims), args.Error(1)
+}
+
+type SlackUserServiceMock struct {
+	mock.Mock
+}
+
+func (m *SlackUserServiceMock) SetSlackUser(ctx context.Context, userID, slackUserID string) (*api.SlackUser, error) {
+	args := m.Called(ctx, userID, slackUserID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.SlackUser), args.Error(1)
+}
+
+func (m *SlackUserServiceMock) ListSlackUsers(ctx context.Context) (*api.ListSlackUsersResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.ListSlackUsersResponse), args.Error(1)
+}
+
+func (m *SlackUserServiceMock) DeleteSlackUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type WebhookServiceMock struct {
+	mock.Mock
+}
+
+func (m *WebhookServiceMock) CreateWebhook(ctx context.Context, input service.WebhookInput) (*api.Webhook, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.Webhook), args.Error(1)
+}
+
+func (m *WebhookServiceMock) ListWebhooks(ctx context.Context) (*api.ListWebhooksResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.ListWebhooksResponse), args.Error(1)
+}
+
+func (m *WebhookServiceMock) GetWebhook(ctx context.Context, id int64) (*api.Webhook, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.Webhook), args.Error(1)
+}
+
+func (m *WebhookServiceMock) UpdateWebhook(ctx context.Context, id int64, input service.WebhookInput) (*api.Webhook, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.Webhook), args.Error(1)
+}
+
+func (m *WebhookServiceMock) DeleteWebhook(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *WebhookServiceMock) ListWebhookDeliveries(ctx context.Context, query service.WebhookDeliveryQuery) (*api.ListWebhookDeliveriesResponse, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.ListWebhookDeliveriesResponse), args.Error(1)
+}
+
+func (m *WebhookServiceMock) RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID int64) (*api.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, deliveryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.WebhookDelivery), args.Error(1)
+}
+
+func (m *WebhookServiceMock) ClaimWebhookDeliveries(ctx context.Context, limit int) ([]domain.WebhookDelivery, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *WebhookServiceMock) ProcessWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+type ReadTokenServiceMock struct {
+	mock.Mock
+}
+
+func (m *ReadTokenServiceMock) CreateReadToken(ctx context.Context, name string, expiresAt *time.Time) (*api.ReadTokenCreatedResponse, error) {
+	args := m.Called(ctx, name, expiresAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.ReadTokenCreatedResponse), args.Error(1)
+}
+
+func (m *ReadTokenServiceMock) ListReadTokens(ctx context.Context) (*api.ListReadTokensResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.ListReadTokensResponse), args.Error(1)
+}
+
+func (m *ReadTokenServiceMock) DeleteReadToken(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *ReadTokenServiceMock) AuthenticateReadToken(ctx context.Context, token string) (*domain.ReadToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.ReadToken), args.Error(1)
+}
+
+type APIKeyServiceMock struct {
+	mock.Mock
+}
+
+func (m *APIKeyServiceMock) CreateAPIKey(ctx context.Context, name string, scopes []string, expiresAt *time.Time, userID *string) (*api.ApiKeyCreatedResponse, error) {
+	args := m.Called(ctx, name, scopes, expiresAt, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.ApiKeyCreatedResponse), args.Error(1)
+}
+
+func (m *APIKeyServiceMock) ListAPIKeys(ctx context.Context) (*api.ListApiKeysResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*api.ListApiKeysResponse), args.Error(1)
+}
+
+func (m *APIKeyServiceMock) DeleteAPIKey(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *APIKeyServiceMock) AuthenticateAPIKey(ctx context.Context, key string) (*domain.APIKey, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.APIKey), args.Error(1)
 }
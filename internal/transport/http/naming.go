@@ -0,0 +1,121 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
+)
+
+// fieldNaming selects how the fields of a JSON response are named.
+type fieldNaming string
+
+const (
+	// fieldNamingLegacy is the output of the unversioned routes: mostly snake_case,
+	// with the camelCase fields the API has always returned.
+	fieldNamingLegacy fieldNaming = "legacy"
+	// fieldNamingSnakeCase names every field in snake_case. It is the default under /v1.
+	fieldNamingSnakeCase fieldNaming = "snake_case"
+
+	// fieldNamingHeader lets a client pick the naming regardless of the route it calls.
+	fieldNamingHeader = "X-Field-Naming"
+)
+
+// legacyFieldNames maps the fields the legacy output names in camelCase to their snake_case names.
+// Add an entry here instead of renaming a field in the spec, so that existing clients keep working.
+var legacyFieldNames = map[string]string{
+	"createdAt": "created_at",
+	"mergedAt":  "merged_at",
+}
+
+// fieldNames serves responses in the naming requested in the X-Field-Naming header,
+// or in defaultNaming if the header is absent. The naming used is echoed in the response header.
+func (s *Server) fieldNames(defaultNaming fieldNaming) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			naming := defaultNaming
+			if requested := r.Header.Get(fieldNamingHeader); requested != "" {
+				naming = fieldNaming(requested)
+			}
+
+			switch naming {
+			case fieldNamingLegacy:
+				w.Header().Set(fieldNamingHeader, string(naming))
+				next.ServeHTTP(w, r)
+
+				return
+			case fieldNamingSnakeCase:
+				w.Header().Set(fieldNamingHeader, string(naming))
+			default:
+				s.respondError(w, http.StatusBadRequest,
+					fmt.Sprintf("validation failed: unknown %s '%s', expected '%s' or '%s'",
+						fieldNamingHeader, naming, fieldNamingLegacy, fieldNamingSnakeCase))
+
+				return
+			}
+
+			buffered := &bufferingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(buffered, r)
+
+			body := renameLegacyFields(buffered.body.Bytes())
+
+			w.Header().Del("Content-Length")
+			w.WriteHeader(buffered.statusCode)
+
+			if _, err := w.Write(body); err != nil {
+				s.log.Error("failed to write response", sl.Err(err))
+			}
+		})
+	}
+}
+
+// renameLegacyFields returns body with the legacy camelCase fields renamed to snake_case at any depth.
+// Bodies that are not JSON are returned unchanged.
+func renameLegacyFields(body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return body
+	}
+
+	if !renameFields(value) {
+		return body
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(value); err != nil {
+		return body
+	}
+
+	return buf.Bytes()
+}
+
+// renameFields renames the legacy fields of value in place and reports whether any was found.
+func renameFields(value any) bool {
+	renamed := false
+
+	switch v := value.(type) {
+	case map[string]any:
+		for legacy, snake := range legacyFieldNames {
+			if field, ok := v[legacy]; ok {
+				delete(v, legacy)
+				v[snake] = field
+				renamed = true
+			}
+		}
+
+		for _, field := range v {
+			renamed = renameFields(field) || renamed
+		}
+	case []any:
+		for _, item := range v {
+			renamed = renameFields(item) || renamed
+		}
+	}
+
+	return renamed
+}
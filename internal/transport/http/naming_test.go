@@ -0,0 +1,113 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestServer_FieldNaming(t *testing.T) {
+	createdAt := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+	pr := &api.PullRequest{
+		PullRequestId:     "pr-1",
+		PullRequestName:   "Feature",
+		AuthorId:          "author-1",
+		Status:            api.PullRequestStatusOPEN,
+		AssignedReviewers: []string{"reviewer-1"},
+		CreatedAt:         &createdAt,
+	}
+
+	legacyBody := `{"pr":{"pull_request_id":"pr-1","pull_request_name":"Feature","author_id":"author-1","status":"OPEN",
+		"assigned_reviewers":["reviewer-1"],"createdAt":"2025-03-14T12:00:00Z","mergedAt":null}}`
+	snakeCaseBody := `{"pr":{"pull_request_id":"pr-1","pull_request_name":"Feature","author_id":"author-1","status":"OPEN",
+		"assigned_reviewers":["reviewer-1"],"created_at":"2025-03-14T12:00:00Z","merged_at":null}}`
+
+	testCases := []struct {
+		name                 string
+		path                 string
+		naming               string
+		expectedStatusCode   int
+		expectedNaming       string
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Legacy route keeps camelCase fields",
+			path:                 "/pullRequest/get?pull_request_id=pr-1",
+			expectedStatusCode:   http.StatusOK,
+			expectedNaming:       "legacy",
+			expectedResponseBody: legacyBody,
+		},
+		{
+			name:                 "v1 route names every field in snake_case",
+			path:                 "/v1/pullRequest/get?pull_request_id=pr-1",
+			expectedStatusCode:   http.StatusOK,
+			expectedNaming:       "snake_case",
+			expectedResponseBody: snakeCaseBody,
+		},
+		{
+			name:                 "Header opts a legacy route into snake_case",
+			path:                 "/pullRequest/get?pull_request_id=pr-1",
+			naming:               "snake_case",
+			expectedStatusCode:   http.StatusOK,
+			expectedNaming:       "snake_case",
+			expectedResponseBody: snakeCaseBody,
+		},
+		{
+			name:                 "Header keeps legacy output under v1",
+			path:                 "/v1/pullRequest/get?pull_request_id=pr-1",
+			naming:               "legacy",
+			expectedStatusCode:   http.StatusOK,
+			expectedNaming:       "legacy",
+			expectedResponseBody: legacyBody,
+		},
+		{
+			name:                 "Unknown naming is rejected",
+			path:                 "/v1/pullRequest/get?pull_request_id=pr-1",
+			naming:               "camelCase",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: unknown X-Field-Naming 'camelCase', expected 'legacy' or 'snake_case'"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prServiceMock := new(PullRequestServiceMock)
+			if tc.expectedStatusCode == http.StatusOK {
+				prServiceMock.On("GetPR", mock.Anything, "pr-1").Return(pr, nil).Once()
+			}
+
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.naming != "" {
+				req.Header.Set(fieldNamingHeader, tc.naming)
+			}
+
+			rr := httptest.NewRecorder()
+			server.Routes().ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			assert.Equal(t, tc.expectedNaming, rr.Header().Get(fieldNamingHeader))
+			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			prServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRenameLegacyFields(t *testing.T) {
+	body := `{"items":[{"createdAt":"t1","nested":{"mergedAt":null}}],"count":12345678901234567890}` + "\n"
+	expected := `{"count":12345678901234567890,"items":[{"created_at":"t1","nested":{"merged_at":null}}]}` + "\n"
+
+	assert.Equal(t, expected, string(renameLegacyFields([]byte(body))))
+
+	unchanged := `{"user_id":"u1"}`
+	assert.Equal(t, unchanged, string(renameLegacyFields([]byte(unchanged))))
+	assert.Equal(t, "not json", string(renameLegacyFields([]byte("not json"))))
+}
@@ -0,0 +1,193 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/oidc"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// The cookie keeping the state, nonce and code verifier of a sign-in between /auth/login and /auth/callback,
+// and how long in seconds the sign-in may take.
+const (
+	oidcLoginCookie       = "pr_reviewer_oidc_login"
+	oidcLoginCookieMaxAge = 10 * 60
+)
+
+// OIDCProvider signs the users in through the identity provider and verifies the ID tokens it issues;
+// *oidc.Provider is the one used in production.
+type OIDCProvider interface {
+	AuthCodeURL(state, nonce, verifier string) string
+	Exchange(ctx context.Context, code, verifier string) (string, error)
+	Verify(ctx context.Context, rawIDToken, nonce string) (*oidc.Claims, error)
+}
+
+// WithOIDC serves the /auth/login and /auth/callback endpoints with p and accepts the ID tokens of p as bearer
+// tokens. The subjects of the tokens are resolved through the service passed to WithOIDCSubjects.
+// Without both options the endpoints respond with 404 and ID tokens are not accepted.
+func WithOIDC(p OIDCProvider) ServerOption {
+	return func(s *Server) {
+		s.oidc = p
+	}
+}
+
+// WithOIDCSubjects serves the /admin/oidcSubjects endpoints with os.
+func WithOIDCSubjects(os service.OIDCSubjectService) ServerOption {
+	return func(s *Server) {
+		s.oidcSubjects = os
+	}
+}
+
+// oidcEnabled reports whether the users can sign in through the identity provider.
+func (s *Server) oidcEnabled() bool {
+	return s.oidc != nil && s.oidcSubjects != nil
+}
+
+func (s *Server) GetAuthLogin(w http.ResponseWriter, r *http.Request) {
+	if !s.oidcEnabled() {
+		s.respondAPIError(w, http.StatusNotFound, api.NOTFOUND, "oidc is not configured")
+		return
+	}
+
+	state, nonce, verifier := rand.Text(), rand.Text(), rand.Text()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcLoginCookie,
+		Value:    strings.Join([]string{state, nonce, verifier}, "."),
+		Path:     "/",
+		MaxAge:   oidcLoginCookieMaxAge,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		// Lax, so that the cookie comes along with the redirect of the provider back to the callback.
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, s.oidc.AuthCodeURL(state, nonce, verifier), http.StatusFound)
+}
+
+func (s *Server) GetAuthCallback(w http.ResponseWriter, r *http.Request, params api.GetAuthCallbackParams) {
+	const op = "internal.transport.http.GetAuthCallback"
+
+	if !s.oidcEnabled() {
+		s.respondAPIError(w, http.StatusNotFound, api.NOTFOUND, "oidc is not configured")
+		return
+	}
+
+	cookie, err := r.Cookie(oidcLoginCookie)
+	if err != nil {
+		s.respondAPIError(w, http.StatusUnauthorized, api.UNAUTHORIZED, "sign-in was not started in this browser or has expired")
+		return
+	}
+
+	parts := strings.Split(cookie.Value, ".")
+	if len(parts) != 3 || subtle.ConstantTimeCompare([]byte(parts[0]), []byte(params.State)) != 1 {
+		s.respondAPIError(w, http.StatusUnauthorized, api.UNAUTHORIZED, "sign-in state mismatch")
+		return
+	}
+
+	// The sign-in is over whatever its outcome: the code of the provider is good for one exchange only.
+	http.SetCookie(w, &http.Cookie{Name: oidcLoginCookie, Path: "/", MaxAge: -1, HttpOnly: true, Secure: isHTTPS(r)})
+
+	nonce, verifier := parts[1], parts[2]
+
+	rawIDToken, err := s.oidc.Exchange(r.Context(), params.Code, verifier)
+	if err != nil {
+		s.respondOIDCError(w, r, op, err)
+		return
+	}
+
+	claims, err := s.oidc.Verify(r.Context(), rawIDToken, nonce)
+	if err != nil {
+		s.respondOIDCError(w, r, op, err)
+		return
+	}
+
+	userID, err := s.oidcSubjects.ResolveOIDCSubject(r.Context(), claims.Subject)
+	if err != nil {
+		s.respondOIDCError(w, r, op, err)
+		return
+	}
+
+	role, err := s.userService.GetRole(r.Context(), userID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, api.OidcLoginResponse{
+		IdToken:   rawIDToken,
+		ExpiresAt: claims.ExpiresAt,
+		UserId:    userID,
+		Role:      api.UserRole(role),
+	})
+}
+
+// respondOIDCError answers a rejected ID token with 401 and an unmapped subject with 403.
+func (s *Server) respondOIDCError(w http.ResponseWriter, r *http.Request, op string, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrInvalidToken):
+		s.respondAPIError(w, http.StatusUnauthorized, api.UNAUTHORIZED, apperrors.ErrInvalidToken.Error())
+	case errors.Is(err, apperrors.ErrNotFound):
+		s.respondAPIError(w, http.StatusForbidden, api.FORBIDDEN, "oidc subject is not mapped to a user")
+	default:
+		s.handleServiceError(w, r, op, err)
+	}
+}
+
+func (s *Server) GetAdminOidcSubjects(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.GetAdminOidcSubjects"
+
+	resp, err := s.oidcSubjects.ListOIDCSubjects(r.Context())
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) PostAdminOidcSubjects(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostAdminOidcSubjects"
+
+	var req setOIDCSubjectRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	mapping, err := s.oidcSubjects.SetOIDCSubject(r.Context(), req.Subject, req.UserID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, api.OidcSubjectResponse{OidcSubject: *mapping})
+}
+
+func (s *Server) DeleteAdminOidcSubjectsSubject(w http.ResponseWriter, r *http.Request, subject string) {
+	const op = "internal.transport.http.DeleteAdminOidcSubjectsSubject"
+
+	if err := s.oidcSubjects.DeleteOIDCSubject(r.Context(), subject); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// isIDToken reports whether a bearer token has the shape of a JWT rather than of a key or token of the service.
+func isIDToken(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// isHTTPS reports whether the client reached the service over HTTPS, directly or through a proxy.
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
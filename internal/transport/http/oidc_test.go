@@ -0,0 +1,250 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/oidc"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// oidcAuth is the configuration of a server requiring tokens with no keys of its own.
+var oidcAuth = config.Auth{ReadTokenRateLimit: 60, ReadTokenRateWindow: time.Minute}
+
+func TestServer_GetAuthLogin(t *testing.T) {
+	t.Run("Not configured", func(t *testing.T) {
+		routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil,
+			WithOIDCSubjects(new(OIDCSubjectServiceMock))).Routes()
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/auth/login", nil))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil,
+		WithOIDC(new(OIDCProviderMock)), WithOIDCSubjects(new(OIDCSubjectServiceMock)), WithAuth(oidcAuth)).Routes()
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/login", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+
+	rr := httptest.NewRecorder()
+	routes.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusFound, rr.Code, "the sign-in needs no token")
+
+	location, err := url.Parse(rr.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "idp.example.com", location.Host)
+
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+
+	cookie := cookies[0]
+	assert.Equal(t, oidcLoginCookie, cookie.Name)
+	assert.True(t, cookie.HttpOnly)
+	assert.True(t, cookie.Secure, "the client came over https through the proxy")
+	assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+	assert.Equal(t, strings.Join([]string{
+		location.Query().Get("state"), location.Query().Get("nonce"), location.Query().Get("verifier"),
+	}, "."), cookie.Value, "the cookie keeps what the callback checks the sign-in against")
+
+	rr = httptest.NewRecorder()
+	routes.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/auth/login", nil))
+
+	assert.NotEqual(t, cookie.Value, rr.Result().Cookies()[0].Value, "every sign-in has a state of its own")
+}
+
+func TestServer_GetAuthCallback(t *testing.T) {
+	expiresAt := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+	loginCookie := &http.Cookie{Name: oidcLoginCookie, Value: "state-1.nonce-1.verifier-1"}
+
+	provider := new(OIDCProviderMock)
+	provider.On("Exchange", mock.Anything, "good-code", "verifier-1").Return("id.token.lead", nil)
+	provider.On("Exchange", mock.Anything, "unmapped-code", "verifier-1").Return("id.token.stranger", nil)
+	provider.On("Exchange", mock.Anything, "stolen-code", "verifier-1").Return("", apperrors.ErrInvalidToken)
+	provider.On("Verify", mock.Anything, "id.token.lead", "nonce-1").Return(&oidc.Claims{Subject: "sub-lead", ExpiresAt: expiresAt}, nil)
+	provider.On("Verify", mock.Anything, "id.token.stranger", "nonce-1").Return(&oidc.Claims{Subject: "sub-stranger"}, nil)
+
+	subjects := new(OIDCSubjectServiceMock)
+	subjects.On("ResolveOIDCSubject", mock.Anything, "sub-lead").Return("u-lead", nil)
+	subjects.On("ResolveOIDCSubject", mock.Anything, "sub-stranger").Return("", apperrors.ErrNotFound)
+
+	usersMock := new(UserServiceMock)
+	usersMock.On("GetRole", mock.Anything, "u-lead").Return(domain.RoleLead, nil)
+
+	routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, usersMock, nil,
+		WithOIDC(provider), WithOIDCSubjects(subjects), WithAuth(oidcAuth)).Routes()
+
+	serve := func(query string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/auth/callback?"+query, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	t.Run("Signed in", func(t *testing.T) {
+		rr := serve("code=good-code&state=state-1", loginCookie)
+
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.JSONEq(t, `{"id_token":"id.token.lead","expires_at":"2026-03-02T10:00:00Z","user_id":"u-lead","role":"lead"}`, rr.Body.String())
+
+		cookies := rr.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Negative(t, cookies[0].MaxAge, "the sign-in is over")
+	})
+
+	testCases := []struct {
+		name         string
+		query        string
+		cookie       *http.Cookie
+		expectedCode int
+	}{
+		{name: "Not started in this browser", query: "code=good-code&state=state-1", expectedCode: http.StatusUnauthorized},
+		{name: "Another sign-in", query: "code=good-code&state=state-2", cookie: loginCookie, expectedCode: http.StatusUnauthorized},
+		{name: "Malformed cookie", query: "code=good-code&state=state-1",
+			cookie: &http.Cookie{Name: oidcLoginCookie, Value: "state-1"}, expectedCode: http.StatusUnauthorized},
+		{name: "Code rejected", query: "code=stolen-code&state=state-1", cookie: loginCookie, expectedCode: http.StatusUnauthorized},
+		{name: "Subject not mapped", query: "code=unmapped-code&state=state-1", cookie: loginCookie, expectedCode: http.StatusForbidden},
+		{name: "No code", query: "error=access_denied&state=state-1", cookie: loginCookie, expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := serve(tc.query, tc.cookie)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, rr.Body.String())
+		})
+	}
+
+	provider.AssertNumberOfCalls(t, "Exchange", 3)
+}
+
+func TestServer_AuthIDTokens(t *testing.T) {
+	team := &api.Team{TeamName: "backend", Members: []api.TeamMember{}}
+
+	teamsMock := new(TeamServiceMock)
+	teamsMock.On("GetTeam", mock.Anything, "backend").Return(team, nil)
+
+	provider := new(OIDCProviderMock)
+	provider.On("Verify", mock.Anything, "id.token.member", "").Return(&oidc.Claims{Subject: "sub-member"}, nil)
+	provider.On("Verify", mock.Anything, "id.token.stranger", "").Return(&oidc.Claims{Subject: "sub-stranger"}, nil)
+	provider.On("Verify", mock.Anything, "id.token.expired", "").Return(nil, apperrors.ErrInvalidToken)
+
+	subjects := new(OIDCSubjectServiceMock)
+	subjects.On("ResolveOIDCSubject", mock.Anything, "sub-member").Return("u-member", nil)
+	subjects.On("ResolveOIDCSubject", mock.Anything, "sub-stranger").Return("", apperrors.ErrNotFound)
+
+	usersMock := new(UserServiceMock)
+	usersMock.On("GetRole", mock.Anything, "u-member").Return(domain.RoleMember, nil)
+
+	serve := func(routes http.Handler, method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamsMock, usersMock, nil,
+		WithOIDC(provider), WithOIDCSubjects(subjects), WithAuth(oidcAuth)).Routes()
+
+	testCases := []struct {
+		name         string
+		method, path string
+		token        string
+		expectedCode int
+	}{
+		{name: "A member reads", method: http.MethodGet, path: "/team/get?team_name=backend", token: "id.token.member", expectedCode: http.StatusOK},
+		{name: "A member may not reassign", method: http.MethodPost, path: "/pullRequest/reassign", token: "id.token.member", expectedCode: http.StatusForbidden},
+		{name: "A member may not administer", method: http.MethodGet, path: "/admin/oidcSubjects", token: "id.token.member", expectedCode: http.StatusForbidden},
+		{name: "Subject not mapped", method: http.MethodGet, path: "/team/get?team_name=backend", token: "id.token.stranger", expectedCode: http.StatusForbidden},
+		{name: "Token rejected", method: http.MethodGet, path: "/team/get?team_name=backend", token: "id.token.expired", expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := serve(routes, tc.method, tc.path, tc.token)
+			assert.Equal(t, tc.expectedCode, rr.Code, rr.Body.String())
+		})
+	}
+
+	t.Run("Not accepted without a provider", func(t *testing.T) {
+		routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamsMock, usersMock, nil,
+			WithOIDCSubjects(subjects), WithAuth(oidcAuth)).Routes()
+
+		rr := serve(routes, http.MethodGet, "/team/get?team_name=backend", "id.token.member")
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+func TestServer_AdminOidcSubjects(t *testing.T) {
+	createdAt := time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC)
+	mapping := api.OidcSubject{Subject: "00uAbC", UserId: "u1", CreatedAt: createdAt}
+	total := 1
+
+	subjects := new(OIDCSubjectServiceMock)
+	subjects.On("SetOIDCSubject", mock.Anything, "00uAbC", "u1").Return(&mapping, nil).Once()
+	subjects.On("SetOIDCSubject", mock.Anything, "00uAbC", "ghost").Return(nil, apperrors.ErrNotFound).Once()
+	subjects.On("ListOIDCSubjects", mock.Anything).
+		Return(&api.ListOidcSubjectsResponse{Items: []api.OidcSubject{mapping}, TotalEstimate: &total}, nil).Once()
+	subjects.On("DeleteOIDCSubject", mock.Anything, "00uAbC").Return(nil).Once()
+	subjects.On("DeleteOIDCSubject", mock.Anything, "ghost").Return(apperrors.ErrNotFound).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithOIDCSubjects(subjects))
+	router := api.Handler(server)
+
+	expectedMapping := `{"subject":"00uAbC","user_id":"u1","created_at":"2026-03-01T10:00:00Z"}`
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/oidcSubjects", strings.NewReader(`{"subject":"00uAbC","user_id":"u1"}`)))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"oidc_subject":`+expectedMapping+`}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/oidcSubjects", strings.NewReader(`{"subject":"00uAbC","user_id":"ghost"}`)))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/oidcSubjects", strings.NewReader(`{"subject":"00uAbC"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/oidcSubjects", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"items":[`+expectedMapping+`],"next_cursor":null,"total_estimate":1}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/admin/oidcSubjects/00uAbC", nil))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/admin/oidcSubjects/ghost", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	subjects.AssertExpectations(t)
+}
@@ -0,0 +1,21 @@
+package http
+
+import (
+	"net/http"
+	"net/http/pprof"
+)
+
+// PprofHandler serves the profiles of the process under /debug/pprof, e.g. /debug/pprof/profile?seconds=30
+// for the CPU and /debug/pprof/heap for the heap. It is meant for an admin address of its own, not for the
+// API server: the profiles expose the internals of the process, and it has no authentication.
+func PprofHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	return mux
+}
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPprofHandler(t *testing.T) {
+	handler := PprofHandler()
+
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/heap?debug=1", "/debug/pprof/goroutine?debug=1", "/debug/pprof/cmdline"} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code, path)
+		assert.NotEmpty(t, rr.Body.String(), path)
+	}
+
+	rr := httptest.NewRecorder()
+	NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil).Routes().
+		ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code, "the API server does not serve the profiles")
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+)
+
+// WithReadTokens serves the /admin/readTokens endpoints with ts and lets WithAuth accept the read tokens.
+func WithReadTokens(ts service.ReadTokenService) ServerOption {
+	return func(s *Server) {
+		s.readTokens = ts
+	}
+}
+
+func (s *Server) GetAdminReadTokens(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.GetAdminReadTokens"
+
+	resp, err := s.readTokens.ListReadTokens(r.Context())
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) PostAdminReadTokens(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostAdminReadTokens"
+
+	var req createReadTokenRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	created, err := s.readTokens.CreateReadToken(r.Context(), req.Name, req.ExpiresAt)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusCreated, created)
+}
+
+func (s *Server) DeleteAdminReadTokensTokenId(w http.ResponseWriter, r *http.Request, tokenID int64) {
+	const op = "internal.transport.http.DeleteAdminReadTokensTokenId"
+
+	if err := s.readTokens.DeleteReadToken(r.Context(), tokenID); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestServer_AdminReadTokens(t *testing.T) {
+	createdAt := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+	token := api.ReadToken{TokenId: 4, Name: "grafana", CreatedAt: createdAt, ExpiresAt: &expiresAt}
+	total := 1
+
+	tokensMock := new(ReadTokenServiceMock)
+	tokensMock.On("CreateReadToken", mock.Anything, "grafana", mock.MatchedBy(func(at *time.Time) bool {
+		return at != nil && at.Equal(expiresAt)
+	})).Return(&api.ReadTokenCreatedResponse{ReadToken: token, Token: "prr_secret"}, nil).Once()
+	tokensMock.On("CreateReadToken", mock.Anything, "stale", mock.Anything).Return(nil, apperrors.ErrValidation).Once()
+	tokensMock.On("ListReadTokens", mock.Anything).
+		Return(&api.ListReadTokensResponse{Items: []api.ReadToken{token}, TotalEstimate: &total}, nil).Once()
+	tokensMock.On("DeleteReadToken", mock.Anything, int64(4)).Return(nil).Once()
+	tokensMock.On("DeleteReadToken", mock.Anything, int64(9)).Return(apperrors.ErrNotFound).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithReadTokens(tokensMock))
+	router := api.Handler(server)
+
+	expectedToken := `{"token_id":4,"name":"grafana","created_at":"2026-03-02T09:00:00Z","expires_at":"2026-06-01T00:00:00Z"}`
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/readTokens",
+		strings.NewReader(`{"name":"grafana","expires_at":"2026-06-01T00:00:00Z"}`)))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.JSONEq(t, `{"read_token":`+expectedToken+`,"token":"prr_secret"}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/readTokens", strings.NewReader(`{"name":"stale"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/readTokens", strings.NewReader(`{}`)))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/readTokens", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"items":[`+expectedToken+`],"next_cursor":null,"total_estimate":1}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/admin/readTokens/4", nil))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/admin/readTokens/9", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	tokensMock.AssertExpectations(t)
+}
@@ -0,0 +1,20 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// rejectWrites answers every request other than GET and HEAD with 503 READONLY, so that an instance
+// pointed at a replica or a standby database serves reads only and never attempts a write.
+func (s *Server) rejectWrites(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		s.respondAPIError(w, http.StatusServiceUnavailable, api.READONLY, "the service is in read-only mode")
+	})
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_ReadOnly(t *testing.T) {
+	prServiceMock := new(PullRequestServiceMock)
+	prServiceMock.On("GetPR", mock.Anything, "pr-1").Return(&api.PullRequest{
+		PullRequestId:     "pr-1",
+		PullRequestName:   "New Feature",
+		AuthorId:          "author-1",
+		Status:            api.PullRequestStatusOPEN,
+		AssignedReviewers: []string{"reviewer-1"},
+	}, nil).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock, WithReadOnly())
+	routes := server.Routes()
+
+	rr := httptest.NewRecorder()
+	routes.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/pullRequest/get?pull_request_id=pr-1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	for _, path := range []string{"/pullRequest/merge", "/v1/pullRequest/merge"} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"pull_request_id": "pr-1"}`))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			routes.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+			require.JSONEq(t, `{"error":{"code":"READONLY","message":"the service is in read-only mode"}}`, rr.Body.String())
+		})
+	}
+
+	prServiceMock.AssertExpectations(t)
+}
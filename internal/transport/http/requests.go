@@ -1,18 +1,47 @@
 package http
 
+import (
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/validation"
+)
+
 type createTeamRequest struct {
 	TeamName string `json:"team_name" validate:"required,min=3,max=50"`
 	Members  []struct {
 		UserID   string `json:"user_id" validate:"required,custom_id,min=1,max=100"`
-		Username string `json:"username" validate:"required,min=2,max=100"`
+		Username string `json:"username" validate:"required,username,min=2,max=100"`
 		IsActive bool   `json:"is_active"`
 	} `json:"members" validate:"omitempty,dive"`
 }
 
+func (r *createTeamRequest) Normalize() {
+	for i := range r.Members {
+		r.Members[i].Username = validation.NormalizeUsername(r.Members[i].Username)
+	}
+}
+
 type createPRRequest struct {
-	PullRequestID   string `json:"pull_request_id" validate:"required,custom_id,min=1,max=100"`
-	PullRequestName string `json:"pull_request_name" validate:"required,min=5,max=255"`
-	AuthorID        string `json:"author_id" validate:"required,custom_id,min=1,max=100"`
+	PullRequestID   string  `json:"pull_request_id" validate:"required,custom_id,min=1,max=100"`
+	PullRequestName string  `json:"pull_request_name" validate:"required,min=5,max=255"`
+	AuthorID        string  `json:"author_id" validate:"required,custom_id,min=1,max=100"`
+	Description     *string `json:"description" validate:"omitempty,max=10000"`
+	ExternalURL     *string `json:"external_url" validate:"omitempty,http_url,max=2048"`
+	// CustomFields are checked against the fields of the author's team by the service.
+	CustomFields map[string]any `json:"custom_fields" validate:"omitempty,max=50"`
+	AssignAt     *time.Time     `json:"assign_at"`
+}
+
+// generatedIDCreatePRRequest is createPRRequest with generate_id=true, which leaves the ID to the service.
+// The fields are those of createPRRequest, so that one converts to the other.
+type generatedIDCreatePRRequest struct {
+	PullRequestID   string         `json:"pull_request_id" validate:"isdefault"`
+	PullRequestName string         `json:"pull_request_name" validate:"required,min=5,max=255"`
+	AuthorID        string         `json:"author_id" validate:"required,custom_id,min=1,max=100"`
+	Description     *string        `json:"description" validate:"omitempty,max=10000"`
+	ExternalURL     *string        `json:"external_url" validate:"omitempty,http_url,max=2048"`
+	CustomFields    map[string]any `json:"custom_fields" validate:"omitempty,max=50"`
+	AssignAt        *time.Time     `json:"assign_at"`
 }
 
 type setUserActiveRequest struct {
@@ -20,15 +49,195 @@ type setUserActiveRequest struct {
 	IsActive bool   `json:"is_active"`
 }
 
+type transferUserRequest struct {
+	UserID          string `json:"user_id" validate:"required,custom_id,min=1,max=100"`
+	TeamName        string `json:"team_name" validate:"omitempty,min=3,max=50"`
+	TeamID          *int   `json:"team_id" validate:"omitempty,min=1"`
+	ReassignReviews bool   `json:"reassign_reviews"`
+}
+
+type restoreUserRequest struct {
+	UserID string `json:"user_id" validate:"required,custom_id,min=1,max=100"`
+}
+
+type setUserRoleRequest struct {
+	UserID string `json:"user_id" validate:"required,custom_id,min=1,max=100"`
+	Role   string `json:"role" validate:"required"`
+}
+
 type mergePRRequest struct {
+	PullRequestID  string `json:"pull_request_id" validate:"required,custom_id,min=1,max=100"`
+	OverrideFreeze bool   `json:"override_freeze"`
+}
+
+type closePRRequest struct {
 	PullRequestID string `json:"pull_request_id" validate:"required,custom_id,min=1,max=100"`
 }
 
+type reviewPRRequest struct {
+	PullRequestID string `json:"pull_request_id" validate:"required,custom_id,min=1,max=100"`
+	ReviewerID    string `json:"reviewer_id" validate:"required,custom_id,min=1,max=100"`
+}
+
+type subscribePRRequest struct {
+	PullRequestID string `json:"pull_request_id" validate:"required,custom_id,min=1,max=100"`
+	UserID        string `json:"user_id" validate:"required,custom_id,min=1,max=100"`
+}
+
 type reassignRequest struct {
 	PullRequestID string `json:"pull_request_id" validate:"required,custom_id,min=1,max=100"`
 	OldUserID     string `json:"old_user_id" validate:"required,custom_id,min=1,max=100"`
+	// Note is the context the replaced reviewer hands over to the new one.
+	Note string `json:"note" validate:"omitempty,max=1000"`
 }
 
+// The team of a team-scoped request is given either by team_name or by team_id; the handler checks that exactly one is set.
+
 type deactivateTeamRequest struct {
-	TeamName string `json:"team_name" validate:"required,min=3,max=50"`
+	TeamName string `json:"team_name" validate:"omitempty,min=3,max=50"`
+	TeamID   *int   `json:"team_id" validate:"omitempty,min=1"`
+	// Force is required in batched mode: reviews without a replacement can only be reported once the batches run.
+	Force     bool `json:"force" validate:"required_with=BatchSize"`
+	BatchSize *int `json:"batch_size" validate:"omitempty,min=1,max=1000"`
+	// DryRun previews the deactivation without batches even if BatchSize is set.
+	DryRun bool `json:"dry_run"`
+}
+
+type reactivateTeamRequest struct {
+	TeamName         string `json:"team_name" validate:"omitempty,min=3,max=50"`
+	TeamID           *int   `json:"team_id" validate:"omitempty,min=1"`
+	RestoreReviewers bool   `json:"restore_reviewers"`
+}
+
+type renameTeamRequest struct {
+	TeamName    string `json:"team_name" validate:"omitempty,min=3,max=50"`
+	TeamID      *int   `json:"team_id" validate:"omitempty,min=1"`
+	NewTeamName string `json:"new_team_name" validate:"required,min=3,max=50"`
+}
+
+type restoreTeamRequest struct {
+	TeamID int `json:"team_id" validate:"required,min=1"`
+}
+
+type createJobRequest struct {
+	Type string `json:"type" validate:"required"`
+	// Params are checked by the handler of the job type.
+	Params map[string]any `json:"params"`
+}
+
+type statsBackfillRequest struct {
+	BatchSize *int `json:"batch_size" validate:"omitempty,min=1,max=1000"`
+}
+
+type setTeamPolicyRequest struct {
+	TeamName              string         `json:"team_name" validate:"omitempty,min=3,max=50"`
+	TeamID                *int           `json:"team_id" validate:"omitempty,min=1"`
+	StrategyWeights       map[string]int `json:"strategy_weights" validate:"required,dive,min=0"`
+	AuthorOpenPRLimit     *int           `json:"author_open_pr_limit" validate:"omitempty,min=1"`
+	OverQuotaAction       *string        `json:"over_quota_action" validate:"omitempty,oneof=reject queue"`
+	ReactivationRebalance *string        `json:"reactivation_rebalance" validate:"omitempty,oneof=off immediate job"`
+}
+
+type setCustomFieldsRequest struct {
+	TeamName string `json:"team_name" validate:"omitempty,min=3,max=50"`
+	TeamID   *int   `json:"team_id" validate:"omitempty,min=1"`
+	Fields   []struct {
+		Key      string `json:"key" validate:"required,max=64"`
+		Type     string `json:"type" validate:"required,oneof=string number boolean"`
+		Required bool   `json:"required"`
+	} `json:"fields" validate:"required,max=50,dive"`
+}
+
+type borrowReviewersRequest struct {
+	TeamName       string `json:"team_name" validate:"omitempty,min=3,max=50"`
+	TeamID         *int   `json:"team_id" validate:"omitempty,min=1"`
+	LenderTeamName string `json:"lender_team_name" validate:"omitempty,min=3,max=50"`
+	LenderTeamID   *int   `json:"lender_team_id" validate:"omitempty,min=1"`
+	Count          int    `json:"count" validate:"required,min=1,max=10"`
+	DurationHours  int    `json:"duration_hours" validate:"required,min=1,max=720"`
+}
+
+type acceptBorrowRequest struct {
+	BorrowID int64 `json:"borrow_id" validate:"required,min=1"`
+}
+
+// A freeze window without a team applies to the whole organization, so both team fields may be omitted.
+type createFreezeWindowRequest struct {
+	TeamName string    `json:"team_name" validate:"omitempty,min=3,max=50"`
+	TeamID   *int      `json:"team_id" validate:"omitempty,min=1"`
+	Reason   string    `json:"reason" validate:"required,max=255"`
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required"`
+}
+
+type webhookRequest struct {
+	URL    string   `json:"url" validate:"required,http_url,max=2048"`
+	Events []string `json:"events" validate:"required,min=1,max=10,dive,required"`
+	// Secret is checked to be present on create by the service, since an update may leave it out.
+	Secret *string `json:"secret" validate:"omitempty,min=16,max=255"`
+}
+
+// gitHubPullRequestEvent holds the fields of a GitHub pull_request event the webhook uses; GitHub sends many more.
+type gitHubPullRequestEvent struct {
+	Action      string `json:"action" validate:"required"`
+	PullRequest struct {
+		Number  int     `json:"number" validate:"required,min=1"`
+		Title   string  `json:"title" validate:"required,max=255"`
+		Body    *string `json:"body" validate:"omitempty,max=65536"`
+		HTMLURL *string `json:"html_url" validate:"omitempty,http_url,max=2048"`
+		Merged  bool    `json:"merged"`
+		User    struct {
+			Login string `json:"login" validate:"required,custom_id,max=100"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		ID       int64  `json:"id" validate:"required,min=1"`
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// gitLabMergeRequestEvent holds the fields of a GitLab Merge Request Hook event the webhook uses; GitLab sends many more.
+type gitLabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username" validate:"required,max=255"`
+	} `json:"user"`
+	Project struct {
+		ID                int64  `json:"id" validate:"required,min=1"`
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID         int     `json:"iid" validate:"required,min=1"`
+		Title       string  `json:"title" validate:"required,max=255"`
+		Description *string `json:"description" validate:"omitempty,max=65536"`
+		URL         *string `json:"url" validate:"omitempty,http_url,max=2048"`
+		Action      string  `json:"action"`
+	} `json:"object_attributes"`
+}
+
+type setGitLabUserRequest struct {
+	GitLabUsername string `json:"gitlab_username" validate:"required,max=255"`
+	UserID         string `json:"user_id" validate:"required,custom_id,min=1,max=100"`
+}
+
+type setOIDCSubjectRequest struct {
+	Subject string `json:"subject" validate:"required,max=255"`
+	UserID  string `json:"user_id" validate:"required,custom_id,min=1,max=100"`
+}
+
+type createReadTokenRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type createAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,max=3,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	UserID    *string    `json:"user_id" validate:"omitempty,custom_id,min=1,max=100"`
+}
+
+type setSlackUserRequest struct {
+	UserID      string `json:"user_id" validate:"required,custom_id,min=1,max=100"`
+	SlackUserID string `json:"slack_user_id" validate:"required,max=255"`
 }
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+)
+
+// WithSecurityHeaders adds the security headers of cfg to every response.
+func WithSecurityHeaders(cfg config.SecurityHeaders) ServerOption {
+	return func(s *Server) {
+		s.securityHeaders = &cfg
+	}
+}
+
+// setSecurityHeaders adds the security headers before the handler writes its answer. Strict-Transport-Security
+// goes only with the requests that came over HTTPS: browsers ignore it over plain HTTP, and sending it from
+// a service reached over HTTP would pin the clients to an HTTPS it may not serve.
+func (s *Server) setSecurityHeaders(next http.Handler) http.Handler {
+	cfg := s.securityHeaders
+
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(cfg.HSTSMaxAge.Seconds()))
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+
+		if cfg.NoSniff {
+			h.Set("X-Content-Type-Options", "nosniff")
+		}
+
+		if cfg.FrameOptions != "" {
+			h.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+
+		if hsts != "" && isHTTPS(r) {
+			h.Set("Strict-Transport-Security", hsts)
+		}
+
+		if cfg.SwaggerCSP != "" && isSwaggerRequest(r) {
+			h.Set("Content-Security-Policy", cfg.SwaggerCSP)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_SecurityHeaders(t *testing.T) {
+	cfg := config.SecurityHeaders{
+		Enabled:      true,
+		NoSniff:      true,
+		FrameOptions: "DENY",
+		HSTSMaxAge:   24 * time.Hour,
+		SwaggerCSP:   "default-src 'self'",
+	}
+
+	serve := func(cfg config.SecurityHeaders, r *http.Request) *httptest.ResponseRecorder {
+		routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithSecurityHeaders(cfg)).Routes()
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, r)
+
+		return rr
+	}
+
+	t.Run("API over HTTP", func(t *testing.T) {
+		rr := serve(cfg, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+		assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "DENY", rr.Header().Get("X-Frame-Options"))
+		assert.Empty(t, rr.Header().Get("Strict-Transport-Security"))
+		assert.Empty(t, rr.Header().Get("Content-Security-Policy"), "the CSP is for the swagger UI")
+	})
+
+	t.Run("API behind a TLS proxy", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/version", nil)
+		req.Header.Set("X-Forwarded-Proto", "https")
+
+		rr := serve(cfg, req)
+		assert.Equal(t, "max-age=86400", rr.Header().Get("Strict-Transport-Security"))
+
+		withSubdomains := cfg
+		withSubdomains.HSTSIncludeSubdomains = true
+
+		rr = serve(withSubdomains, req)
+		assert.Equal(t, "max-age=86400; includeSubDomains", rr.Header().Get("Strict-Transport-Security"))
+	})
+
+	t.Run("Swagger UI", func(t *testing.T) {
+		rr := serve(cfg, httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil))
+
+		assert.Equal(t, "default-src 'self'", rr.Header().Get("Content-Security-Policy"))
+		assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+	})
+
+	t.Run("Headers turned off", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil)
+		req.Header.Set("X-Forwarded-Proto", "https")
+
+		rr := serve(config.SecurityHeaders{Enabled: true}, req)
+
+		for _, header := range []string{"X-Content-Type-Options", "X-Frame-Options", "Strict-Transport-Security", "Content-Security-Policy"} {
+			assert.Empty(t, rr.Header().Get(header), header)
+		}
+	})
+}
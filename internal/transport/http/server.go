@@ -4,15 +4,21 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
 	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/YusovID/pr-reviewer-service/internal/signature"
 	"github.com/YusovID/pr-reviewer-service/internal/validation"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
 	"github.com/YusovID/pr-reviewer-service/pkg/logger/sl"
@@ -26,7 +32,116 @@ type Server struct {
 	log         *slog.Logger
 	teamService service.TeamService
 	userService service.UserService
-	prService   service.PullRequestService
+	// prCommands and prQueries are the two halves of the same service.PullRequestService,
+	// kept apart so that each handler shows which half it needs.
+	prCommands service.PRCommandService
+	prQueries  service.PRQueryService
+	jobService service.JobService
+	// notifications serves the notification delivery log.
+	notifications service.NotificationService
+	// freezes serves the merge freeze windows.
+	freezes service.FreezeService
+	// webhooks manages the outbound webhooks.
+	webhooks service.WebhookService
+	// gitHubWebhook verifies the deliveries of the GitHub webhook; nil disables the webhook.
+	gitHubWebhook *signature.Verifier
+	// gitLabWebhook verifies the deliveries of the GitLab webhook; nil disables the webhook.
+	gitLabWebhook *signature.Verifier
+	// webhookShadow selects the inbound webhooks handled in shadow mode.
+	webhookShadow config.WebhookShadow
+	// gitLabUsers maps GitLab usernames to user IDs for the GitLab webhook.
+	gitLabUsers service.GitLabUserService
+	// slackUsers maps user IDs to the Slack member IDs the Slack notifier sends to.
+	slackUsers service.SlackUserService
+	// oidc signs the users in through the identity provider; nil disables the sign-in and the ID tokens.
+	oidc OIDCProvider
+	// oidcSubjects maps the subjects of the ID tokens to user IDs.
+	oidcSubjects service.OIDCSubjectService
+	// readTokens manages the read-only API tokens and authenticates the requests made with them.
+	readTokens service.ReadTokenService
+	// apiKeys manages the stored API keys and authenticates the requests made with them.
+	apiKeys service.APIKeyService
+	// auth checks the bearer tokens of the API operations; nil leaves the API open.
+	auth       *authenticator
+	authBursts *burstDetector
+	// deprecations indexes the deprecation registry by endpoint.
+	deprecations map[string][]deprecation
+	// slo holds the objectives reported on /slo.
+	slo       config.SLO
+	startedAt time.Time
+	// securityHeaders are added to every response; nil adds none.
+	securityHeaders *config.SecurityHeaders
+	// tracing traces the requests.
+	tracing bool
+	// maxBodyBytes bounds the JSON bodies of the requests.
+	maxBodyBytes int64
+	// compression gzips the large responses; nil sends them as they are.
+	compression *compression
+	// cors lets the pages of other origins call the API; nil sends no CORS headers.
+	cors *corsPolicy
+	// timeouts bound the API requests by route group; nil leaves them unbounded.
+	timeouts *requestTimeouts
+	// readOnly rejects every request that could change data.
+	readOnly bool
+	// noSwagger disables the swagger UI.
+	noSwagger bool
+}
+
+// defaultMaxBodyBytes bounds the JSON bodies of the requests unless WithMaxBodyBytes sets another limit.
+const defaultMaxBodyBytes = 1 << 20
+
+// WithMaxBodyBytes bounds the JSON bodies of the requests; larger ones are answered with 413 PAYLOAD_TOO_LARGE.
+func WithMaxBodyBytes(n int64) ServerOption {
+	return func(s *Server) {
+		s.maxBodyBytes = n
+	}
+}
+
+// ServerOption configures optional behaviour of the Server.
+type ServerOption func(*Server)
+
+// WithSLO sets the service level objectives reported on /slo.
+func WithSLO(cfg config.SLO) ServerOption {
+	return func(s *Server) {
+		s.slo = cfg
+	}
+}
+
+// WithJobs serves the /jobs endpoints with js.
+func WithJobs(js service.JobService) ServerOption {
+	return func(s *Server) {
+		s.jobService = js
+	}
+}
+
+// WithNotifications serves the /admin/notifications endpoints with ns.
+func WithNotifications(ns service.NotificationService) ServerOption {
+	return func(s *Server) {
+		s.notifications = ns
+	}
+}
+
+// WithFreezes serves the /admin/freezes endpoints with fs.
+func WithFreezes(fs service.FreezeService) ServerOption {
+	return func(s *Server) {
+		s.freezes = fs
+	}
+}
+
+// WithReadOnly serves GET and HEAD requests only and answers the others with 503 READONLY.
+// Together with WithPRQueries it lets an instance run against a replica or a standby database.
+func WithReadOnly() ServerOption {
+	return func(s *Server) {
+		s.readOnly = true
+	}
+}
+
+// WithPRQueries serves the read-only pull request endpoints with q instead of the service passed to NewServer,
+// e.g. with a service reading from a replica.
+func WithPRQueries(q service.PRQueryService) ServerOption {
+	return func(s *Server) {
+		s.prQueries = q
+	}
 }
 
 // NewServer creates a new instance of the HTTP server.
@@ -35,13 +150,25 @@ func NewServer(
 	ts service.TeamService,
 	us service.UserService,
 	prs service.PullRequestService,
+	opts ...ServerOption,
 ) *Server {
-	return &Server{
-		log:         log,
-		teamService: ts,
-		userService: us,
-		prService:   prs,
+	s := &Server{
+		log:          log,
+		teamService:  ts,
+		userService:  us,
+		prCommands:   prs,
+		prQueries:    prs,
+		authBursts:   newBurstDetector(defaultAuthBurstThreshold, defaultAuthBurstWindow),
+		deprecations: indexDeprecations(deprecations),
+		startedAt:    time.Now().UTC(),
+		maxBodyBytes: defaultMaxBodyBytes,
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
 }
 
 // Routes sets up the router with all middleware and API endpoints.
@@ -50,22 +177,71 @@ func (s *Server) Routes() http.Handler {
 	mux := chi.NewRouter()
 
 	mux.Use(s.requestID)
+
+	if s.tracing {
+		mux.Use(s.trace)
+	}
+
+	mux.Use(s.versionHeader)
 	mux.Use(s.logRequest)
 	mux.Use(s.metricsMiddleware)
 
-	swaggerHandler, err := swagger.GetHandler()
-	if err != nil {
-		s.log.Error("failed to get swagger handler", sl.Err(err))
-	} else {
-		mux.Mount("/swagger", http.StripPrefix("/swagger", swaggerHandler))
+	if s.securityHeaders != nil {
+		mux.Use(s.setSecurityHeaders)
+	}
+
+	if s.cors != nil {
+		mux.Use(s.allowCORS)
+	}
+
+	if s.compression != nil {
+		mux.Use(s.compress)
+	}
+
+	if s.readOnly {
+		mux.Use(s.rejectWrites)
+	}
+
+	mux.Use(s.auditAuth)
+	mux.Use(s.deprecationNotice)
+
+	if !s.noSwagger {
+		swaggerHandler, err := swagger.GetHandler()
+		if err != nil {
+			s.log.Error("failed to get swagger handler", sl.Err(err))
+		} else {
+			mux.Mount(swaggerPrefix, http.StripPrefix(swaggerPrefix, swaggerHandler))
+		}
 	}
 
 	mux.Handle("/metrics", promhttp.Handler())
-	mux.Mount("/", api.Handler(s))
+	mux.Get("/slo", s.sloReport)
+	mux.Get("/version", s.version)
+	mux.Route("/v1", func(r chi.Router) {
+		r.Use(s.fieldNames(fieldNamingSnakeCase))
+		r.Mount("/", s.apiHandler())
+	})
+	mux.With(s.fieldNames(fieldNamingLegacy)).Mount("/", s.apiHandler())
 
 	return mux
 }
 
+// apiHandler routes the operations of the spec, checking their tokens if authentication is enabled
+// and bounding them by the request timeouts.
+func (s *Server) apiHandler() http.Handler {
+	var opts api.ChiServerOptions
+	if s.auth != nil {
+		opts.Middlewares = []api.MiddlewareFunc{s.authenticate}
+	}
+
+	handler := api.HandlerWithOptions(s, opts)
+	if s.timeouts != nil {
+		handler = s.requestTimeout(handler)
+	}
+
+	return handler
+}
+
 func (s *Server) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
 	const op = "internal.transport.http.PostTeamAdd"
 
@@ -101,13 +277,25 @@ func (s *Server) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
 func (s *Server) GetTeamGet(w http.ResponseWriter, r *http.Request, params api.GetTeamGetParams) {
 	const op = "internal.transport.http.GetTeamGet"
 
-	team, err := s.teamService.GetTeam(r.Context(), params.TeamName)
+	selection, err := selectFields[api.Team](params.Fields)
 	if err != nil {
 		s.handleServiceError(w, r, op, err)
 		return
 	}
 
-	s.respond(w, http.StatusOK, map[string]*api.Team{"team": team})
+	team, err := s.team(r.Context(), queryValue(params.TeamName), params.TeamId)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	projected, err := selection.apply(team)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, map[string]any{"team": projected})
 }
 
 func (s *Server) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
@@ -119,7 +307,79 @@ func (s *Server) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := s.userService.SetIsActive(r.Context(), req.UserID, req.IsActive)
+	resp, err := s.userService.SetIsActive(r.Context(), req.UserID, req.IsActive)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) PostUsersSetRole(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostUsersSetRole"
+
+	var req setUserRoleRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	resp, err := s.userService.SetRole(r.Context(), req.UserID, req.Role)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) PostUsersTransfer(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostUsersTransfer"
+
+	var req transferUserRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	teamName, err := s.teamName(r.Context(), "team", req.TeamName, req.TeamID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	resp, err := s.userService.TransferUser(r.Context(), req.UserID, teamName, req.ReassignReviews)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) DeleteUsers(w http.ResponseWriter, r *http.Request, params api.DeleteUsersParams) {
+	const op = "internal.transport.http.DeleteUsers"
+
+	resp, err := s.userService.DeleteUser(r.Context(), params.UserId)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) PostUsersRestore(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostUsersRestore"
+
+	var req restoreUserRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	user, err := s.userService.RestoreUser(r.Context(), req.UserID)
 	if err != nil {
 		s.handleServiceError(w, r, op, err)
 		return
@@ -128,22 +388,92 @@ func (s *Server) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
 	s.respond(w, http.StatusOK, map[string]*api.User{"user": user})
 }
 
-func (s *Server) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostPullRequestCreate(w http.ResponseWriter, r *http.Request, params api.PostPullRequestCreateParams) {
 	const op = "internal.transport.http.PostPullRequestCreate"
 
+	var req createPRRequest
+
+	if params.GenerateId != nil && *params.GenerateId {
+		var generated generatedIDCreatePRRequest
+		if err := s.decodeAndValidate(r, &generated); err != nil {
+			s.handleServiceError(w, r, op, err)
+			return
+		}
+
+		req = createPRRequest(generated)
+	} else if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	details := service.PRDetails{
+		Description:  req.Description,
+		ExternalURL:  req.ExternalURL,
+		CustomFields: req.CustomFields,
+		AssignAt:     req.AssignAt,
+	}
+
+	// With generate_id the pull request ID is empty, and the service generates one.
+	pr, created, err := s.prCommands.CreatePR(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID, details)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	if params.Expand != nil && *params.Expand == api.PostPullRequestCreateParamsExpandReviewers {
+		if err := s.prQueries.ExpandReviewers(r.Context(), pr); err != nil {
+			s.handleServiceError(w, r, op, err)
+			return
+		}
+	}
+
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+
+	s.respond(w, status, map[string]*api.PullRequest{"pr": pr})
+}
+
+func (s *Server) PostPullRequestCreateAsync(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostPullRequestCreateAsync"
+
 	var req createPRRequest
 	if err := s.decodeAndValidate(r, &req); err != nil {
 		s.handleServiceError(w, r, op, err)
 		return
 	}
 
-	pr, err := s.prService.CreatePR(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID)
+	details := service.PRDetails{
+		Description:  req.Description,
+		ExternalURL:  req.ExternalURL,
+		CustomFields: req.CustomFields,
+		AssignAt:     req.AssignAt,
+	}
+
+	queued, err := s.prCommands.EnqueueCreatePR(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID, details)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	// The status route sits next to this one, so the link keeps the /v1 prefix the request was made with.
+	statusPath := strings.TrimSuffix(r.URL.Path, "/createAsync") + "/createStatus"
+	w.Header().Set("Location", fmt.Sprintf("%s?request_id=%d", statusPath, queued.RequestId))
+
+	s.respond(w, http.StatusAccepted, api.AsyncCreateResponse{Request: *queued})
+}
+
+func (s *Server) GetPullRequestCreateStatus(w http.ResponseWriter, r *http.Request, params api.GetPullRequestCreateStatusParams) {
+	const op = "internal.transport.http.GetPullRequestCreateStatus"
+
+	req, err := s.prQueries.GetCreatePRRequest(r.Context(), params.RequestId)
 	if err != nil {
 		s.handleServiceError(w, r, op, err)
 		return
 	}
 
-	s.respond(w, http.StatusCreated, map[string]*api.PullRequest{"pr": pr})
+	s.respond(w, http.StatusOK, api.AsyncCreateResponse{Request: *req})
 }
 
 func (s *Server) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
@@ -155,144 +485,1027 @@ func (s *Server) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := s.prService.MergePR(r.Context(), req.PullRequestID)
+	resp, err := s.prCommands.MergePR(r.Context(), req.PullRequestID, service.MergeOptions{OverrideFreeze: req.OverrideFreeze})
 	if err != nil {
 		s.handleServiceError(w, r, op, err)
 		return
 	}
 
-	s.respond(w, http.StatusOK, map[string]*api.PullRequest{"pr": pr})
+	s.respond(w, http.StatusOK, resp)
 }
 
-func (s *Server) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
-	const op = "internal.transport.http.PostPullRequestReassign"
+func (s *Server) PostPullRequestClose(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostPullRequestClose"
 
-	var req reassignRequest
+	var req closePRRequest
 	if err := s.decodeAndValidate(r, &req); err != nil {
 		s.handleServiceError(w, r, op, err)
 		return
 	}
 
-	resp, err := s.prService.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID)
+	pr, err := s.prCommands.ClosePR(r.Context(), req.PullRequestID)
 	if err != nil {
 		s.handleServiceError(w, r, op, err)
 		return
 	}
 
-	s.respond(w, http.StatusOK, resp)
+	s.respond(w, http.StatusOK, map[string]*api.PullRequest{"pr": pr})
 }
 
-func (s *Server) GetUsersGetReview(w http.ResponseWriter, r *http.Request, params api.GetUsersGetReviewParams) {
-	const op = "internal.transport.http.GetUsersGetReview"
+func (s *Server) PostPullRequestApprove(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostPullRequestApprove"
+
+	var req reviewPRRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
 
-	resp, err := s.prService.GetReviewAssignments(r.Context(), params.UserId)
+	pr, err := s.prCommands.ApprovePR(r.Context(), req.PullRequestID, req.ReviewerID)
 	if err != nil {
 		s.handleServiceError(w, r, op, err)
 		return
 	}
 
-	s.respond(w, http.StatusOK, resp)
+	s.respond(w, http.StatusOK, map[string]*api.PullRequest{"pr": pr})
 }
 
-func (s *Server) GetStats(w http.ResponseWriter, r *http.Request) {
-	const op = "internal.transport.http.GetStats"
+func (s *Server) PostPullRequestRequestChanges(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostPullRequestRequestChanges"
+
+	var req reviewPRRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
 
-	stats, err := s.prService.GetStats(r.Context())
+	pr, err := s.prCommands.RequestChanges(r.Context(), req.PullRequestID, req.ReviewerID)
 	if err != nil {
 		s.handleServiceError(w, r, op, err)
 		return
 	}
 
-	s.respond(w, http.StatusOK, stats)
+	s.respond(w, http.StatusOK, map[string]*api.PullRequest{"pr": pr})
 }
 
-func (s *Server) PostTeamDeactivate(w http.ResponseWriter, r *http.Request) {
-	const op = "internal.transport.http.PostTeamDeactivate"
+func (s *Server) PostPullRequestSubscribe(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostPullRequestSubscribe"
 
-	var req deactivateTeamRequest
+	var req subscribePRRequest
 	if err := s.decodeAndValidate(r, &req); err != nil {
 		s.handleServiceError(w, r, op, err)
 		return
 	}
 
-	deactivatedCount, reassignedCount, err := s.userService.DeactivateTeam(r.Context(), req.TeamName)
+	sub, created, err := s.prCommands.SubscribePR(r.Context(), req.PullRequestID, req.UserID)
 	if err != nil {
 		s.handleServiceError(w, r, op, err)
 		return
 	}
 
-	s.respond(w, http.StatusOK, map[string]int{
-		"deactivated_users_count": deactivatedCount,
-		"reassigned_prs_count":    reassignedCount,
-	})
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+
+	s.respond(w, status, map[string]*api.PullRequestSubscription{"subscription": sub})
 }
 
-// respond is a helper function to encode data to JSON and write it to the response.
-// It centralizes setting the Content-Type header and writing the status code.
-func (s *Server) respond(w http.ResponseWriter, code int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(code)
+func (s *Server) PostPullRequestReassign(w http.ResponseWriter, r *http.Request, params api.PostPullRequestReassignParams) {
+	const op = "internal.transport.http.PostPullRequestReassign"
 
-	if data != nil {
-		if err := json.NewEncoder(w).Encode(data); err != nil {
-			s.log.Error("failed to encode response", sl.Err(err))
+	var req reassignRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	resp, err := s.prCommands.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID, req.Note)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	if params.Expand != nil && *params.Expand == api.PostPullRequestReassignParamsExpandReviewers {
+		if err := s.prQueries.ExpandReviewers(r.Context(), &resp.Pr); err != nil {
+			s.handleServiceError(w, r, op, err)
+			return
 		}
 	}
+
+	s.respond(w, http.StatusOK, resp)
 }
 
-// respondError is a convenience wrapper around respond for sending simple error messages.
-func (s *Server) respondError(w http.ResponseWriter, code int, message string) {
-	s.respond(w, code, map[string]string{"error": message})
+func (s *Server) GetPullRequestGet(w http.ResponseWriter, r *http.Request, params api.GetPullRequestGetParams) {
+	const op = "internal.transport.http.GetPullRequestGet"
+
+	pr, err := s.prQueries.GetPR(r.Context(), params.PullRequestId)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	if params.Expand != nil && *params.Expand == api.GetPullRequestGetParamsExpandReviewers {
+		if err := s.prQueries.ExpandReviewers(r.Context(), pr); err != nil {
+			s.handleServiceError(w, r, op, err)
+			return
+		}
+	}
+
+	s.respond(w, http.StatusOK, map[string]*api.PullRequest{"pr": pr})
 }
 
-// respondAPIError formats and sends a structured error response that conforms to the OpenAPI specification.
-func (s *Server) respondAPIError(w http.ResponseWriter, code int, apiCode api.ErrorResponseErrorCode, message string) {
-	errResp := api.ErrorResponse{
-		Error: struct {
-			Code    api.ErrorResponseErrorCode `json:"code"`
-			Message string                     `json:"message"`
-		}{
-			Code:    apiCode,
-			Message: message,
-		},
+func (s *Server) GetPullRequestHistory(w http.ResponseWriter, r *http.Request, params api.GetPullRequestHistoryParams) {
+	const op = "internal.transport.http.GetPullRequestHistory"
+
+	history, err := s.prQueries.GetAssignmentHistory(r.Context(), params.PullRequestId)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
 	}
-	s.respond(w, code, errResp)
+
+	s.respond(w, http.StatusOK, history)
 }
 
-// decodeAndValidate is a helper that deserializes a JSON request body into a struct
-// and then runs validation checks on it.
-func (s *Server) decodeAndValidate(r *http.Request, v interface{}) error {
-	if err := s.decode(r.Body, v); err != nil {
-		return err
+// defaultSearchLimit is the page size of GET /pullRequest/search when the limit parameter is omitted.
+const defaultSearchLimit = 20
+
+func (s *Server) GetPullRequestSearch(w http.ResponseWriter, r *http.Request, params api.GetPullRequestSearchParams) {
+	const op = "internal.transport.http.GetPullRequestSearch"
+
+	query := service.PRSearchQuery{
+		Query:  params.Query,
+		Cursor: queryValue(params.Cursor),
+		Limit:  defaultSearchLimit,
 	}
 
-	if err := validation.ValidateStruct(v); err != nil {
-		return err
+	if params.Limit != nil {
+		query.Limit = *params.Limit
 	}
 
-	return nil
-}
+	if params.Offset != nil {
+		query.Offset = *params.Offset
+	}
 
-// decode is a helper function to decode a JSON request body.
-func (s *Server) decode(body io.ReadCloser, v interface{}) error {
-	defer body.Close()
+	if params.Status != nil {
+		query.Status = string(*params.Status)
+	}
 
-	if err := json.NewDecoder(body).Decode(v); err != nil {
-		return fmt.Errorf("%w: %w", apperrors.ErrInvalidRequest, err)
+	if params.CustomField != nil {
+		query.CustomFields = *params.CustomField
 	}
 
-	return nil
+	resp, err := s.prQueries.SearchPRs(r.Context(), query)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
 }
 
-// handleServiceError provides centralized error handling for all HTTP handlers.
-// It logs the internal error and maps it to a user-friendly HTTP response.
-func (s *Server) handleServiceError(w http.ResponseWriter, _ *http.Request, op string, err error) {
-	log := s.log.With(slog.String("op", op))
-	log.Error("service error occurred", sl.Err(err))
+// defaultListLimit is the page size of GET /pullRequest/list when the limit parameter is omitted.
+const defaultListLimit = 20
+
+func (s *Server) GetPullRequestList(w http.ResponseWriter, r *http.Request, params api.GetPullRequestListParams) {
+	const op = "internal.transport.http.GetPullRequestList"
+
+	selection, err := selectFields[api.PullRequest](params.Fields)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	query := service.PRListQuery{
+		AuthorID:    queryValue(params.AuthorId),
+		CreatedFrom: params.CreatedFrom,
+		CreatedTo:   params.CreatedTo,
+		Cursor:      queryValue(params.Cursor),
+		Limit:       defaultListLimit,
+	}
+
+	if params.Status != nil {
+		query.Status = string(*params.Status)
+	}
+
+	if params.Limit != nil {
+		query.Limit = *params.Limit
+	}
+
+	if params.Offset != nil {
+		query.Offset = *params.Offset
+	}
+
+	if params.TeamName != nil || params.TeamId != nil {
+		team, err := s.team(r.Context(), queryValue(params.TeamName), params.TeamId)
+		if err != nil {
+			s.handleServiceError(w, r, op, err)
+			return
+		}
+
+		query.TeamID = *team.TeamId
+	}
+
+	resp, err := s.prQueries.ListPRs(r.Context(), query)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	prs, err := selection.apply(resp.Items)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, map[string]any{"items": prs, "pull_requests": prs, "next_cursor": resp.NextCursor})
+}
+
+// defaultPendingLimit is the number of entries GET /pullRequest/pending returns when the limit parameter is omitted.
+const defaultPendingLimit = 20
+
+func (s *Server) GetPullRequestPending(w http.ResponseWriter, r *http.Request, params api.GetPullRequestPendingParams) {
+	const op = "internal.transport.http.GetPullRequestPending"
+
+	limit := defaultPendingLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+
+	var teamName string
+	if params.TeamName != nil || params.TeamId != nil {
+		var err error
+
+		teamName, err = s.teamName(r.Context(), "team", queryValue(params.TeamName), params.TeamId)
+		if err != nil {
+			s.handleServiceError(w, r, op, err)
+			return
+		}
+	}
+
+	resp, err := s.prQueries.GetPendingAssignments(r.Context(), teamName, limit)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) GetUsersGetReview(w http.ResponseWriter, r *http.Request, params api.GetUsersGetReviewParams) {
+	const op = "internal.transport.http.GetUsersGetReview"
+
+	var customFields []string
+	if params.CustomField != nil {
+		customFields = *params.CustomField
+	}
+
+	resp, err := s.prQueries.GetReviewAssignments(r.Context(), params.UserId, customFields)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) GetStats(w http.ResponseWriter, r *http.Request, params api.GetStatsParams) {
+	const op = "internal.transport.http.GetStats"
+
+	selection, err := selectFields[api.UserStats](params.Fields)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	stats, err := s.prQueries.GetStats(r.Context(), domain.StatsPeriod{From: params.From, To: params.To})
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	userStats, err := selection.apply(stats.Items)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	resp := map[string]any{
+		"items":          userStats,
+		"user_stats":     userStats,
+		"team_stats":     stats.TeamStats,
+		"next_cursor":    stats.NextCursor,
+		"total_estimate": stats.TotalEstimate,
+	}
+	if stats.RefreshedAt != nil {
+		resp["refreshed_at"] = stats.RefreshedAt
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+// defaultLeaderboardLimit is the number of users GET /stats/leaderboard returns when the limit parameter is omitted.
+const defaultLeaderboardLimit = 20
+
+func (s *Server) GetStatsLeaderboard(w http.ResponseWriter, r *http.Request, params api.GetStatsLeaderboardParams) {
+	const op = "internal.transport.http.GetStatsLeaderboard"
+
+	limit := defaultLeaderboardLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+
+	resp, err := s.prQueries.GetLeaderboard(r.Context(), domain.LeaderboardPeriod(params.Period), limit)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) PostTeamDeactivate(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostTeamDeactivate"
+
+	var req deactivateTeamRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	teamName, err := s.teamName(r.Context(), "team", req.TeamName, req.TeamID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	if req.DryRun {
+		resp, err := s.userService.PreviewTeamDeactivation(r.Context(), teamName, req.Force)
+		if err != nil {
+			s.handleServiceError(w, r, op, err)
+			return
+		}
+
+		s.respond(w, http.StatusOK, resp)
+
+		return
+	}
+
+	if req.BatchSize != nil {
+		// An unknown team is reported before a job is queued for it; a team given by its ID is already known.
+		if req.TeamID == nil {
+			if _, err := s.teamService.GetTeam(r.Context(), teamName); err != nil {
+				s.handleServiceError(w, r, op, err)
+				return
+			}
+		}
+
+		// The batched deactivation runs as a team_deactivation job, which is followed on /jobs like any other.
+		job, err := s.jobService.CreateJob(r.Context(), api.JobType(domain.JobTeamDeactivation), map[string]any{
+			"team_name":  teamName,
+			"batch_size": *req.BatchSize,
+		})
+		if err != nil {
+			s.handleServiceError(w, r, op, err)
+			return
+		}
+
+		// The link keeps the /v1 prefix the request was made with.
+		jobsPath := strings.TrimSuffix(r.URL.Path, "/team/deactivate") + "/jobs"
+		w.Header().Set("Location", fmt.Sprintf("%s/%d", jobsPath, job.JobId))
+
+		s.respond(w, http.StatusAccepted, api.JobResponse{Job: *job})
+
+		return
+	}
+
+	resp, err := s.userService.DeactivateTeam(r.Context(), teamName, req.Force)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) PostTeamReactivate(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostTeamReactivate"
+
+	var req reactivateTeamRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	teamName, err := s.teamName(r.Context(), "team", req.TeamName, req.TeamID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	resp, err := s.userService.ReactivateTeam(r.Context(), teamName, req.RestoreReviewers)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) GetTeamDeactivationJob(w http.ResponseWriter, r *http.Request, params api.GetTeamDeactivationJobParams) {
+	const op = "internal.transport.http.GetTeamDeactivationJob"
+
+	job, err := s.userService.GetDeactivationJob(r.Context(), params.JobId)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, api.DeactivationJobResponse{Job: *job})
+}
+
+func (s *Server) PostTeamSetPolicy(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostTeamSetPolicy"
+
+	var req setTeamPolicyRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	teamName, err := s.teamName(r.Context(), "team", req.TeamName, req.TeamID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	policy, err := s.teamService.SetTeamPolicy(r.Context(), api.TeamPolicy{
+		TeamName:              teamName,
+		StrategyWeights:       req.StrategyWeights,
+		AuthorOpenPrLimit:     req.AuthorOpenPRLimit,
+		OverQuotaAction:       (*api.TeamPolicyOverQuotaAction)(req.OverQuotaAction),
+		ReactivationRebalance: (*api.TeamPolicyReactivationRebalance)(req.ReactivationRebalance),
+	})
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, map[string]*api.TeamPolicy{"policy": policy})
+}
+
+func (s *Server) GetTeamGetPolicy(w http.ResponseWriter, r *http.Request, params api.GetTeamGetPolicyParams) {
+	const op = "internal.transport.http.GetTeamGetPolicy"
+
+	teamName, err := s.teamName(r.Context(), "team", queryValue(params.TeamName), params.TeamId)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	policy, err := s.teamService.GetTeamPolicy(r.Context(), teamName)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, map[string]*api.TeamPolicy{"policy": policy})
+}
+
+func (s *Server) GetPolicyEffective(w http.ResponseWriter, r *http.Request, params api.GetPolicyEffectiveParams) {
+	const op = "internal.transport.http.GetPolicyEffective"
+
+	teamName, err := s.teamName(r.Context(), "team", queryValue(params.TeamName), params.TeamId)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	policy, err := s.teamService.GetEffectivePolicy(r.Context(), teamName)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, map[string]*api.EffectivePolicy{"policy": policy})
+}
+
+func (s *Server) PostTeamSetCustomFields(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostTeamSetCustomFields"
+
+	var req setCustomFieldsRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	teamName, err := s.teamName(r.Context(), "team", req.TeamName, req.TeamID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	fields := api.TeamCustomFields{TeamName: teamName, Fields: make([]api.CustomField, len(req.Fields))}
+	for i, field := range req.Fields {
+		fields.Fields[i] = api.CustomField{Key: field.Key, Type: api.CustomFieldType(field.Type), Required: field.Required}
+	}
+
+	saved, err := s.teamService.SetCustomFields(r.Context(), fields)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, map[string]*api.TeamCustomFields{"custom_fields": saved})
+}
+
+func (s *Server) GetTeamGetCustomFields(w http.ResponseWriter, r *http.Request, params api.GetTeamGetCustomFieldsParams) {
+	const op = "internal.transport.http.GetTeamGetCustomFields"
+
+	teamName, err := s.teamName(r.Context(), "team", queryValue(params.TeamName), params.TeamId)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	fields, err := s.teamService.GetCustomFields(r.Context(), teamName)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, map[string]*api.TeamCustomFields{"custom_fields": fields})
+}
+
+func (s *Server) PostTeamBorrow(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostTeamBorrow"
+
+	var req borrowReviewersRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	teamName, err := s.teamName(r.Context(), "team", req.TeamName, req.TeamID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	lenderTeamName, err := s.teamName(r.Context(), "lender_team", req.LenderTeamName, req.LenderTeamID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	borrow, err := s.teamService.RequestReviewerBorrow(r.Context(), teamName, lenderTeamName, req.Count, req.DurationHours)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusCreated, map[string]*api.ReviewerBorrow{"borrow": borrow})
+}
+
+func (s *Server) PostTeamBorrowAccept(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostTeamBorrowAccept"
+
+	var req acceptBorrowRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	borrow, err := s.teamService.AcceptReviewerBorrow(r.Context(), req.BorrowID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, map[string]*api.ReviewerBorrow{"borrow": borrow})
+}
+
+func (s *Server) GetTeamBorrows(w http.ResponseWriter, r *http.Request, params api.GetTeamBorrowsParams) {
+	const op = "internal.transport.http.GetTeamBorrows"
+
+	teamName, err := s.teamName(r.Context(), "team", queryValue(params.TeamName), params.TeamId)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	borrows, err := s.teamService.ListReviewerBorrows(r.Context(), teamName)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	total := len(borrows)
+
+	s.respond(w, http.StatusOK, api.ReviewerBorrowsResponse{Items: borrows, Borrows: borrows, TotalEstimate: &total})
+}
+
+func (s *Server) PostTeamRename(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostTeamRename"
+
+	var req renameTeamRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	teamName, err := s.teamName(r.Context(), "team", req.TeamName, req.TeamID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	team, err := s.teamService.RenameTeam(r.Context(), teamName, req.NewTeamName)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.recordTeamRename(r, teamName, team)
+
+	s.respond(w, http.StatusOK, map[string]*api.Team{"team": team})
+}
+
+func (s *Server) DeleteTeam(w http.ResponseWriter, r *http.Request, params api.DeleteTeamParams) {
+	const op = "internal.transport.http.DeleteTeam"
+
+	teamName, err := s.teamName(r.Context(), "team", queryValue(params.TeamName), params.TeamId)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	force := params.Force != nil && *params.Force
+
+	resp, err := s.userService.DeleteTeam(r.Context(), teamName, force)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.recordTeamDeletion(r, teamName, resp.TeamId)
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) PostTeamRestore(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostTeamRestore"
+
+	var req restoreTeamRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	team, err := s.userService.RestoreTeam(r.Context(), req.TeamID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, map[string]*api.Team{"team": team})
+}
+
+func (s *Server) PostJobs(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostJobs"
+
+	var req createJobRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	job, err := s.jobService.CreateJob(r.Context(), api.JobType(req.Type), req.Params)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	// The link keeps the /v1 prefix the request was made with.
+	w.Header().Set("Location", fmt.Sprintf("%s/%d", strings.TrimSuffix(r.URL.Path, "/"), job.JobId))
+
+	s.respond(w, http.StatusAccepted, api.JobResponse{Job: *job})
+}
+
+func (s *Server) PostAdminStatsBackfill(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostAdminStatsBackfill"
+
+	var req statsBackfillRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	params := map[string]any{}
+	if req.BatchSize != nil {
+		params["batch_size"] = *req.BatchSize
+	}
+
+	job, err := s.jobService.CreateJob(r.Context(), api.JobType(domain.JobStatsBackfill), params)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	// The link keeps the /v1 prefix the request was made with.
+	jobsPath := strings.TrimSuffix(r.URL.Path, "/admin/stats/backfill") + "/jobs"
+	w.Header().Set("Location", fmt.Sprintf("%s/%d", jobsPath, job.JobId))
+
+	s.respond(w, http.StatusAccepted, api.JobResponse{Job: *job})
+}
+
+func (s *Server) GetJobsJobId(w http.ResponseWriter, r *http.Request, jobID int64) {
+	const op = "internal.transport.http.GetJobsJobId"
+
+	job, err := s.jobService.GetJob(r.Context(), jobID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, api.JobResponse{Job: *job})
+}
+
+func (s *Server) DeleteJobsJobId(w http.ResponseWriter, r *http.Request, jobID int64) {
+	const op = "internal.transport.http.DeleteJobsJobId"
+
+	job, err := s.jobService.CancelJob(r.Context(), jobID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, api.JobResponse{Job: *job})
+}
+
+func (s *Server) GetAdminNotifications(w http.ResponseWriter, r *http.Request, params api.GetAdminNotificationsParams) {
+	const op = "internal.transport.http.GetAdminNotifications"
+
+	query := service.DeliveryQuery{
+		Channel:       queryValue(params.Channel),
+		RecipientID:   queryValue(params.RecipientId),
+		PullRequestID: queryValue(params.PullRequestId),
+		Cursor:        queryValue(params.Cursor),
+		Limit:         defaultListLimit,
+	}
+
+	if params.Event != nil {
+		query.Event = string(*params.Event)
+	}
+
+	if params.Status != nil {
+		query.Status = string(*params.Status)
+	}
+
+	if params.Limit != nil {
+		query.Limit = *params.Limit
+	}
+
+	if params.Offset != nil {
+		query.Offset = *params.Offset
+	}
+
+	resp, err := s.notifications.ListDeliveries(r.Context(), query)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) PostAdminNotificationsDeliveryIdRetry(w http.ResponseWriter, r *http.Request, deliveryID int64) {
+	const op = "internal.transport.http.PostAdminNotificationsDeliveryIdRetry"
+
+	delivery, err := s.notifications.RetryDelivery(r.Context(), deliveryID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, api.NotificationDeliveryResponse{Delivery: *delivery})
+}
+
+func (s *Server) GetAdminFreezes(w http.ResponseWriter, r *http.Request, params api.GetAdminFreezesParams) {
+	const op = "internal.transport.http.GetAdminFreezes"
+
+	var teamName string
+	if params.TeamName != nil || params.TeamId != nil {
+		var err error
+
+		teamName, err = s.teamName(r.Context(), "team", queryValue(params.TeamName), params.TeamId)
+		if err != nil {
+			s.handleServiceError(w, r, op, err)
+			return
+		}
+	}
+
+	includeEnded := params.IncludeEnded != nil && *params.IncludeEnded
+
+	resp, err := s.freezes.ListFreezeWindows(r.Context(), teamName, includeEnded)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) PostAdminFreezes(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostAdminFreezes"
+
+	var req createFreezeWindowRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	teamName := req.TeamName
+	if req.TeamID != nil || teamName != "" {
+		var err error
+
+		teamName, err = s.teamName(r.Context(), "team", req.TeamName, req.TeamID)
+		if err != nil {
+			s.handleServiceError(w, r, op, err)
+			return
+		}
+	}
+
+	window, err := s.freezes.CreateFreezeWindow(r.Context(), teamName, req.Reason, req.StartsAt, req.EndsAt)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusCreated, api.FreezeWindowResponse{Freeze: *window})
+}
+
+func (s *Server) DeleteAdminFreezesFreezeId(w http.ResponseWriter, r *http.Request, freezeID int64) {
+	const op = "internal.transport.http.DeleteAdminFreezesFreezeId"
+
+	if err := s.freezes.DeleteFreezeWindow(r.Context(), freezeID); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respond is a helper function to encode data to JSON and write it to the response.
+// It centralizes setting the Content-Type header and writing the status code.
+func (s *Server) respond(w http.ResponseWriter, code int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+
+	if data != nil {
+		if err := json.NewEncoder(w).Encode(data); err != nil {
+			s.log.Error("failed to encode response", sl.Err(err))
+		}
+	}
+}
+
+// respondError is a convenience wrapper around respond for sending simple error messages.
+func (s *Server) respondError(w http.ResponseWriter, code int, message string) {
+	s.respond(w, code, map[string]string{"error": message})
+}
+
+// respondAPIError formats and sends a structured error response that conforms to the OpenAPI specification.
+func (s *Server) respondAPIError(w http.ResponseWriter, code int, apiCode api.ErrorResponseErrorCode, message string) {
+	var errResp api.ErrorResponse
+	errResp.Error.Code = apiCode
+	errResp.Error.Message = message
+
+	s.respond(w, code, errResp)
+}
+
+// respondNoCandidate writes a NO_CANDIDATE error listing the alternatives an administrator may assign manually.
+func (s *Server) respondNoCandidate(w http.ResponseWriter, err *apperrors.NoCandidateError) {
+	alternatives := make([]api.ReplacementAlternative, len(err.Alternatives))
+	for i, a := range err.Alternatives {
+		alternatives[i] = api.ReplacementAlternative{
+			UserId:      a.UserID,
+			Username:    a.Username,
+			TeamName:    a.TeamName,
+			TeamId:      a.TeamID,
+			Reason:      api.ReplacementAlternativeReason(a.Reason),
+			OpenReviews: a.OpenReviews,
+		}
+	}
+
+	var errResp api.ErrorResponse
+	errResp.Error.Code = api.NOCANDIDATE
+	errResp.Error.Message = apperrors.ErrNoCandidate.Error()
+	errResp.Error.Alternatives = &alternatives
+
+	s.respond(w, http.StatusConflict, errResp)
+}
+
+// decodeAndValidate is a helper that deserializes a JSON request body into a struct
+// and then runs validation checks on it.
+func (s *Server) decodeAndValidate(r *http.Request, v interface{}) error {
+	if err := s.decode(r.Body, v); err != nil {
+		return err
+	}
+
+	if err := s.validate(r, v); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// team is teamName for the handlers that need the whole team. A team given by ID is read by ID.
+func (s *Server) team(ctx context.Context, name string, id *int) (*api.Team, error) {
+	if id != nil && name == "" {
+		return s.teamService.GetTeamByID(ctx, *id)
+	}
+
+	teamName, err := s.teamName(ctx, "team", name, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.teamService.GetTeam(ctx, teamName)
+}
+
+// teamName returns the name of the team a request refers to either by name or by ID, which, unlike the name,
+// survives a rename. Exactly one of them must be given; field is the parameter prefix used in the error messages.
+func (s *Server) teamName(ctx context.Context, field, name string, id *int) (string, error) {
+	switch {
+	case name != "" && id != nil:
+		return "", fmt.Errorf("%w: %s_name and %s_id are mutually exclusive", apperrors.ErrValidation, field, field)
+	case id != nil:
+		team, err := s.teamService.GetTeamByID(ctx, *id)
+		if err != nil {
+			return "", err
+		}
+
+		return team.TeamName, nil
+	case name == "":
+		return "", fmt.Errorf("%w: %s_name or %s_id is required", apperrors.ErrValidation, field, field)
+	}
+
+	return name, nil
+}
+
+// queryValue returns the value of an optional query parameter, or an empty string if it is not set.
+func queryValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+// decode is a helper function to decode a JSON request body.
+func (s *Server) decode(body io.ReadCloser, v interface{}) error {
+	body = http.MaxBytesReader(nil, body, s.maxBodyBytes)
+	defer body.Close()
+
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return fmt.Errorf("%w: body exceeds %d bytes", apperrors.ErrBodyTooLarge, tooLarge.Limit)
+		}
+
+		return fmt.Errorf("%w: %w", apperrors.ErrInvalidRequest, err)
+	}
+
+	return nil
+}
+
+// handleServiceError provides centralized error handling for all HTTP handlers.
+// It logs the internal error and maps it to a user-friendly HTTP response.
+func (s *Server) handleServiceError(w http.ResponseWriter, r *http.Request, op string, err error) {
+	ctx := context.Background()
+	if r != nil {
+		ctx = r.Context()
+	}
+
+	log := s.log.With(slog.String("op", op))
+	log.ErrorContext(ctx, "service error occurred", sl.Err(err))
+
+	// A query cancelled by the request timeout fails with an error of its driver rather than with the context's.
+	if errors.Is(err, context.DeadlineExceeded) || (r != nil && errors.Is(r.Context().Err(), context.DeadlineExceeded)) {
+		s.respondTimeout(w)
+		return
+	}
 
 	var (
 		teamExistsErr *apperrors.TeamAlreadyExistsError
 		prExistsErr   *apperrors.PRAlreadyExistsError
+		capacityErr   *apperrors.InsufficientCapacityError
+		quotaErr      *apperrors.AuthorQuotaExceededError
+		inactiveErr   *apperrors.AuthorInactiveError
+		teamSizeErr   *apperrors.TeamTooSmallError
+		approvalErr   *apperrors.ApprovalRequiredError
+		frozenErr     *apperrors.MergeFrozenError
+		noCandErr     *apperrors.NoCandidateError
 		validationErr *validation.ValidationError
 	)
 
@@ -300,8 +1513,12 @@ func (s *Server) handleServiceError(w http.ResponseWriter, _ *http.Request, op s
 	case errors.As(err, &validationErr):
 		wrappedErr := fmt.Errorf("%w: %s", apperrors.ErrValidation, validationErr.Error())
 		s.respondError(w, http.StatusBadRequest, wrappedErr.Error())
+	case errors.Is(err, apperrors.ErrBodyTooLarge):
+		s.respondAPIError(w, http.StatusRequestEntityTooLarge, api.PAYLOADTOOLARGE, err.Error())
 	case errors.Is(err, apperrors.ErrInvalidRequest):
 		s.respondError(w, http.StatusBadRequest, "invalid request body")
+	case errors.Is(err, apperrors.ErrValidation):
+		s.respondError(w, http.StatusBadRequest, err.Error())
 	case errors.Is(err, apperrors.ErrNotFound):
 		s.respondAPIError(w, http.StatusNotFound, api.NOTFOUND, "resource not found")
 	case errors.As(err, &teamExistsErr):
@@ -310,10 +1527,42 @@ func (s *Server) handleServiceError(w http.ResponseWriter, _ *http.Request, op s
 		s.respondAPIError(w, http.StatusConflict, api.PREXISTS, "pull request with this id already exists")
 	case errors.Is(err, apperrors.ErrPRMerged):
 		s.respondAPIError(w, http.StatusConflict, api.PRMERGED, apperrors.ErrPRMerged.Error())
+	case errors.Is(err, apperrors.ErrPRClosed):
+		s.respondAPIError(w, http.StatusConflict, api.PRCLOSED, apperrors.ErrPRClosed.Error())
+	case errors.As(err, &approvalErr):
+		s.respondAPIError(w, http.StatusConflict, api.NOTAPPROVED, approvalErr.Error())
+	case errors.Is(err, apperrors.ErrApprovalRequired):
+		s.respondAPIError(w, http.StatusConflict, api.NOTAPPROVED, apperrors.ErrApprovalRequired.Error())
+	case errors.As(err, &frozenErr):
+		s.respondAPIError(w, http.StatusConflict, api.FREEZE, frozenErr.Error())
+	case errors.Is(err, apperrors.ErrMergeFrozen):
+		s.respondAPIError(w, http.StatusConflict, api.FREEZE, apperrors.ErrMergeFrozen.Error())
 	case errors.Is(err, apperrors.ErrReviewerNotAssigned):
 		s.respondAPIError(w, http.StatusConflict, api.NOTASSIGNED, apperrors.ErrReviewerNotAssigned.Error())
+	case errors.As(err, &noCandErr):
+		s.respondNoCandidate(w, noCandErr)
 	case errors.Is(err, apperrors.ErrNoCandidate):
 		s.respondAPIError(w, http.StatusConflict, api.NOCANDIDATE, apperrors.ErrNoCandidate.Error())
+	case errors.Is(err, apperrors.ErrDeactivationInProgress):
+		s.respondAPIError(w, http.StatusConflict, api.DEACTIVATIONINPROGRESS, apperrors.ErrDeactivationInProgress.Error())
+	case errors.As(err, &capacityErr):
+		s.respondAPIError(w, http.StatusConflict, api.INSUFFICIENTCAPACITY, capacityErr.Error())
+	case errors.Is(err, apperrors.ErrInsufficientCapacity):
+		s.respondAPIError(w, http.StatusConflict, api.INSUFFICIENTCAPACITY, apperrors.ErrInsufficientCapacity.Error())
+	case errors.Is(err, apperrors.ErrBorrowNotPending):
+		s.respondAPIError(w, http.StatusConflict, api.BORROWNOTPENDING, apperrors.ErrBorrowNotPending.Error())
+	case errors.As(err, &quotaErr):
+		s.respondAPIError(w, http.StatusConflict, api.AUTHORQUOTAEXCEEDED, quotaErr.Error())
+	case errors.As(err, &inactiveErr):
+		s.respondAPIError(w, http.StatusConflict, api.AUTHORINACTIVE, inactiveErr.Error())
+	case errors.As(err, &teamSizeErr):
+		s.respondAPIError(w, http.StatusConflict, api.TEAMTOOSMALL, teamSizeErr.Error())
+	case errors.Is(err, apperrors.ErrJobFinished):
+		s.respondAPIError(w, http.StatusConflict, api.JOBFINISHED, apperrors.ErrJobFinished.Error())
+	case errors.Is(err, apperrors.ErrDeliveryNotFailed):
+		s.respondAPIError(w, http.StatusConflict, api.DELIVERYNOTFAILED, apperrors.ErrDeliveryNotFailed.Error())
+	case errors.Is(err, apperrors.ErrDeliveryNotDead):
+		s.respondAPIError(w, http.StatusConflict, api.DELIVERYNOTDEAD, apperrors.ErrDeliveryNotDead.Error())
 	default:
 		s.respondError(w, http.StatusInternalServerError, "internal server error")
 	}
@@ -1,7 +1,9 @@
 package http
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -11,6 +13,8 @@ import (
 	"time"
 
 	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
 	"github.com/YusovID/pr-reviewer-service/pkg/api"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -41,6 +45,24 @@ func TestServer_PostTeamAdd(t *testing.T) {
 			expectedStatusCode:   http.StatusCreated,
 			expectedResponseBody: `{"team":{"team_name":"backend","members":[{"is_active":true,"user_id":"u1","username":"Alice"}]}}`,
 		},
+		{
+			name:        "Success - Username Normalized",
+			requestBody: `{"team_name": "backend", "members": [{"user_id": "u1", "username": "  \u0410\u043d\u0434\u0440\u0435\u0438\u0306 ", "is_active": true}]}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("CreateTeamWithUsers", mock.Anything, mock.MatchedBy(func(team api.Team) bool {
+					return team.Members[0].Username == "Андрей"
+				})).Return(&api.Team{TeamName: "backend", Members: []api.TeamMember{{UserId: "u1", Username: "Андрей", IsActive: true}}}, nil).Once()
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"team":{"team_name":"backend","members":[{"is_active":true,"user_id":"u1","username":"Андрей"}]}}`,
+		},
+		{
+			name:                 "Invalid Username",
+			requestBody:          `{"team_name": "backend", "members": [{"user_id": "u1", "username": "Ann\u200ba", "is_active": true}]}`,
+			setupMocks:           func(tsm *TeamServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: field 'Username' must not contain control or invisible characters"}`,
+		},
 		{
 			name:        "Service Error - Already Exists",
 			requestBody: `{"team_name": "backend", "members": []}`,
@@ -80,38 +102,109 @@ func TestServer_PostTeamAdd(t *testing.T) {
 	}
 }
 
+func TestServer_PostTeamAddBodyLimit(t *testing.T) {
+	members := make([]string, 50)
+	for i := range members {
+		members[i] = fmt.Sprintf(`{"user_id": "u%d", "username": "User %d", "is_active": true}`, i, i)
+	}
+
+	body := `{"team_name": "backend", "members": [` + strings.Join(members, ",") + `]}`
+
+	teamServiceMock := new(TeamServiceMock)
+	teamServiceMock.On("CreateTeamWithUsers", mock.Anything, mock.Anything).Return(&api.Team{TeamName: "backend"}, nil).Once()
+
+	post := func(maxBodyBytes int64) *httptest.ResponseRecorder {
+		server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamServiceMock, nil, nil, WithMaxBodyBytes(maxBodyBytes))
+
+		req := httptest.NewRequest(http.MethodPost, "/team/add", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		api.Handler(server).ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := post(1024)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.JSONEq(t, `{"error":{"code":"PAYLOAD_TOO_LARGE","message":"request body too large: body exceeds 1024 bytes"}}`, rr.Body.String())
+
+	rr = post(int64(len(body)))
+	assert.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	teamServiceMock.AssertExpectations(t)
+}
+
 func TestServer_GetTeamGet(t *testing.T) {
-	teamName := "my-team"
+	teamName, teamID := "my-team", 3
 	teamResponse := &api.Team{
 		TeamName: teamName,
+		TeamId:   &teamID,
 		Members:  []api.TeamMember{{UserId: "u1", Username: "Alice", IsActive: true}},
 	}
 
 	testCases := []struct {
 		name                 string
-		teamNameQuery        string
+		query                string
 		setupMocks           func(*TeamServiceMock)
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
 		{
-			name:          "Success",
-			teamNameQuery: teamName,
+			name:  "Success",
+			query: "team_name=" + teamName,
 			setupMocks: func(tsm *TeamServiceMock) {
 				tsm.On("GetTeam", mock.Anything, teamName).Return(teamResponse, nil).Once()
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"team":{"team_name":"my-team","members":[{"is_active":true,"user_id":"u1","username":"Alice"}]}}`,
+			expectedResponseBody: `{"team":{"team_name":"my-team","team_id":3,"members":[{"is_active":true,"user_id":"u1","username":"Alice"}]}}`,
+		},
+		{
+			name:  "Success - By ID",
+			query: "team_id=3",
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("GetTeamByID", mock.Anything, teamID).Return(teamResponse, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"team":{"team_name":"my-team","team_id":3,"members":[{"is_active":true,"user_id":"u1","username":"Alice"}]}}`,
 		},
 		{
-			name:          "Service Error - Not Found",
-			teamNameQuery: "unknown-team",
+			name:  "Service Error - Not Found",
+			query: "team_name=unknown-team",
 			setupMocks: func(tsm *TeamServiceMock) {
 				tsm.On("GetTeam", mock.Anything, "unknown-team").Return(nil, apperrors.ErrNotFound).Once()
 			},
 			expectedStatusCode:   http.StatusNotFound,
 			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
 		},
+		{
+			name:  "Success - Selected Fields",
+			query: "team_id=3&fields=team_name,members.user_id",
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("GetTeamByID", mock.Anything, teamID).Return(teamResponse, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"team":{"team_name":"my-team","members":[{"user_id":"u1"}]}}`,
+		},
+		{
+			name:                 "Validation Error - Unknown Field",
+			query:                "team_name=my-team&fields=team_name,members.email",
+			setupMocks:           func(tsm *TeamServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: unknown field 'members.email'"}`,
+		},
+		{
+			name:                 "Validation Error - Name And ID",
+			query:                "team_name=my-team&team_id=3",
+			setupMocks:           func(tsm *TeamServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: team_name and team_id are mutually exclusive"}`,
+		},
+		{
+			name:                 "Validation Error - No Team",
+			setupMocks:           func(tsm *TeamServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: team_name or team_id is required"}`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -120,7 +213,7 @@ func TestServer_GetTeamGet(t *testing.T) {
 			tc.setupMocks(teamServiceMock)
 			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamServiceMock, nil, nil)
 
-			req := httptest.NewRequest(http.MethodGet, "/team/get?team_name="+tc.teamNameQuery, nil)
+			req := httptest.NewRequest(http.MethodGet, "/team/get?"+tc.query, nil)
 			rr := httptest.NewRecorder()
 
 			router := api.Handler(server)
@@ -133,48 +226,121 @@ func TestServer_GetTeamGet(t *testing.T) {
 	}
 }
 
-func TestServer_PostUsersSetIsActive(t *testing.T) {
-	userResponse := &api.User{
-		UserId:   "user1",
-		Username: "Test User",
-		TeamName: "team-a",
-		IsActive: false,
+func TestServer_PostTeamSetPolicy(t *testing.T) {
+	policy := &api.TeamPolicy{
+		TeamName:        "backend",
+		StrategyWeights: map[string]int{"random": 80, "least_loaded": 20},
+	}
+	savedPolicy := &api.TeamPolicy{
+		TeamName:        "backend",
+		TeamId:          1,
+		StrategyWeights: map[string]int{"random": 80, "least_loaded": 20},
 	}
 
 	testCases := []struct {
 		name                 string
 		requestBody          string
-		setupMocks           func(*UserServiceMock)
+		setupMocks           func(*TeamServiceMock)
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
 		{
 			name:        "Success",
-			requestBody: `{"user_id": "user1", "is_active": false}`,
-			setupMocks: func(usm *UserServiceMock) {
-				usm.On("SetIsActive", mock.Anything, "user1", false).Return(userResponse, nil).Once()
+			requestBody: `{"team_name": "backend", "strategy_weights": {"random": 80, "least_loaded": 20}}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("SetTeamPolicy", mock.Anything, *policy).Return(savedPolicy, nil).Once()
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"user":{"user_id":"user1","username":"Test User","team_name":"team-a","is_active":false}}`,
+			expectedResponseBody: `{"policy":{"team_name":"backend","team_id":1,"strategy_weights":{"least_loaded":20,"random":80}}}`,
 		},
 		{
-			name:        "Service Error - User Not Found",
-			requestBody: `{"user_id": "not-found", "is_active": false}`,
-			setupMocks: func(usm *UserServiceMock) {
-				usm.On("SetIsActive", mock.Anything, "not-found", false).Return(nil, apperrors.ErrNotFound).Once()
+			name:        "Success - Team given by ID",
+			requestBody: `{"team_id": 1, "strategy_weights": {"random": 80, "least_loaded": 20}}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("GetTeamByID", mock.Anything, 1).Return(&api.Team{TeamName: "backend"}, nil).Once()
+				tsm.On("SetTeamPolicy", mock.Anything, *policy).Return(savedPolicy, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"policy":{"team_name":"backend","team_id":1,"strategy_weights":{"least_loaded":20,"random":80}}}`,
+		},
+		{
+			name:        "Service Error - Team ID Not Found",
+			requestBody: `{"team_id": 9, "strategy_weights": {}}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("GetTeamByID", mock.Anything, 9).Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+		{
+			name:                 "Validation Error - Name And ID",
+			requestBody:          `{"team_name": "backend", "team_id": 1, "strategy_weights": {}}`,
+			setupMocks:           func(tsm *TeamServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: team_name and team_id are mutually exclusive"}`,
+		},
+		{
+			name:        "Service Error - Unknown Strategy",
+			requestBody: `{"team_name": "backend", "strategy_weights": {"alphabetical": 1}}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("SetTeamPolicy", mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("%w: unknown strategy 'alphabetical'", apperrors.ErrValidation)).Once()
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: unknown strategy 'alphabetical'"}`,
+		},
+		{
+			name:        "Service Error - Team Not Found",
+			requestBody: `{"team_name": "unknown", "strategy_weights": {"random": 1}}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("SetTeamPolicy", mock.Anything, mock.Anything).Return(nil, apperrors.ErrNotFound).Once()
 			},
 			expectedStatusCode:   http.StatusNotFound,
 			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
 		},
+		{
+			name: "Success - With author quota",
+			requestBody: `{"team_name": "backend", "strategy_weights": {}, "author_open_pr_limit": 3,
+				"over_quota_action": "queue"}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				limit, action := 3, api.Queue
+				quotaPolicy := api.TeamPolicy{
+					TeamName:          "backend",
+					StrategyWeights:   map[string]int{},
+					AuthorOpenPrLimit: &limit,
+					OverQuotaAction:   &action,
+				}
+				saved := quotaPolicy
+				saved.TeamId = 1
+				tsm.On("SetTeamPolicy", mock.Anything, quotaPolicy).Return(&saved, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"policy":{"team_name":"backend","team_id":1,"strategy_weights":{},"author_open_pr_limit":3,
+				"over_quota_action":"queue"}}`,
+		},
+		{
+			name:                 "Validation Error - Unknown Over Quota Action",
+			requestBody:          `{"team_name": "backend", "strategy_weights": {}, "over_quota_action": "drop"}`,
+			setupMocks:           func(tsm *TeamServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: field 'OverQuotaAction' failed on the 'oneof' tag"}`,
+		},
+		{
+			name:                 "Validation Error - Negative Weight",
+			requestBody:          `{"team_name": "backend", "strategy_weights": {"random": -1}}`,
+			setupMocks:           func(tsm *TeamServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: field 'StrategyWeights[random]' failed on the 'min' tag"}`,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			userServiceMock := new(UserServiceMock)
-			tc.setupMocks(userServiceMock)
-			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, userServiceMock, nil)
+			teamServiceMock := new(TeamServiceMock)
+			tc.setupMocks(teamServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamServiceMock, nil, nil)
 
-			req := httptest.NewRequest(http.MethodPost, "/users/setIsActive", strings.NewReader(tc.requestBody))
+			req := httptest.NewRequest(http.MethodPost, "/team/setPolicy", strings.NewReader(tc.requestBody))
 			req.Header.Set("Content-Type", "application/json")
 
 			rr := httptest.NewRecorder()
@@ -184,78 +350,122 @@ func TestServer_PostUsersSetIsActive(t *testing.T) {
 
 			assert.Equal(t, tc.expectedStatusCode, rr.Code)
 			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
-			userServiceMock.AssertExpectations(t)
+			teamServiceMock.AssertExpectations(t)
 		})
 	}
 }
 
-func TestServer_PostPullRequestCreate(t *testing.T) {
-	now := time.Now()
-	createdPR := &api.PullRequest{
-		PullRequestId:     "pr-1",
-		PullRequestName:   "New Feature",
-		AuthorId:          "author-1",
-		Status:            api.PullRequestStatusOPEN,
-		AssignedReviewers: []string{"reviewer-1"},
-		CreatedAt:         &now,
+func TestServer_GetTeamGetPolicy(t *testing.T) {
+	teamServiceMock := new(TeamServiceMock)
+	teamServiceMock.On("GetTeamPolicy", mock.Anything, "backend").Return(&api.TeamPolicy{
+		TeamName:        "backend",
+		TeamId:          1,
+		StrategyWeights: map[string]int{},
+	}, nil).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamServiceMock, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/team/getPolicy?team_name=backend", nil)
+	rr := httptest.NewRecorder()
+
+	router := api.Handler(server)
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"policy":{"team_name":"backend","team_id":1,"strategy_weights":{}}}`, rr.Body.String())
+	teamServiceMock.AssertExpectations(t)
+}
+
+func TestServer_GetPolicyEffective(t *testing.T) {
+	teamServiceMock := new(TeamServiceMock)
+	teamServiceMock.On("GetEffectivePolicy", mock.Anything, "backend").Return(&api.EffectivePolicy{
+		TeamName:              "backend",
+		TeamId:                1,
+		ReviewersPerPr:        2,
+		StrategyWeights:       map[string]int{"random": 1},
+		OverQuotaAction:       api.Reject,
+		ReactivationRebalance: api.RebalanceOff,
+		Sources:               map[string]api.PolicySource{"strategy_weights": api.PolicySourceDefault},
+	}, nil).Once()
+	teamServiceMock.On("GetEffectivePolicy", mock.Anything, "ghost").Return(nil, apperrors.ErrNotFound).Once()
+
+	router := api.Handler(NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamServiceMock, nil, nil))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/policy/effective?team_name=backend", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"policy":{"team_name":"backend","team_id":1,"reviewers_per_pr":2,"strategy_weights":{"random":1},
+		"over_quota_action":"reject","reactivation_rebalance":"off","require_approvals":false,"strict_checks":false,
+		"sources":{"strategy_weights":"default"}}}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/policy/effective?team_name=ghost", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/policy/effective", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	teamServiceMock.AssertExpectations(t)
+}
+
+func TestServer_PostTeamSetCustomFields(t *testing.T) {
+	fields := &api.TeamCustomFields{
+		TeamName: "backend",
+		Fields: []api.CustomField{
+			{Key: "risk", Type: api.CustomFieldString, Required: true},
+			{Key: "story_points", Type: api.CustomFieldNumber},
+		},
 	}
 
 	testCases := []struct {
 		name                 string
 		requestBody          string
-		setupMocks           func(*PullRequestServiceMock)
+		setupMocks           func(*TeamServiceMock)
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
 		{
-			name:        "Success",
-			requestBody: `{"pull_request_id": "pr-1", "pull_request_name": "New Feature", "author_id": "author-1"}`,
-			setupMocks: func(prsm *PullRequestServiceMock) {
-				prsm.On("CreatePR", mock.Anything, "pr-1", "New Feature", "author-1").
-					Return(createdPR, nil).Once()
+			name: "Success",
+			requestBody: `{"team_name": "backend", "fields": [{"key": "risk", "type": "string", "required": true},
+				{"key": "story_points", "type": "number"}]}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				saved := *fields
+				saved.TeamId = 1
+				tsm.On("SetCustomFields", mock.Anything, *fields).Return(&saved, nil).Once()
 			},
-			expectedStatusCode: http.StatusCreated,
-			expectedResponseBody: `{
-				"pr": {
-					"pull_request_id": "pr-1",
-					"pull_request_name": "New Feature",
-					"author_id": "author-1",
-					"status": "OPEN",
-					"assigned_reviewers": ["reviewer-1"],
-					"createdAt": "` + now.Format(time.RFC3339Nano) + `",
-					"mergedAt": null
-				}
-			}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"custom_fields":{"team_name":"backend","team_id":1,"fields":[{"key":"risk","type":"string","required":true},
+				{"key":"story_points","type":"number","required":false}]}}`,
 		},
 		{
-			name:        "Service Error - Author Not Found",
-			requestBody: `{"pull_request_id": "pr-1", "pull_request_name": "New Feature", "author_id": "author-not-found"}`,
-			setupMocks: func(prsm *PullRequestServiceMock) {
-				prsm.On("CreatePR", mock.Anything, "pr-1", "New Feature", "author-not-found").
-					Return(nil, apperrors.ErrNotFound).Once()
+			name:        "Service Error - Duplicate Key",
+			requestBody: `{"team_name": "backend", "fields": [{"key": "risk", "type": "string"}, {"key": "risk", "type": "number"}]}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("SetCustomFields", mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("%w: custom field 'risk' is listed twice", apperrors.ErrValidation)).Once()
 			},
-			expectedStatusCode:   http.StatusNotFound,
-			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: custom field 'risk' is listed twice"}`,
 		},
 		{
-			name:        "Service Error - PR Already Exists",
-			requestBody: `{"pull_request_id": "pr-exists", "pull_request_name": "New Feature", "author_id": "author-1"}`,
-			setupMocks: func(prsm *PullRequestServiceMock) {
-				prsm.On("CreatePR", mock.Anything, "pr-exists", "New Feature", "author-1").
-					Return(nil, &apperrors.PRAlreadyExistsError{PRID: "pr-exists"}).Once()
-			},
-			expectedStatusCode:   http.StatusConflict,
-			expectedResponseBody: `{"error":{"code":"PR_EXISTS","message":"pull request with this id already exists"}}`,
+			name:                 "Validation Error - Unknown Type",
+			requestBody:          `{"team_name": "backend", "fields": [{"key": "due", "type": "date"}]}`,
+			setupMocks:           func(tsm *TeamServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: field 'Type' failed on the 'oneof' tag"}`,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			prServiceMock := new(PullRequestServiceMock)
-			tc.setupMocks(prServiceMock)
-			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+			teamServiceMock := new(TeamServiceMock)
+			tc.setupMocks(teamServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamServiceMock, nil, nil)
 
-			req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader(tc.requestBody))
+			req := httptest.NewRequest(http.MethodPost, "/team/setCustomFields", strings.NewReader(tc.requestBody))
 			req.Header.Set("Content-Type", "application/json")
 
 			rr := httptest.NewRecorder()
@@ -264,59 +474,111 @@ func TestServer_PostPullRequestCreate(t *testing.T) {
 			router.ServeHTTP(rr, req)
 
 			assert.Equal(t, tc.expectedStatusCode, rr.Code)
-			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
-			prServiceMock.AssertExpectations(t)
+			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			teamServiceMock.AssertExpectations(t)
 		})
 	}
 }
 
-func TestServer_PostPullRequestMerge(t *testing.T) {
-	now := time.Now()
-	mergedPR := &api.PullRequest{
-		PullRequestId: "pr-1",
-		Status:        api.PullRequestStatusMERGED,
-		MergedAt:      &now,
+func TestServer_GetTeamGetCustomFields(t *testing.T) {
+	teamServiceMock := new(TeamServiceMock)
+	teamServiceMock.On("GetCustomFields", mock.Anything, "backend").Return(&api.TeamCustomFields{
+		TeamName: "backend",
+		TeamId:   1,
+		Fields:   []api.CustomField{{Key: "hotfix", Type: api.CustomFieldBoolean}},
+	}, nil).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamServiceMock, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/team/getCustomFields?team_name=backend", nil)
+	rr := httptest.NewRecorder()
+
+	router := api.Handler(server)
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"custom_fields":{"team_name":"backend","team_id":1,"fields":[{"key":"hotfix","type":"boolean","required":false}]}}`, rr.Body.String())
+	teamServiceMock.AssertExpectations(t)
+}
+
+func TestServer_PostTeamBorrow(t *testing.T) {
+	requestedAt := time.Date(2025, time.March, 14, 12, 0, 0, 0, time.UTC)
+	borrow := &api.ReviewerBorrow{
+		BorrowId:       7,
+		TeamName:       "backend",
+		TeamId:         1,
+		LenderTeamName: "payments",
+		LenderTeamId:   3,
+		Count:          2,
+		DurationHours:  48,
+		Status:         api.Requested,
+		RequestedAt:    requestedAt,
+		ReviewerIds:    []string{},
 	}
 
 	testCases := []struct {
 		name                 string
 		requestBody          string
-		setupMocks           func(*PullRequestServiceMock)
+		setupMocks           func(*TeamServiceMock)
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
 		{
 			name:        "Success",
-			requestBody: `{"pull_request_id": "pr-1"}`,
-			setupMocks: func(prsm *PullRequestServiceMock) {
-				prsm.On("MergePR", mock.Anything, "pr-1").Return(mergedPR, nil).Once()
+			requestBody: `{"team_name": "backend", "lender_team_name": "payments", "count": 2, "duration_hours": 48}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("RequestReviewerBorrow", mock.Anything, "backend", "payments", 2, 48).Return(borrow, nil).Once()
 			},
-			expectedStatusCode: http.StatusOK,
-			expectedResponseBody: `{
-				"pr": {
-					"pull_request_id": "pr-1", "status": "MERGED", "mergedAt": "` + now.Format(time.RFC3339Nano) + `",
-					"pull_request_name": "", "author_id": "", "assigned_reviewers": null, "createdAt": null
-				}
-			}`,
+			expectedStatusCode: http.StatusCreated,
+			expectedResponseBody: `{"borrow":{"borrow_id":7,"team_name":"backend","team_id":1,"lender_team_name":"payments",
+				"lender_team_id":3,"count":2,"duration_hours":48,"status":"requested","requested_at":"2025-03-14T12:00:00Z",
+				"accepted_at":null,"expires_at":null,"reviewer_ids":[]}}`,
 		},
 		{
-			name:        "Service Error - PR Not Found",
-			requestBody: `{"pull_request_id": "not-found"}`,
-			setupMocks: func(prsm *PullRequestServiceMock) {
-				prsm.On("MergePR", mock.Anything, "not-found").Return(nil, apperrors.ErrNotFound).Once()
+			name:        "Success - Lender given by ID",
+			requestBody: `{"team_name": "backend", "lender_team_id": 3, "count": 2, "duration_hours": 48}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("GetTeamByID", mock.Anything, 3).Return(&api.Team{TeamName: "payments"}, nil).Once()
+				tsm.On("RequestReviewerBorrow", mock.Anything, "backend", "payments", 2, 48).Return(borrow, nil).Once()
 			},
-			expectedStatusCode:   http.StatusNotFound,
-			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+			expectedStatusCode: http.StatusCreated,
+			expectedResponseBody: `{"borrow":{"borrow_id":7,"team_name":"backend","team_id":1,"lender_team_name":"payments",
+				"lender_team_id":3,"count":2,"duration_hours":48,"status":"requested","requested_at":"2025-03-14T12:00:00Z",
+				"accepted_at":null,"expires_at":null,"reviewer_ids":[]}}`,
+		},
+		{
+			name:                 "Validation Error - No Lender",
+			requestBody:          `{"team_name": "backend", "count": 2, "duration_hours": 48}`,
+			setupMocks:           func(tsm *TeamServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: lender_team_name or lender_team_id is required"}`,
+		},
+		{
+			name:        "Service Error - Same Team",
+			requestBody: `{"team_name": "backend", "lender_team_name": "backend", "count": 1, "duration_hours": 1}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("RequestReviewerBorrow", mock.Anything, "backend", "backend", 1, 1).
+					Return(nil, fmt.Errorf("%w: team cannot borrow reviewers from itself", apperrors.ErrValidation)).Once()
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: team cannot borrow reviewers from itself"}`,
+		},
+		{
+			name:                 "Validation Error - Duration Too Long",
+			requestBody:          `{"team_name": "backend", "lender_team_name": "payments", "count": 1, "duration_hours": 721}`,
+			setupMocks:           func(tsm *TeamServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: field 'DurationHours' failed on the 'max' tag"}`,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			prServiceMock := new(PullRequestServiceMock)
-			tc.setupMocks(prServiceMock)
-			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+			teamServiceMock := new(TeamServiceMock)
+			tc.setupMocks(teamServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamServiceMock, nil, nil)
 
-			req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", strings.NewReader(tc.requestBody))
+			req := httptest.NewRequest(http.MethodPost, "/team/borrow", strings.NewReader(tc.requestBody))
 			req.Header.Set("Content-Type", "application/json")
 
 			rr := httptest.NewRecorder()
@@ -325,77 +587,1932 @@ func TestServer_PostPullRequestMerge(t *testing.T) {
 			router.ServeHTTP(rr, req)
 
 			assert.Equal(t, tc.expectedStatusCode, rr.Code)
-			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
-			prServiceMock.AssertExpectations(t)
+			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			teamServiceMock.AssertExpectations(t)
 		})
 	}
 }
 
-func TestServer_PostPullRequestReassign(t *testing.T) {
-	reassignedResponse := &api.ReassignResponse{
-		Pr: api.PullRequest{
-			PullRequestId:     "pr-123",
-			AssignedReviewers: []string{"new-reviewer"},
-		},
-		ReplacedBy: "new-reviewer",
-	}
-
+func TestServer_PostTeamBorrowAccept(t *testing.T) {
 	testCases := []struct {
 		name                 string
 		requestBody          string
-		setupMocks           func(*PullRequestServiceMock)
+		setupMocks           func(*TeamServiceMock)
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
 		{
-			name:        "Success",
-			requestBody: `{"pull_request_id": "pr-123", "old_user_id": "old-reviewer"}`,
-			setupMocks: func(prsm *PullRequestServiceMock) {
-				prsm.On("ReassignReviewer", mock.Anything, "pr-123", "old-reviewer").
-					Return(reassignedResponse, nil).Once()
+			name:        "Service Error - Not Pending",
+			requestBody: `{"borrow_id": 7}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("AcceptReviewerBorrow", mock.Anything, int64(7)).Return(nil, apperrors.ErrBorrowNotPending).Once()
 			},
-			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"pr":{"pull_request_id":"pr-123","pull_request_name":"","author_id":"","status":"","assigned_reviewers":["new-reviewer"],"createdAt":null,"mergedAt":null},"replaced_by":"new-reviewer"}`,
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"BORROW_NOT_PENDING","message":"reviewer borrow is not awaiting acceptance"}}`,
 		},
 		{
-			name:        "Service Error - PR Merged",
-			requestBody: `{"pull_request_id": "pr-123", "old_user_id": "old-reviewer"}`,
-			setupMocks: func(prsm *PullRequestServiceMock) {
-				prsm.On("ReassignReviewer", mock.Anything, "pr-123", "old-reviewer").
-					Return(nil, apperrors.ErrPRMerged).Once()
+			name:        "Service Error - No Active Members",
+			requestBody: `{"borrow_id": 7}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("AcceptReviewerBorrow", mock.Anything, int64(7)).
+					Return(nil, fmt.Errorf("%w: team 'payments' has no active members to lend", apperrors.ErrInsufficientCapacity)).Once()
 			},
 			expectedStatusCode:   http.StatusConflict,
-			expectedResponseBody: `{"error":{"code":"PR_MERGED","message":"cannot modify merged pull request"}}`,
+			expectedResponseBody: `{"error":{"code":"INSUFFICIENT_CAPACITY","message":"not enough active reviewers to take over the reviews"}}`,
+		},
+		{
+			name:                 "Validation Error - Missing Borrow ID",
+			requestBody:          `{}`,
+			setupMocks:           func(tsm *TeamServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: field 'BorrowID' failed on the 'required' tag"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			teamServiceMock := new(TeamServiceMock)
+			tc.setupMocks(teamServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamServiceMock, nil, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/team/borrow/accept", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			router := api.Handler(server)
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			teamServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetTeamBorrows(t *testing.T) {
+	teamServiceMock := new(TeamServiceMock)
+	teamServiceMock.On("ListReviewerBorrows", mock.Anything, "backend").Return([]api.ReviewerBorrow{}, nil).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamServiceMock, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/team/borrows?team_name=backend", nil)
+	rr := httptest.NewRecorder()
+
+	router := api.Handler(server)
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"items":[],"borrows":[],"next_cursor":null,"total_estimate":0}`, rr.Body.String())
+	teamServiceMock.AssertExpectations(t)
+}
+
+func TestServer_PostTeamRename(t *testing.T) {
+	teamID := 1
+	renamed := &api.Team{
+		TeamName: "platform",
+		TeamId:   &teamID,
+		Members:  []api.TeamMember{{UserId: "u1", Username: "Alice", IsActive: true}},
+	}
+
+	testCases := []struct {
+		name                 string
+		requestBody          string
+		setupMocks           func(*TeamServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedAudit        bool
+	}{
+		{
+			name:        "Success",
+			requestBody: `{"team_name": "backend", "new_team_name": "platform"}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("RenameTeam", mock.Anything, "backend", "platform").Return(renamed, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"team":{"team_name":"platform","team_id":1,"members":[{"user_id":"u1","username":"Alice","is_active":true}]}}`,
+			expectedAudit:        true,
+		},
+		{
+			name:        "Success - Team given by ID",
+			requestBody: `{"team_id": 1, "new_team_name": "platform"}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("GetTeamByID", mock.Anything, 1).Return(&api.Team{TeamName: "backend", TeamId: &teamID}, nil).Once()
+				tsm.On("RenameTeam", mock.Anything, "backend", "platform").Return(renamed, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"team":{"team_name":"platform","team_id":1,"members":[{"user_id":"u1","username":"Alice","is_active":true}]}}`,
+			expectedAudit:        true,
+		},
+		{
+			name:        "Service Error - Name Taken",
+			requestBody: `{"team_name": "backend", "new_team_name": "payments"}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("RenameTeam", mock.Anything, "backend", "payments").
+					Return(nil, &apperrors.TeamAlreadyExistsError{TeamName: "payments"}).Once()
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"TEAM_EXISTS","message":"team with this name already exists"}}`,
+		},
+		{
+			name:        "Service Error - Team Not Found",
+			requestBody: `{"team_name": "unknown", "new_team_name": "platform"}`,
+			setupMocks: func(tsm *TeamServiceMock) {
+				tsm.On("RenameTeam", mock.Anything, "unknown", "platform").Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+		{
+			name:                 "Validation Error - New Name Too Short",
+			requestBody:          `{"team_name": "backend", "new_team_name": "pl"}`,
+			setupMocks:           func(tsm *TeamServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: field 'NewTeamName' failed on the 'min' tag"}`,
+		},
+		{
+			name:                 "Validation Error - No Team",
+			requestBody:          `{"new_team_name": "platform"}`,
+			setupMocks:           func(tsm *TeamServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: team_name or team_id is required"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			teamServiceMock := new(TeamServiceMock)
+			tc.setupMocks(teamServiceMock)
+
+			var logs bytes.Buffer
+			server := NewServer(slog.New(slog.NewJSONHandler(&logs, nil)), teamServiceMock, nil, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/team/rename", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			router := api.Handler(server)
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+
+			if tc.expectedAudit {
+				assert.Contains(t, logs.String(), `"msg":"team renamed"`)
+				assert.Contains(t, logs.String(), `"team_id":1,"old_team_name":"backend","new_team_name":"platform"`)
+			} else {
+				assert.NotContains(t, logs.String(), "team renamed")
+			}
+
+			teamServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_PostUsersSetIsActive(t *testing.T) {
+	userResponse := &api.User{
+		UserId:   "user1",
+		Username: "Test User",
+		TeamName: "team-a",
+		TeamId:   2,
+		IsActive: false,
+	}
+
+	testCases := []struct {
+		name                 string
+		requestBody          string
+		setupMocks           func(*UserServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Success",
+			requestBody: `{"user_id": "user1", "is_active": false}`,
+			setupMocks: func(usm *UserServiceMock) {
+				usm.On("SetIsActive", mock.Anything, "user1", false).Return(&api.SetIsActiveResponse{User: *userResponse}, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"user":{"user_id":"user1","username":"Test User","team_name":"team-a","team_id":2,"is_active":false}}`,
+		},
+		{
+			name:        "Success - Reactivation rebalances reviews",
+			requestBody: `{"user_id": "user1", "is_active": true}`,
+			setupMocks: func(usm *UserServiceMock) {
+				active := *userResponse
+				active.IsActive = true
+
+				usm.On("SetIsActive", mock.Anything, "user1", true).Return(&api.SetIsActiveResponse{
+					User: active,
+					Rebalance: &api.RebalanceResult{Moves: []api.ReviewerMove{
+						{PullRequestId: "pr-1", FromUserId: "user2", ToUserId: "user1"},
+					}},
+				}, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"user":{"user_id":"user1","username":"Test User","team_name":"team-a","team_id":2,"is_active":true},
+				"rebalance":{"moves":[{"pull_request_id":"pr-1","from_user_id":"user2","to_user_id":"user1"}]}}`,
+		},
+		{
+			name:        "Success - Deactivation reassigns reviews",
+			requestBody: `{"user_id": "user1", "is_active": false}`,
+			setupMocks: func(usm *UserServiceMock) {
+				usm.On("SetIsActive", mock.Anything, "user1", false).Return(&api.SetIsActiveResponse{
+					User:          *userResponse,
+					Reassignments: &[]api.ReviewerMove{{PullRequestId: "pr-1", FromUserId: "user1", ToUserId: "user3"}},
+					Warnings:      &[]string{"pull request 'pr-2': no active replacement for reviewer 'user1'"},
+				}, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"user":{"user_id":"user1","username":"Test User","team_name":"team-a","team_id":2,"is_active":false},
+				"reassignments":[{"pull_request_id":"pr-1","from_user_id":"user1","to_user_id":"user3"}],
+				"warnings":["pull request 'pr-2': no active replacement for reviewer 'user1'"]}`,
+		},
+		{
+			name:        "Service Error - User Not Found",
+			requestBody: `{"user_id": "not-found", "is_active": false}`,
+			setupMocks: func(usm *UserServiceMock) {
+				usm.On("SetIsActive", mock.Anything, "not-found", false).Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userServiceMock := new(UserServiceMock)
+			tc.setupMocks(userServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, userServiceMock, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/users/setIsActive", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			router := api.Handler(server)
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			userServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_PostUsersTransfer(t *testing.T) {
+	userServiceMock := new(UserServiceMock)
+	userServiceMock.On("TransferUser", mock.Anything, "u1", "payments", true).Return(&api.TransferUserResponse{
+		User:             api.User{UserId: "u1", Username: "Alice", TeamName: "payments", TeamId: 2, IsActive: true},
+		PreviousTeamId:   1,
+		PreviousTeamName: "backend",
+		Reassignments:    &[]api.ReviewerMove{{PullRequestId: "pr-1", FromUserId: "u1", ToUserId: "u3"}},
+	}, nil).Once()
+	userServiceMock.On("TransferUser", mock.Anything, "ghost", "payments", false).Return(nil, apperrors.ErrNotFound).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, userServiceMock, nil)
+	router := api.Handler(server)
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/users/transfer", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := serve(`{"user_id": "u1", "team_name": "payments", "reassign_reviews": true}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"user":{"user_id":"u1","username":"Alice","team_name":"payments","team_id":2,"is_active":true},
+		"previous_team_id":1,"previous_team_name":"backend",
+		"reassignments":[{"pull_request_id":"pr-1","from_user_id":"u1","to_user_id":"u3"}]}`, rr.Body.String())
+
+	rr = serve(`{"user_id": "ghost", "team_name": "payments"}`)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = serve(`{"user_id": "u1"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	userServiceMock.AssertExpectations(t)
+}
+
+func TestServer_DeleteUsers(t *testing.T) {
+	userServiceMock := new(UserServiceMock)
+	userServiceMock.On("DeleteUser", mock.Anything, "u1").Return(&api.DeleteUserResponse{
+		User:          api.User{UserId: "u1", Username: "Alice", TeamName: "backend", TeamId: 1},
+		Reassignments: []api.ReviewerMove{{PullRequestId: "pr-1", FromUserId: "u1", ToUserId: "u2"}},
+	}, nil).Once()
+	userServiceMock.On("DeleteUser", mock.Anything, "ghost").Return(nil, apperrors.ErrNotFound).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, userServiceMock, nil)
+	router := api.Handler(server)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/users?user_id=u1", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"user":{"user_id":"u1","username":"Alice","team_name":"backend","team_id":1,"is_active":false},
+		"reassignments":[{"pull_request_id":"pr-1","from_user_id":"u1","to_user_id":"u2"}]}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/users?user_id=ghost", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	userServiceMock.AssertExpectations(t)
+}
+
+func TestServer_PostUsersRestore(t *testing.T) {
+	userServiceMock := new(UserServiceMock)
+	userServiceMock.On("RestoreUser", mock.Anything, "u1").
+		Return(&api.User{UserId: "u1", Username: "Alice", TeamName: "backend", TeamId: 1}, nil).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, userServiceMock, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/users/restore", strings.NewReader(`{"user_id": "u1"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	api.Handler(server).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"user":{"user_id":"u1","username":"Alice","team_name":"backend","team_id":1,"is_active":false}}`, rr.Body.String())
+	userServiceMock.AssertExpectations(t)
+}
+
+func TestServer_PostUsersSetRole(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		requestBody          string
+		setupMocks           func(*UserServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Success",
+			requestBody: `{"user_id": "user1", "role": "lead"}`,
+			setupMocks: func(usm *UserServiceMock) {
+				usm.On("SetRole", mock.Anything, "user1", "lead").
+					Return(&api.SetUserRoleResponse{UserId: "user1", Role: api.UserRoleLead}, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"user_id":"user1","role":"lead"}`,
+		},
+		{
+			name:        "Service Error - Unknown Role",
+			requestBody: `{"user_id": "user1", "role": "owner"}`,
+			setupMocks: func(usm *UserServiceMock) {
+				usm.On("SetRole", mock.Anything, "user1", "owner").
+					Return(nil, fmt.Errorf("%w: unknown role 'owner'", apperrors.ErrValidation)).Once()
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: unknown role 'owner'"}`,
+		},
+		{
+			name:        "Service Error - User Not Found",
+			requestBody: `{"user_id": "not-found", "role": "admin"}`,
+			setupMocks: func(usm *UserServiceMock) {
+				usm.On("SetRole", mock.Anything, "not-found", "admin").Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userServiceMock := new(UserServiceMock)
+			tc.setupMocks(userServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, userServiceMock, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/users/setRole", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			router := api.Handler(server)
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			userServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_PostPullRequestCreate(t *testing.T) {
+	now := time.Now()
+	createdPR := &api.PullRequest{
+		PullRequestId:     "pr-1",
+		PullRequestName:   "New Feature",
+		AuthorId:          "author-1",
+		Status:            api.PullRequestStatusOPEN,
+		AssignedReviewers: []string{"reviewer-1"},
+		CreatedAt:         &now,
+	}
+
+	testCases := []struct {
+		name                 string
+		query                string
+		requestBody          string
+		setupMocks           func(*PullRequestServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Success",
+			requestBody: `{"pull_request_id": "pr-1", "pull_request_name": "New Feature", "author_id": "author-1"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("CreatePR", mock.Anything, "pr-1", "New Feature", "author-1", service.PRDetails{}).
+					Return(createdPR, true, nil).Once()
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponseBody: `{
+				"pr": {
+					"pull_request_id": "pr-1",
+					"pull_request_name": "New Feature",
+					"author_id": "author-1",
+					"status": "OPEN",
+					"assigned_reviewers": ["reviewer-1"],
+					"createdAt": "` + now.Format(time.RFC3339Nano) + `",
+					"mergedAt": null
+				}
+			}`,
+		},
+		{
+			name: "Success - With description and external URL",
+			requestBody: `{"pull_request_id": "pr-2", "pull_request_name": "New Feature", "author_id": "author-1",
+				"description": "Adds a feature", "external_url": "https://github.com/org/repo/pull/2"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				description, url := "Adds a feature", "https://github.com/org/repo/pull/2"
+				prsm.On("CreatePR", mock.Anything, "pr-2", "New Feature", "author-1", service.PRDetails{Description: &description, ExternalURL: &url}).
+					Return(&api.PullRequest{
+						PullRequestId:   "pr-2",
+						PullRequestName: "New Feature",
+						AuthorId:        "author-1",
+						Status:          api.PullRequestStatusOPEN,
+						Description:     &description,
+						ExternalUrl:     &url,
+					}, true, nil).Once()
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponseBody: `{
+				"pr": {
+					"pull_request_id": "pr-2",
+					"pull_request_name": "New Feature",
+					"author_id": "author-1",
+					"description": "Adds a feature",
+					"external_url": "https://github.com/org/repo/pull/2",
+					"status": "OPEN",
+					"assigned_reviewers": null,
+					"createdAt": null,
+					"mergedAt": null
+				}
+			}`,
+		},
+		{
+			name:        "Success - Generated ID",
+			query:       "?generate_id=true",
+			requestBody: `{"pull_request_name": "New Feature", "author_id": "author-1"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("CreatePR", mock.Anything, "", "New Feature", "author-1", service.PRDetails{}).
+					Return(&api.PullRequest{
+						PullRequestId:   "pr-01936b2e-5a1c-7cc4-9f0e-3c2b8a6d4e10",
+						PullRequestName: "New Feature",
+						AuthorId:        "author-1",
+						Status:          api.PullRequestStatusOPEN,
+					}, true, nil).Once()
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponseBody: `{
+				"pr": {
+					"pull_request_id": "pr-01936b2e-5a1c-7cc4-9f0e-3c2b8a6d4e10",
+					"pull_request_name": "New Feature",
+					"author_id": "author-1",
+					"status": "OPEN",
+					"assigned_reviewers": null,
+					"createdAt": null,
+					"mergedAt": null
+				}
+			}`,
+		},
+		{
+			name:                 "Validation Error - ID supplied with generate_id",
+			query:                "?generate_id=true",
+			requestBody:          `{"pull_request_id": "pr-1", "pull_request_name": "New Feature", "author_id": "author-1"}`,
+			setupMocks:           func(prsm *PullRequestServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: field 'PullRequestID' must be omitted"}`,
+		},
+		{
+			name:               "Validation Error - Missing ID without generate_id",
+			query:              "?generate_id=false",
+			requestBody:        `{"pull_request_name": "New Feature", "author_id": "author-1"}`,
+			setupMocks:         func(prsm *PullRequestServiceMock) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Validation Error - Invalid external URL",
+			requestBody:        `{"pull_request_id": "pr-2", "pull_request_name": "New Feature", "author_id": "author-1", "external_url": "not a url"}`,
+			setupMocks:         func(prsm *PullRequestServiceMock) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "Service Error - Author Not Found",
+			requestBody: `{"pull_request_id": "pr-1", "pull_request_name": "New Feature", "author_id": "author-not-found"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("CreatePR", mock.Anything, "pr-1", "New Feature", "author-not-found", service.PRDetails{}).
+					Return(nil, false, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+		{
+			name:        "Service Error - PR Already Exists",
+			requestBody: `{"pull_request_id": "pr-exists", "pull_request_name": "New Feature", "author_id": "author-1"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("CreatePR", mock.Anything, "pr-exists", "New Feature", "author-1", service.PRDetails{}).
+					Return(nil, false, &apperrors.PRAlreadyExistsError{PRID: "pr-exists"}).Once()
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"PR_EXISTS","message":"pull request with this id already exists"}}`,
+		},
+		{
+			name:        "Author Quota Exceeded",
+			requestBody: `{"pull_request_id": "pr-1", "pull_request_name": "New Feature", "author_id": "author-1"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("CreatePR", mock.Anything, "pr-1", "New Feature", "author-1", service.PRDetails{}).
+					Return(nil, false, &apperrors.AuthorQuotaExceededError{AuthorID: "author-1", Limit: 3}).Once()
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"AUTHOR_QUOTA_EXCEEDED",
+				"message":"author 'author-1' already has 3 open pull requests, the limit of the team"}}`,
+		},
+		{
+			name:        "Strict Mode - Author Inactive",
+			requestBody: `{"pull_request_id": "pr-1", "pull_request_name": "New Feature", "author_id": "author-1"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("CreatePR", mock.Anything, "pr-1", "New Feature", "author-1", service.PRDetails{}).
+					Return(nil, false, &apperrors.AuthorInactiveError{AuthorID: "author-1"}).Once()
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"AUTHOR_INACTIVE","message":"author 'author-1' is inactive"}}`,
+		},
+		{
+			name:        "Strict Mode - Team Too Small",
+			requestBody: `{"pull_request_id": "pr-1", "pull_request_name": "New Feature", "author_id": "author-1"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("CreatePR", mock.Anything, "pr-1", "New Feature", "author-1", service.PRDetails{}).
+					Return(nil, false, &apperrors.TeamTooSmallError{AuthorID: "author-1", Required: 2, Available: 1}).Once()
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"TEAM_TOO_SMALL",
+				"message":"team of author 'author-1' has 1 active reviewers available, 2 required"}}`,
+		},
+		{
+			name:        "Duplicate Request - Existing PR Returned",
+			requestBody: `{"pull_request_id": "pr-1", "pull_request_name": "New Feature", "author_id": "author-1"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("CreatePR", mock.Anything, "pr-1", "New Feature", "author-1", service.PRDetails{}).
+					Return(createdPR, false, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{
+				"pr": {
+					"pull_request_id": "pr-1",
+					"pull_request_name": "New Feature",
+					"author_id": "author-1",
+					"status": "OPEN",
+					"assigned_reviewers": ["reviewer-1"],
+					"createdAt": "` + now.Format(time.RFC3339Nano) + `",
+					"mergedAt": null
+				}
+			}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prServiceMock := new(PullRequestServiceMock)
+			tc.setupMocks(prServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/create"+tc.query, strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			router := api.Handler(server)
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			if tc.expectedResponseBody != "" {
+				require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			}
+			prServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetPullRequestGet(t *testing.T) {
+	assignedAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+	newPR := func() *api.PullRequest {
+		return &api.PullRequest{
+			PullRequestId:     "pr-1",
+			PullRequestName:   "New Feature",
+			AuthorId:          "author-1",
+			Status:            api.PullRequestStatusOPEN,
+			AssignedReviewers: []string{"reviewer-1"},
+		}
+	}
+
+	testCases := []struct {
+		name                 string
+		query                string
+		setupMocks           func(*PullRequestServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Success",
+			query: "pull_request_id=pr-1",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("GetPR", mock.Anything, "pr-1").Return(newPR(), nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"pr":{"pull_request_id":"pr-1","pull_request_name":"New Feature","author_id":"author-1",
+				"status":"OPEN","assigned_reviewers":["reviewer-1"],"createdAt":null,"mergedAt":null}}`,
+		},
+		{
+			name:  "Success - Expanded Reviewers",
+			query: "pull_request_id=pr-1&expand=reviewers",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("GetPR", mock.Anything, "pr-1").Return(newPR(), nil).Once()
+				prsm.On("ExpandReviewers", mock.Anything, mock.AnythingOfType("*api.PullRequest")).
+					Run(func(args mock.Arguments) {
+						reason := api.Random
+						pr := args.Get(1).(*api.PullRequest)
+						pr.Reviewers = &[]api.ReviewerAssignment{{UserId: "reviewer-1", Reason: &reason, AssignedAt: &assignedAt}}
+					}).Return(nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"pr":{"pull_request_id":"pr-1","pull_request_name":"New Feature","author_id":"author-1",
+				"status":"OPEN","assigned_reviewers":["reviewer-1"],"createdAt":null,"mergedAt":null,
+				"reviewers":[{"user_id":"reviewer-1","reason":"random","assigned_at":"2025-11-01T12:00:00Z"}]}}`,
+		},
+		{
+			name:  "Service Error - Not Found",
+			query: "pull_request_id=missing&expand=reviewers",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("GetPR", mock.Anything, "missing").Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prServiceMock := new(PullRequestServiceMock)
+			tc.setupMocks(prServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+
+			req := httptest.NewRequest(http.MethodGet, "/pullRequest/get?"+tc.query, nil)
+			rr := httptest.NewRecorder()
+
+			router := api.Handler(server)
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			prServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetPullRequestHistory(t *testing.T) {
+	assignedAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name                 string
+		query                string
+		setupMocks           func(*PullRequestServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Success",
+			query: "pull_request_id=pr-1",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				created, deactivated, replaced := api.CauseCreated, api.CauseTeamDeactivated, "reviewer-1"
+				prsm.On("GetAssignmentHistory", mock.Anything, "pr-1").Return(&api.AssignmentHistoryResponse{
+					PullRequestId: "pr-1",
+					Items: []api.AssignmentHistoryEntry{
+						{UserId: "reviewer-1", Reason: api.HistoryReasonRandom, Cause: &created, AssignedAt: assignedAt},
+						{UserId: "reviewer-2", ReplacedUserId: &replaced, Reason: api.HistoryReasonRandom, Cause: &deactivated, AssignedAt: assignedAt.Add(time.Hour)},
+					},
+				}, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"pull_request_id":"pr-1","next_cursor":null,"items":[
+				{"user_id":"reviewer-1","reason":"random","cause":"created","assigned_at":"2025-11-01T12:00:00Z"},
+				{"user_id":"reviewer-2","replaced_user_id":"reviewer-1","reason":"random","cause":"team_deactivated","assigned_at":"2025-11-01T13:00:00Z"}]}`,
+		},
+		{
+			name:  "Service Error - Not Found",
+			query: "pull_request_id=missing",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("GetAssignmentHistory", mock.Anything, "missing").Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prServiceMock := new(PullRequestServiceMock)
+			tc.setupMocks(prServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+
+			req := httptest.NewRequest(http.MethodGet, "/pullRequest/history?"+tc.query, nil)
+			rr := httptest.NewRecorder()
+
+			router := api.Handler(server)
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			prServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetStatsLeaderboard(t *testing.T) {
+	leaderboard := &api.LeaderboardResponse{
+		Period: api.LeaderboardWeek,
+		From:   time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC),
+		Items:  []api.LeaderboardEntry{{Rank: 1, UserId: "u2", Username: "Bob", MergedReviews: 7}},
+	}
+
+	testCases := []struct {
+		name                 string
+		query                string
+		setupMocks           func(*PullRequestServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Success",
+			query: "period=week",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("GetLeaderboard", mock.Anything, domain.LeaderboardWeek, defaultLeaderboardLimit).Return(leaderboard, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"period":"week","from":"2025-11-03T00:00:00Z","to":"2025-11-10T00:00:00Z","next_cursor":null,
+				"items":[{"rank":1,"user_id":"u2","username":"Bob","merged_reviews":7}]}`,
+		},
+		{
+			name:  "Success - Limit",
+			query: "period=month&limit=5",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("GetLeaderboard", mock.Anything, domain.LeaderboardMonth, 5).Return(leaderboard, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"period":"week","from":"2025-11-03T00:00:00Z","to":"2025-11-10T00:00:00Z","next_cursor":null,
+				"items":[{"rank":1,"user_id":"u2","username":"Bob","merged_reviews":7}]}`,
+		},
+		{
+			name:  "Validation Error - Unknown Period",
+			query: "period=year",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("GetLeaderboard", mock.Anything, domain.LeaderboardPeriod("year"), defaultLeaderboardLimit).
+					Return(nil, fmt.Errorf("%w: unknown period 'year'", apperrors.ErrValidation)).Once()
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: unknown period 'year'"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prServiceMock := new(PullRequestServiceMock)
+			tc.setupMocks(prServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+
+			req := httptest.NewRequest(http.MethodGet, "/stats/leaderboard?"+tc.query, nil)
+			rr := httptest.NewRecorder()
+
+			router := api.Handler(server)
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			prServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_WithPRQueries(t *testing.T) {
+	primary := new(PullRequestServiceMock)
+	replica := new(PullRequestServiceMock)
+	replica.On("GetPR", mock.Anything, "pr-1").Return(&api.PullRequest{
+		PullRequestId:     "pr-1",
+		PullRequestName:   "New Feature",
+		AuthorId:          "author-1",
+		Status:            api.PullRequestStatusOPEN,
+		AssignedReviewers: []string{"reviewer-1"},
+	}, nil).Once()
+	primary.On("MergePR", mock.Anything, "pr-1", service.MergeOptions{}).Return(&api.MergeResponse{}, nil).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, primary, WithPRQueries(replica))
+	router := api.Handler(server)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/pullRequest/get?pull_request_id=pr-1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", strings.NewReader(`{"pull_request_id": "pr-1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	primary.AssertExpectations(t)
+	replica.AssertExpectations(t)
+}
+
+func TestServer_PostPullRequestMerge(t *testing.T) {
+	now := time.Now()
+	mergeResp := &api.MergeResponse{
+		Pr: api.PullRequest{
+			PullRequestId: "pr-1",
+			Status:        api.PullRequestStatusMERGED,
+			MergedAt:      &now,
+		},
+		ReviewerStats: []api.UserStats{{UserId: "u2", Username: "Bob", OpenReviews: 1, MergedReviews: 7}},
+	}
+
+	testCases := []struct {
+		name                 string
+		requestBody          string
+		setupMocks           func(*PullRequestServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Success",
+			requestBody: `{"pull_request_id": "pr-1"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("MergePR", mock.Anything, "pr-1", service.MergeOptions{}).Return(mergeResp, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{
+				"pr": {
+					"pull_request_id": "pr-1", "status": "MERGED", "mergedAt": "` + now.Format(time.RFC3339Nano) + `",
+					"pull_request_name": "", "author_id": "", "assigned_reviewers": null, "createdAt": null
+				},
+				"reviewer_stats": [{"user_id": "u2", "username": "Bob", "open_reviews": 1, "merged_reviews": 7}]
+			}`,
+		},
+		{
+			name:        "Service Error - PR Not Found",
+			requestBody: `{"pull_request_id": "not-found"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("MergePR", mock.Anything, "not-found", service.MergeOptions{}).Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+		{
+			name:        "Service Error - PR Closed",
+			requestBody: `{"pull_request_id": "pr-1"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("MergePR", mock.Anything, "pr-1", service.MergeOptions{}).Return(nil, apperrors.ErrPRClosed).Once()
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"PR_CLOSED","message":"cannot modify closed pull request"}}`,
+		},
+		{
+			name:        "Service Error - Not Approved",
+			requestBody: `{"pull_request_id": "pr-1"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("MergePR", mock.Anything, "pr-1", service.MergeOptions{}).
+					Return(nil, &apperrors.ApprovalRequiredError{PRID: "pr-1", ReviewerIDs: []string{"u2", "u3"}}).Once()
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"NOT_APPROVED","message":"pull request 'pr-1' is waiting for the approval of: u2, u3"}}`,
+		},
+		{
+			name:        "Service Error - Merges Frozen",
+			requestBody: `{"pull_request_id": "pr-1"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("MergePR", mock.Anything, "pr-1", service.MergeOptions{}).Return(nil, &apperrors.MergeFrozenError{
+					PRID: "pr-1", Reason: "release freeze", Until: time.Date(2025, 12, 29, 9, 0, 0, 0, time.UTC),
+				}).Once()
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"FREEZE","message":"merges are frozen until 2025-12-29T09:00:00Z: release freeze"}}`,
+		},
+		{
+			name:        "Freeze overridden",
+			requestBody: `{"pull_request_id": "pr-1", "override_freeze": true}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("MergePR", mock.Anything, "pr-1", service.MergeOptions{OverrideFreeze: true}).
+					Return(&api.MergeResponse{Pr: api.PullRequest{PullRequestId: "pr-1", Status: api.PullRequestStatusMERGED}}, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{
+				"pr": {
+					"pull_request_id": "pr-1", "status": "MERGED", "pull_request_name": "", "author_id": "",
+					"assigned_reviewers": null, "createdAt": null, "mergedAt": null
+				},
+				"reviewer_stats": null
+			}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prServiceMock := new(PullRequestServiceMock)
+			tc.setupMocks(prServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			router := api.Handler(server)
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			prServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_PostPullRequestClose(t *testing.T) {
+	closedPR := &api.PullRequest{
+		PullRequestId:     "pr-1",
+		PullRequestName:   "Abandoned",
+		AuthorId:          "u1",
+		Status:            api.PullRequestStatusCLOSED,
+		AssignedReviewers: []string{"u2"},
+	}
+
+	testCases := []struct {
+		name                 string
+		requestBody          string
+		setupMocks           func(*PullRequestServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Success",
+			requestBody: `{"pull_request_id": "pr-1"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("ClosePR", mock.Anything, "pr-1").Return(closedPR, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"pr": {
+				"pull_request_id": "pr-1", "pull_request_name": "Abandoned", "author_id": "u1", "status": "CLOSED",
+				"assigned_reviewers": ["u2"], "createdAt": null, "mergedAt": null
+			}}`,
+		},
+		{
+			name:        "Service Error - PR Merged",
+			requestBody: `{"pull_request_id": "pr-1"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("ClosePR", mock.Anything, "pr-1").Return(nil, apperrors.ErrPRMerged).Once()
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"PR_MERGED","message":"cannot modify merged pull request"}}`,
+		},
+		{
+			name:        "Service Error - PR Not Found",
+			requestBody: `{"pull_request_id": "not-found"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("ClosePR", mock.Anything, "not-found").Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+		{
+			name:                 "Validation Error - Missing ID",
+			requestBody:          `{}`,
+			setupMocks:           func(prsm *PullRequestServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: field 'PullRequestID' failed on the 'required' tag"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prServiceMock := new(PullRequestServiceMock)
+			tc.setupMocks(prServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/close", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			router := api.Handler(server)
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			prServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_PostPullRequestReview(t *testing.T) {
+	reviewedAt := time.Date(2025, 11, 1, 15, 0, 0, 0, time.UTC)
+	reviewedPR := func(state api.ReviewState) *api.PullRequest {
+		return &api.PullRequest{
+			PullRequestId:     "pr-1",
+			PullRequestName:   "Add search",
+			AuthorId:          "u1",
+			Status:            api.PullRequestStatusOPEN,
+			AssignedReviewers: []string{"u2", "u3"},
+			Reviews: &[]api.Review{
+				{UserId: "u2", State: state, ReviewedAt: &reviewedAt},
+				{UserId: "u3", State: api.PENDING},
+			},
+		}
+	}
+	reviewedJSON := func(state string) string {
+		return `{"pr": {
+			"pull_request_id": "pr-1", "pull_request_name": "Add search", "author_id": "u1", "status": "OPEN",
+			"assigned_reviewers": ["u2", "u3"], "createdAt": null, "mergedAt": null,
+			"reviews": [
+				{"user_id": "u2", "state": "` + state + `", "reviewed_at": "2025-11-01T15:00:00Z"},
+				{"user_id": "u3", "state": "PENDING", "reviewed_at": null}
+			]
+		}}`
+	}
+
+	testCases := []struct {
+		name                 string
+		path                 string
+		requestBody          string
+		setupMocks           func(*PullRequestServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Approve",
+			path:        "/pullRequest/approve",
+			requestBody: `{"pull_request_id": "pr-1", "reviewer_id": "u2"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("ApprovePR", mock.Anything, "pr-1", "u2").Return(reviewedPR(api.APPROVED), nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: reviewedJSON("APPROVED"),
+		},
+		{
+			name:        "Request changes",
+			path:        "/pullRequest/requestChanges",
+			requestBody: `{"pull_request_id": "pr-1", "reviewer_id": "u2"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("RequestChanges", mock.Anything, "pr-1", "u2").Return(reviewedPR(api.CHANGESREQUESTED), nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: reviewedJSON("CHANGES_REQUESTED"),
+		},
+		{
+			name:        "Service Error - Not Assigned",
+			path:        "/pullRequest/approve",
+			requestBody: `{"pull_request_id": "pr-1", "reviewer_id": "u1"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("ApprovePR", mock.Anything, "pr-1", "u1").Return(nil, apperrors.ErrReviewerNotAssigned).Once()
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"NOT_ASSIGNED","message":"reviewer is not assigned to this PR"}}`,
+		},
+		{
+			name:        "Service Error - PR Merged",
+			path:        "/pullRequest/requestChanges",
+			requestBody: `{"pull_request_id": "pr-1", "reviewer_id": "u2"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("RequestChanges", mock.Anything, "pr-1", "u2").Return(nil, apperrors.ErrPRMerged).Once()
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"PR_MERGED","message":"cannot modify merged pull request"}}`,
+		},
+		{
+			name:                 "Validation Error - Missing Reviewer",
+			path:                 "/pullRequest/approve",
+			requestBody:          `{"pull_request_id": "pr-1"}`,
+			setupMocks:           func(prsm *PullRequestServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: field 'ReviewerID' failed on the 'required' tag"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prServiceMock := new(PullRequestServiceMock)
+			tc.setupMocks(prServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			router := api.Handler(server)
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			prServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_PostPullRequestSubscribe(t *testing.T) {
+	sub := &api.PullRequestSubscription{
+		PullRequestId: "pr-1",
+		UserId:        "u7",
+		CreatedAt:     time.Date(2025, 11, 1, 9, 0, 0, 0, time.UTC),
+	}
+	subJSON := `{"subscription": {"pull_request_id": "pr-1", "user_id": "u7", "created_at": "2025-11-01T09:00:00Z"}}`
+
+	testCases := []struct {
+		name                 string
+		requestBody          string
+		setupMocks           func(*PullRequestServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Success - New subscription",
+			requestBody: `{"pull_request_id": "pr-1", "user_id": "u7"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("SubscribePR", mock.Anything, "pr-1", "u7").Return(sub, true, nil).Once()
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: subJSON,
+		},
+		{
+			name:        "Success - Already subscribed",
+			requestBody: `{"pull_request_id": "pr-1", "user_id": "u7"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("SubscribePR", mock.Anything, "pr-1", "u7").Return(sub, false, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: subJSON,
+		},
+		{
+			name:        "Service Error - Not Found",
+			requestBody: `{"pull_request_id": "pr-1", "user_id": "ghost"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("SubscribePR", mock.Anything, "pr-1", "ghost").Return(nil, false, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+		{
+			name:                 "Validation Error - Missing User",
+			requestBody:          `{"pull_request_id": "pr-1"}`,
+			setupMocks:           func(prsm *PullRequestServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: field 'UserID' failed on the 'required' tag"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prServiceMock := new(PullRequestServiceMock)
+			tc.setupMocks(prServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/subscribe", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			router := api.Handler(server)
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			prServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_PostPullRequestReassign(t *testing.T) {
+	reassignedResponse := &api.ReassignResponse{
+		Pr: api.PullRequest{
+			PullRequestId:     "pr-123",
+			AssignedReviewers: []string{"new-reviewer"},
+		},
+		ReplacedBy: "new-reviewer",
+	}
+
+	testCases := []struct {
+		name                 string
+		requestBody          string
+		setupMocks           func(*PullRequestServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Success",
+			requestBody: `{"pull_request_id": "pr-123", "old_user_id": "old-reviewer"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("ReassignReviewer", mock.Anything, "pr-123", "old-reviewer", "").
+					Return(reassignedResponse, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"pr":{"pull_request_id":"pr-123","pull_request_name":"","author_id":"","status":"","assigned_reviewers":["new-reviewer"],"createdAt":null,"mergedAt":null},"replaced_by":"new-reviewer"}`,
+		},
+		{
+			name:        "Success with handoff note",
+			requestBody: `{"pull_request_id": "pr-123", "old_user_id": "old-reviewer", "note": "handlers left"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("ReassignReviewer", mock.Anything, "pr-123", "old-reviewer", "handlers left").
+					Return(reassignedResponse, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"pr":{"pull_request_id":"pr-123","pull_request_name":"","author_id":"","status":"","assigned_reviewers":["new-reviewer"],"createdAt":null,"mergedAt":null},"replaced_by":"new-reviewer"}`,
+		},
+		{
+			name:                 "Validation Error - Note too long",
+			requestBody:          `{"pull_request_id": "pr-123", "old_user_id": "old-reviewer", "note": "` + strings.Repeat("a", 1001) + `"}`,
+			setupMocks:           func(prsm *PullRequestServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: field 'Note' failed on the 'max' tag"}`,
+		},
+		{
+			name:        "Service Error - PR Merged",
+			requestBody: `{"pull_request_id": "pr-123", "old_user_id": "old-reviewer"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("ReassignReviewer", mock.Anything, "pr-123", "old-reviewer", "").
+					Return(nil, apperrors.ErrPRMerged).Once()
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"PR_MERGED","message":"cannot modify merged pull request"}}`,
 		},
 		{
 			name:        "Service Error - Not Assigned",
 			requestBody: `{"pull_request_id": "pr-123", "old_user_id": "not-a-reviewer"}`,
 			setupMocks: func(prsm *PullRequestServiceMock) {
-				prsm.On("ReassignReviewer", mock.Anything, "pr-123", "not-a-reviewer").
-					Return(nil, apperrors.ErrReviewerNotAssigned).Once()
+				prsm.On("ReassignReviewer", mock.Anything, "pr-123", "not-a-reviewer", "").
+					Return(nil, apperrors.ErrReviewerNotAssigned).Once()
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"NOT_ASSIGNED","message":"reviewer is not assigned to this PR"}}`,
+		},
+		{
+			name:        "Service Error - No Candidate",
+			requestBody: `{"pull_request_id": "pr-123", "old_user_id": "old-reviewer"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("ReassignReviewer", mock.Anything, "pr-123", "old-reviewer", "").
+					Return(nil, apperrors.ErrNoCandidate).Once()
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"NO_CANDIDATE","message":"no active replacement candidate found in team"}}`,
+		},
+		{
+			name:        "Service Error - No Candidate with alternatives",
+			requestBody: `{"pull_request_id": "pr-123", "old_user_id": "old-reviewer"}`,
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("ReassignReviewer", mock.Anything, "pr-123", "old-reviewer", "").
+					Return(nil, fmt.Errorf("wrapped: %w", &apperrors.NoCandidateError{
+						PRID: "pr-123",
+						Alternatives: []domain.ReplacementAlternative{
+							{UserID: "u4", Username: "Dave", TeamID: 1, TeamName: "backend", Reason: domain.AlternativeInactive},
+							{UserID: "u7", Username: "Grace", TeamID: 2, TeamName: "frontend", Reason: domain.AlternativeOtherTeam, OpenReviews: 1},
+						},
+					})).Once()
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"NO_CANDIDATE","message":"no active replacement candidate found in team","alternatives":[` +
+				`{"user_id":"u4","username":"Dave","team_name":"backend","team_id":1,"reason":"inactive","open_reviews":0},` +
+				`{"user_id":"u7","username":"Grace","team_name":"frontend","team_id":2,"reason":"other_team","open_reviews":1}]}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prServiceMock := new(PullRequestServiceMock)
+			tc.setupMocks(prServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			router := api.Handler(server)
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			prServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetUsersGetReview(t *testing.T) {
+	assigned := []api.PullRequestShort{{PullRequestId: "pr-1", PullRequestName: "Feature A", AuthorId: "author-A", Status: "OPEN"}}
+	total := 1
+	reviewResponse := &api.GetReviewResponse{UserId: "user-1", Items: assigned, PullRequests: assigned, TotalEstimate: &total}
+
+	testCases := []struct {
+		name                 string
+		targetURL            string
+		setupMocks           func(*PullRequestServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Success",
+			targetURL: "/users/getReview?user_id=user-1",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("GetReviewAssignments", mock.Anything, "user-1", []string(nil)).Return(reviewResponse, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"user_id":"user-1","items":[{"pull_request_id":"pr-1","pull_request_name":"Feature A","author_id":"author-A","status":"OPEN"}],
+				"pull_requests":[{"pull_request_id":"pr-1","pull_request_name":"Feature A","author_id":"author-A","status":"OPEN"}],
+				"next_cursor":null,"total_estimate":1}`,
+		},
+		{
+			name:      "User Not Found",
+			targetURL: "/users/getReview?user_id=not-found",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("GetReviewAssignments", mock.Anything, "not-found", []string(nil)).Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prServiceMock := new(PullRequestServiceMock)
+			tc.setupMocks(prServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+
+			router := api.Handler(server)
+			req := httptest.NewRequest(http.MethodGet, tc.targetURL, nil)
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			prServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetPullRequestSearch(t *testing.T) {
+	found := []api.PullRequestShort{{PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1", Status: "OPEN"}}
+	total := 1
+	searchResponse := &api.SearchPullRequestsResponse{Items: found, PullRequests: found, Total: total, TotalEstimate: &total}
+	nextCursor := "MTU"
+
+	testCases := []struct {
+		name                 string
+		targetURL            string
+		setupMocks           func(*PullRequestServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Success with defaults",
+			targetURL: "/pullRequest/search?query=search",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("SearchPRs", mock.Anything, service.PRSearchQuery{Query: "search", Limit: defaultSearchLimit}).
+					Return(searchResponse, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"items":[{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1","status":"OPEN"}],
+				"pull_requests":[{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1","status":"OPEN"}],
+				"next_cursor":null,"total_estimate":1,"total":1}`,
+		},
+		{
+			name:      "Success with filters",
+			targetURL: "/pullRequest/search?query=add+search&status=OPEN&limit=5&offset=10",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("SearchPRs", mock.Anything, service.PRSearchQuery{Query: "add search", Status: "OPEN", Limit: 5, Offset: 10}).
+					Return(&api.SearchPullRequestsResponse{Items: []api.PullRequestShort{}, PullRequests: []api.PullRequestShort{}, Total: 1}, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"items":[],"pull_requests":[],"next_cursor":null,"total":1}`,
+		},
+		{
+			name:      "Success with cursor",
+			targetURL: "/pullRequest/search?query=search&limit=5&cursor=MTA",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("SearchPRs", mock.Anything, service.PRSearchQuery{Query: "search", Cursor: "MTA", Limit: 5}).
+					Return(&api.SearchPullRequestsResponse{
+						Items: []api.PullRequestShort{}, PullRequests: []api.PullRequestShort{}, Total: 20, NextCursor: &nextCursor,
+					}, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"items":[],"pull_requests":[],"next_cursor":"MTU","total":20}`,
+		},
+		{
+			name:      "Success with custom fields",
+			targetURL: "/pullRequest/search?query=search&custom_field=risk:high&custom_field=ticket:ABC-1",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("SearchPRs", mock.Anything, service.PRSearchQuery{
+					Query: "search", CustomFields: []string{"risk:high", "ticket:ABC-1"}, Limit: defaultSearchLimit,
+				}).Return(&api.SearchPullRequestsResponse{Items: []api.PullRequestShort{}, PullRequests: []api.PullRequestShort{}}, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"items":[],"pull_requests":[],"next_cursor":null,"total":0}`,
+		},
+		{
+			name:      "Validation error",
+			targetURL: "/pullRequest/search?query=search&limit=500",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("SearchPRs", mock.Anything, service.PRSearchQuery{Query: "search", Limit: 500}).
+					Return(nil, fmt.Errorf("%w: limit must be between 1 and 100", apperrors.ErrValidation)).Once()
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: limit must be between 1 and 100"}`,
+		},
+		{
+			name:                 "Missing query",
+			targetURL:            "/pullRequest/search",
+			setupMocks:           func(prsm *PullRequestServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prServiceMock := new(PullRequestServiceMock)
+			tc.setupMocks(prServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+
+			router := api.Handler(server)
+			req := httptest.NewRequest(http.MethodGet, tc.targetURL, nil)
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			if tc.expectedResponseBody != "" {
+				assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			}
+			prServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetPullRequestList(t *testing.T) {
+	teamID := 3
+	createdAt := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	cursor := "next-page"
+	prs := []api.PullRequest{{
+		PullRequestId:     "pr-1",
+		PullRequestName:   "Feature",
+		AuthorId:          "u1",
+		Status:            api.PullRequestStatusOPEN,
+		AssignedReviewers: []string{"u2"},
+		CreatedAt:         &createdAt,
+	}}
+	page := &api.ListPullRequestsResponse{Items: prs, PullRequests: prs, NextCursor: &cursor}
+
+	testCases := []struct {
+		name                 string
+		query                string
+		setupMocks           func(*TeamServiceMock, *PullRequestServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Success - Defaults",
+			query: "",
+			setupMocks: func(tsm *TeamServiceMock, prsm *PullRequestServiceMock) {
+				prsm.On("ListPRs", mock.Anything, service.PRListQuery{Limit: defaultListLimit}).
+					Return(&api.ListPullRequestsResponse{Items: []api.PullRequest{}, PullRequests: []api.PullRequest{}}, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"items":[],"pull_requests":[],"next_cursor":null}`,
+		},
+		{
+			name:  "Success - Filters",
+			query: "?status=OPEN&author_id=u1&team_name=backend&created_from=2025-03-01T00:00:00Z&limit=1&cursor=prev-page",
+			setupMocks: func(tsm *TeamServiceMock, prsm *PullRequestServiceMock) {
+				tsm.On("GetTeam", mock.Anything, "backend").Return(&api.Team{TeamName: "backend", TeamId: &teamID}, nil).Once()
+				prsm.On("ListPRs", mock.Anything, service.PRListQuery{
+					Status: "OPEN", AuthorID: "u1", TeamID: teamID, CreatedFrom: &from, Cursor: "prev-page", Limit: 1,
+				}).Return(page, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"items":[{"pull_request_id":"pr-1","pull_request_name":"Feature","author_id":"u1",
+				"status":"OPEN","assigned_reviewers":["u2"],"createdAt":"2025-03-14T12:00:00Z","mergedAt":null}],
+				"pull_requests":[{"pull_request_id":"pr-1","pull_request_name":"Feature","author_id":"u1",
+				"status":"OPEN","assigned_reviewers":["u2"],"createdAt":"2025-03-14T12:00:00Z","mergedAt":null}],"next_cursor":"next-page"}`,
+		},
+		{
+			name:  "Success - Selected Fields",
+			query: "?team_id=3&fields=pull_request_id,createdAt",
+			setupMocks: func(tsm *TeamServiceMock, prsm *PullRequestServiceMock) {
+				tsm.On("GetTeamByID", mock.Anything, teamID).Return(&api.Team{TeamName: "backend", TeamId: &teamID}, nil).Once()
+				prsm.On("ListPRs", mock.Anything, service.PRListQuery{TeamID: teamID, Limit: defaultListLimit}).Return(page, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"items":[{"pull_request_id":"pr-1","createdAt":"2025-03-14T12:00:00Z"}],
+				"pull_requests":[{"pull_request_id":"pr-1","createdAt":"2025-03-14T12:00:00Z"}],"next_cursor":"next-page"}`,
+		},
+		{
+			name:  "Service Error - Team Not Found",
+			query: "?team_id=9",
+			setupMocks: func(tsm *TeamServiceMock, prsm *PullRequestServiceMock) {
+				tsm.On("GetTeamByID", mock.Anything, 9).Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+		{
+			name:  "Service Error - Cursor With Offset",
+			query: "?cursor=next-page&offset=20",
+			setupMocks: func(tsm *TeamServiceMock, prsm *PullRequestServiceMock) {
+				prsm.On("ListPRs", mock.Anything, service.PRListQuery{Cursor: "next-page", Limit: defaultListLimit, Offset: 20}).
+					Return(nil, fmt.Errorf("%w: cursor and offset are mutually exclusive", apperrors.ErrValidation)).Once()
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: cursor and offset are mutually exclusive"}`,
+		},
+		{
+			name:                 "Validation Error - Unknown Field",
+			query:                "?fields=pull_request_id,title",
+			setupMocks:           func(tsm *TeamServiceMock, prsm *PullRequestServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: unknown field 'title'"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			teamServiceMock := new(TeamServiceMock)
+			prServiceMock := new(PullRequestServiceMock)
+			tc.setupMocks(teamServiceMock, prServiceMock)
+
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamServiceMock, nil, prServiceMock)
+			router := api.Handler(server)
+			req := httptest.NewRequest(http.MethodGet, "/pullRequest/list"+tc.query, nil)
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			teamServiceMock.AssertExpectations(t)
+			prServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetStats(t *testing.T) {
+	toMerge := &api.DurationStats{Count: 5, AvgSeconds: 7200, P50Seconds: 3600, P90Seconds: 14400}
+	userStats := []api.UserStats{{UserId: "u1", Username: "Alice", OpenReviews: 1, MergedReviews: 5, TimeToMerge: toMerge}}
+	teamStats := []api.TeamStats{{TeamName: "backend", TimeToMerge: toMerge}}
+	total := 1
+	expectedStats := &api.StatsResponse{Items: userStats, UserStats: userStats, TeamStats: teamStats, TotalEstimate: &total}
+	timeToMerge := `"time_to_merge":{"count":5,"avg_seconds":7200,"p50_seconds":3600,"p90_seconds":14400}`
+
+	testCases := []struct {
+		name                 string
+		query                string
+		setupMocks           func(*PullRequestServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Success",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("GetStats", mock.Anything, domain.StatsPeriod{}).Return(expectedStats, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"items":[{"user_id":"u1","username":"Alice","open_reviews":1,"merged_reviews":5,` + timeToMerge + `}],
+				"user_stats":[{"user_id":"u1","username":"Alice","open_reviews":1,"merged_reviews":5,` + timeToMerge + `}],
+				"team_stats":[{"team_name":"backend",` + timeToMerge + `}],"next_cursor":null,"total_estimate":1}`,
+		},
+		{
+			name:  "Success - Selected Fields",
+			query: "?fields=user_id,open_reviews",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("GetStats", mock.Anything, domain.StatsPeriod{}).Return(expectedStats, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"items":[{"user_id":"u1","open_reviews":1}],"user_stats":[{"user_id":"u1","open_reviews":1}],
+				"team_stats":[{"team_name":"backend",` + timeToMerge + `}],"next_cursor":null,"total_estimate":1}`,
+		},
+		{
+			name:  "Success - Selected Duration Fields",
+			query: "?fields=user_id,time_to_merge.p50_seconds,time_to_first_review",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("GetStats", mock.Anything, domain.StatsPeriod{}).Return(expectedStats, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"items":[{"user_id":"u1","time_to_merge":{"p50_seconds":3600}}],
+				"user_stats":[{"user_id":"u1","time_to_merge":{"p50_seconds":3600}}],
+				"team_stats":[{"team_name":"backend",` + timeToMerge + `}],"next_cursor":null,"total_estimate":1}`,
+		},
+		{
+			name:  "Success - Period",
+			query: "?from=2025-11-03T00:00:00Z&to=2025-11-17T00:00:00Z",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				from := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+				to := time.Date(2025, 11, 17, 0, 0, 0, 0, time.UTC)
+				prsm.On("GetStats", mock.Anything, domain.StatsPeriod{From: &from, To: &to}).Return(expectedStats, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"items":[{"user_id":"u1","username":"Alice","open_reviews":1,"merged_reviews":5,` + timeToMerge + `}],
+				"user_stats":[{"user_id":"u1","username":"Alice","open_reviews":1,"merged_reviews":5,` + timeToMerge + `}],
+				"team_stats":[{"team_name":"backend",` + timeToMerge + `}],"next_cursor":null,"total_estimate":1}`,
+		},
+		{
+			name: "Success - Precomputed",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				refreshedAt := time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC)
+				precomputed := *expectedStats
+				precomputed.RefreshedAt = &refreshedAt
+				prsm.On("GetStats", mock.Anything, domain.StatsPeriod{}).Return(&precomputed, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"items":[{"user_id":"u1","username":"Alice","open_reviews":1,"merged_reviews":5,` + timeToMerge + `}],
+				"user_stats":[{"user_id":"u1","username":"Alice","open_reviews":1,"merged_reviews":5,` + timeToMerge + `}],
+				"team_stats":[{"team_name":"backend",` + timeToMerge + `}],"next_cursor":null,"total_estimate":1,
+				"refreshed_at":"2025-11-03T10:00:00Z"}`,
+		},
+		{
+			name:                 "Validation Error - Empty Field",
+			query:                "?fields=user_id,,open_reviews",
+			setupMocks:           func(prsm *PullRequestServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: malformed field ''"}`,
+		},
+		{
+			name: "Service Error",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("GetStats", mock.Anything, domain.StatsPeriod{}).Return(nil, errors.New("internal error")).Once()
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"internal server error"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prServiceMock := new(PullRequestServiceMock)
+			tc.setupMocks(prServiceMock)
+
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+			router := api.Handler(server)
+			req := httptest.NewRequest(http.MethodGet, "/stats"+tc.query, nil)
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			prServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_PostTeamDeactivate(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		requestBody          string
+		setupMocks           func(*UserServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Success",
+			requestBody: `{"team_name": "team-to-nuke"}`,
+			setupMocks: func(usm *UserServiceMock) {
+				usm.On("DeactivateTeam", mock.Anything, "team-to-nuke", false).
+					Return(&api.DeactivateTeamResponse{DeactivatedUsersCount: 10, ReassignedPrsCount: 5}, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"deactivated_users_count": 10, "reassigned_prs_count": 5}`,
+		},
+		{
+			name:        "Success - Forced with warnings",
+			requestBody: `{"team_name": "team-to-nuke", "force": true}`,
+			setupMocks: func(usm *UserServiceMock) {
+				warnings := []string{"pull request 'pr-1': no active replacement for reviewer 'u1'"}
+				usm.On("DeactivateTeam", mock.Anything, "team-to-nuke", true).
+					Return(&api.DeactivateTeamResponse{DeactivatedUsersCount: 2, ReassignedPrsCount: 1, Warnings: &warnings}, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"deactivated_users_count": 2, "reassigned_prs_count": 1,
+				"warnings": ["pull request 'pr-1': no active replacement for reviewer 'u1'"]}`,
+		},
+		{
+			name:        "Service Error - Insufficient Capacity",
+			requestBody: `{"team_name": "small-team"}`,
+			setupMocks: func(usm *UserServiceMock) {
+				usm.On("DeactivateTeam", mock.Anything, "small-team", false).
+					Return(nil, fmt.Errorf("transaction failed: %w",
+						&apperrors.InsufficientCapacityError{TeamName: "small-team", PRIDs: []string{"pr-1", "pr-2"}})).Once()
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"INSUFFICIENT_CAPACITY",
+				"message":"no active replacement reviewers for 2 pull requests of team 'small-team': pr-1, pr-2"}}`,
+		},
+		{
+			name:        "Service Error - Team Not Found",
+			requestBody: `{"team_name": "not-found-team"}`,
+			setupMocks: func(usm *UserServiceMock) {
+				usm.On("DeactivateTeam", mock.Anything, "not-found-team", false).
+					Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+		{
+			name:        "Service Error - Deactivation In Progress",
+			requestBody: `{"team_name": "busy-team"}`,
+			setupMocks: func(usm *UserServiceMock) {
+				usm.On("DeactivateTeam", mock.Anything, "busy-team", false).
+					Return(nil, apperrors.ErrDeactivationInProgress).Once()
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":{"code":"DEACTIVATION_IN_PROGRESS","message":"team deactivation is already in progress"}}`,
+		},
+		{
+			name:                 "Invalid Request Body",
+			requestBody:          `{"team_name": ""}`,
+			setupMocks:           func(usm *UserServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error": "validation failed: team_name or team_id is required"}`,
+		},
+		{
+			name:        "Success - Dry run",
+			requestBody: `{"team_name": "big-team", "force": true, "batch_size": 100, "dry_run": true}`,
+			setupMocks: func(usm *UserServiceMock) {
+				moves := []api.ReviewerMove{{PullRequestId: "pr-1", FromUserId: "u1", ToUserId: "u4"}}
+				usm.On("PreviewTeamDeactivation", mock.Anything, "big-team", true).
+					Return(&api.DeactivateTeamResponse{DeactivatedUsersCount: 2, ReassignedPrsCount: 1, Reassignments: &moves}, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"deactivated_users_count": 2, "reassigned_prs_count": 1,
+				"reassignments": [{"pull_request_id": "pr-1", "from_user_id": "u1", "to_user_id": "u4"}]}`,
+		},
+		{
+			name:                 "Invalid Request Body - Batched Without Force",
+			requestBody:          `{"team_name": "big-team", "batch_size": 100}`,
+			setupMocks:           func(usm *UserServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error": "validation failed: field 'Force' failed on the 'required_with' tag"}`,
+		},
+		{
+			name:                 "Invalid Request Body - Batch Too Large",
+			requestBody:          `{"team_name": "big-team", "force": true, "batch_size": 5000}`,
+			setupMocks:           func(usm *UserServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error": "validation failed: field 'BatchSize' failed on the 'max' tag"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userServiceMock := new(UserServiceMock)
+			tc.setupMocks(userServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, userServiceMock, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/team/deactivate", strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			router := api.Handler(server)
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			userServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_PostTeamDeactivate_Batched(t *testing.T) {
+	createdAt := time.Date(2025, time.November, 1, 10, 0, 0, 0, time.UTC)
+	params := map[string]any{"team_name": "big-team", "batch_size": 100}
+
+	jobServiceMock := new(JobServiceMock)
+	jobServiceMock.On("CreateJob", mock.Anything, api.JobTypeTeamDeactivation, params).Return(&api.Job{
+		JobId: 12, Type: api.JobTypeTeamDeactivation, Status: api.JobQueued,
+		Params: map[string]any{"team_name": "big-team", "batch_size": float64(100)}, CreatedAt: createdAt,
+	}, nil).Twice()
+	jobServiceMock.On("CreateJob", mock.Anything, api.JobTypeTeamDeactivation, mock.Anything).
+		Return(nil, fmt.Errorf("%w: jobs of type 'team_deactivation' are not run by this service", apperrors.ErrValidation)).Once()
+
+	teamServiceMock := new(TeamServiceMock)
+	teamServiceMock.On("GetTeam", mock.Anything, "big-team").Return(&api.Team{TeamName: "big-team"}, nil).Twice()
+	teamServiceMock.On("GetTeam", mock.Anything, "other-team").Return(&api.Team{TeamName: "other-team"}, nil).Once()
+	teamServiceMock.On("GetTeam", mock.Anything, "unknown-team").Return(nil, apperrors.ErrNotFound).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamServiceMock, new(UserServiceMock), nil, WithJobs(jobServiceMock))
+
+	serve := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		server.Routes().ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := serve("/team/deactivate", `{"team_name": "big-team", "force": true, "batch_size": 100}`)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, "/jobs/12", rr.Header().Get("Location"))
+	assert.JSONEq(t, `{"job":{"job_id":12,"type":"team_deactivation","status":"queued",
+		"params":{"team_name":"big-team","batch_size":100},"progress":{"done":0,"total":0},"cancel_requested":false,
+		"error":null,"result":null,"attempts":0,"created_at":"2025-11-01T10:00:00Z","started_at":null,"finished_at":null}}`, rr.Body.String())
+
+	rr = serve("/v1/team/deactivate", `{"team_name": "big-team", "force": true, "batch_size": 100}`)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, "/v1/jobs/12", rr.Header().Get("Location"))
+
+	rr = serve("/team/deactivate", `{"team_name": "other-team", "force": true, "batch_size": 10}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Empty(t, rr.Header().Get("Location"))
+
+	// No job is queued for a team that does not exist.
+	rr = serve("/team/deactivate", `{"team_name": "unknown-team", "force": true, "batch_size": 10}`)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	jobServiceMock.AssertExpectations(t)
+	teamServiceMock.AssertExpectations(t)
+}
+
+func TestServer_PostTeamReactivate(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		requestBody          string
+		setupMocks           func(usm *UserServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Success - Reviewers restored",
+			requestBody: `{"team_name": "team-to-nuke", "restore_reviewers": true}`,
+			setupMocks: func(usm *UserServiceMock) {
+				usm.On("ReactivateTeam", mock.Anything, "team-to-nuke", true).Return(&api.ReactivateTeamResponse{
+					ReactivatedUsersCount: 2,
+					RestoredReviews:       []api.ReviewerMove{{PullRequestId: "pr-1", FromUserId: "u4", ToUserId: "u1"}},
+				}, nil).Once()
 			},
-			expectedStatusCode:   http.StatusConflict,
-			expectedResponseBody: `{"error":{"code":"NOT_ASSIGNED","message":"reviewer is not assigned to this PR"}}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"reactivated_users_count": 2,
+				"restored_reviews": [{"pull_request_id": "pr-1", "from_user_id": "u4", "to_user_id": "u1"}]}`,
 		},
 		{
-			name:        "Service Error - No Candidate",
-			requestBody: `{"pull_request_id": "pr-123", "old_user_id": "old-reviewer"}`,
-			setupMocks: func(prsm *PullRequestServiceMock) {
-				prsm.On("ReassignReviewer", mock.Anything, "pr-123", "old-reviewer").
-					Return(nil, apperrors.ErrNoCandidate).Once()
+			name:        "Service Error - Nothing to undo",
+			requestBody: `{"team_name": "active-team"}`,
+			setupMocks: func(usm *UserServiceMock) {
+				usm.On("ReactivateTeam", mock.Anything, "active-team", false).Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+		{
+			name:        "Service Error - Deactivation In Progress",
+			requestBody: `{"team_name": "busy-team"}`,
+			setupMocks: func(usm *UserServiceMock) {
+				usm.On("ReactivateTeam", mock.Anything, "busy-team", false).Return(nil, apperrors.ErrDeactivationInProgress).Once()
 			},
 			expectedStatusCode:   http.StatusConflict,
-			expectedResponseBody: `{"error":{"code":"NO_CANDIDATE","message":"no active replacement candidate found in team"}}`,
+			expectedResponseBody: `{"error":{"code":"DEACTIVATION_IN_PROGRESS","message":"team deactivation is already in progress"}}`,
+		},
+		{
+			name:                 "Invalid Request Body",
+			requestBody:          `{"team_name": ""}`,
+			setupMocks:           func(usm *UserServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error": "validation failed: team_name or team_id is required"}`,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			prServiceMock := new(PullRequestServiceMock)
-			tc.setupMocks(prServiceMock)
-			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+			userServiceMock := new(UserServiceMock)
+			tc.setupMocks(userServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, userServiceMock, nil)
 
-			req := httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", strings.NewReader(tc.requestBody))
+			req := httptest.NewRequest(http.MethodPost, "/team/reactivate", strings.NewReader(tc.requestBody))
 			req.Header.Set("Content-Type", "application/json")
 
 			rr := httptest.NewRecorder()
@@ -404,20 +2521,129 @@ func TestServer_PostPullRequestReassign(t *testing.T) {
 			router.ServeHTTP(rr, req)
 
 			assert.Equal(t, tc.expectedStatusCode, rr.Code)
-			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
-			prServiceMock.AssertExpectations(t)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			userServiceMock.AssertExpectations(t)
 		})
 	}
 }
 
-func TestServer_GetUsersGetReview(t *testing.T) {
-	reviewResponse := &api.GetReviewResponse{
-		UserId: "user-1",
-		PullRequests: []api.PullRequestShort{
-			{PullRequestId: "pr-1", PullRequestName: "Feature A", AuthorId: "author-A", Status: "OPEN"},
-		},
+func TestServer_DeleteTeam(t *testing.T) {
+	userServiceMock := new(UserServiceMock)
+	userServiceMock.On("DeleteTeam", mock.Anything, "old-team", true).Return(&api.DeleteTeamResponse{
+		TeamId: 4, DeletedUsersCount: 2, ReassignedPrsCount: 1,
+		Warnings: &[]string{"pull request 'pr-1': no active replacement for reviewer 'u1'"},
+	}, nil).Once()
+	userServiceMock.On("DeleteTeam", mock.Anything, "gone-team", false).Return(nil, apperrors.ErrNotFound).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, userServiceMock, nil)
+	router := api.Handler(server)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/team?team_name=old-team&force=true", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"team_id":4,"deleted_users_count":2,"reassigned_prs_count":1,
+		"warnings":["pull request 'pr-1': no active replacement for reviewer 'u1'"]}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/team?team_name=gone-team", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/team", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	userServiceMock.AssertExpectations(t)
+}
+
+func TestServer_PostTeamRestore(t *testing.T) {
+	teamID := 4
+
+	userServiceMock := new(UserServiceMock)
+	userServiceMock.On("RestoreTeam", mock.Anything, 4).Return(&api.Team{
+		TeamId: &teamID, TeamName: "old-team", Members: []api.TeamMember{{UserId: "u1", Username: "Alice"}},
+	}, nil).Once()
+	userServiceMock.On("RestoreTeam", mock.Anything, 5).Return(nil, &apperrors.TeamAlreadyExistsError{TeamName: "old-team"}).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, userServiceMock, nil)
+	router := api.Handler(server)
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/team/restore", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		return rr
 	}
 
+	rr := serve(`{"team_id": 4}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"team":{"team_id":4,"team_name":"old-team","members":[{"user_id":"u1","username":"Alice","is_active":false}]}}`, rr.Body.String())
+
+	rr = serve(`{"team_id": 5}`)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = serve(`{}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	userServiceMock.AssertExpectations(t)
+}
+
+func TestServer_GetTeamDeactivationJob(t *testing.T) {
+	createdAt := time.Date(2025, time.November, 1, 10, 0, 0, 0, time.UTC)
+	finishedAt := createdAt.Add(time.Minute)
+
+	userServiceMock := new(UserServiceMock)
+	userServiceMock.On("GetDeactivationJob", mock.Anything, int64(7)).Return(&api.DeactivationJob{
+		JobId: 7, TeamName: "big-team", TeamId: 4, Status: api.DeactivationJobSucceeded, BatchSize: 100, TotalBatches: 1, DoneBatches: 1,
+		DeactivatedUsersCount: 2, TotalPrs: 3, ReassignedReviewsCount: 2,
+		Warnings:  []string{"pull request 'pr-1': no active replacement for reviewer 'u1'"},
+		CreatedAt: createdAt, FinishedAt: &finishedAt,
+	}, nil).Once()
+	userServiceMock.On("GetDeactivationJob", mock.Anything, int64(8)).Return(nil, apperrors.ErrNotFound).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, userServiceMock, nil)
+	router := server.Routes()
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/team/deactivationJob?job_id=7", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"job":{"job_id":7,"team_name":"big-team","team_id":4,"status":"succeeded","batch_size":100,"total_batches":1,
+		"done_batches":1,"deactivated_users_count":2,"total_prs":3,"reassigned_reviews_count":2,
+		"warnings":["pull request 'pr-1': no active replacement for reviewer 'u1'"],
+		"created_at":"2025-11-01T10:00:00Z","finished_at":"2025-11-01T10:01:00Z"},
+		"warnings":["GET /team/deactivationJob is deprecated, use GET /jobs/{job_id}"]}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/team/deactivationJob?job_id=8", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("Deprecation"), "the endpoint is replaced by /jobs/{job_id}")
+	userServiceMock.AssertExpectations(t)
+}
+
+func TestServer_GetPullRequestPending(t *testing.T) {
+	enqueuedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	pending := []api.PendingAssignment{{
+		PullRequestId:   "pr-1",
+		PullRequestName: "Add queue",
+		AuthorId:        "u1",
+		TeamName:        "backend",
+		TeamId:          1,
+		Priority:        2,
+		EnqueuedAt:      enqueuedAt,
+		WaitingSeconds:  90,
+	}}
+	pendingResponse := &api.PendingAssignmentsResponse{Items: pending, PendingAssignments: pending}
+	pendingBody := `{"items":[{"pull_request_id":"pr-1","pull_request_name":"Add queue","author_id":"u1",` +
+		`"team_name":"backend","team_id":1,"priority":2,"enqueued_at":"2025-01-02T03:04:05Z","waiting_seconds":90}],` +
+		`"pending_assignments":[{"pull_request_id":"pr-1","pull_request_name":"Add queue","author_id":"u1",` +
+		`"team_name":"backend","team_id":1,"priority":2,"enqueued_at":"2025-01-02T03:04:05Z","waiting_seconds":90}],` +
+		`"next_cursor":null}`
+
 	testCases := []struct {
 		name                 string
 		targetURL            string
@@ -426,22 +2652,42 @@ func TestServer_GetUsersGetReview(t *testing.T) {
 		expectedResponseBody string
 	}{
 		{
-			name:      "Success",
-			targetURL: "/users/getReview?user_id=user-1",
+			name:      "Success with defaults",
+			targetURL: "/pullRequest/pending",
 			setupMocks: func(prsm *PullRequestServiceMock) {
-				prsm.On("GetReviewAssignments", mock.Anything, "user-1").Return(reviewResponse, nil).Once()
+				prsm.On("GetPendingAssignments", mock.Anything, "", defaultPendingLimit).Return(pendingResponse, nil).Once()
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"user_id":"user-1","pull_requests":[{"pull_request_id":"pr-1","pull_request_name":"Feature A","author_id":"author-A","status":"OPEN"}]}`,
+			expectedResponseBody: pendingBody,
 		},
 		{
-			name:      "User Not Found",
-			targetURL: "/users/getReview?user_id=not-found",
+			name:      "Success with team filter",
+			targetURL: "/pullRequest/pending?team_name=backend&limit=5",
 			setupMocks: func(prsm *PullRequestServiceMock) {
-				prsm.On("GetReviewAssignments", mock.Anything, "not-found").Return(nil, apperrors.ErrNotFound).Once()
+				prsm.On("GetPendingAssignments", mock.Anything, "backend", 5).
+					Return(&api.PendingAssignmentsResponse{Items: []api.PendingAssignment{}, PendingAssignments: []api.PendingAssignment{}}, nil).Once()
 			},
-			expectedStatusCode:   http.StatusNotFound,
-			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"items":[],"pending_assignments":[],"next_cursor":null}`,
+		},
+		{
+			name:      "Success with team ID filter",
+			targetURL: "/pullRequest/pending?team_id=1",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("GetPendingAssignments", mock.Anything, "backend", defaultPendingLimit).Return(pendingResponse, nil).Once()
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: pendingBody,
+		},
+		{
+			name:      "Invalid limit",
+			targetURL: "/pullRequest/pending?limit=500",
+			setupMocks: func(prsm *PullRequestServiceMock) {
+				prsm.On("GetPendingAssignments", mock.Anything, "", 500).
+					Return(nil, fmt.Errorf("%w: limit must be between 1 and 100", apperrors.ErrValidation)).Once()
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: limit must be between 1 and 100"}`,
 		},
 	}
 
@@ -449,7 +2695,9 @@ func TestServer_GetUsersGetReview(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			prServiceMock := new(PullRequestServiceMock)
 			tc.setupMocks(prServiceMock)
-			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+			teamServiceMock := new(TeamServiceMock)
+			teamServiceMock.On("GetTeamByID", mock.Anything, 1).Return(&api.Team{TeamName: "backend"}, nil).Maybe()
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamServiceMock, nil, prServiceMock)
 
 			router := api.Handler(server)
 			req := httptest.NewRequest(http.MethodGet, tc.targetURL, nil)
@@ -464,33 +2712,63 @@ func TestServer_GetUsersGetReview(t *testing.T) {
 	}
 }
 
-func TestServer_GetStats(t *testing.T) {
+func TestServer_PostPullRequestCreateAsync(t *testing.T) {
+	enqueuedAt := time.Date(2025, time.November, 1, 10, 0, 0, 0, time.UTC)
+	queued := &api.AsyncCreateRequest{
+		RequestId: 42, PullRequestId: "pr-1001", Status: api.Queued, EnqueuedAt: enqueuedAt,
+	}
+
 	testCases := []struct {
 		name                 string
+		path                 string
+		requestBody          string
 		setupMocks           func(*PullRequestServiceMock)
 		expectedStatusCode   int
+		expectedLocation     string
 		expectedResponseBody string
 	}{
 		{
-			name: "Success",
-			setupMocks: func(prsm *PullRequestServiceMock) {
-				expectedStats := &api.StatsResponse{
-					UserStats: []api.UserStats{
-						{UserId: "u1", Username: "Alice", OpenReviews: 1, MergedReviews: 5},
-					},
-				}
-				prsm.On("GetStats", mock.Anything).Return(expectedStats, nil).Once()
+			name:        "Success",
+			path:        "/pullRequest/createAsync",
+			requestBody: `{"pull_request_id": "pr-1001", "pull_request_name": "Add search", "author_id": "u1"}`,
+			setupMocks: func(psm *PullRequestServiceMock) {
+				psm.On("EnqueueCreatePR", mock.Anything, "pr-1001", "Add search", "u1", service.PRDetails{}).Return(queued, nil).Once()
 			},
-			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"user_stats":[{"user_id":"u1","username":"Alice","open_reviews":1,"merged_reviews":5}]}`,
+			expectedStatusCode: http.StatusAccepted,
+			expectedLocation:   "/pullRequest/createStatus?request_id=42",
+			expectedResponseBody: `{"request":{"request_id":42,"pull_request_id":"pr-1001","status":"queued","attempts":0,
+				"enqueued_at":"2025-11-01T10:00:00Z","started_at":null,"finished_at":null}}`,
 		},
 		{
-			name: "Service Error",
-			setupMocks: func(prsm *PullRequestServiceMock) {
-				prsm.On("GetStats", mock.Anything).Return(nil, errors.New("internal error")).Once()
+			name:        "Success under v1 links the v1 status",
+			path:        "/v1/pullRequest/createAsync",
+			requestBody: `{"pull_request_id": "pr-1001", "pull_request_name": "Add search", "author_id": "u1"}`,
+			setupMocks: func(psm *PullRequestServiceMock) {
+				psm.On("EnqueueCreatePR", mock.Anything, "pr-1001", "Add search", "u1", service.PRDetails{}).Return(queued, nil).Once()
 			},
-			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"internal server error"}`,
+			expectedStatusCode: http.StatusAccepted,
+			expectedLocation:   "/v1/pullRequest/createStatus?request_id=42",
+			expectedResponseBody: `{"request":{"request_id":42,"pull_request_id":"pr-1001","status":"queued","attempts":0,
+				"enqueued_at":"2025-11-01T10:00:00Z","started_at":null,"finished_at":null}}`,
+		},
+		{
+			name:        "Service Error - Disabled",
+			path:        "/pullRequest/createAsync",
+			requestBody: `{"pull_request_id": "pr-1001", "pull_request_name": "Add search", "author_id": "u1"}`,
+			setupMocks: func(psm *PullRequestServiceMock) {
+				psm.On("EnqueueCreatePR", mock.Anything, "pr-1001", "Add search", "u1", service.PRDetails{}).
+					Return(nil, fmt.Errorf("%w: asynchronous pull request creation is disabled", apperrors.ErrValidation)).Once()
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: asynchronous pull request creation is disabled"}`,
+		},
+		{
+			name:                 "Validation Error - Short Name",
+			path:                 "/pullRequest/createAsync",
+			requestBody:          `{"pull_request_id": "pr-1001", "pull_request_name": "Add", "author_id": "u1"}`,
+			setupMocks:           func(psm *PullRequestServiceMock) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: field 'PullRequestName' failed on the 'min' tag"}`,
 		},
 	}
 
@@ -498,12 +2776,66 @@ func TestServer_GetStats(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			prServiceMock := new(PullRequestServiceMock)
 			tc.setupMocks(prServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			server.Routes().ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			assert.Equal(t, tc.expectedLocation, rr.Header().Get("Location"))
+			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			prServiceMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetPullRequestCreateStatus(t *testing.T) {
+	enqueuedAt := time.Date(2025, time.November, 1, 10, 0, 0, 0, time.UTC)
+	finishedAt := enqueuedAt.Add(time.Second)
+
+	testCases := []struct {
+		name                 string
+		setupMocks           func(*PullRequestServiceMock)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Failed request carries the error",
+			setupMocks: func(psm *PullRequestServiceMock) {
+				psm.On("GetCreatePRRequest", mock.Anything, int64(42)).Return(&api.AsyncCreateRequest{
+					RequestId: 42, PullRequestId: "pr-1001", Status: api.Failed, Attempts: 1,
+					EnqueuedAt: enqueuedAt, StartedAt: &enqueuedAt, FinishedAt: &finishedAt,
+					Error: &api.AsyncCreateError{Code: "NOT_FOUND", Message: "author not found or has no team"},
+				}, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"request":{"request_id":42,"pull_request_id":"pr-1001","status":"failed","attempts":1,
+				"enqueued_at":"2025-11-01T10:00:00Z","started_at":"2025-11-01T10:00:00Z","finished_at":"2025-11-01T10:00:01Z",
+				"error":{"code":"NOT_FOUND","message":"author not found or has no team"}}}`,
+		},
+		{
+			name: "Unknown request",
+			setupMocks: func(psm *PullRequestServiceMock) {
+				psm.On("GetCreatePRRequest", mock.Anything, int64(42)).Return(nil, apperrors.ErrNotFound).Once()
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+		},
+	}
 
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prServiceMock := new(PullRequestServiceMock)
+			tc.setupMocks(prServiceMock)
 			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, prServiceMock)
-			router := api.Handler(server)
-			req := httptest.NewRequest(http.MethodGet, "/stats", nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/pullRequest/createStatus?request_id=42", nil)
 			rr := httptest.NewRecorder()
 
+			router := api.Handler(server)
 			router.ServeHTTP(rr, req)
 
 			assert.Equal(t, tc.expectedStatusCode, rr.Code)
@@ -513,59 +2845,310 @@ func TestServer_GetStats(t *testing.T) {
 	}
 }
 
-func TestServer_PostTeamDeactivate(t *testing.T) {
+func TestServer_PostJobs(t *testing.T) {
+	createdAt := time.Date(2025, time.November, 1, 10, 0, 0, 0, time.UTC)
+	queued := &api.Job{
+		JobId: 3, Type: api.JobTypePendingBackfill, Status: api.JobQueued,
+		Params: map[string]interface{}{"batch_size": float64(10)}, CreatedAt: createdAt,
+	}
+
 	testCases := []struct {
 		name                 string
+		path                 string
 		requestBody          string
-		setupMocks           func(*UserServiceMock)
+		setupMocks           func(*JobServiceMock)
 		expectedStatusCode   int
+		expectedLocation     string
 		expectedResponseBody string
 	}{
 		{
 			name:        "Success",
-			requestBody: `{"team_name": "team-to-nuke"}`,
-			setupMocks: func(usm *UserServiceMock) {
-				usm.On("DeactivateTeam", mock.Anything, "team-to-nuke").
-					Return(10, 5, nil).Once()
+			path:        "/jobs",
+			requestBody: `{"type": "pending_backfill", "params": {"batch_size": 10}}`,
+			setupMocks: func(jsm *JobServiceMock) {
+				jsm.On("CreateJob", mock.Anything, api.JobTypePendingBackfill, map[string]any{"batch_size": float64(10)}).
+					Return(queued, nil).Once()
 			},
-			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"deactivated_users_count": 10, "reassigned_prs_count": 5}`,
+			expectedStatusCode: http.StatusAccepted,
+			expectedLocation:   "/jobs/3",
+			expectedResponseBody: `{"job":{"job_id":3,"type":"pending_backfill","status":"queued","params":{"batch_size":10},
+				"progress":{"done":0,"total":0},"result":null,"error":null,"cancel_requested":false,"attempts":0,
+				"created_at":"2025-11-01T10:00:00Z","started_at":null,"finished_at":null}}`,
 		},
 		{
-			name:        "Service Error - Team Not Found",
-			requestBody: `{"team_name": "not-found-team"}`,
-			setupMocks: func(usm *UserServiceMock) {
-				usm.On("DeactivateTeam", mock.Anything, "not-found-team").
-					Return(0, 0, apperrors.ErrNotFound).Once()
+			name:        "Success under v1 links the v1 job",
+			path:        "/v1/jobs",
+			requestBody: `{"type": "pending_backfill", "params": {"batch_size": 10}}`,
+			setupMocks: func(jsm *JobServiceMock) {
+				jsm.On("CreateJob", mock.Anything, api.JobTypePendingBackfill, map[string]any{"batch_size": float64(10)}).
+					Return(queued, nil).Once()
 			},
-			expectedStatusCode:   http.StatusNotFound,
-			expectedResponseBody: `{"error":{"code":"NOT_FOUND","message":"resource not found"}}`,
+			expectedStatusCode: http.StatusAccepted,
+			expectedLocation:   "/v1/jobs/3",
+			expectedResponseBody: `{"job":{"job_id":3,"type":"pending_backfill","status":"queued","params":{"batch_size":10},
+				"progress":{"done":0,"total":0},"result":null,"error":null,"cancel_requested":false,"attempts":0,
+				"created_at":"2025-11-01T10:00:00Z","started_at":null,"finished_at":null}}`,
 		},
 		{
-			name:                 "Invalid Request Body",
-			requestBody:          `{"team_name": ""}`,
-			setupMocks:           func(usm *UserServiceMock) {},
+			name:        "Service Error - Unknown Type",
+			path:        "/jobs",
+			requestBody: `{"type": "reindex"}`,
+			setupMocks: func(jsm *JobServiceMock) {
+				jsm.On("CreateJob", mock.Anything, api.JobType("reindex"), map[string]any(nil)).
+					Return(nil, fmt.Errorf("%w: jobs of type 'reindex' are not run by this service", apperrors.ErrValidation)).Once()
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"validation failed: jobs of type 'reindex' are not run by this service"}`,
+		},
+		{
+			name:                 "Validation Error - Missing Type",
+			path:                 "/jobs",
+			requestBody:          `{"params": {}}`,
+			setupMocks:           func(jsm *JobServiceMock) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error": "validation failed: field 'TeamName' failed on the 'required' tag"}`,
+			expectedResponseBody: `{"error":"validation failed: field 'Type' failed on the 'required' tag"}`,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			userServiceMock := new(UserServiceMock)
-			tc.setupMocks(userServiceMock)
-			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, userServiceMock, nil)
+			jobServiceMock := new(JobServiceMock)
+			tc.setupMocks(jobServiceMock)
+			server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithJobs(jobServiceMock))
 
-			req := httptest.NewRequest(http.MethodPost, "/team/deactivate", strings.NewReader(tc.requestBody))
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.requestBody))
 			req.Header.Set("Content-Type", "application/json")
 
 			rr := httptest.NewRecorder()
-
-			router := api.Handler(server)
-			router.ServeHTTP(rr, req)
+			server.Routes().ServeHTTP(rr, req)
 
 			assert.Equal(t, tc.expectedStatusCode, rr.Code)
-			userServiceMock.AssertExpectations(t)
+			assert.Equal(t, tc.expectedLocation, rr.Header().Get("Location"))
+			assert.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			jobServiceMock.AssertExpectations(t)
 		})
 	}
 }
+
+func TestServer_PostAdminStatsBackfill(t *testing.T) {
+	createdAt := time.Date(2025, time.November, 1, 10, 0, 0, 0, time.UTC)
+
+	jobServiceMock := new(JobServiceMock)
+	jobServiceMock.On("CreateJob", mock.Anything, api.JobTypeStatsBackfill, map[string]any{"batch_size": 500}).Return(&api.Job{
+		JobId: 7, Type: api.JobTypeStatsBackfill, Status: api.JobQueued,
+		Params: map[string]any{"batch_size": float64(500)}, CreatedAt: createdAt,
+	}, nil).Once()
+	jobServiceMock.On("CreateJob", mock.Anything, api.JobTypeStatsBackfill, map[string]any{}).Return(&api.Job{
+		JobId: 8, Type: api.JobTypeStatsBackfill, Status: api.JobQueued, Params: map[string]any{}, CreatedAt: createdAt,
+	}, nil).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithJobs(jobServiceMock))
+
+	serve := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		server.Routes().ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := serve("/admin/stats/backfill", `{"batch_size": 500}`)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, "/jobs/7", rr.Header().Get("Location"))
+	assert.JSONEq(t, `{"job":{"job_id":7,"type":"stats_backfill","status":"queued",
+		"params":{"batch_size":500},"progress":{"done":0,"total":0},"cancel_requested":false,
+		"error":null,"result":null,"attempts":0,"created_at":"2025-11-01T10:00:00Z","started_at":null,"finished_at":null}}`, rr.Body.String())
+
+	rr = serve("/v1/admin/stats/backfill", `{}`)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, "/v1/jobs/8", rr.Header().Get("Location"))
+
+	rr = serve("/admin/stats/backfill", `{"batch_size": 5000}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.JSONEq(t, `{"error":"validation failed: field 'BatchSize' failed on the 'max' tag"}`, rr.Body.String())
+
+	jobServiceMock.AssertExpectations(t)
+}
+
+func TestServer_GetAndDeleteJob(t *testing.T) {
+	createdAt := time.Date(2025, time.November, 1, 10, 0, 0, 0, time.UTC)
+	finishedAt := createdAt.Add(time.Minute)
+	result := map[string]interface{}{"assigned_reviewers": float64(5)}
+	succeeded := &api.Job{
+		JobId: 3, Type: api.JobTypePendingBackfill, Status: api.JobSucceeded, Params: map[string]interface{}{},
+		Progress: api.JobProgress{Done: 5}, Result: &result, Attempts: 1,
+		CreatedAt: createdAt, StartedAt: &createdAt, FinishedAt: &finishedAt,
+	}
+	expectedJob := `{"job":{"job_id":3,"type":"pending_backfill","status":"succeeded","params":{},
+		"progress":{"done":5,"total":0},"result":{"assigned_reviewers":5},"error":null,"cancel_requested":false,"attempts":1,
+		"created_at":"2025-11-01T10:00:00Z","started_at":"2025-11-01T10:00:00Z","finished_at":"2025-11-01T10:01:00Z"}}`
+
+	jobServiceMock := new(JobServiceMock)
+	jobServiceMock.On("GetJob", mock.Anything, int64(3)).Return(succeeded, nil).Once()
+	jobServiceMock.On("GetJob", mock.Anything, int64(4)).Return(nil, apperrors.ErrNotFound).Once()
+	jobServiceMock.On("CancelJob", mock.Anything, int64(3)).Return(nil, apperrors.ErrJobFinished).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithJobs(jobServiceMock))
+	router := api.Handler(server)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/jobs/3", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, expectedJob, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/jobs/4", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/jobs/3", nil))
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.JSONEq(t, `{"error":{"code":"JOB_FINISHED","message":"job has already finished"}}`, rr.Body.String())
+	jobServiceMock.AssertExpectations(t)
+}
+
+func TestServer_AdminNotifications(t *testing.T) {
+	createdAt := time.Date(2025, time.November, 1, 10, 0, 0, 0, time.UTC)
+	failure := "connection refused"
+	failed := api.NotificationDelivery{
+		DeliveryId: 42, Channel: "log", RecipientId: "u2", Event: api.NotificationReviewersAssigned, PullRequestId: "pr-1001",
+		Status: api.DeliveryFailed, Error: &failure, Attempts: 1, CreatedAt: createdAt, UpdatedAt: createdAt,
+	}
+	delivered := failed
+	delivered.Status, delivered.Error, delivered.Attempts = api.DeliveryDelivered, nil, 2
+	nextCursor := "NDI"
+
+	notificationsMock := new(NotificationServiceMock)
+	notificationsMock.On("ListDeliveries", mock.Anything, service.DeliveryQuery{
+		RecipientID: "u2", Status: "failed", Limit: defaultListLimit,
+	}).Return(&api.ListNotificationDeliveriesResponse{
+		Items: []api.NotificationDelivery{failed}, Deliveries: []api.NotificationDelivery{failed}, NextCursor: &nextCursor,
+	}, nil).Once()
+	notificationsMock.On("ListDeliveries", mock.Anything, service.DeliveryQuery{Event: "pr_merged", Cursor: "NDM", Limit: 5}).
+		Return(&api.ListNotificationDeliveriesResponse{Items: []api.NotificationDelivery{}, Deliveries: []api.NotificationDelivery{}}, nil).Once()
+	notificationsMock.On("RetryDelivery", mock.Anything, int64(42)).Return(&delivered, nil).Once()
+	notificationsMock.On("RetryDelivery", mock.Anything, int64(43)).Return(nil, apperrors.ErrDeliveryNotFailed).Once()
+	notificationsMock.On("RetryDelivery", mock.Anything, int64(44)).Return(nil, apperrors.ErrNotFound).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithNotifications(notificationsMock))
+	router := api.Handler(server)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/notifications?recipient_id=u2&status=failed", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	expectedDelivery := `{"delivery_id":42,"channel":"log","recipient_id":"u2","event":"reviewers_assigned",
+		"pull_request_id":"pr-1001","status":"failed","error":"connection refused","attempts":1,
+		"created_at":"2025-11-01T10:00:00Z","updated_at":"2025-11-01T10:00:00Z"}`
+	assert.JSONEq(t, `{"items":[`+expectedDelivery+`],"deliveries":[`+expectedDelivery+`],"next_cursor":"NDI"}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/notifications?event=pr_merged&limit=5&cursor=NDM", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"items":[],"deliveries":[],"next_cursor":null}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/notifications/42/retry", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"delivery":{"delivery_id":42,"channel":"log","recipient_id":"u2","event":"reviewers_assigned",
+		"pull_request_id":"pr-1001","status":"delivered","error":null,"attempts":2,
+		"created_at":"2025-11-01T10:00:00Z","updated_at":"2025-11-01T10:00:00Z"}}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/notifications/43/retry", nil))
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.JSONEq(t, `{"error":{"code":"DELIVERY_NOT_FAILED","message":"notification has already been delivered"}}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/notifications/44/retry", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	notificationsMock.AssertExpectations(t)
+}
+
+func TestServer_AdminFreezes(t *testing.T) {
+	startsAt := time.Date(2025, time.December, 22, 18, 0, 0, 0, time.UTC)
+	endsAt := time.Date(2025, time.December, 29, 9, 0, 0, 0, time.UTC)
+	teamName, teamID := "backend", 7
+	total := 1
+	window := api.FreezeWindow{
+		FreezeId: 3, TeamName: &teamName, TeamId: &teamID, Reason: "release freeze",
+		StartsAt: startsAt, EndsAt: endsAt, CreatedAt: startsAt,
+	}
+
+	freezesMock := new(FreezeServiceMock)
+	freezesMock.On("CreateFreezeWindow", mock.Anything, "backend", "release freeze", startsAt, endsAt).Return(&window, nil).Once()
+	freezesMock.On("CreateFreezeWindow", mock.Anything, "", "incident", endsAt, startsAt).
+		Return(nil, fmt.Errorf("%w: freeze window must end after it starts", apperrors.ErrValidation)).Once()
+	freezesMock.On("ListFreezeWindows", mock.Anything, "backend", true).
+		Return(&api.ListFreezeWindowsResponse{Items: []api.FreezeWindow{window}, Freezes: []api.FreezeWindow{window}, TotalEstimate: &total}, nil).Once()
+	freezesMock.On("ListFreezeWindows", mock.Anything, "backend", false).
+		Return(&api.ListFreezeWindowsResponse{Items: []api.FreezeWindow{window}, Freezes: []api.FreezeWindow{window}, TotalEstimate: &total}, nil).Once()
+	freezesMock.On("DeleteFreezeWindow", mock.Anything, int64(3)).Return(nil).Once()
+	freezesMock.On("DeleteFreezeWindow", mock.Anything, int64(4)).Return(apperrors.ErrNotFound).Once()
+
+	teamServiceMock := new(TeamServiceMock)
+	teamServiceMock.On("GetTeamByID", mock.Anything, teamID).Return(&api.Team{TeamName: teamName, TeamId: &teamID}, nil).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamServiceMock, nil, nil, WithFreezes(freezesMock))
+	router := api.Handler(server)
+
+	expectedWindow := `{"freeze_id":3,"team_name":"backend","team_id":7,"reason":"release freeze",
+		"starts_at":"2025-12-22T18:00:00Z","ends_at":"2025-12-29T09:00:00Z","created_at":"2025-12-22T18:00:00Z"}`
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/freezes", strings.NewReader(
+		`{"team_name":"backend","reason":"release freeze","starts_at":"2025-12-22T18:00:00Z","ends_at":"2025-12-29T09:00:00Z"}`)))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.JSONEq(t, `{"freeze":`+expectedWindow+`}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/freezes", strings.NewReader(
+		`{"reason":"incident","starts_at":"2025-12-29T09:00:00Z","ends_at":"2025-12-22T18:00:00Z"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/freezes", strings.NewReader(`{"starts_at":"2025-12-22T18:00:00Z"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/freezes?team_name=backend&include_ended=true", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"items":[`+expectedWindow+`],"freezes":[`+expectedWindow+`],"next_cursor":null,"total_estimate":1}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/freezes?team_id=7", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"items":[`+expectedWindow+`],"freezes":[`+expectedWindow+`],"next_cursor":null,"total_estimate":1}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/freezes?team_name=backend&team_id=7", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/admin/freezes/3", nil))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Empty(t, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/admin/freezes/4", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	freezesMock.AssertExpectations(t)
+	teamServiceMock.AssertExpectations(t)
+}
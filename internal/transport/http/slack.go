@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// WithSlackUsers serves the /admin/slackUsers endpoints with ss.
+func WithSlackUsers(ss service.SlackUserService) ServerOption {
+	return func(s *Server) {
+		s.slackUsers = ss
+	}
+}
+
+func (s *Server) GetAdminSlackUsers(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.GetAdminSlackUsers"
+
+	resp, err := s.slackUsers.ListSlackUsers(r.Context())
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) PostAdminSlackUsers(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostAdminSlackUsers"
+
+	var req setSlackUserRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	mapping, err := s.slackUsers.SetSlackUser(r.Context(), req.UserID, req.SlackUserID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, api.SlackUserResponse{SlackUser: *mapping})
+}
+
+func (s *Server) DeleteAdminSlackUsersUserId(w http.ResponseWriter, r *http.Request, userID string) {
+	const op = "internal.transport.http.DeleteAdminSlackUsersUserId"
+
+	if err := s.slackUsers.DeleteSlackUser(r.Context(), userID); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestServer_AdminSlackUsers(t *testing.T) {
+	createdAt := time.Date(2026, time.January, 12, 9, 0, 0, 0, time.UTC)
+	mapping := api.SlackUser{UserId: "u1", SlackUserId: "U024BE7LH", CreatedAt: createdAt}
+	total := 1
+
+	usersMock := new(SlackUserServiceMock)
+	usersMock.On("SetSlackUser", mock.Anything, "u1", "U024BE7LH").Return(&mapping, nil).Once()
+	usersMock.On("SetSlackUser", mock.Anything, "u1", "@jdoe").Return(nil, apperrors.ErrValidation).Once()
+	usersMock.On("ListSlackUsers", mock.Anything).
+		Return(&api.ListSlackUsersResponse{Items: []api.SlackUser{mapping}, TotalEstimate: &total}, nil).Once()
+	usersMock.On("DeleteSlackUser", mock.Anything, "u1").Return(nil).Once()
+	usersMock.On("DeleteSlackUser", mock.Anything, "ghost").Return(apperrors.ErrNotFound).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithSlackUsers(usersMock))
+	router := api.Handler(server)
+
+	expectedMapping := `{"user_id":"u1","slack_user_id":"U024BE7LH","created_at":"2026-01-12T09:00:00Z"}`
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/slackUsers", strings.NewReader(`{"user_id":"u1","slack_user_id":"U024BE7LH"}`)))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"slack_user":`+expectedMapping+`}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/slackUsers", strings.NewReader(`{"user_id":"u1","slack_user_id":"@jdoe"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/slackUsers", strings.NewReader(`{"user_id":"u1"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/slackUsers", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"items":[`+expectedMapping+`],"next_cursor":null,"total_estimate":1}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/admin/slackUsers/u1", nil))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/admin/slackUsers/ghost", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	usersMock.AssertExpectations(t)
+}
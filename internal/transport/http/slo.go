@@ -0,0 +1,21 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/YusovID/pr-reviewer-service/internal/slo"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// sloReport responds with the compliance of the instance with the configured objectives.
+func (s *Server) sloReport(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.sloReport"
+
+	report, err := slo.Compliance(prometheus.DefaultGatherer, s.slo, s.startedAt)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, report)
+}
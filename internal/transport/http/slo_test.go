@@ -0,0 +1,43 @@
+package http
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/slo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_SLOReport(t *testing.T) {
+	cfg := config.SLO{
+		Window: 720 * time.Hour,
+		Objectives: []config.SLOObjective{
+			{Name: "stats", Method: http.MethodGet, Path: "/slo-test/never-called", Availability: 0.999},
+		},
+	}
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithSLO(cfg))
+
+	rr := httptest.NewRecorder()
+	server.Routes().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/slo", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var report slo.Report
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+	require.Len(t, report.Objectives, 1)
+
+	objective := report.Objectives[0]
+	assert.Equal(t, "stats", objective.Name)
+	assert.Zero(t, objective.Requests)
+	require.NotNil(t, objective.Availability)
+	assert.True(t, objective.Availability.Met)
+	assert.Nil(t, objective.Latency)
+}
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// swaggerPrefix is the path the swagger UI is served under.
+const swaggerPrefix = "/swagger"
+
+var swaggerRequestsTotal = promauto.NewCounterVec(metrics.SwaggerRequests.CounterOpts(), metrics.SwaggerRequests.Labels)
+
+// WithoutSwagger stops serving the swagger UI, e.g. in production; /swagger then answers 404.
+func WithoutSwagger() ServerOption {
+	return func(s *Server) {
+		s.noSwagger = true
+	}
+}
+
+// isSwaggerRequest reports whether r asks for the swagger UI rather than the API. Such requests are counted
+// by swagger_requests_total instead of the HTTP metrics and logged at debug level, so that a browser loading
+// the UI does not show up on the dashboards as API traffic.
+func isSwaggerRequest(r *http.Request) bool {
+	return r.URL.Path == swaggerPrefix || strings.HasPrefix(r.URL.Path, swaggerPrefix+"/")
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Swagger(t *testing.T) {
+	routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil).Routes()
+
+	serve := func(path, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	swaggerOK := swaggerRequestsTotal.WithLabelValues("200")
+	swaggerNotModified := swaggerRequestsTotal.WithLabelValues("304")
+	okBefore, notModifiedBefore := testutil.ToFloat64(swaggerOK), testutil.ToFloat64(swaggerNotModified)
+	apiBefore := testutil.ToFloat64(httpRequestsTotal.WithLabelValues("/swagger/swagger-ui.css", http.MethodGet, "200"))
+
+	rr := serve("/swagger/swagger-ui.css", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "public, max-age=86400", rr.Header().Get("Cache-Control"))
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	rr = serve("/swagger/swagger-ui.css", etag)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+
+	rr = serve("/swagger/", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "no-cache", rr.Header().Get("Cache-Control"), "the page is revalidated on every load")
+	assert.NotEmpty(t, rr.Header().Get("ETag"))
+
+	assert.Equal(t, okBefore+2, testutil.ToFloat64(swaggerOK))
+	assert.Equal(t, notModifiedBefore+1, testutil.ToFloat64(swaggerNotModified))
+	assert.Equal(t, apiBefore, testutil.ToFloat64(httpRequestsTotal.WithLabelValues("/swagger/swagger-ui.css", http.MethodGet, "200")),
+		"UI assets are not counted as API traffic")
+}
+
+func TestServer_WithoutSwagger(t *testing.T) {
+	routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithoutSwagger()).Routes()
+
+	rr := httptest.NewRecorder()
+	routes.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/swagger/", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// requestTimeouts bound the requests by route group.
+type requestTimeouts struct {
+	// fallback bounds the requests outside the groups; 0 leaves them unbounded.
+	fallback time.Duration
+	// routes are ordered from the longest prefix, so that the first match is the most specific group.
+	routes []config.RouteTimeout
+}
+
+// WithRequestTimeouts cancels the context of an API request once the timeout of its route group, or fallback
+// outside the groups, runs out, and answers the request with 504 TIMEOUT. Without it the requests run until
+// the write timeout of the server cuts the connection.
+func WithRequestTimeouts(fallback time.Duration, routes []config.RouteTimeout) ServerOption {
+	return func(s *Server) {
+		sorted := slices.Clone(routes)
+		slices.SortFunc(sorted, func(a, b config.RouteTimeout) int {
+			return cmp.Compare(len(b.Prefix), len(a.Prefix))
+		})
+
+		s.timeouts = &requestTimeouts{fallback: fallback, routes: sorted}
+	}
+}
+
+// of returns the timeout of the requests to path.
+func (t *requestTimeouts) of(path string) time.Duration {
+	for _, rt := range t.routes {
+		if hasPathPrefix(path, rt.Prefix) {
+			return rt.Timeout
+		}
+	}
+
+	return t.fallback
+}
+
+// requestTimeout bounds a request by the timeout of its route. The queries of a handler run with the context of
+// the request and fail once it is cancelled, and handleServiceError answers such failures with 504; a handler
+// that returns without answering at all is answered here.
+func (s *Server) requestTimeout(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout := s.timeouts.of(r.URL.Path)
+		if timeout <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		tw := &answerTrackingWriter{ResponseWriter: w}
+		next.ServeHTTP(tw, r.WithContext(ctx))
+
+		if !tw.answered && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			s.respondTimeout(w)
+		}
+	})
+}
+
+func (s *Server) respondTimeout(w http.ResponseWriter) {
+	s.respondAPIError(w, http.StatusGatewayTimeout, api.TIMEOUT, "request timed out")
+}
+
+// answerTrackingWriter records whether the handler has started its response.
+type answerTrackingWriter struct {
+	http.ResponseWriter
+	answered bool
+}
+
+func (w *answerTrackingWriter) WriteHeader(code int) {
+	w.answered = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *answerTrackingWriter) Write(b []byte) (int, error) {
+	w.answered = true
+	return w.ResponseWriter.Write(b)
+}
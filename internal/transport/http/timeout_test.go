@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/domain"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestServer_RequestTimeout(t *testing.T) {
+	// waitForCancel blocks like a query does until the request timeout cancels it.
+	waitForCancel := func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}
+
+	teamsMock := new(TeamServiceMock)
+	teamsMock.On("GetTeam", mock.Anything, "slow").Run(waitForCancel).Return(nil, context.DeadlineExceeded)
+	// The driver reports a cancelled query with an error of its own.
+	teamsMock.On("GetTeam", mock.Anything, "cancelled").Run(waitForCancel).
+		Return(nil, errors.New("pq: canceling statement due to user request"))
+
+	prsMock := new(PullRequestServiceMock)
+	prsMock.On("GetStats", mock.Anything, domain.StatsPeriod{}).Run(func(mock.Arguments) {
+		time.Sleep(50 * time.Millisecond)
+	}).Return(&api.StatsResponse{Items: []api.UserStats{}}, nil)
+
+	routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamsMock, nil, prsMock,
+		WithRequestTimeouts(20*time.Millisecond, []config.RouteTimeout{{Prefix: "/stats", Timeout: time.Second}}),
+	).Routes()
+
+	testCases := []struct {
+		name         string
+		path         string
+		expectedCode int
+	}{
+		{name: "Cancelled with the context error", path: "/team/get?team_name=slow", expectedCode: http.StatusGatewayTimeout},
+		{name: "Cancelled with a driver error", path: "/v1/team/get?team_name=cancelled", expectedCode: http.StatusGatewayTimeout},
+		{name: "A group with a longer timeout", path: "/stats", expectedCode: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			routes.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, tc.expectedCode, rr.Code, rr.Body.String())
+		})
+	}
+
+	rr := httptest.NewRecorder()
+	routes.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/team/get?team_name=slow", nil))
+	assert.JSONEq(t, `{"error":{"code":"TIMEOUT","message":"request timed out"}}`, rr.Body.String())
+}
+
+func TestServer_RequestTimeoutSilentHandler(t *testing.T) {
+	s := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithRequestTimeouts(10*time.Millisecond, nil))
+
+	// A handler giving up on the cancelled context without an answer of its own.
+	handler := s.requestTimeout(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/team/get", nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, rr.Code)
+}
+
+func TestRequestTimeouts_Of(t *testing.T) {
+	s := NewServer(nil, nil, nil, nil, WithRequestTimeouts(time.Second, []config.RouteTimeout{
+		{Prefix: "/team", Timeout: 2 * time.Second},
+		{Prefix: "/team/deactivate", Timeout: 3 * time.Second},
+	}))
+
+	assert.Equal(t, 3*time.Second, s.timeouts.of("/v1/team/deactivate"), "the longest prefix wins")
+	assert.Equal(t, 2*time.Second, s.timeouts.of("/team/get"))
+	assert.Equal(t, time.Second, s.timeouts.of("/teams"), "a prefix matches whole path segments")
+	assert.Equal(t, time.Second, s.timeouts.of("/stats"))
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/YusovID/pr-reviewer-service/internal/tracing"
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTracing traces the requests, continuing the traces of the callers passed in the traceparent header.
+// The spans of the service operations and of the queries of a request nest under its span.
+func WithTracing() ServerOption {
+	return func(s *Server) {
+		s.tracing = true
+	}
+}
+
+// trace starts the span of a request. It is named by the route once the request has been routed, so that
+// the requests of a route share the name whatever their IDs. The swagger UI is not traced, as it is not
+// counted in the HTTP metrics either.
+func (s *Server) trace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isSwaggerRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Start(ctx, r.Method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.URLPath(r.URL.Path),
+			semconv.UserAgentOriginal(r.UserAgent()),
+		))
+		defer span.End()
+
+		if requestID := getRequestID(ctx); requestID != "" {
+			span.SetAttributes(attribute.String("http.request.header.x-request-id", requestID))
+		}
+
+		wrapper := newResponseWriterWrapper(w)
+		next.ServeHTTP(wrapper, r.WithContext(ctx))
+
+		if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+			span.SetName(r.Method + " " + rctx.RoutePattern())
+			span.SetAttributes(semconv.HTTPRoute(rctx.RoutePattern()))
+		}
+
+		span.SetAttributes(semconv.HTTPResponseStatusCode(wrapper.statusCode))
+
+		if wrapper.statusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(wrapper.statusCode))
+		}
+	})
+}
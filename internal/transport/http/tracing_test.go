@@ -0,0 +1,85 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+func TestServer_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	teamsMock := new(TeamServiceMock)
+	teamsMock.On("GetTeam", mock.Anything, "backend").Return(&api.Team{TeamName: "backend", Members: []api.TeamMember{}}, nil)
+	teamsMock.On("GetTeam", mock.Anything, "broken").Return(nil, errors.New("connection refused"))
+
+	routes := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), teamsMock, nil, nil, WithTracing()).Routes()
+
+	t.Run("Continues the trace of the caller", func(t *testing.T) {
+		recorder.Reset()
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/team/get?team_name=backend", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		req.Header.Set(requestIDHeader, "req-1")
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+
+		span := spans[0]
+		assert.Equal(t, "GET /v1/team/get", span.Name())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+		assert.Contains(t, span.Attributes(), semconv.HTTPRoute("/v1/team/get"))
+		assert.Contains(t, span.Attributes(), semconv.HTTPResponseStatusCode(http.StatusOK))
+		assert.Equal(t, codes.Unset, span.Status().Code)
+	})
+
+	t.Run("Server error", func(t *testing.T) {
+		recorder.Reset()
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/team/get?team_name=broken", nil))
+		require.Equal(t, http.StatusInternalServerError, rr.Code)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "GET /team/get", spans[0].Name())
+		assert.False(t, spans[0].Parent().IsValid(), "a new trace is started without a traceparent")
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+	})
+
+	t.Run("Swagger UI is not traced", func(t *testing.T) {
+		recorder.Reset()
+
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil))
+
+		assert.Empty(t, recorder.Ended())
+	})
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/YusovID/pr-reviewer-service/internal/validation"
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// otherValidationTag replaces the tags missing from validationTags in the tag label.
+const otherValidationTag = "other"
+
+var validationFailuresTotal = promauto.NewCounterVec(metrics.ValidationFailures.CounterOpts(), metrics.ValidationFailures.Labels)
+
+// validationTags are the tags reported as they are by validationFailuresTotal. The request types use only these,
+// and any other tag is reported as otherValidationTag, so that the number of series stays bounded.
+var validationTags = map[string]bool{
+	"required":      true,
+	"required_with": true,
+	"custom_id":     true,
+	"username":      true,
+	"min":           true,
+	"max":           true,
+	"oneof":         true,
+	"http_url":      true,
+}
+
+// validate runs validation.ValidateStruct on a decoded request body and counts every rejected field
+// by endpoint, so that the integrators sending malformed data and the fields causing friction can be found.
+func (s *Server) validate(r *http.Request, v interface{}) error {
+	err := validation.ValidateStruct(v)
+
+	var validationErr *validation.ValidationError
+	if errors.As(err, &validationErr) {
+		endpoint := validationEndpoint(r)
+
+		for _, f := range validationErr.Fields {
+			validationFailuresTotal.WithLabelValues(endpoint, validationField(f.Field), validationTag(f.Tag)).Inc()
+		}
+	}
+
+	return err
+}
+
+// validationEndpoint returns the route pattern of r, so that the IDs in the path do not become label values.
+func validationEndpoint(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		return rctx.RoutePattern()
+	}
+
+	return r.URL.Path
+}
+
+// validationField drops the index of a slice or map element, which comes from the request, e.g. "Members[2]" is "Members".
+func validationField(field string) string {
+	name, _, _ := strings.Cut(field, "[")
+	return name
+}
+
+func validationTag(tag string) string {
+	if validationTags[tag] {
+		return tag
+	}
+
+	return otherValidationTag
+}
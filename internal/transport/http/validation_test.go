@@ -0,0 +1,44 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_ValidationFailureMetrics(t *testing.T) {
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, new(PullRequestServiceMock))
+	routes := server.Routes()
+
+	const endpoint = "/pullRequest/create"
+	missingID := validationFailuresTotal.WithLabelValues(endpoint, "PullRequestID", "required")
+	invalidURL := validationFailuresTotal.WithLabelValues(endpoint, "ExternalURL", "http_url")
+	missingIDBefore, invalidURLBefore := testutil.ToFloat64(missingID), testutil.ToFloat64(invalidURL)
+
+	body := `{"pull_request_name": "New Feature", "author_id": "author-1", "external_url": "not a url"}`
+	req := httptest.NewRequest(http.MethodPost, endpoint, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	routes.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	assert.Equal(t, missingIDBefore+1, testutil.ToFloat64(missingID))
+	assert.Equal(t, invalidURLBefore+1, testutil.ToFloat64(invalidURL))
+}
+
+func TestValidationLabels(t *testing.T) {
+	assert.Equal(t, "Members", validationField("Members[2]"))
+	assert.Equal(t, "Fields", validationField("Fields[some-key]"))
+	assert.Equal(t, "TeamName", validationField("TeamName"))
+
+	assert.Equal(t, "custom_id", validationTag("custom_id"))
+	assert.Equal(t, otherValidationTag, validationTag("email"))
+}
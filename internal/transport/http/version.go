@@ -0,0 +1,26 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/YusovID/pr-reviewer-service/internal/buildinfo"
+)
+
+const appVersionHeader = "X-App-Version"
+
+// version responds with the build of the running instance.
+func (s *Server) version(w http.ResponseWriter, _ *http.Request) {
+	s.respond(w, http.StatusOK, buildinfo.Get())
+}
+
+// versionHeader adds the version of the running instance to every response,
+// so a response can be traced back to a build during incident triage.
+func (s *Server) versionHeader(next http.Handler) http.Handler {
+	version := buildinfo.Version
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(appVersionHeader, version)
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/buildinfo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Version(t *testing.T) {
+	version, commit, date := buildinfo.Version, buildinfo.Commit, buildinfo.Date
+	buildinfo.Version, buildinfo.Commit, buildinfo.Date = "v1.2.3", "0123abc", "2025-03-14T12:00:00Z"
+	t.Cleanup(func() {
+		buildinfo.Version, buildinfo.Commit, buildinfo.Date = version, commit, date
+	})
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil)
+
+	rr := httptest.NewRecorder()
+	server.Routes().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "v1.2.3", rr.Header().Get(appVersionHeader))
+
+	var info buildinfo.Info
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &info))
+	assert.Equal(t, "v1.2.3", info.Version)
+	assert.Equal(t, "0123abc", info.Commit)
+	assert.Equal(t, "2025-03-14T12:00:00Z", info.BuildDate)
+	assert.NotEmpty(t, info.GoVersion)
+}
+
+func TestServer_VersionHeaderOnErrors(t *testing.T) {
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil)
+
+	rr := httptest.NewRecorder()
+	server.Routes().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/no-such-endpoint", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, buildinfo.Version, rr.Header().Get(appVersionHeader))
+}
@@ -0,0 +1,130 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+)
+
+// WithWebhooks serves the /admin/webhooks endpoints with ws.
+func WithWebhooks(ws service.WebhookService) ServerOption {
+	return func(s *Server) {
+		s.webhooks = ws
+	}
+}
+
+func (s *Server) GetAdminWebhooks(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.GetAdminWebhooks"
+
+	resp, err := s.webhooks.ListWebhooks(r.Context())
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) PostAdminWebhooks(w http.ResponseWriter, r *http.Request) {
+	const op = "internal.transport.http.PostAdminWebhooks"
+
+	var req webhookRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	webhook, err := s.webhooks.CreateWebhook(r.Context(), webhookInput(&req))
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusCreated, api.WebhookResponse{Webhook: *webhook})
+}
+
+func (s *Server) GetAdminWebhooksWebhookId(w http.ResponseWriter, r *http.Request, webhookID int64) {
+	const op = "internal.transport.http.GetAdminWebhooksWebhookId"
+
+	webhook, err := s.webhooks.GetWebhook(r.Context(), webhookID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, api.WebhookResponse{Webhook: *webhook})
+}
+
+func (s *Server) PutAdminWebhooksWebhookId(w http.ResponseWriter, r *http.Request, webhookID int64) {
+	const op = "internal.transport.http.PutAdminWebhooksWebhookId"
+
+	var req webhookRequest
+	if err := s.decodeAndValidate(r, &req); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	webhook, err := s.webhooks.UpdateWebhook(r.Context(), webhookID, webhookInput(&req))
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, api.WebhookResponse{Webhook: *webhook})
+}
+
+func (s *Server) DeleteAdminWebhooksWebhookId(w http.ResponseWriter, r *http.Request, webhookID int64) {
+	const op = "internal.transport.http.DeleteAdminWebhooksWebhookId"
+
+	if err := s.webhooks.DeleteWebhook(r.Context(), webhookID); err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) GetAdminWebhooksWebhookIdDeliveries(w http.ResponseWriter, r *http.Request, webhookID int64,
+	params api.GetAdminWebhooksWebhookIdDeliveriesParams,
+) {
+	const op = "internal.transport.http.GetAdminWebhooksWebhookIdDeliveries"
+
+	query := service.WebhookDeliveryQuery{
+		WebhookID: webhookID,
+		Cursor:    queryValue(params.Cursor),
+		Limit:     defaultListLimit,
+	}
+
+	if params.Status != nil {
+		query.Status = string(*params.Status)
+	}
+
+	if params.Limit != nil {
+		query.Limit = *params.Limit
+	}
+
+	resp, err := s.webhooks.ListWebhookDeliveries(r.Context(), query)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, resp)
+}
+
+func (s *Server) PostAdminWebhooksWebhookIdDeliveriesDeliveryIdRedeliver(w http.ResponseWriter, r *http.Request, webhookID int64, deliveryID int64) {
+	const op = "internal.transport.http.PostAdminWebhooksWebhookIdDeliveriesDeliveryIdRedeliver"
+
+	delivery, err := s.webhooks.RedeliverWebhookDelivery(r.Context(), webhookID, deliveryID)
+	if err != nil {
+		s.handleServiceError(w, r, op, err)
+		return
+	}
+
+	s.respond(w, http.StatusOK, api.WebhookDeliveryResponse{Delivery: *delivery})
+}
+
+func webhookInput(req *webhookRequest) service.WebhookInput {
+	return service.WebhookInput{URL: req.URL, Events: req.Events, Secret: req.Secret}
+}
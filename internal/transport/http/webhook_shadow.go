@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Providers of the shadow mode metrics.
+const (
+	shadowProviderGitHub = "github"
+	shadowProviderGitLab = "gitlab"
+)
+
+var (
+	webhookShadowEventsTotal = promauto.NewCounterVec(
+		metrics.WebhookShadowEvents.CounterOpts(), metrics.WebhookShadowEvents.Labels,
+	)
+	webhookShadowReviewersTotal = promauto.NewCounterVec(
+		metrics.WebhookShadowReviewers.CounterOpts(), metrics.WebhookShadowReviewers.Labels,
+	)
+)
+
+// WithWebhookShadow puts the inbound webhooks of the providers enabled in cfg in shadow mode: their deliveries
+// are handled as usual, but the reviewers of an opened pull request are only computed, and nothing is persisted.
+func WithWebhookShadow(cfg config.WebhookShadow) ServerOption {
+	return func(s *Server) {
+		s.webhookShadow = cfg
+	}
+}
+
+// shadowPreview is the assignment a delivery handled in shadow mode would have made.
+type shadowPreview struct {
+	// exists is set when the pull request is in the service already; a live delivery would be a duplicate.
+	exists  bool
+	preview *service.AssignmentPreview
+}
+
+// previewShadowAssignment computes the reviewers the pull request prID of authorID would be assigned,
+// logging them and counting them in the shadow mode metrics.
+func (s *Server) previewShadowAssignment(ctx context.Context, log *slog.Logger, provider, prID, authorID string) (*shadowPreview, error) {
+	if _, err := s.prQueries.GetPR(ctx, prID); err == nil {
+		return &shadowPreview{exists: true}, nil
+	} else if !errors.Is(err, apperrors.ErrNotFound) {
+		return nil, err
+	}
+
+	preview, err := s.prQueries.PreviewAssignment(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookShadowReviewersTotal.WithLabelValues(provider, string(preview.Strategy)).Add(float64(len(preview.ReviewerIDs)))
+
+	log.Info("shadow assignment computed", slog.String("pull_request_id", prID), slog.String("author_id", authorID),
+		slog.Int("team_id", preview.TeamID), slog.Any("reviewers", preview.ReviewerIDs),
+		slog.String("strategy", string(preview.Strategy)), slog.Int("missing", preview.Missing))
+
+	return &shadowPreview{preview: preview}, nil
+}
+
+// countShadowEvent counts a delivery of provider handled in shadow mode.
+func countShadowEvent(provider, outcome string) {
+	webhookShadowEventsTotal.WithLabelValues(provider, outcome).Inc()
+}
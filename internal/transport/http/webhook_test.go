@@ -0,0 +1,131 @@
+package http
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YusovID/pr-reviewer-service/internal/apperrors"
+	"github.com/YusovID/pr-reviewer-service/internal/service"
+	"github.com/YusovID/pr-reviewer-service/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestServer_AdminWebhooks(t *testing.T) {
+	createdAt := time.Date(2025, time.December, 22, 18, 0, 0, 0, time.UTC)
+	secret := "0123456789abcdef"
+	total := 1
+	webhook := api.Webhook{
+		WebhookId: 7, Url: "https://ci.example.com/hook", Events: []api.WebhookEvent{api.WebhookPRCreated},
+		CreatedAt: createdAt, UpdatedAt: createdAt,
+	}
+
+	webhooksMock := new(WebhookServiceMock)
+	webhooksMock.On("CreateWebhook", mock.Anything, service.WebhookInput{
+		URL: "https://ci.example.com/hook", Events: []string{"pr.created"}, Secret: &secret,
+	}).Return(&webhook, nil).Once()
+	webhooksMock.On("CreateWebhook", mock.Anything, service.WebhookInput{URL: "https://ci.example.com/hook", Events: []string{"pr.created"}}).
+		Return(nil, fmt.Errorf("%w: secret is required", apperrors.ErrValidation)).Once()
+	webhooksMock.On("ListWebhooks", mock.Anything).Return(&api.ListWebhooksResponse{Items: []api.Webhook{webhook}, TotalEstimate: &total}, nil).Once()
+	webhooksMock.On("UpdateWebhook", mock.Anything, int64(7), service.WebhookInput{URL: "https://ci.example.com/hook", Events: []string{"pr.created"}}).
+		Return(&webhook, nil).Once()
+	webhooksMock.On("GetWebhook", mock.Anything, int64(8)).Return(nil, apperrors.ErrNotFound).Once()
+	webhooksMock.On("DeleteWebhook", mock.Anything, int64(7)).Return(nil).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithWebhooks(webhooksMock))
+	router := api.Handler(server)
+
+	expectedWebhook := `{"webhook_id":7,"url":"https://ci.example.com/hook","events":["pr.created"],
+		"created_at":"2025-12-22T18:00:00Z","updated_at":"2025-12-22T18:00:00Z"}`
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/webhooks", strings.NewReader(
+		`{"url":"https://ci.example.com/hook","events":["pr.created"],"secret":"0123456789abcdef"}`)))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.JSONEq(t, `{"webhook":`+expectedWebhook+`}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/webhooks", strings.NewReader(
+		`{"url":"https://ci.example.com/hook","events":["pr.created"]}`)))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/webhooks", strings.NewReader(
+		`{"url":"https://ci.example.com/hook","events":["pr.created"],"secret":"short"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code, "a short secret is rejected before the service")
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/webhooks", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"items":[`+expectedWebhook+`],"next_cursor":null,"total_estimate":1}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/admin/webhooks/7", strings.NewReader(
+		`{"url":"https://ci.example.com/hook","events":["pr.created"]}`)))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/webhooks/8", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/admin/webhooks/7", nil))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Empty(t, rr.Body.String())
+	webhooksMock.AssertExpectations(t)
+}
+
+func TestServer_AdminWebhookDeliveries(t *testing.T) {
+	createdAt := time.Date(2025, time.December, 22, 18, 0, 0, 0, time.UTC)
+	lastError := "webhook responded with status 500"
+	responseStatus := http.StatusInternalServerError
+	next := "MTA"
+	delivery := api.WebhookDelivery{
+		DeliveryId: 11, WebhookId: 7, Event: api.WebhookPRMerged, PullRequestId: "pr-1", Status: api.WebhookDeliveryDead,
+		Attempts: 8, NextAttemptAt: createdAt, LastError: &lastError, ResponseStatus: &responseStatus,
+		CreatedAt: createdAt, UpdatedAt: createdAt,
+	}
+
+	webhooksMock := new(WebhookServiceMock)
+	webhooksMock.On("ListWebhookDeliveries", mock.Anything, service.WebhookDeliveryQuery{WebhookID: 7, Status: "dead", Limit: 1}).
+		Return(&api.ListWebhookDeliveriesResponse{Items: []api.WebhookDelivery{delivery}, NextCursor: &next}, nil).Once()
+	webhooksMock.On("RedeliverWebhookDelivery", mock.Anything, int64(7), int64(11)).Return(&delivery, nil).Once()
+	webhooksMock.On("RedeliverWebhookDelivery", mock.Anything, int64(7), int64(12)).
+		Return(nil, fmt.Errorf("%w: delivery 12", apperrors.ErrDeliveryNotDead)).Once()
+
+	server := NewServer(slog.New(slog.NewJSONHandler(os.Stdout, nil)), nil, nil, nil, WithWebhooks(webhooksMock))
+	router := api.Handler(server)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/webhooks/7/deliveries?status=dead&limit=1", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"items":[{"delivery_id":11,"webhook_id":7,"event":"pr.merged","pull_request_id":"pr-1","status":"dead",
+		"attempts":8,"next_attempt_at":"2025-12-22T18:00:00Z","last_error":"webhook responded with status 500","response_status":500,
+		"created_at":"2025-12-22T18:00:00Z","updated_at":"2025-12-22T18:00:00Z"}],"next_cursor":"MTA"}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/webhooks/7/deliveries/11/redeliver", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/webhooks/7/deliveries/12/redeliver", nil))
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"DELIVERY_NOT_DEAD"`)
+	webhooksMock.AssertExpectations(t)
+}
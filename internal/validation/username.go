@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalizer is implemented by requests that bring their fields to a canonical form.
+// ValidateStruct normalizes such requests before it checks them.
+type Normalizer interface {
+	Normalize()
+}
+
+// NormalizeUsername trims the white space around a username and brings it to Unicode NFC,
+// so that a name typed with a precomposed letter ("й") and with a combining mark ("и" + U+0306)
+// is stored, compared and ordered as the same string.
+func NormalizeUsername(name string) string {
+	return norm.NFC.String(strings.TrimSpace(name))
+}
+
+// UsernameKey returns the form in which normalized usernames are compared case-insensitively.
+// It folds case with the Unicode rules, so that e.g. "ИВАН" and "иван" share a key.
+func UsernameKey(name string) string {
+	return cases.Fold().String(name)
+}
+
+// isPrintableUsername reports whether name is valid UTF-8 without control or format characters,
+// which are invisible and would make two usernames that look the same differ.
+func isPrintableUsername(name string) bool {
+	if !utf8.ValidString(name) {
+		return false
+	}
+
+	return !strings.ContainsFunc(name, func(r rune) bool {
+		return unicode.IsControl(r) || unicode.Is(unicode.Cf, r)
+	})
+}
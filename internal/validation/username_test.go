@@ -0,0 +1,75 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeUsername(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Already normalized", input: "Алексей", expected: "Алексей"},
+		{name: "Surrounding spaces", input: " \tМария Иванова\n", expected: "Мария Иванова"},
+		{name: "Combining breve", input: "Андреи\u0306", expected: "Андрей"},
+		{name: "Combining diaeresis", input: "Фе\u0308дор", expected: "Фёдор"},
+		{name: "Latin with accent", input: " Jose\u0301 ", expected: "José"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NormalizeUsername(tc.input))
+		})
+	}
+}
+
+func TestUsernameKey(t *testing.T) {
+	assert.Equal(t, UsernameKey("иван"), UsernameKey("ИВАН"))
+	assert.Equal(t, UsernameKey("Straße"), UsernameKey("STRASSE"))
+	assert.NotEqual(t, UsernameKey("Иван"), UsernameKey("Ivan"))
+}
+
+type usernameRequest struct {
+	Username string `validate:"required,username,min=2"`
+}
+
+func (r *usernameRequest) Normalize() {
+	r.Username = NormalizeUsername(r.Username)
+}
+
+func TestValidateStruct_Username(t *testing.T) {
+	testCases := []struct {
+		name             string
+		input            string
+		expected         string
+		expectedErrorMsg string
+	}{
+		{name: "Success: Cyrillic username", input: "Ёжик", expected: "Ёжик"},
+		{name: "Success: Normalized before validation", input: "  Ии\u0306  ", expected: "Ий"},
+		{name: "Failure: Only spaces", input: "   ", expectedErrorMsg: "field 'Username' failed on the 'required' tag"},
+		{name: "Failure: Control character", input: "Анна\x07", expectedErrorMsg: "field 'Username' must not contain control or invisible characters"},
+		{name: "Failure: Zero-width space", input: "Ан\u200bна", expectedErrorMsg: "field 'Username' must not contain control or invisible characters"},
+		{name: "Failure: Invalid UTF-8", input: "Ан\xffна", expectedErrorMsg: "field 'Username' must not contain control or invisible characters"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &usernameRequest{Username: tc.input}
+
+			err := ValidateStruct(req)
+			if tc.expectedErrorMsg != "" {
+				require.IsType(t, &ValidationError{}, err)
+				assert.Contains(t, err.Error(), tc.expectedErrorMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, req.Username)
+		})
+	}
+}
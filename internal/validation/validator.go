@@ -33,11 +33,28 @@ func init() {
 		// as it indicates a critical startup failure.
 		panic(fmt.Sprintf("failed to register custom validation: %v", err))
 	}
+
+	// The "username" tag rejects usernames with invisible characters; see isPrintableUsername.
+	err = validate.RegisterValidation("username", func(fl validator.FieldLevel) bool {
+		return isPrintableUsername(fl.Field().String())
+	})
+	if err != nil {
+		panic(fmt.Sprintf("failed to register custom validation: %v", err))
+	}
+}
+
+// FieldError identifies a field that failed validation and the tag it failed on.
+// Field is the name of the struct field, with the index of a slice or map element, e.g. "Members[2]".
+type FieldError struct {
+	Field string
+	Tag   string
 }
 
 // ValidationError is a custom error type that holds a slice of validation error messages.
+// Fields holds the failures behind the messages, in the same order.
 type ValidationError struct {
 	Errors []string
+	Fields []FieldError
 }
 
 // Error returns a single string concatenating all validation error messages.
@@ -46,10 +63,18 @@ func (v *ValidationError) Error() string {
 }
 
 // ValidateStruct performs validation on a given struct based on its validation tags.
+// A struct implementing Normalizer is normalized in place first.
 // If validation fails, it returns a *ValidationError with user-friendly messages.
 func ValidateStruct(s interface{}) error {
+	if n, ok := s.(Normalizer); ok {
+		n.Normalize()
+	}
+
 	if err := validate.Struct(s); err != nil {
-		var validationErrors []string
+		var (
+			validationErrors []string
+			fields           []FieldError
+		)
 
 		// Cast the error to validator.ValidationErrors to iterate over individual field errors.
 		for _, err := range err.(validator.ValidationErrors) {
@@ -61,6 +86,10 @@ func ValidateStruct(s interface{}) error {
 					"field '%s' must contain only letters, numbers, hyphens, and underscores",
 					err.Field(),
 				)
+			case "username":
+				message = fmt.Sprintf("field '%s' must not contain control or invisible characters", err.Field())
+			case "isdefault":
+				message = fmt.Sprintf("field '%s' must be omitted", err.Field())
 			default:
 				// Default message for other standard validation tags like 'required', 'min', 'max', etc.
 				message = fmt.Sprintf(
@@ -70,9 +99,10 @@ func ValidateStruct(s interface{}) error {
 				)
 			}
 			validationErrors = append(validationErrors, message)
+			fields = append(fields, FieldError{Field: err.Field(), Tag: err.Tag()})
 		}
 
-		return &ValidationError{Errors: validationErrors}
+		return &ValidationError{Errors: validationErrors, Fields: fields}
 	}
 
 	return nil
@@ -87,6 +87,19 @@ func TestValidateStruct(t *testing.T) {
 	}
 }
 
+func TestValidateStruct_Fields(t *testing.T) {
+	err := ValidateStruct(TestStruct{ID: "invalid id", Email: "not-an-email"})
+	require.IsType(t, &ValidationError{}, err)
+
+	verr := err.(*ValidationError)
+	assert.Equal(t, []FieldError{
+		{Field: "ID", Tag: "custom_id"},
+		{Field: "Name", Tag: "required"},
+		{Field: "Email", Tag: "email"},
+	}, verr.Fields)
+	assert.Len(t, verr.Errors, len(verr.Fields))
+}
+
 func TestValidationError_Error(t *testing.T) {
 	err := &ValidationError{
 		Errors: []string{"error 1", "error 2"},
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_assignment_history_strategy;
+DROP INDEX IF EXISTS idx_assignment_history_pr_id;
+
+DROP TABLE IF EXISTS assignment_history;
+DROP TABLE IF EXISTS team_policies;
\ No newline at end of file
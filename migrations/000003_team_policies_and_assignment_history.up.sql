@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS team_policies (
+    team_id INT PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    strategy_weights JSONB NOT NULL DEFAULT '{}'::jsonb,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS assignment_history (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    replaced_user_id VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    strategy VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_history_pr_id ON assignment_history (pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_assignment_history_strategy ON assignment_history (strategy);
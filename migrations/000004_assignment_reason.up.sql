@@ -0,0 +1,7 @@
+ALTER TABLE assignment_history ADD COLUMN IF NOT EXISTS reason VARCHAR(50);
+
+UPDATE assignment_history SET reason = strategy WHERE reason IS NULL;
+
+ALTER TABLE assignment_history ALTER COLUMN reason SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_assignment_history_pr_user ON assignment_history (pull_request_id, user_id, id DESC);
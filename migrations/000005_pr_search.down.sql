@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pull_requests_search_vector;
+
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS search_vector;
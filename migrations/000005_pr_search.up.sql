@@ -0,0 +1,5 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', name)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_search_vector ON pull_requests USING GIN (search_vector);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_pull_requests_search_vector;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE pull_requests
+    ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', name)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_search_vector ON pull_requests USING GIN (search_vector);
+
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS external_url;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS description;
@@ -0,0 +1,15 @@
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS description TEXT;
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS external_url VARCHAR(2048);
+
+-- A generated column cannot be redefined in place, so the search vector is rebuilt to cover descriptions.
+DROP INDEX IF EXISTS idx_pull_requests_search_vector;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE pull_requests
+    ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', name), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_search_vector ON pull_requests USING GIN (search_vector);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_pull_requests_open_author;
+
+ALTER TABLE team_policies DROP COLUMN IF EXISTS over_quota_action;
+ALTER TABLE team_policies DROP COLUMN IF EXISTS author_open_pr_limit;
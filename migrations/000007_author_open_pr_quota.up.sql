@@ -0,0 +1,6 @@
+ALTER TABLE team_policies
+    ADD COLUMN IF NOT EXISTS author_open_pr_limit INT CHECK (author_open_pr_limit > 0),
+    ADD COLUMN IF NOT EXISTS over_quota_action VARCHAR(16) NOT NULL DEFAULT 'reject'
+        CHECK (over_quota_action IN ('reject', 'queue'));
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_open_author ON pull_requests (author_id) WHERE status = 'OPEN';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_pending_assignments_team_id;
+DROP INDEX IF EXISTS idx_pending_assignments_order;
+
+DROP TABLE IF EXISTS pending_assignments;
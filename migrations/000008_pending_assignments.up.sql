@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS pending_assignments (
+    pull_request_id VARCHAR(255) PRIMARY KEY REFERENCES pull_requests(id) ON DELETE CASCADE,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    priority INT NOT NULL CHECK (priority > 0),
+    enqueued_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_assignments_order ON pending_assignments (priority DESC, enqueued_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pending_assignments_team_id ON pending_assignments (team_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_reviewer_borrows_lender_team_id;
+DROP INDEX IF EXISTS idx_reviewer_borrows_team_id;
+
+DROP TABLE IF EXISTS borrowed_reviewers;
+DROP TABLE IF EXISTS reviewer_borrows;
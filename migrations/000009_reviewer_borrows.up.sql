@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS reviewer_borrows (
+    id BIGSERIAL PRIMARY KEY,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    lender_team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    reviewer_count INT NOT NULL CHECK (reviewer_count > 0),
+    duration_hours INT NOT NULL CHECK (duration_hours > 0),
+    status VARCHAR(50) NOT NULL CHECK (status IN ('requested', 'accepted')) DEFAULT 'requested',
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    accepted_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+    CHECK (team_id <> lender_team_id)
+);
+
+CREATE TABLE IF NOT EXISTS borrowed_reviewers (
+    borrow_id BIGINT NOT NULL REFERENCES reviewer_borrows(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (borrow_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviewer_borrows_team_id ON reviewer_borrows (team_id, expires_at);
+CREATE INDEX IF NOT EXISTS idx_reviewer_borrows_lender_team_id ON reviewer_borrows (lender_team_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_pr_create_requests_unfinished;
+DROP TABLE IF EXISTS pr_create_requests;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS pr_create_requests (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL,
+    pull_request_name VARCHAR(255) NOT NULL,
+    author_id VARCHAR(255) NOT NULL,
+    description TEXT,
+    external_url TEXT,
+    status VARCHAR(50) NOT NULL CHECK (status IN ('queued', 'processing', 'succeeded', 'failed')) DEFAULT 'queued',
+    attempts INT NOT NULL DEFAULT 0,
+    error_code VARCHAR(50),
+    error_message TEXT,
+    enqueued_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_create_requests_unfinished ON pr_create_requests (id) WHERE status IN ('queued', 'processing');
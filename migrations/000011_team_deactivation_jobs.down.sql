@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_team_deactivation_warnings_job_id;
+DROP INDEX IF EXISTS idx_team_deactivation_batches_pending;
+
+DROP TABLE IF EXISTS team_deactivation_warnings;
+DROP TABLE IF EXISTS team_deactivation_prs;
+DROP TABLE IF EXISTS team_deactivation_batches;
+DROP TABLE IF EXISTS team_deactivation_users;
+DROP TABLE IF EXISTS team_deactivation_jobs;
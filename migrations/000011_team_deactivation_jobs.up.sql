@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS team_deactivation_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL CHECK (status IN ('running', 'succeeded')) DEFAULT 'running',
+    batch_size INT NOT NULL CHECK (batch_size > 0),
+    total_batches INT NOT NULL DEFAULT 0,
+    done_batches INT NOT NULL DEFAULT 0,
+    total_prs INT NOT NULL DEFAULT 0,
+    reassigned_reviews INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS team_deactivation_users (
+    job_id BIGINT NOT NULL REFERENCES team_deactivation_jobs(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (job_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS team_deactivation_batches (
+    job_id BIGINT NOT NULL REFERENCES team_deactivation_jobs(id) ON DELETE CASCADE,
+    batch_no INT NOT NULL,
+    status VARCHAR(50) NOT NULL CHECK (status IN ('pending', 'done')) DEFAULT 'pending',
+    finished_at TIMESTAMPTZ,
+    PRIMARY KEY (job_id, batch_no)
+);
+
+CREATE TABLE IF NOT EXISTS team_deactivation_prs (
+    job_id BIGINT NOT NULL,
+    batch_no INT NOT NULL,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    PRIMARY KEY (job_id, batch_no, pull_request_id),
+    FOREIGN KEY (job_id, batch_no) REFERENCES team_deactivation_batches(job_id, batch_no) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS team_deactivation_warnings (
+    id BIGSERIAL PRIMARY KEY,
+    job_id BIGINT NOT NULL REFERENCES team_deactivation_jobs(id) ON DELETE CASCADE,
+    message TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_deactivation_batches_pending ON team_deactivation_batches (job_id, batch_no) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_team_deactivation_warnings_job_id ON team_deactivation_warnings (job_id);
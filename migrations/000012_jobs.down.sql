@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_jobs_unfinished;
+
+DROP TABLE IF EXISTS jobs;
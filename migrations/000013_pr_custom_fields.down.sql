@@ -0,0 +1,4 @@
+ALTER TABLE pr_create_requests DROP COLUMN IF EXISTS custom_fields;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS custom_fields;
+
+DROP TABLE IF EXISTS team_custom_fields;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS team_custom_fields (
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    key VARCHAR(64) NOT NULL,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('string', 'number', 'boolean')),
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (team_id, key)
+);
+
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE pr_create_requests ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS reviewers_not_author ON reviewers;
+
+DROP FUNCTION IF EXISTS reviewers_reject_author();
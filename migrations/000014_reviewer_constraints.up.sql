@@ -0,0 +1,19 @@
+-- The primary key of reviewers already keeps a user from reviewing the same pull request twice.
+-- A check constraint cannot look at pull_requests, so the author is rejected by a trigger.
+-- Like a NOT VALID constraint, it checks only the rows written from now on.
+CREATE OR REPLACE FUNCTION reviewers_reject_author() RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pull_requests WHERE id = NEW.pull_request_id AND author_id = NEW.user_id) THEN
+        RAISE EXCEPTION 'user % is the author of pull request %', NEW.user_id, NEW.pull_request_id
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'reviewers_not_author';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS reviewers_not_author ON reviewers;
+
+CREATE TRIGGER reviewers_not_author
+    BEFORE INSERT OR UPDATE ON reviewers
+    FOR EACH ROW EXECUTE FUNCTION reviewers_reject_author();
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_pull_requests_created_at;
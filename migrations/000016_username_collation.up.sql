@@ -0,0 +1,2 @@
+UPDATE users SET username = normalize(btrim(username), NFC) WHERE username IS DISTINCT FROM normalize(btrim(username), NFC);
+CREATE INDEX IF NOT EXISTS idx_users_team_username ON users (team_id, username COLLATE "und-x-icu");
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pr_subscriptions_user_id;
+
+DROP TABLE IF EXISTS pr_subscriptions;
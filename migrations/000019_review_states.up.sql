@@ -0,0 +1,4 @@
+ALTER TABLE reviewers
+    ADD COLUMN IF NOT EXISTS review_state VARCHAR(32) NOT NULL DEFAULT 'PENDING'
+        CHECK (review_state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED')),
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
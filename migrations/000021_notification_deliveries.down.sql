@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notification_deliveries_failed;
+DROP INDEX IF EXISTS idx_notification_deliveries_pull_request_id;
+DROP INDEX IF EXISTS idx_notification_deliveries_recipient_id;
+
+DROP TABLE IF EXISTS notification_deliveries;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    channel VARCHAR(50) NOT NULL,
+    recipient_id VARCHAR(255) NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    pull_request_id VARCHAR(255) NOT NULL,
+    status VARCHAR(50) NOT NULL CHECK (status IN ('delivered', 'failed')),
+    error TEXT,
+    attempts INT NOT NULL DEFAULT 1,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_recipient_id ON notification_deliveries (recipient_id, id);
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_pull_request_id ON notification_deliveries (pull_request_id, id);
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_failed ON notification_deliveries (id) WHERE status = 'failed';
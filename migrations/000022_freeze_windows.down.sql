@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_freeze_windows_ends_at;
+
+DROP TABLE IF EXISTS freeze_windows;
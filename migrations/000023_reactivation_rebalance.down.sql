@@ -0,0 +1 @@
+ALTER TABLE team_policies DROP COLUMN IF EXISTS reactivation_rebalance;
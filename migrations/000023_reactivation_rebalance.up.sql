@@ -0,0 +1,3 @@
+ALTER TABLE team_policies
+    ADD COLUMN IF NOT EXISTS reactivation_rebalance VARCHAR(16) NOT NULL DEFAULT 'off'
+        CHECK (reactivation_rebalance IN ('off', 'immediate', 'job'));
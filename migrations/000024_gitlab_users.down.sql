@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_gitlab_users_user_id;
+
+DROP TABLE IF EXISTS gitlab_users;
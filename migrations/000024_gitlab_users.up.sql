@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS gitlab_users (
+    gitlab_username VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_gitlab_users_user_id ON gitlab_users (user_id);
@@ -0,0 +1 @@
+ALTER TABLE assignment_history DROP COLUMN IF EXISTS handoff_note;
@@ -0,0 +1 @@
+ALTER TABLE assignment_history ADD COLUMN IF NOT EXISTS handoff_note TEXT;
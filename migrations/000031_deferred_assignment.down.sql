@@ -0,0 +1,2 @@
+ALTER TABLE pr_create_requests DROP COLUMN IF EXISTS assign_at;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS assign_at;
@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS assign_at TIMESTAMPTZ;
+ALTER TABLE pr_create_requests ADD COLUMN IF NOT EXISTS assign_at TIMESTAMPTZ;
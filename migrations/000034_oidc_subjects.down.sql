@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_oidc_subjects_user_id;
+
+DROP TABLE IF EXISTS oidc_subjects;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS oidc_subjects (
+    subject VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_oidc_subjects_user_id ON oidc_subjects (user_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_assignment_history_pr_timeline;
+
+ALTER TABLE assignment_history DROP COLUMN IF EXISTS cause;
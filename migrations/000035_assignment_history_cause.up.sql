@@ -0,0 +1,12 @@
+ALTER TABLE assignment_history ADD COLUMN IF NOT EXISTS cause VARCHAR(50);
+
+-- Reviewers assigned on creation are recorded at the creation time of the pull request, later ones come from the queue.
+UPDATE assignment_history h
+SET cause = CASE WHEN h.created_at = p.created_at THEN 'created' ELSE 'pending' END
+FROM pull_requests p
+WHERE p.id = h.pull_request_id AND h.cause IS NULL AND h.replaced_user_id IS NULL;
+
+-- The cause of the other earlier replacements stays unknown: a reassignment and a deactivation look the same.
+UPDATE assignment_history SET cause = 'rebalance' WHERE cause IS NULL AND reason = 'rebalance';
+
+CREATE INDEX IF NOT EXISTS idx_assignment_history_pr_timeline ON assignment_history (pull_request_id, created_at, id);
@@ -0,0 +1,3 @@
+ALTER TABLE reviewers
+    DROP COLUMN IF EXISTS first_reviewed_at,
+    DROP COLUMN IF EXISTS assigned_at;
@@ -0,0 +1,18 @@
+ALTER TABLE reviewers
+    ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS first_reviewed_at TIMESTAMPTZ;
+
+-- A reviewer was assigned by their latest assignment on record, or else with the pull request.
+UPDATE reviewers r
+SET assigned_at = COALESCE(
+    (SELECT MAX(h.created_at) FROM assignment_history h WHERE h.pull_request_id = r.pull_request_id AND h.user_id = r.user_id),
+    p.created_at)
+FROM pull_requests p
+WHERE p.id = r.pull_request_id AND r.assigned_at IS NULL;
+
+-- Only the latest decision was kept so far; it is the best guess of the first one.
+UPDATE reviewers SET first_reviewed_at = reviewed_at WHERE first_reviewed_at IS NULL;
+
+ALTER TABLE reviewers
+    ALTER COLUMN assigned_at SET DEFAULT NOW(),
+    ALTER COLUMN assigned_at SET NOT NULL;
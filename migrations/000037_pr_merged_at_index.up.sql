@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_pull_requests_merged_at ON pull_requests (merged_at) WHERE status = 'MERGED';
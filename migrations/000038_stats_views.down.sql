@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS stats_refresh;
+DROP MATERIALIZED VIEW IF EXISTS team_review_stats;
+DROP MATERIALIZED VIEW IF EXISTS user_review_stats;
@@ -0,0 +1,50 @@
+-- The all-time review statistics are precomputed, so that reading them does not join every pull request.
+-- The views mirror the unbounded statsQuery and GetTeamStats queries of the repository and are refreshed
+-- by a background job; the unique indexes let them be refreshed concurrently with the reads.
+CREATE MATERIALIZED VIEW IF NOT EXISTS user_review_stats AS
+SELECT
+    u.id AS user_id,
+    u.username,
+    COUNT(CASE WHEN pr.status = 'OPEN' THEN 1 END) AS open_reviews,
+    COUNT(CASE WHEN pr.status = 'MERGED' THEN 1 END) AS merged_reviews,
+    COUNT(CASE WHEN r.first_reviewed_at IS NOT NULL THEN 1 END) AS first_reviews,
+    AVG(CASE WHEN r.first_reviewed_at IS NOT NULL THEN EXTRACT(EPOCH FROM r.first_reviewed_at - r.assigned_at) END)::float8 AS first_review_avg_seconds,
+    percentile_cont(0.5) WITHIN GROUP (ORDER BY CASE WHEN r.first_reviewed_at IS NOT NULL THEN EXTRACT(EPOCH FROM r.first_reviewed_at - r.assigned_at) END) AS first_review_p50_seconds,
+    percentile_cont(0.9) WITHIN GROUP (ORDER BY CASE WHEN r.first_reviewed_at IS NOT NULL THEN EXTRACT(EPOCH FROM r.first_reviewed_at - r.assigned_at) END) AS first_review_p90_seconds,
+    AVG(CASE WHEN pr.status = 'MERGED' THEN EXTRACT(EPOCH FROM pr.merged_at - r.assigned_at) END)::float8 AS merge_avg_seconds,
+    percentile_cont(0.5) WITHIN GROUP (ORDER BY CASE WHEN pr.status = 'MERGED' THEN EXTRACT(EPOCH FROM pr.merged_at - r.assigned_at) END) AS merge_p50_seconds,
+    percentile_cont(0.9) WITHIN GROUP (ORDER BY CASE WHEN pr.status = 'MERGED' THEN EXTRACT(EPOCH FROM pr.merged_at - r.assigned_at) END) AS merge_p90_seconds
+FROM users u
+LEFT JOIN reviewers r ON u.id = r.user_id
+LEFT JOIN pull_requests pr ON r.pull_request_id = pr.id
+GROUP BY u.id, u.username;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_review_stats_user_id ON user_review_stats (user_id);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS team_review_stats AS
+SELECT
+    t.name AS team_name,
+    COUNT(fr.first_reviewed_at) AS first_reviewed_prs,
+    AVG(EXTRACT(EPOCH FROM fr.first_reviewed_at - pr.created_at))::float8 AS first_review_avg_seconds,
+    percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM fr.first_reviewed_at - pr.created_at)) AS first_review_p50_seconds,
+    percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM fr.first_reviewed_at - pr.created_at)) AS first_review_p90_seconds,
+    COUNT(CASE WHEN pr.status = 'MERGED' THEN 1 END) AS merged_prs,
+    AVG(CASE WHEN pr.status = 'MERGED' THEN EXTRACT(EPOCH FROM pr.merged_at - pr.created_at) END)::float8 AS merge_avg_seconds,
+    percentile_cont(0.5) WITHIN GROUP (ORDER BY CASE WHEN pr.status = 'MERGED' THEN EXTRACT(EPOCH FROM pr.merged_at - pr.created_at) END) AS merge_p50_seconds,
+    percentile_cont(0.9) WITHIN GROUP (ORDER BY CASE WHEN pr.status = 'MERGED' THEN EXTRACT(EPOCH FROM pr.merged_at - pr.created_at) END) AS merge_p90_seconds
+FROM pull_requests pr
+JOIN users u ON u.id = pr.author_id
+JOIN teams t ON t.id = u.team_id
+LEFT JOIN (SELECT pull_request_id, MIN(first_reviewed_at) AS first_reviewed_at FROM reviewers GROUP BY pull_request_id) fr ON fr.pull_request_id = pr.id
+WHERE fr.first_reviewed_at IS NOT NULL OR pr.status = 'MERGED'
+GROUP BY t.name;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_team_review_stats_team_name ON team_review_stats (team_name);
+
+-- The single row records when the views were last refreshed; they are populated on creation.
+CREATE TABLE IF NOT EXISTS stats_refresh (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    refreshed_at TIMESTAMPTZ NOT NULL
+);
+
+INSERT INTO stats_refresh (refreshed_at) VALUES (NOW()) ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_assignment_history_replaced_user_id;
+DROP INDEX IF EXISTS idx_team_deactivations_team_id;
+
+DROP TABLE IF EXISTS team_deactivation_members;
+DROP TABLE IF EXISTS team_deactivations;
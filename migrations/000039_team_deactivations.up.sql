@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS team_deactivations (
+    id BIGSERIAL PRIMARY KEY,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    -- job_id is set for a batched deactivation, whose reviews are reassigned by the job.
+    job_id BIGINT REFERENCES team_deactivation_jobs(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reactivated_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS team_deactivation_members (
+    deactivation_id BIGINT NOT NULL REFERENCES team_deactivations(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (deactivation_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_deactivations_team_id ON team_deactivations (team_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_assignment_history_replaced_user_id ON assignment_history (replaced_user_id, created_at)
+    WHERE replaced_user_id IS NOT NULL;
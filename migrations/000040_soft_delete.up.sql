@@ -0,0 +1,52 @@
+-- Deleted teams and users are kept with the time of their deletion, so that they can be restored.
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- The name of a deleted team can be taken by a new one.
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_teams_name ON teams (name) WHERE deleted_at IS NULL;
+
+-- The statistics views leave the deleted teams and users out like the queries of the repository.
+DROP MATERIALIZED VIEW IF EXISTS team_review_stats;
+DROP MATERIALIZED VIEW IF EXISTS user_review_stats;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS user_review_stats AS
+SELECT
+    u.id AS user_id,
+    u.username,
+    COUNT(CASE WHEN pr.status = 'OPEN' THEN 1 END) AS open_reviews,
+    COUNT(CASE WHEN pr.status = 'MERGED' THEN 1 END) AS merged_reviews,
+    COUNT(CASE WHEN r.first_reviewed_at IS NOT NULL THEN 1 END) AS first_reviews,
+    AVG(CASE WHEN r.first_reviewed_at IS NOT NULL THEN EXTRACT(EPOCH FROM r.first_reviewed_at - r.assigned_at) END)::float8 AS first_review_avg_seconds,
+    percentile_cont(0.5) WITHIN GROUP (ORDER BY CASE WHEN r.first_reviewed_at IS NOT NULL THEN EXTRACT(EPOCH FROM r.first_reviewed_at - r.assigned_at) END) AS first_review_p50_seconds,
+    percentile_cont(0.9) WITHIN GROUP (ORDER BY CASE WHEN r.first_reviewed_at IS NOT NULL THEN EXTRACT(EPOCH FROM r.first_reviewed_at - r.assigned_at) END) AS first_review_p90_seconds,
+    AVG(CASE WHEN pr.status = 'MERGED' THEN EXTRACT(EPOCH FROM pr.merged_at - r.assigned_at) END)::float8 AS merge_avg_seconds,
+    percentile_cont(0.5) WITHIN GROUP (ORDER BY CASE WHEN pr.status = 'MERGED' THEN EXTRACT(EPOCH FROM pr.merged_at - r.assigned_at) END) AS merge_p50_seconds,
+    percentile_cont(0.9) WITHIN GROUP (ORDER BY CASE WHEN pr.status = 'MERGED' THEN EXTRACT(EPOCH FROM pr.merged_at - r.assigned_at) END) AS merge_p90_seconds
+FROM users u
+LEFT JOIN reviewers r ON u.id = r.user_id
+LEFT JOIN pull_requests pr ON r.pull_request_id = pr.id
+WHERE u.deleted_at IS NULL
+GROUP BY u.id, u.username;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_review_stats_user_id ON user_review_stats (user_id);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS team_review_stats AS
+SELECT
+    t.name AS team_name,
+    COUNT(fr.first_reviewed_at) AS first_reviewed_prs,
+    AVG(EXTRACT(EPOCH FROM fr.first_reviewed_at - pr.created_at))::float8 AS first_review_avg_seconds,
+    percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM fr.first_reviewed_at - pr.created_at)) AS first_review_p50_seconds,
+    percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM fr.first_reviewed_at - pr.created_at)) AS first_review_p90_seconds,
+    COUNT(CASE WHEN pr.status = 'MERGED' THEN 1 END) AS merged_prs,
+    AVG(CASE WHEN pr.status = 'MERGED' THEN EXTRACT(EPOCH FROM pr.merged_at - pr.created_at) END)::float8 AS merge_avg_seconds,
+    percentile_cont(0.5) WITHIN GROUP (ORDER BY CASE WHEN pr.status = 'MERGED' THEN EXTRACT(EPOCH FROM pr.merged_at - pr.created_at) END) AS merge_p50_seconds,
+    percentile_cont(0.9) WITHIN GROUP (ORDER BY CASE WHEN pr.status = 'MERGED' THEN EXTRACT(EPOCH FROM pr.merged_at - pr.created_at) END) AS merge_p90_seconds
+FROM pull_requests pr
+JOIN users u ON u.id = pr.author_id
+JOIN teams t ON t.id = u.team_id
+LEFT JOIN (SELECT pull_request_id, MIN(first_reviewed_at) AS first_reviewed_at FROM reviewers GROUP BY pull_request_id) fr ON fr.pull_request_id = pr.id
+WHERE (fr.first_reviewed_at IS NOT NULL OR pr.status = 'MERGED') AND u.deleted_at IS NULL AND t.deleted_at IS NULL
+GROUP BY t.name;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_team_review_stats_team_name ON team_review_stats (team_name);
@@ -0,0 +1,5 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_primary_team_membership;
+
+DROP INDEX IF EXISTS idx_user_teams_team_id;
+
+DROP TABLE IF EXISTS user_teams;
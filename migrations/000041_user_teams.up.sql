@@ -0,0 +1,19 @@
+-- A user reviews for every team the user is a member of. users.team_id stays the primary team of the user:
+-- the team whose reviewers are picked for the user's pull requests and whose statistics they count toward.
+-- Dropping the column in favor of user_teams alone is out of the scope of this migration.
+CREATE TABLE IF NOT EXISTS user_teams (
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, team_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_teams_team_id ON user_teams (team_id, user_id);
+
+INSERT INTO user_teams (user_id, team_id)
+SELECT id, team_id FROM users WHERE team_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+-- The primary team is one of the memberships of the user. The check is deferred to the commit, so that
+-- a transaction may change the primary team and the memberships in any order.
+ALTER TABLE users ADD CONSTRAINT users_primary_team_membership
+    FOREIGN KEY (id, team_id) REFERENCES user_teams (user_id, team_id) DEFERRABLE INITIALLY DEFERRED;
@@ -18,290 +18,4839 @@ const (
 	UserTokenScopes  = "UserToken.Scopes"
 )
 
+// Defines values for ApiKeyScope.
+const (
+	ApiKeyScopeAdmin ApiKeyScope = "admin"
+	ApiKeyScopeRead  ApiKeyScope = "read"
+	ApiKeyScopeWrite ApiKeyScope = "write"
+)
+
+// Defines values for AssignmentHistoryEntryCause.
+const (
+	CauseCreated         AssignmentHistoryEntryCause = "created"
+	CausePending         AssignmentHistoryEntryCause = "pending"
+	CauseReassign        AssignmentHistoryEntryCause = "reassign"
+	CauseRebalance       AssignmentHistoryEntryCause = "rebalance"
+	CauseTeamDeactivated AssignmentHistoryEntryCause = "team_deactivated"
+	CauseTeamReactivated AssignmentHistoryEntryCause = "team_reactivated"
+	CauseUserDeactivated AssignmentHistoryEntryCause = "user_deactivated"
+	CauseUserTransferred AssignmentHistoryEntryCause = "user_transferred"
+)
+
+// Defines values for AssignmentHistoryEntryReason.
+const (
+	HistoryReasonEscalation  AssignmentHistoryEntryReason = "escalation"
+	HistoryReasonLeastLoaded AssignmentHistoryEntryReason = "least_loaded"
+	HistoryReasonManual      AssignmentHistoryEntryReason = "manual"
+	HistoryReasonRandom      AssignmentHistoryEntryReason = "random"
+	HistoryReasonRebalance   AssignmentHistoryEntryReason = "rebalance"
+	HistoryReasonRestored    AssignmentHistoryEntryReason = "restored"
+	HistoryReasonRoundRobin  AssignmentHistoryEntryReason = "round_robin"
+	HistoryReasonTagMatch    AssignmentHistoryEntryReason = "tag_match"
+)
+
+// Defines values for AsyncCreateRequestStatus.
+const (
+	Failed     AsyncCreateRequestStatus = "failed"
+	Processing AsyncCreateRequestStatus = "processing"
+	Queued     AsyncCreateRequestStatus = "queued"
+	Succeeded  AsyncCreateRequestStatus = "succeeded"
+)
+
+// Defines values for CustomFieldType.
+const (
+	CustomFieldBoolean CustomFieldType = "boolean"
+	CustomFieldNumber  CustomFieldType = "number"
+	CustomFieldString  CustomFieldType = "string"
+)
+
+// Defines values for DeactivationJobStatus.
+const (
+	DeactivationJobRunning   DeactivationJobStatus = "running"
+	DeactivationJobSucceeded DeactivationJobStatus = "succeeded"
+)
+
 // Defines values for ErrorResponseErrorCode.
 const (
-	NOCANDIDATE ErrorResponseErrorCode = "NO_CANDIDATE"
-	NOTASSIGNED ErrorResponseErrorCode = "NOT_ASSIGNED"
-	NOTFOUND    ErrorResponseErrorCode = "NOT_FOUND"
-	PREXISTS    ErrorResponseErrorCode = "PR_EXISTS"
-	PRMERGED    ErrorResponseErrorCode = "PR_MERGED"
-	TEAMEXISTS  ErrorResponseErrorCode = "TEAM_EXISTS"
+	AUTHORINACTIVE         ErrorResponseErrorCode = "AUTHOR_INACTIVE"
+	AUTHORQUOTAEXCEEDED    ErrorResponseErrorCode = "AUTHOR_QUOTA_EXCEEDED"
+	BORROWNOTPENDING       ErrorResponseErrorCode = "BORROW_NOT_PENDING"
+	DEACTIVATIONINPROGRESS ErrorResponseErrorCode = "DEACTIVATION_IN_PROGRESS"
+	DELIVERYNOTDEAD        ErrorResponseErrorCode = "DELIVERY_NOT_DEAD"
+	DELIVERYNOTFAILED      ErrorResponseErrorCode = "DELIVERY_NOT_FAILED"
+	FORBIDDEN              ErrorResponseErrorCode = "FORBIDDEN"
+	FREEZE                 ErrorResponseErrorCode = "FREEZE"
+	INSUFFICIENTCAPACITY   ErrorResponseErrorCode = "INSUFFICIENT_CAPACITY"
+	INVALIDSIGNATURE       ErrorResponseErrorCode = "INVALID_SIGNATURE"
+	JOBFINISHED            ErrorResponseErrorCode = "JOB_FINISHED"
+	NOCANDIDATE            ErrorResponseErrorCode = "NO_CANDIDATE"
+	NOTAPPROVED            ErrorResponseErrorCode = "NOT_APPROVED"
+	NOTASSIGNED            ErrorResponseErrorCode = "NOT_ASSIGNED"
+	NOTFOUND               ErrorResponseErrorCode = "NOT_FOUND"
+	PAYLOADTOOLARGE        ErrorResponseErrorCode = "PAYLOAD_TOO_LARGE"
+	PRCLOSED               ErrorResponseErrorCode = "PR_CLOSED"
+	PREXISTS               ErrorResponseErrorCode = "PR_EXISTS"
+	PRMERGED               ErrorResponseErrorCode = "PR_MERGED"
+	RATELIMITED            ErrorResponseErrorCode = "RATE_LIMITED"
+	READONLY               ErrorResponseErrorCode = "READONLY"
+	TEAMEXISTS             ErrorResponseErrorCode = "TEAM_EXISTS"
+	TEAMTOOSMALL           ErrorResponseErrorCode = "TEAM_TOO_SMALL"
+	TIMEOUT                ErrorResponseErrorCode = "TIMEOUT"
+	UNAUTHORIZED           ErrorResponseErrorCode = "UNAUTHORIZED"
+)
+
+// Defines values for GitHubWebhookResultOutcome.
+const (
+	GitHubClosed    GitHubWebhookResultOutcome = "closed"
+	GitHubCreated   GitHubWebhookResultOutcome = "created"
+	GitHubDuplicate GitHubWebhookResultOutcome = "duplicate"
+	GitHubIgnored   GitHubWebhookResultOutcome = "ignored"
+	GitHubMerged    GitHubWebhookResultOutcome = "merged"
+)
+
+// Defines values for GitLabWebhookResultOutcome.
+const (
+	GitLabClosed    GitLabWebhookResultOutcome = "closed"
+	GitLabCreated   GitLabWebhookResultOutcome = "created"
+	GitLabDuplicate GitLabWebhookResultOutcome = "duplicate"
+	GitLabIgnored   GitLabWebhookResultOutcome = "ignored"
+	GitLabMerged    GitLabWebhookResultOutcome = "merged"
+	GitLabUnmapped  GitLabWebhookResultOutcome = "unmapped"
+)
+
+// Defines values for JobStatus.
+const (
+	JobCancelled JobStatus = "cancelled"
+	JobFailed    JobStatus = "failed"
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+)
+
+// Defines values for JobType.
+const (
+	JobTypePendingBackfill   JobType = "pending_backfill"
+	JobTypeReviewerRebalance JobType = "reviewer_rebalance"
+	JobTypeStatsBackfill     JobType = "stats_backfill"
+	JobTypeStatsExport       JobType = "stats_export"
+	JobTypeTeamDeactivation  JobType = "team_deactivation"
+	JobTypeTeamImport        JobType = "team_import"
+)
+
+// Defines values for LeaderboardPeriod.
+const (
+	LeaderboardMonth LeaderboardPeriod = "month"
+	LeaderboardWeek  LeaderboardPeriod = "week"
+)
+
+// Defines values for NotificationDeliveryStatus.
+const (
+	DeliveryDelivered NotificationDeliveryStatus = "delivered"
+	DeliveryFailed    NotificationDeliveryStatus = "failed"
+)
+
+// Defines values for NotificationEvent.
+const (
+	NotificationChangesRequested   NotificationEvent = "changes_requested"
+	NotificationPRClosed           NotificationEvent = "pr_closed"
+	NotificationPRMerged           NotificationEvent = "pr_merged"
+	NotificationReviewApproved     NotificationEvent = "review_approved"
+	NotificationReviewerReassigned NotificationEvent = "reviewer_reassigned"
+	NotificationReviewersAssigned  NotificationEvent = "reviewers_assigned"
+)
+
+// Defines values for PolicySource.
+const (
+	PolicySourceDefault PolicySource = "default"
+	PolicySourceTeam    PolicySource = "team"
 )
 
 // Defines values for PullRequestStatus.
 const (
+	PullRequestStatusCLOSED PullRequestStatus = "CLOSED"
 	PullRequestStatusMERGED PullRequestStatus = "MERGED"
 	PullRequestStatusOPEN   PullRequestStatus = "OPEN"
 )
 
 // Defines values for PullRequestShortStatus.
 const (
+	PullRequestShortStatusCLOSED PullRequestShortStatus = "CLOSED"
 	PullRequestShortStatusMERGED PullRequestShortStatus = "MERGED"
 	PullRequestShortStatusOPEN   PullRequestShortStatus = "OPEN"
 )
 
-// ErrorResponse defines model for ErrorResponse.
-type ErrorResponse struct {
-	Error struct {
-		Code    ErrorResponseErrorCode `json:"code"`
-		Message string                 `json:"message"`
-	} `json:"error"`
-}
+// Defines values for ReplacementAlternativeReason.
+const (
+	Inactive  ReplacementAlternativeReason = "inactive"
+	OtherTeam ReplacementAlternativeReason = "other_team"
+)
 
-// ErrorResponseErrorCode defines model for ErrorResponse.Error.Code.
-type ErrorResponseErrorCode string
+// Defines values for ReviewState.
+const (
+	APPROVED         ReviewState = "APPROVED"
+	CHANGESREQUESTED ReviewState = "CHANGES_REQUESTED"
+	PENDING          ReviewState = "PENDING"
+)
 
-// GetReviewResponse defines model for GetReviewResponse.
-type GetReviewResponse struct {
-	PullRequests []PullRequestShort `json:"pull_requests"`
-	UserId       string             `json:"user_id"`
-}
+// Defines values for ReviewerAssignmentReason.
+const (
+	Escalation  ReviewerAssignmentReason = "escalation"
+	LeastLoaded ReviewerAssignmentReason = "least_loaded"
+	Manual      ReviewerAssignmentReason = "manual"
+	Random      ReviewerAssignmentReason = "random"
+	Rebalance   ReviewerAssignmentReason = "rebalance"
+	Restored    ReviewerAssignmentReason = "restored"
+	RoundRobin  ReviewerAssignmentReason = "round_robin"
+	TagMatch    ReviewerAssignmentReason = "tag_match"
+)
 
-// PullRequest defines model for PullRequest.
-type PullRequest struct {
-	// AssignedReviewers user_id назначенных ревьюверов (0..2)
-	AssignedReviewers []string `json:"assigned_reviewers"`
+// Defines values for ReviewerBorrowStatus.
+const (
+	Accepted  ReviewerBorrowStatus = "accepted"
+	Requested ReviewerBorrowStatus = "requested"
+)
 
-	// AuthorId Идентификатор автора. Допускаются буквы, цифры, дефисы и подчеркивания.
-	AuthorId  string     `json:"author_id"`
-	CreatedAt *time.Time `json:"createdAt"`
-	MergedAt  *time.Time `json:"mergedAt"`
+// Defines values for TeamPolicyOverQuotaAction.
+const (
+	Queue  TeamPolicyOverQuotaAction = "queue"
+	Reject TeamPolicyOverQuotaAction = "reject"
+)
 
-	// PullRequestId Идентификатор PR. Допускаются буквы, цифры, дефисы и подчеркивания.
-	PullRequestId   string            `json:"pull_request_id"`
-	PullRequestName string            `json:"pull_request_name"`
-	Status          PullRequestStatus `json:"status"`
-}
+// Defines values for TeamPolicyReactivationRebalance.
+const (
+	RebalanceImmediate TeamPolicyReactivationRebalance = "immediate"
+	RebalanceJob       TeamPolicyReactivationRebalance = "job"
+	RebalanceOff       TeamPolicyReactivationRebalance = "off"
+)
 
-// PullRequestStatus defines model for PullRequest.Status.
-type PullRequestStatus string
+// Defines values for UserRole.
+const (
+	UserRoleAdmin  UserRole = "admin"
+	UserRoleLead   UserRole = "lead"
+	UserRoleMember UserRole = "member"
+)
 
-// PullRequestShort defines model for PullRequestShort.
-type PullRequestShort struct {
-	AuthorId        string                 `json:"author_id"`
-	PullRequestId   string                 `json:"pull_request_id"`
-	PullRequestName string                 `json:"pull_request_name"`
-	Status          PullRequestShortStatus `json:"status"`
-}
+// Defines values for WebhookDeliveryStatus.
+const (
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+)
 
-// PullRequestShortStatus defines model for PullRequestShort.Status.
-type PullRequestShortStatus string
+// Defines values for WebhookEvent.
+const (
+	WebhookPRCreated          WebhookEvent = "pr.created"
+	WebhookPRMerged           WebhookEvent = "pr.merged"
+	WebhookReviewerReassigned WebhookEvent = "reviewer.reassigned"
+)
 
-// ReassignResponse defines model for ReassignResponse.
-type ReassignResponse struct {
-	Pr PullRequest `json:"pr"`
+// Defines values for WebhookPullRequestStatus.
+const (
+	WebhookPullRequestStatusCLOSED WebhookPullRequestStatus = "CLOSED"
+	WebhookPullRequestStatusMERGED WebhookPullRequestStatus = "MERGED"
+	WebhookPullRequestStatusOPEN   WebhookPullRequestStatus = "OPEN"
+)
 
-	// ReplacedBy user_id нового ревьювера
-	ReplacedBy string `json:"replaced_by"`
-}
+// Defines values for ExpandQuery.
+const (
+	ExpandQueryReviewers ExpandQuery = "reviewers"
+)
 
-// StatsResponse defines model for StatsResponse.
-type StatsResponse struct {
-	UserStats []UserStats `json:"user_stats"`
-}
+// Defines values for PullRequestStatusQuery.
+const (
+	PullRequestStatusQueryCLOSED PullRequestStatusQuery = "CLOSED"
+	PullRequestStatusQueryMERGED PullRequestStatusQuery = "MERGED"
+	PullRequestStatusQueryOPEN   PullRequestStatusQuery = "OPEN"
+)
 
-// Team defines model for Team.
-type Team struct {
-	Members  []TeamMember `json:"members"`
-	TeamName string       `json:"team_name"`
-}
+// Defines values for PostPullRequestCreateParamsExpand.
+const (
+	PostPullRequestCreateParamsExpandReviewers PostPullRequestCreateParamsExpand = "reviewers"
+)
 
-// TeamMember defines model for TeamMember.
-type TeamMember struct {
-	IsActive bool `json:"is_active"`
+// Defines values for GetPullRequestGetParamsExpand.
+const (
+	GetPullRequestGetParamsExpandReviewers GetPullRequestGetParamsExpand = "reviewers"
+)
 
-	// UserId Идентификатор пользователя. Допускаются буквы, цифры, дефисы и подчеркивания.
-	UserId   string `json:"user_id"`
-	Username string `json:"username"`
-}
+// Defines values for PostPullRequestReassignParamsExpand.
+const (
+	PostPullRequestReassignParamsExpandReviewers PostPullRequestReassignParamsExpand = "reviewers"
+)
 
-// User defines model for User.
-type User struct {
-	IsActive bool   `json:"is_active"`
-	TeamName string `json:"team_name"`
+// Defines values for GetPullRequestSearchParamsStatus.
+const (
+	CLOSED GetPullRequestSearchParamsStatus = "CLOSED"
+	MERGED GetPullRequestSearchParamsStatus = "MERGED"
+	OPEN   GetPullRequestSearchParamsStatus = "OPEN"
+)
 
-	// UserId Идентификатор пользователя. Допускаются буквы, цифры, дефисы и подчеркивания.
-	UserId   string `json:"user_id"`
-	Username string `json:"username"`
+// ApiKey defines model for ApiKey.
+type ApiKey struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// ExpiresAt Момент, с которого ключ не принимается. Не задан — ключ бессрочный.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	KeyId     int64      `json:"key_id"`
+
+	// LastUsedAt Последнее использование ключа с точностью до минуты. Не задано — ключ не использовался.
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+
+	// Name Назначение ключа, например сервис или скрипт, которому он выдан
+	Name   string        `json:"name"`
+	Scopes []ApiKeyScope `json:"scopes"`
+
+	// UserId Пользователь, от имени которого действует ключ. Не задан — ключ сервиса, роль его не ограничивает.
+	UserId *string `json:"user_id,omitempty"`
 }
 
-// UserStats defines model for UserStats.
-type UserStats struct {
-	MergedReviews int    `json:"merged_reviews"`
-	OpenReviews   int    `json:"open_reviews"`
-	UserId        string `json:"user_id"`
-	Username      string `json:"username"`
+// ApiKeyCreatedResponse defines model for ApiKeyCreatedResponse.
+type ApiKeyCreatedResponse struct {
+	ApiKey ApiKey `json:"api_key"`
+
+	// Key Значение ключа. Возвращается только при создании и не хранится в сервисе.
+	Key string `json:"key"`
 }
 
-// TeamNameQuery defines model for TeamNameQuery.
-type TeamNameQuery = string
+// ApiKeyScope read — запросы GET и HEAD вне /admin, write — остальные запросы вне /admin, admin — запросы к /admin.
+type ApiKeyScope string
 
-// UserIdQuery Идентификатор пользователя. Допускаются буквы, цифры, дефисы и подчеркивания.
-type UserIdQuery = string
+// AssignmentHistoryEntry defines model for AssignmentHistoryEntry.
+type AssignmentHistoryEntry struct {
+	AssignedAt time.Time `json:"assigned_at"`
 
-// PostPullRequestCreateJSONBody defines parameters for PostPullRequestCreate.
-type PostPullRequestCreateJSONBody struct {
-	// AuthorId Идентификатор автора. Допускаются буквы, цифры, дефисы и подчеркивания.
-	AuthorId string `json:"author_id"`
+	// Cause Что изменило ревьюверов: created — создание PR, pending — назначение из очереди /pullRequest/pending, reassign — /pullRequest/reassign, user_deactivated — деактивация ревьювера, team_deactivated — деактивация команды, rebalance — перераспределение на вернувшегося участника, team_reactivated — возврат ревью при отмене деактивации команды (/team/reactivate), user_transferred — перевод ревьювера в другую команду (/users/transfer). Отсутствует у замен, записанных до появления причин.
+	Cause *AssignmentHistoryEntryCause `json:"cause,omitempty"`
 
-	// PullRequestId Идентификатор PR. Допускаются буквы, цифры, дефисы и подчеркивания.
-	PullRequestId   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-}
+	// HandoffNote Заметка, оставленная предыдущим ревьювером при переназначении.
+	HandoffNote *string `json:"handoff_note,omitempty"`
 
-// PostPullRequestMergeJSONBody defines parameters for PostPullRequestMerge.
-type PostPullRequestMergeJSONBody struct {
-	PullRequestId string `json:"pull_request_id"`
+	// Reason Почему выбран ревьювер, как в ReviewerAssignment.
+	Reason AssignmentHistoryEntryReason `json:"reason"`
+
+	// ReplacedUserId Ревьювер, которого заменил user_id; отсутствует у первоначальных назначений.
+	ReplacedUserId *string `json:"replaced_user_id,omitempty"`
+
+	// UserId Назначенный ревьювер
+	UserId string `json:"user_id"`
 }
 
-// PostPullRequestReassignJSONBody defines parameters for PostPullRequestReassign.
-type PostPullRequestReassignJSONBody struct {
-	OldUserId     string `json:"old_user_id"`
-	PullRequestId string `json:"pull_request_id"`
+// AssignmentHistoryEntryCause Что изменило ревьюверов: created — создание PR, pending — назначение из очереди /pullRequest/pending, reassign — /pullRequest/reassign, user_deactivated — деактивация ревьювера, team_deactivated — деактивация команды, rebalance — перераспределение на вернувшегося участника, team_reactivated — возврат ревью при отмене деактивации команды (/team/reactivate), user_transferred — перевод ревьювера в другую команду (/users/transfer). Отсутствует у замен, записанных до появления причин.
+type AssignmentHistoryEntryCause string
+
+// AssignmentHistoryEntryReason Почему выбран ревьювер, как в ReviewerAssignment.
+type AssignmentHistoryEntryReason string
+
+// AssignmentHistoryResponse defines model for AssignmentHistoryResponse.
+type AssignmentHistoryResponse struct {
+	// Items Записи истории от старых к новым
+	Items []AssignmentHistoryEntry `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor    *string `json:"next_cursor"`
+	PullRequestId string  `json:"pull_request_id"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
 }
 
-// PostTeamDeactivateJSONBody defines parameters for PostTeamDeactivate.
-type PostTeamDeactivateJSONBody struct {
-	TeamName string `json:"team_name"`
+// AssignmentPolicySnapshot Политика назначения ревьюверов, действовавшая при создании PR. Возвращается только /pullRequest/get; у PR, созданных до появления снимков, отсутствует.
+type AssignmentPolicySnapshot struct {
+	// AuthorOpenPrLimit Лимит открытых PR автора. Не задан — без ограничения.
+	AuthorOpenPrLimit *int                      `json:"author_open_pr_limit,omitempty"`
+	OverQuotaAction   TeamPolicyOverQuotaAction `json:"over_quota_action"`
+
+	// PolicyUpdatedAt Время последнего изменения политики команды. Отсутствует, если политика не задавалась.
+	PolicyUpdatedAt *time.Time `json:"policy_updated_at,omitempty"`
+
+	// ReviewersPerPr Число ревьюверов, которое требовалось назначить
+	ReviewersPerPr int `json:"reviewers_per_pr"`
+
+	// Strategy Стратегия, выбранная для PR по весам
+	Strategy string `json:"strategy"`
+
+	// StrategyWeights Веса стратегий, по которым выбиралась стратегия. Без весов в политике команды — стратегия по умолчанию с весом 1.
+	StrategyWeights map[string]int `json:"strategy_weights"`
+	TeamId          int            `json:"team_id"`
 }
 
-// GetTeamGetParams defines parameters for GetTeamGet.
-type GetTeamGetParams struct {
-	// TeamName Уникальное имя команды
-	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+// AsyncCreateError Причина, по которой PR не создан. У запроса в статусе queued — ошибка предыдущей попытки, после которой запрос будет повторен.
+type AsyncCreateError struct {
+	// Code PR_EXISTS, NOT_FOUND, AUTHOR_QUOTA_EXCEEDED — как в ответе /pullRequest/create; VALIDATION_FAILED — некорректные данные PR; INTERNAL_ERROR — внутренняя ошибка.
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
-// GetUsersGetReviewParams defines parameters for GetUsersGetReview.
-type GetUsersGetReviewParams struct {
-	UserId UserIdQuery `form:"user_id" json:"user_id"`
+// AsyncCreateRequest defines model for AsyncCreateRequest.
+type AsyncCreateRequest struct {
+	// Attempts Сколько раз обработчик брал запрос в работу
+	Attempts   int       `json:"attempts"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+
+	// Error Причина, по которой PR не создан. У запроса в статусе queued — ошибка предыдущей попытки, после которой запрос будет повторен.
+	Error         *AsyncCreateError `json:"error,omitempty"`
+	FinishedAt    *time.Time        `json:"finished_at"`
+	Pr            *PullRequest      `json:"pr,omitempty"`
+	PullRequestId string            `json:"pull_request_id"`
+	RequestId     int64             `json:"request_id"`
+	StartedAt     *time.Time        `json:"started_at"`
+
+	// Status queued — ожидает обработчика, processing — PR создается, succeeded — PR создан, failed — PR не создан, причина в error.
+	Status AsyncCreateRequestStatus `json:"status"`
 }
 
-// PostUsersSetIsActiveJSONBody defines parameters for PostUsersSetIsActive.
-type PostUsersSetIsActiveJSONBody struct {
-	IsActive bool `json:"is_active"`
+// AsyncCreateRequestStatus queued — ожидает обработчика, processing — PR создается, succeeded — PR создан, failed — PR не создан, причина в error.
+type AsyncCreateRequestStatus string
 
-	// UserId Идентификатор пользователя. Допускаются буквы, цифры, дефисы и подчеркивания.
-	UserId string `json:"user_id"`
+// AsyncCreateResponse defines model for AsyncCreateResponse.
+type AsyncCreateResponse struct {
+	Request AsyncCreateRequest `json:"request"`
 }
 
-// PostPullRequestCreateJSONRequestBody defines body for PostPullRequestCreate for application/json ContentType.
-type PostPullRequestCreateJSONRequestBody PostPullRequestCreateJSONBody
+// CreateJobBody defines model for CreateJobBody.
+type CreateJobBody struct {
+	Params *map[string]interface{} `json:"params,omitempty"`
 
-// PostPullRequestMergeJSONRequestBody defines body for PostPullRequestMerge for application/json ContentType.
-type PostPullRequestMergeJSONRequestBody PostPullRequestMergeJSONBody
+	// Type team_import — создать команды из params.teams (массив Team), уже существующие пропускаются; team_deactivation — пакетная деактивация команды params.team_name пакетами по params.batch_size PR; pending_backfill — назначить ревьюверов всем PR из очереди ожидающих назначений, пока это возможно; stats_export — выгрузить статистику ревью, как /stats; reviewer_rebalance — передать участнику params.user_id ревью самых загруженных коллег до справедливой доли; stats_backfill — пересчитать время назначения и первого ревью по истории назначений пакетами по params.batch_size PR и обновить предрассчитанную статистику /stats.
+	Type JobType `json:"type"`
+}
 
-// PostPullRequestReassignJSONRequestBody defines body for PostPullRequestReassign for application/json ContentType.
-type PostPullRequestReassignJSONRequestBody PostPullRequestReassignJSONBody
+// CreatePullRequestBody defines model for CreatePullRequestBody.
+type CreatePullRequestBody struct {
+	// AssignAt Отложить назначение ревьюверов до этого времени, например до начала следующего рабочего дня. Время переносится в рабочие часы (pull_requests.working_hours); PR создается открытым без ревьюверов и ждет в очереди /pullRequest/pending, а ревьюверов назначает фоновый обработчик очереди. Прошедшее время в рабочие часы назначает ревьюверов сразу. Не позже чем через 30 дней.
+	AssignAt *time.Time `json:"assign_at,omitempty"`
 
-// PostTeamAddJSONRequestBody defines body for PostTeamAdd for application/json ContentType.
-type PostTeamAddJSONRequestBody = Team
+	// AuthorId Идентификатор автора. Допускаются буквы, цифры, дефисы и подчеркивания.
+	AuthorId string `json:"author_id"`
 
-// PostTeamDeactivateJSONRequestBody defines body for PostTeamDeactivate for application/json ContentType.
-type PostTeamDeactivateJSONRequestBody PostTeamDeactivateJSONBody
+	// CustomFields Значения пользовательских полей команды автора. Неизвестные поля, значения другого типа и отсутствие обязательных полей отклоняются с кодом 400.
+	CustomFields *map[string]interface{} `json:"custom_fields,omitempty"`
 
-// PostUsersSetIsActiveJSONRequestBody defines body for PostUsersSetIsActive for application/json ContentType.
-type PostUsersSetIsActiveJSONRequestBody PostUsersSetIsActiveJSONBody
+	// Description Описание PR
+	Description *string `json:"description,omitempty"`
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// Создать PR и автоматически назначить до 2 ревьюверов из команды автора
-	// (POST /pullRequest/create)
-	PostPullRequestCreate(w http.ResponseWriter, r *http.Request)
-	// Пометить PR как MERGED (идемпотентная операция)
-	// (POST /pullRequest/merge)
-	PostPullRequestMerge(w http.ResponseWriter, r *http.Request)
-	// Переназначить конкретного ревьювера на другого из его команды
-	// (POST /pullRequest/reassign)
-	PostPullRequestReassign(w http.ResponseWriter, r *http.Request)
-	// Получить статистику по ревью для всех пользователей
-	// (GET /stats)
-	GetStats(w http.ResponseWriter, r *http.Request)
-	// Создать команду с участниками (создаёт/обновляет пользователей)
-	// (POST /team/add)
-	PostTeamAdd(w http.ResponseWriter, r *http.Request)
-	// Массово деактивировать всех пользователей команды и переназначить их открытые PR
-	// (POST /team/deactivate)
-	PostTeamDeactivate(w http.ResponseWriter, r *http.Request)
-	// Получить команду с участниками
-	// (GET /team/get)
-	GetTeamGet(w http.ResponseWriter, r *http.Request, params GetTeamGetParams)
-	// Получить PR'ы, где пользователь назначен ревьювером
-	// (GET /users/getReview)
-	GetUsersGetReview(w http.ResponseWriter, r *http.Request, params GetUsersGetReviewParams)
-	// Установить флаг активности пользователя
-	// (POST /users/setIsActive)
-	PostUsersSetIsActive(w http.ResponseWriter, r *http.Request)
+	// ExternalUrl Ссылка на PR в GitHub/GitLab
+	ExternalUrl *string `json:"external_url,omitempty"`
+
+	// PullRequestId Идентификатор PR. Допускаются буквы, цифры, дефисы и подчеркивания. Обязателен, если не передан generate_id=true.
+	PullRequestId   *string `json:"pull_request_id,omitempty"`
+	PullRequestName string  `json:"pull_request_name"`
 }
 
-// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+// CustomField defines model for CustomField.
+type CustomField struct {
+	// Key Имя поля в custom_fields PR. Строчные латинские буквы, цифры и подчеркивания.
+	Key string `json:"key"`
 
-type Unimplemented struct{}
+	// Required Поле обязательно при создании PR
+	Required bool `json:"required"`
 
-// Создать PR и автоматически назначить до 2 ревьюверов из команды автора
-// (POST /pullRequest/create)
-func (_ Unimplemented) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
+	// Type Тип значений поля в JSON
+	Type CustomFieldType `json:"type"`
 }
 
-// Пометить PR как MERGED (идемпотентная операция)
-// (POST /pullRequest/merge)
-func (_ Unimplemented) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
+// CustomFieldList Пользовательские поля PR команды, упорядоченные по key
+type CustomFieldList = []CustomField
+
+// CustomFieldType Тип значений поля в JSON
+type CustomFieldType string
+
+// DeactivateTeamResponse defines model for DeactivateTeamResponse.
+type DeactivateTeamResponse struct {
+	DeactivatedUsersCount int `json:"deactivated_users_count"`
+	ReassignedPrsCount    int `json:"reassigned_prs_count"`
+
+	// Reassignments Ревью, которые перейдут другим участникам команды. Возвращается только с dry_run.
+	Reassignments *[]ReviewerMove `json:"reassignments,omitempty"`
+
+	// Warnings Ревью, которые не удалось переназначить при деактивации с force=true. Такие PR остаются с деактивированным ревьювером.
+	Warnings *[]string `json:"warnings,omitempty"`
 }
 
-// Переназначить конкретного ревьювера на другого из его команды
-// (POST /pullRequest/reassign)
-func (_ Unimplemented) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
+// DeactivationJob defines model for DeactivationJob.
+type DeactivationJob struct {
+	// BatchSize Наибольшее число PR в одном пакете
+	BatchSize             int       `json:"batch_size"`
+	CreatedAt             time.Time `json:"created_at"`
+	DeactivatedUsersCount int       `json:"deactivated_users_count"`
+
+	// DoneBatches Число обработанных пакетов; каждый пакет переназначается в отдельной транзакции
+	DoneBatches int        `json:"done_batches"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	JobId       int64      `json:"job_id"`
+
+	// ReassignedReviewsCount Число ревью, переданных активным пользователям в обработанных пакетах
+	ReassignedReviewsCount int `json:"reassigned_reviews_count"`
+
+	// Status running — часть пакетов еще не обработана, succeeded — все ревью переназначены.
+	Status       DeactivationJobStatus `json:"status"`
+	TeamId       int                   `json:"team_id"`
+	TeamName     string                `json:"team_name"`
+	TotalBatches int                   `json:"total_batches"`
+
+	// TotalPrs Число открытых PR деактивированных пользователей на момент деактивации
+	TotalPrs int `json:"total_prs"`
+
+	// Warnings Ревью, которые не удалось переназначить. Такие PR остаются с деактивированным ревьювером.
+	Warnings []string `json:"warnings"`
 }
 
-// Получить статистику по ревью для всех пользователей
-// (GET /stats)
-func (_ Unimplemented) GetStats(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
+// DeactivationJobStatus running — часть пакетов еще не обработана, succeeded — все ревью переназначены.
+type DeactivationJobStatus string
+
+// DeactivationJobResponse defines model for DeactivationJobResponse.
+type DeactivationJobResponse struct {
+	Job DeactivationJob `json:"job"`
 }
 
-// Создать команду с участниками (создаёт/обновляет пользователей)
-// (POST /team/add)
-func (_ Unimplemented) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
+// DeleteTeamResponse defines model for DeleteTeamResponse.
+type DeleteTeamResponse struct {
+	// DeletedUsersCount Сколько участников удалено вместе с командой.
+	DeletedUsersCount  int `json:"deleted_users_count"`
+	ReassignedPrsCount int `json:"reassigned_prs_count"`
+
+	// TeamId Идентификатор удаленной команды, по которому ее восстанавливает /team/restore.
+	TeamId int `json:"team_id"`
+
+	// Warnings Ревью, которые не удалось переназначить при удалении с force=true. Такие PR остаются с удаленным ревьювером.
+	Warnings *[]string `json:"warnings,omitempty"`
 }
 
-// Массово деактивировать всех пользователей команды и переназначить их открытые PR
-// (POST /team/deactivate)
-func (_ Unimplemented) PostTeamDeactivate(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
+// DeleteUserResponse defines model for DeleteUserResponse.
+type DeleteUserResponse struct {
+	// Reassignments Открытые ревью пользователя, переназначенные другим участникам команды.
+	Reassignments []ReviewerMove `json:"reassignments"`
+	User          User           `json:"user"`
+
+	// Warnings Ревью пользователя, которые некому переназначить. Такие PR остаются с удаленным ревьювером.
+	Warnings *[]string `json:"warnings,omitempty"`
 }
 
-// Получить команду с участниками
-// (GET /team/get)
-func (_ Unimplemented) GetTeamGet(w http.ResponseWriter, r *http.Request, params GetTeamGetParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+// DurationStats Распределение длительностей в секундах; перцентили интерполируются линейно.
+type DurationStats struct {
+	AvgSeconds float64 `json:"avg_seconds"`
+
+	// Count Количество учтенных длительностей
+	Count      int     `json:"count"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P90Seconds float64 `json:"p90_seconds"`
 }
 
-// Получить PR'ы, где пользователь назначен ревьювером
+// EffectivePolicy Политика назначения ревьюверов, которая действительно применяется к PR команды: настройки сервиса по умолчанию, перекрытые политикой команды.
+type EffectivePolicy struct {
+	// AuthorOpenPrLimit Максимальное число открытых PR одного автора в команде. Не задано — без ограничения.
+	AuthorOpenPrLimit     *int                            `json:"author_open_pr_limit,omitempty"`
+	OverQuotaAction       TeamPolicyOverQuotaAction       `json:"over_quota_action"`
+	ReactivationRebalance TeamPolicyReactivationRebalance `json:"reactivation_rebalance"`
+
+	// RequireApprovals PR сливается, только когда его одобрили все ревьюверы (pull_requests.require_approvals)
+	RequireApprovals bool `json:"require_approvals"`
+
+	// ReviewersPerPr Число ревьюверов, назначаемых на PR
+	ReviewersPerPr int `json:"reviewers_per_pr"`
+
+	// Sources Источник каждого из полей политики
+	Sources map[string]PolicySource `json:"sources"`
+
+	// StrategyWeights Веса стратегий выбора ревьюверов. Без весов в политике команды — стратегия по умолчанию (pull_requests.default_strategy) с весом 1.
+	StrategyWeights map[string]int `json:"strategy_weights"`
+
+	// StrictChecks PR неактивного автора или команды, в которой не хватает ревьюверов, отклоняется (pull_requests.strict_checks)
+	StrictChecks bool   `json:"strict_checks"`
+	TeamId       int    `json:"team_id"`
+	TeamName     string `json:"team_name"`
+}
+
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	Error struct {
+		// Alternatives Только для NO_CANDIDATE: ближайшие альтернативы, которые автоматический выбор не рассматривает. Администратор может назначить одну из них вручную.
+		Alternatives *[]ReplacementAlternative `json:"alternatives,omitempty"`
+		Code         ErrorResponseErrorCode    `json:"code"`
+		Message      string                    `json:"message"`
+	} `json:"error"`
+}
+
+// ErrorResponseErrorCode defines model for ErrorResponse.Error.Code.
+type ErrorResponseErrorCode string
+
+// FreezeWindow defines model for FreezeWindow.
+type FreezeWindow struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// EndsAt Конец окна, не входит в него
+	EndsAt   time.Time `json:"ends_at"`
+	FreezeId int64     `json:"freeze_id"`
+	Reason   string    `json:"reason"`
+	StartsAt time.Time `json:"starts_at"`
+	TeamId   *int      `json:"team_id,omitempty"`
+
+	// TeamName Команда, PR которой нельзя сливать; отсутствует у окна на всю организацию
+	TeamName *string `json:"team_name,omitempty"`
+}
+
+// FreezeWindowResponse defines model for FreezeWindowResponse.
+type FreezeWindowResponse struct {
+	Freeze FreezeWindow `json:"freeze"`
+}
+
+// GetReviewResponse defines model for GetReviewResponse.
+type GetReviewResponse struct {
+	Items []PullRequestShort `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// PullRequests Устарело, используйте items
+	PullRequests []PullRequestShort `json:"pull_requests"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int   `json:"total_estimate,omitempty"`
+	UserId        string `json:"user_id"`
+}
+
+// GitHubPullRequestEvent Событие pull_request вебхука GitHub. Перечислены только поля, которые использует сервис; остальные поля GitHub игнорируются.
+type GitHubPullRequestEvent struct {
+	// Action Действие GitHub; сервис обрабатывает opened и closed
+	Action      string `json:"action"`
+	PullRequest *struct {
+		Body    *string `json:"body"`
+		HtmlUrl *string `json:"html_url,omitempty"`
+		Merged  *bool   `json:"merged,omitempty"`
+		Number  int     `json:"number"`
+		Title   string  `json:"title"`
+		User    struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request,omitempty"`
+	Repository *struct {
+		FullName string `json:"full_name"`
+		Id       int64  `json:"id"`
+	} `json:"repository,omitempty"`
+}
+
+// GitHubWebhookResult defines model for GitHubWebhookResult.
+type GitHubWebhookResult struct {
+	// Outcome created, merged, closed — PR создан, слит или закрыт; duplicate — событие уже обработано, например при повторной доставке; ignored — событие или действие сервис не обрабатывает.
+	Outcome GitHubWebhookResultOutcome `json:"outcome"`
+
+	// PullRequestId Идентификатор PR в сервисе
+	PullRequestId *string `json:"pull_request_id,omitempty"`
+
+	// Reviewers Ревьюверы, которые были бы назначены PR. Возвращается только в режиме тени для outcome created.
+	Reviewers *[]string `json:"reviewers,omitempty"`
+
+	// Shadow true — вебхук работает в режиме тени: событие обработано и ревьюверы подобраны, но PR не создан и не изменен.
+	Shadow *bool `json:"shadow,omitempty"`
+
+	// Strategy Стратегия, которой подобраны ревьюверы в режиме тени
+	Strategy *string `json:"strategy,omitempty"`
+}
+
+// GitHubWebhookResultOutcome created, merged, closed — PR создан, слит или закрыт; duplicate — событие уже обработано, например при повторной доставке; ignored — событие или действие сервис не обрабатывает.
+type GitHubWebhookResultOutcome string
+
+// GitLabMergeRequestEvent Событие Merge Request Hook вебхука GitLab. Перечислены только поля, которые использует сервис; остальные поля GitLab игнорируются.
+type GitLabMergeRequestEvent struct {
+	ObjectAttributes struct {
+		// Action Действие GitLab; сервис обрабатывает open, merge и close
+		Action      *string `json:"action,omitempty"`
+		Description *string `json:"description"`
+
+		// Iid Номер MR в проекте
+		Iid   int     `json:"iid"`
+		Title string  `json:"title"`
+		Url   *string `json:"url,omitempty"`
+	} `json:"object_attributes"`
+
+	// ObjectKind Вид события; сервис обрабатывает merge_request
+	ObjectKind string `json:"object_kind"`
+	Project    struct {
+		Id                int64   `json:"id"`
+		PathWithNamespace *string `json:"path_with_namespace,omitempty"`
+	} `json:"project"`
+
+	// User Пользователь GitLab, совершивший действие; для open — автор MR
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+}
+
+// GitLabUser defines model for GitLabUser.
+type GitLabUser struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// GitlabUsername Имя пользователя GitLab в нижнем регистре
+	GitlabUsername string `json:"gitlab_username"`
+
+	// UserId Пользователь сервиса, которому соответствует пользователь GitLab
+	UserId string `json:"user_id"`
+}
+
+// GitLabUserResponse defines model for GitLabUserResponse.
+type GitLabUserResponse struct {
+	GitlabUser GitLabUser `json:"gitlab_user"`
+}
+
+// GitLabWebhookResult defines model for GitLabWebhookResult.
+type GitLabWebhookResult struct {
+	// Outcome created, merged, closed — PR создан, слит или закрыт; duplicate — событие уже обработано, например при повторной доставке; ignored — событие или действие сервис не обрабатывает; unmapped — автор MR не сопоставлен пользователю сервиса, PR не создан.
+	Outcome GitLabWebhookResultOutcome `json:"outcome"`
+
+	// PullRequestId Идентификатор PR в сервисе
+	PullRequestId *string `json:"pull_request_id,omitempty"`
+
+	// Reviewers Ревьюверы, которые были бы назначены PR. Возвращается только в режиме тени для outcome created.
+	Reviewers *[]string `json:"reviewers,omitempty"`
+
+	// Shadow true — вебхук работает в режиме тени: событие обработано и ревьюверы подобраны, но PR не создан и не изменен.
+	Shadow *bool `json:"shadow,omitempty"`
+
+	// Strategy Стратегия, которой подобраны ревьюверы в режиме тени
+	Strategy *string `json:"strategy,omitempty"`
+}
+
+// GitLabWebhookResultOutcome created, merged, closed — PR создан, слит или закрыт; duplicate — событие уже обработано, например при повторной доставке; ignored — событие или действие сервис не обрабатывает; unmapped — автор MR не сопоставлен пользователю сервиса, PR не создан.
+type GitLabWebhookResultOutcome string
+
+// Job defines model for Job.
+type Job struct {
+	// Attempts Сколько раз обработчик брал задачу в работу. Задачу, обработчик которой не продлил аренду, забирает другой обработчик.
+	Attempts int `json:"attempts"`
+
+	// CancelRequested Отмена запрошена, обработчик остановит задачу в ближайшей контрольной точке
+	CancelRequested bool       `json:"cancel_requested"`
+	CreatedAt       time.Time  `json:"created_at"`
+	Error           *string    `json:"error"`
+	FinishedAt      *time.Time `json:"finished_at"`
+	JobId           int64      `json:"job_id"`
+
+	// Params Параметры, с которыми задача создана
+	Params   map[string]interface{} `json:"params"`
+	Progress JobProgress            `json:"progress"`
+
+	// Result Результат задачи, формат зависит от типа. У отмененной задачи — результат выполненной части.
+	Result    *map[string]interface{} `json:"result"`
+	StartedAt *time.Time              `json:"started_at"`
+
+	// Status queued — ожидает обработчика, running — выполняется, succeeded — выполнена, failed — прервана ошибкой из error, cancelled — отменена. Из queued задача переходит в running или cancelled, из running — в succeeded, failed или cancelled; три последних статуса окончательные.
+	Status JobStatus `json:"status"`
+
+	// Type team_import — создать команды из params.teams (массив Team), уже существующие пропускаются; team_deactivation — пакетная деактивация команды params.team_name пакетами по params.batch_size PR; pending_backfill — назначить ревьюверов всем PR из очереди ожидающих назначений, пока это возможно; stats_export — выгрузить статистику ревью, как /stats; reviewer_rebalance — передать участнику params.user_id ревью самых загруженных коллег до справедливой доли; stats_backfill — пересчитать время назначения и первого ревью по истории назначений пакетами по params.batch_size PR и обновить предрассчитанную статистику /stats.
+	Type JobType `json:"type"`
+}
+
+// JobStatus queued — ожидает обработчика, running — выполняется, succeeded — выполнена, failed — прервана ошибкой из error, cancelled — отменена. Из queued задача переходит в running или cancelled, из running — в succeeded, failed или cancelled; три последних статуса окончательные.
+type JobStatus string
+
+// JobProgress defines model for JobProgress.
+type JobProgress struct {
+	Done int `json:"done"`
+
+	// Total Общий объем работы; 0, если он заранее неизвестен
+	Total int `json:"total"`
+}
+
+// JobResponse defines model for JobResponse.
+type JobResponse struct {
+	Job Job `json:"job"`
+}
+
+// JobType team_import — создать команды из params.teams (массив Team), уже существующие пропускаются; team_deactivation — пакетная деактивация команды params.team_name пакетами по params.batch_size PR; pending_backfill — назначить ревьюверов всем PR из очереди ожидающих назначений, пока это возможно; stats_export — выгрузить статистику ревью, как /stats; reviewer_rebalance — передать участнику params.user_id ревью самых загруженных коллег до справедливой доли; stats_backfill — пересчитать время назначения и первого ревью по истории назначений пакетами по params.batch_size PR и обновить предрассчитанную статистику /stats.
+type JobType string
+
+// LeaderboardEntry defines model for LeaderboardEntry.
+type LeaderboardEntry struct {
+	// MergedReviews Количество ревью PR, слитых в периоде
+	MergedReviews int `json:"merged_reviews"`
+
+	// Rank Место пользователя; пользователи с равным числом ревью делят место
+	Rank     int    `json:"rank"`
+	UserId   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// LeaderboardPeriod Календарный период в UTC; неделя начинается с понедельника.
+type LeaderboardPeriod string
+
+// LeaderboardResponse defines model for LeaderboardResponse.
+type LeaderboardResponse struct {
+	// From Начало периода включительно
+	From time.Time `json:"from"`
+
+	// Items Пользователи по убыванию числа ревью, затем по имени
+	Items []LeaderboardEntry `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// Period Календарный период в UTC; неделя начинается с понедельника.
+	Period LeaderboardPeriod `json:"period"`
+
+	// To Конец периода, не включая его
+	To time.Time `json:"to"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+}
+
+// ListApiKeysResponse defines model for ListApiKeysResponse.
+type ListApiKeysResponse struct {
+	Items []ApiKey `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+}
+
+// ListFreezeWindowsResponse defines model for ListFreezeWindowsResponse.
+type ListFreezeWindowsResponse struct {
+	// Freezes Устарело, используйте items
+	Freezes []FreezeWindow `json:"freezes"`
+	Items   []FreezeWindow `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+}
+
+// ListGitLabUsersResponse defines model for ListGitLabUsersResponse.
+type ListGitLabUsersResponse struct {
+	Items []GitLabUser `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+}
+
+// ListNotificationDeliveriesResponse defines model for ListNotificationDeliveriesResponse.
+type ListNotificationDeliveriesResponse struct {
+	// Deliveries Устарело, используйте items
+	Deliveries []NotificationDelivery `json:"deliveries"`
+	Items      []NotificationDelivery `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+}
+
+// ListOidcSubjectsResponse defines model for ListOidcSubjectsResponse.
+type ListOidcSubjectsResponse struct {
+	Items []OidcSubject `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+}
+
+// ListPullRequestsResponse defines model for ListPullRequestsResponse.
+type ListPullRequestsResponse struct {
+	// Items PR, начиная с самых новых (по createdAt, затем по pull_request_id)
+	Items []PullRequest `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// PullRequests Устарело, используйте items
+	PullRequests []PullRequest `json:"pull_requests"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+}
+
+// ListReadTokensResponse defines model for ListReadTokensResponse.
+type ListReadTokensResponse struct {
+	Items []ReadToken `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+}
+
+// ListSlackUsersResponse defines model for ListSlackUsersResponse.
+type ListSlackUsersResponse struct {
+	Items []SlackUser `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+}
+
+// ListWebhookDeliveriesResponse defines model for ListWebhookDeliveriesResponse.
+type ListWebhookDeliveriesResponse struct {
+	Items []WebhookDelivery `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+}
+
+// ListWebhooksResponse defines model for ListWebhooksResponse.
+type ListWebhooksResponse struct {
+	Items []Webhook `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+}
+
+// MergeResponse defines model for MergeResponse.
+type MergeResponse struct {
+	Pr PullRequest `json:"pr"`
+
+	// ReviewerStats Счетчики ревью назначенных ревьюверов после слияния. Считаются в той же транзакции, что и слияние, поэтому согласованы со статусом PR.
+	ReviewerStats []UserStats `json:"reviewer_stats"`
+}
+
+// NotificationDelivery defines model for NotificationDelivery.
+type NotificationDelivery struct {
+	// Attempts Число попыток доставки, включая повторные
+	Attempts int `json:"attempts"`
+
+	// Channel Канал уведомлений, например `log`
+	Channel    string    `json:"channel"`
+	CreatedAt  time.Time `json:"created_at"`
+	DeliveryId int64     `json:"delivery_id"`
+
+	// Error Ошибка последней неудачной попытки
+	Error *string `json:"error"`
+
+	// Event Событие PR, о котором отправлено уведомление
+	Event         NotificationEvent `json:"event"`
+	PullRequestId string            `json:"pull_request_id"`
+
+	// RecipientId Пользователь, которому адресовано уведомление
+	RecipientId string `json:"recipient_id"`
+
+	// Status Результат последней попытки доставки
+	Status NotificationDeliveryStatus `json:"status"`
+
+	// UpdatedAt Время последней попытки
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NotificationDeliveryResponse defines model for NotificationDeliveryResponse.
+type NotificationDeliveryResponse struct {
+	Delivery NotificationDelivery `json:"delivery"`
+}
+
+// NotificationDeliveryStatus Результат последней попытки доставки
+type NotificationDeliveryStatus string
+
+// NotificationEvent Событие PR, о котором отправлено уведомление
+type NotificationEvent string
+
+// OidcLoginResponse defines model for OidcLoginResponse.
+type OidcLoginResponse struct {
+	// ExpiresAt Момент, с которого токен не принимается; после него нужно войти снова
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// IdToken ID-токен, передаваемый в заголовке `Authorization: Bearer <токен>`
+	IdToken string `json:"id_token"`
+
+	// Role member — только чтение, lead — также изменение PR и переназначение ревьюверов, admin — также создание и деактивация команд, назначение ролей и /admin.
+	Role UserRole `json:"role"`
+
+	// UserId Пользователь сервиса, сопоставленный субъекту токена
+	UserId string `json:"user_id"`
+}
+
+// OidcSubject defines model for OidcSubject.
+type OidcSubject struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// Subject Субъект (`sub`) ID-токенов провайдера удостоверений
+	Subject string `json:"subject"`
+
+	// UserId Пользователь сервиса, от имени которого действуют ID-токены субъекта
+	UserId string `json:"user_id"`
+}
+
+// OidcSubjectResponse defines model for OidcSubjectResponse.
+type OidcSubjectResponse struct {
+	OidcSubject OidcSubject `json:"oidc_subject"`
+}
+
+// Page Общая часть ответов списочных эндпоинтов. Ответ дополняет ее полем items со своими элементами.
+type Page struct {
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+}
+
+// PendingAssignment defines model for PendingAssignment.
+type PendingAssignment struct {
+	// AssignAt Время отложенного назначения; до него PR не обслуживается
+	AssignAt   *time.Time `json:"assign_at,omitempty"`
+	AuthorId   string     `json:"author_id"`
+	EnqueuedAt time.Time  `json:"enqueued_at"`
+
+	// Priority Сколько ревьюверов еще не хватает PR. PR с большим приоритетом обслуживаются первыми, при равном приоритете — в порядке постановки в очередь.
+	Priority        int    `json:"priority"`
+	PullRequestId   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	TeamId          int    `json:"team_id"`
+	TeamName        string `json:"team_name"`
+
+	// WaitingSeconds Сколько секунд PR ожидает в очереди
+	WaitingSeconds int `json:"waiting_seconds"`
+}
+
+// PendingAssignmentsResponse defines model for PendingAssignmentsResponse.
+type PendingAssignmentsResponse struct {
+	// Items PR в порядке обслуживания очереди
+	Items []PendingAssignment `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// PendingAssignments Устарело, используйте items
+	PendingAssignments []PendingAssignment `json:"pending_assignments"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+}
+
+// PolicySource Откуда взято значение: default — настройка сервиса, общая для всех команд, team — политика команды (/team/setPolicy).
+type PolicySource string
+
+// PullRequest defines model for PullRequest.
+type PullRequest struct {
+	// AssignAt Время, на которое отложено назначение ревьюверов (assign_at при создании, перенесенный в рабочие часы). Отсутствует, если ревьюверы назначались при создании PR.
+	AssignAt *time.Time `json:"assign_at,omitempty"`
+
+	// AssignedReviewers user_id назначенных ревьюверов (0..2)
+	AssignedReviewers []string `json:"assigned_reviewers"`
+
+	// AssignmentPolicy Политика назначения ревьюверов, действовавшая при создании PR. Возвращается только /pullRequest/get; у PR, созданных до появления снимков, отсутствует.
+	AssignmentPolicy *AssignmentPolicySnapshot `json:"assignment_policy,omitempty"`
+
+	// AuthorId Идентификатор автора. Допускаются буквы, цифры, дефисы и подчеркивания.
+	AuthorId  string     `json:"author_id"`
+	CreatedAt *time.Time `json:"createdAt"`
+
+	// CustomFields Значения пользовательских полей, определенных командой автора (см. /team/setCustomFields)
+	CustomFields *map[string]interface{} `json:"custom_fields,omitempty"`
+
+	// Description Описание PR
+	Description *string `json:"description,omitempty"`
+
+	// ExternalUrl Ссылка на PR в GitHub/GitLab
+	ExternalUrl *string    `json:"external_url,omitempty"`
+	MergedAt    *time.Time `json:"mergedAt"`
+
+	// PullRequestId Идентификатор PR. Допускаются буквы, цифры, дефисы и подчеркивания.
+	PullRequestId   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+
+	// Reviewers Подробности назначения ревьюверов. Возвращается только при expand=reviewers
+	Reviewers *[]ReviewerAssignment `json:"reviewers,omitempty"`
+
+	// Reviews Решения назначенных ревьюверов, упорядоченные по user_id. Возвращается /pullRequest/get, /pullRequest/approve и /pullRequest/requestChanges
+	Reviews *[]Review         `json:"reviews,omitempty"`
+	Status  PullRequestStatus `json:"status"`
+}
+
+// PullRequestStatus defines model for PullRequest.Status.
+type PullRequestStatus string
+
+// PullRequestShort defines model for PullRequestShort.
+type PullRequestShort struct {
+	AuthorId string `json:"author_id"`
+
+	// CustomFields Значения пользовательских полей, определенных командой автора (см. /team/setCustomFields)
+	CustomFields    *map[string]interface{} `json:"custom_fields,omitempty"`
+	PullRequestId   string                  `json:"pull_request_id"`
+	PullRequestName string                  `json:"pull_request_name"`
+	Status          PullRequestShortStatus  `json:"status"`
+}
+
+// PullRequestShortStatus defines model for PullRequestShort.Status.
+type PullRequestShortStatus string
+
+// PullRequestSubscription Подписка пользователя на PR. Подписчик получает уведомления о каждом изменении состояния PR (назначение и переназначение ревьюверов, слияние, закрытие), даже если он не ревьювер.
+type PullRequestSubscription struct {
+	CreatedAt     time.Time `json:"created_at"`
+	PullRequestId string    `json:"pull_request_id"`
+	UserId        string    `json:"user_id"`
+}
+
+// ReadToken defines model for ReadToken.
+type ReadToken struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// ExpiresAt Момент, с которого токен не принимается. Не задан — токен бессрочный.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// LastUsedAt Последнее использование токена с точностью до минуты. Не задано — токен не использовался.
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+
+	// Name Назначение токена, например дашборд или скрипт, которому он выдан
+	Name    string `json:"name"`
+	TokenId int64  `json:"token_id"`
+}
+
+// ReadTokenCreatedResponse defines model for ReadTokenCreatedResponse.
+type ReadTokenCreatedResponse struct {
+	ReadToken ReadToken `json:"read_token"`
+
+	// Token Значение токена. Возвращается только при создании и не хранится в сервисе.
+	Token string `json:"token"`
+}
+
+// ReactivateTeamResponse defines model for ReactivateTeamResponse.
+type ReactivateTeamResponse struct {
+	// ReactivatedUsersCount Сколько пользователей снова активны. Пользователи, активированные после деактивации другим способом, не учитываются.
+	ReactivatedUsersCount int `json:"reactivated_users_count"`
+
+	// RestoredReviews Ревью, возвращенные прежним ревьюверам (только с restore_reviewers).
+	RestoredReviews []ReviewerMove `json:"restored_reviews"`
+}
+
+// ReassignResponse defines model for ReassignResponse.
+type ReassignResponse struct {
+	Pr PullRequest `json:"pr"`
+
+	// ReplacedBy user_id нового ревьювера
+	ReplacedBy string `json:"replaced_by"`
+}
+
+// RebalanceResult defines model for RebalanceResult.
+type RebalanceResult struct {
+	// JobId Задача reviewer_rebalance, если политика команды откладывает перераспределение
+	JobId *int64 `json:"job_id,omitempty"`
+
+	// Moves Перенесенные ревью; пусто, если перераспределение поставлено в очередь задачей
+	Moves []ReviewerMove `json:"moves"`
+}
+
+// ReplacementAlternative defines model for ReplacementAlternative.
+type ReplacementAlternative struct {
+	// OpenReviews Число открытых PR, которые пользователь уже ревьюит
+	OpenReviews int `json:"open_reviews"`
+
+	// Reason Почему пользователь не был выбран автоматически: inactive — неактивный участник команды ревьювера, other_team — активный участник другой команды (ревьюверы выбираются только внутри команды и среди одолженных ей участников).
+	Reason   ReplacementAlternativeReason `json:"reason"`
+	TeamId   int                          `json:"team_id"`
+	TeamName string                       `json:"team_name"`
+	UserId   string                       `json:"user_id"`
+	Username string                       `json:"username"`
+}
+
+// ReplacementAlternativeReason Почему пользователь не был выбран автоматически: inactive — неактивный участник команды ревьювера, other_team — активный участник другой команды (ревьюверы выбираются только внутри команды и среди одолженных ей участников).
+type ReplacementAlternativeReason string
+
+// Review defines model for Review.
+type Review struct {
+	// ReviewedAt Когда ревьювер принял решение; null, пока решение не принято
+	ReviewedAt *time.Time `json:"reviewed_at"`
+
+	// State PENDING — ревьювер еще не принял решение; с него начинается каждое назначение, в том числе после переназначения. Ревьювер может изменить свое решение, пока PR открыт.
+	State  ReviewState `json:"state"`
+	UserId string      `json:"user_id"`
+}
+
+// ReviewState PENDING — ревьювер еще не принял решение; с него начинается каждое назначение, в том числе после переназначения. Ревьювер может изменить свое решение, пока PR открыт.
+type ReviewState string
+
+// ReviewerAssignment defines model for ReviewerAssignment.
+type ReviewerAssignment struct {
+	AssignedAt *time.Time `json:"assigned_at,omitempty"`
+
+	// HandoffNote Заметка, оставленная предыдущим ревьювером при переназначении.
+	HandoffNote *string `json:"handoff_note,omitempty"`
+
+	// Reason Почему выбран ревьювер. Отсутствует для назначений, сделанных до появления истории назначений.
+	Reason *ReviewerAssignmentReason `json:"reason,omitempty"`
+	UserId string                    `json:"user_id"`
+}
+
+// ReviewerAssignmentReason Почему выбран ревьювер. Отсутствует для назначений, сделанных до появления истории назначений.
+type ReviewerAssignmentReason string
+
+// ReviewerBorrow defines model for ReviewerBorrow.
+type ReviewerBorrow struct {
+	AcceptedAt *time.Time `json:"accepted_at"`
+	BorrowId   int64      `json:"borrow_id"`
+
+	// Count Сколько ревьюверов запрошено
+	Count int `json:"count"`
+
+	// DurationHours На сколько часов после принятия предоставляются ревьюверы
+	DurationHours int `json:"duration_hours"`
+
+	// ExpiresAt До этого момента одолженные участники выбираются ревьюверами PR команды team_name
+	ExpiresAt    *time.Time `json:"expires_at"`
+	LenderTeamId int        `json:"lender_team_id"`
+
+	// LenderTeamName Команда, которая предоставляет ревьюверов
+	LenderTeamName string    `json:"lender_team_name"`
+	RequestedAt    time.Time `json:"requested_at"`
+
+	// ReviewerIds Одолженные участники; пусто, пока запрос не принят
+	ReviewerIds []string             `json:"reviewer_ids"`
+	Status      ReviewerBorrowStatus `json:"status"`
+	TeamId      int                  `json:"team_id"`
+
+	// TeamName Команда, которая одалживает ревьюверов
+	TeamName string `json:"team_name"`
+}
+
+// ReviewerBorrowStatus defines model for ReviewerBorrow.Status.
+type ReviewerBorrowStatus string
+
+// ReviewerBorrowsResponse defines model for ReviewerBorrowsResponse.
+type ReviewerBorrowsResponse struct {
+	// Borrows Устарело, используйте items
+	Borrows []ReviewerBorrow `json:"borrows"`
+
+	// Items Запросы, в которых команда одалживает или предоставляет ревьюверов, кроме истекших; сначала новые
+	Items []ReviewerBorrow `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+}
+
+// ReviewerMove Ревью PR, переданное от одного ревьювера другому
+type ReviewerMove struct {
+	FromUserId    string `json:"from_user_id"`
+	PullRequestId string `json:"pull_request_id"`
+	ToUserId      string `json:"to_user_id"`
+}
+
+// SearchPullRequestsResponse defines model for SearchPullRequestsResponse.
+type SearchPullRequestsResponse struct {
+	// Items Найденные PR, начиная с наиболее релевантных
+	Items []PullRequestShort `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// PullRequests Устарело, используйте items
+	PullRequests []PullRequestShort `json:"pull_requests"`
+
+	// Total Общее количество найденных PR без учета страниц
+	Total int `json:"total"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+}
+
+// SetIsActiveResponse defines model for SetIsActiveResponse.
+type SetIsActiveResponse struct {
+	// Reassignments Открытые ревью деактивированного пользователя, переназначенные другим участникам команды в той же транзакции. Возвращается только при деактивации.
+	Reassignments *[]ReviewerMove  `json:"reassignments,omitempty"`
+	Rebalance     *RebalanceResult `json:"rebalance,omitempty"`
+	User          User             `json:"user"`
+
+	// Warnings Ревью деактивированного пользователя, которые некому переназначить. Такие PR остаются с деактивированным ревьювером.
+	Warnings *[]string `json:"warnings,omitempty"`
+}
+
+// SetUserRoleResponse defines model for SetUserRoleResponse.
+type SetUserRoleResponse struct {
+	// Role member — только чтение, lead — также изменение PR и переназначение ревьюверов, admin — также создание и деактивация команд, назначение ролей и /admin.
+	Role   UserRole `json:"role"`
+	UserId string   `json:"user_id"`
+}
+
+// SlackUser defines model for SlackUser.
+type SlackUser struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// SlackUserId Идентификатор участника Slack (member ID), которому отправляются личные сообщения пользователя
+	SlackUserId string `json:"slack_user_id"`
+
+	// UserId Пользователь сервиса
+	UserId string `json:"user_id"`
+}
+
+// SlackUserResponse defines model for SlackUserResponse.
+type SlackUserResponse struct {
+	SlackUser SlackUser `json:"slack_user"`
+}
+
+// StatsResponse defines model for StatsResponse.
+type StatsResponse struct {
+	Items []UserStats `json:"items"`
+
+	// NextCursor Курсор следующей страницы для параметра cursor; null, если страница последняя
+	NextCursor *string `json:"next_cursor"`
+
+	// RefreshedAt Время пересчета предрасчитанной статистики, из которой взят ответ. Отсутствует, если статистика посчитана по текущим данным
+	RefreshedAt *time.Time `json:"refreshed_at,omitempty"`
+
+	// TeamStats Команды, у PR которых в периоде было первое решение ревьювера или слияние, по имени команды
+	TeamStats []TeamStats `json:"team_stats"`
+
+	// TotalEstimate Оценка общего количества элементов без учета страниц. Отсутствует, если для подсчета пришлось бы просмотреть таблицу.
+	TotalEstimate *int `json:"total_estimate,omitempty"`
+
+	// UserStats Устарело, используйте items
+	UserStats []UserStats `json:"user_stats"`
+}
+
+// Team defines model for Team.
+type Team struct {
+	Members []TeamMember `json:"members"`
+
+	// TeamId Идентификатор команды, не меняется при переименовании
+	TeamId   *int   `json:"team_id,omitempty"`
+	TeamName string `json:"team_name"`
+}
+
+// TeamCustomFields defines model for TeamCustomFields.
+type TeamCustomFields struct {
+	// Fields Пользовательские поля PR команды, упорядоченные по key
+	Fields   CustomFieldList `json:"fields"`
+	TeamId   int             `json:"team_id"`
+	TeamName string          `json:"team_name"`
+}
+
+// TeamMember defines model for TeamMember.
+type TeamMember struct {
+	IsActive bool `json:"is_active"`
+
+	// UserId Идентификатор пользователя. Допускаются буквы, цифры, дефисы и подчеркивания.
+	UserId string `json:"user_id"`
+
+	// Username Имя пользователя. Пробелы по краям обрезаются, имя приводится к Unicode NFC. Управляющие и невидимые символы не допускаются.
+	Username string `json:"username"`
+}
+
+// TeamPolicy defines model for TeamPolicy.
+type TeamPolicy struct {
+	// AuthorOpenPrLimit Максимальное число открытых PR одного автора в команде. Не задано — без ограничения.
+	AuthorOpenPrLimit *int `json:"author_open_pr_limit,omitempty"`
+
+	// OverQuotaAction Что делать с PR сверх лимита author_open_pr_limit: reject — отклонить создание с кодом AUTHOR_QUOTA_EXCEEDED, queue — создать PR без ревьюверов (в очереди на назначение).
+	OverQuotaAction *TeamPolicyOverQuotaAction `json:"over_quota_action,omitempty"`
+
+	// ReactivationRebalance Перераспределение ревью при повторной активации участника через /users/setIsActive: off — не перераспределять, immediate — в той же транзакции передать вернувшемуся участнику ревью самых загруженных коллег до справедливой доли, job — сделать то же в фоновой задаче reviewer_rebalance.
+	ReactivationRebalance *TeamPolicyReactivationRebalance `json:"reactivation_rebalance,omitempty"`
+
+	// StrategyWeights Относительные веса стратегий выбора ревьюверов (random, least_loaded, round_robin). Для каждого назначения стратегия выбирается случайно пропорционально весу и сохраняется в истории назначений. Пустой объект — стратегия по умолчанию (настройка pull_requests.default_strategy, по умолчанию random).
+	StrategyWeights map[string]int `json:"strategy_weights"`
+	TeamId          int            `json:"team_id"`
+	TeamName        string         `json:"team_name"`
+}
+
+// TeamPolicyOverQuotaAction Что делать с PR сверх лимита author_open_pr_limit: reject — отклонить создание с кодом AUTHOR_QUOTA_EXCEEDED, queue — создать PR без ревьюверов (в очереди на назначение).
+type TeamPolicyOverQuotaAction string
+
+// TeamPolicyReactivationRebalance Перераспределение ревью при повторной активации участника через /users/setIsActive: off — не перераспределять, immediate — в той же транзакции передать вернувшемуся участнику ревью самых загруженных коллег до справедливой доли, job — сделать то же в фоновой задаче reviewer_rebalance.
+type TeamPolicyReactivationRebalance string
+
+// TeamSelector Команда задается ровно одним из полей team_name и team_id.
+type TeamSelector struct {
+	// TeamId Идентификатор команды, не меняется при переименовании
+	TeamId   *int    `json:"team_id,omitempty"`
+	TeamName *string `json:"team_name,omitempty"`
+}
+
+// TeamStats Скорость ревью и слияния PR, авторы которых состоят в команде.
+type TeamStats struct {
+	TeamName string `json:"team_name"`
+
+	// TimeToFirstReview Время от создания PR до первого решения любого ревьювера по PR, впервые решенным в периоде. Отсутствует, если таких PR нет.
+	TimeToFirstReview *DurationStats `json:"time_to_first_review,omitempty"`
+
+	// TimeToMerge Время от создания до слияния PR, слитых в периоде. Отсутствует, если таких PR нет.
+	TimeToMerge *DurationStats `json:"time_to_merge,omitempty"`
+}
+
+// TransferUserResponse defines model for TransferUserResponse.
+type TransferUserResponse struct {
+	PreviousTeamId   int    `json:"previous_team_id"`
+	PreviousTeamName string `json:"previous_team_name"`
+
+	// Reassignments Открытые ревью пользователя в прежней команде, переназначенные ее участникам. Возвращается только с reassign_reviews.
+	Reassignments *[]ReviewerMove `json:"reassignments,omitempty"`
+	User          User            `json:"user"`
+
+	// Warnings Ревью пользователя в прежней команде, которые некому переназначить. Такие PR остаются с переведенным ревьювером.
+	Warnings *[]string `json:"warnings,omitempty"`
+}
+
+// User defines model for User.
+type User struct {
+	IsActive bool `json:"is_active"`
+
+	// TeamId Идентификатор основной команды пользователя.
+	TeamId int `json:"team_id"`
+
+	// TeamName Основная команда пользователя: из нее выбираются ревьюверы его PR. Пользователь может состоять и в других командах.
+	TeamName string `json:"team_name"`
+
+	// UserId Идентификатор пользователя. Допускаются буквы, цифры, дефисы и подчеркивания.
+	UserId   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// UserRole member — только чтение, lead — также изменение PR и переназначение ревьюверов, admin — также создание и деактивация команд, назначение ролей и /admin.
+type UserRole string
+
+// UserStats defines model for UserStats.
+type UserStats struct {
+	MergedReviews int `json:"merged_reviews"`
+	OpenReviews   int `json:"open_reviews"`
+
+	// TimeToFirstReview Время от назначения пользователя до его первого решения по ревью, впервые решенным в периоде. Отсутствует, если таких ревью нет.
+	TimeToFirstReview *DurationStats `json:"time_to_first_review,omitempty"`
+
+	// TimeToMerge Время от назначения пользователя до слияния PR, учтенных в merged_reviews. Отсутствует, если таких PR нет.
+	TimeToMerge *DurationStats `json:"time_to_merge,omitempty"`
+	UserId      string         `json:"user_id"`
+	Username    string         `json:"username"`
+}
+
+// Webhook defines model for Webhook.
+type Webhook struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// Events События, на которые подписан вебхук
+	Events    []WebhookEvent `json:"events"`
+	UpdatedAt time.Time      `json:"updated_at"`
+
+	// Url Адрес, на который отправляются события методом POST
+	Url       string `json:"url"`
+	WebhookId int64  `json:"webhook_id"`
+}
+
+// WebhookBody defines model for WebhookBody.
+type WebhookBody struct {
+	Events []WebhookEvent `json:"events"`
+
+	// Secret Секрет, которым подписываются доставки. Обязателен при создании; при изменении вебхука без секрета сохраняется прежний. Секрет не возвращается в ответах.
+	Secret *string `json:"secret,omitempty"`
+
+	// Url Абсолютный адрес http или https
+	Url string `json:"url"`
+}
+
+// WebhookDelivery defines model for WebhookDelivery.
+type WebhookDelivery struct {
+	// Attempts Число сделанных попыток доставки
+	Attempts   int       `json:"attempts"`
+	CreatedAt  time.Time `json:"created_at"`
+	DeliveryId int64     `json:"delivery_id"`
+
+	// Event Событие PR, отправляемое исходящим вебхукам
+	Event WebhookEvent `json:"event"`
+
+	// LastError Ошибка последней неудачной попытки
+	LastError *string `json:"last_error"`
+
+	// NextAttemptAt Время следующей попытки; для доставок не в статусе pending не имеет значения
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	PullRequestId string    `json:"pull_request_id"`
+
+	// ResponseStatus HTTP-статус ответа вебхука на последнюю неудачную попытку, если ответ был получен
+	ResponseStatus *int `json:"response_status,omitempty"`
+
+	// Status pending — доставка ждет очередной попытки; delivered — вебхук принял событие; dead — все попытки неудачны, доставка повторяется только по запросу администратора.
+	Status    WebhookDeliveryStatus `json:"status"`
+	UpdatedAt time.Time             `json:"updated_at"`
+	WebhookId int64                 `json:"webhook_id"`
+}
+
+// WebhookDeliveryResponse defines model for WebhookDeliveryResponse.
+type WebhookDeliveryResponse struct {
+	Delivery WebhookDelivery `json:"delivery"`
+}
+
+// WebhookDeliveryStatus pending — доставка ждет очередной попытки; delivered — вебхук принял событие; dead — все попытки неудачны, доставка повторяется только по запросу администратора.
+type WebhookDeliveryStatus string
+
+// WebhookEvent Событие PR, отправляемое исходящим вебхукам
+type WebhookEvent string
+
+// WebhookPayload Тело запроса, которым событие доставляется вебхуку. Запрос подписан секретом вебхука по схеме заголовков X-Signature, X-Signature-Timestamp и X-Signature-Nonce; заголовок X-Webhook-Event содержит событие, X-Webhook-Delivery — идентификатор доставки, одинаковый у всех ее попыток.
+type WebhookPayload struct {
+	// Event Событие PR, отправляемое исходящим вебхукам
+	Event WebhookEvent `json:"event"`
+
+	// NewReviewerId Только для reviewer.reassigned — назначенный вместо него ревьювер
+	NewReviewerId *string   `json:"new_reviewer_id,omitempty"`
+	OccurredAt    time.Time `json:"occurred_at"`
+
+	// OldReviewerId Только для reviewer.reassigned — снятый ревьювер
+	OldReviewerId *string `json:"old_reviewer_id,omitempty"`
+
+	// PullRequest Состояние PR в момент события
+	PullRequest WebhookPullRequest `json:"pull_request"`
+}
+
+// WebhookPullRequest Состояние PR в момент события
+type WebhookPullRequest struct {
+	AssignedReviewers []string                 `json:"assigned_reviewers"`
+	AuthorId          string                   `json:"author_id"`
+	CreatedAt         time.Time                `json:"created_at"`
+	Description       *string                  `json:"description,omitempty"`
+	ExternalUrl       *string                  `json:"external_url,omitempty"`
+	MergedAt          *time.Time               `json:"merged_at,omitempty"`
+	PullRequestId     string                   `json:"pull_request_id"`
+	PullRequestName   string                   `json:"pull_request_name"`
+	Status            WebhookPullRequestStatus `json:"status"`
+}
+
+// WebhookPullRequestStatus defines model for WebhookPullRequest.Status.
+type WebhookPullRequestStatus string
+
+// WebhookResponse defines model for WebhookResponse.
+type WebhookResponse struct {
+	Webhook Webhook `json:"webhook"`
+}
+
+// CursorQuery defines model for CursorQuery.
+type CursorQuery = string
+
+// CustomFieldQuery defines model for CustomFieldQuery.
+type CustomFieldQuery = []string
+
+// ExpandQuery defines model for ExpandQuery.
+type ExpandQuery string
+
+// FieldsQuery defines model for FieldsQuery.
+type FieldsQuery = string
+
+// LimitQuery defines model for LimitQuery.
+type LimitQuery = int
+
+// OffsetQuery defines model for OffsetQuery.
+type OffsetQuery = int
+
+// PullRequestIdQuery Идентификатор PR. Допускаются буквы, цифры, дефисы и подчеркивания.
+type PullRequestIdQuery = string
+
+// PullRequestStatusQuery defines model for PullRequestStatusQuery.
+type PullRequestStatusQuery string
+
+// SearchQuery defines model for SearchQuery.
+type SearchQuery = string
+
+// TeamIdQuery defines model for TeamIdQuery.
+type TeamIdQuery = int
+
+// TeamNameQuery defines model for TeamNameQuery.
+type TeamNameQuery = string
+
+// UserIdQuery Идентификатор пользователя. Допускаются буквы, цифры, дефисы и подчеркивания.
+type UserIdQuery = string
+
+// PostAdminApiKeysJSONBody defines parameters for PostAdminApiKeys.
+type PostAdminApiKeysJSONBody struct {
+	// ExpiresAt Момент, с которого ключ не принимается; должен быть в будущем. Не задан — ключ бессрочный.
+	ExpiresAt *time.Time    `json:"expires_at,omitempty"`
+	Name      string        `json:"name"`
+	Scopes    []ApiKeyScope `json:"scopes"`
+
+	// UserId Пользователь, от имени которого действует ключ; запросы ключа ограничивает роль пользователя.
+	UserId *string `json:"user_id,omitempty"`
+}
+
+// GetAdminFreezesParams defines parameters for GetAdminFreezes.
+type GetAdminFreezesParams struct {
+	// TeamName Вернуть только окна, действующие на команду, включая окна всей организации
+	TeamName *string `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// TeamId Вернуть только окна, действующие на команду с указанным идентификатором, вместо team_name
+	TeamId *int `form:"team_id,omitempty" json:"team_id,omitempty"`
+
+	// IncludeEnded Вернуть также завершившиеся окна
+	IncludeEnded *bool `form:"include_ended,omitempty" json:"include_ended,omitempty"`
+}
+
+// PostAdminFreezesJSONBody defines parameters for PostAdminFreezes.
+type PostAdminFreezesJSONBody struct {
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+	StartsAt time.Time `json:"starts_at"`
+
+	// TeamId Идентификатор команды, не меняется при переименовании
+	TeamId   *int    `json:"team_id,omitempty"`
+	TeamName *string `json:"team_name,omitempty"`
+}
+
+// PostAdminGitlabUsersJSONBody defines parameters for PostAdminGitlabUsers.
+type PostAdminGitlabUsersJSONBody struct {
+	GitlabUsername string `json:"gitlab_username"`
+	UserId         string `json:"user_id"`
+}
+
+// GetAdminNotificationsParams defines parameters for GetAdminNotifications.
+type GetAdminNotificationsParams struct {
+	// Channel Вернуть только доставки через указанный канал
+	Channel *string `form:"channel,omitempty" json:"channel,omitempty"`
+
+	// RecipientId Вернуть только уведомления указанного пользователя
+	RecipientId *string `form:"recipient_id,omitempty" json:"recipient_id,omitempty"`
+
+	// PullRequestId Вернуть только уведомления об указанном PR
+	PullRequestId *string                     `form:"pull_request_id,omitempty" json:"pull_request_id,omitempty"`
+	Event         *NotificationEvent          `form:"event,omitempty" json:"event,omitempty"`
+	Status        *NotificationDeliveryStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Limit Максимальное количество элементов в ответе
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Количество пропускаемых элементов. Устарел, используйте cursor.
+	Offset *OffsetQuery `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Cursor Курсор из next_cursor предыдущей страницы. Не сочетается с offset.
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// PostAdminOidcSubjectsJSONBody defines parameters for PostAdminOidcSubjects.
+type PostAdminOidcSubjectsJSONBody struct {
+	Subject string `json:"subject"`
+	UserId  string `json:"user_id"`
+}
+
+// PostAdminReadTokensJSONBody defines parameters for PostAdminReadTokens.
+type PostAdminReadTokensJSONBody struct {
+	// ExpiresAt Момент, с которого токен не принимается; должен быть в будущем. Не задан — токен бессрочный.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Name      string     `json:"name"`
+}
+
+// PostAdminSlackUsersJSONBody defines parameters for PostAdminSlackUsers.
+type PostAdminSlackUsersJSONBody struct {
+	SlackUserId string `json:"slack_user_id"`
+	UserId      string `json:"user_id"`
+}
+
+// PostAdminStatsBackfillJSONBody defines parameters for PostAdminStatsBackfill.
+type PostAdminStatsBackfillJSONBody struct {
+	// BatchSize Сколько PR пересчитывается в одной транзакции
+	BatchSize *int `json:"batch_size,omitempty"`
+}
+
+// GetAdminWebhooksWebhookIdDeliveriesParams defines parameters for GetAdminWebhooksWebhookIdDeliveries.
+type GetAdminWebhooksWebhookIdDeliveriesParams struct {
+	Status *WebhookDeliveryStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Limit Максимальное количество элементов в ответе
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Курсор из next_cursor предыдущей страницы. Не сочетается с offset.
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetAuthCallbackParams defines parameters for GetAuthCallback.
+type GetAuthCallbackParams struct {
+	Code  string `form:"code" json:"code"`
+	State string `form:"state" json:"state"`
+}
+
+// GetPolicyEffectiveParams defines parameters for GetPolicyEffective.
+type GetPolicyEffectiveParams struct {
+	// TeamName Уникальное имя команды. Команда задается ровно одним из параметров team_name и team_id.
+	TeamName *TeamNameQuery `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// TeamId Идентификатор команды. Не меняется при переименовании команды. Команда задается ровно одним из параметров team_name и team_id.
+	TeamId *TeamIdQuery `form:"team_id,omitempty" json:"team_id,omitempty"`
+}
+
+// PostPullRequestApproveJSONBody defines parameters for PostPullRequestApprove.
+type PostPullRequestApproveJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+	ReviewerId    string `json:"reviewer_id"`
+}
+
+// PostPullRequestCloseJSONBody defines parameters for PostPullRequestClose.
+type PostPullRequestCloseJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+}
+
+// PostPullRequestCreateParams defines parameters for PostPullRequestCreate.
+type PostPullRequestCreateParams struct {
+	// Expand Дополнительные данные в ответе. reviewers — подробности назначения каждого ревьювера (поле reviewers у PR).
+	Expand *PostPullRequestCreateParamsExpand `form:"expand,omitempty" json:"expand,omitempty"`
+
+	// GenerateId Сгенерировать идентификатор PR (pr- и UUIDv7) вместо переданного в pull_request_id. С true поле pull_request_id нужно опустить; сгенерированный идентификатор возвращается в ответе.
+	GenerateId *bool `form:"generate_id,omitempty" json:"generate_id,omitempty"`
+}
+
+// PostPullRequestCreateParamsExpand defines parameters for PostPullRequestCreate.
+type PostPullRequestCreateParamsExpand string
+
+// GetPullRequestCreateStatusParams defines parameters for GetPullRequestCreateStatus.
+type GetPullRequestCreateStatusParams struct {
+	RequestId int64 `form:"request_id" json:"request_id"`
+}
+
+// GetPullRequestGetParams defines parameters for GetPullRequestGet.
+type GetPullRequestGetParams struct {
+	PullRequestId PullRequestIdQuery `form:"pull_request_id" json:"pull_request_id"`
+
+	// Expand Дополнительные данные в ответе. reviewers — подробности назначения каждого ревьювера (поле reviewers у PR).
+	Expand *GetPullRequestGetParamsExpand `form:"expand,omitempty" json:"expand,omitempty"`
+}
+
+// GetPullRequestGetParamsExpand defines parameters for GetPullRequestGet.
+type GetPullRequestGetParamsExpand string
+
+// GetPullRequestHistoryParams defines parameters for GetPullRequestHistory.
+type GetPullRequestHistoryParams struct {
+	PullRequestId PullRequestIdQuery `form:"pull_request_id" json:"pull_request_id"`
+}
+
+// GetPullRequestListParams defines parameters for GetPullRequestList.
+type GetPullRequestListParams struct {
+	// Status Вернуть только PR с указанным статусом
+	Status *PullRequestStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// AuthorId Вернуть только PR указанного автора
+	AuthorId *string `form:"author_id,omitempty" json:"author_id,omitempty"`
+
+	// TeamName Уникальное имя команды. Команда задается ровно одним из параметров team_name и team_id.
+	TeamName *TeamNameQuery `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// TeamId Идентификатор команды. Не меняется при переименовании команды. Команда задается ровно одним из параметров team_name и team_id.
+	TeamId *TeamIdQuery `form:"team_id,omitempty" json:"team_id,omitempty"`
+
+	// CreatedFrom Вернуть только PR, созданные не раньше указанного момента
+	CreatedFrom *time.Time `form:"created_from,omitempty" json:"created_from,omitempty"`
+
+	// CreatedTo Вернуть только PR, созданные раньше указанного момента
+	CreatedTo *time.Time `form:"created_to,omitempty" json:"created_to,omitempty"`
+
+	// Limit Максимальное количество элементов в ответе
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Количество пропускаемых элементов. Устарел, используйте cursor.
+	Offset *OffsetQuery `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Cursor Курсор из next_cursor предыдущей страницы. Не сочетается с offset.
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Fields Выборка полей ответа (sparse fieldset): имена полей через запятую, вложенные поля — через точку. Поле объекта в массиве выбирается так же, как поле одиночного объекта. Не задано — возвращаются все поля. Неизвестное поле — ошибка 400.
+	Fields *FieldsQuery `form:"fields,omitempty" json:"fields,omitempty"`
+}
+
+// PostPullRequestMergeJSONBody defines parameters for PostPullRequestMerge.
+type PostPullRequestMergeJSONBody struct {
+	// OverrideFreeze Слить PR несмотря на действующее окно заморозки; обход записывается в лог
+	OverrideFreeze *bool  `json:"override_freeze,omitempty"`
+	PullRequestId  string `json:"pull_request_id"`
+}
+
+// GetPullRequestPendingParams defines parameters for GetPullRequestPending.
+type GetPullRequestPendingParams struct {
+	// TeamName Вернуть только PR указанной команды
+	TeamName *string `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// TeamId Вернуть только PR команды с указанным идентификатором, вместо team_name
+	TeamId *int `form:"team_id,omitempty" json:"team_id,omitempty"`
+
+	// Limit Максимальное количество элементов в ответе
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PostPullRequestReassignJSONBody defines parameters for PostPullRequestReassign.
+type PostPullRequestReassignJSONBody struct {
+	// Note Заметка для нового ревьювера о состоянии ревью. Сохраняется в истории назначений и передается ему в уведомлении.
+	Note          *string `json:"note,omitempty"`
+	OldUserId     string  `json:"old_user_id"`
+	PullRequestId string  `json:"pull_request_id"`
+}
+
+// PostPullRequestReassignParams defines parameters for PostPullRequestReassign.
+type PostPullRequestReassignParams struct {
+	// Expand Дополнительные данные в ответе. reviewers — подробности назначения каждого ревьювера (поле reviewers у PR).
+	Expand *PostPullRequestReassignParamsExpand `form:"expand,omitempty" json:"expand,omitempty"`
+}
+
+// PostPullRequestReassignParamsExpand defines parameters for PostPullRequestReassign.
+type PostPullRequestReassignParamsExpand string
+
+// PostPullRequestRequestChangesJSONBody defines parameters for PostPullRequestRequestChanges.
+type PostPullRequestRequestChangesJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+	ReviewerId    string `json:"reviewer_id"`
+}
+
+// GetPullRequestSearchParams defines parameters for GetPullRequestSearch.
+type GetPullRequestSearchParams struct {
+	// Query Поисковый запрос по названию PR. Поддерживается синтаксис веб-поиска: фразы в кавычках, OR, исключение слов через минус.
+	Query SearchQuery `form:"query" json:"query"`
+
+	// Status Вернуть только PR с указанным статусом
+	Status *GetPullRequestSearchParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// CustomField Фильтр по пользовательскому полю в виде key:value, например custom_field=risk:high. Значение сравнивается с текстовым представлением поля (true/false для boolean, число без кавычек для number). Несколько фильтров объединяются через И.
+	CustomField *CustomFieldQuery `form:"custom_field,omitempty" json:"custom_field,omitempty"`
+
+	// Limit Максимальное количество элементов в ответе
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Количество пропускаемых элементов. Устарел, используйте cursor.
+	Offset *OffsetQuery `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Cursor Курсор из next_cursor предыдущей страницы. Не сочетается с offset.
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetPullRequestSearchParamsStatus defines parameters for GetPullRequestSearch.
+type GetPullRequestSearchParamsStatus string
+
+// PostPullRequestSubscribeJSONBody defines parameters for PostPullRequestSubscribe.
+type PostPullRequestSubscribeJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+	UserId        string `json:"user_id"`
+}
+
+// GetStatsParams defines parameters for GetStats.
+type GetStatsParams struct {
+	// From Начало периода включительно
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Конец периода, не включая его; должен быть позже from
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+
+	// Fields Выборка полей ответа (sparse fieldset): имена полей через запятую, вложенные поля — через точку. Поле объекта в массиве выбирается так же, как поле одиночного объекта. Не задано — возвращаются все поля. Неизвестное поле — ошибка 400.
+	Fields *FieldsQuery `form:"fields,omitempty" json:"fields,omitempty"`
+}
+
+// GetStatsLeaderboardParams defines parameters for GetStatsLeaderboard.
+type GetStatsLeaderboardParams struct {
+	Period LeaderboardPeriod `form:"period" json:"period"`
+
+	// Limit Максимальное количество элементов в ответе
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// DeleteTeamParams defines parameters for DeleteTeam.
+type DeleteTeamParams struct {
+	// TeamName Уникальное имя команды. Команда задается ровно одним из параметров team_name и team_id.
+	TeamName *TeamNameQuery `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// TeamId Идентификатор команды. Не меняется при переименовании команды. Команда задается ровно одним из параметров team_name и team_id.
+	TeamId *TeamIdQuery `form:"team_id,omitempty" json:"team_id,omitempty"`
+
+	// Force Удалить команду, даже если для части открытых ревью не найдется замены. Без флага в этом случае ничего не меняется и возвращается 409 INSUFFICIENT_CAPACITY.
+	Force *bool `form:"force,omitempty" json:"force,omitempty"`
+}
+
+// PostTeamBorrowJSONBody defines parameters for PostTeamBorrow.
+type PostTeamBorrowJSONBody struct {
+	Count         int  `json:"count"`
+	DurationHours int  `json:"duration_hours"`
+	LenderTeamId  *int `json:"lender_team_id,omitempty"`
+
+	// LenderTeamName Команда, у которой запрашиваются ревьюверы
+	LenderTeamName *string `json:"lender_team_name,omitempty"`
+	TeamId         *int    `json:"team_id,omitempty"`
+
+	// TeamName Команда, которой нужны ревьюверы
+	TeamName *string `json:"team_name,omitempty"`
+}
+
+// PostTeamBorrowAcceptJSONBody defines parameters for PostTeamBorrowAccept.
+type PostTeamBorrowAcceptJSONBody struct {
+	BorrowId int64 `json:"borrow_id"`
+}
+
+// GetTeamBorrowsParams defines parameters for GetTeamBorrows.
+type GetTeamBorrowsParams struct {
+	// TeamName Уникальное имя команды. Команда задается ровно одним из параметров team_name и team_id.
+	TeamName *TeamNameQuery `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// TeamId Идентификатор команды. Не меняется при переименовании команды. Команда задается ровно одним из параметров team_name и team_id.
+	TeamId *TeamIdQuery `form:"team_id,omitempty" json:"team_id,omitempty"`
+}
+
+// PostTeamDeactivateJSONBody defines parameters for PostTeamDeactivate.
+type PostTeamDeactivateJSONBody struct {
+	// BatchSize Деактивировать команду в фоне задачей team_deactivation: ответ 202 содержит задачу, статус и прогресс которой возвращает /jobs/{job_id}. Задача деактивирует участников и переназначает ревью пакетами не более чем по batch_size PR, каждый пакет в отдельной транзакции. Требует force=true: заранее проверить, что замены хватит на все пакеты, нельзя, поэтому ревью без замены попадают в warnings результата задачи. Неизвестная команда дает 404 до постановки задачи, остальные ошибки деактивации возвращаются в error задачи.
+	BatchSize *int `json:"batch_size,omitempty"`
+
+	// DryRun Только показать, что сделает деактивация: сколько пользователей будет деактивировано и какие ревью кому перейдут (reassignments). Деактивация выполняется в транзакции, которая затем откатывается, поэтому ничего не меняется; без force ревью без замены дают 409, как и без dry_run. batch_size не учитывается: ревью планируются сразу, а пакеты распределяются по нагрузке на момент своей обработки, поэтому их замены могут отличаться.
+	DryRun *bool `json:"dry_run,omitempty"`
+
+	// Force Деактивировать команду, даже если для части открытых ревью не найдется замены. Без флага в этом случае ничего не меняется и возвращается 409 INSUFFICIENT_CAPACITY.
+	Force    *bool   `json:"force,omitempty"`
+	TeamId   *int    `json:"team_id,omitempty"`
+	TeamName *string `json:"team_name,omitempty"`
+}
+
+// GetTeamDeactivationJobParams defines parameters for GetTeamDeactivationJob.
+type GetTeamDeactivationJobParams struct {
+	JobId int64 `form:"job_id" json:"job_id"`
+}
+
+// GetTeamGetParams defines parameters for GetTeamGet.
+type GetTeamGetParams struct {
+	// TeamName Уникальное имя команды. Команда задается ровно одним из параметров team_name и team_id.
+	TeamName *TeamNameQuery `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// TeamId Идентификатор команды. Не меняется при переименовании команды. Команда задается ровно одним из параметров team_name и team_id.
+	TeamId *TeamIdQuery `form:"team_id,omitempty" json:"team_id,omitempty"`
+
+	// Fields Выборка полей ответа (sparse fieldset): имена полей через запятую, вложенные поля — через точку. Поле объекта в массиве выбирается так же, как поле одиночного объекта. Не задано — возвращаются все поля. Неизвестное поле — ошибка 400.
+	Fields *FieldsQuery `form:"fields,omitempty" json:"fields,omitempty"`
+}
+
+// GetTeamGetCustomFieldsParams defines parameters for GetTeamGetCustomFields.
+type GetTeamGetCustomFieldsParams struct {
+	// TeamName Уникальное имя команды. Команда задается ровно одним из параметров team_name и team_id.
+	TeamName *TeamNameQuery `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// TeamId Идентификатор команды. Не меняется при переименовании команды. Команда задается ровно одним из параметров team_name и team_id.
+	TeamId *TeamIdQuery `form:"team_id,omitempty" json:"team_id,omitempty"`
+}
+
+// GetTeamGetPolicyParams defines parameters for GetTeamGetPolicy.
+type GetTeamGetPolicyParams struct {
+	// TeamName Уникальное имя команды. Команда задается ровно одним из параметров team_name и team_id.
+	TeamName *TeamNameQuery `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// TeamId Идентификатор команды. Не меняется при переименовании команды. Команда задается ровно одним из параметров team_name и team_id.
+	TeamId *TeamIdQuery `form:"team_id,omitempty" json:"team_id,omitempty"`
+}
+
+// PostTeamReactivateJSONBody defines parameters for PostTeamReactivate.
+type PostTeamReactivateJSONBody struct {
+	// RestoreReviewers Вернуть пользователям ревью открытых PR, переназначенные при деактивации. Ревью возвращается, только если его по-прежнему ведет тот, кому оно досталось при деактивации, и прежний ревьювер не назначен на PR снова; возврат записывается в историю назначений с cause team_reactivated.
+	RestoreReviewers *bool   `json:"restore_reviewers,omitempty"`
+	TeamId           *int    `json:"team_id,omitempty"`
+	TeamName         *string `json:"team_name,omitempty"`
+}
+
+// PostTeamRenameJSONBody defines parameters for PostTeamRename.
+type PostTeamRenameJSONBody struct {
+	NewTeamName string `json:"new_team_name"`
+
+	// TeamId Идентификатор команды, не меняется при переименовании
+	TeamId   *int    `json:"team_id,omitempty"`
+	TeamName *string `json:"team_name,omitempty"`
+}
+
+// PostTeamRestoreJSONBody defines parameters for PostTeamRestore.
+type PostTeamRestoreJSONBody struct {
+	TeamId int `json:"team_id"`
+}
+
+// PostTeamSetCustomFieldsJSONBody defines parameters for PostTeamSetCustomFields.
+type PostTeamSetCustomFieldsJSONBody struct {
+	// Fields Пользовательские поля PR команды, упорядоченные по key
+	Fields CustomFieldList `json:"fields"`
+
+	// TeamId Идентификатор команды, не меняется при переименовании
+	TeamId   *int    `json:"team_id,omitempty"`
+	TeamName *string `json:"team_name,omitempty"`
+}
+
+// PostTeamSetPolicyJSONBody defines parameters for PostTeamSetPolicy.
+type PostTeamSetPolicyJSONBody struct {
+	AuthorOpenPrLimit     *int                             `json:"author_open_pr_limit,omitempty"`
+	OverQuotaAction       *TeamPolicyOverQuotaAction       `json:"over_quota_action,omitempty"`
+	ReactivationRebalance *TeamPolicyReactivationRebalance `json:"reactivation_rebalance,omitempty"`
+	StrategyWeights       map[string]int                   `json:"strategy_weights"`
+
+	// TeamId Идентификатор команды, не меняется при переименовании
+	TeamId   *int    `json:"team_id,omitempty"`
+	TeamName *string `json:"team_name,omitempty"`
+}
+
+// DeleteUsersParams defines parameters for DeleteUsers.
+type DeleteUsersParams struct {
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
+}
+
+// GetUsersGetReviewParams defines parameters for GetUsersGetReview.
+type GetUsersGetReviewParams struct {
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
+
+	// CustomField Фильтр по пользовательскому полю в виде key:value, например custom_field=risk:high. Значение сравнивается с текстовым представлением поля (true/false для boolean, число без кавычек для number). Несколько фильтров объединяются через И.
+	CustomField *CustomFieldQuery `form:"custom_field,omitempty" json:"custom_field,omitempty"`
+}
+
+// PostUsersRestoreJSONBody defines parameters for PostUsersRestore.
+type PostUsersRestoreJSONBody struct {
+	// UserId Идентификатор пользователя. Допускаются буквы, цифры, дефисы и подчеркивания.
+	UserId string `json:"user_id"`
+}
+
+// PostUsersSetIsActiveJSONBody defines parameters for PostUsersSetIsActive.
+type PostUsersSetIsActiveJSONBody struct {
+	IsActive bool `json:"is_active"`
+
+	// UserId Идентификатор пользователя. Допускаются буквы, цифры, дефисы и подчеркивания.
+	UserId string `json:"user_id"`
+}
+
+// PostUsersSetRoleJSONBody defines parameters for PostUsersSetRole.
+type PostUsersSetRoleJSONBody struct {
+	// Role member — только чтение, lead — также изменение PR и переназначение ревьюверов, admin — также создание и деактивация команд, назначение ролей и /admin.
+	Role UserRole `json:"role"`
+
+	// UserId Идентификатор пользователя. Допускаются буквы, цифры, дефисы и подчеркивания.
+	UserId string `json:"user_id"`
+}
+
+// PostUsersTransferJSONBody defines parameters for PostUsersTransfer.
+type PostUsersTransferJSONBody struct {
+	// ReassignReviews Переназначить открытые ревью пользователя в прежней команде.
+	ReassignReviews *bool `json:"reassign_reviews,omitempty"`
+
+	// TeamId Идентификатор команды, не меняется при переименовании
+	TeamId   *int    `json:"team_id,omitempty"`
+	TeamName *string `json:"team_name,omitempty"`
+
+	// UserId Идентификатор пользователя. Допускаются буквы, цифры, дефисы и подчеркивания.
+	UserId string `json:"user_id"`
+}
+
+// PostWebhooksGithubParams defines parameters for PostWebhooksGithub.
+type PostWebhooksGithubParams struct {
+	// XGitHubEvent Тип события GitHub
+	XGitHubEvent string `json:"X-GitHub-Event"`
+}
+
+// PostWebhooksGitlabParams defines parameters for PostWebhooksGitlab.
+type PostWebhooksGitlabParams struct {
+	// XGitlabEvent Тип события GitLab
+	XGitlabEvent string `json:"X-Gitlab-Event"`
+}
+
+// PostAdminApiKeysJSONRequestBody defines body for PostAdminApiKeys for application/json ContentType.
+type PostAdminApiKeysJSONRequestBody PostAdminApiKeysJSONBody
+
+// PostAdminFreezesJSONRequestBody defines body for PostAdminFreezes for application/json ContentType.
+type PostAdminFreezesJSONRequestBody PostAdminFreezesJSONBody
+
+// PostAdminGitlabUsersJSONRequestBody defines body for PostAdminGitlabUsers for application/json ContentType.
+type PostAdminGitlabUsersJSONRequestBody PostAdminGitlabUsersJSONBody
+
+// PostAdminOidcSubjectsJSONRequestBody defines body for PostAdminOidcSubjects for application/json ContentType.
+type PostAdminOidcSubjectsJSONRequestBody PostAdminOidcSubjectsJSONBody
+
+// PostAdminReadTokensJSONRequestBody defines body for PostAdminReadTokens for application/json ContentType.
+type PostAdminReadTokensJSONRequestBody PostAdminReadTokensJSONBody
+
+// PostAdminSlackUsersJSONRequestBody defines body for PostAdminSlackUsers for application/json ContentType.
+type PostAdminSlackUsersJSONRequestBody PostAdminSlackUsersJSONBody
+
+// PostAdminStatsBackfillJSONRequestBody defines body for PostAdminStatsBackfill for application/json ContentType.
+type PostAdminStatsBackfillJSONRequestBody PostAdminStatsBackfillJSONBody
+
+// PostAdminWebhooksJSONRequestBody defines body for PostAdminWebhooks for application/json ContentType.
+type PostAdminWebhooksJSONRequestBody = WebhookBody
+
+// PutAdminWebhooksWebhookIdJSONRequestBody defines body for PutAdminWebhooksWebhookId for application/json ContentType.
+type PutAdminWebhooksWebhookIdJSONRequestBody = WebhookBody
+
+// PostJobsJSONRequestBody defines body for PostJobs for application/json ContentType.
+type PostJobsJSONRequestBody = CreateJobBody
+
+// PostPullRequestApproveJSONRequestBody defines body for PostPullRequestApprove for application/json ContentType.
+type PostPullRequestApproveJSONRequestBody PostPullRequestApproveJSONBody
+
+// PostPullRequestCloseJSONRequestBody defines body for PostPullRequestClose for application/json ContentType.
+type PostPullRequestCloseJSONRequestBody PostPullRequestCloseJSONBody
+
+// PostPullRequestCreateJSONRequestBody defines body for PostPullRequestCreate for application/json ContentType.
+type PostPullRequestCreateJSONRequestBody = CreatePullRequestBody
+
+// PostPullRequestCreateAsyncJSONRequestBody defines body for PostPullRequestCreateAsync for application/json ContentType.
+type PostPullRequestCreateAsyncJSONRequestBody = CreatePullRequestBody
+
+// PostPullRequestMergeJSONRequestBody defines body for PostPullRequestMerge for application/json ContentType.
+type PostPullRequestMergeJSONRequestBody PostPullRequestMergeJSONBody
+
+// PostPullRequestReassignJSONRequestBody defines body for PostPullRequestReassign for application/json ContentType.
+type PostPullRequestReassignJSONRequestBody PostPullRequestReassignJSONBody
+
+// PostPullRequestRequestChangesJSONRequestBody defines body for PostPullRequestRequestChanges for application/json ContentType.
+type PostPullRequestRequestChangesJSONRequestBody PostPullRequestRequestChangesJSONBody
+
+// PostPullRequestSubscribeJSONRequestBody defines body for PostPullRequestSubscribe for application/json ContentType.
+type PostPullRequestSubscribeJSONRequestBody PostPullRequestSubscribeJSONBody
+
+// PostTeamAddJSONRequestBody defines body for PostTeamAdd for application/json ContentType.
+type PostTeamAddJSONRequestBody = Team
+
+// PostTeamBorrowJSONRequestBody defines body for PostTeamBorrow for application/json ContentType.
+type PostTeamBorrowJSONRequestBody PostTeamBorrowJSONBody
+
+// PostTeamBorrowAcceptJSONRequestBody defines body for PostTeamBorrowAccept for application/json ContentType.
+type PostTeamBorrowAcceptJSONRequestBody PostTeamBorrowAcceptJSONBody
+
+// PostTeamDeactivateJSONRequestBody defines body for PostTeamDeactivate for application/json ContentType.
+type PostTeamDeactivateJSONRequestBody PostTeamDeactivateJSONBody
+
+// PostTeamReactivateJSONRequestBody defines body for PostTeamReactivate for application/json ContentType.
+type PostTeamReactivateJSONRequestBody PostTeamReactivateJSONBody
+
+// PostTeamRenameJSONRequestBody defines body for PostTeamRename for application/json ContentType.
+type PostTeamRenameJSONRequestBody PostTeamRenameJSONBody
+
+// PostTeamRestoreJSONRequestBody defines body for PostTeamRestore for application/json ContentType.
+type PostTeamRestoreJSONRequestBody PostTeamRestoreJSONBody
+
+// PostTeamSetCustomFieldsJSONRequestBody defines body for PostTeamSetCustomFields for application/json ContentType.
+type PostTeamSetCustomFieldsJSONRequestBody PostTeamSetCustomFieldsJSONBody
+
+// PostTeamSetPolicyJSONRequestBody defines body for PostTeamSetPolicy for application/json ContentType.
+type PostTeamSetPolicyJSONRequestBody PostTeamSetPolicyJSONBody
+
+// PostUsersRestoreJSONRequestBody defines body for PostUsersRestore for application/json ContentType.
+type PostUsersRestoreJSONRequestBody PostUsersRestoreJSONBody
+
+// PostUsersSetIsActiveJSONRequestBody defines body for PostUsersSetIsActive for application/json ContentType.
+type PostUsersSetIsActiveJSONRequestBody PostUsersSetIsActiveJSONBody
+
+// PostUsersSetRoleJSONRequestBody defines body for PostUsersSetRole for application/json ContentType.
+type PostUsersSetRoleJSONRequestBody PostUsersSetRoleJSONBody
+
+// PostUsersTransferJSONRequestBody defines body for PostUsersTransfer for application/json ContentType.
+type PostUsersTransferJSONRequestBody PostUsersTransferJSONBody
+
+// PostWebhooksGithubJSONRequestBody defines body for PostWebhooksGithub for application/json ContentType.
+type PostWebhooksGithubJSONRequestBody = GitHubPullRequestEvent
+
+// PostWebhooksGitlabJSONRequestBody defines body for PostWebhooksGitlab for application/json ContentType.
+type PostWebhooksGitlabJSONRequestBody = GitLabMergeRequestEvent
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// API-ключи
+	// (GET /admin/apiKeys)
+	GetAdminApiKeys(w http.ResponseWriter, r *http.Request)
+	// Выдать API-ключ
+	// (POST /admin/apiKeys)
+	PostAdminApiKeys(w http.ResponseWriter, r *http.Request)
+	// Отозвать API-ключ
+	// (DELETE /admin/apiKeys/{key_id})
+	DeleteAdminApiKeysKeyId(w http.ResponseWriter, r *http.Request, keyId int64)
+	// Окна заморозки слияний
+	// (GET /admin/freezes)
+	GetAdminFreezes(w http.ResponseWriter, r *http.Request, params GetAdminFreezesParams)
+	// Создать окно заморозки слияний
+	// (POST /admin/freezes)
+	PostAdminFreezes(w http.ResponseWriter, r *http.Request)
+	// Удалить окно заморозки слияний
+	// (DELETE /admin/freezes/{freeze_id})
+	DeleteAdminFreezesFreezeId(w http.ResponseWriter, r *http.Request, freezeId int64)
+	// Сопоставление пользователей GitLab
+	// (GET /admin/gitlabUsers)
+	GetAdminGitlabUsers(w http.ResponseWriter, r *http.Request)
+	// Сопоставить пользователя GitLab пользователю сервиса
+	// (POST /admin/gitlabUsers)
+	PostAdminGitlabUsers(w http.ResponseWriter, r *http.Request)
+	// Удалить сопоставление пользователя GitLab
+	// (DELETE /admin/gitlabUsers/{gitlab_username})
+	DeleteAdminGitlabUsersGitlabUsername(w http.ResponseWriter, r *http.Request, gitlabUsername string)
+	// Журнал доставки уведомлений
+	// (GET /admin/notifications)
+	GetAdminNotifications(w http.ResponseWriter, r *http.Request, params GetAdminNotificationsParams)
+	// Повторить неудачную доставку уведомления
+	// (POST /admin/notifications/{delivery_id}/retry)
+	PostAdminNotificationsDeliveryIdRetry(w http.ResponseWriter, r *http.Request, deliveryId int64)
+	// Сопоставление субъектов провайдера удостоверений
+	// (GET /admin/oidcSubjects)
+	GetAdminOidcSubjects(w http.ResponseWriter, r *http.Request)
+	// Сопоставить субъект провайдера удостоверений пользователю сервиса
+	// (POST /admin/oidcSubjects)
+	PostAdminOidcSubjects(w http.ResponseWriter, r *http.Request)
+	// Удалить сопоставление субъекта провайдера удостоверений
+	// (DELETE /admin/oidcSubjects/{subject})
+	DeleteAdminOidcSubjectsSubject(w http.ResponseWriter, r *http.Request, subject string)
+	// Токены чтения
+	// (GET /admin/readTokens)
+	GetAdminReadTokens(w http.ResponseWriter, r *http.Request)
+	// Выдать токен чтения
+	// (POST /admin/readTokens)
+	PostAdminReadTokens(w http.ResponseWriter, r *http.Request)
+	// Отозвать токен чтения
+	// (DELETE /admin/readTokens/{token_id})
+	DeleteAdminReadTokensTokenId(w http.ResponseWriter, r *http.Request, tokenId int64)
+	// Сопоставление пользователей Slack
+	// (GET /admin/slackUsers)
+	GetAdminSlackUsers(w http.ResponseWriter, r *http.Request)
+	// Сопоставить пользователю участника Slack
+	// (POST /admin/slackUsers)
+	PostAdminSlackUsers(w http.ResponseWriter, r *http.Request)
+	// Удалить сопоставление пользователя Slack
+	// (DELETE /admin/slackUsers/{user_id})
+	DeleteAdminSlackUsersUserId(w http.ResponseWriter, r *http.Request, userId string)
+	// Пересчитать статистику ревью по истории
+	// (POST /admin/stats/backfill)
+	PostAdminStatsBackfill(w http.ResponseWriter, r *http.Request)
+	// Исходящие вебхуки
+	// (GET /admin/webhooks)
+	GetAdminWebhooks(w http.ResponseWriter, r *http.Request)
+	// Зарегистрировать исходящий вебхук
+	// (POST /admin/webhooks)
+	PostAdminWebhooks(w http.ResponseWriter, r *http.Request)
+	// Удалить исходящий вебхук
+	// (DELETE /admin/webhooks/{webhook_id})
+	DeleteAdminWebhooksWebhookId(w http.ResponseWriter, r *http.Request, webhookId int64)
+	// Исходящий вебхук
+	// (GET /admin/webhooks/{webhook_id})
+	GetAdminWebhooksWebhookId(w http.ResponseWriter, r *http.Request, webhookId int64)
+	// Изменить исходящий вебхук
+	// (PUT /admin/webhooks/{webhook_id})
+	PutAdminWebhooksWebhookId(w http.ResponseWriter, r *http.Request, webhookId int64)
+	// Доставки исходящего вебхука
+	// (GET /admin/webhooks/{webhook_id}/deliveries)
+	GetAdminWebhooksWebhookIdDeliveries(w http.ResponseWriter, r *http.Request, webhookId int64, params GetAdminWebhooksWebhookIdDeliveriesParams)
+	// Повторить доставку в статусе dead
+	// (POST /admin/webhooks/{webhook_id}/deliveries/{delivery_id}/redeliver)
+	PostAdminWebhooksWebhookIdDeliveriesDeliveryIdRedeliver(w http.ResponseWriter, r *http.Request, webhookId int64, deliveryId int64)
+	// Завершить вход через провайдер удостоверений
+	// (GET /auth/callback)
+	GetAuthCallback(w http.ResponseWriter, r *http.Request, params GetAuthCallbackParams)
+	// Войти через провайдер удостоверений
+	// (GET /auth/login)
+	GetAuthLogin(w http.ResponseWriter, r *http.Request)
+	// Создать длительную задачу
+	// (POST /jobs)
+	PostJobs(w http.ResponseWriter, r *http.Request)
+	// Отменить задачу
+	// (DELETE /jobs/{job_id})
+	DeleteJobsJobId(w http.ResponseWriter, r *http.Request, jobId int64)
+	// Состояние и прогресс задачи
+	// (GET /jobs/{job_id})
+	GetJobsJobId(w http.ResponseWriter, r *http.Request, jobId int64)
+	// Получить действующую политику назначения ревьюверов команды
+	// (GET /policy/effective)
+	GetPolicyEffective(w http.ResponseWriter, r *http.Request, params GetPolicyEffectiveParams)
+	// Одобрить PR от имени ревьювера
+	// (POST /pullRequest/approve)
+	PostPullRequestApprove(w http.ResponseWriter, r *http.Request)
+	// Закрыть PR без слияния (идемпотентная операция)
+	// (POST /pullRequest/close)
+	PostPullRequestClose(w http.ResponseWriter, r *http.Request)
+	// Создать PR и автоматически назначить до 2 ревьюверов из команды автора
+	// (POST /pullRequest/create)
+	PostPullRequestCreate(w http.ResponseWriter, r *http.Request, params PostPullRequestCreateParams)
+	// Принять запрос на создание PR для асинхронной обработки
+	// (POST /pullRequest/createAsync)
+	PostPullRequestCreateAsync(w http.ResponseWriter, r *http.Request)
+	// Статус асинхронного создания PR
+	// (GET /pullRequest/createStatus)
+	GetPullRequestCreateStatus(w http.ResponseWriter, r *http.Request, params GetPullRequestCreateStatusParams)
+	// Получить PR с назначенными ревьюверами
+	// (GET /pullRequest/get)
+	GetPullRequestGet(w http.ResponseWriter, r *http.Request, params GetPullRequestGetParams)
+	// История назначений ревьюверов PR
+	// (GET /pullRequest/history)
+	GetPullRequestHistory(w http.ResponseWriter, r *http.Request, params GetPullRequestHistoryParams)
+	// Список PR с фильтрами и пагинацией
+	// (GET /pullRequest/list)
+	GetPullRequestList(w http.ResponseWriter, r *http.Request, params GetPullRequestListParams)
+	// Пометить PR как MERGED (идемпотентная операция)
+	// (POST /pullRequest/merge)
+	PostPullRequestMerge(w http.ResponseWriter, r *http.Request)
+	// Очередь PR, ожидающих назначения ревьюверов
+	// (GET /pullRequest/pending)
+	GetPullRequestPending(w http.ResponseWriter, r *http.Request, params GetPullRequestPendingParams)
+	// Переназначить конкретного ревьювера на другого из команды автора PR
+	// (POST /pullRequest/reassign)
+	PostPullRequestReassign(w http.ResponseWriter, r *http.Request, params PostPullRequestReassignParams)
+	// Запросить изменения в PR от имени ревьювера
+	// (POST /pullRequest/requestChanges)
+	PostPullRequestRequestChanges(w http.ResponseWriter, r *http.Request)
+	// Полнотекстовый поиск PR по названию
+	// (GET /pullRequest/search)
+	GetPullRequestSearch(w http.ResponseWriter, r *http.Request, params GetPullRequestSearchParams)
+	// Подписать пользователя на уведомления о PR (идемпотентная операция)
+	// (POST /pullRequest/subscribe)
+	PostPullRequestSubscribe(w http.ResponseWriter, r *http.Request)
+	// Получить статистику по ревью для всех пользователей
+	// (GET /stats)
+	GetStats(w http.ResponseWriter, r *http.Request, params GetStatsParams)
+	// Получить лидеров ревью за текущую неделю или месяц
+	// (GET /stats/leaderboard)
+	GetStatsLeaderboard(w http.ResponseWriter, r *http.Request, params GetStatsLeaderboardParams)
+	// Удалить команду
+	// (DELETE /team)
+	DeleteTeam(w http.ResponseWriter, r *http.Request, params DeleteTeamParams)
+	// Создать команду с участниками (создаёт/обновляет пользователей)
+	// (POST /team/add)
+	PostTeamAdd(w http.ResponseWriter, r *http.Request)
+	// Запросить ревьюверов у другой команды на время
+	// (POST /team/borrow)
+	PostTeamBorrow(w http.ResponseWriter, r *http.Request)
+	// Принять запрос на ревьюверов
+	// (POST /team/borrow/accept)
+	PostTeamBorrowAccept(w http.ResponseWriter, r *http.Request)
+	// Запросы на ревьюверов, в которых участвует команда
+	// (GET /team/borrows)
+	GetTeamBorrows(w http.ResponseWriter, r *http.Request, params GetTeamBorrowsParams)
+	// Массово деактивировать всех пользователей команды и переназначить их открытые PR
+	// (POST /team/deactivate)
+	PostTeamDeactivate(w http.ResponseWriter, r *http.Request)
+	// Прогресс пакетной деактивации команды
+	// (GET /team/deactivationJob)
+	GetTeamDeactivationJob(w http.ResponseWriter, r *http.Request, params GetTeamDeactivationJobParams)
+	// Получить команду с участниками
+	// (GET /team/get)
+	GetTeamGet(w http.ResponseWriter, r *http.Request, params GetTeamGetParams)
+	// Получить пользовательские поля PR команды
+	// (GET /team/getCustomFields)
+	GetTeamGetCustomFields(w http.ResponseWriter, r *http.Request, params GetTeamGetCustomFieldsParams)
+	// Получить политику назначения ревьюверов команды
+	// (GET /team/getPolicy)
+	GetTeamGetPolicy(w http.ResponseWriter, r *http.Request, params GetTeamGetPolicyParams)
+	// Отменить последнюю деактивацию команды
+	// (POST /team/reactivate)
+	PostTeamReactivate(w http.ResponseWriter, r *http.Request)
+	// Переименовать команду
+	// (POST /team/rename)
+	PostTeamRename(w http.ResponseWriter, r *http.Request)
+	// Восстановить удаленную команду
+	// (POST /team/restore)
+	PostTeamRestore(w http.ResponseWriter, r *http.Request)
+	// Задать пользовательские поля PR команды
+	// (POST /team/setCustomFields)
+	PostTeamSetCustomFields(w http.ResponseWriter, r *http.Request)
+	// Задать политику назначения ревьюверов для команды (веса стратегий)
+	// (POST /team/setPolicy)
+	PostTeamSetPolicy(w http.ResponseWriter, r *http.Request)
+	// Удалить пользователя
+	// (DELETE /users)
+	DeleteUsers(w http.ResponseWriter, r *http.Request, params DeleteUsersParams)
+	// Получить PR'ы, где пользователь назначен ревьювером
+	// (GET /users/getReview)
+	GetUsersGetReview(w http.ResponseWriter, r *http.Request, params GetUsersGetReviewParams)
+	// Восстановить удаленного пользователя
+	// (POST /users/restore)
+	PostUsersRestore(w http.ResponseWriter, r *http.Request)
+	// Установить флаг активности пользователя
+	// (POST /users/setIsActive)
+	PostUsersSetIsActive(w http.ResponseWriter, r *http.Request)
+	// Назначить роль пользователю
+	// (POST /users/setRole)
+	PostUsersSetRole(w http.ResponseWriter, r *http.Request)
+	// Перевести пользователя в другую команду
+	// (POST /users/transfer)
+	PostUsersTransfer(w http.ResponseWriter, r *http.Request)
+	// Принять событие вебхука GitHub
+	// (POST /webhooks/github)
+	PostWebhooksGithub(w http.ResponseWriter, r *http.Request, params PostWebhooksGithubParams)
+	// Принять событие вебхука GitLab
+	// (POST /webhooks/gitlab)
+	PostWebhooksGitlab(w http.ResponseWriter, r *http.Request, params PostWebhooksGitlabParams)
+}
+
+// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+
+type Unimplemented struct{}
+
+// API-ключи
+// (GET /admin/apiKeys)
+func (_ Unimplemented) GetAdminApiKeys(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Выдать API-ключ
+// (POST /admin/apiKeys)
+func (_ Unimplemented) PostAdminApiKeys(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Отозвать API-ключ
+// (DELETE /admin/apiKeys/{key_id})
+func (_ Unimplemented) DeleteAdminApiKeysKeyId(w http.ResponseWriter, r *http.Request, keyId int64) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Окна заморозки слияний
+// (GET /admin/freezes)
+func (_ Unimplemented) GetAdminFreezes(w http.ResponseWriter, r *http.Request, params GetAdminFreezesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Создать окно заморозки слияний
+// (POST /admin/freezes)
+func (_ Unimplemented) PostAdminFreezes(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удалить окно заморозки слияний
+// (DELETE /admin/freezes/{freeze_id})
+func (_ Unimplemented) DeleteAdminFreezesFreezeId(w http.ResponseWriter, r *http.Request, freezeId int64) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Сопоставление пользователей GitLab
+// (GET /admin/gitlabUsers)
+func (_ Unimplemented) GetAdminGitlabUsers(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Сопоставить пользователя GitLab пользователю сервиса
+// (POST /admin/gitlabUsers)
+func (_ Unimplemented) PostAdminGitlabUsers(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удалить сопоставление пользователя GitLab
+// (DELETE /admin/gitlabUsers/{gitlab_username})
+func (_ Unimplemented) DeleteAdminGitlabUsersGitlabUsername(w http.ResponseWriter, r *http.Request, gitlabUsername string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Журнал доставки уведомлений
+// (GET /admin/notifications)
+func (_ Unimplemented) GetAdminNotifications(w http.ResponseWriter, r *http.Request, params GetAdminNotificationsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Повторить неудачную доставку уведомления
+// (POST /admin/notifications/{delivery_id}/retry)
+func (_ Unimplemented) PostAdminNotificationsDeliveryIdRetry(w http.ResponseWriter, r *http.Request, deliveryId int64) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Сопоставление субъектов провайдера удостоверений
+// (GET /admin/oidcSubjects)
+func (_ Unimplemented) GetAdminOidcSubjects(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Сопоставить субъект провайдера удостоверений пользователю сервиса
+// (POST /admin/oidcSubjects)
+func (_ Unimplemented) PostAdminOidcSubjects(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удалить сопоставление субъекта провайдера удостоверений
+// (DELETE /admin/oidcSubjects/{subject})
+func (_ Unimplemented) DeleteAdminOidcSubjectsSubject(w http.ResponseWriter, r *http.Request, subject string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Токены чтения
+// (GET /admin/readTokens)
+func (_ Unimplemented) GetAdminReadTokens(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Выдать токен чтения
+// (POST /admin/readTokens)
+func (_ Unimplemented) PostAdminReadTokens(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Отозвать токен чтения
+// (DELETE /admin/readTokens/{token_id})
+func (_ Unimplemented) DeleteAdminReadTokensTokenId(w http.ResponseWriter, r *http.Request, tokenId int64) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Сопоставление пользователей Slack
+// (GET /admin/slackUsers)
+func (_ Unimplemented) GetAdminSlackUsers(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Сопоставить пользователю участника Slack
+// (POST /admin/slackUsers)
+func (_ Unimplemented) PostAdminSlackUsers(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удалить сопоставление пользователя Slack
+// (DELETE /admin/slackUsers/{user_id})
+func (_ Unimplemented) DeleteAdminSlackUsersUserId(w http.ResponseWriter, r *http.Request, userId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Пересчитать статистику ревью по истории
+// (POST /admin/stats/backfill)
+func (_ Unimplemented) PostAdminStatsBackfill(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Исходящие вебхуки
+// (GET /admin/webhooks)
+func (_ Unimplemented) GetAdminWebhooks(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Зарегистрировать исходящий вебхук
+// (POST /admin/webhooks)
+func (_ Unimplemented) PostAdminWebhooks(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удалить исходящий вебхук
+// (DELETE /admin/webhooks/{webhook_id})
+func (_ Unimplemented) DeleteAdminWebhooksWebhookId(w http.ResponseWriter, r *http.Request, webhookId int64) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Исходящий вебхук
+// (GET /admin/webhooks/{webhook_id})
+func (_ Unimplemented) GetAdminWebhooksWebhookId(w http.ResponseWriter, r *http.Request, webhookId int64) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Изменить исходящий вебхук
+// (PUT /admin/webhooks/{webhook_id})
+func (_ Unimplemented) PutAdminWebhooksWebhookId(w http.ResponseWriter, r *http.Request, webhookId int64) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Доставки исходящего вебхука
+// (GET /admin/webhooks/{webhook_id}/deliveries)
+func (_ Unimplemented) GetAdminWebhooksWebhookIdDeliveries(w http.ResponseWriter, r *http.Request, webhookId int64, params GetAdminWebhooksWebhookIdDeliveriesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Повторить доставку в статусе dead
+// (POST /admin/webhooks/{webhook_id}/deliveries/{delivery_id}/redeliver)
+func (_ Unimplemented) PostAdminWebhooksWebhookIdDeliveriesDeliveryIdRedeliver(w http.ResponseWriter, r *http.Request, webhookId int64, deliveryId int64) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Завершить вход через провайдер удостоверений
+// (GET /auth/callback)
+func (_ Unimplemented) GetAuthCallback(w http.ResponseWriter, r *http.Request, params GetAuthCallbackParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Войти через провайдер удостоверений
+// (GET /auth/login)
+func (_ Unimplemented) GetAuthLogin(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Создать длительную задачу
+// (POST /jobs)
+func (_ Unimplemented) PostJobs(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Отменить задачу
+// (DELETE /jobs/{job_id})
+func (_ Unimplemented) DeleteJobsJobId(w http.ResponseWriter, r *http.Request, jobId int64) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Состояние и прогресс задачи
+// (GET /jobs/{job_id})
+func (_ Unimplemented) GetJobsJobId(w http.ResponseWriter, r *http.Request, jobId int64) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить действующую политику назначения ревьюверов команды
+// (GET /policy/effective)
+func (_ Unimplemented) GetPolicyEffective(w http.ResponseWriter, r *http.Request, params GetPolicyEffectiveParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Одобрить PR от имени ревьювера
+// (POST /pullRequest/approve)
+func (_ Unimplemented) PostPullRequestApprove(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Закрыть PR без слияния (идемпотентная операция)
+// (POST /pullRequest/close)
+func (_ Unimplemented) PostPullRequestClose(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Создать PR и автоматически назначить до 2 ревьюверов из команды автора
+// (POST /pullRequest/create)
+func (_ Unimplemented) PostPullRequestCreate(w http.ResponseWriter, r *http.Request, params PostPullRequestCreateParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Принять запрос на создание PR для асинхронной обработки
+// (POST /pullRequest/createAsync)
+func (_ Unimplemented) PostPullRequestCreateAsync(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Статус асинхронного создания PR
+// (GET /pullRequest/createStatus)
+func (_ Unimplemented) GetPullRequestCreateStatus(w http.ResponseWriter, r *http.Request, params GetPullRequestCreateStatusParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить PR с назначенными ревьюверами
+// (GET /pullRequest/get)
+func (_ Unimplemented) GetPullRequestGet(w http.ResponseWriter, r *http.Request, params GetPullRequestGetParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// История назначений ревьюверов PR
+// (GET /pullRequest/history)
+func (_ Unimplemented) GetPullRequestHistory(w http.ResponseWriter, r *http.Request, params GetPullRequestHistoryParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Список PR с фильтрами и пагинацией
+// (GET /pullRequest/list)
+func (_ Unimplemented) GetPullRequestList(w http.ResponseWriter, r *http.Request, params GetPullRequestListParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Пометить PR как MERGED (идемпотентная операция)
+// (POST /pullRequest/merge)
+func (_ Unimplemented) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Очередь PR, ожидающих назначения ревьюверов
+// (GET /pullRequest/pending)
+func (_ Unimplemented) GetPullRequestPending(w http.ResponseWriter, r *http.Request, params GetPullRequestPendingParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Переназначить конкретного ревьювера на другого из команды автора PR
+// (POST /pullRequest/reassign)
+func (_ Unimplemented) PostPullRequestReassign(w http.ResponseWriter, r *http.Request, params PostPullRequestReassignParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Запросить изменения в PR от имени ревьювера
+// (POST /pullRequest/requestChanges)
+func (_ Unimplemented) PostPullRequestRequestChanges(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Полнотекстовый поиск PR по названию
+// (GET /pullRequest/search)
+func (_ Unimplemented) GetPullRequestSearch(w http.ResponseWriter, r *http.Request, params GetPullRequestSearchParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Подписать пользователя на уведомления о PR (идемпотентная операция)
+// (POST /pullRequest/subscribe)
+func (_ Unimplemented) PostPullRequestSubscribe(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить статистику по ревью для всех пользователей
+// (GET /stats)
+func (_ Unimplemented) GetStats(w http.ResponseWriter, r *http.Request, params GetStatsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить лидеров ревью за текущую неделю или месяц
+// (GET /stats/leaderboard)
+func (_ Unimplemented) GetStatsLeaderboard(w http.ResponseWriter, r *http.Request, params GetStatsLeaderboardParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удалить команду
+// (DELETE /team)
+func (_ Unimplemented) DeleteTeam(w http.ResponseWriter, r *http.Request, params DeleteTeamParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Создать команду с участниками (создаёт/обновляет пользователей)
+// (POST /team/add)
+func (_ Unimplemented) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Запросить ревьюверов у другой команды на время
+// (POST /team/borrow)
+func (_ Unimplemented) PostTeamBorrow(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Принять запрос на ревьюверов
+// (POST /team/borrow/accept)
+func (_ Unimplemented) PostTeamBorrowAccept(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Запросы на ревьюверов, в которых участвует команда
+// (GET /team/borrows)
+func (_ Unimplemented) GetTeamBorrows(w http.ResponseWriter, r *http.Request, params GetTeamBorrowsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Массово деактивировать всех пользователей команды и переназначить их открытые PR
+// (POST /team/deactivate)
+func (_ Unimplemented) PostTeamDeactivate(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Прогресс пакетной деактивации команды
+// (GET /team/deactivationJob)
+func (_ Unimplemented) GetTeamDeactivationJob(w http.ResponseWriter, r *http.Request, params GetTeamDeactivationJobParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить команду с участниками
+// (GET /team/get)
+func (_ Unimplemented) GetTeamGet(w http.ResponseWriter, r *http.Request, params GetTeamGetParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить пользовательские поля PR команды
+// (GET /team/getCustomFields)
+func (_ Unimplemented) GetTeamGetCustomFields(w http.ResponseWriter, r *http.Request, params GetTeamGetCustomFieldsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить политику назначения ревьюверов команды
+// (GET /team/getPolicy)
+func (_ Unimplemented) GetTeamGetPolicy(w http.ResponseWriter, r *http.Request, params GetTeamGetPolicyParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Отменить последнюю деактивацию команды
+// (POST /team/reactivate)
+func (_ Unimplemented) PostTeamReactivate(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Переименовать команду
+// (POST /team/rename)
+func (_ Unimplemented) PostTeamRename(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Восстановить удаленную команду
+// (POST /team/restore)
+func (_ Unimplemented) PostTeamRestore(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Задать пользовательские поля PR команды
+// (POST /team/setCustomFields)
+func (_ Unimplemented) PostTeamSetCustomFields(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Задать политику назначения ревьюверов для команды (веса стратегий)
+// (POST /team/setPolicy)
+func (_ Unimplemented) PostTeamSetPolicy(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удалить пользователя
+// (DELETE /users)
+func (_ Unimplemented) DeleteUsers(w http.ResponseWriter, r *http.Request, params DeleteUsersParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить PR'ы, где пользователь назначен ревьювером
 // (GET /users/getReview)
 func (_ Unimplemented) GetUsersGetReview(w http.ResponseWriter, r *http.Request, params GetUsersGetReviewParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// Установить флаг активности пользователя
-// (POST /users/setIsActive)
-func (_ Unimplemented) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
+// Восстановить удаленного пользователя
+// (POST /users/restore)
+func (_ Unimplemented) PostUsersRestore(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Установить флаг активности пользователя
+// (POST /users/setIsActive)
+func (_ Unimplemented) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Назначить роль пользователю
+// (POST /users/setRole)
+func (_ Unimplemented) PostUsersSetRole(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Перевести пользователя в другую команду
+// (POST /users/transfer)
+func (_ Unimplemented) PostUsersTransfer(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Принять событие вебхука GitHub
+// (POST /webhooks/github)
+func (_ Unimplemented) PostWebhooksGithub(w http.ResponseWriter, r *http.Request, params PostWebhooksGithubParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Принять событие вебхука GitLab
+// (POST /webhooks/gitlab)
+func (_ Unimplemented) PostWebhooksGitlab(w http.ResponseWriter, r *http.Request, params PostWebhooksGitlabParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// GetAdminApiKeys operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminApiKeys(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminApiKeys(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAdminApiKeys operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminApiKeys(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminApiKeys(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteAdminApiKeysKeyId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAdminApiKeysKeyId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "key_id" -------------
+	var keyId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "key_id", chi.URLParam(r, "key_id"), &keyId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "key_id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAdminApiKeysKeyId(w, r, keyId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAdminFreezes operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminFreezes(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAdminFreezesParams
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "team_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_id", r.URL.Query(), &params.TeamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "include_ended" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_ended", r.URL.Query(), &params.IncludeEnded)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_ended", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminFreezes(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAdminFreezes operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminFreezes(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminFreezes(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteAdminFreezesFreezeId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAdminFreezesFreezeId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "freeze_id" -------------
+	var freezeId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "freeze_id", chi.URLParam(r, "freeze_id"), &freezeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "freeze_id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAdminFreezesFreezeId(w, r, freezeId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAdminGitlabUsers operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminGitlabUsers(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminGitlabUsers(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAdminGitlabUsers operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminGitlabUsers(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminGitlabUsers(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteAdminGitlabUsersGitlabUsername operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAdminGitlabUsersGitlabUsername(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "gitlab_username" -------------
+	var gitlabUsername string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "gitlab_username", chi.URLParam(r, "gitlab_username"), &gitlabUsername, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "gitlab_username", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAdminGitlabUsersGitlabUsername(w, r, gitlabUsername)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAdminNotifications operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminNotifications(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAdminNotificationsParams
+
+	// ------------- Optional query parameter "channel" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "channel", r.URL.Query(), &params.Channel)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "channel", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "recipient_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "recipient_id", r.URL.Query(), &params.RecipientId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "recipient_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "pull_request_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pull_request_id", r.URL.Query(), &params.PullRequestId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "pull_request_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "event" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "event", r.URL.Query(), &params.Event)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "event", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminNotifications(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAdminNotificationsDeliveryIdRetry operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminNotificationsDeliveryIdRetry(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "delivery_id" -------------
+	var deliveryId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "delivery_id", chi.URLParam(r, "delivery_id"), &deliveryId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "delivery_id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminNotificationsDeliveryIdRetry(w, r, deliveryId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAdminOidcSubjects operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminOidcSubjects(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminOidcSubjects(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAdminOidcSubjects operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminOidcSubjects(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminOidcSubjects(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteAdminOidcSubjectsSubject operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAdminOidcSubjectsSubject(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "subject" -------------
+	var subject string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "subject", chi.URLParam(r, "subject"), &subject, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "subject", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAdminOidcSubjectsSubject(w, r, subject)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAdminReadTokens operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminReadTokens(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminReadTokens(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAdminReadTokens operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminReadTokens(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminReadTokens(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteAdminReadTokensTokenId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAdminReadTokensTokenId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "token_id" -------------
+	var tokenId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "token_id", chi.URLParam(r, "token_id"), &tokenId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token_id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAdminReadTokensTokenId(w, r, tokenId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAdminSlackUsers operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminSlackUsers(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminSlackUsers(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAdminSlackUsers operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminSlackUsers(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminSlackUsers(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteAdminSlackUsersUserId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAdminSlackUsersUserId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "user_id" -------------
+	var userId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "user_id", chi.URLParam(r, "user_id"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAdminSlackUsersUserId(w, r, userId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAdminStatsBackfill operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminStatsBackfill(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminStatsBackfill(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAdminWebhooks operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminWebhooks(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminWebhooks(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAdminWebhooks operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminWebhooks(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminWebhooks(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteAdminWebhooksWebhookId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAdminWebhooksWebhookId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "webhook_id", chi.URLParam(r, "webhook_id"), &webhookId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "webhook_id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAdminWebhooksWebhookId(w, r, webhookId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAdminWebhooksWebhookId operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminWebhooksWebhookId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "webhook_id", chi.URLParam(r, "webhook_id"), &webhookId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "webhook_id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminWebhooksWebhookId(w, r, webhookId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PutAdminWebhooksWebhookId operation middleware
+func (siw *ServerInterfaceWrapper) PutAdminWebhooksWebhookId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "webhook_id", chi.URLParam(r, "webhook_id"), &webhookId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "webhook_id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PutAdminWebhooksWebhookId(w, r, webhookId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAdminWebhooksWebhookIdDeliveries operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminWebhooksWebhookIdDeliveries(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "webhook_id", chi.URLParam(r, "webhook_id"), &webhookId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "webhook_id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAdminWebhooksWebhookIdDeliveriesParams
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminWebhooksWebhookIdDeliveries(w, r, webhookId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostAdminWebhooksWebhookIdDeliveriesDeliveryIdRedeliver operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminWebhooksWebhookIdDeliveriesDeliveryIdRedeliver(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "webhook_id", chi.URLParam(r, "webhook_id"), &webhookId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "webhook_id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "delivery_id" -------------
+	var deliveryId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "delivery_id", chi.URLParam(r, "delivery_id"), &deliveryId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "delivery_id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminWebhooksWebhookIdDeliveriesDeliveryIdRedeliver(w, r, webhookId, deliveryId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAuthCallback operation middleware
+func (siw *ServerInterfaceWrapper) GetAuthCallback(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAuthCallbackParams
+
+	// ------------- Required query parameter "code" -------------
+
+	if paramValue := r.URL.Query().Get("code"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "code"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "code", r.URL.Query(), &params.Code)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "code", Err: err})
+		return
+	}
+
+	// ------------- Required query parameter "state" -------------
+
+	if paramValue := r.URL.Query().Get("state"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "state"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "state", r.URL.Query(), &params.State)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "state", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAuthCallback(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAuthLogin operation middleware
+func (siw *ServerInterfaceWrapper) GetAuthLogin(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAuthLogin(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostJobs operation middleware
+func (siw *ServerInterfaceWrapper) PostJobs(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostJobs(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteJobsJobId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteJobsJobId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "job_id" -------------
+	var jobId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "job_id", chi.URLParam(r, "job_id"), &jobId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "job_id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteJobsJobId(w, r, jobId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetJobsJobId operation middleware
+func (siw *ServerInterfaceWrapper) GetJobsJobId(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "job_id" -------------
+	var jobId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "job_id", chi.URLParam(r, "job_id"), &jobId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "job_id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetJobsJobId(w, r, jobId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetPolicyEffective operation middleware
+func (siw *ServerInterfaceWrapper) GetPolicyEffective(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	ctx = context.WithValue(ctx, UserTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPolicyEffectiveParams
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "team_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_id", r.URL.Query(), &params.TeamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPolicyEffective(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestApprove operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestApprove(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestApprove(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestClose operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestClose(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestClose(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestCreate operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPullRequestCreateParams
+
+	// ------------- Optional query parameter "expand" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "expand", r.URL.Query(), &params.Expand)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "expand", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "generate_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "generate_id", r.URL.Query(), &params.GenerateId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "generate_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestCreate(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestCreateAsync operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestCreateAsync(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestCreateAsync(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetPullRequestCreateStatus operation middleware
+func (siw *ServerInterfaceWrapper) GetPullRequestCreateStatus(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	ctx = context.WithValue(ctx, UserTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestCreateStatusParams
+
+	// ------------- Required query parameter "request_id" -------------
+
+	if paramValue := r.URL.Query().Get("request_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "request_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "request_id", r.URL.Query(), &params.RequestId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "request_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPullRequestCreateStatus(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetPullRequestGet operation middleware
+func (siw *ServerInterfaceWrapper) GetPullRequestGet(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestGetParams
+
+	// ------------- Required query parameter "pull_request_id" -------------
+
+	if paramValue := r.URL.Query().Get("pull_request_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "pull_request_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "pull_request_id", r.URL.Query(), &params.PullRequestId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "pull_request_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "expand" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "expand", r.URL.Query(), &params.Expand)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "expand", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPullRequestGet(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetPullRequestHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetPullRequestHistory(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestHistoryParams
+
+	// ------------- Required query parameter "pull_request_id" -------------
+
+	if paramValue := r.URL.Query().Get("pull_request_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "pull_request_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "pull_request_id", r.URL.Query(), &params.PullRequestId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "pull_request_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPullRequestHistory(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetPullRequestList operation middleware
+func (siw *ServerInterfaceWrapper) GetPullRequestList(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestListParams
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "author_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "author_id", r.URL.Query(), &params.AuthorId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "author_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "team_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_id", r.URL.Query(), &params.TeamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "created_from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_from", r.URL.Query(), &params.CreatedFrom)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "created_from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "created_to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_to", r.URL.Query(), &params.CreatedTo)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "created_to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "fields" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "fields", r.URL.Query(), &params.Fields)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "fields", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPullRequestList(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestMerge operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestMerge(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetPullRequestPending operation middleware
+func (siw *ServerInterfaceWrapper) GetPullRequestPending(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestPendingParams
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "team_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_id", r.URL.Query(), &params.TeamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPullRequestPending(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestReassign operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPullRequestReassignParams
+
+	// ------------- Optional query parameter "expand" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "expand", r.URL.Query(), &params.Expand)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "expand", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestReassign(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestRequestChanges operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestRequestChanges(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestRequestChanges(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetPullRequestSearch operation middleware
+func (siw *ServerInterfaceWrapper) GetPullRequestSearch(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestSearchParams
+
+	// ------------- Required query parameter "query" -------------
+
+	if paramValue := r.URL.Query().Get("query"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "query"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "query", r.URL.Query(), &params.Query)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "query", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "custom_field" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "custom_field", r.URL.Query(), &params.CustomField)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "custom_field", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPullRequestSearch(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestSubscribe operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestSubscribe(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestSubscribe(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetStats operation middleware
+func (siw *ServerInterfaceWrapper) GetStats(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsParams
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "fields" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "fields", r.URL.Query(), &params.Fields)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "fields", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStats(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetStatsLeaderboard operation middleware
+func (siw *ServerInterfaceWrapper) GetStatsLeaderboard(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsLeaderboardParams
+
+	// ------------- Required query parameter "period" -------------
+
+	if paramValue := r.URL.Query().Get("period"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "period"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "period", r.URL.Query(), &params.Period)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "period", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStatsLeaderboard(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteTeam operation middleware
+func (siw *ServerInterfaceWrapper) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteTeamParams
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "team_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_id", r.URL.Query(), &params.TeamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "force" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "force", r.URL.Query(), &params.Force)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "force", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteTeam(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTeamAdd operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamAdd(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTeamBorrow operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamBorrow(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamBorrow(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTeamBorrowAccept operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamBorrowAccept(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamBorrowAccept(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTeamBorrows operation middleware
+func (siw *ServerInterfaceWrapper) GetTeamBorrows(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	ctx = context.WithValue(ctx, UserTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTeamBorrowsParams
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "team_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_id", r.URL.Query(), &params.TeamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTeamBorrows(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTeamDeactivate operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamDeactivate(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamDeactivate(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
 }
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler            ServerInterface
-	HandlerMiddlewares []MiddlewareFunc
-	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+// GetTeamDeactivationJob operation middleware
+func (siw *ServerInterfaceWrapper) GetTeamDeactivationJob(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTeamDeactivationJobParams
+
+	// ------------- Required query parameter "job_id" -------------
+
+	if paramValue := r.URL.Query().Get("job_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "job_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "job_id", r.URL.Query(), &params.JobId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "job_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTeamDeactivationJob(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
 }
 
-type MiddlewareFunc func(http.Handler) http.Handler
+// GetTeamGet operation middleware
+func (siw *ServerInterfaceWrapper) GetTeamGet(w http.ResponseWriter, r *http.Request) {
 
-// PostPullRequestCreate operation middleware
-func (siw *ServerInterfaceWrapper) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	ctx = context.WithValue(ctx, UserTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTeamGetParams
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "team_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_id", r.URL.Query(), &params.TeamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "fields" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "fields", r.URL.Query(), &params.Fields)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "fields", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTeamGet(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTeamGetCustomFields operation middleware
+func (siw *ServerInterfaceWrapper) GetTeamGetCustomFields(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	ctx = context.WithValue(ctx, UserTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTeamGetCustomFieldsParams
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "team_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_id", r.URL.Query(), &params.TeamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTeamGetCustomFields(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTeamGetPolicy operation middleware
+func (siw *ServerInterfaceWrapper) GetTeamGetPolicy(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	ctx = context.WithValue(ctx, UserTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTeamGetPolicyParams
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "team_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_id", r.URL.Query(), &params.TeamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTeamGetPolicy(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTeamReactivate operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamReactivate(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamReactivate(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTeamRename operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamRename(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamRename(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTeamRestore operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamRestore(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamRestore(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTeamSetCustomFields operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamSetCustomFields(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamSetCustomFields(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTeamSetPolicy operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamSetPolicy(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
@@ -310,7 +4859,47 @@ func (siw *ServerInterfaceWrapper) PostPullRequestCreate(w http.ResponseWriter,
 	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostPullRequestCreate(w, r)
+		siw.Handler.PostTeamSetPolicy(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteUsers operation middleware
+func (siw *ServerInterfaceWrapper) DeleteUsers(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteUsersParams
+
+	// ------------- Required query parameter "user_id" -------------
+
+	if paramValue := r.URL.Query().Get("user_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "user_id", r.URL.Query(), &params.UserId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteUsers(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -320,17 +4909,47 @@ func (siw *ServerInterfaceWrapper) PostPullRequestCreate(w http.ResponseWriter,
 	handler.ServeHTTP(w, r)
 }
 
-// PostPullRequestMerge operation middleware
-func (siw *ServerInterfaceWrapper) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
+// GetUsersGetReview operation middleware
+func (siw *ServerInterfaceWrapper) GetUsersGetReview(w http.ResponseWriter, r *http.Request) {
+
+	var err error
 
 	ctx := r.Context()
 
 	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
 
+	ctx = context.WithValue(ctx, UserTokenScopes, []string{})
+
 	r = r.WithContext(ctx)
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsersGetReviewParams
+
+	// ------------- Required query parameter "user_id" -------------
+
+	if paramValue := r.URL.Query().Get("user_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "user_id", r.URL.Query(), &params.UserId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "custom_field" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "custom_field", r.URL.Query(), &params.CustomField)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "custom_field", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostPullRequestMerge(w, r)
+		siw.Handler.GetUsersGetReview(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -340,8 +4959,8 @@ func (siw *ServerInterfaceWrapper) PostPullRequestMerge(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r)
 }
 
-// PostPullRequestReassign operation middleware
-func (siw *ServerInterfaceWrapper) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
+// PostUsersRestore operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersRestore(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
@@ -350,7 +4969,7 @@ func (siw *ServerInterfaceWrapper) PostPullRequestReassign(w http.ResponseWriter
 	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostPullRequestReassign(w, r)
+		siw.Handler.PostUsersRestore(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -360,25 +4979,17 @@ func (siw *ServerInterfaceWrapper) PostPullRequestReassign(w http.ResponseWriter
 	handler.ServeHTTP(w, r)
 }
 
-// GetStats operation middleware
-func (siw *ServerInterfaceWrapper) GetStats(w http.ResponseWriter, r *http.Request) {
-
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetStats(w, r)
-	}))
+// PostUsersSetIsActive operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+	ctx := r.Context()
 
-	handler.ServeHTTP(w, r)
-}
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
 
-// PostTeamAdd operation middleware
-func (siw *ServerInterfaceWrapper) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
+	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostTeamAdd(w, r)
+		siw.Handler.PostUsersSetIsActive(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -388,11 +4999,17 @@ func (siw *ServerInterfaceWrapper) PostTeamAdd(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r)
 }
 
-// PostTeamDeactivate operation middleware
-func (siw *ServerInterfaceWrapper) PostTeamDeactivate(w http.ResponseWriter, r *http.Request) {
+// PostUsersSetRole operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersSetRole(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+
+	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostTeamDeactivate(w, r)
+		siw.Handler.PostUsersSetRole(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -402,39 +5019,17 @@ func (siw *ServerInterfaceWrapper) PostTeamDeactivate(w http.ResponseWriter, r *
 	handler.ServeHTTP(w, r)
 }
 
-// GetTeamGet operation middleware
-func (siw *ServerInterfaceWrapper) GetTeamGet(w http.ResponseWriter, r *http.Request) {
-
-	var err error
+// PostUsersTransfer operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersTransfer(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
 	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
 
-	ctx = context.WithValue(ctx, UserTokenScopes, []string{})
-
 	r = r.WithContext(ctx)
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetTeamGetParams
-
-	// ------------- Required query parameter "team_name" -------------
-
-	if paramValue := r.URL.Query().Get("team_name"); paramValue != "" {
-
-	} else {
-		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "team_name"})
-		return
-	}
-
-	err = runtime.BindQueryParameter("form", true, true, "team_name", r.URL.Query(), &params.TeamName)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
-		return
-	}
-
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetTeamGet(w, r, params)
+		siw.Handler.PostUsersTransfer(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -444,39 +5039,41 @@ func (siw *ServerInterfaceWrapper) GetTeamGet(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r)
 }
 
-// GetUsersGetReview operation middleware
-func (siw *ServerInterfaceWrapper) GetUsersGetReview(w http.ResponseWriter, r *http.Request) {
+// PostWebhooksGithub operation middleware
+func (siw *ServerInterfaceWrapper) PostWebhooksGithub(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
-	ctx := r.Context()
-
-	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
-
-	ctx = context.WithValue(ctx, UserTokenScopes, []string{})
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostWebhooksGithubParams
 
-	r = r.WithContext(ctx)
+	headers := r.Header
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetUsersGetReviewParams
+	// ------------- Required header parameter "X-GitHub-Event" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-GitHub-Event")]; found {
+		var XGitHubEvent string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-GitHub-Event", Count: n})
+			return
+		}
 
-	// ------------- Required query parameter "user_id" -------------
+		err = runtime.BindStyledParameterWithOptions("simple", "X-GitHub-Event", valueList[0], &XGitHubEvent, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-GitHub-Event", Err: err})
+			return
+		}
 
-	if paramValue := r.URL.Query().Get("user_id"); paramValue != "" {
+		params.XGitHubEvent = XGitHubEvent
 
 	} else {
-		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_id"})
-		return
-	}
-
-	err = runtime.BindQueryParameter("form", true, true, "user_id", r.URL.Query(), &params.UserId)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		err := fmt.Errorf("Header parameter X-GitHub-Event is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "X-GitHub-Event", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetUsersGetReview(w, r, params)
+		siw.Handler.PostWebhooksGithub(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -486,17 +5083,41 @@ func (siw *ServerInterfaceWrapper) GetUsersGetReview(w http.ResponseWriter, r *h
 	handler.ServeHTTP(w, r)
 }
 
-// PostUsersSetIsActive operation middleware
-func (siw *ServerInterfaceWrapper) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
+// PostWebhooksGitlab operation middleware
+func (siw *ServerInterfaceWrapper) PostWebhooksGitlab(w http.ResponseWriter, r *http.Request) {
 
-	ctx := r.Context()
+	var err error
 
-	ctx = context.WithValue(ctx, AdminTokenScopes, []string{})
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostWebhooksGitlabParams
 
-	r = r.WithContext(ctx)
+	headers := r.Header
+
+	// ------------- Required header parameter "X-Gitlab-Event" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Gitlab-Event")]; found {
+		var XGitlabEvent string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Gitlab-Event", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Gitlab-Event", valueList[0], &XGitlabEvent, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Gitlab-Event", Err: err})
+			return
+		}
+
+		params.XGitlabEvent = XGitlabEvent
+
+	} else {
+		err := fmt.Errorf("Header parameter X-Gitlab-Event is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "X-Gitlab-Event", Err: err})
+		return
+	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostUsersSetIsActive(w, r)
+		siw.Handler.PostWebhooksGitlab(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -619,33 +5240,225 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/apiKeys", wrapper.GetAdminApiKeys)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/apiKeys", wrapper.PostAdminApiKeys)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/apiKeys/{key_id}", wrapper.DeleteAdminApiKeysKeyId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/freezes", wrapper.GetAdminFreezes)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/freezes", wrapper.PostAdminFreezes)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/freezes/{freeze_id}", wrapper.DeleteAdminFreezesFreezeId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/gitlabUsers", wrapper.GetAdminGitlabUsers)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/gitlabUsers", wrapper.PostAdminGitlabUsers)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/gitlabUsers/{gitlab_username}", wrapper.DeleteAdminGitlabUsersGitlabUsername)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/notifications", wrapper.GetAdminNotifications)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/notifications/{delivery_id}/retry", wrapper.PostAdminNotificationsDeliveryIdRetry)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/oidcSubjects", wrapper.GetAdminOidcSubjects)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/oidcSubjects", wrapper.PostAdminOidcSubjects)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/oidcSubjects/{subject}", wrapper.DeleteAdminOidcSubjectsSubject)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/readTokens", wrapper.GetAdminReadTokens)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/readTokens", wrapper.PostAdminReadTokens)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/readTokens/{token_id}", wrapper.DeleteAdminReadTokensTokenId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/slackUsers", wrapper.GetAdminSlackUsers)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/slackUsers", wrapper.PostAdminSlackUsers)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/slackUsers/{user_id}", wrapper.DeleteAdminSlackUsersUserId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/stats/backfill", wrapper.PostAdminStatsBackfill)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/webhooks", wrapper.GetAdminWebhooks)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/webhooks", wrapper.PostAdminWebhooks)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/webhooks/{webhook_id}", wrapper.DeleteAdminWebhooksWebhookId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/webhooks/{webhook_id}", wrapper.GetAdminWebhooksWebhookId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/admin/webhooks/{webhook_id}", wrapper.PutAdminWebhooksWebhookId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/webhooks/{webhook_id}/deliveries", wrapper.GetAdminWebhooksWebhookIdDeliveries)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/webhooks/{webhook_id}/deliveries/{delivery_id}/redeliver", wrapper.PostAdminWebhooksWebhookIdDeliveriesDeliveryIdRedeliver)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/auth/callback", wrapper.GetAuthCallback)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/auth/login", wrapper.GetAuthLogin)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/jobs", wrapper.PostJobs)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/jobs/{job_id}", wrapper.DeleteJobsJobId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/jobs/{job_id}", wrapper.GetJobsJobId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/policy/effective", wrapper.GetPolicyEffective)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/pullRequest/approve", wrapper.PostPullRequestApprove)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/pullRequest/close", wrapper.PostPullRequestClose)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/pullRequest/create", wrapper.PostPullRequestCreate)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/pullRequest/createAsync", wrapper.PostPullRequestCreateAsync)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/pullRequest/createStatus", wrapper.GetPullRequestCreateStatus)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/pullRequest/get", wrapper.GetPullRequestGet)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/pullRequest/history", wrapper.GetPullRequestHistory)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/pullRequest/list", wrapper.GetPullRequestList)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/pullRequest/merge", wrapper.PostPullRequestMerge)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/pullRequest/pending", wrapper.GetPullRequestPending)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/pullRequest/reassign", wrapper.PostPullRequestReassign)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/pullRequest/requestChanges", wrapper.PostPullRequestRequestChanges)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/pullRequest/search", wrapper.GetPullRequestSearch)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/pullRequest/subscribe", wrapper.PostPullRequestSubscribe)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/stats", wrapper.GetStats)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/stats/leaderboard", wrapper.GetStatsLeaderboard)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/team", wrapper.DeleteTeam)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/team/add", wrapper.PostTeamAdd)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/borrow", wrapper.PostTeamBorrow)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/borrow/accept", wrapper.PostTeamBorrowAccept)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/team/borrows", wrapper.GetTeamBorrows)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/team/deactivate", wrapper.PostTeamDeactivate)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/team/deactivationJob", wrapper.GetTeamDeactivationJob)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/team/get", wrapper.GetTeamGet)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/team/getCustomFields", wrapper.GetTeamGetCustomFields)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/team/getPolicy", wrapper.GetTeamGetPolicy)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/reactivate", wrapper.PostTeamReactivate)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/rename", wrapper.PostTeamRename)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/restore", wrapper.PostTeamRestore)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/setCustomFields", wrapper.PostTeamSetCustomFields)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/setPolicy", wrapper.PostTeamSetPolicy)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/users", wrapper.DeleteUsers)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/users/getReview", wrapper.GetUsersGetReview)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/restore", wrapper.PostUsersRestore)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/users/setIsActive", wrapper.PostUsersSetIsActive)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/setRole", wrapper.PostUsersSetRole)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/transfer", wrapper.PostUsersTransfer)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/webhooks/github", wrapper.PostWebhooksGithub)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/webhooks/gitlab", wrapper.PostWebhooksGitlab)
+	})
 
 	return r
 }
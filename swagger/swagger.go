@@ -1,19 +1,92 @@
 package swagger
 
 import (
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
 	"io/fs"
 	"net/http"
+	"path"
+	"strings"
 )
 
 //go:embed swagger-ui/*
 var content embed.FS
 
+// Cache-Control values of the assets. The pages and the spec change with every release under the same names,
+// so the browser revalidates them by their ETag; the bundled swagger-ui scripts and styles change only
+// with an upgrade of swagger-ui and are cached for a day.
+const (
+	revalidateCacheControl = "no-cache"
+	assetCacheControl      = "public, max-age=86400"
+)
+
+// revalidated lists the assets that must be revalidated on every use.
+var revalidated = map[string]bool{
+	"index.html":             true,
+	"swagger-initializer.js": true,
+	"openapi.yml":            true,
+}
+
+// GetHandler serves the swagger-ui assets with Cache-Control headers and strong ETags,
+// so that a browser reloading the UI gets 304 Not Modified instead of the bundles.
 func GetHandler() (http.Handler, error) {
 	subFS, err := fs.Sub(content, "swagger-ui")
 	if err != nil {
 		return nil, err
 	}
 
-	return http.FileServer(http.FS(subFS)), nil
+	etags, err := hashFiles(subFS)
+	if err != nil {
+		return nil, err
+	}
+
+	files := http.FileServer(http.FS(subFS))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		if name == "" {
+			name = "index.html"
+		}
+
+		if etag, ok := etags[name]; ok {
+			// http.FileServer answers If-None-Match with 304 once the ETag is set.
+			w.Header().Set("ETag", etag)
+
+			if revalidated[name] {
+				w.Header().Set("Cache-Control", revalidateCacheControl)
+			} else {
+				w.Header().Set("Cache-Control", assetCacheControl)
+			}
+		}
+
+		files.ServeHTTP(w, r)
+	}), nil
+}
+
+// hashFiles returns the ETag of every file in fsys by its path. The files are embedded,
+// so the hashes are computed once.
+func hashFiles(fsys fs.FS) (map[string]string, error) {
+	etags := map[string]string{}
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(data)
+		etags[name] = `"` + hex.EncodeToString(sum[:16]) + `"`
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return etags, nil
 }
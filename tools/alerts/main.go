@@ -0,0 +1,74 @@
+// Command alerts generates Prometheus burn-rate alerting rules from the SLO section of a config file.
+//
+// Run it after changing the objectives in the config:
+//
+//	go run ./tools/alerts -config config/docker.yml -out slo-alerts.yml
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/YusovID/pr-reviewer-service/internal/config"
+	"github.com/YusovID/pr-reviewer-service/internal/slo"
+	"github.com/ilyakaznacheev/cleanenv"
+	"gopkg.in/yaml.v3"
+)
+
+const header = "# Code generated by tools/alerts from the slo section of %s. DO NOT EDIT.\n"
+
+func main() {
+	configPath := flag.String("config", "config/docker.yml", "config file with the slo section")
+	out := flag.String("out", "-", "file to write the rules to, '-' for stdout")
+	flag.Parse()
+
+	data, err := generate(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to generate rules: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *out == "-" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(*out, data, 0o644)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write rules: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// generate reads only the slo section, so the generator needs none of the secrets the service does.
+func generate(configPath string) ([]byte, error) {
+	var cfg struct {
+		SLO config.SLO `yaml:"slo"`
+	}
+
+	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
+		return nil, fmt.Errorf("cannot read config: %w", err)
+	}
+
+	if err := cfg.SLO.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid slo config: %w", err)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, header, configPath)
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(slo.Rules(cfg.SLO)); err != nil {
+		return nil, err
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
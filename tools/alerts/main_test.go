@@ -0,0 +1,22 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommittedRulesAreUpToDate(t *testing.T) {
+	t.Chdir("../..")
+
+	committed, err := os.ReadFile("slo-alerts.yml")
+	require.NoError(t, err)
+
+	generated, err := generate("config/docker.yml")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(generated), string(committed),
+		"alerting rules are out of date, run: go run ./tools/alerts -config config/docker.yml -out slo-alerts.yml")
+}
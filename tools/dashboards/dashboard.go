@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+)
+
+const (
+	dashboardUID   = "pr-reviewer-service"
+	dashboardTitle = "PR Reviewer Service"
+
+	panelWidth  = 12
+	panelHeight = 8
+	gridWidth   = 24
+)
+
+var groupTitles = map[metrics.Group]string{
+	metrics.GroupHTTP:     "HTTP",
+	metrics.GroupBusiness: "Business",
+	metrics.GroupWorker:   "Workers",
+	metrics.GroupOutbound: "Outbound integrations",
+	metrics.GroupDB:       "DB pool",
+	metrics.GroupCache:    "Cache",
+}
+
+var quantiles = []struct{ value, legend string }{
+	{"0.5", "p50"},
+	{"0.95", "p95"},
+	{"0.99", "p99"},
+}
+
+// The types below cover the subset of the Grafana dashboard JSON model the generator emits.
+
+type dashboard struct {
+	UID           string     `json:"uid"`
+	Title         string     `json:"title"`
+	Tags          []string   `json:"tags"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Editable      bool       `json:"editable"`
+	Refresh       string     `json:"refresh"`
+	Time          timeRange  `json:"time"`
+	Templating    templating `json:"templating"`
+	Panels        []panel    `json:"panels"`
+}
+
+type timeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type templating struct {
+	List []variable `json:"list"`
+}
+
+type variable struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Query string `json:"query"`
+}
+
+type panel struct {
+	ID          int          `json:"id"`
+	Type        string       `json:"type"`
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	GridPos     gridPos      `json:"gridPos"`
+	Collapsed   *bool        `json:"collapsed,omitempty"`
+	Datasource  *datasource  `json:"datasource,omitempty"`
+	FieldConfig *fieldConfig `json:"fieldConfig,omitempty"`
+	Targets     []target     `json:"targets,omitempty"`
+}
+
+type gridPos struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type fieldConfig struct {
+	Defaults fieldDefaults `json:"defaults"`
+}
+
+type fieldDefaults struct {
+	Unit string `json:"unit"`
+}
+
+type target struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat,omitempty"`
+}
+
+// buildDashboard lays out a row per metric group with a panel per metric, two panels in a line.
+func buildDashboard(catalog []metrics.Metric) dashboard {
+	d := dashboard{
+		UID:           dashboardUID,
+		Title:         dashboardTitle,
+		Tags:          []string{"pr-reviewer-service", "generated"},
+		SchemaVersion: 39,
+		Editable:      true,
+		Refresh:       "10s",
+		Time:          timeRange{From: "now-1h", To: "now"},
+		Templating: templating{List: []variable{
+			{Name: "datasource", Label: "Data source", Type: "datasource", Query: "prometheus"},
+		}},
+		Panels: []panel{},
+	}
+
+	id, y := 0, 0
+
+	for _, group := range metrics.Groups {
+		var members []metrics.Metric
+
+		for _, m := range catalog {
+			if m.Group == group {
+				members = append(members, m)
+			}
+		}
+
+		if len(members) == 0 {
+			continue
+		}
+
+		id++
+		collapsed := false
+		d.Panels = append(d.Panels, panel{
+			ID:        id,
+			Type:      "row",
+			Title:     groupTitles[group],
+			GridPos:   gridPos{X: 0, Y: y, W: gridWidth, H: 1},
+			Collapsed: &collapsed,
+		})
+		y++
+
+		for i, m := range members {
+			id++
+			p := metricPanel(m)
+			p.ID = id
+			p.GridPos = gridPos{X: (i % 2) * panelWidth, Y: y + (i/2)*panelHeight, W: panelWidth, H: panelHeight}
+			d.Panels = append(d.Panels, p)
+		}
+
+		y += (len(members) + 1) / 2 * panelHeight
+	}
+
+	return d
+}
+
+// metricPanel returns a time series panel with a query suited to the metric type:
+// per-second rate for counters, the current value for gauges and quantiles for histograms.
+func metricPanel(m metrics.Metric) panel {
+	p := panel{
+		Type:        "timeseries",
+		Title:       m.Help,
+		Description: m.Name,
+		Datasource:  &datasource{Type: "prometheus", UID: "${datasource}"},
+	}
+
+	unit := "short"
+
+	switch m.Type {
+	case metrics.Counter:
+		unit = "ops"
+		if strings.HasSuffix(m.Name, "_seconds_total") {
+			unit = "s"
+		}
+
+		p.Targets = []target{{
+			RefID:        "A",
+			Expr:         fmt.Sprintf("sum%s (rate(%s[$__rate_interval]))", byClause(m.Labels), m.Name),
+			LegendFormat: legend(m.Labels),
+		}}
+	case metrics.Gauge:
+		if strings.HasSuffix(m.Name, "_seconds") {
+			unit = "s"
+		}
+
+		p.Targets = []target{{
+			RefID:        "A",
+			Expr:         fmt.Sprintf("sum%s (%s)", byClause(m.Labels), m.Name),
+			LegendFormat: legend(m.Labels),
+		}}
+	case metrics.Histogram:
+		if strings.HasSuffix(m.Name, "_seconds") {
+			unit = "s"
+		}
+
+		for i, q := range quantiles {
+			p.Targets = append(p.Targets, target{
+				RefID:        string(rune('A' + i)),
+				Expr:         fmt.Sprintf("histogram_quantile(%s, sum by (le) (rate(%s_bucket[$__rate_interval])))", q.value, m.Name),
+				LegendFormat: q.legend,
+			})
+		}
+	}
+
+	p.FieldConfig = &fieldConfig{Defaults: fieldDefaults{Unit: unit}}
+
+	return p
+}
+
+func byClause(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	return " by (" + strings.Join(labels, ", ") + ")"
+}
+
+func legend(labels []string) string {
+	parts := make([]string, len(labels))
+	for i, label := range labels {
+		parts[i] = "{{" + label + "}}"
+	}
+
+	return strings.Join(parts, " ")
+}
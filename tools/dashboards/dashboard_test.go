@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const committedDashboard = "../../grafana/dashboards/pr-reviewer-service.json"
+
+func TestCommittedDashboardIsUpToDate(t *testing.T) {
+	committed, err := os.ReadFile(committedDashboard)
+	require.NoError(t, err)
+
+	generated, err := render(metrics.All())
+	require.NoError(t, err)
+
+	assert.Equal(t, string(generated), string(committed),
+		"dashboard is out of date, run: go run ./tools/dashboards -out grafana/dashboards/pr-reviewer-service.json")
+}
+
+func TestBuildDashboard_PanelPerMetric(t *testing.T) {
+	catalog := metrics.All()
+	d := buildDashboard(catalog)
+
+	rows := 0
+	exprs := map[string]string{}
+
+	for _, p := range d.Panels {
+		if p.Type == "row" {
+			rows++
+			continue
+		}
+
+		require.NotEmpty(t, p.Targets)
+		exprs[p.Description] = p.Targets[0].Expr
+	}
+
+	assert.Equal(t, len(metrics.Groups), rows)
+	require.Len(t, exprs, len(catalog))
+
+	for _, m := range catalog {
+		assert.True(t, strings.Contains(exprs[m.Name], m.Name), "panel of %s queries %s", m.Name, exprs[m.Name])
+	}
+
+	assert.Equal(t, "sum by (path, method, status) (rate(http_requests_total[$__rate_interval]))", exprs[metrics.HTTPRequests.Name])
+	assert.Equal(t, "histogram_quantile(0.5, sum by (le) (rate(http_request_duration_seconds_bucket[$__rate_interval])))",
+		exprs[metrics.HTTPRequestDuration.Name])
+	assert.Equal(t, "sum by (db_name) (go_sql_open_connections)", exprs[metrics.DBOpenConnections.Name])
+}
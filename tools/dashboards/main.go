@@ -0,0 +1,46 @@
+// Command dashboards generates the Grafana dashboard of the service from the metrics catalog.
+//
+// Run it after adding or renaming a metric in internal/metrics:
+//
+//	go run ./tools/dashboards -out grafana/dashboards/pr-reviewer-service.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/YusovID/pr-reviewer-service/internal/metrics"
+)
+
+func main() {
+	out := flag.String("out", "-", "file to write the dashboard JSON to, '-' for stdout")
+	flag.Parse()
+
+	data, err := render(metrics.All())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to render dashboard: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *out == "-" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(*out, data, 0o644)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write dashboard: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func render(catalog []metrics.Metric) ([]byte, error) {
+	data, err := json.MarshalIndent(buildDashboard(catalog), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}